@@ -12,9 +12,11 @@ import (
 	"github.com/nvandessel/floop/internal/config"
 	"github.com/nvandessel/floop/internal/constants"
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/ranking"
 	"github.com/nvandessel/floop/internal/session"
 	"github.com/nvandessel/floop/internal/spreading"
 	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/tiering"
 	"github.com/spf13/cobra"
 )
 
@@ -74,7 +76,8 @@ func runActivate(cmd *cobra.Command, args []string) error {
 
 	// Build context
 	ctxBuilder := activation.NewContextBuilder().
-		WithRepoRoot(root)
+		WithRepoRoot(root).
+		WithContentSniffing(sniffContentEnabled())
 	if file != "" {
 		ctxBuilder.WithFile(file)
 	}
@@ -108,11 +111,12 @@ func runActivate(cmd *cobra.Command, args []string) error {
 
 	// Run spreading activation pipeline
 	ctx := context.Background()
-	pipeline := spreading.NewPipeline(graphStore, spreading.DefaultConfig())
+	pipeline := spreading.NewPipeline(graphStore, spreadingConfig()).WithStandingSeeds(standingSeeds())
 	results, err := pipeline.Run(ctx, actCtx)
 	if err != nil {
 		return fmt.Errorf("spreading activation: %w", err)
 	}
+	recordHeatmapActivation(root, actCtx.FilePath, len(results))
 
 	if len(results) == 0 {
 		// Save state (prompt count) even if no results
@@ -122,6 +126,9 @@ func runActivate(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Apply temporal ranking and external scorer adjustments, if configured
+	results = rerankResults(ctx, graphStore, &actCtx, results)
+
 	// Filter through session state
 	filtered := sessState.FilterResults(results, activationToTier, estimateTokenCost)
 
@@ -221,6 +228,57 @@ func loadBehaviorMap(ctx context.Context, graphStore store.GraphStore) (map[stri
 	return bMap, nil
 }
 
+// loadExternalScorer returns the external ranking scorer from config, or nil
+// when it is disabled or the config cannot be loaded.
+func loadExternalScorer() *ranking.ExternalScorer {
+	cfg, err := config.Load()
+	if err != nil || !cfg.Ranking.External.Enabled {
+		return nil
+	}
+	ext := cfg.Ranking.External
+	return ranking.NewExternalScorer(ranking.ExternalScorerConfig{
+		Command: ext.Command,
+		URL:     ext.URL,
+		Timeout: ext.Timeout,
+	})
+}
+
+// applyExternalRanking merges external scorer adjustments into results. On
+// failure it warns on stderr and returns results unchanged.
+func applyExternalRanking(ctx context.Context, scorer *ranking.ExternalScorer, actCtx *models.ContextSnapshot, results []spreading.Result, behaviors map[string]*models.Behavior) []spreading.Result {
+	adjusted, err := tiering.ApplyExternalScores(ctx, scorer, actCtx, results, behaviors)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: external scorer failed, using built-in ranking: %v\n", err)
+	}
+	return adjusted
+}
+
+// rerankResults applies temporal ranking and the configured external
+// scorer, if enabled, to spreading activation results before session
+// filtering.
+func rerankResults(ctx context.Context, graphStore store.GraphStore, actCtx *models.ContextSnapshot, results []spreading.Result) []spreading.Result {
+	scorer := loadExternalScorer()
+	weight := temporalWeight()
+	if scorer == nil && weight == 0 {
+		return results
+	}
+	bMap, err := loadBehaviorMap(ctx, graphStore)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: reranking skipped: %v\n", err)
+		return results
+	}
+	behaviors := make(map[string]*models.Behavior, len(bMap))
+	for id := range bMap {
+		b := bMap[id]
+		behaviors[id] = &b
+	}
+	results = tiering.ApplyTemporalSignals(actCtx, results, behaviors, weight)
+	if scorer == nil {
+		return results
+	}
+	return applyExternalRanking(ctx, scorer, actCtx, results, behaviors)
+}
+
 // applyTokenBudget trims the filtered results to fit within the token budget.
 func applyTokenBudget(filtered []session.FilteredResult, budget int) []session.FilteredResult {
 	if budget <= 0 {
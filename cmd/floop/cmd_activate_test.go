@@ -3,12 +3,15 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
 
+	"github.com/nvandessel/floop/internal/constants"
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/session"
 	"github.com/spf13/cobra"
@@ -461,3 +464,103 @@ func TestActiveCmdNotInitialized(t *testing.T) {
 		t.Fatalf("active not initialized failed: %v", err)
 	}
 }
+
+func TestActiveCmdChangeset(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newActiveCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"active", "--json", "--task", "coding", "--files", "main.go", "app.py", "--root", tmpDir})
+
+	output := captureStdout(t, func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("active --files failed: %v", err)
+		}
+	})
+
+	var result struct {
+		Files       []string            `json:"files"`
+		TriggeredBy map[string][]string `json:"triggered_by"`
+		Count       int                 `json:"count"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("failed to parse output %q: %v", output, err)
+	}
+	if want := []string{"main.go", "app.py"}; !reflect.DeepEqual(result.Files, want) {
+		t.Errorf("files = %v, want %v", result.Files, want)
+	}
+	if result.Count != 1 {
+		t.Fatalf("count = %d, want the learned behavior listed once", result.Count)
+	}
+	if got := result.TriggeredBy[behaviorID]; !reflect.DeepEqual(got, []string{"main.go"}) {
+		t.Errorf("triggered_by[%s] = %v, want [main.go]", behaviorID, got)
+	}
+}
+
+func TestActiveCmdTooManyFiles(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	files := make([]string, constants.MaxChangesetFiles+1)
+	for i := range files {
+		files[i] = fmt.Sprintf("f%d.go", i)
+	}
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newActiveCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs(append([]string{"active", "--root", tmpDir, "--files"}, strings.Join(files, ",")))
+
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "too many files") {
+		t.Errorf("Execute() error = %v, want too many files", err)
+	}
+}
+
+func TestActiveCmdTags(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	learnCmd := newTestRootCmd()
+	learnCmd.AddCommand(newLearnCmd())
+	learnCmd.SetOut(&bytes.Buffer{})
+	learnCmd.SetArgs([]string{"learn", "--right", "run table tests with t.Run subtests", "--when", "tags=testing", "--root", tmpDir})
+	if err := learnCmd.Execute(); err != nil {
+		t.Fatalf("learn failed: %v", err)
+	}
+
+	activeNames := func(tags string) []string {
+		t.Helper()
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newActiveCmd())
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetArgs([]string{"active", "--json", "--no-daemon", "--tags", tags, "--root", tmpDir})
+		output := captureStdout(t, func() {
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("active --tags failed: %v", err)
+			}
+		})
+		var result struct {
+			Context models.ContextSnapshot `json:"context"`
+			Active  []models.Behavior      `json:"active"`
+		}
+		if err := json.Unmarshal([]byte(output), &result); err != nil {
+			t.Fatalf("failed to parse output %q: %v", output, err)
+		}
+		if len(result.Context.Tags) == 0 {
+			t.Errorf("context = %+v, want the declared tags", result.Context)
+		}
+		var names []string
+		for _, b := range result.Active {
+			if strings.Contains(b.Content.Canonical, "t.Run") {
+				names = append(names, b.Name)
+			}
+		}
+		return names
+	}
+
+	if names := activeNames("testing/unit"); len(names) != 1 {
+		t.Errorf("--tags testing/unit activated %v, want the testing behavior", names)
+	}
+	if names := activeNames("review"); len(names) != 0 {
+		t.Errorf("--tags review activated %v, want nothing tagged testing", names)
+	}
+}
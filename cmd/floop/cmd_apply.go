@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/authored"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newApplyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Sync hand-authored behavior files into the project store",
+		Long: `Validate the behavior definition files in .floop/behaviors/*.yaml and
+make the project store match them. Definition files are meant to be
+committed, so deliberately authored behaviors go through code review like
+any other change:
+
+  behaviors:
+    - id: wrap-errors
+      kind: directive
+      content: Wrap errors with fmt.Errorf("...: %w", err)
+      when:
+        language: go
+      tags: [go, errors]
+
+Each behavior is created, or updated when its definition changed. A
+behavior changed in the store instead of its file (edited, forgotten,
+deprecated) is reported as drift and overwritten. Behaviors whose
+definitions were removed from the files are orphans; --prune forgets them.
+
+--dry-run prints the plan without writing. --check does the same and fails
+when the store differs from the files, for CI.
+
+Examples:
+  floop apply                 # Apply the definition files
+  floop apply --dry-run       # Show what would change
+  floop apply --check         # Fail if the store has drifted
+  floop apply --prune --json  # Also forget orphans; machine-readable`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			check, _ := cmd.Flags().GetBool("check")
+			prune, _ := cmd.Flags().GetBool("prune")
+			out := cmd.OutOrStdout()
+
+			floopDir := filepath.Join(root, ".floop")
+			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
+				return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+			}
+
+			defs, err := authored.Load(root)
+			if err != nil {
+				var invalid *authored.ValidationError
+				if jsonOut && errors.As(err, &invalid) {
+					json.NewEncoder(out).Encode(map[string]interface{}{
+						"error":    "invalid behavior definitions",
+						"problems": invalid.Problems,
+					})
+				}
+				return err
+			}
+
+			graphStore, err := store.NewSQLiteGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			ctx := context.Background()
+			changes, err := authored.Plan(ctx, graphStore, defs)
+			if err != nil {
+				return err
+			}
+
+			pending := 0
+			for _, c := range changes {
+				if c.Pending(prune) {
+					pending++
+				}
+			}
+
+			failed := 0
+			write := !dryRun && !check
+			if write {
+				failed = authored.Apply(ctx, graphStore, changes, prune, time.Now())
+				if err := graphStore.Sync(ctx); err != nil {
+					return fmt.Errorf("failed to sync changes: %w", err)
+				}
+			}
+
+			if jsonOut {
+				if changes == nil {
+					changes = []authored.Change{}
+				}
+				json.NewEncoder(out).Encode(map[string]interface{}{
+					"changes":     changes,
+					"definitions": len(defs),
+					"pending":     pending,
+					"failed":      failed,
+					"applied":     write,
+				})
+			} else {
+				printApplyChanges(cmd, changes, prune)
+				switch {
+				case len(defs) == 0 && len(changes) == 0:
+					fmt.Fprintf(out, "No behavior definitions in %s.\n", authored.Dir)
+				case write:
+					fmt.Fprintf(out, "\nApplied %d of %d change(s) from %d definition(s).\n", pending-failed, pending, len(defs))
+				default:
+					fmt.Fprintf(out, "\n%d change(s) pending for %d definition(s).\n", pending, len(defs))
+				}
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d change(s) failed", failed)
+			}
+			if check && pending > 0 {
+				return fmt.Errorf("store differs from %s: %d change(s) pending", authored.Dir, pending)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Show what would change without writing")
+	cmd.Flags().Bool("check", false, "Exit non-zero if the store differs from the definition files")
+	cmd.Flags().Bool("prune", false, "Forget behaviors whose definitions were removed from the files")
+
+	return cmd
+}
+
+// printApplyChanges lists the changes other than unchanged behaviors.
+func printApplyChanges(cmd *cobra.Command, changes []authored.Change, prune bool) {
+	out := cmd.OutOrStdout()
+	for _, c := range changes {
+		if c.Action == authored.ActionUnchanged {
+			continue
+		}
+		line := fmt.Sprintf("  %-10s %s", c.Action, c.ID)
+		if c.File != "" {
+			line += " (" + c.File + ")"
+		}
+		if len(c.Fields) > 0 {
+			line += ": " + strings.Join(c.Fields, ", ")
+		}
+		if c.Action == authored.ActionOrphan && !prune {
+			line += " [kept; --prune forgets it]"
+		}
+		if c.Error != "" {
+			line += " - " + c.Error
+		}
+		fmt.Fprintln(out, line)
+	}
+}
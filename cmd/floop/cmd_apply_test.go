@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/authored"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func runApplyCmd(t *testing.T, root string, args ...string) (string, error) {
+	t.Helper()
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newApplyCmd())
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(append([]string{"apply", "--root", root}, args...))
+	err := rootCmd.Execute()
+	return out.String(), err
+}
+
+func writeBehaviorFile(t *testing.T, root, content string) {
+	t.Helper()
+	dir := filepath.Join(root, authored.Dir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "team.yaml"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApplyCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newInitCmd())
+	rootCmd.SetArgs([]string{"init", "--root", tmpDir})
+	rootCmd.SetOut(&bytes.Buffer{})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	out, err := runApplyCmd(t, tmpDir)
+	if err != nil || !strings.Contains(out, "No behavior definitions") {
+		t.Fatalf("apply without files = %q, %v", out, err)
+	}
+
+	writeBehaviorFile(t, tmpDir, `
+behaviors:
+  - id: wrap-errors
+    content: Wrap errors with context
+    when: {language: go}
+`)
+	if _, err := runApplyCmd(t, tmpDir, "--check"); err == nil || !strings.Contains(err.Error(), "1 change(s) pending") {
+		t.Fatalf("apply --check error = %v, want pending change", err)
+	}
+	if _, err := runApplyCmd(t, tmpDir, "--dry-run"); err != nil {
+		t.Fatalf("apply --dry-run error = %v", err)
+	}
+
+	out, err = runApplyCmd(t, tmpDir, "--json")
+	if err != nil {
+		t.Fatalf("apply --json error = %v", err)
+	}
+	var result struct {
+		Changes []authored.Change `json:"changes"`
+		Pending int               `json:"pending"`
+		Applied bool              `json:"applied"`
+	}
+	if err := json.NewDecoder(strings.NewReader(out)).Decode(&result); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out)
+	}
+	if !result.Applied || result.Pending != 1 || len(result.Changes) != 1 || result.Changes[0].Action != authored.ActionCreate || !result.Changes[0].Applied {
+		t.Fatalf("apply --json = %+v", result)
+	}
+
+	gs, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, err := gs.GetNode(context.Background(), "wrap-errors")
+	gs.Close()
+	if err != nil || node == nil || node.Kind != store.NodeKindBehavior {
+		t.Fatalf("GetNode(wrap-errors) = %+v, %v", node, err)
+	}
+
+	if _, err := runApplyCmd(t, tmpDir, "--check"); err != nil {
+		t.Errorf("apply --check after apply error = %v", err)
+	}
+
+	writeBehaviorFile(t, tmpDir, "behaviors:\n  - id: Bad\n")
+	if _, err := runApplyCmd(t, tmpDir); err == nil || !strings.Contains(err.Error(), "content is required") {
+		t.Errorf("apply with invalid file error = %v", err)
+	}
+}
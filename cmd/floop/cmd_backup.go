@@ -1,16 +1,21 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/nvandessel/floop/internal/backup"
 	"github.com/nvandessel/floop/internal/config"
 	"github.com/nvandessel/floop/internal/constants"
 	"github.com/nvandessel/floop/internal/pathutil"
+	"github.com/nvandessel/floop/internal/restorepoint"
 	"github.com/nvandessel/floop/internal/store"
 	"github.com/spf13/cobra"
 )
@@ -164,17 +169,34 @@ Format is auto-detected.
 
 Modes:
   merge   - Skip existing nodes/edges (default)
-  replace - Clear store first, then restore
+  replace - Clear store first, then restore. A restore point is saved
+            first; undo with 'floop restore-point apply <id>'.
+
+In merge mode, a node that exists with different content is a conflict.
+Conflicts are listed after the restore and resolved by --strategy:
+  keep-local   - Keep the store's version (default)
+  take-backup  - Replace it with the backup's version
+  merge-fields - Keep the store's fields, adding those only the backup has
+--interactive asks how to resolve each conflict instead, including which
+side each differing field comes from.
+
+Progress is shown on stderr, as JSONL events with --json. Ctrl-C stops after
+the current node or batch of edges and reports what was restored; restoring
+the same file again in merge mode picks up the rest.
 
 Examples:
   floop restore-backup ~/.floop/backups/floop-backup-20260206-120000.json.gz
-  floop restore-backup backup.json --mode replace`,
+  floop restore-backup backup.json --mode replace
+  floop restore-backup backup.json --strategy take-backup
+  floop restore-backup backup.json --interactive`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			inputPath := args[0]
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			mode, _ := cmd.Flags().GetString("mode")
+			strategyName, _ := cmd.Flags().GetString("strategy")
+			interactive, _ := cmd.Flags().GetBool("interactive")
 
 			allowedDirs, err := pathutil.DefaultAllowedBackupDirsWithProjectRoot(root)
 			if err != nil {
@@ -188,37 +210,237 @@ Examples:
 			if mode == "replace" {
 				restoreMode = backup.RestoreReplace
 			}
+			strategy, err := backup.ParseConflictStrategy(strategyName)
+			if err != nil {
+				return err
+			}
+			if interactive && jsonOut {
+				return fmt.Errorf("--interactive cannot be combined with --json")
+			}
 
-			ctx := context.Background()
+			// Ctrl-C stops after the current node or edge batch
+			rep, notice := commandProgress(cmd, jsonOut)
+			ctx, stop := interruptContext(notice)
+			defer stop()
 			graphStore, err := store.NewMultiGraphStore(root)
 			if err != nil {
 				return fmt.Errorf("failed to open store: %w", err)
 			}
 			defer graphStore.Close()
 
-			result, err := backup.Restore(ctx, graphStore, inputPath, restoreMode)
-			if err != nil {
+			// Replace overwrites the stores: snapshot them first so it can be undone
+			var pointID string
+			if restoreMode == backup.RestoreReplace {
+				point, err := restorepoint.Create(context.WithoutCancel(ctx), graphStore, root, "restore-backup",
+					fmt.Sprintf("restore-backup %s --mode replace", filepath.Base(inputPath)), nil)
+				if err != nil {
+					return fmt.Errorf("failed to create restore point: %w", err)
+				}
+				pointID = point.ID
+			}
+
+			opts := backup.RestoreOptions{
+				Mode:     restoreMode,
+				Progress: rep,
+				Strategy: strategy,
+			}
+			if interactive && restoreMode == backup.RestoreMerge {
+				// Prompts replace the progress bar
+				opts.Progress = nil
+				opts.Resolve = promptConflictResolver(ctx, cmd.InOrStdin(), os.Stdout, strategy)
+			}
+			result, err := backup.RestoreWithOptions(ctx, graphStore, inputPath, opts)
+			interrupted := errors.Is(err, context.Canceled)
+			if err != nil && !interrupted {
 				return fmt.Errorf("restore failed: %w", err)
 			}
+			summary := "Restore complete"
+			if interrupted {
+				summary = "Restore interrupted"
+			}
 
 			if jsonOut {
-				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				out := map[string]interface{}{
 					"nodes_restored": result.NodesRestored,
 					"nodes_skipped":  result.NodesSkipped,
+					"nodes_updated":  result.NodesUpdated,
 					"edges_restored": result.EdgesRestored,
 					"edges_skipped":  result.EdgesSkipped,
-					"message":        fmt.Sprintf("Restore complete: %d nodes, %d edges", result.NodesRestored, result.EdgesRestored),
-				})
+					"message":        fmt.Sprintf("%s: %d nodes, %d edges", summary, result.NodesRestored, result.EdgesRestored),
+				}
+				if interrupted {
+					out["interrupted"] = true
+				}
+				if len(result.Conflicts) > 0 {
+					out["conflicts"] = result.Conflicts
+				}
+				if pointID != "" {
+					out["restore_point"] = pointID
+				}
+				return json.NewEncoder(os.Stdout).Encode(out)
 			}
 
-			fmt.Printf("Restore complete (mode: %s)\n", mode)
-			fmt.Printf("  Nodes: %d restored, %d skipped\n", result.NodesRestored, result.NodesSkipped)
+			fmt.Printf("%s (mode: %s)\n", summary, mode)
+			fmt.Printf("  Nodes: %d restored, %d skipped, %d updated\n", result.NodesRestored, result.NodesSkipped, result.NodesUpdated)
 			fmt.Printf("  Edges: %d restored, %d skipped\n", result.EdgesRestored, result.EdgesSkipped)
+			if len(result.Conflicts) > 0 {
+				fmt.Printf("  Conflicts: %d\n", len(result.Conflicts))
+				for _, c := range result.Conflicts {
+					fmt.Printf("    %s: %s (%s)\n", conflictLabel(c), strings.Join(c.Fields, ", "), conflictOutcome(c))
+				}
+			}
+			if pointID != "" {
+				fmt.Printf("  Undo with: floop restore-point apply %s\n", pointID)
+			}
 			return nil
 		},
 	}
 
 	cmd.Flags().String("mode", "merge", "Restore mode: merge or replace")
+	cmd.Flags().String("strategy", "keep-local", "Merge conflict resolution: keep-local, take-backup, merge-fields")
+	cmd.Flags().BoolP("interactive", "i", false, "Ask how to resolve each merge conflict")
 
 	return cmd
 }
+
+// conflictLabel names a conflicting node for output.
+func conflictLabel(c backup.Conflict) string {
+	if c.Name != "" && c.Name != c.ID {
+		return fmt.Sprintf("%s (%s)", c.Name, c.ID)
+	}
+	return c.ID
+}
+
+// conflictOutcome describes how a conflict was resolved.
+func conflictOutcome(c backup.Conflict) string {
+	switch c.Resolution {
+	case backup.ConflictTakeBackup:
+		return "took backup"
+	case backup.ConflictMergeFields:
+		return "merged, from backup: " + strings.Join(c.FromBackup, ", ")
+	}
+	return "kept local"
+}
+
+// promptConflictResolver returns a resolver that shows each conflict on out
+// and reads the choice from in, one line per answer. Quitting, end of input,
+// or cancelling ctx resolves the remaining conflicts with fallback.
+func promptConflictResolver(ctx context.Context, in io.Reader, out io.Writer, fallback backup.ConflictStrategy) backup.ConflictResolver {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		reader := bufio.NewReader(in)
+		for {
+			line, err := reader.ReadString('\n')
+			if line != "" || err == nil {
+				lines <- strings.TrimSpace(line)
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	done := false
+	ask := func(prompt string) (string, bool) {
+		fmt.Fprint(out, prompt)
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				fmt.Fprintln(out)
+			}
+			return line, ok
+		case <-ctx.Done():
+			fmt.Fprintln(out)
+			return "", false
+		}
+	}
+
+	n := 0
+	return func(_ context.Context, c backup.Conflict) (backup.Resolution, error) {
+		if done {
+			return backup.Resolution{Strategy: fallback}, nil
+		}
+		n++
+		fmt.Fprintf(out, "\nConflict %d: %s\n", n, conflictLabel(c))
+		for _, field := range c.Fields {
+			fmt.Fprintf(out, "  %s\n", field)
+			fmt.Fprintf(out, "    local:  %s\n", conflictValue(c, field, false))
+			fmt.Fprintf(out, "    backup: %s\n", conflictValue(c, field, true))
+		}
+		for {
+			answer, ok := ask("[l]ocal  [b]ackup  [m]erge fields  [q]uit: ")
+			if !ok {
+				done = true
+				return backup.Resolution{Strategy: fallback}, nil
+			}
+			switch strings.ToLower(answer) {
+			case "l", "local":
+				return backup.Resolution{Strategy: backup.ConflictKeepLocal}, nil
+			case "b", "backup":
+				return backup.Resolution{Strategy: backup.ConflictTakeBackup}, nil
+			case "m", "merge":
+				return promptConflictFields(c, ask)
+			case "q", "quit":
+				done = true
+				fmt.Fprintf(out, "Resolving the remaining conflicts with %s\n", fallback)
+				return backup.Resolution{Strategy: fallback}, nil
+			}
+			fmt.Fprintf(out, "Unknown choice: %s\n", answer)
+		}
+	}
+}
+
+// promptConflictFields asks which side each differing field of c comes from.
+// An empty answer takes the suggestion: the backup's value where only the
+// backup sets the field, the store's otherwise.
+func promptConflictFields(c backup.Conflict, ask func(string) (string, bool)) (backup.Resolution, error) {
+	suggested := make(map[string]bool)
+	for _, field := range c.BackupOnly() {
+		suggested[field] = true
+	}
+	fromBackup := []string{}
+	for _, field := range c.Fields {
+		choices := "[L/b]"
+		if suggested[field] {
+			choices = "[l/B]"
+		}
+		for {
+			answer, ok := ask(fmt.Sprintf("  %s %s: ", field, choices))
+			if !ok {
+				answer = ""
+			}
+			takeBackup := suggested[field]
+			switch strings.ToLower(answer) {
+			case "":
+			case "l", "local":
+				takeBackup = false
+			case "b", "backup":
+				takeBackup = true
+			default:
+				continue
+			}
+			if takeBackup {
+				fromBackup = append(fromBackup, field)
+			}
+			break
+		}
+	}
+	return backup.Resolution{Strategy: backup.ConflictMergeFields, FromBackup: fromBackup}, nil
+}
+
+// conflictValue formats one side's value of a conflicting field.
+func conflictValue(c backup.Conflict, field string, fromBackup bool) string {
+	v, ok := c.Value(field, fromBackup)
+	if !ok {
+		return "(not set)"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	s := string(data)
+	if len(s) > 100 {
+		s = s[:97] + "..."
+	}
+	return s
+}
@@ -2,9 +2,14 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/store"
 )
 
 // backupOutputPath returns a valid backup output path inside the tmpDir's .floop/backups/.
@@ -161,3 +166,102 @@ func TestRestoreFromBackupCmdNotFound(t *testing.T) {
 		t.Error("expected error for nonexistent backup file")
 	}
 }
+
+func TestRestoreFromBackupCmdInteractiveConflict(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+	outputPath := backupOutputPath(t, tmpDir, "test-backup.json")
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newBackupCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"backup", "--no-compress", "--output", outputPath, "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("backup failed: %v", err)
+	}
+
+	// Edit the behavior after the backup
+	ctx := context.Background()
+	canonical := func() interface{} {
+		t.Helper()
+		graphStore, err := store.NewMultiGraphStore(tmpDir)
+		if err != nil {
+			t.Fatalf("failed to open store: %v", err)
+		}
+		defer graphStore.Close()
+		node, err := graphStore.GetNode(ctx, behaviorID)
+		if err != nil || node == nil {
+			t.Fatalf("GetNode() = %v, %v", node, err)
+		}
+		inner, _ := node.Content["content"].(map[string]interface{})
+		return inner["canonical"]
+	}
+	original := canonical()
+	graphStore, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	node, _ := graphStore.GetNode(ctx, behaviorID)
+	node.Content["content"] = map[string]interface{}{"canonical": "edited after the backup"}
+	if err := graphStore.UpdateNode(ctx, *node); err != nil {
+		t.Fatalf("UpdateNode() error = %v", err)
+	}
+	graphStore.Close()
+
+	rootCmd2 := newTestRootCmd()
+	rootCmd2.AddCommand(newRestoreFromBackupCmd())
+	rootCmd2.SetOut(&bytes.Buffer{})
+	rootCmd2.SetIn(strings.NewReader("x\nb\n"))
+	rootCmd2.SetArgs([]string{"restore-backup", outputPath, "--interactive", "--root", tmpDir})
+	out := captureStdout(t, func() {
+		if err := rootCmd2.Execute(); err != nil {
+			t.Fatalf("restore-backup --interactive failed: %v", err)
+		}
+	})
+
+	if !strings.Contains(out, "content.content.canonical") || !strings.Contains(out, "Unknown choice: x") {
+		t.Errorf("prompt output missing the conflict: %s", out)
+	}
+	if !strings.Contains(out, "1 updated") || !strings.Contains(out, "(took backup)") {
+		t.Errorf("summary missing the resolution: %s", out)
+	}
+	if got := canonical(); got != original {
+		t.Errorf("canonical = %v, want the backup's %v", got, original)
+	}
+}
+
+func TestPromptConflictResolverMergeFields(t *testing.T) {
+	c := backup.Conflict{
+		ID:     "b-1",
+		Fields: []string{"content.content.canonical", "content.when"},
+		Local: store.Node{Content: map[string]interface{}{
+			"content": map[string]interface{}{"canonical": "local"},
+		}},
+		Backup: store.Node{Content: map[string]interface{}{
+			"content": map[string]interface{}{"canonical": "backup"},
+			"when":    map[string]interface{}{"language": "go"},
+		}},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  backup.Resolution
+	}{
+		// Empty answers take the suggestions: local canonical, backup-only when
+		{"suggested", "m\n\n\n", backup.Resolution{Strategy: backup.ConflictMergeFields, FromBackup: []string{"content.when"}}},
+		{"picked", "m\nb\nl\n", backup.Resolution{Strategy: backup.ConflictMergeFields, FromBackup: []string{"content.content.canonical"}}},
+		{"end of input falls back", "", backup.Resolution{Strategy: backup.ConflictTakeBackup}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolve := promptConflictResolver(context.Background(), strings.NewReader(tt.input), &bytes.Buffer{}, backup.ConflictTakeBackup)
+			got, err := resolve(context.Background(), c)
+			if err != nil {
+				t.Fatalf("resolve() error = %v", err)
+			}
+			if got.Strategy != tt.want.Strategy || strings.Join(got.FromBackup, ",") != strings.Join(tt.want.FromBackup, ",") {
+				t.Errorf("resolve() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/nvandessel/floop/internal/bench"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newBenchCmd() *cobra.Command {
+	defaults := bench.DefaultConfig()
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Benchmark the activation pipeline on a synthetic graph",
+		Long: `Generate a synthetic behavior graph of the given size in a scratch store,
+then run seed selection, spreading activation, and tiering against it for
+rotating language and task contexts, reporting p50/p95 latency and
+allocations per stage.
+
+The graph is generated from --seed, so runs with the same flags are
+comparable across builds. Your own .floop stores are not touched.
+
+Examples:
+  floop bench                                  # 1000 behaviors, 3000 edges
+  floop bench --behaviors 10000 --edges 40000
+  floop bench --store memory --iterations 500 --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			storeKind, _ := cmd.Flags().GetString("store")
+
+			cfg := bench.DefaultConfig()
+			cfg.Behaviors, _ = cmd.Flags().GetInt("behaviors")
+			cfg.Edges, _ = cmd.Flags().GetInt("edges")
+			cfg.Iterations, _ = cmd.Flags().GetInt("iterations")
+			cfg.Warmup, _ = cmd.Flags().GetInt("warmup")
+			cfg.Seed, _ = cmd.Flags().GetInt64("seed")
+			cfg.TokenBudget, _ = cmd.Flags().GetInt("token-budget")
+			cfg.Spreading = spreadingConfig()
+
+			if cfg.Behaviors < 1 || cfg.Iterations < 1 {
+				return fmt.Errorf("--behaviors and --iterations must be at least 1")
+			}
+			if cfg.Edges < 0 || cfg.Warmup < 0 {
+				return fmt.Errorf("--edges and --warmup must be 0 or more")
+			}
+
+			var graphStore store.GraphStore
+			switch storeKind {
+			case "sqlite":
+				dir, err := os.MkdirTemp("", "floop-bench-")
+				if err != nil {
+					return fmt.Errorf("creating scratch directory: %w", err)
+				}
+				defer os.RemoveAll(dir)
+				s, err := store.NewSQLiteGraphStore(dir)
+				if err != nil {
+					return fmt.Errorf("opening scratch store: %w", err)
+				}
+				graphStore = s
+			case "memory":
+				graphStore = store.NewInMemoryGraphStore()
+			default:
+				return fmt.Errorf("invalid --store %q (want sqlite or memory)", storeKind)
+			}
+			defer graphStore.Close()
+
+			if !jsonOut {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Generating %d behaviors and %d edges...\n", cfg.Behaviors, cfg.Edges)
+			}
+			report, err := bench.Run(context.Background(), graphStore, cfg)
+			if err != nil {
+				return fmt.Errorf("benchmark failed: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"store":  storeKind,
+					"report": report,
+				})
+			}
+
+			fmt.Fprintf(out, "Store: %s, %d behaviors, %d edges (setup %.0f ms)\n",
+				storeKind, report.Behaviors, report.Edges, report.SetupMs)
+			fmt.Fprintf(out, "Iterations: %d, mean %.1f seeds and %.1f activated per run\n\n",
+				report.Iterations, report.MeanSeeds, report.MeanResults)
+			fmt.Fprintf(out, "%-10s %9s %9s %9s %9s %10s %12s\n", "Stage", "p50 ms", "p95 ms", "Mean ms", "Max ms", "Allocs/op", "Bytes/op")
+			fmt.Fprintln(out, repeatChar('-', 74))
+			for _, st := range report.Stages {
+				fmt.Fprintf(out, "%-10s %9.2f %9.2f %9.2f %9.2f %10d %12d\n",
+					st.Stage, st.P50Ms, st.P95Ms, st.MeanMs, st.MaxMs, st.AllocsPerOp, st.BytesPerOp)
+			}
+			if report.EdgeErrors > 0 {
+				fmt.Fprintf(out, "\n%d generated edge(s) were rejected by the store.\n", report.EdgeErrors)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Int("behaviors", defaults.Behaviors, "Number of generated behaviors")
+	cmd.Flags().Int("edges", defaults.Edges, "Number of generated edges")
+	cmd.Flags().Int("iterations", defaults.Iterations, "Number of measured pipeline runs")
+	cmd.Flags().Int("warmup", defaults.Warmup, "Number of unmeasured runs before measuring")
+	cmd.Flags().Int64("seed", defaults.Seed, "Random seed of the generated graph")
+	cmd.Flags().Int("token-budget", defaults.TokenBudget, "Token budget for the tiering stage")
+	cmd.Flags().String("store", "sqlite", "Store to benchmark: sqlite (scratch directory) or memory")
+	return cmd
+}
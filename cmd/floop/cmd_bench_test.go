@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBenchCmd(t *testing.T) {
+	isolateHome(t, t.TempDir())
+
+	run := func(t *testing.T, args ...string) (string, error) {
+		t.Helper()
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newBenchCmd())
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs(append([]string{"bench", "--behaviors", "100", "--edges", "200", "--iterations", "5", "--warmup", "0"}, args...))
+		err := rootCmd.Execute()
+		return buf.String(), err
+	}
+
+	t.Run("sqlite json", func(t *testing.T) {
+		out, err := run(t, "--json")
+		if err != nil {
+			t.Fatalf("bench --json failed: %v", err)
+		}
+		var got struct {
+			Store  string `json:"store"`
+			Report struct {
+				Behaviors int `json:"behaviors"`
+				Stages    []struct {
+					Stage string  `json:"stage"`
+					P95Ms float64 `json:"p95_ms"`
+				} `json:"stages"`
+			} `json:"report"`
+		}
+		if err := json.Unmarshal([]byte(out), &got); err != nil {
+			t.Fatalf("invalid JSON output: %v\n%s", err, out)
+		}
+		if got.Store != "sqlite" || got.Report.Behaviors != 100 || len(got.Report.Stages) != 4 {
+			t.Errorf("output = %+v", got)
+		}
+	})
+
+	t.Run("memory text", func(t *testing.T) {
+		out, err := run(t, "--store", "memory")
+		if err != nil {
+			t.Fatalf("bench failed: %v", err)
+		}
+		for _, want := range []string{"p95 ms", "spreading", "tiering", "total"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("output missing %q:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("invalid store", func(t *testing.T) {
+		if _, err := run(t, "--store", "redis"); err == nil {
+			t.Error("bench --store redis succeeded, want an error")
+		}
+	})
+}
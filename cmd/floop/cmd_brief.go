@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/brief"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/corrections"
+	"github.com/nvandessel/floop/internal/heatmap"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/spf13/cobra"
+)
+
+func newBriefCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "brief",
+		Short: "Summarize what floop knows for onboarding",
+		Long: `Generate a concise overview of the behavior store: the strongest behaviors
+per tag, recent learnings, known conflicts, and coverage gaps (repo areas
+corrected with no guidance, see 'floop heatmap').
+
+--audience human (the default) writes Markdown for an onboarding doc, with
+counts, names, and confidence. --audience agent writes the same knowledge as
+instructions for the start of an agent session.
+
+With --file, --task, or --language the brief covers only the behaviors
+active in that context.
+
+Examples:
+  floop brief > docs/floop-brief.md
+  floop brief --audience agent
+  floop brief --file internal/store/sqlite.go --audience agent
+  floop brief --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			audienceFlag, _ := cmd.Flags().GetString("audience")
+			file, _ := cmd.Flags().GetString("file")
+			task, _ := cmd.Flags().GetString("task")
+			language, _ := cmd.Flags().GetString("language")
+			opts := brief.DefaultOptions()
+			opts.MaxTags, _ = cmd.Flags().GetInt("tags")
+			opts.PerTag, _ = cmd.Flags().GetInt("per-tag")
+			opts.Recent, _ = cmd.Flags().GetInt("recent")
+
+			audience, err := brief.ParseAudience(audienceFlag)
+			if err != nil {
+				return err
+			}
+
+			floopDir := filepath.Join(root, ".floop")
+			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
+				return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+			}
+
+			behaviors, err := loadBehaviorsWithScope(root, constants.ScopeBoth)
+			if err != nil {
+				return fmt.Errorf("failed to load behaviors: %w", err)
+			}
+			if file != "" || task != "" || language != "" {
+				behaviors = activeForBrief(root, file, task, language, behaviors)
+			}
+
+			activations, err := heatmap.LoadActivations(floopDir)
+			if err != nil {
+				return err
+			}
+			logged, err := corrections.Load(floopDir)
+			if err != nil {
+				return err
+			}
+			gaps := heatmap.Build(root, activations, logged, heatmap.Options{Depth: 2})
+
+			b := brief.Build(behaviors, gaps, opts)
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(b)
+			}
+			return brief.Render(os.Stdout, b, audience)
+		},
+	}
+
+	cmd.Flags().String("audience", string(brief.AudienceHuman), "Who the brief is for: human or agent")
+	cmd.Flags().String("file", "", "Only cover behaviors active for this file")
+	cmd.Flags().String("task", "", "Only cover behaviors active for this task")
+	cmd.Flags().String("language", "", "Only cover behaviors active for this language")
+	cmd.Flags().Int("tags", 8, "Number of tags to show, most behaviors first")
+	cmd.Flags().Int("per-tag", 3, "Behaviors shown per tag")
+	cmd.Flags().Int("recent", 5, "Recent learnings (last 14 days) to show")
+	return cmd
+}
+
+// activeForBrief narrows behaviors to those active in the given context.
+func activeForBrief(root, file, task, language string, behaviors []models.Behavior) []models.Behavior {
+	ctxBuilder := activation.NewContextBuilder().
+		WithRepoRoot(root).
+		WithContentSniffing(sniffContentEnabled())
+	if file != "" {
+		ctxBuilder.WithFile(file)
+	}
+	if task != "" {
+		ctxBuilder.WithTask(task)
+	}
+	if language != "" {
+		ctxBuilder.WithLanguage(language)
+	}
+	matches := newEvaluator().Evaluate(ctxBuilder.Build(), behaviors)
+
+	active := make([]models.Behavior, 0, len(matches))
+	for _, m := range matches {
+		active = append(active, m.Behavior)
+	}
+	return active
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/brief"
+	"github.com/spf13/cobra"
+)
+
+// briefTestCmds returns fresh commands for the tests in this file.
+func briefTestCmds() []*cobra.Command {
+	return []*cobra.Command{newInitCmd(), newLearnCmd(), newBriefCmd()}
+}
+
+func TestBriefCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	if _, err := runTestCmd(t, briefTestCmds(), "init", "--root", tmpDir); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	for _, args := range [][]string{
+		{"--right", "use uv for python packages instead of pip", "--tags", "python", "--language", "python"},
+		{"--right", "wrap errors with fmt.Errorf and %w", "--language", "go"},
+	} {
+		if _, err := runTestCmd(t, briefTestCmds(), append(append([]string{"learn"}, args...), "--root", tmpDir)...); err != nil {
+			t.Fatalf("learn failed: %v", err)
+		}
+	}
+
+	out, err := runTestCmd(t, briefTestCmds(), "brief", "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("brief --json failed: %v", err)
+	}
+	var b brief.Brief
+	if err := json.Unmarshal([]byte(out), &b); err != nil {
+		t.Fatalf("brief --json = %s: %v", out, err)
+	}
+	if b.Behaviors != 2 || len(b.Recent) != 2 {
+		t.Errorf("brief = %+v, want 2 behaviors, both recent", b)
+	}
+
+	human, err := runTestCmd(t, briefTestCmds(), "brief", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("brief failed: %v", err)
+	}
+	if !strings.Contains(human, "# What floop knows") || !strings.Contains(human, "use uv for python packages") {
+		t.Errorf("human brief = %s", human)
+	}
+
+	// A context narrows the brief to the behaviors active there
+	agent, err := runTestCmd(t, briefTestCmds(), "brief", "--audience", "agent", "--language", "go", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("brief --audience agent failed: %v", err)
+	}
+	if !strings.Contains(agent, "wrap errors with fmt.Errorf") || strings.Contains(agent, "use uv") {
+		t.Errorf("agent brief for go = %s, want only the go behavior", agent)
+	}
+
+	if _, err := runTestCmd(t, briefTestCmds(), "brief", "--audience", "robot", "--root", tmpDir); err == nil {
+		t.Error("invalid --audience should fail")
+	}
+}
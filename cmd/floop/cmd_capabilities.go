@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/capabilities"
+	"github.com/spf13/cobra"
+)
+
+func newCapabilitiesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "capabilities",
+		Short: "List supported features, tools, edge kinds, and config keys",
+		Long: `List what this floop build supports: feature flags, store schema
+version, MCP tools and resources, edge kinds, and config keys.
+
+Integrators should feature-detect with --json instead of parsing version
+strings. The same object is advertised by the MCP server under
+capabilities.experimental.floop in its initialize result.
+
+Examples:
+  floop capabilities          # Human-readable summary
+  floop capabilities --json   # Machine-readable object`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			caps := capabilities.Describe(version)
+			out := cmd.OutOrStdout()
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(caps)
+			}
+
+			fmt.Fprintf(out, "floop %s (schema version %d)\n\n", caps.Version, caps.SchemaVersion)
+			fmt.Fprintf(out, "Features:        %s\n", strings.Join(caps.Features, ", "))
+			fmt.Fprintf(out, "MCP tools:       %s\n", strings.Join(caps.Tools, ", "))
+			fmt.Fprintf(out, "MCP resources:   %s\n", strings.Join(caps.Resources, ", "))
+			fmt.Fprintf(out, "Edge kinds:      %s\n", strings.Join(caps.EdgeKinds, ", "))
+			fmt.Fprintf(out, "User edge kinds: %s\n", strings.Join(caps.UserEdgeKinds, ", "))
+			fmt.Fprintf(out, "Config keys:     %s\n", strings.Join(caps.ConfigKeys, ", "))
+			return nil
+		},
+	}
+}
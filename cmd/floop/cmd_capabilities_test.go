@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/capabilities"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestCapabilitiesCmd(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newCapabilitiesCmd())
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs([]string{"capabilities", "--json"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("capabilities --json failed: %v", err)
+		}
+
+		var caps capabilities.Capabilities
+		if err := json.Unmarshal(buf.Bytes(), &caps); err != nil {
+			t.Fatalf("invalid JSON output: %v\n%s", err, buf.String())
+		}
+		if caps.SchemaVersion != store.SchemaVersion || !caps.Has("learn") {
+			t.Errorf("capabilities = %+v", caps)
+		}
+	})
+
+	t.Run("text", func(t *testing.T) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newCapabilitiesCmd())
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs([]string{"capabilities"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("capabilities failed: %v", err)
+		}
+		for _, want := range []string{"schema version", "floop_active", "co-activated", "llm.provider"} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("output missing %q:\n%s", want, buf.String())
+			}
+		}
+	})
+}
@@ -5,9 +5,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/nvandessel/floop/internal/assembly"
 	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/publish"
+	"github.com/nvandessel/floop/internal/replica"
+	"github.com/nvandessel/floop/internal/utils"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -68,12 +76,99 @@ func newConfigListCmd() *cobra.Command {
 				fmt.Printf("  llm.base_url:          %s\n", valueOrDefault(cfg.LLM.BaseURL, "(default)"))
 				fmt.Printf("  llm.comparison_model:  %s\n", valueOrDefault(cfg.LLM.ComparisonModel, "(default)"))
 				fmt.Printf("  llm.merge_model:       %s\n", valueOrDefault(cfg.LLM.MergeModel, "(default)"))
+				fmt.Printf("  llm.embedding_model:   %s\n", valueOrDefault(cfg.LLM.EmbeddingModel, "(not set)"))
 				fmt.Printf("  llm.timeout:           %v\n", cfg.LLM.Timeout)
 				fmt.Printf("  llm.fallback_to_rules: %v\n", cfg.LLM.FallbackToRules)
 				fmt.Println()
 				fmt.Println("Deduplication Settings:")
 				fmt.Printf("  deduplication.auto_merge:            %v\n", cfg.Deduplication.AutoMerge)
 				fmt.Printf("  deduplication.similarity_threshold:  %.2f\n", cfg.Deduplication.SimilarityThreshold)
+				fmt.Printf("  deduplication.similarity:            %s\n", valueOrDefault(cfg.Deduplication.Similarity, "auto"))
+				fmt.Println()
+				fmt.Println("Prompt Settings:")
+				fmt.Printf("  prompt.ordering:  %s\n", valueOrDefault(cfg.Prompt.Ordering, "kind"))
+				fmt.Printf("  prompt.packing:   %s\n", valueOrDefault(cfg.Prompt.Packing, "importance"))
+				fmt.Printf("  prompt.budgets:   %s\n", valueOrDefault(formatKindBudgets(cfg.Prompt.Budgets), "(none)"))
+				fmt.Println()
+				fmt.Println("Quality Gate Settings:")
+				fmt.Printf("  quality.enabled:    %v\n", cfg.Quality.Enabled)
+				fmt.Printf("  quality.min_score:  %.2f\n", cfg.Quality.MinScore)
+				fmt.Printf("  quality.use_llm:    %v\n", cfg.Quality.UseLLM)
+				fmt.Println()
+				fmt.Println("Storage Limits (0 = unlimited):")
+				fmt.Printf("  limits.max_behaviors_per_scope:  %d\n", cfg.Limits.MaxBehaviorsPerScope)
+				fmt.Printf("  limits.max_canonical_length:     %d\n", cfg.Limits.MaxCanonicalLength)
+				fmt.Printf("  limits.max_edges_per_node:       %d\n", cfg.Limits.MaxEdgesPerNode)
+				fmt.Println()
+				fmt.Println("Sleep Phase Settings:")
+				fmt.Printf("  consolidation.sleep.enabled:   %v\n", cfg.Consolidation.Sleep.Enabled)
+				fmt.Printf("  consolidation.sleep.interval:  %s\n", cfg.Consolidation.Sleep.Interval)
+				fmt.Println()
+				fmt.Println("Decay Settings:")
+				fmt.Printf("  decay.enabled:            %v\n", cfg.Decay.Enabled)
+				fmt.Printf("  decay.interval:           %s\n", cfg.Decay.Interval)
+				fmt.Printf("  decay.demote_after_days:  %d\n", cfg.Decay.DemoteAfterDays)
+				fmt.Printf("  decay.prune_after_days:   %d (0 = never)\n", cfg.Decay.PruneAfterDays)
+				fmt.Println()
+				fmt.Println("External Ranking Settings:")
+				fmt.Printf("  ranking.external.enabled:  %v\n", cfg.Ranking.External.Enabled)
+				fmt.Printf("  ranking.external.command:  %s\n", valueOrDefault(cfg.Ranking.External.Command, "(not set)"))
+				fmt.Printf("  ranking.external.url:      %s\n", valueOrDefault(cfg.Ranking.External.URL, "(not set)"))
+				fmt.Printf("  ranking.external.timeout:  %v\n", cfg.Ranking.External.Timeout)
+				fmt.Println()
+				fmt.Println("Match Settings:")
+				fmt.Printf("  ranking.match.mode:       %s\n", valueOrDefault(cfg.Ranking.Match.Mode, "strict"))
+				fmt.Printf("  ranking.match.min_score:  %.2f\n", cfg.Ranking.Match.MinScore)
+				fmt.Println()
+				fmt.Println("Temporal Ranking Settings:")
+				fmt.Printf("  ranking.temporal.enabled:     %v\n", cfg.Ranking.Temporal.Enabled)
+				fmt.Printf("  ranking.temporal.weight:      %.2f (0 = default, %.2f)\n", cfg.Ranking.Temporal.Weight, constants.DefaultTemporalWeight)
+				fmt.Printf("  ranking.temporal.busy_calls:  %d (0 = default, %d)\n", cfg.Ranking.Temporal.BusyCalls, constants.DefaultTemporalBusyCalls)
+				fmt.Println()
+				fmt.Println("Activation Context Settings:")
+				fmt.Printf("  activation.sniff_content:  %v\n", cfg.Activation.SniffContent)
+				fmt.Printf("  activation.standing_seeds: %d configured\n", len(cfg.Activation.StandingSeeds))
+				fmt.Printf("  activation.edge_half_life: %v (0 = default, ~69h)\n", cfg.Activation.EdgeHalfLife)
+				fmt.Println()
+				fmt.Println("Rate Limit Settings:")
+				fmt.Printf("  rate_limit.max_wait:        %v\n", cfg.RateLimit.MaxWait)
+				fmt.Printf("  rate_limit.global_ceiling:  %v (x each tool's per-client limit)\n", cfg.RateLimit.Ceiling())
+				fmt.Println()
+				fmt.Println("MCP Tool Deadlines:")
+				fmt.Printf("  tool_timeouts.default:  %v (0 = default, %ds; negative disables)\n", cfg.ToolTimeouts.Default, constants.DefaultToolTimeoutSeconds)
+				fmt.Printf("  tool_timeouts.tools:    %d configured\n", len(cfg.ToolTimeouts.Tools))
+				fmt.Println()
+				fmt.Println("Learning Policy (floop_learn):")
+				fmt.Printf("  learning.auto_accept_threshold:  %.2f\n", cfg.Learning.AutoAcceptThreshold)
+				fmt.Printf("  learning.auto_merge:             %v\n", cfg.Learning.AutoMerge)
+				fmt.Printf("  learning.auto_merge_threshold:   %.2f\n", cfg.Learning.AutoMergeThreshold)
+				fmt.Printf("  learning.review.constraints:     %v\n", cfg.Learning.Review.Constraints)
+				fmt.Printf("  learning.review.min_confidence:  %.2f\n", cfg.Learning.Review.MinConfidence)
+				fmt.Printf("  learning.review.max_similarity:  %.2f\n", cfg.Learning.Review.MaxSimilarity)
+				fmt.Printf("  learning.scope:                  %s\n", valueOrDefault(cfg.Learning.Scope, "auto"))
+				fmt.Printf("  learning.scopes:                 %d configured\n", len(cfg.Learning.Scopes))
+				fmt.Printf("  learning.extractor:              %s\n", valueOrDefault(cfg.Learning.Extractor, "rules"))
+				fmt.Printf("  learning.circuit_breaker.enabled:          %v\n", cfg.Learning.CircuitBreaker.Enabled)
+				fmt.Printf("  learning.circuit_breaker.max_per_session:  %d\n", cfg.Learning.CircuitBreaker.MaxPerSession)
+				fmt.Printf("  learning.circuit_breaker.window:           %d\n", cfg.Learning.CircuitBreaker.Window)
+				fmt.Printf("  learning.circuit_breaker.similarity:       %.2f\n", cfg.Learning.CircuitBreaker.Similarity)
+				fmt.Printf("  learning.circuit_breaker.max_repeats:      %d\n", cfg.Learning.CircuitBreaker.MaxRepeats)
+				fmt.Printf("  learning.circuit_breaker.cooldown:         %v\n", cfg.Learning.CircuitBreaker.Cooldown)
+				fmt.Println()
+				fmt.Println("Changelog Settings:")
+				fmt.Printf("  changelog.enabled:  %v\n", cfg.Changelog.Enabled)
+				fmt.Println()
+				fmt.Println("Sync Settings:")
+				fmt.Printf("  sync.remote:  %s\n", valueOrDefault(cfg.Sync.Remote, "(project origin)"))
+				fmt.Printf("  sync.branch:  %s\n", cfg.Sync.Branch)
+				fmt.Println()
+				fmt.Println("Store Settings:")
+				fmt.Printf("  stores.scopes:  %s\n", valueOrDefault(strings.Join(cfg.Stores.Scopes, ", "), "(none)"))
+				fmt.Printf("  stores.policy:  %s\n", valueOrDefault(cfg.Stores.Policy, "(none)"))
+				fmt.Println()
+				fmt.Println("Replication Settings:")
+				fmt.Printf("  replication.target:  %s\n", valueOrDefault(cfg.Replication.Target, "(disabled)"))
+				fmt.Printf("  replication.stores:  %s\n", valueOrDefault(cfg.Replication.Stores, "global"))
 			}
 
 			return nil
@@ -182,6 +277,8 @@ func getConfigValue(cfg *config.FloopConfig, key string) (interface{}, bool) {
 		return cfg.LLM.ComparisonModel, true
 	case "llm.merge_model":
 		return cfg.LLM.MergeModel, true
+	case "llm.embedding_model":
+		return cfg.LLM.EmbeddingModel, true
 	case "llm.timeout":
 		return cfg.LLM.Timeout.String(), true
 	case "llm.enabled":
@@ -192,6 +289,104 @@ func getConfigValue(cfg *config.FloopConfig, key string) (interface{}, bool) {
 		return cfg.Deduplication.AutoMerge, true
 	case "deduplication.similarity_threshold":
 		return cfg.Deduplication.SimilarityThreshold, true
+	case "deduplication.similarity":
+		return cfg.Deduplication.Similarity, true
+	case "prompt.ordering":
+		return cfg.Prompt.Ordering, true
+	case "prompt.packing":
+		return cfg.Prompt.Packing, true
+	case "quality.enabled":
+		return cfg.Quality.Enabled, true
+	case "quality.min_score":
+		return cfg.Quality.MinScore, true
+	case "quality.use_llm":
+		return cfg.Quality.UseLLM, true
+	case "limits.max_behaviors_per_scope":
+		return cfg.Limits.MaxBehaviorsPerScope, true
+	case "limits.max_canonical_length":
+		return cfg.Limits.MaxCanonicalLength, true
+	case "limits.max_edges_per_node":
+		return cfg.Limits.MaxEdgesPerNode, true
+	case "consolidation.sleep.enabled":
+		return cfg.Consolidation.Sleep.Enabled, true
+	case "consolidation.sleep.interval":
+		return cfg.Consolidation.Sleep.Interval, true
+	case "decay.enabled":
+		return cfg.Decay.Enabled, true
+	case "decay.interval":
+		return cfg.Decay.Interval, true
+	case "decay.demote_after_days":
+		return cfg.Decay.DemoteAfterDays, true
+	case "decay.prune_after_days":
+		return cfg.Decay.PruneAfterDays, true
+	case "ranking.external.enabled":
+		return cfg.Ranking.External.Enabled, true
+	case "ranking.external.command":
+		return cfg.Ranking.External.Command, true
+	case "ranking.external.url":
+		return cfg.Ranking.External.URL, true
+	case "ranking.external.timeout":
+		return cfg.Ranking.External.Timeout.String(), true
+	case "ranking.match.mode":
+		return cfg.Ranking.Match.Mode, true
+	case "ranking.match.min_score":
+		return cfg.Ranking.Match.MinScore, true
+	case "ranking.temporal.enabled":
+		return cfg.Ranking.Temporal.Enabled, true
+	case "ranking.temporal.weight":
+		return cfg.Ranking.Temporal.Weight, true
+	case "ranking.temporal.busy_calls":
+		return cfg.Ranking.Temporal.BusyCalls, true
+	case "activation.sniff_content":
+		return cfg.Activation.SniffContent, true
+	case "activation.edge_half_life":
+		return cfg.Activation.EdgeHalfLife.String(), true
+	case "rate_limit.max_wait":
+		return cfg.RateLimit.MaxWait.String(), true
+	case "rate_limit.global_ceiling":
+		return cfg.RateLimit.Ceiling(), true
+	case "tool_timeouts.default":
+		return cfg.ToolTimeouts.Default.String(), true
+	case "learning.auto_accept_threshold":
+		return cfg.Learning.AutoAcceptThreshold, true
+	case "learning.auto_merge":
+		return cfg.Learning.AutoMerge, true
+	case "learning.auto_merge_threshold":
+		return cfg.Learning.AutoMergeThreshold, true
+	case "learning.review.constraints":
+		return cfg.Learning.Review.Constraints, true
+	case "learning.review.min_confidence":
+		return cfg.Learning.Review.MinConfidence, true
+	case "learning.review.max_similarity":
+		return cfg.Learning.Review.MaxSimilarity, true
+	case "learning.scope":
+		return cfg.Learning.Scope, true
+	case "learning.extractor":
+		return cfg.Learning.Extractor, true
+	case "learning.circuit_breaker.enabled":
+		return cfg.Learning.CircuitBreaker.Enabled, true
+	case "learning.circuit_breaker.max_per_session":
+		return cfg.Learning.CircuitBreaker.MaxPerSession, true
+	case "learning.circuit_breaker.window":
+		return cfg.Learning.CircuitBreaker.Window, true
+	case "learning.circuit_breaker.similarity":
+		return cfg.Learning.CircuitBreaker.Similarity, true
+	case "learning.circuit_breaker.max_repeats":
+		return cfg.Learning.CircuitBreaker.MaxRepeats, true
+	case "learning.circuit_breaker.cooldown":
+		return cfg.Learning.CircuitBreaker.Cooldown.String(), true
+	case "changelog.enabled":
+		return cfg.Changelog.Enabled, true
+	case "sync.remote":
+		return cfg.Sync.Remote, true
+	case "sync.branch":
+		return cfg.Sync.Branch, true
+	case "replication.target":
+		return cfg.Replication.Target, true
+	case "replication.stores":
+		return cfg.Replication.Stores, true
+	case "stores.policy":
+		return cfg.Stores.Policy, true
 	default:
 		return nil, false
 	}
@@ -214,6 +409,8 @@ func setConfigValue(cfg *config.FloopConfig, key, value string) error {
 		cfg.LLM.ComparisonModel = value
 	case "llm.merge_model":
 		cfg.LLM.MergeModel = value
+	case "llm.embedding_model":
+		cfg.LLM.EmbeddingModel = value
 	case "llm.timeout":
 		d, err := time.ParseDuration(value)
 		if err != nil {
@@ -235,6 +432,215 @@ func setConfigValue(cfg *config.FloopConfig, key, value string) error {
 			return fmt.Errorf("threshold must be between 0 and 1, got %f", f)
 		}
 		cfg.Deduplication.SimilarityThreshold = f
+	case "deduplication.similarity":
+		if value != "auto" && value != "embedding" && value != "jaccard" {
+			return fmt.Errorf("invalid similarity: %s (valid: auto, embedding, jaccard)", value)
+		}
+		cfg.Deduplication.Similarity = value
+	case "prompt.ordering":
+		if _, err := assembly.ParseOrderStrategy(value); err != nil {
+			return err
+		}
+		cfg.Prompt.Ordering = value
+	case "prompt.packing":
+		if _, err := assembly.ParsePackingStrategy(value); err != nil {
+			return err
+		}
+		cfg.Prompt.Packing = value
+	case "quality.enabled":
+		cfg.Quality.Enabled = value == "true" || value == "1"
+	case "quality.min_score":
+		var f float64
+		if _, err := fmt.Sscanf(value, "%f", &f); err != nil {
+			return fmt.Errorf("invalid score: %s (must be a number between 0 and 1)", value)
+		}
+		if f < 0 || f > 1 {
+			return fmt.Errorf("score must be between 0 and 1, got %f", f)
+		}
+		cfg.Quality.MinScore = f
+	case "quality.use_llm":
+		cfg.Quality.UseLLM = value == "true" || value == "1"
+	case "limits.max_behaviors_per_scope", "limits.max_canonical_length", "limits.max_edges_per_node":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid limit: %s (must be a non-negative integer, 0 = unlimited)", value)
+		}
+		switch key {
+		case "limits.max_behaviors_per_scope":
+			cfg.Limits.MaxBehaviorsPerScope = n
+		case "limits.max_canonical_length":
+			cfg.Limits.MaxCanonicalLength = n
+		default:
+			cfg.Limits.MaxEdgesPerNode = n
+		}
+	case "consolidation.sleep.enabled":
+		cfg.Consolidation.Sleep.Enabled = value == "true" || value == "1"
+	case "consolidation.sleep.interval":
+		d, err := utils.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid interval: %s (e.g. 24h, 7d)", value)
+		}
+		cfg.Consolidation.Sleep.Interval = value
+	case "decay.enabled":
+		cfg.Decay.Enabled = value == "true" || value == "1"
+	case "decay.interval":
+		d, err := utils.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid interval: %s (e.g. 24h, 7d)", value)
+		}
+		cfg.Decay.Interval = value
+	case "decay.demote_after_days", "decay.prune_after_days":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid days: %s (must be a non-negative integer)", value)
+		}
+		if key == "decay.demote_after_days" {
+			cfg.Decay.DemoteAfterDays = n
+		} else {
+			cfg.Decay.PruneAfterDays = n
+		}
+	case "ranking.external.enabled":
+		cfg.Ranking.External.Enabled = value == "true" || value == "1"
+	case "ranking.external.command":
+		cfg.Ranking.External.Command = value
+	case "ranking.external.url":
+		if value != "" && !strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "https://") {
+			return fmt.Errorf("invalid url: %s (must start with http:// or https://)", value)
+		}
+		cfg.Ranking.External.URL = value
+	case "ranking.external.timeout":
+		d, err := time.ParseDuration(value)
+		limit := constants.MaxExternalScorerTimeoutMs * time.Millisecond
+		if err != nil || d < 0 || d > limit {
+			return fmt.Errorf("invalid timeout: %s (must be a duration up to %v, e.g. 300ms)", value, limit)
+		}
+		cfg.Ranking.External.Timeout = d
+	case "ranking.match.mode":
+		if value != "strict" && value != "graded" {
+			return fmt.Errorf("invalid match mode: %s (valid: strict, graded)", value)
+		}
+		cfg.Ranking.Match.Mode = value
+	case "ranking.match.min_score":
+		var f float64
+		if _, err := fmt.Sscanf(value, "%f", &f); err != nil {
+			return fmt.Errorf("invalid score: %s (must be a number between 0 and 1)", value)
+		}
+		if f < 0 || f > 1 {
+			return fmt.Errorf("score must be between 0 and 1, got %f", f)
+		}
+		cfg.Ranking.Match.MinScore = f
+	case "ranking.temporal.enabled":
+		cfg.Ranking.Temporal.Enabled = value == "true" || value == "1"
+	case "ranking.temporal.weight":
+		var f float64
+		if _, err := fmt.Sscanf(value, "%f", &f); err != nil {
+			return fmt.Errorf("invalid weight: %s (must be a number between 0 and 1)", value)
+		}
+		if f < 0 || f > 1 {
+			return fmt.Errorf("weight must be between 0 and 1, got %f", f)
+		}
+		cfg.Ranking.Temporal.Weight = f
+	case "ranking.temporal.busy_calls":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid busy_calls: %s (must be a non-negative integer)", value)
+		}
+		cfg.Ranking.Temporal.BusyCalls = n
+	case "activation.sniff_content":
+		cfg.Activation.SniffContent = value == "true" || value == "1"
+	case "activation.edge_half_life":
+		d, err := utils.ParseDuration(value)
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid half-life: %s (must be a duration, e.g. 72h or 7d)", value)
+		}
+		cfg.Activation.EdgeHalfLife = d
+	case "rate_limit.max_wait":
+		d, err := time.ParseDuration(value)
+		limit := constants.MaxRateLimitMaxWaitMs * time.Millisecond
+		if err != nil || d < 0 || d > limit {
+			return fmt.Errorf("invalid wait: %s (must be a duration up to %v, e.g. 500ms)", value, limit)
+		}
+		cfg.RateLimit.MaxWait = d
+	case "rate_limit.global_ceiling":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || f < 1 {
+			return fmt.Errorf("invalid ceiling: %s (must be a number, at least 1)", value)
+		}
+		cfg.RateLimit.GlobalCeiling = f
+	case "tool_timeouts.default":
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid timeout: %s (must be a duration, e.g. 30s; negative disables)", value)
+		}
+		cfg.ToolTimeouts.Default = d
+	case "learning.auto_accept_threshold":
+		return setUnitFloat(&cfg.Learning.AutoAcceptThreshold, value)
+	case "learning.auto_merge":
+		cfg.Learning.AutoMerge = value == "true" || value == "1"
+	case "learning.auto_merge_threshold":
+		return setUnitFloat(&cfg.Learning.AutoMergeThreshold, value)
+	case "learning.review.constraints":
+		cfg.Learning.Review.Constraints = value == "true" || value == "1"
+	case "learning.review.min_confidence":
+		return setUnitFloat(&cfg.Learning.Review.MinConfidence, value)
+	case "learning.review.max_similarity":
+		return setUnitFloat(&cfg.Learning.Review.MaxSimilarity, value)
+	case "learning.scope":
+		if value != "auto" && value != "local" && value != "global" {
+			return fmt.Errorf("invalid scope: %s (valid: auto, local, global)", value)
+		}
+		cfg.Learning.Scope = value
+	case "learning.extractor":
+		if value != "rules" && value != "llm" {
+			return fmt.Errorf("invalid extractor: %s (valid: rules, llm)", value)
+		}
+		cfg.Learning.Extractor = value
+	case "learning.circuit_breaker.enabled":
+		cfg.Learning.CircuitBreaker.Enabled = value == "true" || value == "1"
+	case "learning.circuit_breaker.max_per_session", "learning.circuit_breaker.window", "learning.circuit_breaker.max_repeats":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid count: %s (must be a non-negative integer)", value)
+		}
+		switch key {
+		case "learning.circuit_breaker.max_per_session":
+			cfg.Learning.CircuitBreaker.MaxPerSession = n
+		case "learning.circuit_breaker.window":
+			cfg.Learning.CircuitBreaker.Window = n
+		default:
+			cfg.Learning.CircuitBreaker.MaxRepeats = n
+		}
+	case "learning.circuit_breaker.similarity":
+		return setUnitFloat(&cfg.Learning.CircuitBreaker.Similarity, value)
+	case "learning.circuit_breaker.cooldown":
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid cooldown: %s (must be a duration, e.g. 10m)", value)
+		}
+		cfg.Learning.CircuitBreaker.Cooldown = d
+	case "changelog.enabled":
+		cfg.Changelog.Enabled = value == "true" || value == "1"
+	case "sync.remote":
+		cfg.Sync.Remote = value
+	case "sync.branch":
+		if value == "" {
+			return fmt.Errorf("sync.branch cannot be empty")
+		}
+		cfg.Sync.Branch = value
+	case "replication.target":
+		if value != "" {
+			if _, err := publish.ParseTarget(value); err != nil {
+				return err
+			}
+		}
+		cfg.Replication.Target = value
+	case "replication.stores":
+		if _, err := replica.ParseScope(value); err != nil {
+			return err
+		}
+		cfg.Replication.Stores = value
+	case "stores.policy":
+		cfg.Stores.Policy = value
 	default:
 		return fmt.Errorf("unknown configuration key: %s", key)
 	}
@@ -273,3 +679,27 @@ func valueOrDefault(value, defaultValue string) string {
 	}
 	return value
 }
+
+// formatKindBudgets renders per-kind budgets as "kind=value" pairs sorted by
+// kind.
+func formatKindBudgets(budgets map[string]string) string {
+	pairs := make([]string, 0, len(budgets))
+	for kind, budget := range budgets {
+		pairs = append(pairs, kind+"="+budget)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+// setUnitFloat parses value as a number between 0 and 1 into dst.
+func setUnitFloat(dst *float64, value string) error {
+	var f float64
+	if _, err := fmt.Sscanf(value, "%f", &f); err != nil {
+		return fmt.Errorf("invalid threshold: %s (must be a number between 0 and 1)", value)
+	}
+	if f < 0 || f > 1 {
+		return fmt.Errorf("threshold must be between 0 and 1, got %f", f)
+	}
+	*dst = f
+	return nil
+}
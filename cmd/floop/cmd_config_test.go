@@ -58,6 +58,8 @@ func TestGetConfigValue(t *testing.T) {
 		{"llm.merge_model", "llm.merge_model", true},
 		{"deduplication.auto_merge", "deduplication.auto_merge", true},
 		{"deduplication.similarity_threshold", "deduplication.similarity_threshold", true},
+		{"prompt.ordering", "prompt.ordering", true},
+		{"prompt.packing", "prompt.packing", true},
 		{"unknown key", "nonexistent.key", false},
 	}
 
@@ -90,6 +92,16 @@ func TestGetConfigValue(t *testing.T) {
 	}
 }
 
+func TestGetConfigValue_AllKeys(t *testing.T) {
+	// config.Keys is advertised by floop capabilities; every key must resolve
+	cfg := config.Default()
+	for _, key := range config.Keys {
+		if _, found := getConfigValue(cfg, key); !found {
+			t.Errorf("config.Keys lists %q but getConfigValue does not accept it", key)
+		}
+	}
+}
+
 func TestSetConfigValue(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -115,6 +127,50 @@ func TestSetConfigValue(t *testing.T) {
 		{"threshold too high", "deduplication.similarity_threshold", "1.5", true},
 		{"threshold too low", "deduplication.similarity_threshold", "-0.1", true},
 		{"invalid threshold", "deduplication.similarity_threshold", "abc", true},
+		{"valid ordering", "prompt.ordering", "constraints-first", false},
+		{"invalid ordering", "prompt.ordering", "random", true},
+		{"valid packing", "prompt.packing", "value", false},
+		{"invalid packing", "prompt.packing", "insertion", true},
+		{"external scorer command", "ranking.external.command", "python3 rank.py", false},
+		{"external scorer url", "ranking.external.url", "http://localhost:9000/score", false},
+		{"external scorer bad url", "ranking.external.url", "localhost:9000", true},
+		{"external scorer timeout", "ranking.external.timeout", "500ms", false},
+		{"external scorer timeout too long", "ranking.external.timeout", "10s", true},
+		{"graded match mode", "ranking.match.mode", "graded", false},
+		{"invalid match mode", "ranking.match.mode", "fuzzy", true},
+		{"valid match min score", "ranking.match.min_score", "0.6", false},
+		{"match min score too high", "ranking.match.min_score", "1.2", true},
+		{"rate limit max wait", "rate_limit.max_wait", "250ms", false},
+		{"rate limit max wait too long", "rate_limit.max_wait", "5s", true},
+		{"rate limit global ceiling", "rate_limit.global_ceiling", "2.5", false},
+		{"rate limit global ceiling too low", "rate_limit.global_ceiling", "0.5", true},
+		{"tool timeouts default", "tool_timeouts.default", "10s", false},
+		{"tool timeouts disabled", "tool_timeouts.default", "-1s", false},
+		{"tool timeouts invalid", "tool_timeouts.default", "soon", true},
+		{"learning auto accept threshold", "learning.auto_accept_threshold", "0.7", false},
+		{"learning auto accept threshold too high", "learning.auto_accept_threshold", "1.5", true},
+		{"learning review min confidence", "learning.review.min_confidence", "0.4", false},
+		{"learning scope", "learning.scope", "global", false},
+		{"invalid learning scope", "learning.scope", "team", true},
+		{"llm extractor", "learning.extractor", "llm", false},
+		{"edge half-life", "activation.edge_half_life", "7d", false},
+		{"negative edge half-life", "activation.edge_half_life", "-1h", true},
+		{"invalid extractor", "learning.extractor", "regex", true},
+		{"breaker session cap", "learning.circuit_breaker.max_per_session", "20", false},
+		{"negative breaker window", "learning.circuit_breaker.window", "-1", true},
+		{"breaker cooldown", "learning.circuit_breaker.cooldown", "5m", false},
+		{"invalid breaker cooldown", "learning.circuit_breaker.cooldown", "soon", true},
+		{"changelog enabled", "changelog.enabled", "true", false},
+		{"sync remote", "sync.remote", "git@example.com:team/behaviors.git", false},
+		{"sync branch", "sync.branch", "shared", false},
+		{"empty sync branch", "sync.branch", "", true},
+		{"replication target", "replication.target", "s3://backups/floop", false},
+		{"unsupported replication target", "replication.target", "ftp://nas/floop", true},
+		{"replication stores", "replication.stores", "all", false},
+		{"invalid replication stores", "replication.stores", "local", true},
+		{"embedding model", "llm.embedding_model", "text-embedding-3-small", false},
+		{"embedding similarity", "deduplication.similarity", "embedding", false},
+		{"invalid similarity", "deduplication.similarity", "cosine", true},
 		{"unknown key", "nonexistent.key", "value", true},
 	}
 
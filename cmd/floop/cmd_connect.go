@@ -27,6 +27,7 @@ Edge kinds:
   conflicts     - Source and target cannot both be active
   similar-to    - Behaviors are related/similar
   learned-from  - Source was derived from target
+  specializes   - Source is a specific case of the target
 
 Examples:
   floop connect behavior-abc behavior-xyz similar-to
@@ -46,7 +47,7 @@ Examples:
 			// Validate kind
 			edgeKind := store.EdgeKind(kind)
 			if !store.ValidUserEdgeKinds[edgeKind] {
-				return fmt.Errorf("invalid edge kind: %s (must be one of: requires, overrides, conflicts, similar-to, learned-from)", kind)
+				return fmt.Errorf("invalid edge kind: %s (must be one of: requires, overrides, conflicts, similar-to, learned-from, specializes)", kind)
 			}
 
 			// Validate weight
@@ -31,6 +31,7 @@ func newConsolidateCmd() *cobra.Command {
 	cmd.Flags().String("since", "", "Consolidate events since duration (e.g., 24h)")
 	cmd.Flags().Bool("dry-run", false, "Show what would be extracted without promoting")
 	cmd.Flags().String("executor", "", "Consolidation executor: heuristic (default), llm, local")
+	cmd.AddCommand(newConsolidateSleepCmd())
 	return cmd
 }
 
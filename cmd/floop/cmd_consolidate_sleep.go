@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/consolidation"
+	"github.com/nvandessel/floop/internal/outcome"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newConsolidateSleepCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sleep",
+		Short: "Run the sleep-phase maintenance pass over the stores",
+		Long: `Run the sleep phase: an offline maintenance pass over the local and global
+stores, modeled on memory consolidation during sleep.
+
+Steps, in order:
+  backup       Back up both stores before changing anything
+  dedup        Merge duplicate behaviors
+  prune        Remove co-activated and similar-to edges whose decayed weight
+               has fallen to the prune threshold
+  recalibrate  Move confidence toward each behavior's observed followed/
+               overridden rate, counting linked outcomes (floop outcomes)
+  decay        Lower confidence of behaviors not activated or confirmed in
+               decay.demote_after_days (default 90; constraints, authored,
+               and imported behaviors never decay)
+  summarize    Generate summaries for behaviors that lack one
+
+Every change is reported. Reports of real runs are appended to
+~/.floop/sleep_reports.jsonl. Set consolidation.sleep.enabled to run the sleep
+phase automatically from 'floop mcp-server' every consolidation.sleep.interval.
+
+Examples:
+  floop consolidate sleep                     # Run all steps
+  floop consolidate sleep --dry-run           # Show what would change
+  floop consolidate sleep --skip dedup,decay  # Leave out steps`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			skipList, _ := cmd.Flags().GetStringSlice("skip")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			skip := make(map[string]bool, len(skipList))
+			for _, step := range skipList {
+				step = strings.TrimSpace(step)
+				if !isSleepStep(step) {
+					return fmt.Errorf("unknown sleep step %q (valid: %s)", step, strings.Join(consolidation.SleepSteps, ", "))
+				}
+				skip[step] = true
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				cfg = config.Default()
+			}
+			backupDir, err := backup.DefaultBackupDir()
+			if err != nil {
+				return fmt.Errorf("failed to get backup directory: %w", err)
+			}
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			outcomes, err := outcome.LoadStats(filepath.Join(root, ".floop"))
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: failed to load outcomes: %v\n", err)
+			}
+
+			report, err := consolidation.RunSleepPhase(context.Background(), graphStore, consolidation.SleepOptions{
+				DryRun:     dryRun,
+				Skip:       skip,
+				BackupDir:  backupDir,
+				Retention:  buildRetentionPolicy(&cfg.Backup),
+				Outcomes:   outcomes,
+				StaleAfter: time.Duration(cfg.Decay.DemoteAfterDays) * 24 * time.Hour,
+			})
+			if err != nil {
+				return fmt.Errorf("sleep phase failed: %w", err)
+			}
+
+			if !dryRun {
+				homeDir, err := os.UserHomeDir()
+				if err != nil {
+					return fmt.Errorf("failed to get home directory: %w", err)
+				}
+				if err := consolidation.RecordSleepReport(filepath.Join(homeDir, ".floop"), report); err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "warning: failed to record sleep report: %v\n", err)
+				}
+			}
+
+			if jsonOut {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(report)
+			}
+			printSleepReport(cmd.OutOrStdout(), report)
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Show what would change without writing anything")
+	cmd.Flags().StringSlice("skip", nil, "Steps to leave out: "+strings.Join(consolidation.SleepSteps, ", "))
+
+	return cmd
+}
+
+// isSleepStep reports whether name is a sleep-phase step.
+func isSleepStep(name string) bool {
+	for _, step := range consolidation.SleepSteps {
+		if step == name {
+			return true
+		}
+	}
+	return false
+}
+
+// printSleepReport lists every change a sleep phase made.
+func printSleepReport(out io.Writer, report *consolidation.SleepReport) {
+	verb := "Sleep phase complete"
+	if report.DryRun {
+		verb = "Sleep phase dry run"
+	}
+	fmt.Fprintf(out, "%s: %d changes in %s\n", verb, report.Changes(), report.Duration)
+
+	if report.Backup != "" {
+		fmt.Fprintf(out, "\nBackup: %s\n", report.Backup)
+	}
+	if len(report.Merged) > 0 {
+		fmt.Fprintln(out, "\nMerged duplicates:")
+		for _, m := range report.Merged {
+			if report.DryRun {
+				fmt.Fprintf(out, "  %-6s %d duplicates found\n", m.Scope, m.Found)
+				continue
+			}
+			if m.Found > 0 {
+				fmt.Fprintf(out, "  %-6s kept %s, removed %s\n", m.Scope, strings.Join(m.KeptIDs, ", "), strings.Join(m.RemovedIDs, ", "))
+			}
+			if len(m.ParentIDs) > 0 {
+				fmt.Fprintf(out, "  %-6s linked context variants under %s\n", m.Scope, strings.Join(m.ParentIDs, ", "))
+			}
+		}
+	}
+	if len(report.PrunedEdges) > 0 {
+		fmt.Fprintln(out, "\nPruned edges:")
+		for _, e := range report.PrunedEdges {
+			fmt.Fprintf(out, "  %-6s %s -[%s]-> %s (weight %.3f)\n", e.Scope, e.Source, e.Kind, e.Target, e.Weight)
+		}
+	}
+	printConfidenceChanges(out, "Recalibrated confidence:", report.Recalibrated)
+	printConfidenceChanges(out, "Decayed confidence:", report.Decayed)
+	if len(report.Summarized) > 0 {
+		fmt.Fprintln(out, "\nSummarized:")
+		for _, s := range report.Summarized {
+			fmt.Fprintf(out, "  %s: %s\n", s.BehaviorID, s.Summary)
+		}
+	}
+	if len(report.Errors) > 0 {
+		fmt.Fprintln(out, "\nErrors:")
+		for _, e := range report.Errors {
+			fmt.Fprintf(out, "  %s\n", e)
+		}
+	}
+	if report.DryRun {
+		fmt.Fprintln(out, "\n(dry-run: nothing was changed)")
+	}
+}
+
+// printConfidenceChanges lists confidence changes under title.
+func printConfidenceChanges(out io.Writer, title string, changes []consolidation.ConfidenceChange) {
+	if len(changes) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "\n%s\n", title)
+	for _, c := range changes {
+		fmt.Fprintf(out, "  %s: %.2f -> %.2f (%s)\n", c.BehaviorID, c.From, c.To, c.Reason)
+	}
+}
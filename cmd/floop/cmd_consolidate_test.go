@@ -252,3 +252,56 @@ func TestConsolidateCmdDryRunText(t *testing.T) {
 		t.Errorf("expected 'dry-run' in output, got: %s", output)
 	}
 }
+
+func TestConsolidateSleepCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	run := func(args ...string) string {
+		t.Helper()
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newInitCmd(), newLearnCmd(), newConsolidateCmd())
+		rootCmd.SetArgs(args)
+		var outBuf bytes.Buffer
+		rootCmd.SetOut(&outBuf)
+		captured := captureStdout(t, func() {
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("%v failed: %v", args, err)
+			}
+		})
+		return outBuf.String() + captured
+	}
+
+	run("init", "--root", tmpDir)
+	run("learn", "--right", "use pathlib.Path instead of os.path", "--scope", "local", "--root", tmpDir, "--json")
+
+	out := run("consolidate", "sleep", "--dry-run", "--json", "--root", tmpDir)
+	var dry map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &dry); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if dry["dry_run"] != true {
+		t.Errorf("dry_run = %v, want true", dry["dry_run"])
+	}
+	reportsPath := filepath.Join(tmpDir, "home", ".floop", "sleep_reports.jsonl")
+	if _, err := os.Stat(reportsPath); err == nil {
+		t.Error("dry run should not record a report")
+	}
+
+	out = run("consolidate", "sleep", "--skip", "dedup", "--root", tmpDir)
+	if !strings.Contains(out, "Sleep phase complete") || !strings.Contains(out, "Backup:") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if _, err := os.Stat(reportsPath); err != nil {
+		t.Errorf("report not recorded: %v", err)
+	}
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newConsolidateCmd())
+	rootCmd.SetArgs([]string{"consolidate", "sleep", "--skip", "nap", "--root", tmpDir})
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "unknown sleep step") {
+		t.Errorf("expected unknown step error, got %v", err)
+	}
+}
@@ -28,7 +28,7 @@ func TestMergeDuplicatePairsEmpty(t *testing.T) {
 	ctx := context.Background()
 	s := store.NewInMemoryGraphStore()
 
-	count := mergeDuplicatePairs(ctx, s, nil, nil, false)
+	count := mergeDuplicatePairs(ctx, s, nil, nil, false, nil)
 	if count != 0 {
 		t.Errorf("mergeDuplicatePairs with nil duplicates = %d, want 0", count)
 	}
@@ -71,7 +71,7 @@ func TestMergeDuplicatePairsBasic(t *testing.T) {
 		{BehaviorA: &b1, BehaviorB: &b2, Similarity: 0.95},
 	}
 
-	count := mergeDuplicatePairs(ctx, s, duplicates, nil, false)
+	count := mergeDuplicatePairs(ctx, s, duplicates, nil, false, nil)
 	if count != 1 {
 		t.Errorf("mergeDuplicatePairs = %d, want 1", count)
 	}
@@ -119,7 +119,7 @@ func TestMergeDuplicatePairsJSON(t *testing.T) {
 	}
 
 	// jsonOut=true should suppress stderr warnings
-	count := mergeDuplicatePairs(ctx, s, duplicates, nil, true)
+	count := mergeDuplicatePairs(ctx, s, duplicates, nil, true, nil)
 	if count != 1 {
 		t.Errorf("mergeDuplicatePairs JSON mode = %d, want 1", count)
 	}
@@ -144,7 +144,7 @@ func TestMergeDuplicatePairsSkipsAlreadyMerged(t *testing.T) {
 		{BehaviorA: &b2, BehaviorB: &b3, Similarity: 0.90},
 	}
 
-	count := mergeDuplicatePairs(ctx, s, duplicates, nil, false)
+	count := mergeDuplicatePairs(ctx, s, duplicates, nil, false, nil)
 	// Only first pair merged; b2 already merged so second pair skipped
 	if count != 1 {
 		t.Errorf("mergeDuplicatePairs with overlapping = %d, want 1", count)
@@ -616,7 +616,7 @@ func TestDeprecateCmdWithReplacement(t *testing.T) {
 	rootCmd2.AddCommand(newDeprecateCmd())
 	rootCmd2.SetOut(&bytes.Buffer{})
 	rootCmd2.SetArgs([]string{
-		"deprecate", nodes[0].ID,
+		"deprecate", nodes[0].ID, "--force",
 		"--reason", "replaced",
 		"--replacement", nodes[1].ID,
 		"--root", tmpDir,
@@ -4747,7 +4747,7 @@ func TestRestoreCmdNotDeprecated(t *testing.T) {
 	rootCmd := newTestRootCmd()
 	rootCmd.AddCommand(newRestoreCmd())
 	rootCmd.SetOut(&bytes.Buffer{})
-	rootCmd.SetArgs([]string{"restore", behaviorID, "--root", tmpDir})
+	rootCmd.SetArgs([]string{"restore", behaviorID, "--force", "--root", tmpDir})
 
 	err := rootCmd.Execute()
 	if err == nil {
@@ -4786,7 +4786,7 @@ func TestForgetAndRestoreFlow(t *testing.T) {
 	rootCmd2 := newTestRootCmd()
 	rootCmd2.AddCommand(newRestoreCmd())
 	rootCmd2.SetOut(&bytes.Buffer{})
-	rootCmd2.SetArgs([]string{"restore", behaviorID, "--root", tmpDir})
+	rootCmd2.SetArgs([]string{"restore", behaviorID, "--force", "--root", tmpDir})
 
 	if err := rootCmd2.Execute(); err != nil {
 		t.Fatalf("restore failed: %v", err)
@@ -5539,14 +5539,14 @@ func TestDeprecateCmdAlreadyDeprecated(t *testing.T) {
 	rootCmd1 := newTestRootCmd()
 	rootCmd1.AddCommand(newDeprecateCmd())
 	rootCmd1.SetOut(&bytes.Buffer{})
-	rootCmd1.SetArgs([]string{"deprecate", behaviorID, "--reason", "first", "--root", tmpDir})
+	rootCmd1.SetArgs([]string{"deprecate", behaviorID, "--reason", "first", "--force", "--root", tmpDir})
 	rootCmd1.Execute()
 
 	// Try deprecate again
 	rootCmd2 := newTestRootCmd()
 	rootCmd2.AddCommand(newDeprecateCmd())
 	rootCmd2.SetOut(&bytes.Buffer{})
-	rootCmd2.SetArgs([]string{"deprecate", behaviorID, "--reason", "second", "--root", tmpDir})
+	rootCmd2.SetArgs([]string{"deprecate", behaviorID, "--reason", "second", "--force", "--root", tmpDir})
 
 	err := rootCmd2.Execute()
 	if err == nil {
@@ -5794,7 +5794,7 @@ func TestMergeDuplicatePairsWithPairs(t *testing.T) {
 	defer devNull.Close()
 	os.Stdout = w
 
-	count := mergeDuplicatePairs(ctx, s, pairs, nil, false)
+	count := mergeDuplicatePairs(ctx, s, pairs, nil, false, nil)
 
 	w.Close()
 	os.Stdout = old
@@ -5848,7 +5848,7 @@ func TestMergeDuplicatePairsAlreadyMerged(t *testing.T) {
 	defer devNull.Close()
 	os.Stdout = w
 
-	count := mergeDuplicatePairs(ctx, s, pairs, nil, false)
+	count := mergeDuplicatePairs(ctx, s, pairs, nil, false, nil)
 
 	w.Close()
 	os.Stdout = old
@@ -6039,7 +6039,7 @@ func TestRestoreCmdTextModeSuccess(t *testing.T) {
 	rootCmd2 := newTestRootCmd()
 	rootCmd2.AddCommand(newRestoreCmd())
 	rootCmd2.SetOut(&bytes.Buffer{})
-	rootCmd2.SetArgs([]string{"restore", behaviorID, "--root", tmpDir})
+	rootCmd2.SetArgs([]string{"restore", behaviorID, "--force", "--root", tmpDir})
 
 	if err := rootCmd2.Execute(); err != nil {
 		t.Fatalf("restore text mode failed: %v", err)
@@ -7352,7 +7352,7 @@ func TestMergeDuplicatePairsR4(t *testing.T) {
 		},
 	}
 
-	count := mergeDuplicatePairs(ctx, graphStore, pairs, nil, false)
+	count := mergeDuplicatePairs(ctx, graphStore, pairs, nil, false, nil)
 	if count != 1 {
 		t.Errorf("expected 1 merge, got %d", count)
 	}
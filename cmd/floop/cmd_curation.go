@@ -10,7 +10,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/dedup"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/quarantine"
+	"github.com/nvandessel/floop/internal/restorepoint"
+	"github.com/nvandessel/floop/internal/retirement"
 	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -21,13 +28,15 @@ func newForgetCmd() *cobra.Command {
 		Long: `Mark a behavior as forgotten, removing it from active use.
 
 The behavior is not deleted, just marked with kind "forgotten-behavior".
-Use 'floop restore' to undo this action.`,
+Use 'floop restore' to undo this action. The behavior can be given by ID,
+name, or slug (its name without "learned/").`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			force, _ := cmd.Flags().GetBool("force")
 			reason, _ := cmd.Flags().GetString("reason")
+			scope, _ := cmd.Flags().GetString("scope")
 			id := args[0]
 
 			// JSON mode implies force (no interactive prompts)
@@ -49,10 +58,10 @@ Use 'floop restore' to undo this action.`,
 
 			ctx := context.Background()
 
-			// Find the behavior by ID
-			node, err := graphStore.GetNode(ctx, id)
+			// Find the behavior by ID in the requested store(s)
+			node, err := findCurationNode(ctx, graphStore, id, scope)
 			if err != nil {
-				return fmt.Errorf("failed to get behavior: %w", err)
+				return err
 			}
 			if node == nil {
 				if jsonOut {
@@ -64,6 +73,8 @@ Use 'floop restore' to undo this action.`,
 				}
 				return fmt.Errorf("behavior not found: %s", id)
 			}
+			// The behavior may have been given by name or slug
+			id = node.ID
 
 			// Verify it's an active behavior
 			if node.Kind != store.NodeKindBehavior {
@@ -90,28 +101,15 @@ Use 'floop restore' to undo this action.`,
 				if reason != "" {
 					fmt.Printf("Reason: %s\n", reason)
 				}
-				fmt.Print("\nConfirm? [y/N]: ")
-				reader := bufio.NewReader(os.Stdin)
-				response, _ := reader.ReadString('\n')
-				response = strings.TrimSpace(strings.ToLower(response))
-				if response != "y" && response != "yes" {
+				printScopeWarning(node.Origin)
+				if !confirmed() {
 					fmt.Println("Cancelled.")
 					return nil
 				}
 			}
 
 			// Update node to forgotten state
-			now := time.Now()
-			if node.Metadata == nil {
-				node.Metadata = make(map[string]interface{})
-			}
-			node.Metadata["original_kind"] = node.Kind
-			node.Metadata["forgotten_at"] = now.Format(time.RFC3339)
-			node.Metadata["forgotten_by"] = os.Getenv("USER")
-			if reason != "" {
-				node.Metadata["forget_reason"] = reason
-			}
-			node.Kind = store.NodeKindForgotten
+			markForgotten(node, reason, time.Now())
 
 			if err := graphStore.UpdateNode(ctx, *node); err != nil {
 				return fmt.Errorf("failed to update behavior: %w", err)
@@ -127,10 +125,11 @@ Use 'floop restore' to undo this action.`,
 					"id":         id,
 					"name":       name,
 					"reason":     reason,
+					"scope":      node.Origin,
 					"restorable": true,
 				})
 			} else {
-				fmt.Printf("Behavior '%s' has been forgotten.\n", name)
+				fmt.Printf("Behavior '%s' has been forgotten (%s store).\n", name, node.Origin)
 				fmt.Println("Use 'floop restore' to undo this action.")
 			}
 
@@ -140,10 +139,172 @@ Use 'floop restore' to undo this action.`,
 
 	cmd.Flags().Bool("force", false, "Skip confirmation prompt")
 	cmd.Flags().String("reason", "", "Reason for forgetting")
+	addCurationScopeFlag(cmd)
 
 	return cmd
 }
 
+func newRetireCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retire <behavior-id>",
+		Short: "Retire a behavior with a grace period before it is forgotten",
+		Long: `Retire a behavior: stop injecting it, but keep watching its topic.
+
+During the grace period every new correction is compared against the
+retired behavior. If one recurs on its topic, the behavior is restored
+automatically with a note explaining why. If none does, it is forgotten
+when the grace period ends. Either outcome appears in the next
+session-start digest.
+
+Use 'floop restore' to bring a retired behavior back by hand.`,
+		Example: `  floop retire b-123
+  floop retire b-123 --grace 30d --reason "superseded by linter rule"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			force, _ := cmd.Flags().GetBool("force")
+			reason, _ := cmd.Flags().GetString("reason")
+			graceStr, _ := cmd.Flags().GetString("grace")
+			scope, _ := cmd.Flags().GetString("scope")
+			id := args[0]
+
+			grace, err := utils.ParseDuration(graceStr)
+			if err != nil || grace <= 0 {
+				return fmt.Errorf("invalid --grace %q: want a positive duration such as 14d or 72h", graceStr)
+			}
+
+			// JSON mode implies force (no interactive prompts)
+			if jsonOut {
+				force = true
+			}
+
+			floopDir := filepath.Join(root, ".floop")
+			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
+				return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+			}
+
+			// Open graph store
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			ctx := context.Background()
+
+			// Find the behavior by ID in the requested store(s)
+			node, err := findCurationNode(ctx, graphStore, id, scope)
+			if err != nil {
+				return err
+			}
+			if node == nil {
+				if jsonOut {
+					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+						"error": "behavior not found",
+						"id":    id,
+					})
+					return nil
+				}
+				return fmt.Errorf("behavior not found: %s", id)
+			}
+			// The behavior may have been given by name or slug
+			id = node.ID
+
+			// Verify it's an active behavior
+			if node.Kind != store.NodeKindBehavior {
+				if jsonOut {
+					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+						"error":        "not an active behavior",
+						"id":           id,
+						"current_kind": node.Kind,
+					})
+					return nil
+				}
+				return fmt.Errorf("not an active behavior (current kind: %s)", node.Kind)
+			}
+
+			// Get behavior name for display
+			name := id
+			if n, ok := node.Content["name"].(string); ok {
+				name = n
+			}
+
+			// Confirm unless --force
+			if !force {
+				fmt.Printf("Retire behavior: %s\n", name)
+				if reason != "" {
+					fmt.Printf("Reason: %s\n", reason)
+				}
+				printScopeWarning(node.Origin)
+				if !confirmed() {
+					fmt.Println("Cancelled.")
+					return nil
+				}
+			}
+
+			now := time.Now()
+			retirement.Retire(node, reason, grace, now)
+			until, _ := retirement.GraceEnd(*node)
+
+			if err := graphStore.UpdateNode(ctx, *node); err != nil {
+				return fmt.Errorf("failed to update behavior: %w", err)
+			}
+
+			if err := graphStore.Sync(ctx); err != nil {
+				return fmt.Errorf("failed to sync changes: %w", err)
+			}
+
+			if jsonOut {
+				json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"status":        "retired",
+					"id":            id,
+					"name":          name,
+					"reason":        reason,
+					"scope":         node.Origin,
+					"retired_until": until.Format(time.RFC3339),
+				})
+			} else {
+				fmt.Printf("Behavior '%s' has been retired (%s store).\n", name, node.Origin)
+				fmt.Printf("It will be forgotten on %s unless a correction recurs on its topic.\n", until.Local().Format("2006-01-02"))
+				fmt.Println("Use 'floop restore' to undo this action.")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("force", false, "Skip confirmation prompt")
+	cmd.Flags().String("reason", "", "Reason for retiring")
+	cmd.Flags().String("grace", fmt.Sprintf("%dd", constants.DefaultRetirementGraceDays), "Grace period before the behavior is forgotten (e.g. 14d, 72h)")
+	addCurationScopeFlag(cmd)
+
+	return cmd
+}
+
+// sweepRetiredForDigest forgets retired behaviors past their grace period
+// and returns the digest of every retirement notice not yet shown. Errors
+// yield an empty digest so hooks stay silent.
+func sweepRetiredForDigest(root string) string {
+	floopDir := filepath.Join(root, ".floop")
+
+	graphStore, err := store.NewMultiGraphStore(root)
+	if err != nil {
+		return ""
+	}
+	notices, err := retirement.Sweep(context.Background(), graphStore, time.Now(), false)
+	graphStore.Close()
+	if err == nil {
+		_ = retirement.RecordNotices(floopDir, notices)
+	}
+
+	pending, err := retirement.TakeNotices(floopDir)
+	if err != nil {
+		return ""
+	}
+	return retirement.FormatDigest(pending)
+}
+
 func newDeprecateCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "deprecate <behavior-id>",
@@ -158,8 +319,15 @@ Use --replacement to link to a newer behavior.`,
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			reason, _ := cmd.Flags().GetString("reason")
 			replacement, _ := cmd.Flags().GetString("replacement")
+			force, _ := cmd.Flags().GetBool("force")
+			scope, _ := cmd.Flags().GetString("scope")
 			id := args[0]
 
+			// JSON mode implies force (no interactive prompts)
+			if jsonOut {
+				force = true
+			}
+
 			floopDir := filepath.Join(root, ".floop")
 			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
 				return fmt.Errorf(".floop not initialized. Run 'floop init' first")
@@ -179,10 +347,10 @@ Use --replacement to link to a newer behavior.`,
 
 			ctx := context.Background()
 
-			// Find the behavior by ID
-			node, err := graphStore.GetNode(ctx, id)
+			// Find the behavior by ID in the requested store(s)
+			node, err := findCurationNode(ctx, graphStore, id, scope)
 			if err != nil {
-				return fmt.Errorf("failed to get behavior: %w", err)
+				return err
 			}
 			if node == nil {
 				if jsonOut {
@@ -194,6 +362,8 @@ Use --replacement to link to a newer behavior.`,
 				}
 				return fmt.Errorf("behavior not found: %s", id)
 			}
+			// The behavior may have been given by name or slug
+			id = node.ID
 
 			// Verify it's an active behavior
 			if node.Kind != store.NodeKindBehavior {
@@ -225,13 +395,23 @@ Use --replacement to link to a newer behavior.`,
 				name = n
 			}
 
+			// Mutating the global store affects every project: confirm first
+			if node.Origin == store.OriginGlobal && !force {
+				fmt.Printf("Deprecate behavior: %s\n", name)
+				printScopeWarning(node.Origin)
+				if !confirmed() {
+					fmt.Println("Cancelled.")
+					return nil
+				}
+			}
+
 			// Update node to deprecated state
 			now := time.Now()
 			if node.Metadata == nil {
 				node.Metadata = make(map[string]interface{})
 			}
 			node.Metadata["original_kind"] = node.Kind
-			node.Metadata["deprecated_at"] = now.Format(time.RFC3339)
+			node.Metadata["deprecated_at"] = now.UTC().Format(time.RFC3339)
 			node.Metadata["deprecated_by"] = os.Getenv("USER")
 			node.Metadata["deprecation_reason"] = reason
 			if replacement != "" {
@@ -252,7 +432,7 @@ Use --replacement to link to a newer behavior.`,
 					Weight:    1.0,
 					CreatedAt: now,
 					Metadata: map[string]interface{}{
-						"created_at": now.Format(time.RFC3339),
+						"created_at": now.UTC().Format(time.RFC3339),
 					},
 				}
 				if err := graphStore.AddEdge(ctx, edge); err != nil {
@@ -270,6 +450,7 @@ Use --replacement to link to a newer behavior.`,
 					"id":         id,
 					"name":       name,
 					"reason":     reason,
+					"scope":      node.Origin,
 					"restorable": true,
 				}
 				if replacement != "" {
@@ -277,7 +458,7 @@ Use --replacement to link to a newer behavior.`,
 				}
 				json.NewEncoder(os.Stdout).Encode(result)
 			} else {
-				fmt.Printf("Behavior '%s' has been deprecated.\n", name)
+				fmt.Printf("Behavior '%s' has been deprecated (%s store).\n", name, node.Origin)
 				fmt.Printf("Reason: %s\n", reason)
 				if replacement != "" {
 					fmt.Printf("Replacement: %s\n", replacement)
@@ -291,6 +472,8 @@ Use --replacement to link to a newer behavior.`,
 
 	cmd.Flags().String("reason", "", "Reason for deprecation (required)")
 	cmd.Flags().String("replacement", "", "ID of behavior that replaces this one")
+	cmd.Flags().Bool("force", false, "Skip confirmation prompt for global behaviors")
+	addCurationScopeFlag(cmd)
 
 	return cmd
 }
@@ -298,16 +481,25 @@ Use --replacement to link to a newer behavior.`,
 func newRestoreCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "restore <behavior-id>",
-		Short: "Restore a deprecated or forgotten behavior",
-		Long: `Restore a behavior that was previously deprecated or forgotten.
+		Short: "Restore a deprecated, forgotten, retired, or quarantined behavior",
+		Long: `Restore a behavior that was previously deprecated, forgotten, retired, or
+quarantined.
 
-This undoes 'floop forget' or 'floop deprecate'.`,
+This undoes 'floop forget', 'floop deprecate', or 'floop retire', and
+releases a behavior quarantined as a possible prompt injection after review.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
+			force, _ := cmd.Flags().GetBool("force")
+			scope, _ := cmd.Flags().GetString("scope")
 			id := args[0]
 
+			// JSON mode implies force (no interactive prompts)
+			if jsonOut {
+				force = true
+			}
+
 			floopDir := filepath.Join(root, ".floop")
 			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
 				return fmt.Errorf(".floop not initialized. Run 'floop init' first")
@@ -322,10 +514,10 @@ This undoes 'floop forget' or 'floop deprecate'.`,
 
 			ctx := context.Background()
 
-			// Find the behavior by ID
-			node, err := graphStore.GetNode(ctx, id)
+			// Find the behavior by ID in the requested store(s)
+			node, err := findCurationNode(ctx, graphStore, id, scope)
 			if err != nil {
-				return fmt.Errorf("failed to get behavior: %w", err)
+				return err
 			}
 			if node == nil {
 				if jsonOut {
@@ -337,18 +529,22 @@ This undoes 'floop forget' or 'floop deprecate'.`,
 				}
 				return fmt.Errorf("behavior not found: %s", id)
 			}
+			// The behavior may have been given by name or slug
+			id = node.ID
 
-			// Verify it's restorable (deprecated or forgotten)
-			if node.Kind != store.NodeKindDeprecated && node.Kind != store.NodeKindForgotten {
+			// Verify it's restorable (deprecated, forgotten, retired, or quarantined)
+			switch node.Kind {
+			case store.NodeKindDeprecated, store.NodeKindForgotten, store.NodeKindRetired, store.NodeKindQuarantined:
+			default:
 				if jsonOut {
 					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-						"error":        "behavior is not deprecated or forgotten",
+						"error":        "behavior is not deprecated, forgotten, retired, or quarantined",
 						"id":           id,
 						"current_kind": node.Kind,
 					})
 					return nil
 				}
-				return fmt.Errorf("behavior is not deprecated or forgotten (current kind: %s)", node.Kind)
+				return fmt.Errorf("behavior is not deprecated, forgotten, retired, or quarantined (current kind: %s)", node.Kind)
 			}
 
 			// Get behavior name for display
@@ -357,6 +553,16 @@ This undoes 'floop forget' or 'floop deprecate'.`,
 				name = n
 			}
 
+			// Mutating the global store affects every project: confirm first
+			if node.Origin == store.OriginGlobal && !force {
+				fmt.Printf("Restore behavior: %s\n", name)
+				printScopeWarning(node.Origin)
+				if !confirmed() {
+					fmt.Println("Cancelled.")
+					return nil
+				}
+			}
+
 			previousKind := node.Kind
 
 			// Restore original kind
@@ -368,7 +574,7 @@ This undoes 'floop forget' or 'floop deprecate'.`,
 
 			// Record restoration
 			now := time.Now()
-			node.Metadata["restored_at"] = now.Format(time.RFC3339)
+			node.Metadata["restored_at"] = now.UTC().Format(time.RFC3339)
 			node.Metadata["restored_by"] = os.Getenv("USER")
 
 			// Clean up curation metadata
@@ -380,6 +586,14 @@ This undoes 'floop forget' or 'floop deprecate'.`,
 			delete(node.Metadata, "deprecated_by")
 			delete(node.Metadata, "deprecation_reason")
 			delete(node.Metadata, "replacement_id")
+			retirement.ClearMetadata(node)
+			quarantine.ClearMetadata(node)
+
+			// A passed expiry would deprecate the behavior again on the next sweep
+			if b := models.NodeToBehavior(*node); b.IsExpired(now) {
+				delete(node.Metadata, "expires_at")
+				delete(node.Metadata, "valid_until")
+			}
 
 			if err := graphStore.UpdateNode(ctx, *node); err != nil {
 				return fmt.Errorf("failed to update behavior: %w", err)
@@ -406,15 +620,19 @@ This undoes 'floop forget' or 'floop deprecate'.`,
 					"name":          name,
 					"previous_kind": previousKind,
 					"current_kind":  originalKind,
+					"scope":         node.Origin,
 				})
 			} else {
-				fmt.Printf("Behavior '%s' has been restored.\n", name)
+				fmt.Printf("Behavior '%s' has been restored (%s store).\n", name, node.Origin)
 			}
 
 			return nil
 		},
 	}
 
+	cmd.Flags().Bool("force", false, "Skip confirmation prompt for global behaviors")
+	addCurationScopeFlag(cmd)
+
 	return cmd
 }
 
@@ -424,16 +642,24 @@ func newMergeCmd() *cobra.Command {
 		Short: "Merge two behaviors into one",
 		Long: `Combine two similar behaviors into one.
 
-The source behavior is marked as merged and linked to the target.
+The source behavior is marked as merged and linked to the target. Either
+can be given by ID, name, or slug (its name without "learned/").
 Use --into to specify which behavior survives (default: target).
 
-This action cannot be undone with restore.`,
+When-conditions are combined so the result applies wherever either
+behavior did: differing values of one key become a list, and other
+conflicts an "any" OR-group. Conditions that cannot be combined, such as
+two different "any" groups, refuse the merge.
+
+This action cannot be undone with restore. A restore point is saved first;
+undo the merge with 'floop restore-point apply <id>'.`,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			force, _ := cmd.Flags().GetBool("force")
 			into, _ := cmd.Flags().GetString("into")
+			scope, _ := cmd.Flags().GetString("scope")
 			sourceID := args[0]
 			targetID := args[1]
 
@@ -464,21 +690,25 @@ This action cannot be undone with restore.`,
 			ctx := context.Background()
 
 			// Load both behaviors
-			sourceNode, err := graphStore.GetNode(ctx, sourceID)
+			sourceNode, err := findCurationNode(ctx, graphStore, sourceID, scope)
 			if err != nil {
-				return fmt.Errorf("failed to get source behavior: %w", err)
+				return err
 			}
 			if sourceNode == nil {
 				return fmt.Errorf("source behavior not found: %s", sourceID)
 			}
 
-			targetNode, err := graphStore.GetNode(ctx, targetID)
+			targetNode, err := findCurationNode(ctx, graphStore, targetID, scope)
 			if err != nil {
-				return fmt.Errorf("failed to get target behavior: %w", err)
+				return err
 			}
 			if targetNode == nil {
 				return fmt.Errorf("target behavior not found: %s", targetID)
 			}
+			sourceID, targetID = sourceNode.ID, targetNode.ID
+			if sourceID == targetID {
+				return fmt.Errorf("cannot merge a behavior into itself: %s", sourceID)
+			}
 
 			// Verify both are active behaviors
 			if sourceNode.Kind != store.NodeKindBehavior {
@@ -501,107 +731,21 @@ This action cannot be undone with restore.`,
 			// Confirm unless --force
 			if !force {
 				fmt.Printf("Merge behaviors:\n")
-				fmt.Printf("  Source (will be merged): %s\n", sourceName)
-				fmt.Printf("  Target (will survive):   %s\n", targetName)
-				fmt.Println("\nThis action cannot be undone.")
-				fmt.Print("\nConfirm? [y/N]: ")
-				reader := bufio.NewReader(os.Stdin)
-				response, _ := reader.ReadString('\n')
-				response = strings.TrimSpace(strings.ToLower(response))
-				if response != "y" && response != "yes" {
+				fmt.Printf("  Source (will be merged): %s (%s)\n", sourceName, sourceNode.Origin)
+				fmt.Printf("  Target (will survive):   %s (%s)\n", targetName, targetNode.Origin)
+				fmt.Println("\nThis action cannot be undone with restore (a restore point is saved first).")
+				if sourceNode.Origin == store.OriginGlobal || targetNode.Origin == store.OriginGlobal {
+					printScopeWarning(store.OriginGlobal)
+				}
+				if !confirmed() {
 					fmt.Println("Cancelled.")
 					return nil
 				}
 			}
 
-			now := time.Now()
-
-			// Merge when conditions (union)
-			sourceWhen, _ := sourceNode.Content["when"].(map[string]interface{})
-			targetWhen, _ := targetNode.Content["when"].(map[string]interface{})
-			if targetWhen == nil {
-				targetWhen = make(map[string]interface{})
-			}
-			for k, v := range sourceWhen {
-				if _, exists := targetWhen[k]; !exists {
-					targetWhen[k] = v
-				}
-			}
-			targetNode.Content["when"] = targetWhen
-
-			// Keep higher confidence
-			sourceConf, _ := sourceNode.Metadata["confidence"].(float64)
-			targetConf, _ := targetNode.Metadata["confidence"].(float64)
-			if sourceConf > targetConf {
-				targetNode.Metadata["confidence"] = sourceConf
-			}
-
-			// Keep higher priority
-			sourcePrio, _ := sourceNode.Metadata["priority"].(int)
-			targetPrio, _ := targetNode.Metadata["priority"].(int)
-			if sourcePrio > targetPrio {
-				targetNode.Metadata["priority"] = sourcePrio
-			}
-
-			// Track merge in target metadata
-			mergedFrom, _ := targetNode.Metadata["merged_from"].([]interface{})
-			mergedFrom = append(mergedFrom, sourceID)
-			targetNode.Metadata["merged_from"] = mergedFrom
-			targetNode.Metadata["last_merge_at"] = now.Format(time.RFC3339)
-
-			// Update target
-			if err := graphStore.UpdateNode(ctx, *targetNode); err != nil {
-				return fmt.Errorf("failed to update target behavior: %w", err)
-			}
-
-			// Mark source as merged
-			if sourceNode.Metadata == nil {
-				sourceNode.Metadata = make(map[string]interface{})
-			}
-			sourceNode.Metadata["original_kind"] = sourceNode.Kind
-			sourceNode.Metadata["merged_into"] = targetID
-			sourceNode.Metadata["merged_at"] = now.Format(time.RFC3339)
-			sourceNode.Metadata["merged_by"] = os.Getenv("USER")
-			sourceNode.Kind = store.NodeKindMerged
-
-			if err := graphStore.UpdateNode(ctx, *sourceNode); err != nil {
-				return fmt.Errorf("failed to update source behavior: %w", err)
-			}
-
-			// Add merged-into edge
-			edge := store.Edge{
-				Source:    sourceID,
-				Target:    targetID,
-				Kind:      store.EdgeKindMergedInto,
-				Weight:    1.0,
-				CreatedAt: now,
-				Metadata: map[string]interface{}{
-					"merged_at": now.Format(time.RFC3339),
-				},
-			}
-			if err := graphStore.AddEdge(ctx, edge); err != nil {
-				return fmt.Errorf("failed to add merge edge: %w", err)
-			}
-
-			// Redirect edges that pointed to source to point to target
-			inboundEdges, err := graphStore.GetEdges(ctx, sourceID, store.DirectionInbound, "")
-			if err == nil {
-				for _, e := range inboundEdges {
-					if e.Kind != store.EdgeKindMergedInto { // Don't redirect the edge we just added
-						// Remove old edge
-						_ = graphStore.RemoveEdge(ctx, e.Source, e.Target, e.Kind)
-						// Defensive fallback for legacy edges missing Weight/CreatedAt
-						if e.Weight <= 0 {
-							e.Weight = 1.0
-						}
-						if e.CreatedAt.IsZero() {
-							e.CreatedAt = now
-						}
-						// Add redirected edge
-						e.Target = targetID
-						_ = graphStore.AddEdge(ctx, e)
-					}
-				}
+			point, conflicts, err := mergeBehaviorNodes(ctx, graphStore, root, sourceNode, targetNode)
+			if err != nil {
+				return err
 			}
 
 			if err := graphStore.Sync(ctx); err != nil {
@@ -610,16 +754,25 @@ This action cannot be undone with restore.`,
 
 			if jsonOut {
 				json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-					"status":       "merged",
-					"source_id":    sourceID,
-					"source_name":  sourceName,
-					"target_id":    targetID,
-					"target_name":  targetName,
-					"surviving_id": targetID,
+					"status":         "merged",
+					"source_id":      sourceID,
+					"source_name":    sourceName,
+					"target_id":      targetID,
+					"target_name":    targetName,
+					"surviving_id":   targetID,
+					"source_scope":   sourceNode.Origin,
+					"target_scope":   targetNode.Origin,
+					"restore_point":  point.ID,
+					"when":           targetNode.Content["when"],
+					"when_conflicts": conflicts,
 				})
 			} else {
 				fmt.Printf("Behaviors merged successfully.\n")
-				fmt.Printf("  '%s' has been merged into '%s'\n", sourceName, targetName)
+				fmt.Printf("  '%s' (%s store) has been merged into '%s' (%s store)\n", sourceName, sourceNode.Origin, targetName, targetNode.Origin)
+				for _, c := range conflicts {
+					fmt.Printf("  When-conditions combined: %s\n", c)
+				}
+				fmt.Printf("  Undo with: floop restore-point apply %s\n", point.ID)
 			}
 
 			return nil
@@ -628,6 +781,181 @@ This action cannot be undone with restore.`,
 
 	cmd.Flags().Bool("force", false, "Skip confirmation prompt")
 	cmd.Flags().String("into", "", "ID of behavior that should survive (default: second argument)")
+	addCurationScopeFlag(cmd)
 
 	return cmd
 }
+
+// markForgotten moves node to the forgotten state, recording reason if set.
+// The caller writes the node back to the store.
+func markForgotten(node *store.Node, reason string, now time.Time) {
+	if node.Metadata == nil {
+		node.Metadata = make(map[string]interface{})
+	}
+	node.Metadata["original_kind"] = node.Kind
+	node.Metadata["forgotten_at"] = now.UTC().Format(time.RFC3339)
+	node.Metadata["forgotten_by"] = os.Getenv("USER")
+	if reason != "" {
+		node.Metadata["forget_reason"] = reason
+	}
+	node.Kind = store.NodeKindForgotten
+}
+
+// mergeBehaviorNodes merges sourceNode into targetNode after saving a
+// restore point: the target takes the combined when-conditions (see
+// dedup.MergeWhen) and the higher confidence and priority, the source is
+// marked merged, and edges into the source are redirected to the target.
+// It returns the when-condition conflicts the merge resolved; conditions
+// that cannot be combined refuse the merge before anything is changed. The
+// caller syncs the store.
+func mergeBehaviorNodes(ctx context.Context, graphStore *store.MultiGraphStore, root string, sourceNode, targetNode *store.Node) (*restorepoint.Point, []dedup.WhenConflict, error) {
+	sourceID, targetID := sourceNode.ID, targetNode.ID
+	sourceWhen, _ := sourceNode.Content["when"].(map[string]interface{})
+	targetWhen, _ := targetNode.Content["when"].(map[string]interface{})
+	when, conflicts, err := dedup.MergeWhen(targetWhen, sourceWhen)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot merge %s into %s: %w", sourceID, targetID, err)
+	}
+
+	point, err := restorepoint.Create(ctx, graphStore, root, "merge",
+		fmt.Sprintf("merge %s into %s", sourceID, targetID), []string{sourceID, targetID})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create restore point: %w", err)
+	}
+
+	now := time.Now()
+	targetNode.Content["when"] = when
+
+	// Keep higher confidence
+	sourceConf, _ := sourceNode.Metadata["confidence"].(float64)
+	targetConf, _ := targetNode.Metadata["confidence"].(float64)
+	if sourceConf > targetConf {
+		targetNode.Metadata["confidence"] = sourceConf
+	}
+
+	// Keep higher priority
+	sourcePrio, _ := sourceNode.Metadata["priority"].(int)
+	targetPrio, _ := targetNode.Metadata["priority"].(int)
+	if sourcePrio > targetPrio {
+		targetNode.Metadata["priority"] = sourcePrio
+	}
+
+	// Track merge in target metadata
+	mergedFrom, _ := targetNode.Metadata["merged_from"].([]interface{})
+	mergedFrom = append(mergedFrom, sourceID)
+	targetNode.Metadata["merged_from"] = mergedFrom
+	targetNode.Metadata["last_merge_at"] = now.UTC().Format(time.RFC3339)
+
+	// Update target
+	if err := graphStore.UpdateNode(ctx, *targetNode); err != nil {
+		return nil, nil, fmt.Errorf("failed to update target behavior: %w", err)
+	}
+
+	// Mark source as merged
+	if sourceNode.Metadata == nil {
+		sourceNode.Metadata = make(map[string]interface{})
+	}
+	sourceNode.Metadata["original_kind"] = sourceNode.Kind
+	sourceNode.Metadata["merged_into"] = targetID
+	sourceNode.Metadata["merged_at"] = now.UTC().Format(time.RFC3339)
+	sourceNode.Metadata["merged_by"] = os.Getenv("USER")
+	sourceNode.Kind = store.NodeKindMerged
+
+	if err := graphStore.UpdateNode(ctx, *sourceNode); err != nil {
+		return nil, nil, fmt.Errorf("failed to update source behavior: %w", err)
+	}
+
+	// Add merged-into edge
+	edge := store.Edge{
+		Source:    sourceID,
+		Target:    targetID,
+		Kind:      store.EdgeKindMergedInto,
+		Weight:    1.0,
+		CreatedAt: now,
+		Metadata: map[string]interface{}{
+			"merged_at": now.UTC().Format(time.RFC3339),
+		},
+	}
+	if err := graphStore.AddEdge(ctx, edge); err != nil {
+		return nil, nil, fmt.Errorf("failed to add merge edge: %w", err)
+	}
+
+	// Redirect edges that pointed to source to point to target
+	inboundEdges, err := graphStore.GetEdges(ctx, sourceID, store.DirectionInbound, "")
+	if err == nil {
+		for _, e := range inboundEdges {
+			if e.Kind != store.EdgeKindMergedInto { // Don't redirect the edge we just added
+				// Remove old edge
+				_ = graphStore.RemoveEdge(ctx, e.Source, e.Target, e.Kind)
+				// Defensive fallback for legacy edges missing Weight/CreatedAt
+				if e.Weight <= 0 {
+					e.Weight = 1.0
+				}
+				if e.CreatedAt.IsZero() {
+					e.CreatedAt = now
+				}
+				// Add redirected edge
+				e.Target = targetID
+				_ = graphStore.AddEdge(ctx, e)
+			}
+		}
+	}
+
+	return point, conflicts, nil
+}
+
+// addCurationScopeFlag registers --scope on a curation command.
+func addCurationScopeFlag(cmd *cobra.Command) {
+	cmd.Flags().String("scope", "auto", "Store holding the behavior: auto (local, then global), local, or global")
+}
+
+// findCurationNode looks up a behavior for a curation command by ID, name,
+// or slug (see store.ResolveNode). With scope
+// "auto" it searches the local store, then the global one; "local" or
+// "global" restricts the lookup to that store. The returned node carries its
+// Origin, so the update is written back to the store it came from. A nil node
+// means the behavior was not found in the requested scope.
+func findCurationNode(ctx context.Context, graphStore *store.MultiGraphStore, id, scope string) (*store.Node, error) {
+	var (
+		node *store.Node
+		err  error
+	)
+	switch scope {
+	case "", "auto":
+		node, err = store.ResolveNode(ctx, graphStore, id)
+	case string(store.ScopeLocal):
+		node, err = store.ResolveNode(ctx, graphStore.LocalStore(), id)
+		if node != nil {
+			node.Origin = store.OriginLocal
+		}
+	case string(store.ScopeGlobal):
+		node, err = store.ResolveNode(ctx, graphStore.GlobalStore(), id)
+		if node != nil {
+			node.Origin = store.OriginGlobal
+		}
+	default:
+		return nil, fmt.Errorf("--scope must be 'auto', 'local', or 'global'")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get behavior: %w", err)
+	}
+	return node, nil
+}
+
+// printScopeWarning notes in a confirmation prompt that a change reaches
+// beyond this project.
+func printScopeWarning(origin store.Origin) {
+	fmt.Printf("Scope: %s\n", origin)
+	if origin == store.OriginGlobal {
+		fmt.Println("This behavior lives in the global store (~/.floop/) and the change affects every project.")
+	}
+}
+
+// confirmed asks for a yes/no answer on stdin.
+func confirmed() bool {
+	fmt.Print("\nConfirm? [y/N]: ")
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes"
+}
@@ -2,8 +2,12 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"strings"
 	"testing"
+
+	"github.com/nvandessel/floop/internal/store"
 )
 
 func TestForgetCmdNotInitialized(t *testing.T) {
@@ -185,14 +189,14 @@ func TestRestoreCmdNotRestorable(t *testing.T) {
 	rootCmd := newTestRootCmd()
 	rootCmd.AddCommand(newRestoreCmd())
 	rootCmd.SetOut(&bytes.Buffer{})
-	rootCmd.SetArgs([]string{"restore", behaviorID, "--root", tmpDir})
+	rootCmd.SetArgs([]string{"restore", behaviorID, "--force", "--root", tmpDir})
 
 	err := rootCmd.Execute()
 	if err == nil {
 		t.Error("expected error for non-restorable behavior")
 	}
-	if !strings.Contains(err.Error(), "not deprecated or forgotten") {
-		t.Errorf("expected 'not deprecated or forgotten' error, got: %v", err)
+	if !strings.Contains(err.Error(), "not deprecated, forgotten, retired, or quarantined") {
+		t.Errorf("expected 'not deprecated, forgotten, retired, or quarantined' error, got: %v", err)
 	}
 }
 
@@ -212,7 +216,7 @@ func TestForgetThenRestore(t *testing.T) {
 	rootCmd2 := newTestRootCmd()
 	rootCmd2.AddCommand(newRestoreCmd())
 	rootCmd2.SetOut(&bytes.Buffer{})
-	rootCmd2.SetArgs([]string{"restore", behaviorID, "--root", tmpDir})
+	rootCmd2.SetArgs([]string{"restore", behaviorID, "--force", "--root", tmpDir})
 	if err := rootCmd2.Execute(); err != nil {
 		t.Fatalf("restore after forget failed: %v", err)
 	}
@@ -225,7 +229,7 @@ func TestDeprecateThenRestore(t *testing.T) {
 	rootCmd := newTestRootCmd()
 	rootCmd.AddCommand(newDeprecateCmd())
 	rootCmd.SetOut(&bytes.Buffer{})
-	rootCmd.SetArgs([]string{"deprecate", behaviorID, "--reason", "outdated", "--root", tmpDir})
+	rootCmd.SetArgs([]string{"deprecate", behaviorID, "--reason", "outdated", "--force", "--root", tmpDir})
 	if err := rootCmd.Execute(); err != nil {
 		t.Fatalf("deprecate failed: %v", err)
 	}
@@ -234,12 +238,64 @@ func TestDeprecateThenRestore(t *testing.T) {
 	rootCmd2 := newTestRootCmd()
 	rootCmd2.AddCommand(newRestoreCmd())
 	rootCmd2.SetOut(&bytes.Buffer{})
-	rootCmd2.SetArgs([]string{"restore", behaviorID, "--root", tmpDir})
+	rootCmd2.SetArgs([]string{"restore", behaviorID, "--force", "--root", tmpDir})
 	if err := rootCmd2.Execute(); err != nil {
 		t.Fatalf("restore after deprecate failed: %v", err)
 	}
 }
 
+func TestRetireThenRestore(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+
+	// Retire
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newRetireCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"retire", behaviorID, "--grace", "7d", "--force", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("retire failed: %v", err)
+	}
+
+	graphStore, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	node, err := graphStore.GetNode(context.Background(), behaviorID)
+	graphStore.Close()
+	if err != nil || node == nil {
+		t.Fatalf("GetNode(%s) = %v, %v", behaviorID, node, err)
+	}
+	if node.Kind != store.NodeKindRetired {
+		t.Fatalf("kind after retire = %s, want %s", node.Kind, store.NodeKindRetired)
+	}
+	if _, ok := node.Metadata["retired_until"]; !ok {
+		t.Error("retire should record retired_until")
+	}
+
+	// Restore
+	rootCmd2 := newTestRootCmd()
+	rootCmd2.AddCommand(newRestoreCmd())
+	rootCmd2.SetOut(&bytes.Buffer{})
+	rootCmd2.SetArgs([]string{"restore", behaviorID, "--force", "--root", tmpDir})
+	if err := rootCmd2.Execute(); err != nil {
+		t.Fatalf("restore after retire failed: %v", err)
+	}
+}
+
+func TestRetireCmdInvalidGrace(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newRetireCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"retire", behaviorID, "--grace", "soon", "--force", "--root", tmpDir})
+
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "invalid --grace") {
+		t.Errorf("expected 'invalid --grace' error, got: %v", err)
+	}
+}
+
 func TestRestoreCmdJSON(t *testing.T) {
 	tmpDir, behaviorID := setupQueryTest(t)
 
@@ -307,3 +363,182 @@ func TestMergeCmdInvalidInto(t *testing.T) {
 		t.Errorf("expected '--into must be one of' error, got: %v", err)
 	}
 }
+
+func TestCurationScope(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+
+	run := func(args ...string) (string, error) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newDeprecateCmd(), newRestoreCmd())
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetArgs(append(args, "--root", tmpDir))
+		var err error
+		out := captureStdout(t, func() { err = rootCmd.Execute() })
+		return out, err
+	}
+	kind := func() store.NodeKind {
+		graphStore, err := store.NewMultiGraphStore(tmpDir)
+		if err != nil {
+			t.Fatalf("failed to open store: %v", err)
+		}
+		defer graphStore.Close()
+		node, _ := graphStore.GetNode(context.Background(), behaviorID)
+		if node.Origin != store.OriginGlobal {
+			t.Fatalf("test behavior origin = %s, want global", node.Origin)
+		}
+		return node.Kind
+	}
+
+	// The learned behavior lives in the global store, so a local-only
+	// lookup does not find it
+	if _, err := run("deprecate", behaviorID, "--reason", "old", "--scope", "local", "--force"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("deprecate --scope local error = %v, want not found", err)
+	}
+
+	// Without --force, a global mutation asks first; no answer cancels
+	out, err := run("deprecate", behaviorID, "--reason", "old")
+	if err != nil {
+		t.Fatalf("deprecate failed: %v", err)
+	}
+	if !strings.Contains(out, "affects every project") || !strings.Contains(out, "Cancelled") {
+		t.Errorf("expected global warning and cancellation, got:\n%s", out)
+	}
+	if got := kind(); got != store.NodeKindBehavior {
+		t.Fatalf("cancelled deprecate changed kind to %s", got)
+	}
+
+	out, err = run("deprecate", behaviorID, "--reason", "old", "--scope", "global", "--json")
+	if err != nil {
+		t.Fatalf("deprecate --json failed: %v", err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if result["scope"] != "global" {
+		t.Errorf("scope = %v, want global", result["scope"])
+	}
+	if got := kind(); got != store.NodeKindDeprecated {
+		t.Errorf("kind = %s, want deprecated", got)
+	}
+
+	out, err = run("restore", behaviorID, "--force")
+	if err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if !strings.Contains(out, "restored (global store)") {
+		t.Errorf("restore output should report the scope, got:\n%s", out)
+	}
+}
+
+func TestForgetBySlug(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+	ctx := context.Background()
+
+	graphStore, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	node, _ := graphStore.GetNode(ctx, behaviorID)
+	name, _ := node.Content["name"].(string)
+	graphStore.Close()
+	slug := strings.TrimPrefix(name, "learned/")
+	if slug == name {
+		t.Fatalf("learned behavior name %q has no learned/ prefix", name)
+	}
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newForgetCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"forget", slug, "--force", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("forget by slug failed: %v", err)
+	}
+
+	graphStore, err = store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to reopen store: %v", err)
+	}
+	defer graphStore.Close()
+	node, _ = graphStore.GetNode(ctx, behaviorID)
+	if node == nil || node.Kind != store.NodeKindForgotten {
+		t.Errorf("behavior after forget by slug = %+v, want forgotten", node)
+	}
+}
+
+func TestMergeCmdWhenConflicts(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newInitCmd())
+	rootCmd.SetArgs([]string{"init", "--root", tmpDir})
+	rootCmd.SetOut(&bytes.Buffer{})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	gs, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	whens := map[string]map[string]interface{}{
+		"b-go":     {"language": "go"},
+		"b-python": {"language": "python"},
+		"b-any-a":  {"any": []interface{}{map[string]interface{}{"task": "testing"}}},
+		"b-any-b":  {"any": []interface{}{map[string]interface{}{"task": "review"}}},
+	}
+	for id, when := range whens {
+		node := store.Node{
+			ID:   id,
+			Kind: store.NodeKindBehavior,
+			Content: map[string]interface{}{
+				"name":    id,
+				"kind":    "directive",
+				"content": map[string]interface{}{"canonical": id + " canonical"},
+				"when":    when,
+			},
+			Metadata: map[string]interface{}{"confidence": 0.6},
+		}
+		if _, err := gs.AddNode(context.Background(), node); err != nil {
+			t.Fatalf("AddNode(%s) error = %v", id, err)
+		}
+	}
+	gs.Close()
+
+	run := func(args ...string) (string, error) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newMergeCmd())
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetArgs(append(args, "--root", tmpDir))
+		var err error
+		out := captureStdout(t, func() { err = rootCmd.Execute() })
+		return out, err
+	}
+
+	out, err := run("merge", "b-python", "b-go", "--json")
+	if err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+	var result struct {
+		When          map[string]interface{}   `json:"when"`
+		WhenConflicts []map[string]interface{} `json:"when_conflicts"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	langs, _ := result.When["language"].([]interface{})
+	if len(langs) != 2 || langs[0] != "go" || langs[1] != "python" {
+		t.Errorf("merged when = %v, want language go or python", result.When)
+	}
+	if len(result.WhenConflicts) != 1 || result.WhenConflicts[0]["resolution"] != "union" {
+		t.Errorf("when_conflicts = %v, want one union", result.WhenConflicts)
+	}
+
+	// OR-groups that differ cannot be combined: nothing is merged
+	if _, err := run("merge", "b-any-a", "b-any-b", "--force"); err == nil || !strings.Contains(err.Error(), "cannot be merged") {
+		t.Fatalf("merge error = %v, want when-conditions that cannot be merged", err)
+	}
+	if node := reviewNode(t, tmpDir, "b-any-a"); node.Kind != store.NodeKindBehavior {
+		t.Errorf("blocked merge changed the source to %s", node.Kind)
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/decay"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newDecayCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decay",
+		Short: "Demote and prune behaviors that are no longer used",
+		Long: `Run a decay pass over the local and global stores.
+
+A behavior that has not been activated or confirmed for
+decay.demote_after_days (default 90) loses 0.05 confidence per pass, down
+to 0.3. One unused for decay.prune_after_days (default 180) is pruned: it
+is retired, restored automatically if a correction recurs on its topic,
+and forgotten when the 14-day grace period ends. Constraints and authored
+or imported behaviors never decay.
+
+Reports of real runs are appended to ~/.floop/decay_reports.jsonl. Set
+decay.enabled to run a pass automatically from 'floop mcp-server' every
+decay.interval.
+
+Examples:
+  floop decay --dry-run                 # Show what would be demoted or pruned
+  floop decay                           # Apply the pass
+  floop decay --prune-after-days 365    # Prune only after a year unused`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			cfg, err := config.Load()
+			if err != nil {
+				cfg = config.Default()
+			}
+			demoteDays, pruneDays := cfg.Decay.DemoteAfterDays, cfg.Decay.PruneAfterDays
+			if cmd.Flags().Changed("demote-after-days") {
+				demoteDays, _ = cmd.Flags().GetInt("demote-after-days")
+			}
+			if cmd.Flags().Changed("prune-after-days") {
+				pruneDays, _ = cmd.Flags().GetInt("prune-after-days")
+			}
+			if demoteDays < 0 || pruneDays < 0 {
+				return fmt.Errorf("--demote-after-days and --prune-after-days must be non-negative")
+			}
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			report, err := decay.Run(context.Background(), graphStore, decay.Options{
+				DemoteAfter: time.Duration(demoteDays) * 24 * time.Hour,
+				PruneAfter:  time.Duration(pruneDays) * 24 * time.Hour,
+				DryRun:      dryRun,
+			})
+			if err != nil {
+				return fmt.Errorf("decay failed: %w", err)
+			}
+
+			if !dryRun {
+				globalPath, err := store.GlobalFloopPath()
+				if err != nil {
+					return fmt.Errorf("failed to get global path: %w", err)
+				}
+				if err := os.MkdirAll(globalPath, 0700); err == nil {
+					if err := decay.RecordReport(globalPath, report); err != nil {
+						fmt.Fprintf(cmd.ErrOrStderr(), "warning: failed to record decay report: %v\n", err)
+					}
+				}
+			}
+
+			if jsonOut {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(report)
+			}
+			printDecayReport(cmd.OutOrStdout(), report)
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Show what would change without writing anything")
+	cmd.Flags().Int("demote-after-days", 0, "Days unused before confidence decays (default: decay.demote_after_days)")
+	cmd.Flags().Int("prune-after-days", 0, "Days unused before a behavior is retired, 0 = never (default: decay.prune_after_days)")
+
+	return cmd
+}
+
+// printDecayReport lists the behaviors a decay pass demoted and pruned.
+func printDecayReport(out io.Writer, report *decay.Report) {
+	if report.Changes() == 0 {
+		fmt.Fprintln(out, "No behaviors to decay.")
+		return
+	}
+
+	demoted, pruned := "Demoted", "Pruned (retired)"
+	if report.DryRun {
+		demoted, pruned = "Would demote", "Would prune (retire)"
+	}
+	if len(report.Demoted) > 0 {
+		fmt.Fprintf(out, "%s %d behavior(s) unused for over %d days:\n", demoted, len(report.Demoted), report.DemoteAfterDays)
+		for _, c := range report.Demoted {
+			fmt.Fprintf(out, "  %s (%s): %.2f -> %.2f, idle %d days\n", c.Name, c.BehaviorID, c.From, c.To, c.IdleDays)
+		}
+	}
+	if len(report.Pruned) > 0 {
+		if len(report.Demoted) > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "%s %d behavior(s) unused for over %d days:\n", pruned, len(report.Pruned), report.PruneAfterDays)
+		for _, c := range report.Pruned {
+			fmt.Fprintf(out, "  %s (%s): idle %d days, forgotten on %s\n", c.Name, c.BehaviorID, c.IdleDays, c.RetiredUntil.Local().Format("2006-01-02"))
+		}
+		if !report.DryRun {
+			fmt.Fprintln(out, "Use 'floop restore' to undo a retirement.")
+		}
+	}
+}
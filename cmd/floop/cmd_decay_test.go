@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/decay"
+)
+
+func TestDecayCmd(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	run := func(args ...string) (string, error) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newDecayCmd())
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs(append([]string{"decay", "--root", tmpDir}, args...))
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("--dry-run", "--json", "--demote-after-days", "30")
+	if err != nil {
+		t.Fatalf("decay --dry-run failed: %v", err)
+	}
+	var report decay.Report
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if !report.DryRun || report.DemoteAfterDays != 30 || report.Changes() != 0 {
+		t.Errorf("report = %+v, want a dry run after 30 days with no changes for a fresh behavior", report)
+	}
+
+	if out, err := run(); err != nil || !strings.Contains(out, "No behaviors to decay.") {
+		t.Errorf("decay = %q, %v", out, err)
+	}
+	if _, err := run("--prune-after-days", "-1"); err == nil {
+		t.Error("expected error for negative --prune-after-days")
+	}
+}
+
+func TestPrintDecayReport(t *testing.T) {
+	until := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+	report := &decay.Report{
+		DemoteAfterDays: 90,
+		PruneAfterDays:  180,
+		Demoted:         []decay.Change{{BehaviorID: "b1", Name: "prefer-pathlib", IdleDays: 120, From: 0.6, To: 0.55}},
+		Pruned:          []decay.Change{{BehaviorID: "b2", Name: "old-build-steps", IdleDays: 200, From: 0.7, RetiredUntil: &until}},
+	}
+
+	tests := []struct {
+		name   string
+		dryRun bool
+		want   []string
+	}{
+		{"applied", false, []string{
+			"Demoted 1 behavior(s) unused for over 90 days:",
+			"prefer-pathlib (b1): 0.60 -> 0.55, idle 120 days",
+			"Pruned (retired) 1 behavior(s) unused for over 180 days:",
+			"old-build-steps (b2): idle 200 days, forgotten on 2026-06-15",
+			"floop restore",
+		}},
+		{"dry run", true, []string{"Would demote 1", "Would prune (retire) 1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report.DryRun = tt.dryRun
+			var out bytes.Buffer
+			printDecayReport(&out, report)
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
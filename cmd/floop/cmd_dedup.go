@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,6 +14,8 @@ import (
 	"github.com/nvandessel/floop/internal/edges"
 	"github.com/nvandessel/floop/internal/llm"
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/progress"
+	"github.com/nvandessel/floop/internal/restorepoint"
 	"github.com/nvandessel/floop/internal/store"
 	"github.com/spf13/cobra"
 )
@@ -26,6 +29,10 @@ func newDeduplicateCmd() *cobra.Command {
 This command analyzes all behaviors in the store, identifies duplicates based on
 semantic similarity, and can automatically merge them.
 
+Progress is shown on stderr, as JSONL events with --json. Ctrl-C stops after
+the current comparison or merge and reports what was done; an interrupted
+comparison merges nothing.
+
 Examples:
   floop deduplicate                  # Find duplicates across both stores (default)
   floop deduplicate --dry-run        # Show what would be merged
@@ -88,7 +95,11 @@ Examples:
 				}
 			}
 
-			ctx := context.Background()
+			// Ctrl-C stops after the current comparison or merge and
+			// reports what was done
+			rep, notice := commandProgress(cmd, jsonOut)
+			ctx, stop := interruptContext(notice)
+			defer stop()
 
 			// Load config and create LLM client once
 			floopCfg, err := config.Load()
@@ -105,6 +116,7 @@ Examples:
 				AutoMerge:           !dryRun,
 				UseLLM:              useLLM,
 				MaxBatchSize:        100,
+				Progress:            rep,
 			}
 
 			// Handle cross-store deduplication
@@ -146,7 +158,7 @@ func runSingleStoreDedup(ctx context.Context, root string, scope store.StoreScop
 		if pathErr != nil {
 			return fmt.Errorf("failed to get global path: %w", pathErr)
 		}
-		graphStore, err = store.NewSQLiteGraphStore(filepath.Dir(globalPath))
+		graphStore, err = store.NewGlobalGraphStore(filepath.Dir(globalPath))
 	default:
 		return fmt.Errorf("runSingleStoreDedup requires local or global scope, got %q", scope)
 	}
@@ -156,14 +168,47 @@ func runSingleStoreDedup(ctx context.Context, root string, scope store.StoreScop
 	}
 	defer graphStore.Close()
 
-	return runDedupOnStore(ctx, graphStore, cfg, llmClient, dryRun, jsonOut)
+	// Snapshot the duplicates before merging so the run can be undone
+	checkpoint := func(ids []string) (string, error) {
+		snap, err := restorepoint.CaptureStore(context.WithoutCancel(ctx), graphStore, scope, ids)
+		if err != nil {
+			return "", err
+		}
+		dir, err := restorepoint.Dir(root)
+		if err != nil {
+			return "", err
+		}
+		point, err := restorepoint.Save(dir, "deduplicate", fmt.Sprintf("deduplicate %s store", scope), []restorepoint.Snapshot{snap})
+		if err != nil {
+			return "", err
+		}
+		return point.ID, nil
+	}
+
+	if cfg.Similarity == nil {
+		cfg.Similarity = dedupSimilarity(llmClient, graphStore)
+	}
+	return runDedupOnStoreWithCheckpoint(ctx, graphStore, cfg, llmClient, dryRun, jsonOut, checkpoint)
 }
 
 // runDedupOnStore performs deduplication on the given store.
 // Extracted for testability — accepts a GraphStore directly.
 func runDedupOnStore(ctx context.Context, graphStore store.GraphStore, cfg dedup.DeduplicatorConfig, llmClient llm.Client, dryRun, jsonOut bool) error {
+	return runDedupOnStoreWithCheckpoint(ctx, graphStore, cfg, llmClient, dryRun, jsonOut, nil)
+}
+
+// runDedupOnStoreWithCheckpoint is runDedupOnStore with a checkpoint called
+// with the IDs of every duplicate before anything is merged. It returns the
+// ID of the restore point it saved; an error aborts the merge.
+//
+// Cancelling ctx stops the run between comparisons or merges. A run
+// interrupted while comparing reports the duplicates found so far and
+// merges nothing; one interrupted while merging keeps the merges done.
+func runDedupOnStoreWithCheckpoint(ctx context.Context, graphStore store.GraphStore, cfg dedup.DeduplicatorConfig, llmClient llm.Client, dryRun, jsonOut bool, checkpoint func(ids []string) (string, error)) error {
+	work := context.WithoutCancel(ctx)
+
 	// Load all behaviors
-	behaviors, err := edges.LoadBehaviorsFromStore(ctx, graphStore)
+	behaviors, err := edges.LoadBehaviorsFromStore(work, graphStore)
 	if err != nil {
 		return fmt.Errorf("failed to load behaviors: %w", err)
 	}
@@ -181,10 +226,16 @@ func runDedupOnStore(ctx context.Context, graphStore store.GraphStore, cfg dedup
 		return nil
 	}
 
-	// Find duplicate pairs
-	duplicates := findDuplicatePairs(behaviors, cfg, llmClient)
+	// Specializes links keep already-generalized variants apart
+	if err := edges.AttachRelationships(work, graphStore, behaviors); err != nil {
+		return fmt.Errorf("failed to load relationships: %w", err)
+	}
+
+	// Find duplicate pairs, and context variants that must not be merged
+	duplicates, variants, compared := findDuplicatesAndVariants(ctx, behaviors, cfg, llmClient)
+	interrupted := ctx.Err() != nil
 
-	if len(duplicates) == 0 {
+	if len(duplicates) == 0 && len(variants) == 0 && !interrupted {
 		if jsonOut {
 			json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
 				"status":           "no_duplicates",
@@ -197,7 +248,7 @@ func runDedupOnStore(ctx context.Context, graphStore store.GraphStore, cfg dedup
 		return nil
 	}
 
-	if dryRun {
+	if dryRun || interrupted {
 		if jsonOut {
 			var pairs []map[string]interface{}
 			for _, dup := range duplicates {
@@ -209,40 +260,90 @@ func runDedupOnStore(ctx context.Context, graphStore store.GraphStore, cfg dedup
 					"similarity": dup.Similarity,
 				})
 			}
-			json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+			out := map[string]interface{}{
 				"status":           "dry_run",
 				"total_behaviors":  len(behaviors),
 				"duplicates_found": len(duplicates),
 				"duplicates":       pairs,
-			})
+				"context_variants": variantIDs(variants),
+			}
+			if interrupted {
+				out["status"] = "interrupted"
+				out["behaviors_compared"] = compared
+				out["merges_performed"] = 0
+			}
+			json.NewEncoder(os.Stdout).Encode(out)
 		} else {
-			fmt.Printf("Dry run: Found %d duplicate pairs among %d behaviors.\n\n", len(duplicates), len(behaviors))
+			if interrupted {
+				fmt.Printf("Interrupted after comparing %d of %d behaviors; nothing was merged.\n", compared, len(behaviors))
+				fmt.Printf("Found %d duplicate pairs so far.\n\n", len(duplicates))
+			} else {
+				fmt.Printf("Dry run: Found %d duplicate pairs among %d behaviors.\n\n", len(duplicates), len(behaviors))
+			}
 			for i, dup := range duplicates {
 				fmt.Printf("%d. Similarity: %.2f\n", i+1, dup.Similarity)
 				fmt.Printf("   A: [%s] %s\n", dup.BehaviorA.ID, dup.BehaviorA.Name)
 				fmt.Printf("   B: [%s] %s\n", dup.BehaviorB.ID, dup.BehaviorB.Name)
 				fmt.Println()
 			}
+			printContextVariants(variants, "would be linked under a shared parent")
 		}
 		return nil
 	}
 
-	// Perform merges
-	mergeCount := mergeDuplicatePairs(ctx, graphStore, duplicates, llmClient, jsonOut)
+	var pointID string
+	if checkpoint != nil {
+		var ids []string
+		for _, dup := range duplicates {
+			ids = append(ids, dup.BehaviorA.ID, dup.BehaviorB.ID)
+		}
+		id, err := checkpoint(ids)
+		if err != nil {
+			return fmt.Errorf("failed to create restore point: %w", err)
+		}
+		pointID = id
+	}
+
+	// Perform merges, then link the surviving context variants
+	mergeCount := mergeDuplicatePairs(ctx, graphStore, duplicates, llmClient, jsonOut, cfg.Progress)
+	interrupted = ctx.Err() != nil
+	var parents []string
+	if !interrupted {
+		parents = linkContextVariants(work, graphStore, variants, jsonOut)
+	}
 
-	if err := graphStore.Sync(ctx); err != nil {
+	if err := graphStore.Sync(work); err != nil {
 		return fmt.Errorf("failed to sync changes: %w", err)
 	}
 
 	if jsonOut {
-		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+		out := map[string]interface{}{
 			"status":           "completed",
 			"total_behaviors":  len(behaviors),
 			"duplicates_found": len(duplicates),
 			"merges_performed": mergeCount,
-		})
+			"shared_parents":   parents,
+		}
+		if interrupted {
+			out["status"] = "interrupted"
+		}
+		if pointID != "" {
+			out["restore_point"] = pointID
+		}
+		json.NewEncoder(os.Stdout).Encode(out)
+	} else if interrupted {
+		fmt.Printf("\nDeduplication interrupted: %d merges performed; run 'floop deduplicate' again to continue.\n", mergeCount)
+		if pointID != "" {
+			fmt.Printf("Undo with: floop restore-point apply %s\n", pointID)
+		}
 	} else {
 		fmt.Printf("\nDeduplication complete: %d merges performed.\n", mergeCount)
+		if len(parents) > 0 {
+			fmt.Printf("Linked %d group(s) of context variants under shared parents.\n", len(parents))
+		}
+		if pointID != "" {
+			fmt.Printf("Undo with: floop restore-point apply %s\n", pointID)
+		}
 	}
 
 	return nil
@@ -251,6 +352,17 @@ func runDedupOnStore(ctx context.Context, graphStore store.GraphStore, cfg dedup
 // findDuplicatePairs performs pairwise similarity comparison across all behaviors,
 // returning pairs that exceed the configured similarity threshold.
 func findDuplicatePairs(behaviors []models.Behavior, cfg dedup.DeduplicatorConfig, llmClient llm.Client) []duplicatePair {
+	duplicates, _, _ := findDuplicatesAndVariants(context.Background(), behaviors, cfg, llmClient)
+	return duplicates
+}
+
+// findDuplicatesAndVariants is findDuplicatePairs that also returns context
+// variants: groups of behaviors above the threshold whose when-conditions are
+// incompatible. Variants are never returned as duplicate pairs, and pairs
+// already linked by specializes edges are skipped. Comparison stops once ctx
+// is cancelled; the last result is how many behaviors were compared against
+// the rest.
+func findDuplicatesAndVariants(ctx context.Context, behaviors []models.Behavior, cfg dedup.DeduplicatorConfig, llmClient llm.Client) ([]duplicatePair, [][]*models.Behavior, int) {
 	useLLM := cfg.UseLLM && llmClient != nil
 
 	// Create embedding cache so each behavior text is embedded at most once.
@@ -260,24 +372,140 @@ func findDuplicatePairs(behaviors []models.Behavior, cfg dedup.DeduplicatorConfi
 	}
 
 	var duplicates []duplicatePair
+	var variants [][]*models.Behavior
+	grouped := make(map[string]bool)
+	cfg.Progress.Start("deduplicate", len(behaviors))
+	defer cfg.Progress.Finish()
 	for i := 0; i < len(behaviors); i++ {
+		if ctx.Err() != nil {
+			return duplicates, variants, i
+		}
+		group := []*models.Behavior{&behaviors[i]}
 		for j := i + 1; j < len(behaviors); j++ {
-			sim := edges.ComputeBehaviorSimilarity(&behaviors[i], &behaviors[j], llmClient, useLLM, cache)
-			if sim >= cfg.SimilarityThreshold {
-				duplicates = append(duplicates, duplicatePair{
-					BehaviorA:  &behaviors[i],
-					BehaviorB:  &behaviors[j],
-					Similarity: sim,
+			a, b := &behaviors[i], &behaviors[j]
+			if dedup.SpecializesRelated(a, b) {
+				continue
+			}
+			compatible := dedup.ContextsCompatible(a, b)
+			if !compatible && (grouped[a.ID] || grouped[b.ID]) {
+				continue
+			}
+			sim, threshold := 0.0, cfg.SimilarityThreshold
+			if cfg.Similarity != nil {
+				result := dedup.ComputeSimilarity(a, b, dedup.SimilarityConfig{
+					UseLLM:         useLLM,
+					LLMClient:      llmClient,
+					EmbeddingCache: cache,
+					Backend:        cfg.Similarity,
 				})
+				sim = result.Score
+				if result.Method == "embedding" && cfg.EmbeddingThreshold > 0 {
+					threshold = cfg.EmbeddingThreshold
+				}
+			} else {
+				sim = edges.ComputeBehaviorSimilarity(a, b, llmClient, useLLM, cache)
+			}
+			if sim < threshold {
+				continue
 			}
+			if !compatible {
+				group = append(group, b)
+				continue
+			}
+			duplicates = append(duplicates, duplicatePair{
+				BehaviorA:  a,
+				BehaviorB:  b,
+				Similarity: sim,
+			})
 		}
+		if len(group) > 1 {
+			for _, b := range group {
+				grouped[b.ID] = true
+			}
+			variants = append(variants, group)
+		}
+		cfg.Progress.Step(behaviors[i].ID)
 	}
-	return duplicates
+	return duplicates, variants, len(behaviors)
+}
+
+// dedupSimilarity returns the similarity backend selected by
+// deduplication.similarity, caching embeddings in stores. It returns nil,
+// leaving the default comparison chain, for "auto" or when the backend is
+// unavailable.
+func dedupSimilarity(llmClient llm.Client, stores ...store.GraphStore) dedup.Similarity {
+	cfg, err := config.Load()
+	if err != nil || cfg.Deduplication.Similarity == "" || cfg.Deduplication.Similarity == dedup.SimilarityAuto {
+		return nil
+	}
+	if llmClient == nil && cfg.Deduplication.Similarity == dedup.SimilarityEmbedding {
+		llmClient = createLLMClient(cfg)
+	}
+	sim, err := dedup.NewSimilarity(cfg.Deduplication.Similarity, llmClient, cfg.LLM.EmbeddingModelName(), stores...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; using default similarity\n", err)
+		return nil
+	}
+	return sim
+}
+
+// linkContextVariants links each group of context variants still in the
+// store under a shared parent. Returns the IDs of the parents created.
+func linkContextVariants(ctx context.Context, graphStore store.GraphStore, variants [][]*models.Behavior, jsonOut bool) []string {
+	var parents []string
+	for _, group := range variants {
+		var live []*models.Behavior
+		for _, b := range group {
+			if node, err := graphStore.GetNode(ctx, b.ID); err == nil && node != nil {
+				live = append(live, b)
+			}
+		}
+		if len(live) < 2 {
+			continue
+		}
+		parent, err := dedup.LinkContextVariants(ctx, graphStore, live)
+		if err != nil {
+			if !jsonOut {
+				fmt.Fprintf(os.Stderr, "Warning: failed to link context variants of %s: %v\n", live[0].ID, err)
+			}
+			continue
+		}
+		parents = append(parents, parent.ID)
+		if !jsonOut {
+			fmt.Printf("Linked %d context variants under shared parent %s\n", len(live), parent.ID)
+		}
+	}
+	return parents
+}
+
+// printContextVariants describes each group of context variants.
+func printContextVariants(variants [][]*models.Behavior, action string) {
+	if len(variants) == 0 {
+		return
+	}
+	fmt.Printf("Found %d group(s) of context variants (similar content, incompatible when-conditions; %s, not merged):\n", len(variants), action)
+	for _, ids := range variantIDs(variants) {
+		fmt.Printf("   %v\n", ids)
+	}
+	fmt.Println()
+}
+
+// variantIDs returns the behavior IDs of each context variant group.
+func variantIDs(variants [][]*models.Behavior) [][]string {
+	out := make([][]string, len(variants))
+	for i, group := range variants {
+		for _, b := range group {
+			out[i] = append(out[i], b.ID)
+		}
+	}
+	return out
 }
 
 // mergeDuplicatePairs merges each duplicate pair, updating the store.
-// Returns the number of successful merges.
-func mergeDuplicatePairs(ctx context.Context, graphStore store.GraphStore, duplicates []duplicatePair, llmClient llm.Client, jsonOut bool) int {
+// Returns the number of successful merges. Once ctx is cancelled no further
+// pair is merged; the merge in progress is completed.
+func mergeDuplicatePairs(ctx context.Context, graphStore store.GraphStore, duplicates []duplicatePair, llmClient llm.Client, jsonOut bool, rep *progress.Reporter) int {
+	work := context.WithoutCancel(ctx)
 	mergeCount := 0
 	merged := make(map[string]bool)
 
@@ -286,45 +514,54 @@ func mergeDuplicatePairs(ctx context.Context, graphStore store.GraphStore, dupli
 		LLMClient: llmClient,
 	})
 
+	rep.Start("merge", len(duplicates))
+	defer rep.Finish()
 	for _, dup := range duplicates {
-		if merged[dup.BehaviorA.ID] || merged[dup.BehaviorB.ID] {
-			continue
+		if ctx.Err() != nil {
+			break
 		}
-
-		mergedBehavior, err := merger.Merge(ctx, []*models.Behavior{dup.BehaviorA, dup.BehaviorB})
-		if err != nil {
-			if !jsonOut {
-				fmt.Fprintf(os.Stderr, "Warning: failed to merge %s and %s: %v\n",
-					dup.BehaviorA.ID, dup.BehaviorB.ID, err)
-			}
-			continue
+		if !merged[dup.BehaviorA.ID] && !merged[dup.BehaviorB.ID] && mergeDuplicatePair(work, graphStore, merger, dup, jsonOut) {
+			merged[dup.BehaviorB.ID] = true
+			mergeCount++
 		}
+		rep.Step(dup.BehaviorB.ID)
+	}
+	return mergeCount
+}
 
-		mergedNode := models.BehaviorToNode(mergedBehavior)
-		mergedNode.ID = dup.BehaviorA.ID
-		if err := graphStore.UpdateNode(ctx, mergedNode); err != nil {
-			if !jsonOut {
-				fmt.Fprintf(os.Stderr, "Warning: failed to save merged behavior: %v\n", err)
-			}
-			continue
+// mergeDuplicatePair merges dup.BehaviorB into dup.BehaviorA, reporting
+// whether the merged behavior was saved.
+func mergeDuplicatePair(ctx context.Context, graphStore store.GraphStore, merger *dedup.BehaviorMerger, dup duplicatePair, jsonOut bool) bool {
+	mergedBehavior, err := merger.Merge(ctx, []*models.Behavior{dup.BehaviorA, dup.BehaviorB})
+	if err != nil {
+		if !jsonOut {
+			fmt.Fprintf(os.Stderr, "Warning: failed to merge %s and %s: %v\n",
+				dup.BehaviorA.ID, dup.BehaviorB.ID, err)
 		}
+		return false
+	}
 
-		if err := graphStore.DeleteNode(ctx, dup.BehaviorB.ID); err != nil {
-			if !jsonOut {
-				fmt.Fprintf(os.Stderr, "Warning: failed to delete merged behavior %s: %v\n",
-					dup.BehaviorB.ID, err)
-			}
+	mergedNode := models.BehaviorToNode(mergedBehavior)
+	mergedNode.ID = dup.BehaviorA.ID
+	if err := graphStore.UpdateNode(ctx, mergedNode); err != nil {
+		if !jsonOut {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save merged behavior: %v\n", err)
 		}
+		return false
+	}
 
-		merged[dup.BehaviorB.ID] = true
-		mergeCount++
-
+	if err := graphStore.DeleteNode(ctx, dup.BehaviorB.ID); err != nil {
 		if !jsonOut {
-			fmt.Printf("Merged: %s <- %s (similarity: %.2f)\n",
-				mergedBehavior.Name, dup.BehaviorB.Name, dup.Similarity)
+			fmt.Fprintf(os.Stderr, "Warning: failed to delete merged behavior %s: %v\n",
+				dup.BehaviorB.ID, err)
 		}
 	}
-	return mergeCount
+
+	if !jsonOut {
+		fmt.Printf("Merged: %s <- %s (similarity: %.2f)\n",
+			mergedBehavior.Name, dup.BehaviorB.Name, dup.Similarity)
+	}
+	return true
 }
 
 // runCrossStoreDedup runs deduplication across local and global stores.
@@ -345,7 +582,7 @@ func runCrossStoreDedup(ctx context.Context, root string, cfg dedup.Deduplicator
 	if err != nil {
 		return fmt.Errorf("failed to get global path: %w", err)
 	}
-	globalStore, err := store.NewSQLiteGraphStore(filepath.Dir(globalPath))
+	globalStore, err := store.NewGlobalGraphStore(filepath.Dir(globalPath))
 	if err != nil {
 		return fmt.Errorf("failed to open global store: %w", err)
 	}
@@ -360,13 +597,27 @@ func runCrossStoreDedup(ctx context.Context, root string, cfg dedup.Deduplicator
 	// Configure auto-merge based on dry-run
 	crossCfg := cfg
 	crossCfg.AutoMerge = !dryRun
+	if crossCfg.Similarity == nil {
+		crossCfg.Similarity = dedupSimilarity(llmClient, localStore, globalStore)
+	}
 
 	// Create cross-store deduplicator with LLM client for embedding-based comparison
 	deduplicator := dedup.NewCrossStoreDeduplicatorWithLLM(localStore, globalStore, merger, crossCfg, llmClient)
 
-	// Run deduplication
+	// Snapshot both stores before merging so the run can be undone
+	var pointID string
+	if !dryRun {
+		id, err := saveCrossStoreRestorePoint(context.WithoutCancel(ctx), root, localStore, globalStore)
+		if err != nil {
+			return fmt.Errorf("failed to create restore point: %w", err)
+		}
+		pointID = id
+	}
+
+	// Run deduplication; an interrupted run reports the behaviors it reached
 	results, err := deduplicator.DeduplicateAcrossStores(ctx)
-	if err != nil {
+	interrupted := errors.Is(err, context.Canceled)
+	if err != nil && !interrupted {
 		return fmt.Errorf("cross-store deduplication failed: %w", err)
 	}
 
@@ -384,17 +635,24 @@ func runCrossStoreDedup(ctx context.Context, root string, cfg dedup.Deduplicator
 	}
 
 	if jsonOut {
+		status := "completed"
+		if interrupted {
+			status = "interrupted"
+		}
 		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-			"status":         "completed",
+			"status":         status,
 			"dry_run":        dryRun,
 			"total_compared": len(results),
 			"skipped":        skipped,
 			"merged":         mergedCount,
 			"no_duplicate":   none,
 			"results":        results,
+			"restore_point":  pointID,
 		})
 	} else {
-		if dryRun {
+		if interrupted {
+			fmt.Printf("Cross-store deduplication interrupted; run 'floop deduplicate' again to continue\n\n")
+		} else if dryRun {
 			fmt.Printf("Dry run: Cross-store deduplication analysis\n\n")
 		} else {
 			fmt.Printf("Cross-store deduplication complete\n\n")
@@ -403,6 +661,9 @@ func runCrossStoreDedup(ctx context.Context, root string, cfg dedup.Deduplicator
 		fmt.Printf("  Skipped (same ID in global):  %d\n", skipped)
 		fmt.Printf("  Semantic duplicates found:    %d\n", mergedCount)
 		fmt.Printf("  No duplicate found:           %d\n", none)
+		if pointID != "" {
+			fmt.Printf("Undo with: floop restore-point apply %s\n", pointID)
+		}
 
 		// Show details of duplicates
 		if mergedCount > 0 {
@@ -418,3 +679,25 @@ func runCrossStoreDedup(ctx context.Context, root string, cfg dedup.Deduplicator
 
 	return nil
 }
+
+// saveCrossStoreRestorePoint snapshots every node in both stores and saves
+// them as one restore point, returning its ID.
+func saveCrossStoreRestorePoint(ctx context.Context, root string, localStore, globalStore store.GraphStore) (string, error) {
+	localSnap, err := restorepoint.CaptureStore(ctx, localStore, store.ScopeLocal, nil)
+	if err != nil {
+		return "", err
+	}
+	globalSnap, err := restorepoint.CaptureStore(ctx, globalStore, store.ScopeGlobal, nil)
+	if err != nil {
+		return "", err
+	}
+	dir, err := restorepoint.Dir(root)
+	if err != nil {
+		return "", err
+	}
+	point, err := restorepoint.Save(dir, "deduplicate", "deduplicate across local and global stores", []restorepoint.Snapshot{localSnap, globalSnap})
+	if err != nil {
+		return "", err
+	}
+	return point.ID, nil
+}
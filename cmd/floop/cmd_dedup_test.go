@@ -2,11 +2,14 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"strings"
 	"testing"
 
 	"github.com/nvandessel/floop/internal/dedup"
+	"github.com/nvandessel/floop/internal/llm"
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
 )
 
 func TestNewDeduplicateCmd(t *testing.T) {
@@ -214,3 +217,73 @@ func TestDeduplicateCmdMerge(t *testing.T) {
 		t.Fatalf("deduplicate failed: %v", err)
 	}
 }
+
+func TestFindDuplicatesAndVariants(t *testing.T) {
+	content := models.BehaviorContent{Canonical: "use error wrapping with fmt.Errorf for context"}
+	behaviors := []models.Behavior{
+		{ID: "b-go", When: map[string]interface{}{"language": "go"}, Content: content},
+		{ID: "b-go-2", When: map[string]interface{}{"language": "go"}, Content: content},
+		{ID: "b-py", When: map[string]interface{}{"language": "python"}, Content: content},
+	}
+	cfg := dedup.DeduplicatorConfig{SimilarityThreshold: 0.5}
+
+	duplicates, variants, _ := findDuplicatesAndVariants(context.Background(), behaviors, cfg, nil)
+	if len(duplicates) != 1 || duplicates[0].BehaviorA.ID != "b-go" || duplicates[0].BehaviorB.ID != "b-go-2" {
+		t.Errorf("duplicates = %+v, want only the two go behaviors", duplicates)
+	}
+	got := variantIDs(variants)
+	if len(got) != 1 || strings.Join(got[0], ",") != "b-go,b-py" {
+		t.Errorf("variants = %v, want [[b-go b-py]]", got)
+	}
+}
+
+func TestFindDuplicatesAndVariants_EmbeddingSimilarity(t *testing.T) {
+	behaviors := []models.Behavior{
+		{ID: "b-1", Content: models.BehaviorContent{Canonical: "wrap errors with fmt.Errorf"}},
+		{ID: "b-2", Content: models.BehaviorContent{Canonical: "add context when returning an error"}},
+	}
+	client := llm.NewMockClient().WithEmbedResult([]float32{0.6, 0.8})
+	sim, err := dedup.NewSimilarity(dedup.SimilarityEmbedding, client, "nomic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := dedup.DeduplicatorConfig{SimilarityThreshold: 0.99, EmbeddingThreshold: 0.7, Similarity: sim}
+
+	duplicates, _, _ := findDuplicatesAndVariants(context.Background(), behaviors, cfg, nil)
+	if len(duplicates) != 1 {
+		t.Fatalf("duplicates = %+v, want the reworded pair", duplicates)
+	}
+	if len(client.EmbedCalls) != 2 {
+		t.Errorf("Embed calls = %v, want one per behavior", client.EmbedCalls)
+	}
+}
+
+func TestDedup_Interrupted(t *testing.T) {
+	content := models.BehaviorContent{Canonical: "use error wrapping with fmt.Errorf for context"}
+	behaviors := []models.Behavior{
+		{ID: "b-1", Name: "wrap-1", Content: content},
+		{ID: "b-2", Name: "wrap-2", Content: content},
+	}
+	cfg := dedup.DeduplicatorConfig{SimilarityThreshold: 0.5}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	duplicates, _, compared := findDuplicatesAndVariants(ctx, behaviors, cfg, nil)
+	if len(duplicates) != 0 || compared != 0 {
+		t.Errorf("cancelled comparison = %d pairs after %d behaviors, want none", len(duplicates), compared)
+	}
+
+	s := store.NewInMemoryGraphStore()
+	for i := range behaviors {
+		if _, err := s.AddNode(context.Background(), models.BehaviorToNode(&behaviors[i])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pairs := []duplicatePair{{BehaviorA: &behaviors[0], BehaviorB: &behaviors[1], Similarity: 1}}
+	if count := mergeDuplicatePairs(ctx, s, pairs, nil, true, nil); count != 0 {
+		t.Errorf("cancelled merge = %d merges, want 0", count)
+	}
+	if node, _ := s.GetNode(context.Background(), "b-2"); node == nil {
+		t.Error("b-2 was merged away after the interrupt")
+	}
+}
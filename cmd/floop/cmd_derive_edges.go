@@ -103,7 +103,7 @@ Examples:
 			}
 
 			if hasGlobal && (storeScope == store.ScopeGlobal || storeScope == store.ScopeBoth) {
-				graphStore, err := store.NewSQLiteGraphStore(filepath.Dir(resolvedGlobalPath))
+				graphStore, err := store.NewGlobalGraphStore(filepath.Dir(resolvedGlobalPath))
 				if err != nil {
 					return fmt.Errorf("failed to open global store: %w", err)
 				}
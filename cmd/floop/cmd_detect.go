@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/corrections"
 	"github.com/nvandessel/floop/internal/learning"
 	"github.com/nvandessel/floop/internal/llm"
 	"github.com/nvandessel/floop/internal/models"
@@ -194,7 +195,8 @@ Examples:
 			defer graphStore.Close()
 
 			// Process through learning loop
-			loop := learning.NewLearningLoop(graphStore, nil)
+			loopConfig := withStorageLimits(withExtractor(withQualityGate(nil)))
+			loop := learning.NewLearningLoop(graphStore, loopConfig)
 			ctx := context.Background()
 
 			result, err := loop.ProcessCorrection(ctx, correction)
@@ -211,17 +213,55 @@ Examples:
 				return nil
 			}
 
+			if result.Held {
+				holdErr := holdResult(floopDir, result, loopConfig)
+				if jsonOut {
+					out := map[string]interface{}{
+						"detected": true,
+						"wrong":    wrong,
+						"right":    right,
+						"captured": false,
+						"held":     holdErr == nil,
+						"quality":  result.Quality,
+					}
+					if holdErr != nil {
+						out["error"] = holdErr.Error()
+					}
+					json.NewEncoder(os.Stdout).Encode(out)
+				} else if holdErr == nil {
+					fmt.Printf("Correction held (quality %.2f): %s\n", result.Quality.Score, correction.ID)
+				}
+				return nil
+			}
+
+			if result.Quota != nil {
+				deferErr := deferCorrection(floopDir, correction)
+				if jsonOut {
+					out := map[string]interface{}{
+						"detected": true,
+						"wrong":    wrong,
+						"right":    right,
+						"captured": false,
+						"quota":    result.Quota,
+					}
+					if deferErr != nil {
+						out["error"] = deferErr.Error()
+					}
+					json.NewEncoder(os.Stdout).Encode(out)
+				} else {
+					printQuotaExceeded(result.Quota)
+				}
+				return nil
+			}
+
 			// Mark correction as processed
 			correction.Processed = true
 			processedAt := time.Now()
 			correction.ProcessedAt = &processedAt
 
-			// Append to corrections log
-			correctionsPath := filepath.Join(floopDir, "corrections.jsonl")
-			f, err := os.OpenFile(correctionsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
-			if err == nil {
-				json.NewEncoder(f).Encode(correction)
-				f.Close()
+			// Record in corrections log
+			if err := corrections.Append(floopDir, correction); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to record correction: %v\n", err)
 			}
 
 			if jsonOut {
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+
+	"github.com/nvandessel/floop/internal/graphembed"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// maxPriority is the highest priority the ranking scorer distinguishes.
+const maxPriority = 10
+
+func newExportEmbeddingsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-embeddings",
+		Short: "Export graph embeddings of behaviors as CSV",
+		Long: `Export a structural embedding of every behavior as CSV, for experimenting
+with custom ranking models outside floop.
+
+Methods:
+  spectral   Leading eigenvectors of the normalized adjacency matrix (default)
+  node2vec   Skip-gram embeddings trained on biased random walks
+
+Each row has behavior_id, name, kind, scope, priority, the graph embedding
+(graph_0..graph_N), and, when behaviors have stored content embeddings, the
+content embedding (content_0..content_M). Output is deterministic for a given
+--seed.
+
+Feed results back with 'floop import-priorities', which reads the
+behavior_id and priority columns of a CSV in this format.
+
+Examples:
+  floop export-embeddings -o embeddings.csv
+  floop export-embeddings --method node2vec --dimensions 32 -o n2v.csv
+  floop export-embeddings --scope local --no-content`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			method, _ := cmd.Flags().GetString("method")
+			scope, _ := cmd.Flags().GetString("scope")
+			output, _ := cmd.Flags().GetString("output")
+			noContent, _ := cmd.Flags().GetBool("no-content")
+
+			cfg := graphembed.DefaultConfig(graphembed.Method(method))
+			cfg.Dimensions, _ = cmd.Flags().GetInt("dimensions")
+			cfg.Seed, _ = cmd.Flags().GetUint64("seed")
+			cfg.P, _ = cmd.Flags().GetFloat64("p")
+			cfg.Q, _ = cmd.Flags().GetFloat64("q")
+
+			storeScope := store.StoreScope(scope)
+			if !storeScope.Valid() {
+				return fmt.Errorf("invalid scope: %s (must be local, global, or both)", scope)
+			}
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			var source store.GraphStore = graphStore
+			switch storeScope {
+			case store.ScopeLocal:
+				source = graphStore.LocalStore()
+			case store.ScopeGlobal:
+				source = graphStore.GlobalStore()
+			}
+
+			ctx := context.Background()
+			g, err := graphembed.Load(ctx, source)
+			if err != nil {
+				return err
+			}
+			vectors, err := graphembed.Embed(g, cfg)
+			if err != nil {
+				return err
+			}
+
+			var content map[string][]float32
+			if !noContent {
+				if es, ok := source.(store.EmbeddingStore); ok {
+					embeddings, err := es.GetAllEmbeddings(ctx)
+					if err != nil {
+						return fmt.Errorf("failed to load content embeddings: %w", err)
+					}
+					content = make(map[string][]float32, len(embeddings))
+					for _, e := range embeddings {
+						content[e.BehaviorID] = e.Embedding
+					}
+				}
+			}
+
+			rows := make([]embeddingRow, 0, len(g.IDs))
+			for i, id := range g.IDs {
+				node, err := source.GetNode(ctx, id)
+				if err != nil {
+					return fmt.Errorf("failed to get behavior %s: %w", id, err)
+				}
+				if node == nil {
+					continue
+				}
+				b := models.NodeToBehavior(*node)
+				rowScope := string(node.Origin)
+				if storeScope != store.ScopeBoth {
+					rowScope = scope
+				}
+				rows = append(rows, embeddingRow{behavior: b, scope: rowScope, graph: vectors[i], content: content[id]})
+			}
+
+			out := cmd.OutOrStdout()
+			if output != "" && output != "-" {
+				f, err := os.Create(output)
+				if err != nil {
+					return fmt.Errorf("failed to create %s: %w", output, err)
+				}
+				defer f.Close()
+				out = f
+			}
+			contentDims, err := writeEmbeddingsCSV(out, rows, cfg.Dimensions)
+			if err != nil {
+				return fmt.Errorf("failed to write embeddings: %w", err)
+			}
+
+			if output == "" || output == "-" {
+				return nil
+			}
+			if jsonOut {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]interface{}{
+					"output":             output,
+					"behaviors":          len(rows),
+					"method":             cfg.Method,
+					"graph_dimensions":   cfg.Dimensions,
+					"content_dimensions": contentDims,
+				})
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Exported %d behaviors to %s (%s, %d graph dims", len(rows), output, cfg.Method, cfg.Dimensions)
+			if contentDims > 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), ", %d content dims", contentDims)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), ")")
+			return nil
+		},
+	}
+
+	cmd.Flags().String("method", string(graphembed.MethodSpectral), "Embedding method: spectral, node2vec")
+	cmd.Flags().Int("dimensions", 16, "Graph embedding dimensions")
+	cmd.Flags().String("scope", "both", "Store scope: local, global, or both")
+	cmd.Flags().StringP("output", "o", "", "Output CSV file (default: stdout)")
+	cmd.Flags().Bool("no-content", false, "Omit stored content embeddings")
+	cmd.Flags().Uint64("seed", 42, "Random seed")
+	cmd.Flags().Float64("p", 1, "node2vec return parameter (higher = less backtracking)")
+	cmd.Flags().Float64("q", 1, "node2vec in-out parameter (higher = more local walks)")
+
+	return cmd
+}
+
+// embeddingRow is one behavior in an embeddings export.
+type embeddingRow struct {
+	behavior models.Behavior
+	scope    string
+	graph    []float64
+	content  []float32
+}
+
+// writeEmbeddingsCSV writes rows as CSV and returns the number of content
+// embedding columns. Content embeddings take the dimension of the first one
+// found; behaviors without one (or with a different size, from another
+// model) have empty content cells.
+func writeEmbeddingsCSV(out io.Writer, rows []embeddingRow, graphDims int) (int, error) {
+	contentDims := 0
+	for _, r := range rows {
+		if len(r.content) > 0 {
+			contentDims = len(r.content)
+			break
+		}
+	}
+
+	w := csv.NewWriter(out)
+	header := []string{"behavior_id", "name", "kind", "scope", "priority"}
+	for i := range graphDims {
+		header = append(header, fmt.Sprintf("graph_%d", i))
+	}
+	for i := range contentDims {
+		header = append(header, fmt.Sprintf("content_%d", i))
+	}
+	if err := w.Write(header); err != nil {
+		return 0, err
+	}
+
+	for _, r := range rows {
+		record := []string{r.behavior.ID, r.behavior.Name, string(r.behavior.Kind), r.scope, strconv.Itoa(r.behavior.Priority)}
+		for _, v := range r.graph {
+			record = append(record, strconv.FormatFloat(v, 'g', 6, 64))
+		}
+		for i := range contentDims {
+			cell := ""
+			if len(r.content) == contentDims {
+				cell = strconv.FormatFloat(float64(r.content[i]), 'g', 6, 32)
+			}
+			record = append(record, cell)
+		}
+		if err := w.Write(record); err != nil {
+			return 0, err
+		}
+	}
+	w.Flush()
+	return contentDims, w.Error()
+}
+
+func newImportPrioritiesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-priorities <file.csv>",
+		Short: "Set behavior priorities from a CSV file",
+		Long: `Set behavior priorities from a CSV file, such as the output of a ranking
+model trained on 'floop export-embeddings'.
+
+The file needs a header row with behavior_id and priority columns; other
+columns are ignored. Priorities range from 0 to 10 and feed the priority
+component of activation ranking. Fractional values are rounded.
+
+Examples:
+  floop import-priorities ranked.csv
+  floop import-priorities ranked.csv --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open %s: %w", args[0], err)
+			}
+			defer f.Close()
+			priorities, err := readPriorities(f)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", args[0], err)
+			}
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			ctx := context.Background()
+			var updated, unchanged int
+			var unknown []string
+			for _, p := range priorities {
+				node, err := graphStore.GetNode(ctx, p.id)
+				if err != nil {
+					return fmt.Errorf("failed to get behavior %s: %w", p.id, err)
+				}
+				if node == nil || node.Kind != store.NodeKindBehavior {
+					unknown = append(unknown, p.id)
+					continue
+				}
+				if models.NodeToBehavior(*node).Priority == p.priority {
+					unchanged++
+					continue
+				}
+				updated++
+				if dryRun {
+					continue
+				}
+				if node.Metadata == nil {
+					node.Metadata = make(map[string]interface{})
+				}
+				node.Metadata["priority"] = p.priority
+				if err := graphStore.UpdateNode(ctx, *node); err != nil {
+					return fmt.Errorf("failed to update %s: %w", p.id, err)
+				}
+			}
+			if !dryRun && updated > 0 {
+				if err := graphStore.Sync(ctx); err != nil {
+					return fmt.Errorf("failed to sync store: %w", err)
+				}
+			}
+
+			if jsonOut {
+				if unknown == nil {
+					unknown = []string{}
+				}
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]interface{}{
+					"updated":   updated,
+					"unchanged": unchanged,
+					"unknown":   unknown,
+					"dry_run":   dryRun,
+				})
+			}
+			verb := "Updated"
+			if dryRun {
+				verb = "Would update"
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s %d priorities (%d unchanged)\n", verb, updated, unchanged)
+			if len(unknown) > 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "Skipped %d unknown behavior IDs:\n", len(unknown))
+				for _, id := range unknown {
+					fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", id)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Show what would change without writing")
+
+	return cmd
+}
+
+// importedPriority is one row of a priorities file.
+type importedPriority struct {
+	id       string
+	priority int
+}
+
+// readPriorities parses a CSV with behavior_id and priority columns.
+func readPriorities(r io.Reader) ([]importedPriority, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("missing header row: %w", err)
+	}
+	idCol, priorityCol := -1, -1
+	for i, name := range header {
+		switch name {
+		case "behavior_id":
+			idCol = i
+		case "priority":
+			priorityCol = i
+		}
+	}
+	if idCol < 0 || priorityCol < 0 {
+		return nil, fmt.Errorf("header must include behavior_id and priority columns")
+	}
+
+	var priorities []importedPriority
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return priorities, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if max(idCol, priorityCol) >= len(record) {
+			return nil, fmt.Errorf("line %d: missing columns", line)
+		}
+		v, err := strconv.ParseFloat(record[priorityCol], 64)
+		if err != nil || v < 0 || v > maxPriority {
+			return nil, fmt.Errorf("line %d: priority %q must be a number from 0 to %d", line, record[priorityCol], maxPriority)
+		}
+		priorities = append(priorities, importedPriority{id: record[idCol], priority: int(math.Round(v))})
+	}
+}
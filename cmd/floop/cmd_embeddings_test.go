@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// embeddingsTestCmds returns fresh commands for the tests in this file.
+func embeddingsTestCmds() []*cobra.Command {
+	return []*cobra.Command{newInitCmd(), newLearnCmd(), newExportEmbeddingsCmd(), newImportPrioritiesCmd()}
+}
+
+func TestExportEmbeddingsAndImportPriorities(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	mustRunTestCmd(t, embeddingsTestCmds(), "init", "--root", tmpDir)
+	mustRunTestCmd(t, embeddingsTestCmds(), "learn", "--right", "use pathlib.Path instead of os.path", "--scope", "local", "--root", tmpDir, "--json")
+	mustRunTestCmd(t, embeddingsTestCmds(), "learn", "--right", "prefer table-driven tests in Go", "--scope", "local", "--root", tmpDir, "--json")
+
+	out := mustRunTestCmd(t, embeddingsTestCmds(), "export-embeddings", "--scope", "local", "--dimensions", "4", "--root", tmpDir)
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV %q: %v", out, err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d CSV records, want header + 2 rows", len(records))
+	}
+	wantHeader := "behavior_id,name,kind,scope,priority,graph_0,graph_1,graph_2,graph_3"
+	if got := strings.Join(records[0], ","); got != wantHeader {
+		t.Errorf("header = %s, want %s", got, wantHeader)
+	}
+	if records[1][3] != "local" || records[1][4] != "0" {
+		t.Errorf("row = %v, want local scope and priority 0", records[1])
+	}
+
+	// Write the export to a file, rank it externally, and import the result
+	exportPath := filepath.Join(tmpDir, "embeddings.csv")
+	summary := mustRunTestCmd(t, embeddingsTestCmds(), "export-embeddings", "--scope", "local", "--method", "node2vec", "--root", tmpDir, "-o", exportPath)
+	if !strings.Contains(summary, "Exported 2 behaviors") {
+		t.Errorf("unexpected summary %q", summary)
+	}
+
+	ranked := "behavior_id,priority\n" + records[1][0] + ",7.6\n" + records[2][0] + ",0\nmissing-id,3\n"
+	rankedPath := filepath.Join(tmpDir, "ranked.csv")
+	if err := os.WriteFile(rankedPath, []byte(ranked), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out = mustRunTestCmd(t, embeddingsTestCmds(), "import-priorities", rankedPath, "--dry-run", "--root", tmpDir)
+	if !strings.Contains(out, "Would update 1 priorities (1 unchanged)") || !strings.Contains(out, "missing-id") {
+		t.Errorf("unexpected dry-run output:\n%s", out)
+	}
+	out = mustRunTestCmd(t, embeddingsTestCmds(), "import-priorities", rankedPath, "--root", tmpDir)
+	if !strings.Contains(out, "Updated 1 priorities") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	graphStore, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer graphStore.Close()
+	node, err := graphStore.GetNode(context.Background(), records[1][0])
+	if err != nil || node == nil {
+		t.Fatalf("GetNode failed: %v", err)
+	}
+	if p := models.NodeToBehavior(*node).Priority; p != 8 {
+		t.Errorf("priority = %d, want 8 (rounded from 7.6)", p)
+	}
+}
+
+func TestReadPriorities_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"missing columns", "id,score\na,1\n"},
+		{"out of range", "behavior_id,priority\na,11\n"},
+		{"not a number", "behavior_id,priority\na,high\n"},
+		{"empty", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := readPriorities(strings.NewReader(tt.input)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
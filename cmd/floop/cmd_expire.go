@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/expiry"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newExpireCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "expire",
+		Short: "Deprecate behaviors whose expiry has passed",
+		Long: `Deprecate time-boxed behaviors (learned with --expires) whose expiry has
+passed. Expired behaviors stop activating as soon as they expire; this
+sweep also moves them out of the active set and queues a notice that is
+shown in the next session-start digest.
+
+The sweep also runs automatically at session start and when the MCP server
+starts. Undo with 'floop restore <id>', which clears the passed expiry.
+
+Examples:
+  floop expire             # Deprecate expired behaviors
+  floop expire --dry-run   # List expired behaviors without changing them`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			floopDir := filepath.Join(root, ".floop")
+			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
+				return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+			}
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			notices, err := expiry.Sweep(context.Background(), graphStore, time.Now(), dryRun)
+			if err != nil {
+				return err
+			}
+			if !dryRun {
+				if err := expiry.RecordNotices(floopDir, notices); err != nil {
+					return err
+				}
+			}
+
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"dry_run": dryRun,
+					"expired": notices,
+				})
+			}
+
+			if len(notices) == 0 {
+				fmt.Println("No expired behaviors.")
+				return nil
+			}
+
+			verb := "Deprecated"
+			if dryRun {
+				verb = "Would deprecate"
+			}
+			fmt.Printf("%s %d expired behavior(s):\n", verb, len(notices))
+			for _, n := range notices {
+				fmt.Printf("  %s (%s) expired %s\n", n.Name, n.BehaviorID, n.ExpiresAt.Local().Format("2006-01-02 15:04"))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("dry-run", false, "List expired behaviors without deprecating them")
+	return cmd
+}
+
+// sweepExpiredForDigest deprecates expired behaviors and returns the digest
+// of every notice not yet shown. Errors yield an empty digest so hooks stay
+// silent.
+func sweepExpiredForDigest(root string) string {
+	floopDir := filepath.Join(root, ".floop")
+
+	graphStore, err := store.NewMultiGraphStore(root)
+	if err != nil {
+		return ""
+	}
+	notices, err := expiry.Sweep(context.Background(), graphStore, time.Now(), false)
+	graphStore.Close()
+	if err == nil {
+		_ = expiry.RecordNotices(floopDir, notices)
+	}
+
+	pending, err := expiry.TakeNotices(floopDir)
+	if err != nil {
+		return ""
+	}
+	return expiry.FormatDigest(pending)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/expiry"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// expireTestCmds returns fresh commands for the tests in this file.
+func expireTestCmds() []*cobra.Command {
+	return []*cobra.Command{newInitCmd(), newLearnCmd(), newExpireCmd(), newRestoreCmd(), newHookCmd()}
+}
+
+func TestExpireCmd_DeprecatesAndRestores(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	if _, err := runTestCmd(t, expireTestCmds(), "init", "--root", tmpDir); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if _, err := runTestCmd(t, expireTestCmds(), "learn", "--right", "run migrations with --v2", "--expires", "yesterday", "--root", tmpDir); err == nil {
+		t.Fatal("learn should reject an invalid --expires")
+	}
+	if _, err := runTestCmd(t, expireTestCmds(), "learn", "--right", "run migrations with --v2", "--expires", "72h", "--scope", "local", "--root", tmpDir, "--json"); err != nil {
+		t.Fatalf("learn failed: %v", err)
+	}
+
+	// Backdate the learned behavior's expiry so the sweep picks it up
+	ctx := context.Background()
+	s, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	nodes, err := s.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil || len(nodes) != 1 {
+		t.Fatalf("QueryNodes() = %d nodes, %v; want 1", len(nodes), err)
+	}
+	id := nodes[0].ID
+	if b := models.NodeToBehavior(nodes[0]); b.ExpiresAt == nil {
+		t.Fatal("learned behavior should carry its expiry")
+	}
+	nodes[0].Metadata["expires_at"] = time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	if err := s.UpdateNode(ctx, nodes[0]); err != nil {
+		t.Fatalf("UpdateNode() error = %v", err)
+	}
+	s.Close()
+
+	if _, err := runTestCmd(t, expireTestCmds(), "expire", "--dry-run", "--root", tmpDir); err != nil {
+		t.Fatalf("expire --dry-run failed: %v", err)
+	}
+	if _, err := runTestCmd(t, expireTestCmds(), "expire", "--root", tmpDir); err != nil {
+		t.Fatalf("expire failed: %v", err)
+	}
+
+	s, err = store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	node, _ := s.GetNode(ctx, id)
+	s.Close()
+	if node == nil || node.Kind != store.NodeKindDeprecated {
+		t.Fatalf("expired behavior = %+v, want deprecated", node)
+	}
+
+	// The session-start digest reports the notice once
+	out, err := runTestCmd(t, expireTestCmds(), "hook", "session-start", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("hook session-start failed: %v", err)
+	}
+	if !strings.Contains(out, "Expired behaviors deprecated (1)") || !strings.Contains(out, id) {
+		t.Errorf("session-start output missing expiry digest:\n%s", out)
+	}
+	out, _ = runTestCmd(t, expireTestCmds(), "hook", "session-start", "--root", tmpDir)
+	if strings.Contains(out, "Expired behaviors") {
+		t.Errorf("expiry digest should only be shown once:\n%s", out)
+	}
+
+	// Restoring clears the passed expiry so the next sweep leaves it alone
+	if _, err := runTestCmd(t, expireTestCmds(), "restore", id, "--root", tmpDir); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	s, err = store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+	notices, err := expiry.Sweep(ctx, s, time.Now(), true)
+	if err != nil {
+		t.Fatalf("Sweep() error = %v", err)
+	}
+	if len(notices) != 0 {
+		t.Errorf("restored behavior should not be expired again, got %+v", notices)
+	}
+}
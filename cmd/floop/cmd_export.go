@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/importer"
+	"github.com/nvandessel/floop/internal/pack"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export <dir>",
+		Short: "Export behaviors as a reviewable Markdown behavior pack",
+		Long: `Export behaviors to a directory of Markdown files that a team can review in a
+pull request and share across repos with 'floop import'.
+
+The directory holds a pack.md with the pack's metadata and an index, and one
+.md file per behavior. Each behavior file has YAML frontmatter (id, name,
+kind, when-conditions, tags, confidence, priority, edges to other behaviors
+in the pack, provenance, and usage stats) followed by the behavior's text as
+the Markdown body.
+
+Only active behaviors are exported. For a binary pack installable from a URL
+or GitHub release, use 'floop pack create'.
+
+Examples:
+  floop export team-behaviors/
+  floop export go-pack/ --tags go --id my-org/go --version 1.0.0
+  floop export team-behaviors/ --scope local --force`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := args[0]
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			scope, _ := cmd.Flags().GetString("scope")
+			tags, _ := cmd.Flags().GetString("tags")
+			kinds, _ := cmd.Flags().GetString("kinds")
+			fromPack, _ := cmd.Flags().GetString("from-pack")
+			id, _ := cmd.Flags().GetString("id")
+			ver, _ := cmd.Flags().GetString("version")
+			desc, _ := cmd.Flags().GetString("description")
+			author, _ := cmd.Flags().GetString("author")
+			force, _ := cmd.Flags().GetBool("force")
+
+			storeScope := store.StoreScope(scope)
+			if !storeScope.Valid() {
+				return fmt.Errorf("invalid scope: %s (must be local, global, or both)", scope)
+			}
+
+			filter := pack.CreateFilter{FromPack: fromPack}
+			if tags != "" {
+				filter.Tags = strings.Split(tags, ",")
+			}
+			if kinds != "" {
+				filter.Kinds = strings.Split(kinds, ",")
+			}
+			manifest := pack.PackManifest{
+				ID:          pack.PackID(id),
+				Version:     ver,
+				Description: desc,
+				Author:      author,
+			}
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			var source store.GraphStore = graphStore
+			switch storeScope {
+			case store.ScopeLocal:
+				source = graphStore.LocalStore()
+			case store.ScopeGlobal:
+				source = graphStore.GlobalStore()
+			}
+
+			p, err := pack.ExportMarkdown(context.Background(), source, filter, manifest, dir, pack.MarkdownExportOptions{
+				FloopVersion: version,
+				Force:        force,
+			})
+			if err != nil {
+				return fmt.Errorf("export failed: %w", err)
+			}
+
+			edges := 0
+			for _, b := range p.Behaviors {
+				edges += len(b.Edges)
+			}
+			if jsonOut {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]interface{}{
+					"dir":       dir,
+					"behaviors": len(p.Behaviors),
+					"edges":     edges,
+				})
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Exported %d behaviors and %d edges to %s\n", len(p.Behaviors), edges, dir)
+			return nil
+		},
+	}
+
+	cmd.Flags().String("scope", "both", "Store scope: local, global, or both")
+	cmd.Flags().String("tags", "", "Only export behaviors with any of these tags (comma-separated)")
+	cmd.Flags().String("kinds", "", "Only export behaviors of these kinds (comma-separated)")
+	cmd.Flags().String("from-pack", "", "Only export behaviors installed from this pack")
+	cmd.Flags().String("id", "", "Pack ID in namespace/name format, stamped on import")
+	cmd.Flags().String("version", "", "Pack version, stamped on import")
+	cmd.Flags().String("description", "", "Pack description")
+	cmd.Flags().String("author", "", "Pack author")
+	cmd.Flags().Bool("force", false, "Replace the .md files of a non-empty directory")
+
+	return cmd
+}
+
+func newImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <path>",
+		Short: "Import a Markdown behavior pack or external sources such as ADRs",
+		Long: `Import behaviors from a Markdown behavior pack written by 'floop export'
+(or by hand in the same format).
+
+With --from, import from an external source instead, so the knowledge a team
+already wrote down seeds the graph:
+  adr   Architecture decision records in Markdown (adr-tools or MADR
+        layout), a directory of them or one file. Each accepted ADR becomes
+        a behavior holding its decision, as a constraint, preference, or
+        directive depending on its wording; proposed, rejected, deprecated,
+        and superseded ADRs are skipped. Behaviors record the importer and
+        source file in their provenance, and their ID comes from the file
+        name, so importing again only adds new ADRs.
+
+Behaviors already in the store are deduplicated:
+  - a behavior with the same ID is left alone, even if it was forgotten
+  - a behavior similar to an existing one (deduplication.similarity_threshold,
+    or --threshold) is skipped as a duplicate
+
+Edges between pack behaviors are added, with edges to a skipped duplicate
+pointing at the existing behavior instead. Usage stats in the pack are not
+imported. Behaviors are stamped with the pack's id and version, if it has
+them.
+
+Examples:
+  floop import team-behaviors/ --dry-run
+  floop import team-behaviors/
+  floop import go-pack/ --scope global --threshold 0.8
+  floop import --from adr docs/adr/ --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := args[0]
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			scope, _ := cmd.Flags().GetString("scope")
+			threshold, _ := cmd.Flags().GetFloat64("threshold")
+			from, _ := cmd.Flags().GetString("from")
+
+			storeScope := store.StoreScope(scope)
+			if storeScope != store.ScopeLocal && storeScope != store.ScopeGlobal {
+				return fmt.Errorf("invalid scope: %s (must be local or global)", scope)
+			}
+			if threshold < 0 || threshold > 1 {
+				return fmt.Errorf("invalid threshold: %v (must be between 0 and 1)", threshold)
+			}
+			if !cmd.Flags().Changed("threshold") {
+				if cfg, err := config.Load(); err == nil {
+					threshold = cfg.Deduplication.SimilarityThreshold
+				}
+			}
+
+			var imp importer.Importer
+			var p *pack.MarkdownPack
+			if from == "pack" {
+				var err error
+				if p, err = pack.ReadMarkdownPack(dir); err != nil {
+					return err
+				}
+			} else {
+				var ok bool
+				if imp, ok = importer.Get(from); !ok {
+					return fmt.Errorf("unknown source %q, available: pack, %s", from, strings.Join(importer.Formats(), ", "))
+				}
+			}
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			opts := pack.MarkdownImportOptions{
+				DryRun:              dryRun,
+				Scope:               storeScope,
+				SimilarityThreshold: threshold,
+			}
+			var result *pack.MarkdownImportResult
+			var skipped []importer.Skipped
+			if imp != nil {
+				imported, err := importer.Import(context.Background(), graphStore, imp, dir, opts)
+				if err != nil {
+					return fmt.Errorf("import failed: %w", err)
+				}
+				if jsonOut {
+					return json.NewEncoder(cmd.OutOrStdout()).Encode(imported)
+				}
+				result, skipped = imported.MarkdownImportResult, imported.Skipped
+			} else {
+				result, err = pack.ImportMarkdown(context.Background(), graphStore, p, opts)
+				if err != nil {
+					return fmt.Errorf("import failed: %w", err)
+				}
+				if jsonOut {
+					return json.NewEncoder(cmd.OutOrStdout()).Encode(result)
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			verb := "Imported"
+			if dryRun {
+				verb = "Would import"
+			}
+			fmt.Fprintf(out, "%s %d behaviors and %d edges from %s\n", verb, len(result.Added), result.EdgesAdded, dir)
+			for _, id := range result.Added {
+				fmt.Fprintf(out, "  + %s\n", id)
+			}
+			if len(result.Existing) > 0 {
+				fmt.Fprintf(out, "Already present (%d):\n", len(result.Existing))
+				for _, id := range result.Existing {
+					fmt.Fprintf(out, "  = %s\n", id)
+				}
+			}
+			if len(result.Duplicates) > 0 {
+				fmt.Fprintf(out, "Duplicates skipped (%d):\n", len(result.Duplicates))
+				for _, d := range result.Duplicates {
+					fmt.Fprintf(out, "  ~ %s duplicates %s (%.2f)\n", d.ID, d.ExistingID, d.Similarity)
+				}
+			}
+			if len(skipped) > 0 {
+				fmt.Fprintf(out, "Not imported (%d):\n", len(skipped))
+				for _, s := range skipped {
+					fmt.Fprintf(out, "  - %s: %s\n", s.File, s.Reason)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Show what would be imported without writing")
+	cmd.Flags().String("from", "pack", "Source to import: pack, or an external format ("+strings.Join(importer.Formats(), ", ")+")")
+	cmd.Flags().String("scope", "local", "Store to import into: local or global")
+	cmd.Flags().Float64("threshold", 0, "Similarity at which a behavior counts as a duplicate (default: deduplication.similarity_threshold)")
+
+	return cmd
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/pathutil"
+	"github.com/nvandessel/floop/internal/portability"
+	"github.com/spf13/cobra"
+)
+
+func newExportAllCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-all",
+		Short: "Export the whole floop installation to one archive",
+		Long: `Export everything floop keeps on disk to a single .tar.gz archive for
+moving to another machine:
+
+  - global store (~/.floop) and project store (<root>/.floop)
+  - ~/.floop/config.yaml, with secrets such as llm.api_key removed
+    (${VAR} references are kept)
+  - corrections (exported from floop.db as corrections.jsonl) and
+    held_corrections.jsonl per scope
+  - audit.jsonl per scope
+  - an index of ~/.floop/backups (the backup files are not included)
+
+This is whole-installation portability, distinct from 'floop backup' (one
+graph) and 'floop pack' (shareable behavior bundles). Restore it with
+'floop import-all'.
+
+Default location: ~/.floop/backups/floop-export-YYYYMMDD-HHMMSS.tar.gz
+
+Examples:
+  floop export-all                                  # Export to default location
+  floop export-all --output ~/.floop/backups/move.tar.gz
+  floop export-all --no-local                       # Global scope only`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			outputPath, _ := cmd.Flags().GetString("output")
+			noLocal, _ := cmd.Flags().GetBool("no-local")
+
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("failed to get home directory: %w", err)
+			}
+
+			if outputPath == "" {
+				dir, err := backup.DefaultBackupDir()
+				if err != nil {
+					return fmt.Errorf("failed to get backup directory: %w", err)
+				}
+				outputPath = portability.GenerateExportPath(dir)
+			} else {
+				allowedDirs, err := pathutil.DefaultAllowedBackupDirsWithProjectRoot(root)
+				if err != nil {
+					return fmt.Errorf("failed to determine allowed backup dirs: %w", err)
+				}
+				if err := pathutil.ValidatePath(outputPath, allowedDirs); err != nil {
+					return fmt.Errorf("export path rejected: %w", err)
+				}
+			}
+
+			opts := portability.ExportOptions{
+				HomeDir:      homeDir,
+				FloopVersion: version,
+			}
+			if !noLocal {
+				opts.ProjectRoot = root
+			}
+
+			manifest, err := portability.Export(context.Background(), outputPath, opts)
+			if err != nil {
+				return fmt.Errorf("export failed: %w", err)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"path":     outputPath,
+					"manifest": manifest,
+				})
+			}
+
+			fmt.Printf("Exported floop installation to %s\n", outputPath)
+			for _, f := range manifest.Files {
+				fmt.Printf("  %-36s %d bytes\n", f.Path, f.Size)
+			}
+			if len(manifest.ExcludedSecrets) > 0 {
+				fmt.Printf("Secrets excluded: %v (set them again on the new machine)\n", manifest.ExcludedSecrets)
+			}
+			if len(manifest.Backups) > 0 {
+				fmt.Printf("Indexed %d backup(s); copy ~/.floop/backups separately to keep them.\n", len(manifest.Backups))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("output", "", "Output file path (default: auto-generated in ~/.floop/backups/)")
+	cmd.Flags().Bool("no-local", false, "Export the global scope only")
+
+	return cmd
+}
+
+func newImportAllCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-all <archive>",
+		Short: "Import a whole-installation archive from floop export-all",
+		Long: `Import an archive created by 'floop export-all', typically on a new machine.
+
+The archive is verified against its manifest checksums before anything is
+written. Then:
+
+  - the global and project stores are merged (existing behaviors are kept)
+  - corrections, held corrections, and audit logs gain only lines they
+    don't already have
+  - config.yaml is written only if ~/.floop/config.yaml does not exist,
+    unless --overwrite-config is given
+
+The project scope is restored into --root. Run from the project directory,
+or pass --no-local to import the global scope only.
+
+Place the archive in ~/.floop/backups/ or <root>/.floop/backups/ first.
+
+Examples:
+  floop import-all ~/.floop/backups/floop-export-20260301-120000.tar.gz
+  floop import-all move.tar.gz --dry-run           # Verify and show contents
+  floop import-all move.tar.gz --overwrite-config`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inputPath := args[0]
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			noLocal, _ := cmd.Flags().GetBool("no-local")
+			overwriteConfig, _ := cmd.Flags().GetBool("overwrite-config")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			allowedDirs, err := pathutil.DefaultAllowedBackupDirsWithProjectRoot(root)
+			if err != nil {
+				return fmt.Errorf("failed to determine allowed backup dirs: %w", err)
+			}
+			if err := pathutil.ValidatePath(inputPath, allowedDirs); err != nil {
+				return fmt.Errorf("import path rejected: %w", err)
+			}
+
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("failed to get home directory: %w", err)
+			}
+
+			opts := portability.ImportOptions{
+				HomeDir:         homeDir,
+				OverwriteConfig: overwriteConfig,
+				DryRun:          dryRun,
+			}
+			if !noLocal {
+				opts.ProjectRoot = root
+			}
+
+			result, err := portability.Import(context.Background(), inputPath, opts)
+			if err != nil {
+				return fmt.Errorf("import failed: %w", err)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(result)
+			}
+
+			m := result.Manifest
+			fmt.Printf("Archive from floop %s, created %s (%d files, checksums verified)\n",
+				valueOrDefault(m.FloopVersion, "unknown"), m.CreatedAt.Format("2006-01-02 15:04:05"), len(m.Files))
+			if dryRun {
+				for _, f := range m.Files {
+					fmt.Printf("  %-36s %d bytes\n", f.Path, f.Size)
+				}
+				fmt.Printf("Config: would be %s\n", result.Config)
+			} else {
+				for _, scope := range []string{portability.ScopeGlobal, portability.ScopeLocal} {
+					if r, ok := result.Stores[scope]; ok {
+						fmt.Printf("  %s store: %d nodes restored, %d skipped; %d edges restored, %d skipped\n",
+							scope, r.NodesRestored, r.NodesSkipped, r.EdgesRestored, r.EdgesSkipped)
+					}
+				}
+				logs := make([]string, 0, len(result.LinesAppended))
+				for p := range result.LinesAppended {
+					logs = append(logs, p)
+				}
+				sort.Strings(logs)
+				for _, p := range logs {
+					fmt.Printf("  %s: %d new line(s)\n", p, result.LinesAppended[p])
+				}
+				fmt.Printf("Config: %s\n", result.Config)
+			}
+			for _, scope := range result.SkippedScopes {
+				fmt.Printf("Skipped %s scope (--no-local)\n", scope)
+			}
+			if len(m.ExcludedSecrets) > 0 {
+				fmt.Printf("Secrets not included: %v\n", m.ExcludedSecrets)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("no-local", false, "Import the global scope only")
+	cmd.Flags().Bool("overwrite-config", false, "Replace an existing ~/.floop/config.yaml")
+	cmd.Flags().Bool("dry-run", false, "Verify the archive and show its contents without importing")
+
+	return cmd
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportAllThenImportAll(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+	archive := backupOutputPath(t, tmpDir, "move.tar.gz")
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newExportAllCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"export-all", "--output", archive, "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("export-all failed: %v", err)
+	}
+	if _, err := os.Stat(archive); err != nil {
+		t.Fatalf("archive not created: %v", err)
+	}
+
+	for _, args := range [][]string{
+		{"import-all", archive, "--dry-run", "--root", tmpDir},
+		{"import-all", archive, "--json", "--root", tmpDir},
+	} {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newImportAllCmd())
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetArgs(args)
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+	}
+}
+
+func TestExportAllCmd_RejectsOutsidePath(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newExportAllCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"export-all", "--output", filepath.Join(tmpDir, "elsewhere.tar.gz"), "--root", tmpDir})
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "export path rejected") {
+		t.Errorf("export-all outside backup dirs error = %v, want rejection", err)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/nvandessel/floop/internal/corrections"
+	"github.com/nvandessel/floop/internal/dataset"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newExportCorrectionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-corrections",
+		Short: "Export corrections and their outcomes as a CSV, Parquet, or JSONL dataset",
+		Long: `Export every logged correction, flattened into one table for research
+and analysis outside floop.
+
+Each row pairs a correction with one behavior extracted from it, so a
+correction that produced two behaviors has two rows and one that produced
+none has a single row with empty behavior columns. Rows carry:
+
+  - the correction: id, scope, timestamp, processed state, the agent's
+    action, the human's response, and the corrected action
+  - its context: repo, branch, project type, file, language, frameworks,
+    task, environment, and agent
+  - the extracted behavior: id, name, kind, status (active, forgotten,
+    deprecated, merged, retired, or quarantined), and tags
+  - downstream stats: confidence, priority, activation, follow,
+    confirmation, and override counts, and last activation time
+
+Corrections are read from the corrections log of <root>/.floop (local)
+and ~/.floop (global). Parquet output is Snappy-compressed with typed
+columns; CSV leaves null cells empty. JSONL output is the raw correction
+records instead of rows, one per line, oldest first within each scope, in
+the corrections.jsonl layout 'floop import-all' reads.
+
+Examples:
+  floop export-corrections -o corrections.csv
+  floop export-corrections --format parquet -o corrections.parquet
+  floop export-corrections --scope local | duckdb -c "SELECT ..."
+  floop export-corrections --format jsonl --scope local -o corrections.jsonl`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			format, _ := cmd.Flags().GetString("format")
+			scope, _ := cmd.Flags().GetString("scope")
+			output, _ := cmd.Flags().GetString("output")
+
+			if format != "jsonl" && !slices.Contains(dataset.Formats, format) {
+				return fmt.Errorf("invalid format: %s (must be csv, parquet, or jsonl)", format)
+			}
+			storeScope := store.StoreScope(scope)
+			if !storeScope.Valid() {
+				return fmt.Errorf("invalid scope: %s (must be local, global, or both)", scope)
+			}
+
+			var sources []dataset.Source
+			if storeScope != store.ScopeGlobal {
+				local, err := corrections.Load(filepath.Join(root, ".floop"))
+				if err != nil {
+					return fmt.Errorf("failed to read local corrections: %w", err)
+				}
+				sources = append(sources, dataset.Source{Scope: "local", Corrections: local})
+			}
+			if storeScope != store.ScopeLocal {
+				globalPath, err := store.GlobalFloopPath()
+				if err != nil {
+					return fmt.Errorf("failed to get global path: %w", err)
+				}
+				global, err := corrections.Load(globalPath)
+				if err != nil {
+					return fmt.Errorf("failed to read global corrections: %w", err)
+				}
+				sources = append(sources, dataset.Source{Scope: "global", Corrections: global})
+			}
+			if format == "jsonl" {
+				var records []models.Correction
+				for _, src := range sources {
+					records = append(records, src.Corrections...)
+				}
+				return writeExport(cmd, output, format, jsonOut, len(records), len(records), func(w io.Writer) error {
+					return corrections.WriteJSONL(w, records)
+				})
+			}
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			var source store.GraphStore = graphStore
+			switch storeScope {
+			case store.ScopeLocal:
+				source = graphStore.LocalStore()
+			case store.ScopeGlobal:
+				source = graphStore.GlobalStore()
+			}
+
+			rows, err := dataset.Build(context.Background(), source, sources)
+			if err != nil {
+				return err
+			}
+
+			count := 0
+			for _, src := range sources {
+				count += len(src.Corrections)
+			}
+			return writeExport(cmd, output, format, jsonOut, count, len(rows), func(w io.Writer) error {
+				return dataset.Write(w, format, rows)
+			})
+		},
+	}
+
+	cmd.Flags().String("format", "csv", "Output format: csv, parquet, jsonl")
+	cmd.Flags().String("scope", "both", "Store scope: local, global, or both")
+	cmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+
+	return cmd
+}
+
+// writeExport writes an export-corrections dataset to output, or stdout,
+// and reports it when written to a file.
+func writeExport(cmd *cobra.Command, output, format string, jsonOut bool, count, rows int, write func(io.Writer) error) error {
+	out := cmd.OutOrStdout()
+	if output != "" && output != "-" {
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", output, err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := write(out); err != nil {
+		return fmt.Errorf("failed to write corrections: %w", err)
+	}
+
+	if output == "" || output == "-" {
+		return nil
+	}
+	if jsonOut {
+		return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]interface{}{
+			"output":      output,
+			"format":      format,
+			"corrections": count,
+			"rows":        rows,
+		})
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Exported %d corrections (%d rows) to %s (%s)\n", count, rows, output, format)
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/corrections"
+	"github.com/spf13/cobra"
+)
+
+// exportCorrectionsTestCmds returns fresh commands for the tests in this file.
+func exportCorrectionsTestCmds() []*cobra.Command {
+	return []*cobra.Command{newInitCmd(), newLearnCmd(), newExportCorrectionsCmd()}
+}
+
+func TestExportCorrections(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	mustRunTestCmd(t, exportCorrectionsTestCmds(), "init", "--root", tmpDir)
+	mustRunTestCmd(t, exportCorrectionsTestCmds(), "learn", "--wrong", "used os.path", "--right", "use pathlib.Path instead of os.path", "--scope", "local", "--root", tmpDir, "--json")
+
+	out := mustRunTestCmd(t, exportCorrectionsTestCmds(), "export-corrections", "--scope", "local", "--root", tmpDir)
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV %q: %v", out, err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d CSV records, want header + 1 row", len(records))
+	}
+	cell := func(name string) string {
+		for i, n := range records[0] {
+			if n == name {
+				return records[1][i]
+			}
+		}
+		t.Fatalf("missing column %s", name)
+		return ""
+	}
+	if cell("scope") != "local" || cell("agent_action") != "used os.path" {
+		t.Errorf("row = %v", records[1])
+	}
+	if cell("behavior_id") == "" || cell("behavior_status") != "active" {
+		t.Errorf("behavior_id/status = %q/%q, want the extracted active behavior", cell("behavior_id"), cell("behavior_status"))
+	}
+
+	path := filepath.Join(tmpDir, "corrections.parquet")
+	out = mustRunTestCmd(t, exportCorrectionsTestCmds(), "export-corrections", "--format", "parquet", "--scope", "local", "--root", tmpDir, "-o", path, "--json")
+	var summary map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &summary); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if summary["corrections"] != float64(1) || summary["rows"] != float64(1) || summary["format"] != "parquet" {
+		t.Errorf("summary = %v", summary)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("PAR1")) {
+		t.Error("output is not a Parquet file")
+	}
+}
+
+func TestExportCorrections_JSONL(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	mustRunTestCmd(t, exportCorrectionsTestCmds(), "init", "--root", tmpDir)
+	mustRunTestCmd(t, exportCorrectionsTestCmds(), "learn", "--wrong", "used os.path", "--right", "use pathlib.Path instead of os.path", "--scope", "local", "--root", tmpDir, "--json")
+	mustRunTestCmd(t, exportCorrectionsTestCmds(), "learn", "--right", "wrap errors with fmt.Errorf and %w", "--scope", "local", "--root", tmpDir, "--json")
+
+	path := filepath.Join(tmpDir, "export", "corrections.jsonl")
+	os.MkdirAll(filepath.Dir(path), 0700)
+	mustRunTestCmd(t, exportCorrectionsTestCmds(), "export-corrections", "--format", "jsonl", "--scope", "local", "--root", tmpDir, "-o", path)
+	exported, err := corrections.ReadJSONL(path)
+	if err != nil {
+		t.Fatalf("ReadJSONL() error = %v", err)
+	}
+	if len(exported) != 2 || exported[0].AgentAction != "used os.path" || !exported[1].Processed {
+		t.Fatalf("exported = %+v, want both corrections, oldest first", exported)
+	}
+
+	// The export is imported when placed in another project's .floop
+	other := filepath.Join(tmpDir, "other")
+	os.MkdirAll(filepath.Join(other, ".floop"), 0700)
+	data, _ := os.ReadFile(path)
+	os.WriteFile(filepath.Join(other, ".floop", corrections.File), data, 0600)
+	if imported := loggedCorrections(t, other); !reflect.DeepEqual(imported, loggedCorrections(t, tmpDir)) {
+		t.Errorf("imported = %+v, want the exported corrections", imported)
+	}
+}
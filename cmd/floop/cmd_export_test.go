@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// exportTestCmds returns fresh commands for the tests in this file.
+func exportTestCmds() []*cobra.Command {
+	return []*cobra.Command{newInitCmd(), newLearnCmd(), newExportCmd(), newImportCmd()}
+}
+
+func TestExportImport(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	source := filepath.Join(tmpDir, "source")
+	target := filepath.Join(tmpDir, "target")
+
+	mustRunTestCmd(t, exportTestCmds(), "init", "--root", source)
+	mustRunTestCmd(t, exportTestCmds(), "learn", "--right", "use pathlib.Path instead of os.path", "--scope", "local", "--root", source, "--json")
+	mustRunTestCmd(t, exportTestCmds(), "learn", "--right", "prefer table-driven tests in Go", "--scope", "local", "--root", source, "--json")
+
+	packDir := filepath.Join(tmpDir, "pack")
+	out := mustRunTestCmd(t, exportTestCmds(), "export", packDir, "--scope", "local", "--id", "team/shared", "--version", "1.0.0", "--root", source)
+	if !strings.Contains(out, "Exported 2 behaviors") {
+		t.Fatalf("unexpected export output %q", out)
+	}
+
+	mustRunTestCmd(t, exportTestCmds(), "init", "--root", target)
+	out = mustRunTestCmd(t, exportTestCmds(), "import", packDir, "--dry-run", "--root", target)
+	if !strings.Contains(out, "Would import 2 behaviors") {
+		t.Errorf("unexpected dry-run output:\n%s", out)
+	}
+
+	out = mustRunTestCmd(t, exportTestCmds(), "import", packDir, "--root", target, "--json")
+	var result struct {
+		Added    []string `json:"added"`
+		Existing []string `json:"existing"`
+		DryRun   bool     `json:"dry_run"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if len(result.Added) != 2 || result.DryRun {
+		t.Errorf("import result = %+v, want 2 added", result)
+	}
+
+	out = mustRunTestCmd(t, exportTestCmds(), "import", packDir, "--root", target)
+	if !strings.Contains(out, "Imported 0 behaviors") || !strings.Contains(out, "Already present (2)") {
+		t.Errorf("unexpected re-import output:\n%s", out)
+	}
+}
+
+func TestImportFromADR(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	root := filepath.Join(tmpDir, "repo")
+	adrDir := filepath.Join(root, "docs", "adr")
+	if err := os.MkdirAll(adrDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	adrs := map[string]string{
+		"0001-no-shared-databases.md": "# 1. No shared databases\n\n## Status\n\nAccepted\n\n## Decision\n\nServices must not read another service's database.\n",
+		"0002-use-graphql.md":         "# 2. Use GraphQL\n\n## Status\n\nProposed\n\n## Decision\n\nExpose a GraphQL gateway.\n",
+	}
+	for name, content := range adrs {
+		if err := os.WriteFile(filepath.Join(adrDir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mustRunTestCmd(t, exportTestCmds(), "init", "--root", root)
+	out := mustRunTestCmd(t, exportTestCmds(), "import", "--from", "adr", adrDir, "--root", root)
+	if !strings.Contains(out, "Imported 1 behaviors") || !strings.Contains(out, "adr-0001-no-shared-databases") ||
+		!strings.Contains(out, "0002-use-graphql.md: status proposed") {
+		t.Errorf("unexpected import output:\n%s", out)
+	}
+
+	out = mustRunTestCmd(t, exportTestCmds(), "import", "--from", "adr", adrDir, "--root", root, "--json")
+	var result struct {
+		Added    []string `json:"added"`
+		Existing []string `json:"existing"`
+		Skipped  []struct {
+			File   string `json:"file"`
+			Reason string `json:"reason"`
+		} `json:"skipped"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if len(result.Added) != 0 || len(result.Existing) != 1 || len(result.Skipped) != 1 {
+		t.Errorf("re-import result = %+v, want the ADR already present and one skipped", result)
+	}
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newImportCmd())
+	rootCmd.SetArgs([]string{"import", "--from", "eslint", adrDir, "--root", root})
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "unknown source") {
+		t.Errorf("import --from eslint error = %v, want unknown source", err)
+	}
+}
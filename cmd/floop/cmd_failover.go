@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/publish"
+	"github.com/nvandessel/floop/internal/replica"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newFailoverCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "failover",
+		Short: "Promote a store's warm standby replica",
+		Long: `Replace a store with its replica at the replication target.
+
+With replication.target set, every store sync ships the files that changed to
+the target. failover verifies the replica against its manifest, moves the
+store's database and JSONL to .floop/failover-<time>/, writes the replica in
+their place, and reopens the store to rebuild its database.
+
+Examples:
+  floop failover --dry-run
+  floop failover
+  floop failover --from /mnt/nas/floop --local`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			from, _ := cmd.Flags().GetString("from")
+			local, _ := cmd.Flags().GetBool("local")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			if from == "" {
+				if cfg, err := config.Load(); err == nil {
+					from = cfg.Replication.Target
+				}
+			}
+			if from == "" {
+				return fmt.Errorf("no replica to promote: pass --from or set replication.target")
+			}
+			target, err := replicaTarget(from)
+			if err != nil {
+				return err
+			}
+
+			globalDir, err := store.GlobalFloopPath()
+			if err != nil {
+				return err
+			}
+			floopDir := globalDir
+			if local {
+				absRoot, err := filepath.Abs(root)
+				if err != nil {
+					return fmt.Errorf("failed to resolve root: %w", err)
+				}
+				root = absRoot
+				floopDir = store.LocalFloopPath(root)
+			}
+			name := replica.StoreName(floopDir, globalDir)
+
+			ctx := context.Background()
+			promotion, err := replica.Plan(ctx, target, name)
+			if err != nil {
+				return err
+			}
+			m := promotion.Manifest
+
+			if dryRun {
+				if jsonOut {
+					return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+						"store":      name,
+						"target":     target.String(),
+						"dry_run":    true,
+						"sequence":   m.Sequence,
+						"source":     m.Source,
+						"host":       m.Host,
+						"updated_at": m.UpdatedAt,
+						"files":      promotion.Files(),
+						"partitions": len(promotion.Partitions()),
+					})
+				}
+				fmt.Printf("Replica of %s store at %s is ready to promote\n", name, target.String())
+				printReplicaManifest(promotion)
+				return nil
+			}
+
+			saved, err := promotion.Apply(floopDir, time.Now())
+			if err != nil {
+				if saved != "" {
+					return fmt.Errorf("failover failed: %w (replaced files are in %s)", err, saved)
+				}
+				return fmt.Errorf("failover failed: %w", err)
+			}
+
+			// Reopening rebuilds the database from the promoted JSONL
+			var graphStore store.GraphStore
+			if local {
+				graphStore, err = store.NewSQLiteGraphStore(root)
+			} else {
+				graphStore, err = store.NewGlobalGraphStore(filepath.Dir(globalDir))
+			}
+			if err != nil {
+				return fmt.Errorf("failed to open promoted store (replaced files are in %s): %w", saved, err)
+			}
+			behaviors, err := graphStore.QueryNodes(ctx, map[string]interface{}{"kind": "behavior"})
+			graphStore.Close()
+			if err != nil {
+				return fmt.Errorf("failed to query promoted store: %w", err)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"store":      name,
+					"target":     target.String(),
+					"sequence":   m.Sequence,
+					"updated_at": m.UpdatedAt,
+					"behaviors":  len(behaviors),
+					"saved_dir":  saved,
+				})
+			}
+			fmt.Printf("Promoted replica of %s store: %d behaviors\n", name, len(behaviors))
+			printReplicaManifest(promotion)
+			fmt.Printf("  Replaced files: %s\n", saved)
+			return nil
+		},
+	}
+
+	cmd.Flags().String("from", "", "Replica target: directory or s3://bucket/prefix (default: replication.target config)")
+	cmd.Flags().Bool("local", false, "Promote the project's local store instead of the global store")
+	cmd.Flags().Bool("dry-run", false, "Verify the replica and show what would be promoted")
+
+	return cmd
+}
+
+// printReplicaManifest prints where and when a replica was shipped from.
+func printReplicaManifest(p *replica.Promotion) {
+	m := p.Manifest
+	source := m.Source
+	if m.Host != "" {
+		source = m.Host + ":" + source
+	}
+	fmt.Printf("  Shipped: %s (sequence %d) from %s\n", m.UpdatedAt.Local().Format(time.RFC3339), m.Sequence, source)
+	fmt.Printf("  Files: %d", len(p.Files()))
+	if parts := p.Partitions(); len(parts) > 0 {
+		fmt.Printf(", %d partitions", len(parts))
+	}
+	fmt.Println()
+}
+
+// replicaTarget parses a replication target.
+func replicaTarget(to string) (replica.Target, error) {
+	target, err := publish.ParseTarget(to)
+	if err != nil {
+		return nil, err
+	}
+	rt, ok := target.(replica.Target)
+	if !ok {
+		return nil, fmt.Errorf("replication target %s cannot be read back", target.String())
+	}
+	return rt, nil
+}
+
+// enableReplication ships every store sync to the configured replication
+// target. A failed shipment is reported but does not fail the sync.
+func enableReplication(cfg config.ReplicationConfig) error {
+	if cfg.Target == "" {
+		store.SetSyncReporter(nil)
+		return nil
+	}
+	target, err := replicaTarget(cfg.Target)
+	if err != nil {
+		return err
+	}
+	scope, err := replica.ParseScope(cfg.Stores)
+	if err != nil {
+		return err
+	}
+	globalDir, err := store.GlobalFloopPath()
+	if err != nil {
+		return err
+	}
+	r := replica.New(target, scope, globalDir)
+	store.SetSyncReporter(func(ctx context.Context, synced store.SyncedFiles) {
+		if _, err := r.Ship(ctx, synced); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: replication failed: %v\n", err)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestFailoverCmdPromotesLocalReplica(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	replicaDir := filepath.Join(tmpDir, "replica")
+
+	if err := enableReplication(config.ReplicationConfig{Target: replicaDir, Stores: "all"}); err != nil {
+		t.Fatalf("enableReplication() error = %v", err)
+	}
+	t.Cleanup(func() { store.SetSyncReporter(nil) })
+
+	ctx := context.Background()
+	s, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	for _, id := range []string{"b-1", "b-2"} {
+		if _, err := s.AddNode(ctx, store.Node{
+			ID:   id,
+			Kind: store.NodeKindBehavior,
+			Content: map[string]interface{}{
+				"name":    id,
+				"kind":    "directive",
+				"content": map[string]interface{}{"canonical": "Behavior " + id},
+			},
+		}); err != nil {
+			t.Fatalf("AddNode(%s) error = %v", id, err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	store.SetSyncReporter(nil)
+
+	// Lose the local store
+	nodesFile := filepath.Join(tmpDir, ".floop", "nodes.jsonl")
+	if err := os.WriteFile(nodesFile, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) map[string]interface{} {
+		t.Helper()
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newFailoverCmd())
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetArgs(append([]string{"failover", "--from", replicaDir, "--local", "--root", tmpDir, "--json"}, args...))
+		var result map[string]interface{}
+		out := captureStdout(t, func() {
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("failover %v failed: %v", args, err)
+			}
+		})
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("failed to parse output %q: %v", out, err)
+		}
+		return result
+	}
+
+	dry := run("--dry-run")
+	if dry["dry_run"] != true || !strings.HasPrefix(dry["store"].(string), "local/") {
+		t.Errorf("dry run = %v, want a dry run of the local store", dry)
+	}
+	if data, _ := os.ReadFile(nodesFile); len(data) != 0 {
+		t.Error("dry run changed the store")
+	}
+
+	result := run()
+	if result["behaviors"] != float64(2) {
+		t.Errorf("promoted store has %v behaviors, want 2", result["behaviors"])
+	}
+	saved, _ := result["saved_dir"].(string)
+	if _, err := os.Stat(filepath.Join(saved, "nodes.jsonl")); err != nil {
+		t.Errorf("replaced nodes.jsonl not kept in %s: %v", saved, err)
+	}
+}
+
+func TestFailoverCmdRequiresTarget(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newFailoverCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"failover", "--root", tmpDir})
+
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "replication.target") {
+		t.Errorf("expected missing target error, got %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/dedup"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newFailuresCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "failures",
+		Short: "Review failures reported by agents",
+		Long: `List failures agents detected in their own work (tests failing after a
+change, a broken build) and reported with floop_report_failure, together
+with what fixed them.
+
+Reported failures wait in a review queue. Learning one extracts a behavior
+from it like a correction, with provenance source type "failure".
+
+Examples:
+  floop failures                       # List failures awaiting review
+  floop failures --all                 # Include learned and dismissed failures
+  floop failures learn failure-123     # Extract a behavior from a failure
+  floop failures dismiss failure-123   # Discard a failure`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			all, _ := cmd.Flags().GetBool("all")
+
+			graphStore, err := openFailureStore(root)
+			if err != nil {
+				return err
+			}
+			defer graphStore.Close()
+
+			status := models.FailureStatusPending
+			if all {
+				status = ""
+			}
+			failures, err := learning.ListFailures(context.Background(), graphStore, status)
+			if err != nil {
+				return err
+			}
+
+			if jsonOut {
+				if failures == nil {
+					failures = []models.Failure{}
+				}
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"failures": failures,
+					"count":    len(failures),
+				})
+			}
+
+			if len(failures) == 0 {
+				fmt.Println("No failures awaiting review.")
+				return nil
+			}
+
+			for _, f := range failures {
+				fmt.Printf("%s  %s  %s  %s\n", f.ID, f.Kind, f.Status, f.Timestamp.Local().Format("2006-01-02 15:04"))
+				if f.Command != "" {
+					fmt.Printf("  Command: %s\n", truncatePreview(f.Command, 70))
+				}
+				fmt.Printf("  Error:   %s\n", truncatePreview(f.Error, 70))
+				fmt.Printf("  Fix:     %s\n", truncatePreview(f.Fix, 70))
+				if f.BehaviorID != "" {
+					fmt.Printf("  Behavior: %s\n", f.BehaviorID)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("all", false, "Include learned and dismissed failures")
+	cmd.AddCommand(newFailuresLearnCmd(), newFailuresDismissCmd())
+	return cmd
+}
+
+func newFailuresLearnCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "learn <failure-id>",
+		Short: "Extract a behavior from a reported failure",
+		Long: `Run a pending failure through the learning loop with auto-merge. What the
+agent did and the failure become what went wrong; the fix becomes the
+behavior. The quality gate is skipped since the failure was reviewed.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			graphStore, err := openFailureStore(root)
+			if err != nil {
+				return err
+			}
+			defer graphStore.Close()
+
+			failure, err := learning.GetFailure(ctx, graphStore, args[0])
+			if err != nil {
+				return err
+			}
+			if failure == nil {
+				return fmt.Errorf("no failure with ID %s", args[0])
+			}
+
+			cfg := learning.DefaultLearningLoopConfig()
+			cfg.AutoMerge = true
+			merger := dedup.NewBehaviorMerger(dedup.MergerConfig{})
+			cfg.Deduplicator = dedup.NewStoreDeduplicator(graphStore, merger, dedup.DeduplicatorConfig{
+				SimilarityThreshold: constants.DefaultAutoMergeThreshold,
+				AutoMerge:           true,
+				Similarity:          dedupSimilarity(nil, graphStore),
+			})
+			loop := learning.NewLearningLoop(graphStore, withStorageLimits(withExtractor(&cfg)))
+
+			result, err := learning.LearnFailure(ctx, graphStore, loop, failure)
+			if err != nil {
+				return err
+			}
+			if result.Quota != nil {
+				if jsonOut {
+					return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+						"status":     "limit_reached",
+						"failure_id": failure.ID,
+						"quota":      result.Quota,
+					})
+				}
+				printQuotaExceeded(result.Quota)
+				fmt.Println("Failure left pending; run 'floop failures learn' again after consolidating.")
+				return nil
+			}
+
+			if err := graphStore.Sync(ctx); err != nil {
+				return fmt.Errorf("failed to sync store: %w", err)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"status":   "learned",
+					"failure":  failure,
+					"behavior": result.CandidateBehavior,
+					"merged":   result.MergedIntoExisting,
+				})
+			}
+			if result.MergedIntoExisting {
+				fmt.Printf("Learned %s: merged into %s\n", failure.ID, failure.BehaviorID)
+			} else {
+				fmt.Printf("Learned %s: %s (%s)\n", failure.ID, result.CandidateBehavior.Name, failure.BehaviorID)
+			}
+			return nil
+		},
+	}
+}
+
+func newFailuresDismissCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "dismiss <failure-id>",
+		Short: "Discard a reported failure",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			ctx := context.Background()
+
+			graphStore, err := openFailureStore(root)
+			if err != nil {
+				return err
+			}
+			defer graphStore.Close()
+
+			failure, err := learning.GetFailure(ctx, graphStore, args[0])
+			if err != nil {
+				return err
+			}
+			if failure == nil {
+				return fmt.Errorf("no failure with ID %s", args[0])
+			}
+			if err := learning.DismissFailure(ctx, graphStore, failure); err != nil {
+				return err
+			}
+			if err := graphStore.Sync(ctx); err != nil {
+				return fmt.Errorf("failed to sync store: %w", err)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"status":     "dismissed",
+					"failure_id": failure.ID,
+				})
+			}
+			fmt.Printf("Dismissed %s.\n", failure.ID)
+			return nil
+		},
+	}
+}
+
+// openFailureStore opens the graph store holding the failure review queue.
+func openFailureStore(root string) (*store.MultiGraphStore, error) {
+	if _, err := os.Stat(filepath.Join(root, ".floop")); os.IsNotExist(err) {
+		return nil, fmt.Errorf(".floop not initialized. Run 'floop init' first")
+	}
+	graphStore, err := store.NewMultiGraphStore(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open graph store: %w", err)
+	}
+	return graphStore, nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// failuresTestCmds returns fresh commands for the tests in this file.
+func failuresTestCmds() []*cobra.Command {
+	return []*cobra.Command{newInitCmd(), newFailuresCmd()}
+}
+
+// recordTestFailures adds failures to the review queue under tmpDir.
+func recordTestFailures(t *testing.T, tmpDir string, failures ...*models.Failure) {
+	t.Helper()
+	s, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewMultiGraphStore() error = %v", err)
+	}
+	defer s.Close()
+	for _, f := range failures {
+		if _, _, err := learning.RecordFailure(context.Background(), s, f); err != nil {
+			t.Fatalf("RecordFailure() error = %v", err)
+		}
+	}
+	if err := s.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+}
+
+func loadTestFailure(t *testing.T, tmpDir, id string) *models.Failure {
+	t.Helper()
+	s, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewMultiGraphStore() error = %v", err)
+	}
+	defer s.Close()
+	f, err := learning.GetFailure(context.Background(), s, id)
+	if err != nil || f == nil {
+		t.Fatalf("GetFailure(%s) = %v, %v", id, f, err)
+	}
+	return f
+}
+
+func TestFailuresCmd_LearnAndDismiss(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	if _, err := runTestCmd(t, failuresTestCmds(), "init", "--root", tmpDir); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if _, err := runTestCmd(t, failuresTestCmds(), "failures", "--root", tmpDir); err != nil {
+		t.Fatalf("failures (empty) failed: %v", err)
+	}
+
+	build := learning.NewFailure(models.FailureKindBuild, "go build ./...", "renamed the config package",
+		"undefined: config.Load", "update every import after renaming a package", models.ContextSnapshot{FileLanguage: "go"})
+	lint := learning.NewFailure(models.FailureKindLint, "golangci-lint run", "", "line too long", "wrap long lines", models.ContextSnapshot{})
+	recordTestFailures(t, tmpDir, build, lint)
+
+	out, err := runTestCmd(t, failuresTestCmds(), "failures", "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("failures --json failed: %v", err)
+	}
+	var listed struct {
+		Count int `json:"count"`
+	}
+	if err := json.Unmarshal([]byte(out), &listed); err != nil || listed.Count != 2 {
+		t.Fatalf("failures --json = %s (%v), want 2 pending", out, err)
+	}
+
+	if _, err := runTestCmd(t, failuresTestCmds(), "failures", "learn", build.ID, "--root", tmpDir); err != nil {
+		t.Fatalf("failures learn failed: %v", err)
+	}
+	learned := loadTestFailure(t, tmpDir, build.ID)
+	if learned.Status != models.FailureStatusLearned || learned.BehaviorID == "" {
+		t.Fatalf("learned failure = %+v, want learned with a behavior", learned)
+	}
+	s, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewMultiGraphStore() error = %v", err)
+	}
+	node, err := s.GetNode(context.Background(), learned.BehaviorID)
+	s.Close()
+	if err != nil || node == nil {
+		t.Fatalf("GetNode(%s) = %v, %v", learned.BehaviorID, node, err)
+	}
+	if got := models.NodeToBehavior(*node).Provenance.SourceType; got != models.SourceTypeFailure {
+		t.Errorf("behavior source type = %s, want %s", got, models.SourceTypeFailure)
+	}
+
+	if _, err := runTestCmd(t, failuresTestCmds(), "failures", "learn", build.ID, "--root", tmpDir); err == nil {
+		t.Error("learning a failure twice should fail")
+	}
+
+	if _, err := runTestCmd(t, failuresTestCmds(), "failures", "dismiss", lint.ID, "--root", tmpDir); err != nil {
+		t.Fatalf("failures dismiss failed: %v", err)
+	}
+	if got := loadTestFailure(t, tmpDir, lint.ID).Status; got != models.FailureStatusDismissed {
+		t.Errorf("dismissed status = %s, want dismissed", got)
+	}
+
+	if _, err := runTestCmd(t, failuresTestCmds(), "failures", "--all", "--root", tmpDir); err != nil {
+		t.Fatalf("failures --all failed: %v", err)
+	}
+	if _, err := runTestCmd(t, failuresTestCmds(), "failures", "dismiss", "failure-missing", "--root", tmpDir); err == nil {
+		t.Error("dismissing an unknown failure should fail")
+	}
+}
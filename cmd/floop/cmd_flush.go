@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nvandessel/floop/internal/mcp"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// flushPollInterval is how often 'floop flush' checks for pending writes.
+const flushPollInterval = 50 * time.Millisecond
+
+func newFlushCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "flush",
+		Short: "Wait for running MCP servers to finish their background writes",
+		Long: `Wait until MCP servers on this machine have no background writes in
+flight. A floop_active call records activation hits, implicit
+confirmations, edge timestamps, and Hebbian weight updates in the
+background, so 'floop list', 'floop stats', or a test reading the store
+right after it may see stale numbers. Run 'floop flush' first to read
+your writes.
+
+Servers mark pending writes in the project's .floop directory, or in
+~/.floop when the project has none; both are checked. CLI commands
+write synchronously and never leave work behind.
+
+Examples:
+  floop flush
+  floop flush --timeout 30s && floop stats`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			out := cmd.OutOrStdout()
+
+			if timeout <= 0 {
+				return fmt.Errorf("--timeout must be positive")
+			}
+			dirs := []string{store.LocalFloopPath(root)}
+			if global, err := store.GlobalFloopPath(); err == nil && global != dirs[0] {
+				dirs = append(dirs, global)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			start := time.Now()
+			pending, err := mcp.WaitForPendingServers(ctx, flushPollInterval, dirs...)
+			if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+				return err
+			}
+			waited := time.Since(start)
+
+			if jsonOut {
+				if pending == nil {
+					pending = []mcp.PendingServer{}
+				}
+				if encErr := json.NewEncoder(out).Encode(map[string]interface{}{
+					"flushed":         len(pending) == 0,
+					"waited_seconds":  waited.Seconds(),
+					"pending_servers": pending,
+				}); encErr != nil {
+					return encErr
+				}
+			} else if len(pending) == 0 {
+				fmt.Fprintf(out, "No background writes pending (waited %v).\n", waited.Round(time.Millisecond))
+			} else {
+				for _, p := range pending {
+					fmt.Fprintf(out, "  mcp-server pid %d: writes pending since %s (%s)\n", p.PID, p.Since.Local().Format(time.RFC3339), p.Marker)
+				}
+				fmt.Fprintln(out, "If no such server is running, its marker file is stale and can be removed.")
+			}
+			if len(pending) > 0 {
+				return fmt.Errorf("background writes still pending after %v", timeout)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Duration("timeout", 10*time.Second, "How long to wait for pending writes")
+
+	return cmd
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/mcp"
+)
+
+func TestFlushCmdNothingPending(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	var out bytes.Buffer
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newFlushCmd())
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"flush", "--json", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+
+	var result struct {
+		Flushed bool `json:"flushed"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out.String(), err)
+	}
+	if !result.Flushed {
+		t.Errorf("flushed = false with nothing pending: %s", out.String())
+	}
+}
+
+func TestFlushCmdTimesOutOnPendingServer(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	pendingDir := filepath.Join(tmpDir, ".floop", mcp.PendingDir)
+	if err := os.MkdirAll(pendingDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(pendingDir, "4242.json"), []byte(`{"pid":4242,"since":"2026-10-14T09:00:00Z"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newFlushCmd())
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"flush", "--timeout", "100ms", "--root", tmpDir})
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "still pending") {
+		t.Fatalf("flush error = %v, want writes still pending", err)
+	}
+	if !strings.Contains(out.String(), "pid 4242") {
+		t.Errorf("output = %q, want the pending server", out.String())
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/edges"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newGeneralizeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generalize",
+		Short: "Suggest generalized parents for behaviors that share a pattern",
+		Long: `Find groups of behaviors that share a pattern ("use X not Y" across several
+libraries) and suggest a generalized parent for each group.
+
+Accepting a suggestion creates the parent behavior, with only the
+when-conditions every child shares, and links each child to it with a
+specializes edge. When a child and its parent both match a context, the
+resolver keeps the more specific child.
+
+Examples:
+  floop generalize                          # List suggestions for the local store
+  floop generalize --scope global           # List suggestions for the global store
+  floop generalize accept gen-1a2b3c4d      # Create the parent and specializes edges
+  floop generalize accept gen-1a2b3c4d --canonical "Prefer the maintained library"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			scope, _ := cmd.Flags().GetString("scope")
+			minChildren, _ := cmd.Flags().GetInt("min-children")
+
+			ctx := context.Background()
+			graphStore, err := openGeneralizeStore(root, scope)
+			if err != nil {
+				return err
+			}
+			defer graphStore.Close()
+
+			behaviors, suggestions, err := loadGeneralizations(ctx, graphStore, minChildren)
+			if err != nil {
+				return err
+			}
+
+			if jsonOut {
+				if suggestions == nil {
+					suggestions = []edges.GeneralizationSuggestion{}
+				}
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"scope":       scope,
+					"suggestions": suggestions,
+				})
+			}
+
+			if len(suggestions) == 0 {
+				fmt.Println("No generalization suggestions.")
+				return nil
+			}
+
+			names := make(map[string]string, len(behaviors))
+			for _, b := range behaviors {
+				names[b.ID] = b.Name
+			}
+
+			fmt.Printf("Generalization suggestions: %d\n", len(suggestions))
+			for _, s := range suggestions {
+				fmt.Printf("\n%s  score %.2f  pattern %q\n", s.ID, s.Score, s.Pattern)
+				fmt.Printf("  Parent: %s (%s)\n", s.Parent.Name, s.Parent.Kind)
+				if len(s.Parent.When) > 0 {
+					fmt.Printf("  When: %v\n", s.Parent.When)
+				}
+				fmt.Printf("  Children (%d):\n", len(s.Children))
+				for _, id := range s.Children {
+					fmt.Printf("    - %s (%s)\n", names[id], id)
+				}
+			}
+			fmt.Println("\nAccept with: floop generalize accept <id>")
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().String("scope", "local", "Store scope: local or global")
+	cmd.PersistentFlags().Int("min-children", constants.GeneralizeMinChildren, "Minimum behaviors sharing a pattern")
+
+	cmd.AddCommand(newGeneralizeAcceptCmd())
+	return cmd
+}
+
+func newGeneralizeAcceptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "accept <suggestion-id>",
+		Short: "Create a suggested parent and link its children",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			scope, _ := cmd.Flags().GetString("scope")
+			minChildren, _ := cmd.Flags().GetInt("min-children")
+			name, _ := cmd.Flags().GetString("name")
+			canonical, _ := cmd.Flags().GetString("canonical")
+
+			ctx := context.Background()
+			graphStore, err := openGeneralizeStore(root, scope)
+			if err != nil {
+				return err
+			}
+			defer graphStore.Close()
+
+			_, suggestions, err := loadGeneralizations(ctx, graphStore, minChildren)
+			if err != nil {
+				return err
+			}
+
+			var suggestion *edges.GeneralizationSuggestion
+			for i := range suggestions {
+				if suggestions[i].ID == args[0] {
+					suggestion = &suggestions[i]
+					break
+				}
+			}
+			if suggestion == nil {
+				return fmt.Errorf("no generalization suggestion with ID %s (run 'floop generalize' to list)", args[0])
+			}
+
+			if name != "" {
+				suggestion.Parent.Name = name
+			}
+			if canonical != "" {
+				suggestion.Parent.Content.Canonical = canonical
+			}
+
+			if err := edges.AcceptGeneralization(ctx, graphStore, *suggestion); err != nil {
+				return err
+			}
+
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"status":        "accepted",
+					"suggestion_id": suggestion.ID,
+					"parent":        suggestion.Parent,
+					"children":      suggestion.Children,
+				})
+			}
+			fmt.Printf("Created %s (%s) generalizing %d behaviors.\n",
+				suggestion.Parent.Name, suggestion.Parent.ID, len(suggestion.Children))
+			return nil
+		},
+	}
+
+	cmd.Flags().String("name", "", "Name for the parent behavior (default: derived from the pattern)")
+	cmd.Flags().String("canonical", "", "Content for the parent behavior (default: the shared pattern)")
+	return cmd
+}
+
+// openGeneralizeStore opens the single store that suggestions are computed
+// for, so a parent always lives beside its children.
+func openGeneralizeStore(root, scope string) (store.GraphStore, error) {
+	switch store.StoreScope(scope) {
+	case store.ScopeLocal:
+		if _, err := os.Stat(filepath.Join(root, ".floop")); err != nil {
+			return nil, fmt.Errorf(".floop not initialized. Run 'floop init' first")
+		}
+		graphStore, err := store.NewSQLiteGraphStore(root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open local store: %w", err)
+		}
+		return graphStore, nil
+	case store.ScopeGlobal:
+		globalPath, err := store.GlobalFloopPath()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get global path: %w", err)
+		}
+		graphStore, err := store.NewGlobalGraphStore(filepath.Dir(globalPath))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open global store: %w", err)
+		}
+		return graphStore, nil
+	default:
+		return nil, fmt.Errorf("invalid scope: %s (must be local or global)", scope)
+	}
+}
+
+// loadGeneralizations loads behaviors with their specializations and
+// computes suggestions.
+func loadGeneralizations(ctx context.Context, graphStore store.GraphStore, minChildren int) ([]models.Behavior, []edges.GeneralizationSuggestion, error) {
+	behaviors, err := edges.LoadBehaviorsFromStore(ctx, graphStore)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load behaviors: %w", err)
+	}
+	if err := edges.AttachRelationships(ctx, graphStore, behaviors); err != nil {
+		return nil, nil, err
+	}
+	return behaviors, edges.SuggestGeneralizations(behaviors, minChildren), nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestNewGeneralizeCmd(t *testing.T) {
+	cmd := newGeneralizeCmd()
+
+	if cmd.Use != "generalize" {
+		t.Errorf("Use = %q, want generalize", cmd.Use)
+	}
+	for _, flag := range []string{"scope", "min-children"} {
+		if cmd.PersistentFlags().Lookup(flag) == nil {
+			t.Errorf("missing --%s flag", flag)
+		}
+	}
+
+	found := false
+	for _, sub := range cmd.Commands() {
+		if sub.Name() == "accept" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("missing accept subcommand")
+	}
+}
+
+func TestGeneralizeAccept(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newInitCmd())
+	rootCmd.SetArgs([]string{"init", "--root", tmpDir})
+	rootCmd.SetOut(&bytes.Buffer{})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	ctx := context.Background()
+	graphStore, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	for _, b := range []models.Behavior{
+		{ID: "b-httpx", Name: "use-httpx", Kind: models.BehaviorKindPreference,
+			When:    map[string]interface{}{"language": "python", "task": "http"},
+			Content: models.BehaviorContent{Canonical: "use httpx not requests"}, Confidence: 0.8},
+		{ID: "b-pathlib", Name: "use-pathlib", Kind: models.BehaviorKindPreference,
+			When:    map[string]interface{}{"language": "python", "task": "files"},
+			Content: models.BehaviorContent{Canonical: "use pathlib not os.path"}, Confidence: 0.8},
+		{ID: "b-pytest", Name: "use-pytest", Kind: models.BehaviorKindPreference,
+			When:    map[string]interface{}{"language": "python", "task": "testing"},
+			Content: models.BehaviorContent{Canonical: "use pytest not unittest"}, Confidence: 0.8},
+	} {
+		if _, err := graphStore.AddNode(ctx, models.BehaviorToNode(&b)); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+	_, suggestions, err := loadGeneralizations(ctx, graphStore, constants.GeneralizeMinChildren)
+	graphStore.Close()
+	if err != nil {
+		t.Fatalf("loadGeneralizations() error = %v", err)
+	}
+	if len(suggestions) != 1 {
+		t.Fatalf("got %d suggestions, want 1", len(suggestions))
+	}
+	suggestion := suggestions[0]
+
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newGeneralizeCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"generalize", "accept", suggestion.ID, "--canonical", "Prefer the maintained library", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("generalize accept failed: %v", err)
+	}
+
+	behaviors, err := loadBehaviorsWithScope(tmpDir, constants.ScopeLocal)
+	if err != nil {
+		t.Fatalf("loadBehaviorsWithScope() error = %v", err)
+	}
+	var parent *models.Behavior
+	linked := 0
+	for i := range behaviors {
+		b := &behaviors[i]
+		if b.ID == suggestion.Parent.ID {
+			parent = b
+		}
+		if len(b.Specializes) == 1 && b.Specializes[0] == suggestion.Parent.ID {
+			linked++
+		}
+	}
+	if parent == nil {
+		t.Fatal("parent behavior not created")
+	}
+	if parent.Content.Canonical != "Prefer the maintained library" {
+		t.Errorf("parent canonical = %q", parent.Content.Canonical)
+	}
+	if linked != 3 {
+		t.Errorf("linked children = %d, want 3", linked)
+	}
+
+	// Unknown IDs are rejected
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newGeneralizeCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"generalize", "accept", "gen-missing", "--root", tmpDir})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error for unknown suggestion ID")
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -9,6 +10,8 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/pathutil"
 	"github.com/nvandessel/floop/internal/ranking"
 	"github.com/nvandessel/floop/internal/store"
 	"github.com/nvandessel/floop/internal/visualization"
@@ -19,13 +22,67 @@ func newGraphCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "graph",
 		Short: "Visualize the behavior graph",
-		Long:  `Output the behavior graph in DOT (Graphviz), JSON, or interactive HTML format.`,
+		Long: `Output the behavior graph in DOT (Graphviz), JSON, or interactive HTML format.
+
+Large graphs can be narrowed with filters: tags, kinds, a minimum
+confidence, the neighborhood of one behavior, or the top N behaviors by
+PageRank. --focus (an ID, name, or slug) with --depth extracts the subgraph
+around one behavior; --min-weight drops weaker edges, and --focus then
+follows only the edges that remain. The HTML page loads big graphs
+progressively, most central behaviors first.
+
+--output writes the graph to a file in any format; DOT and JSON otherwise
+go to stdout.
+
+--since-backup and --since highlight what changed: behaviors and edges are
+colored as new, modified, weight-increased, weight-decreased, or pruned
+relative to a backup (a file path, or "latest"), or as new or modified
+since a timestamp. Pruned items need a backup to be detected.
+
+Examples:
+  floop graph --format html --top 200
+  floop graph --format html --focus b-123 --depth 2
+  floop graph --format dot --focus use-uv --min-weight 0.5 -o uv.dot
+  floop graph --format json --kinds constraint --tags git
+  floop graph --format html --since-backup latest
+  floop graph --format json --since 2026-10-01T09:00:00Z`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			root, _ := cmd.Flags().GetString("root")
 			format, _ := cmd.Flags().GetString("format")
 			output, _ := cmd.Flags().GetString("output")
 			noOpen, _ := cmd.Flags().GetBool("no-open")
 			serve, _ := cmd.Flags().GetBool("serve")
+			tags, _ := cmd.Flags().GetStringSlice("tags")
+			kinds, _ := cmd.Flags().GetStringSlice("kinds")
+			minConfidence, _ := cmd.Flags().GetFloat64("min-confidence")
+			around, _ := cmd.Flags().GetString("around")
+			focus, _ := cmd.Flags().GetString("focus")
+			minWeight, _ := cmd.Flags().GetFloat64("min-weight")
+			depth, _ := cmd.Flags().GetInt("depth")
+			top, _ := cmd.Flags().GetInt("top")
+			sinceBackup, _ := cmd.Flags().GetString("since-backup")
+			since, _ := cmd.Flags().GetString("since")
+
+			// --around is the older name of --focus
+			if focus != "" && around != "" && focus != around {
+				return fmt.Errorf("use either --focus or --around, not both")
+			}
+			if focus == "" {
+				focus = around
+			}
+
+			filter := &visualization.Filter{
+				Tags:          tags,
+				Kinds:         kinds,
+				MinConfidence: minConfidence,
+				Around:        focus,
+				Depth:         depth,
+				TopN:          top,
+				MinWeight:     minWeight,
+			}
+			if err := filter.Validate(); err != nil {
+				return err
+			}
 
 			gs, err := openStoreForGraph(root)
 			if err != nil {
@@ -40,34 +97,71 @@ func newGraphCmd() *cobra.Command {
 
 			ctx := cmd.Context()
 
+			if filter.Around != "" {
+				node, err := store.ResolveNode(ctx, gs, filter.Around)
+				if err != nil {
+					return fmt.Errorf("look up %s: %w", filter.Around, err)
+				}
+				if node == nil {
+					return fmt.Errorf("behavior not found: %s", filter.Around)
+				}
+				filter.Around = node.ID
+			}
+
+			changes, err := graphChanges(ctx, gs, root, sinceBackup, since)
+			if err != nil {
+				return err
+			}
+			if changes != nil && visualization.Format(format) == visualization.FormatDOT {
+				return fmt.Errorf("--since-backup and --since require json or html format")
+			}
+
+			// PageRank sizes HTML nodes and ranks behaviors for --top
+			var pageRank map[string]float64
+			if visualization.Format(format) == visualization.FormatHTML || top > 0 {
+				pageRank, err = ranking.ComputePageRank(ctx, gs, ranking.DefaultPageRankConfig())
+				if err != nil {
+					return fmt.Errorf("compute PageRank: %w", err)
+				}
+			}
+
 			switch visualization.Format(format) {
 			case visualization.FormatDOT:
-				dot, err := visualization.RenderDOT(ctx, gs)
+				dot, err := visualization.RenderDOTFiltered(ctx, gs, filter, pageRank, edgeDecayRate())
 				if err != nil {
 					return fmt.Errorf("render DOT: %w", err)
 				}
-				fmt.Fprint(cmd.OutOrStdout(), dot)
+				return writeGraphOutput(cmd, []byte(dot), output)
 
 			case visualization.FormatJSON:
-				result, err := visualization.RenderJSON(ctx, gs)
+				var result map[string]interface{}
+				if changes != nil {
+					result, err = visualization.RenderEnrichedJSON(ctx, gs, &visualization.EnrichmentData{
+						PageRank:      pageRank,
+						Filter:        filter,
+						Changes:       changes,
+						EdgeDecayRate: edgeDecayRate(),
+					})
+				} else {
+					result, err = visualization.RenderJSONFiltered(ctx, gs, filter, pageRank, edgeDecayRate())
+				}
 				if err != nil {
 					return fmt.Errorf("render JSON: %w", err)
 				}
-				enc := json.NewEncoder(cmd.OutOrStdout())
+				var buf bytes.Buffer
+				enc := json.NewEncoder(&buf)
 				enc.SetIndent("", "  ")
 				if err := enc.Encode(result); err != nil {
 					return fmt.Errorf("encode JSON: %w", err)
 				}
+				return writeGraphOutput(cmd, buf.Bytes(), output)
 
 			case visualization.FormatHTML:
-				// Compute PageRank for node sizing
-				pageRank, err := ranking.ComputePageRank(ctx, gs, ranking.DefaultPageRankConfig())
-				if err != nil {
-					return fmt.Errorf("compute PageRank: %w", err)
-				}
-
 				enrichment := &visualization.EnrichmentData{
-					PageRank: pageRank,
+					PageRank:      pageRank,
+					Filter:        filter,
+					Changes:       changes,
+					EdgeDecayRate: edgeDecayRate(),
 				}
 
 				if serve {
@@ -89,13 +183,86 @@ func newGraphCmd() *cobra.Command {
 	}
 
 	cmd.Flags().String("format", "dot", "Output format: dot, json, or html")
-	cmd.Flags().StringP("output", "o", "", "Output file path (html format only)")
+	cmd.Flags().StringP("output", "o", "", "Output file path (default: stdout for dot and json, a temp file for html)")
 	cmd.Flags().Bool("no-open", false, "Don't open browser after generating HTML")
 	cmd.Flags().Bool("serve", false, "Start a local server with electric mode (spreading activation visualization)")
+	cmd.Flags().StringSlice("tags", nil, "Only include behaviors with any of these tags")
+	cmd.Flags().StringSlice("kinds", nil, "Only include these behavior kinds (e.g. constraint,directive)")
+	cmd.Flags().Float64("min-confidence", 0, "Only include behaviors with at least this confidence (0.0-1.0)")
+	cmd.Flags().String("focus", "", "Only include the subgraph around this behavior (ID, name, or slug)")
+	cmd.Flags().String("around", "", "Same as --focus")
+	cmd.Flags().Int("depth", 0, "Hops from --focus to include (default 1, max 5)")
+	cmd.Flags().Float64("min-weight", 0, "Only include edges with at least this weight (0.0-1.0)")
+	cmd.Flags().Int("top", 0, "Only include the N behaviors with the highest PageRank")
+	cmd.Flags().String("since-backup", "", "Highlight changes since this backup file (or 'latest')")
+	cmd.Flags().String("since", "", "Highlight behaviors and edges created or updated since this RFC3339 time")
 
 	return cmd
 }
 
+// graphChanges diffs the graph against the baseline chosen by --since-backup
+// or --since. Returns nil when neither is set.
+func graphChanges(ctx context.Context, gs store.GraphStore, root, sinceBackup, since string) (*backup.GraphDiff, error) {
+	switch {
+	case sinceBackup != "" && since != "":
+		return nil, fmt.Errorf("use either --since-backup or --since, not both")
+
+	case since != "":
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --since %q: use RFC3339, e.g. 2026-10-01T09:00:00Z", since)
+		}
+		changes, err := backup.DiffSince(ctx, gs, t)
+		if err != nil {
+			return nil, fmt.Errorf("diff since %s: %w", since, err)
+		}
+		return changes, nil
+
+	case sinceBackup != "":
+		path := sinceBackup
+		if path == "latest" {
+			dir, err := backup.DefaultBackupDir()
+			if err != nil {
+				return nil, err
+			}
+			if path, err = backup.LatestBackup(dir); err != nil {
+				return nil, err
+			}
+		}
+		allowedDirs, err := pathutil.DefaultAllowedBackupDirsWithProjectRoot(root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine allowed backup dirs: %w", err)
+		}
+		if err := pathutil.ValidatePath(path, allowedDirs); err != nil {
+			return nil, fmt.Errorf("backup path rejected: %w", err)
+		}
+		base, err := backup.ReadBackup(path)
+		if err != nil {
+			return nil, fmt.Errorf("read backup: %w", err)
+		}
+		changes, err := backup.Diff(ctx, gs, base)
+		if err != nil {
+			return nil, fmt.Errorf("diff against backup: %w", err)
+		}
+		return changes, nil
+	}
+	return nil, nil
+}
+
+// writeGraphOutput writes a rendered DOT or JSON graph to output, or to
+// stdout when output is empty.
+func writeGraphOutput(cmd *cobra.Command, data []byte, output string) error {
+	if output == "" {
+		_, err := cmd.OutOrStdout().Write(data)
+		return err
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return fmt.Errorf("write graph file: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Graph written to %s\n", output)
+	return nil
+}
+
 // writeStaticHTML renders the graph to a self-contained HTML file.
 func writeStaticHTML(cmd *cobra.Command, ctx context.Context, gs store.GraphStore, enrichment *visualization.EnrichmentData, output string, noOpen bool) error {
 	htmlBytes, err := visualization.RenderHTML(ctx, gs, enrichment)
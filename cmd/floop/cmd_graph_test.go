@@ -2,11 +2,20 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"io"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
 )
 
 func TestGraphServeImpliesHTMLFormat(t *testing.T) {
@@ -123,3 +132,228 @@ func TestGraphDefaultFormatIsDOT(t *testing.T) {
 		t.Errorf("expected DOT output containing 'digraph', got: %s", output)
 	}
 }
+
+func TestGraphCmdFilters(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newInitCmd())
+	rootCmd.SetArgs([]string{"init", "--root", tmpDir})
+	rootCmd.SetOut(&bytes.Buffer{})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	s, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewMultiGraphStore() error = %v", err)
+	}
+	for _, b := range []models.Behavior{
+		{ID: "b-git", Name: "git", Kind: models.BehaviorKindConstraint, Confidence: 0.9, Content: models.BehaviorContent{Canonical: "Never force push", Tags: []string{"git"}}},
+		{ID: "b-go", Name: "go", Kind: models.BehaviorKindDirective, Confidence: 0.9, Content: models.BehaviorContent{Canonical: "Wrap errors", Tags: []string{"go"}}},
+	} {
+		if _, err := s.AddNodeToScope(context.Background(), models.BehaviorToNode(&b), constants.ScopeLocal); err != nil {
+			t.Fatalf("AddNodeToScope() error = %v", err)
+		}
+	}
+	if err := s.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	s.Close()
+
+	rootCmd2 := newTestRootCmd()
+	rootCmd2.AddCommand(newGraphCmd())
+	var out bytes.Buffer
+	rootCmd2.SetOut(&out)
+	rootCmd2.SetArgs([]string{"graph", "--format", "json", "--tags", "git", "--root", tmpDir})
+	if err := rootCmd2.Execute(); err != nil {
+		t.Fatalf("graph --tags failed: %v", err)
+	}
+
+	var result struct {
+		NodeCount int `json:"node_count"`
+		Nodes     []struct {
+			ID string `json:"id"`
+		} `json:"nodes"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("graph output %q: %v", out.String(), err)
+	}
+	if result.NodeCount != 1 || result.Nodes[0].ID != "b-git" {
+		t.Errorf("graph --tags git = %+v, want only b-git", result)
+	}
+
+	rootCmd3 := newTestRootCmd()
+	rootCmd3.AddCommand(newGraphCmd())
+	rootCmd3.SetOut(&bytes.Buffer{})
+	rootCmd3.SetArgs([]string{"graph", "--depth", "2", "--root", tmpDir})
+	if err := rootCmd3.Execute(); err == nil || !strings.Contains(err.Error(), "depth requires") {
+		t.Errorf("graph --depth without --around error = %v", err)
+	}
+}
+
+func TestGraphCmdSinceBackup(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newInitCmd())
+	rootCmd.SetArgs([]string{"init", "--root", tmpDir})
+	rootCmd.SetOut(&bytes.Buffer{})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	ctx := context.Background()
+	addBehavior := func(b models.Behavior) {
+		t.Helper()
+		s, err := store.NewMultiGraphStore(tmpDir)
+		if err != nil {
+			t.Fatalf("NewMultiGraphStore() error = %v", err)
+		}
+		defer s.Close()
+		if _, err := s.AddNodeToScope(ctx, models.BehaviorToNode(&b), constants.ScopeLocal); err != nil {
+			t.Fatalf("AddNodeToScope() error = %v", err)
+		}
+		if err := s.Sync(ctx); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+	}
+	addBehavior(models.Behavior{ID: "b-old", Name: "old", Kind: models.BehaviorKindDirective, Confidence: 0.8, Content: models.BehaviorContent{Canonical: "Wrap errors"}})
+
+	backupPath := filepath.Join(tmpDir, ".floop", "backups", "floop-backup-20261001-090000.json.gz")
+	s, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewMultiGraphStore() error = %v", err)
+	}
+	if _, err := backup.Backup(ctx, s, backupPath); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	s.Close()
+
+	addBehavior(models.Behavior{ID: "b-new", Name: "new", Kind: models.BehaviorKindDirective, Confidence: 0.8, Content: models.BehaviorContent{Canonical: "Use table tests"}})
+
+	rootCmd2 := newTestRootCmd()
+	rootCmd2.AddCommand(newGraphCmd())
+	var out bytes.Buffer
+	rootCmd2.SetOut(&out)
+	rootCmd2.SetArgs([]string{"graph", "--format", "json", "--since-backup", backupPath, "--root", tmpDir})
+	if err := rootCmd2.Execute(); err != nil {
+		t.Fatalf("graph --since-backup failed: %v", err)
+	}
+
+	var result struct {
+		Nodes []struct {
+			ID     string `json:"id"`
+			Change string `json:"change"`
+		} `json:"nodes"`
+		Changes struct {
+			Nodes map[string]int `json:"nodes"`
+		} `json:"changes"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("graph output %q: %v", out.String(), err)
+	}
+	changes := make(map[string]string)
+	for _, n := range result.Nodes {
+		changes[n.ID] = n.Change
+	}
+	if changes["b-new"] != "new" || changes["b-old"] != "" {
+		t.Errorf("node changes = %v, want only b-new new", changes)
+	}
+	if result.Changes.Nodes["new"] != 1 {
+		t.Errorf("changes summary = %+v, want 1 new", result.Changes)
+	}
+
+	for _, args := range [][]string{
+		{"graph", "--since-backup", backupPath},
+		{"graph", "--format", "json", "--since", "yesterday"},
+		{"graph", "--format", "json", "--since", "2026-10-01T09:00:00Z", "--since-backup", backupPath},
+	} {
+		rootCmd3 := newTestRootCmd()
+		rootCmd3.AddCommand(newGraphCmd())
+		rootCmd3.SetOut(&bytes.Buffer{})
+		rootCmd3.SetArgs(append(args, "--root", tmpDir))
+		if err := rootCmd3.Execute(); err == nil {
+			t.Errorf("%v should fail", args)
+		}
+	}
+}
+
+func TestGraphCmdFocusMinWeight(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newInitCmd())
+	rootCmd.SetArgs([]string{"init", "--root", tmpDir})
+	rootCmd.SetOut(&bytes.Buffer{})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	ctx := context.Background()
+	s, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewMultiGraphStore() error = %v", err)
+	}
+	for _, b := range []models.Behavior{
+		{ID: "b-uv", Name: "learned/use-uv", Kind: models.BehaviorKindDirective, Confidence: 0.9, Content: models.BehaviorContent{Canonical: "Use uv"}},
+		{ID: "b-venv", Name: "learned/use-venv", Kind: models.BehaviorKindDirective, Confidence: 0.9, Content: models.BehaviorContent{Canonical: "Use a venv"}},
+		{ID: "b-pip", Name: "learned/no-pip", Kind: models.BehaviorKindConstraint, Confidence: 0.9, Content: models.BehaviorContent{Canonical: "Never pip install globally"}},
+	} {
+		if _, err := s.AddNodeToScope(ctx, models.BehaviorToNode(&b), constants.ScopeLocal); err != nil {
+			t.Fatalf("AddNodeToScope() error = %v", err)
+		}
+	}
+	now := time.Now()
+	for _, e := range []store.Edge{
+		{Source: "b-uv", Target: "b-venv", Kind: store.EdgeKindSimilarTo, Weight: 0.9, CreatedAt: now},
+		{Source: "b-uv", Target: "b-pip", Kind: store.EdgeKindSimilarTo, Weight: 0.2, CreatedAt: now},
+	} {
+		if err := s.AddEdge(ctx, e); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+	if err := s.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	s.Close()
+
+	outPath := filepath.Join(tmpDir, "uv.json")
+	rootCmd2 := newTestRootCmd()
+	rootCmd2.AddCommand(newGraphCmd())
+	var out bytes.Buffer
+	rootCmd2.SetOut(&out)
+	rootCmd2.SetArgs([]string{"graph", "--format", "json", "--focus", "use-uv", "--min-weight", "0.5", "-o", outPath, "--root", tmpDir})
+	if err := rootCmd2.Execute(); err != nil {
+		t.Fatalf("graph --focus failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Graph written to "+outPath) {
+		t.Errorf("graph output = %q, want the written file reported", out.String())
+	}
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read %s: %v", outPath, err)
+	}
+	var result struct {
+		NodeCount int `json:"node_count"`
+		EdgeCount int `json:"edge_count"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("graph file %q: %v", data, err)
+	}
+	if result.NodeCount != 2 || result.EdgeCount != 1 {
+		t.Errorf("graph --focus use-uv --min-weight 0.5 = %+v, want b-uv and b-venv joined by one edge", result)
+	}
+
+	rootCmd3 := newTestRootCmd()
+	rootCmd3.AddCommand(newGraphCmd())
+	rootCmd3.SetOut(&bytes.Buffer{})
+	rootCmd3.SetArgs([]string{"graph", "--focus", "missing", "--root", tmpDir})
+	if err := rootCmd3.Execute(); err == nil || !strings.Contains(err.Error(), "behavior not found") {
+		t.Errorf("graph --focus missing error = %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/corrections"
+	"github.com/nvandessel/floop/internal/heatmap"
+	"github.com/nvandessel/floop/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func newHeatmapCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "heatmap",
+		Short: "Show guidance and correction density by repo area",
+		Long: `Show, per directory or file, how much guidance floop delivered there
+(behaviors active in 'floop activate', the hooks, and floop_active) against
+how often the agent was corrected there (the corrections log).
+
+Densities are each path's share of all guidance and all corrections in the
+report. Paths are listed by how far correction density exceeds guidance
+density, so areas where the agent keeps getting corrected without learned
+behaviors come first; those never given any guidance are marked as gaps.
+
+Examples:
+  floop heatmap                # Top two directory levels
+  floop heatmap --depth 0      # Individual files
+  floop heatmap --since 30d --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			depth, _ := cmd.Flags().GetInt("depth")
+			sinceFlag, _ := cmd.Flags().GetString("since")
+			limit, _ := cmd.Flags().GetInt("limit")
+
+			if depth < 0 {
+				return fmt.Errorf("--depth must be 0 or more")
+			}
+			opts := heatmap.Options{Depth: depth}
+			if sinceFlag != "" {
+				d, err := utils.ParseDuration(sinceFlag)
+				if err != nil {
+					return fmt.Errorf("invalid --since: %w", err)
+				}
+				opts.Since = time.Now().Add(-d)
+			}
+
+			floopDir := filepath.Join(root, ".floop")
+			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
+				return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+			}
+			activations, err := heatmap.LoadActivations(floopDir)
+			if err != nil {
+				return err
+			}
+			logged, err := corrections.Load(floopDir)
+			if err != nil {
+				return err
+			}
+
+			rows := heatmap.Build(root, activations, logged, opts)
+			if limit > 0 && len(rows) > limit {
+				rows = rows[:limit]
+			}
+
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"depth": depth,
+					"paths": rows,
+				})
+			}
+
+			if len(rows) == 0 {
+				fmt.Println("No activations or corrections with file context recorded.")
+				return nil
+			}
+
+			fmt.Printf("%-40s %6s %8s %6s %8s %8s\n", "Path", "Active", "Guidance", "Fixes", "Guide%", "Fix%")
+			fmt.Println(repeatChar('-', 81))
+			gaps := 0
+			for _, r := range rows {
+				mark := ""
+				if r.Gap {
+					mark = "  gap"
+					gaps++
+				}
+				fmt.Printf("%-40s %6d %8d %6d %7.0f%% %7.0f%%%s\n",
+					truncatePreview(r.Path, 40), r.Activations, r.Guidance, r.Corrections,
+					r.GuidanceDensity*100, r.CorrectionDensity*100, mark)
+			}
+			if gaps > 0 {
+				fmt.Printf("\n%d path(s) corrected with no guidance; consider 'floop learn' for them.\n", gaps)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Int("depth", 2, "Group paths by this many leading components (0 for individual files)")
+	cmd.Flags().String("since", "", "Only count activations and corrections within this duration (e.g. 7d, 72h)")
+	cmd.Flags().Int("limit", 0, "Show at most this many paths (0 for all)")
+	return cmd
+}
+
+// recordHeatmapActivation logs an activation for the heatmap. It is best
+// effort: activation must not fail because the log could not be written.
+func recordHeatmapActivation(root, file string, behaviors int) {
+	if file == "" {
+		return
+	}
+	_ = heatmap.RecordActivation(filepath.Join(root, ".floop"), root, file, behaviors, time.Now())
+}
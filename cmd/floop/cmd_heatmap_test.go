@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/heatmap"
+	"github.com/spf13/cobra"
+)
+
+// heatmapTestCmds returns fresh commands for the tests in this file.
+func heatmapTestCmds() []*cobra.Command {
+	return []*cobra.Command{newInitCmd(), newLearnCmd(), newActivateCmd(), newHeatmapCmd()}
+}
+
+func TestHeatmapCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	if _, err := runTestCmd(t, heatmapTestCmds(), "init", "--root", tmpDir); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if _, err := runTestCmd(t, heatmapTestCmds(), "heatmap", "--root", tmpDir); err != nil {
+		t.Fatalf("heatmap (empty) failed: %v", err)
+	}
+
+	if _, err := runTestCmd(t, heatmapTestCmds(), "learn", "--right", "document every exported flag", "--file", "docs/CLI_REFERENCE.md", "--root", tmpDir, "--json"); err != nil {
+		t.Fatalf("learn failed: %v", err)
+	}
+	for _, file := range []string{"internal/store/file.go", "internal/mcp/server.go"} {
+		if _, err := runTestCmd(t, heatmapTestCmds(), "activate", "--file", file, "--root", tmpDir, "--json"); err != nil {
+			t.Fatalf("activate failed: %v", err)
+		}
+	}
+
+	out, err := runTestCmd(t, heatmapTestCmds(), "heatmap", "--depth", "1", "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("heatmap --json failed: %v", err)
+	}
+	var report struct {
+		Depth int           `json:"depth"`
+		Paths []heatmap.Row `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("heatmap --json = %s: %v", out, err)
+	}
+	if len(report.Paths) != 2 {
+		t.Fatalf("paths = %+v, want docs and internal", report.Paths)
+	}
+	if p := report.Paths[0]; p.Path != "docs" || p.Corrections != 1 || !p.Gap {
+		t.Errorf("paths[0] = %+v, want docs corrected with no guidance", p)
+	}
+	if p := report.Paths[1]; p.Path != "internal" || p.Activations != 2 || p.Corrections != 0 {
+		t.Errorf("paths[1] = %+v, want internal with 2 activations", p)
+	}
+
+	if _, err := runTestCmd(t, heatmapTestCmds(), "heatmap", "--depth", "-1", "--root", tmpDir); err == nil {
+		t.Error("negative --depth should fail")
+	}
+	if _, err := runTestCmd(t, heatmapTestCmds(), "heatmap", "--since", "soon", "--root", tmpDir); err == nil {
+		t.Error("invalid --since should fail")
+	}
+}
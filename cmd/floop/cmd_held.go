@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/corrections"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+func newHeldCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "held",
+		Short: "List corrections held by the quality gate",
+		Long: `List corrections that scored below the quality threshold and were held
+instead of becoming behaviors, with reject statistics.
+
+Corrections are scored on length, actionable verbs, and specificity (plus an
+optional LLM rating). Thresholds are configured with quality.enabled,
+quality.min_score, and quality.use_llm.
+
+Examples:
+  floop held                     # List held corrections and reject stats
+  floop held release c-123       # Queue a held correction for 'floop reprocess'
+  floop held drop c-123          # Discard a held correction`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			held, err := learning.LoadHeldCorrections(filepath.Join(root, ".floop"))
+			if err != nil {
+				return err
+			}
+			stats := learning.SummarizeHeld(held)
+
+			if jsonOut {
+				if held == nil {
+					held = []learning.HeldCorrection{}
+				}
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"held":  held,
+					"stats": stats,
+				})
+			}
+
+			if len(held) == 0 {
+				fmt.Println("No held corrections.")
+				return nil
+			}
+
+			fmt.Printf("Held corrections: %d (average score %.2f)\n\n", stats.Total, stats.AverageScore)
+			fmt.Println("Rejects by reason:")
+			for _, reason := range stats.SortedReasons() {
+				fmt.Printf("  %-28s %d\n", reason, stats.ByReason[reason])
+			}
+			fmt.Println()
+
+			for _, h := range held {
+				fmt.Printf("%s  score %.2f < %.2f  %s\n",
+					h.Correction.ID, h.Quality.Score, h.MinScore, h.HeldAt.Local().Format("2006-01-02 15:04"))
+				fmt.Printf("  Right: %s\n", truncatePreview(h.Correction.CorrectedAction, 70))
+				if len(h.Quality.Reasons) > 0 {
+					fmt.Printf("  Reasons: %v\n", h.Quality.Reasons)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.AddCommand(newHeldReleaseCmd(), newHeldDropCmd())
+	return cmd
+}
+
+func newHeldReleaseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "release <correction-id>",
+		Short: "Queue a held correction for extraction",
+		Long: `Move a held correction into the corrections log as unprocessed, bypassing the
+quality gate. Run 'floop reprocess' to extract a behavior from it.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			floopDir := filepath.Join(root, ".floop")
+
+			held, err := learning.RemoveHeldCorrection(floopDir, args[0])
+			if err != nil {
+				return err
+			}
+			if held == nil {
+				return fmt.Errorf("no held correction with ID %s", args[0])
+			}
+
+			correction := held.Correction
+			correction.Processed = false
+			correction.ProcessedAt = nil
+
+			if err := corrections.Append(floopDir, correction); err != nil {
+				return fmt.Errorf("failed to record correction: %w", err)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"status":        "released",
+					"correction_id": correction.ID,
+				})
+			}
+			fmt.Printf("Released %s. Run 'floop reprocess' to extract it.\n", correction.ID)
+			return nil
+		},
+	}
+}
+
+func newHeldDropCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "drop <correction-id>",
+		Short: "Discard a held correction",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			held, err := learning.RemoveHeldCorrection(filepath.Join(root, ".floop"), args[0])
+			if err != nil {
+				return err
+			}
+			if held == nil {
+				return fmt.Errorf("no held correction with ID %s", args[0])
+			}
+
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"status":        "dropped",
+					"correction_id": held.Correction.ID,
+				})
+			}
+			fmt.Printf("Dropped %s.\n", held.Correction.ID)
+			return nil
+		},
+	}
+}
+
+// withQualityGate adds the configured correction quality gate to loopConfig,
+// allocating a default config if loopConfig is nil. It returns loopConfig
+// unchanged when the gate is disabled or config cannot be loaded.
+func withQualityGate(loopConfig *learning.LearningLoopConfig) *learning.LearningLoopConfig {
+	cfg, err := config.Load()
+	if err != nil || !cfg.Quality.Enabled {
+		return loopConfig
+	}
+
+	if loopConfig == nil {
+		defaults := learning.DefaultLearningLoopConfig()
+		loopConfig = &defaults
+	}
+
+	var client llm.Client
+	if cfg.Quality.UseLLM {
+		client = createLLMClient(cfg)
+	}
+	loopConfig.QualityScorer = learning.NewQualityScorer(cfg.Quality.UseLLM, client)
+	loopConfig.MinQualityScore = cfg.Quality.MinScore
+	return loopConfig
+}
+
+// holdResult writes a held learning result to the holding area in floopDir.
+func holdResult(floopDir string, result *learning.LearningResult, loopConfig *learning.LearningLoopConfig) error {
+	held := learning.HeldCorrection{
+		Correction: result.Correction,
+		HeldAt:     time.Now(),
+	}
+	if loopConfig != nil {
+		held.MinScore = loopConfig.MinQualityScore
+	}
+	if result.Quality != nil {
+		held.Quality = *result.Quality
+	}
+	return learning.HoldCorrection(floopDir, held)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/spf13/cobra"
+)
+
+// heldTestCmds returns fresh commands for the tests in this file.
+func heldTestCmds() []*cobra.Command {
+	return []*cobra.Command{newInitCmd(), newLearnCmd(), newHeldCmd()}
+}
+
+func TestHeldCmd_QualityGateHoldsAndReleases(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	t.Setenv("FLOOP_QUALITY_ENABLED", "true")
+
+	if _, err := runTestCmd(t, heldTestCmds(), "init", "--root", tmpDir); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	// Noise is held, a specific instruction is learned
+	if _, err := runTestCmd(t, heldTestCmds(), "learn", "--right", "no, stop", "--root", tmpDir, "--json"); err != nil {
+		t.Fatalf("learn (noise) failed: %v", err)
+	}
+	if _, err := runTestCmd(t, heldTestCmds(), "learn", "--right", "use pathlib.Path instead of os.path", "--root", tmpDir, "--json"); err != nil {
+		t.Fatalf("learn (useful) failed: %v", err)
+	}
+
+	floopDir := filepath.Join(tmpDir, ".floop")
+	held, err := learning.LoadHeldCorrections(floopDir)
+	if err != nil {
+		t.Fatalf("LoadHeldCorrections() error = %v", err)
+	}
+	if len(held) != 1 || held[0].Correction.CorrectedAction != "no, stop" {
+		t.Fatalf("held = %+v, want only the noisy correction", held)
+	}
+	if held[0].MinScore == 0 {
+		t.Error("held correction should record the threshold it failed")
+	}
+
+	for _, c := range loggedCorrections(t, tmpDir) {
+		if c.CorrectedAction == "no, stop" {
+			t.Error("held correction should not be logged")
+		}
+	}
+
+	if _, err := runTestCmd(t, heldTestCmds(), "held", "--root", tmpDir); err != nil {
+		t.Fatalf("held failed: %v", err)
+	}
+	if _, err := runTestCmd(t, heldTestCmds(), "held", "--json", "--root", tmpDir); err != nil {
+		t.Fatalf("held --json failed: %v", err)
+	}
+
+	id := held[0].Correction.ID
+	if _, err := runTestCmd(t, heldTestCmds(), "held", "release", id, "--root", tmpDir); err != nil {
+		t.Fatalf("held release failed: %v", err)
+	}
+	held, _ = learning.LoadHeldCorrections(floopDir)
+	if len(held) != 0 {
+		t.Errorf("held after release = %d, want 0", len(held))
+	}
+	released := false
+	for _, c := range loggedCorrections(t, tmpDir) {
+		released = released || (c.ID == id && !c.Processed)
+	}
+	if !released {
+		t.Error("released correction should be logged unprocessed")
+	}
+
+	if _, err := runTestCmd(t, heldTestCmds(), "held", "drop", id, "--root", tmpDir); err == nil {
+		t.Error("held drop of a released correction should fail")
+	}
+}
+
+func TestHeldCmd_Drop(t *testing.T) {
+	tmpDir := t.TempDir()
+	floopDir := filepath.Join(tmpDir, ".floop")
+	if err := os.MkdirAll(floopDir, 0700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	h := learning.HeldCorrection{}
+	h.Correction.ID = "c-1"
+	if err := learning.HoldCorrection(floopDir, h); err != nil {
+		t.Fatalf("HoldCorrection() error = %v", err)
+	}
+
+	if _, err := runTestCmd(t, heldTestCmds(), "held", "drop", "c-1", "--root", tmpDir); err != nil {
+		t.Fatalf("held drop failed: %v", err)
+	}
+	held, _ := learning.LoadHeldCorrections(floopDir)
+	if len(held) != 0 {
+		t.Errorf("held after drop = %d, want 0", len(held))
+	}
+}
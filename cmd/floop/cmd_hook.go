@@ -11,6 +11,7 @@ import (
 
 	"github.com/nvandessel/floop/internal/activation"
 	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/corrections"
 	"github.com/nvandessel/floop/internal/learning"
 	"github.com/nvandessel/floop/internal/llm"
 	"github.com/nvandessel/floop/internal/models"
@@ -55,7 +56,7 @@ shell scripts, eliminating bash/jq dependencies for Windows support.`,
 }
 
 // newHookSessionStartCmd creates the 'hook session-start' subcommand.
-// It emits the floop learn directive for session injection.
+// It emits the floop learn directive and expiry digest for session injection.
 func newHookSessionStartCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "session-start",
@@ -188,7 +189,7 @@ func hookLog(root, hookName, stage, outcome string, extra map[string]interface{}
 	defer f.Close()
 
 	entry := map[string]interface{}{
-		"timestamp": time.Now().Format(time.RFC3339),
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
 		"hook":      hookName,
 		"stage":     stage,
 		"outcome":   outcome,
@@ -300,23 +301,48 @@ func runDetectCorrection(cmd *cobra.Command, root, prompt string, client llm.Cli
 		Processed:       false,
 	}
 
-	loop := learning.NewLearningLoop(graphStore, nil)
-	_, processErr := loop.ProcessCorrection(ctx, correction)
+	loopConfig := withStorageLimits(withExtractor(withQualityGate(nil)))
+	loop := learning.NewLearningLoop(graphStore, loopConfig)
+	learnResult, processErr := loop.ProcessCorrection(ctx, correction)
 	if processErr != nil {
 		hookLog(root, "detect-correction", "process", "process_error", map[string]interface{}{"error": processErr.Error()})
 		return nil
 	}
 
-	// Mark processed and append to corrections log
+	if learnResult.Held {
+		if err := holdResult(filepath.Join(root, ".floop"), learnResult, loopConfig); err != nil {
+			hookLog(root, "detect-correction", "hold", "hold_error", map[string]interface{}{"error": err.Error()})
+			return nil
+		}
+		hookLog(root, "detect-correction", "hold", "correction_held", map[string]interface{}{
+			"correction_id": correction.ID,
+			"score":         learnResult.Quality.Score,
+			"reasons":       learnResult.Quality.Reasons,
+		})
+		return nil
+	}
+
+	if learnResult.Quota != nil {
+		if err := deferCorrection(filepath.Join(root, ".floop"), correction); err != nil {
+			hookLog(root, "detect-correction", "quota", "defer_error", map[string]interface{}{"error": err.Error()})
+			return nil
+		}
+		hookLog(root, "detect-correction", "quota", "storage_limit_reached", map[string]interface{}{
+			"correction_id": correction.ID,
+			"limit":         learnResult.Quota.Usage.Limit,
+			"scope":         learnResult.Quota.Usage.Scope,
+			"candidates":    len(learnResult.Quota.Candidates),
+		})
+		return nil
+	}
+
+	// Mark processed and record in corrections log
 	correction.Processed = true
 	processedAt := time.Now()
 	correction.ProcessedAt = &processedAt
 
-	correctionsPath := filepath.Join(root, ".floop", "corrections.jsonl")
-	f, err := os.OpenFile(correctionsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
-	if err == nil {
-		json.NewEncoder(f).Encode(correction)
-		f.Close()
+	if err := corrections.Append(filepath.Join(root, ".floop"), correction); err != nil {
+		hookLog(root, "detect-correction", "correction_log", "write_error", map[string]interface{}{"error": err.Error()})
 	}
 
 	hookLog(root, "detect-correction", "complete", "correction_captured", map[string]interface{}{"correction_id": correction.ID})
@@ -342,6 +368,8 @@ This applies to: explicit corrections, preferences, "don't do X", repeated feedb
 // Behavior injection is handled entirely by the dynamic-context PreToolUse hook,
 // which uses spreading activation to surface relevant behaviors as the agent works.
 // This keeps the upfront token cost near zero.
+// It also sweeps expired and retired behaviors and appends a digest of any
+// not yet reported.
 func runHookPrompt(cmd *cobra.Command, root string) error {
 	// Check initialization silently
 	if !floopDirExists(root) {
@@ -349,6 +377,12 @@ func runHookPrompt(cmd *cobra.Command, root string) error {
 	}
 
 	fmt.Fprint(cmd.OutOrStdout(), floopLearnDirective())
+	if digest := sweepExpiredForDigest(root); digest != "" {
+		fmt.Fprint(cmd.OutOrStdout(), "\n"+digest)
+	}
+	if digest := sweepRetiredForDigest(root); digest != "" {
+		fmt.Fprint(cmd.OutOrStdout(), "\n"+digest)
+	}
 	return nil
 }
 
@@ -363,7 +397,8 @@ func runHookActivate(cmd *cobra.Command, root, file, task string, tokenBudget in
 
 	// Build context
 	ctxBuilder := activation.NewContextBuilder().
-		WithRepoRoot(root)
+		WithRepoRoot(root).
+		WithContentSniffing(sniffContentEnabled())
 	if file != "" {
 		ctxBuilder.WithFile(file)
 	}
@@ -394,18 +429,22 @@ func runHookActivate(cmd *cobra.Command, root, file, task string, tokenBudget in
 
 	// Run spreading activation
 	ctx := context.Background()
-	pipeline := spreading.NewPipeline(graphStore, spreading.DefaultConfig())
+	pipeline := spreading.NewPipeline(graphStore, spreadingConfig()).WithStandingSeeds(standingSeeds())
 	results, err := pipeline.Run(ctx, actCtx)
 	if err != nil {
 		_ = session.SaveState(sessState, sessionDir)
 		return nil
 	}
+	recordHeatmapActivation(root, actCtx.FilePath, len(results))
 
 	if len(results) == 0 {
 		_ = session.SaveState(sessState, sessionDir)
 		return nil
 	}
 
+	// Apply temporal ranking and external scorer adjustments, if configured
+	results = rerankResults(ctx, graphStore, &actCtx, results)
+
 	// Filter through session state
 	filtered := sessState.FilterResults(results, activationToTier, estimateTokenCost)
 	if len(filtered) == 0 {
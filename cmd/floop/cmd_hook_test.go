@@ -892,14 +892,9 @@ func TestRunDetectCorrection_FullSuccess(t *testing.T) {
 		t.Errorf("expected 'Correction Captured' in output, got: %s", output)
 	}
 
-	// Verify corrections.jsonl was written
-	correctionsPath := filepath.Join(tmpDir, ".floop", "corrections.jsonl")
-	data, err := os.ReadFile(correctionsPath)
-	if err != nil {
-		t.Fatalf("expected corrections.jsonl to exist: %v", err)
-	}
-	if !strings.Contains(string(data), "use log.Printf") {
-		t.Errorf("expected correction content in corrections.jsonl, got: %s", data)
+	// Verify the correction was logged
+	if data := loggedCorrectionJSON(t, tmpDir); !strings.Contains(string(data), "use log.Printf") {
+		t.Errorf("expected correction content in the corrections log, got: %s", data)
 	}
 
 	// Verify hook-debug.log contains correction_captured
@@ -14,6 +14,7 @@ import (
 	"github.com/nvandessel/floop/internal/config"
 	"github.com/nvandessel/floop/internal/constants"
 	"github.com/nvandessel/floop/internal/hooks"
+	"github.com/nvandessel/floop/internal/pack"
 	"github.com/nvandessel/floop/internal/seed"
 	"github.com/nvandessel/floop/internal/setup"
 	"github.com/nvandessel/floop/internal/store"
@@ -41,6 +42,7 @@ Examples:
   floop init --global                 # Global install, all defaults
   floop init --project                # Project-level install, all defaults
   floop init --global --project       # Both scopes
+  floop init --pack go-backend        # Project install seeded with the Go starter pack
   floop init --global --hooks=all --token-budget 2000  # Explicit everything`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			globalFlag, _ := cmd.Flags().GetBool("global")
@@ -51,13 +53,14 @@ Examples:
 			root, _ := cmd.Flags().GetString("root")
 			embeddingsFlag, _ := cmd.Flags().GetBool("embeddings")
 			noEmbeddingsFlag, _ := cmd.Flags().GetBool("no-embeddings")
+			packSources, _ := cmd.Flags().GetStringSlice("pack")
 
 			// Determine if we're in interactive or non-interactive mode.
 			// Any meaningful flag makes it non-interactive.
 			interactive := !globalFlag && !projectFlag &&
 				!cmd.Flags().Changed("hooks") && !cmd.Flags().Changed("token-budget") &&
 				!cmd.Flags().Changed("root") && !cmd.Flags().Changed("embeddings") &&
-				!cmd.Flags().Changed("no-embeddings")
+				!cmd.Flags().Changed("no-embeddings") && !cmd.Flags().Changed("pack")
 
 			var doGlobal, doProject bool
 			var doEmbeddings bool
@@ -104,6 +107,16 @@ Examples:
 				result["project"] = projectResult
 			}
 
+			// Install starter or other packs into the project store, or the
+			// global one for a global-only init
+			if len(packSources) > 0 {
+				packResults, err := installInitPacks(root, doProject, packSources, jsonOut)
+				if err != nil {
+					return fmt.Errorf("pack install failed: %w", err)
+				}
+				result["packs"] = packResults
+			}
+
 			// Set up local embeddings if requested
 			if doEmbeddings {
 				embResult, err := setupEmbeddings(jsonOut)
@@ -140,6 +153,7 @@ Examples:
 	cmd.Flags().Int("token-budget", config.Default().TokenBudget.Default, "Token budget for behavior injection")
 	cmd.Flags().Bool("embeddings", false, "Download and enable local embeddings for semantic retrieval")
 	cmd.Flags().Bool("no-embeddings", false, "Skip local embeddings setup")
+	cmd.Flags().StringSlice("pack", nil, "Install a pack after init: a starter pack (go-backend, python, react), path, or URL (repeatable)")
 
 	return cmd
 }
@@ -188,7 +202,7 @@ func initScope(scope constants.Scope, projectRoot string, hooksMode string, toke
 	manifestPath := filepath.Join(floopDir, "manifest.yaml")
 	if _, err := os.Stat(manifestPath); os.IsNotExist(err) {
 		manifest := fmt.Sprintf("# Feedback Loop Manifest\nversion: \"1.0\"\ncreated: %s\n",
-			time.Now().Format(time.RFC3339))
+			time.Now().UTC().Format(time.RFC3339))
 		if err := os.WriteFile(manifestPath, []byte(manifest), 0600); err != nil {
 			return nil, fmt.Errorf("creating manifest.yaml: %w", err)
 		}
@@ -227,7 +241,7 @@ func initScope(scope constants.Scope, projectRoot string, hooksMode string, toke
 	// 3. Seed meta-behaviors (global only)
 	if scope == constants.ScopeGlobal {
 		homeDir, _ := os.UserHomeDir()
-		globalStore, err := store.NewSQLiteGraphStore(homeDir)
+		globalStore, err := store.NewGlobalGraphStore(homeDir)
 		if err != nil {
 			return nil, fmt.Errorf("opening global store for seeding: %w", err)
 		}
@@ -256,6 +270,55 @@ func initScope(scope constants.Scope, projectRoot string, hooksMode string, toke
 	return result, nil
 }
 
+// installInitPacks installs packs into the project store, or into the global
+// store when only the global scope was initialized.
+func installInitPacks(root string, project bool, sources []string, jsonOut bool) ([]map[string]interface{}, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.Default()
+	}
+
+	var graphStore store.GraphStore
+	if project {
+		graphStore, err = store.NewSQLiteGraphStore(root)
+	} else {
+		var homeDir string
+		if homeDir, err = os.UserHomeDir(); err == nil {
+			graphStore, err = store.NewGlobalGraphStore(homeDir)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening store: %w", err)
+	}
+	defer graphStore.Close()
+
+	var installed []map[string]interface{}
+	for _, source := range sources {
+		results, err := pack.InstallFromSource(context.Background(), graphStore, source, cfg, pack.InstallFromSourceOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+		for _, r := range results {
+			installed = append(installed, map[string]interface{}{
+				"source":  source,
+				"pack_id": r.PackID,
+				"version": r.Version,
+				"added":   len(r.Added),
+				"updated": len(r.Updated),
+				"skipped": len(r.Skipped),
+			})
+			if !jsonOut {
+				fmt.Printf("Installed pack %s v%s (%d behaviors added)\n", r.PackID, r.Version, len(r.Added))
+			}
+		}
+	}
+
+	if err := cfg.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to save config: %v\n", err)
+	}
+	return installed, nil
+}
+
 // runInteractiveInit prompts the user for init configuration.
 func runInteractiveInit() (doGlobal, doProject bool, hooksMode string, tokenBudget int, doEmbeddings bool, err error) {
 	reader := bufio.NewReader(os.Stdin)
@@ -3,10 +3,14 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
 )
 
 func TestNewInitCmdFlags(t *testing.T) {
@@ -15,7 +19,7 @@ func TestNewInitCmdFlags(t *testing.T) {
 		t.Errorf("Use = %q, want %q", cmd.Use, "init")
 	}
 
-	for _, flag := range []string{"global", "project", "hooks", "token-budget", "embeddings", "no-embeddings"} {
+	for _, flag := range []string{"global", "project", "hooks", "token-budget", "embeddings", "no-embeddings", "pack"} {
 		if cmd.Flags().Lookup(flag) == nil {
 			t.Errorf("missing --%s flag", flag)
 		}
@@ -137,3 +141,51 @@ func TestReadLineEmpty(t *testing.T) {
 		t.Errorf("readLine() = %q, want empty string", result)
 	}
 }
+
+func TestInitCmdStarterPack(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newInitCmd())
+	rootCmd.SetArgs([]string{"init", "--root", tmpDir, "--pack", "go-backend", "--json"})
+	rootCmd.SetOut(&bytes.Buffer{})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("init --pack failed: %v", err)
+	}
+
+	s, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("opening store: %v", err)
+	}
+	defer s.Close()
+
+	nodes, err := s.QueryNodes(context.Background(), map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		t.Fatalf("QueryNodes() error = %v", err)
+	}
+	if len(nodes) == 0 {
+		t.Fatal("init --pack go-backend installed no behaviors")
+	}
+	for _, n := range nodes {
+		b := models.NodeToBehavior(n)
+		if b.Provenance.SourceType != models.SourceTypePack || b.Provenance.Package != "floop/go-backend" {
+			t.Errorf("%s provenance = %+v, want source_type pack from floop/go-backend", n.ID, b.Provenance)
+		}
+	}
+}
+
+func TestInitCmdUnknownPack(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newInitCmd())
+	rootCmd.SetArgs([]string{"init", "--root", tmpDir, "--pack", "./no-such-pack.fpack", "--json"})
+	rootCmd.SetOut(&bytes.Buffer{})
+
+	if err := rootCmd.Execute(); err == nil {
+		t.Fatal("expected error for a missing pack")
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// gitMergeDriverName is the merge driver name registered in git config and
+// referenced from .gitattributes.
+const gitMergeDriverName = "floop"
+
+// floopGitAttributes are the .gitattributes lines routing the behavior
+// JSONL files through the floop merge driver.
+var floopGitAttributes = []string{
+	".floop/nodes.jsonl merge=" + gitMergeDriverName,
+	".floop/edges.jsonl merge=" + gitMergeDriverName,
+}
+
+func newIntegrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "integrate",
+		Short: "Set up floop integrations with other tools",
+	}
+	cmd.AddCommand(newIntegrateGitCmd())
+	return cmd
+}
+
+func newIntegrateGitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "git",
+		Short: "Register the floop merge driver for .floop JSONL files",
+		Long: `Make .floop/nodes.jsonl and .floop/edges.jsonl safe to share through
+normal git merges.
+
+This registers 'floop git-merge-driver' as the "floop" merge driver in the
+repository's git config, and adds .gitattributes entries routing the JSONL
+files through it. Commit .gitattributes; git config is not shared, so each
+clone runs 'floop integrate git' once.
+
+Examples:
+  floop integrate git`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			gitConfig := [][2]string{
+				{"merge." + gitMergeDriverName + ".name", "floop behavior JSONL merge"},
+				{"merge." + gitMergeDriverName + ".driver", "floop git-merge-driver %O %A %B %P"},
+			}
+			for _, kv := range gitConfig {
+				if out, err := exec.Command("git", "-C", root, "config", kv[0], kv[1]).CombinedOutput(); err != nil {
+					return fmt.Errorf("git config %s: %w: %s", kv[0], err, strings.TrimSpace(string(out)))
+				}
+			}
+
+			attrsPath := filepath.Join(root, ".gitattributes")
+			added, err := ensureLines(attrsPath, floopGitAttributes)
+			if err != nil {
+				return fmt.Errorf("failed to update .gitattributes: %w", err)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]interface{}{
+					"driver":         gitMergeDriverName,
+					"gitattributes":  attrsPath,
+					"attributes_set": added,
+				})
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Registered the %q merge driver in git config\n", gitMergeDriverName)
+			if len(added) > 0 {
+				fmt.Fprintf(out, "Added %d entries to %s; commit it to share the setup\n", len(added), attrsPath)
+			} else {
+				fmt.Fprintf(out, "%s already routes the JSONL files through the driver\n", attrsPath)
+			}
+			return nil
+		},
+	}
+}
+
+// ensureLines appends the lines missing from the file at path, creating it
+// if needed. It returns the lines added.
+func ensureLines(path string, lines []string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	existing := make(map[string]bool)
+	for _, l := range strings.Split(string(data), "\n") {
+		existing[strings.TrimSpace(l)] = true
+	}
+
+	var added []string
+	var buf strings.Builder
+	if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+		buf.WriteString("\n")
+	}
+	for _, l := range lines {
+		if !existing[l] {
+			added = append(added, l)
+			buf.WriteString(l + "\n")
+		}
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.WriteString(buf.String()); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return added, f.Close()
+}
+
+func newGitMergeDriverCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "git-merge-driver <base> <ours> <theirs> [path]",
+		Short: "Merge .floop JSONL files by node ID (run by git)",
+		Long: `Three-way merge of nodes.jsonl or edges.jsonl, run by git as a custom merge
+driver (see 'floop integrate git'). The merged result is written to <ours>.
+
+Nodes are matched by ID and edges by source, target, and kind, so changes to
+different behaviors never conflict. When both sides changed the same record,
+content hashes decide: if only usage data differs (stats, edge weights), the
+most recently updated copy wins. Otherwise the record is written between
+conflict markers and the command exits non-zero, leaving it for you to
+resolve.`,
+		Args: cobra.RangeArgs(3, 4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := args[1]
+			if len(args) == 4 {
+				path = args[3]
+			}
+			var inputs [3][]byte
+			for i := range inputs {
+				data, err := os.ReadFile(args[i])
+				if err != nil {
+					return fmt.Errorf("failed to read %s: %w", args[i], err)
+				}
+				inputs[i] = data
+			}
+
+			merged, conflicts, err := store.MergeJSONL(inputs[0], inputs[1], inputs[2])
+			if err != nil {
+				return fmt.Errorf("failed to merge %s: %w", path, err)
+			}
+			if err := os.WriteFile(args[1], merged, 0o644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", args[1], err)
+			}
+			if len(conflicts) > 0 {
+				for _, k := range conflicts {
+					fmt.Fprintf(cmd.ErrOrStderr(), "CONFLICT (%s): %s changed on both sides\n", path, k)
+				}
+				return fmt.Errorf("%d conflicting record(s) in %s", len(conflicts), path)
+			}
+			return nil
+		},
+	}
+}
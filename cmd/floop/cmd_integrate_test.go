@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIntegrateGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", root).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".gitattributes"), []byte("*.png binary"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newIntegrateCmd())
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetArgs([]string{"integrate", "git", "--root", root})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("integrate git failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, ".gitattributes"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "*.png binary\n.floop/nodes.jsonl merge=floop\n.floop/edges.jsonl merge=floop\n"
+	if string(data) != want {
+		t.Errorf(".gitattributes = %q, want %q", data, want)
+	}
+	out, err := exec.Command("git", "-C", root, "config", "merge.floop.driver").Output()
+	if err != nil || strings.TrimSpace(string(out)) != "floop git-merge-driver %O %A %B %P" {
+		t.Errorf("merge.floop.driver = %q, %v", out, err)
+	}
+}
+
+func TestGitMergeDriver(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	b1 := `{"id":"b-1","kind":"behavior","content":{"name":"wrap-errors"},"metadata":{}}` + "\n"
+	b2 := `{"id":"b-2","kind":"behavior","content":{"name":"no-panic"},"metadata":{}}` + "\n"
+	b3 := `{"id":"b-3","kind":"behavior","content":{"name":"use-pathlib"},"metadata":{}}` + "\n"
+	base := write("base", b1)
+	ours := write("ours", b1+b2)
+	theirs := write("theirs", b1+b3)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newGitMergeDriverCmd())
+	rootCmd.SetArgs([]string{"git-merge-driver", base, ours, theirs, ".floop/nodes.jsonl"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("git-merge-driver failed: %v", err)
+	}
+	data, err := os.ReadFile(ours)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != b1+b2+b3 {
+		t.Errorf("merged = %q", data)
+	}
+
+	// Both sides renaming b-1 is a conflict
+	write("ours", strings.Replace(b1, "wrap-errors", "wrap-all-errors", 1))
+	write("theirs", strings.Replace(b1, "wrap-errors", "wrap-errors-with-context", 1))
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newGitMergeDriverCmd())
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"git-merge-driver", base, ours, theirs})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected a conflict error")
+	}
+	if data, _ := os.ReadFile(ours); !strings.Contains(string(data), "<<<<<<< ours") {
+		t.Errorf("conflicting merge missing markers: %q", data)
+	}
+}
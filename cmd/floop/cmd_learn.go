@@ -8,10 +8,14 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/nvandessel/floop/internal/config"
 	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/corrections"
 	"github.com/nvandessel/floop/internal/dedup"
+	"github.com/nvandessel/floop/internal/expiry"
 	"github.com/nvandessel/floop/internal/learning"
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/retirement"
 	"github.com/nvandessel/floop/internal/sanitize"
 	"github.com/nvandessel/floop/internal/store"
 	"github.com/nvandessel/floop/internal/tagging"
@@ -31,9 +35,28 @@ whether the behavior can be auto-accepted or requires human review.
 The --wrong flag is optional. When omitted, the behavior is created from
 the --right content alone (the "wrong" action is stored as provenance only).
 
+Use --when to add temporal conditions, evaluated against the activation
+time, so a behavior switches itself on and off: weekday=friday,
+date=2026-05-15..2026-06-01, after=2026-06-01, or before=2026-06-01.
+tags=testing ties a behavior to a declared tag context instead: it
+activates for 'floop active --tags testing' or --tags testing/unit.
+
+Use --expires to time-box a behavior that only applies for a while
+("during the v2 migration, always..."). It stops activating after the
+expiry and is then deprecated by 'floop expire'.
+
+Use --from-file to import a batch of corrections, one JSON object per
+line with the fields "wrong", "right", "file", "task", "language", "tags",
+"when", and "expires_at". Every line is validated before any is learned;
+repeats within the batch are learned once, and each line's outcome is
+reported. --file, --task, and --language apply to lines that omit them.
+
 Examples:
   floop learn --right "use pathlib.Path instead"
-  floop learn --wrong "used os.path" --right "use pathlib.Path instead"`,
+  floop learn --wrong "used os.path" --right "use pathlib.Path instead"
+  floop learn --right "run migrations with --v2" --expires 2026-12-31
+  floop learn --right "freeze: no dependency bumps" --when before=2026-06-01
+  floop learn --from-file transcript.jsonl`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			wrong, _ := cmd.Flags().GetString("wrong")
 			right, _ := cmd.Flags().GetString("right")
@@ -41,6 +64,14 @@ Examples:
 			task, _ := cmd.Flags().GetString("task")
 			language, _ := cmd.Flags().GetString("language")
 			root, _ := cmd.Flags().GetString("root")
+
+			if fromFile, _ := cmd.Flags().GetString("from-file"); fromFile != "" {
+				if cmd.Flags().Changed("wrong") || cmd.Flags().Changed("right") {
+					return fmt.Errorf("--from-file cannot be combined with --wrong or --right")
+				}
+				return runLearnBatch(cmd, root, fromFile)
+			}
+
 			// Validate required parameters
 			if right == "" {
 				return fmt.Errorf("--right is required and cannot be empty")
@@ -69,8 +100,35 @@ Examples:
 				return fmt.Errorf("--tags accepts at most %d tags, got %d", tagging.MaxExtraTags, len(tags))
 			}
 
+			// Read extra when-conditions
+			whenFlags, _ := cmd.Flags().GetStringArray("when")
+			var extraWhen map[string]interface{}
+			for _, w := range whenFlags {
+				key, value, err := models.ParseCondition(w)
+				if err != nil {
+					return fmt.Errorf("--when: %w", err)
+				}
+				if extraWhen == nil {
+					extraWhen = make(map[string]interface{})
+				}
+				extraWhen[key] = value
+			}
+
 			// Build context snapshot
 			now := time.Now()
+
+			var expiresAt *time.Time
+			if expires, _ := cmd.Flags().GetString("expires"); expires != "" {
+				t, err := expiry.Parse(expires, now)
+				if err != nil {
+					return fmt.Errorf("--expires: %w", err)
+				}
+				if !t.After(now) {
+					return fmt.Errorf("--expires must be in the future")
+				}
+				expiresAt = &t
+			}
+
 			ctxSnapshot := models.ContextSnapshot{
 				Timestamp: now,
 				FilePath:  file,
@@ -92,6 +150,8 @@ Examples:
 				AgentAction:     wrong,
 				CorrectedAction: right,
 				ExtraTags:       tags,
+				ExtraWhen:       extraWhen,
+				ExpiresAt:       expiresAt,
 				Processed:       false,
 			}
 
@@ -108,33 +168,10 @@ Examples:
 			}
 			defer graphStore.Close()
 
-			// Process through learning loop with auto-merge support
-			autoMerge, _ := cmd.Flags().GetBool("auto-merge")
-			var loopConfig *learning.LearningLoopConfig
-			if autoMerge {
-				cfg := learning.DefaultLearningLoopConfig()
-				cfg.AutoMerge = true
-				merger := dedup.NewBehaviorMerger(dedup.MergerConfig{})
-				cfg.Deduplicator = dedup.NewStoreDeduplicator(graphStore, merger, dedup.DeduplicatorConfig{
-					SimilarityThreshold: constants.DefaultAutoMergeThreshold,
-					AutoMerge:           true,
-				})
-				loopConfig = &cfg
-			}
-
-			// Apply --scope override if explicitly set
-			if cmd.Flags().Changed("scope") {
-				scopeVal, _ := cmd.Flags().GetString("scope")
-				s := constants.Scope(scopeVal)
-				if s != constants.ScopeLocal && s != constants.ScopeGlobal {
-					return fmt.Errorf("--scope must be 'local' or 'global'")
-				}
-				if loopConfig == nil {
-					loopConfig = &learning.LearningLoopConfig{}
-				}
-				loopConfig.ScopeOverride = &s
+			loopConfig, err := learnLoopConfig(cmd, graphStore)
+			if err != nil {
+				return err
 			}
-
 			loop := learning.NewLearningLoop(graphStore, loopConfig)
 			ctx := context.Background()
 
@@ -143,21 +180,61 @@ Examples:
 				return fmt.Errorf("failed to process correction: %w", err)
 			}
 
+			// Low-quality corrections go to the holding area, not the corrections log
+			if result.Held {
+				if err := holdResult(floopDir, result, loopConfig); err != nil {
+					return err
+				}
+				jsonOut, _ := cmd.Flags().GetBool("json")
+				if jsonOut {
+					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+						"status":     "held",
+						"correction": correction,
+						"quality":    result.Quality,
+					})
+				} else {
+					fmt.Printf("Correction held (quality %.2f below %.2f): %s\n",
+						result.Quality.Score, loopConfig.MinQualityScore, correction.CorrectedAction)
+					if len(result.Quality.Reasons) > 0 {
+						fmt.Printf("  Reasons: %v\n", result.Quality.Reasons)
+					}
+					fmt.Printf("  Run 'floop held release %s' to learn it anyway.\n", correction.ID)
+				}
+				return nil
+			}
+
+			// At a storage limit, keep the correction for 'floop reprocess'
+			// and propose consolidation instead of adding a behavior
+			if result.Quota != nil {
+				if err := deferCorrection(floopDir, correction); err != nil {
+					return err
+				}
+				jsonOut, _ := cmd.Flags().GetBool("json")
+				if jsonOut {
+					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+						"status":     "limit_reached",
+						"correction": correction,
+						"quota":      result.Quota,
+					})
+				} else {
+					printQuotaExceeded(result.Quota)
+					fmt.Println("Correction saved unprocessed; run 'floop reprocess' after consolidating.")
+				}
+				return nil
+			}
+
 			// Mark correction as processed
 			correction.Processed = true
 			processedAt := time.Now()
 			correction.ProcessedAt = &processedAt
 
-			// Append to corrections log (after processing so Processed flag is correct)
-			correctionsPath := filepath.Join(floopDir, "corrections.jsonl")
-			f, err := os.OpenFile(correctionsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
-			if err != nil {
-				return fmt.Errorf("failed to open corrections log: %w", err)
+			// Record in corrections log (after processing so Processed flag is correct)
+			if err := corrections.Append(floopDir, correction); err != nil {
+				return fmt.Errorf("failed to record correction: %w", err)
 			}
-			defer f.Close()
 
-			if err := json.NewEncoder(f).Encode(correction); err != nil {
-				return fmt.Errorf("failed to write correction: %w", err)
+			if err := retirement.RecordNotices(floopDir, result.Restored); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to record retirement notices: %v\n", err)
 			}
 
 			jsonOut, _ := cmd.Flags().GetBool("json")
@@ -170,6 +247,9 @@ Examples:
 					"auto_accepted":   result.AutoAccepted,
 					"requires_review": result.RequiresReview,
 					"review_reasons":  result.ReviewReasons,
+					"restored":        result.Restored,
+					"quarantined":     result.Injection != nil,
+					"injection":       result.Injection,
 				})
 			} else {
 				fmt.Println("Correction captured and processed:")
@@ -188,14 +268,28 @@ Examples:
 				fmt.Printf("  ID:   %s\n", result.CandidateBehavior.ID)
 				fmt.Printf("  Name: %s\n", result.CandidateBehavior.Name)
 				fmt.Printf("  Kind: %s\n", result.CandidateBehavior.Kind)
+				if result.CandidateBehavior.ExpiresAt != nil {
+					fmt.Printf("  Expires: %s\n", result.CandidateBehavior.ExpiresAt.Local().Format("2006-01-02 15:04"))
+				}
 				fmt.Println()
-				if result.AutoAccepted {
+				if result.Injection != nil {
+					fmt.Println("Status: Quarantined as a possible prompt injection")
+					for _, f := range result.Injection.Findings {
+						fmt.Printf("  - %s: %q\n", f.Rule, f.Excerpt)
+					}
+					fmt.Printf("  Review with 'floop quarantine'; release with 'floop restore %s'.\n", result.CandidateBehavior.ID)
+				} else if result.AutoAccepted {
 					fmt.Println("Status: Auto-accepted")
 				} else if result.RequiresReview {
 					fmt.Println("Status: Requires review")
 					for _, reason := range result.ReviewReasons {
 						fmt.Printf("  - %s\n", reason)
 					}
+					fmt.Println("  Review with 'floop review'.")
+				}
+				for _, n := range result.Restored {
+					fmt.Printf("Restored retired behavior: %s (%s)\n", n.Name, n.BehaviorID)
+					fmt.Printf("  %s\n", n.Note)
 				}
 			}
 
@@ -204,28 +298,86 @@ Examples:
 	}
 
 	cmd.Flags().String("wrong", "", "What the agent did (optional, stored as provenance only)")
-	cmd.Flags().String("right", "", "What should have been done (required)")
+	cmd.Flags().String("right", "", "What should have been done (required unless --from-file)")
 	cmd.Flags().String("file", "", "Current file path")
 	cmd.Flags().String("task", "", "Current task type")
 	cmd.Flags().String("language", "", "Programming language (e.g. 'go', 'python'). Overrides file extension inference")
 	cmd.Flags().String("scope", "", "Override auto-classification: local (project) or global (user)")
 	cmd.Flags().Bool("auto-merge", true, "Automatically merge similar behaviors (matches MCP behavior)")
 	cmd.Flags().StringSlice("tags", nil, "Additional tags to apply, merged with inferred tags (max 5)")
-	cmd.MarkFlagRequired("right")
+	cmd.Flags().StringArray("when", nil, "Extra condition key=value: weekday, date (YYYY-MM-DD or from..until), after, before, or any key with a JSON operator value such as language={\"not\":\"go\"} (repeatable)")
+	cmd.Flags().String("expires", "", "Expire the behavior at a time (RFC3339), date (YYYY-MM-DD, end of day UTC), or after a duration (72h, 14d)")
+	cmd.Flags().String("from-file", "", "Learn a batch of corrections from a JSONL file of wrong/right pairs")
 
 	return cmd
 }
 
+// learnLoopConfig builds the learning loop configuration for 'floop learn'
+// from its --auto-merge and --scope flags, the quality gate, the configured
+// extractor, and storage limits.
+func learnLoopConfig(cmd *cobra.Command, graphStore store.GraphStore) (*learning.LearningLoopConfig, error) {
+	// Process through learning loop with auto-merge support
+	autoMerge, _ := cmd.Flags().GetBool("auto-merge")
+	var loopConfig *learning.LearningLoopConfig
+	if autoMerge {
+		cfg := learning.DefaultLearningLoopConfig()
+		cfg.AutoMerge = true
+		merger := dedup.NewBehaviorMerger(dedup.MergerConfig{})
+		cfg.Deduplicator = dedup.NewStoreDeduplicator(graphStore, merger, dedup.DeduplicatorConfig{
+			SimilarityThreshold: constants.DefaultAutoMergeThreshold,
+			AutoMerge:           true,
+			Similarity:          dedupSimilarity(nil, graphStore),
+		})
+		loopConfig = &cfg
+	}
+
+	// Apply --scope override if explicitly set
+	if cmd.Flags().Changed("scope") {
+		scopeVal, _ := cmd.Flags().GetString("scope")
+		s := constants.Scope(scopeVal)
+		if s != constants.ScopeLocal && s != constants.ScopeGlobal {
+			return nil, fmt.Errorf("--scope must be 'local' or 'global'")
+		}
+		if loopConfig == nil {
+			loopConfig = &learning.LearningLoopConfig{}
+		}
+		loopConfig.ScopeOverride = &s
+	}
+
+	return withStorageLimits(withExtractor(withQualityGate(loopConfig))), nil
+}
+
+// withExtractor sets loopConfig's extractor from learning.extractor,
+// allocating a default config if loopConfig is nil. It returns loopConfig
+// unchanged for the rule-based extractor or when config cannot be loaded.
+func withExtractor(loopConfig *learning.LearningLoopConfig) *learning.LearningLoopConfig {
+	cfg, err := config.Load()
+	if err != nil || cfg.Learning.Extractor != learning.ExtractorLLM {
+		return loopConfig
+	}
+
+	if loopConfig == nil {
+		defaults := learning.DefaultLearningLoopConfig()
+		loopConfig = &defaults
+	}
+	loopConfig.Extractor = learning.NewExtractor(cfg.Learning.Extractor, createLLMClient(cfg))
+	return loopConfig
+}
+
 func newReprocessCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "reprocess",
 		Short: "Reprocess orphaned corrections into behaviors",
 		Long: `Reprocess corrections that were captured before behavior extraction was implemented.
 
-This command reads all corrections from corrections.jsonl, identifies those that
+This command reads all corrections from the corrections log, identifies those that
 haven't been processed (no corresponding behavior exists), and runs them through
 the learning loop to extract behaviors.
 
+Progress is shown on stderr, as JSONL events with --json. Ctrl-C stops after
+the current correction; the corrections already learned are marked processed,
+so running reprocess again continues with the rest.
+
 Example:
   floop reprocess           # Reprocess local corrections
   floop reprocess --dry-run # Preview what would be processed`,
@@ -238,45 +390,33 @@ Example:
 				return fmt.Errorf(".floop not initialized. Run 'floop init' first")
 			}
 
-			// Read corrections file
-			correctionsPath := filepath.Join(floopDir, "corrections.jsonl")
-			data, err := os.ReadFile(correctionsPath)
+			correctionLog, err := corrections.Open(floopDir)
 			if err != nil {
-				if os.IsNotExist(err) {
-					if jsonOut {
-						json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-							"status":    "no_corrections",
-							"processed": 0,
-							"skipped":   0,
-						})
-					} else {
-						fmt.Println("No corrections file found.")
-					}
-					return nil
-				}
-				return fmt.Errorf("failed to read corrections: %w", err)
+				return fmt.Errorf("failed to open corrections: %w", err)
 			}
+			defer correctionLog.Close()
 
-			// Parse all corrections
-			var corrections []models.Correction
-			lines := splitLines(string(data))
-			for _, line := range lines {
-				if line == "" {
-					continue
-				}
-				var c models.Correction
-				if err := json.Unmarshal([]byte(line), &c); err != nil {
-					continue
+			total, err := correctionLog.CountCorrections(cmd.Context(), corrections.Filter{})
+			if err != nil {
+				return err
+			}
+			if total == 0 {
+				if jsonOut {
+					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+						"status":    "no_corrections",
+						"processed": 0,
+						"skipped":   0,
+					})
+				} else {
+					fmt.Println("No corrections found.")
 				}
-				corrections = append(corrections, c)
+				return nil
 			}
 
-			// Filter to unprocessed corrections
-			var unprocessed []models.Correction
-			for _, c := range corrections {
-				if !c.Processed {
-					unprocessed = append(unprocessed, c)
-				}
+			notProcessed := false
+			unprocessed, _, err := correctionLog.ListCorrections(cmd.Context(), corrections.Filter{Processed: &notProcessed})
+			if err != nil {
+				return err
 			}
 
 			if len(unprocessed) == 0 {
@@ -284,10 +424,10 @@ Example:
 					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
 						"status":    "all_processed",
 						"processed": 0,
-						"skipped":   len(corrections),
+						"skipped":   total,
 					})
 				} else {
-					fmt.Printf("All %d corrections have already been processed.\n", len(corrections))
+					fmt.Printf("All %d corrections have already been processed.\n", total)
 				}
 				return nil
 			}
@@ -297,12 +437,12 @@ Example:
 					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
 						"status":            "dry_run",
 						"would_process":     len(unprocessed),
-						"already_processed": len(corrections) - len(unprocessed),
+						"already_processed": total - len(unprocessed),
 						"corrections":       unprocessed,
 					})
 				} else {
 					fmt.Printf("Dry run: would process %d unprocessed corrections (out of %d total)\n\n",
-						len(unprocessed), len(corrections))
+						len(unprocessed), total)
 					for i, c := range unprocessed {
 						fmt.Printf("%d. [%s]\n", i+1, c.Timestamp.Format(time.RFC3339))
 						fmt.Printf("   Wrong: %s\n", c.AgentAction)
@@ -330,6 +470,7 @@ Example:
 				cfg.Deduplicator = dedup.NewStoreDeduplicator(graphStore, merger, dedup.DeduplicatorConfig{
 					SimilarityThreshold: constants.DefaultAutoMergeThreshold,
 					AutoMerge:           true,
+					Similarity:          dedupSimilarity(nil, graphStore),
 				})
 				loopConfig = &cfg
 			}
@@ -347,17 +488,27 @@ Example:
 				loopConfig.ScopeOverride = &s
 			}
 
+			loopConfig = withStorageLimits(withExtractor(loopConfig))
 			loop := learning.NewLearningLoop(graphStore, loopConfig)
-			ctx := context.Background()
+
+			// Ctrl-C stops after the correction being processed; the ones
+			// already learned are still marked below
+			rep, notice := commandProgress(cmd, jsonOut)
+			interrupt, stop := interruptContext(notice)
+			defer stop()
+			ctx := context.WithoutCancel(interrupt)
 
 			var processed []models.Correction
+			var deferred, attempted int
 			var results []map[string]interface{}
 
-			for i := range corrections {
-				c := &corrections[i]
-				if c.Processed {
-					continue
+			rep.Start("reprocess", len(unprocessed))
+			for i := range unprocessed {
+				c := &unprocessed[i]
+				if interrupt.Err() != nil {
+					break
 				}
+				attempted++
 
 				// Sanitize correction fields before reprocessing
 				c.AgentAction = sanitize.SanitizeBehaviorContent(c.AgentAction)
@@ -370,6 +521,7 @@ Example:
 				}
 
 				result, err := loop.ProcessCorrection(ctx, *c)
+				rep.Step(c.ID)
 				if err != nil {
 					if !jsonOut {
 						fmt.Fprintf(os.Stderr, "Warning: failed to process correction %s: %v\n", c.ID, err)
@@ -377,6 +529,15 @@ Example:
 					continue
 				}
 
+				// Leave corrections blocked by a storage limit for a later run
+				if result.Quota != nil {
+					deferred++
+					if !jsonOut {
+						fmt.Fprintf(os.Stderr, "Warning: correction %s not learned: storage limit reached (%s)\n", c.ID, result.Quota.Usage)
+					}
+					continue
+				}
+
 				// Mark as processed
 				c.Processed = true
 				now := time.Now()
@@ -395,37 +556,36 @@ Example:
 				}
 			}
 
-			// Rewrite corrections file with updated processed flags
-			tmpPath := correctionsPath + ".tmp"
-			tmpFile, err := os.Create(tmpPath)
-			if err != nil {
-				return fmt.Errorf("failed to create temp file: %w", err)
-			}
-
-			encoder := json.NewEncoder(tmpFile)
-			for _, c := range corrections {
-				if err := encoder.Encode(c); err != nil {
-					tmpFile.Close()
-					os.Remove(tmpPath)
-					return fmt.Errorf("failed to write correction: %w", err)
-				}
-			}
-			tmpFile.Close()
+			rep.Finish()
+			interrupted := attempted < len(unprocessed)
 
-			if err := os.Rename(tmpPath, correctionsPath); err != nil {
-				return fmt.Errorf("failed to update corrections file: %w", err)
+			// Record the processed flags of the corrections learned
+			if err := correctionLog.AddCorrections(ctx, processed); err != nil {
+				return fmt.Errorf("failed to update corrections: %w", err)
 			}
 
 			if jsonOut {
-				json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				out := map[string]interface{}{
 					"status":    "completed",
 					"processed": len(processed),
-					"skipped":   len(corrections) - len(processed),
+					"skipped":   total - len(processed),
+					"deferred":  deferred,
 					"results":   results,
-				})
+				}
+				if interrupted {
+					out["status"] = "interrupted"
+					out["remaining"] = len(unprocessed) - attempted
+				}
+				json.NewEncoder(os.Stdout).Encode(out)
 			} else {
+				if interrupted {
+					fmt.Printf("\nInterrupted: %d corrections left unprocessed; run 'floop reprocess' again to continue.\n", len(unprocessed)-attempted)
+				}
 				fmt.Printf("\nReprocessed %d corrections into behaviors.\n", len(processed))
-				fmt.Printf("Skipped %d already-processed corrections.\n", len(corrections)-len(unprocessed))
+				fmt.Printf("Skipped %d already-processed corrections.\n", total-len(unprocessed))
+				if deferred > 0 {
+					fmt.Printf("Deferred %d corrections at storage limits; run 'floop status' for details.\n", deferred)
+				}
 			}
 
 			return nil
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/corrections"
+	"github.com/nvandessel/floop/internal/expiry"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/retirement"
+	"github.com/nvandessel/floop/internal/sanitize"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/tagging"
+	"github.com/spf13/cobra"
+)
+
+// runLearnBatch implements 'floop learn --from-file'.
+func runLearnBatch(cmd *cobra.Command, root, path string) error {
+	jsonOut, _ := cmd.Flags().GetBool("json")
+
+	floopDir := filepath.Join(root, ".floop")
+	if _, err := os.Stat(floopDir); os.IsNotExist(err) {
+		return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open batch file: %w", err)
+	}
+	entries, err := learning.ReadBatchEntries(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("invalid batch file %s: %w", path, err)
+	}
+
+	// Build every correction before learning any, so one bad entry leaves
+	// the store untouched
+	var defaults learning.BatchEntry
+	defaults.File, _ = cmd.Flags().GetString("file")
+	defaults.Task, _ = cmd.Flags().GetString("task")
+	defaults.Language, _ = cmd.Flags().GetString("language")
+	now := time.Now()
+	batch := make([]models.Correction, 0, len(entries))
+	for i, e := range entries {
+		c, err := batchCorrection(e, defaults, now, i)
+		if err != nil {
+			return fmt.Errorf("batch entry %d: %w", i+1, err)
+		}
+		batch = append(batch, c)
+	}
+
+	if len(batch) == 0 {
+		if jsonOut {
+			return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				"status": "empty",
+				"items":  []interface{}{},
+				"counts": map[string]int{},
+			})
+		}
+		fmt.Printf("No corrections in %s.\n", path)
+		return nil
+	}
+
+	graphStore, err := store.NewMultiGraphStore(root)
+	if err != nil {
+		return fmt.Errorf("failed to open graph store: %w", err)
+	}
+	defer graphStore.Close()
+
+	loopConfig, err := learnLoopConfig(cmd, graphStore)
+	if err != nil {
+		return err
+	}
+	loop := learning.NewLearningLoop(graphStore, loopConfig)
+	ctx := context.Background()
+
+	results := learning.ProcessBatch(ctx, loop, batch, nil)
+
+	// Held and deferred corrections go where a single learn would put
+	// them; learned ones are logged together once the batch is done
+	var processed []models.Correction
+	var restored []retirement.Notice
+	for i := range results {
+		r := &results[i]
+		switch r.Status {
+		case learning.BatchDuplicate, learning.BatchError:
+			continue
+		case learning.BatchHeld:
+			if err := holdResult(floopDir, r.Result, loopConfig); err != nil {
+				return err
+			}
+		case learning.BatchLimitReached:
+			if err := deferCorrection(floopDir, r.Correction); err != nil {
+				return err
+			}
+		default:
+			processedAt := time.Now()
+			r.Correction.Processed = true
+			r.Correction.ProcessedAt = &processedAt
+			processed = append(processed, r.Correction)
+			restored = append(restored, r.Result.Restored...)
+		}
+	}
+
+	if len(processed) > 0 {
+		if err := graphStore.Sync(ctx); err != nil {
+			return fmt.Errorf("failed to sync store: %w", err)
+		}
+		if err := corrections.Append(floopDir, processed...); err != nil {
+			return fmt.Errorf("failed to record corrections: %w", err)
+		}
+	}
+
+	if err := retirement.RecordNotices(floopDir, restored); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to record retirement notices: %v\n", err)
+	}
+
+	counts := learning.BatchCounts(results)
+	if jsonOut {
+		items := make([]map[string]interface{}, 0, len(results))
+		for _, r := range results {
+			items = append(items, batchItemJSON(r))
+		}
+		return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+			"status": "processed",
+			"items":  items,
+			"counts": counts,
+		})
+	}
+
+	fmt.Printf("Processed %d corrections from %s:\n", len(results), path)
+	for _, r := range results {
+		fmt.Printf("  %d. [%s] %s\n", r.Index+1, r.Status, r.Correction.CorrectedAction[:min(60, len(r.Correction.CorrectedAction))])
+		switch {
+		case r.Status == learning.BatchDuplicate:
+			fmt.Printf("     Same as entry %d\n", r.DuplicateOf+1)
+		case r.Err != nil:
+			fmt.Printf("     %v\n", r.Err)
+		case r.Status == learning.BatchMerged:
+			fmt.Printf("     Merged into %s\n", r.Result.MergedBehaviorID)
+		case r.Result != nil && r.Result.CandidateBehavior.ID != "" && r.Status != learning.BatchHeld:
+			fmt.Printf("     Behavior %s\n", r.Result.CandidateBehavior.ID)
+		}
+	}
+	fmt.Println()
+	for _, status := range learning.BatchStatuses {
+		if counts[status] > 0 {
+			fmt.Printf("  %s: %d\n", status, counts[status])
+		}
+	}
+	if counts[learning.BatchReview] > 0 {
+		fmt.Println("Review queued behaviors with 'floop review'.")
+	}
+	if counts[learning.BatchLimitReached] > 0 {
+		fmt.Println("Corrections at storage limits were saved unprocessed; run 'floop reprocess' after consolidating.")
+	}
+	return nil
+}
+
+// batchCorrection builds the correction for entry i of a batch, filling
+// file, task, and language from defaults where the entry omits them.
+func batchCorrection(e learning.BatchEntry, defaults learning.BatchEntry, now time.Time, i int) (models.Correction, error) {
+	if e.File == "" {
+		e.File = defaults.File
+	}
+	if e.Task == "" {
+		e.Task = defaults.Task
+	}
+	if e.Language == "" {
+		e.Language = defaults.Language
+	}
+
+	// Sanitize inputs to prevent stored prompt injection
+	right := sanitize.SanitizeBehaviorContent(e.Right)
+	if right == "" {
+		return models.Correction{}, fmt.Errorf("\"right\" is empty after sanitization: input contained only unsafe content")
+	}
+	wrong := e.Wrong
+	if wrong != "" {
+		wrong = sanitize.SanitizeBehaviorContent(wrong)
+	}
+	ctxSnapshot := models.ContextSnapshot{Timestamp: now}
+	if e.Task != "" {
+		ctxSnapshot.Task = sanitize.SanitizeBehaviorContent(e.Task)
+	}
+	if e.File != "" {
+		ctxSnapshot.FilePath = sanitize.SanitizeFilePath(e.File)
+		ctxSnapshot.FileLanguage = models.InferLanguage(ctxSnapshot.FilePath)
+		ctxSnapshot.FileExt = filepath.Ext(ctxSnapshot.FilePath)
+	}
+	if e.Language != "" {
+		ctxSnapshot.FileLanguage = sanitize.SanitizeBehaviorContent(e.Language)
+	}
+
+	if len(e.Tags) > tagging.MaxExtraTags {
+		return models.Correction{}, fmt.Errorf("\"tags\" accepts at most %d tags, got %d", tagging.MaxExtraTags, len(e.Tags))
+	}
+
+	var extraWhen map[string]interface{}
+	for _, w := range e.When {
+		key, value, err := models.ParseCondition(w)
+		if err != nil {
+			return models.Correction{}, fmt.Errorf("\"when\": %w", err)
+		}
+		if extraWhen == nil {
+			extraWhen = make(map[string]interface{})
+		}
+		extraWhen[key] = value
+	}
+
+	var expiresAt *time.Time
+	if e.ExpiresAt != "" {
+		t, err := expiry.Parse(e.ExpiresAt, now)
+		if err != nil {
+			return models.Correction{}, fmt.Errorf("\"expires_at\": %w", err)
+		}
+		if !t.After(now) {
+			return models.Correction{}, fmt.Errorf("\"expires_at\" must be in the future")
+		}
+		expiresAt = &t
+	}
+
+	return models.Correction{
+		ID:              fmt.Sprintf("c-%d-%d", now.UnixNano(), i),
+		Timestamp:       now,
+		Context:         ctxSnapshot,
+		AgentAction:     wrong,
+		CorrectedAction: right,
+		ExtraTags:       e.Tags,
+		ExtraWhen:       extraWhen,
+		ExpiresAt:       expiresAt,
+	}, nil
+}
+
+// batchItemJSON is the --json form of one batch result.
+func batchItemJSON(r learning.BatchItemResult) map[string]interface{} {
+	item := map[string]interface{}{
+		"index":         r.Index,
+		"status":        r.Status,
+		"correction_id": r.Correction.ID,
+		"right":         r.Correction.CorrectedAction,
+	}
+	if r.Status == learning.BatchDuplicate {
+		item["duplicate_of"] = r.DuplicateOf
+	}
+	if r.Err != nil {
+		item["error"] = r.Err.Error()
+	}
+	if res := r.Result; res != nil {
+		if res.CandidateBehavior.ID != "" && !res.Held {
+			item["behavior_id"] = res.CandidateBehavior.ID
+			item["scope"] = res.Scope
+		}
+		if res.MergedIntoExisting {
+			item["merged_into_id"] = res.MergedBehaviorID
+			item["merge_similarity"] = res.MergeSimilarity
+		}
+		if len(res.ReviewReasons) > 0 {
+			item["review_reasons"] = res.ReviewReasons
+		}
+		if res.Quality != nil {
+			item["quality"] = res.Quality
+		}
+		if res.Quota != nil {
+			item["quota"] = res.Quota
+		}
+	}
+	return item
+}
@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/nvandessel/floop/internal/corrections"
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/store"
 )
@@ -146,12 +148,7 @@ func TestLearnCmdSanitizesInputs(t *testing.T) {
 				t.Fatalf("unexpected error: %v", err)
 			}
 
-			// Read the correction from the corrections.jsonl file
-			correctionsPath := filepath.Join(tmpDir, ".floop", "corrections.jsonl")
-			data, err := os.ReadFile(correctionsPath)
-			if err != nil {
-				t.Fatalf("failed to read corrections: %v", err)
-			}
+			data := loggedCorrectionJSON(t, tmpDir)
 
 			var correction map[string]interface{}
 			if err := json.Unmarshal(data, &correction); err != nil {
@@ -224,11 +221,7 @@ func TestLearnCmdTagsFlag(t *testing.T) {
 		}
 
 		// Read the correction and verify extra_tags
-		correctionsPath := filepath.Join(tmpDir, ".floop", "corrections.jsonl")
-		data, err := os.ReadFile(correctionsPath)
-		if err != nil {
-			t.Fatalf("failed to read corrections: %v", err)
-		}
+		data := loggedCorrectionJSON(t, tmpDir)
 
 		var correction models.Correction
 		if err := json.Unmarshal(data, &correction); err != nil {
@@ -303,11 +296,7 @@ func TestLearnCmdTagsFlag(t *testing.T) {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		correctionsPath := filepath.Join(tmpDir, ".floop", "corrections.jsonl")
-		data, err := os.ReadFile(correctionsPath)
-		if err != nil {
-			t.Fatalf("failed to read corrections: %v", err)
-		}
+		data := loggedCorrectionJSON(t, tmpDir)
 
 		var correction models.Correction
 		if err := json.Unmarshal(data, &correction); err != nil {
@@ -350,11 +339,7 @@ func TestLearnCmdWithoutWrong(t *testing.T) {
 	}
 
 	// Verify correction was written
-	correctionsPath := filepath.Join(tmpDir, ".floop", "corrections.jsonl")
-	data, err := os.ReadFile(correctionsPath)
-	if err != nil {
-		t.Fatalf("failed to read corrections: %v", err)
-	}
+	data := loggedCorrectionJSON(t, tmpDir)
 
 	var correction models.Correction
 	if err := json.Unmarshal(data, &correction); err != nil {
@@ -398,11 +383,7 @@ func TestLearnCmdLanguageFlag(t *testing.T) {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		correctionsPath := filepath.Join(tmpDir, ".floop", "corrections.jsonl")
-		data, err := os.ReadFile(correctionsPath)
-		if err != nil {
-			t.Fatalf("failed to read corrections: %v", err)
-		}
+		data := loggedCorrectionJSON(t, tmpDir)
 
 		var correction models.Correction
 		if err := json.Unmarshal(data, &correction); err != nil {
@@ -443,11 +424,7 @@ func TestLearnCmdLanguageFlag(t *testing.T) {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		correctionsPath := filepath.Join(tmpDir, ".floop", "corrections.jsonl")
-		data, err := os.ReadFile(correctionsPath)
-		if err != nil {
-			t.Fatalf("failed to read corrections: %v", err)
-		}
+		data := loggedCorrectionJSON(t, tmpDir)
 
 		var correction models.Correction
 		if err := json.Unmarshal(data, &correction); err != nil {
@@ -461,6 +438,79 @@ func TestLearnCmdLanguageFlag(t *testing.T) {
 	})
 }
 
+func TestLearnCmdFromFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newInitCmd())
+	rootCmd.SetArgs([]string{"init", "--root", tmpDir})
+	rootCmd.SetOut(&bytes.Buffer{})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	transcript := filepath.Join(tmpDir, "transcript.jsonl")
+	lines := strings.Join([]string{
+		`{"wrong":"used pip install","right":"use uv instead of pip for package management"}`,
+		``,
+		`{"right":"Use uv instead of pip for package management"}`,
+		`{"right":"wrap errors with fmt.Errorf and %w","file":"main.go"}`,
+	}, "\n")
+	if err := os.WriteFile(transcript, []byte(lines), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd2 := newTestRootCmd()
+	rootCmd2.AddCommand(newLearnCmd())
+	rootCmd2.SetArgs([]string{"learn", "--from-file", transcript, "--language", "python", "--root", tmpDir, "--json"})
+	rootCmd2.SetOut(&bytes.Buffer{})
+	if err := rootCmd2.Execute(); err != nil {
+		t.Fatalf("learn --from-file failed: %v", err)
+	}
+
+	// The repeated entry is learned once
+	logged := loggedCorrections(t, tmpDir)
+	if len(logged) != 2 {
+		t.Fatalf("got %d logged corrections, want 2", len(logged))
+	}
+	if !logged[0].Processed || logged[0].Context.FileLanguage != "python" {
+		t.Errorf("logged[0] = %+v, want processed with --language default", logged[0])
+	}
+	if logged[1].Context.FilePath != "main.go" || logged[1].Context.FileLanguage != "python" {
+		t.Errorf("logged[1] context = %+v, want main.go with --language default", logged[1].Context)
+	}
+
+	t.Run("invalid entry changes nothing", func(t *testing.T) {
+		bad := filepath.Join(tmpDir, "bad.jsonl")
+		if err := os.WriteFile(bad, []byte(`{"right":"prefer table-driven tests"}`+"\n"+`{"wrong":"no right"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cmd := newTestRootCmd()
+		cmd.AddCommand(newLearnCmd())
+		cmd.SetArgs([]string{"learn", "--from-file", bad, "--root", tmpDir})
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("learn --from-file error = %v, want line 2 error", err)
+		}
+		if after := loggedCorrections(t, tmpDir); !reflect.DeepEqual(after, logged) {
+			t.Error("corrections log changed after a rejected batch")
+		}
+	})
+
+	t.Run("right with from-file rejected", func(t *testing.T) {
+		cmd := newTestRootCmd()
+		cmd.AddCommand(newLearnCmd())
+		cmd.SetArgs([]string{"learn", "--from-file", transcript, "--right", "x", "--root", tmpDir})
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		if err := cmd.Execute(); err == nil {
+			t.Error("expected error combining --from-file with --right")
+		}
+	})
+}
+
 func TestReprocessCmdSanitizesCorrections(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -576,19 +626,10 @@ func TestReprocessCmdSanitizesCorrections(t *testing.T) {
 				t.Fatal("no behaviors found after reprocess")
 			}
 
-			// Also verify the rewritten corrections.jsonl has sanitized fields
-			data, err := os.ReadFile(correctionsPath)
-			if err != nil {
-				t.Fatalf("failed to read rewritten corrections: %v", err)
-			}
-
-			lines := strings.Split(strings.TrimSpace(string(data)), "\n")
-			if len(lines) == 0 {
-				t.Fatal("no corrections in rewritten file")
-			}
-
+			// Also verify the recorded correction has sanitized fields
+			data := loggedCorrectionJSON(t, tmpDir)
 			var rewrittenCorrection map[string]interface{}
-			if err := json.Unmarshal([]byte(lines[0]), &rewrittenCorrection); err != nil {
+			if err := json.Unmarshal(data, &rewrittenCorrection); err != nil {
 				t.Fatalf("failed to parse rewritten correction: %v", err)
 			}
 
@@ -617,3 +658,29 @@ func TestReprocessCmdSanitizesCorrections(t *testing.T) {
 		})
 	}
 }
+
+// loggedCorrections returns the corrections recorded for the project at
+// root.
+func loggedCorrections(t *testing.T, root string) []models.Correction {
+	t.Helper()
+	logged, err := corrections.Load(filepath.Join(root, ".floop"))
+	if err != nil {
+		t.Fatalf("failed to read corrections: %v", err)
+	}
+	return logged
+}
+
+// loggedCorrectionJSON returns the first correction recorded for the
+// project at root, as JSON.
+func loggedCorrectionJSON(t *testing.T, root string) []byte {
+	t.Helper()
+	logged := loggedCorrections(t, root)
+	if len(logged) == 0 {
+		t.Fatal("no corrections recorded")
+	}
+	data, err := json.Marshal(logged[0])
+	if err != nil {
+		t.Fatalf("failed to marshal correction: %v", err)
+	}
+	return data
+}
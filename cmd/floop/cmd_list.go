@@ -7,11 +7,16 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/nvandessel/floop/internal/activation"
 	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/corrections"
+	"github.com/nvandessel/floop/internal/edges"
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/tagging"
 	"github.com/spf13/cobra"
 )
 
@@ -27,6 +32,8 @@ func newListCmd() *cobra.Command {
 			localFlag, _ := cmd.Flags().GetBool("local")
 			allFlag, _ := cmd.Flags().GetBool("all")
 			tagFilter, _ := cmd.Flags().GetString("tag")
+			limit, _ := cmd.Flags().GetInt("limit")
+			cursor, _ := cmd.Flags().GetString("cursor")
 
 			// Validate flag combinations
 			if globalFlag && localFlag {
@@ -38,14 +45,26 @@ func newListCmd() *cobra.Command {
 			if localFlag && allFlag {
 				return fmt.Errorf("cannot specify both --local and --all")
 			}
+			if limit < 0 {
+				return fmt.Errorf("--limit must be 0 or more")
+			}
 
-			// Handle --corrections early: it reads from local corrections.jsonl only,
+			// Handle --corrections early: it reads local corrections only,
 			// scope checks are irrelevant and would emit misleading warnings.
 			if showCorrections {
 				if globalFlag || localFlag || allFlag {
 					fmt.Fprintln(cmd.ErrOrStderr(), "Warning: --corrections reads local corrections only; scope flags are ignored")
 				}
-				return listCorrections(cmd.OutOrStdout(), root, jsonOut)
+				filter, err := correctionFilter(cmd)
+				if err != nil {
+					return err
+				}
+				return listCorrections(cmd.Context(), cmd.OutOrStdout(), root, filter, jsonOut)
+			}
+			for _, name := range []string{"since", "until", "processed", "unprocessed"} {
+				if cmd.Flags().Changed(name) {
+					return fmt.Errorf("--%s requires --corrections", name)
+				}
 			}
 
 			// Determine scope
@@ -124,24 +143,32 @@ func newListCmd() *cobra.Command {
 				}
 			}
 
-			// Load behaviors from appropriate store(s)
-			behaviors, err := loadBehaviorsWithScope(root, scope)
-			if err != nil {
-				return fmt.Errorf("failed to load behaviors: %w", err)
-			}
+			// Load behaviors from appropriate store(s), a page of them when
+			// --limit or --cursor is given
+			var behaviors []models.Behavior
+			var next string
+			var err error
+			if limit > 0 || cursor != "" {
+				behaviors, next, err = loadBehaviorPage(root, scope, tagFilter, cursor, limit)
+				if err != nil {
+					return fmt.Errorf("failed to load behaviors: %w", err)
+				}
+			} else {
+				behaviors, err = loadBehaviorsWithScope(root, scope)
+				if err != nil {
+					return fmt.Errorf("failed to load behaviors: %w", err)
+				}
 
-			// Filter by tag if specified
-			if tagFilter != "" {
-				var filtered []models.Behavior
-				for _, b := range behaviors {
-					for _, t := range b.Content.Tags {
-						if t == tagFilter {
+				// Filter by tag if specified, including the tags below it
+				if tagFilter != "" {
+					var filtered []models.Behavior
+					for _, b := range behaviors {
+						if tagging.HasTag(b.Content.Tags, tagFilter) {
 							filtered = append(filtered, b)
-							break
 						}
 					}
+					behaviors = filtered
 				}
-				behaviors = filtered
 			}
 
 			if jsonOut {
@@ -154,12 +181,18 @@ func newListCmd() *cobra.Command {
 					"count":     len(behaviors),
 					"scope":     string(scope),
 				}
+				if scopes := scopeOrigins(behaviors); len(scopes) > 0 {
+					result["scopes"] = scopes
+				}
+				if next != "" {
+					result["next_cursor"] = next
+				}
 				json.NewEncoder(cmd.OutOrStdout()).Encode(result)
 			} else {
 				// Show scope in header
 				scopeStr := string(scope)
 				if scope == constants.ScopeBoth {
-					scopeStr = "all (local + global)"
+					scopeStr = "all (" + strings.Join(append([]string{"local", "global"}, scopeOrigins(behaviors)...), " + ") + ")"
 				}
 
 				if len(behaviors) == 0 {
@@ -180,8 +213,17 @@ func newListCmd() *cobra.Command {
 						fmt.Fprintf(cmd.OutOrStdout(), "   When: %v\n", b.When)
 					}
 					fmt.Fprintf(cmd.OutOrStdout(), "   Confidence: %.2f\n", b.Confidence)
+					if b.Origin != "" {
+						fmt.Fprintf(cmd.OutOrStdout(), "   Origin: %s\n", b.Origin)
+					}
 					fmt.Fprintln(cmd.OutOrStdout())
 				}
+				if next != "" {
+					if limit <= 0 {
+						limit = store.DefaultNodePageSize
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "More behaviors follow: floop list --limit %d --cursor %s\n", limit, next)
+				}
 			}
 
 			return nil
@@ -193,170 +235,287 @@ func newListCmd() *cobra.Command {
 	cmd.Flags().Bool("local", false, "Show behaviors from local project store only")
 	cmd.Flags().Bool("all", false, "Show behaviors from both local and global stores")
 	_ = cmd.Flags().MarkDeprecated("all", "both is now the default scope; use --local or --global to narrow")
-	cmd.Flags().String("tag", "", "Filter behaviors by tag (exact match)")
+	cmd.Flags().String("tag", "", "Filter behaviors by tag, including tags below it (testing matches testing/unit)")
+	cmd.Flags().Int("limit", 0, "Show at most this many behaviors, in ID order, or corrections, in time order (default: all)")
+	cmd.Flags().String("cursor", "", "Continue a paged listing from the cursor it printed")
+	cmd.Flags().String("since", "", "With --corrections, only corrections at or after this RFC 3339 time or duration ago (e.g. 7d)")
+	cmd.Flags().String("until", "", "With --corrections, only corrections before this RFC 3339 time or duration ago")
+	cmd.Flags().Bool("processed", false, "With --corrections, only corrections already learned")
+	cmd.Flags().Bool("unprocessed", false, "With --corrections, only corrections not yet learned")
 
 	return cmd
 }
 
-func listCorrections(w io.Writer, root string, jsonOut bool) error {
-	correctionsPath := filepath.Join(root, ".floop", "corrections.jsonl")
-
-	data, err := os.ReadFile(correctionsPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			if jsonOut {
-				json.NewEncoder(w).Encode(map[string]interface{}{
-					"corrections": []models.Correction{},
-					"count":       0,
-				})
-			} else {
-				fmt.Fprintln(w, "No corrections captured yet.")
-			}
-			return nil
+// listCorrections prints a page of the local corrections matching filter.
+func listCorrections(ctx context.Context, w io.Writer, root string, filter corrections.Filter, jsonOut bool) error {
+	floopDir := filepath.Join(root, ".floop")
+	var page []models.Correction
+	var next string
+	total := 0
+	if _, err := os.Stat(floopDir); err == nil {
+		correctionLog, err := corrections.Open(floopDir)
+		if err != nil {
+			return err
 		}
-		return err
-	}
-
-	// Parse JSONL into models.Correction
-	var corrections []models.Correction
-	lines := splitLines(string(data))
-	for _, line := range lines {
-		if line == "" {
-			continue
+		defer correctionLog.Close()
+		if page, next, err = correctionLog.ListCorrections(ctx, filter); err != nil {
+			return err
 		}
-		var c models.Correction
-		if err := json.Unmarshal([]byte(line), &c); err != nil {
-			continue
+		if total, err = correctionLog.CountCorrections(ctx, filter); err != nil {
+			return err
 		}
-		corrections = append(corrections, c)
+	}
+	if page == nil {
+		page = []models.Correction{}
 	}
 
 	if jsonOut {
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"corrections": corrections,
-			"count":       len(corrections),
-		})
-	} else {
-		if len(corrections) == 0 {
-			fmt.Fprintln(w, "No corrections captured yet.")
-			return nil
+		result := map[string]interface{}{
+			"corrections": page,
+			"count":       len(page),
+			"total":       total,
 		}
-		fmt.Fprintf(w, "Captured corrections (%d):\n\n", len(corrections))
-		for i, c := range corrections {
-			fmt.Fprintf(w, "%d. [%s]\n", i+1, c.Timestamp.Format("2006-01-02T15:04:05Z07:00"))
-			fmt.Fprintf(w, "   Wrong: %s\n", c.AgentAction)
-			fmt.Fprintf(w, "   Right: %s\n", c.CorrectedAction)
-			if c.Context.FilePath != "" {
-				fmt.Fprintf(w, "   File:  %s\n", c.Context.FilePath)
-			}
-			fmt.Fprintln(w)
+		if next != "" {
+			result["next_cursor"] = next
 		}
+		return json.NewEncoder(w).Encode(result)
 	}
 
+	if total == 0 {
+		fmt.Fprintln(w, "No corrections captured yet.")
+		return nil
+	}
+	if len(page) < total {
+		fmt.Fprintf(w, "Captured corrections (%d of %d):\n\n", len(page), total)
+	} else {
+		fmt.Fprintf(w, "Captured corrections (%d):\n\n", total)
+	}
+	for i, c := range page {
+		fmt.Fprintf(w, "%d. [%s]\n", i+1, c.Timestamp.Format("2006-01-02T15:04:05Z07:00"))
+		fmt.Fprintf(w, "   Wrong: %s\n", c.AgentAction)
+		fmt.Fprintf(w, "   Right: %s\n", c.CorrectedAction)
+		if c.Context.FilePath != "" {
+			fmt.Fprintf(w, "   File:  %s\n", c.Context.FilePath)
+		}
+		fmt.Fprintln(w)
+	}
+	if next != "" {
+		fmt.Fprintf(w, "More corrections follow: floop list --corrections --limit %d --cursor %s\n", filter.Limit, next)
+	}
 	return nil
 }
 
-// splitLines splits a string into lines without using strings.Split for efficiency.
-func splitLines(s string) []string {
-	var lines []string
-	start := 0
-	for i := 0; i < len(s); i++ {
-		if s[i] == '\n' {
-			lines = append(lines, s[start:i])
-			start = i + 1
+// correctionFilter builds the corrections filter of the list flags.
+func correctionFilter(cmd *cobra.Command) (corrections.Filter, error) {
+	var filter corrections.Filter
+	filter.Limit, _ = cmd.Flags().GetInt("limit")
+	filter.Cursor, _ = cmd.Flags().GetString("cursor")
+	for _, bound := range []struct {
+		flag string
+		dst  *time.Time
+	}{{"since", &filter.Since}, {"until", &filter.Until}} {
+		value, _ := cmd.Flags().GetString(bound.flag)
+		if value == "" {
+			continue
 		}
+		t, err := corrections.ParseTimeBound(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid --%s: %w", bound.flag, err)
+		}
+		*bound.dst = t
 	}
-	if start < len(s) {
-		lines = append(lines, s[start:])
+	processed, _ := cmd.Flags().GetBool("processed")
+	unprocessed, _ := cmd.Flags().GetBool("unprocessed")
+	switch {
+	case processed && unprocessed:
+		return filter, fmt.Errorf("cannot specify both --processed and --unprocessed")
+	case processed || unprocessed:
+		filter.Processed = &processed
+	}
+	return filter, nil
+}
+
+// scopeOrigins returns the scope-store origins among behaviors, e.g.
+// "scope:go", in order of first appearance.
+func scopeOrigins(behaviors []models.Behavior) []string {
+	var origins []string
+	seen := make(map[string]bool)
+	for _, b := range behaviors {
+		if _, scoped := store.Origin(b.Origin).ScopeName(); scoped && !seen[b.Origin] {
+			seen[b.Origin] = true
+			origins = append(origins, b.Origin)
+		}
 	}
-	return lines
+	return origins
 }
 
 // loadBehaviorsWithScope loads behaviors from the specified scope (local, global, or both).
 func loadBehaviorsWithScope(projectRoot string, scope constants.Scope) ([]models.Behavior, error) {
+	graphStore, origin, err := openScopeStore(projectRoot, scope)
+	if err != nil {
+		return nil, err
+	}
+	defer graphStore.Close()
+
+	return behaviorsFromStore(context.Background(), graphStore, origin)
+}
+
+// loadBehaviorPage loads one page of the behaviors of scope, with tag and
+// the tags below it if tag is set, in ID order. It returns the cursor of
+// the next page, empty after the last page.
+func loadBehaviorPage(projectRoot string, scope constants.Scope, tag, cursor string, limit int) ([]models.Behavior, string, error) {
+	graphStore, origin, err := openScopeStore(projectRoot, scope)
+	if err != nil {
+		return nil, "", err
+	}
+	defer graphStore.Close()
+
 	ctx := context.Background()
-	var graphStore store.GraphStore
-	var err error
+	predicate := map[string]interface{}{"kind": string(store.NodeKindBehavior)}
+	if tag != "" {
+		predicate["tag"] = tag
+	}
+	nodes, next, err := store.QueryNodesPage(ctx, graphStore, predicate, cursor, limit)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query behaviors: %w", err)
+	}
 
+	behaviors := make([]models.Behavior, 0, len(nodes))
+	for _, node := range nodes {
+		behaviors = append(behaviors, behaviorWithOrigin(node, origin))
+	}
+	if err := edges.AttachRelationships(ctx, graphStore, behaviors); err != nil {
+		return nil, "", err
+	}
+	return behaviors, next, nil
+}
+
+// openScopeStore opens the store of scope. Single-store reads have no
+// origin of their own, so the returned origin is taken from the scope;
+// it is empty for both.
+func openScopeStore(projectRoot string, scope constants.Scope) (store.GraphStore, string, error) {
 	switch scope {
 	case constants.ScopeLocal:
 		// Load from local store only
-		graphStore, err = store.NewSQLiteGraphStore(projectRoot)
+		graphStore, err := store.NewSQLiteGraphStore(projectRoot)
 		if err != nil {
-			return nil, fmt.Errorf("failed to open local store: %w", err)
+			return nil, "", fmt.Errorf("failed to open local store: %w", err)
 		}
+		return graphStore, string(scope), nil
 
 	case constants.ScopeGlobal:
 		// Load from global store only
 		globalPath, err := store.GlobalFloopPath()
 		if err != nil {
-			return nil, fmt.Errorf("failed to get global path: %w", err)
+			return nil, "", fmt.Errorf("failed to get global path: %w", err)
 		}
-		graphStore, err = store.NewSQLiteGraphStore(filepath.Dir(globalPath))
+		graphStore, err := store.NewGlobalGraphStore(filepath.Dir(globalPath))
 		if err != nil {
-			return nil, fmt.Errorf("failed to open global store: %w", err)
+			return nil, "", fmt.Errorf("failed to open global store: %w", err)
 		}
+		return graphStore, string(scope), nil
 
 	case constants.ScopeBoth:
 		// Load from both stores using MultiGraphStore
-		graphStore, err = store.NewMultiGraphStore(projectRoot)
+		graphStore, err := store.NewMultiGraphStore(projectRoot)
 		if err != nil {
-			return nil, fmt.Errorf("failed to open multi-store: %w", err)
+			return nil, "", fmt.Errorf("failed to open multi-store: %w", err)
 		}
+		return graphStore, "", nil
 
 	default:
-		return nil, fmt.Errorf("invalid scope: %s", scope)
+		return nil, "", fmt.Errorf("invalid scope: %s", scope)
 	}
+}
 
-	defer graphStore.Close()
-
-	// Query all behavior nodes
-	nodes, err := graphStore.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+// behaviorsFromStore loads the behavior nodes of graphStore a page at a
+// time, with their relationships attached. Behaviors without an origin are
+// given origin.
+func behaviorsFromStore(ctx context.Context, graphStore store.GraphStore, origin string) ([]models.Behavior, error) {
+	behaviors := make([]models.Behavior, 0)
+	err := store.WalkNodes(ctx, graphStore, map[string]interface{}{"kind": string(store.NodeKindBehavior)}, func(node store.Node) error {
+		behaviors = append(behaviors, behaviorWithOrigin(node, origin))
+		return nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to query behaviors: %w", err)
 	}
 
-	// Convert nodes to behaviors
-	behaviors := make([]models.Behavior, 0, len(nodes))
-	for _, node := range nodes {
-		b := models.NodeToBehavior(node)
-		behaviors = append(behaviors, b)
+	if err := edges.AttachRelationships(ctx, graphStore, behaviors); err != nil {
+		return nil, err
 	}
 
 	return behaviors, nil
 }
 
+// behaviorWithOrigin converts node to a behavior, giving it origin if the
+// node has none.
+func behaviorWithOrigin(node store.Node, origin string) models.Behavior {
+	b := models.NodeToBehavior(node)
+	if b.Origin == "" {
+		b.Origin = origin
+	}
+	return b
+}
+
 func newActiveCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "active",
+		Use:   "active [files...]",
 		Short: "Show behaviors active in current context",
 		Long: `List all behaviors that are currently active based on the
 current context (file, task, language, etc.).
 
-Use --json for machine-readable output suitable for agent consumption.`,
+Pass --tags to declare what the work is about: behaviors with a 'tags'
+when-condition activate when a declared tag matches it, and a declared
+sub-tag such as testing/unit also matches the condition tags=testing.
+
+Pass --files (or file arguments) to evaluate a changeset: each file is
+evaluated on its own and the active behaviors are the union across files,
+each annotated with the files that triggered it.
+
+Use --json for machine-readable output suitable for agent consumption.
+While 'floop watch' runs for the project, --json queries are answered by
+the daemon instead of opening the stores (--no-daemon evaluates directly).`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			root, _ := cmd.Flags().GetString("root")
 			file, _ := cmd.Flags().GetString("file")
+			files, _ := cmd.Flags().GetStringSlice("files")
 			task, _ := cmd.Flags().GetString("task")
 			env, _ := cmd.Flags().GetString("env")
+			agent, _ := cmd.Flags().GetString("agent")
+			tags, _ := cmd.Flags().GetStringSlice("tags")
+			kinds, _ := cmd.Flags().GetStringSlice("kinds")
+			excludeKinds, _ := cmd.Flags().GetStringSlice("exclude-kinds")
 			jsonOut, _ := cmd.Flags().GetBool("json")
-
-			// Determine effective scope — degrade gracefully if one store is missing
-			activeScope := constants.ScopeBoth
-			floopDir := filepath.Join(root, ".floop")
-			hasLocal := true
-			if _, err := os.Stat(floopDir); err != nil {
-				hasLocal = false
+			noDaemon, _ := cmd.Flags().GetBool("no-daemon")
+			gitContext, _ := cmd.Flags().GetBool("git-context")
+
+			query := activeQuery{
+				File:         file,
+				Files:        append(files, args...),
+				Task:         task,
+				Env:          env,
+				Agent:        agent,
+				Tags:         tags,
+				Kinds:        kinds,
+				ExcludeKinds: excludeKinds,
+				GitContext:   gitContext,
+			}
+			if err := query.validate(); err != nil {
+				return err
 			}
 
-			hasGlobal := true
-			if globalPath, err := store.GlobalFloopPath(); err != nil {
-				hasGlobal = false
-			} else if _, err := os.Stat(globalPath); err != nil {
-				hasGlobal = false
+			// A running 'floop watch' daemon answers without opening the stores
+			if jsonOut && !noDaemon {
+				if resp, err := queryWatchDaemon(watchSocketPath(root), query); err == nil {
+					_, err := os.Stdout.Write(resp)
+					return err
+				}
 			}
 
-			if !hasLocal && !hasGlobal {
+			// Determine effective scope — degrade gracefully if one store is missing
+			activeScope, ok := availableScope(root)
+			if !ok {
 				if jsonOut {
 					json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]interface{}{
 						"error": "no .floop stores initialized",
@@ -366,11 +525,6 @@ Use --json for machine-readable output suitable for agent consumption.`,
 				}
 				return nil
 			}
-			if !hasLocal {
-				activeScope = constants.ScopeGlobal
-			} else if !hasGlobal {
-				activeScope = constants.ScopeLocal
-			}
 
 			// Load behaviors from available store(s)
 			behaviors, err := loadBehaviorsWithScope(root, activeScope)
@@ -378,33 +532,19 @@ Use --json for machine-readable output suitable for agent consumption.`,
 				return fmt.Errorf("failed to load behaviors: %w", err)
 			}
 
-			// Build context
-			ctxBuilder := activation.NewContextBuilder().
-				WithFile(file).
-				WithTask(task).
-				WithEnvironment(env).
-				WithRepoRoot(root)
-			ctx := ctxBuilder.Build()
-
-			// Evaluate which behaviors are active
-			evaluator := activation.NewEvaluator()
-			matches := evaluator.Evaluate(ctx, behaviors)
-
-			// Resolve conflicts
-			resolver := activation.NewResolver()
-			result := resolver.Resolve(matches)
+			eval, err := evaluateActive(root, behaviors, query)
+			if err != nil {
+				return err
+			}
+			ctx, result, changeset, triggeredBy := eval.ctx, eval.result, eval.changeset, eval.triggeredBy
 
 			if jsonOut {
-				json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-					"context":    ctx,
-					"active":     result.Active,
-					"overridden": result.Overridden,
-					"excluded":   result.Excluded,
-					"count":      len(result.Active),
-				})
+				json.NewEncoder(os.Stdout).Encode(eval.jsonOutput())
 			} else {
 				fmt.Printf("Context:\n")
-				if ctx.FilePath != "" {
+				if len(changeset) > 0 {
+					fmt.Printf("  Files: %s\n", strings.Join(changeset, ", "))
+				} else if ctx.FilePath != "" {
 					fmt.Printf("  File: %s\n", ctx.FilePath)
 				}
 				if ctx.FileLanguage != "" {
@@ -413,14 +553,29 @@ Use --json for machine-readable output suitable for agent consumption.`,
 				if ctx.Task != "" {
 					fmt.Printf("  Task: %s\n", ctx.Task)
 				}
+				if len(ctx.Tags) > 0 {
+					fmt.Printf("  Tags: %s\n", strings.Join(ctx.Tags, ", "))
+				}
 				if ctx.Branch != "" {
 					fmt.Printf("  Branch: %s\n", ctx.Branch)
 				}
+				if len(ctx.StagedFiles) > 0 {
+					fmt.Printf("  Staged: %s\n", strings.Join(ctx.StagedFiles, ", "))
+				}
+				if len(ctx.ChangedPaths) > 0 {
+					fmt.Printf("  Changed: %s\n", strings.Join(ctx.ChangedPaths, ", "))
+				}
+				if ctx.CommitMessage != "" {
+					fmt.Printf("  Commit message: %s\n", strings.SplitN(ctx.CommitMessage, "\n", 2)[0])
+				}
 				fmt.Println()
 
 				if len(result.Active) == 0 {
 					fmt.Println("No active behaviors for this context.")
-					if len(behaviors) > 0 {
+					if len(result.Blocked) > 0 {
+						fmt.Println()
+						printBlocked(result.Blocked)
+					} else if len(behaviors) > 0 {
 						fmt.Printf("\n(%d behaviors exist but none match current context)\n", len(behaviors))
 					}
 					return nil
@@ -433,13 +588,22 @@ Use --json for machine-readable output suitable for agent consumption.`,
 					if len(b.When) > 0 {
 						fmt.Printf("   When: %v\n", b.When)
 					}
+					if b.Origin != "" {
+						fmt.Printf("   Origin: %s\n", b.Origin)
+					}
+					if triggers := triggeredBy[b.ID]; len(triggers) > 0 {
+						fmt.Printf("   Triggered by: %s\n", strings.Join(triggers, ", "))
+					}
 					fmt.Println()
 				}
 
 				if len(result.Overridden) > 0 {
 					fmt.Printf("Overridden behaviors (%d):\n", len(result.Overridden))
 					for _, o := range result.Overridden {
-						fmt.Printf("  - %s (by %s)\n", o.Behavior.Name, o.OverrideBy)
+						fmt.Printf("  - %s (by %s: %s)\n", o.Behavior.Name, o.OverrideBy, o.Reason)
+						if len(o.Chain) > 2 {
+							fmt.Printf("    Chain: %s\n", strings.Join(o.Chain, " -> "))
+						}
 					}
 					fmt.Println()
 				}
@@ -449,6 +613,11 @@ Use --json for machine-readable output suitable for agent consumption.`,
 					for _, e := range result.Excluded {
 						fmt.Printf("  - %s (conflicts with %s)\n", e.Behavior.Name, e.ConflictsWith)
 					}
+					fmt.Println()
+				}
+
+				if len(result.Blocked) > 0 {
+					printBlocked(result.Blocked)
 				}
 			}
 
@@ -457,8 +626,170 @@ Use --json for machine-readable output suitable for agent consumption.`,
 	}
 
 	cmd.Flags().String("file", "", "Current file path")
+	cmd.Flags().StringSlice("files", nil, "Files in the current changeset (comma-separated)")
 	cmd.Flags().String("task", "", "Current task type")
 	cmd.Flags().String("env", "", "Environment (dev, staging, prod)")
+	cmd.Flags().String("agent", "", "Agent client name (e.g. claude-code, cursor; default: $FLOOP_AGENT)")
+	cmd.Flags().StringSlice("tags", nil, "Tags for the current work, matched by 'tags' when-conditions (e.g. testing/unit; default: $FLOOP_TAGS)")
+	cmd.Flags().StringSlice("kinds", nil, "Only include these behavior kinds (e.g. constraint,directive)")
+	cmd.Flags().StringSlice("exclude-kinds", nil, "Exclude these behavior kinds (e.g. episodic)")
+	cmd.Flags().Bool("no-daemon", false, "Evaluate directly even if 'floop watch' is running")
+	cmd.Flags().Bool("git-context", false, "Capture staged files, changed paths, and the commit message in progress from git")
 
 	return cmd
 }
+
+// activeQuery is one 'floop active' evaluation. 'floop watch' serves the
+// same queries, JSON-encoded, over its socket.
+type activeQuery struct {
+	File         string   `json:"file,omitempty"`
+	Files        []string `json:"files,omitempty"`
+	Task         string   `json:"task,omitempty"`
+	Env          string   `json:"env,omitempty"`
+	Agent        string   `json:"agent,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	Kinds        []string `json:"kinds,omitempty"`
+	ExcludeKinds []string `json:"exclude_kinds,omitempty"`
+	GitContext   bool     `json:"git_context,omitempty"`
+}
+
+// validate checks the query's kind filter and changeset size.
+func (q activeQuery) validate() error {
+	if _, err := activation.ParseKindFilter(q.Kinds, q.ExcludeKinds); err != nil {
+		return err
+	}
+	if len(q.Files) > constants.MaxChangesetFiles {
+		return fmt.Errorf("too many files: %d (max %d)", len(q.Files), constants.MaxChangesetFiles)
+	}
+	return nil
+}
+
+// activeEvaluation is the outcome of an activeQuery.
+type activeEvaluation struct {
+	ctx         models.ContextSnapshot
+	result      activation.ResolveResult
+	changeset   []string
+	triggeredBy map[string][]string
+}
+
+// evaluateActive evaluates which of behaviors are active for q. A changeset
+// evaluates each file on its own and unions the results.
+func evaluateActive(root string, behaviors []models.Behavior, q activeQuery) (*activeEvaluation, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	kindFilter, _ := activation.ParseKindFilter(q.Kinds, q.ExcludeKinds)
+
+	eval := &activeEvaluation{}
+	if len(q.Files) > 0 {
+		eval.changeset = uniqueFiles(append([]string{q.File}, q.Files...))
+	}
+
+	buildContext := func(file string, gitContext bool) models.ContextSnapshot {
+		return activation.NewContextBuilder().
+			WithFile(file).
+			WithTask(q.Task).
+			WithEnvironment(q.Env).
+			WithAgent(q.Agent).
+			WithTags(q.Tags).
+			WithRepoRoot(root).
+			WithContentSniffing(sniffContentEnabled()).
+			WithGitContext(gitContext).
+			Build()
+	}
+
+	evaluator := newEvaluator()
+	var matches []activation.ActivationResult
+	if len(eval.changeset) > 0 {
+		perFile := make([][]activation.ActivationResult, len(eval.changeset))
+		for i, f := range eval.changeset {
+			// The working tree state is the same for every file; capture it once
+			fileCtx := buildContext(f, q.GitContext && i == 0)
+			if i == 0 {
+				eval.ctx = fileCtx
+			} else {
+				fileCtx.StagedFiles = eval.ctx.StagedFiles
+				fileCtx.ChangedPaths = eval.ctx.ChangedPaths
+				fileCtx.CommitMessage = eval.ctx.CommitMessage
+			}
+			perFile[i] = evaluator.Evaluate(fileCtx, behaviors)
+		}
+		matches, eval.triggeredBy = activation.MergeFileMatches(eval.changeset, perFile)
+	} else {
+		eval.ctx = buildContext(q.File, q.GitContext)
+		matches = evaluator.Evaluate(eval.ctx, behaviors)
+	}
+
+	// Resolve conflicts, then narrow to the requested kinds
+	eval.result = activation.NewResolver().WithBehaviors(behaviors).Resolve(matches)
+	eval.result.Active = kindFilter.Apply(eval.result.Active)
+	return eval, nil
+}
+
+// jsonOutput is the 'floop active --json' document for the evaluation.
+func (e *activeEvaluation) jsonOutput() map[string]interface{} {
+	out := map[string]interface{}{
+		"context":    e.ctx,
+		"active":     e.result.Active,
+		"overridden": e.result.Overridden,
+		"excluded":   e.result.Excluded,
+		"blocked":    e.result.Blocked,
+		"count":      len(e.result.Active),
+	}
+	if len(e.changeset) > 0 {
+		activeTriggers := make(map[string][]string, len(e.result.Active))
+		for _, b := range e.result.Active {
+			activeTriggers[b.ID] = e.triggeredBy[b.ID]
+		}
+		out["files"] = e.changeset
+		out["triggered_by"] = activeTriggers
+	}
+	return out
+}
+
+// availableScope returns the scope covering the stores that exist under
+// root, or false if neither the local nor the global store does.
+func availableScope(root string) (constants.Scope, bool) {
+	hasLocal := true
+	if _, err := os.Stat(filepath.Join(root, ".floop")); err != nil {
+		hasLocal = false
+	}
+
+	hasGlobal := true
+	if globalPath, err := store.GlobalFloopPath(); err != nil {
+		hasGlobal = false
+	} else if _, err := os.Stat(globalPath); err != nil {
+		hasGlobal = false
+	}
+
+	switch {
+	case hasLocal && hasGlobal:
+		return constants.ScopeBoth, true
+	case hasLocal:
+		return constants.ScopeLocal, true
+	case hasGlobal:
+		return constants.ScopeGlobal, true
+	}
+	return "", false
+}
+
+// uniqueFiles returns files without blanks or repeats, in order.
+func uniqueFiles(files []string) []string {
+	seen := make(map[string]bool, len(files))
+	var out []string
+	for _, f := range files {
+		if f != "" && !seen[f] {
+			seen[f] = true
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// printBlocked lists behaviors held back by unmet requirements.
+func printBlocked(blocked []activation.BlockedInfo) {
+	fmt.Printf("Blocked by requirements (%d):\n", len(blocked))
+	for _, b := range blocked {
+		fmt.Printf("  - %s (%s)\n", b.Behavior.Name, b.Reason)
+	}
+}
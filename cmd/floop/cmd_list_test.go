@@ -2,14 +2,19 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/nvandessel/floop/internal/corrections"
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
 )
 
 func TestListCorrectionsWithData(t *testing.T) {
@@ -37,7 +42,7 @@ func TestListCorrectionsWithData(t *testing.T) {
 
 	// Test human output
 	var buf bytes.Buffer
-	err := listCorrections(&buf, tmpDir, false)
+	err := listCorrections(context.Background(), &buf, tmpDir, corrections.Filter{}, false)
 	if err != nil {
 		t.Fatalf("listCorrections failed: %v", err)
 	}
@@ -51,7 +56,7 @@ func TestListCorrectionsWithData(t *testing.T) {
 
 	// Test JSON output
 	var jsonBuf bytes.Buffer
-	err = listCorrections(&jsonBuf, tmpDir, true)
+	err = listCorrections(context.Background(), &jsonBuf, tmpDir, corrections.Filter{}, true)
 	if err != nil {
 		t.Fatalf("listCorrections JSON failed: %v", err)
 	}
@@ -77,7 +82,7 @@ func TestListCorrectionsEmptyFile(t *testing.T) {
 	os.WriteFile(filepath.Join(floopDir, "corrections.jsonl"), []byte(""), 0600)
 
 	var buf bytes.Buffer
-	err := listCorrections(&buf, tmpDir, false)
+	err := listCorrections(context.Background(), &buf, tmpDir, corrections.Filter{}, false)
 	if err != nil {
 		t.Fatalf("listCorrections on empty file failed: %v", err)
 	}
@@ -233,3 +238,131 @@ func TestListCmdWithInitializedLocalStore(t *testing.T) {
 		t.Fatalf("list --local failed: %v", err)
 	}
 }
+
+func TestListCmdPages(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	s, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	for _, id := range []string{"b-1", "b-2", "b-3"} {
+		b := &models.Behavior{
+			ID:         id,
+			Name:       "behavior " + id,
+			Kind:       models.BehaviorKindDirective,
+			Content:    models.BehaviorContent{Canonical: "Canonical of " + id},
+			Confidence: 0.8,
+			Provenance: models.Provenance{SourceType: models.SourceTypeAuthored, CreatedAt: time.Now()},
+		}
+		if _, err := s.AddNode(context.Background(), models.BehaviorToNode(b)); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+	s.Close()
+
+	list := func(args ...string) map[string]interface{} {
+		t.Helper()
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newListCmd())
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs(append([]string{"list", "--local", "--json", "--root", tmpDir}, args...))
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("list %v failed: %v", args, err)
+		}
+		var result map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+			t.Fatalf("failed to parse JSON: %v\n%s", err, buf.String())
+		}
+		return result
+	}
+
+	first := list("--limit", "2")
+	if first["count"] != float64(2) || first["next_cursor"] != "b-2" {
+		t.Fatalf("first page = count %v, next_cursor %v; want 2 and b-2", first["count"], first["next_cursor"])
+	}
+	last := list("--limit", "2", "--cursor", "b-2")
+	if last["count"] != float64(1) || last["next_cursor"] != nil {
+		t.Errorf("last page = count %v, next_cursor %v; want 1 and none", last["count"], last["next_cursor"])
+	}
+	behaviors, _ := last["behaviors"].([]interface{})
+	if len(behaviors) != 1 || behaviors[0].(map[string]interface{})["id"] != "b-3" {
+		t.Errorf("last page behaviors = %v, want b-3", behaviors)
+	}
+}
+
+func TestListCmd_CorrectionsFiltered(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	now := time.Now()
+	var logged []models.Correction
+	for i := 0; i < 5; i++ {
+		logged = append(logged, models.Correction{
+			ID:              fmt.Sprintf("c-%d", i),
+			Timestamp:       now.Add(time.Duration(i-5) * 24 * time.Hour),
+			AgentAction:     "wrong",
+			CorrectedAction: fmt.Sprintf("right %d", i),
+			Processed:       i%2 == 1,
+		})
+	}
+	if err := corrections.Append(filepath.Join(tmpDir, ".floop"), logged...); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	list := func(args ...string) map[string]interface{} {
+		t.Helper()
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newListCmd())
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs(append([]string{"list", "--corrections", "--json", "--root", tmpDir}, args...))
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("list %v failed: %v", args, err)
+		}
+		var result map[string]interface{}
+		if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+			t.Fatalf("invalid JSON %q: %v", out.String(), err)
+		}
+		return result
+	}
+	ids := func(result map[string]interface{}) []string {
+		var out []string
+		for _, c := range result["corrections"].([]interface{}) {
+			out = append(out, c.(map[string]interface{})["id"].(string))
+		}
+		return out
+	}
+
+	if got := ids(list("--since", "4d", "--unprocessed")); !reflect.DeepEqual(got, []string{"c-2", "c-4"}) {
+		t.Errorf("--since 4d --unprocessed = %v, want [c-2 c-4]", got)
+	}
+	if got := ids(list("--until", now.Add(-3*24*time.Hour).Format(time.RFC3339), "--processed")); !reflect.DeepEqual(got, []string{"c-1"}) {
+		t.Errorf("--until 3 days ago --processed = %v, want [c-1]", got)
+	}
+
+	first := list("--limit", "2")
+	if got := ids(first); !reflect.DeepEqual(got, []string{"c-0", "c-1"}) || first["total"] != float64(5) {
+		t.Fatalf("first page = %v, total %v", got, first["total"])
+	}
+	second := list("--limit", "2", "--cursor", first["next_cursor"].(string))
+	if got := ids(second); !reflect.DeepEqual(got, []string{"c-2", "c-3"}) || second["next_cursor"] == nil {
+		t.Errorf("second page = %v, next %v", got, second["next_cursor"])
+	}
+
+	for _, args := range [][]string{
+		{"list", "--corrections", "--processed", "--unprocessed", "--root", tmpDir},
+		{"list", "--corrections", "--since", "last week", "--root", tmpDir},
+		{"list", "--unprocessed", "--root", tmpDir},
+	} {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newListCmd())
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetArgs(args)
+		if err := rootCmd.Execute(); err == nil {
+			t.Errorf("%v should fail", args[1:])
+		}
+	}
+}
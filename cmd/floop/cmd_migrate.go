@@ -68,7 +68,7 @@ func runMigrate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("creating global .floop directory: %w", err)
 	}
 
-	globalStore, err := store.NewSQLiteGraphStore(globalRoot)
+	globalStore, err := store.NewGlobalGraphStore(globalRoot)
 	if err != nil {
 		return fmt.Errorf("opening global store: %w", err)
 	}
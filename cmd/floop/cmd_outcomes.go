@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/outcome"
+	"github.com/nvandessel/floop/internal/session"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// outcomeReportEntry is one behavior's row in the outcome report.
+type outcomeReportEntry struct {
+	BehaviorID  string               `json:"behavior_id"`
+	Name        string               `json:"name,omitempty"`
+	Positive    int                  `json:"positive"`
+	Negative    int                  `json:"negative"`
+	SuccessRate float64              `json:"success_rate"`
+	ByKind      map[outcome.Kind]int `json:"by_kind"`
+	LastAt      time.Time            `json:"last_at"`
+}
+
+func newOutcomesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "outcomes",
+		Short: "Report downstream results linked to behaviors",
+		Long: `Show how the work went when each behavior was active: outcomes such as
+tests passing, a PR merging, or a bug being reopened, recorded with
+'floop outcomes record' and linked to the behaviors active in a session.
+
+Outcomes also feed the recalibrate step of 'floop consolidate sleep' and the
+outcome columns of 'floop stats'.
+
+Examples:
+  floop outcomes                                         # Per-behavior outcome report
+  floop outcomes record tests-passed --session-id abc123  # Link to a session's behaviors
+  floop outcomes record bug-reopened --behavior b-1 --ref https://example.com/issues/7`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			events, err := outcome.Load(filepath.Join(root, ".floop"))
+			if err != nil {
+				return err
+			}
+			entries := outcomeReport(root, outcome.Aggregate(events))
+
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"outcomes":  len(events),
+					"behaviors": entries,
+				})
+			}
+
+			if len(events) == 0 {
+				fmt.Println("No outcomes recorded.")
+				return nil
+			}
+
+			fmt.Printf("Outcomes: %d linked to %d behaviors\n\n", len(events), len(entries))
+			fmt.Printf("%-24s %-30s %5s %5s %6s\n", "ID", "Name", "Good", "Bad", "Rate")
+			fmt.Println(repeatChar('-', 74))
+			for _, e := range entries {
+				fmt.Printf("%-24s %-30s %5d %5d %5.0f%%\n",
+					truncatePreview(e.BehaviorID, 24), truncatePreview(e.Name, 30), e.Positive, e.Negative, e.SuccessRate*100)
+			}
+			return nil
+		},
+	}
+
+	cmd.AddCommand(newOutcomesRecordCmd())
+	return cmd
+}
+
+func newOutcomesRecordCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "record <kind>",
+		Short: "Record an outcome for the behaviors active in a session",
+		Long: `Record a downstream result and link it to the behaviors injected during a
+session (as tracked by 'floop activate' and the hooks), plus any given with
+--behavior.
+
+Kinds: tests-passed, build-passed, pr-merged (good);
+       tests-failed, build-failed, pr-rejected, bug-reopened, reverted (bad)`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			sessionID, _ := cmd.Flags().GetString("session-id")
+			behaviorIDs, _ := cmd.Flags().GetStringSlice("behavior")
+			ref, _ := cmd.Flags().GetString("ref")
+			note, _ := cmd.Flags().GetString("note")
+
+			kind, err := outcome.ParseKind(args[0])
+			if err != nil {
+				return err
+			}
+			floopDir := filepath.Join(root, ".floop")
+			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
+				return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+			}
+
+			linked := append([]string(nil), behaviorIDs...)
+			if sessionID != "" {
+				state, err := session.LoadState(sessionStateDir(sessionID))
+				if err != nil {
+					return fmt.Errorf("failed to load session %s: %w", sessionID, err)
+				}
+				linked = append(linked, state.InjectedIDs()...)
+			}
+
+			event := outcome.NewEvent(kind, sessionID, linked, ref, note, time.Now())
+			if len(event.BehaviorIDs) == 0 {
+				return fmt.Errorf("no behaviors to link: session %q injected none; pass --behavior", sessionID)
+			}
+			if err := outcome.Record(floopDir, event); err != nil {
+				return err
+			}
+
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"status":  "recorded",
+					"outcome": event,
+				})
+			}
+			fmt.Printf("Recorded %s for %d behavior(s).\n", event.Kind, len(event.BehaviorIDs))
+			return nil
+		},
+	}
+
+	cmd.Flags().String("session-id", "default", "Session whose injected behaviors the outcome is linked to (empty for none)")
+	cmd.Flags().StringSlice("behavior", nil, "Behavior IDs to link in addition to the session's (repeatable)")
+	cmd.Flags().String("ref", "", "Reference for the outcome, e.g. a PR URL or commit")
+	cmd.Flags().String("note", "", "Free-form detail")
+	return cmd
+}
+
+// outcomeReport builds the per-behavior report, best success rate first,
+// naming behaviors found in the store.
+func outcomeReport(root string, stats map[string]outcome.Stats) []outcomeReportEntry {
+	names := make(map[string]string)
+	if len(stats) > 0 {
+		if graphStore, err := store.NewMultiGraphStore(root); err == nil {
+			ctx := context.Background()
+			for id := range stats {
+				if node, err := graphStore.GetNode(ctx, id); err == nil && node != nil {
+					names[id] = models.NodeToBehavior(*node).Name
+				}
+			}
+			graphStore.Close()
+		}
+	}
+
+	entries := make([]outcomeReportEntry, 0, len(stats))
+	for id, s := range stats {
+		entries = append(entries, outcomeReportEntry{
+			BehaviorID:  id,
+			Name:        names[id],
+			Positive:    s.Positive,
+			Negative:    s.Negative,
+			SuccessRate: s.SuccessRate(),
+			ByKind:      s.ByKind,
+			LastAt:      s.LastAt,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].SuccessRate != entries[j].SuccessRate {
+			return entries[i].SuccessRate > entries[j].SuccessRate
+		}
+		if entries[i].Positive+entries[i].Negative != entries[j].Positive+entries[j].Negative {
+			return entries[i].Positive+entries[i].Negative > entries[j].Positive+entries[j].Negative
+		}
+		return entries[i].BehaviorID < entries[j].BehaviorID
+	})
+	return entries
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/outcome"
+	"github.com/nvandessel/floop/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// outcomesTestCmds returns fresh commands for the tests in this file.
+func outcomesTestCmds() []*cobra.Command {
+	return []*cobra.Command{newInitCmd(), newOutcomesCmd()}
+}
+
+func TestOutcomesCmd_RecordAndReport(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	if _, err := runTestCmd(t, outcomesTestCmds(), "init", "--root", tmpDir); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if _, err := runTestCmd(t, outcomesTestCmds(), "outcomes", "--root", tmpDir); err != nil {
+		t.Fatalf("outcomes (empty) failed: %v", err)
+	}
+
+	// A session that injected two behaviors
+	state := session.NewState(session.DefaultConfig())
+	state.RecordInjection("b-1", models.TierFull, 0.9, 10)
+	state.RecordInjection("b-2", models.TierSummary, 0.6, 5)
+	sessionDir := sessionStateDir("outcome-test")
+	if err := os.MkdirAll(sessionDir, 0700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := session.SaveState(state, sessionDir); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	if _, err := runTestCmd(t, outcomesTestCmds(), "outcomes", "record", "tests-passed", "--session-id", "outcome-test", "--root", tmpDir); err != nil {
+		t.Fatalf("outcomes record (session) failed: %v", err)
+	}
+	if _, err := runTestCmd(t, outcomesTestCmds(), "outcomes", "record", "reverted", "--session-id", "", "--behavior", "b-2", "--root", tmpDir); err != nil {
+		t.Fatalf("outcomes record (behavior) failed: %v", err)
+	}
+	if _, err := runTestCmd(t, outcomesTestCmds(), "outcomes", "record", "shipped", "--behavior", "b-1", "--root", tmpDir); err == nil {
+		t.Error("invalid outcome kind should fail")
+	}
+	if _, err := runTestCmd(t, outcomesTestCmds(), "outcomes", "record", "tests-passed", "--session-id", "empty-session", "--root", tmpDir); err == nil {
+		t.Error("recording with no behaviors to link should fail")
+	}
+
+	stats, err := outcome.LoadStats(filepath.Join(tmpDir, ".floop"))
+	if err != nil {
+		t.Fatalf("LoadStats() error = %v", err)
+	}
+	if stats["b-1"].Positive != 1 || stats["b-2"].Positive != 1 || stats["b-2"].Negative != 1 {
+		t.Errorf("stats = %+v, want b-1 good, b-2 good and bad", stats)
+	}
+
+	out, err := runTestCmd(t, outcomesTestCmds(), "outcomes", "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("outcomes --json failed: %v", err)
+	}
+	var report struct {
+		Outcomes  int                  `json:"outcomes"`
+		Behaviors []outcomeReportEntry `json:"behaviors"`
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("outcomes --json = %s: %v", out, err)
+	}
+	if report.Outcomes != 2 || len(report.Behaviors) != 2 || report.Behaviors[0].BehaviorID != "b-1" {
+		t.Errorf("report = %+v, want b-1 (100%%) ranked first", report)
+	}
+}
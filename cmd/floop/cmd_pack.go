@@ -15,14 +15,23 @@ import (
 
 func newPackCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "pack",
-		Short: "Manage skill packs (create, install, list, remove)",
+		Use:     "pack",
+		Aliases: []string{"packs"},
+		Short:   "Manage skill packs (create, install, list, remove)",
 		Long: `Skill packs are portable behavior collections that can be shared and installed.
 
+Starter packs for common stacks (go-backend, python, react) ship with
+floop, and curated packs published in a registry bootstrap an empty store
+without waiting for corrections to accumulate. Installed behaviors carry
+source_type: pack, so 'floop pack remove' takes them out again.
+
 Examples:
   floop pack create my-pack.fpack --id my-org/my-pack --version 1.0.0
   floop pack install my-pack.fpack
+  floop pack install go-backend
+  floop pack install go-standards@1
   floop pack list
+  floop pack list --available
   floop pack info my-org/my-pack
   floop pack remove my-org/my-pack`,
 	}
@@ -141,20 +150,31 @@ Examples:
 func newPackInstallCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "install <source>",
-		Short: "Install a skill pack from a file, URL, or GitHub repo",
+		Short: "Install a skill pack from a file, URL, GitHub repo, or registry",
 		Long: `Install behaviors from a skill pack into the store.
 
-Supports local files, HTTP URLs, and GitHub shorthand sources.
+Supports local files, HTTP URLs, GitHub shorthand sources, starter packs,
+and registry packs. A bare name[@version] names one of the starter packs
+built into floop (go-backend, python, react; see 'floop pack list
+--available'), or else is looked up in the configured registries
+(packs.registries). With --registry only that registry is searched; "@1"
+selects the highest 1.x version. Registry packs must match their published checksum
+and carry a valid signature from the registry's public key unless
+--allow-unsigned is set.
+
 Follows the seeder pattern: forgotten behaviors are not re-added,
 existing behaviors are version-gated for updates, and provenance
-is stamped on each installed behavior.
+(source_type: pack) is stamped on each installed behavior.
 
 Examples:
   floop pack install my-pack.fpack
   floop pack install https://example.com/pack.fpack
+  floop pack install go-backend
   floop pack install gh:owner/repo
   floop pack install gh:owner/repo@v1.0.0
-  floop pack install gh:owner/repo --all-assets`,
+  floop pack install gh:owner/repo --all-assets
+  floop pack install go-standards@1
+  floop pack install go-standards --registry ./registry --allow-unsigned`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			source := args[0]
@@ -178,6 +198,7 @@ Examples:
 			results, err := pack.InstallFromSource(ctx, graphStore, source, cfg, pack.InstallFromSourceOptions{
 				DeriveEdges: deriveEdges,
 				AllAssets:   allAssets,
+				Registry:    packRegistryOptions(cmd),
 			})
 			if err != nil {
 				return fmt.Errorf("pack install failed: %w", err)
@@ -224,27 +245,57 @@ Examples:
 
 	cmd.Flags().Bool("derive-edges", false, "Automatically derive edges between pack behaviors and existing behaviors")
 	cmd.Flags().Bool("all-assets", false, "Install all .fpack assets from a multi-asset release")
+	addPackRegistryFlags(cmd)
 
 	return cmd
 }
 
+// addPackRegistryFlags adds the flags selecting and trusting a pack registry.
+func addPackRegistryFlags(cmd *cobra.Command) {
+	cmd.Flags().String("registry", "", "Registry name from config, or a registry URL or directory")
+	cmd.Flags().String("public-key", "", "Base64 ed25519 key verifying registry pack signatures")
+	cmd.Flags().Bool("allow-unsigned", false, "Install registry packs without a verifiable signature")
+}
+
+// packRegistryOptions reads the flags added by addPackRegistryFlags.
+func packRegistryOptions(cmd *cobra.Command) pack.RegistryOptions {
+	registry, _ := cmd.Flags().GetString("registry")
+	publicKey, _ := cmd.Flags().GetString("public-key")
+	allowUnsigned, _ := cmd.Flags().GetBool("allow-unsigned")
+	return pack.RegistryOptions{
+		Registry:      registry,
+		PublicKey:     publicKey,
+		AllowUnsigned: allowUnsigned,
+	}
+}
+
 func newPackListCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "list",
-		Short: "List installed skill packs",
-		Long: `Show all currently installed skill packs from config.
+		Short: "List installed or available skill packs",
+		Long: `Show all currently installed skill packs from config, or with
+--available the starter packs built into floop and the packs offered by
+the configured registries.
 
 Examples:
   floop pack list
-  floop pack list --json`,
+  floop pack list --json
+  floop pack list --available
+  floop pack list --available --registry ./registry`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			jsonOut, _ := cmd.Flags().GetBool("json")
+			availableOnly, _ := cmd.Flags().GetBool("available")
 
 			cfg, err := config.Load()
 			if err != nil {
 				cfg = config.Default()
 			}
 
+			if availableOnly {
+				registry, _ := cmd.Flags().GetString("registry")
+				return listAvailablePacks(cfg, registry, jsonOut)
+			}
+
 			installed := pack.ListInstalled(cfg)
 
 			if jsonOut {
@@ -270,9 +321,87 @@ Examples:
 		},
 	}
 
+	cmd.Flags().Bool("available", false, "List packs offered by registries instead of installed packs")
+	cmd.Flags().String("registry", "", "Registry name from config, or a registry URL or directory")
+
 	return cmd
 }
 
+// listAvailablePacks prints the starter packs and the packs offered by the
+// selected registries. Starter packs are left out when --registry names one.
+func listAvailablePacks(cfg *config.FloopConfig, registry string, jsonOut bool) error {
+	var starters []pack.StarterPack
+	if registry == "" {
+		var err error
+		if starters, err = pack.StarterPacks(); err != nil {
+			return err
+		}
+	}
+
+	var available []pack.AvailablePack
+	if registry != "" || len(cfg.Packs.Registries) > 0 {
+		var err error
+		available, err = pack.ListAvailable(context.Background(), cfg, pack.RegistryOptions{Registry: registry})
+		if err != nil {
+			return fmt.Errorf("listing registry packs: %w", err)
+		}
+	}
+
+	installed := make(map[string]string, len(cfg.Packs.Installed))
+	for _, p := range cfg.Packs.Installed {
+		installed[p.ID] = p.Version
+	}
+
+	if jsonOut {
+		return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+			"starter":   starters,
+			"available": available,
+			"count":     len(starters) + len(available),
+		})
+	}
+
+	if len(starters) == 0 && len(available) == 0 {
+		fmt.Println("No packs available.")
+		return nil
+	}
+
+	if len(starters) > 0 {
+		fmt.Printf("Starter packs (%d, built in):\n", len(starters))
+		for _, p := range starters {
+			line := fmt.Sprintf("  %s@%s (%s, %d behaviors)", p.Name, p.Version, p.ID, p.Behaviors)
+			if v, ok := installed[p.ID]; ok {
+				line += fmt.Sprintf(" [installed v%s]", v)
+			}
+			fmt.Println(line)
+			if p.Description != "" {
+				fmt.Printf("    %s\n", p.Description)
+			}
+		}
+		if len(available) > 0 {
+			fmt.Println()
+		}
+	}
+	if len(available) == 0 {
+		return nil
+	}
+
+	fmt.Printf("Available packs (%d):\n", len(available))
+	for _, p := range available {
+		line := fmt.Sprintf("  %s@%s (%s, registry %s)", p.Name, p.Latest, p.ID, p.Registry)
+		if !p.Signed {
+			line += " [unsigned]"
+		}
+		if v, ok := installed[p.ID]; ok {
+			line += fmt.Sprintf(" [installed v%s]", v)
+		}
+		fmt.Println(line)
+		if p.Description != "" {
+			fmt.Printf("    %s\n", p.Description)
+		}
+	}
+	return nil
+}
+
 func newPackInfoCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "info <pack-id>",
@@ -403,6 +532,7 @@ Examples:
 
 			opts := pack.InstallFromSourceOptions{
 				DeriveEdges: deriveEdges,
+				Registry:    packRegistryOptions(cmd),
 			}
 
 			// Collect (source, packID) pairs to update
@@ -535,6 +665,7 @@ Examples:
 
 	cmd.Flags().Bool("derive-edges", false, "Automatically derive edges between pack behaviors and existing behaviors")
 	cmd.Flags().Bool("all", false, "Update all installed packs that have remote sources")
+	addPackRegistryFlags(cmd)
 
 	return cmd
 }
@@ -544,10 +675,15 @@ func newPackRemoveCmd() *cobra.Command {
 		Use:   "remove <pack-id>",
 		Short: "Remove an installed skill pack",
 		Long: `Remove a pack by marking its behaviors as forgotten and removing
-the pack from the installed packs list.
+the pack from the installed packs list. Forgotten behaviors stay out
+if the pack is installed again.
+
+With --purge, the pack's behaviors and their edges are deleted instead,
+leaving the store as it was before the pack was installed.
 
 Examples:
   floop pack remove my-org/my-pack
+  floop pack remove my-org/my-pack --purge
   floop pack remove my-org/my-pack --json`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -567,7 +703,15 @@ Examples:
 			}
 			defer graphStore.Close()
 
-			result, err := pack.Remove(ctx, graphStore, packID, cfg)
+			purge, _ := cmd.Flags().GetBool("purge")
+			remove := pack.Remove
+			action := "marked as forgotten"
+			if purge {
+				remove = pack.Purge
+				action = "deleted"
+			}
+
+			result, err := remove(ctx, graphStore, packID, cfg)
 			if err != nil {
 				return fmt.Errorf("pack remove failed: %w", err)
 			}
@@ -580,16 +724,19 @@ Examples:
 				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
 					"pack_id":           result.PackID,
 					"behaviors_removed": result.BehaviorsRemoved,
-					"message":           fmt.Sprintf("Removed %s: %d behaviors marked as forgotten", result.PackID, result.BehaviorsRemoved),
+					"purged":            purge,
+					"message":           fmt.Sprintf("Removed %s: %d behaviors %s", result.PackID, result.BehaviorsRemoved, action),
 				})
 			}
 
 			fmt.Printf("Removed %s\n", result.PackID)
-			fmt.Printf("  Behaviors marked as forgotten: %d\n", result.BehaviorsRemoved)
+			fmt.Printf("  Behaviors %s: %d\n", action, result.BehaviorsRemoved)
 			return nil
 		},
 	}
 
+	cmd.Flags().Bool("purge", false, "Delete the pack's behaviors and edges instead of marking them forgotten")
+
 	return cmd
 }
 
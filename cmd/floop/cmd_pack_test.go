@@ -295,6 +295,41 @@ func TestPackInstallJSON(t *testing.T) {
 	}
 }
 
+func TestPackInstallStarter(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newPackCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"pack", "install", "react", "--json", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("pack install react failed: %v", err)
+	}
+
+	// Starter packs remove by ID like any other pack
+	rootCmd2 := newTestRootCmd()
+	rootCmd2.AddCommand(newPackCmd())
+	rootCmd2.SetOut(&bytes.Buffer{})
+	rootCmd2.SetArgs([]string{"pack", "remove", "floop/react", "--json", "--root", tmpDir})
+	if err := rootCmd2.Execute(); err != nil {
+		t.Fatalf("pack remove floop/react failed: %v", err)
+	}
+}
+
+func TestPackListAvailableStarters(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newPackCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"pack", "list", "--available", "--root", tmpDir})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("pack list --available failed: %v", err)
+	}
+}
+
 func TestPackRemoveNotInstalled(t *testing.T) {
 	tmpDir := t.TempDir()
 	isolateHome(t, tmpDir)
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/project"
+	"github.com/nvandessel/floop/internal/promote"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newPromoteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "promote [behavior-id]",
+		Short: "Promote a proven local behavior to the global store",
+		Long: `Promote a local behavior into the global store (~/.floop/) so every
+project benefits from it.
+
+A behavior must have been confirmed --min-confirmations times (default 3)
+before it can be promoted; --force skips the check. By default the global
+store gets a copy and the local behavior is deprecated with a link to it,
+so the local copy defers to the global one and 'floop restore' can bring it
+back. With --move the behavior keeps its ID and leaves the local store.
+If the global store already holds a behavior with the same content, the
+local behavior defers to that one instead. A restore point is saved first.
+
+--suggest lists the local behaviors ready for promotion: at least
+--min-confirmations confirmations, confidence of at least 0.7, and no more
+than 20% of their feedback being overrides.
+
+Examples:
+  floop promote --suggest               # List behaviors ready for promotion
+  floop promote behavior-abc123         # Copy to global, local defers to it
+  floop promote behavior-abc123 --move  # Move to global, keeping the ID
+  floop promote use-gofmt --force       # Promote before it has enough confirmations`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			suggest, _ := cmd.Flags().GetBool("suggest")
+			move, _ := cmd.Flags().GetBool("move")
+			force, _ := cmd.Flags().GetBool("force")
+			minConfirmations, _ := cmd.Flags().GetInt("min-confirmations")
+
+			if suggest == (len(args) == 1) {
+				return fmt.Errorf("give a behavior ID or --suggest")
+			}
+			if minConfirmations < 1 {
+				return fmt.Errorf("--min-confirmations must be at least 1")
+			}
+
+			floopDir := filepath.Join(root, ".floop")
+			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
+				return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+			}
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			ctx := context.Background()
+
+			if suggest {
+				thresholds := promote.DefaultThresholds()
+				thresholds.MinConfirmations = minConfirmations
+				suggestions, err := promote.Suggest(ctx, graphStore, thresholds)
+				if err != nil {
+					return err
+				}
+				if jsonOut {
+					return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+						"suggestions": suggestions,
+						"count":       len(suggestions),
+					})
+				}
+				if len(suggestions) == 0 {
+					fmt.Println("No local behaviors are ready for promotion.")
+					return nil
+				}
+				fmt.Printf("%d local behavior(s) ready for promotion:\n", len(suggestions))
+				for _, s := range suggestions {
+					fmt.Printf("  %s (%s): %d confirmed, %d overridden, confidence %.2f\n",
+						s.Name, s.ID, s.Confirmations, s.Overrides, s.Confidence)
+				}
+				fmt.Println("\nPromote one with 'floop promote <behavior-id>'.")
+				return nil
+			}
+
+			// The behavior may be given by name or slug
+			id := args[0]
+			node, err := findCurationNode(ctx, graphStore, id, string(store.ScopeLocal))
+			if err != nil {
+				return err
+			}
+			if node != nil {
+				id = node.ID
+			}
+
+			projectID, _ := project.ResolveProjectID(root)
+			result, err := promote.Promote(ctx, graphStore, id, promote.Options{
+				MinConfirmations: minConfirmations,
+				Force:            force,
+				Move:             move,
+				Actor:            os.Getenv("USER"),
+				Project:          projectID,
+				Root:             root,
+			})
+			if err != nil {
+				if jsonOut {
+					return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+						"error": err.Error(),
+						"id":    id,
+					})
+				}
+				return err
+			}
+
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"status": "promoted",
+					"result": result,
+				})
+			}
+
+			switch {
+			case result.Existing:
+				fmt.Printf("The global store already holds '%s' as %s.\n", result.Name, result.GlobalID)
+			case result.Moved:
+				fmt.Printf("Behavior '%s' moved to the global store.\n", result.Name)
+			default:
+				fmt.Printf("Behavior '%s' promoted to the global store as %s.\n", result.Name, result.GlobalID)
+			}
+			if !result.Moved {
+				fmt.Printf("The local copy %s is deprecated and defers to it.\n", result.LocalID)
+			}
+			fmt.Printf("Restore point: %s\n", result.RestorePoint)
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("suggest", false, "List local behaviors ready for promotion")
+	cmd.Flags().Bool("move", false, "Move the behavior instead of leaving a deprecated local copy")
+	cmd.Flags().Bool("force", false, "Promote regardless of confirmations")
+	cmd.Flags().Int("min-confirmations", constants.DefaultPromoteMinConfirmations, "Confirmations needed to promote, and to be suggested")
+
+	return cmd
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/promote"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// promoteTestCmds returns fresh commands for the tests in this file.
+func promoteTestCmds() []*cobra.Command {
+	return []*cobra.Command{newInitCmd(), newLearnCmd(), newPromoteCmd()}
+}
+
+// confirmLocal sets the confirmation count of a local behavior.
+func confirmLocal(t *testing.T, root, id string, times int) {
+	t.Helper()
+	ctx := context.Background()
+	s, err := store.NewSQLiteGraphStore(root)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+	node, err := s.GetNode(ctx, id)
+	if err != nil || node == nil {
+		t.Fatalf("GetNode(%s) = %v, %v", id, node, err)
+	}
+	node.Metadata["confidence"] = 0.9
+	node.Metadata["stats"] = map[string]interface{}{"times_confirmed": times}
+	if err := s.UpdateNode(ctx, *node); err != nil {
+		t.Fatalf("UpdateNode() error = %v", err)
+	}
+}
+
+func TestPromoteCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	if _, err := runTestCmd(t, promoteTestCmds(), "init", "--root", tmpDir); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if _, err := runTestCmd(t, promoteTestCmds(), "learn", "--right", "wrap errors with %w", "--scope", "local", "--root", tmpDir); err != nil {
+		t.Fatalf("learn failed: %v", err)
+	}
+	s, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	nodes, _ := s.QueryNodes(context.Background(), map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	s.Close()
+	if len(nodes) != 1 {
+		t.Fatalf("learned %d behaviors, want 1", len(nodes))
+	}
+	id := nodes[0].ID
+
+	if _, err := runTestCmd(t, promoteTestCmds(), "promote", "--root", tmpDir); err == nil {
+		t.Error("promote without an ID or --suggest should fail")
+	}
+	if _, err := runTestCmd(t, promoteTestCmds(), "promote", id, "--root", tmpDir); err == nil || !strings.Contains(err.Error(), "needed for promotion") {
+		t.Errorf("promote of an unconfirmed behavior error = %v", err)
+	}
+
+	confirmLocal(t, tmpDir, id, 4)
+	out, err := runTestCmd(t, promoteTestCmds(), "promote", "--suggest", "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("promote --suggest failed: %v", err)
+	}
+	var suggested struct {
+		Suggestions []promote.Suggestion `json:"suggestions"`
+	}
+	if err := json.Unmarshal([]byte(out), &suggested); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if len(suggested.Suggestions) != 1 || suggested.Suggestions[0].ID != id {
+		t.Errorf("suggestions = %+v, want %s", suggested.Suggestions, id)
+	}
+
+	out, err = runTestCmd(t, promoteTestCmds(), "promote", id, "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("promote failed: %v", err)
+	}
+	var promoted struct {
+		Status string         `json:"status"`
+		Result promote.Result `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(out), &promoted); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if promoted.Status != "promoted" || promoted.Result.GlobalID != promote.GlobalID(id) {
+		t.Errorf("promote output = %+v", promoted)
+	}
+
+	graphStore, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("open stores: %v", err)
+	}
+	defer graphStore.Close()
+	local, _ := graphStore.LocalStore().GetNode(context.Background(), id)
+	global, _ := graphStore.GlobalStore().GetNode(context.Background(), promoted.Result.GlobalID)
+	if local == nil || local.Kind != store.NodeKindDeprecated || global == nil || global.Kind != store.NodeKindBehavior {
+		t.Errorf("after promote: local = %+v, global = %+v", local, global)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/nvandessel/floop/internal/assembly"
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/publish"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newPublishCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "publish",
+		Short: "Publish a read-only snapshot of the behavior graph",
+		Long: `Write a consistent, versioned snapshot of the behavior graph for other tools.
+
+Each run writes snapshots/<id>/ containing nodes.jsonl, edges.jsonl, a compiled
+prompt per common context (default, each language, each task), and a
+manifest.json with checksums. latest.json is updated last and points at the
+newest snapshot, so readers never see a partial publish.
+
+S3 targets use the standard AWS credential chain (environment, ~/.aws).
+
+Examples:
+  floop publish --to ./site/floop
+  floop publish --to s3://my-bucket/floop
+  floop publish --to ./out --format xml --order constraints-first`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			to, _ := cmd.Flags().GetString("to")
+			format, _ := cmd.Flags().GetString("format")
+			order, _ := cmd.Flags().GetString("order")
+
+			target, err := publish.ParseTarget(to)
+			if err != nil {
+				return err
+			}
+
+			var outputFormat assembly.Format
+			switch format {
+			case "markdown", "":
+				outputFormat = assembly.FormatMarkdown
+			case "xml":
+				outputFormat = assembly.FormatXML
+			case "plain":
+				outputFormat = assembly.FormatPlain
+			default:
+				return fmt.Errorf("invalid format: %s (valid: markdown, xml, plain)", format)
+			}
+
+			// Fall back to the configured ordering strategy when --order is not given
+			if order == "" {
+				if cfg, err := config.Load(); err == nil {
+					order = cfg.Prompt.Ordering
+				}
+			}
+			ordering, err := assembly.ParseOrderStrategy(order)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open store: %w", err)
+			}
+			defer graphStore.Close()
+
+			manifest, err := publish.Publish(ctx, graphStore, target, publish.Options{
+				Format:       outputFormat,
+				Ordering:     ordering,
+				FloopVersion: version,
+			})
+			if err != nil {
+				return fmt.Errorf("publish failed: %w", err)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"target":      target.String(),
+					"snapshot_id": manifest.SnapshotID,
+					"node_count":  manifest.NodeCount,
+					"edge_count":  manifest.EdgeCount,
+					"prompts":     manifest.Prompts,
+					"files":       manifest.Files,
+				})
+			}
+
+			fmt.Printf("Published snapshot %s: %d nodes, %d edges, %d prompts\n",
+				manifest.SnapshotID, manifest.NodeCount, manifest.EdgeCount, len(manifest.Prompts))
+			fmt.Printf("  Target: %s\n", target.String())
+			return nil
+		},
+	}
+
+	cmd.Flags().String("to", "", "Destination directory or s3://bucket/prefix (required)")
+	cmd.Flags().String("format", "markdown", "Prompt format: markdown, xml, plain")
+	cmd.Flags().String("order", "", "Prompt section ordering (default: prompt.ordering config)")
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/publish"
+)
+
+func TestPublishCmdToDir(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+	outDir := filepath.Join(tmpDir, "published")
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newPublishCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"publish", "--to", outDir, "--root", tmpDir})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outDir, publish.LatestFile))
+	if err != nil {
+		t.Fatalf("latest.json not written: %v", err)
+	}
+	var latest publish.Latest
+	if err := json.Unmarshal(data, &latest); err != nil {
+		t.Fatalf("failed to parse latest.json: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, filepath.FromSlash(latest.Manifest))); err != nil {
+		t.Errorf("manifest referenced by latest.json missing: %v", err)
+	}
+}
+
+func TestPublishCmdRequiresTarget(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newPublishCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"publish", "--root", tmpDir})
+
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error without --to")
+	}
+}
+
+func TestPublishCmdInvalidFormat(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newPublishCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"publish", "--to", filepath.Join(tmpDir, "out"), "--format", "yaml", "--root", tmpDir})
+
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error for invalid format")
+	}
+}
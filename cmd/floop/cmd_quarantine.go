@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/quarantine"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newQuarantineCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "quarantine",
+		Short: "Review behaviors quarantined as possible prompt injection",
+		Long: `List behaviors held back because their content looked like prompt
+injection: attempts to override earlier instructions, hijack the agent's
+role, extract the system prompt, fake chat-template markers, exfiltrate
+secrets, or hide text with invisible or lookalike Unicode characters.
+
+The learning loop quarantines suspicious behaviors as they are learned.
+'floop quarantine scan' checks behaviors that arrived another way, such as
+skill packs or imports. Quarantined behaviors are never injected.
+
+Release a reviewed behavior with 'floop restore <id>', or discard it with
+'floop forget <id>'.
+
+Examples:
+  floop quarantine              # List quarantined behaviors
+  floop quarantine scan         # Quarantine suspicious active behaviors
+  floop quarantine scan --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			graphStore, err := openQuarantineStore(root)
+			if err != nil {
+				return err
+			}
+			defer graphStore.Close()
+
+			entries, err := quarantine.Quarantined(context.Background(), graphStore)
+			if err != nil {
+				return err
+			}
+
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"quarantined": entries,
+					"count":       len(entries),
+				})
+			}
+
+			if len(entries) == 0 {
+				fmt.Println("No quarantined behaviors.")
+				return nil
+			}
+			printQuarantineEntries(entries)
+			fmt.Println("Release with 'floop restore <id>' after review, or discard with 'floop forget <id>'.")
+			return nil
+		},
+	}
+
+	cmd.AddCommand(newQuarantineScanCmd())
+	return cmd
+}
+
+func newQuarantineScanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scan",
+		Short: "Quarantine active behaviors that look like prompt injection",
+		Long: `Analyze every active behavior in the local and global stores and
+quarantine the suspicious ones. Use --dry-run to list them without changing
+anything.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			graphStore, err := openQuarantineStore(root)
+			if err != nil {
+				return err
+			}
+			defer graphStore.Close()
+
+			entries, err := quarantine.Scan(context.Background(), graphStore, time.Now(), dryRun)
+			if err != nil {
+				return err
+			}
+
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"dry_run":     dryRun,
+					"suspicious":  entries,
+					"quarantined": len(entries) > 0 && !dryRun,
+					"count":       len(entries),
+				})
+			}
+
+			if len(entries) == 0 {
+				fmt.Println("No suspicious behaviors found.")
+				return nil
+			}
+			printQuarantineEntries(entries)
+			if dryRun {
+				fmt.Printf("%d behavior(s) would be quarantined. Run without --dry-run to quarantine them.\n", len(entries))
+			} else {
+				fmt.Printf("Quarantined %d behavior(s). Release with 'floop restore <id>' after review.\n", len(entries))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("dry-run", false, "List suspicious behaviors without quarantining them")
+	return cmd
+}
+
+// printQuarantineEntries prints each entry with its findings.
+func printQuarantineEntries(entries []quarantine.Entry) {
+	for _, e := range entries {
+		fmt.Printf("%s  %s", e.BehaviorID, e.Name)
+		if e.Origin != "" {
+			fmt.Printf("  (%s)", e.Origin)
+		}
+		fmt.Println()
+		fmt.Printf("  Content: %s\n", truncatePreview(e.Canonical, 70))
+		for _, f := range e.Findings {
+			fmt.Printf("  - %s: %q\n", f.Rule, f.Excerpt)
+		}
+	}
+	fmt.Println()
+}
+
+// openQuarantineStore opens the graph store holding quarantined behaviors.
+func openQuarantineStore(root string) (*store.MultiGraphStore, error) {
+	if _, err := os.Stat(filepath.Join(root, ".floop")); os.IsNotExist(err) {
+		return nil, fmt.Errorf(".floop not initialized. Run 'floop init' first")
+	}
+	graphStore, err := store.NewMultiGraphStore(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open graph store: %w", err)
+	}
+	return graphStore, nil
+}
@@ -1,17 +1,21 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/nvandessel/floop/internal/activation"
-	"github.com/nvandessel/floop/internal/assembly"
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/edges"
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/prompt"
+	"github.com/nvandessel/floop/internal/spreading"
 	"github.com/nvandessel/floop/internal/store"
-	"github.com/nvandessel/floop/internal/tiering"
 	"github.com/spf13/cobra"
 )
 
@@ -19,7 +23,9 @@ func newShowCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "show [behavior-id]",
 		Short: "Show details of a behavior",
-		Args:  cobra.ExactArgs(1),
+		Long: `Show the details of a behavior, given by ID, name, or slug (its name
+without "learned/").`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
@@ -37,19 +43,10 @@ func newShowCmd() *cobra.Command {
 				return nil
 			}
 
-			// Load all behaviors from both local and global stores
-			behaviors, err := loadBehaviorsWithScope(root, store.ScopeBoth)
+			// Find the behavior by ID, name, or slug in the local and global stores
+			found, err := findBehavior(root, id)
 			if err != nil {
-				return fmt.Errorf("failed to load behaviors: %w", err)
-			}
-
-			// Find the behavior
-			var found *models.Behavior
-			for _, b := range behaviors {
-				if b.ID == id || b.Name == id {
-					found = &b
-					break
-				}
+				return err
 			}
 
 			if found == nil {
@@ -72,6 +69,9 @@ func newShowCmd() *cobra.Command {
 				fmt.Printf("Kind: %s\n", found.Kind)
 				fmt.Printf("Confidence: %.2f\n", found.Confidence)
 				fmt.Printf("Priority: %d\n", found.Priority)
+				if found.Origin != "" {
+					fmt.Printf("Origin: %s\n", found.Origin)
+				}
 				fmt.Println()
 
 				fmt.Println("Content:")
@@ -115,19 +115,49 @@ func newShowCmd() *cobra.Command {
 	return cmd
 }
 
+// printClauses prints the sub-clauses of an operator or "any" condition as
+// an indented tree.
+func printClauses(clauses []models.ClauseResult, indent string) {
+	for _, c := range clauses {
+		mark := "✗"
+		switch c.Status {
+		case models.StatusConfirmed:
+			mark = "✓"
+		case models.StatusAbsent:
+			mark = "·"
+		}
+		fmt.Printf("%s%s %s (%s)\n", indent, mark, c.Clause, c.Status)
+		printClauses(c.Clauses, indent+"  ")
+	}
+}
+
 func newWhyCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "why [behavior-id]",
 		Short: "Explain why a behavior is or isn't active",
 		Long: `Show the activation status of a behavior and explain why.
 
-This helps debug when a behavior isn't being applied as expected.`,
+This helps debug when a behavior isn't being applied as expected. The
+behavior can be given by ID, name, or slug (its name without "learned/").
+
+With --trace, spreading activation is run for the context and the
+propagation is shown: the chain of edges that carried activation from a
+seed to the behavior, with each edge's weight, decay, and energy, and
+every edge that reached it. Use it to find out why a behavior whose
+conditions don't match appeared in the output anyway.
+
+Examples:
+  floop why learned/wrap-errors --file main.go
+  floop why learned/wrap-errors --file main.go --trace
+  floop why learned/wrap-errors --task testing --trace --json`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			root, _ := cmd.Flags().GetString("root")
 			file, _ := cmd.Flags().GetString("file")
 			task, _ := cmd.Flags().GetString("task")
 			env, _ := cmd.Flags().GetString("env")
+			agent, _ := cmd.Flags().GetString("agent")
+			traceFlag, _ := cmd.Flags().GetBool("trace")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			id := args[0]
 
@@ -143,19 +173,10 @@ This helps debug when a behavior isn't being applied as expected.`,
 				return nil
 			}
 
-			// Load all behaviors from both local and global stores
-			behaviors, err := loadBehaviorsWithScope(root, store.ScopeBoth)
+			// Find the behavior by ID, name, or slug in the local and global stores
+			found, err := findBehavior(root, id)
 			if err != nil {
-				return fmt.Errorf("failed to load behaviors: %w", err)
-			}
-
-			// Find the behavior
-			var found *models.Behavior
-			for _, b := range behaviors {
-				if b.ID == id || b.Name == id {
-					found = &b
-					break
-				}
+				return err
 			}
 
 			if found == nil {
@@ -175,20 +196,51 @@ This helps debug when a behavior isn't being applied as expected.`,
 				WithFile(file).
 				WithTask(task).
 				WithEnvironment(env).
-				WithRepoRoot(root)
+				WithAgent(agent).
+				WithRepoRoot(root).
+				WithContentSniffing(sniffContentEnabled())
 			ctx := ctxBuilder.Build()
 
 			// Get explanation
-			evaluator := activation.NewEvaluator()
+			evaluator := newEvaluator()
 			explanation := evaluator.WhyActive(ctx, *found)
 
+			// Matching conditions is not enough: resolution can still hold it back
+			if explanation.IsActive {
+				behaviors, err := loadBehaviorsWithScope(root, store.ScopeBoth)
+				if err != nil {
+					return fmt.Errorf("failed to load behaviors: %w", err)
+				}
+				resolved := activation.NewResolver().WithBehaviors(behaviors).Resolve(evaluator.Evaluate(ctx, behaviors))
+				if reason, heldBack := resolved.Explain(found.ID); heldBack {
+					explanation.IsActive = false
+					explanation.Reason = reason
+				}
+			}
+
+			var trace *spreading.Trace
+			if traceFlag {
+				if trace, err = traceSpreading(root, ctx); err != nil {
+					return err
+				}
+			}
+
 			if jsonOut {
-				json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				out := map[string]interface{}{
 					"behavior":    found,
 					"context":     ctx,
 					"explanation": explanation,
 					"scope":       "local",
-				})
+				}
+				if trace != nil {
+					out["trace"] = map[string]interface{}{
+						"seeds":       traceSeeds(trace.Seeds),
+						"steps":       trace.Steps,
+						"firings":     len(trace.Firings),
+						"explanation": trace.Explain(found.ID),
+					}
+				}
+				json.NewEncoder(os.Stdout).Encode(out)
 			} else {
 				fmt.Printf("Behavior: %s\n", found.Name)
 				fmt.Printf("ID: %s\n", found.ID)
@@ -200,6 +252,9 @@ This helps debug when a behavior isn't being applied as expected.`,
 					fmt.Println("Status: NOT ACTIVE")
 				}
 				fmt.Printf("Reason: %s\n", explanation.Reason)
+				if len(explanation.Conditions) > 0 {
+					fmt.Printf("Match score: %.2f (%s matching)\n", explanation.Score, explanation.Mode)
+				}
 				fmt.Println()
 
 				if len(explanation.Conditions) > 0 {
@@ -209,8 +264,13 @@ This helps debug when a behavior isn't being applied as expected.`,
 						if !c.Matched {
 							status = "✗"
 						}
-						fmt.Printf("  %s %s: required=%v, actual=%v\n",
-							status, c.Field, c.Required, c.Actual)
+						weight := ""
+						if c.Weight > 0 {
+							weight = fmt.Sprintf(", weight=%g", c.Weight)
+						}
+						fmt.Printf("  %s %s: required=%v, actual=%v%s\n",
+							status, c.Field, c.Required, c.Actual, weight)
+						printClauses(c.Clauses, "      ")
 					}
 					fmt.Println()
 				}
@@ -225,12 +285,23 @@ This helps debug when a behavior isn't being applied as expected.`,
 				if ctx.Task != "" {
 					fmt.Printf("  task: %s\n", ctx.Task)
 				}
+				if len(ctx.Frameworks) > 0 {
+					fmt.Printf("  framework: %s\n", strings.Join(ctx.Frameworks, ", "))
+				}
+				if len(ctx.Imports) > 0 {
+					fmt.Printf("  imports: %s\n", strings.Join(ctx.Imports, ", "))
+				}
 				if ctx.Branch != "" {
 					fmt.Printf("  branch: %s\n", ctx.Branch)
 				}
 				if ctx.Environment != "" {
 					fmt.Printf("  environment: %s\n", ctx.Environment)
 				}
+
+				if trace != nil {
+					fmt.Println()
+					printSpreadingTrace(trace, found.ID)
+				}
 			}
 
 			return nil
@@ -240,10 +311,85 @@ This helps debug when a behavior isn't being applied as expected.`,
 	cmd.Flags().String("file", "", "Current file path")
 	cmd.Flags().String("task", "", "Current task type")
 	cmd.Flags().String("env", "", "Environment (dev, staging, prod)")
+	cmd.Flags().String("agent", "", "Agent client name (e.g. claude-code, cursor; default: $FLOOP_AGENT)")
+	cmd.Flags().Bool("trace", false, "Run spreading activation and show how activation reached the behavior")
 
 	return cmd
 }
 
+// traceSpreading runs spreading activation for ctx over the local and
+// global stores, recording the propagation.
+func traceSpreading(root string, ctx models.ContextSnapshot) (*spreading.Trace, error) {
+	graphStore, err := store.NewMultiGraphStore(root)
+	if err != nil {
+		return nil, fmt.Errorf("opening store: %w", err)
+	}
+	defer graphStore.Close()
+
+	pipeline := spreading.NewPipeline(graphStore, spreadingConfig()).WithStandingSeeds(standingSeeds())
+	trace, err := pipeline.Explain(context.Background(), ctx)
+	if err != nil {
+		return nil, fmt.Errorf("spreading activation: %w", err)
+	}
+	return trace, nil
+}
+
+// traceSeeds converts seeds to their JSON form.
+func traceSeeds(seeds []spreading.Seed) []map[string]interface{} {
+	out := make([]map[string]interface{}, len(seeds))
+	for i, s := range seeds {
+		out[i] = map[string]interface{}{
+			"behavior_id": s.BehaviorID,
+			"activation":  s.Activation,
+			"source":      s.Source,
+		}
+	}
+	return out
+}
+
+// printSpreadingTrace prints how spreading activation reached behaviorID.
+func printSpreadingTrace(trace *spreading.Trace, behaviorID string) {
+	x := trace.Explain(behaviorID)
+	fmt.Printf("Spreading trace (%d seeds, %d steps, %d edge firings):\n", len(trace.Seeds), trace.Steps, len(trace.Firings))
+	switch {
+	case len(trace.Seeds) == 0:
+		fmt.Println("  No behavior matches this context, so nothing was seeded.")
+		return
+	case x.Seed:
+		fmt.Printf("  Seeded directly (%s)\n", x.SeedSource)
+	case x.Reached:
+		fmt.Printf("  Spread-only: reached %d hop(s) from seed %q\n", x.Distance, x.SeedSource)
+	case len(x.Incoming) > 0:
+		fmt.Println("  Reached by spreading, but dropped below the activation threshold")
+	default:
+		fmt.Println("  Not reached: no edge from an activated behavior leads here")
+	}
+	fmt.Printf("  Activation: %.3f (before inhibition and sigmoid: %.3f)\n", x.Activation, x.RawActivation)
+
+	if len(x.Path) > 0 {
+		fmt.Println()
+		fmt.Println("  Path:")
+		fmt.Printf("    %s (seed)\n", x.Path[0].From)
+		for _, f := range x.Path {
+			fmt.Printf("    -> %s via %s [step %d]: %.3f x weight %.2f (effective %.2f) x decay %.2f = %+.3f\n",
+				f.To, f.EdgeKind, f.Step, f.SourceActivation, f.Weight, f.EffectiveWeight, f.Decay, f.Energy)
+		}
+	}
+
+	if len(x.Incoming) > 0 {
+		fmt.Println()
+		fmt.Println("  Incoming edges:")
+		for _, f := range x.Incoming {
+			mark := " "
+			if f.Raised {
+				mark = "*"
+			}
+			fmt.Printf("   %s step %d: %s via %s, %+.3f\n", mark, f.Step, f.From, f.EdgeKind, f.Energy)
+		}
+		fmt.Println("    (* raised the behavior's activation)")
+	}
+}
+
 func newPromptCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "prompt",
@@ -257,17 +403,42 @@ Examples:
   floop prompt --file main.go
   floop prompt --file main.go --format xml --token-budget 500
   floop prompt --file main.go --tiered --token-budget 2000
+  floop prompt --file main.go --task testing --order task-relevant-first
+  floop prompt --file main.go --kinds constraint
+  floop prompt --file main.go --token-budget 800 --packing value
+  floop prompt --git-context
   floop prompt --file main.go --json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			root, _ := cmd.Flags().GetString("root")
 			file, _ := cmd.Flags().GetString("file")
 			task, _ := cmd.Flags().GetString("task")
 			env, _ := cmd.Flags().GetString("env")
+			agent, _ := cmd.Flags().GetString("agent")
 			format, _ := cmd.Flags().GetString("format")
 			maxTokens, _ := cmd.Flags().GetInt("max-tokens")
 			tokenBudget, _ := cmd.Flags().GetInt("token-budget")
 			tiered, _ := cmd.Flags().GetBool("tiered")
+			order, _ := cmd.Flags().GetString("order")
+			packing, _ := cmd.Flags().GetString("packing")
+			kinds, _ := cmd.Flags().GetStringSlice("kinds")
+			excludeKinds, _ := cmd.Flags().GetStringSlice("exclude-kinds")
 			jsonOut, _ := cmd.Flags().GetBool("json")
+			gitContext, _ := cmd.Flags().GetBool("git-context")
+
+			kindFilter, err := activation.ParseKindFilter(kinds, excludeKinds)
+			if err != nil {
+				return err
+			}
+			outputFormat, err := prompt.ParseFormat(format)
+			if err != nil {
+				return err
+			}
+
+			// --order and --packing fall back to the configured strategies
+			var promptCfg config.PromptConfig
+			if cfg, err := config.Load(); err == nil {
+				promptCfg = cfg.Prompt
+			}
 
 			// Support both --max-tokens and --token-budget for backwards compatibility
 			if tokenBudget > 0 {
@@ -297,39 +468,30 @@ Examples:
 				WithFile(file).
 				WithTask(task).
 				WithEnvironment(env).
-				WithRepoRoot(root)
+				WithAgent(agent).
+				WithRepoRoot(root).
+				WithContentSniffing(sniffContentEnabled()).
+				WithGitContext(gitContext)
 			ctx := ctxBuilder.Build()
 
-			// Evaluate which behaviors are active
-			evaluator := activation.NewEvaluator()
-			matches := evaluator.Evaluate(ctx, behaviors)
-
-			// Resolve conflicts
-			resolver := activation.NewResolver()
-			resolved := resolver.Resolve(matches)
-
-			// Set output format
-			var outputFormat assembly.Format
-			switch format {
-			case "xml":
-				outputFormat = assembly.FormatXML
-			case "plain":
-				outputFormat = assembly.FormatPlain
-			default:
-				outputFormat = assembly.FormatMarkdown
+			resolved := prompt.Resolve(newEvaluator(), ctx, behaviors, kindFilter)
+			result, err := prompt.Compile(resolved, promptCfg, prompt.Options{
+				Format:      outputFormat,
+				Task:        task,
+				Order:       order,
+				Packing:     packing,
+				TokenBudget: maxTokens,
+				Tiered:      tiered,
+				Rerank: func(results []spreading.Result, behaviorMap map[string]*models.Behavior) []spreading.Result {
+					return applyExternalRanking(context.Background(), loadExternalScorer(), &ctx, results, behaviorMap)
+				},
+			})
+			if err != nil {
+				return err
 			}
 
-			compiler := assembly.NewCompiler().
-				WithFormat(outputFormat)
-
-			// Use tiered injection if requested
-			if tiered && maxTokens > 0 {
-				// Create tiered injection plan via bridge → ActivationTierMapper
-				results, behaviorMap := tiering.BehaviorsToResults(resolved.Active)
-				mapper := tiering.NewActivationTierMapper(tiering.DefaultActivationTierConfig())
-				plan := mapper.MapResults(results, behaviorMap, maxTokens)
-				tieredCompiled := compiler.CompileTiered(plan)
-
+			if result.Tiered != nil {
+				tieredCompiled, plan := result.Tiered, result.Plan
 				if jsonOut {
 					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
 						"context":              ctx,
@@ -357,56 +519,38 @@ Examples:
 						len(plan.FullBehaviors), len(plan.SummarizedBehaviors), len(plan.OmittedBehaviors))
 					fmt.Fprintf(os.Stderr, "Tokens: ~%d / %d budget\n", plan.TotalTokens, maxTokens)
 				}
-			} else {
-				// Use standard optimization
-				var activeBehaviors []models.Behavior
-				var excluded []models.Behavior
-
-				if maxTokens > 0 {
-					optimizer := assembly.NewOptimizer(maxTokens)
-					optResult := optimizer.Optimize(resolved.Active)
-					activeBehaviors = optResult.Included
-					excluded = optResult.Excluded
-				} else {
-					activeBehaviors = resolved.Active
-				}
-
-				compiled := compiler.Compile(activeBehaviors)
-
-				for _, e := range excluded {
-					compiled.ExcludedBehaviors = append(compiled.ExcludedBehaviors, e.ID)
-				}
+				return nil
+			}
 
-				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-						"context":            ctx,
-						"prompt":             compiled.Text,
-						"format":             compiled.Format,
-						"total_tokens":       compiled.TotalTokens,
-						"included_behaviors": compiled.IncludedBehaviors,
-						"excluded_behaviors": compiled.ExcludedBehaviors,
-						"sections":           compiled.Sections,
-						"tiered":             false,
-					})
-				} else {
-					if len(activeBehaviors) == 0 {
-						fmt.Println("No active behaviors for this context.")
-						return nil
-					}
+			compiled := result.Prompt
+			if jsonOut {
+				json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"context":            ctx,
+					"prompt":             compiled.Text,
+					"format":             compiled.Format,
+					"total_tokens":       compiled.TotalTokens,
+					"included_behaviors": compiled.IncludedBehaviors,
+					"excluded_behaviors": compiled.ExcludedBehaviors,
+					"sections":           compiled.Sections,
+					"tiered":             false,
+				})
+				return nil
+			}
+			if len(result.Included) == 0 {
+				fmt.Println("No active behaviors for this context.")
+				return nil
+			}
 
-					fmt.Println(compiled.Text)
+			fmt.Println(compiled.Text)
 
-					fmt.Fprintln(os.Stderr)
-					fmt.Fprintf(os.Stderr, "---\n")
-					fmt.Fprintf(os.Stderr, "Behaviors: %d included", len(compiled.IncludedBehaviors))
-					if len(compiled.ExcludedBehaviors) > 0 {
-						fmt.Fprintf(os.Stderr, ", %d excluded (token limit)", len(compiled.ExcludedBehaviors))
-					}
-					fmt.Fprintln(os.Stderr)
-					fmt.Fprintf(os.Stderr, "Tokens: ~%d\n", compiled.TotalTokens)
-				}
+			fmt.Fprintln(os.Stderr)
+			fmt.Fprintf(os.Stderr, "---\n")
+			fmt.Fprintf(os.Stderr, "Behaviors: %d included", len(compiled.IncludedBehaviors))
+			if len(compiled.ExcludedBehaviors) > 0 {
+				fmt.Fprintf(os.Stderr, ", %d excluded (token limit)", len(compiled.ExcludedBehaviors))
 			}
-
+			fmt.Fprintln(os.Stderr)
+			fmt.Fprintf(os.Stderr, "Tokens: ~%d\n", compiled.TotalTokens)
 			return nil
 		},
 	}
@@ -414,10 +558,42 @@ Examples:
 	cmd.Flags().String("file", "", "Current file path")
 	cmd.Flags().String("task", "", "Current task type")
 	cmd.Flags().String("env", "", "Environment (dev, staging, prod)")
+	cmd.Flags().String("agent", "", "Agent client name (e.g. claude-code, cursor; default: $FLOOP_AGENT)")
 	cmd.Flags().String("format", "markdown", "Output format (markdown, xml, plain)")
 	cmd.Flags().Int("max-tokens", 0, "Maximum tokens (0 = unlimited, deprecated: use --token-budget)")
 	cmd.Flags().Int("token-budget", 0, "Token budget for behavior injection (enables intelligent tiering)")
 	cmd.Flags().Bool("tiered", false, "Use tiered injection (full/summary/omit) instead of simple truncation")
+	cmd.Flags().StringSlice("kinds", nil, "Only include these behavior kinds (e.g. constraint,directive)")
+	cmd.Flags().StringSlice("exclude-kinds", nil, "Exclude these behavior kinds (e.g. episodic)")
+	cmd.Flags().String("order", "", "Section ordering: kind, constraints-first, group-by-tag, task-relevant-first, alphabetical (default: prompt.ordering config)")
+	cmd.Flags().String("packing", "", "Budget packing: importance, value (default: prompt.packing config)")
+	cmd.Flags().Bool("git-context", false, "Capture staged files, changed paths, and the commit message in progress from git")
 
 	return cmd
 }
+
+// findBehavior looks up an active behavior by ID, name, or slug in the local
+// and global stores, with its relationships attached. Returns nil if there
+// is no such behavior.
+func findBehavior(root, ref string) (*models.Behavior, error) {
+	graphStore, err := store.NewMultiGraphStore(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open multi-store: %w", err)
+	}
+	defer graphStore.Close()
+
+	ctx := context.Background()
+	node, err := store.ResolveNode(ctx, graphStore, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up behavior: %w", err)
+	}
+	if node == nil || node.Kind != store.NodeKindBehavior {
+		return nil, nil
+	}
+
+	behaviors := []models.Behavior{models.NodeToBehavior(*node)}
+	if err := edges.AttachRelationships(ctx, graphStore, behaviors); err != nil {
+		return nil, err
+	}
+	return &behaviors[0], nil
+}
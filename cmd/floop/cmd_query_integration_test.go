@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/nvandessel/floop/internal/store"
@@ -140,6 +141,38 @@ func TestWhyCmdJSON(t *testing.T) {
 	}
 }
 
+func TestWhyCmdTrace(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+
+	out := captureStdout(t, func() {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newWhyCmd())
+		rootCmd.SetArgs([]string{"why", behaviorID, "--trace", "--json", "--file", "main.go", "--task", "coding", "--root", tmpDir})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("why --trace failed: %v", err)
+		}
+	})
+
+	var result struct {
+		Trace struct {
+			Seeds       []map[string]interface{} `json:"seeds"`
+			Explanation struct {
+				BehaviorID string `json:"behavior_id"`
+				Seed       bool   `json:"seed"`
+			} `json:"explanation"`
+		} `json:"trace"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if result.Trace.Explanation.BehaviorID != behaviorID || !result.Trace.Explanation.Seed {
+		t.Errorf("trace explanation = %+v, want %s as a seed", result.Trace.Explanation, behaviorID)
+	}
+	if len(result.Trace.Seeds) == 0 {
+		t.Error("trace has no seeds")
+	}
+}
+
 func TestWhyCmdNotFound(t *testing.T) {
 	tmpDir, _ := setupQueryTest(t)
 
@@ -206,6 +239,22 @@ func TestPromptCmdWithTokenBudget(t *testing.T) {
 	}
 }
 
+func TestPromptCmdPacking(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	for _, packing := range []string{"value", "insertion"} {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newPromptCmd())
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetArgs([]string{"prompt", "--file", "main.go", "--token-budget", "500", "--packing", packing, "--root", tmpDir})
+
+		err := rootCmd.Execute()
+		if wantErr := packing == "insertion"; (err != nil) != wantErr {
+			t.Errorf("prompt --packing %s error = %v, wantErr %v", packing, err, wantErr)
+		}
+	}
+}
+
 func TestPromptCmdTiered(t *testing.T) {
 	tmpDir, _ := setupQueryTest(t)
 
@@ -284,3 +333,21 @@ func TestListCmdTagFilter(t *testing.T) {
 		t.Errorf("expected 0 behaviors with nonexistent tag, got %d", int(countVal))
 	}
 }
+
+func TestFindBehaviorByName(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+
+	byID, err := findBehavior(tmpDir, behaviorID)
+	if err != nil || byID == nil {
+		t.Fatalf("findBehavior(id) = %v, %v", byID, err)
+	}
+	for _, ref := range []string{byID.Name, strings.TrimPrefix(byID.Name, "learned/")} {
+		b, err := findBehavior(tmpDir, ref)
+		if err != nil || b == nil || b.ID != behaviorID {
+			t.Errorf("findBehavior(%q) = %v, %v, want %s", ref, b, err, behaviorID)
+		}
+	}
+	if b, err := findBehavior(tmpDir, "nonexistent"); err != nil || b != nil {
+		t.Errorf("findBehavior(nonexistent) = %v, %v, want nil", b, err)
+	}
+}
@@ -17,7 +17,7 @@ func TestNewWhyCmd(t *testing.T) {
 		t.Errorf("Use = %q, want %q", cmd.Use, "why [behavior-id]")
 	}
 
-	for _, flag := range []string{"file", "task", "env"} {
+	for _, flag := range []string{"file", "task", "env", "trace"} {
 		if cmd.Flags().Lookup(flag) == nil {
 			t.Errorf("missing --%s flag", flag)
 		}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newRebalanceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rebalance",
+		Short: "Partition the global store into separate databases",
+		Long: `Split the global store into partitions, each a separate SQLite database
+under ~/.floop/partitions/, or redistribute behaviors after the store has
+grown.
+
+Strategies:
+  language    Group behaviors by their language condition
+  tag         Group behaviors by their first tag
+  community   Group behaviors densely connected in the behavior graph
+  none        Merge every partition back into ~/.floop and stop partitioning
+
+Groups smaller than --min-size stay in ~/.floop, which also keeps edges
+between partitions. Partitions are opened only when needed: activation skips
+any partition whose behaviors share a when-condition the context
+contradicts, such as a language partition for another language. New
+behaviors join an existing partition of their language or tag; run
+rebalance again to create partitions for new groups.
+
+Without --by, the current strategy is reapplied.
+
+Examples:
+  floop rebalance --by language --dry-run   # Preview the partitions
+  floop rebalance --by language             # Partition by language
+  floop rebalance                           # Rebalance with the current strategy
+  floop rebalance --by none                 # Merge back into one store`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			by, _ := cmd.Flags().GetString("by")
+			minSize, _ := cmd.Flags().GetInt("min-size")
+			if minSize < 1 {
+				return fmt.Errorf("--min-size must be at least 1")
+			}
+
+			globalPath, err := store.GlobalFloopPath()
+			if err != nil {
+				return fmt.Errorf("failed to get global path: %w", err)
+			}
+			if _, err := os.Stat(globalPath); err != nil {
+				return fmt.Errorf("global .floop not accessible: %w", err)
+			}
+
+			if by == "" {
+				manifest, err := store.ReadPartitionManifest(globalPath)
+				if err != nil {
+					return err
+				}
+				if manifest == nil {
+					return fmt.Errorf("the global store is not partitioned; choose a strategy with --by")
+				}
+				by = string(manifest.Strategy)
+			}
+			strategy, err := store.ParsePartitionStrategy(by)
+			if err != nil {
+				return err
+			}
+
+			report, err := store.RebalancePartitions(context.Background(), filepath.Dir(globalPath), store.RebalanceOptions{
+				Strategy: strategy,
+				MinSize:  minSize,
+				DryRun:   dryRun,
+			})
+			if err != nil {
+				return fmt.Errorf("rebalance failed: %w", err)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(report)
+			}
+			printRebalanceReport(cmd.OutOrStdout(), report)
+			return nil
+		},
+	}
+
+	cmd.Flags().String("by", "", "Partition strategy: language, tag, community, or none (default: current strategy)")
+	cmd.Flags().Int("min-size", store.DefaultMinPartitionSize, "Fewest behaviors a partition is created for")
+	cmd.Flags().Bool("dry-run", false, "Show the resulting partitions without moving anything")
+
+	return cmd
+}
+
+// printRebalanceReport lists the partitions a rebalance produced.
+func printRebalanceReport(out io.Writer, report *store.RebalanceReport) {
+	verb := "Moved"
+	if report.DryRun {
+		verb = "Would move"
+	}
+	if report.Strategy == store.PartitionByNone {
+		fmt.Fprintf(out, "%s %d node(s) back into the global store.\n", verb, report.Moved)
+		return
+	}
+
+	fmt.Fprintf(out, "Partitions by %s:\n", report.Strategy)
+	for _, p := range report.Partitions {
+		fmt.Fprintf(out, "  %-30s %5d behavior(s)%s\n", p.Key, p.Behaviors, formatPartitionWhen(p.When))
+	}
+	fmt.Fprintf(out, "  %-30s %5d behavior(s)\n", "core", report.Core)
+	fmt.Fprintf(out, "\n%s %d node(s).\n", verb, report.Moved)
+	if len(report.Removed) > 0 {
+		fmt.Fprintf(out, "Removed partitions: %s\n", strings.Join(report.Removed, ", "))
+	}
+}
+
+// formatPartitionWhen renders the conditions a partition's behaviors share.
+func formatPartitionWhen(when map[string]interface{}) string {
+	if len(when) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(when))
+	for k := range when {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, when[k]))
+	}
+	return "  when " + strings.Join(parts, ", ")
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestRebalanceCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	home, _ := os.UserHomeDir()
+
+	gs, err := store.NewSQLiteGraphStore(home)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	for _, id := range []string{"go-a", "go-b", "py-a"} {
+		lang := "go"
+		if strings.HasPrefix(id, "py") {
+			lang = "python"
+		}
+		if _, err := gs.AddNode(context.Background(), store.Node{
+			ID:   id,
+			Kind: store.NodeKindBehavior,
+			Content: map[string]interface{}{
+				"name":    id,
+				"kind":    "directive",
+				"content": map[string]interface{}{"canonical": id + " canonical"},
+				"when":    map[string]interface{}{"language": lang},
+			},
+		}); err != nil {
+			t.Fatalf("AddNode(%s) error = %v", id, err)
+		}
+	}
+	gs.Close()
+
+	run := func(args ...string) (string, error) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newRebalanceCmd())
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs(append([]string{"rebalance"}, args...))
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+
+	if _, err := run(); err == nil || !strings.Contains(err.Error(), "--by") {
+		t.Errorf("rebalance without a strategy on an unpartitioned store = %v, want an error naming --by", err)
+	}
+	if _, err := run("--by", "size"); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+
+	out, err := run("--by", "language", "--min-size", "2", "--json")
+	if err != nil {
+		t.Fatalf("rebalance --by language failed: %v", err)
+	}
+	var report store.RebalanceReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if len(report.Partitions) != 1 || report.Partitions[0].Key != "lang-go" || report.Core != 1 || report.Moved != 2 {
+		t.Errorf("report = %+v, want lang-go with 2 behaviors moved and py-a left in the core", report)
+	}
+
+	out, err = run("--dry-run", "--min-size", "2")
+	if err != nil {
+		t.Fatalf("rebalance --dry-run failed: %v", err)
+	}
+	for _, want := range []string{"Partitions by language:", "lang-go", "when language=go", "Would move 0 node(s)."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	if out, err := run("--by", "none"); err != nil || !strings.Contains(out, "Moved 2 node(s) back into the global store.") {
+		t.Errorf("rebalance --by none = %q, %v", out, err)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nvandessel/floop/internal/remote"
+	"github.com/spf13/cobra"
+)
+
+func newRemotePromptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remote-prompt",
+		Short: "Fetch a compiled prompt from a floop server",
+		Long: `Fetch the prompt for the current context from a central floop server
+started with 'floop serve'. No local store is needed, which suits remote
+dev boxes and codespaces.
+
+The last response for each server and context is cached under the user
+cache directory. Repeat requests send its ETag, and the cached prompt is
+reused when the server reports it unchanged.
+
+Examples:
+  floop remote-prompt --server http://floop.internal:8765 --file main.go
+  floop remote-prompt --server http://floop.internal:8765 --task testing --format xml
+  floop remote-prompt --server http://floop.internal:8765 --file main.go --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			server, _ := cmd.Flags().GetString("server")
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			req := remote.Request{}
+			req.File, _ = cmd.Flags().GetString("file")
+			req.Language, _ = cmd.Flags().GetString("language")
+			req.Task, _ = cmd.Flags().GetString("task")
+			req.Env, _ = cmd.Flags().GetString("env")
+			req.Agent, _ = cmd.Flags().GetString("agent")
+			req.Format, _ = cmd.Flags().GetString("format")
+			req.TokenBudget, _ = cmd.Flags().GetInt("token-budget")
+			req.Top, _ = cmd.Flags().GetInt("top")
+			if req.Agent == "" {
+				req.Agent = os.Getenv("FLOOP_AGENT")
+			}
+			if req.Top < 1 || req.Top > remote.MaxTop {
+				return fmt.Errorf("--top must be between 1 and %d", remote.MaxTop)
+			}
+
+			client, err := remote.NewClient(server, timeout)
+			if err != nil {
+				return err
+			}
+
+			cachePath := remotePromptCachePath(server, req)
+			cached := readRemotePromptCache(cachePath)
+			etag := ""
+			if cached != nil {
+				etag = cached.ETag
+			}
+
+			resp, err := client.Prompt(context.Background(), req, etag)
+			switch {
+			case errors.Is(err, remote.ErrNotModified):
+				resp = cached
+			case err != nil:
+				return err
+			default:
+				writeRemotePromptCache(cachePath, resp)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(resp)
+			}
+			if len(resp.Behaviors) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No active behaviors for this context.")
+				return nil
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), resp.Prompt)
+			fmt.Fprintln(cmd.ErrOrStderr())
+			fmt.Fprintf(cmd.ErrOrStderr(), "---\n")
+			fmt.Fprintf(cmd.ErrOrStderr(), "Top behaviors: %d\n", len(resp.Behaviors))
+			fmt.Fprintf(cmd.ErrOrStderr(), "Tokens: ~%d\n", resp.Tokens)
+			return nil
+		},
+	}
+
+	cmd.Flags().String("server", "", "Base URL of the floop server (e.g. http://floop.internal:8765)")
+	cmd.Flags().String("file", "", "Current file path")
+	cmd.Flags().String("language", "", "Programming language (default: inferred from --file)")
+	cmd.Flags().String("task", "", "Current task type")
+	cmd.Flags().String("env", "", "Environment (dev, staging, prod)")
+	cmd.Flags().String("agent", "", "Agent client name (e.g. claude-code, cursor; default: $FLOOP_AGENT)")
+	cmd.Flags().String("format", "markdown", "Output format (markdown, xml, plain)")
+	cmd.Flags().Int("token-budget", 0, "Token budget for the compiled prompt (0 = unlimited)")
+	cmd.Flags().Int("top", remote.DefaultTop, "Number of top behaviors to return")
+	cmd.Flags().Duration("timeout", remote.DefaultTimeout, "Request timeout")
+	cmd.MarkFlagRequired("server")
+
+	return cmd
+}
+
+// remotePromptCachePath returns the cache file for a server and request, or
+// "" when there is no user cache directory.
+func remotePromptCachePath(server string, req remote.Request) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(server + "?" + req.Query().Encode()))
+	return filepath.Join(dir, "floop", "remote", hex.EncodeToString(sum[:8])+".json")
+}
+
+// readRemotePromptCache returns the cached response at path, or nil if
+// there is none.
+func readRemotePromptCache(path string) *remote.Response {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var resp remote.Response
+	if err := json.Unmarshal(data, &resp); err != nil || resp.ETag == "" {
+		return nil
+	}
+	return &resp
+}
+
+// writeRemotePromptCache caches resp at path. Failures are ignored: the
+// cache only saves a transfer.
+func writeRemotePromptCache(path string, resp *remote.Response) {
+	if path == "" || resp.ETag == "" {
+		return
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+	os.WriteFile(path, data, 0o600)
+}
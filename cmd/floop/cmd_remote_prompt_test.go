@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/remote"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestRemotePromptCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(tmpDir, "cache"))
+	home, _ := os.UserHomeDir()
+
+	gs, err := store.NewSQLiteGraphStore(home)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	for id, lang := range map[string]string{"use-pathlib": "python", "wrap-errors": "go"} {
+		if _, err := gs.AddNode(context.Background(), store.Node{
+			ID:   id,
+			Kind: store.NodeKindBehavior,
+			Content: map[string]interface{}{
+				"name":    id,
+				"kind":    "directive",
+				"content": map[string]interface{}{"canonical": id + " canonical"},
+				"when":    map[string]interface{}{"language": lang},
+			},
+		}); err != nil {
+			t.Fatalf("AddNode(%s) error = %v", id, err)
+		}
+	}
+	gs.Close()
+
+	// The server's root has no project store, so only global behaviors serve
+	srv := httptest.NewServer(remote.NewHandler(remotePromptCompiler(filepath.Join(tmpDir, "server"))))
+	defer srv.Close()
+
+	run := func(args ...string) (string, error) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newRemotePromptCmd())
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs(append([]string{"remote-prompt", "--server", srv.URL}, args...))
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("--file", "app.py")
+	if err != nil {
+		t.Fatalf("remote-prompt failed: %v", err)
+	}
+	if !strings.Contains(out, "use-pathlib canonical") || strings.Contains(out, "wrap-errors") {
+		t.Errorf("prompt for app.py = %q, want only the Python behavior", out)
+	}
+
+	out, err = run("--file", "app.py", "--json")
+	if err != nil {
+		t.Fatalf("remote-prompt --json failed: %v", err)
+	}
+	var resp remote.Response
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if resp.Version != remote.ProtocolVersion || resp.ETag == "" || len(resp.Behaviors) != 1 || resp.Behaviors[0].ID != "use-pathlib" {
+		t.Errorf("response = %+v, want use-pathlib with an etag", resp)
+	}
+
+	// An unchanged prompt is answered from the cache
+	cached := readRemotePromptCache(remotePromptCachePath(srv.URL, remote.Request{File: "app.py", Format: "markdown", Top: remote.DefaultTop}))
+	if cached == nil || cached.ETag != resp.ETag {
+		t.Errorf("cached response = %+v, want etag %s", cached, resp.ETag)
+	}
+
+	if out, err := run("--file", "lib.rs"); err != nil || !strings.Contains(out, "No active behaviors") {
+		t.Errorf("remote-prompt for lib.rs = %q, %v", out, err)
+	}
+	if _, err := run("--top", "0"); err == nil {
+		t.Error("expected error for --top 0")
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/restorepoint"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newRestorePointCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore-point",
+		Short: "List and apply restore points saved before destructive operations",
+		Long: fmt.Sprintf(`Before merge, deduplicate (without --dry-run), and restore-backup --mode
+replace change anything, floop snapshots the affected nodes and edges into
+.floop/restore-points/ (or ~/.floop/restore-points/ without a project store).
+Applying a restore point puts those nodes and edges back as they were.
+
+The newest %d restore points are kept.
+
+Examples:
+  floop restore-point list
+  floop restore-point apply rp-1706000000000000000`, constants.MaxRestorePoints),
+	}
+
+	cmd.AddCommand(
+		newRestorePointListCmd(),
+		newRestorePointApplyCmd(),
+	)
+
+	return cmd
+}
+
+func newRestorePointListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List restore points, newest first",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			dir, err := restorepoint.Dir(root)
+			if err != nil {
+				return fmt.Errorf("failed to get restore point directory: %w", err)
+			}
+			points, err := restorepoint.List(dir)
+			if err != nil {
+				return err
+			}
+
+			if jsonOut {
+				type jsonEntry struct {
+					ID          string `json:"id"`
+					Operation   string `json:"operation"`
+					Description string `json:"description,omitempty"`
+					CreatedAt   string `json:"created_at"`
+					NodeCount   int    `json:"node_count"`
+					EdgeCount   int    `json:"edge_count"`
+				}
+				entries := make([]jsonEntry, 0, len(points))
+				for _, p := range points {
+					entries = append(entries, jsonEntry{
+						ID:          p.ID,
+						Operation:   p.Operation,
+						Description: p.Description,
+						CreatedAt:   p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+						NodeCount:   p.NodeCount(),
+						EdgeCount:   p.EdgeCount(),
+					})
+				}
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"restore_points": entries,
+					"total_count":    len(entries),
+					"directory":      dir,
+				})
+			}
+
+			if len(points) == 0 {
+				fmt.Printf("No restore points found in %s\n", dir)
+				return nil
+			}
+
+			fmt.Printf("Restore points in %s:\n", dir)
+			for _, p := range points {
+				fmt.Printf("  %s  %s  %-15s %d nodes, %d edges  %s\n",
+					p.ID, p.CreatedAt.Local().Format("2006-01-02 15:04:05"), p.Operation,
+					p.NodeCount(), p.EdgeCount(), p.Description)
+			}
+			return nil
+		},
+	}
+}
+
+func newRestorePointApplyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply <id>",
+		Short: "Put the nodes and edges in a restore point back",
+		Long: `Restore every node and edge captured in a restore point. Edges touching
+those nodes that were added since are removed. Restore points taken of a
+whole store (deduplicate across stores, restore-backup --mode replace) also
+remove nodes added since.
+
+The current state is saved as a new restore point first, so applying one
+can itself be undone.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			force, _ := cmd.Flags().GetBool("force")
+			id := args[0]
+
+			// JSON mode implies force (no interactive prompts)
+			if jsonOut {
+				force = true
+			}
+
+			dir, err := restorepoint.Dir(root)
+			if err != nil {
+				return fmt.Errorf("failed to get restore point directory: %w", err)
+			}
+			point, err := restorepoint.Load(dir, id)
+			if err != nil {
+				return err
+			}
+
+			if !force {
+				fmt.Printf("Apply restore point %s (%s, %s)\n", point.ID, point.Operation, point.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+				fmt.Printf("  %d nodes, %d edges will be put back.\n", point.NodeCount(), point.EdgeCount())
+				if !confirmed() {
+					fmt.Println("Cancelled.")
+					return nil
+				}
+			}
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			ctx := context.Background()
+
+			// Snapshot what applying will overwrite
+			var ids []string
+			full := false
+			for _, snap := range point.Snapshots {
+				full = full || snap.Full
+				for _, n := range snap.Nodes {
+					ids = append(ids, n.ID)
+				}
+				ids = append(ids, snap.Absent...)
+			}
+			if full {
+				ids = nil
+			}
+			before, err := restorepoint.Create(ctx, graphStore, root, "restore-point", "before applying "+point.ID, ids)
+			if err != nil {
+				return fmt.Errorf("failed to create restore point: %w", err)
+			}
+
+			result, err := restorepoint.Apply(ctx, graphStore, point)
+			if err != nil {
+				return fmt.Errorf("failed to apply restore point: %w", err)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"status":         "applied",
+					"id":             point.ID,
+					"nodes_restored": result.NodesRestored,
+					"nodes_removed":  result.NodesRemoved,
+					"edges_restored": result.EdgesRestored,
+					"edges_removed":  result.EdgesRemoved,
+					"edges_skipped":  result.EdgesSkipped,
+					"restore_point":  before.ID,
+				})
+			}
+
+			fmt.Printf("Restore point %s applied.\n", point.ID)
+			fmt.Printf("  Nodes: %d restored, %d removed\n", result.NodesRestored, result.NodesRemoved)
+			fmt.Printf("  Edges: %d restored, %d removed, %d skipped\n", result.EdgesRestored, result.EdgesRemoved, result.EdgesSkipped)
+			fmt.Printf("  Undo with: floop restore-point apply %s\n", before.ID)
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("force", false, "Skip confirmation prompt")
+
+	return cmd
+}
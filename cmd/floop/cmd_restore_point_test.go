@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/restorepoint"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestRestorePointUndoesMerge(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	// Learn a second behavior
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newLearnCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"learn", "--right", "use parameterized queries", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("learn failed: %v", err)
+	}
+
+	ctx := context.Background()
+	graphStore, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	nodes, err := graphStore.QueryNodes(ctx, map[string]interface{}{"kind": "behavior"})
+	graphStore.Close()
+	if err != nil || len(nodes) < 2 {
+		t.Fatalf("need at least 2 behaviors, got %d", len(nodes))
+	}
+	sourceID, targetID := nodes[0].ID, nodes[1].ID
+
+	rootCmd2 := newTestRootCmd()
+	rootCmd2.AddCommand(newMergeCmd())
+	rootCmd2.SetOut(&bytes.Buffer{})
+	rootCmd2.SetArgs([]string{"merge", sourceID, targetID, "--force", "--root", tmpDir})
+	if err := rootCmd2.Execute(); err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+
+	dir, err := restorepoint.Dir(tmpDir)
+	if err != nil {
+		t.Fatalf("Dir() error = %v", err)
+	}
+	points, err := restorepoint.List(dir)
+	if err != nil || len(points) != 1 || points[0].Operation != "merge" {
+		t.Fatalf("restore points = %+v (err %v), want one merge point", points, err)
+	}
+
+	// List as JSON
+	rootCmd3 := newTestRootCmd()
+	rootCmd3.AddCommand(newRestorePointCmd())
+	rootCmd3.SetArgs([]string{"restore-point", "list", "--json", "--root", tmpDir})
+	out := captureStdout(t, func() {
+		if err := rootCmd3.Execute(); err != nil {
+			t.Fatalf("restore-point list failed: %v", err)
+		}
+	})
+	var listed struct {
+		TotalCount int `json:"total_count"`
+	}
+	if err := json.Unmarshal([]byte(out), &listed); err != nil || listed.TotalCount != 1 {
+		t.Fatalf("restore-point list --json = %q, want one point", out)
+	}
+
+	rootCmd4 := newTestRootCmd()
+	rootCmd4.AddCommand(newRestorePointCmd())
+	rootCmd4.SetOut(&bytes.Buffer{})
+	rootCmd4.SetArgs([]string{"restore-point", "apply", points[0].ID, "--force", "--root", tmpDir})
+	if err := rootCmd4.Execute(); err != nil {
+		t.Fatalf("restore-point apply failed: %v", err)
+	}
+
+	graphStore2, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer graphStore2.Close()
+	source, _ := graphStore2.GetNode(ctx, sourceID)
+	if source == nil || source.Kind != store.NodeKindBehavior {
+		t.Errorf("source after apply = %+v, want an active behavior again", source)
+	}
+	edges, _ := graphStore2.GetEdges(ctx, sourceID, store.DirectionOutbound, store.EdgeKindMergedInto)
+	if len(edges) != 0 {
+		t.Errorf("merged-into edge should be removed, got %d", len(edges))
+	}
+
+	// Applying saved the pre-apply state as a new point
+	if points, _ := restorepoint.List(dir); len(points) != 2 {
+		t.Errorf("restore points after apply = %d, want 2", len(points))
+	}
+}
+
+func TestRestorePointApplyNotFound(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newRestorePointCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"restore-point", "apply", "rp-missing", "--force", "--root", tmpDir})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error for missing restore point")
+	}
+}
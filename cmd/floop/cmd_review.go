@@ -0,0 +1,397 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/review"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// reviewDecision is one entry of a --decisions batch.
+type reviewDecision struct {
+	ID     string                 `json:"id"`
+	Action string                 `json:"action"`           // accept, edit, reject, or merge
+	When   map[string]interface{} `json:"when,omitempty"`   // replaces the when-conditions (accept, edit)
+	Into   string                 `json:"into,omitempty"`   // merge target (default: the suggested one)
+	Reason string                 `json:"reason,omitempty"` // recorded on rejected behaviors
+}
+
+// reviewResult reports the outcome of a decision.
+type reviewResult struct {
+	ID           string `json:"id"`
+	Action       string `json:"action"`
+	Status       string `json:"status"` // the new state, or "error"
+	Into         string `json:"into,omitempty"`
+	RestorePoint string `json:"restore_point,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+func newReviewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "review",
+		Short: "Review behaviors flagged by the learning loop",
+		Long: `Work through the behaviors the learning loop flagged for review:
+constraints, low-confidence placements, near-duplicates of existing
+behaviors, and would-be merges. Flagged behaviors stay active while they
+wait.
+
+Interactively, each behavior is shown with the reasons it was flagged:
+  a  accept it as is
+  e  edit its when-conditions (a JSON object; {} removes them all)
+  r  reject it (it is forgotten and can be brought back with 'floop restore')
+  m  merge it into another behavior (default: the behavior it duplicates)
+  s  skip it for now
+  q  quit
+
+With --json and no --decisions, the queue is printed instead. --decisions
+applies a batch from a JSON file ('-' reads stdin) without prompting, for CI
+and agents:
+
+  [{"id": "b-1", "action": "accept"},
+   {"id": "b-2", "action": "accept", "when": {"language": "go"}},
+   {"id": "b-3", "action": "edit", "when": {"task": "testing"}},
+   {"id": "b-4", "action": "reject", "reason": "too specific"},
+   {"id": "b-5", "action": "merge", "into": "b-9"}]
+
+'edit' changes the conditions and leaves the behavior in the queue.
+
+Examples:
+  floop review                                # Review interactively
+  floop review --json                         # Print the queue
+  floop review --decisions decisions.json     # Apply a batch
+  agent-triage | floop review --decisions - --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			decisionsPath, _ := cmd.Flags().GetString("decisions")
+
+			floopDir := filepath.Join(root, ".floop")
+			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
+				return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+			}
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			ctx := context.Background()
+			out := cmd.OutOrStdout()
+
+			if decisionsPath != "" {
+				decisions, err := readReviewDecisions(cmd.InOrStdin(), decisionsPath)
+				if err != nil {
+					return err
+				}
+				results, failed := applyReviewDecisions(ctx, graphStore, root, decisions)
+				if err := graphStore.Sync(ctx); err != nil {
+					return fmt.Errorf("failed to sync changes: %w", err)
+				}
+				pending, err := review.Pending(ctx, graphStore)
+				if err != nil {
+					return err
+				}
+
+				if jsonOut {
+					json.NewEncoder(out).Encode(map[string]interface{}{
+						"results": results,
+						"failed":  failed,
+						"pending": len(pending),
+					})
+				} else {
+					for _, r := range results {
+						if r.Error != "" {
+							fmt.Fprintf(out, "  %-10s %s: %s\n", "error", r.ID, r.Error)
+						} else {
+							fmt.Fprintf(out, "  %-10s %s\n", r.Status, r.ID)
+						}
+					}
+					fmt.Fprintf(out, "\nApplied %d of %d decision(s); %d behavior(s) still pending review.\n",
+						len(results)-failed, len(results), len(pending))
+				}
+				if failed > 0 {
+					return fmt.Errorf("%d review decision(s) failed", failed)
+				}
+				return nil
+			}
+
+			pending, err := review.Pending(ctx, graphStore)
+			if err != nil {
+				return err
+			}
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"pending": pending,
+					"count":   len(pending),
+				})
+			}
+			if len(pending) == 0 {
+				fmt.Fprintln(out, "No behaviors pending review.")
+				return nil
+			}
+			return runReviewSession(ctx, cmd.InOrStdin(), out, graphStore, root, pending)
+		},
+	}
+
+	cmd.Flags().String("decisions", "", "Apply review decisions from a JSON file ('-' for stdin) without prompting")
+
+	return cmd
+}
+
+// readReviewDecisions reads a --decisions batch from path, or from stdin
+// when path is "-".
+func readReviewDecisions(stdin io.Reader, path string) ([]reviewDecision, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read review decisions: %w", err)
+	}
+	var decisions []reviewDecision
+	if err := json.Unmarshal(data, &decisions); err != nil {
+		return nil, fmt.Errorf("invalid review decisions: %w", err)
+	}
+	return decisions, nil
+}
+
+// applyReviewDecisions applies each decision in order, continuing past
+// failures. Returns a result per decision and the number that failed. The
+// caller syncs the store.
+func applyReviewDecisions(ctx context.Context, graphStore *store.MultiGraphStore, root string, decisions []reviewDecision) ([]reviewResult, int) {
+	results := make([]reviewResult, 0, len(decisions))
+	failed := 0
+	for _, d := range decisions {
+		result, err := applyReviewDecision(ctx, graphStore, root, d, time.Now())
+		if err != nil {
+			result = reviewResult{ID: d.ID, Action: d.Action, Status: "error", Error: err.Error()}
+			failed++
+		}
+		results = append(results, result)
+	}
+	return results, failed
+}
+
+// applyReviewDecision resolves (or, for edit, updates) one pending behavior.
+func applyReviewDecision(ctx context.Context, graphStore *store.MultiGraphStore, root string, d reviewDecision, now time.Time) (reviewResult, error) {
+	result := reviewResult{ID: d.ID, Action: d.Action}
+	node, err := graphStore.GetNode(ctx, d.ID)
+	if err != nil {
+		return result, fmt.Errorf("failed to get behavior: %w", err)
+	}
+	if node == nil {
+		return result, fmt.Errorf("behavior not found")
+	}
+	if !review.IsPending(*node) {
+		return result, fmt.Errorf("not pending review")
+	}
+
+	if d.When != nil {
+		if err := models.ValidateWhen(d.When); err != nil {
+			return result, fmt.Errorf("invalid when-conditions: %w", err)
+		}
+	}
+
+	switch d.Action {
+	case "accept":
+		if d.When != nil {
+			node.Content["when"] = d.When
+		}
+		review.Clear(node)
+		node.Metadata["reviewed_at"] = now.UTC().Format(time.RFC3339)
+		node.Metadata["reviewed_by"] = os.Getenv("USER")
+		result.Status = "accepted"
+
+	case "edit":
+		if d.When == nil {
+			return result, fmt.Errorf("edit needs when-conditions")
+		}
+		node.Content["when"] = d.When
+		result.Status = "edited"
+
+	case "reject":
+		reason := d.Reason
+		if reason == "" {
+			reason = "rejected in review"
+		}
+		review.Clear(node)
+		markForgotten(node, reason, now)
+		result.Status = "rejected"
+
+	case "merge":
+		into := d.Into
+		if into == "" {
+			into = review.NewEntry(*node).MergeTarget
+		}
+		if into == "" {
+			return result, fmt.Errorf("no merge target suggested; set into")
+		}
+		if into == node.ID {
+			return result, fmt.Errorf("cannot merge a behavior into itself")
+		}
+		target, err := graphStore.GetNode(ctx, into)
+		if err != nil {
+			return result, fmt.Errorf("failed to get merge target: %w", err)
+		}
+		if target == nil || target.Kind != store.NodeKindBehavior {
+			return result, fmt.Errorf("merge target %s is not an active behavior", into)
+		}
+		review.Clear(node)
+		point, _, err := mergeBehaviorNodes(ctx, graphStore, root, node, target)
+		if err != nil {
+			return result, err
+		}
+		result.Status = "merged"
+		result.Into = into
+		result.RestorePoint = point.ID
+		return result, nil
+
+	default:
+		return result, fmt.Errorf("unknown action %q (valid: accept, edit, reject, merge)", d.Action)
+	}
+
+	if err := graphStore.UpdateNode(ctx, *node); err != nil {
+		return result, fmt.Errorf("failed to update behavior: %w", err)
+	}
+	return result, nil
+}
+
+// runReviewSession walks the queue interactively, reading one command per
+// line from in. End of input quits.
+func runReviewSession(ctx context.Context, in io.Reader, out io.Writer, graphStore *store.MultiGraphStore, root string, pending []review.Entry) error {
+	reader := bufio.NewReader(in)
+	ask := func(prompt string) (string, bool) {
+		fmt.Fprint(out, prompt)
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			fmt.Fprintln(out)
+			return "", false
+		}
+		return strings.TrimSpace(line), true
+	}
+
+	counts := make(map[string]int)
+	defer func() {
+		fmt.Fprintf(out, "\nReviewed: %d accepted, %d rejected, %d merged, %d skipped.\n",
+			counts["accepted"], counts["rejected"], counts["merged"], counts["skipped"])
+	}()
+
+	for i := 0; i < len(pending); i++ {
+		entry := pending[i]
+		printReviewEntry(out, entry, i+1, len(pending))
+
+		answer, ok := ask("[a]ccept  [e]dit when  [r]eject  [m]erge  [s]kip  [q]uit: ")
+		if !ok {
+			return nil
+		}
+		d := reviewDecision{ID: entry.BehaviorID}
+		switch strings.ToLower(answer) {
+		case "a", "accept":
+			d.Action = "accept"
+		case "e", "edit":
+			line, ok := ask("when (JSON object, empty to keep): ")
+			if !ok {
+				return nil
+			}
+			if line != "" {
+				if err := json.Unmarshal([]byte(line), &d.When); err != nil || d.When == nil {
+					fmt.Fprintf(out, "Not a JSON object: %s\n", line)
+					i--
+					continue
+				}
+				d.Action = "edit"
+				if _, err := applyReviewDecision(ctx, graphStore, root, d, time.Now()); err != nil {
+					fmt.Fprintf(out, "Error: %v\n", err)
+				} else if err := graphStore.Sync(ctx); err != nil {
+					return fmt.Errorf("failed to sync changes: %w", err)
+				} else {
+					entry.When = d.When
+					pending[i] = entry
+				}
+			}
+			i-- // show the behavior again with its new conditions
+			continue
+		case "r", "reject":
+			d.Action = "reject"
+			if d.Reason, ok = ask("reason (optional): "); !ok {
+				return nil
+			}
+		case "m", "merge":
+			d.Action = "merge"
+			prompt := "merge into behavior ID: "
+			if entry.MergeTarget != "" {
+				prompt = fmt.Sprintf("merge into behavior ID [%s]: ", entry.MergeTarget)
+			}
+			if d.Into, ok = ask(prompt); !ok {
+				return nil
+			}
+		case "s", "skip", "":
+			counts["skipped"]++
+			continue
+		case "q", "quit":
+			return nil
+		default:
+			fmt.Fprintf(out, "Unknown command %q.\n", answer)
+			i--
+			continue
+		}
+
+		result, err := applyReviewDecision(ctx, graphStore, root, d, time.Now())
+		if err != nil {
+			fmt.Fprintf(out, "Error: %v\n", err)
+			i--
+			continue
+		}
+		if err := graphStore.Sync(ctx); err != nil {
+			return fmt.Errorf("failed to sync changes: %w", err)
+		}
+		counts[result.Status]++
+		switch result.Status {
+		case "merged":
+			fmt.Fprintf(out, "Merged into %s. Undo with: floop restore-point apply %s\n", result.Into, result.RestorePoint)
+		default:
+			fmt.Fprintf(out, "%s.\n", strings.ToUpper(result.Status[:1])+result.Status[1:])
+		}
+	}
+	return nil
+}
+
+// printReviewEntry shows a pending behavior and why it was flagged.
+func printReviewEntry(out io.Writer, e review.Entry, n, total int) {
+	fmt.Fprintf(out, "\n[%d/%d] %s (%s)  %s", n, total, e.Name, e.BehaviorID, e.Kind)
+	if e.Origin != "" {
+		fmt.Fprintf(out, ", %s store", e.Origin)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "  %s\n", e.Canonical)
+	when := "{}"
+	if len(e.When) > 0 {
+		data, _ := json.Marshal(e.When)
+		when = string(data)
+	}
+	fmt.Fprintf(out, "  when: %s\n", when)
+	if !e.RequestedAt.IsZero() {
+		fmt.Fprintf(out, "  Flagged %s:\n", e.RequestedAt.Local().Format("2006-01-02 15:04"))
+	}
+	for _, r := range e.Reasons {
+		fmt.Fprintf(out, "    - %s\n", r)
+	}
+	if e.MergeTarget != "" {
+		fmt.Fprintf(out, "  Suggested merge target: %s\n", e.MergeTarget)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/review"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// setupReviewTest initializes a project with three behaviors pending review
+// (b-accept, b-reject, b-merge, oldest first) and the active behavior
+// b-target that b-merge duplicates.
+func setupReviewTest(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newInitCmd())
+	rootCmd.SetArgs([]string{"init", "--root", tmpDir})
+	rootCmd.SetOut(&bytes.Buffer{})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	gs, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	defer gs.Close()
+	now := time.Now().Add(-time.Hour)
+	for i, id := range []string{"b-accept", "b-reject", "b-merge", "b-target"} {
+		node := store.Node{
+			ID:   id,
+			Kind: store.NodeKindBehavior,
+			Content: map[string]interface{}{
+				"name":    id,
+				"kind":    "directive",
+				"content": map[string]interface{}{"canonical": id + " canonical"},
+			},
+			Metadata: map[string]interface{}{"confidence": 0.6},
+		}
+		switch id {
+		case "b-merge":
+			review.Mark(&node, []string{"Would merge into existing behavior: b-target"}, "b-target", now.Add(time.Duration(i)*time.Minute))
+		case "b-target":
+		default:
+			review.Mark(&node, []string{"Low placement confidence: 0.40"}, "", now.Add(time.Duration(i)*time.Minute))
+		}
+		if _, err := gs.AddNode(context.Background(), node); err != nil {
+			t.Fatalf("AddNode(%s) error = %v", id, err)
+		}
+	}
+	return tmpDir
+}
+
+func runReviewCmd(t *testing.T, root, stdin string, args ...string) (string, error) {
+	t.Helper()
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newReviewCmd())
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetIn(strings.NewReader(stdin))
+	rootCmd.SetArgs(append([]string{"review", "--root", root}, args...))
+	err := rootCmd.Execute()
+	return out.String(), err
+}
+
+func reviewNode(t *testing.T, root, id string) *store.Node {
+	t.Helper()
+	gs, err := store.NewMultiGraphStore(root)
+	if err != nil {
+		t.Fatalf("NewMultiGraphStore() error = %v", err)
+	}
+	defer gs.Close()
+	node, err := gs.GetNode(context.Background(), id)
+	if err != nil || node == nil {
+		t.Fatalf("GetNode(%s) = %v, %v", id, node, err)
+	}
+	return node
+}
+
+func TestReviewCmd_Batch(t *testing.T) {
+	root := setupReviewTest(t)
+
+	out, err := runReviewCmd(t, root, "", "--json")
+	if err != nil {
+		t.Fatalf("review --json failed: %v", err)
+	}
+	var queue struct {
+		Pending []review.Entry `json:"pending"`
+		Count   int            `json:"count"`
+	}
+	if err := json.Unmarshal([]byte(out), &queue); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if queue.Count != 3 || queue.Pending[0].BehaviorID != "b-accept" || queue.Pending[2].MergeTarget != "b-target" {
+		t.Errorf("queue = %+v, want the three flagged behaviors oldest first", queue)
+	}
+
+	decisions := `[
+		{"id": "b-accept", "action": "accept", "when": {"language": "go"}},
+		{"id": "b-reject", "action": "reject", "reason": "too vague"},
+		{"id": "b-merge", "action": "merge"},
+		{"id": "b-target", "action": "accept"},
+		{"id": "b-accept", "action": "launch"}
+	]`
+	out, err = runReviewCmd(t, root, decisions, "--decisions", "-", "--json")
+	if err == nil || !strings.Contains(err.Error(), "2 review decision(s) failed") {
+		t.Errorf("review --decisions error = %v, want 2 failures", err)
+	}
+	var batch struct {
+		Results []reviewResult `json:"results"`
+		Failed  int            `json:"failed"`
+		Pending int            `json:"pending"`
+	}
+	if err := json.NewDecoder(strings.NewReader(out)).Decode(&batch); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	wantStatus := []string{"accepted", "rejected", "merged", "error", "error"}
+	for i, want := range wantStatus {
+		if batch.Results[i].Status != want {
+			t.Errorf("result %d = %+v, want status %s", i, batch.Results[i], want)
+		}
+	}
+	if batch.Pending != 0 || batch.Results[2].Into != "b-target" || batch.Results[2].RestorePoint == "" {
+		t.Errorf("batch = %+v", batch)
+	}
+
+	accepted := reviewNode(t, root, "b-accept")
+	if review.IsPending(*accepted) || accepted.Content["when"].(map[string]interface{})["language"] != "go" {
+		t.Errorf("accepted node = %+v, want resolved with the new when", accepted)
+	}
+	if n := reviewNode(t, root, "b-reject"); n.Kind != store.NodeKindForgotten || n.Metadata["forget_reason"] != "too vague" {
+		t.Errorf("rejected node = %+v, want forgotten with the reason", n)
+	}
+	if n := reviewNode(t, root, "b-merge"); n.Kind != store.NodeKindMerged || n.Metadata["merged_into"] != "b-target" {
+		t.Errorf("merged node = %+v, want merged into b-target", n)
+	}
+}
+
+func TestReviewCmd_Interactive(t *testing.T) {
+	root := setupReviewTest(t)
+
+	// Edit then accept b-accept, skip b-reject, and quit at b-merge
+	input := strings.Join([]string{"e", `{"task": "testing"}`, "a", "s", "q"}, "\n") + "\n"
+	out, err := runReviewCmd(t, root, input)
+	if err != nil {
+		t.Fatalf("review failed: %v", err)
+	}
+	for _, want := range []string{"[1/3] b-accept", "Low placement confidence", `when: {"task":"testing"}`, "Accepted.", "[2/3] b-reject", "Reviewed: 1 accepted, 0 rejected, 0 merged, 1 skipped."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if n := reviewNode(t, root, "b-merge"); !review.IsPending(*n) {
+		t.Error("behavior shown at quit left the queue")
+	}
+
+	if n := reviewNode(t, root, "b-accept"); review.IsPending(*n) || n.Content["when"].(map[string]interface{})["task"] != "testing" {
+		t.Errorf("b-accept = %+v, want accepted with the edited when", n)
+	}
+	if n := reviewNode(t, root, "b-reject"); !review.IsPending(*n) {
+		t.Error("skipped behavior left the queue")
+	}
+
+	// End of input ends the session; the merge target is the default
+	out, err = runReviewCmd(t, root, "s\nm\n\n")
+	if err != nil {
+		t.Fatalf("review failed: %v", err)
+	}
+	if !strings.Contains(out, "merge into behavior ID [b-target]") || !strings.Contains(out, "Merged into b-target.") {
+		t.Errorf("merge output:\n%s", out)
+	}
+}
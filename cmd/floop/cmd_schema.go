@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func newSchemaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema [sqlite|jsonl|backup]",
+		Short: "Describe the on-disk formats of a .floop directory",
+		Long: `Describe the formats this floop build reads and writes: the SQLite store
+schema, the records of its JSONL files, and the backup file formats.
+
+With --json the description is emitted as JSON Schema documents generated
+from the code, so tools that read .floop directly can validate against the
+running build. Name a section to emit only that part.
+
+Examples:
+  floop schema                 # Human-readable summary
+  floop schema --json          # Every format as JSON
+  floop schema jsonl --json    # JSONL record schemas only`,
+		Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: schema.Sections,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			out := cmd.OutOrStdout()
+
+			doc, err := schema.Describe(context.Background(), version)
+			if err != nil {
+				return fmt.Errorf("failed to describe schema: %w", err)
+			}
+
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if len(args) == 1 {
+					return enc.Encode(doc.Section(args[0]))
+				}
+				return enc.Encode(doc)
+			}
+
+			show := func(section string) bool {
+				return len(args) == 0 || args[0] == section
+			}
+			fmt.Fprintf(out, "floop %s\n", doc.FloopVersion)
+			if show(schema.SectionSQLite) {
+				fmt.Fprintf(out, "\nSQLite schema (version %d):\n", doc.SQLite.Version)
+				for _, table := range doc.SQLite.Tables {
+					var columns []string
+					for _, col := range table.Columns {
+						columns = append(columns, col.Name)
+					}
+					fmt.Fprintf(out, "  %-22s %s\n", table.Name, strings.Join(columns, ", "))
+				}
+			}
+			if show(schema.SectionJSONL) {
+				fmt.Fprintln(out, "\nJSONL files:")
+				for _, f := range doc.JSONL {
+					fields := make([]string, 0, len(f.Record.Properties))
+					for name := range f.Record.Properties {
+						fields = append(fields, name)
+					}
+					slices.Sort(fields)
+					fmt.Fprintf(out, "  %-22s %s\n", f.File, strings.Join(fields, ", "))
+				}
+			}
+			if show(schema.SectionBackup) {
+				fmt.Fprintln(out, "\nBackup formats:")
+				for _, b := range doc.Backup {
+					fmt.Fprintf(out, "  v%d  %s\n", b.Version, b.Layout)
+				}
+			}
+			return nil
+		},
+	}
+}
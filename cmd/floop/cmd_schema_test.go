@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/schema"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestSchemaCmd(t *testing.T) {
+	run := func(t *testing.T, args ...string) (string, error) {
+		t.Helper()
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newSchemaCmd())
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs(append([]string{"schema"}, args...))
+		err := rootCmd.Execute()
+		return buf.String(), err
+	}
+
+	t.Run("json", func(t *testing.T) {
+		out, err := run(t, "--json")
+		if err != nil {
+			t.Fatalf("schema --json failed: %v", err)
+		}
+		var doc schema.Document
+		if err := json.Unmarshal([]byte(out), &doc); err != nil {
+			t.Fatalf("invalid JSON output: %v\n%s", err, out)
+		}
+		if doc.SQLite == nil || doc.SQLite.Version != store.SchemaVersion || len(doc.JSONL) == 0 || len(doc.Backup) == 0 {
+			t.Errorf("document = %+v", doc)
+		}
+	})
+
+	t.Run("section", func(t *testing.T) {
+		out, err := run(t, "jsonl", "--json")
+		if err != nil {
+			t.Fatalf("schema jsonl --json failed: %v", err)
+		}
+		var files []schema.JSONLFile
+		if err := json.Unmarshal([]byte(out), &files); err != nil {
+			t.Fatalf("invalid JSON output: %v\n%s", err, out)
+		}
+		if len(files) == 0 || files[0].Record == nil {
+			t.Errorf("jsonl section = %+v", files)
+		}
+	})
+
+	t.Run("text", func(t *testing.T) {
+		out, err := run(t)
+		if err != nil {
+			t.Fatalf("schema failed: %v", err)
+		}
+		for _, want := range []string{"SQLite schema", "behaviors", "edges.jsonl", "v2"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("output missing %q:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("unknown section", func(t *testing.T) {
+		if _, err := run(t, "events"); err == nil {
+			t.Error("schema events succeeded, want an error")
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// searchResult is a behavior found by keyword search.
+type searchResult struct {
+	Behavior models.Behavior `json:"behavior"`
+	Score    float64         `json:"score"`
+}
+
+func newSearchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Find behaviors by keyword",
+		Long: `Search behavior names, content, and tags for the words of the query,
+most relevant first. Words match by prefix and by stem, so "test" also
+finds "tests" and "testing"; a behavior matching any word is a hit.
+
+Unlike 'floop similar', which compares whole texts, search looks up
+keywords in a full-text index and needs no embeddings. Run it before
+learning a behavior to see what the store already knows about a topic.
+
+Examples:
+  floop search logging
+  floop search "error wrapping" --limit 5
+  floop search sqlite migration --json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			limit, _ := cmd.Flags().GetInt("limit")
+			query := strings.Join(args, " ")
+			out := cmd.OutOrStdout()
+
+			if limit < 1 {
+				return fmt.Errorf("--limit must be at least 1")
+			}
+
+			floopDir := filepath.Join(root, ".floop")
+			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
+				return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+			}
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			hits, err := store.SearchBehaviors(context.Background(), graphStore, query, limit)
+			if err != nil {
+				return fmt.Errorf("search failed: %w", err)
+			}
+			results := make([]searchResult, len(hits))
+			for i, h := range hits {
+				results[i] = searchResult{Behavior: behaviorWithOrigin(h.Node, ""), Score: h.Score}
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"query":   query,
+					"results": results,
+					"count":   len(results),
+				})
+			}
+
+			if len(results) == 0 {
+				fmt.Fprintf(out, "No behaviors match %q.\n", query)
+				return nil
+			}
+			fmt.Fprintf(out, "Behaviors matching %q:\n\n", query)
+			for i, r := range results {
+				fmt.Fprintf(out, "%d. [%s] %s (%.2f)\n", i+1, r.Behavior.Kind, r.Behavior.Name, r.Score)
+				fmt.Fprintf(out, "   %s\n", truncatePreview(r.Behavior.Content.Canonical, 100))
+				fmt.Fprintf(out, "   ID: %s", r.Behavior.ID)
+				if r.Behavior.Origin != "" {
+					fmt.Fprintf(out, " (%s)", r.Behavior.Origin)
+				}
+				fmt.Fprintln(out)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Int("limit", store.DefaultSearchLimit, "Maximum number of behaviors to show")
+
+	return cmd
+}
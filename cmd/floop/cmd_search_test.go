@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSearchCmd(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	t.Run("json", func(t *testing.T) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newSearchCmd())
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs([]string{"search", "structured", "logs", "--json", "--root", tmpDir})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("search --json failed: %v", err)
+		}
+
+		var result struct {
+			Query   string `json:"query"`
+			Count   int    `json:"count"`
+			Results []struct {
+				Behavior struct {
+					ID string `json:"id"`
+				} `json:"behavior"`
+				Score float64 `json:"score"`
+			} `json:"results"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+			t.Fatalf("invalid JSON output: %v\n%s", err, buf.String())
+		}
+		if result.Query != "structured logs" || result.Count != 1 {
+			t.Fatalf("result = %+v, want one hit for the joined query", result)
+		}
+		if r := result.Results[0]; r.Behavior.ID == "" || r.Score <= 0 {
+			t.Errorf("hit = %+v, want an ID and a positive score", r)
+		}
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newSearchCmd())
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs([]string{"search", "chromodynamics", "--root", tmpDir})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		if !strings.Contains(buf.String(), "No behaviors match") {
+			t.Errorf("output = %q, want no matches", buf.String())
+		}
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newSearchCmd())
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetArgs([]string{"search", "slog", "--limit", "0", "--root", tmpDir})
+		if err := rootCmd.Execute(); err == nil {
+			t.Error("expected an error for --limit 0")
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/pack"
+	"github.com/nvandessel/floop/internal/selfupdate"
+	"github.com/spf13/cobra"
+)
+
+func newSelfUpdateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Update the floop binary to the latest GitHub release",
+		Long: `Download the latest floop release from GitHub and replace the running binary.
+
+The release archive for this platform is verified against the release's
+checksums.txt (SHA-256) before anything is replaced. Releases are not
+signed, so the checksum guards against corrupted or truncated downloads
+served by GitHub, not against a compromised release.
+
+The binary is replaced atomically: the new file is written next to the old
+one and renamed over it. On Windows the running binary is moved aside to
+floop.exe.old and removed on the next self-update.
+
+Channels:
+  stable  Latest full release (default)
+  edge    Newest release, including prereleases
+
+Examples:
+  floop self-update                  # Update to the latest stable release
+  floop self-update --check          # Only report whether an update exists
+  floop self-update --channel edge   # Follow prereleases
+  floop self-update --force          # Reinstall even if already up to date`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			channelFlag, _ := cmd.Flags().GetString("channel")
+			check, _ := cmd.Flags().GetBool("check")
+			force, _ := cmd.Flags().GetBool("force")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			channel, err := selfupdate.ParseChannel(channelFlag)
+			if err != nil {
+				return err
+			}
+
+			exePath, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("failed to locate running binary: %w", err)
+			}
+			if resolved, err := filepath.EvalSymlinks(exePath); err == nil {
+				exePath = resolved
+			}
+
+			if !check && !force {
+				if version == "dev" {
+					return fmt.Errorf("this is a development build; use --force to replace it with a release")
+				}
+				if strings.Contains(filepath.ToSlash(exePath), "/Cellar/") {
+					return fmt.Errorf("floop was installed by Homebrew; run 'brew upgrade floop' or use --force")
+				}
+			}
+			selfupdate.CleanupOld(exePath)
+
+			ctx, cancel := context.WithTimeout(context.Background(), selfupdate.DownloadTimeout)
+			defer cancel()
+
+			release, err := selfupdate.LatestRelease(ctx, pack.NewGitHubClient(), channel)
+			if err != nil {
+				return fmt.Errorf("failed to check for updates: %w", err)
+			}
+
+			current := strings.TrimPrefix(version, "v")
+			latest := pack.ReleaseVersion(release)
+			available := version == "dev" || selfupdate.CompareVersions(latest, current) > 0
+
+			result := map[string]interface{}{
+				"current":   current,
+				"latest":    latest,
+				"channel":   string(channel),
+				"available": available,
+				"updated":   false,
+			}
+
+			if check || (!available && !force) {
+				if jsonOut {
+					return json.NewEncoder(os.Stdout).Encode(result)
+				}
+				switch {
+				case available:
+					fmt.Printf("Update available: %s -> %s (%s channel)\n", current, latest, channel)
+					fmt.Println("Run 'floop self-update' to install it.")
+				default:
+					fmt.Printf("floop %s is up to date (%s channel, latest %s)\n", current, channel, latest)
+				}
+				return nil
+			}
+
+			if !jsonOut {
+				fmt.Printf("Updating floop %s -> %s (%s/%s)...\n", current, latest, runtime.GOOS, runtime.GOARCH)
+			}
+			client := &http.Client{Timeout: selfupdate.DownloadTimeout}
+			if err := selfupdate.Apply(ctx, client, release, runtime.GOOS, runtime.GOARCH, exePath); err != nil {
+				return fmt.Errorf("self-update failed: %w", err)
+			}
+
+			result["updated"] = true
+			result["path"] = exePath
+			if jsonOut {
+				return json.NewEncoder(os.Stdout).Encode(result)
+			}
+			fmt.Printf("Checksum verified; installed floop %s at %s\n", latest, exePath)
+			return nil
+		},
+	}
+
+	cmd.Flags().String("channel", string(selfupdate.ChannelStable), "Release channel: stable or edge")
+	cmd.Flags().Bool("check", false, "Only check whether an update is available")
+	cmd.Flags().Bool("force", false, "Install even if already up to date, a development build, or Homebrew-managed")
+
+	return cmd
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/prompt"
+	"github.com/nvandessel/floop/internal/remote"
+	"github.com/spf13/cobra"
+)
+
+func newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve compiled prompts over HTTP to remote clients",
+		Long: `Run floop in HTTP mode: a read-only server that compiles prompts from this
+machine's behaviors for thin clients without a local store, such as remote
+dev boxes and codespaces. Clients fetch prompts with 'floop remote-prompt'.
+
+The server answers GET /v1/prompt with the compiled prompt, the top active
+behaviors, and an ETag. Context (file, language, task, env, agent) comes
+from query parameters. Nothing on the server is modified.
+
+Behaviors come from the global store, plus the project store under --root
+when one exists. The server has no authentication and listens on localhost
+by default; expose it only on networks you trust.
+
+Examples:
+  floop serve                          # Listen on 127.0.0.1:8765
+  floop serve --addr 0.0.0.0:8765      # Accept connections from other machines
+  curl 'http://127.0.0.1:8765/v1/prompt?file=main.go'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			addr, _ := cmd.Flags().GetString("addr")
+
+			ln, err := net.Listen("tcp", addr)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %s: %w", addr, err)
+			}
+			srv := &http.Server{
+				Handler:           remote.NewHandler(remotePromptCompiler(root)),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+
+			sigCh := make(chan os.Signal, 1)
+			notifySignals(sigCh)
+			defer signal.Stop(sigCh)
+			go func() {
+				<-sigCh
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				srv.Shutdown(shutdownCtx)
+			}()
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Serving prompts at http://%s%s\n", ln.Addr(), remote.PromptPath)
+			fmt.Fprintf(cmd.OutOrStdout(), "Press Ctrl-C to stop.\n")
+			if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("server error: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("addr", "127.0.0.1:8765", "Address to listen on")
+
+	return cmd
+}
+
+// remotePromptCompiler compiles prompts for remote requests the way
+// 'floop prompt' does, re-reading the stores on every request so clients
+// always see current behaviors. Content sniffing is off because the
+// client's files are not on this machine.
+func remotePromptCompiler(root string) remote.CompileFunc {
+	return func(ctx context.Context, req remote.Request) (*remote.Response, error) {
+		scope := constants.ScopeGlobal
+		if _, err := os.Stat(filepath.Join(root, ".floop")); err == nil {
+			scope = constants.ScopeBoth
+		}
+		behaviors, err := loadBehaviorsWithScope(root, scope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load behaviors: %w", err)
+		}
+
+		actCtx := activation.NewContextBuilder().
+			WithFile(req.File).
+			WithLanguage(req.Language).
+			WithTask(req.Task).
+			WithEnvironment(req.Env).
+			WithAgent(req.Agent).
+			Build()
+		resolved := prompt.Resolve(newEvaluator(), actCtx, behaviors, activation.KindFilter{})
+
+		var promptCfg config.PromptConfig
+		if cfg, err := config.Load(); err == nil {
+			promptCfg = cfg.Prompt
+		}
+		outputFormat, err := prompt.ParseFormat(req.Format)
+		if err != nil {
+			return nil, err
+		}
+		result, err := prompt.Compile(resolved, promptCfg, prompt.Options{
+			Format:      outputFormat,
+			Task:        req.Task,
+			TokenBudget: req.TokenBudget,
+		})
+		if err != nil {
+			return nil, err
+		}
+		compiled, active := result.Prompt, result.Included
+
+		resp := &remote.Response{
+			Prompt: compiled.Text,
+			Format: string(compiled.Format),
+			Tokens: compiled.TotalTokens,
+		}
+		for i, b := range active {
+			if req.Top > 0 && i >= req.Top {
+				break
+			}
+			resp.Behaviors = append(resp.Behaviors, remoteBehavior(b))
+		}
+		return resp, nil
+	}
+}
+
+// remoteBehavior converts a behavior to its compact wire form.
+func remoteBehavior(b models.Behavior) remote.Behavior {
+	return remote.Behavior{
+		ID:         b.ID,
+		Name:       b.Name,
+		Kind:       string(b.Kind),
+		Content:    b.Content.Canonical,
+		Confidence: b.Confidence,
+	}
+}
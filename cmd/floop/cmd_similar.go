@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/dedup"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newSimilarCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "similar",
+		Short: "Find behaviors similar to example text",
+		Long: `Rank existing behaviors by similarity to example text, using the same
+scoring as deduplication: embeddings when an embedding-capable LLM is
+configured, otherwise word overlap.
+
+Run it before learning a behavior by hand to see whether a near-duplicate
+already exists. Matches marked "duplicate" would be flagged by
+'floop deduplicate'.
+
+Examples:
+  floop similar --text "prefer context.Context as first arg"
+  floop similar --text "use pathlib" --limit 5 --min-score 0.3
+  floop similar --text "wrap errors with %w" --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			text, _ := cmd.Flags().GetString("text")
+			limit, _ := cmd.Flags().GetInt("limit")
+			minScore, _ := cmd.Flags().GetFloat64("min-score")
+			out := cmd.OutOrStdout()
+
+			if minScore < 0 || minScore > 1 {
+				return fmt.Errorf("--min-score must be between 0.0 and 1.0")
+			}
+
+			floopDir := filepath.Join(root, ".floop")
+			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
+				return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+			}
+
+			floopCfg, err := config.Load()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to load config: %v\n", err)
+			}
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			matches, err := dedup.FindSimilar(context.Background(), graphStore, text, dedup.SimilarOptions{
+				SimilarityThreshold: constants.DefaultAutoMergeThreshold,
+				EmbeddingThreshold:  constants.DefaultEmbeddingDedupThreshold,
+				LLMClient:           createLLMClient(floopCfg),
+				MinScore:            minScore,
+				Limit:               limit,
+			})
+			if err != nil {
+				return fmt.Errorf("similarity search failed: %w", err)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"text":    text,
+					"matches": matches,
+				})
+			}
+
+			if len(matches) == 0 {
+				fmt.Fprintln(out, "No similar behaviors found.")
+				return nil
+			}
+			fmt.Fprintf(out, "Behaviors similar to %q:\n\n", text)
+			for i, m := range matches {
+				marker := ""
+				if m.WouldDuplicate {
+					marker = "  [duplicate]"
+				}
+				fmt.Fprintf(out, "%d. %s (%.2f, %s)%s\n", i+1, m.Behavior.Name, m.Score, m.Method, marker)
+				fmt.Fprintf(out, "   %s\n", truncatePreview(m.Behavior.Content.Canonical, 100))
+				fmt.Fprintf(out, "   ID: %s\n", m.Behavior.ID)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("text", "", "Example behavior text to compare against the store (required)")
+	cmd.Flags().Int("limit", 10, "Maximum number of matches to show (0 for all)")
+	cmd.Flags().Float64("min-score", 0.1, "Minimum similarity score to show (0.0-1.0)")
+	cmd.MarkFlagRequired("text")
+
+	return cmd
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSimilarCmd(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	t.Run("json", func(t *testing.T) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newSimilarCmd())
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs([]string{"similar", "--text", "use slog structured logging", "--json", "--root", tmpDir})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("similar --json failed: %v", err)
+		}
+
+		var result struct {
+			Matches []struct {
+				Score          float64 `json:"score"`
+				Method         string  `json:"method"`
+				WouldDuplicate bool    `json:"would_duplicate"`
+			} `json:"matches"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+			t.Fatalf("invalid JSON output: %v\n%s", err, buf.String())
+		}
+		if len(result.Matches) == 0 {
+			t.Fatal("expected the learned behavior to match")
+		}
+		if top := result.Matches[0]; top.Method != "jaccard" || !top.WouldDuplicate {
+			t.Errorf("top match = %+v, want jaccard duplicate", top)
+		}
+	})
+
+	t.Run("text", func(t *testing.T) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newSimilarCmd())
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs([]string{"similar", "--text", "quantum chromodynamics", "--min-score", "0.5", "--root", tmpDir})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("similar failed: %v", err)
+		}
+		if !strings.Contains(buf.String(), "No similar behaviors found") {
+			t.Errorf("output = %q, want no matches", buf.String())
+		}
+	})
+
+	t.Run("invalid min-score", func(t *testing.T) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newSimilarCmd())
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetArgs([]string{"similar", "--text", "x", "--min-score", "2", "--root", tmpDir})
+		if err := rootCmd.Execute(); err == nil {
+			t.Error("expected error for --min-score out of range")
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/corrections"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/quota"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newStatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show store usage against storage limits",
+		Long: `Show how much of each storage limit the local and global stores use.
+
+Limits are configured with limits.max_behaviors_per_scope,
+limits.max_canonical_length, and limits.max_edges_per_node (0 = unlimited).
+Usage at 90% of a limit or more is flagged; once a limit is reached, learning
+proposes consolidation candidates instead of adding behaviors.
+
+Examples:
+  floop status          # Usage per scope with warnings
+  floop status --json   # Machine-readable usage report`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			limits := loadStorageLimits()
+			stores := map[string]store.GraphStore{
+				"local":  graphStore.LocalStore(),
+				"global": graphStore.GlobalStore(),
+			}
+			usage, err := quota.Report(context.Background(), stores, limits)
+			if err != nil {
+				return err
+			}
+
+			var warnings []quota.Usage
+			for _, u := range usage {
+				if u.Status != quota.StatusOK {
+					warnings = append(warnings, u)
+				}
+			}
+
+			if jsonOut {
+				if usage == nil {
+					usage = []quota.Usage{}
+				}
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"usage":    usage,
+					"warnings": len(warnings),
+				})
+			}
+
+			if len(usage) == 0 {
+				fmt.Println("All storage limits are disabled.")
+				return nil
+			}
+
+			fmt.Println("Storage usage:")
+			for _, u := range usage {
+				fmt.Printf("  %-8s %-26s %6d / %-6d %s\n", u.Scope, u.Limit, u.Used, u.Max, u.Status)
+			}
+			if len(warnings) > 0 {
+				fmt.Println()
+				for _, u := range warnings {
+					state := "is approaching"
+					if u.Status == quota.StatusExceeded {
+						state = "has reached"
+					}
+					fmt.Printf("Warning: %s store %s %s (%d/%d)\n", u.Scope, state, u.Limit, u.Used, u.Max)
+				}
+				fmt.Println("Run 'floop deduplicate', 'floop merge', or 'floop forget' to make room.")
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// loadStorageLimits returns the configured storage limits, or the defaults
+// when config cannot be loaded.
+func loadStorageLimits() quota.Limits {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.Default()
+	}
+	return quota.Limits{
+		MaxBehaviorsPerScope: cfg.Limits.MaxBehaviorsPerScope,
+		MaxCanonicalLength:   cfg.Limits.MaxCanonicalLength,
+		MaxEdgesPerNode:      cfg.Limits.MaxEdgesPerNode,
+	}
+}
+
+// withStorageLimits adds the configured storage limits to loopConfig,
+// allocating a default config if loopConfig is nil.
+func withStorageLimits(loopConfig *learning.LearningLoopConfig) *learning.LearningLoopConfig {
+	if loopConfig == nil {
+		defaults := learning.DefaultLearningLoopConfig()
+		loopConfig = &defaults
+	}
+	loopConfig.Limits = loadStorageLimits()
+	return loopConfig
+}
+
+// deferCorrection records correction as unprocessed, so 'floop reprocess'
+// can learn it once consolidation has made room.
+func deferCorrection(floopDir string, correction models.Correction) error {
+	correction.Processed = false
+	correction.ProcessedAt = nil
+
+	if err := corrections.Append(floopDir, correction); err != nil {
+		return fmt.Errorf("failed to record correction: %w", err)
+	}
+	return nil
+}
+
+// printQuotaExceeded describes a reached storage limit and the consolidation
+// candidates proposed to make room.
+func printQuotaExceeded(exceeded *learning.QuotaExceeded) {
+	fmt.Printf("Storage limit reached: %s\n", exceeded.Usage)
+	if len(exceeded.Candidates) == 0 {
+		return
+	}
+	fmt.Println("Consolidation candidates:")
+	for _, c := range exceeded.Candidates {
+		name := c.BehaviorID
+		if c.Name != "" {
+			name = fmt.Sprintf("%s (%s)", c.BehaviorID, c.Name)
+		}
+		fmt.Printf("  - %s %s: %s\n", c.Action, name, c.Reason)
+	}
+}
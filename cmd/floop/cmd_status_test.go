@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/quota"
+	"github.com/spf13/cobra"
+)
+
+// statusTestCmds returns fresh commands for the tests in this file.
+func statusTestCmds() []*cobra.Command {
+	return []*cobra.Command{newInitCmd(), newLearnCmd(), newStatusCmd()}
+}
+
+func TestStatusCmd_StorageLimits(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	t.Setenv("FLOOP_LIMITS_MAX_BEHAVIORS_PER_SCOPE", "1")
+
+	if _, err := runTestCmd(t, statusTestCmds(), "init", "--root", tmpDir); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if _, err := runTestCmd(t, statusTestCmds(), "learn", "--right", "use pathlib.Path instead of os.path", "--scope", "local", "--root", tmpDir, "--json"); err != nil {
+		t.Fatalf("learn (first) failed: %v", err)
+	}
+
+	// The local store is full: the next correction is deferred, not learned
+	out, err := runTestCmd(t, statusTestCmds(), "learn", "--right", "prefer table-driven tests in Go", "--scope", "local", "--root", tmpDir, "--json")
+	if err != nil {
+		t.Fatalf("learn (second) failed: %v", err)
+	}
+	var learned map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &learned); err != nil {
+		t.Fatalf("invalid learn JSON %q: %v", out, err)
+	}
+	if learned["status"] != "limit_reached" {
+		t.Errorf("status = %v, want limit_reached", learned["status"])
+	}
+
+	var deferred []models.Correction
+	for _, c := range loggedCorrections(t, tmpDir) {
+		if !c.Processed {
+			deferred = append(deferred, c)
+		}
+	}
+	if len(deferred) != 1 || deferred[0].CorrectedAction != "prefer table-driven tests in Go" {
+		t.Errorf("unprocessed corrections = %+v, want the deferred one", deferred)
+	}
+
+	out, err = runTestCmd(t, statusTestCmds(), "status", "--root", tmpDir, "--json")
+	if err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+	var report struct {
+		Usage    []quota.Usage `json:"usage"`
+		Warnings int           `json:"warnings"`
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("invalid status JSON %q: %v", out, err)
+	}
+	found := false
+	for _, u := range report.Usage {
+		if u.Scope == "local" && u.Limit == quota.LimitBehaviors {
+			found = true
+			if u.Used != 1 || u.Status != quota.StatusExceeded {
+				t.Errorf("local behaviors = %+v, want 1/1 exceeded", u)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("status report missing local %s: %+v", quota.LimitBehaviors, report.Usage)
+	}
+	if report.Warnings == 0 {
+		t.Error("expected status to warn about the reached limit")
+	}
+
+	out, err = runTestCmd(t, statusTestCmds(), "status", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+	if !strings.Contains(out, "Warning: local store has reached max_behaviors_per_scope") {
+		t.Errorf("status output missing warning:\n%s", out)
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newSyncCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Share behaviors with a team through a git remote",
+		Long: `Share learned behaviors through a dedicated git branch, without copying
+SQLite files.
+
+The branch holds nodes.jsonl and edges.jsonl. 'floop sync pull' merges it
+into the store, and 'floop sync push' pulls, then commits the merged result
+and pushes it. Both work from a clone in .floop/sync.
+
+Conflicts are resolved per behavior: identical content is left alone, and
+otherwise the most recently updated copy wins. Usage stats stay local, and
+deletions are not shared; forget a behavior to retire it for everyone.
+
+The remote is --remote, sync.remote, or the project's origin remote, and the
+branch is --branch or sync.branch (default floop-behaviors).
+
+Examples:
+  floop sync push
+  floop sync pull
+  floop sync push --remote git@github.com:my-org/behaviors.git --branch main`,
+	}
+
+	cmd.PersistentFlags().String("remote", "", "Git remote to sync with (default: sync.remote, then the project's origin)")
+	cmd.PersistentFlags().String("branch", "", "Branch holding the shared behaviors (default: sync.branch)")
+	cmd.PersistentFlags().String("scope", "local", "Store to sync: local or global")
+
+	cmd.AddCommand(
+		newSyncDirectionCmd("push", "Merge the store into the shared branch and push it"),
+		newSyncDirectionCmd("pull", "Merge the shared branch into the store"),
+	)
+	return cmd
+}
+
+func newSyncDirectionCmd(direction, short string) *cobra.Command {
+	return &cobra.Command{
+		Use:   direction,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			remote, _ := cmd.Flags().GetString("remote")
+			branch, _ := cmd.Flags().GetString("branch")
+			scope, _ := cmd.Flags().GetString("scope")
+
+			storeScope := store.StoreScope(scope)
+			if storeScope != store.ScopeLocal && storeScope != store.ScopeGlobal {
+				return fmt.Errorf("invalid scope: %s (must be local or global)", scope)
+			}
+			cfg, err := config.Load()
+			if err != nil {
+				cfg = config.Default()
+			}
+			if remote == "" {
+				remote = cfg.Sync.Remote
+			}
+			if remote == "" {
+				if remote, err = projectOrigin(root); err != nil {
+					return fmt.Errorf("no sync remote: set --remote or sync.remote, or add an origin remote to the project")
+				}
+			}
+			if branch == "" {
+				branch = cfg.Sync.Branch
+			}
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			target, floopDir := graphStore.LocalStore(), store.LocalFloopPath(root)
+			if storeScope == store.ScopeGlobal {
+				target = graphStore.GlobalStore()
+				if floopDir, err = store.GlobalFloopPath(); err != nil {
+					return err
+				}
+			}
+			syncStore, err := store.NewGitSyncStore(target, store.GitSyncConfig{
+				Remote: remote,
+				Branch: branch,
+				Dir:    filepath.Join(floopDir, "sync"),
+			})
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			var result *store.GitSyncResult
+			if direction == "push" {
+				result, err = syncStore.Push(ctx)
+			} else {
+				result, err = syncStore.Pull(ctx)
+			}
+			if err != nil {
+				return fmt.Errorf("sync %s failed: %w", direction, err)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(result)
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Pulled from %s (%s): %d added, %d updated, %d kept local, %d edges added\n",
+				remote, branch, len(result.Added), len(result.Updated), len(result.Kept), result.EdgesAdded)
+			for _, id := range result.Added {
+				fmt.Fprintf(out, "  + %s\n", id)
+			}
+			for _, id := range result.Updated {
+				fmt.Fprintf(out, "  ~ %s\n", id)
+			}
+			for _, id := range result.Kept {
+				fmt.Fprintf(out, "  = %s\n", id)
+			}
+			if direction == "push" {
+				if result.Commit != "" {
+					fmt.Fprintf(out, "Pushed %s to %s\n", result.Commit, branch)
+				} else {
+					fmt.Fprintln(out, "Nothing to push")
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// projectOrigin returns the URL of the origin remote of the repo at root.
+func projectOrigin(root string) (string, error) {
+	out, err := exec.Command("git", "-C", root, "remote", "get-url", "origin").Output()
+	if err != nil {
+		return "", err
+	}
+	url := strings.TrimSpace(string(out))
+	if url == "" {
+		return "", fmt.Errorf("origin remote has no URL")
+	}
+	return url, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// syncTestCmds returns fresh commands for the tests in this file.
+func syncTestCmds() []*cobra.Command {
+	return []*cobra.Command{newInitCmd(), newLearnCmd(), newSyncCmd()}
+}
+
+func TestSyncPushPull(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	remote := filepath.Join(tmpDir, "shared.git")
+	if out, err := exec.Command("git", "init", "-q", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v: %s", err, out)
+	}
+	alice := filepath.Join(tmpDir, "alice")
+	bob := filepath.Join(tmpDir, "bob")
+
+	mustRunTestCmd(t, syncTestCmds(), "init", "--root", alice)
+	mustRunTestCmd(t, syncTestCmds(), "learn", "--right", "use pathlib.Path instead of os.path", "--scope", "local", "--root", alice, "--json")
+	out := mustRunTestCmd(t, syncTestCmds(), "sync", "push", "--remote", remote, "--root", alice)
+	if !strings.Contains(out, "Pushed ") {
+		t.Fatalf("unexpected push output:\n%s", out)
+	}
+
+	mustRunTestCmd(t, syncTestCmds(), "init", "--root", bob)
+	out = mustRunTestCmd(t, syncTestCmds(), "sync", "pull", "--remote", remote, "--root", bob, "--json")
+	var result struct {
+		Added []string `json:"added"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if len(result.Added) != 1 {
+		t.Errorf("pull result = %+v, want 1 added", result)
+	}
+
+	out = mustRunTestCmd(t, syncTestCmds(), "sync", "push", "--remote", remote, "--root", bob)
+	if !strings.Contains(out, "Nothing to push") {
+		t.Errorf("unexpected second push output:\n%s", out)
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/store"
@@ -14,15 +17,286 @@ import (
 
 func newTagsCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "tags",
-		Short: "Manage behavior tags",
-		Long:  `Commands for managing semantic tags on behaviors.`,
+		Use:     "tags",
+		Aliases: []string{"tag"},
+		Short:   "Manage behavior tags",
+		Long: `Commands for managing semantic tags on behaviors.
+
+Tags are hierarchical: levels are separated by "/", as in testing/unit.
+A tag filter or a 'tags' when-condition on testing also covers
+testing/unit, but not the other way around.`,
 	}
 
 	cmd.AddCommand(newTagsBackfillCmd())
+	cmd.AddCommand(newTagsAddCmd())
+	cmd.AddCommand(newTagsRemoveCmd())
+	cmd.AddCommand(newTagsListCmd())
+	return cmd
+}
+
+func newTagsAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <behavior> <tag>...",
+		Short: "Add tags to a behavior",
+		Long: `Add tags to a behavior given by ID, name, or slug.
+
+Tags are normalized the way learned tags are: lowercased, mapped to their
+canonical form (golang becomes go), and stripped of empty levels. A behavior
+keeps at most 8 tags.`,
+		Example: `  floop tags add use-uv python tooling/packaging
+  floop tags add b-123 testing/unit --json`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTagsEdit(cmd, args[0], args[1:], true)
+		},
+	}
+	cmd.Flags().String("scope", "auto", "Store to look the behavior up in: auto, local, or global")
 	return cmd
 }
 
+func newTagsRemoveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove <behavior> <tag>...",
+		Short: "Remove tags from a behavior",
+		Long: `Remove tags from a behavior given by ID, name, or slug. Only the
+exact tags given are removed: removing testing keeps testing/unit.`,
+		Example: `  floop tags remove use-uv tooling/packaging`,
+		Args:    cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTagsEdit(cmd, args[0], args[1:], false)
+		},
+	}
+	cmd.Flags().String("scope", "auto", "Store to look the behavior up in: auto, local, or global")
+	return cmd
+}
+
+// tagsEditOutput is the result of 'floop tags add' and 'floop tags remove'.
+type tagsEditOutput struct {
+	BehaviorID string   `json:"behavior_id"`
+	Name       string   `json:"name"`
+	Tags       []string `json:"tags"`
+	Changed    []string `json:"changed"`
+	Unchanged  []string `json:"unchanged,omitempty"`
+}
+
+// runTagsEdit adds tags to, or removes them from, one behavior.
+func runTagsEdit(cmd *cobra.Command, ref string, tagArgs []string, add bool) error {
+	root, _ := cmd.Flags().GetString("root")
+	jsonOut, _ := cmd.Flags().GetBool("json")
+	scope, _ := cmd.Flags().GetString("scope")
+
+	if _, err := os.Stat(filepath.Join(root, ".floop")); os.IsNotExist(err) {
+		return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+	}
+
+	graphStore, err := store.NewMultiGraphStore(root)
+	if err != nil {
+		return fmt.Errorf("opening graph store: %w", err)
+	}
+	defer graphStore.Close()
+
+	ctx := context.Background()
+	node, err := findCurationNode(ctx, graphStore, ref, scope)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return fmt.Errorf("behavior not found: %s", ref)
+	}
+	if node.Kind != store.NodeKindBehavior {
+		return fmt.Errorf("not an active behavior (current kind: %s)", node.Kind)
+	}
+
+	dict := tagging.NewDictionary()
+	var requested []string
+	for _, t := range tagArgs {
+		tag := tagging.NormalizeTag(t, dict)
+		if tag == "" {
+			return fmt.Errorf("invalid tag %q", t)
+		}
+		requested = append(requested, tag)
+	}
+
+	b := models.NodeToBehavior(*node)
+	tags, changed, unchanged := editTags(b.Content.Tags, requested, add)
+	if len(tags) > tagging.MaxTags {
+		return fmt.Errorf("a behavior can have at most %d tags, %s would have %d", tagging.MaxTags, b.Name, len(tags))
+	}
+
+	if len(changed) > 0 {
+		contentMap, ok := node.Content["content"].(map[string]interface{})
+		if !ok {
+			contentMap = make(map[string]interface{})
+			node.Content["content"] = contentMap
+		}
+		contentMap["tags"] = tags
+		if err := graphStore.UpdateNode(ctx, *node); err != nil {
+			return fmt.Errorf("updating behavior %s: %w", node.ID, err)
+		}
+		if err := graphStore.Sync(ctx); err != nil {
+			return fmt.Errorf("syncing store: %w", err)
+		}
+	}
+
+	output := tagsEditOutput{BehaviorID: node.ID, Name: b.Name, Tags: tags, Changed: changed, Unchanged: unchanged}
+	if output.Tags == nil {
+		output.Tags = []string{}
+	}
+	if output.Changed == nil {
+		output.Changed = []string{}
+	}
+	out := cmd.OutOrStdout()
+	if jsonOut {
+		return json.NewEncoder(out).Encode(output)
+	}
+
+	verb, already := "Added", "already tagged"
+	if !add {
+		verb, already = "Removed", "not tagged"
+	}
+	if len(changed) > 0 {
+		fmt.Fprintf(out, "%s %s on %s\n", verb, strings.Join(changed, ", "), b.Name)
+	}
+	if len(unchanged) > 0 {
+		fmt.Fprintf(out, "Skipped %s (%s)\n", strings.Join(unchanged, ", "), already)
+	}
+	fmt.Fprintf(out, "Tags: %s\n", strings.Join(tags, ", "))
+	return nil
+}
+
+// editTags adds requested to tags, or removes them. It returns the sorted
+// result, the requested tags that changed it, and those that did not.
+func editTags(tags, requested []string, add bool) (result, changed, unchanged []string) {
+	set := make(map[string]bool, len(tags)+len(requested))
+	for _, t := range tags {
+		set[t] = true
+	}
+	seen := make(map[string]bool, len(requested))
+	for _, t := range requested {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		if set[t] == add {
+			unchanged = append(unchanged, t)
+			continue
+		}
+		set[t] = add
+		changed = append(changed, t)
+	}
+	for t, ok := range set {
+		if ok {
+			result = append(result, t)
+		}
+	}
+	sort.Strings(result)
+	return result, changed, unchanged
+}
+
+func newTagsListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list [tag]",
+		Short: "List tags with behavior counts",
+		Long: `List the tags of active behaviors as a tree, each with the number of
+behaviors under it. A parent level such as testing counts every behavior
+tagged testing or below it, even when no behavior carries testing itself.
+
+Give a tag to list only it and the tags below it; the store's tag index
+finds the matching behaviors without scanning them all.`,
+		Example: `  floop tags list
+  floop tags list testing --json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			prefix := ""
+			if len(args) == 1 {
+				if prefix = tagging.NormalizeTag(args[0], nil); prefix == "" {
+					return fmt.Errorf("invalid tag %q", args[0])
+				}
+			}
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("opening graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			counts, err := countTags(context.Background(), graphStore, prefix)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{"tags": counts})
+			}
+			if len(counts) == 0 {
+				fmt.Fprintln(out, "No tagged behaviors.")
+				return nil
+			}
+			// Levels are indented below the listed tag, or below the top level
+			base := 0
+			if prefix != "" {
+				base = strings.Count(prefix, tagging.Separator)
+			}
+			for _, c := range counts {
+				label := c.Tag
+				depth := strings.Count(c.Tag, tagging.Separator) - base
+				if depth > 0 {
+					label = c.Tag[strings.LastIndex(c.Tag, tagging.Separator)+1:]
+				}
+				fmt.Fprintf(out, "%s%s (%d)\n", strings.Repeat("  ", depth), label, c.Count)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+// tagCount is one line of 'floop tags list'.
+type tagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"` // Active behaviors with this tag or one below it
+}
+
+// countTags counts the active behaviors under each tag and its ancestors,
+// limited to prefix and the tags below it when prefix is set. Results are
+// in tree order, so each parent directly precedes its children.
+func countTags(ctx context.Context, graphStore store.GraphStore, prefix string) ([]tagCount, error) {
+	predicate := map[string]interface{}{"kind": string(store.NodeKindBehavior)}
+	if prefix != "" {
+		predicate["tag"] = prefix
+	}
+	nodes, err := graphStore.QueryNodes(ctx, predicate)
+	if err != nil {
+		return nil, fmt.Errorf("querying behaviors: %w", err)
+	}
+
+	counts := make(map[string]int)
+	for _, node := range nodes {
+		for _, tag := range tagging.Expand(models.NodeToBehavior(node).Content.Tags) {
+			if prefix == "" || tagging.Matches(tag, prefix) {
+				counts[tag]++
+			}
+		}
+	}
+
+	result := make([]tagCount, 0, len(counts))
+	for tag, n := range counts {
+		result = append(result, tagCount{Tag: tag, Count: n})
+	}
+	sort.Slice(result, func(i, j int) bool { return tagTreeLess(result[i].Tag, result[j].Tag) })
+	return result, nil
+}
+
+// tagTreeLess orders tags level by level, so "testing/unit" follows
+// "testing" directly instead of after "testing-tools".
+func tagTreeLess(a, b string) bool {
+	return strings.ReplaceAll(a, tagging.Separator, "\x00") < strings.ReplaceAll(b, tagging.Separator, "\x00")
+}
+
 func newTagsBackfillCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "backfill",
@@ -3,10 +3,14 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/tagging"
 )
 
 func TestNewTagsCmd(t *testing.T) {
@@ -176,3 +180,89 @@ func TestTagsBackfillCmdJSON(t *testing.T) {
 		t.Fatalf("tags backfill --json failed: %v", err)
 	}
 }
+
+func TestTagsAddRemoveList(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+
+	run := func(args ...string) string {
+		t.Helper()
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newTagsCmd())
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs(append(args, "--root", tmpDir))
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+		return buf.String()
+	}
+	tagsOf := func() []string {
+		t.Helper()
+		s, err := store.NewMultiGraphStore(tmpDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+		node, err := s.GetNode(context.Background(), behaviorID)
+		if err != nil || node == nil {
+			t.Fatalf("GetNode() = %v, %v", node, err)
+		}
+		return models.NodeToBehavior(*node).Content.Tags
+	}
+
+	var added tagsEditOutput
+	if err := json.Unmarshal([]byte(run("tag", "add", behaviorID, "Testing//Unit/", "golang", "--json")), &added); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(added.Changed, []string{"testing/unit", "go"}) {
+		t.Errorf("added = %v, want the normalized tags", added.Changed)
+	}
+	if tags := tagsOf(); !tagging.HasTag(tags, "testing") || !tagging.HasTag(tags, "go") {
+		t.Errorf("tags = %v, want testing/unit and go stored", tags)
+	}
+
+	out := run("tags", "list")
+	if !strings.Contains(out, "testing (1)\n  unit (1)\n") {
+		t.Errorf("tags list = %q, want testing with unit below it", out)
+	}
+	var listed struct {
+		Tags []tagCount `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(run("tags", "list", "testing", "--json")), &listed); err != nil {
+		t.Fatal(err)
+	}
+	if want := []tagCount{{"testing", 1}, {"testing/unit", 1}}; !reflect.DeepEqual(listed.Tags, want) {
+		t.Errorf("tags list testing = %v, want %v", listed.Tags, want)
+	}
+
+	out = run("tags", "remove", behaviorID, "testing", "testing/unit")
+	if !strings.Contains(out, "Removed testing/unit") || !strings.Contains(out, "Skipped testing (not tagged)") {
+		t.Errorf("tags remove = %q", out)
+	}
+	if tags := tagsOf(); tagging.HasTag(tags, "testing") {
+		t.Errorf("tags = %v, want testing/unit removed", tags)
+	}
+}
+
+func TestEditTags(t *testing.T) {
+	tags, changed, unchanged := editTags([]string{"go", "testing"}, []string{"lint", "go", "lint"}, true)
+	if !reflect.DeepEqual(tags, []string{"go", "lint", "testing"}) || !reflect.DeepEqual(changed, []string{"lint"}) || !reflect.DeepEqual(unchanged, []string{"go"}) {
+		t.Errorf("add = %v, %v, %v", tags, changed, unchanged)
+	}
+	tags, changed, unchanged = editTags([]string{"go"}, []string{"go", "lint"}, false)
+	if tags != nil || !reflect.DeepEqual(changed, []string{"go"}) || !reflect.DeepEqual(unchanged, []string{"lint"}) {
+		t.Errorf("remove = %v, %v, %v", tags, changed, unchanged)
+	}
+}
+
+func TestTagsAddLimit(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newTagsCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"tags", "add", behaviorID, "a1", "a2", "a3", "a4", "a5", "a6", "a7", "a8", "a9", "--root", tmpDir})
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "at most 8 tags") {
+		t.Errorf("Execute() error = %v, want the tag limit", err)
+	}
+}
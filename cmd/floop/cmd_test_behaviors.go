@@ -0,0 +1,335 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// behaviorTestSuiteFile is the default suite, under the project's .floop/.
+const behaviorTestSuiteFile = "tests.yaml"
+
+// behaviorTestSuite is a declarative suite of activation assertions.
+type behaviorTestSuite struct {
+	Tests []behaviorTestCase `yaml:"tests"`
+}
+
+// behaviorTestCase asserts which behaviors a context activates. File may be
+// a glob, in which case every matching project file must pass.
+type behaviorTestCase struct {
+	Name            string   `yaml:"name"`
+	File            string   `yaml:"file"`
+	Files           []string `yaml:"files"`
+	Task            string   `yaml:"task"`
+	Env             string   `yaml:"env"`
+	Agent           string   `yaml:"agent"`
+	MustActivate    []string `yaml:"must_activate"`
+	MustNotActivate []string `yaml:"must_not_activate"`
+}
+
+// behaviorTestResult is the outcome of one test case.
+type behaviorTestResult struct {
+	Name     string   `json:"name"`
+	Passed   bool     `json:"passed"`
+	Files    []string `json:"files,omitempty"`
+	Failures []string `json:"failures,omitempty"`
+}
+
+func newTestBehaviorsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test-behaviors",
+		Short: "Run declarative activation assertions against the behavior store",
+		Long: `Run a suite of activation assertions from .floop/tests.yaml and exit
+non-zero if any fail, so learned behaviors can be tested in CI like code.
+
+The stores are read from their exported JSONL files into memory: the
+database is never opened, created, or written, so the command is safe on a
+fresh checkout. Only the project store is tested unless --scope says
+otherwise, keeping results independent of the machine's global store.
+
+Each test names a context and the behaviors (by ID or name) that it must
+and must not activate:
+
+  tests:
+    - name: API bugfixes wrap errors
+      file: src/api/*.py
+      task: bugfix
+      must_activate: [learned/wrap-errors]
+      must_not_activate: [learned/use-print-debugging]
+
+A file given as a glob is expanded against the project, and the
+assertions must hold for every matching file. files evaluates a changeset
+as 'floop active --files' does. env and agent are also accepted.
+
+Examples:
+  floop test-behaviors
+  floop test-behaviors --suite ci/floop-tests.yaml --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			suitePath, _ := cmd.Flags().GetString("suite")
+			scope, _ := cmd.Flags().GetString("scope")
+
+			storeScope := store.StoreScope(scope)
+			if !storeScope.Valid() {
+				return fmt.Errorf("invalid scope: %s (must be local, global, or both)", scope)
+			}
+			if suitePath == "" {
+				suitePath = filepath.Join(root, ".floop", behaviorTestSuiteFile)
+			} else if !filepath.IsAbs(suitePath) {
+				suitePath = filepath.Join(root, suitePath)
+			}
+
+			suite, err := loadBehaviorTestSuite(suitePath)
+			if err != nil {
+				return err
+			}
+			behaviors, err := loadSnapshotBehaviors(root, storeScope)
+			if err != nil {
+				return err
+			}
+
+			results := make([]behaviorTestResult, len(suite.Tests))
+			failed := 0
+			for i, tc := range suite.Tests {
+				results[i] = runBehaviorTest(root, behaviors, tc)
+				if !results[i].Passed {
+					failed++
+				}
+			}
+
+			if jsonOut {
+				if err := json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"suite":  suitePath,
+					"passed": len(results) - failed,
+					"failed": failed,
+					"tests":  results,
+				}); err != nil {
+					return err
+				}
+			} else {
+				printBehaviorTestResults(cmd.OutOrStdout(), results)
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d of %d behavior tests failed", failed, len(results))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("suite", "", "Test suite file (default: .floop/tests.yaml)")
+	cmd.Flags().String("scope", "local", "Stores to test: local, global, or both")
+
+	return cmd
+}
+
+// loadBehaviorTestSuite reads and checks the suite at path. Unknown fields
+// are rejected so a misspelled assertion cannot silently pass.
+func loadBehaviorTestSuite(path string) (*behaviorTestSuite, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open test suite: %w", err)
+	}
+	defer f.Close()
+
+	var suite behaviorTestSuite
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(&suite); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse test suite %s: %w", path, err)
+	}
+	if len(suite.Tests) == 0 {
+		return nil, fmt.Errorf("test suite %s has no tests", path)
+	}
+
+	for i, tc := range suite.Tests {
+		if tc.Name == "" {
+			return nil, fmt.Errorf("test %d: name is required", i+1)
+		}
+		if len(tc.MustActivate) == 0 && len(tc.MustNotActivate) == 0 {
+			return nil, fmt.Errorf("test %q: needs must_activate or must_not_activate", tc.Name)
+		}
+		if err := (activeQuery{Files: tc.Files}).validate(); err != nil {
+			return nil, fmt.Errorf("test %q: %w", tc.Name, err)
+		}
+	}
+	return &suite, nil
+}
+
+// loadSnapshotBehaviors loads the behaviors of the stores in scope from
+// their JSONL exports, without opening the databases. A behavior in both
+// stores is taken from the local one.
+func loadSnapshotBehaviors(root string, scope store.StoreScope) ([]models.Behavior, error) {
+	ctx := context.Background()
+	var behaviors []models.Behavior
+	seen := make(map[string]bool)
+
+	load := func(floopDir string, origin store.StoreScope) error {
+		snap, err := store.LoadJSONLSnapshot(floopDir)
+		if err != nil {
+			return err
+		}
+		loaded, err := behaviorsFromStore(ctx, snap, string(origin))
+		if err != nil {
+			return err
+		}
+		for _, b := range loaded {
+			if !seen[b.ID] {
+				seen[b.ID] = true
+				behaviors = append(behaviors, b)
+			}
+		}
+		return nil
+	}
+
+	if scope == store.ScopeLocal || scope == store.ScopeBoth {
+		if err := load(store.LocalFloopPath(root), store.ScopeLocal); err != nil {
+			return nil, err
+		}
+	}
+	if scope == store.ScopeGlobal || scope == store.ScopeBoth {
+		globalPath, err := store.GlobalFloopPath()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get global path: %w", err)
+		}
+		if err := load(globalPath, store.ScopeGlobal); err != nil {
+			return nil, err
+		}
+	}
+	return behaviors, nil
+}
+
+// runBehaviorTest evaluates one test case against behaviors.
+func runBehaviorTest(root string, behaviors []models.Behavior, tc behaviorTestCase) behaviorTestResult {
+	result := behaviorTestResult{Name: tc.Name}
+	fail := func(format string, args ...interface{}) {
+		result.Failures = append(result.Failures, fmt.Sprintf(format, args...))
+	}
+
+	// Resolve references up front: a test naming a behavior that no longer
+	// exists is stale, not passing
+	byRef := make(map[string]string, 2*len(behaviors))
+	for _, b := range behaviors {
+		byRef[b.ID] = b.ID
+		if _, taken := byRef[b.Name]; !taken {
+			byRef[b.Name] = b.ID
+		}
+	}
+	resolve := func(refs []string) map[string]string {
+		ids := make(map[string]string, len(refs))
+		for _, ref := range refs {
+			if id, ok := byRef[ref]; ok {
+				ids[ref] = id
+			} else {
+				fail("unknown behavior %q", ref)
+			}
+		}
+		return ids
+	}
+	mustActivate := resolve(tc.MustActivate)
+	mustNotActivate := resolve(tc.MustNotActivate)
+
+	files := []string{tc.File}
+	if isGlobPattern(tc.File) {
+		matches, err := expandTestGlob(root, tc.File)
+		if err != nil {
+			fail("file %s: %v", tc.File, err)
+		} else if len(matches) == 0 {
+			fail("file %s matches no files", tc.File)
+		}
+		files = matches
+		result.Files = matches
+	}
+
+	for _, file := range files {
+		eval, err := evaluateActive(root, behaviors, activeQuery{
+			File:  file,
+			Files: tc.Files,
+			Task:  tc.Task,
+			Env:   tc.Env,
+			Agent: tc.Agent,
+		})
+		if err != nil {
+			fail("%v", err)
+			continue
+		}
+		active := make(map[string]bool, len(eval.result.Active))
+		for _, b := range eval.result.Active {
+			active[b.ID] = true
+		}
+
+		where := ""
+		if len(files) > 1 {
+			where = " for " + file
+		}
+		for _, ref := range tc.MustActivate {
+			if id, ok := mustActivate[ref]; ok && !active[id] {
+				fail("%s is not active%s", ref, where)
+			}
+		}
+		for _, ref := range tc.MustNotActivate {
+			if id, ok := mustNotActivate[ref]; ok && active[id] {
+				fail("%s is active%s", ref, where)
+			}
+		}
+	}
+
+	result.Passed = len(result.Failures) == 0
+	return result
+}
+
+// isGlobPattern reports whether s contains glob metacharacters.
+func isGlobPattern(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
+
+// expandTestGlob returns the project files matching pattern, relative to
+// root and slash-separated.
+func expandTestGlob(root, pattern string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(matches))
+	for _, m := range matches {
+		if info, err := os.Stat(m); err != nil || info.IsDir() {
+			continue
+		}
+		rel, err := filepath.Rel(root, m)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, filepath.ToSlash(rel))
+	}
+	return files, nil
+}
+
+// printBehaviorTestResults writes one line per test, its failures, and a
+// summary.
+func printBehaviorTestResults(w io.Writer, results []behaviorTestResult) {
+	failed := 0
+	for _, r := range results {
+		if r.Passed {
+			fmt.Fprintf(w, "PASS  %s\n", r.Name)
+			continue
+		}
+		failed++
+		fmt.Fprintf(w, "FAIL  %s\n", r.Name)
+		for _, f := range r.Failures {
+			fmt.Fprintf(w, "      - %s\n", f)
+		}
+	}
+	fmt.Fprintf(w, "\n%d passed, %d failed\n", len(results)-failed, failed)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testBehaviorName = "learned/use-slog-structured-logging"
+
+// runTestBehaviors writes suite to the project and runs test-behaviors.
+func runTestBehaviors(t *testing.T, root, suite string, extraArgs ...string) (string, error) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(root, ".floop", "tests.yaml"), []byte(suite), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newTestBehaviorsCmd())
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs(append([]string{"test-behaviors", "--root", root}, extraArgs...))
+	err := rootCmd.Execute()
+	return buf.String(), err
+}
+
+func writeProjectFile(t *testing.T, root, rel string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTestBehaviorsCmd(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+	writeProjectFile(t, tmpDir, "src/api/a.go")
+	writeProjectFile(t, tmpDir, "src/api/b.go")
+	writeProjectFile(t, tmpDir, "src/api/c.py")
+
+	tests := []struct {
+		name     string
+		suite    string
+		wantErr  bool
+		wantText []string
+	}{
+		{
+			name: "passing by name and ID",
+			suite: `tests:
+  - name: go files log with slog
+    file: main.go
+    task: coding
+    must_activate: [` + testBehaviorName + `]
+  - name: python files do not
+    file: app.py
+    must_not_activate: [` + behaviorID + `]
+`,
+			wantText: []string{"PASS  go files log with slog", "PASS  python files do not", "2 passed, 0 failed"},
+		},
+		{
+			name: "glob must hold for every file",
+			suite: `tests:
+  - name: all go files
+    file: src/api/*.go
+    must_activate: [` + testBehaviorName + `]
+  - name: every api file
+    file: src/api/*
+    must_activate: [` + testBehaviorName + `]
+`,
+			wantErr:  true,
+			wantText: []string{"PASS  all go files", "FAIL  every api file", "is not active for src/api/c.py", "1 passed, 1 failed"},
+		},
+		{
+			name: "glob matching nothing fails",
+			suite: `tests:
+  - name: no such files
+    file: web/*.ts
+    must_not_activate: [` + testBehaviorName + `]
+`,
+			wantErr:  true,
+			wantText: []string{"matches no files"},
+		},
+		{
+			name: "unknown behavior fails",
+			suite: `tests:
+  - name: renamed behavior
+    file: main.go
+    must_activate: [learned/gone]
+`,
+			wantErr:  true,
+			wantText: []string{`unknown behavior "learned/gone"`},
+		},
+		{
+			name: "changeset",
+			suite: `tests:
+  - name: mixed changeset
+    files: [app.py, main.go]
+    must_activate: [` + testBehaviorName + `]
+`,
+			wantText: []string{"PASS  mixed changeset"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := runTestBehaviors(t, tmpDir, tt.suite, "--scope", "both")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v\n%s", err, tt.wantErr, out)
+			}
+			for _, want := range tt.wantText {
+				if !strings.Contains(out, want) {
+					t.Errorf("output missing %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestTestBehaviorsCmdInvalidSuite(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	tests := []struct {
+		name    string
+		suite   string
+		wantErr string
+	}{
+		{"misspelled field", "tests:\n  - name: x\n    must_activte: [a]\n", "must_activte"},
+		{"no assertions", "tests:\n  - name: x\n    file: main.go\n", "needs must_activate"},
+		{"missing name", "tests:\n  - file: main.go\n    must_activate: [a]\n", "name is required"},
+		{"empty", "", "has no tests"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runTestBehaviors(t, tmpDir, tt.suite)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTestBehaviorsCmdReadOnlyCheckout(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	// A checkout holding only the exported behaviors, as in CI
+	checkout := t.TempDir()
+	data, err := os.ReadFile(filepath.Join(tmpDir, "home", ".floop", "nodes.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(checkout, ".floop"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(checkout, ".floop", "nodes.jsonl"), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	suite := "tests:\n  - name: go files\n    file: main.go\n    must_activate: [" + testBehaviorName + "]\n"
+	output := captureStdout(t, func() {
+		if _, err := runTestBehaviors(t, checkout, suite, "--json"); err != nil {
+			t.Fatalf("test-behaviors failed: %v", err)
+		}
+	})
+
+	var result struct {
+		Passed int `json:"passed"`
+		Failed int `json:"failed"`
+		Tests  []struct {
+			Name   string `json:"name"`
+			Passed bool   `json:"passed"`
+		} `json:"tests"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("failed to parse output %q: %v", output, err)
+	}
+	if result.Passed != 1 || result.Failed != 0 || len(result.Tests) != 1 || !result.Tests[0].Passed {
+		t.Errorf("result = %+v, want one passing test", result)
+	}
+	entries, _ := os.ReadDir(filepath.Join(checkout, ".floop"))
+	if len(entries) != 2 {
+		t.Errorf(".floop holds %d entries after the run, want only nodes.jsonl and tests.yaml", len(entries))
+	}
+}
@@ -99,7 +99,7 @@ Examples:
 // runSingleStoreValidation validates a single store.
 func runSingleStoreValidation(ctx context.Context, root string, scope store.StoreScope, jsonOut bool) error {
 	// Open the appropriate store
-	var graphStore *store.SQLiteGraphStore
+	var graphStore store.ExtendedGraphStore
 	var err error
 
 	switch scope {
@@ -110,7 +110,7 @@ func runSingleStoreValidation(ctx context.Context, root string, scope store.Stor
 		if pathErr != nil {
 			return fmt.Errorf("failed to get global path: %w", pathErr)
 		}
-		graphStore, err = store.NewSQLiteGraphStore(filepath.Dir(globalPath))
+		graphStore, err = store.NewGlobalGraphStore(filepath.Dir(globalPath))
 	default:
 		return fmt.Errorf("runSingleStoreValidation requires local or global scope, got %q", scope)
 	}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// watchSocketName is the daemon's unix socket, under the project's .floop/.
+const watchSocketName = "watch.sock"
+
+// watchMaxScanFiles bounds each poll's walk of the project tree.
+const watchMaxScanFiles = 20000
+
+// watchSkipDirs are directories never scanned for file changes, in addition
+// to hidden ones.
+var watchSkipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"dist":         true,
+	"build":        true,
+}
+
+// watchSocketPath returns where the watch daemon for root listens.
+func watchSocketPath(root string) string {
+	return filepath.Join(root, ".floop", watchSocketName)
+}
+
+func newWatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Serve active behaviors from a long-lived daemon",
+		Long: `Run a daemon that keeps behaviors loaded and answers 'floop active'
+queries over a unix socket (.floop/watch.sock), without opening the
+stores on every call.
+
+The daemon polls the stores and reloads behaviors when they change, and
+polls the project tree to track the most recently modified file. Queries
+that name no file are evaluated against that file.
+
+While it runs, 'floop active --json' for this project is answered by the
+daemon automatically. Agents can also connect to the socket directly: send
+one JSON query per line ({"file": "...", "task": "...", "files": [...],
+"kinds": [...]}) and read one 'floop active --json' document per line.
+
+Stop it with Ctrl-C or SIGTERM.
+
+Examples:
+  floop watch
+  floop watch --interval 5s`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			interval, _ := cmd.Flags().GetDuration("interval")
+			if interval <= 0 {
+				return fmt.Errorf("--interval must be positive")
+			}
+
+			if _, ok := availableScope(root); !ok {
+				return fmt.Errorf("no .floop stores initialized; run 'floop init' first")
+			}
+			if err := os.MkdirAll(filepath.Join(root, ".floop"), 0o700); err != nil {
+				return fmt.Errorf("failed to create .floop: %w", err)
+			}
+
+			d := newWatchDaemon(root)
+			if err := d.reload(); err != nil {
+				return err
+			}
+			d.scan()
+
+			socketPath := watchSocketPath(root)
+			ln, err := listenWatchSocket(socketPath)
+			if err != nil {
+				return err
+			}
+			defer os.Remove(socketPath)
+
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+			sigCh := make(chan os.Signal, 1)
+			notifySignals(sigCh)
+			defer signal.Stop(sigCh)
+			go func() {
+				select {
+				case <-sigCh:
+					cancel()
+				case <-ctx.Done():
+				}
+				ln.Close()
+			}()
+			go d.poll(ctx, interval)
+
+			fmt.Fprintf(cmd.ErrOrStderr(), "floop watch: serving %d behaviors on %s\n", d.count(), socketPath)
+			return d.serve(ln)
+		},
+	}
+	cmd.Flags().Duration("interval", 2*time.Second, "How often to poll the stores and project files for changes")
+	return cmd
+}
+
+// listenWatchSocket listens on socketPath, replacing a stale socket left by
+// a daemon that exited uncleanly.
+func listenWatchSocket(socketPath string) (net.Listener, error) {
+	if _, err := os.Stat(socketPath); err == nil {
+		if conn, err := net.DialTimeout("unix", socketPath, 200*time.Millisecond); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("floop watch is already running on %s", socketPath)
+		}
+		if err := os.Remove(socketPath); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	}
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", socketPath, err)
+	}
+	return ln, nil
+}
+
+// queryWatchDaemon sends q to the daemon on socketPath and returns its
+// response line. Any failure, including an error reported by the daemon,
+// is returned so the caller can evaluate directly instead.
+func queryWatchDaemon(socketPath string, q activeQuery) ([]byte, error) {
+	conn, err := net.DialTimeout("unix", socketPath, 200*time.Millisecond)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(10 * time.Second)); err != nil {
+		return nil, err
+	}
+
+	if err := json.NewEncoder(conn).Encode(q); err != nil {
+		return nil, err
+	}
+	resp, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+	var status struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(resp, &status); err != nil {
+		return nil, err
+	}
+	if status.Error != "" {
+		return nil, errors.New(status.Error)
+	}
+	return resp, nil
+}
+
+// watchDaemon holds the loaded behaviors and the watched file context for
+// 'floop watch'.
+type watchDaemon struct {
+	root string
+
+	mu         sync.RWMutex
+	behaviors  []models.Behavior
+	storeStamp string    // storeFingerprint at the last load
+	lastFile   string    // most recently modified project file, relative to root
+	scannedAt  time.Time // files modified after this are new to the next scan
+}
+
+func newWatchDaemon(root string) *watchDaemon {
+	return &watchDaemon{root: root}
+}
+
+// count returns the number of loaded behaviors.
+func (d *watchDaemon) count() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.behaviors)
+}
+
+// currentFile returns the most recently modified project file.
+func (d *watchDaemon) currentFile() string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.lastFile
+}
+
+// reload loads the behaviors from the available stores.
+func (d *watchDaemon) reload() error {
+	scope, ok := availableScope(d.root)
+	var behaviors []models.Behavior
+	if ok {
+		var err error
+		if behaviors, err = loadBehaviorsWithScope(d.root, scope); err != nil {
+			return fmt.Errorf("failed to load behaviors: %w", err)
+		}
+	}
+	// Fingerprint after loading: opening the stores touches them too
+	stamp := d.storeFingerprint()
+
+	d.mu.Lock()
+	d.behaviors, d.storeStamp = behaviors, stamp
+	d.mu.Unlock()
+	return nil
+}
+
+// storeFingerprint summarizes the size and modification time of the store
+// files, so a change by any writer triggers a reload.
+func (d *watchDaemon) storeFingerprint() string {
+	dirs := []string{filepath.Join(d.root, ".floop")}
+	if globalPath, err := store.GlobalFloopPath(); err == nil {
+		dirs = append(dirs, globalPath)
+		dirs = append(dirs, store.PartitionDirs(globalPath)...)
+	}
+	var b strings.Builder
+	for _, dir := range dirs {
+		for _, name := range []string{"floop.db", "floop.db-wal", "nodes.jsonl", "edges.jsonl"} {
+			if info, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				fmt.Fprintf(&b, "%s/%s:%d:%d;", dir, name, info.Size(), info.ModTime().UnixNano())
+			}
+		}
+	}
+	return b.String()
+}
+
+// scan walks the project tree and records the newest file modified since
+// the previous scan.
+func (d *watchDaemon) scan() {
+	d.mu.RLock()
+	since := d.scannedAt
+	d.mu.RUnlock()
+	started := time.Now()
+
+	var newest string
+	var newestTime time.Time
+	seen := 0
+	filepath.WalkDir(d.root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if path != d.root && (strings.HasPrefix(name, ".") || watchSkipDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if seen++; seen > watchMaxScanFiles {
+			return filepath.SkipAll
+		}
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			return nil
+		}
+		if mod := info.ModTime(); mod.After(since) && mod.After(newestTime) {
+			newest, newestTime = path, mod
+		}
+		return nil
+	})
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.scannedAt = started
+	// The first scan only sets the baseline: nothing has changed yet
+	if newest != "" && !since.IsZero() {
+		if rel, err := filepath.Rel(d.root, newest); err == nil {
+			d.lastFile = filepath.ToSlash(rel)
+		}
+	}
+}
+
+// poll reloads behaviors when the stores change and rescans the project
+// files, every interval until ctx is done.
+func (d *watchDaemon) poll(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		d.mu.RLock()
+		stale := d.storeFingerprint() != d.storeStamp
+		d.mu.RUnlock()
+		if stale {
+			if err := d.reload(); err != nil {
+				fmt.Fprintf(os.Stderr, "floop watch: %v\n", err)
+			}
+		}
+		d.scan()
+	}
+}
+
+// serve answers queries on ln until it is closed.
+func (d *watchDaemon) serve(ln net.Listener) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("accept: %w", err)
+		}
+		go d.handle(conn)
+	}
+}
+
+// handle answers each JSON query line on conn with one JSON document line.
+func (d *watchDaemon) handle(conn net.Conn) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	enc := json.NewEncoder(conn)
+	for scanner.Scan() {
+		if err := enc.Encode(d.answer(scanner.Bytes())); err != nil {
+			return
+		}
+	}
+}
+
+// answer evaluates one query line.
+func (d *watchDaemon) answer(line []byte) interface{} {
+	var q activeQuery
+	if err := json.Unmarshal(line, &q); err != nil {
+		return map[string]string{"error": fmt.Sprintf("invalid query: %v", err)}
+	}
+	if q.File == "" && len(q.Files) == 0 {
+		q.File = d.currentFile()
+	}
+
+	d.mu.RLock()
+	behaviors := d.behaviors
+	d.mu.RUnlock()
+
+	eval, err := evaluateActive(d.root, behaviors, q)
+	if err != nil {
+		return map[string]string{"error": err.Error()}
+	}
+	return eval.jsonOutput()
+}
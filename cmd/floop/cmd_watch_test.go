@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startWatchDaemon serves a daemon for root until the test ends.
+func startWatchDaemon(t *testing.T, root string) *watchDaemon {
+	t.Helper()
+	d := newWatchDaemon(root)
+	if err := d.reload(); err != nil {
+		t.Fatalf("reload() error = %v", err)
+	}
+	ln, err := listenWatchSocket(watchSocketPath(root))
+	if err != nil {
+		t.Fatalf("listenWatchSocket() error = %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- d.serve(ln) }()
+	t.Cleanup(func() {
+		ln.Close()
+		if err := <-done; err != nil {
+			t.Errorf("serve() error = %v", err)
+		}
+	})
+	return d
+}
+
+func TestWatchDaemonAnswersQueries(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+	d := startWatchDaemon(t, tmpDir)
+	if d.count() != 1 {
+		t.Fatalf("count() = %d, want 1", d.count())
+	}
+
+	resp, err := queryWatchDaemon(watchSocketPath(tmpDir), activeQuery{Files: []string{"main.go", "app.py"}, Task: "coding"})
+	if err != nil {
+		t.Fatalf("queryWatchDaemon() error = %v", err)
+	}
+	var result struct {
+		TriggeredBy map[string][]string `json:"triggered_by"`
+		Count       int                 `json:"count"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		t.Fatalf("failed to parse response %q: %v", resp, err)
+	}
+	if result.Count != 1 {
+		t.Errorf("count = %d, want 1", result.Count)
+	}
+	if got := result.TriggeredBy[behaviorID]; !reflect.DeepEqual(got, []string{"main.go"}) {
+		t.Errorf("triggered_by[%s] = %v, want [main.go]", behaviorID, got)
+	}
+
+	if _, err := queryWatchDaemon(watchSocketPath(tmpDir), activeQuery{Kinds: []string{"bogus"}}); err == nil {
+		t.Error("expected the daemon to report an invalid query")
+	}
+}
+
+func TestWatchDaemonDefaultsToLastFile(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+	d := newWatchDaemon(tmpDir)
+	if err := d.reload(); err != nil {
+		t.Fatalf("reload() error = %v", err)
+	}
+	d.scan()
+	if got := d.currentFile(); got != "" {
+		t.Errorf("currentFile() after the baseline scan = %q, want none", got)
+	}
+
+	if err := os.MkdirAll(filepath.Join(tmpDir, "pkg"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(tmpDir, "pkg", "main.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+	d.scan()
+	if got := d.currentFile(); got != "pkg/main.go" {
+		t.Fatalf("currentFile() = %q, want pkg/main.go", got)
+	}
+
+	out, err := json.Marshal(d.answer([]byte(`{"task":"coding"}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result struct {
+		Context map[string]interface{} `json:"context"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		t.Fatalf("failed to parse answer %s: %v", out, err)
+	}
+	if got := result.Context["file_path"]; got != "pkg/main.go" {
+		t.Errorf("context file = %v, want pkg/main.go (answer: %s)", got, out)
+	}
+}
+
+func TestWatchDaemonInvalidQuery(t *testing.T) {
+	d := newWatchDaemon(t.TempDir())
+	out, err := json.Marshal(d.answer([]byte("not json")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "invalid query") {
+		t.Errorf("answer() = %s, want an invalid query error", out)
+	}
+}
+
+func TestListenWatchSocket(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+	socketPath := watchSocketPath(tmpDir)
+
+	// A stale socket file is replaced
+	if err := os.WriteFile(socketPath, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ln, err := listenWatchSocket(socketPath)
+	if err != nil {
+		t.Fatalf("listenWatchSocket() over a stale socket error = %v", err)
+	}
+	defer ln.Close()
+
+	if _, err := listenWatchSocket(socketPath); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Errorf("second listenWatchSocket() error = %v, want already running", err)
+	}
+}
+
+func TestActiveCmdUsesWatchDaemon(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+	startWatchDaemon(t, tmpDir)
+
+	for _, noDaemon := range []bool{false, true} {
+		args := []string{"active", "--json", "--file", "main.go", "--root", tmpDir}
+		if noDaemon {
+			args = append(args, "--no-daemon")
+		}
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newActiveCmd())
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetArgs(args)
+		output := captureStdout(t, func() {
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("active (no-daemon=%v) failed: %v", noDaemon, err)
+			}
+		})
+		var result struct {
+			Count int `json:"count"`
+		}
+		if err := json.Unmarshal([]byte(output), &result); err != nil {
+			t.Fatalf("failed to parse output %q: %v", output, err)
+		}
+		if result.Count != 1 {
+			t.Errorf("count (no-daemon=%v) = %d, want 1", noDaemon, result.Count)
+		}
+	}
+}
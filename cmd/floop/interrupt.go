@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/nvandessel/floop/internal/progress"
+	"github.com/spf13/cobra"
+)
+
+// interruptContext returns a context that is cancelled by the first Ctrl-C,
+// so a long operation can stop after its current transaction and report
+// what it did. The operation should check the context between transactions
+// and do each one with context.WithoutCancel. The signal handler is removed
+// once the context is cancelled, so a second Ctrl-C ends the process at
+// once. If notice is non-nil, a line is written to it on the first Ctrl-C.
+// Call stop when the operation is done.
+func interruptContext(notice io.Writer) (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sigCh := make(chan os.Signal, 1)
+	notifySignals(sigCh)
+	go func() {
+		select {
+		case <-sigCh:
+			signal.Stop(sigCh)
+			if notice != nil {
+				fmt.Fprintln(notice, "\nInterrupted: finishing the current step (Ctrl-C again to abort)")
+			}
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(sigCh)
+		cancel()
+	}
+}
+
+// commandProgress returns the progress reporter for a long-running command
+// and the writer its interrupt notice goes to. Progress goes to stderr, as
+// JSONL events with --json, so stdout keeps only the command's result.
+func commandProgress(cmd *cobra.Command, jsonOut bool) (*progress.Reporter, io.Writer) {
+	if jsonOut {
+		return progress.ForWriter(cmd.ErrOrStderr(), true), nil
+	}
+	return progress.ForWriter(cmd.ErrOrStderr(), false), cmd.ErrOrStderr()
+}
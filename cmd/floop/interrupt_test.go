@@ -0,0 +1,37 @@
+//go:build !windows
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestInterruptContext(t *testing.T) {
+	var notice bytes.Buffer
+	ctx, stop := interruptContext(&notice)
+	defer stop()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context not cancelled by SIGINT")
+	}
+	if !strings.Contains(notice.String(), "Interrupted") {
+		t.Errorf("notice = %q, want the interrupt acknowledged", notice.String())
+	}
+}
+
+func TestInterruptContextStop(t *testing.T) {
+	ctx, stop := interruptContext(nil)
+	stop()
+	if ctx.Err() == nil {
+		t.Error("stop() should cancel the context")
+	}
+}
@@ -2,15 +2,83 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"runtime/debug"
 	"time"
 
+	"github.com/nvandessel/floop/internal/activation"
 	"github.com/nvandessel/floop/internal/config"
 	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/prompt"
+	"github.com/nvandessel/floop/internal/ranking"
+	"github.com/nvandessel/floop/internal/spreading"
+	"github.com/nvandessel/floop/internal/store"
 	"github.com/spf13/cobra"
 )
 
+// newEvaluator creates an activation evaluator using the configured match
+// mode. Strict matching is used when the config cannot be loaded or names an
+// unknown mode.
+func newEvaluator() *activation.Evaluator {
+	cfg, _ := config.Load()
+	return prompt.NewEvaluator(cfg)
+}
+
+// sniffContentEnabled reports whether activation should read the current
+// file for imports and frameworks. It defaults to true when the config cannot
+// be loaded.
+func sniffContentEnabled() bool {
+	cfg, err := config.Load()
+	if err != nil {
+		return true
+	}
+	return cfg.Activation.SniffContent
+}
+
+// temporalWeight returns the temporal ranking weight, or zero when temporal
+// ranking is disabled or the config cannot be loaded.
+func temporalWeight() float64 {
+	cfg, err := config.Load()
+	if err != nil {
+		return 0
+	}
+	return cfg.Ranking.Temporal.EffectiveWeight()
+}
+
+// standingSeeds returns the configured standing spreading seeds, or none
+// when the config cannot be loaded.
+func standingSeeds() []spreading.StandingSeed {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil
+	}
+	seeds := make([]spreading.StandingSeed, 0, len(cfg.Activation.StandingSeeds))
+	for _, sc := range cfg.Activation.StandingSeeds {
+		seeds = append(seeds, spreading.StandingSeed{Tag: sc.Tag, BehaviorID: sc.Behavior, Activation: sc.Activation})
+	}
+	return seeds
+}
+
+// edgeDecayRate returns the spreading edge decay rate for the configured
+// activation.edge_half_life, or the default when the config cannot be
+// loaded.
+func edgeDecayRate() float64 {
+	cfg, err := config.Load()
+	if err != nil {
+		return ranking.DefaultDecayRate
+	}
+	return ranking.HalfLifeDecayRate(cfg.Activation.EdgeHalfLife)
+}
+
+// spreadingConfig returns the default spreading configuration with the
+// configured edge decay applied.
+func spreadingConfig() spreading.Config {
+	cfg := spreading.DefaultConfig()
+	cfg.TemporalDecayRate = edgeDecayRate()
+	return cfg
+}
+
 // createLLMClient creates an LLM client based on config settings.
 // Returns nil if LLM is not enabled or configured.
 // Supports providers: anthropic, openai, ollama, subagent, local.
@@ -48,11 +116,12 @@ func createLLMClient(cfg *config.FloopConfig, timeout ...time.Duration) llm.Clie
 	}
 
 	clientCfg := llm.ClientConfig{
-		Provider: cfg.LLM.Provider,
-		APIKey:   cfg.LLM.APIKey,
-		BaseURL:  cfg.LLM.BaseURL,
-		Model:    cfg.LLM.ComparisonModel,
-		Timeout:  resolveTimeout(cfg.LLM.Timeout),
+		Provider:       cfg.LLM.Provider,
+		APIKey:         cfg.LLM.APIKey,
+		BaseURL:        cfg.LLM.BaseURL,
+		Model:          cfg.LLM.ComparisonModel,
+		Timeout:        resolveTimeout(cfg.LLM.Timeout),
+		EmbeddingModel: cfg.LLM.EmbeddingModel,
 	}
 
 	switch cfg.LLM.Provider {
@@ -123,6 +192,13 @@ func versionString() string {
 	return fmt.Sprintf("%s (commit: %s, built: %s)", version, commit, date)
 }
 
+// enableStartupTiming writes a timing breakdown to w for every store opened.
+func enableStartupTiming(w io.Writer) {
+	store.SetOpenTimingReporter(func(t store.OpenTiming) {
+		fmt.Fprintf(w, "floop: %s\n", t)
+	})
+}
+
 func main() {
 	resolveVersion()
 
@@ -141,6 +217,20 @@ context-aware behavior activation for consistent agent operation.`,
 	// Global flags
 	rootCmd.PersistentFlags().Bool("json", false, "Output as JSON (for agent consumption)")
 	rootCmd.PersistentFlags().String("root", ".", "Project root directory")
+	rootCmd.PersistentFlags().Bool("verbose", false, "Print a startup timing breakdown to stderr")
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+			enableStartupTiming(os.Stderr)
+		}
+		if cfg, err := config.Load(); err == nil {
+			store.SetChangelogEnabled(cfg.Changelog.Enabled)
+			store.SetScopeChain(cfg.Stores.Scopes)
+			store.SetPolicyStoreDir(cfg.Stores.Policy)
+			if err := enableReplication(cfg.Replication); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: replication disabled: %v\n", err)
+			}
+		}
+	}
 
 	// Add subcommands
 	rootCmd.AddCommand(
@@ -148,6 +238,14 @@ context-aware behavior activation for consistent agent operation.`,
 		newInitCmd(),
 		newLearnCmd(),
 		newReprocessCmd(),
+		newHeldCmd(),
+		newFailuresCmd(),
+		newOutcomesCmd(),
+		newHeatmapCmd(),
+		newBriefCmd(),
+		newQuarantineCmd(),
+		newReviewCmd(),
+		newStatusCmd(),
 		newListCmd(),
 		newActiveCmd(),
 		newGraphCmd(),
@@ -155,16 +253,35 @@ context-aware behavior activation for consistent agent operation.`,
 		newWhyCmd(),
 		newPromptCmd(),
 		newMCPServerCmd(),
+		newServeCmd(),
+		newRemotePromptCmd(),
 		// Curation commands
 		newForgetCmd(),
+		newRetireCmd(),
 		newDeprecateCmd(),
 		newRestoreCmd(),
 		newMergeCmd(),
+		newPromoteCmd(),
+		newExpireCmd(),
+		newDecayCmd(),
 		// Management commands
 		newDeduplicateCmd(),
+		newSimilarCmd(),
+		newSearchCmd(),
+		newFlushCmd(),
 		newValidateCmd(),
+		newApplyCmd(),
+		newRebalanceCmd(),
+		newTestBehaviorsCmd(),
 		newConfigCmd(),
 		newPackCmd(),
+		newExportCmd(),
+		newImportCmd(),
+		newSyncCmd(),
+		newFailoverCmd(),
+		newIntegrateCmd(),
+		newGitMergeDriverCmd(),
+		newWatchCmd(),
 		// Token optimization commands
 		newSummarizeCmd(),
 		newStatsCmd(),
@@ -174,11 +291,23 @@ context-aware behavior activation for consistent agent operation.`,
 		// Graph management commands
 		newConnectCmd(),
 		newDeriveEdgesCmd(),
+		newGeneralizeCmd(),
+		newExportEmbeddingsCmd(),
+		newImportPrioritiesCmd(),
+		newExportCorrectionsCmd(),
 		// Backup/restore commands
 		newBackupCmd(),
 		newRestoreFromBackupCmd(),
+		newRestorePointCmd(),
+		newExportAllCmd(),
+		newImportAllCmd(),
+		newPublishCmd(),
 		// Hook management commands
 		newUpgradeCmd(),
+		newSelfUpdateCmd(),
+		newCapabilitiesCmd(),
+		newSchemaCmd(),
+		newBenchCmd(),
 		// Tag management commands
 		newTagsCmd(),
 		// Native hook commands (replacing shell scripts)
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/nvandessel/floop/internal/corrections"
 	"github.com/nvandessel/floop/internal/store"
 	"github.com/spf13/cobra"
 )
@@ -24,6 +25,32 @@ func newTestRootCmd() *cobra.Command {
 	return rootCmd
 }
 
+// runTestCmd runs args against a test root command with cmds added. It
+// returns what the command wrote to its output and to stdout.
+func runTestCmd(t *testing.T, cmds []*cobra.Command, args ...string) (string, error) {
+	t.Helper()
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(cmds...)
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(args)
+	var err error
+	captured := captureStdout(t, func() {
+		err = rootCmd.Execute()
+	})
+	return out.String() + captured, err
+}
+
+// mustRunTestCmd is runTestCmd for commands that must succeed.
+func mustRunTestCmd(t *testing.T, cmds []*cobra.Command, args ...string) string {
+	t.Helper()
+	out, err := runTestCmd(t, cmds, args...)
+	if err != nil {
+		t.Fatalf("%v failed: %v", args, err)
+	}
+	return out
+}
+
 // isolateHome sets HOME to a temp directory to avoid touching real ~/.floop/
 // MUST be called for any test that creates stores.
 // Uses t.Setenv for thread-safe env var handling and automatic cleanup.
@@ -39,34 +66,6 @@ func isolateHome(t *testing.T, tmpDir string) {
 	}
 }
 
-func TestSplitLines(t *testing.T) {
-	tests := []struct {
-		name  string
-		input string
-		want  []string
-	}{
-		{"empty", "", []string{}},
-		{"single line no newline", "foo", []string{"foo"}},
-		{"single line with newline", "foo\n", []string{"foo"}},
-		{"multiple lines", "foo\nbar\nbaz", []string{"foo", "bar", "baz"}},
-		{"multiple lines with trailing", "foo\nbar\n", []string{"foo", "bar"}},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := splitLines(tt.input)
-			if len(got) != len(tt.want) {
-				t.Errorf("splitLines(%q) = %v, want %v", tt.input, got, tt.want)
-				return
-			}
-			for i := range got {
-				if got[i] != tt.want[i] {
-					t.Errorf("splitLines(%q)[%d] = %q, want %q", tt.input, i, got[i], tt.want[i])
-				}
-			}
-		})
-	}
-}
-
 func TestNewVersionCmd(t *testing.T) {
 	cmd := newVersionCmd()
 	if cmd.Use != "version" {
@@ -320,12 +319,7 @@ func TestLearnCmdCapturesCorrection(t *testing.T) {
 	}
 
 	// Verify correction was written
-	correctionsPath := filepath.Join(tmpDir, ".floop", "corrections.jsonl")
-	data, err := os.ReadFile(correctionsPath)
-	if err != nil {
-		t.Fatalf("failed to read corrections: %v", err)
-	}
-
+	data := loggedCorrectionJSON(t, tmpDir)
 	var correction map[string]interface{}
 	if err := json.Unmarshal(data, &correction); err != nil {
 		t.Fatalf("failed to parse correction: %v", err)
@@ -368,7 +362,7 @@ func TestListCorrectionsEmpty(t *testing.T) {
 	}
 
 	// List should succeed with empty results
-	err := listCorrections(os.Stdout, tmpDir, false)
+	err := listCorrections(context.Background(), os.Stdout, tmpDir, corrections.Filter{}, false)
 	if err != nil {
 		t.Fatalf("listCorrections failed: %v", err)
 	}
@@ -379,7 +373,7 @@ func TestListCorrectionsNotInitialized(t *testing.T) {
 	isolateHome(t, tmpDir)
 
 	// List should succeed gracefully
-	err := listCorrections(os.Stdout, tmpDir, false)
+	err := listCorrections(context.Background(), os.Stdout, tmpDir, corrections.Filter{}, false)
 	if err != nil {
 		t.Fatalf("listCorrections failed: %v", err)
 	}
@@ -666,7 +660,7 @@ func TestCurationWorkflow(t *testing.T) {
 	rootCmd4.AddCommand(newRestoreCmd())
 	rootCmd4.SetOut(&bytes.Buffer{})
 	rootCmd4.SetArgs([]string{
-		"restore", behaviorID,
+		"restore", behaviorID, "--force",
 		"--root", tmpDir,
 	})
 	if err := rootCmd4.Execute(); err != nil {
@@ -689,7 +683,7 @@ func TestCurationWorkflow(t *testing.T) {
 	rootCmd5.AddCommand(newDeprecateCmd())
 	rootCmd5.SetOut(&bytes.Buffer{})
 	rootCmd5.SetArgs([]string{
-		"deprecate", behaviorID,
+		"deprecate", behaviorID, "--force",
 		"--reason", "outdated approach",
 		"--root", tmpDir,
 	})
@@ -713,7 +707,7 @@ func TestCurationWorkflow(t *testing.T) {
 	rootCmd6.AddCommand(newRestoreCmd())
 	rootCmd6.SetOut(&bytes.Buffer{})
 	rootCmd6.SetArgs([]string{
-		"restore", behaviorID,
+		"restore", behaviorID, "--force",
 		"--root", tmpDir,
 	})
 	if err := rootCmd6.Execute(); err != nil {
@@ -850,8 +844,8 @@ func TestMergeWorkflow(t *testing.T) {
 	if err == nil {
 		t.Error("expected error when restoring merged behavior")
 	}
-	if !strings.Contains(err.Error(), "not deprecated or forgotten") {
-		t.Errorf("expected 'not deprecated or forgotten' error, got: %v", err)
+	if !strings.Contains(err.Error(), "not deprecated, forgotten, retired, or quarantined") {
+		t.Errorf("expected 'not deprecated, forgotten, retired, or quarantined' error, got: %v", err)
 	}
 }
 
@@ -942,8 +936,8 @@ func TestRestoreActiveBehaviorFails(t *testing.T) {
 	if err == nil {
 		t.Error("expected error when restoring active behavior")
 	}
-	if !strings.Contains(err.Error(), "not deprecated or forgotten") {
-		t.Errorf("expected 'not deprecated or forgotten' error, got: %v", err)
+	if !strings.Contains(err.Error(), "not deprecated, forgotten, retired, or quarantined") {
+		t.Errorf("expected 'not deprecated, forgotten, retired, or quarantined' error, got: %v", err)
 	}
 }
 
@@ -1012,7 +1006,7 @@ func TestDeprecateWithReplacement(t *testing.T) {
 	rootCmd4.AddCommand(newDeprecateCmd())
 	rootCmd4.SetOut(&bytes.Buffer{})
 	rootCmd4.SetArgs([]string{
-		"deprecate", id1,
+		"deprecate", id1, "--force",
 		"--reason", "superseded by slog approach",
 		"--replacement", id2,
 		"--root", tmpDir,
@@ -38,15 +38,21 @@ Example usage in Continue.dev config.json:
       }
     }
   }
+
+Per-tool call counts, latencies, and rate-limit rejections are recorded
+while the server runs and can be read with 'floop stats --tools'. Pass
+--metrics-addr to also serve them to Prometheus at /metrics over HTTP.
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			root, _ := cmd.Flags().GetString("root")
+			metricsAddr, _ := cmd.Flags().GetString("metrics-addr")
 
 			// Create MCP server
 			server, err := mcp.NewServer(&mcp.Config{
-				Name:    "floop",
-				Version: version,
-				Root:    root,
+				Name:        "floop",
+				Version:     version,
+				Root:        root,
+				MetricsAddr: metricsAddr,
 			})
 			if err != nil {
 				return fmt.Errorf("failed to create MCP server: %w", err)
@@ -61,5 +67,7 @@ Example usage in Continue.dev config.json:
 		},
 	}
 
+	cmd.Flags().String("metrics-addr", "", "Serve Prometheus metrics at /metrics on this address (e.g., 127.0.0.1:9464)")
+
 	return cmd
 }
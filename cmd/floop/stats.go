@@ -5,12 +5,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
+	"time"
 
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/metrics"
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/outcome"
 	"github.com/nvandessel/floop/internal/store"
 	"github.com/nvandessel/floop/internal/tiering"
 	"github.com/nvandessel/floop/internal/tokens"
+	"github.com/nvandessel/floop/internal/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -26,13 +32,36 @@ understand which behaviors are most valuable and which may need review.
 Examples:
   floop stats              # Show all stats
   floop stats --top 10     # Show top 10 by usage
-  floop stats --sort score # Sort by ranking score`,
+  floop stats --sort score # Sort by ranking score
+  floop stats --by-client  # Learned/activated counts per agent client
+  floop stats --tools      # MCP tool calls, latencies, and rate limiting
+  floop stats --health     # Is the feedback loop working? Health dashboard
+
+While 'floop mcp-server' runs, it compares each floop_active result with the
+last one for the same client and arguments. The share of behaviors that
+changed is the call's churn, and 1 minus the mean churn is the active-set
+stability score shown here. Churn comes from background updates (Hebbian
+edge learning, PageRank refreshes, decay) and from new behaviors; a low
+score means agents get erratic guidance, and floop stats names the settings
+that may cause it.
+
+--health summarizes whether the feedback loop works: the most and least
+activated behaviors, how often feedback overrides rather than confirms
+them, behaviors unused for decay.demote_after_days, edge growth, average
+confidence, and store size.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			topN, _ := cmd.Flags().GetInt("top")
 			sortBy, _ := cmd.Flags().GetString("sort")
 			budget, _ := cmd.Flags().GetInt("budget")
+			byClient, _ := cmd.Flags().GetBool("by-client")
+			tools, _ := cmd.Flags().GetBool("tools")
+			health, _ := cmd.Flags().GetBool("health")
+
+			if tools {
+				return printToolMetrics(root, jsonOut)
+			}
 
 			// Open graph store
 			graphStore, err := store.NewMultiGraphStore(root)
@@ -43,6 +72,13 @@ Examples:
 
 			ctx := context.Background()
 
+			if byClient {
+				return printClientStats(ctx, graphStore, jsonOut)
+			}
+			if health {
+				return printHealth(ctx, graphStore, root, topN, jsonOut)
+			}
+
 			// Query all behaviors
 			nodes, err := graphStore.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
 			if err != nil {
@@ -73,14 +109,22 @@ Examples:
 				TimesConfirmed  int     `json:"times_confirmed"`
 				TimesOverridden int     `json:"times_overridden"`
 				FollowRate      float64 `json:"follow_rate"`
+				OutcomesGood    int     `json:"outcomes_positive"`
+				OutcomesBad     int     `json:"outcomes_negative"`
+				OutcomeRate     float64 `json:"outcome_rate"`
 				HasSummary      bool    `json:"has_summary"`
 				TokenCost       int     `json:"token_cost"`
 				SummaryCost     int     `json:"summary_cost"`
 			}
 
+			outcomes, err := outcome.LoadStats(filepath.Join(root, ".floop"))
+			if err != nil {
+				return err
+			}
+
 			stats := make([]BehaviorStats, 0, len(nodes))
 			behaviors := make([]models.Behavior, 0, len(nodes))
-			var totalActivations, totalFollowed, totalConfirmed, totalOverridden int
+			var totalActivations, totalFollowed, totalConfirmed, totalOverridden, totalGood, totalBad int
 			kindCounts := make(map[string]int)
 
 			for _, node := range nodes {
@@ -93,6 +137,7 @@ Examples:
 					followRate = float64(positiveSignals) / float64(behavior.Stats.TimesActivated)
 				}
 
+				linked := outcomes[behavior.ID]
 				tokenCost := tokens.EstimateTokens(behavior.Content.Canonical)
 				summaryCost := tokens.EstimateTokens(behavior.Content.Summary)
 
@@ -107,6 +152,9 @@ Examples:
 					TimesConfirmed:  behavior.Stats.TimesConfirmed,
 					TimesOverridden: behavior.Stats.TimesOverridden,
 					FollowRate:      followRate,
+					OutcomesGood:    linked.Positive,
+					OutcomesBad:     linked.Negative,
+					OutcomeRate:     linked.SuccessRate(),
 					HasSummary:      behavior.Content.Summary != "",
 					TokenCost:       tokenCost,
 					SummaryCost:     summaryCost,
@@ -116,6 +164,8 @@ Examples:
 				totalFollowed += behavior.Stats.TimesFollowed
 				totalConfirmed += behavior.Stats.TimesConfirmed
 				totalOverridden += behavior.Stats.TimesOverridden
+				totalGood += linked.Positive
+				totalBad += linked.Negative
 				kindCounts[string(behavior.Kind)]++
 			}
 
@@ -133,6 +183,13 @@ Examples:
 				sort.Slice(stats, func(i, j int) bool {
 					return stats[i].FollowRate > stats[j].FollowRate
 				})
+			case "outcomes":
+				sort.Slice(stats, func(i, j int) bool {
+					if stats[i].OutcomeRate != stats[j].OutcomeRate {
+						return stats[i].OutcomeRate > stats[j].OutcomeRate
+					}
+					return stats[i].OutcomesGood+stats[i].OutcomesBad > stats[j].OutcomesGood+stats[j].OutcomesBad
+				})
 			case "confidence":
 				sort.Slice(stats, func(i, j int) bool {
 					return stats[i].Confidence > stats[j].Confidence
@@ -161,6 +218,8 @@ Examples:
 				"total_followed":    totalFollowed,
 				"total_confirmed":   totalConfirmed,
 				"total_overridden":  totalOverridden,
+				"outcomes_positive": totalGood,
+				"outcomes_negative": totalBad,
 				"by_kind":           kindCounts,
 			}
 
@@ -213,13 +272,19 @@ Examples:
 				"behaviors":         tokenBudgetBehaviors,
 			}
 
+			stability := readStability(root)
+
 			// Output
 			if jsonOut {
-				json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				out := map[string]interface{}{
 					"behaviors":    stats,
 					"summary":      summary,
 					"token_budget": tokenBudgetInfo,
-				})
+				}
+				if stability != nil {
+					out["stability"] = stability
+				}
+				json.NewEncoder(os.Stdout).Encode(out)
 			} else {
 				fmt.Printf("Behavior Statistics\n")
 				fmt.Printf("===================\n\n")
@@ -231,6 +296,9 @@ Examples:
 				fmt.Printf("  Total followed:    %d\n", totalFollowed)
 				fmt.Printf("  Total confirmed:   %d\n", totalConfirmed)
 				fmt.Printf("  Total overridden:  %d\n", totalOverridden)
+				if totalGood+totalBad > 0 {
+					fmt.Printf("  Linked outcomes:   %d good, %d bad\n", totalGood, totalBad)
+				}
 				fmt.Printf("\n")
 
 				fmt.Printf("By kind:\n")
@@ -248,6 +316,11 @@ Examples:
 				fmt.Printf("  Omitted:      %d behaviors\n", len(plan.OmittedBehaviors))
 				fmt.Printf("\n")
 
+				if stability != nil {
+					printStability(stability)
+					fmt.Printf("\n")
+				}
+
 				// Top 5 by token cost
 				if len(stats) > 0 {
 					// Sort a copy by TokenCost descending
@@ -309,13 +382,184 @@ Examples:
 	}
 
 	cmd.Flags().Int("top", 0, "Show only top N behaviors")
-	cmd.Flags().String("sort", "score", "Sort by: score, activations, followed, rate, confidence, priority")
+	cmd.Flags().String("sort", "score", "Sort by: score, activations, followed, rate, outcomes, confidence, priority")
 	cmd.Flags().String("scope", "local", "Scope: local, global, or both")
 	cmd.Flags().Int("budget", 2000, "Token budget for injection simulation")
+	cmd.Flags().Bool("by-client", false, "Show learned and activated counts per agent client")
+	cmd.Flags().Bool("tools", false, "Show per-tool MCP call metrics recorded by mcp-server")
+	cmd.Flags().Bool("health", false, "Show the behavior health dashboard (--top sets list length, default 5)")
 
 	return cmd
 }
 
+// printClientStats prints per-client attribution of learned and activated behaviors.
+func printClientStats(ctx context.Context, graphStore store.GraphStore, jsonOut bool) error {
+	statsStore, ok := graphStore.(store.ClientStatsStore)
+	if !ok {
+		return fmt.Errorf("store does not support per-client stats")
+	}
+
+	clients, err := statsStore.GetClientStats(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get client stats: %w", err)
+	}
+
+	if jsonOut {
+		if clients == nil {
+			clients = []store.ClientStats{}
+		}
+		return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+			"clients": clients,
+		})
+	}
+
+	if len(clients) == 0 {
+		fmt.Println("No per-client activity recorded.")
+		return nil
+	}
+
+	fmt.Printf("Behavior Statistics by Client\n")
+	fmt.Printf("=============================\n\n")
+	fmt.Printf("%-24s %8s %10s %12s  %s\n", "Client", "Learned", "Activated", "Activations", "Last active")
+	fmt.Println(repeatChar('-', 80))
+	for _, c := range clients {
+		last := "-"
+		if c.LastActivated != nil {
+			last = c.LastActivated.Local().Format("2006-01-02 15:04")
+		}
+		name := c.Client
+		if len(name) > 24 {
+			name = name[:21] + "..."
+		}
+		fmt.Printf("%-24s %8d %10d %12d  %s\n", name, c.BehaviorsLearned, c.BehaviorsActivated, c.Activations, last)
+	}
+	return nil
+}
+
+// printToolMetrics prints the per-tool metrics snapshot written by the
+// project's MCP server.
+func printToolMetrics(root string, jsonOut bool) error {
+	snap, err := metrics.ReadSnapshotFile(filepath.Join(root, ".floop", metrics.SnapshotFile))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if jsonOut {
+		if snap == nil {
+			return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				"tools": []interface{}{},
+			})
+		}
+		return json.NewEncoder(os.Stdout).Encode(snap)
+	}
+
+	if snap == nil || len(snap.Tools) == 0 {
+		fmt.Println("No tool metrics recorded. They are collected while 'floop mcp-server' runs.")
+		return nil
+	}
+
+	fmt.Printf("MCP Tool Metrics\n")
+	fmt.Printf("================\n\n")
+	fmt.Printf("%-28s %7s %7s %8s %8s %9s %9s %9s\n", "Tool", "Calls", "Errors", "Limited", "Timeout", "Avg ms", "p95 ms", "Max ms")
+	fmt.Println(repeatChar('-', 92))
+	for _, t := range snap.Tools {
+		fmt.Printf("%-28s %7d %7d %8d %8d %9.1f %9.1f %9.1f\n", t.Tool, t.Calls, t.Errors, t.RateLimited, t.TimedOut, t.AvgMs, t.P95Ms, t.MaxMs)
+	}
+	fmt.Printf("\nBackground workers: %d/%d busy, %d tasks dropped\n",
+		snap.Background.QueueDepth, snap.Background.QueueCapacity, snap.Background.Dropped)
+	fmt.Printf("Updated %s (server started %s)\n",
+		snap.UpdatedAt.Local().Format("2006-01-02 15:04:05"), snap.StartedAt.Local().Format("2006-01-02 15:04:05"))
+	if snap.Stability.Comparisons > 0 {
+		fmt.Println()
+		printStability(newStabilityReport(snap.Stability, loadConfigQuiet()))
+	}
+	return nil
+}
+
+// Active-set stability below minStableScore, over at least
+// minStabilityComparisons repeated-context calls, is reported as erratic.
+const (
+	minStableScore          = 0.8
+	minStabilityComparisons = 10
+)
+
+// stabilityReport is the active-set stability measured by the MCP server,
+// with warnings when guidance is erratic.
+type stabilityReport struct {
+	metrics.StabilitySnapshot
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// readStability returns the stability recorded in the project's metrics
+// snapshot, or nil if no server has measured any yet.
+func readStability(root string) *stabilityReport {
+	snap, err := metrics.ReadSnapshotFile(filepath.Join(root, ".floop", metrics.SnapshotFile))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		return nil
+	}
+	if snap.Stability.Comparisons == 0 {
+		return nil
+	}
+	return newStabilityReport(snap.Stability, loadConfigQuiet())
+}
+
+// loadConfigQuiet loads the floop config, returning nil if it cannot be read.
+func loadConfigQuiet() *config.FloopConfig {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil
+	}
+	return cfg
+}
+
+// newStabilityReport warns when st shows erratic guidance, naming the
+// settings in cfg that make the active set move between identical calls.
+func newStabilityReport(st metrics.StabilitySnapshot, cfg *config.FloopConfig) *stabilityReport {
+	report := &stabilityReport{StabilitySnapshot: st}
+	if st.Comparisons < minStabilityComparisons || st.Score >= minStableScore {
+		return report
+	}
+	report.Warnings = append(report.Warnings, fmt.Sprintf(
+		"guidance is erratic: %d of %d repeated-context calls returned a different active set", st.Changed, st.Comparisons))
+	if cfg == nil {
+		return report
+	}
+
+	hints := 0
+	if hl := cfg.Activation.EdgeHalfLife; hl > 0 && hl < 24*time.Hour {
+		report.Warnings = append(report.Warnings, fmt.Sprintf(
+			"activation.edge_half_life is %v: edge weights fade between calls; raise it, or unset it for the ~69h default", hl))
+		hints++
+	}
+	if cfg.Decay.Enabled {
+		if d, err := utils.ParseDuration(cfg.Decay.Interval); err == nil && d < time.Hour {
+			report.Warnings = append(report.Warnings, fmt.Sprintf(
+				"decay.interval is %s: decay passes demote behaviors while agents work; use 24h or more", cfg.Decay.Interval))
+			hints++
+		}
+	}
+	if hints == 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf(
+			"no setting stands out; behaviors competing for the token budget (token_budget.default: %d) or fast-changing co-activation edges can cause it (see floop graph --min-weight)",
+			cfg.TokenBudget.Default))
+	}
+	return report
+}
+
+// printStability prints the active-set stability section of floop stats.
+func printStability(r *stabilityReport) {
+	fmt.Printf("Active-Set Stability:\n")
+	fmt.Printf("  Score:        %.2f\n", r.Score)
+	fmt.Printf("  Changed:      %d of %d repeated-context calls\n", r.Changed, r.Comparisons)
+	fmt.Printf("  Churn:        %.2f mean, %.2f max\n", r.MeanChurn, r.MaxChurn)
+	for _, w := range r.Warnings {
+		fmt.Printf("  Warning: %s\n", w)
+	}
+}
+
 func repeatChar(c rune, n int) string {
 	result := make([]rune, n)
 	for i := range result {
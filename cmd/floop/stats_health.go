@@ -0,0 +1,312 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/decay"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Behaviors with at least minContestedFeedback confirmations and overrides,
+// most of them overrides, are listed as contested by floop stats --health.
+const (
+	minContestedFeedback = 3
+	contestedRatio       = 0.5
+)
+
+// healthBehavior is one behavior in a floop stats --health list.
+type healthBehavior struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	Confidence      float64   `json:"confidence"`
+	TimesActivated  int       `json:"times_activated"`
+	TimesConfirmed  int       `json:"times_confirmed"`
+	TimesOverridden int       `json:"times_overridden"`
+	OverrideRatio   float64   `json:"override_ratio"`
+	LastUsed        time.Time `json:"last_used"`
+}
+
+// edgeGrowth counts the edges of the graph and how many were added recently.
+type edgeGrowth struct {
+	Total       int            `json:"total"`
+	Added7Days  int            `json:"added_7d"`
+	Added30Days int            `json:"added_30d"`
+	Growth30d   float64        `json:"growth_30d"` // Added30Days over the count 30 days ago
+	ByKind      map[string]int `json:"by_kind"`
+}
+
+// storeSize is the size on disk of one store's database.
+type storeSize struct {
+	Scope     string `json:"scope"`
+	Path      string `json:"path"`
+	Bytes     int64  `json:"bytes"`
+	Behaviors int    `json:"behaviors"`
+}
+
+// healthReport is the behavior health dashboard of floop stats --health:
+// whether behaviors activate, whether feedback confirms or overrides them,
+// and how the graph and stores grow.
+type healthReport struct {
+	Behaviors      int              `json:"behaviors"`
+	AvgConfidence  float64          `json:"avg_confidence"`
+	Activations    int              `json:"activations"`
+	Confirmed      int              `json:"confirmed"`
+	Overridden     int              `json:"overridden"`
+	OverrideRatio  float64          `json:"override_ratio"`
+	NeverActivated int              `json:"never_activated"`
+	MostActivated  []healthBehavior `json:"most_activated"`
+	LeastActivated []healthBehavior `json:"least_activated"`
+	Contested      []healthBehavior `json:"contested"`
+	StaleAfterDays int              `json:"stale_after_days"`
+	StaleCount     int              `json:"stale_count"`
+	Stale          []healthBehavior `json:"stale"`
+	Edges          edgeGrowth       `json:"edges"`
+	Stores         []storeSize      `json:"stores"`
+	Warnings       []string         `json:"warnings,omitempty"`
+}
+
+// overrideRatio is the share of explicit feedback that overrode rather
+// than confirmed, or 0 without any.
+func overrideRatio(confirmed, overridden int) float64 {
+	if confirmed+overridden == 0 {
+		return 0
+	}
+	return float64(overridden) / float64(confirmed+overridden)
+}
+
+// newHealthReport builds the dashboard from behaviors and edges as of now,
+// listing at most top behaviors in each list. Behaviors unused for
+// staleAfterDays are stale.
+func newHealthReport(behaviors []models.Behavior, edges []store.Edge, top, staleAfterDays int, now time.Time) *healthReport {
+	report := &healthReport{
+		Behaviors:      len(behaviors),
+		StaleAfterDays: staleAfterDays,
+		Edges:          edgeGrowth{ByKind: make(map[string]int)},
+		MostActivated:  []healthBehavior{},
+		LeastActivated: []healthBehavior{},
+		Contested:      []healthBehavior{},
+		Stale:          []healthBehavior{},
+		Stores:         []storeSize{},
+	}
+
+	entries := make([]healthBehavior, 0, len(behaviors))
+	var confidence float64
+	var contested, stale []healthBehavior
+	staleBefore := now.AddDate(0, 0, -staleAfterDays)
+	for _, b := range behaviors {
+		e := healthBehavior{
+			ID:              b.ID,
+			Name:            b.Name,
+			Confidence:      b.Confidence,
+			TimesActivated:  b.Stats.TimesActivated,
+			TimesConfirmed:  b.Stats.TimesConfirmed,
+			TimesOverridden: b.Stats.TimesOverridden,
+			OverrideRatio:   overrideRatio(b.Stats.TimesConfirmed, b.Stats.TimesOverridden),
+			LastUsed:        decay.LastUsed(b),
+		}
+		entries = append(entries, e)
+		confidence += b.Confidence
+		report.Activations += e.TimesActivated
+		report.Confirmed += e.TimesConfirmed
+		report.Overridden += e.TimesOverridden
+		if e.TimesActivated == 0 {
+			report.NeverActivated++
+		}
+		if e.TimesConfirmed+e.TimesOverridden >= minContestedFeedback && e.OverrideRatio > contestedRatio {
+			contested = append(contested, e)
+		}
+		if !e.LastUsed.IsZero() && e.LastUsed.Before(staleBefore) {
+			stale = append(stale, e)
+		}
+	}
+	if len(behaviors) > 0 {
+		report.AvgConfidence = confidence / float64(len(behaviors))
+	}
+	report.OverrideRatio = overrideRatio(report.Confirmed, report.Overridden)
+	report.StaleCount = len(stale)
+
+	limit := func(list []healthBehavior) []healthBehavior {
+		if top > 0 && len(list) > top {
+			return list[:top]
+		}
+		return list
+	}
+
+	// Ties are broken by ID so the lists are stable between runs
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].TimesActivated != entries[j].TimesActivated {
+			return entries[i].TimesActivated > entries[j].TimesActivated
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	report.MostActivated = append(report.MostActivated, limit(entries)...)
+	least := make([]healthBehavior, len(entries))
+	for i, e := range entries {
+		least[len(entries)-1-i] = e
+	}
+	report.LeastActivated = append(report.LeastActivated, limit(least)...)
+
+	sort.Slice(contested, func(i, j int) bool {
+		if contested[i].OverrideRatio != contested[j].OverrideRatio {
+			return contested[i].OverrideRatio > contested[j].OverrideRatio
+		}
+		return contested[i].TimesOverridden > contested[j].TimesOverridden
+	})
+	report.Contested = append(report.Contested, limit(contested)...)
+
+	sort.Slice(stale, func(i, j int) bool { return stale[i].LastUsed.Before(stale[j].LastUsed) })
+	report.Stale = append(report.Stale, limit(stale)...)
+
+	for _, e := range edges {
+		report.Edges.Total++
+		report.Edges.ByKind[string(e.Kind)]++
+		if e.CreatedAt.After(now.AddDate(0, 0, -7)) {
+			report.Edges.Added7Days++
+		}
+		if e.CreatedAt.After(now.AddDate(0, 0, -30)) {
+			report.Edges.Added30Days++
+		}
+	}
+	if before := report.Edges.Total - report.Edges.Added30Days; before > 0 {
+		report.Edges.Growth30d = float64(report.Edges.Added30Days) / float64(before)
+	}
+
+	report.Warnings = healthWarnings(report)
+	return report
+}
+
+// healthWarnings names the signs that the feedback loop is not working.
+func healthWarnings(r *healthReport) []string {
+	var warnings []string
+	if r.Behaviors == 0 {
+		return nil
+	}
+	if r.Activations == 0 {
+		warnings = append(warnings, "no behavior has activated yet; check that your agent calls floop_active or the session hooks are installed (floop integrate)")
+	} else if r.NeverActivated*2 > r.Behaviors {
+		warnings = append(warnings, fmt.Sprintf("%d of %d behaviors have never activated; their when-conditions may be too narrow", r.NeverActivated, r.Behaviors))
+	}
+	if r.Confirmed+r.Overridden == 0 && r.Activations > 0 {
+		warnings = append(warnings, "no confirmations or overrides recorded; without floop_feedback, confidence cannot learn which behaviors help")
+	} else if r.OverrideRatio > contestedRatio {
+		warnings = append(warnings, fmt.Sprintf("%.0f%% of feedback overrides behaviors; review the contested behaviors", r.OverrideRatio*100))
+	}
+	if r.StaleCount*4 > r.Behaviors {
+		warnings = append(warnings, fmt.Sprintf("%d of %d behaviors are stale (unused for %d days); 'floop decay' demotes them", r.StaleCount, r.Behaviors, r.StaleAfterDays))
+	}
+	return warnings
+}
+
+// storeSizes returns the database size of the local and global stores,
+// with the behaviors each holds.
+func storeSizes(root string, nodes []store.Node) []storeSize {
+	counts := make(map[store.Origin]int)
+	for _, n := range nodes {
+		counts[n.Origin]++
+	}
+	dirs := []struct {
+		origin store.Origin
+		dir    string
+	}{{store.OriginLocal, filepath.Join(root, ".floop")}}
+	if global, err := store.GlobalFloopPath(); err == nil && global != dirs[0].dir {
+		dirs = append(dirs, struct {
+			origin store.Origin
+			dir    string
+		}{store.OriginGlobal, global})
+	}
+
+	var sizes []storeSize
+	for _, d := range dirs {
+		path := filepath.Join(d.dir, "floop.db")
+		var bytes int64
+		for _, name := range []string{path, path + "-wal", path + "-shm"} {
+			if info, err := os.Stat(name); err == nil {
+				bytes += info.Size()
+			}
+		}
+		sizes = append(sizes, storeSize{Scope: string(d.origin), Path: path, Bytes: bytes, Behaviors: counts[d.origin]})
+	}
+	return sizes
+}
+
+// printHealth prints the floop stats --health dashboard.
+func printHealth(ctx context.Context, graphStore *store.MultiGraphStore, root string, top int, jsonOut bool) error {
+	nodes, err := graphStore.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return fmt.Errorf("failed to query behaviors: %w", err)
+	}
+	behaviors := make([]models.Behavior, 0, len(nodes))
+	for _, node := range nodes {
+		behaviors = append(behaviors, models.NodeToBehavior(node))
+	}
+	edges, err := graphStore.GetAllEdges(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load edges: %w", err)
+	}
+
+	staleAfterDays := constants.DefaultStaleBehaviorDays
+	if cfg := loadConfigQuiet(); cfg != nil && cfg.Decay.DemoteAfterDays > 0 {
+		staleAfterDays = cfg.Decay.DemoteAfterDays
+	}
+	if top <= 0 {
+		top = 5
+	}
+	report := newHealthReport(behaviors, edges, top, staleAfterDays, time.Now())
+	report.Stores = storeSizes(root, nodes)
+
+	if jsonOut {
+		return json.NewEncoder(os.Stdout).Encode(report)
+	}
+
+	fmt.Printf("Behavior Health\n")
+	fmt.Printf("===============\n\n")
+	fmt.Printf("  Behaviors:       %d (%d never activated)\n", report.Behaviors, report.NeverActivated)
+	fmt.Printf("  Avg confidence:  %.2f\n", report.AvgConfidence)
+	fmt.Printf("  Activations:     %d\n", report.Activations)
+	fmt.Printf("  Feedback:        %d confirmed, %d overridden (%.0f%% overrides)\n",
+		report.Confirmed, report.Overridden, report.OverrideRatio*100)
+	fmt.Printf("  Stale:           %d unused for %d+ days\n", report.StaleCount, report.StaleAfterDays)
+	fmt.Printf("  Edges:           %d (+%d in 7 days, +%d in 30 days, %+.0f%%)\n",
+		report.Edges.Total, report.Edges.Added7Days, report.Edges.Added30Days, report.Edges.Growth30d*100)
+	for _, s := range report.Stores {
+		fmt.Printf("  %-17s%s, %d behaviors\n", "Store ("+s.Scope+"):", formatBytes(s.Bytes), s.Behaviors)
+	}
+	fmt.Printf("\n")
+
+	printHealthTable("Most activated", report.MostActivated)
+	printHealthTable("Least activated", report.LeastActivated)
+	printHealthTable("Contested (mostly overridden)", report.Contested)
+	printHealthTable("Stale", report.Stale)
+
+	for _, w := range report.Warnings {
+		fmt.Printf("Warning: %s\n", w)
+	}
+	return nil
+}
+
+// printHealthTable prints one behavior list of the health dashboard.
+func printHealthTable(title string, list []healthBehavior) {
+	if len(list) == 0 {
+		return
+	}
+	fmt.Printf("%s:\n", title)
+	fmt.Printf("  %-30s %6s %9s %10s %9s %10s  %s\n", "Name", "Act", "Confirmed", "Overridden", "Override", "Confidence", "Last used")
+	for _, e := range list {
+		name := e.Name
+		if name == "" {
+			name = e.ID
+		}
+		fmt.Printf("  %-30s %6d %9d %10d %8.0f%% %10.2f  %s\n",
+			truncatePreview(name, 27), e.TimesActivated, e.TimesConfirmed, e.TimesOverridden,
+			e.OverrideRatio*100, e.Confidence, e.LastUsed.Local().Format("2006-01-02"))
+	}
+	fmt.Printf("\n")
+}
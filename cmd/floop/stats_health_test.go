@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestNewHealthReport(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(d int) *time.Time {
+		t := now.AddDate(0, 0, -d)
+		return &t
+	}
+	behavior := func(id string, confidence float64, activated, confirmed, overridden int, last *time.Time) models.Behavior {
+		return models.Behavior{
+			ID:         id,
+			Name:       id,
+			Confidence: confidence,
+			Stats: models.BehaviorStats{
+				TimesActivated:  activated,
+				TimesConfirmed:  confirmed,
+				TimesOverridden: overridden,
+				LastActivated:   last,
+				CreatedAt:       now.AddDate(0, 0, -200),
+			},
+		}
+	}
+	behaviors := []models.Behavior{
+		behavior("busy", 0.9, 40, 10, 1, daysAgo(1)),
+		behavior("contested", 0.4, 12, 1, 5, daysAgo(2)),
+		behavior("forgotten", 0.6, 2, 0, 0, daysAgo(120)),
+		behavior("unused", 0.5, 0, 0, 0, nil),
+	}
+	edges := []store.Edge{
+		{Source: "busy", Target: "contested", Kind: store.EdgeKindCoActivated, CreatedAt: now.AddDate(0, 0, -3)},
+		{Source: "busy", Target: "forgotten", Kind: store.EdgeKindSimilarTo, CreatedAt: now.AddDate(0, 0, -20)},
+		{Source: "unused", Target: "busy", Kind: store.EdgeKindSimilarTo, CreatedAt: now.AddDate(0, 0, -90)},
+		{Source: "forgotten", Target: "busy", Kind: store.EdgeKindSimilarTo, CreatedAt: now.AddDate(0, 0, -95)},
+	}
+
+	r := newHealthReport(behaviors, edges, 2, 90, now)
+
+	if r.Behaviors != 4 || r.Activations != 54 || r.Confirmed != 11 || r.Overridden != 6 || r.NeverActivated != 1 {
+		t.Errorf("totals = %+v", r)
+	}
+	if got := r.AvgConfidence; got < 0.599 || got > 0.601 {
+		t.Errorf("AvgConfidence = %v, want 0.6", got)
+	}
+	ids := func(list []healthBehavior) []string {
+		var out []string
+		for _, e := range list {
+			out = append(out, e.ID)
+		}
+		return out
+	}
+	if got := strings.Join(ids(r.MostActivated), ","); got != "busy,contested" {
+		t.Errorf("MostActivated = %s", got)
+	}
+	if got := strings.Join(ids(r.LeastActivated), ","); got != "unused,forgotten" {
+		t.Errorf("LeastActivated = %s", got)
+	}
+	if got := strings.Join(ids(r.Contested), ","); got != "contested" {
+		t.Errorf("Contested = %s", got)
+	}
+	// unused was created 200 days ago and never used since
+	if got := strings.Join(ids(r.Stale), ","); got != "unused,forgotten" || r.StaleCount != 2 {
+		t.Errorf("Stale = %s (count %d)", got, r.StaleCount)
+	}
+	if r.Edges.Total != 4 || r.Edges.Added7Days != 1 || r.Edges.Added30Days != 2 || r.Edges.Growth30d != 1 || r.Edges.ByKind[string(store.EdgeKindSimilarTo)] != 3 {
+		t.Errorf("Edges = %+v", r.Edges)
+	}
+	if len(r.Warnings) != 1 || !strings.Contains(r.Warnings[0], "stale") {
+		t.Errorf("Warnings = %v, want the stale warning", r.Warnings)
+	}
+}
+
+func TestHealthWarnings(t *testing.T) {
+	tests := []struct {
+		name   string
+		report healthReport
+		want   string
+	}{
+		{"no activations", healthReport{Behaviors: 3}, "no behavior has activated"},
+		{"mostly never activated", healthReport{Behaviors: 3, Activations: 5, NeverActivated: 2, Confirmed: 1}, "never activated"},
+		{"no feedback", healthReport{Behaviors: 3, Activations: 5}, "no confirmations or overrides"},
+		{"overrides dominate", healthReport{Behaviors: 3, Activations: 5, Confirmed: 1, Overridden: 3, OverrideRatio: 0.75}, "75% of feedback"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Join(healthWarnings(&tt.report), "\n")
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("healthWarnings() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if w := healthWarnings(&healthReport{}); w != nil {
+		t.Errorf("healthWarnings() of an empty store = %v, want none", w)
+	}
+}
+
+func TestStatsCmdHealth(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+
+	run := func(args ...string) string {
+		t.Helper()
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newStatsCmd())
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetArgs(append([]string{"stats", "--health", "--root", tmpDir}, args...))
+		var err error
+		out := captureStdout(t, func() { err = rootCmd.Execute() })
+		if err != nil {
+			t.Fatalf("stats --health %v failed: %v", args, err)
+		}
+		return out
+	}
+
+	var report healthReport
+	if err := json.Unmarshal([]byte(run("--json")), &report); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if report.Behaviors == 0 || len(report.MostActivated) == 0 || report.MostActivated[0].ID != behaviorID {
+		t.Errorf("report = %+v, want the learned behavior", report)
+	}
+	var global *storeSize
+	for i := range report.Stores {
+		if report.Stores[i].Scope == string(store.OriginGlobal) {
+			global = &report.Stores[i]
+		}
+	}
+	if global == nil || global.Bytes == 0 || global.Behaviors != report.Behaviors {
+		t.Errorf("stores = %+v, want the global store holding the behaviors", report.Stores)
+	}
+
+	out := run()
+	for _, want := range []string{"Behavior Health", "Avg confidence:", "Most activated:", "Store (global):"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -2,9 +2,15 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/metrics"
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/tiering"
 	"github.com/nvandessel/floop/internal/tokens"
@@ -284,7 +290,7 @@ func TestNewStatsCmd(t *testing.T) {
 	if cmd.Use != "stats" {
 		t.Errorf("Use = %q, want %q", cmd.Use, "stats")
 	}
-	for _, flag := range []string{"top", "sort", "scope", "budget"} {
+	for _, flag := range []string{"top", "sort", "scope", "budget", "by-client", "tools", "health"} {
 		if cmd.Flags().Lookup(flag) == nil {
 			t.Errorf("missing --%s flag", flag)
 		}
@@ -317,6 +323,115 @@ func TestStatsCmdJSON(t *testing.T) {
 	}
 }
 
+func TestStatsCmdTools(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	run := func(args ...string) string {
+		t.Helper()
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newStatsCmd())
+		rootCmd.SetArgs(append([]string{"stats", "--tools", "--root", tmpDir}, args...))
+		return captureStdout(t, func() {
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("stats --tools failed: %v", err)
+			}
+		})
+	}
+
+	if out := run(); !strings.Contains(out, "No tool metrics recorded") {
+		t.Errorf("output without a snapshot = %q", out)
+	}
+	if out := run("--json"); strings.TrimSpace(out) != `{"tools":[]}` {
+		t.Errorf("JSON without a snapshot = %q", out)
+	}
+
+	reg := metrics.NewRegistry()
+	reg.ObserveCall("floop_active", 20*time.Millisecond, nil)
+	reg.ObserveRateLimited("floop_active")
+	if err := reg.WriteSnapshotFile(filepath.Join(tmpDir, ".floop", metrics.SnapshotFile)); err != nil {
+		t.Fatalf("WriteSnapshotFile() error = %v", err)
+	}
+
+	if out := run(); !strings.Contains(out, "floop_active") {
+		t.Errorf("output missing floop_active:\n%s", out)
+	}
+	var snap metrics.Snapshot
+	if err := json.Unmarshal([]byte(run("--json")), &snap); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(snap.Tools) != 1 || snap.Tools[0].Calls != 1 || snap.Tools[0].RateLimited != 1 {
+		t.Errorf("tools = %+v, want one floop_active call, rate limited once", snap.Tools)
+	}
+}
+
+func TestStatsCmdStability(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	reg := metrics.NewRegistry()
+	for i := 0; i < 12; i++ {
+		reg.ObserveActiveSet("go", []string{"a", fmt.Sprintf("b-%d", i)})
+	}
+	if err := reg.WriteSnapshotFile(filepath.Join(tmpDir, ".floop", metrics.SnapshotFile)); err != nil {
+		t.Fatalf("WriteSnapshotFile() error = %v", err)
+	}
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newStatsCmd())
+	rootCmd.SetArgs([]string{"stats", "--json", "--root", tmpDir})
+	out := captureStdout(t, func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("stats --json failed: %v", err)
+		}
+	})
+
+	var got struct {
+		Stability *stabilityReport `json:"stability"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got.Stability == nil || got.Stability.Comparisons != 11 || got.Stability.Changed != 11 {
+		t.Fatalf("stability = %+v, want 11 changed comparisons", got.Stability)
+	}
+	if len(got.Stability.Warnings) == 0 {
+		t.Errorf("stability %.2f should warn about erratic guidance", got.Stability.Score)
+	}
+}
+
+func TestNewStabilityReport(t *testing.T) {
+	erratic := metrics.StabilitySnapshot{Comparisons: 20, Changed: 15, MeanChurn: 0.5, Score: 0.5}
+	short := config.Default()
+	short.Activation.EdgeHalfLife = 2 * time.Hour
+	short.Decay.Enabled = true
+	short.Decay.Interval = "10m"
+
+	tests := []struct {
+		name string
+		st   metrics.StabilitySnapshot
+		cfg  *config.FloopConfig
+		want []string
+	}{
+		{"stable", metrics.StabilitySnapshot{Comparisons: 20, Changed: 1, MeanChurn: 0.05, Score: 0.95}, config.Default(), nil},
+		{"too few calls", metrics.StabilitySnapshot{Comparisons: 3, Changed: 3, MeanChurn: 1, Score: 0}, config.Default(), nil},
+		{"defaults", erratic, config.Default(), []string{"15 of 20", "token_budget.default"}},
+		{"short half-life and decay", erratic, short, []string{"15 of 20", "activation.edge_half_life is 2h0m0s", "decay.interval is 10m"}},
+		{"no config", erratic, nil, []string{"15 of 20"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newStabilityReport(tt.st, tt.cfg).Warnings
+			if len(got) != len(tt.want) {
+				t.Fatalf("warnings = %q, want %d", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("warning %d = %q, want it to mention %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
 func TestStatsCmdTopN(t *testing.T) {
 	tmpDir, _ := setupQueryTest(t)
 
@@ -347,6 +462,24 @@ func TestStatsCmdSortOptions(t *testing.T) {
 	}
 }
 
+func TestStatsCmdByClient(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	for _, args := range [][]string{
+		{"stats", "--by-client", "--root", tmpDir},
+		{"stats", "--by-client", "--json", "--root", tmpDir},
+	} {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newStatsCmd())
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetArgs(args)
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+	}
+}
+
 func TestStatsCmdEmptyStore(t *testing.T) {
 	tmpDir := t.TempDir()
 	isolateHome(t, tmpDir)
@@ -0,0 +1,83 @@
+//go:build cgo
+
+// Command libfloop builds floop as a C-shared library, so agent frameworks
+// in other languages can learn from corrections and compile prompts
+// in-process instead of running the CLI:
+//
+//	go build -buildmode=c-shared -o libfloop.so ./cmd/libfloop
+//
+// The build also writes libfloop.h. Every function takes and returns
+// NUL-terminated UTF-8 JSON (see internal/libfloop for the request and
+// response fields). Returned strings are allocated with malloc and must be
+// released with floop_free. Calls are safe from multiple threads.
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"unsafe"
+
+	"github.com/nvandessel/floop/internal/libfloop"
+)
+
+// Set via ldflags at build time, like the floop CLI.
+var version = "dev"
+
+// floop_learn learns from a correction: {"root", "wrong", "right", "file",
+// "task", "language", "tags", "scope"}.
+//
+//export floop_learn
+func floop_learn(request *C.char) *C.char {
+	return call(libfloop.OpLearn, request)
+}
+
+// floop_active lists the behaviors active in a context: {"root", "file",
+// "task", "language", "env", "agent", "tags"}.
+//
+//export floop_active
+func floop_active(request *C.char) *C.char {
+	return call(libfloop.OpActive, request)
+}
+
+// floop_prompt compiles the behaviors active in a context into a prompt
+// section: the context fields of floop_active plus "format" and
+// "token_budget".
+//
+//export floop_prompt
+func floop_prompt(request *C.char) *C.char {
+	return call(libfloop.OpPrompt, request)
+}
+
+// floop_version returns {"version", "abi_version"}.
+//
+//export floop_version
+func floop_version() *C.char {
+	data, _ := json.Marshal(map[string]interface{}{
+		"version":     version,
+		"abi_version": libfloop.ABIVersion,
+	})
+	return C.CString(string(data))
+}
+
+// floop_free releases a string returned by libfloop. NULL is ignored.
+//
+//export floop_free
+func floop_free(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
+
+// call runs op on the request string and returns the response envelope. A
+// NULL request is an empty one.
+func call(op string, request *C.char) *C.char {
+	var req []byte
+	if request != nil {
+		req = []byte(C.GoString(request))
+	}
+	return C.CString(string(libfloop.Call(op, req)))
+}
+
+func main() {}
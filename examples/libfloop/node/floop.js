@@ -0,0 +1,77 @@
+// Thin wrapper around libfloop using koffi (npm install koffi).
+//
+// Build the library first (see docs/integrations/libfloop.md), then:
+//
+//   const { Floop } = require('./floop');
+//   const floop = new Floop('./libfloop.so');
+//   floop.learn({ right: 'use pathlib.Path instead of os.path', file: 'app.py' });
+//   console.log(floop.prompt({ file: 'app.py', token_budget: 500 }).text);
+
+'use strict';
+
+const koffi = require('koffi');
+
+class FloopError extends Error {}
+
+class Floop {
+  constructor(path = './libfloop.so', root = undefined) {
+    const lib = koffi.load(path);
+    // Returned strings are freed with floop_free, so they are taken as
+    // pointers and decoded by hand
+    this._learn = lib.func('void *floop_learn(const char *request)');
+    this._active = lib.func('void *floop_active(const char *request)');
+    this._prompt = lib.func('void *floop_prompt(const char *request)');
+    this._version = lib.func('void *floop_version()');
+    this._free = lib.func('void floop_free(void *s)');
+    this.root = root;
+  }
+
+  _take(ptr) {
+    try {
+      return JSON.parse(koffi.decode(ptr, 'char', -1));
+    } finally {
+      this._free(ptr);
+    }
+  }
+
+  _call(fn, request) {
+    const req = { ...request };
+    if (this.root !== undefined && req.root === undefined) {
+      req.root = this.root;
+    }
+    const resp = this._take(fn(JSON.stringify(req)));
+    if (!resp.ok) {
+      throw new FloopError(resp.error);
+    }
+    return resp.result;
+  }
+
+  version() {
+    return this._take(this._version());
+  }
+
+  // learn({ right, wrong, file, task, language, tags, scope })
+  learn(request) {
+    return this._call(this._learn, request);
+  }
+
+  // active({ file, task, language, env, agent, tags, kinds, exclude_kinds }) returns the behaviors
+  active(context = {}) {
+    return this._call(this._active, context).behaviors;
+  }
+
+  // prompt({ ...context, format, token_budget, tiered, order, packing }) returns the compiled prompt
+  prompt(request = {}) {
+    return this._call(this._prompt, request);
+  }
+}
+
+module.exports = { Floop, FloopError };
+
+if (require.main === module) {
+  const floop = new Floop(process.argv[2] || './libfloop.so');
+  console.log(floop.version());
+  for (const b of floop.active({ file: 'main.go' })) {
+    console.log(`[${b.kind}] ${b.name}`);
+  }
+}
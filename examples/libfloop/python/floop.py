@@ -0,0 +1,72 @@
+"""Thin ctypes wrapper around libfloop.
+
+Build the library first (see docs/integrations/libfloop.md), then:
+
+    from floop import Floop
+
+    floop = Floop("./libfloop.so")
+    floop.learn(right="use pathlib.Path instead of os.path", file="app.py")
+    print(floop.prompt(file="app.py", token_budget=500)["text"])
+"""
+
+import ctypes
+import json
+
+
+class FloopError(Exception):
+    """An error returned by libfloop."""
+
+
+class Floop:
+    """In-process access to floop's learn, active, and prompt operations."""
+
+    def __init__(self, path="libfloop.so", root=None):
+        self._lib = ctypes.CDLL(path)
+        for name in ("floop_learn", "floop_active", "floop_prompt"):
+            fn = getattr(self._lib, name)
+            fn.argtypes = [ctypes.c_char_p]
+            fn.restype = ctypes.c_void_p  # freed with floop_free, so not c_char_p
+        self._lib.floop_version.argtypes = []
+        self._lib.floop_version.restype = ctypes.c_void_p
+        self._lib.floop_free.argtypes = [ctypes.c_void_p]
+        self._lib.floop_free.restype = None
+        self.root = root
+
+    def _take(self, ptr):
+        try:
+            return json.loads(ctypes.string_at(ptr).decode("utf-8"))
+        finally:
+            self._lib.floop_free(ptr)
+
+    def _call(self, fn, **request):
+        if self.root is not None:
+            request.setdefault("root", self.root)
+        request = {k: v for k, v in request.items() if v is not None}
+        resp = self._take(fn(json.dumps(request).encode("utf-8")))
+        if not resp["ok"]:
+            raise FloopError(resp["error"])
+        return resp["result"]
+
+    def version(self):
+        return self._take(self._lib.floop_version())
+
+    def learn(self, right, wrong=None, **context):
+        """Learn from a correction; returns the learn response."""
+        return self._call(self._lib.floop_learn, right=right, wrong=wrong, **context)
+
+    def active(self, **context):
+        """Return the behaviors active in a context (file, task, language, ...)."""
+        return self._call(self._lib.floop_active, **context)["behaviors"]
+
+    def prompt(self, format=None, token_budget=None, **context):
+        """Compile the active behaviors into a prompt section."""
+        return self._call(self._lib.floop_prompt, format=format, token_budget=token_budget, **context)
+
+
+if __name__ == "__main__":
+    import sys
+
+    floop = Floop(sys.argv[1] if len(sys.argv) > 1 else "./libfloop.so")
+    print(floop.version())
+    for b in floop.active(file="main.go"):
+        print(f"[{b['kind']}] {b['name']}")
@@ -0,0 +1,39 @@
+package activation
+
+// MergeFileMatches unions activation results evaluated separately for each
+// file of a changeset. perFile[i] holds the results for files[i]. Each
+// behavior appears once, keeping its most specific match (ties go to the
+// higher match score), in the order it was first seen. triggeredBy maps each
+// behavior ID to the files that activated it, in changeset order.
+func MergeFileMatches(files []string, perFile [][]ActivationResult) (merged []ActivationResult, triggeredBy map[string][]string) {
+	triggeredBy = make(map[string][]string)
+	position := make(map[string]int)
+	for i, results := range perFile {
+		for _, r := range results {
+			id := r.Behavior.ID
+			if i < len(files) && !containsFile(triggeredBy[id], files[i]) {
+				triggeredBy[id] = append(triggeredBy[id], files[i])
+			}
+			pos, seen := position[id]
+			if !seen {
+				position[id] = len(merged)
+				merged = append(merged, r)
+				continue
+			}
+			best := merged[pos]
+			if r.Specificity > best.Specificity || (r.Specificity == best.Specificity && r.MatchScore > best.MatchScore) {
+				merged[pos] = r
+			}
+		}
+	}
+	return merged, triggeredBy
+}
+
+func containsFile(files []string, file string) bool {
+	for _, f := range files {
+		if f == file {
+			return true
+		}
+	}
+	return false
+}
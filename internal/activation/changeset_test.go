@@ -0,0 +1,49 @@
+package activation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestMergeFileMatches(t *testing.T) {
+	result := func(id string, specificity int, score float64) ActivationResult {
+		return ActivationResult{Behavior: models.Behavior{ID: id}, Specificity: specificity, MatchScore: score}
+	}
+	files := []string{"main.go", "app.ts"}
+	perFile := [][]ActivationResult{
+		{result("always", 0, 0), result("go-only", 1, 1), result("shared", 1, 0.5)},
+		{result("always", 0, 0), result("ts-only", 1, 1), result("shared", 2, 1)},
+	}
+
+	merged, triggeredBy := MergeFileMatches(files, perFile)
+
+	var ids []string
+	for _, m := range merged {
+		ids = append(ids, m.Behavior.ID)
+	}
+	if want := []string{"always", "go-only", "shared", "ts-only"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("merged IDs = %v, want %v", ids, want)
+	}
+	if merged[2].Specificity != 2 {
+		t.Errorf("shared Specificity = %d, want the more specific match (2)", merged[2].Specificity)
+	}
+
+	wantTriggers := map[string][]string{
+		"always":  {"main.go", "app.ts"},
+		"go-only": {"main.go"},
+		"shared":  {"main.go", "app.ts"},
+		"ts-only": {"app.ts"},
+	}
+	if !reflect.DeepEqual(triggeredBy, wantTriggers) {
+		t.Errorf("triggeredBy = %v, want %v", triggeredBy, wantTriggers)
+	}
+}
+
+func TestMergeFileMatches_Empty(t *testing.T) {
+	merged, triggeredBy := MergeFileMatches(nil, nil)
+	if len(merged) != 0 || len(triggeredBy) != 0 {
+		t.Errorf("MergeFileMatches(nil) = %v, %v, want empty", merged, triggeredBy)
+	}
+}
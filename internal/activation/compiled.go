@@ -0,0 +1,71 @@
+package activation
+
+import (
+	"reflect"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// compiledCondition is one when-condition prepared for repeated evaluation.
+type compiledCondition struct {
+	key      string
+	required interface{}
+	match    models.ConditionMatcher
+}
+
+// compiledWhen caches the compiled conditions of one behavior together with
+// a copy of the when-map they were compiled from, so a changed behavior is
+// recompiled rather than matched with stale conditions.
+type compiledWhen struct {
+	when       map[string]interface{}
+	conditions []compiledCondition
+}
+
+func compileWhen(when map[string]interface{}) *compiledWhen {
+	cw := &compiledWhen{
+		when:       make(map[string]interface{}, len(when)),
+		conditions: make([]compiledCondition, 0, len(when)),
+	}
+	for key, required := range when {
+		cw.when[key] = required
+		cw.conditions = append(cw.conditions, compiledCondition{
+			key:      key,
+			required: required,
+			match:    models.CompileCondition(key, required),
+		})
+	}
+	return cw
+}
+
+// conditionsFor returns the compiled conditions for b, compiling them on
+// first use and again whenever b's when-conditions have changed.
+func (e *Evaluator) conditionsFor(b models.Behavior) []compiledCondition {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if cw, ok := e.compiled[b.ID]; ok && reflect.DeepEqual(cw.when, b.When) {
+		return cw.conditions
+	}
+	cw := compileWhen(b.When)
+	if e.compiled == nil {
+		e.compiled = make(map[string]*compiledWhen)
+	}
+	e.compiled[b.ID] = cw
+	return cw.conditions
+}
+
+// Invalidate drops the compiled conditions cached for the given behavior IDs,
+// or for every behavior when called with no IDs. Changed behaviors are
+// detected automatically; call this to release entries for deleted ones.
+func (e *Evaluator) Invalidate(ids ...string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(ids) == 0 {
+		e.compiled = nil
+		return
+	}
+	for _, id := range ids {
+		delete(e.compiled, id)
+	}
+}
@@ -0,0 +1,50 @@
+package activation
+
+import (
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestEvaluator_RecompilesChangedConditions(t *testing.T) {
+	evaluator := NewEvaluator()
+	ctx := models.ContextSnapshot{FileLanguage: "go"}
+
+	b := models.Behavior{ID: "b1", When: map[string]interface{}{"language": "go"}}
+	if !evaluator.IsActive(ctx, b) {
+		t.Fatal("behavior should be active for go")
+	}
+
+	// Same ID, new conditions: the cached matchers must not be reused
+	b.When = map[string]interface{}{"language": "python"}
+	if evaluator.IsActive(ctx, b) {
+		t.Error("behavior updated to python should not be active for go")
+	}
+
+	// In-place edits are detected too
+	b.When["language"] = "go"
+	if !evaluator.IsActive(ctx, b) {
+		t.Error("behavior edited back to go should be active")
+	}
+}
+
+func TestEvaluator_Invalidate(t *testing.T) {
+	evaluator := NewEvaluator()
+	ctx := models.ContextSnapshot{FileLanguage: "go"}
+	for _, id := range []string{"b1", "b2"} {
+		evaluator.IsActive(ctx, models.Behavior{ID: id, When: map[string]interface{}{"language": "go"}})
+	}
+	if len(evaluator.compiled) != 2 {
+		t.Fatalf("compiled entries = %d, want 2", len(evaluator.compiled))
+	}
+
+	evaluator.Invalidate("b1")
+	if _, ok := evaluator.compiled["b1"]; ok || len(evaluator.compiled) != 1 {
+		t.Errorf("after Invalidate(b1) compiled = %v, want only b2", evaluator.compiled)
+	}
+
+	evaluator.Invalidate()
+	if len(evaluator.compiled) != 0 {
+		t.Errorf("after Invalidate() compiled entries = %d, want 0", len(evaluator.compiled))
+	}
+}
@@ -5,10 +5,12 @@ import (
 	"os/exec"
 	"os/user"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/tagging"
 )
 
 // ContextBuilder gathers context from the environment for activation evaluation
@@ -19,6 +21,16 @@ type ContextBuilder struct {
 	Environment string
 	Language    string
 	RepoRoot    string
+	Agent       string
+	Tags        []string
+
+	// SniffContent reads the head of FilePath for import statements and
+	// framework markers
+	SniffContent bool
+
+	// GitContext captures staged files, changed paths, and the commit
+	// message in progress from the repository at RepoRoot
+	GitContext bool
 
 	// Additional custom values
 	Custom map[string]interface{}
@@ -61,6 +73,30 @@ func (b *ContextBuilder) WithRepoRoot(root string) *ContextBuilder {
 	return b
 }
 
+// WithAgent sets the agent client name (e.g. from MCP initialize)
+func (b *ContextBuilder) WithAgent(agent string) *ContextBuilder {
+	b.Agent = agent
+	return b
+}
+
+// WithTags sets the tags declared for the current work (e.g. "testing/unit")
+func (b *ContextBuilder) WithTags(tags []string) *ContextBuilder {
+	b.Tags = tags
+	return b
+}
+
+// WithContentSniffing enables reading the file for imports and frameworks
+func (b *ContextBuilder) WithContentSniffing(enabled bool) *ContextBuilder {
+	b.SniffContent = enabled
+	return b
+}
+
+// WithGitContext enables capturing the working tree state from git
+func (b *ContextBuilder) WithGitContext(enabled bool) *ContextBuilder {
+	b.GitContext = enabled
+	return b
+}
+
 // WithCustom adds a custom context field
 func (b *ContextBuilder) WithCustom(key string, value interface{}) *ContextBuilder {
 	b.Custom[key] = value
@@ -86,6 +122,11 @@ func (b *ContextBuilder) Build() models.ContextSnapshot {
 		ctx.FileLanguage = b.Language
 	}
 
+	// Content signals: imports and the frameworks they reveal
+	if b.SniffContent && b.FilePath != "" {
+		ctx.Imports, ctx.Frameworks = sniffFile(b.FilePath, ctx.FileLanguage)
+	}
+
 	// Set task
 	if b.Task != "" {
 		ctx.Task = b.Task
@@ -100,6 +141,22 @@ func (b *ContextBuilder) Build() models.ContextSnapshot {
 		ctx.Environment = detectEnvironment()
 	}
 
+	// Set agent - check override, then FLOOP_AGENT
+	if b.Agent != "" {
+		ctx.Agent = b.Agent
+	} else if agent := os.Getenv("FLOOP_AGENT"); agent != "" {
+		ctx.Agent = agent
+	}
+
+	// Set tags - check override, then FLOOP_TAGS (comma-separated)
+	tags := b.Tags
+	if len(tags) == 0 {
+		if env := os.Getenv("FLOOP_TAGS"); env != "" {
+			tags = strings.Split(env, ",")
+		}
+	}
+	ctx.Tags = normalizeTags(tags)
+
 	// Get git info
 	repoRoot := b.RepoRoot
 	if repoRoot == "" {
@@ -109,9 +166,20 @@ func (b *ContextBuilder) Build() models.ContextSnapshot {
 	ctx.Repo = getGitRemote(repoRoot)
 	ctx.Branch = getGitBranch(repoRoot)
 
+	// Working tree state: staged files, changed paths, commit message
+	if b.GitContext {
+		state := captureGitState(repoRoot)
+		ctx.StagedFiles = state.staged
+		ctx.ChangedPaths = state.changed
+		ctx.CommitMessage = state.message
+	}
+
 	// Infer project type from repo root
 	ctx.ProjectType = models.InferProjectType(repoRoot)
 
+	// Detect repository facts (cached in .floop/context-cache.json)
+	ctx.RepoFacts = RepoFingerprint(repoRoot)
+
 	// Get user info
 	if u, err := user.Current(); err == nil {
 		ctx.User = u.Username
@@ -120,6 +188,20 @@ func (b *ContextBuilder) Build() models.ContextSnapshot {
 	return ctx
 }
 
+// normalizeTags normalizes declared tags, dropping blanks and repeats.
+func normalizeTags(tags []string) []string {
+	var out []string
+	seen := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		if t = tagging.NormalizeTag(t, nil); t != "" && !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
 // detectEnvironment detects CI/test environment from environment variables
 func detectEnvironment() string {
 	// Check specific CI providers first (more specific)
@@ -3,6 +3,7 @@ package activation
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/nvandessel/floop/internal/models"
@@ -355,3 +356,44 @@ func TestContextBuilder_WithLanguage(t *testing.T) {
 		})
 	}
 }
+
+func TestContextBuilder_WithTags(t *testing.T) {
+	t.Setenv("FLOOP_TAGS", "")
+
+	if ctx := NewContextBuilder().Build(); ctx.Tags != nil {
+		t.Errorf("Tags = %v, want none", ctx.Tags)
+	}
+
+	t.Setenv("FLOOP_TAGS", "testing/unit, Go")
+	ctx := NewContextBuilder().Build()
+	if !reflect.DeepEqual(ctx.Tags, []string{"go", "testing/unit"}) {
+		t.Errorf("Tags = %v, want normalized tags from FLOOP_TAGS", ctx.Tags)
+	}
+
+	// Explicit tags take precedence over FLOOP_TAGS
+	ctx = NewContextBuilder().WithTags([]string{"Review/", "review", " "}).Build()
+	if !reflect.DeepEqual(ctx.Tags, []string{"review"}) {
+		t.Errorf("Tags = %v, want [review]", ctx.Tags)
+	}
+}
+
+func TestContextBuilder_WithAgent(t *testing.T) {
+	t.Setenv("FLOOP_AGENT", "")
+
+	ctx := NewContextBuilder().Build()
+	if ctx.Agent != "" {
+		t.Errorf("Agent = %q, want empty", ctx.Agent)
+	}
+
+	t.Setenv("FLOOP_AGENT", "cursor")
+	ctx = NewContextBuilder().Build()
+	if ctx.Agent != "cursor" {
+		t.Errorf("Agent = %q, want %q from FLOOP_AGENT", ctx.Agent, "cursor")
+	}
+
+	// Explicit agent takes precedence over FLOOP_AGENT
+	ctx = NewContextBuilder().WithAgent("claude-code").Build()
+	if ctx.Agent != "claude-code" {
+		t.Errorf("Agent = %q, want %q", ctx.Agent, "claude-code")
+	}
+}
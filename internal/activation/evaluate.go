@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/nvandessel/floop/internal/models"
 )
@@ -32,31 +34,35 @@ type MatchResult struct {
 	Contradicted []string               // conditions where context value differed
 }
 
-// Evaluator determines which behaviors are active for a given context
-type Evaluator struct{}
+// Evaluator determines which behaviors are active for a given context.
+// It caches each behavior's compiled when-conditions by ID, so an evaluator
+// that is kept around does not re-parse conditions on every call. It is safe
+// for concurrent use.
+type Evaluator struct {
+	opts MatchOptions
 
-// NewEvaluator creates a new evaluator
+	mu       sync.Mutex
+	compiled map[string]*compiledWhen
+}
+
+// NewEvaluator creates a new evaluator using strict matching
 func NewEvaluator() *Evaluator {
-	return &Evaluator{}
+	return &Evaluator{
+		opts:     MatchOptions{Mode: MatchStrict},
+		compiled: make(map[string]*compiledWhen),
+	}
 }
 
 // Evaluate checks which behaviors match the given context.
 // A behavior matches if none of its conditions are contradicted.
 // Absent conditions (context has no value for the key) are neutral.
+// Behaviors whose expiry has passed (as of ctx.Timestamp, or now if unset)
+// never match.
 // Returns behaviors that match, sorted by specificity (most specific first).
 func (e *Evaluator) Evaluate(ctx models.ContextSnapshot, behaviors []models.Behavior) []ActivationResult {
 	var results []ActivationResult
-
 	for _, b := range behaviors {
-		mr := e.evaluateMatch(ctx, b)
-		if mr.Matched {
-			results = append(results, ActivationResult{
-				Behavior:          b,
-				MatchedConditions: mr.Confirmed,
-				Specificity:       len(mr.Confirmed),
-				MatchScore:        mr.Score,
-			})
-		}
+		results = e.appendMatch(results, ctx, b)
 	}
 
 	// Sort by specificity (higher first), then by priority
@@ -65,12 +71,53 @@ func (e *Evaluator) Evaluate(ctx models.ContextSnapshot, behaviors []models.Beha
 	return results
 }
 
+// EvaluateIndex is Evaluate over the behaviors in idx, skipping those the
+// index rules out. Use it when the same behaviors are evaluated against many
+// contexts; the results are the same as Evaluate's. In graded mode a
+// contradicted condition does not rule a behavior out, so every indexed
+// behavior is evaluated.
+func (e *Evaluator) EvaluateIndex(ctx models.ContextSnapshot, idx *Index) []ActivationResult {
+	if e.opts.Mode == MatchGraded {
+		return e.Evaluate(ctx, idx.behaviors)
+	}
+
+	var results []ActivationResult
+	for _, pos := range idx.candidates(ctx) {
+		results = e.appendMatch(results, ctx, idx.behaviors[pos])
+	}
+
+	sortBySpecificityAndPriority(results)
+
+	return results
+}
+
+// appendMatch appends b's activation result to results if b matches ctx.
+func (e *Evaluator) appendMatch(results []ActivationResult, ctx models.ContextSnapshot, b models.Behavior) []ActivationResult {
+	mr := e.evaluateMatch(ctx, b)
+	if !mr.Matched {
+		return results
+	}
+	return append(results, ActivationResult{
+		Behavior:          b,
+		MatchedConditions: mr.Confirmed,
+		Specificity:       len(mr.Confirmed),
+		MatchScore:        mr.Score,
+	})
+}
+
 // evaluateMatch checks a behavior's when-conditions against the context using
 // partial matching semantics:
 //   - Confirmed: context has the key and values match
 //   - Contradicted: context has the key but values differ (excludes behavior)
 //   - Absent: context doesn't have the key (neutral)
+//
+// In graded mode the score is weighted by field importance, and a behavior
+// with contradicted conditions still matches if its score reaches the
+// minimum. Expired behaviors never match.
 func (e *Evaluator) evaluateMatch(ctx models.ContextSnapshot, b models.Behavior) MatchResult {
+	if b.IsExpired(evaluationTime(ctx)) {
+		return MatchResult{Matched: false}
+	}
 	if len(b.When) == 0 {
 		return MatchResult{Matched: true, Score: 0.0, Confirmed: nil}
 	}
@@ -79,14 +126,26 @@ func (e *Evaluator) evaluateMatch(ctx models.ContextSnapshot, b models.Behavior)
 	var absent []string
 	var contradicted []string
 
-	for key, required := range b.When {
-		matched, hasValue := ctx.MatchField(key, required)
+	for _, cond := range e.conditionsFor(b) {
+		matched, hasValue := cond.match(&ctx)
 		if hasValue && !matched {
-			contradicted = append(contradicted, key)
+			contradicted = append(contradicted, cond.key)
 		} else if hasValue && matched {
-			confirmed[key] = required
+			confirmed[cond.key] = cond.required
 		} else {
-			absent = append(absent, key)
+			absent = append(absent, cond.key)
+		}
+	}
+
+	if e.opts.Mode == MatchGraded {
+		score := e.opts.weightedScore(b.When, confirmed)
+		matched := len(contradicted) == 0 || (score > 0 && score >= e.opts.minScore())
+		return MatchResult{
+			Matched:      matched,
+			Score:        score,
+			Confirmed:    confirmed,
+			Absent:       absent,
+			Contradicted: contradicted,
 		}
 	}
 
@@ -109,6 +168,15 @@ func (e *Evaluator) evaluateMatch(ctx models.ContextSnapshot, b models.Behavior)
 	}
 }
 
+// evaluationTime is the time expiry is checked against: the context
+// timestamp, or now if the context has none.
+func evaluationTime(ctx models.ContextSnapshot) time.Time {
+	if ctx.Timestamp.IsZero() {
+		return time.Now()
+	}
+	return ctx.Timestamp
+}
+
 // sortBySpecificityAndPriority sorts results by specificity desc, then priority desc
 func sortBySpecificityAndPriority(results []ActivationResult) {
 	sort.Slice(results, func(i, j int) bool {
@@ -126,11 +194,33 @@ func (e *Evaluator) IsActive(ctx models.ContextSnapshot, b models.Behavior) bool
 	return mr.Matched
 }
 
+// MayMatch reports whether a behavior with the when-conditions when could
+// match ctx. Partitioned stores use it to skip partitions whose shared
+// conditions ctx contradicts. In graded mode a contradiction does not rule
+// a behavior out, so it always returns true.
+func (e *Evaluator) MayMatch(ctx models.ContextSnapshot, when map[string]interface{}) bool {
+	if e.opts.Mode == MatchGraded {
+		return true
+	}
+	for _, cond := range compileWhen(when).conditions {
+		if matched, hasValue := cond.match(&ctx); hasValue && !matched {
+			return false
+		}
+	}
+	return true
+}
+
 // WhyActive explains why a behavior is or isn't active for a context
 func (e *Evaluator) WhyActive(ctx models.ContextSnapshot, b models.Behavior) ActivationExplanation {
 	explanation := ActivationExplanation{
 		BehaviorID: b.ID,
 		IsActive:   false,
+		Mode:       e.opts.Mode,
+	}
+
+	if b.IsExpired(evaluationTime(ctx)) {
+		explanation.Reason = fmt.Sprintf("Expired at %s", b.ExpiresAt.Format(time.RFC3339))
+		return explanation
 	}
 
 	if len(b.When) == 0 {
@@ -141,6 +231,7 @@ func (e *Evaluator) WhyActive(ctx models.ContextSnapshot, b models.Behavior) Act
 
 	// Reuse evaluateMatch for the core classification logic
 	mr := e.evaluateMatch(ctx, b)
+	explanation.Score = mr.Score
 
 	// Build condition details from the match result
 	for key, required := range b.When {
@@ -148,6 +239,10 @@ func (e *Evaluator) WhyActive(ctx models.ContextSnapshot, b models.Behavior) Act
 			Field:    key,
 			Required: required,
 			Actual:   ctx.GetField(key),
+			Clauses:  ctx.ExplainCondition(key, required),
+		}
+		if e.opts.Mode == MatchGraded {
+			conditionResult.Weight = e.opts.weight(key)
 		}
 
 		if _, ok := mr.Confirmed[key]; ok {
@@ -166,9 +261,20 @@ func (e *Evaluator) WhyActive(ctx models.ContextSnapshot, b models.Behavior) Act
 
 	// Behavior is active if no conditions are contradicted
 	explanation.IsActive = mr.Matched
+	sort.Slice(explanation.Conditions, func(i, j int) bool {
+		return explanation.Conditions[i].Field < explanation.Conditions[j].Field
+	})
 	if len(mr.Contradicted) > 0 {
 		sort.Strings(mr.Contradicted)
-		explanation.Reason = fmt.Sprintf("Contradicted on: %s", strings.Join(mr.Contradicted, ", "))
+		contradicted := strings.Join(mr.Contradicted, ", ")
+		switch {
+		case mr.Matched:
+			explanation.Reason = fmt.Sprintf("Partially matched (score %.2f, contradicted on: %s)", mr.Score, contradicted)
+		case e.opts.Mode == MatchGraded:
+			explanation.Reason = fmt.Sprintf("Contradicted on: %s (score %.2f, below %.2f)", contradicted, mr.Score, e.opts.minScore())
+		default:
+			explanation.Reason = fmt.Sprintf("Contradicted on: %s", contradicted)
+		}
 	} else if len(mr.Absent) == 0 {
 		explanation.Reason = "All conditions confirmed"
 	} else {
@@ -194,6 +300,8 @@ type ActivationExplanation struct {
 	BehaviorID string            `json:"behavior_id"`
 	IsActive   bool              `json:"is_active"`
 	Reason     string            `json:"reason"`
+	Mode       MatchMode         `json:"mode"`
+	Score      float64           `json:"score"` // match score; weighted in graded mode
 	Conditions []ConditionResult `json:"conditions,omitempty"`
 }
 
@@ -203,5 +311,9 @@ type ConditionResult struct {
 	Required interface{} `json:"required"`
 	Actual   interface{} `json:"actual"`
 	Matched  bool        `json:"matched"`
-	Status   string      `json:"status"` // "confirmed", "contradicted", "absent"
+	Status   string      `json:"status"`           // "confirmed", "contradicted", "absent"
+	Weight   float64     `json:"weight,omitempty"` // field importance, graded mode only
+
+	// Clauses break down operator and "any" conditions (see models.WhenAny)
+	Clauses []models.ClauseResult `json:"clauses,omitempty"`
 }
@@ -1,6 +1,7 @@
 package activation
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -200,6 +201,40 @@ func TestEvaluator_WhyActive(t *testing.T) {
 	}
 }
 
+func TestEvaluator_WhyActive_Clauses(t *testing.T) {
+	behavior := models.Behavior{
+		ID: "b1",
+		When: map[string]interface{}{
+			"file_path": map[string]interface{}{"not": map[string]interface{}{"glob": "vendor/**"}},
+			models.WhenAny: []interface{}{
+				map[string]interface{}{"language": "python"},
+				map[string]interface{}{"task": "testing"},
+			},
+		},
+	}
+	ctx := models.ContextSnapshot{FilePath: "internal/x_test.go", FileLanguage: "go", Task: "testing"}
+
+	explanation := NewEvaluator().WhyActive(ctx, behavior)
+	if !explanation.IsActive || len(explanation.Conditions) != 2 {
+		t.Fatalf("WhyActive() = %+v, want active with 2 conditions", explanation)
+	}
+	anyCond, pathCond := explanation.Conditions[0], explanation.Conditions[1]
+	if anyCond.Field != models.WhenAny || anyCond.Status != "confirmed" || len(anyCond.Clauses) != 2 {
+		t.Fatalf("any condition = %+v", anyCond)
+	}
+	if anyCond.Clauses[0].Status != models.StatusContradicted || anyCond.Clauses[1].Status != models.StatusConfirmed {
+		t.Errorf("any clauses = %+v, want python contradicted and testing confirmed", anyCond.Clauses)
+	}
+	if pathCond.Status != "confirmed" || len(pathCond.Clauses) != 1 || pathCond.Clauses[0].Clause != `not glob "vendor/**"` {
+		t.Errorf("file_path condition = %+v", pathCond)
+	}
+
+	ctx.FilePath = "vendor/lib/x.go"
+	if NewEvaluator().IsActive(ctx, behavior) {
+		t.Error("behavior should not be active under vendor/")
+	}
+}
+
 func TestEvaluator_PartialMatching(t *testing.T) {
 	evaluator := NewEvaluator()
 
@@ -297,3 +332,83 @@ func TestEvaluator_NoConditions(t *testing.T) {
 		t.Errorf("Unexpected reason: %s", explanation.Reason)
 	}
 }
+
+func TestEvaluator_MayMatch(t *testing.T) {
+	ctx := models.ContextSnapshot{FileLanguage: "go", Task: "testing"}
+
+	tests := []struct {
+		name string
+		opts MatchOptions
+		when map[string]interface{}
+		want bool
+	}{
+		{"no shared conditions", MatchOptions{}, nil, true},
+		{"confirmed", MatchOptions{}, map[string]interface{}{"language": "go"}, true},
+		{"absent from context", MatchOptions{}, map[string]interface{}{"branch": "main"}, true},
+		{"contradicted", MatchOptions{}, map[string]interface{}{"language": "python"}, false},
+		{"graded never rules out", MatchOptions{Mode: MatchGraded}, map[string]interface{}{"language": "python"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewEvaluator().WithMatchOptions(tt.opts).MayMatch(ctx, tt.when); got != tt.want {
+				t.Errorf("MayMatch(%v) = %v, want %v", tt.when, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluator_Expiry(t *testing.T) {
+	evaluator := NewEvaluator()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	behaviors := []models.Behavior{
+		{ID: "expired", ExpiresAt: &past},
+		{ID: "current", ExpiresAt: &future},
+		{ID: "forever"},
+	}
+
+	results := evaluator.Evaluate(models.ContextSnapshot{Timestamp: now}, behaviors)
+	got := make(map[string]bool)
+	for _, r := range results {
+		got[r.Behavior.ID] = true
+	}
+	if got["expired"] || !got["current"] || !got["forever"] {
+		t.Errorf("Evaluate() active = %v, want current and forever only", got)
+	}
+
+	// The context timestamp decides expiry, so a later context retires "current"
+	if evaluator.IsActive(models.ContextSnapshot{Timestamp: future}, behaviors[1]) {
+		t.Error("behavior should be inactive at its expiry time")
+	}
+
+	explanation := evaluator.WhyActive(models.ContextSnapshot{Timestamp: now}, behaviors[0])
+	if explanation.IsActive || !strings.HasPrefix(explanation.Reason, "Expired at") {
+		t.Errorf("WhyActive() = %+v, want inactive with expiry reason", explanation)
+	}
+}
+
+func TestEvaluator_TemporalConditions(t *testing.T) {
+	evaluator := NewEvaluator()
+	freeze := models.Behavior{
+		ID:   "freeze",
+		When: map[string]interface{}{"before": "2026-06-01"},
+	}
+
+	during := models.ContextSnapshot{Timestamp: time.Date(2026, 5, 31, 23, 0, 0, 0, time.UTC)}
+	after := models.ContextSnapshot{Timestamp: time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)}
+
+	results := evaluator.Evaluate(during, []models.Behavior{freeze})
+	if len(results) != 1 || results[0].Specificity != 1 {
+		t.Fatalf("Evaluate() during freeze = %+v, want freeze confirmed", results)
+	}
+	if len(evaluator.Evaluate(after, []models.Behavior{freeze})) != 0 {
+		t.Error("freeze behavior should deactivate on its end date")
+	}
+
+	explanation := evaluator.WhyActive(after, freeze)
+	if explanation.IsActive || explanation.Reason != "Contradicted on: before" {
+		t.Errorf("WhyActive() = %+v, want contradicted on before", explanation)
+	}
+}
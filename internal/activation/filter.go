@@ -0,0 +1,95 @@
+package activation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// filterableKinds are the behavior kinds accepted by KindFilter.
+var filterableKinds = map[models.BehaviorKind]bool{
+	models.BehaviorKindDirective:  true,
+	models.BehaviorKindConstraint: true,
+	models.BehaviorKindProcedure:  true,
+	models.BehaviorKindPreference: true,
+	models.BehaviorKindEpisodic:   true,
+	models.BehaviorKindWorkflow:   true,
+}
+
+// KindFilter restricts an active set to certain behavior kinds.
+// An empty Include list means all kinds are included; Exclude is applied after Include.
+type KindFilter struct {
+	Include []models.BehaviorKind `json:"include,omitempty"`
+	Exclude []models.BehaviorKind `json:"exclude,omitempty"`
+}
+
+// ParseKindFilter builds a KindFilter from raw kind names, validating each.
+// Entries may be comma-separated ("constraint,directive") and are trimmed.
+func ParseKindFilter(include, exclude []string) (KindFilter, error) {
+	var f KindFilter
+	var err error
+	if f.Include, err = parseKinds(include); err != nil {
+		return KindFilter{}, err
+	}
+	if f.Exclude, err = parseKinds(exclude); err != nil {
+		return KindFilter{}, err
+	}
+	return f, nil
+}
+
+func parseKinds(raw []string) ([]models.BehaviorKind, error) {
+	var kinds []models.BehaviorKind
+	for _, entry := range raw {
+		for _, part := range strings.Split(entry, ",") {
+			part = strings.TrimSpace(strings.ToLower(part))
+			if part == "" {
+				continue
+			}
+			kind := models.BehaviorKind(part)
+			if !filterableKinds[kind] {
+				return nil, fmt.Errorf("invalid behavior kind: %s (valid: directive, constraint, procedure, preference, episodic, workflow)", part)
+			}
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds, nil
+}
+
+// IsEmpty reports whether the filter would let every behavior through.
+func (f KindFilter) IsEmpty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0
+}
+
+// Allows reports whether a behavior kind passes the filter.
+func (f KindFilter) Allows(kind models.BehaviorKind) bool {
+	if len(f.Include) > 0 && !containsKind(f.Include, kind) {
+		return false
+	}
+	return !containsKind(f.Exclude, kind)
+}
+
+// Apply returns the behaviors that pass the filter, preserving order.
+// Filtering is meant to run after conflict resolution so that an excluded
+// behavior still suppresses the behaviors it overrides or conflicts with.
+func (f KindFilter) Apply(behaviors []models.Behavior) []models.Behavior {
+	if f.IsEmpty() {
+		return behaviors
+	}
+	filtered := make([]models.Behavior, 0, len(behaviors))
+	for _, b := range behaviors {
+		if f.Allows(b.Kind) {
+			filtered = append(filtered, b)
+		}
+	}
+	return filtered
+}
+
+func containsKind(kinds []models.BehaviorKind, kind models.BehaviorKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
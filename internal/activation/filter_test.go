@@ -0,0 +1,73 @@
+package activation
+
+import (
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestParseKindFilter(t *testing.T) {
+	tests := []struct {
+		name        string
+		include     []string
+		exclude     []string
+		wantInclude int
+		wantExclude int
+		wantErr     bool
+	}{
+		{"empty", nil, nil, 0, 0, false},
+		{"separate entries", []string{"constraint", "directive"}, nil, 2, 0, false},
+		{"comma separated", []string{"constraint, directive"}, []string{"episodic"}, 2, 1, false},
+		{"case insensitive", []string{"Constraint"}, nil, 1, 0, false},
+		{"blank entries ignored", []string{"", " , "}, nil, 0, 0, false},
+		{"invalid include", []string{"rule"}, nil, 0, 0, true},
+		{"invalid exclude", nil, []string{"forgotten-behavior"}, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseKindFilter(tt.include, tt.exclude)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKindFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(f.Include) != tt.wantInclude || len(f.Exclude) != tt.wantExclude {
+				t.Errorf("got include=%v exclude=%v", f.Include, f.Exclude)
+			}
+		})
+	}
+}
+
+func TestKindFilter_Apply(t *testing.T) {
+	behaviors := []models.Behavior{
+		{ID: "c1", Kind: models.BehaviorKindConstraint},
+		{ID: "d1", Kind: models.BehaviorKindDirective},
+		{ID: "p1", Kind: models.BehaviorKindPreference},
+		{ID: "c2", Kind: models.BehaviorKindConstraint},
+	}
+
+	tests := []struct {
+		name   string
+		filter KindFilter
+		want   []string
+	}{
+		{"empty passes all", KindFilter{}, []string{"c1", "d1", "p1", "c2"}},
+		{"include only constraints", KindFilter{Include: []models.BehaviorKind{models.BehaviorKindConstraint}}, []string{"c1", "c2"}},
+		{"exclude preferences", KindFilter{Exclude: []models.BehaviorKind{models.BehaviorKindPreference}}, []string{"c1", "d1", "c2"}},
+		{"exclude wins over include", KindFilter{
+			Include: []models.BehaviorKind{models.BehaviorKindConstraint, models.BehaviorKindDirective},
+			Exclude: []models.BehaviorKind{models.BehaviorKindDirective},
+		}, []string{"c1", "c2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.Apply(behaviors)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Apply() returned %d behaviors, want %d", len(got), len(tt.want))
+			}
+			for i, b := range got {
+				if b.ID != tt.want[i] {
+					t.Errorf("Apply()[%d] = %s, want %s", i, b.ID, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package activation
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// ContextCacheFile is the file in .floop/ that caches the repository
+// fingerprint between runs.
+const ContextCacheFile = "context-cache.json"
+
+// repoMarker is a file or directory whose presence reveals a repository fact.
+type repoMarker struct {
+	path           string
+	buildSystem    string
+	packageManager string
+	ci             string
+	monorepoTool   string
+}
+
+// repoMarkers are checked at the repository root. Their modification times
+// form the cache key, so editing, adding, or removing one refreshes the
+// fingerprint.
+var repoMarkers = []repoMarker{
+	{path: "go.mod", buildSystem: "go", packageManager: "go"},
+	{path: "go.work", monorepoTool: "go-workspace"},
+	{path: "Cargo.toml", buildSystem: "cargo", packageManager: "cargo"},
+	{path: "package.json"},
+	{path: "package-lock.json", packageManager: "npm"},
+	{path: "yarn.lock", packageManager: "yarn"},
+	{path: "pnpm-lock.yaml", packageManager: "pnpm"},
+	{path: "bun.lockb", packageManager: "bun"},
+	{path: "pyproject.toml"},
+	{path: "poetry.lock", packageManager: "poetry"},
+	{path: "uv.lock", packageManager: "uv"},
+	{path: "Pipfile", packageManager: "pipenv"},
+	{path: "requirements.txt", packageManager: "pip"},
+	{path: "setup.py"},
+	{path: "Gemfile", packageManager: "bundler"},
+	{path: "composer.json", packageManager: "composer"},
+	{path: "pom.xml", buildSystem: "maven"},
+	{path: "build.gradle", buildSystem: "gradle"},
+	{path: "build.gradle.kts", buildSystem: "gradle"},
+	{path: "Makefile", buildSystem: "make"},
+	{path: "CMakeLists.txt", buildSystem: "cmake"},
+	{path: "WORKSPACE", buildSystem: "bazel", monorepoTool: "bazel"},
+	{path: "WORKSPACE.bazel", buildSystem: "bazel", monorepoTool: "bazel"},
+	{path: "MODULE.bazel", buildSystem: "bazel", monorepoTool: "bazel"},
+	{path: "flake.nix", buildSystem: "nix"},
+	{path: "Dockerfile"},
+	{path: "nx.json", monorepoTool: "nx"},
+	{path: "turbo.json", monorepoTool: "turborepo"},
+	{path: "lerna.json", monorepoTool: "lerna"},
+	{path: "pnpm-workspace.yaml", monorepoTool: "pnpm-workspaces"},
+	{path: "rush.json", monorepoTool: "rush"},
+	{path: ".github/workflows", ci: "github-actions"},
+	{path: ".gitlab-ci.yml", ci: "gitlab-ci"},
+	{path: ".circleci", ci: "circleci"},
+	{path: "Jenkinsfile", ci: "jenkins"},
+	{path: ".travis.yml", ci: "travis"},
+	{path: "azure-pipelines.yml", ci: "azure-pipelines"},
+	{path: "bitbucket-pipelines.yml", ci: "bitbucket-pipelines"},
+	{path: ".buildkite", ci: "buildkite"},
+	{path: ".drone.yml", ci: "drone"},
+}
+
+// contextCache is the on-disk form of ContextCacheFile.
+type contextCache struct {
+	// Markers maps each present marker path to its modification time.
+	Markers     map[string]time.Time    `json:"markers"`
+	Fingerprint *models.RepoFingerprint `json:"fingerprint"`
+	DetectedAt  time.Time               `json:"detected_at"`
+}
+
+// RepoFingerprint returns the repository fingerprint for repoRoot. When the
+// repository has a .floop directory, the result is cached in
+// .floop/context-cache.json and recomputed only when a marker file is added,
+// removed, or modified. Returns nil when no marker is present.
+func RepoFingerprint(repoRoot string) *models.RepoFingerprint {
+	markers := statMarkers(repoRoot)
+	if len(markers) == 0 {
+		return nil
+	}
+
+	floopDir := filepath.Join(repoRoot, ".floop")
+	cachePath := filepath.Join(floopDir, ContextCacheFile)
+	if cached, ok := readContextCache(cachePath); ok && sameMarkers(cached.Markers, markers) {
+		return cached.Fingerprint
+	}
+
+	fp := detectFingerprint(repoRoot, markers)
+	if info, err := os.Stat(floopDir); err == nil && info.IsDir() {
+		// Best effort: a read-only .floop just means detecting again next time
+		writeContextCache(cachePath, contextCache{Markers: markers, Fingerprint: fp, DetectedAt: time.Now()})
+	}
+	return fp
+}
+
+// statMarkers returns the modification time of every marker present.
+func statMarkers(repoRoot string) map[string]time.Time {
+	markers := make(map[string]time.Time)
+	for _, m := range repoMarkers {
+		if info, err := os.Stat(filepath.Join(repoRoot, filepath.FromSlash(m.path))); err == nil {
+			markers[m.path] = info.ModTime()
+		}
+	}
+	return markers
+}
+
+func sameMarkers(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, t := range a {
+		if other, ok := b[path]; !ok || !other.Equal(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// detectFingerprint derives the fingerprint from the markers present.
+func detectFingerprint(repoRoot string, present map[string]time.Time) *models.RepoFingerprint {
+	files := make(map[string]bool)
+	buildSystems := make(map[string]bool)
+	packageManagers := make(map[string]bool)
+	ci := make(map[string]bool)
+	monorepoTools := make(map[string]bool)
+
+	for _, m := range repoMarkers {
+		if _, ok := present[m.path]; !ok {
+			continue
+		}
+		files[m.path] = true
+		buildSystems[m.buildSystem] = true
+		packageManagers[m.packageManager] = true
+		ci[m.ci] = true
+		monorepoTools[m.monorepoTool] = true
+	}
+
+	if files["package.json"] {
+		// A package.json without a lockfile is managed with npm
+		if !packageManagers["npm"] && !packageManagers["yarn"] && !packageManagers["pnpm"] && !packageManagers["bun"] {
+			packageManagers["npm"] = true
+		}
+		if packageWorkspaces(filepath.Join(repoRoot, "package.json")) {
+			monorepoTools["npm-workspaces"] = true
+		}
+	}
+
+	return &models.RepoFingerprint{
+		Files:           sortedSet(files),
+		BuildSystems:    sortedSet(buildSystems),
+		PackageManagers: sortedSet(packageManagers),
+		CI:              sortedSet(ci),
+		MonorepoTools:   sortedSet(monorepoTools),
+	}
+}
+
+// sortedSet returns the non-empty members of set, sorted.
+func sortedSet(set map[string]bool) []string {
+	var out []string
+	for v := range set {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// packageWorkspaces reports whether a package.json declares workspaces.
+func packageWorkspaces(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var pkg struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return false
+	}
+	return len(pkg.Workspaces) > 0 && string(pkg.Workspaces) != "null"
+}
+
+func readContextCache(path string) (contextCache, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return contextCache{}, false
+	}
+	var cache contextCache
+	if err := json.Unmarshal(data, &cache); err != nil || cache.Fingerprint == nil {
+		return contextCache{}, false
+	}
+	return cache, true
+}
+
+// writeContextCache writes the cache atomically via temp file + rename.
+func writeContextCache(path string, cache contextCache) {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+	}
+}
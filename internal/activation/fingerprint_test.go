@@ -0,0 +1,160 @@
+package activation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func writeRepoFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRepoFingerprint_Detect(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  models.RepoFingerprint
+	}{
+		{
+			name:  "go module with github actions",
+			files: map[string]string{"go.mod": "module x", "Makefile": "", ".github/workflows/ci.yml": ""},
+			want: models.RepoFingerprint{
+				Files:           []string{".github/workflows", "Makefile", "go.mod"},
+				BuildSystems:    []string{"go", "make"},
+				PackageManagers: []string{"go"},
+				CI:              []string{"github-actions"},
+			},
+		},
+		{
+			name:  "pnpm monorepo",
+			files: map[string]string{"package.json": `{"workspaces": ["packages/*"]}`, "pnpm-lock.yaml": "", "turbo.json": "{}"},
+			want: models.RepoFingerprint{
+				Files:           []string{"package.json", "pnpm-lock.yaml", "turbo.json"},
+				PackageManagers: []string{"pnpm"},
+				MonorepoTools:   []string{"npm-workspaces", "turborepo"},
+			},
+		},
+		{
+			name:  "bazel with plain package.json",
+			files: map[string]string{"MODULE.bazel": "", "package.json": "{}", ".gitlab-ci.yml": ""},
+			want: models.RepoFingerprint{
+				Files:           []string{".gitlab-ci.yml", "MODULE.bazel", "package.json"},
+				BuildSystems:    []string{"bazel"},
+				PackageManagers: []string{"npm"},
+				CI:              []string{"gitlab-ci"},
+				MonorepoTools:   []string{"bazel"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for rel, content := range tt.files {
+				writeRepoFile(t, root, rel, content)
+			}
+			got := RepoFingerprint(root)
+			if got == nil {
+				t.Fatal("RepoFingerprint() = nil")
+			}
+			check := func(field string, got, want []string) {
+				if strings.Join(got, ",") != strings.Join(want, ",") {
+					t.Errorf("%s = %v, want %v", field, got, want)
+				}
+			}
+			check("Files", got.Files, tt.want.Files)
+			check("BuildSystems", got.BuildSystems, tt.want.BuildSystems)
+			check("PackageManagers", got.PackageManagers, tt.want.PackageManagers)
+			check("CI", got.CI, tt.want.CI)
+			check("MonorepoTools", got.MonorepoTools, tt.want.MonorepoTools)
+		})
+	}
+}
+
+func TestRepoFingerprint_NoMarkers(t *testing.T) {
+	if fp := RepoFingerprint(t.TempDir()); fp != nil {
+		t.Errorf("RepoFingerprint() = %+v, want nil", fp)
+	}
+}
+
+func TestRepoFingerprint_CacheRefresh(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".floop"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	writeRepoFile(t, root, "go.mod", "module x")
+
+	fp := RepoFingerprint(root)
+	cachePath := filepath.Join(root, ".floop", ContextCacheFile)
+	if _, err := os.Stat(cachePath); err != nil {
+		t.Fatalf("cache not written: %v", err)
+	}
+	if strings.Join(fp.BuildSystems, ",") != "go" {
+		t.Fatalf("BuildSystems = %v, want [go]", fp.BuildSystems)
+	}
+
+	// An unchanged repository is served from the cache
+	cache, ok := readContextCache(cachePath)
+	if !ok {
+		t.Fatal("cache unreadable")
+	}
+	cache.Fingerprint.CI = []string{"from-cache"}
+	writeContextCache(cachePath, cache)
+	if got := RepoFingerprint(root); strings.Join(got.CI, ",") != "from-cache" {
+		t.Errorf("CI = %v, want cached value", got.CI)
+	}
+
+	// Adding a marker file invalidates the cache
+	writeRepoFile(t, root, "Jenkinsfile", "")
+	if got := RepoFingerprint(root); strings.Join(got.CI, ",") != "jenkins" {
+		t.Errorf("CI after adding Jenkinsfile = %v, want [jenkins]", got.CI)
+	}
+
+	// So does modifying one
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(root, "go.mod"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	cache, _ = readContextCache(cachePath)
+	cache.Fingerprint.BuildSystems = []string{"stale"}
+	writeContextCache(cachePath, cache)
+	if got := RepoFingerprint(root); strings.Join(got.BuildSystems, ",") != "go" {
+		t.Errorf("BuildSystems after touching go.mod = %v, want [go]", got.BuildSystems)
+	}
+}
+
+func TestRepoFingerprint_Conditions(t *testing.T) {
+	root := t.TempDir()
+	writeRepoFile(t, root, "go.mod", "module x")
+	writeRepoFile(t, root, "WORKSPACE", "")
+
+	ctx := NewContextBuilder().WithRepoRoot(root).Build()
+	evaluator := NewEvaluator()
+	tests := []struct {
+		when map[string]interface{}
+		want bool
+	}{
+		{map[string]interface{}{"build_system": "bazel"}, true},
+		{map[string]interface{}{"repo_files": "go.mod"}, true},
+		{map[string]interface{}{"monorepo_tool": []interface{}{"nx", "bazel"}}, true},
+		{map[string]interface{}{"package_manager": "npm"}, false},
+		{map[string]interface{}{"ci": "github-actions"}, true}, // absent, neutral
+	}
+	for _, tt := range tests {
+		b := models.Behavior{ID: "b", When: tt.when}
+		if got := evaluator.IsActive(ctx, b); got != tt.want {
+			t.Errorf("IsActive(%v) = %v, want %v", tt.when, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package activation
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Bounds on captured git state, so a huge working tree or message can't
+// bloat every context.
+const (
+	maxGitPaths         = 1000
+	maxCommitMessageLen = 4096
+)
+
+// gitState is the working tree state captured for activation.
+type gitState struct {
+	staged  []string // Paths with staged changes
+	changed []string // Paths with staged, unstaged, or untracked changes
+	message string   // Commit message being written, if any
+}
+
+// captureGitState reads the working tree state of the repository at
+// repoRoot. Outside a repository, or when git fails, it returns a zero
+// state.
+func captureGitState(repoRoot string) gitState {
+	out, err := gitOutput(repoRoot, "status", "--porcelain=v1", "-z", "--untracked-files=all")
+	if err != nil {
+		return gitState{}
+	}
+	state := parsePorcelain(out)
+	state.message = commitMessageInProgress(repoRoot)
+	return state
+}
+
+// parsePorcelain parses 'git status --porcelain=v1 -z' output. A rename
+// counts both its new and its original path as changed.
+func parsePorcelain(out []byte) gitState {
+	var state gitState
+	staged := make(map[string]bool)
+	changed := make(map[string]bool)
+	entries := bytes.Split(out, []byte{0})
+	for i := 0; i < len(entries); i++ {
+		entry := string(entries[i])
+		if len(entry) < 4 {
+			continue
+		}
+		x, path := entry[0], entry[3:]
+		changed[path] = true
+		if x != ' ' && x != '?' && x != '!' {
+			staged[path] = true
+		}
+		if x == 'R' || x == 'C' {
+			// The original path follows as its own entry
+			if i+1 < len(entries) {
+				i++
+				changed[string(entries[i])] = true
+			}
+		}
+	}
+	state.staged = boundedPaths(staged)
+	state.changed = boundedPaths(changed)
+	return state
+}
+
+// boundedPaths returns the sorted paths in set, at most maxGitPaths.
+func boundedPaths(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	paths := make([]string, 0, len(set))
+	for p := range set {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	if len(paths) > maxGitPaths {
+		paths = paths[:maxGitPaths]
+	}
+	return paths
+}
+
+// commitMessageInProgress returns the message of a commit being written:
+// .git/COMMIT_EDITMSG without comment lines or the verbose diff, unless it
+// is just the message of the last commit.
+func commitMessageInProgress(repoRoot string) string {
+	out, err := gitOutput(repoRoot, "rev-parse", "--git-path", "COMMIT_EDITMSG")
+	if err != nil {
+		return ""
+	}
+	path := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repoRoot, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	message := cleanCommitMessage(string(data))
+	if message == "" {
+		return ""
+	}
+	if last, err := gitOutput(repoRoot, "log", "-1", "--format=%B"); err == nil && cleanCommitMessage(string(last)) == message {
+		return ""
+	}
+	if len(message) > maxCommitMessageLen {
+		message = message[:maxCommitMessageLen]
+	}
+	return message
+}
+
+// cleanCommitMessage strips comment lines and everything below the
+// scissors line of a commit message template.
+func cleanCommitMessage(s string) string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if strings.HasPrefix(line, "# ") && strings.Contains(line, ">8") {
+			break
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t\r"))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// gitOutput runs git in dir and returns its standard output.
+func gitOutput(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	return cmd.Output()
+}
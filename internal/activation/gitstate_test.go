@@ -0,0 +1,109 @@
+package activation
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParsePorcelain(t *testing.T) {
+	out := []byte("M  staged.go\x00 M unstaged.go\x00MM both.go\x00?? new.txt\x00R  new/name.go\x00old/name.go\x00")
+	state := parsePorcelain(out)
+
+	wantStaged := []string{"both.go", "new/name.go", "staged.go"}
+	if !reflect.DeepEqual(state.staged, wantStaged) {
+		t.Errorf("staged = %v, want %v", state.staged, wantStaged)
+	}
+	wantChanged := []string{"both.go", "new.txt", "new/name.go", "old/name.go", "staged.go", "unstaged.go"}
+	if !reflect.DeepEqual(state.changed, wantChanged) {
+		t.Errorf("changed = %v, want %v", state.changed, wantChanged)
+	}
+
+	if empty := parsePorcelain(nil); empty.staged != nil || empty.changed != nil {
+		t.Errorf("parsePorcelain(nil) = %+v, want zero state", empty)
+	}
+}
+
+func TestCleanCommitMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain", "Add migration\n\nCreates the users table.\n", "Add migration\n\nCreates the users table."},
+		{"comments stripped", "Add migration\n# Please enter the commit message\n#\n", "Add migration"},
+		{"scissors", "Fix bug\n# ------------------------ >8 ------------------------\ndiff --git a/x b/x\n", "Fix bug"},
+		{"template only", "\n# Please enter the commit message\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cleanCommitMessage(tt.input); got != tt.want {
+				t.Errorf("cleanCommitMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContextBuilder_WithGitContext(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	git("config", "user.email", "dev@example.com")
+	git("config", "user.name", "Dev")
+	write("README.md", "readme\n")
+	git("add", "README.md")
+	git("commit", "-q", "-m", "Initial commit")
+
+	write("migrations/0001_users.sql", "create table users;\n")
+	git("add", "migrations/0001_users.sql")
+	write("README.md", "readme, edited\n")
+	write(".git/COMMIT_EDITMSG", "Add users migration\n# Please enter the commit message\n")
+
+	ctx := NewContextBuilder().WithRepoRoot(dir).WithGitContext(true).Build()
+	if want := []string{"migrations/0001_users.sql"}; !reflect.DeepEqual(ctx.StagedFiles, want) {
+		t.Errorf("StagedFiles = %v, want %v", ctx.StagedFiles, want)
+	}
+	if want := []string{"README.md", "migrations/0001_users.sql"}; !reflect.DeepEqual(ctx.ChangedPaths, want) {
+		t.Errorf("ChangedPaths = %v, want %v", ctx.ChangedPaths, want)
+	}
+	if ctx.CommitMessage != "Add users migration" {
+		t.Errorf("CommitMessage = %q, want %q", ctx.CommitMessage, "Add users migration")
+	}
+	if matched, hasValue := ctx.MatchField("changed_paths", "migrations/**"); !matched || !hasValue {
+		t.Errorf("changed_paths migrations/** = (%v, %v), want confirmed", matched, hasValue)
+	}
+
+	// The message left behind by the last commit is not in progress
+	write(".git/COMMIT_EDITMSG", "Initial commit\n")
+	if ctx := NewContextBuilder().WithRepoRoot(dir).WithGitContext(true).Build(); ctx.CommitMessage != "" {
+		t.Errorf("CommitMessage = %q, want empty after commit", ctx.CommitMessage)
+	}
+
+	// Capture is off by default
+	if ctx := NewContextBuilder().WithRepoRoot(dir).Build(); ctx.StagedFiles != nil || ctx.ChangedPaths != nil {
+		t.Errorf("default Build() captured git state: %v, %v", ctx.StagedFiles, ctx.ChangedPaths)
+	}
+}
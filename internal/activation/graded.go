@@ -0,0 +1,116 @@
+package activation
+
+import (
+	"fmt"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// MatchMode selects how contradicted when-conditions are treated.
+type MatchMode string
+
+const (
+	// MatchStrict excludes a behavior when any condition is contradicted.
+	MatchStrict MatchMode = "strict"
+
+	// MatchGraded keeps a behavior with contradicted conditions when the
+	// weighted share of confirmed conditions reaches the minimum score.
+	MatchGraded MatchMode = "graded"
+)
+
+// ParseMatchMode parses a match mode name. An empty name is strict.
+func ParseMatchMode(s string) (MatchMode, error) {
+	switch MatchMode(s) {
+	case "", MatchStrict:
+		return MatchStrict, nil
+	case MatchGraded:
+		return MatchGraded, nil
+	default:
+		return "", fmt.Errorf("invalid match mode %q (valid: strict, graded)", s)
+	}
+}
+
+// MatchOptions configures how an Evaluator scores when-conditions.
+type MatchOptions struct {
+	// Mode is strict (the default) or graded.
+	Mode MatchMode
+
+	// MinScore is the minimum weighted score for a behavior with contradicted
+	// conditions to activate in graded mode. Zero uses
+	// constants.DefaultGradedMatchMinScore.
+	MinScore float64
+
+	// FieldWeights overrides DefaultFieldWeights for individual keys.
+	FieldWeights map[string]float64
+}
+
+// DefaultFieldWeights is the importance of each context field in graded
+// scoring. A confirmed file path says more about relevance than a confirmed
+// branch. Keys not listed weigh 1.
+var DefaultFieldWeights = map[string]float64{
+	"file_path":        3,
+	"file.path":        3,
+	"language":         2,
+	"file_language":    2,
+	"file.language":    2,
+	"ext":              2,
+	"file_ext":         2,
+	"file.ext":         2,
+	"task":             2,
+	"project_type":     1.5,
+	"repo":             1.5,
+	"branch":           1,
+	"environment":      1,
+	"env":              1,
+	"agent":            1,
+	"user":             1,
+	models.WhenWeekday: 0.5,
+	models.WhenDate:    0.5,
+	models.WhenAfter:   0.5,
+	models.WhenBefore:  0.5,
+}
+
+// weight returns the importance of a condition key.
+func (o MatchOptions) weight(key string) float64 {
+	if w, ok := o.FieldWeights[key]; ok && w > 0 {
+		return w
+	}
+	if w, ok := DefaultFieldWeights[key]; ok {
+		return w
+	}
+	return 1
+}
+
+// minScore returns the graded admission threshold.
+func (o MatchOptions) minScore() float64 {
+	if o.MinScore > 0 {
+		return o.MinScore
+	}
+	return constants.DefaultGradedMatchMinScore
+}
+
+// weightedScore is the weighted share of conditions that were confirmed.
+func (o MatchOptions) weightedScore(when map[string]interface{}, confirmed map[string]interface{}) float64 {
+	var total, matched float64
+	for key := range when {
+		w := o.weight(key)
+		total += w
+		if _, ok := confirmed[key]; ok {
+			matched += w
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return matched / total
+}
+
+// WithMatchOptions sets how the evaluator scores when-conditions.
+func (e *Evaluator) WithMatchOptions(opts MatchOptions) *Evaluator {
+	if opts.Mode == "" {
+		opts.Mode = MatchStrict
+	}
+	e.opts = opts
+	return e
+}
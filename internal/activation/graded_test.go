@@ -0,0 +1,110 @@
+package activation
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestParseMatchMode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    MatchMode
+		wantErr bool
+	}{
+		{"", MatchStrict, false},
+		{"strict", MatchStrict, false},
+		{"graded", MatchGraded, false},
+		{"fuzzy", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMatchMode(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseMatchMode(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestEvaluator_GradedMatching(t *testing.T) {
+	// language (2) and task (2) confirmed, branch (1) contradicted: 4/5
+	b := models.Behavior{ID: "b1", When: map[string]interface{}{
+		"language": "go", "task": "testing", "branch": "main",
+	}}
+	ctx := models.ContextSnapshot{FileLanguage: "go", Task: "testing", Branch: "feature"}
+
+	tests := []struct {
+		name       string
+		opts       MatchOptions
+		wantActive bool
+		wantScore  float64
+	}{
+		{"strict excludes contradicted", MatchOptions{Mode: MatchStrict}, false, 0},
+		{"graded keeps high score", MatchOptions{Mode: MatchGraded}, true, 0.8},
+		{"graded min score not reached", MatchOptions{Mode: MatchGraded, MinScore: 0.9}, false, 0.8},
+		{"field weight override", MatchOptions{Mode: MatchGraded, FieldWeights: map[string]float64{"branch": 6}}, false, 0.4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewEvaluator().WithMatchOptions(tt.opts)
+			results := evaluator.Evaluate(ctx, []models.Behavior{b})
+			if got := len(results) == 1; got != tt.wantActive {
+				t.Fatalf("active = %v, want %v", got, tt.wantActive)
+			}
+			if tt.wantActive {
+				if math.Abs(results[0].MatchScore-tt.wantScore) > 1e-9 {
+					t.Errorf("MatchScore = %v, want %v", results[0].MatchScore, tt.wantScore)
+				}
+				if results[0].Specificity != 2 {
+					t.Errorf("Specificity = %d, want 2 confirmed conditions", results[0].Specificity)
+				}
+			}
+			if exp := evaluator.WhyActive(ctx, b); math.Abs(exp.Score-tt.wantScore) > 1e-9 {
+				t.Errorf("explanation Score = %v, want %v", exp.Score, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestEvaluator_WhyActiveGradedBreakdown(t *testing.T) {
+	evaluator := NewEvaluator().WithMatchOptions(MatchOptions{Mode: MatchGraded})
+	b := models.Behavior{ID: "b1", When: map[string]interface{}{"language": "go", "branch": "main"}}
+	ctx := models.ContextSnapshot{FileLanguage: "go", Branch: "feature"}
+
+	exp := evaluator.WhyActive(ctx, b)
+	if !exp.IsActive || exp.Mode != MatchGraded {
+		t.Fatalf("explanation = %+v, want active in graded mode", exp)
+	}
+	if !strings.Contains(exp.Reason, "Partially matched (score 0.67, contradicted on: branch)") {
+		t.Errorf("Reason = %q", exp.Reason)
+	}
+	want := []struct {
+		field  string
+		status string
+		weight float64
+	}{{"branch", "contradicted", 1}, {"language", "confirmed", 2}}
+	if len(exp.Conditions) != len(want) {
+		t.Fatalf("Conditions = %+v", exp.Conditions)
+	}
+	for i, w := range want {
+		c := exp.Conditions[i]
+		if c.Field != w.field || c.Status != w.status || c.Weight != w.weight {
+			t.Errorf("Conditions[%d] = %+v, want %s %s weight %v", i, c, w.field, w.status, w.weight)
+		}
+	}
+}
+
+func TestEvaluator_EvaluateIndexGraded(t *testing.T) {
+	behaviors := []models.Behavior{
+		{ID: "go-main", When: map[string]interface{}{"language": "go", "branch": "main"}},
+	}
+	evaluator := NewEvaluator().WithMatchOptions(MatchOptions{Mode: MatchGraded, MinScore: 0.3})
+
+	// The indexed language condition is contradicted, but branch (1/3) still
+	// reaches the minimum score
+	ctx := models.ContextSnapshot{FileLanguage: "python", Branch: "main"}
+	if got := evaluator.EvaluateIndex(ctx, NewIndex(behaviors)); len(got) != 1 {
+		t.Errorf("EvaluateIndex() = %d results, want 1", len(got))
+	}
+}
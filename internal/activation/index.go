@@ -0,0 +1,135 @@
+package activation
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// Index is an inverted index from when-condition values to the behaviors
+// that require them. Evaluating a context against an index only checks
+// behaviors whose indexed condition agrees with the context, so evaluation
+// scales with the number of plausible matches rather than the store size.
+//
+// An index is a snapshot: build a new one when the behaviors change.
+type Index struct {
+	behaviors []models.Behavior
+
+	// unindexed holds positions of behaviors with no exact-valued condition;
+	// they are always evaluated.
+	unindexed []int
+
+	// byKey indexes each behavior under one exact-valued condition.
+	byKey map[string]*keyIndex
+}
+
+// keyIndex lists the behaviors indexed under one when-condition key.
+type keyIndex struct {
+	all     []int
+	byValue map[string][]int
+}
+
+// NewIndex builds an index over behaviors. Each behavior is indexed under its
+// most selective exact-valued condition (a plain string or a list of
+// strings); conditions with globs, temporal keys, or non-string values are
+// left to full evaluation.
+func NewIndex(behaviors []models.Behavior) *Index {
+	idx := &Index{
+		behaviors: behaviors,
+		byKey:     make(map[string]*keyIndex),
+	}
+	for i, b := range behaviors {
+		key, values := indexCondition(b.When)
+		if key == "" {
+			idx.unindexed = append(idx.unindexed, i)
+			continue
+		}
+		ki, ok := idx.byKey[key]
+		if !ok {
+			ki = &keyIndex{byValue: make(map[string][]int)}
+			idx.byKey[key] = ki
+		}
+		ki.all = append(ki.all, i)
+		for _, v := range values {
+			ki.byValue[v] = append(ki.byValue[v], i)
+		}
+	}
+	return idx
+}
+
+// Len returns the number of indexed behaviors.
+func (idx *Index) Len() int {
+	return len(idx.behaviors)
+}
+
+// candidates returns the positions, in input order, of behaviors that are not
+// ruled out by their indexed condition. A behavior is ruled out when the
+// context has a string value for its indexed key that the condition does not
+// accept; any other context value is left to full evaluation.
+func (idx *Index) candidates(ctx models.ContextSnapshot) []int {
+	out := append([]int(nil), idx.unindexed...)
+	for key, ki := range idx.byKey {
+		if actual, ok := ctx.GetField(key).(string); ok && actual != "" {
+			out = append(out, ki.byValue[actual]...)
+		} else {
+			out = append(out, ki.all...)
+		}
+	}
+	sort.Ints(out)
+
+	// A list condition with repeated values indexes a behavior twice
+	unique := out[:0]
+	for i, pos := range out {
+		if i == 0 || pos != out[i-1] {
+			unique = append(unique, pos)
+		}
+	}
+	return unique
+}
+
+// indexCondition picks the condition a behavior is indexed under: the
+// exact-valued one accepting the fewest values, ties broken by key. It
+// returns an empty key when no condition can be indexed.
+func indexCondition(when map[string]interface{}) (string, []string) {
+	var bestKey string
+	var bestValues []string
+	for key, required := range when {
+		if models.IsTemporalKey(key) || key == models.WhenAny {
+			continue
+		}
+		values, ok := exactValues(required)
+		if !ok {
+			continue
+		}
+		if bestKey == "" || len(values) < len(bestValues) ||
+			(len(values) == len(bestValues) && key < bestKey) {
+			bestKey, bestValues = key, values
+		}
+	}
+	return bestKey, bestValues
+}
+
+// exactValues returns the string values a condition accepts when it accepts
+// nothing else. A list's non-string options never match, so they are dropped.
+func exactValues(required interface{}) ([]string, bool) {
+	switch req := required.(type) {
+	case string:
+		if strings.Contains(req, "*") {
+			return nil, false
+		}
+		return []string{req}, true
+	case []string:
+		return req, true
+	case []interface{}:
+		values := make([]string, 0, len(req))
+		for _, option := range req {
+			if s, ok := option.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values, true
+	default:
+		return nil, false
+	}
+}
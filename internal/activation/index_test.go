@@ -0,0 +1,96 @@
+package activation
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func indexTestBehaviors() []models.Behavior {
+	return []models.Behavior{
+		{ID: "always"},
+		{ID: "go", When: map[string]interface{}{"language": "go"}},
+		{ID: "python", When: map[string]interface{}{"language": "python"}},
+		{ID: "go-or-rust", When: map[string]interface{}{"language": []interface{}{"go", "rust"}}},
+		{ID: "go-testing", When: map[string]interface{}{"language": "go", "task": "testing"}},
+		{ID: "glob", When: map[string]interface{}{"file_path": "*.go"}},
+		{ID: "weekday", When: map[string]interface{}{models.WhenWeekday: "monday"}},
+		{ID: "custom", When: map[string]interface{}{"team": "platform"}},
+		{ID: "empty-list", When: map[string]interface{}{"language": []string{}}},
+		{ID: "not-go", When: map[string]interface{}{"language": map[string]interface{}{"not": "go"}}},
+		{ID: "go-or-testing", When: map[string]interface{}{models.WhenAny: []interface{}{
+			map[string]interface{}{"language": "go"},
+			map[string]interface{}{"task": "testing"},
+		}}},
+	}
+}
+
+func TestEvaluator_EvaluateIndex(t *testing.T) {
+	behaviors := indexTestBehaviors()
+	idx := NewIndex(behaviors)
+	if idx.Len() != len(behaviors) {
+		t.Fatalf("Len() = %d, want %d", idx.Len(), len(behaviors))
+	}
+	monday := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+
+	contexts := []struct {
+		name string
+		ctx  models.ContextSnapshot
+	}{
+		{"empty", models.ContextSnapshot{}},
+		{"go", models.ContextSnapshot{FileLanguage: "go", FilePath: "main.go"}},
+		{"go testing", models.ContextSnapshot{FileLanguage: "go", Task: "testing"}},
+		{"python", models.ContextSnapshot{FileLanguage: "python", Timestamp: monday}},
+		{"rust", models.ContextSnapshot{FileLanguage: "rust"}},
+		{"custom", models.ContextSnapshot{Custom: map[string]interface{}{"team": "platform"}}},
+		{"custom non-string", models.ContextSnapshot{Custom: map[string]interface{}{"team": 7}}},
+	}
+
+	evaluator := NewEvaluator()
+	for _, tt := range contexts {
+		t.Run(tt.name, func(t *testing.T) {
+			want := resultIDs(evaluator.Evaluate(tt.ctx, behaviors))
+			got := resultIDs(evaluator.EvaluateIndex(tt.ctx, idx))
+			if len(got) != len(want) {
+				t.Fatalf("EvaluateIndex() = %v, want %v", got, want)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Fatalf("EvaluateIndex() = %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestIndex_Candidates(t *testing.T) {
+	behaviors := indexTestBehaviors()
+	idx := NewIndex(behaviors)
+
+	var got []string
+	for _, pos := range idx.candidates(models.ContextSnapshot{FileLanguage: "python"}) {
+		got = append(got, behaviors[pos].ID)
+	}
+	// Go-only, rust and empty-list behaviors are ruled out without
+	// evaluation; operator and "any" conditions are never indexed
+	want := []string{"always", "python", "glob", "weekday", "custom", "not-go", "go-or-testing"}
+	if len(got) != len(want) {
+		t.Fatalf("candidates = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("candidates = %v, want %v", got, want)
+		}
+	}
+}
+
+func resultIDs(results []ActivationResult) []string {
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.Behavior.ID
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,242 @@
+package activation
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// overrideLink records which behavior overrides another, and why. via lists
+// the non-matching behaviors the override passes through, from by down.
+type overrideLink struct {
+	by     string
+	reason string
+	via    []string
+}
+
+const (
+	reasonSuperseded  = "Superseded by more specific behavior"
+	reasonGeneralized = "Generalized by matching specialization"
+)
+
+// resolveOverrides works out which matches are overridden, following
+// overrides transitively: when A overrides B and B overrides C, both B and C
+// yield, and C's chain is A -> B -> C. Overrides also pass through behaviors
+// that did not match but that r.overrides knows: C yields to A even if B did
+// not match.
+//
+// Behaviors that override each other in a cycle cannot all yield, so the
+// highest-ranked member (by pickWinner, ties going to the smallest ID) keeps
+// its place and the rest of the cycle is overridden by it. When a behavior has
+// several overriders, the highest-ranked one is reported. The result does not
+// depend on match order.
+func (r *Resolver) resolveOverrides(matches []ActivationResult) map[string]overrideLink {
+	byID := make(map[string]ActivationResult, len(matches))
+	for _, m := range matches {
+		byID[m.Behavior.ID] = m
+	}
+
+	// graph maps each behavior to the matching behaviors it overrides
+	graph := make(map[string][]string, len(matches))
+	via := make(map[[2]string][]string)
+	for _, m := range matches {
+		for _, path := range r.overridePaths(m.Behavior, byID) {
+			graph[m.Behavior.ID] = append(graph[m.Behavior.ID], path.target)
+			via[[2]string{m.Behavior.ID, path.target}] = path.via
+		}
+	}
+
+	links := make(map[string]overrideLink)
+	component := make(map[string]int)
+	for i, scc := range overrideComponents(matches, graph) {
+		for _, id := range scc {
+			component[id] = i
+		}
+		if len(scc) < 2 {
+			continue
+		}
+		winner := r.rankedFirst(scc, byID)
+		reason := "Override cycle among " + strings.Join(scc, ", ") + "; kept " + winner
+		for _, id := range scc {
+			if id != winner {
+				links[id] = overrideLink{by: winner, reason: reason}
+			}
+		}
+	}
+
+	// Overrides between different components form a DAG
+	overriders := make(map[string][]string)
+	for src, targets := range graph {
+		for _, target := range targets {
+			if component[src] != component[target] {
+				overriders[target] = append(overriders[target], src)
+			}
+		}
+	}
+	for target, srcs := range overriders {
+		if _, inCycle := links[target]; inCycle {
+			continue
+		}
+		by := r.rankedFirst(srcs, byID)
+		links[target] = overrideLink{by: by, reason: reasonSuperseded, via: via[[2]string{by, target}]}
+	}
+
+	// Prefer the most specific behavior: a generalized parent yields to a
+	// matching child that specializes it
+	childIDs := make([]string, 0, len(matches))
+	for _, m := range matches {
+		childIDs = append(childIDs, m.Behavior.ID)
+	}
+	sort.Strings(childIDs)
+	for _, childID := range childIDs {
+		for _, parentID := range byID[childID].Behavior.Specializes {
+			if _, exists := byID[parentID]; !exists || parentID == childID {
+				continue
+			}
+			if _, already := links[parentID]; !already {
+				links[parentID] = overrideLink{by: childID, reason: reasonGeneralized}
+			}
+		}
+	}
+
+	return links
+}
+
+// overridePath is a matching behavior reached by following override edges
+// through the non-matching behaviors in via.
+type overridePath struct {
+	target string
+	via    []string
+}
+
+// overridePaths returns the matching behaviors b overrides, directly or
+// through behaviors that did not match, each by its shortest path.
+func (r *Resolver) overridePaths(b models.Behavior, byID map[string]ActivationResult) []overridePath {
+	type step struct {
+		id  string
+		via []string
+	}
+	var paths []overridePath
+	seen := map[string]bool{b.ID: true}
+	queue := []step{{id: b.ID}}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		var targets []string
+		if m, matched := byID[cur.id]; matched {
+			targets = m.Behavior.Overrides
+		} else if r.overrides != nil {
+			targets = r.overrides(cur.id)
+		}
+		for _, target := range targets {
+			if seen[target] {
+				continue
+			}
+			seen[target] = true
+			if _, matched := byID[target]; matched {
+				paths = append(paths, overridePath{target: target, via: cur.via})
+				continue
+			}
+			if r.overrides != nil {
+				queue = append(queue, step{id: target, via: append(append([]string(nil), cur.via...), target)})
+			}
+		}
+	}
+	return paths
+}
+
+// overrideChain returns the chain of behaviors ending at id, starting from
+// the behavior at the top that is not itself overridden.
+func overrideChain(id string, links map[string]overrideLink) []string {
+	chain := []string{id}
+	seen := map[string]bool{id: true}
+	for {
+		link, ok := links[chain[len(chain)-1]]
+		if !ok || seen[link.by] {
+			break
+		}
+		seen[link.by] = true
+		for i := len(link.via) - 1; i >= 0; i-- {
+			chain = append(chain, link.via[i])
+		}
+		chain = append(chain, link.by)
+	}
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
+
+// rankedFirst returns the highest-ranked of the given IDs by pickWinner.
+// Candidates are compared in ID order so ties go to the smallest ID.
+func (r *Resolver) rankedFirst(ids []string, byID map[string]ActivationResult) string {
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	best := sorted[0]
+	for _, id := range sorted[1:] {
+		best = r.pickWinner(byID[best], byID[id])
+	}
+	return best
+}
+
+// overrideComponents returns the strongly connected components of the
+// override graph, each sorted by ID, using Tarjan's algorithm over sorted
+// IDs so the result is deterministic.
+func overrideComponents(matches []ActivationResult, graph map[string][]string) [][]string {
+	ids := make([]string, 0, len(matches))
+	for _, m := range matches {
+		ids = append(ids, m.Behavior.ID)
+	}
+	sort.Strings(ids)
+
+	index := make(map[string]int, len(ids))
+	lowlink := make(map[string]int, len(ids))
+	onStack := make(map[string]bool, len(ids))
+	var stack []string
+	var components [][]string
+	next := 0
+
+	var visit func(id string)
+	visit = func(id string) {
+		index[id] = next
+		lowlink[id] = next
+		next++
+		stack = append(stack, id)
+		onStack[id] = true
+
+		targets := append([]string(nil), graph[id]...)
+		sort.Strings(targets)
+		for _, target := range targets {
+			if _, visited := index[target]; !visited {
+				visit(target)
+				lowlink[id] = min(lowlink[id], lowlink[target])
+			} else if onStack[target] {
+				lowlink[id] = min(lowlink[id], index[target])
+			}
+		}
+
+		if lowlink[id] == index[id] {
+			var scc []string
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				scc = append(scc, top)
+				if top == id {
+					break
+				}
+			}
+			sort.Strings(scc)
+			components = append(components, scc)
+		}
+	}
+
+	for _, id := range ids {
+		if _, visited := index[id]; !visited {
+			visit(id)
+		}
+	}
+	return components
+}
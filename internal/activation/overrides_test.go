@@ -0,0 +1,156 @@
+package activation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestResolver_OverrideChains(t *testing.T) {
+	resolver := NewResolver()
+
+	tests := []struct {
+		name       string
+		matches    []ActivationResult
+		wantActive []string
+		wantChains map[string]string // overridden ID -> chain
+		wantReason map[string]string // overridden ID -> reason substring
+	}{
+		{
+			name: "two-level chain",
+			matches: []ActivationResult{
+				{Behavior: models.Behavior{ID: "c"}},
+				{Behavior: models.Behavior{ID: "b", Overrides: []string{"c"}}},
+				{Behavior: models.Behavior{ID: "a", Overrides: []string{"b"}}},
+			},
+			wantActive: []string{"a"},
+			wantChains: map[string]string{"b": "a -> b", "c": "a -> b -> c"},
+		},
+		{
+			name: "chain ignores match order",
+			matches: []ActivationResult{
+				{Behavior: models.Behavior{ID: "a", Overrides: []string{"b"}}},
+				{Behavior: models.Behavior{ID: "b", Overrides: []string{"c"}}},
+				{Behavior: models.Behavior{ID: "c"}},
+			},
+			wantActive: []string{"a"},
+			wantChains: map[string]string{"b": "a -> b", "c": "a -> b -> c"},
+		},
+		{
+			name: "two-way cycle keeps higher priority",
+			matches: []ActivationResult{
+				{Behavior: models.Behavior{ID: "a", Overrides: []string{"b"}}},
+				{Behavior: models.Behavior{ID: "b", Overrides: []string{"a"}, Priority: 5}},
+			},
+			wantActive: []string{"b"},
+			wantChains: map[string]string{"a": "b -> a"},
+			wantReason: map[string]string{"a": "Override cycle among a, b; kept b"},
+		},
+		{
+			name: "three-way cycle tie goes to smallest ID",
+			matches: []ActivationResult{
+				{Behavior: models.Behavior{ID: "z", Overrides: []string{"y"}}},
+				{Behavior: models.Behavior{ID: "y", Overrides: []string{"x"}}},
+				{Behavior: models.Behavior{ID: "x", Overrides: []string{"z"}}},
+			},
+			wantActive: []string{"x"},
+			wantChains: map[string]string{"y": "x -> y", "z": "x -> z"},
+			wantReason: map[string]string{"z": "kept x"},
+		},
+		{
+			name: "cycle below a winner",
+			matches: []ActivationResult{
+				{Behavior: models.Behavior{ID: "top", Overrides: []string{"a"}}},
+				{Behavior: models.Behavior{ID: "a", Overrides: []string{"b"}}},
+				{Behavior: models.Behavior{ID: "b", Overrides: []string{"a"}}},
+			},
+			wantActive: []string{"top"},
+			wantChains: map[string]string{"a": "top -> a", "b": "top -> a -> b"},
+		},
+		{
+			name: "highest-ranked overrider is reported",
+			matches: []ActivationResult{
+				{Behavior: models.Behavior{ID: "base"}},
+				{Behavior: models.Behavior{ID: "p", Overrides: []string{"base"}}, Specificity: 1},
+				{Behavior: models.Behavior{ID: "q", Overrides: []string{"base"}}, Specificity: 3},
+			},
+			wantActive: []string{"p", "q"},
+			wantChains: map[string]string{"base": "q -> base"},
+		},
+		{
+			name: "self-override is ignored",
+			matches: []ActivationResult{
+				{Behavior: models.Behavior{ID: "a", Overrides: []string{"a"}}},
+			},
+			wantActive: []string{"a"},
+			wantChains: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := resolver.Resolve(tt.matches)
+
+			var active []string
+			for _, b := range result.Active {
+				active = append(active, b.ID)
+			}
+			if strings.Join(active, ",") != strings.Join(tt.wantActive, ",") {
+				t.Errorf("Active = %v, want %v", active, tt.wantActive)
+			}
+
+			if len(result.Overridden) != len(tt.wantChains) {
+				t.Fatalf("Overridden = %+v, want %d entries", result.Overridden, len(tt.wantChains))
+			}
+			for _, o := range result.Overridden {
+				want, ok := tt.wantChains[o.Behavior.ID]
+				if !ok {
+					t.Errorf("unexpected overridden behavior %s", o.Behavior.ID)
+					continue
+				}
+				if got := strings.Join(o.Chain, " -> "); got != want {
+					t.Errorf("%s chain = %q, want %q", o.Behavior.ID, got, want)
+				}
+				if o.OverrideBy != o.Chain[len(o.Chain)-2] {
+					t.Errorf("%s OverrideBy = %s, want direct overrider in %v", o.Behavior.ID, o.OverrideBy, o.Chain)
+				}
+				if sub, ok := tt.wantReason[o.Behavior.ID]; ok && !strings.Contains(o.Reason, sub) {
+					t.Errorf("%s reason = %q, want it to contain %q", o.Behavior.ID, o.Reason, sub)
+				}
+			}
+		})
+	}
+
+	t.Run("explain reports the chain", func(t *testing.T) {
+		result := resolver.Resolve(tests[0].matches)
+		got, held := result.Explain("c")
+		if !held || !strings.Contains(got, "via a -> b -> c") {
+			t.Errorf("Explain(c) = %q, %v; want chain a -> b -> c", got, held)
+		}
+	})
+}
+
+func TestResolver_OverridesThroughNonMatching(t *testing.T) {
+	a := models.Behavior{ID: "a", Overrides: []string{"b"}}
+	b := models.Behavior{ID: "b", Overrides: []string{"c"}}
+	c := models.Behavior{ID: "c"}
+	matches := []ActivationResult{{Behavior: a}, {Behavior: c}}
+
+	// Without the non-matching behaviors, a and c look unrelated
+	if result := NewResolver().Resolve(matches); len(result.Active) != 2 {
+		t.Errorf("Active without behaviors = %+v, want a and c", result.Active)
+	}
+
+	result := NewResolver().WithBehaviors([]models.Behavior{a, b, c}).Resolve(matches)
+	if len(result.Active) != 1 || result.Active[0].ID != "a" {
+		t.Errorf("Active = %+v, want only a", result.Active)
+	}
+	if len(result.Overridden) != 1 {
+		t.Fatalf("Overridden = %+v, want c", result.Overridden)
+	}
+	o := result.Overridden[0]
+	if o.Behavior.ID != "c" || o.OverrideBy != "a" || strings.Join(o.Chain, " -> ") != "a -> b -> c" {
+		t.Errorf("Overridden[0] = %s by %s via %v, want c by a via a -> b -> c", o.Behavior.ID, o.OverrideBy, o.Chain)
+	}
+}
@@ -0,0 +1,162 @@
+package activation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// BlockedInfo describes a behavior held back because a behavior it requires
+// is not active.
+type BlockedInfo struct {
+	Behavior   models.Behavior `json:"behavior"`
+	RequiredID string          `json:"required_id"`
+	Reason     string          `json:"reason"`
+}
+
+// requirementState explains why a required behavior is unavailable.
+type requirementState string
+
+const (
+	stateNotMatched requirementState = "is not active in this context"
+	stateOverridden requirementState = "was overridden"
+	stateExcluded   requirementState = "lost a conflict"
+	stateBlocked    requirementState = "is itself blocked"
+)
+
+// blockUnmetRequirements returns the candidates that cannot activate because
+// a behavior they require is unavailable, keyed by ID. available holds the IDs
+// that may satisfy a requirement; unavailable explains the rest (IDs missing
+// from both are treated as not matching the context).
+//
+// Behaviors on a requires cycle are always blocked, since none of them can be
+// the first to activate. Blocking cascades: a behavior requiring a blocked
+// behavior is blocked too.
+func blockUnmetRequirements(candidates []models.Behavior, available map[string]bool, unavailable map[string]requirementState) map[string]BlockedInfo {
+	blocked := make(map[string]BlockedInfo)
+
+	byID := make(map[string]models.Behavior, len(candidates))
+	for _, b := range candidates {
+		byID[b.ID] = b
+	}
+
+	for _, cycle := range requiresCycles(candidates) {
+		path := strings.Join(append(cycle, cycle[0]), " -> ")
+		for i, id := range cycle {
+			blocked[id] = BlockedInfo{
+				Behavior:   byID[id],
+				RequiredID: cycle[(i+1)%len(cycle)],
+				Reason:     "Requirement cycle: " + path,
+			}
+		}
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, b := range candidates {
+			if _, done := blocked[b.ID]; done {
+				continue
+			}
+			for _, req := range b.Requires {
+				state, missing := requirementStatus(req, available, unavailable, blocked)
+				if !missing {
+					continue
+				}
+				blocked[b.ID] = BlockedInfo{
+					Behavior:   b,
+					RequiredID: req,
+					Reason:     fmt.Sprintf("Requires %s, which %s", req, state),
+				}
+				changed = true
+				break
+			}
+		}
+	}
+
+	return blocked
+}
+
+// requirementStatus reports whether req is unavailable, and why.
+func requirementStatus(req string, available map[string]bool, unavailable map[string]requirementState, blocked map[string]BlockedInfo) (requirementState, bool) {
+	if _, isBlocked := blocked[req]; isBlocked {
+		return stateBlocked, true
+	}
+	if available[req] {
+		return "", false
+	}
+	if state, ok := unavailable[req]; ok {
+		return state, true
+	}
+	return stateNotMatched, true
+}
+
+// requiresCycles returns each requires cycle among behaviors as the IDs on
+// the cycle, starting from the smallest ID. Requirements outside behaviors
+// are ignored. The result is deterministic.
+func requiresCycles(behaviors []models.Behavior) [][]string {
+	graph := make(map[string][]string, len(behaviors))
+	ids := make([]string, 0, len(behaviors))
+	for _, b := range behaviors {
+		ids = append(ids, b.ID)
+		graph[b.ID] = b.Requires
+	}
+	sort.Strings(ids)
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	color := make(map[string]int, len(ids))
+	var stack []string
+	seen := make(map[string]bool)
+	var cycles [][]string
+
+	var visit func(id string)
+	visit = func(id string) {
+		color[id] = visiting
+		stack = append(stack, id)
+		for _, next := range graph[id] {
+			if _, known := graph[next]; !known {
+				continue
+			}
+			switch color[next] {
+			case unvisited:
+				visit(next)
+			case visiting:
+				start := len(stack) - 1
+				for stack[start] != next {
+					start--
+				}
+				cycle := rotateToMin(append([]string(nil), stack[start:]...))
+				key := strings.Join(cycle, "\x00")
+				if !seen[key] {
+					seen[key] = true
+					cycles = append(cycles, cycle)
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		color[id] = visited
+	}
+
+	for _, id := range ids {
+		if color[id] == unvisited {
+			visit(id)
+		}
+	}
+	return cycles
+}
+
+// rotateToMin rotates a cycle so it starts at its smallest ID.
+func rotateToMin(cycle []string) []string {
+	minIdx := 0
+	for i, id := range cycle {
+		if id < cycle[minIdx] {
+			minIdx = i
+		}
+	}
+	return append(cycle[minIdx:], cycle[:minIdx]...)
+}
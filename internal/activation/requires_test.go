@@ -0,0 +1,129 @@
+package activation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestResolver_Requires(t *testing.T) {
+	resolver := NewResolver()
+
+	tests := []struct {
+		name        string
+		matches     []ActivationResult
+		wantActive  []string
+		wantBlocked map[string]string // blocked ID -> reason substring
+	}{
+		{
+			name: "requirement active",
+			matches: []ActivationResult{
+				{Behavior: models.Behavior{ID: "a", Requires: []string{"b"}}},
+				{Behavior: models.Behavior{ID: "b"}},
+			},
+			wantActive:  []string{"a", "b"},
+			wantBlocked: map[string]string{},
+		},
+		{
+			name: "requirement not matched",
+			matches: []ActivationResult{
+				{Behavior: models.Behavior{ID: "a", Requires: []string{"b"}}},
+			},
+			wantActive:  []string{},
+			wantBlocked: map[string]string{"a": "Requires b, which is not active in this context"},
+		},
+		{
+			name: "blocking cascades",
+			matches: []ActivationResult{
+				{Behavior: models.Behavior{ID: "a", Requires: []string{"b"}}},
+				{Behavior: models.Behavior{ID: "b", Requires: []string{"c"}}},
+				{Behavior: models.Behavior{ID: "d"}},
+			},
+			wantActive: []string{"d"},
+			wantBlocked: map[string]string{
+				"a": "Requires b, which is itself blocked",
+				"b": "Requires c, which is not active in this context",
+			},
+		},
+		{
+			name: "requirement overridden",
+			matches: []ActivationResult{
+				{Behavior: models.Behavior{ID: "a", Requires: []string{"b"}}},
+				{Behavior: models.Behavior{ID: "b"}},
+				{Behavior: models.Behavior{ID: "c", Overrides: []string{"b"}}},
+			},
+			wantActive:  []string{"c"},
+			wantBlocked: map[string]string{"a": "Requires b, which was overridden"},
+		},
+		{
+			name: "requirement lost conflict",
+			matches: []ActivationResult{
+				{Behavior: models.Behavior{ID: "a", Requires: []string{"b"}}},
+				{Behavior: models.Behavior{ID: "b", Conflicts: []string{"c"}}, Specificity: 1},
+				{Behavior: models.Behavior{ID: "c"}, Specificity: 2},
+			},
+			wantActive:  []string{"c"},
+			wantBlocked: map[string]string{"a": "Requires b, which lost a conflict"},
+		},
+		{
+			name: "blocked behavior does not override",
+			matches: []ActivationResult{
+				{Behavior: models.Behavior{ID: "a", Requires: []string{"missing"}, Overrides: []string{"b"}}},
+				{Behavior: models.Behavior{ID: "b"}},
+			},
+			wantActive:  []string{"b"},
+			wantBlocked: map[string]string{"a": "Requires missing"},
+		},
+		{
+			name: "cycle blocks every member",
+			matches: []ActivationResult{
+				{Behavior: models.Behavior{ID: "b", Requires: []string{"c"}}},
+				{Behavior: models.Behavior{ID: "c", Requires: []string{"b"}}},
+				{Behavior: models.Behavior{ID: "a", Requires: []string{"b"}}},
+			},
+			wantActive: []string{},
+			wantBlocked: map[string]string{
+				"b": "Requirement cycle: b -> c -> b",
+				"c": "Requirement cycle: b -> c -> b",
+				"a": "Requires b, which is itself blocked",
+			},
+		},
+		{
+			name: "self requirement is a cycle",
+			matches: []ActivationResult{
+				{Behavior: models.Behavior{ID: "a", Requires: []string{"a"}}},
+			},
+			wantActive:  []string{},
+			wantBlocked: map[string]string{"a": "Requirement cycle: a -> a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := resolver.Resolve(tt.matches)
+
+			var active []string
+			for _, b := range result.Active {
+				active = append(active, b.ID)
+			}
+			if strings.Join(active, ",") != strings.Join(tt.wantActive, ",") {
+				t.Errorf("Active = %v, want %v", active, tt.wantActive)
+			}
+
+			if len(result.Blocked) != len(tt.wantBlocked) {
+				t.Fatalf("Blocked = %+v, want %d", result.Blocked, len(tt.wantBlocked))
+			}
+			for _, info := range result.Blocked {
+				want, ok := tt.wantBlocked[info.Behavior.ID]
+				if !ok {
+					t.Errorf("unexpected blocked behavior %s", info.Behavior.ID)
+					continue
+				}
+				if !strings.HasPrefix(info.Reason, want) {
+					t.Errorf("Blocked[%s].Reason = %q, want prefix %q", info.Behavior.ID, info.Reason, want)
+				}
+			}
+		})
+	}
+}
@@ -1,17 +1,42 @@
 package activation
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/nvandessel/floop/internal/models"
 )
 
 // Resolver handles conflicts between active behaviors
-type Resolver struct{}
+type Resolver struct {
+	// overrides returns the IDs a behavior that did not match overrides, so
+	// override chains pass through it. Nil treats only matches as known.
+	overrides func(id string) []string
+}
 
 // NewResolver creates a new conflict resolver
 func NewResolver() *Resolver {
 	return &Resolver{}
 }
 
+// WithOverrides sets how the resolver finds the behaviors a non-matching
+// behavior overrides. With it, when A overrides B and B overrides C, C yields
+// to a matching A even if B did not match.
+func (r *Resolver) WithOverrides(overrides func(id string) []string) *Resolver {
+	r.overrides = overrides
+	return r
+}
+
+// WithBehaviors lets override chains pass through any of the given
+// behaviors, matching or not.
+func (r *Resolver) WithBehaviors(all []models.Behavior) *Resolver {
+	byID := make(map[string][]string, len(all))
+	for _, b := range all {
+		byID[b.ID] = b.Overrides
+	}
+	return r.WithOverrides(func(id string) []string { return byID[id] })
+}
+
 // ResolveResult contains the final active behaviors after conflict resolution
 type ResolveResult struct {
 	// Active behaviors after resolution
@@ -22,6 +47,13 @@ type ResolveResult struct {
 
 	// Conflicting behaviors that were excluded
 	Excluded []ConflictInfo
+
+	// Generalized parents hidden by a matching specialization (also listed
+	// in Overridden). The compiler renders them as headers over their children.
+	Generalized []models.Behavior
+
+	// Blocked behaviors whose requirements are not active
+	Blocked []BlockedInfo
 }
 
 // OverrideInfo describes why a behavior was overridden
@@ -29,6 +61,10 @@ type OverrideInfo struct {
 	Behavior   models.Behavior `json:"behavior"`
 	OverrideBy string          `json:"override_by"`
 	Reason     string          `json:"reason"`
+
+	// Chain lists the override path from the top-most overriding behavior
+	// down to this one, e.g. [A, B, C] when A overrides B overrides C.
+	Chain []string `json:"chain,omitempty"`
 }
 
 // ConflictInfo describes a conflict between behaviors
@@ -39,18 +75,43 @@ type ConflictInfo struct {
 	Reason        string          `json:"reason"`
 }
 
-// Resolve takes a list of matching behaviors and resolves conflicts
+// Resolve takes a list of matching behaviors and resolves conflicts.
+// A behavior activates only if every behavior it requires is also active;
+// otherwise it is reported in Blocked. Requirements are checked before
+// overrides and conflicts (a behavior missing a dependency never overrides
+// another) and again after (a dependency that lost also blocks).
 func (r *Resolver) Resolve(matches []ActivationResult) ResolveResult {
 	result := ResolveResult{
-		Active:     make([]models.Behavior, 0),
-		Overridden: make([]OverrideInfo, 0),
-		Excluded:   make([]ConflictInfo, 0),
+		Active:      make([]models.Behavior, 0),
+		Overridden:  make([]OverrideInfo, 0),
+		Excluded:    make([]ConflictInfo, 0),
+		Generalized: make([]models.Behavior, 0),
+		Blocked:     make([]BlockedInfo, 0),
 	}
 
 	if len(matches) == 0 {
 		return result
 	}
 
+	// Hold back behaviors whose requirements did not match the context
+	allMatches := matches
+	matched := make(map[string]bool, len(matches))
+	matchedBehaviors := make([]models.Behavior, 0, len(matches))
+	for _, m := range matches {
+		matched[m.Behavior.ID] = true
+		matchedBehaviors = append(matchedBehaviors, m.Behavior)
+	}
+	blocked := blockUnmetRequirements(matchedBehaviors, matched, nil)
+	if len(blocked) > 0 {
+		unblocked := make([]ActivationResult, 0, len(matches))
+		for _, m := range matches {
+			if _, isBlocked := blocked[m.Behavior.ID]; !isBlocked {
+				unblocked = append(unblocked, m)
+			}
+		}
+		matches = unblocked
+	}
+
 	// Build lookup maps
 	behaviorByID := make(map[string]models.Behavior)
 	resultByID := make(map[string]ActivationResult)
@@ -61,16 +122,9 @@ func (r *Resolver) Resolve(matches []ActivationResult) ResolveResult {
 
 	// Track which behaviors are excluded
 	excluded := make(map[string]bool)
-	overridden := make(map[string]string) // ID -> overriding ID
 
-	// Process overrides
-	for _, m := range matches {
-		for _, overriddenID := range m.Behavior.Overrides {
-			if _, exists := behaviorByID[overriddenID]; exists {
-				overridden[overriddenID] = m.Behavior.ID
-			}
-		}
-	}
+	// Process overrides, including chains and specializations
+	overridden := r.resolveOverrides(matches)
 
 	// Process conflicts - higher priority/specificity wins
 	for i, m1 := range matches {
@@ -137,11 +191,15 @@ func (r *Resolver) Resolve(matches []ActivationResult) ResolveResult {
 			continue
 		}
 
-		if overrideBy, wasOverridden := overridden[id]; wasOverridden {
+		if link, wasOverridden := overridden[id]; wasOverridden {
+			if link.reason == reasonGeneralized {
+				result.Generalized = append(result.Generalized, m.Behavior)
+			}
 			result.Overridden = append(result.Overridden, OverrideInfo{
 				Behavior:   m.Behavior,
-				OverrideBy: overrideBy,
-				Reason:     "Superseded by more specific behavior",
+				OverrideBy: link.by,
+				Reason:     link.reason,
+				Chain:      overrideChain(id, overridden),
 			})
 			continue
 		}
@@ -149,9 +207,63 @@ func (r *Resolver) Resolve(matches []ActivationResult) ResolveResult {
 		result.Active = append(result.Active, m.Behavior)
 	}
 
+	// Requirements that were overridden or lost a conflict block their dependents
+	unavailable := make(map[string]requirementState, len(overridden)+len(excluded))
+	for id := range overridden {
+		unavailable[id] = stateOverridden
+	}
+	for id := range excluded {
+		unavailable[id] = stateExcluded
+	}
+	active := make(map[string]bool, len(result.Active))
+	for _, b := range result.Active {
+		active[b.ID] = true
+	}
+	if late := blockUnmetRequirements(result.Active, active, unavailable); len(late) > 0 {
+		kept := make([]models.Behavior, 0, len(result.Active))
+		for _, b := range result.Active {
+			if info, isBlocked := late[b.ID]; isBlocked {
+				blocked[b.ID] = info
+				continue
+			}
+			kept = append(kept, b)
+		}
+		result.Active = kept
+	}
+
+	for _, m := range allMatches {
+		if info, isBlocked := blocked[m.Behavior.ID]; isBlocked {
+			result.Blocked = append(result.Blocked, info)
+		}
+	}
+
 	return result
 }
 
+// Explain returns why resolution held back the behavior with the given ID,
+// or false if it was not held back.
+func (res ResolveResult) Explain(id string) (string, bool) {
+	for _, b := range res.Blocked {
+		if b.Behavior.ID == id {
+			return "Blocked: " + b.Reason, true
+		}
+	}
+	for _, o := range res.Overridden {
+		if o.Behavior.ID == id {
+			if len(o.Chain) > 2 {
+				return fmt.Sprintf("Overridden by %s via %s (%s)", o.OverrideBy, strings.Join(o.Chain, " -> "), o.Reason), true
+			}
+			return fmt.Sprintf("Overridden by %s (%s)", o.OverrideBy, o.Reason), true
+		}
+	}
+	for _, e := range res.Excluded {
+		if e.Behavior.ID == id {
+			return fmt.Sprintf("Lost conflict with %s", e.Winner), true
+		}
+	}
+	return "", false
+}
+
 // pickWinner determines which behavior wins a conflict
 func (r *Resolver) pickWinner(a, b ActivationResult) string {
 	// Higher specificity wins
@@ -99,6 +99,46 @@ func TestResolver_Overrides(t *testing.T) {
 	}
 }
 
+func TestResolver_Specializes(t *testing.T) {
+	resolver := NewResolver()
+
+	// b2 and b3 specialize the generalized parent b1
+	parent := ActivationResult{Behavior: models.Behavior{ID: "b1", Name: "general-rule"}, Specificity: 0}
+	child := ActivationResult{
+		Behavior:    models.Behavior{ID: "b2", Name: "specific-rule", Specializes: []string{"b1"}},
+		Specificity: 1,
+	}
+
+	t.Run("child match hides parent", func(t *testing.T) {
+		result := resolver.Resolve([]ActivationResult{parent, child})
+
+		if len(result.Active) != 1 || result.Active[0].ID != "b2" {
+			t.Fatalf("Active = %v, want only b2", result.Active)
+		}
+		if len(result.Overridden) != 1 {
+			t.Fatalf("Expected 1 overridden, got %d", len(result.Overridden))
+		}
+		got := result.Overridden[0]
+		if got.Behavior.ID != "b1" || got.OverrideBy != "b2" {
+			t.Errorf("Overridden = %s by %s, want b1 by b2", got.Behavior.ID, got.OverrideBy)
+		}
+		if got.Reason != "Generalized by matching specialization" {
+			t.Errorf("Reason = %q", got.Reason)
+		}
+		if len(result.Generalized) != 1 || result.Generalized[0].ID != "b1" {
+			t.Errorf("Generalized = %v, want [b1]", result.Generalized)
+		}
+	})
+
+	t.Run("parent alone stays active", func(t *testing.T) {
+		result := resolver.Resolve([]ActivationResult{parent})
+
+		if len(result.Active) != 1 || result.Active[0].ID != "b1" {
+			t.Fatalf("Active = %v, want only b1", result.Active)
+		}
+	})
+}
+
 func TestResolver_Conflicts(t *testing.T) {
 	resolver := NewResolver()
 
@@ -0,0 +1,181 @@
+package activation
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// maxSniffBytes bounds how much of a file is read for content signals.
+// Imports sit at the top of a file, so the head is enough.
+const maxSniffBytes = 32 * 1024
+
+var (
+	goImportLine   = regexp.MustCompile(`^import\s+(?:[\w.]+\s+)?"([^"]+)"`)
+	goImportSpec   = regexp.MustCompile(`^(?:[\w.]+\s+)?"([^"]+)"`)
+	pyImport       = regexp.MustCompile(`^import\s+([\w.]+(?:\s*,\s*[\w.]+)*)`)
+	pyFromImport   = regexp.MustCompile(`^from\s+([\w.]+)\s+import\b`)
+	jsImportFrom   = regexp.MustCompile(`^(?:import|export)\b[^'"]*\bfrom\s+['"]([^'"]+)['"]`)
+	jsImportBare   = regexp.MustCompile(`^import\s+['"]([^'"]+)['"]`)
+	jsRequire      = regexp.MustCompile(`\brequire\(\s*['"]([^'"]+)['"]\s*\)`)
+	rustUse        = regexp.MustCompile(`^(?:pub\s+)?use\s+([\w]+)`)
+	rustExtern     = regexp.MustCompile(`^extern\s+crate\s+(\w+)`)
+	javaImport     = regexp.MustCompile(`^import\s+(?:static\s+)?([\w.]+)`)
+	pyImportAsPart = regexp.MustCompile(`\s+as\s+\w+$`)
+)
+
+// frameworkMarkers maps framework names to the import prefixes that reveal
+// them. An import matches a marker when it equals it or continues it with a
+// path separator.
+var frameworkMarkers = map[string][]string{
+	"react":   {"react", "react-dom"},
+	"next":    {"next"},
+	"vue":     {"vue"},
+	"angular": {"@angular/core"},
+	"svelte":  {"svelte"},
+	"express": {"express"},
+	"jest":    {"jest", "@jest/globals"},
+	"pytest":  {"pytest"},
+	"django":  {"django"},
+	"flask":   {"flask"},
+	"fastapi": {"fastapi"},
+	"gin":     {"github.com/gin-gonic/gin"},
+	"echo":    {"github.com/labstack/echo"},
+	"cobra":   {"github.com/spf13/cobra"},
+	"testify": {"github.com/stretchr/testify"},
+	"tokio":   {"tokio"},
+	"actix":   {"actix_web"},
+	"axum":    {"axum"},
+	"spring":  {"org.springframework"},
+	"junit":   {"org.junit"},
+}
+
+// SniffContent extracts import statements from file content and the
+// frameworks they reveal. The language selects the import syntax; unknown
+// languages yield nothing. Results are sorted and deduplicated.
+func SniffContent(language, content string) (imports, frameworks []string) {
+	seen := make(map[string]bool)
+	add := func(imp string) {
+		imp = strings.TrimSpace(imp)
+		// Relative imports name local files, not packages
+		if imp != "" && !strings.HasPrefix(imp, ".") && !seen[imp] {
+			seen[imp] = true
+			imports = append(imports, imp)
+		}
+	}
+
+	inGoBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		switch language {
+		case "go":
+			switch {
+			case inGoBlock:
+				if strings.HasPrefix(line, ")") {
+					inGoBlock = false
+				} else if m := goImportSpec.FindStringSubmatch(line); m != nil {
+					add(m[1])
+				}
+			case strings.HasPrefix(line, "import ("):
+				inGoBlock = true
+			default:
+				if m := goImportLine.FindStringSubmatch(line); m != nil {
+					add(m[1])
+				}
+			}
+		case "python":
+			if m := pyFromImport.FindStringSubmatch(line); m != nil {
+				add(m[1])
+			} else if m := pyImport.FindStringSubmatch(line); m != nil {
+				for _, mod := range strings.Split(m[1], ",") {
+					add(pyImportAsPart.ReplaceAllString(strings.TrimSpace(mod), ""))
+				}
+			}
+		case "javascript", "typescript":
+			if m := jsImportFrom.FindStringSubmatch(line); m != nil {
+				add(m[1])
+			} else if m := jsImportBare.FindStringSubmatch(line); m != nil {
+				add(m[1])
+			}
+			for _, m := range jsRequire.FindAllStringSubmatch(line, -1) {
+				add(m[1])
+			}
+		case "rust":
+			if m := rustExtern.FindStringSubmatch(line); m != nil {
+				add(m[1])
+			} else if m := rustUse.FindStringSubmatch(line); m != nil {
+				add(m[1])
+			}
+		case "java":
+			if m := javaImport.FindStringSubmatch(line); m != nil {
+				add(m[1])
+			}
+		}
+	}
+	sort.Strings(imports)
+	return imports, detectFrameworks(imports)
+}
+
+// detectFrameworks returns the frameworks whose markers appear in imports.
+func detectFrameworks(imports []string) []string {
+	var frameworks []string
+	for name, markers := range frameworkMarkers {
+		if importsAny(imports, markers) {
+			frameworks = append(frameworks, name)
+		}
+	}
+	sort.Strings(frameworks)
+	return frameworks
+}
+
+func importsAny(imports, markers []string) bool {
+	for _, imp := range imports {
+		for _, marker := range markers {
+			if imp == marker || strings.HasPrefix(imp, marker+"/") || strings.HasPrefix(imp, marker+".") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sniffFile reads the head of a regular file and returns its content
+// signals. Unreadable files yield nothing.
+func sniffFile(path, language string) (imports, frameworks []string) {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxSniffBytes))
+	if err != nil {
+		return nil, nil
+	}
+	content := string(data)
+	if language == "" {
+		language = sniffLanguage(path, content)
+	}
+	return SniffContent(language, content)
+}
+
+// sniffLanguage covers extensions InferLanguage does not know, then falls
+// back to the content.
+func sniffLanguage(path, content string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsx", ".mjs", ".cjs":
+		return "javascript"
+	case ".tsx":
+		return "typescript"
+	}
+	return models.InferLanguageFromContent(content)
+}
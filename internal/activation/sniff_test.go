@@ -0,0 +1,124 @@
+package activation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestSniffContent(t *testing.T) {
+	tests := []struct {
+		name           string
+		language       string
+		content        string
+		wantImports    []string
+		wantFrameworks []string
+	}{
+		{
+			name:     "go block and single imports",
+			language: "go",
+			content: `package main
+
+import "fmt"
+
+import (
+	"net/http"
+	gin "github.com/gin-gonic/gin"
+)
+`,
+			wantImports:    []string{"fmt", "github.com/gin-gonic/gin", "net/http"},
+			wantFrameworks: []string{"gin"},
+		},
+		{
+			name:     "python import forms",
+			language: "python",
+			content: `import os, sys as system
+from django.db import models
+from . import helpers
+import pytest
+`,
+			wantImports:    []string{"django.db", "os", "pytest", "sys"},
+			wantFrameworks: []string{"django", "pytest"},
+		},
+		{
+			name:     "javascript imports and require",
+			language: "javascript",
+			content: `import React, { useState } from 'react';
+import './styles.css';
+import "react-dom/client";
+const express = require("express");
+export { Button } from "./Button";
+`,
+			wantImports:    []string{"express", "react", "react-dom/client"},
+			wantFrameworks: []string{"express", "react"},
+		},
+		{
+			name:           "rust use and extern crate",
+			language:       "rust",
+			content:        "extern crate serde;\nuse tokio::runtime::Runtime;\nuse std::io;\n",
+			wantImports:    []string{"serde", "std", "tokio"},
+			wantFrameworks: []string{"tokio"},
+		},
+		{
+			name:           "java imports",
+			language:       "java",
+			content:        "import org.junit.jupiter.api.Test;\nimport static java.util.Objects.requireNonNull;\n",
+			wantImports:    []string{"java.util.Objects.requireNonNull", "org.junit.jupiter.api.Test"},
+			wantFrameworks: []string{"junit"},
+		},
+		{
+			name:     "unknown language",
+			language: "markdown",
+			content:  "import foo from 'bar'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imports, frameworks := SniffContent(tt.language, tt.content)
+			if strings.Join(imports, ",") != strings.Join(tt.wantImports, ",") {
+				t.Errorf("imports = %v, want %v", imports, tt.wantImports)
+			}
+			if strings.Join(frameworks, ",") != strings.Join(tt.wantFrameworks, ",") {
+				t.Errorf("frameworks = %v, want %v", frameworks, tt.wantFrameworks)
+			}
+		})
+	}
+}
+
+func TestContextBuilder_WithContentSniffing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Button.tsx")
+	content := "import React from 'react';\nexport const Button = () => <button />;\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	off := NewContextBuilder().WithFile(path).Build()
+	if off.Imports != nil || off.Frameworks != nil {
+		t.Errorf("sniffing disabled: imports = %v, frameworks = %v, want none", off.Imports, off.Frameworks)
+	}
+
+	ctx := NewContextBuilder().WithFile(path).WithContentSniffing(true).Build()
+	if len(ctx.Frameworks) != 1 || ctx.Frameworks[0] != "react" {
+		t.Fatalf("frameworks = %v, want [react]", ctx.Frameworks)
+	}
+
+	// Behaviors can be conditioned on the sniffed fields
+	evaluator := NewEvaluator()
+	react := models.Behavior{ID: "react", When: map[string]interface{}{"framework": "react"}}
+	vue := models.Behavior{ID: "vue", When: map[string]interface{}{"framework": []interface{}{"vue", "svelte"}}}
+	imports := models.Behavior{ID: "imports", When: map[string]interface{}{"imports": "react*"}}
+	if !evaluator.IsActive(ctx, react) || !evaluator.IsActive(ctx, imports) {
+		t.Error("react behaviors should be active for a React component")
+	}
+	if evaluator.IsActive(ctx, vue) {
+		t.Error("vue behavior should not be active for a React component")
+	}
+
+	// Without content signals the framework condition is absent, not contradicted
+	if mr := evaluator.evaluateMatch(off, react); !mr.Matched || len(mr.Absent) != 1 {
+		t.Errorf("match without sniffing = %+v, want absent framework", mr)
+	}
+}
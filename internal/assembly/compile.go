@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/sanitize"
 	"github.com/nvandessel/floop/internal/tokens"
 )
 
@@ -39,24 +40,31 @@ type CompiledPrompt struct {
 	ExcludedBehaviors []string `json:"excluded_behaviors,omitempty"`
 }
 
-// PromptSection groups behaviors by kind
+// PromptSection groups behaviors under a title. Kind is set for per-kind
+// sections and Tag for tag-grouped sections; mixed sections leave both empty.
 type PromptSection struct {
-	Kind       models.BehaviorKind `json:"kind"`
+	Kind       models.BehaviorKind `json:"kind,omitempty"`
+	Tag        string              `json:"tag,omitempty"`
 	Title      string              `json:"title"`
 	Content    string              `json:"content"`
 	TokenCount int                 `json:"token_count"`
-	Behaviors  []string            `json:"behaviors"` // IDs of included behaviors
+	Behaviors  []string            `json:"behaviors"`         // IDs of included behaviors
+	Parents    []string            `json:"parents,omitempty"` // IDs of generalized parents rendered over their children
 }
 
 // Compiler transforms active behaviors into prompt-ready format
 type Compiler struct {
-	format Format
+	format   Format
+	ordering OrderStrategy
+	task     string
+	parents  map[string]models.Behavior
 }
 
 // NewCompiler creates a new behavior compiler
 func NewCompiler() *Compiler {
 	return &Compiler{
-		format: FormatMarkdown,
+		format:   FormatMarkdown,
+		ordering: OrderByKind,
 	}
 }
 
@@ -66,6 +74,28 @@ func (c *Compiler) WithFormat(format Format) *Compiler {
 	return c
 }
 
+// WithOrdering sets the section ordering strategy
+func (c *Compiler) WithOrdering(ordering OrderStrategy) *Compiler {
+	c.ordering = ordering
+	return c
+}
+
+// WithTask sets the current task, used by OrderTaskRelevantFirst
+func (c *Compiler) WithTask(task string) *Compiler {
+	c.task = task
+	return c
+}
+
+// WithParents sets generalized parents available as group headers. Children
+// that specialize the same parent are rendered beneath it (see compose.go).
+func (c *Compiler) WithParents(parents []models.Behavior) *Compiler {
+	c.parents = make(map[string]models.Behavior, len(parents))
+	for _, p := range parents {
+		c.parents[p.ID] = p
+	}
+	return c
+}
+
 // Compile transforms active behaviors into a prompt-ready format
 func (c *Compiler) Compile(behaviors []models.Behavior) *CompiledPrompt {
 	if len(behaviors) == 0 {
@@ -78,19 +108,28 @@ func (c *Compiler) Compile(behaviors []models.Behavior) *CompiledPrompt {
 		}
 	}
 
-	// Group behaviors by kind
-	grouped := c.groupByKind(behaviors)
-
-	// Build sections
-	sections := c.buildSections(grouped)
+	// Arrange behaviors into ordered sections, nesting children beneath
+	// shared generalized parents
+	composer := c.newComposer(behaviors)
+	sections := c.buildSections(composer.arrange(c.arrange(composer.standalone(behaviors))))
 
 	// Assemble final text
 	text := c.assembleText(sections)
 
-	// Collect behavior IDs
+	// Collect behavior IDs, including parents rendered only as headers
 	var includedIDs []string
+	included := make(map[string]bool, len(behaviors))
 	for _, b := range behaviors {
 		includedIDs = append(includedIDs, b.ID)
+		included[b.ID] = true
+	}
+	for _, s := range sections {
+		for _, id := range s.Parents {
+			if !included[id] {
+				includedIDs = append(includedIDs, id)
+				included[id] = true
+			}
+		}
 	}
 
 	return &CompiledPrompt{
@@ -123,35 +162,30 @@ func (c *Compiler) groupByKind(behaviors []models.Behavior) map[models.BehaviorK
 	return grouped
 }
 
-// buildSections creates prompt sections from grouped behaviors
-func (c *Compiler) buildSections(grouped map[models.BehaviorKind][]models.Behavior) []PromptSection {
-	// Define order of sections (constraints first as they're most important)
-	kindOrder := []models.BehaviorKind{
-		models.BehaviorKindConstraint,
-		models.BehaviorKindDirective,
-		models.BehaviorKindPreference,
-		models.BehaviorKindProcedure,
-	}
-
+// buildSections creates prompt sections from arranged section groups
+func (c *Compiler) buildSections(groups []sectionGroup) []PromptSection {
 	var sections []PromptSection
 
-	for _, kind := range kindOrder {
-		behaviors, exists := grouped[kind]
-		if !exists || len(behaviors) == 0 {
-			continue
-		}
-
+	for _, g := range groups {
 		section := PromptSection{
-			Kind:      kind,
-			Title:     c.kindTitle(kind),
-			Behaviors: make([]string, 0, len(behaviors)),
+			Kind:      g.kind,
+			Tag:       g.tag,
+			Title:     g.title,
+			Behaviors: make([]string, 0, len(g.behaviors)),
 		}
 
 		var contentParts []string
-		for _, b := range behaviors {
-			content := c.formatBehavior(b)
-			contentParts = append(contentParts, content)
-			section.Behaviors = append(section.Behaviors, b.ID)
+		for _, item := range g.items() {
+			if item.parent == nil {
+				contentParts = append(contentParts, c.formatBehavior(item.behavior))
+				section.Behaviors = append(section.Behaviors, item.behavior.ID)
+				continue
+			}
+			contentParts = append(contentParts, c.formatComposed(*item.parent, item.children))
+			section.Parents = append(section.Parents, item.parent.ID)
+			for _, child := range item.children {
+				section.Behaviors = append(section.Behaviors, child.ID)
+			}
 		}
 
 		section.Content = strings.Join(contentParts, "\n")
@@ -194,11 +228,38 @@ func (c *Compiler) formatBehavior(b models.Behavior) string {
 
 func (c *Compiler) formatBehaviorMarkdown(b models.Behavior, content string) string {
 	// Format with bullet point
-	return fmt.Sprintf("- %s", content)
+	return fmt.Sprintf("- %s", c.renderContent(content))
 }
 
 func (c *Compiler) formatBehaviorXML(b models.Behavior, content string) string {
-	return fmt.Sprintf("<behavior kind=\"%s\">%s</behavior>", b.Kind, escapeXML(content))
+	return fmt.Sprintf("<behavior kind=\"%s\">%s</behavior>", kindAttr(b.Kind), c.renderContent(content))
+}
+
+// renderContent applies the render-time content policy to stored text.
+// Content can reach the store without passing through the learning
+// pipeline's sanitizer, so it is cleaned again here: XML output is escaped,
+// and markdown and plain output are framed so the text stays inside its
+// bullet and can't open sections of its own.
+func (c *Compiler) renderContent(s string) string {
+	if c.format == FormatXML {
+		return escapeXML(sanitize.RenderText(s))
+	}
+	return sanitize.RenderPromptItem(s)
+}
+
+// renderInline renders stored text that must stay on a single line, such as
+// names and cluster labels.
+func (c *Compiler) renderInline(s string) string {
+	s = strings.Join(strings.Fields(sanitize.RenderText(s)), " ")
+	if c.format == FormatXML {
+		return escapeXML(s)
+	}
+	return s
+}
+
+// kindAttr renders a behavior kind for an XML attribute value.
+func kindAttr(kind models.BehaviorKind) string {
+	return escapeXML(sanitize.RenderText(string(kind)))
 }
 
 // escapeXML escapes XML special characters in content strings.
@@ -212,7 +273,7 @@ func escapeXML(s string) string {
 }
 
 func (c *Compiler) formatBehaviorPlain(b models.Behavior, content string) string {
-	return content
+	return c.renderContent(content)
 }
 
 // assembleText combines sections into final prompt text
@@ -227,9 +288,9 @@ func (c *Compiler) assembleText(sections []PromptSection) string {
 	case FormatXML:
 		parts = append(parts, "<learned-behaviors>")
 		for _, s := range sections {
-			parts = append(parts, fmt.Sprintf("<%s>", strings.ToLower(s.Title)))
+			parts = append(parts, fmt.Sprintf("<%s>", sectionElement(s.Title)))
 			parts = append(parts, s.Content)
-			parts = append(parts, fmt.Sprintf("</%s>", strings.ToLower(s.Title)))
+			parts = append(parts, fmt.Sprintf("</%s>", sectionElement(s.Title)))
 		}
 		parts = append(parts, "</learned-behaviors>")
 
@@ -295,7 +356,8 @@ func (c *Compiler) CompileTiered(plan *models.InjectionPlan) *TieredCompiledProm
 		}
 	}
 
-	// Compile full-tier behaviors normally
+	// Compile full-tier behaviors normally, with the plan's group headers
+	// available as parents
 	fullBehaviors := make([]models.Behavior, 0, len(plan.FullBehaviors))
 	for _, ib := range plan.FullBehaviors {
 		if ib.Behavior != nil {
@@ -303,7 +365,7 @@ func (c *Compiler) CompileTiered(plan *models.InjectionPlan) *TieredCompiledProm
 		}
 	}
 
-	basePrompt := c.Compile(fullBehaviors)
+	basePrompt := c.withGroupHeaders(plan.GroupHeaders).Compile(fullBehaviors)
 
 	// Build tiered prompt
 	result := &TieredCompiledPrompt{
@@ -360,9 +422,8 @@ func (c *Compiler) buildQuickReferenceSection(summarized []models.InjectedBehavi
 		if len(shortID) > 8 {
 			shortID = shortID[:8]
 		}
-		content := ib.Content
+		content := c.renderContent(ib.Content)
 		if c.format == FormatXML {
-			content = escapeXML(content)
 			shortID = escapeXML(shortID)
 		}
 		lines = append(lines, fmt.Sprintf("- [%s] %s", shortID, content))
@@ -384,10 +445,7 @@ func (c *Compiler) buildNameOnlySection(nameOnly []models.InjectedBehavior) stri
 			continue
 		}
 		// Content is pre-formatted by the tier mapper as `name` [kind] #tags
-		content := ib.Content
-		if c.format == FormatXML {
-			content = escapeXML(content)
-		}
+		content := c.renderContent(ib.Content)
 		lines = append(lines, fmt.Sprintf("- %s", content))
 	}
 
@@ -527,17 +585,17 @@ func (c *Compiler) formatCluster(cluster BehaviorCluster, totalCount int) string
 
 	switch c.format {
 	case FormatXML:
-		lines = append(lines, fmt.Sprintf("<cluster label=\"%s\" count=\"%d\">", escapeXML(cluster.ClusterLabel), totalCount))
+		lines = append(lines, fmt.Sprintf("<cluster label=\"%s\" count=\"%d\">", c.renderInline(cluster.ClusterLabel), totalCount))
 		if cluster.Representative.Behavior != nil {
 			lines = append(lines, fmt.Sprintf("  <behavior kind=\"%s\">%s</behavior>",
-				cluster.Representative.Behavior.Kind,
-				escapeXML(cluster.Representative.Content)))
+				kindAttr(cluster.Representative.Behavior.Kind),
+				c.renderContent(cluster.Representative.Content)))
 		}
 		if len(cluster.Members) > 0 {
 			var names []string
 			for _, m := range cluster.Members {
 				if m.Behavior != nil {
-					names = append(names, escapeXML(m.Behavior.Name))
+					names = append(names, c.renderInline(m.Behavior.Name))
 				}
 			}
 			lines = append(lines, fmt.Sprintf("  <also>%s</also>", strings.Join(names, ", ")))
@@ -545,30 +603,30 @@ func (c *Compiler) formatCluster(cluster BehaviorCluster, totalCount int) string
 		lines = append(lines, "</cluster>")
 
 	case FormatPlain:
-		lines = append(lines, fmt.Sprintf("%s (%d behaviors):", cluster.ClusterLabel, totalCount))
+		lines = append(lines, fmt.Sprintf("%s (%d behaviors):", c.renderInline(cluster.ClusterLabel), totalCount))
 		if cluster.Representative.Content != "" {
-			lines = append(lines, fmt.Sprintf("  %s", cluster.Representative.Content))
+			lines = append(lines, fmt.Sprintf("  %s", c.renderContent(cluster.Representative.Content)))
 		}
 		if len(cluster.Members) > 0 {
 			var names []string
 			for _, m := range cluster.Members {
 				if m.Behavior != nil {
-					names = append(names, m.Behavior.Name)
+					names = append(names, c.renderInline(m.Behavior.Name))
 				}
 			}
 			lines = append(lines, fmt.Sprintf("  Also: %s (use `floop show <id>` for details)", strings.Join(names, ", ")))
 		}
 
 	default: // FormatMarkdown
-		lines = append(lines, fmt.Sprintf("### %s (%d behaviors)", cluster.ClusterLabel, totalCount))
+		lines = append(lines, fmt.Sprintf("### %s (%d behaviors)", c.renderInline(cluster.ClusterLabel), totalCount))
 		if cluster.Representative.Content != "" {
-			lines = append(lines, fmt.Sprintf("- **%s**", cluster.Representative.Content))
+			lines = append(lines, fmt.Sprintf("- **%s**", c.renderContent(cluster.Representative.Content)))
 		}
 		if len(cluster.Members) > 0 {
 			var names []string
 			for _, m := range cluster.Members {
 				if m.Behavior != nil {
-					names = append(names, m.Behavior.Name)
+					names = append(names, c.renderInline(m.Behavior.Name))
 				}
 			}
 			lines = append(lines, fmt.Sprintf("- _Also: %s_ (use `floop show <id>` for details)", strings.Join(names, ", ")))
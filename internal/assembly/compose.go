@@ -0,0 +1,233 @@
+package assembly
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// minComposedChildren is the number of children in a section that must share
+// a generalized parent before they are nested beneath it. A lone child is
+// rendered on its own.
+const minComposedChildren = 2
+
+// sectionItem is one rendered entry in a section: either a single behavior,
+// or a generalized parent with the children that specialize it.
+type sectionItem struct {
+	behavior models.Behavior
+	parent   *models.Behavior
+	children []models.Behavior
+}
+
+// items returns the section's entries in rendering order.
+func (g sectionGroup) items() []sectionItem {
+	if g.composed != nil {
+		return g.composed
+	}
+	items := make([]sectionItem, 0, len(g.behaviors))
+	for _, b := range g.behaviors {
+		items = append(items, sectionItem{behavior: b})
+	}
+	return items
+}
+
+// composer nests children beneath the generalized parents they specialize,
+// so a shared rule is rendered once instead of once per child.
+type composer struct {
+	// parents are the known generalized parents: those set with WithParents
+	// plus any compiled behavior that another compiled behavior specializes.
+	parents map[string]models.Behavior
+
+	// headers are compiled behaviors held back to be rendered as headers.
+	headers map[string]bool
+}
+
+// newComposer builds a composer for the behaviors being compiled.
+func (c *Compiler) newComposer(behaviors []models.Behavior) *composer {
+	cm := &composer{
+		parents: make(map[string]models.Behavior, len(c.parents)),
+		headers: make(map[string]bool),
+	}
+	for id, p := range c.parents {
+		cm.parents[id] = p
+	}
+
+	byID := make(map[string]models.Behavior, len(behaviors))
+	for _, b := range behaviors {
+		byID[b.ID] = b
+	}
+	counts := make(map[string]int)
+	for _, b := range behaviors {
+		for _, id := range b.Specializes {
+			if p, ok := byID[id]; ok {
+				cm.parents[id] = p
+			}
+		}
+		if id, ok := cm.parentOf(b); ok {
+			counts[id]++
+		}
+	}
+	for id, n := range counts {
+		if _, compiled := byID[id]; compiled && n >= minComposedChildren {
+			cm.headers[id] = true
+		}
+	}
+	return cm
+}
+
+// parentOf returns the first known parent that b specializes.
+func (cm *composer) parentOf(b models.Behavior) (string, bool) {
+	for _, id := range b.Specializes {
+		if _, ok := cm.parents[id]; ok {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// standalone returns behaviors minus those held back as headers.
+func (cm *composer) standalone(behaviors []models.Behavior) []models.Behavior {
+	if len(cm.headers) == 0 {
+		return behaviors
+	}
+	result := make([]models.Behavior, 0, len(behaviors))
+	for _, b := range behaviors {
+		if !cm.headers[b.ID] {
+			result = append(result, b)
+		}
+	}
+	return result
+}
+
+// arrange nests children within each section beneath their shared parent,
+// at the position of the first child. A header whose children ended up in
+// different sections is rendered on its own so it is never dropped.
+func (cm *composer) arrange(groups []sectionGroup) []sectionGroup {
+	if len(cm.parents) == 0 {
+		return groups
+	}
+
+	used := make(map[string]bool)
+	for gi := range groups {
+		g := &groups[gi]
+
+		counts := make(map[string]int)
+		for _, b := range g.behaviors {
+			if id, ok := cm.parentOf(b); ok {
+				counts[id]++
+			}
+		}
+
+		var items []sectionItem
+		emitted := make(map[string]bool)
+		composed := false
+		for _, b := range g.behaviors {
+			id, ok := cm.parentOf(b)
+			if !ok || counts[id] < minComposedChildren {
+				items = append(items, sectionItem{behavior: b})
+				continue
+			}
+			if emitted[id] {
+				continue
+			}
+			emitted[id] = true
+			composed = true
+			used[id] = true
+
+			parent := cm.parents[id]
+			item := sectionItem{parent: &parent}
+			for _, child := range g.behaviors {
+				if childParent, ok := cm.parentOf(child); ok && childParent == id {
+					item.children = append(item.children, child)
+				}
+			}
+			items = append(items, item)
+		}
+		if composed {
+			g.composed = items
+		}
+	}
+
+	headerIDs := make([]string, 0, len(cm.headers))
+	for id := range cm.headers {
+		headerIDs = append(headerIDs, id)
+	}
+	sort.Strings(headerIDs)
+	for _, id := range headerIDs {
+		if used[id] || len(groups) == 0 {
+			continue
+		}
+		parent := cm.parents[id]
+		target := 0
+		for gi := range groups {
+			if groups[gi].kind == parent.Kind {
+				target = gi
+				break
+			}
+		}
+		g := &groups[target]
+		if g.composed == nil {
+			g.composed = g.items()
+		}
+		g.composed = append(g.composed, sectionItem{behavior: parent})
+	}
+
+	return groups
+}
+
+// withGroupHeaders returns a copy of the compiler with the plan's group
+// headers added to its parents. The receiver is left unchanged.
+func (c *Compiler) withGroupHeaders(headers []models.InjectedBehavior) *Compiler {
+	if len(headers) == 0 {
+		return c
+	}
+	cp := *c
+	cp.parents = make(map[string]models.Behavior, len(c.parents)+len(headers))
+	for id, p := range c.parents {
+		cp.parents[id] = p
+	}
+	for _, ib := range headers {
+		if ib.Behavior != nil {
+			cp.parents[ib.Behavior.ID] = *ib.Behavior
+		}
+	}
+	return &cp
+}
+
+// formatComposed renders a parent once with its children nested beneath.
+func (c *Compiler) formatComposed(parent models.Behavior, children []models.Behavior) string {
+	var sb strings.Builder
+
+	switch c.format {
+	case FormatXML:
+		sb.WriteString(fmt.Sprintf("<behavior kind=\"%s\">%s", kindAttr(parent.Kind), c.renderContent(parent.Content.Canonical)))
+		for _, child := range children {
+			sb.WriteString("\n  ")
+			sb.WriteString(c.formatBehaviorXML(child, child.Content.Canonical))
+		}
+		sb.WriteString("\n</behavior>")
+	case FormatPlain:
+		sb.WriteString(c.renderContent(parent.Content.Canonical))
+		for _, child := range children {
+			sb.WriteString("\n  - ")
+			sb.WriteString(c.renderChild(child.Content.Canonical))
+		}
+	default: // FormatMarkdown
+		sb.WriteString("- ")
+		sb.WriteString(c.renderContent(parent.Content.Canonical))
+		for _, child := range children {
+			sb.WriteString("\n  - ")
+			sb.WriteString(c.renderChild(child.Content.Canonical))
+		}
+	}
+
+	return sb.String()
+}
+
+// renderChild renders a nested child's content, indenting continuation lines
+// one level deeper so they stay beneath the child's bullet.
+func (c *Compiler) renderChild(s string) string {
+	return strings.ReplaceAll(c.renderContent(s), "\n", "\n  ")
+}
@@ -0,0 +1,125 @@
+package assembly
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func composeFixture() (models.Behavior, []models.Behavior) {
+	parent := models.Behavior{
+		ID: "g1", Name: "general-use-not", Kind: models.BehaviorKindPreference,
+		Content: models.BehaviorContent{Canonical: "Prefer the maintained library"},
+	}
+	children := []models.Behavior{
+		{ID: "c1", Kind: models.BehaviorKindPreference, Specializes: []string{"g1"},
+			Content: models.BehaviorContent{Canonical: "use httpx not requests"}},
+		{ID: "c2", Kind: models.BehaviorKindPreference, Specializes: []string{"g1"},
+			Content: models.BehaviorContent{Canonical: "use pathlib not os.path"}},
+		{ID: "d1", Kind: models.BehaviorKindDirective,
+			Content: models.BehaviorContent{Canonical: "Run gofmt"}},
+	}
+	return parent, children
+}
+
+func TestCompiler_Compile_ComposesChildrenUnderParent(t *testing.T) {
+	parent, behaviors := composeFixture()
+
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{FormatMarkdown, "- Prefer the maintained library\n  - use httpx not requests\n  - use pathlib not os.path"},
+		{FormatPlain, "Prefer the maintained library\n  - use httpx not requests\n  - use pathlib not os.path"},
+		{FormatXML, "<behavior kind=\"preference\">Prefer the maintained library\n  <behavior kind=\"preference\">use httpx not requests</behavior>\n  <behavior kind=\"preference\">use pathlib not os.path</behavior>\n</behavior>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			result := NewCompiler().WithFormat(tt.format).WithParents([]models.Behavior{parent}).Compile(behaviors)
+
+			if !strings.Contains(result.Text, tt.want) {
+				t.Errorf("Text missing composed block.\ngot:\n%s\nwant substring:\n%s", result.Text, tt.want)
+			}
+			if strings.Count(result.Text, "Prefer the maintained library") != 1 {
+				t.Errorf("parent should be rendered once:\n%s", result.Text)
+			}
+
+			var prefs *PromptSection
+			for i := range result.Sections {
+				if result.Sections[i].Kind == models.BehaviorKindPreference {
+					prefs = &result.Sections[i]
+				}
+			}
+			if prefs == nil {
+				t.Fatal("missing preferences section")
+			}
+			if len(prefs.Parents) != 1 || prefs.Parents[0] != "g1" {
+				t.Errorf("Parents = %v, want [g1]", prefs.Parents)
+			}
+			if len(prefs.Behaviors) != 2 {
+				t.Errorf("Behaviors = %v, want the two children", prefs.Behaviors)
+			}
+
+			found := false
+			for _, id := range result.IncludedBehaviors {
+				if id == "g1" {
+					found = true
+				}
+			}
+			if !found {
+				t.Error("IncludedBehaviors should list the rendered parent")
+			}
+		})
+	}
+}
+
+func TestCompiler_Compile_ParentInInput(t *testing.T) {
+	parent, behaviors := composeFixture()
+
+	// Without the resolver, the parent may arrive alongside its children
+	result := NewCompiler().Compile(append([]models.Behavior{parent}, behaviors...))
+
+	if strings.Count(result.Text, "Prefer the maintained library") != 1 {
+		t.Errorf("parent should be rendered once:\n%s", result.Text)
+	}
+	if !strings.Contains(result.Text, "- Prefer the maintained library\n  - use httpx not requests") {
+		t.Errorf("children should be nested under parent:\n%s", result.Text)
+	}
+}
+
+func TestCompiler_Compile_SingleChildNotComposed(t *testing.T) {
+	parent, behaviors := composeFixture()
+
+	result := NewCompiler().WithParents([]models.Behavior{parent}).Compile(behaviors[1:])
+
+	if strings.Contains(result.Text, "Prefer the maintained library") {
+		t.Errorf("a lone child should render without its parent:\n%s", result.Text)
+	}
+	if !strings.Contains(result.Text, "- use pathlib not os.path") {
+		t.Errorf("child missing:\n%s", result.Text)
+	}
+}
+
+func TestCompiler_CompileTiered_GroupHeaders(t *testing.T) {
+	parent, behaviors := composeFixture()
+
+	plan := &models.InjectionPlan{
+		GroupHeaders: []models.InjectedBehavior{{Behavior: &parent, Tier: models.TierFull}},
+	}
+	for i := range behaviors {
+		plan.FullBehaviors = append(plan.FullBehaviors, models.InjectedBehavior{Behavior: &behaviors[i], Tier: models.TierFull})
+	}
+
+	compiler := NewCompiler()
+	result := compiler.CompileTiered(plan)
+	if !strings.Contains(result.Text, "- Prefer the maintained library\n  - use httpx not requests") {
+		t.Errorf("group header not composed:\n%s", result.Text)
+	}
+
+	// Headers from one plan must not leak into later compiles
+	if plain := compiler.Compile(behaviors); strings.Contains(plain.Text, "Prefer the maintained library") {
+		t.Errorf("compiler kept plan headers:\n%s", plain.Text)
+	}
+}
@@ -1,14 +1,110 @@
 package assembly
 
 import (
+	"fmt"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/nvandessel/floop/internal/models"
 )
 
+// PackingStrategy selects the order in which an Optimizer fills its token
+// budget once pinned and always-included behaviors are in.
+type PackingStrategy string
+
+const (
+	// PackImportance takes constraints first, then higher priority, then
+	// higher confidence (default).
+	PackImportance PackingStrategy = "importance"
+
+	// PackValue takes the behaviors of highest value first, where value is
+	// activation score × confidence.
+	PackValue PackingStrategy = "value"
+)
+
+// ValidPackingStrategies lists all supported packing strategies.
+var ValidPackingStrategies = []PackingStrategy{PackImportance, PackValue}
+
+// ParsePackingStrategy converts a string to a PackingStrategy.
+// An empty string maps to PackImportance.
+func ParsePackingStrategy(s string) (PackingStrategy, error) {
+	if s == "" {
+		return PackImportance, nil
+	}
+	for _, valid := range ValidPackingStrategies {
+		if PackingStrategy(s) == valid {
+			return valid, nil
+		}
+	}
+	return "", fmt.Errorf("invalid packing strategy: %s (valid: importance, value)", s)
+}
+
+// KindBudget limits what one behavior kind takes of a token budget.
+type KindBudget struct {
+	// Always includes every behavior of the kind, even past the budget.
+	Always bool
+
+	// Share caps the kind at this fraction of the budget (0-1). Zero
+	// leaves the kind limited only by the budget itself.
+	Share float64
+}
+
+// budgetKinds are the behavior kinds a budget may name.
+var budgetKinds = map[models.BehaviorKind]bool{
+	models.BehaviorKindDirective:  true,
+	models.BehaviorKindConstraint: true,
+	models.BehaviorKindProcedure:  true,
+	models.BehaviorKindPreference: true,
+	models.BehaviorKindEpisodic:   true,
+	models.BehaviorKindWorkflow:   true,
+}
+
+// ParseKindBudgets parses per-kind budgets keyed by behavior kind. Each
+// value is "always", a fraction such as "0.3", or a percentage such as
+// "30%".
+func ParseKindBudgets(budgets map[string]string) (map[models.BehaviorKind]KindBudget, error) {
+	if len(budgets) == 0 {
+		return nil, nil
+	}
+	parsed := make(map[models.BehaviorKind]KindBudget, len(budgets))
+	for kind, value := range budgets {
+		if !budgetKinds[models.BehaviorKind(kind)] {
+			return nil, fmt.Errorf("invalid budget kind: %s (valid: directive, constraint, procedure, preference, episodic, workflow)", kind)
+		}
+		budget, err := parseKindBudget(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid budget for %s: %w", kind, err)
+		}
+		parsed[models.BehaviorKind(kind)] = budget
+	}
+	return parsed, nil
+}
+
+func parseKindBudget(value string) (KindBudget, error) {
+	value = strings.TrimSpace(value)
+	if value == "always" {
+		return KindBudget{Always: true}, nil
+	}
+	share, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil {
+		return KindBudget{}, fmt.Errorf("%q is not always, a fraction, or a percentage", value)
+	}
+	if strings.HasSuffix(value, "%") {
+		share /= 100
+	}
+	if share <= 0 || share > 1 {
+		return KindBudget{}, fmt.Errorf("share %q must be above 0 and at most 100%%", value)
+	}
+	return KindBudget{Share: share}, nil
+}
+
 // Optimizer handles token budget management for behavior compilation
 type Optimizer struct {
 	maxTokens int
+	budgets   map[models.BehaviorKind]KindBudget
+	packing   PackingStrategy
+	scores    map[string]float64
 }
 
 // OptimizationResult contains the result of token optimization
@@ -33,11 +129,35 @@ type OptimizationResult struct {
 func NewOptimizer(maxTokens int) *Optimizer {
 	return &Optimizer{
 		maxTokens: maxTokens,
+		packing:   PackImportance,
 	}
 }
 
-// Optimize selects behaviors that fit within the token budget
-// Prioritizes by: constraints first, then by priority, then by confidence
+// WithKindBudgets sets per-kind budgets: kinds marked Always are included
+// in full, and kinds with a Share may use at most that part of the budget.
+func (o *Optimizer) WithKindBudgets(budgets map[models.BehaviorKind]KindBudget) *Optimizer {
+	o.budgets = budgets
+	return o
+}
+
+// WithPacking sets the order in which the budget is filled.
+func (o *Optimizer) WithPacking(packing PackingStrategy) *Optimizer {
+	o.packing = packing
+	return o
+}
+
+// WithScores sets the activation score of each behavior by ID, for
+// PackValue. A behavior without a score counts as fully activated.
+func (o *Optimizer) WithScores(scores map[string]float64) *Optimizer {
+	o.scores = scores
+	return o
+}
+
+// Optimize selects behaviors that fit within the token budget. Pinned
+// behaviors and those of kinds budgeted Always are included first, even
+// past the budget. The rest fill what remains in the packing order,
+// skipping any that would exceed the budget or their kind's share. The
+// default order is constraints first, then by priority, then by confidence.
 func (o *Optimizer) Optimize(behaviors []models.Behavior) OptimizationResult {
 	if o.maxTokens <= 0 {
 		// No limit - include all
@@ -50,24 +170,47 @@ func (o *Optimizer) Optimize(behaviors []models.Behavior) OptimizationResult {
 		}
 	}
 
-	// Sort behaviors by importance
+	// Sort behaviors in packing order
 	sorted := make([]models.Behavior, len(behaviors))
 	copy(sorted, behaviors)
-	o.sortByImportance(sorted)
+	if o.packing == PackValue {
+		o.sortByValue(sorted)
+	} else {
+		o.sortByImportance(sorted)
+	}
 
 	var included []models.Behavior
 	var excluded []models.Behavior
 	tokensUsed := 0
+	kindTokens := make(map[models.BehaviorKind]int)
 
 	// Estimate overhead for formatting (headers, etc.)
 	overhead := 50 // Rough estimate for markdown headers
 
+	// Pinned and always-included behaviors go in regardless of budget
+	var rest []models.Behavior
 	for _, b := range sorted {
+		if b.Pinned || o.budgets[b.Kind].Always {
+			tokenCost := o.estimateBehaviorTokens(b)
+			included = append(included, b)
+			tokensUsed += tokenCost
+			kindTokens[b.Kind] += tokenCost
+		} else {
+			rest = append(rest, b)
+		}
+	}
+
+	for _, b := range rest {
 		tokenCost := o.estimateBehaviorTokens(b)
+		fits := tokensUsed+tokenCost+overhead <= o.maxTokens
+		if share := o.budgets[b.Kind].Share; share > 0 && float64(kindTokens[b.Kind]+tokenCost) > share*float64(o.maxTokens) {
+			fits = false
+		}
 
-		if tokensUsed+tokenCost+overhead <= o.maxTokens {
+		if fits {
 			included = append(included, b)
 			tokensUsed += tokenCost
+			kindTokens[b.Kind] += tokenCost
 		} else {
 			excluded = append(excluded, b)
 		}
@@ -116,6 +259,27 @@ func (o *Optimizer) sortByImportance(behaviors []models.Behavior) {
 	})
 }
 
+// sortByValue orders behaviors by activation score × confidence, highest
+// first, falling back to importance on ties.
+func (o *Optimizer) sortByValue(behaviors []models.Behavior) {
+	sort.SliceStable(behaviors, func(i, j int) bool {
+		vi, vj := o.value(behaviors[i]), o.value(behaviors[j])
+		if vi != vj {
+			return vi > vj
+		}
+		return o.compareImportance(behaviors[i], behaviors[j])
+	})
+}
+
+// value is a behavior's activation score × confidence.
+func (o *Optimizer) value(b models.Behavior) float64 {
+	score, ok := o.scores[b.ID]
+	if !ok {
+		score = 1
+	}
+	return score * b.Confidence
+}
+
 // estimateBehaviorTokens estimates tokens for a single behavior
 func (o *Optimizer) estimateBehaviorTokens(b models.Behavior) int {
 	// Use canonical content for estimation
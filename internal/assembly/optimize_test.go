@@ -1,6 +1,9 @@
 package assembly
 
 import (
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/nvandessel/floop/internal/models"
@@ -172,3 +175,160 @@ func TestOptimizer_Optimize_Empty(t *testing.T) {
 		t.Error("expected not truncated for empty input")
 	}
 }
+
+func TestParsePackingStrategy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    PackingStrategy
+		wantErr bool
+	}{
+		{"", PackImportance, false},
+		{"importance", PackImportance, false},
+		{"value", PackValue, false},
+		{"insertion", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePackingStrategy(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParsePackingStrategy(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseKindBudgets(t *testing.T) {
+	got, err := ParseKindBudgets(map[string]string{"constraint": "always", "preference": "30%", "directive": "0.5"})
+	if err != nil {
+		t.Fatalf("ParseKindBudgets() error = %v", err)
+	}
+	want := map[models.BehaviorKind]KindBudget{
+		models.BehaviorKindConstraint: {Always: true},
+		models.BehaviorKindPreference: {Share: 0.3},
+		models.BehaviorKindDirective:  {Share: 0.5},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseKindBudgets() = %+v, want %+v", got, want)
+	}
+
+	for _, bad := range []map[string]string{
+		{"rule": "always"},
+		{"directive": "most"},
+		{"directive": "0"},
+		{"directive": "150%"},
+	} {
+		if _, err := ParseKindBudgets(bad); err == nil {
+			t.Errorf("ParseKindBudgets(%v) succeeded, want an error", bad)
+		}
+	}
+}
+
+// budgetBehavior returns a behavior whose content costs about words tokens.
+func budgetBehavior(id string, kind models.BehaviorKind, words int) models.Behavior {
+	return models.Behavior{
+		ID:         id,
+		Kind:       kind,
+		Confidence: 0.5,
+		Content:    models.BehaviorContent{Canonical: strings.TrimSpace(strings.Repeat("word ", words))},
+	}
+}
+
+func includedIDs(result OptimizationResult) []string {
+	var ids []string
+	for _, b := range result.Included {
+		ids = append(ids, b.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestOptimizer_Optimize_Pinned(t *testing.T) {
+	big := budgetBehavior("big", models.BehaviorKindPreference, 400)
+	big.Pinned = true
+	behaviors := []models.Behavior{
+		budgetBehavior("constraint", models.BehaviorKindConstraint, 10),
+		budgetBehavior("directive", models.BehaviorKindDirective, 40),
+		big,
+	}
+
+	// The pinned behavior goes in first, leaving room for the constraint only
+	o := NewOptimizer(0)
+	budget := o.estimateBehaviorTokens(big) + o.estimateBehaviorTokens(behaviors[0]) + 60
+	result := NewOptimizer(budget).Optimize(behaviors)
+	if got := includedIDs(result); !reflect.DeepEqual(got, []string{"big", "constraint"}) {
+		t.Errorf("included = %v, want [big constraint]", got)
+	}
+
+	// Even past the budget
+	result = NewOptimizer(100).Optimize(behaviors)
+	if got := includedIDs(result); !reflect.DeepEqual(got, []string{"big"}) {
+		t.Errorf("included = %v, want [big]", got)
+	}
+	if result.TokensUsed <= result.TokensAvailable {
+		t.Errorf("TokensUsed = %d, want the pinned behavior past the %d budget", result.TokensUsed, result.TokensAvailable)
+	}
+}
+
+func TestOptimizer_Optimize_KindBudgets(t *testing.T) {
+	o := NewOptimizer(400)
+	behaviors := []models.Behavior{
+		budgetBehavior("c1", models.BehaviorKindConstraint, 150),
+		budgetBehavior("c2", models.BehaviorKindConstraint, 150),
+		budgetBehavior("p1", models.BehaviorKindPreference, 40),
+		budgetBehavior("p2", models.BehaviorKindPreference, 40),
+		budgetBehavior("d1", models.BehaviorKindDirective, 10),
+	}
+	behaviors[1].Confidence = 0.4
+	pref := o.estimateBehaviorTokens(behaviors[2])
+
+	tests := []struct {
+		name    string
+		budgets map[models.BehaviorKind]KindBudget
+		want    []string
+	}{
+		{"no budgets", nil, []string{"c1", "d1", "p1", "p2"}},
+		{
+			"constraints always, the rest fill what remains",
+			map[models.BehaviorKind]KindBudget{models.BehaviorKindConstraint: {Always: true}},
+			[]string{"c1", "c2"},
+		},
+		{
+			"share caps a kind",
+			map[models.BehaviorKind]KindBudget{
+				models.BehaviorKindConstraint: {Share: 0.5},
+				models.BehaviorKindPreference: {Share: float64(pref+1) / 400},
+			},
+			[]string{"c1", "d1", "p1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewOptimizer(400).WithKindBudgets(tt.budgets).Optimize(behaviors)
+			if got := includedIDs(result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("included = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptimizer_Optimize_PackValue(t *testing.T) {
+	behaviors := []models.Behavior{
+		budgetBehavior("constraint", models.BehaviorKindConstraint, 60),
+		budgetBehavior("hot", models.BehaviorKindPreference, 60),
+		budgetBehavior("cold", models.BehaviorKindDirective, 60),
+	}
+	behaviors[1].Confidence = 0.9
+	behaviors[2].Confidence = 0.9
+	scores := map[string]float64{"constraint": 0.2, "hot": 0.9, "cold": 0.1}
+
+	// Two of the three fit
+	budget := 2*NewOptimizer(0).estimateBehaviorTokens(behaviors[0]) + 60
+
+	byImportance := NewOptimizer(budget).WithScores(scores).Optimize(behaviors)
+	if got := includedIDs(byImportance); !reflect.DeepEqual(got, []string{"cold", "constraint"}) {
+		t.Errorf("importance included = %v, want [cold constraint]", got)
+	}
+
+	byValue := NewOptimizer(budget).WithPacking(PackValue).WithScores(scores).Optimize(behaviors)
+	if got := includedIDs(byValue); !reflect.DeepEqual(got, []string{"constraint", "hot"}) {
+		t.Errorf("value included = %v, want [constraint hot]", got)
+	}
+}
@@ -0,0 +1,263 @@
+package assembly
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// OrderStrategy controls how the Compiler arranges behaviors into sections.
+// Agents follow instructions near the top of a prompt more reliably, so the
+// strategy decides what leads.
+type OrderStrategy string
+
+const (
+	// OrderByKind groups behaviors into per-kind sections in a fixed order
+	// (constraints, directives, preferences, procedures). This is the default.
+	OrderByKind OrderStrategy = "kind"
+
+	// OrderConstraintsFirst emits constraints in their own leading section,
+	// followed by every other behavior in a single section ordered by
+	// priority and confidence regardless of kind.
+	OrderConstraintsFirst OrderStrategy = "constraints-first"
+
+	// OrderGroupByTag groups behaviors into one section per primary tag
+	// (the first tag), sorted alphabetically. Untagged behaviors go last.
+	OrderGroupByTag OrderStrategy = "group-by-tag"
+
+	// OrderTaskRelevantFirst leads with behaviors whose when-condition
+	// explicitly names the current task, then falls back to kind sections.
+	OrderTaskRelevantFirst OrderStrategy = "task-relevant-first"
+
+	// OrderAlphabetical emits a single section sorted by behavior name.
+	OrderAlphabetical OrderStrategy = "alphabetical"
+)
+
+// ValidOrderStrategies lists the accepted ordering strategy names.
+var ValidOrderStrategies = []OrderStrategy{
+	OrderByKind,
+	OrderConstraintsFirst,
+	OrderGroupByTag,
+	OrderTaskRelevantFirst,
+	OrderAlphabetical,
+}
+
+// ParseOrderStrategy converts a string to an OrderStrategy.
+// An empty string maps to OrderByKind.
+func ParseOrderStrategy(s string) (OrderStrategy, error) {
+	if s == "" {
+		return OrderByKind, nil
+	}
+	for _, valid := range ValidOrderStrategies {
+		if OrderStrategy(s) == valid {
+			return valid, nil
+		}
+	}
+	names := make([]string, len(ValidOrderStrategies))
+	for i, v := range ValidOrderStrategies {
+		names[i] = string(v)
+	}
+	return "", fmt.Errorf("invalid ordering strategy: %s (valid: %s)", s, strings.Join(names, ", "))
+}
+
+// kindOrder defines the order of per-kind sections (constraints first as
+// they're most important).
+var kindOrder = []models.BehaviorKind{
+	models.BehaviorKindConstraint,
+	models.BehaviorKindDirective,
+	models.BehaviorKindPreference,
+	models.BehaviorKindProcedure,
+}
+
+// sectionGroup is an ordered set of behaviors rendered under one title.
+// When composed is set, it replaces behaviors as the rendering order.
+type sectionGroup struct {
+	kind      models.BehaviorKind
+	tag       string
+	title     string
+	behaviors []models.Behavior
+	composed  []sectionItem
+}
+
+// arrange splits behaviors into ordered section groups according to the
+// compiler's ordering strategy.
+func (c *Compiler) arrange(behaviors []models.Behavior) []sectionGroup {
+	switch c.ordering {
+	case OrderConstraintsFirst:
+		return c.arrangeConstraintsFirst(behaviors)
+	case OrderGroupByTag:
+		return c.arrangeByTag(behaviors)
+	case OrderTaskRelevantFirst:
+		return c.arrangeTaskRelevantFirst(behaviors)
+	case OrderAlphabetical:
+		return c.arrangeAlphabetical(behaviors)
+	default:
+		return c.arrangeByKind(behaviors)
+	}
+}
+
+// arrangeByKind groups behaviors into the fixed per-kind section order.
+func (c *Compiler) arrangeByKind(behaviors []models.Behavior) []sectionGroup {
+	grouped := c.groupByKind(behaviors)
+
+	var groups []sectionGroup
+	for _, kind := range kindOrder {
+		bs, exists := grouped[kind]
+		if !exists || len(bs) == 0 {
+			continue
+		}
+		groups = append(groups, sectionGroup{kind: kind, title: c.kindTitle(kind), behaviors: bs})
+	}
+	return groups
+}
+
+// arrangeConstraintsFirst emits constraints, then all remaining behaviors
+// in one section ordered by priority and confidence.
+func (c *Compiler) arrangeConstraintsFirst(behaviors []models.Behavior) []sectionGroup {
+	var constraints, rest []models.Behavior
+	for _, b := range behaviors {
+		if b.Kind == models.BehaviorKindConstraint {
+			constraints = append(constraints, b)
+		} else {
+			rest = append(rest, b)
+		}
+	}
+	sortByPriorityAndConfidence(constraints)
+	sortByPriorityAndConfidence(rest)
+
+	var groups []sectionGroup
+	if len(constraints) > 0 {
+		groups = append(groups, sectionGroup{
+			kind:      models.BehaviorKindConstraint,
+			title:     c.kindTitle(models.BehaviorKindConstraint),
+			behaviors: constraints,
+		})
+	}
+	if len(rest) > 0 {
+		groups = append(groups, sectionGroup{title: "Guidance", behaviors: rest})
+	}
+	return groups
+}
+
+// arrangeByTag groups behaviors by their primary (first) tag.
+func (c *Compiler) arrangeByTag(behaviors []models.Behavior) []sectionGroup {
+	byTag := make(map[string][]models.Behavior)
+	var untagged []models.Behavior
+	for _, b := range behaviors {
+		if len(b.Content.Tags) == 0 || b.Content.Tags[0] == "" {
+			untagged = append(untagged, b)
+			continue
+		}
+		tag := b.Content.Tags[0]
+		byTag[tag] = append(byTag[tag], b)
+	}
+
+	tags := make([]string, 0, len(byTag))
+	for tag := range byTag {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	groups := make([]sectionGroup, 0, len(tags)+1)
+	for _, tag := range tags {
+		bs := byTag[tag]
+		sortByPriorityAndConfidence(bs)
+		groups = append(groups, sectionGroup{tag: tag, title: tag, behaviors: bs})
+	}
+	if len(untagged) > 0 {
+		sortByPriorityAndConfidence(untagged)
+		groups = append(groups, sectionGroup{title: "Other", behaviors: untagged})
+	}
+	return groups
+}
+
+// arrangeTaskRelevantFirst leads with behaviors whose when.task matches the
+// compiler's task, followed by the remaining behaviors in kind order.
+// Without a task this is equivalent to OrderByKind.
+func (c *Compiler) arrangeTaskRelevantFirst(behaviors []models.Behavior) []sectionGroup {
+	if c.task == "" {
+		return c.arrangeByKind(behaviors)
+	}
+
+	var relevant, rest []models.Behavior
+	for _, b := range behaviors {
+		if namesTask(b.When, c.task) {
+			relevant = append(relevant, b)
+		} else {
+			rest = append(rest, b)
+		}
+	}
+	sortByPriorityAndConfidence(relevant)
+
+	var groups []sectionGroup
+	if len(relevant) > 0 {
+		groups = append(groups, sectionGroup{title: "Current Task", behaviors: relevant})
+	}
+	return append(groups, c.arrangeByKind(rest)...)
+}
+
+// arrangeAlphabetical emits a single section sorted by name, then ID.
+func (c *Compiler) arrangeAlphabetical(behaviors []models.Behavior) []sectionGroup {
+	sorted := make([]models.Behavior, len(behaviors))
+	copy(sorted, behaviors)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ni, nj := strings.ToLower(sorted[i].Name), strings.ToLower(sorted[j].Name)
+		if ni != nj {
+			return ni < nj
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+	return []sectionGroup{{title: "Behaviors", behaviors: sorted}}
+}
+
+// namesTask reports whether a when-condition explicitly targets the task.
+func namesTask(when map[string]interface{}, task string) bool {
+	switch v := when["task"].(type) {
+	case string:
+		return v == task
+	case []string:
+		for _, t := range v {
+			if t == task {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, t := range v {
+			if s, ok := t.(string); ok && s == task {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sortByPriorityAndConfidence sorts behaviors by priority (descending)
+// then confidence (descending).
+func sortByPriorityAndConfidence(behaviors []models.Behavior) {
+	sort.SliceStable(behaviors, func(i, j int) bool {
+		if behaviors[i].Priority != behaviors[j].Priority {
+			return behaviors[i].Priority > behaviors[j].Priority
+		}
+		return behaviors[i].Confidence > behaviors[j].Confidence
+	})
+}
+
+// sectionElement returns an XML-safe element name for a section title.
+func sectionElement(title string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('-')
+		}
+	}
+	name := strings.Trim(sb.String(), "-")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "section-" + name
+	}
+	return name
+}
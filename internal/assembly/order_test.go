@@ -0,0 +1,172 @@
+package assembly
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func orderingFixture() []models.Behavior {
+	return []models.Behavior{
+		{
+			ID:       "d1",
+			Name:     "wrap-errors",
+			Kind:     models.BehaviorKindDirective,
+			Priority: 1,
+			Content:  models.BehaviorContent{Canonical: "Wrap errors with context", Tags: []string{"errors"}},
+		},
+		{
+			ID:      "c1",
+			Name:    "no-secrets",
+			Kind:    models.BehaviorKindConstraint,
+			Content: models.BehaviorContent{Canonical: "Never commit secrets", Tags: []string{"security"}},
+		},
+		{
+			ID:       "p1",
+			Name:     "table-tests",
+			Kind:     models.BehaviorKindPreference,
+			Priority: 5,
+			When:     map[string]interface{}{"task": "testing"},
+			Content:  models.BehaviorContent{Canonical: "Prefer table-driven tests", Tags: []string{"testing"}},
+		},
+		{
+			ID:      "d2",
+			Name:    "Always-gofmt",
+			Kind:    models.BehaviorKindDirective,
+			When:    map[string]interface{}{"task": []interface{}{"development", "testing"}},
+			Content: models.BehaviorContent{Canonical: "Run gofmt"},
+		},
+	}
+}
+
+func sectionTitles(p *CompiledPrompt) []string {
+	titles := make([]string, len(p.Sections))
+	for i, s := range p.Sections {
+		titles[i] = s.Title
+	}
+	return titles
+}
+
+func TestParseOrderStrategy(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    OrderStrategy
+		wantErr bool
+	}{
+		{"", OrderByKind, false},
+		{"kind", OrderByKind, false},
+		{"constraints-first", OrderConstraintsFirst, false},
+		{"group-by-tag", OrderGroupByTag, false},
+		{"task-relevant-first", OrderTaskRelevantFirst, false},
+		{"alphabetical", OrderAlphabetical, false},
+		{"random", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseOrderStrategy(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOrderStrategy(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseOrderStrategy(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompiler_Ordering_Sections(t *testing.T) {
+	tests := []struct {
+		name     string
+		ordering OrderStrategy
+		task     string
+		want     []string
+	}{
+		{"kind", OrderByKind, "", []string{"Constraints", "Directives", "Preferences"}},
+		{"constraints-first", OrderConstraintsFirst, "", []string{"Constraints", "Guidance"}},
+		{"group-by-tag", OrderGroupByTag, "", []string{"errors", "security", "testing", "Other"}},
+		{"task-relevant-first", OrderTaskRelevantFirst, "testing", []string{"Current Task", "Constraints", "Directives"}},
+		{"task-relevant-first without task", OrderTaskRelevantFirst, "", []string{"Constraints", "Directives", "Preferences"}},
+		{"alphabetical", OrderAlphabetical, "", []string{"Behaviors"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewCompiler().WithOrdering(tt.ordering).WithTask(tt.task).Compile(orderingFixture())
+			got := sectionTitles(result)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("sections = %v, want %v", got, tt.want)
+			}
+			if len(result.IncludedBehaviors) != 4 {
+				t.Errorf("expected 4 included behaviors, got %d", len(result.IncludedBehaviors))
+			}
+		})
+	}
+}
+
+func TestCompiler_Ordering_ConstraintsFirstMixesKinds(t *testing.T) {
+	result := NewCompiler().WithOrdering(OrderConstraintsFirst).Compile(orderingFixture())
+	guidance := result.Sections[1]
+	// Priority 5 preference leads, then priority 1 directive, then priority 0 directive
+	want := []string{"p1", "d1", "d2"}
+	if strings.Join(guidance.Behaviors, ",") != strings.Join(want, ",") {
+		t.Errorf("guidance order = %v, want %v", guidance.Behaviors, want)
+	}
+}
+
+func TestCompiler_Ordering_TaskRelevantFirst(t *testing.T) {
+	result := NewCompiler().WithOrdering(OrderTaskRelevantFirst).WithTask("testing").Compile(orderingFixture())
+	lead := result.Sections[0]
+	if strings.Join(lead.Behaviors, ",") != "p1,d2" {
+		t.Errorf("task section = %v, want [p1 d2]", lead.Behaviors)
+	}
+	if strings.Index(result.Text, "Prefer table-driven tests") > strings.Index(result.Text, "Never commit secrets") {
+		t.Error("expected task-relevant behavior before constraints")
+	}
+}
+
+func TestCompiler_Ordering_Alphabetical(t *testing.T) {
+	result := NewCompiler().WithOrdering(OrderAlphabetical).Compile(orderingFixture())
+	want := "d2,c1,p1,d1" // Always-gofmt, no-secrets, table-tests, wrap-errors
+	if got := strings.Join(result.Sections[0].Behaviors, ","); got != want {
+		t.Errorf("alphabetical order = %s, want %s", got, want)
+	}
+}
+
+func TestCompiler_Ordering_GroupByTagSetsTag(t *testing.T) {
+	result := NewCompiler().WithOrdering(OrderGroupByTag).Compile(orderingFixture())
+	if result.Sections[0].Tag != "errors" {
+		t.Errorf("expected first section tag 'errors', got %q", result.Sections[0].Tag)
+	}
+	if result.Sections[len(result.Sections)-1].Tag != "" {
+		t.Error("expected untagged section to have empty tag")
+	}
+}
+
+func TestCompiler_Ordering_XMLElementNames(t *testing.T) {
+	result := NewCompiler().
+		WithFormat(FormatXML).
+		WithOrdering(OrderTaskRelevantFirst).
+		WithTask("testing").
+		Compile(orderingFixture())
+	if !strings.Contains(result.Text, "<current-task>") || !strings.Contains(result.Text, "</current-task>") {
+		t.Errorf("expected XML-safe section element, got:\n%s", result.Text)
+	}
+}
+
+func TestSectionElement(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Constraints", "constraints"},
+		{"Current Task", "current-task"},
+		{"testing/unit", "testing-unit"},
+		{"2fa", "section-2fa"},
+		{"<>", "section-"},
+	}
+	for _, tt := range tests {
+		if got := sectionElement(tt.title); got != tt.want {
+			t.Errorf("sectionElement(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+// Package authored loads hand-written behavior definitions from
+// .floop/behaviors/*.yaml and keeps the project store in step with them.
+//
+// Definition files are meant to be committed and reviewed like code. 'floop
+// apply' validates them, then creates, updates, and (with --prune) forgets
+// store behaviors so the store matches the files. Each applied behavior
+// records the file it came from and a hash of the definition, so a behavior
+// later changed in the store instead of its file is reported as drift.
+package authored
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Dir is the directory, relative to the project root, holding definition
+// files.
+const Dir = ".floop/behaviors"
+
+// idPattern is the accepted form of a definition ID.
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// validKinds are the behavior kinds a definition may declare.
+var validKinds = map[models.BehaviorKind]bool{
+	models.BehaviorKindDirective:  true,
+	models.BehaviorKindConstraint: true,
+	models.BehaviorKindProcedure:  true,
+	models.BehaviorKindPreference: true,
+	models.BehaviorKindEpisodic:   true,
+	models.BehaviorKindWorkflow:   true,
+}
+
+// File is the layout of one definition file.
+type File struct {
+	Behaviors []Definition `yaml:"behaviors"`
+}
+
+// Definition is one hand-authored behavior.
+type Definition struct {
+	ID        string                 `yaml:"id" json:"id"`
+	Name      string                 `yaml:"name,omitempty" json:"name,omitempty"`             // Default: the ID
+	Kind      string                 `yaml:"kind,omitempty" json:"kind,omitempty"`             // Default: directive
+	Content   string                 `yaml:"content" json:"content"`                           // Canonical text
+	Summary   string                 `yaml:"summary,omitempty" json:"summary,omitempty"`       // One-line reminder for tiered injection
+	Tags      []string               `yaml:"tags,omitempty" json:"tags,omitempty"`             // Keyword tags
+	When      map[string]interface{} `yaml:"when,omitempty" json:"when,omitempty"`             // Activation conditions
+	Priority  int                    `yaml:"priority,omitempty" json:"priority,omitempty"`     // Conflict resolution priority
+	ExpiresAt string                 `yaml:"expires_at,omitempty" json:"expires_at,omitempty"` // RFC 3339 time or YYYY-MM-DD date
+	Pinned    bool                   `yaml:"pinned,omitempty" json:"pinned,omitempty"`         // Always include in compiled prompts
+
+	// File is the definition file, relative to the project root. Set on load.
+	File string `yaml:"-" json:"-"`
+}
+
+// ValidationError collects every problem found in the definition files.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid behavior definitions:\n  %s", strings.Join(e.Problems, "\n  "))
+}
+
+// Load reads and validates every *.yaml and *.yml file under projectRoot's
+// .floop/behaviors, in name order. A missing directory yields no
+// definitions. All problems are reported together as a *ValidationError.
+func Load(projectRoot string) ([]Definition, error) {
+	dir := filepath.Join(projectRoot, Dir)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", Dir, err)
+	}
+
+	var defs []Definition
+	var problems []string
+	seen := make(map[string]string)
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		rel := filepath.ToSlash(filepath.Join(Dir, entry.Name()))
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+		fileDefs, err := parseFile(data)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", rel, err))
+			continue
+		}
+		for i, def := range fileDefs {
+			def.File = rel
+			for _, p := range def.validate() {
+				problems = append(problems, fmt.Sprintf("%s: behaviors[%d]: %s", rel, i, p))
+			}
+			if def.ID != "" {
+				if prev, ok := seen[def.ID]; ok {
+					problems = append(problems, fmt.Sprintf("%s: behaviors[%d]: id %q is already defined in %s", rel, i, def.ID, prev))
+					continue
+				}
+				seen[def.ID] = rel
+			}
+			defs = append(defs, def)
+		}
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	return defs, nil
+}
+
+// parseFile decodes a definition file, rejecting unknown fields.
+func parseFile(data []byte) ([]Definition, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var f File
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return f.Behaviors, nil
+}
+
+// validate returns the problems with a single definition.
+func (d Definition) validate() []string {
+	var problems []string
+	switch {
+	case d.ID == "":
+		problems = append(problems, "id is required")
+	case !idPattern.MatchString(d.ID):
+		problems = append(problems, fmt.Sprintf("id %q must be lowercase letters, digits, '.', '_', or '-'", d.ID))
+	}
+	if strings.TrimSpace(d.Content) == "" {
+		problems = append(problems, "content is required")
+	}
+	if d.Kind != "" && !validKinds[models.BehaviorKind(d.Kind)] {
+		problems = append(problems, fmt.Sprintf("unknown kind %q (valid: directive, constraint, procedure, preference, episodic, workflow)", d.Kind))
+	}
+	for _, tag := range d.Tags {
+		if strings.TrimSpace(tag) == "" {
+			problems = append(problems, "tags cannot be empty")
+			break
+		}
+	}
+	if d.When != nil {
+		if err := models.ValidateWhen(normalizeWhen(d.When)); err != nil {
+			problems = append(problems, fmt.Sprintf("when: %v", err))
+		}
+	}
+	if d.ExpiresAt != "" {
+		if _, err := parseExpiry(d.ExpiresAt); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	return problems
+}
+
+// normalized returns the definition with defaults filled in and values in
+// the form the store holds them, so definitions read from files and from
+// store nodes compare equal when they say the same thing.
+func (d Definition) normalized() Definition {
+	n := d
+	if n.Name == "" {
+		n.Name = n.ID
+	}
+	if n.Kind == "" {
+		n.Kind = string(models.BehaviorKindDirective)
+	}
+	n.Content = strings.TrimSpace(n.Content)
+	n.Summary = strings.TrimSpace(n.Summary)
+	if len(n.Tags) == 0 {
+		n.Tags = nil
+	} else {
+		n.Tags = append([]string(nil), n.Tags...)
+		sort.Strings(n.Tags)
+	}
+	if len(n.When) == 0 {
+		n.When = nil
+	} else {
+		n.When = normalizeWhen(n.When)
+	}
+	if t, err := parseExpiry(n.ExpiresAt); err == nil && n.ExpiresAt != "" {
+		n.ExpiresAt = t.UTC().Format(time.RFC3339)
+	}
+	return n
+}
+
+// Hash identifies the normalized definition, ignoring the file it is in.
+func (d Definition) Hash() string {
+	n := d.normalized()
+	n.File = ""
+	data, _ := json.Marshal(n)
+	return contentHash(data)
+}
+
+// normalizeWhen round-trips when-conditions through JSON, the form they
+// take in the store: numbers become float64. YAML reads an unquoted
+// 2026-05-15 as a timestamp, so times become strings first.
+func normalizeWhen(when map[string]interface{}) map[string]interface{} {
+	data, err := json.Marshal(timesToStrings(when))
+	if err != nil {
+		return when
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return when
+	}
+	return out
+}
+
+// timesToStrings replaces time.Time values in v with their YAML text: a
+// date for midnight UTC, RFC 3339 otherwise.
+func timesToStrings(v interface{}) interface{} {
+	switch val := v.(type) {
+	case time.Time:
+		if val.Equal(val.Truncate(24 * time.Hour)) {
+			return val.UTC().Format("2006-01-02")
+		}
+		return val.Format(time.RFC3339)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = timesToStrings(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = timesToStrings(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// parseExpiry parses an expires_at value: an RFC 3339 time, or a date that
+// expires at the start of that day (UTC).
+func parseExpiry(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("expires_at %q must be an RFC 3339 time or a YYYY-MM-DD date", s)
+}
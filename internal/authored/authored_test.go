@@ -0,0 +1,107 @@
+package authored
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeDefinitions writes name under dir's .floop/behaviors.
+func writeDefinitions(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, Dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if defs, err := Load(dir); err != nil || defs != nil {
+		t.Fatalf("Load() without a directory = %v, %v; want nil, nil", defs, err)
+	}
+
+	writeDefinitions(t, dir, "go.yaml", `
+behaviors:
+  - id: wrap-errors
+    content: Wrap errors with context
+    when:
+      language: go
+  - id: table-tests
+    kind: preference
+    content: Prefer table-driven tests
+    tags: [go, testing]
+`)
+	writeDefinitions(t, dir, "release.yml", `
+behaviors:
+  - id: freeze
+    kind: constraint
+    content: No schema changes during the freeze
+    expires_at: 2026-12-31
+    when:
+      date: {before: 2026-12-31}
+`)
+	writeDefinitions(t, dir, "notes.txt", "not a definition file")
+
+	defs, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(defs) != 3 || defs[0].ID != "wrap-errors" || defs[2].ID != "freeze" {
+		t.Fatalf("Load() = %+v, want wrap-errors, table-tests, freeze", defs)
+	}
+	if defs[2].File != ".floop/behaviors/release.yml" {
+		t.Errorf("File = %q", defs[2].File)
+	}
+	if n := defs[2].normalized(); n.ExpiresAt != "2026-12-31T00:00:00Z" || n.When["date"].(map[string]interface{})["before"] != "2026-12-31" {
+		t.Errorf("normalized() = %+v", n)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"missing id", "behaviors:\n  - content: x\n", "behaviors[0]: id is required"},
+		{"bad id", "behaviors:\n  - id: Wrap Errors\n    content: x\n", "must be lowercase"},
+		{"missing content", "behaviors:\n  - id: a\n", "content is required"},
+		{"unknown kind", "behaviors:\n  - id: a\n    kind: rule\n    content: x\n", `unknown kind "rule"`},
+		{"bad when", "behaviors:\n  - id: a\n    content: x\n    when: {branch: {regex: \"(\"}}\n", "when: branch: invalid regex"},
+		{"bad expiry", "behaviors:\n  - id: a\n    content: x\n    expires_at: soon\n", `expires_at "soon"`},
+		{"unknown field", "behaviors:\n  - id: a\n    content: x\n    confidence: 0.9\n", "field confidence not found"},
+		{"duplicate id", "behaviors:\n  - id: a\n    content: x\n  - id: a\n    content: y\n", `id "a" is already defined in .floop/behaviors/b.yaml`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeDefinitions(t, dir, "b.yaml", tt.content)
+			_, err := Load(dir)
+			var invalid *ValidationError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("Load() error = %v, want *ValidationError", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefinition_Hash(t *testing.T) {
+	a := Definition{ID: "a", Content: " Wrap errors ", Tags: []string{"go", "errors"}, When: map[string]interface{}{"priority": 2}}
+	b := Definition{ID: "a", Name: "a", Kind: "directive", Content: "Wrap errors", Tags: []string{"errors", "go"}, When: map[string]interface{}{"priority": 2.0}, File: "x.yaml"}
+	if a.Hash() != b.Hash() {
+		t.Error("equivalent definitions hash differently")
+	}
+	b.Priority = 5
+	if a.Hash() == b.Hash() {
+		t.Error("different priorities hash the same")
+	}
+}
@@ -0,0 +1,320 @@
+package authored
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Metadata keys recorded on behaviors applied from definition files.
+const (
+	MetaFile = "authored_file" // Definition file, relative to the project root
+	MetaHash = "authored_hash" // Hash of the definition last applied
+)
+
+// Actions a Change can carry.
+const (
+	ActionCreate    = "create"    // Defined in a file, not yet in the store
+	ActionUpdate    = "update"    // The file changed since it was last applied
+	ActionDrift     = "drift"     // The store behavior was changed outside its file
+	ActionUnchanged = "unchanged" // Store and file agree
+	ActionOrphan    = "orphan"    // Applied from a file that no longer defines it
+	ActionConflict  = "conflict"  // The ID belongs to a behavior not defined in files
+)
+
+// Change is one difference between the definition files and the store.
+type Change struct {
+	ID      string   `json:"id"`
+	File    string   `json:"file,omitempty"`
+	Action  string   `json:"action"`
+	Fields  []string `json:"fields,omitempty"`  // Fields whose store value differs from the file
+	Applied bool     `json:"applied,omitempty"` // Set by Apply once the store reflects the file
+	Error   string   `json:"error,omitempty"`
+
+	def   *Definition
+	node  *store.Node
+	stale bool // The recorded hash is out of date though the content agrees
+}
+
+// Pending reports whether applying the change would modify the store.
+// Orphans only count when pruning.
+func (c Change) Pending(prune bool) bool {
+	switch c.Action {
+	case ActionCreate, ActionUpdate, ActionDrift, ActionConflict:
+		return true
+	case ActionOrphan:
+		return prune
+	default:
+		return false
+	}
+}
+
+// Plan compares defs with the behaviors in graphStore, which should be the
+// project store. Changes are ordered by ID.
+func Plan(ctx context.Context, graphStore store.GraphStore, defs []Definition) ([]Change, error) {
+	var changes []Change
+	defined := make(map[string]bool, len(defs))
+	for i := range defs {
+		def := &defs[i]
+		defined[def.ID] = true
+		node, err := graphStore.GetNode(ctx, def.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get behavior %s: %w", def.ID, err)
+		}
+		changes = append(changes, planDefinition(def, node))
+	}
+
+	nodes, err := graphStore.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query behaviors: %w", err)
+	}
+	for i := range nodes {
+		node := &nodes[i]
+		file, ok := node.Metadata[MetaFile].(string)
+		if !ok || defined[node.ID] {
+			continue
+		}
+		changes = append(changes, Change{ID: node.ID, File: file, Action: ActionOrphan, node: node})
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ID < changes[j].ID })
+	return changes, nil
+}
+
+// planDefinition compares one definition with its store node, if any.
+func planDefinition(def *Definition, node *store.Node) Change {
+	change := Change{ID: def.ID, File: def.File, def: def, node: node}
+	if node == nil {
+		change.Action = ActionCreate
+		return change
+	}
+	recorded, managed := node.Metadata[MetaHash].(string)
+	if !managed {
+		change.Action = ActionConflict
+		change.Error = "id is used by a behavior not defined in files"
+		return change
+	}
+
+	current := definitionFromNode(*node)
+	change.Fields = diffFields(current, def.normalized())
+	if node.Kind != store.NodeKindBehavior {
+		change.Fields = append(change.Fields, "status")
+	}
+	switch {
+	case len(change.Fields) == 0:
+		change.Action = ActionUnchanged
+		change.stale = recorded != def.Hash() || node.Metadata[MetaFile] != def.File
+	case current.Hash() != recorded || node.Kind != store.NodeKindBehavior:
+		change.Action = ActionDrift
+	default:
+		change.Action = ActionUpdate
+	}
+	if node.Kind == store.NodeKindQuarantined {
+		change.Error = "behavior is quarantined; release it with 'floop restore' first"
+	}
+	return change
+}
+
+// Apply makes the store match the definition files for each pending change:
+// it creates and updates behaviors (overwriting drift) and, when prune is
+// set, forgets orphans. Changes that cannot be applied carry an Error and
+// are counted in the returned failure count. The caller syncs the store.
+func Apply(ctx context.Context, graphStore store.GraphStore, changes []Change, prune bool, now time.Time) int {
+	failed := 0
+	for i := range changes {
+		c := &changes[i]
+		if c.Error != "" {
+			failed++
+			continue
+		}
+		if !c.Pending(prune) && !c.stale {
+			continue
+		}
+		if err := applyChange(ctx, graphStore, c, now); err != nil {
+			c.Error = err.Error()
+			failed++
+			continue
+		}
+		c.Applied = c.Pending(prune)
+	}
+	return failed
+}
+
+// applyChange writes one change to the store.
+func applyChange(ctx context.Context, graphStore store.GraphStore, c *Change, now time.Time) error {
+	if c.Action == ActionOrphan {
+		forget(c.node, now)
+		if err := graphStore.UpdateNode(ctx, *c.node); err != nil {
+			return fmt.Errorf("failed to forget behavior: %w", err)
+		}
+		return nil
+	}
+
+	if c.node == nil {
+		node := newNode(c.def, now)
+		if _, err := graphStore.AddNode(ctx, node); err != nil {
+			var dup *store.DuplicateContentError
+			if errors.As(err, &dup) {
+				return fmt.Errorf("content duplicates behavior %s", dup.ExistingID)
+			}
+			return fmt.Errorf("failed to add behavior: %w", err)
+		}
+		return nil
+	}
+
+	node := *c.node
+	writeDefinition(&node, c.def)
+	if node.Kind != store.NodeKindBehavior {
+		restore(&node, now)
+	}
+	if err := graphStore.UpdateNode(ctx, node); err != nil {
+		return fmt.Errorf("failed to update behavior: %w", err)
+	}
+	return nil
+}
+
+// newNode builds the store node for a definition applied for the first
+// time.
+func newNode(def *Definition, now time.Time) store.Node {
+	n := def.normalized()
+	b := models.Behavior{
+		ID:         n.ID,
+		Name:       n.Name,
+		Kind:       models.BehaviorKind(n.Kind),
+		MemoryType: models.MemoryTypeForKind(models.BehaviorKind(n.Kind)),
+		When:       n.When,
+		Content:    models.BehaviorContent{Canonical: n.Content, Summary: n.Summary, Tags: n.Tags},
+		Confidence: 1.0,
+		Priority:   n.Priority,
+		Pinned:     n.Pinned,
+		Provenance: models.Provenance{
+			SourceType: models.SourceTypeAuthored,
+			CreatedAt:  now,
+			Author:     os.Getenv("USER"),
+		},
+	}
+	if n.ExpiresAt != "" {
+		t, _ := parseExpiry(n.ExpiresAt)
+		b.ExpiresAt = &t
+	}
+	node := models.BehaviorToNode(&b)
+	node.Metadata["scope"] = string(constants.ScopeLocal)
+	node.Metadata[MetaFile] = def.File
+	node.Metadata[MetaHash] = def.Hash()
+	return node
+}
+
+// writeDefinition replaces the authored fields of node with def, keeping its
+// statistics, confidence, and provenance.
+func writeDefinition(node *store.Node, def *Definition) {
+	n := def.normalized()
+	if node.Content == nil {
+		node.Content = make(map[string]interface{})
+	}
+	if node.Metadata == nil {
+		node.Metadata = make(map[string]interface{})
+	}
+	node.Content["name"] = n.Name
+	node.Content["kind"] = n.Kind
+	node.Content["memory_type"] = string(models.MemoryTypeForKind(models.BehaviorKind(n.Kind)))
+	node.Content["when"] = n.When
+	node.Content["content"] = models.BehaviorContent{Canonical: n.Content, Summary: n.Summary, Tags: n.Tags}
+	node.Metadata["priority"] = n.Priority
+	if n.ExpiresAt != "" {
+		node.Metadata["expires_at"] = n.ExpiresAt
+	} else {
+		delete(node.Metadata, "expires_at")
+	}
+	delete(node.Metadata, "valid_until")
+	if n.Pinned {
+		node.Metadata["pinned"] = true
+	} else {
+		delete(node.Metadata, "pinned")
+	}
+	node.Metadata[MetaFile] = def.File
+	node.Metadata[MetaHash] = def.Hash()
+}
+
+// restore reactivates a curated behavior whose file still defines it.
+func restore(node *store.Node, now time.Time) {
+	node.Kind = store.NodeKindBehavior
+	node.Metadata["restored_at"] = now.UTC().Format(time.RFC3339)
+	node.Metadata["restored_by"] = "floop apply"
+	for _, key := range []string{
+		"original_kind", "forgotten_at", "forgotten_by", "forget_reason",
+		"deprecated_at", "deprecated_by", "deprecation_reason", "replacement_id",
+	} {
+		delete(node.Metadata, key)
+	}
+}
+
+// forget marks an orphaned behavior forgotten, as 'floop forget' does.
+func forget(node *store.Node, now time.Time) {
+	node.Metadata["original_kind"] = node.Kind
+	node.Metadata["forgotten_at"] = now.UTC().Format(time.RFC3339)
+	node.Metadata["forgotten_by"] = "floop apply"
+	node.Metadata["forget_reason"] = "removed from " + Dir
+	node.Kind = store.NodeKindForgotten
+}
+
+// definitionFromNode reads the authored fields of a store node back into a
+// normalized definition.
+func definitionFromNode(node store.Node) Definition {
+	b := models.NodeToBehavior(node)
+	def := Definition{
+		ID:      node.ID,
+		Name:    b.Name,
+		Kind:    string(b.Kind),
+		Content: b.Content.Canonical,
+		Summary: b.Content.Summary,
+		Tags:    b.Content.Tags,
+		When:    b.When,
+		Pinned:  b.Pinned,
+	}
+	switch p := node.Metadata["priority"].(type) {
+	case int:
+		def.Priority = p
+	case float64:
+		def.Priority = int(p)
+	}
+	if b.ExpiresAt != nil {
+		def.ExpiresAt = b.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	return def.normalized()
+}
+
+// diffFields names the fields in which two normalized definitions differ.
+func diffFields(a, b Definition) []string {
+	var fields []string
+	add := func(name string, equal bool) {
+		if !equal {
+			fields = append(fields, name)
+		}
+	}
+	add("name", a.Name == b.Name)
+	add("kind", a.Kind == b.Kind)
+	add("content", a.Content == b.Content)
+	add("summary", a.Summary == b.Summary)
+	add("tags", reflect.DeepEqual(a.Tags, b.Tags))
+	add("when", reflect.DeepEqual(a.When, b.When))
+	add("priority", a.Priority == b.Priority)
+	add("expires_at", a.ExpiresAt == b.ExpiresAt)
+	add("pinned", a.Pinned == b.Pinned)
+	return fields
+}
+
+// contentHash returns the hex SHA-256 of data.
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
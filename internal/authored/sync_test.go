@@ -0,0 +1,187 @@
+package authored
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func openStore(t *testing.T, dir string) *store.SQLiteGraphStore {
+	t.Helper()
+	gs, err := store.NewSQLiteGraphStore(dir)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	t.Cleanup(func() { gs.Close() })
+	return gs
+}
+
+// actions maps each change's ID to its action.
+func actions(changes []Change) map[string]string {
+	out := make(map[string]string, len(changes))
+	for _, c := range changes {
+		out[c.ID] = c.Action
+	}
+	return out
+}
+
+func TestPlanAndApply(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	gs := openStore(t, dir)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	writeDefinitions(t, dir, "team.yaml", `
+behaviors:
+  - id: wrap-errors
+    content: Wrap errors with context
+    when: {language: go}
+    priority: 5
+  - id: no-panics
+    kind: constraint
+    content: Never panic in library code
+    pinned: true
+`)
+	learned := models.Behavior{ID: "learned-1", Name: "learned", Kind: models.BehaviorKindDirective, Content: models.BehaviorContent{Canonical: "Learned thing"}}
+	if _, err := gs.AddNode(ctx, models.BehaviorToNode(&learned)); err != nil {
+		t.Fatal(err)
+	}
+
+	defs, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := Plan(ctx, gs, defs)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if want := map[string]string{"no-panics": ActionCreate, "wrap-errors": ActionCreate}; !reflect.DeepEqual(actions(changes), want) {
+		t.Fatalf("first plan = %v, want %v", actions(changes), want)
+	}
+	if failed := Apply(ctx, gs, changes, false, now); failed != 0 {
+		t.Fatalf("Apply() failed = %d: %+v", failed, changes)
+	}
+
+	node, _ := gs.GetNode(ctx, "wrap-errors")
+	b := models.NodeToBehavior(*node)
+	if b.Content.Canonical != "Wrap errors with context" || b.When["language"] != "go" || b.Provenance.SourceType != models.SourceTypeAuthored {
+		t.Errorf("applied behavior = %+v", b)
+	}
+	if node.Metadata[MetaFile] != ".floop/behaviors/team.yaml" {
+		t.Errorf("authored_file = %v", node.Metadata[MetaFile])
+	}
+	if pinned, _ := gs.GetNode(ctx, "no-panics"); !models.NodeToBehavior(*pinned).Pinned {
+		t.Error("no-panics not pinned")
+	}
+
+	// Reapplying is a no-op
+	changes, _ = Plan(ctx, gs, defs)
+	if want := map[string]string{"no-panics": ActionUnchanged, "wrap-errors": ActionUnchanged}; !reflect.DeepEqual(actions(changes), want) {
+		t.Fatalf("replan = %v, want %v", actions(changes), want)
+	}
+
+	// Edit one behavior in the store, change the other in its file, and
+	// drop a third from the files
+	node.Content["when"] = map[string]interface{}{"language": "rust"}
+	if err := gs.UpdateNode(ctx, *node); err != nil {
+		t.Fatal(err)
+	}
+	writeDefinitions(t, dir, "team.yaml", `
+behaviors:
+  - id: wrap-errors
+    content: Wrap errors with context
+    when: {language: go}
+    priority: 5
+`)
+	writeDefinitions(t, dir, "more.yaml", `
+behaviors:
+  - id: no-panics
+    kind: constraint
+    content: Never panic in library code; return errors
+`)
+	defs, err = Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, _ = Plan(ctx, gs, defs)
+	if want := map[string]string{"no-panics": ActionUpdate, "wrap-errors": ActionDrift}; !reflect.DeepEqual(actions(changes), want) {
+		t.Fatalf("drift plan = %v, want %v", actions(changes), want)
+	}
+	for _, c := range changes {
+		if c.ID == "wrap-errors" && !reflect.DeepEqual(c.Fields, []string{"when"}) {
+			t.Errorf("drift fields = %v, want [when]", c.Fields)
+		}
+	}
+	if failed := Apply(ctx, gs, changes, false, now); failed != 0 {
+		t.Fatalf("Apply() failed = %d: %+v", failed, changes)
+	}
+	node, _ = gs.GetNode(ctx, "wrap-errors")
+	if node.Content["when"].(map[string]interface{})["language"] != "go" {
+		t.Errorf("drift not overwritten: when = %v", node.Content["when"])
+	}
+	node, _ = gs.GetNode(ctx, "no-panics")
+	if node.Metadata[MetaFile] != ".floop/behaviors/more.yaml" {
+		t.Errorf("authored_file after move = %v", node.Metadata[MetaFile])
+	}
+
+	// Removing a definition orphans its behavior; only --prune forgets it
+	writeDefinitions(t, dir, "more.yaml", "behaviors: []\n")
+	defs, _ = Load(dir)
+	changes, _ = Plan(ctx, gs, defs)
+	if actions(changes)["no-panics"] != ActionOrphan {
+		t.Fatalf("orphan plan = %v", actions(changes))
+	}
+	Apply(ctx, gs, changes, false, now)
+	if node, _ := gs.GetNode(ctx, "no-panics"); node.Kind != store.NodeKindBehavior {
+		t.Errorf("orphan forgotten without prune: kind = %s", node.Kind)
+	}
+	Apply(ctx, gs, changes, true, now)
+	if node, _ := gs.GetNode(ctx, "no-panics"); node.Kind != store.NodeKindForgotten {
+		t.Errorf("orphan kind after prune = %s, want forgotten", node.Kind)
+	}
+
+	// Defining it again restores it
+	writeDefinitions(t, dir, "more.yaml", `
+behaviors:
+  - id: no-panics
+    kind: constraint
+    content: Never panic in library code; return errors
+`)
+	defs, _ = Load(dir)
+	changes, _ = Plan(ctx, gs, defs)
+	if actions(changes)["no-panics"] != ActionDrift {
+		t.Fatalf("forgotten plan = %v", actions(changes))
+	}
+	Apply(ctx, gs, changes, false, now)
+	if node, _ := gs.GetNode(ctx, "no-panics"); node.Kind != store.NodeKindBehavior || node.Metadata["forgotten_at"] != nil {
+		t.Errorf("restored node = %+v", node)
+	}
+}
+
+func TestPlan_Conflict(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	gs := openStore(t, dir)
+	learned := models.Behavior{ID: "wrap-errors", Name: "learned", Kind: models.BehaviorKindDirective, Content: models.BehaviorContent{Canonical: "Learned thing"}}
+	if _, err := gs.AddNode(ctx, models.BehaviorToNode(&learned)); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := Plan(ctx, gs, []Definition{{ID: "wrap-errors", Content: "Wrap errors"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Action != ActionConflict {
+		t.Fatalf("Plan() = %+v, want a conflict", changes)
+	}
+	if failed := Apply(ctx, gs, changes, false, time.Now()); failed != 1 {
+		t.Errorf("Apply() failed = %d, want 1", failed)
+	}
+	if node, _ := gs.GetNode(ctx, "wrap-errors"); models.NodeToBehavior(*node).Content.Canonical != "Learned thing" {
+		t.Error("conflicting behavior was overwritten")
+	}
+}
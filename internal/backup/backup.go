@@ -4,6 +4,7 @@ package backup
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -13,6 +14,7 @@ import (
 	"time"
 
 	"github.com/nvandessel/floop/internal/pathutil"
+	"github.com/nvandessel/floop/internal/progress"
 	"github.com/nvandessel/floop/internal/store"
 )
 
@@ -154,18 +156,43 @@ func writeV1(outputPath string, bf *BackupFormat) error {
 type RestoreMode string
 
 const (
-	// RestoreMerge skips nodes/edges that already exist (default).
+	// RestoreMerge skips nodes/edges that already exist (default). A node
+	// that exists with different content is a Conflict, resolved by the
+	// restore's ConflictStrategy or ConflictResolver.
 	RestoreMerge RestoreMode = "merge"
 	// RestoreReplace clears the store before restoring.
 	RestoreReplace RestoreMode = "replace"
 )
 
+// RestoreOptions configures RestoreWithOptions.
+type RestoreOptions struct {
+	Mode        RestoreMode
+	AllowedDirs []string           // nil = skip path validation
+	Progress    *progress.Reporter // nil = no progress reporting
+
+	// Strategy resolves merge conflicts when Resolve is nil; empty keeps
+	// the store's version.
+	Strategy ConflictStrategy
+	// Resolve, if set, chooses the resolution of each merge conflict.
+	Resolve ConflictResolver
+}
+
+// restoreEdgeBatch is how many edges a restore adds per transaction, so it
+// can report progress and stop between batches.
+const restoreEdgeBatch = 500
+
 // RestoreResult contains statistics about the restore operation.
 type RestoreResult struct {
 	NodesRestored int `json:"nodes_restored"`
 	NodesSkipped  int `json:"nodes_skipped"`
+	NodesUpdated  int `json:"nodes_updated"`
 	EdgesRestored int `json:"edges_restored"`
 	EdgesSkipped  int `json:"edges_skipped"`
+
+	// Conflicts lists the nodes a merge restore found with different
+	// content, with how each was resolved. Those kept as they were also
+	// count as skipped; the rest count as updated.
+	Conflicts []Conflict `json:"conflicts,omitempty"`
 }
 
 // Restore imports nodes and edges from a backup file into the store.
@@ -178,8 +205,19 @@ type RestoreResult struct {
 //   - SchemaVersion < store.SchemaVersion: prints warning to stderr
 //   - SchemaVersion == 0: silent (old format, no schema version)
 func Restore(ctx context.Context, graphStore store.GraphStore, inputPath string, mode RestoreMode, allowedDirs ...string) (*RestoreResult, error) {
-	if len(allowedDirs) > 0 {
-		if err := pathutil.ValidatePath(inputPath, allowedDirs); err != nil {
+	return RestoreWithOptions(ctx, graphStore, inputPath, RestoreOptions{
+		Mode:        mode,
+		AllowedDirs: allowedDirs,
+	})
+}
+
+// RestoreWithOptions is Restore with explicit options. Once ctx is
+// cancelled, the node or edge batch being written is finished and the
+// restore stops: the store is synced and the partial result is returned
+// with ctx's error.
+func RestoreWithOptions(ctx context.Context, graphStore store.GraphStore, inputPath string, opts RestoreOptions) (*RestoreResult, error) {
+	if len(opts.AllowedDirs) > 0 {
+		if err := pathutil.ValidatePath(inputPath, opts.AllowedDirs); err != nil {
 			return nil, fmt.Errorf("restore path rejected: %w", err)
 		}
 	}
@@ -194,7 +232,7 @@ func Restore(ctx context.Context, graphStore store.GraphStore, inputPath string,
 		return nil, err
 	}
 
-	return restoreFromBackup(ctx, graphStore, backup, mode)
+	return restoreFromBackup(ctx, graphStore, backup, opts)
 }
 
 // checkSchemaVersion reads the V2 header and validates schema version compatibility.
@@ -270,48 +308,106 @@ func readV1(inputPath string) (*BackupFormat, error) {
 	return &backup, nil
 }
 
-// restoreFromBackup applies a parsed BackupFormat to the store.
-func restoreFromBackup(ctx context.Context, graphStore store.GraphStore, backup *BackupFormat, mode RestoreMode) (*RestoreResult, error) {
+// restoreFromBackup applies a parsed BackupFormat to the store, stopping
+// between nodes or edge batches once ctx is cancelled.
+func restoreFromBackup(ctx context.Context, graphStore store.GraphStore, backup *BackupFormat, opts RestoreOptions) (*RestoreResult, error) {
 	result := &RestoreResult{}
+	work := context.WithoutCancel(ctx)
+	mode, rep := opts.Mode, opts.Progress
 
+	rep.Start("restore nodes", len(backup.Nodes))
 	for _, bn := range backup.Nodes {
-		if mode == RestoreMerge {
-			existing, err := graphStore.GetNode(ctx, bn.ID)
-			if err != nil {
-				return nil, fmt.Errorf("failed to check existing node %s: %w", bn.ID, err)
-			}
-			if existing != nil {
-				result.NodesSkipped++
-				continue
-			}
+		if ctx.Err() != nil {
+			break
 		}
-
-		if _, err := graphStore.AddNode(ctx, bn.Node); err != nil {
-			if mode == RestoreMerge {
-				result.NodesSkipped++
-				continue
-			}
-			return nil, fmt.Errorf("failed to restore node %s: %w", bn.ID, err)
+		if err := restoreNode(work, graphStore, bn, opts, result); err != nil {
+			return nil, err
 		}
-		result.NodesRestored++
+		rep.Step(bn.ID)
 	}
+	rep.Finish()
 
-	for _, edge := range backup.Edges {
-		if err := graphStore.AddEdge(ctx, edge); err != nil {
-			if mode == RestoreMerge {
-				result.EdgesSkipped++
-				continue
+	// Edges to nodes missing from the store are skipped; other invalid
+	// edges fail a replace restore
+	if ctx.Err() == nil {
+		rep.Start("restore edges", len(backup.Edges))
+	}
+	for start := 0; start < len(backup.Edges) && ctx.Err() == nil; start += restoreEdgeBatch {
+		batch := backup.Edges[start:min(start+restoreEdgeBatch, len(backup.Edges))]
+		rejected, err := store.BatchAddEdges(work, graphStore, batch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore edges: %w", err)
+		}
+		for _, r := range rejected {
+			if mode != RestoreMerge && !errors.Is(r.Err, store.ErrEdgeEndpointNotFound) {
+				return nil, fmt.Errorf("failed to restore edge %s->%s: %w", r.Edge.Source, r.Edge.Target, r.Err)
 			}
-			return nil, fmt.Errorf("failed to restore edge %s->%s: %w", edge.Source, edge.Target, err)
 		}
-		result.EdgesRestored++
+		result.EdgesSkipped += len(rejected)
+		result.EdgesRestored += len(batch) - len(rejected)
+		rep.Advance(len(batch), "")
 	}
+	rep.Finish()
 
-	if err := graphStore.Sync(ctx); err != nil {
+	if err := graphStore.Sync(work); err != nil {
 		return nil, fmt.Errorf("failed to sync after restore: %w", err)
 	}
 
-	return result, nil
+	return result, ctx.Err()
+}
+
+// restoreNode adds one backed-up node to the store, counting it in result.
+// In merge mode, a node already in the store is kept unless its content
+// differs, when the conflict is resolved as opts direct.
+func restoreNode(ctx context.Context, graphStore store.GraphStore, bn BackupNode, opts RestoreOptions, result *RestoreResult) error {
+	if opts.Mode == RestoreMerge {
+		existing, err := graphStore.GetNode(ctx, bn.ID)
+		if err != nil {
+			return fmt.Errorf("failed to check existing node %s: %w", bn.ID, err)
+		}
+		if existing != nil {
+			return mergeNode(ctx, graphStore, *existing, bn.Node, opts, result)
+		}
+	}
+
+	if _, err := graphStore.AddNode(ctx, bn.Node); err != nil {
+		if opts.Mode == RestoreMerge {
+			result.NodesSkipped++
+			return nil
+		}
+		return fmt.Errorf("failed to restore node %s: %w", bn.ID, err)
+	}
+	result.NodesRestored++
+	return nil
+}
+
+// mergeNode resolves a backed-up node that is already in the store,
+// counting it in result.
+func mergeNode(ctx context.Context, graphStore store.GraphStore, existing, backed store.Node, opts RestoreOptions, result *RestoreResult) error {
+	conflict := newConflict(existing, backed)
+	if conflict == nil {
+		result.NodesSkipped++
+		return nil
+	}
+
+	res := Resolution{Strategy: opts.Strategy}
+	if opts.Resolve != nil {
+		var err error
+		if res, err = opts.Resolve(ctx, *conflict); err != nil {
+			return err
+		}
+	}
+	updated, err := resolveConflict(ctx, graphStore, conflict, res)
+	if err != nil {
+		return err
+	}
+	if updated {
+		result.NodesUpdated++
+	} else {
+		result.NodesSkipped++
+	}
+	result.Conflicts = append(result.Conflicts, *conflict)
+	return nil
 }
 
 // GenerateBackupPath creates a timestamped backup filename in the given directory.
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -14,6 +15,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/nvandessel/floop/internal/progress"
 	"github.com/nvandessel/floop/internal/store"
 )
 
@@ -792,3 +794,64 @@ func TestCheckSchemaVersion_V1File(t *testing.T) {
 		t.Errorf("checkSchemaVersion() should be silent for V1 files, got: %v", err)
 	}
 }
+
+func TestRestoreFromBackup_EdgeValidation(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	node := func(id string) BackupNode {
+		return BackupNode{store.Node{
+			ID:      id,
+			Kind:    "behavior",
+			Content: map[string]interface{}{"name": id, "kind": "directive", "content": map[string]interface{}{"canonical": "Content for " + id}},
+		}}
+	}
+	valid := store.Edge{Source: "node-a", Target: "node-b", Kind: store.EdgeKindRequires, Weight: 0.9, CreatedAt: now}
+	dangling := store.Edge{Source: "node-a", Target: "node-gone", Kind: store.EdgeKindSimilarTo, Weight: 0.5, CreatedAt: now}
+	invalid := store.Edge{Source: "node-b", Target: "node-a", Kind: store.EdgeKindSimilarTo, Weight: 2, CreatedAt: now}
+
+	dst := createTestStore(t)
+	defer dst.Close()
+	result, err := restoreFromBackup(ctx, dst, &BackupFormat{
+		Nodes: []BackupNode{node("node-a"), node("node-b")},
+		Edges: []store.Edge{valid, dangling},
+	}, RestoreOptions{Mode: RestoreReplace})
+	if err != nil {
+		t.Fatalf("restoreFromBackup() error = %v", err)
+	}
+	if result.EdgesRestored != 1 || result.EdgesSkipped != 1 {
+		t.Errorf("EdgesRestored/Skipped = %d/%d, want 1/1 (dangling edge skipped)", result.EdgesRestored, result.EdgesSkipped)
+	}
+
+	dst2 := createTestStore(t)
+	defer dst2.Close()
+	if _, err := restoreFromBackup(ctx, dst2, &BackupFormat{
+		Nodes: []BackupNode{node("node-a"), node("node-b")},
+		Edges: []store.Edge{valid, invalid},
+	}, RestoreOptions{Mode: RestoreReplace}); err == nil {
+		t.Error("replace restore with an invalid edge should fail")
+	}
+}
+
+func TestRestoreFromBackup_Cancelled(t *testing.T) {
+	node := BackupNode{store.Node{
+		ID:      "node-a",
+		Kind:    "behavior",
+		Content: map[string]interface{}{"name": "node-a", "kind": "directive", "content": map[string]interface{}{"canonical": "Content for node-a"}},
+	}}
+	dst := createTestStore(t)
+	defer dst.Close()
+
+	var events bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := restoreFromBackup(ctx, dst, &BackupFormat{Nodes: []BackupNode{node}}, RestoreOptions{Mode: RestoreMerge, Progress: progress.New(&events, progress.FormatJSON)})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("restoreFromBackup() error = %v, want context.Canceled", err)
+	}
+	if result == nil || result.NodesRestored != 0 {
+		t.Errorf("result = %+v, want a partial result with nothing restored", result)
+	}
+	if !strings.Contains(events.String(), `"operation":"restore nodes"`) || strings.Contains(events.String(), "restore edges") {
+		t.Errorf("progress events = %q, want the node phase only", events.String())
+	}
+}
@@ -0,0 +1,273 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// ConflictStrategy decides what a merge restore does with a node that is
+// already in the store with different content.
+type ConflictStrategy string
+
+const (
+	// ConflictKeepLocal keeps the store's version of the node (default).
+	ConflictKeepLocal ConflictStrategy = "keep-local"
+	// ConflictTakeBackup replaces the store's version with the backup's.
+	ConflictTakeBackup ConflictStrategy = "take-backup"
+	// ConflictMergeFields keeps the store's value of every field it sets and
+	// takes the backup's value of the fields only the backup sets.
+	ConflictMergeFields ConflictStrategy = "merge-fields"
+)
+
+// ParseConflictStrategy validates a strategy name. The empty name selects
+// ConflictKeepLocal.
+func ParseConflictStrategy(s string) (ConflictStrategy, error) {
+	switch strategy := ConflictStrategy(s); strategy {
+	case "":
+		return ConflictKeepLocal, nil
+	case ConflictKeepLocal, ConflictTakeBackup, ConflictMergeFields:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("invalid conflict strategy: %s (valid: keep-local, take-backup, merge-fields)", s)
+}
+
+// Conflict is a node a merge restore found in both the store and the backup
+// with different content. Fields lists the dotted paths that differ, such as
+// "kind", "content.content.canonical", or "metadata.confidence"; as for Diff,
+// usage stats are not compared.
+type Conflict struct {
+	ID     string     `json:"id"`
+	Name   string     `json:"name,omitempty"`
+	Fields []string   `json:"fields"`
+	Local  store.Node `json:"-"`
+	Backup store.Node `json:"-"`
+
+	// Resolution is how the conflict was resolved, and FromBackup the fields
+	// taken from the backup by a merge-fields resolution.
+	Resolution ConflictStrategy `json:"resolution"`
+	FromBackup []string         `json:"from_backup,omitempty"`
+}
+
+// Resolution is how one conflict is to be resolved. For
+// ConflictMergeFields, FromBackup names the differing fields whose backup
+// value is taken, and every other field keeps the store's value; nil
+// FromBackup takes the fields only the backup sets.
+type Resolution struct {
+	Strategy   ConflictStrategy
+	FromBackup []string
+}
+
+// ConflictResolver chooses the resolution of each conflict, e.g. by asking
+// the user. An error stops the restore.
+type ConflictResolver func(ctx context.Context, c Conflict) (Resolution, error)
+
+// Value returns the value of field in the store's or the backup's version of
+// the node, and whether it is set.
+func (c Conflict) Value(field string, fromBackup bool) (interface{}, bool) {
+	n := c.Local
+	if fromBackup {
+		n = c.Backup
+	}
+	return lookupField(conflictFields(n), field)
+}
+
+// BackupOnly returns the differing fields that only the backup sets, which a
+// merge-fields resolution takes by default.
+func (c Conflict) BackupOnly() []string {
+	var fields []string
+	for _, f := range c.Fields {
+		if _, ok := c.Value(f, false); !ok {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// newConflict compares the store's and the backup's version of a node. It
+// returns nil if they match.
+func newConflict(local, backup store.Node) *Conflict {
+	if sameBehavior(local, backup) {
+		return nil
+	}
+	fields := diffFields("", conflictFields(local), conflictFields(backup))
+	if len(fields) == 0 {
+		return nil
+	}
+	sort.Strings(fields)
+	name, _ := local.Content["name"].(string)
+	return &Conflict{ID: local.ID, Name: name, Fields: fields, Local: local, Backup: backup}
+}
+
+// resolveConflict applies res to c, updating the node unless the store's
+// version is kept. It reports whether the node was updated and records the
+// resolution in c.
+func resolveConflict(ctx context.Context, graphStore store.GraphStore, c *Conflict, res Resolution) (bool, error) {
+	var node store.Node
+	switch res.Strategy {
+	case ConflictKeepLocal, "":
+		c.Resolution = ConflictKeepLocal
+		return false, nil
+	case ConflictTakeBackup:
+		node = c.Backup
+		node.Origin = c.Local.Origin
+	case ConflictMergeFields:
+		fromBackup := res.FromBackup
+		if fromBackup == nil {
+			fromBackup = c.BackupOnly()
+		}
+		var err error
+		if node, err = mergeFields(*c, fromBackup); err != nil {
+			return false, err
+		}
+		c.FromBackup = fromBackup
+		if len(fromBackup) == 0 {
+			c.Resolution = ConflictKeepLocal
+			c.FromBackup = nil
+			return false, nil
+		}
+	default:
+		return false, fmt.Errorf("invalid conflict strategy: %s", res.Strategy)
+	}
+
+	// Read-only scope behaviors can't change: the store's version stays
+	if err := graphStore.UpdateNode(ctx, node); err != nil {
+		if errors.Is(err, store.ErrReadOnlyOrigin) {
+			c.Resolution = ConflictKeepLocal
+			c.FromBackup = nil
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to resolve conflict on node %s: %w", c.ID, err)
+	}
+	c.Resolution = res.Strategy
+	return true, nil
+}
+
+// mergeFields returns the store's version of the node with the fields in
+// fromBackup set to their backup values, or removed where the backup does
+// not set them.
+func mergeFields(c Conflict, fromBackup []string) (store.Node, error) {
+	node := c.Local
+	node.Content = copyMap(c.Local.Content)
+	node.Metadata = copyMap(c.Local.Metadata)
+	root := map[string]interface{}{
+		"kind":     string(node.Kind),
+		"content":  node.Content,
+		"metadata": node.Metadata,
+	}
+	for _, field := range fromBackup {
+		if !slices.Contains(c.Fields, field) {
+			return store.Node{}, fmt.Errorf("field %s does not differ in node %s", field, c.ID)
+		}
+		v, ok := c.Value(field, true)
+		setField(root, field, v, ok)
+	}
+	kind, _ := root["kind"].(string)
+	node.Kind = store.NodeKind(kind)
+	node.Content, _ = root["content"].(map[string]interface{})
+	node.Metadata, _ = root["metadata"].(map[string]interface{})
+	return node, nil
+}
+
+// conflictFields returns the fields of n that conflicts compare, in the
+// shape of its JSON encoding.
+func conflictFields(n store.Node) map[string]interface{} {
+	fields := map[string]interface{}{
+		"kind":    string(n.Kind),
+		"content": n.Content,
+	}
+	metadata := map[string]interface{}{}
+	for _, key := range []string{"confidence", "priority"} {
+		if v, ok := n.Metadata[key]; ok {
+			metadata[key] = v
+		}
+	}
+	fields["metadata"] = metadata
+	return fields
+}
+
+// diffFields returns the dotted paths under prefix at which a and b differ,
+// descending into values that are maps on both sides. A null value counts
+// as unset, as the store and JSONL don't keep null fields apart from absent
+// ones.
+func diffFields(prefix string, a, b map[string]interface{}) []string {
+	keys := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	var fields []string
+	for k := range keys {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		av, bv := a[k], b[k]
+		aok, bok := av != nil, bv != nil
+		am, aIsMap := av.(map[string]interface{})
+		bm, bIsMap := bv.(map[string]interface{})
+		switch {
+		case aIsMap && bIsMap:
+			fields = append(fields, diffFields(path, am, bm)...)
+		case aok != bok || !sameJSON(av, bv):
+			fields = append(fields, path)
+		}
+	}
+	return fields
+}
+
+// lookupField returns the value at a dotted path in m, treating null as
+// unset like diffFields.
+func lookupField(m map[string]interface{}, path string) (interface{}, bool) {
+	key, rest, nested := strings.Cut(path, ".")
+	v := m[key]
+	if v == nil || !nested {
+		return v, v != nil
+	}
+	sub, isMap := v.(map[string]interface{})
+	if !isMap {
+		return nil, false
+	}
+	return lookupField(sub, rest)
+}
+
+// setField sets the value at a dotted path in m, or deletes it if !ok,
+// creating intermediate maps as needed.
+func setField(m map[string]interface{}, path string, v interface{}, ok bool) {
+	key, rest, nested := strings.Cut(path, ".")
+	if !nested {
+		if ok {
+			m[key] = v
+		} else {
+			delete(m, key)
+		}
+		return
+	}
+	sub, isMap := m[key].(map[string]interface{})
+	if !isMap {
+		if !ok {
+			return
+		}
+		sub = make(map[string]interface{})
+	} else {
+		sub = copyMap(sub)
+	}
+	m[key] = sub
+	setField(sub, rest, v, ok)
+}
+
+// copyMap returns a shallow copy of m.
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
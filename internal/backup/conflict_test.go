@@ -0,0 +1,165 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func conflictNode(canonical string, when map[string]interface{}, confidence float64) store.Node {
+	content := map[string]interface{}{
+		"name":    "wrap-errors",
+		"kind":    "directive",
+		"content": map[string]interface{}{"canonical": canonical},
+	}
+	if when != nil {
+		content["when"] = when
+	}
+	return store.Node{
+		ID:       "b-1",
+		Kind:     store.NodeKindBehavior,
+		Content:  content,
+		Metadata: map[string]interface{}{"confidence": confidence},
+	}
+}
+
+func TestParseConflictStrategy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ConflictStrategy
+		wantErr bool
+	}{
+		{"", ConflictKeepLocal, false},
+		{"keep-local", ConflictKeepLocal, false},
+		{"take-backup", ConflictTakeBackup, false},
+		{"merge-fields", ConflictMergeFields, false},
+		{"overwrite", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseConflictStrategy(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseConflictStrategy(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestNewConflict(t *testing.T) {
+	local := conflictNode("Wrap errors with %w", nil, 0.8)
+	if c := newConflict(local, conflictNode("Wrap errors with %w", nil, 0.8)); c != nil {
+		t.Errorf("identical nodes conflict on %v", c.Fields)
+	}
+
+	// Usage stats alone are not a conflict
+	used := conflictNode("Wrap errors with %w", nil, 0.8)
+	used.Metadata["stats"] = map[string]interface{}{"times_activated": 12}
+	if c := newConflict(local, used); c != nil {
+		t.Errorf("usage stats conflict on %v", c.Fields)
+	}
+
+	// Nor are null fields the store leaves out
+	nulled := conflictNode("Wrap errors with %w", nil, 0.8)
+	nulled.Content["requires"] = nil
+	if c := newConflict(local, nulled); c != nil {
+		t.Errorf("null field conflicts on %v", c.Fields)
+	}
+
+	backed := conflictNode("Wrap errors using fmt.Errorf", map[string]interface{}{"language": "go"}, 0.6)
+	c := newConflict(local, backed)
+	if c == nil {
+		t.Fatal("differing nodes don't conflict")
+	}
+	want := []string{"content.content.canonical", "content.when", "metadata.confidence"}
+	if !reflect.DeepEqual(c.Fields, want) {
+		t.Errorf("Fields = %v, want %v", c.Fields, want)
+	}
+	if got := c.BackupOnly(); !reflect.DeepEqual(got, []string{"content.when"}) {
+		t.Errorf("BackupOnly() = %v, want content.when", got)
+	}
+	if c.Name != "wrap-errors" {
+		t.Errorf("Name = %q, want wrap-errors", c.Name)
+	}
+}
+
+func TestRestoreFromBackup_Conflicts(t *testing.T) {
+	local := conflictNode("Wrap errors with %w", nil, 0.8)
+	backed := conflictNode("Wrap errors using fmt.Errorf", map[string]interface{}{"language": "go"}, 0.6)
+
+	tests := []struct {
+		name          string
+		opts          RestoreOptions
+		wantUpdated   int
+		wantCanonical string
+		wantWhen      bool
+		wantConf      float64
+		wantRes       ConflictStrategy
+	}{
+		{"keep local by default", RestoreOptions{}, 0, "Wrap errors with %w", false, 0.8, ConflictKeepLocal},
+		{"take backup", RestoreOptions{Strategy: ConflictTakeBackup}, 1, "Wrap errors using fmt.Errorf", true, 0.6, ConflictTakeBackup},
+		{"merge fields", RestoreOptions{Strategy: ConflictMergeFields}, 1, "Wrap errors with %w", true, 0.8, ConflictMergeFields},
+		{
+			"resolver picks fields",
+			RestoreOptions{Resolve: func(_ context.Context, c Conflict) (Resolution, error) {
+				return Resolution{Strategy: ConflictMergeFields, FromBackup: []string{"content.content.canonical"}}, nil
+			}},
+			1, "Wrap errors using fmt.Errorf", false, 0.8, ConflictMergeFields,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			dst := createTestStore(t)
+			defer dst.Close()
+			if _, err := dst.AddNode(ctx, local); err != nil {
+				t.Fatalf("AddNode() error = %v", err)
+			}
+
+			tt.opts.Mode = RestoreMerge
+			result, err := restoreFromBackup(ctx, dst, &BackupFormat{Nodes: []BackupNode{{backed}}}, tt.opts)
+			if err != nil {
+				t.Fatalf("restoreFromBackup() error = %v", err)
+			}
+			if result.NodesUpdated != tt.wantUpdated || result.NodesSkipped != 1-tt.wantUpdated {
+				t.Errorf("updated/skipped = %d/%d, want %d/%d", result.NodesUpdated, result.NodesSkipped, tt.wantUpdated, 1-tt.wantUpdated)
+			}
+			if len(result.Conflicts) != 1 || result.Conflicts[0].Resolution != tt.wantRes {
+				t.Fatalf("Conflicts = %+v, want one resolved %s", result.Conflicts, tt.wantRes)
+			}
+
+			got, err := dst.GetNode(ctx, "b-1")
+			if err != nil || got == nil {
+				t.Fatalf("GetNode() = %v, %v", got, err)
+			}
+			inner, _ := got.Content["content"].(map[string]interface{})
+			if inner["canonical"] != tt.wantCanonical {
+				t.Errorf("canonical = %v, want %q", inner["canonical"], tt.wantCanonical)
+			}
+			if _, ok := got.Content["when"]; ok != tt.wantWhen {
+				t.Errorf("when set = %v, want %v", ok, tt.wantWhen)
+			}
+			if got.Metadata["confidence"] != tt.wantConf {
+				t.Errorf("confidence = %v, want %v", got.Metadata["confidence"], tt.wantConf)
+			}
+		})
+	}
+}
+
+func TestRestoreFromBackup_ResolverError(t *testing.T) {
+	ctx := context.Background()
+	dst := createTestStore(t)
+	defer dst.Close()
+	if _, err := dst.AddNode(ctx, conflictNode("Wrap errors with %w", nil, 0.8)); err != nil {
+		t.Fatalf("AddNode() error = %v", err)
+	}
+
+	stop := errors.New("stop")
+	_, err := restoreFromBackup(ctx, dst, &BackupFormat{Nodes: []BackupNode{{conflictNode("Other", nil, 0.8)}}}, RestoreOptions{
+		Mode:    RestoreMerge,
+		Resolve: func(context.Context, Conflict) (Resolution, error) { return Resolution{}, stop },
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("restoreFromBackup() error = %v, want the resolver's error", err)
+	}
+}
@@ -0,0 +1,259 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// ChangeStatus describes how a node or edge changed relative to a baseline.
+type ChangeStatus string
+
+const (
+	ChangeNew             ChangeStatus = "new"              // Absent from the baseline
+	ChangeModified        ChangeStatus = "modified"         // Content, kind, confidence, or priority changed
+	ChangeWeightIncreased ChangeStatus = "weight-increased" // Edge weight went up
+	ChangeWeightDecreased ChangeStatus = "weight-decreased" // Edge weight went down
+	ChangePruned          ChangeStatus = "pruned"           // In the baseline, gone now
+)
+
+// GraphDiff records what changed in the behavior graph since a baseline.
+// Unchanged nodes and edges are absent from the maps.
+type GraphDiff struct {
+	// Base is when the baseline was taken: a backup's creation time or the
+	// timestamp compared against.
+	Base time.Time
+
+	// Nodes maps behavior IDs to their change status.
+	Nodes map[string]ChangeStatus
+
+	// Edges maps EdgeKey values to their change status.
+	Edges map[string]ChangeStatus
+
+	// PrunedNodes and PrunedEdges hold pruned items as they were in the
+	// baseline, so they can still be shown.
+	PrunedNodes []store.Node
+	PrunedEdges []store.Edge
+}
+
+// EdgeKey identifies an edge by its endpoints and kind.
+func EdgeKey(e store.Edge) string {
+	return fmt.Sprintf("%s:%s:%s", e.Source, e.Target, e.Kind)
+}
+
+// ReadBackup reads a V1 or V2 backup file without restoring it.
+func ReadBackup(inputPath string) (*BackupFormat, error) {
+	return readBackupAuto(inputPath)
+}
+
+// LatestBackup returns the path of the newest backup in dir.
+func LatestBackup(dir string) (string, error) {
+	backups, err := ListBackups(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(backups) == 0 {
+		return "", fmt.Errorf("no backups found in %s", dir)
+	}
+	return backups[0].Path, nil
+}
+
+// Diff compares the behavior graph in graphStore against a backup. Only
+// active behaviors are compared, so a behavior forgotten, merged, or
+// quarantined since the backup counts as pruned. Usage stats are ignored:
+// activations alone don't make a behavior modified.
+func Diff(ctx context.Context, graphStore store.GraphStore, base *BackupFormat) (*GraphDiff, error) {
+	nodes, edges, err := behaviorGraph(ctx, graphStore)
+	if err != nil {
+		return nil, err
+	}
+
+	baseNodes := make(map[string]store.Node)
+	for _, bn := range base.Nodes {
+		if bn.Kind == store.NodeKindBehavior {
+			baseNodes[bn.ID] = bn.Node
+		}
+	}
+	baseEdges := make(map[string]store.Edge)
+	for _, e := range base.Edges {
+		if _, ok := baseNodes[e.Source]; !ok {
+			continue
+		}
+		if _, ok := baseNodes[e.Target]; !ok {
+			continue
+		}
+		baseEdges[EdgeKey(e)] = e
+	}
+
+	diff := &GraphDiff{
+		Base:  base.CreatedAt,
+		Nodes: make(map[string]ChangeStatus),
+		Edges: make(map[string]ChangeStatus),
+	}
+
+	current := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		current[n.ID] = true
+		old, ok := baseNodes[n.ID]
+		switch {
+		case !ok:
+			diff.Nodes[n.ID] = ChangeNew
+		case !sameBehavior(old, n):
+			diff.Nodes[n.ID] = ChangeModified
+		}
+	}
+	for _, bn := range base.Nodes {
+		if bn.Kind == store.NodeKindBehavior && !current[bn.ID] {
+			diff.Nodes[bn.ID] = ChangePruned
+			diff.PrunedNodes = append(diff.PrunedNodes, bn.Node)
+		}
+	}
+
+	currentEdges := make(map[string]bool, len(edges))
+	for _, e := range edges {
+		key := EdgeKey(e)
+		currentEdges[key] = true
+		old, ok := baseEdges[key]
+		switch {
+		case !ok:
+			diff.Edges[key] = ChangeNew
+		case e.Weight > old.Weight+weightEpsilon:
+			diff.Edges[key] = ChangeWeightIncreased
+		case e.Weight < old.Weight-weightEpsilon:
+			diff.Edges[key] = ChangeWeightDecreased
+		}
+	}
+	for _, e := range base.Edges {
+		key := EdgeKey(e)
+		if _, ok := baseEdges[key]; ok && !currentEdges[key] {
+			diff.Edges[key] = ChangePruned
+			diff.PrunedEdges = append(diff.PrunedEdges, e)
+		}
+	}
+
+	return diff, nil
+}
+
+// DiffSince reports the behaviors and edges created or updated after since,
+// using the timestamps the store keeps. Without a baseline, pruned items and
+// weight changes can't be detected; use Diff with a backup for those.
+func DiffSince(ctx context.Context, graphStore store.GraphStore, since time.Time) (*GraphDiff, error) {
+	nodes, edges, err := behaviorGraph(ctx, graphStore)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &GraphDiff{
+		Base:  since,
+		Nodes: make(map[string]ChangeStatus),
+		Edges: make(map[string]ChangeStatus),
+	}
+	for _, n := range nodes {
+		created, updated := nodeTimes(n)
+		switch {
+		case created.After(since):
+			diff.Nodes[n.ID] = ChangeNew
+		case updated.After(since):
+			diff.Nodes[n.ID] = ChangeModified
+		}
+	}
+	for _, e := range edges {
+		if e.CreatedAt.After(since) {
+			diff.Edges[EdgeKey(e)] = ChangeNew
+		}
+	}
+	return diff, nil
+}
+
+// weightEpsilon absorbs float noise from the backup's JSON round trip.
+const weightEpsilon = 1e-9
+
+// behaviorGraph returns the active behaviors in graphStore and the edges
+// between them.
+func behaviorGraph(ctx context.Context, graphStore store.GraphStore) ([]store.Node, []store.Edge, error) {
+	nodes, err := graphStore.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query nodes: %w", err)
+	}
+	ids := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		ids[n.ID] = true
+	}
+
+	var edges []store.Edge
+	seen := make(map[string]bool)
+	for _, n := range nodes {
+		out, err := graphStore.GetEdges(ctx, n.ID, store.DirectionOutbound, "")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get edges for %s: %w", n.ID, err)
+		}
+		for _, e := range out {
+			key := EdgeKey(e)
+			if ids[e.Target] && !seen[key] {
+				seen[key] = true
+				edges = append(edges, e)
+			}
+		}
+	}
+	return nodes, edges, nil
+}
+
+// sameBehavior reports whether two versions of a behavior match in the
+// fields a user would call a change. Values are compared as JSON because a
+// backup's copy has been through a JSON round trip.
+func sameBehavior(a, b store.Node) bool {
+	return a.Kind == b.Kind && sameJSON(comparableFields(a), comparableFields(b))
+}
+
+func comparableFields(n store.Node) map[string]interface{} {
+	return map[string]interface{}{
+		"content":    n.Content,
+		"confidence": n.Metadata["confidence"],
+		"priority":   n.Metadata["priority"],
+	}
+}
+
+// sameJSON compares a and b by their JSON encoding, which sorts map keys.
+func sameJSON(a, b interface{}) bool {
+	ab, errA := json.Marshal(a)
+	bb, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return string(ab) == string(bb)
+}
+
+// nodeTimes returns when a behavior was created and last updated. The
+// provenance creation time is preferred because the store's own created_at
+// is reset when a behavior is rewritten.
+func nodeTimes(n store.Node) (created, updated time.Time) {
+	stats, _ := n.Metadata["stats"].(map[string]interface{})
+	created = parseTime(stats["created_at"])
+	updated = parseTime(stats["updated_at"])
+
+	for _, field := range []interface{}{n.Metadata["provenance"], n.Content["provenance"]} {
+		if p, ok := field.(map[string]interface{}); ok {
+			if t := parseTime(p["created_at"]); !t.IsZero() {
+				created = t
+				break
+			}
+		}
+	}
+	return created, updated
+}
+
+func parseTime(v interface{}) time.Time {
+	switch t := v.(type) {
+	case time.Time:
+		return t
+	case string:
+		if parsed, err := time.Parse(time.RFC3339, t); err == nil {
+			return parsed
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,165 @@
+package backup
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestDiff(t *testing.T) {
+	ctx := context.Background()
+	s := createTestStore(t)
+	defer s.Close()
+	addTestData(t, s)
+
+	path := filepath.Join(t.TempDir(), "base.json.gz")
+	if _, err := Backup(ctx, s, path); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	base, err := ReadBackup(path)
+	if err != nil {
+		t.Fatalf("ReadBackup() error = %v", err)
+	}
+
+	// No changes yet: activations and re-reads must not count
+	diff, err := Diff(ctx, s, base)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if len(diff.Nodes) != 0 || len(diff.Edges) != 0 {
+		t.Fatalf("Diff() of unchanged store = %v / %v, want empty", diff.Nodes, diff.Edges)
+	}
+
+	// node-a: modified; node-c: pruned (with its edge); node-d: new
+	nodeA, err := s.GetNode(ctx, "node-a")
+	if err != nil {
+		t.Fatalf("GetNode() error = %v", err)
+	}
+	nodeA.Content["content"] = map[string]interface{}{"canonical": "Rewritten content"}
+	if err := s.UpdateNode(ctx, *nodeA); err != nil {
+		t.Fatalf("UpdateNode() error = %v", err)
+	}
+	if err := s.DeleteNode(ctx, "node-c"); err != nil {
+		t.Fatalf("DeleteNode() error = %v", err)
+	}
+	if _, err := s.AddNode(ctx, store.Node{
+		ID:       "node-d",
+		Kind:     store.NodeKindBehavior,
+		Content:  map[string]interface{}{"name": "node-d", "kind": "directive", "content": map[string]interface{}{"canonical": "New"}},
+		Metadata: map[string]interface{}{"confidence": 0.7},
+	}); err != nil {
+		t.Fatalf("AddNode() error = %v", err)
+	}
+	if err := s.AddEdge(ctx, store.Edge{Source: "node-d", Target: "node-a", Kind: store.EdgeKindRequires, Weight: 0.5, CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("AddEdge() error = %v", err)
+	}
+	if err := s.BatchUpdateEdgeWeights(ctx, []store.EdgeWeightUpdate{
+		{Source: "node-a", Target: "node-b", Kind: store.EdgeKindRequires, NewWeight: 0.95},
+	}); err != nil {
+		t.Fatalf("BatchUpdateEdgeWeights() error = %v", err)
+	}
+
+	diff, err = Diff(ctx, s, base)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	wantNodes := map[string]ChangeStatus{"node-a": ChangeModified, "node-c": ChangePruned, "node-d": ChangeNew}
+	if len(diff.Nodes) != len(wantNodes) {
+		t.Errorf("Diff().Nodes = %v, want %v", diff.Nodes, wantNodes)
+	}
+	for id, want := range wantNodes {
+		if diff.Nodes[id] != want {
+			t.Errorf("node %s change = %q, want %q", id, diff.Nodes[id], want)
+		}
+	}
+	if len(diff.PrunedNodes) != 1 || diff.PrunedNodes[0].ID != "node-c" {
+		t.Errorf("PrunedNodes = %v, want node-c", diff.PrunedNodes)
+	}
+
+	wantEdges := map[string]ChangeStatus{
+		"node-a:node-b:requires":   ChangeWeightIncreased,
+		"node-b:node-c:similar-to": ChangePruned,
+		"node-d:node-a:requires":   ChangeNew,
+	}
+	for key, want := range wantEdges {
+		if diff.Edges[key] != want {
+			t.Errorf("edge %s change = %q, want %q", key, diff.Edges[key], want)
+		}
+	}
+	if len(diff.PrunedEdges) != 1 {
+		t.Errorf("PrunedEdges = %v, want 1", diff.PrunedEdges)
+	}
+	if !diff.Base.Equal(base.CreatedAt) {
+		t.Errorf("Base = %v, want %v", diff.Base, base.CreatedAt)
+	}
+}
+
+func TestDiffSince(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+
+	since := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	before := since.Add(-time.Hour).Format(time.RFC3339)
+	after := since.Add(time.Hour).Format(time.RFC3339)
+	for _, n := range []struct {
+		id, created, updated string
+	}{
+		{"old", before, before},
+		{"touched", before, after},
+		{"fresh", after, after},
+	} {
+		if _, err := s.AddNode(ctx, store.Node{
+			ID:   n.id,
+			Kind: store.NodeKindBehavior,
+			Metadata: map[string]interface{}{
+				"stats": map[string]interface{}{"created_at": n.created, "updated_at": n.updated},
+			},
+		}); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+	s.AddEdge(ctx, store.Edge{Source: "old", Target: "touched", Kind: store.EdgeKindRequires, Weight: 1, CreatedAt: since.Add(-time.Hour)})
+	s.AddEdge(ctx, store.Edge{Source: "fresh", Target: "old", Kind: store.EdgeKindRequires, Weight: 1, CreatedAt: since.Add(time.Hour)})
+
+	diff, err := DiffSince(ctx, s, since)
+	if err != nil {
+		t.Fatalf("DiffSince() error = %v", err)
+	}
+	want := map[string]ChangeStatus{"touched": ChangeModified, "fresh": ChangeNew}
+	if len(diff.Nodes) != len(want) {
+		t.Errorf("DiffSince().Nodes = %v, want %v", diff.Nodes, want)
+	}
+	for id, status := range want {
+		if diff.Nodes[id] != status {
+			t.Errorf("node %s change = %q, want %q", id, diff.Nodes[id], status)
+		}
+	}
+	if len(diff.Edges) != 1 || diff.Edges["fresh:old:requires"] != ChangeNew {
+		t.Errorf("DiffSince().Edges = %v, want fresh:old:requires new", diff.Edges)
+	}
+}
+
+func TestLatestBackup(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LatestBackup(dir); err == nil {
+		t.Error("LatestBackup() on empty dir should fail")
+	}
+
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+	path := GenerateBackupPath(dir)
+	if _, err := Backup(ctx, s, path); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	got, err := LatestBackup(dir)
+	if err != nil {
+		t.Fatalf("LatestBackup() error = %v", err)
+	}
+	if got != path {
+		t.Errorf("LatestBackup() = %q, want %q", got, path)
+	}
+}
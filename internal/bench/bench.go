@@ -0,0 +1,367 @@
+// Package bench measures the activation pipeline on synthetic graphs. It
+// fills a store with generated behaviors and edges, then repeatedly runs
+// seed selection, spreading activation, and tiering for rotating contexts,
+// recording the latency and allocations of each stage, so regressions in
+// the store or the spreading engine show up as numbers.
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/spreading"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/tiering"
+)
+
+// Stage names, in pipeline order.
+const (
+	StageSeeds     = "seeds"     // Store query and context evaluation
+	StageSpreading = "spreading" // Propagation from the seeds
+	StageTiering   = "tiering"   // Loading activated behaviors and mapping tiers
+	StageTotal     = "total"     // All of the above
+)
+
+// Languages and tasks the generated behaviors are conditioned on, and that
+// the benchmark contexts rotate through.
+var (
+	languages = []string{"go", "python", "typescript", "rust", "java"}
+	tasks     = []string{"testing", "refactor", "debugging", "review", "docs"}
+)
+
+// edgeKinds are the kinds of generated edges.
+var edgeKinds = []store.EdgeKind{
+	store.EdgeKindRequires,
+	store.EdgeKindSimilarTo,
+	store.EdgeKindCoActivated,
+	store.EdgeKindSpecializes,
+}
+
+// Config configures a benchmark run.
+type Config struct {
+	Behaviors   int   // Generated behavior nodes
+	Edges       int   // Generated edges between them
+	Iterations  int   // Measured pipeline runs
+	Warmup      int   // Unmeasured runs before the measured ones
+	Seed        int64 // Random seed of the generated graph
+	TokenBudget int   // Budget the tiering stage maps results into
+
+	Spreading spreading.Config
+}
+
+// DefaultConfig returns a Config for a mid-sized store.
+func DefaultConfig() Config {
+	return Config{
+		Behaviors:   1000,
+		Edges:       3000,
+		Iterations:  100,
+		Warmup:      5,
+		Seed:        1,
+		TokenBudget: 2000,
+		Spreading:   spreading.DefaultConfig(),
+	}
+}
+
+// StageResult is the latency and allocation profile of one stage.
+type StageResult struct {
+	Stage       string  `json:"stage"`
+	P50Ms       float64 `json:"p50_ms"`
+	P95Ms       float64 `json:"p95_ms"`
+	MeanMs      float64 `json:"mean_ms"`
+	MaxMs       float64 `json:"max_ms"`
+	AllocsPerOp uint64  `json:"allocs_per_op"`
+	BytesPerOp  uint64  `json:"bytes_per_op"`
+}
+
+// Report is the result of a benchmark run.
+type Report struct {
+	Behaviors   int           `json:"behaviors"`
+	Edges       int           `json:"edges"`
+	EdgeErrors  int           `json:"edge_errors,omitempty"` // Generated edges the store rejected
+	Iterations  int           `json:"iterations"`
+	SetupMs     float64       `json:"setup_ms"`
+	MeanSeeds   float64       `json:"mean_seeds"`
+	MeanResults float64       `json:"mean_results"`
+	Stages      []StageResult `json:"stages"`
+}
+
+// Generate returns n behavior nodes and m edges between them, drawn from
+// seed. About a fifth of the behaviors are unconditional; the rest apply to
+// a language, a task, or both. Self-loops and repeated edges are skipped,
+// so fewer than m edges are returned when n is too small to hold m.
+func Generate(n, m int, seed int64) ([]store.Node, []store.Edge) {
+	rng := rand.New(rand.NewSource(seed))
+	created := time.Now().Add(-30 * 24 * time.Hour)
+
+	nodes := make([]store.Node, 0, n)
+	for i := 0; i < n; i++ {
+		when := map[string]interface{}{}
+		switch rng.Intn(5) {
+		case 0:
+		case 1:
+			when["task"] = tasks[rng.Intn(len(tasks))]
+		case 2:
+			when["language"] = languages[rng.Intn(len(languages))]
+		default:
+			when["language"] = languages[rng.Intn(len(languages))]
+			when["task"] = tasks[rng.Intn(len(tasks))]
+		}
+		b := &models.Behavior{
+			ID:   fmt.Sprintf("bench-%06d", i),
+			Name: fmt.Sprintf("bench-behavior-%d", i),
+			Kind: models.BehaviorKindDirective,
+			When: when,
+			Content: models.BehaviorContent{
+				Canonical: fmt.Sprintf("Synthetic benchmark behavior %d: follow the %d-th convention of this codebase", i, i),
+				Summary:   fmt.Sprintf("Convention %d", i),
+			},
+			Provenance: models.Provenance{SourceType: models.SourceTypeAuthored, CreatedAt: created},
+			Confidence: 0.5 + rng.Float64()/2,
+		}
+		nodes = append(nodes, models.BehaviorToNode(b))
+	}
+
+	if n < 2 {
+		return nodes, nil
+	}
+	maxEdges := n * (n - 1) * len(edgeKinds)
+	if m > maxEdges {
+		m = maxEdges
+	}
+	seen := make(map[string]bool, m)
+	edges := make([]store.Edge, 0, m)
+	for len(edges) < m {
+		src, dst := rng.Intn(n), rng.Intn(n)
+		if src == dst {
+			continue
+		}
+		kind := edgeKinds[rng.Intn(len(edgeKinds))]
+		key := fmt.Sprintf("%d:%d:%s", src, dst, kind)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		edges = append(edges, store.Edge{
+			Source:    nodes[src].ID,
+			Target:    nodes[dst].ID,
+			Kind:      kind,
+			Weight:    0.2 + rng.Float64()*0.8,
+			CreatedAt: created,
+		})
+	}
+	return nodes, edges
+}
+
+// Contexts returns the contexts benchmark iterations rotate through.
+func Contexts() []models.ContextSnapshot {
+	contexts := make([]models.ContextSnapshot, 0, len(languages)*len(tasks))
+	for _, lang := range languages {
+		for _, task := range tasks {
+			contexts = append(contexts, models.ContextSnapshot{FileLanguage: lang, Task: task})
+		}
+	}
+	return contexts
+}
+
+// Run fills s with a generated graph and benchmarks the pipeline on it. s
+// should be empty; Run neither clears nor closes it.
+func Run(ctx context.Context, s store.GraphStore, cfg Config) (*Report, error) {
+	if cfg.Behaviors <= 0 || cfg.Iterations <= 0 {
+		return nil, fmt.Errorf("behaviors and iterations must be positive")
+	}
+
+	start := time.Now()
+	nodes, edges := Generate(cfg.Behaviors, cfg.Edges, cfg.Seed)
+	for _, node := range nodes {
+		if _, err := s.AddNode(ctx, node); err != nil {
+			return nil, fmt.Errorf("adding node %s: %w", node.ID, err)
+		}
+	}
+	edgeErrs, err := store.BatchAddEdges(ctx, s, edges)
+	if err != nil {
+		return nil, fmt.Errorf("adding edges: %w", err)
+	}
+	if err := s.Sync(ctx); err != nil {
+		return nil, fmt.Errorf("syncing store: %w", err)
+	}
+	report := &Report{
+		Behaviors:  len(nodes),
+		Edges:      len(edges) - len(edgeErrs),
+		EdgeErrors: len(edgeErrs),
+		Iterations: cfg.Iterations,
+		SetupMs:    durationMs(time.Since(start)),
+	}
+
+	r := &runner{
+		store:    s,
+		selector: spreading.NewSeedSelector(s),
+		engine:   spreading.NewEngine(s, cfg.Spreading),
+		mapper:   tiering.NewActivationTierMapper(tiering.DefaultActivationTierConfig()),
+		budget:   cfg.TokenBudget,
+	}
+	contexts := Contexts()
+	for i := 0; i < cfg.Warmup; i++ {
+		if _, err := r.run(ctx, contexts[i%len(contexts)]); err != nil {
+			return nil, err
+		}
+	}
+
+	samples := make(map[string][]sample)
+	var seeds, results int
+	for i := 0; i < cfg.Iterations; i++ {
+		it, err := r.run(ctx, contexts[i%len(contexts)])
+		if err != nil {
+			return nil, err
+		}
+		var total sample
+		for _, st := range it.stages {
+			samples[st.stage] = append(samples[st.stage], st.sample)
+			total.d += st.d
+			total.allocs += st.allocs
+			total.bytes += st.bytes
+		}
+		samples[StageTotal] = append(samples[StageTotal], total)
+		seeds += it.seeds
+		results += it.results
+	}
+	report.MeanSeeds = float64(seeds) / float64(cfg.Iterations)
+	report.MeanResults = float64(results) / float64(cfg.Iterations)
+	for _, stage := range []string{StageSeeds, StageSpreading, StageTiering, StageTotal} {
+		report.Stages = append(report.Stages, summarize(stage, samples[stage]))
+	}
+	return report, nil
+}
+
+// runner runs the pipeline stages against one store.
+type runner struct {
+	store    store.GraphStore
+	selector *spreading.SeedSelector
+	engine   *spreading.Engine
+	mapper   *tiering.ActivationTierMapper
+	budget   int
+}
+
+// sample is one measurement of a stage.
+type sample struct {
+	d      time.Duration
+	allocs uint64
+	bytes  uint64
+}
+
+type stageSample struct {
+	stage string
+	sample
+}
+
+// iteration is the measurements of one pipeline run.
+type iteration struct {
+	stages  []stageSample
+	seeds   int
+	results int
+}
+
+// run runs the pipeline once for actCtx.
+func (r *runner) run(ctx context.Context, actCtx models.ContextSnapshot) (*iteration, error) {
+	it := &iteration{}
+
+	var seeds []spreading.Seed
+	err := measure(it, StageSeeds, func() (err error) {
+		seeds, err = r.selector.SelectSeeds(ctx, actCtx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("seed selection: %w", err)
+	}
+	it.seeds = len(seeds)
+
+	var results []spreading.Result
+	err = measure(it, StageSpreading, func() (err error) {
+		results, err = r.engine.Activate(ctx, seeds)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("spreading activation: %w", err)
+	}
+	it.results = len(results)
+
+	err = measure(it, StageTiering, func() error {
+		behaviors := make(map[string]*models.Behavior, len(results))
+		for _, res := range results {
+			node, err := r.store.GetNode(ctx, res.BehaviorID)
+			if err != nil {
+				return err
+			}
+			if node != nil {
+				b := models.NodeToBehavior(*node)
+				behaviors[res.BehaviorID] = &b
+			}
+		}
+		r.mapper.MapResults(results, behaviors, r.budget)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("tiering: %w", err)
+	}
+	return it, nil
+}
+
+// measure runs fn and records its latency and allocations as stage of it.
+func measure(it *iteration, stage string, fn func() error) error {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	err := fn()
+	d := time.Since(start)
+	runtime.ReadMemStats(&after)
+	it.stages = append(it.stages, stageSample{stage: stage, sample: sample{
+		d:      d,
+		allocs: after.Mallocs - before.Mallocs,
+		bytes:  after.TotalAlloc - before.TotalAlloc,
+	}})
+	return err
+}
+
+// summarize reduces the samples of stage to percentiles and means.
+func summarize(stage string, samples []sample) StageResult {
+	res := StageResult{Stage: stage}
+	if len(samples) == 0 {
+		return res
+	}
+	durations := make([]time.Duration, len(samples))
+	var sum time.Duration
+	var allocs, bytes uint64
+	for i, s := range samples {
+		durations[i] = s.d
+		sum += s.d
+		allocs += s.allocs
+		bytes += s.bytes
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	n := uint64(len(samples))
+	res.P50Ms = durationMs(quantile(durations, 0.5))
+	res.P95Ms = durationMs(quantile(durations, 0.95))
+	res.MeanMs = durationMs(sum / time.Duration(n))
+	res.MaxMs = durationMs(durations[len(durations)-1])
+	res.AllocsPerOp = allocs / n
+	res.BytesPerOp = bytes / n
+	return res
+}
+
+// quantile returns the nearest-rank quantile q of sorted.
+func quantile(sorted []time.Duration, q float64) time.Duration {
+	rank := int(math.Ceil(q * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,80 @@
+package bench
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestGenerate(t *testing.T) {
+	nodes, edges := Generate(50, 120, 7)
+	if len(nodes) != 50 || len(edges) != 120 {
+		t.Fatalf("Generate() = %d nodes, %d edges, want 50 and 120", len(nodes), len(edges))
+	}
+	seen := make(map[string]bool)
+	for _, e := range edges {
+		if e.Source == e.Target {
+			t.Errorf("self-loop on %s", e.Source)
+		}
+		key := e.Source + e.Target + string(e.Kind)
+		if seen[key] {
+			t.Errorf("duplicate edge %s -%s-> %s", e.Source, e.Kind, e.Target)
+		}
+		seen[key] = true
+	}
+
+	again, _ := Generate(50, 120, 7)
+	if !reflect.DeepEqual(nodes[10].Content["when"], again[10].Content["when"]) {
+		t.Error("Generate() is not deterministic for a seed")
+	}
+
+	// A graph too small for the requested edges is capped
+	if _, edges := Generate(2, 100, 1); len(edges) != 2*len(edgeKinds) {
+		t.Errorf("Generate(2, 100) = %d edges, want %d", len(edges), 2*len(edgeKinds))
+	}
+}
+
+func TestRun(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Behaviors = 200
+	cfg.Edges = 600
+	cfg.Iterations = 10
+	cfg.Warmup = 1
+
+	report, err := Run(context.Background(), store.NewInMemoryGraphStore(), cfg)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if report.Behaviors != 200 || report.Edges != 600 || report.Iterations != 10 {
+		t.Errorf("report = %+v", report)
+	}
+	if report.MeanSeeds == 0 || report.MeanResults == 0 {
+		t.Errorf("no seeds or results: %+v", report)
+	}
+
+	want := []string{StageSeeds, StageSpreading, StageTiering, StageTotal}
+	if len(report.Stages) != len(want) {
+		t.Fatalf("Stages = %+v, want %v", report.Stages, want)
+	}
+	for i, st := range report.Stages {
+		if st.Stage != want[i] {
+			t.Errorf("Stages[%d] = %s, want %s", i, st.Stage, want[i])
+		}
+		if st.P50Ms > st.P95Ms || st.P95Ms > st.MaxMs {
+			t.Errorf("%s percentiles out of order: %+v", st.Stage, st)
+		}
+	}
+	if total := report.Stages[3]; total.AllocsPerOp == 0 {
+		t.Errorf("total allocs/op = 0")
+	}
+}
+
+func TestRunInvalid(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Iterations = 0
+	if _, err := Run(context.Background(), store.NewInMemoryGraphStore(), cfg); err == nil {
+		t.Error("Run() with no iterations succeeded, want an error")
+	}
+}
@@ -0,0 +1,379 @@
+// Package brief builds a concise overview of what floop knows: the
+// strongest behaviors per tag, recent learnings, known conflicts, and repo
+// areas that keep drawing corrections without guidance. It renders as
+// Markdown for people (onboarding docs) or for agents (the start of a
+// session).
+package brief
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/heatmap"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// Audience selects how a brief is rendered.
+type Audience string
+
+const (
+	AudienceHuman Audience = "human"
+	AudienceAgent Audience = "agent"
+)
+
+// ParseAudience validates an audience name.
+func ParseAudience(s string) (Audience, error) {
+	switch Audience(s) {
+	case AudienceHuman, AudienceAgent:
+		return Audience(s), nil
+	}
+	return "", fmt.Errorf("invalid audience %q (use human or agent)", s)
+}
+
+// Untagged groups behaviors that have no tags.
+const Untagged = "untagged"
+
+// Options controls what a brief includes.
+type Options struct {
+	MaxTags      int           // Tags to show, most behaviors first
+	PerTag       int           // Behaviors shown per tag
+	Recent       int           // Recent learnings to show
+	RecentWindow time.Duration // How far back recent learnings go
+	MaxGaps      int           // Coverage gaps to show
+	Now          time.Time
+}
+
+// DefaultOptions returns the options 'floop brief' uses.
+func DefaultOptions() Options {
+	return Options{
+		MaxTags:      8,
+		PerTag:       3,
+		Recent:       5,
+		RecentWindow: 14 * 24 * time.Hour,
+		MaxGaps:      5,
+		Now:          time.Now(),
+	}
+}
+
+// Entry is one behavior in a brief.
+type Entry struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Kind        string    `json:"kind"`
+	Canonical   string    `json:"canonical"`
+	Confidence  float64   `json:"confidence"`
+	Activations int       `json:"activations"`
+	Origin      string    `json:"origin,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TagGroup is a tag with its strongest behaviors.
+type TagGroup struct {
+	Tag       string  `json:"tag"`
+	Count     int     `json:"count"` // All behaviors with the tag
+	Behaviors []Entry `json:"behaviors"`
+}
+
+// Conflict is a pair of behaviors marked as mutually exclusive.
+type Conflict struct {
+	A Entry `json:"a"`
+	B Entry `json:"b"`
+}
+
+// Brief is the overview of a set of behaviors.
+type Brief struct {
+	GeneratedAt time.Time      `json:"generated_at"`
+	Behaviors   int            `json:"behaviors"`
+	ByKind      map[string]int `json:"by_kind"`
+	ByOrigin    map[string]int `json:"by_origin"`
+	Tags        []TagGroup     `json:"tags"`
+	Recent      []Entry        `json:"recent"`
+	Conflicts   []Conflict     `json:"conflicts"`
+	Gaps        []heatmap.Row  `json:"gaps"`
+}
+
+// Build summarizes behaviors. Seed meta-behaviors and expired behaviors
+// are left out. gaps are heatmap rows; only those marked as gaps are kept.
+func Build(behaviors []models.Behavior, gaps []heatmap.Row, opts Options) *Brief {
+	b := &Brief{
+		GeneratedAt: opts.Now,
+		ByKind:      make(map[string]int),
+		ByOrigin:    make(map[string]int),
+		Tags:        []TagGroup{},
+		Recent:      []Entry{},
+		Conflicts:   []Conflict{},
+		Gaps:        []heatmap.Row{},
+	}
+
+	var kept []models.Behavior
+	byID := make(map[string]Entry)
+	for _, beh := range behaviors {
+		if strings.HasPrefix(beh.ID, "seed-") || beh.IsExpired(opts.Now) {
+			continue
+		}
+		kept = append(kept, beh)
+		byID[beh.ID] = entry(beh)
+		b.ByKind[string(beh.Kind)]++
+		if beh.Origin != "" {
+			b.ByOrigin[beh.Origin]++
+		}
+	}
+	b.Behaviors = len(kept)
+
+	// Strongest behaviors per tag
+	tagged := make(map[string][]Entry)
+	for _, beh := range kept {
+		tags := beh.Content.Tags
+		if len(tags) == 0 {
+			tags = []string{Untagged}
+		}
+		for _, tag := range tags {
+			tagged[tag] = append(tagged[tag], byID[beh.ID])
+		}
+	}
+	for tag, entries := range tagged {
+		sort.Slice(entries, func(i, j int) bool { return stronger(entries[i], entries[j]) })
+		group := TagGroup{Tag: tag, Count: len(entries), Behaviors: entries}
+		if opts.PerTag > 0 && len(group.Behaviors) > opts.PerTag {
+			group.Behaviors = group.Behaviors[:opts.PerTag]
+		}
+		b.Tags = append(b.Tags, group)
+	}
+	sort.Slice(b.Tags, func(i, j int) bool {
+		// Untagged behaviors go last whatever their number
+		if (b.Tags[i].Tag == Untagged) != (b.Tags[j].Tag == Untagged) {
+			return b.Tags[j].Tag == Untagged
+		}
+		if b.Tags[i].Count != b.Tags[j].Count {
+			return b.Tags[i].Count > b.Tags[j].Count
+		}
+		return b.Tags[i].Tag < b.Tags[j].Tag
+	})
+	if opts.MaxTags > 0 && len(b.Tags) > opts.MaxTags {
+		b.Tags = b.Tags[:opts.MaxTags]
+	}
+
+	// Recent learnings, newest first
+	for _, beh := range kept {
+		e := byID[beh.ID]
+		if opts.RecentWindow > 0 && e.CreatedAt.Before(opts.Now.Add(-opts.RecentWindow)) {
+			continue
+		}
+		b.Recent = append(b.Recent, e)
+	}
+	sort.Slice(b.Recent, func(i, j int) bool {
+		if !b.Recent[i].CreatedAt.Equal(b.Recent[j].CreatedAt) {
+			return b.Recent[i].CreatedAt.After(b.Recent[j].CreatedAt)
+		}
+		return b.Recent[i].ID < b.Recent[j].ID
+	})
+	if opts.Recent >= 0 && len(b.Recent) > opts.Recent {
+		b.Recent = b.Recent[:opts.Recent]
+	}
+
+	// Conflicts between kept behaviors, each pair once
+	seen := make(map[[2]string]bool)
+	for _, beh := range kept {
+		for _, other := range beh.Conflicts {
+			if _, ok := byID[other]; !ok {
+				continue
+			}
+			pair := [2]string{beh.ID, other}
+			if pair[1] < pair[0] {
+				pair[0], pair[1] = pair[1], pair[0]
+			}
+			if seen[pair] {
+				continue
+			}
+			seen[pair] = true
+			b.Conflicts = append(b.Conflicts, Conflict{A: byID[pair[0]], B: byID[pair[1]]})
+		}
+	}
+	sort.Slice(b.Conflicts, func(i, j int) bool {
+		if b.Conflicts[i].A.ID != b.Conflicts[j].A.ID {
+			return b.Conflicts[i].A.ID < b.Conflicts[j].A.ID
+		}
+		return b.Conflicts[i].B.ID < b.Conflicts[j].B.ID
+	})
+
+	for _, row := range gaps {
+		if !row.Gap {
+			continue
+		}
+		if opts.MaxGaps > 0 && len(b.Gaps) >= opts.MaxGaps {
+			break
+		}
+		b.Gaps = append(b.Gaps, row)
+	}
+	return b
+}
+
+// entry converts a behavior to a brief entry.
+func entry(b models.Behavior) Entry {
+	created := b.Provenance.CreatedAt
+	if created.IsZero() {
+		created = b.Stats.CreatedAt
+	}
+	canonical := b.Content.Canonical
+	if canonical == "" {
+		canonical = b.Content.Summary
+	}
+	return Entry{
+		ID:          b.ID,
+		Name:        b.Name,
+		Kind:        string(b.Kind),
+		Canonical:   strings.Join(strings.Fields(canonical), " "),
+		Confidence:  b.Confidence,
+		Activations: b.Stats.TimesActivated,
+		Origin:      b.Origin,
+		CreatedAt:   created,
+	}
+}
+
+// stronger orders entries by activations, then confidence, then ID.
+func stronger(a, b Entry) bool {
+	if a.Activations != b.Activations {
+		return a.Activations > b.Activations
+	}
+	if a.Confidence != b.Confidence {
+		return a.Confidence > b.Confidence
+	}
+	return a.ID < b.ID
+}
+
+// Render writes the brief as Markdown for audience.
+func Render(w io.Writer, b *Brief, audience Audience) error {
+	var sb strings.Builder
+	if audience == AudienceAgent {
+		renderAgent(&sb, b)
+	} else {
+		renderHuman(&sb, b)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// renderHuman writes an overview for an onboarding doc: counts, then each
+// section with names, confidence, and activation counts.
+func renderHuman(sb *strings.Builder, b *Brief) {
+	sb.WriteString("# What floop knows\n\n")
+	if b.Behaviors == 0 {
+		sb.WriteString("No behaviors learned yet. Corrections captured with `floop learn` show up here.\n")
+		return
+	}
+	fmt.Fprintf(sb, "%d behaviors", b.Behaviors)
+	if parts := counts(b.ByKind); parts != "" {
+		fmt.Fprintf(sb, " (%s)", parts)
+	}
+	if parts := counts(b.ByOrigin); parts != "" {
+		fmt.Fprintf(sb, ", stored %s", parts)
+	}
+	fmt.Fprintf(sb, ". Generated %s.\n", b.GeneratedAt.Format("2006-01-02"))
+
+	if len(b.Tags) > 0 {
+		sb.WriteString("\n## Top behaviors by tag\n")
+		for _, g := range b.Tags {
+			fmt.Fprintf(sb, "\n### %s (%d)\n\n", g.Tag, g.Count)
+			for _, e := range g.Behaviors {
+				fmt.Fprintf(sb, "- **%s**: %s _(confidence %.2f, activated %d times)_\n", e.Name, e.Canonical, e.Confidence, e.Activations)
+			}
+		}
+	}
+
+	if len(b.Recent) > 0 {
+		sb.WriteString("\n## Recently learned\n\n")
+		for _, e := range b.Recent {
+			fmt.Fprintf(sb, "- %s **%s**: %s\n", e.CreatedAt.Format("2006-01-02"), e.Name, e.Canonical)
+		}
+	}
+
+	if len(b.Conflicts) > 0 {
+		sb.WriteString("\n## Known conflicts\n\n")
+		for _, c := range b.Conflicts {
+			fmt.Fprintf(sb, "- **%s** conflicts with **%s**\n", c.A.Name, c.B.Name)
+		}
+	}
+
+	if len(b.Gaps) > 0 {
+		sb.WriteString("\n## Coverage gaps\n\nAreas the agent was corrected in where no behavior has been active yet:\n\n")
+		for _, g := range b.Gaps {
+			fmt.Fprintf(sb, "- `%s`: %d correction(s)\n", g.Path, g.Corrections)
+		}
+	}
+}
+
+// renderAgent writes guidance for the start of an agent session: the
+// behaviors as instructions, each once, and what to be careful about.
+func renderAgent(sb *strings.Builder, b *Brief) {
+	sb.WriteString("# Project conventions learned by floop\n\n")
+	if b.Behaviors == 0 {
+		sb.WriteString("No conventions learned yet. When corrected, capture the correction with floop_learn.\n")
+		return
+	}
+
+	shown := make(map[string]bool)
+	if len(b.Tags) > 0 {
+		sb.WriteString("Follow these conventions, strongest first:\n")
+		for _, g := range b.Tags {
+			var lines []string
+			for _, e := range g.Behaviors {
+				if shown[e.ID] {
+					continue
+				}
+				shown[e.ID] = true
+				lines = append(lines, "- "+e.Canonical)
+			}
+			if len(lines) == 0 {
+				continue
+			}
+			fmt.Fprintf(sb, "\n## %s\n%s\n", g.Tag, strings.Join(lines, "\n"))
+		}
+	}
+
+	var recent []string
+	for _, e := range b.Recent {
+		if !shown[e.ID] {
+			recent = append(recent, "- "+e.Canonical)
+		}
+	}
+	if len(recent) > 0 {
+		fmt.Fprintf(sb, "\n## Recently learned\n%s\n", strings.Join(recent, "\n"))
+	}
+
+	if len(b.Conflicts) > 0 {
+		sb.WriteString("\n## Conflicting guidance\nThese pairs contradict each other; ask which applies before following either:\n")
+		for _, c := range b.Conflicts {
+			fmt.Fprintf(sb, "- \"%s\" vs \"%s\"\n", c.A.Canonical, c.B.Canonical)
+		}
+	}
+
+	if len(b.Gaps) > 0 {
+		paths := make([]string, len(b.Gaps))
+		for i, g := range b.Gaps {
+			paths[i] = "`" + g.Path + "`"
+		}
+		fmt.Fprintf(sb, "\n## Areas without guidance\nYou have been corrected in %s, where no learned behavior has applied yet. Confirm conventions before changing them, and capture corrections with floop_learn.\n", strings.Join(paths, ", "))
+	}
+}
+
+// counts renders a count map as "3 directive, 1 constraint", largest first.
+func counts(m map[string]int) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if m[keys[i]] != m[keys[j]] {
+			return m[keys[i]] > m[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%d %s", m[k], k)
+	}
+	return strings.Join(parts, ", ")
+}
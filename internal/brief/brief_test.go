@@ -0,0 +1,137 @@
+package brief
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/heatmap"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func testBehavior(id, canonical string, tags []string, activations int, created time.Time) models.Behavior {
+	return models.Behavior{
+		ID:         id,
+		Name:       "learned/" + id,
+		Kind:       models.BehaviorKindDirective,
+		Content:    models.BehaviorContent{Canonical: canonical, Tags: tags},
+		Confidence: 0.7,
+		Provenance: models.Provenance{CreatedAt: created},
+		Stats:      models.BehaviorStats{TimesActivated: activations},
+		Origin:     "local",
+	}
+}
+
+func TestParseAudience(t *testing.T) {
+	for _, s := range []string{"human", "agent"} {
+		if a, err := ParseAudience(s); err != nil || string(a) != s {
+			t.Errorf("ParseAudience(%q) = %q, %v", s, a, err)
+		}
+	}
+	if _, err := ParseAudience("robot"); err == nil {
+		t.Error("ParseAudience(robot) should fail")
+	}
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-14 * 24 * time.Hour).Add(-time.Hour)
+	expired := now.Add(-time.Hour)
+
+	uv := testBehavior("b-uv", "use uv for python packages", []string{"python"}, 9, past)
+	pip := testBehavior("b-pip", "pin pip versions in requirements.txt", []string{"python"}, 2, now.Add(-time.Hour))
+	pip.Conflicts = []string{"b-uv"}
+	uv.Conflicts = []string{"b-pip"}
+	pytest := testBehavior("b-pytest", "use pytest fixtures", []string{"python", "testing"}, 5, past)
+	wrap := testBehavior("b-wrap", "wrap errors with %w", []string{"go"}, 1, now.Add(-2*time.Hour))
+	loose := testBehavior("b-loose", "keep commits small", nil, 0, now.Add(-3*time.Hour))
+	old := testBehavior("b-old", "use the v1 API", []string{"go"}, 50, past)
+	old.ExpiresAt = &expired
+	seed := testBehavior("seed-know-floop-tools", "use floop", nil, 100, past)
+
+	gaps := []heatmap.Row{
+		{Path: "docs", Corrections: 3, Gap: true},
+		{Path: "internal/store", Corrections: 1, Guidance: 4},
+	}
+
+	opts := DefaultOptions()
+	opts.Now = now
+	opts.PerTag = 2
+	b := Build([]models.Behavior{uv, pip, pytest, wrap, loose, old, seed}, gaps, opts)
+
+	if b.Behaviors != 5 {
+		t.Errorf("Behaviors = %d, want 5 (seed and expired left out)", b.Behaviors)
+	}
+	if b.ByKind["directive"] != 5 || b.ByOrigin["local"] != 5 {
+		t.Errorf("ByKind = %v, ByOrigin = %v", b.ByKind, b.ByOrigin)
+	}
+
+	var tags []string
+	for _, g := range b.Tags {
+		tags = append(tags, g.Tag)
+	}
+	if strings.Join(tags, ",") != "python,go,testing,untagged" {
+		t.Errorf("tags = %v, want python,go,testing,untagged", tags)
+	}
+	python := b.Tags[0]
+	if python.Count != 3 || len(python.Behaviors) != 2 || python.Behaviors[0].ID != "b-uv" || python.Behaviors[1].ID != "b-pytest" {
+		t.Errorf("python = %+v, want 3 behaviors with the 2 most activated shown", python)
+	}
+
+	var recent []string
+	for _, e := range b.Recent {
+		recent = append(recent, e.ID)
+	}
+	if strings.Join(recent, ",") != "b-pip,b-wrap,b-loose" {
+		t.Errorf("recent = %v, want b-pip,b-wrap,b-loose", recent)
+	}
+
+	if len(b.Conflicts) != 1 || b.Conflicts[0].A.ID != "b-pip" || b.Conflicts[0].B.ID != "b-uv" {
+		t.Errorf("conflicts = %+v, want the b-pip/b-uv pair once", b.Conflicts)
+	}
+	if len(b.Gaps) != 1 || b.Gaps[0].Path != "docs" {
+		t.Errorf("gaps = %+v, want docs only", b.Gaps)
+	}
+}
+
+func TestRender(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	uv := testBehavior("b-uv", "use uv for python packages", []string{"python"}, 9, now)
+	pip := testBehavior("b-pip", "pin pip versions", []string{"python"}, 2, now)
+	pip.Conflicts = []string{"b-uv"}
+	opts := DefaultOptions()
+	opts.Now = now
+	b := Build([]models.Behavior{uv, pip}, []heatmap.Row{{Path: "docs", Corrections: 2, Gap: true}}, opts)
+
+	var human strings.Builder
+	if err := Render(&human, b, AudienceHuman); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# What floop knows", "2 behaviors", "### python (2)", "**learned/b-uv**: use uv", "## Known conflicts", "`docs`: 2 correction(s)"} {
+		if !strings.Contains(human.String(), want) {
+			t.Errorf("human brief missing %q:\n%s", want, human.String())
+		}
+	}
+
+	var agent strings.Builder
+	if err := Render(&agent, b, AudienceAgent); err != nil {
+		t.Fatal(err)
+	}
+	out := agent.String()
+	for _, want := range []string{"Follow these conventions", "- use uv for python packages", "\"pin pip versions\" vs \"use uv for python packages\"", "`docs`"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("agent brief missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "## Recently learned") {
+		t.Errorf("agent brief repeats behaviors already listed:\n%s", out)
+	}
+	if strings.Contains(out, "confidence") || strings.Contains(out, "learned/b-uv") {
+		t.Errorf("agent brief should give instructions, not store details:\n%s", out)
+	}
+
+	var empty strings.Builder
+	if err := Render(&empty, Build(nil, nil, opts), AudienceAgent); err != nil || !strings.Contains(empty.String(), "No conventions learned yet") {
+		t.Errorf("empty agent brief = %q, %v", empty.String(), err)
+	}
+}
@@ -0,0 +1,193 @@
+// Package capabilities describes what this floop build supports, so
+// integrators can feature-detect instead of parsing version strings.
+package capabilities
+
+import (
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Features lists the feature flags this build supports. A flag is only ever
+// added; integrators test for presence.
+var Features = []string{
+	"learn",                // floop learn / floop_learn
+	"spreading-activation", // graph-based activation from context seeds
+	"query-filters",        // floop_query kind/tag/confidence/text/related_to filters
+	"feedback",             // floop_feedback confirmed/overridden signals
+	"feedback-batch",       // floop_feedback_batch end-of-session reconciliation
+	"deduplication",        // floop deduplicate / floop_deduplicate
+	"quality-gate",         // low-quality corrections are held for review
+	"expiry",               // behaviors with expires_at are deprecated when due
+	"retirement",           // retired behaviors with a grace period
+	"backup-restore",       // floop backup / restore-backup
+	"restore-points",       // automatic restore points before destructive operations
+	"skill-packs",          // floop pack and floop_pack_install
+	"consolidation",        // floop_observe events and floop_consolidate
+	"sleep-phase",          // floop consolidate sleep
+	"external-ranking",     // ranking.external.* re-ranking hook
+	"publish",              // read-only snapshots for other tools
+	"mcp-prewarm",          // activation computed on MCP initialize
+	"self-update",          // floop self-update
+	"similar",              // floop similar / floop_similar search by example
+	"failure-capture",      // floop_report_failure and floop failures review queue
+	"outcomes",             // floop_record_outcome and floop outcomes, feeding recalibration
+	"injection-quarantine", // injection analysis at learn time and floop quarantine review
+	"rate-limit-hints",     // structured retry data on rate-limited MCP calls
+	"learning-policy",      // learning.* auto-accept, merge, review, and scope policy
+	"corrections-export",   // floop export-corrections CSV/Parquet datasets
+	"markdown-packs",       // floop export / import Markdown behavior packs
+	"changelog",            // changelog.enabled appends behavior changes to .floop/CHANGELOG.md
+	"git-sync",             // floop sync push / pull through a shared git branch
+	"embedding-dedup",      // deduplication.similarity embedding backend with cached vectors
+	"git-merge-driver",     // floop integrate git / git-merge-driver for .floop JSONL files
+	"hnsw-index",           // persisted pure-Go ANN index for floop_active when LanceDB is unavailable
+	"watch-daemon",         // floop watch serves floop active over .floop/watch.sock
+	"behavior-tests",       // floop test-behaviors runs .floop/tests.yaml activation assertions
+	"differential-dedup",   // floop_deduplicate and sleep dedup compare only new or changed behaviors
+	"tool-metrics",         // per-tool MCP metrics via floop stats --tools and mcp-server --metrics-addr
+	"behavior-decay",       // floop decay demotes and retires behaviors unused for decay.*_after_days
+	"store-partitions",     // floop rebalance splits the global store into lazily opened partitions
+	"remote-prompt",        // floop serve HTTP mode and floop remote-prompt thin client
+	"behavior-review",      // floop review queue for behaviors the learning loop flagged
+	"learn-breaker",        // floop_learn circuit breaker for session caps and repetitive corrections
+	"when-operators",       // not, glob, regex, and any when-conditions with floop why sub-clauses
+	"git-context",          // --git-context staged_files, changed_paths, and commit_message conditions
+	"behavior-files",       // floop apply syncs .floop/behaviors/*.yaml with drift detection
+	"context-pin",          // floop_pin_context freezes the active set for a task behind a handle
+	"scope-chain",          // stores.scopes read-only scope stores with scope:<name> origins
+	"pack-registry",        // floop pack install name@version from signed registries, pack remove --purge
+	"learn-batch",          // floop learn --from-file and floop_learn_batch with per-item status
+	"heatmap",              // floop heatmap guidance vs correction density per repo path
+	"brief",                // floop brief onboarding overview for human or agent audiences
+	"llm-extractor",        // learning.extractor llm generalizes corrections with rule fallback
+	"edge-half-life",       // activation.edge_half_life edge decay with effective weights in floop_graph
+	"name-lookup",          // show, why, curation commands, and expand resource resolve names through an index
+	"graph-subgraph",       // floop graph --focus and --min-weight export a weighted neighborhood
+	"tag-hierarchy",        // hierarchical tags, floop tags add/remove/list, and tags when-conditions via active --tags
+	"cli-progress",         // progress bars or JSONL progress events and Ctrl-C partial results for long commands
+	"active-set-stability", // floop stats active-set stability score and churn warnings from floop_active
+	"session-isolation",    // per-client MCP sessions with their own implicit confirmations, and floop_session_info
+	"store-replication",    // replication.target ships store changes on every sync, and floop failover promotes the replica
+	"restore-conflicts",    // merge restores list conflicting nodes and resolve them by --strategy, --interactive, or floop_restore strategy
+	"prompt-budgets",       // prompt.budgets per-kind token budgets, pinned behaviors, and value packing for floop prompt
+	"schema-introspection", // floop schema --json emits the SQLite, JSONL, and backup formats as JSON Schema
+	"bench",                // floop bench reports per-stage latency and allocations of the activation pipeline on a synthetic graph
+	"temporal-ranking",     // ranking.temporal ranks time/ and workload/ tagged behaviors by time of day, day of week, and session workload
+	"libfloop",             // C-shared library exposing learn, active, and prompt over a JSON C ABI for in-process embedding
+	"full-text-search",     // floop search / floop_search keyword lookup over an FTS5 index of behavior names, content, and tags
+	"starter-packs",        // embedded go-backend, python, and react packs via floop init --pack and floop pack install <name>
+	"tool-deadlines",       // tool_timeouts per-tool MCP deadlines with deadline_exceeded errors and partial floop_active results
+	"activation-trace",     // floop why --trace / floop_explain show the edges, weights, and decay that carried activation to a behavior
+	"client-rate-limits",   // MCP rate limits per client session under rate_limit.global_ceiling, with limiter state in floop_session_info
+	"flush",                // floop flush / flush: true on MCP read tools wait for background stat and Hebbian writes
+	"adr-import",           // floop import --from adr turns architecture decision records into behaviors
+	"corrections-db",       // corrections live in floop.db; floop list --corrections / floop_list filter by time and processed state and page
+	"when-merge",           // merge and dedup combine conflicting when-conditions into lists or any OR-groups, or refuse with the reason
+	"stats-health",         // floop stats --health dashboard: activation, override ratio, stale behaviors, edge growth, store size
+	"event-bus",            // mcp-server emits behavior.learned/merged/forgotten and backup.created events to file, webhook, and stdout sinks
+	"promote",              // floop promote / floop_promote move confirmed local behaviors into the global store; --suggest lists candidates
+	"policy-store",         // stores.policy read-only policy store below the scope chain, with policy origins
+}
+
+// MCPTools lists the tools registered by "floop mcp-server".
+var MCPTools = []string{
+	"floop_active",
+	"floop_pin_context",
+	"floop_learn",
+	"floop_learn_batch",
+	"floop_report_failure",
+	"floop_record_outcome",
+	"floop_session_info",
+	"floop_list",
+	"floop_query",
+	"floop_deduplicate",
+	"floop_backup",
+	"floop_restore",
+	"floop_connect",
+	"floop_validate",
+	"floop_graph",
+	"floop_feedback",
+	"floop_feedback_batch",
+	"floop_pack_install",
+	"floop_observe",
+	"floop_consolidate",
+	"floop_similar",
+	"floop_search",
+	"floop_explain",
+	"floop_promote",
+}
+
+// MCPResources lists the resource URIs and URI templates served by
+// "floop mcp-server".
+var MCPResources = []string{
+	"floop://behaviors/active",
+	"floop://behaviors/expand/{id}",
+}
+
+// EdgeKinds lists every edge kind the store accepts, user-facing or not.
+var EdgeKinds = []store.EdgeKind{
+	store.EdgeKindRequires,
+	store.EdgeKindOverrides,
+	store.EdgeKindConflicts,
+	store.EdgeKindSimilarTo,
+	store.EdgeKindLearnedFrom,
+	store.EdgeKindCoActivated,
+	store.EdgeKindDeprecatedTo,
+	store.EdgeKindMergedInto,
+	store.EdgeKindSpecializes,
+}
+
+// Capabilities is the machine-readable description returned by
+// "floop capabilities --json" and in the MCP initialize result.
+type Capabilities struct {
+	// Version is the floop version string.
+	Version string `json:"version"`
+
+	// SchemaVersion is the SQLite store schema version.
+	SchemaVersion int `json:"schema_version"`
+
+	// Features lists supported feature flags.
+	Features []string `json:"features"`
+
+	// Tools and Resources list the MCP server's tools and resources.
+	Tools     []string `json:"tools"`
+	Resources []string `json:"resources"`
+
+	// EdgeKinds lists every edge kind; UserEdgeKinds the subset accepted by
+	// floop connect and floop_connect.
+	EdgeKinds     []string `json:"edge_kinds"`
+	UserEdgeKinds []string `json:"user_edge_kinds"`
+
+	// ConfigKeys lists the keys accepted by floop config get/set.
+	ConfigKeys []string `json:"config_keys"`
+}
+
+// Describe returns the capabilities of this build, reporting version as its
+// version string.
+func Describe(version string) Capabilities {
+	c := Capabilities{
+		Version:       version,
+		SchemaVersion: store.SchemaVersion,
+		Features:      append([]string(nil), Features...),
+		Tools:         append([]string(nil), MCPTools...),
+		Resources:     append([]string(nil), MCPResources...),
+		ConfigKeys:    append([]string(nil), config.Keys...),
+	}
+	for _, k := range EdgeKinds {
+		c.EdgeKinds = append(c.EdgeKinds, string(k))
+		if store.ValidUserEdgeKinds[k] {
+			c.UserEdgeKinds = append(c.UserEdgeKinds, string(k))
+		}
+	}
+	return c
+}
+
+// Has reports whether feature is among c's features.
+func (c Capabilities) Has(feature string) bool {
+	for _, f := range c.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package capabilities
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestDescribe(t *testing.T) {
+	c := Describe("1.2.3")
+
+	if c.Version != "1.2.3" || c.SchemaVersion != store.SchemaVersion {
+		t.Errorf("Version/SchemaVersion = %q/%d", c.Version, c.SchemaVersion)
+	}
+	if !c.Has("self-update") || c.Has("no-such-feature") {
+		t.Error("Has() should report listed features only")
+	}
+	if len(c.UserEdgeKinds) != len(store.ValidUserEdgeKinds) {
+		t.Errorf("UserEdgeKinds = %v, want all of store.ValidUserEdgeKinds", c.UserEdgeKinds)
+	}
+
+	for name, list := range map[string][]string{
+		"features":    c.Features,
+		"tools":       c.Tools,
+		"resources":   c.Resources,
+		"edge_kinds":  c.EdgeKinds,
+		"config_keys": c.ConfigKeys,
+	} {
+		seen := make(map[string]bool)
+		for _, v := range list {
+			if seen[v] {
+				t.Errorf("%s lists %q twice", name, v)
+			}
+			seen[v] = true
+		}
+		if len(list) == 0 {
+			t.Errorf("%s is empty", name)
+		}
+	}
+
+	// Describe returns copies: callers can't modify the package lists
+	c.Features[0] = "changed"
+	if Features[0] == "changed" {
+		t.Error("Describe() should copy Features")
+	}
+}
+
+func TestDescribe_JSON(t *testing.T) {
+	data, err := json.Marshal(Describe("dev"))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	for _, key := range []string{"version", "schema_version", "features", "tools", "resources", "edge_kinds", "user_edge_kinds", "config_keys"} {
+		if _, ok := m[key]; !ok {
+			t.Errorf("JSON missing %q", key)
+		}
+	}
+}
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/nvandessel/floop/internal/assembly"
 	"github.com/nvandessel/floop/internal/constants"
 	"github.com/nvandessel/floop/internal/utils"
 	"gopkg.in/yaml.v3"
@@ -40,6 +42,473 @@ type FloopConfig struct {
 
 	// Events contains settings for the raw event buffer.
 	Events EventsConfig `json:"events" yaml:"events"`
+
+	// Decay contains settings for demoting and pruning unused behaviors.
+	Decay DecayConfig `json:"decay" yaml:"decay"`
+
+	// Prompt contains settings for prompt assembly.
+	Prompt PromptConfig `json:"prompt" yaml:"prompt"`
+
+	// Quality contains settings for the correction quality gate.
+	Quality QualityConfig `json:"quality" yaml:"quality"`
+
+	// Limits contains storage size guardrails.
+	Limits LimitsConfig `json:"limits" yaml:"limits"`
+
+	// Ranking contains settings for activation ranking.
+	Ranking RankingConfig `json:"ranking" yaml:"ranking"`
+
+	// Activation contains settings for building the activation context.
+	Activation ActivationConfig `json:"activation" yaml:"activation"`
+
+	// RateLimit contains settings for MCP tool rate limiting.
+	RateLimit RateLimitConfig `json:"rate_limit" yaml:"rate_limit"`
+
+	// ToolTimeouts contains the execution deadlines of MCP tool calls.
+	ToolTimeouts ToolTimeoutsConfig `json:"tool_timeouts" yaml:"tool_timeouts"`
+
+	// Learning contains the policy applied to behaviors learned over MCP.
+	Learning LearningConfig `json:"learning" yaml:"learning"`
+
+	// Changelog contains settings for the behavior changelog.
+	Changelog ChangelogConfig `json:"changelog" yaml:"changelog"`
+
+	// Sync contains settings for sharing behaviors through git.
+	Sync SyncConfig `json:"sync" yaml:"sync"`
+
+	// Stores contains settings for the stores read alongside local and global.
+	Stores StoresConfig `json:"stores" yaml:"stores"`
+
+	// Replication contains settings for warm standby replication.
+	Replication ReplicationConfig `json:"replication" yaml:"replication"`
+
+	// EventBus contains the sinks floop mcp-server emits events to.
+	EventBus EventBusConfig `json:"event_bus" yaml:"event_bus"`
+}
+
+// EventBusConfig configures the structured events floop mcp-server emits
+// when behaviors are learned, merged, or forgotten and when backups are
+// created, so floop activity can be piped into chat or observability tools.
+type EventBusConfig struct {
+	// Sinks receive the events. No sinks means no events are emitted.
+	Sinks []EventSinkConfig `json:"sinks,omitempty" yaml:"sinks,omitempty"`
+}
+
+// EventSinkConfig configures one event sink.
+type EventSinkConfig struct {
+	// Type is file (JSONL audit file), webhook (JSON POST), or stdout.
+	Type string `json:"type" yaml:"type"`
+
+	// Path is the JSONL file a file sink appends to, relative to the
+	// project root. Default: .floop/events.jsonl.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	// URL receives each event of a webhook sink as a JSON POST.
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// Timeout bounds each webhook request. Zero uses
+	// constants.DefaultWebhookTimeoutMs.
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	// Events limits the sink to these event types (e.g. behavior.learned).
+	// Empty means all of them.
+	Events []string `json:"events,omitempty" yaml:"events,omitempty"`
+}
+
+// EventTypes lists the event types an event sink can subscribe to. It
+// matches the types defined by the eventbus package.
+var EventTypes = []string{"behavior.learned", "behavior.merged", "behavior.forgotten", "backup.created"}
+
+// ReplicationConfig configures warm standby replication: every store Sync
+// also ships the files that changed to a replica, which floop failover
+// promotes.
+type ReplicationConfig struct {
+	// Target is where replicas are kept: a directory, such as a mounted
+	// NAS share, or s3://bucket/prefix. Empty disables replication.
+	Target string `json:"target,omitempty" yaml:"target,omitempty"`
+
+	// Stores selects the stores replicated: "global" (default) or "all",
+	// which adds the local store of every project floop runs in.
+	Stores string `json:"stores,omitempty" yaml:"stores,omitempty"`
+}
+
+// StoresConfig configures the scope chain and the policy store: read-only
+// behavior stores layered below the local and global stores.
+type StoresConfig struct {
+	// Scopes lists scope stores in precedence order, earliest first. A bare
+	// name like "go" is ~/.floop/scopes/go; anything else is a directory
+	// path, with ~ expanded and relative paths resolved against the project
+	// root.
+	Scopes []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+
+	// Policy is the directory of a read-only policy store, read below the
+	// scope chain, with ~ expanded and relative paths resolved against the
+	// project root. Empty reads none.
+	Policy string `json:"policy,omitempty" yaml:"policy,omitempty"`
+}
+
+// SyncConfig configures floop sync push/pull.
+type SyncConfig struct {
+	// Remote is the git URL behaviors are shared through. Empty uses the
+	// project's origin remote.
+	Remote string `json:"remote" yaml:"remote"`
+
+	// Branch is the branch holding the shared behaviors.
+	Branch string `json:"branch" yaml:"branch"`
+}
+
+// ChangelogConfig configures the behavior changelog.
+type ChangelogConfig struct {
+	// Enabled appends a line to .floop/CHANGELOG.md for every behavior
+	// learned, updated, merged, or forgotten, so behavior changes can be
+	// reviewed in pull requests alongside code.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+}
+
+// LearningConfig is the policy floop_learn applies to new behaviors: when
+// they are merged into a duplicate, auto-accepted, or flagged for review,
+// and which store they go to.
+type LearningConfig struct {
+	// AutoAcceptThreshold is the minimum placement confidence for a
+	// behavior without review flags to be auto-accepted.
+	AutoAcceptThreshold float64 `json:"auto_accept_threshold" yaml:"auto_accept_threshold"`
+
+	// AutoMerge merges a new behavior into a near-duplicate instead of
+	// adding it.
+	AutoMerge bool `json:"auto_merge" yaml:"auto_merge"`
+
+	// AutoMergeThreshold is the minimum similarity for an auto-merge.
+	AutoMergeThreshold float64 `json:"auto_merge_threshold" yaml:"auto_merge_threshold"`
+
+	// Review selects the behaviors flagged for human review.
+	Review ReviewConfig `json:"review" yaml:"review"`
+
+	// Scope routes learned behaviors: "auto" (default) classifies each by
+	// its conditions, "local" or "global" sends all of them to one store.
+	Scope string `json:"scope" yaml:"scope"`
+
+	// Scopes overrides the policy for behaviors routed to "local" or
+	// "global".
+	Scopes map[string]LearningOverride `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+
+	// CircuitBreaker stops an agent from flooding the store with
+	// near-identical corrections.
+	CircuitBreaker CircuitBreakerConfig `json:"circuit_breaker" yaml:"circuit_breaker"`
+
+	// Extractor turns corrections into behaviors: "rules" (default) or
+	// "llm", which generalizes each correction with the configured LLM and
+	// falls back to rules when none is available.
+	Extractor string `json:"extractor" yaml:"extractor"`
+}
+
+// CircuitBreakerConfig caps what one MCP session may learn. When a
+// correction repeats too many recent ones, floop_learn is rejected for a
+// cool-down period with a response telling the agent to stop.
+type CircuitBreakerConfig struct {
+	// Enabled turns the breaker on.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// MaxPerSession is how many corrections one session may learn
+	// (0 = unlimited).
+	MaxPerSession int `json:"max_per_session" yaml:"max_per_session"`
+
+	// Window is how many recent corrections each new one is compared with.
+	Window int `json:"window" yaml:"window"`
+
+	// Similarity is the content similarity at which two corrections count
+	// as repeats.
+	Similarity float64 `json:"similarity" yaml:"similarity"`
+
+	// MaxRepeats is how many repeats within the window trip the breaker
+	// (0 = never).
+	MaxRepeats int `json:"max_repeats" yaml:"max_repeats"`
+
+	// Cooldown is how long a tripped breaker rejects learn calls.
+	Cooldown time.Duration `json:"cooldown" yaml:"cooldown"`
+}
+
+// ReviewConfig selects the learned behaviors that need human review.
+type ReviewConfig struct {
+	// Constraints flags every constraint behavior.
+	Constraints bool `json:"constraints" yaml:"constraints"`
+
+	// MinConfidence flags behaviors placed with lower confidence.
+	MinConfidence float64 `json:"min_confidence" yaml:"min_confidence"`
+
+	// MaxSimilarity flags behaviors more similar than this to an existing one.
+	MaxSimilarity float64 `json:"max_similarity" yaml:"max_similarity"`
+}
+
+// LearningOverride replaces parts of the learning policy for one scope.
+// Unset fields keep the base policy's value.
+type LearningOverride struct {
+	AutoAcceptThreshold *float64       `json:"auto_accept_threshold,omitempty" yaml:"auto_accept_threshold,omitempty"`
+	AutoMerge           *bool          `json:"auto_merge,omitempty" yaml:"auto_merge,omitempty"`
+	AutoMergeThreshold  *float64       `json:"auto_merge_threshold,omitempty" yaml:"auto_merge_threshold,omitempty"`
+	Review              ReviewOverride `json:"review,omitempty" yaml:"review,omitempty"`
+}
+
+// ReviewOverride replaces parts of the review triggers for one scope.
+type ReviewOverride struct {
+	Constraints   *bool    `json:"constraints,omitempty" yaml:"constraints,omitempty"`
+	MinConfidence *float64 `json:"min_confidence,omitempty" yaml:"min_confidence,omitempty"`
+	MaxSimilarity *float64 `json:"max_similarity,omitempty" yaml:"max_similarity,omitempty"`
+}
+
+// ForScope returns the policy for behaviors routed to scope: c with the
+// scope's override applied, and no further overrides.
+func (c LearningConfig) ForScope(scope string) LearningConfig {
+	o, ok := c.Scopes[scope]
+	c.Scopes = nil
+	if !ok {
+		return c
+	}
+	if o.AutoAcceptThreshold != nil {
+		c.AutoAcceptThreshold = *o.AutoAcceptThreshold
+	}
+	if o.AutoMerge != nil {
+		c.AutoMerge = *o.AutoMerge
+	}
+	if o.AutoMergeThreshold != nil {
+		c.AutoMergeThreshold = *o.AutoMergeThreshold
+	}
+	if o.Review.Constraints != nil {
+		c.Review.Constraints = *o.Review.Constraints
+	}
+	if o.Review.MinConfidence != nil {
+		c.Review.MinConfidence = *o.Review.MinConfidence
+	}
+	if o.Review.MaxSimilarity != nil {
+		c.Review.MaxSimilarity = *o.Review.MaxSimilarity
+	}
+	return c
+}
+
+// RateLimitConfig configures how the MCP server handles rate-limited calls.
+type RateLimitConfig struct {
+	// MaxWait is how long a rate-limited tool call may wait for its next
+	// token before failing with a retry hint. Zero fails immediately.
+	MaxWait time.Duration `json:"max_wait" yaml:"max_wait"`
+
+	// GlobalCeiling bounds the calls of all clients together: each tool's
+	// limit applies to every client session separately, and all sessions
+	// may use GlobalCeiling times that limit. Zero uses the default (4).
+	GlobalCeiling float64 `json:"global_ceiling" yaml:"global_ceiling"`
+}
+
+// Ceiling returns the global ceiling, with zero meaning the default.
+func (c RateLimitConfig) Ceiling() float64 {
+	if c.GlobalCeiling == 0 {
+		return constants.DefaultRateLimitGlobalCeiling
+	}
+	return c.GlobalCeiling
+}
+
+// ToolTimeoutsConfig bounds how long an MCP tool call may run before the
+// agent gets an answer: a partial result where the tool has one, otherwise
+// a deadline error.
+type ToolTimeoutsConfig struct {
+	// Default is the deadline of tools without a built-in or configured
+	// one. Zero uses the default (30s); a negative value disables every
+	// deadline not set in Tools.
+	Default time.Duration `json:"default" yaml:"default"`
+
+	// Tools sets the deadline of individual tools, e.g. floop_active: 2s,
+	// overriding the built-in ones. A negative value disables the tool's
+	// deadline.
+	Tools map[string]time.Duration `json:"tools,omitempty" yaml:"tools,omitempty"`
+}
+
+// defaultToolTimeouts are the built-in deadlines of tools expected to answer
+// faster, or allowed to run longer, than Default.
+var defaultToolTimeouts = map[string]time.Duration{
+	"floop_active":       2 * time.Second,
+	"floop_pin_context":  2 * time.Second,
+	"floop_deduplicate":  60 * time.Second,
+	"floop_backup":       60 * time.Second,
+	"floop_restore":      60 * time.Second,
+	"floop_consolidate":  120 * time.Second,
+	"floop_learn_batch":  120 * time.Second,
+	"floop_pack_install": 120 * time.Second,
+}
+
+// Timeout returns the deadline of tool, or zero if it has none.
+func (t ToolTimeoutsConfig) Timeout(tool string) time.Duration {
+	if d := t.Tools[tool]; d != 0 {
+		return max(d, 0)
+	}
+	if t.Default < 0 {
+		return 0
+	}
+	if d, ok := defaultToolTimeouts[tool]; ok {
+		return d
+	}
+	if t.Default > 0 {
+		return t.Default
+	}
+	return constants.DefaultToolTimeoutSeconds * time.Second
+}
+
+// ActivationConfig configures the context behaviors are activated against.
+type ActivationConfig struct {
+	// SniffContent reads the head of the current file for import statements
+	// and framework markers, exposed as the imports and framework context
+	// fields.
+	SniffContent bool `json:"sniff_content" yaml:"sniff_content"`
+
+	// StandingSeeds are project priorities injected into every spreading
+	// run at low activation, e.g. the behaviors tagged "security".
+	StandingSeeds []StandingSeedConfig `json:"standing_seeds,omitempty" yaml:"standing_seeds,omitempty"`
+
+	// EdgeHalfLife is how long it takes an edge's effective weight in
+	// spreading activation to halve since activation last flowed through
+	// it. Zero uses the default of about 69 hours.
+	EdgeHalfLife time.Duration `json:"edge_half_life,omitempty" yaml:"edge_half_life,omitempty"`
+}
+
+// StandingSeedConfig names one standing seed: every behavior with Tag, or
+// the single behavior Behavior. Exactly one of the two is set.
+type StandingSeedConfig struct {
+	Tag      string `json:"tag,omitempty" yaml:"tag,omitempty"`
+	Behavior string `json:"behavior,omitempty" yaml:"behavior,omitempty"`
+
+	// Activation is the energy injected. Zero uses the default (0.2).
+	// Range: 0.0 to 0.35
+	Activation float64 `json:"activation,omitempty" yaml:"activation,omitempty"`
+}
+
+// RankingConfig configures activation ranking.
+type RankingConfig struct {
+	// External configures an optional external scorer.
+	External ExternalScorerConfig `json:"external" yaml:"external"`
+
+	// Match configures how when-conditions are scored.
+	Match MatchConfig `json:"match" yaml:"match"`
+
+	// Temporal configures time-of-day and workload ranking signals.
+	Temporal TemporalRankingConfig `json:"temporal" yaml:"temporal"`
+}
+
+// TemporalRankingConfig configures temporal ranking: behaviors tagged with
+// a time of day (time/evening), a day type (time/weekend), or a workload
+// (workload/busy) rank higher when the activation fits and lower when it
+// does not. It never changes which behaviors activate.
+type TemporalRankingConfig struct {
+	// Enabled turns temporal ranking on.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Weight is the largest fraction by which a behavior's score is raised
+	// or lowered. Zero uses the default (0.2).
+	// Range: 0.0 to 1.0
+	Weight float64 `json:"weight,omitempty" yaml:"weight,omitempty"`
+
+	// BusyCalls is the number of tool calls in the last 10 minutes at which
+	// an MCP session counts as fully busy. Zero uses the default (30).
+	BusyCalls int `json:"busy_calls,omitempty" yaml:"busy_calls,omitempty"`
+}
+
+// EffectiveWeight returns the configured weight, or the default when
+// temporal ranking is enabled without one, or zero when it is disabled.
+func (t TemporalRankingConfig) EffectiveWeight() float64 {
+	if !t.Enabled {
+		return 0
+	}
+	if t.Weight > 0 {
+		return t.Weight
+	}
+	return constants.DefaultTemporalWeight
+}
+
+// EffectiveBusyCalls returns the configured busy threshold or the default.
+func (t TemporalRankingConfig) EffectiveBusyCalls() int {
+	if t.BusyCalls > 0 {
+		return t.BusyCalls
+	}
+	return constants.DefaultTemporalBusyCalls
+}
+
+// MatchConfig configures when-condition matching during activation.
+type MatchConfig struct {
+	// Mode is "strict" (any contradicted condition excludes a behavior) or
+	// "graded" (a behavior with contradicted conditions still activates when
+	// the weighted share of confirmed conditions reaches MinScore).
+	Mode string `json:"mode" yaml:"mode"`
+
+	// MinScore is the minimum weighted match score in graded mode.
+	// Range: 0.0 to 1.0
+	MinScore float64 `json:"min_score" yaml:"min_score"`
+
+	// FieldWeights overrides the importance of individual condition fields
+	// in graded scoring, e.g. {"branch": 2}.
+	FieldWeights map[string]float64 `json:"field_weights,omitempty" yaml:"field_weights,omitempty"`
+}
+
+// ExternalScorerConfig configures an external scorer: a command or HTTP
+// endpoint that receives the candidate behaviors and context, and returns
+// score adjustments merged with the built-in scores. On error or timeout,
+// built-in ranking is used unchanged.
+type ExternalScorerConfig struct {
+	// Enabled turns the external scorer on.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Command is run with the request on stdin and prints the response on
+	// stdout. It is split on whitespace and run without a shell.
+	Command string `json:"command,omitempty" yaml:"command,omitempty"`
+
+	// URL receives the request as a JSON POST. Exactly one of Command and
+	// URL must be set when enabled.
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// Timeout bounds each scoring call.
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// LimitsConfig caps store growth. When a limit is reached, learning proposes
+// consolidation candidates instead of adding behaviors. Zero disables a limit.
+type LimitsConfig struct {
+	// MaxBehaviorsPerScope is the maximum number of behaviors in each of the
+	// local and global stores.
+	MaxBehaviorsPerScope int `json:"max_behaviors_per_scope" yaml:"max_behaviors_per_scope"`
+
+	// MaxCanonicalLength is the maximum length of a learned behavior's
+	// canonical content, in bytes.
+	MaxCanonicalLength int `json:"max_canonical_length" yaml:"max_canonical_length"`
+
+	// MaxEdgesPerNode is the maximum number of edges touching one behavior.
+	MaxEdgesPerNode int `json:"max_edges_per_node" yaml:"max_edges_per_node"`
+}
+
+// QualityConfig configures the quality gate applied to corrections before
+// behavior extraction.
+type QualityConfig struct {
+	// Enabled turns on quality scoring. Corrections scoring below MinScore
+	// are held instead of becoming behaviors.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// MinScore is the minimum quality score for extraction.
+	// Range: 0.0 to 1.0
+	MinScore float64 `json:"min_score" yaml:"min_score"`
+
+	// UseLLM blends an LLM quality rating into the heuristic score when an
+	// LLM client is configured.
+	UseLLM bool `json:"use_llm" yaml:"use_llm"`
+}
+
+// PromptConfig configures how active behaviors are assembled into prompts.
+type PromptConfig struct {
+	// Ordering selects the section ordering strategy:
+	// "kind" (default), "constraints-first", "group-by-tag",
+	// "task-relevant-first", or "alphabetical".
+	Ordering string `json:"ordering" yaml:"ordering"`
+
+	// Packing selects the order in which a token budget is filled:
+	// "importance" (default) or "value" (activation score × confidence).
+	Packing string `json:"packing,omitempty" yaml:"packing,omitempty"`
+
+	// Budgets sets per-kind token budgets, keyed by behavior kind: "always"
+	// includes every behavior of the kind even past the budget, and a
+	// fraction ("0.3") or percentage ("30%") caps the kind's share of it.
+	Budgets map[string]string `json:"budgets,omitempty" yaml:"budgets,omitempty"`
 }
 
 // TokenBudgetConfig configures token budget limits for behavior injection.
@@ -91,10 +560,14 @@ type InstalledPack struct {
 	EdgeCount     int       `json:"edge_count" yaml:"edge_count"`
 }
 
-// Registry is a URL for discovering skill packs.
+// Registry is a URL or directory for discovering skill packs.
 type Registry struct {
 	Name string `json:"name" yaml:"name"`
 	URL  string `json:"url" yaml:"url"`
+
+	// PublicKey is the base64 ed25519 key that signs the registry's packs.
+	// Packs from a registry without one install only with --allow-unsigned.
+	PublicKey string `json:"public_key,omitempty" yaml:"public_key,omitempty"`
 }
 
 // LoggingConfig configures floop's logging behavior.
@@ -124,6 +597,11 @@ type LLMConfig struct {
 	// MergeModel is the model to use for behavior merging (may differ from comparison).
 	MergeModel string `json:"merge_model,omitempty" yaml:"merge_model,omitempty"`
 
+	// EmbeddingModel is the embedding model for the openai and ollama
+	// providers (e.g. text-embedding-3-small, nomic-embed-text). If empty,
+	// those providers don't produce embeddings.
+	EmbeddingModel string `json:"embedding_model,omitempty" yaml:"embedding_model,omitempty"`
+
 	// Timeout is the maximum duration to wait for LLM responses.
 	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
 
@@ -168,6 +646,23 @@ func (c LLMConfig) RedactedAPIKey() string {
 	return c.APIKey[:4] + "..." + c.APIKey[len(c.APIKey)-4:]
 }
 
+// EmbeddingModelName returns the name recorded with embeddings from the
+// configured provider: the embedding model file for local, otherwise
+// EmbeddingModel.
+func (c LLMConfig) EmbeddingModelName() string {
+	if c.Provider == "local" {
+		path := c.LocalEmbeddingModelPath
+		if path == "" {
+			path = c.LocalModelPath
+		}
+		if path == "" {
+			return ""
+		}
+		return filepath.Base(path)
+	}
+	return c.EmbeddingModel
+}
+
 // String implements fmt.Stringer to prevent accidental API key logging.
 // It returns a representation with the API key redacted.
 func (c LLMConfig) String() string {
@@ -183,6 +678,11 @@ type DeduplicationConfig struct {
 	// SimilarityThreshold is the minimum similarity score for duplicate detection.
 	// Range: 0.0 to 1.0
 	SimilarityThreshold float64 `json:"similarity_threshold" yaml:"similarity_threshold"`
+
+	// Similarity selects how behaviors are compared: "auto" (embedding, then
+	// LLM, then Jaccard, as the LLM client allows), "embedding" (cosine
+	// similarity of embeddings cached per behavior), or "jaccard".
+	Similarity string `json:"similarity" yaml:"similarity"`
 }
 
 // ConsolidationConfig configures memory consolidation behavior.
@@ -193,6 +693,41 @@ type ConsolidationConfig struct {
 	// Executor specifies which consolidation engine to use.
 	// Values: "heuristic" (v0), "llm" (v1), "local" (v2).
 	Executor string `json:"executor" yaml:"executor"`
+
+	// Sleep configures the periodic sleep-phase maintenance pass.
+	Sleep SleepConfig `json:"sleep" yaml:"sleep"`
+}
+
+// SleepConfig configures the sleep phase: a periodic pass that deduplicates,
+// prunes weak edges, recalibrates confidence, regenerates summaries, decays
+// stale behaviors, and backs up the graph.
+type SleepConfig struct {
+	// Enabled runs the sleep phase on a timer while the MCP server is up.
+	// It can always be run by hand with 'floop consolidate sleep'.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Interval is the minimum time between runs (e.g. "24h").
+	Interval string `json:"interval" yaml:"interval"`
+}
+
+// DecayConfig configures decay: behaviors not activated or confirmed for
+// DemoteAfterDays lose confidence, and those unused for PruneAfterDays are
+// retired.
+type DecayConfig struct {
+	// Enabled runs a decay pass on a timer while the MCP server is up.
+	// It can always be run by hand with 'floop decay'.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Interval is the minimum time between scheduled passes (e.g. "24h").
+	Interval string `json:"interval" yaml:"interval"`
+
+	// DemoteAfterDays is how long a behavior may go unused before it loses
+	// confidence. The sleep phase's decay step uses it too.
+	DemoteAfterDays int `json:"demote_after_days" yaml:"demote_after_days"`
+
+	// PruneAfterDays is how long a behavior may go unused before it is
+	// retired. Zero disables pruning.
+	PruneAfterDays int `json:"prune_after_days" yaml:"prune_after_days"`
 }
 
 // EventsConfig configures the raw event buffer.
@@ -202,6 +737,70 @@ type EventsConfig struct {
 	RetentionDays int `json:"retention_days" yaml:"retention_days"`
 }
 
+// Keys lists the dot-notation keys accepted by "floop config get" and
+// "floop config set".
+var Keys = []string{
+	"llm.provider",
+	"llm.api_key",
+	"llm.base_url",
+	"llm.comparison_model",
+	"llm.merge_model",
+	"llm.embedding_model",
+	"llm.timeout",
+	"llm.enabled",
+	"llm.fallback_to_rules",
+	"deduplication.auto_merge",
+	"deduplication.similarity_threshold",
+	"deduplication.similarity",
+	"prompt.ordering",
+	"prompt.packing",
+	"quality.enabled",
+	"quality.min_score",
+	"quality.use_llm",
+	"limits.max_behaviors_per_scope",
+	"limits.max_canonical_length",
+	"limits.max_edges_per_node",
+	"consolidation.sleep.enabled",
+	"consolidation.sleep.interval",
+	"decay.enabled",
+	"decay.interval",
+	"decay.demote_after_days",
+	"decay.prune_after_days",
+	"ranking.external.enabled",
+	"ranking.external.command",
+	"ranking.external.url",
+	"ranking.external.timeout",
+	"ranking.match.mode",
+	"ranking.match.min_score",
+	"ranking.temporal.enabled",
+	"ranking.temporal.weight",
+	"ranking.temporal.busy_calls",
+	"activation.sniff_content",
+	"activation.edge_half_life",
+	"rate_limit.max_wait",
+	"rate_limit.global_ceiling",
+	"tool_timeouts.default",
+	"learning.auto_accept_threshold",
+	"learning.auto_merge",
+	"learning.auto_merge_threshold",
+	"learning.review.constraints",
+	"learning.review.min_confidence",
+	"learning.review.max_similarity",
+	"learning.scope",
+	"learning.extractor",
+	"learning.circuit_breaker.enabled",
+	"learning.circuit_breaker.max_per_session",
+	"learning.circuit_breaker.window",
+	"learning.circuit_breaker.similarity",
+	"learning.circuit_breaker.max_repeats",
+	"learning.circuit_breaker.cooldown",
+	"changelog.enabled",
+	"sync.remote",
+	"sync.branch",
+	"replication.target",
+	"replication.stores",
+}
+
 // Default returns a FloopConfig with sensible defaults.
 func Default() *FloopConfig {
 	return &FloopConfig{
@@ -217,6 +816,7 @@ func Default() *FloopConfig {
 		Deduplication: DeduplicationConfig{
 			AutoMerge:           false,
 			SimilarityThreshold: constants.DefaultSimilarityThreshold,
+			Similarity:          "auto",
 		},
 		Logging: LoggingConfig{
 			Level: "info",
@@ -235,10 +835,69 @@ func Default() *FloopConfig {
 		Consolidation: ConsolidationConfig{
 			AutoConsolidate: false,
 			Executor:        "heuristic",
+			Sleep: SleepConfig{
+				Interval: "24h",
+			},
 		},
 		Events: EventsConfig{
 			RetentionDays: 90,
 		},
+		Decay: DecayConfig{
+			Interval:        "24h",
+			DemoteAfterDays: constants.DefaultStaleBehaviorDays,
+			PruneAfterDays:  constants.DefaultDecayPruneDays,
+		},
+		Prompt: PromptConfig{
+			Ordering: "kind",
+		},
+		Quality: QualityConfig{
+			Enabled:  false,
+			MinScore: constants.DefaultMinCorrectionQuality,
+		},
+		Limits: LimitsConfig{
+			MaxBehaviorsPerScope: constants.DefaultMaxBehaviorsPerScope,
+			MaxCanonicalLength:   constants.DefaultMaxCanonicalLength,
+			MaxEdgesPerNode:      constants.DefaultMaxEdgesPerNode,
+		},
+		Ranking: RankingConfig{
+			External: ExternalScorerConfig{
+				Timeout: constants.DefaultExternalScorerTimeoutMs * time.Millisecond,
+			},
+			Match: MatchConfig{
+				Mode:     "strict",
+				MinScore: constants.DefaultGradedMatchMinScore,
+			},
+		},
+		Activation: ActivationConfig{
+			SniffContent: true,
+		},
+		RateLimit: RateLimitConfig{
+			MaxWait:       constants.DefaultRateLimitMaxWaitMs * time.Millisecond,
+			GlobalCeiling: constants.DefaultRateLimitGlobalCeiling,
+		},
+		Sync: SyncConfig{
+			Branch: constants.DefaultSyncBranch,
+		},
+		Learning: LearningConfig{
+			AutoAcceptThreshold: constants.DefaultAutoAcceptThreshold,
+			AutoMerge:           true,
+			AutoMergeThreshold:  constants.DefaultAutoMergeThreshold,
+			Review: ReviewConfig{
+				Constraints:   true,
+				MinConfidence: constants.LowConfidenceThreshold,
+				MaxSimilarity: constants.ReviewSimilarityThreshold,
+			},
+			Scope:     "auto",
+			Extractor: "rules",
+			CircuitBreaker: CircuitBreakerConfig{
+				Enabled:       true,
+				MaxPerSession: constants.DefaultBreakerMaxPerSession,
+				Window:        constants.DefaultBreakerWindow,
+				Similarity:    constants.DefaultBreakerSimilarity,
+				MaxRepeats:    constants.DefaultBreakerMaxRepeats,
+				Cooldown:      constants.DefaultBreakerCooldownMinutes * time.Minute,
+			},
+		},
 	}
 }
 
@@ -290,6 +949,11 @@ func (c *FloopConfig) Validate() error {
 		return fmt.Errorf("similarity_threshold must be between 0 and 1, got %f", c.Deduplication.SimilarityThreshold)
 	}
 
+	validSimilarity := map[string]bool{"": true, "auto": true, "embedding": true, "jaccard": true}
+	if !validSimilarity[c.Deduplication.Similarity] {
+		return fmt.Errorf("invalid deduplication.similarity: %s (valid: auto, embedding, jaccard)", c.Deduplication.Similarity)
+	}
+
 	if c.LLM.Timeout < 0 {
 		return fmt.Errorf("timeout must be non-negative, got %v", c.LLM.Timeout)
 	}
@@ -333,12 +997,202 @@ func (c *FloopConfig) Validate() error {
 	if !validExecutors[c.Consolidation.Executor] {
 		return fmt.Errorf("invalid consolidation executor: %s (valid: heuristic, llm, local, or empty)", c.Consolidation.Executor)
 	}
+	if c.Consolidation.Sleep.Interval != "" {
+		if d, err := utils.ParseDuration(c.Consolidation.Sleep.Interval); err != nil {
+			return fmt.Errorf("consolidation.sleep.interval: %w", err)
+		} else if d <= 0 {
+			return fmt.Errorf("consolidation.sleep.interval must be positive, got %s", c.Consolidation.Sleep.Interval)
+		}
+	}
+
+	// Decay validation
+	if c.Decay.Interval != "" {
+		if d, err := utils.ParseDuration(c.Decay.Interval); err != nil {
+			return fmt.Errorf("decay.interval: %w", err)
+		} else if d <= 0 {
+			return fmt.Errorf("decay.interval must be positive, got %s", c.Decay.Interval)
+		}
+	}
+	if c.Decay.DemoteAfterDays < 0 {
+		return fmt.Errorf("decay.demote_after_days must be non-negative, got %d", c.Decay.DemoteAfterDays)
+	}
+	if c.Decay.PruneAfterDays < 0 {
+		return fmt.Errorf("decay.prune_after_days must be non-negative, got %d", c.Decay.PruneAfterDays)
+	}
 
 	// Events validation
 	if c.Events.RetentionDays < 0 {
 		return fmt.Errorf("events.retention_days must be non-negative, got %d", c.Events.RetentionDays)
 	}
 
+	// Prompt validation
+	if _, err := assembly.ParseOrderStrategy(c.Prompt.Ordering); err != nil {
+		return fmt.Errorf("invalid prompt ordering: %s (valid: %s)", c.Prompt.Ordering, joinNames(assembly.ValidOrderStrategies))
+	}
+	if _, err := assembly.ParsePackingStrategy(c.Prompt.Packing); err != nil {
+		return fmt.Errorf("invalid prompt packing: %s (valid: %s)", c.Prompt.Packing, joinNames(assembly.ValidPackingStrategies))
+	}
+	validBudgetKinds := map[string]bool{"directive": true, "constraint": true, "procedure": true, "preference": true, "episodic": true, "workflow": true}
+	for kind, budget := range c.Prompt.Budgets {
+		if !validBudgetKinds[kind] {
+			return fmt.Errorf("invalid prompt.budgets kind: %s (valid: directive, constraint, procedure, preference, episodic, workflow)", kind)
+		}
+		if !validKindBudget(budget) {
+			return fmt.Errorf("invalid prompt.budgets.%s: %q (must be always, a fraction such as 0.3, or a percentage such as 30%%)", kind, budget)
+		}
+	}
+
+	// Quality validation
+	if c.Quality.MinScore < 0 || c.Quality.MinScore > 1 {
+		return fmt.Errorf("quality.min_score must be between 0 and 1, got %f", c.Quality.MinScore)
+	}
+
+	// Limits validation
+	if c.Limits.MaxBehaviorsPerScope < 0 {
+		return fmt.Errorf("limits.max_behaviors_per_scope must be non-negative, got %d", c.Limits.MaxBehaviorsPerScope)
+	}
+	if c.Limits.MaxCanonicalLength < 0 {
+		return fmt.Errorf("limits.max_canonical_length must be non-negative, got %d", c.Limits.MaxCanonicalLength)
+	}
+	if c.Limits.MaxEdgesPerNode < 0 {
+		return fmt.Errorf("limits.max_edges_per_node must be non-negative, got %d", c.Limits.MaxEdgesPerNode)
+	}
+
+	// External scorer validation
+	if ext := c.Ranking.External; ext.Enabled {
+		if (ext.Command == "") == (ext.URL == "") {
+			return fmt.Errorf("ranking.external: exactly one of command and url must be set")
+		}
+		if ext.URL != "" && !strings.HasPrefix(ext.URL, "http://") && !strings.HasPrefix(ext.URL, "https://") {
+			return fmt.Errorf("ranking.external.url must be an http or https URL, got %s", ext.URL)
+		}
+	}
+	if limit := constants.MaxExternalScorerTimeoutMs * time.Millisecond; c.Ranking.External.Timeout < 0 || c.Ranking.External.Timeout > limit {
+		return fmt.Errorf("ranking.external.timeout must be between 0 and %v, got %v", limit, c.Ranking.External.Timeout)
+	}
+
+	// Match mode validation
+	switch c.Ranking.Match.Mode {
+	case "", "strict", "graded":
+	default:
+		return fmt.Errorf("ranking.match.mode must be strict or graded, got %s", c.Ranking.Match.Mode)
+	}
+	if c.Ranking.Match.MinScore < 0 || c.Ranking.Match.MinScore > 1 {
+		return fmt.Errorf("ranking.match.min_score must be between 0.0 and 1.0, got %f", c.Ranking.Match.MinScore)
+	}
+	for field, w := range c.Ranking.Match.FieldWeights {
+		if w <= 0 {
+			return fmt.Errorf("ranking.match.field_weights.%s must be positive, got %f", field, w)
+		}
+	}
+	if w := c.Ranking.Temporal.Weight; w < 0 || w > 1 {
+		return fmt.Errorf("ranking.temporal.weight must be between 0.0 and 1.0, got %f", w)
+	}
+	if c.Ranking.Temporal.BusyCalls < 0 {
+		return fmt.Errorf("ranking.temporal.busy_calls must be non-negative, got %d", c.Ranking.Temporal.BusyCalls)
+	}
+
+	// Event sink validation
+	for i, sink := range c.EventBus.Sinks {
+		prefix := fmt.Sprintf("event_bus.sinks[%d]", i)
+		switch sink.Type {
+		case "file", "stdout":
+		case "webhook":
+			if !strings.HasPrefix(sink.URL, "http://") && !strings.HasPrefix(sink.URL, "https://") {
+				return fmt.Errorf("%s.url must be an http or https URL, got %q", prefix, sink.URL)
+			}
+		default:
+			return fmt.Errorf("%s.type must be file, webhook, or stdout, got %q", prefix, sink.Type)
+		}
+		if limit := constants.MaxWebhookTimeoutMs * time.Millisecond; sink.Timeout < 0 || sink.Timeout > limit {
+			return fmt.Errorf("%s.timeout must be between 0 and %v, got %v", prefix, limit, sink.Timeout)
+		}
+		for _, event := range sink.Events {
+			if !slices.Contains(EventTypes, event) {
+				return fmt.Errorf("%s.events: unknown event type %q (valid: %s)", prefix, event, strings.Join(EventTypes, ", "))
+			}
+		}
+	}
+
+	// Standing seed validation
+	for i, seed := range c.Activation.StandingSeeds {
+		if (seed.Tag == "") == (seed.Behavior == "") {
+			return fmt.Errorf("activation.standing_seeds[%d]: exactly one of tag and behavior must be set", i)
+		}
+		if seed.Activation < 0 || seed.Activation > constants.MaxStandingSeedActivation {
+			return fmt.Errorf("activation.standing_seeds[%d].activation must be between 0.0 and %.2f, got %f", i, constants.MaxStandingSeedActivation, seed.Activation)
+		}
+	}
+
+	if c.Activation.EdgeHalfLife < 0 {
+		return fmt.Errorf("activation.edge_half_life must be non-negative, got %v", c.Activation.EdgeHalfLife)
+	}
+
+	if limit := constants.MaxRateLimitMaxWaitMs * time.Millisecond; c.RateLimit.MaxWait < 0 || c.RateLimit.MaxWait > limit {
+		return fmt.Errorf("rate_limit.max_wait must be between 0 and %v, got %v", limit, c.RateLimit.MaxWait)
+	}
+	if c.RateLimit.GlobalCeiling != 0 && c.RateLimit.GlobalCeiling < 1 {
+		return fmt.Errorf("rate_limit.global_ceiling must be at least 1, got %v", c.RateLimit.GlobalCeiling)
+	}
+
+	// Learning policy validation
+	validScopes := map[string]bool{"": true, "auto": true, "local": true, "global": true}
+	if !validScopes[c.Learning.Scope] {
+		return fmt.Errorf("invalid learning.scope: %s (valid: auto, local, global)", c.Learning.Scope)
+	}
+	validExtractors := map[string]bool{"": true, "rules": true, "llm": true}
+	if !validExtractors[c.Learning.Extractor] {
+		return fmt.Errorf("invalid learning.extractor: %s (valid: rules, llm)", c.Learning.Extractor)
+	}
+	if err := c.Learning.validateThresholds("learning"); err != nil {
+		return err
+	}
+	for scope := range c.Learning.Scopes {
+		if scope != "local" && scope != "global" {
+			return fmt.Errorf("invalid learning.scopes key: %s (valid: local, global)", scope)
+		}
+		if err := c.Learning.ForScope(scope).validateThresholds("learning.scopes." + scope); err != nil {
+			return err
+		}
+	}
+	cb := c.Learning.CircuitBreaker
+	if cb.MaxPerSession < 0 || cb.Window < 0 || cb.MaxRepeats < 0 {
+		return fmt.Errorf("learning.circuit_breaker max_per_session, window, and max_repeats must be non-negative")
+	}
+	if cb.MaxRepeats > cb.Window {
+		return fmt.Errorf("learning.circuit_breaker.max_repeats (%d) cannot exceed learning.circuit_breaker.window (%d)", cb.MaxRepeats, cb.Window)
+	}
+	if cb.Similarity < 0 || cb.Similarity > 1 {
+		return fmt.Errorf("learning.circuit_breaker.similarity must be between 0.0 and 1.0, got %f", cb.Similarity)
+	}
+	if cb.Cooldown < 0 {
+		return fmt.Errorf("learning.circuit_breaker.cooldown must be non-negative, got %v", cb.Cooldown)
+	}
+
+	validReplicationStores := map[string]bool{"": true, "global": true, "all": true}
+	if !validReplicationStores[c.Replication.Stores] {
+		return fmt.Errorf("invalid replication.stores: %s (valid: global, all)", c.Replication.Stores)
+	}
+
+	return nil
+}
+
+// validateThresholds checks that c's thresholds are in [0, 1], naming
+// fields under prefix.
+func (c LearningConfig) validateThresholds(prefix string) error {
+	for _, t := range []struct {
+		name  string
+		value float64
+	}{
+		{"auto_accept_threshold", c.AutoAcceptThreshold},
+		{"auto_merge_threshold", c.AutoMergeThreshold},
+		{"review.min_confidence", c.Review.MinConfidence},
+		{"review.max_similarity", c.Review.MaxSimilarity},
+	} {
+		if t.value < 0 || t.value > 1 {
+			return fmt.Errorf("%s.%s must be between 0.0 and 1.0, got %f", prefix, t.name, t.value)
+		}
+	}
 	return nil
 }
 
@@ -369,6 +1223,31 @@ func parseSizeSimple(s string) (int64, error) {
 	return 0, fmt.Errorf("invalid size: %q (expected suffix: B, KB, MB, GB)", s)
 }
 
+// joinNames lists strategy names for an error message.
+func joinNames[T ~string](names []T) string {
+	parts := make([]string, len(names))
+	for i, n := range names {
+		parts[i] = string(n)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// validKindBudget validates a per-kind budget like "always", "0.3", "30%".
+func validKindBudget(s string) bool {
+	s = strings.TrimSpace(s)
+	if s == "always" {
+		return true
+	}
+	share, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return false
+	}
+	if strings.HasSuffix(s, "%") {
+		share /= 100
+	}
+	return share > 0 && share <= 1
+}
+
 // applyEnvOverrides applies environment variable overrides to the config.
 func applyEnvOverrides(config *FloopConfig) {
 	if v := os.Getenv("FLOOP_LLM_PROVIDER"); v != "" {
@@ -427,6 +1306,14 @@ func applyEnvOverrides(config *FloopConfig) {
 		}
 	}
 
+	if v := os.Getenv("FLOOP_DEDUP_SIMILARITY"); v != "" {
+		config.Deduplication.Similarity = v
+	}
+
+	if v := os.Getenv("FLOOP_LLM_EMBEDDING_MODEL"); v != "" {
+		config.LLM.EmbeddingModel = v
+	}
+
 	if v := os.Getenv("FLOOP_LOG_LEVEL"); v != "" {
 		config.Logging.Level = v
 	}
@@ -457,6 +1344,191 @@ func applyEnvOverrides(config *FloopConfig) {
 	if v := os.Getenv("FLOOP_BACKUP_MAX_AGE"); v != "" {
 		config.Backup.Retention.MaxAge = v
 	}
+
+	if v := os.Getenv("FLOOP_PROMPT_ORDERING"); v != "" {
+		config.Prompt.Ordering = v
+	}
+	if v := os.Getenv("FLOOP_PROMPT_PACKING"); v != "" {
+		config.Prompt.Packing = v
+	}
+
+	// Quality gate overrides
+	if v := os.Getenv("FLOOP_QUALITY_ENABLED"); v != "" {
+		config.Quality.Enabled = v == "true" || v == "1"
+	}
+	if v := os.Getenv("FLOOP_QUALITY_MIN_SCORE"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			config.Quality.MinScore = f
+		}
+	}
+	if v := os.Getenv("FLOOP_QUALITY_USE_LLM"); v != "" {
+		config.Quality.UseLLM = v == "true" || v == "1"
+	}
+
+	// Sleep phase overrides
+	if v := os.Getenv("FLOOP_SLEEP_ENABLED"); v != "" {
+		config.Consolidation.Sleep.Enabled = v == "true" || v == "1"
+	}
+	if v := os.Getenv("FLOOP_SLEEP_INTERVAL"); v != "" {
+		config.Consolidation.Sleep.Interval = v
+	}
+
+	// Decay overrides
+	if v := os.Getenv("FLOOP_DECAY_ENABLED"); v != "" {
+		config.Decay.Enabled = v == "true" || v == "1"
+	}
+	if v := os.Getenv("FLOOP_DECAY_INTERVAL"); v != "" {
+		config.Decay.Interval = v
+	}
+	if v := os.Getenv("FLOOP_DECAY_DEMOTE_AFTER_DAYS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			config.Decay.DemoteAfterDays = n
+		}
+	}
+	if v := os.Getenv("FLOOP_DECAY_PRUNE_AFTER_DAYS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			config.Decay.PruneAfterDays = n
+		}
+	}
+
+	// Storage limit overrides
+	if v := os.Getenv("FLOOP_LIMITS_MAX_BEHAVIORS_PER_SCOPE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			config.Limits.MaxBehaviorsPerScope = n
+		}
+	}
+	if v := os.Getenv("FLOOP_LIMITS_MAX_CANONICAL_LENGTH"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			config.Limits.MaxCanonicalLength = n
+		}
+	}
+	if v := os.Getenv("FLOOP_LIMITS_MAX_EDGES_PER_NODE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			config.Limits.MaxEdgesPerNode = n
+		}
+	}
+
+	// External scorer overrides
+	if v := os.Getenv("FLOOP_RANKING_EXTERNAL_ENABLED"); v != "" {
+		config.Ranking.External.Enabled = v == "true" || v == "1"
+	}
+	if v := os.Getenv("FLOOP_RANKING_EXTERNAL_COMMAND"); v != "" {
+		config.Ranking.External.Command = v
+	}
+	if v := os.Getenv("FLOOP_RANKING_EXTERNAL_URL"); v != "" {
+		config.Ranking.External.URL = v
+	}
+	if v := os.Getenv("FLOOP_RANKING_EXTERNAL_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			config.Ranking.External.Timeout = d
+		}
+	}
+
+	// Match mode overrides
+	if v := os.Getenv("FLOOP_RANKING_MATCH_MODE"); v != "" {
+		config.Ranking.Match.Mode = v
+	}
+	if v := os.Getenv("FLOOP_RANKING_MATCH_MIN_SCORE"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			config.Ranking.Match.MinScore = f
+		}
+	}
+
+	// Temporal ranking overrides
+	if v := os.Getenv("FLOOP_RANKING_TEMPORAL_ENABLED"); v != "" {
+		config.Ranking.Temporal.Enabled = v == "true" || v == "1"
+	}
+	if v := os.Getenv("FLOOP_RANKING_TEMPORAL_WEIGHT"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			config.Ranking.Temporal.Weight = f
+		}
+	}
+
+	// Activation context overrides
+	if v := os.Getenv("FLOOP_ACTIVATION_SNIFF_CONTENT"); v != "" {
+		config.Activation.SniffContent = v == "true" || v == "1"
+	}
+	if v := os.Getenv("FLOOP_ACTIVATION_EDGE_HALF_LIFE"); v != "" {
+		if d, err := utils.ParseDuration(v); err == nil {
+			config.Activation.EdgeHalfLife = d
+		}
+	}
+
+	// Rate limit overrides
+	if v := os.Getenv("FLOOP_RATE_LIMIT_MAX_WAIT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			config.RateLimit.MaxWait = d
+		}
+	}
+	if v := os.Getenv("FLOOP_RATE_LIMIT_GLOBAL_CEILING"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			config.RateLimit.GlobalCeiling = f
+		}
+	}
+
+	// Tool deadline overrides
+	if v := os.Getenv("FLOOP_TOOL_TIMEOUTS_DEFAULT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			config.ToolTimeouts.Default = d
+		}
+	}
+
+	// Learning policy overrides
+	if v := os.Getenv("FLOOP_LEARNING_AUTO_ACCEPT_THRESHOLD"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			config.Learning.AutoAcceptThreshold = f
+		}
+	}
+	if v := os.Getenv("FLOOP_LEARNING_AUTO_MERGE"); v != "" {
+		config.Learning.AutoMerge = v == "true" || v == "1"
+	}
+	if v := os.Getenv("FLOOP_LEARNING_SCOPE"); v != "" {
+		config.Learning.Scope = v
+	}
+	if v := os.Getenv("FLOOP_LEARNING_EXTRACTOR"); v != "" {
+		config.Learning.Extractor = v
+	}
+	if v := os.Getenv("FLOOP_LEARNING_CIRCUIT_BREAKER_ENABLED"); v != "" {
+		config.Learning.CircuitBreaker.Enabled = v == "true" || v == "1"
+	}
+	if v := os.Getenv("FLOOP_LEARNING_CIRCUIT_BREAKER_MAX_PER_SESSION"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			config.Learning.CircuitBreaker.MaxPerSession = n
+		}
+	}
+
+	// Changelog overrides
+	if v := os.Getenv("FLOOP_CHANGELOG_ENABLED"); v != "" {
+		config.Changelog.Enabled = v == "true" || v == "1"
+	}
+
+	// Sync overrides
+	if v := os.Getenv("FLOOP_SYNC_REMOTE"); v != "" {
+		config.Sync.Remote = v
+	}
+	if v := os.Getenv("FLOOP_SYNC_BRANCH"); v != "" {
+		config.Sync.Branch = v
+	}
+
+	// Replication overrides
+	if v := os.Getenv("FLOOP_REPLICATION_TARGET"); v != "" {
+		config.Replication.Target = v
+	}
+	if v := os.Getenv("FLOOP_REPLICATION_STORES"); v != "" {
+		config.Replication.Stores = v
+	}
+
+	// Store overrides: extra scopes come after the configured ones
+	if v := os.Getenv("FLOOP_SCOPES"); v != "" {
+		for _, scope := range filepath.SplitList(v) {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				config.Stores.Scopes = append(config.Stores.Scopes, scope)
+			}
+		}
+	}
+	if v := os.Getenv("FLOOP_POLICY_STORE"); v != "" {
+		config.Stores.Policy = v
+	}
 }
 
 // Save writes the config to the default config file with atomic write.
@@ -3,10 +3,13 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/nvandessel/floop/internal/assembly"
 )
 
 func TestDefault(t *testing.T) {
@@ -461,6 +464,69 @@ func TestValidate_TokenBudget(t *testing.T) {
 	}
 }
 
+func TestValidate_PromptOrdering(t *testing.T) {
+	tests := []struct {
+		ordering string
+		wantErr  bool
+	}{
+		{"", false},
+		{"kind", false},
+		{"constraints-first", false},
+		{"group-by-tag", false},
+		{"task-relevant-first", false},
+		{"alphabetical", false},
+		{"random", true},
+	}
+	// Every strategy the compiler accepts validates
+	for _, strategy := range assembly.ValidOrderStrategies {
+		tests = append(tests, struct {
+			ordering string
+			wantErr  bool
+		}{string(strategy), false})
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ordering, func(t *testing.T) {
+			config := Default()
+			config.Prompt.Ordering = tt.ordering
+			err := config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_PromptBudgets(t *testing.T) {
+	tests := []struct {
+		name    string
+		packing string
+		budgets map[string]string
+		wantErr bool
+	}{
+		{"defaults", "", nil, false},
+		{"value packing", "value", nil, false},
+		{"invalid packing", "insertion", nil, true},
+		{"budgets", "", map[string]string{"constraint": "always", "preference": "30%", "directive": "0.5"}, false},
+		{"unknown kind", "", map[string]string{"rule": "always"}, true},
+		{"zero share", "", map[string]string{"preference": "0"}, true},
+		{"share over 100%", "", map[string]string{"preference": "120%"}, true},
+		{"not a share", "", map[string]string{"preference": "most"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Default()
+			config.Prompt.Packing = tt.packing
+			config.Prompt.Budgets = tt.budgets
+			err := config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestLoadFromFile_NotFound(t *testing.T) {
 	_, err := LoadFromFile("/nonexistent/path/config.yaml")
 	if err == nil {
@@ -697,3 +763,449 @@ func TestSaveTo_CreatesDirectory(t *testing.T) {
 		t.Fatalf("config file should exist: %v", err)
 	}
 }
+
+func TestEnvOverrides_QualityConfig(t *testing.T) {
+	t.Setenv("FLOOP_QUALITY_ENABLED", "true")
+	t.Setenv("FLOOP_QUALITY_MIN_SCORE", "0.45")
+	t.Setenv("FLOOP_QUALITY_USE_LLM", "1")
+
+	config := Default()
+	applyEnvOverrides(config)
+
+	if !config.Quality.Enabled {
+		t.Error("expected Quality.Enabled to be true after env override")
+	}
+	if config.Quality.MinScore != 0.45 {
+		t.Errorf("expected Quality.MinScore 0.45, got %f", config.Quality.MinScore)
+	}
+	if !config.Quality.UseLLM {
+		t.Error("expected Quality.UseLLM to be true after env override")
+	}
+}
+
+func TestValidate_QualityConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		minScore float64
+		wantErr  bool
+	}{
+		{"zero", 0, false},
+		{"default", 0.3, false},
+		{"one", 1, false},
+		{"negative", -0.1, true},
+		{"above one", 1.5, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Default()
+			config.Quality.MinScore = tt.minScore
+			err := config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_SleepInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval string
+		wantErr  bool
+	}{
+		{"empty", "", false},
+		{"default", "24h", false},
+		{"days", "7d", false},
+		{"zero", "0h", true},
+		{"garbage", "nightly", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Default()
+			config.Consolidation.Sleep.Interval = tt.interval
+			err := config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_ExternalScorer(t *testing.T) {
+	tests := []struct {
+		name    string
+		ext     ExternalScorerConfig
+		wantErr bool
+	}{
+		{"disabled", ExternalScorerConfig{}, false},
+		{"command", ExternalScorerConfig{Enabled: true, Command: "python3 rank.py", Timeout: time.Second}, false},
+		{"url", ExternalScorerConfig{Enabled: true, URL: "http://localhost:8080/score"}, false},
+		{"neither", ExternalScorerConfig{Enabled: true}, true},
+		{"both", ExternalScorerConfig{Enabled: true, Command: "rank", URL: "http://localhost"}, true},
+		{"non-http url", ExternalScorerConfig{Enabled: true, URL: "file:///tmp/rank"}, true},
+		{"timeout too long", ExternalScorerConfig{Enabled: true, Command: "rank", Timeout: time.Minute}, true},
+		{"negative timeout", ExternalScorerConfig{Timeout: -time.Second}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Default()
+			config.Ranking.External = tt.ext
+			err := config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_Match(t *testing.T) {
+	tests := []struct {
+		name    string
+		match   MatchConfig
+		wantErr bool
+	}{
+		{"default strict", MatchConfig{Mode: "strict", MinScore: 0.5}, false},
+		{"unset mode", MatchConfig{}, false},
+		{"graded with weights", MatchConfig{Mode: "graded", MinScore: 0.6, FieldWeights: map[string]float64{"branch": 2}}, false},
+		{"unknown mode", MatchConfig{Mode: "fuzzy"}, true},
+		{"min score too high", MatchConfig{Mode: "graded", MinScore: 1.5}, true},
+		{"non-positive weight", MatchConfig{Mode: "graded", FieldWeights: map[string]float64{"task": 0}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Default()
+			config.Ranking.Match = tt.match
+			err := config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_StandingSeeds(t *testing.T) {
+	tests := []struct {
+		name    string
+		seeds   []StandingSeedConfig
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"tag with default activation", []StandingSeedConfig{{Tag: "security"}}, false},
+		{"behavior with activation", []StandingSeedConfig{{Behavior: "behavior-abc", Activation: 0.3}}, false},
+		{"neither tag nor behavior", []StandingSeedConfig{{Activation: 0.2}}, true},
+		{"both tag and behavior", []StandingSeedConfig{{Tag: "security", Behavior: "behavior-abc"}}, true},
+		{"activation too high", []StandingSeedConfig{{Tag: "security", Activation: 0.9}}, true},
+		{"negative activation", []StandingSeedConfig{{Tag: "security", Activation: -0.1}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Default()
+			config.Activation.StandingSeeds = tt.seeds
+			err := config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_EventBus(t *testing.T) {
+	tests := []struct {
+		name    string
+		sinks   []EventSinkConfig
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"file and stdout", []EventSinkConfig{{Type: "file"}, {Type: "stdout", Events: []string{"behavior.learned"}}}, false},
+		{"webhook", []EventSinkConfig{{Type: "webhook", URL: "https://hooks.example.com/floop", Timeout: 2 * time.Second}}, false},
+		{"webhook without url", []EventSinkConfig{{Type: "webhook"}}, true},
+		{"webhook with non-http url", []EventSinkConfig{{Type: "webhook", URL: "ftp://example.com"}}, true},
+		{"unknown type", []EventSinkConfig{{Type: "kafka"}}, true},
+		{"unknown event", []EventSinkConfig{{Type: "file", Events: []string{"behavior.updated"}}}, true},
+		{"timeout too long", []EventSinkConfig{{Type: "webhook", URL: "https://example.com", Timeout: time.Hour}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Default()
+			config.EventBus.Sinks = tt.sinks
+			err := config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_RateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxWait time.Duration
+		wantErr bool
+	}{
+		{"default", 500 * time.Millisecond, false},
+		{"disabled", 0, false},
+		{"at limit", time.Second, false},
+		{"too long", 2 * time.Second, true},
+		{"negative", -time.Millisecond, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Default()
+			config.RateLimit.MaxWait = tt.maxWait
+			err := config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEnvOverrides_RateLimit(t *testing.T) {
+	t.Setenv("FLOOP_RATE_LIMIT_MAX_WAIT", "250ms")
+	t.Setenv("FLOOP_RATE_LIMIT_GLOBAL_CEILING", "2.5")
+
+	config := Default()
+	applyEnvOverrides(config)
+
+	if config.RateLimit.MaxWait != 250*time.Millisecond {
+		t.Errorf("expected RateLimit.MaxWait 250ms, got %v", config.RateLimit.MaxWait)
+	}
+	if config.RateLimit.GlobalCeiling != 2.5 {
+		t.Errorf("expected RateLimit.GlobalCeiling 2.5, got %v", config.RateLimit.GlobalCeiling)
+	}
+}
+
+func TestRateLimitConfig_Ceiling(t *testing.T) {
+	if got := (RateLimitConfig{}).Ceiling(); got != 4 {
+		t.Errorf("unset Ceiling() = %v, want the default 4", got)
+	}
+	if got := (RateLimitConfig{GlobalCeiling: 2}).Ceiling(); got != 2 {
+		t.Errorf("Ceiling() = %v, want 2", got)
+	}
+
+	config := Default()
+	config.RateLimit.GlobalCeiling = 0.5
+	if err := config.Validate(); err == nil {
+		t.Error("Validate() should reject a global ceiling below 1")
+	}
+}
+
+func TestToolTimeoutsConfig_Timeout(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ToolTimeoutsConfig
+		tool string
+		want time.Duration
+	}{
+		{"built-in", ToolTimeoutsConfig{}, "floop_active", 2 * time.Second},
+		{"default", ToolTimeoutsConfig{}, "floop_learn", 30 * time.Second},
+		{"configured default", ToolTimeoutsConfig{Default: 5 * time.Second}, "floop_learn", 5 * time.Second},
+		{"configured default keeps built-ins", ToolTimeoutsConfig{Default: 5 * time.Second}, "floop_deduplicate", time.Minute},
+		{"tool override", ToolTimeoutsConfig{Tools: map[string]time.Duration{"floop_active": 500 * time.Millisecond}}, "floop_active", 500 * time.Millisecond},
+		{"tool disabled", ToolTimeoutsConfig{Tools: map[string]time.Duration{"floop_active": -1}}, "floop_active", 0},
+		{"all disabled", ToolTimeoutsConfig{Default: -1}, "floop_active", 0},
+		{"all disabled but one", ToolTimeoutsConfig{Default: -1, Tools: map[string]time.Duration{"floop_learn": time.Second}}, "floop_learn", time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.Timeout(tt.tool); got != tt.want {
+				t.Errorf("Timeout(%q) = %v, want %v", tt.tool, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolTimeoutsConfig_YAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := "tool_timeouts:\n  default: 10s\n  tools:\n    floop_active: 1500ms\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if got := config.ToolTimeouts.Timeout("floop_active"); got != 1500*time.Millisecond {
+		t.Errorf("floop_active timeout = %v, want 1.5s", got)
+	}
+	if got := config.ToolTimeouts.Timeout("floop_learn"); got != 10*time.Second {
+		t.Errorf("floop_learn timeout = %v, want 10s", got)
+	}
+}
+
+func TestEnvOverrides_Changelog(t *testing.T) {
+	t.Setenv("FLOOP_CHANGELOG_ENABLED", "1")
+
+	config := Default()
+	applyEnvOverrides(config)
+
+	if !config.Changelog.Enabled {
+		t.Error("expected Changelog.Enabled true")
+	}
+}
+
+func TestEnvOverrides_Sync(t *testing.T) {
+	t.Setenv("FLOOP_SYNC_REMOTE", "git@example.com:team/behaviors.git")
+	t.Setenv("FLOOP_SYNC_BRANCH", "shared")
+
+	config := Default()
+	applyEnvOverrides(config)
+
+	if config.Sync.Remote != "git@example.com:team/behaviors.git" || config.Sync.Branch != "shared" {
+		t.Errorf("expected sync overrides, got %+v", config.Sync)
+	}
+}
+
+func TestEnvOverrides_Replication(t *testing.T) {
+	t.Setenv("FLOOP_REPLICATION_TARGET", "s3://backups/floop")
+	t.Setenv("FLOOP_REPLICATION_STORES", "all")
+
+	config := Default()
+	applyEnvOverrides(config)
+
+	if config.Replication.Target != "s3://backups/floop" || config.Replication.Stores != "all" {
+		t.Errorf("expected replication overrides, got %+v", config.Replication)
+	}
+}
+
+func TestEnvOverrides_PromptPacking(t *testing.T) {
+	t.Setenv("FLOOP_PROMPT_PACKING", "value")
+
+	config := Default()
+	applyEnvOverrides(config)
+
+	if config.Prompt.Packing != "value" {
+		t.Errorf("Prompt.Packing = %q, want value", config.Prompt.Packing)
+	}
+}
+
+func TestEnvOverrides_Scopes(t *testing.T) {
+	t.Setenv("FLOOP_SCOPES", strings.Join([]string{"team", " ", "/srv/org-floop"}, string(filepath.ListSeparator)))
+
+	config := Default()
+	config.Stores.Scopes = []string{"go"}
+	applyEnvOverrides(config)
+
+	if want := []string{"go", "team", "/srv/org-floop"}; !reflect.DeepEqual(config.Stores.Scopes, want) {
+		t.Errorf("Stores.Scopes = %v, want %v", config.Stores.Scopes, want)
+	}
+}
+
+func TestEnvOverrides_PolicyStore(t *testing.T) {
+	t.Setenv("FLOOP_POLICY_STORE", "~/src/acme/policy")
+
+	config := Default()
+	applyEnvOverrides(config)
+
+	if config.Stores.Policy != "~/src/acme/policy" {
+		t.Errorf("Stores.Policy = %q, want ~/src/acme/policy", config.Stores.Policy)
+	}
+}
+
+func TestEnvOverrides_DedupSimilarity(t *testing.T) {
+	t.Setenv("FLOOP_DEDUP_SIMILARITY", "embedding")
+	t.Setenv("FLOOP_LLM_EMBEDDING_MODEL", "nomic-embed-text")
+
+	config := Default()
+	applyEnvOverrides(config)
+
+	if config.Deduplication.Similarity != "embedding" || config.LLM.EmbeddingModel != "nomic-embed-text" {
+		t.Errorf("expected similarity overrides, got %+v / %q", config.Deduplication, config.LLM.EmbeddingModel)
+	}
+	config.Deduplication.Similarity = "cosine"
+	if err := config.Validate(); err == nil {
+		t.Error("Validate() accepted an unknown similarity backend")
+	}
+}
+
+func TestLLMConfig_EmbeddingModelName(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  LLMConfig
+		want string
+	}{
+		{"openai", LLMConfig{Provider: "openai", EmbeddingModel: "text-embedding-3-small"}, "text-embedding-3-small"},
+		{"local embedding model", LLMConfig{Provider: "local", LocalModelPath: "/m/chat.gguf", LocalEmbeddingModelPath: "/m/nomic.gguf"}, "nomic.gguf"},
+		{"local model", LLMConfig{Provider: "local", LocalModelPath: "/m/chat.gguf"}, "chat.gguf"},
+		{"local unset", LLMConfig{Provider: "local"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.EmbeddingModelName(); got != tt.want {
+				t.Errorf("EmbeddingModelName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLearningConfig_ForScope(t *testing.T) {
+	threshold := 0.95
+	trustConstraints := false
+	base := Default().Learning
+	base.Scopes = map[string]LearningOverride{
+		"global": {AutoAcceptThreshold: &threshold, Review: ReviewOverride{Constraints: &trustConstraints}},
+	}
+
+	global := base.ForScope("global")
+	if global.AutoAcceptThreshold != threshold || global.Review.Constraints {
+		t.Errorf("ForScope(global) = %+v, want the override applied", global)
+	}
+	if global.AutoMergeThreshold != base.AutoMergeThreshold || global.Review.MinConfidence != base.Review.MinConfidence {
+		t.Errorf("ForScope(global) = %+v, want base values for unset fields", global)
+	}
+	if global.Scopes != nil {
+		t.Error("ForScope() should drop the per-scope overrides")
+	}
+
+	local := base.ForScope("local")
+	if local.AutoAcceptThreshold != base.AutoAcceptThreshold || !local.Review.Constraints {
+		t.Errorf("ForScope(local) = %+v, want the base policy", local)
+	}
+}
+
+func TestValidate_Learning(t *testing.T) {
+	tooHigh := 1.5
+	tests := []struct {
+		name    string
+		modify  func(*LearningConfig)
+		wantErr bool
+	}{
+		{"default", func(*LearningConfig) {}, false},
+		{"global routing", func(c *LearningConfig) { c.Scope = "global" }, false},
+		{"unknown routing", func(c *LearningConfig) { c.Scope = "team" }, true},
+		{"llm extractor", func(c *LearningConfig) { c.Extractor = "llm" }, false},
+		{"unknown extractor", func(c *LearningConfig) { c.Extractor = "regex" }, true},
+		{"auto accept too high", func(c *LearningConfig) { c.AutoAcceptThreshold = 1.2 }, true},
+		{"negative review confidence", func(c *LearningConfig) { c.Review.MinConfidence = -0.1 }, true},
+		{"unknown scope override", func(c *LearningConfig) { c.Scopes = map[string]LearningOverride{"team": {}} }, true},
+		{"invalid scope override", func(c *LearningConfig) {
+			c.Scopes = map[string]LearningOverride{"local": {AutoMergeThreshold: &tooHigh}}
+		}, true},
+		{"breaker disabled limits", func(c *LearningConfig) {
+			c.CircuitBreaker = CircuitBreakerConfig{}
+		}, false},
+		{"breaker repeats exceed window", func(c *LearningConfig) { c.CircuitBreaker.MaxRepeats = c.CircuitBreaker.Window + 1 }, true},
+		{"breaker similarity too high", func(c *LearningConfig) { c.CircuitBreaker.Similarity = 1.5 }, true},
+		{"negative breaker cooldown", func(c *LearningConfig) { c.CircuitBreaker.Cooldown = -time.Second }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Default()
+			tt.modify(&config.Learning)
+			err := config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,516 @@
+package consolidation
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/decay"
+	"github.com/nvandessel/floop/internal/dedup"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/outcome"
+	"github.com/nvandessel/floop/internal/ranking"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/summarization"
+)
+
+// SleepReportsFile records one report per sleep phase, relative to the
+// .floop directory.
+const SleepReportsFile = "sleep_reports.jsonl"
+
+// Sleep phase steps, in the order they run. The backup runs first so every
+// other change can be rolled back.
+const (
+	SleepStepBackup      = "backup"
+	SleepStepDedup       = "dedup"
+	SleepStepPrune       = "prune"
+	SleepStepRecalibrate = "recalibrate"
+	SleepStepSummarize   = "summarize"
+	SleepStepDecay       = "decay"
+)
+
+// SleepSteps lists every sleep phase step in run order.
+var SleepSteps = []string{SleepStepBackup, SleepStepDedup, SleepStepPrune, SleepStepRecalibrate, SleepStepSummarize, SleepStepDecay}
+
+// prunableEdgeKinds are the auto-derived edge kinds the sleep phase may
+// prune. Structural edges (requires, overrides, conflicts, ...) are never
+// touched.
+var prunableEdgeKinds = map[store.EdgeKind]bool{
+	store.EdgeKindSimilarTo:   true,
+	store.EdgeKindCoActivated: true,
+}
+
+// SleepOptions configures a sleep phase.
+type SleepOptions struct {
+	// DryRun reports what would change without writing anything.
+	DryRun bool
+
+	// Skip names steps to leave out (see SleepSteps).
+	Skip map[string]bool
+
+	// Now is the reference time for decay; zero means time.Now().
+	Now time.Time
+
+	// BackupDir receives the backup. Empty skips the backup step.
+	BackupDir string
+
+	// Retention, if set, is applied to BackupDir after the backup.
+	Retention backup.RetentionPolicy
+
+	// DedupThreshold is the similarity at or above which behaviors merge.
+	// Zero uses constants.DefaultAutoMergeThreshold.
+	DedupThreshold float64
+
+	// PruneThreshold is the decayed edge weight at or below which
+	// auto-derived edges are pruned. Zero uses constants.DefaultSleepPruneThreshold.
+	PruneThreshold float64
+
+	// StaleAfter is how long a behavior may go without activating before its
+	// confidence decays. Zero uses constants.DefaultStaleBehaviorDays.
+	StaleAfter time.Duration
+
+	// Outcomes are the downstream results linked to each behavior. Positive
+	// outcomes count toward recalibration like confirmations, negative ones
+	// like overrides.
+	Outcomes map[string]outcome.Stats
+}
+
+// SleepReport records everything a sleep phase changed (or, in a dry run,
+// would change).
+type SleepReport struct {
+	StartedAt    time.Time          `json:"started_at"`
+	Duration     string             `json:"duration"`
+	DryRun       bool               `json:"dry_run"`
+	Backup       string             `json:"backup,omitempty"`
+	Merged       []SleepMerge       `json:"merged,omitempty"`
+	PrunedEdges  []SleepPrunedEdge  `json:"pruned_edges,omitempty"`
+	Recalibrated []ConfidenceChange `json:"recalibrated,omitempty"`
+	Summarized   []SummaryChange    `json:"summarized,omitempty"`
+	Decayed      []ConfidenceChange `json:"decayed,omitempty"`
+	Errors       []string           `json:"errors,omitempty"`
+}
+
+// SleepMerge summarizes the duplicates merged in one scope. In a dry run only
+// Found is set.
+type SleepMerge struct {
+	Scope      string   `json:"scope"`
+	Found      int      `json:"found"`
+	KeptIDs    []string `json:"kept_ids,omitempty"`
+	RemovedIDs []string `json:"removed_ids,omitempty"`
+
+	// MergedFrom maps each kept ID to the removed IDs merged into it.
+	MergedFrom map[string][]string `json:"merged_from,omitempty"`
+
+	// ParentIDs are shared parents created to link context variants:
+	// similar behaviors with incompatible when-conditions.
+	ParentIDs []string `json:"parent_ids,omitempty"`
+}
+
+// SleepPrunedEdge is an auto-derived edge removed for being too weak.
+type SleepPrunedEdge struct {
+	Scope  string         `json:"scope"`
+	Source string         `json:"source"`
+	Target string         `json:"target"`
+	Kind   store.EdgeKind `json:"kind"`
+	Weight float64        `json:"weight"`
+}
+
+// ConfidenceChange is a behavior whose confidence was adjusted.
+type ConfidenceChange struct {
+	BehaviorID string  `json:"behavior_id"`
+	Name       string  `json:"name,omitempty"`
+	From       float64 `json:"from"`
+	To         float64 `json:"to"`
+	Reason     string  `json:"reason"`
+}
+
+// SummaryChange is a behavior that was given a summary.
+type SummaryChange struct {
+	BehaviorID string `json:"behavior_id"`
+	Name       string `json:"name,omitempty"`
+	Summary    string `json:"summary"`
+}
+
+// Changes returns the number of changes in the report, not counting the
+// backup.
+func (r *SleepReport) Changes() int {
+	n := len(r.PrunedEdges) + len(r.Recalibrated) + len(r.Summarized) + len(r.Decayed)
+	for _, m := range r.Merged {
+		if r.DryRun {
+			n += m.Found
+		} else {
+			n += len(m.RemovedIDs) + len(m.ParentIDs)
+		}
+	}
+	return n
+}
+
+// sleepScope is one store the sleep phase maintains.
+type sleepScope struct {
+	name  string
+	store store.GraphStore
+}
+
+// RunSleepPhase runs the sleep phase over graphStore: back up, merge
+// duplicates, prune weak auto-derived edges, recalibrate confidence from
+// follow/override signals, summarize behaviors that lack a summary, and decay
+// the confidence of behaviors that have not activated in a long time.
+//
+// Like sleep consolidating the day's memories, each step is conservative: a
+// failing step is recorded in the report and the rest still run.
+func RunSleepPhase(ctx context.Context, graphStore store.GraphStore, opts SleepOptions) (*SleepReport, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	started := time.Now()
+	report := &SleepReport{StartedAt: now, DryRun: opts.DryRun}
+
+	scopes := []sleepScope{{name: "all", store: graphStore}}
+	if ss, ok := graphStore.(store.ScopedStore); ok {
+		scopes = []sleepScope{
+			{name: string(constants.ScopeLocal), store: ss.LocalStore()},
+			{name: string(constants.ScopeGlobal), store: ss.GlobalStore()},
+		}
+	}
+
+	run := func(step string) bool { return !opts.Skip[step] }
+
+	if run(SleepStepBackup) && opts.BackupDir != "" && !opts.DryRun {
+		if err := sleepBackup(ctx, graphStore, opts, report); err != nil {
+			// Without a backup nothing else is safe to change
+			return report, err
+		}
+	}
+
+	for _, scope := range scopes {
+		if run(SleepStepDedup) {
+			if err := sleepDedup(ctx, scope, opts, report); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("dedup (%s): %v", scope.name, err))
+			}
+		}
+		if run(SleepStepPrune) {
+			if err := sleepPrune(ctx, scope, now, opts, report); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("prune (%s): %v", scope.name, err))
+			}
+		}
+		if run(SleepStepRecalibrate) || run(SleepStepDecay) {
+			if err := sleepConfidence(ctx, scope, now, opts, report); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("confidence (%s): %v", scope.name, err))
+			}
+		}
+		if run(SleepStepSummarize) {
+			if err := sleepSummarize(ctx, scope, opts, report); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("summarize (%s): %v", scope.name, err))
+			}
+		}
+	}
+
+	if !opts.DryRun {
+		if err := graphStore.Sync(ctx); err != nil {
+			return report, fmt.Errorf("failed to sync store: %w", err)
+		}
+	}
+
+	report.Duration = time.Since(started).Round(time.Millisecond).String()
+	return report, nil
+}
+
+// sleepBackup writes a backup and applies the retention policy.
+func sleepBackup(ctx context.Context, graphStore store.GraphStore, opts SleepOptions, report *SleepReport) error {
+	if err := os.MkdirAll(opts.BackupDir, 0700); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	path := backup.GenerateBackupPath(opts.BackupDir)
+	if _, err := backup.Backup(ctx, graphStore, path); err != nil {
+		return fmt.Errorf("backup failed: %w", err)
+	}
+	report.Backup = path
+	if opts.Retention != nil {
+		if _, err := backup.ApplyRetention(opts.BackupDir, opts.Retention); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("backup retention: %v", err))
+		}
+	}
+	return nil
+}
+
+// sleepDedup merges duplicate behaviors within one scope.
+func sleepDedup(ctx context.Context, scope sleepScope, opts SleepOptions, report *SleepReport) error {
+	threshold := opts.DedupThreshold
+	if threshold == 0 {
+		threshold = constants.DefaultAutoMergeThreshold
+	}
+	deduplicator := dedup.NewStoreDeduplicator(scope.store, dedup.NewBehaviorMerger(dedup.MergerConfig{}), dedup.DeduplicatorConfig{
+		SimilarityThreshold: threshold,
+		AutoMerge:           !opts.DryRun,
+	})
+	result, err := deduplicator.DeduplicateStore(ctx, scope.store)
+	if err != nil {
+		return err
+	}
+	report.Errors = append(report.Errors, result.Errors...)
+
+	if result.DuplicatesFound == 0 && len(result.SharedParents) == 0 {
+		return nil
+	}
+	m := SleepMerge{Scope: scope.name, Found: result.DuplicatesFound, RemovedIDs: result.DeletedIDs, MergedFrom: result.MergedFrom}
+	for _, merged := range result.MergedBehaviors {
+		m.KeptIDs = append(m.KeptIDs, merged.ID)
+	}
+	for _, parent := range result.SharedParents {
+		m.ParentIDs = append(m.ParentIDs, parent.ID)
+	}
+	report.Merged = append(report.Merged, m)
+	return nil
+}
+
+// sleepPrune removes auto-derived edges whose decayed weight is at or below
+// the prune threshold.
+func sleepPrune(ctx context.Context, scope sleepScope, now time.Time, opts SleepOptions, report *SleepReport) error {
+	threshold := opts.PruneThreshold
+	if threshold == 0 {
+		threshold = constants.DefaultSleepPruneThreshold
+	}
+	edges, err := allEdges(ctx, scope.store)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range edges {
+		if !prunableEdgeKinds[e.Kind] {
+			continue
+		}
+		weight := e.Weight
+		if e.LastActivated != nil {
+			hours := now.Sub(*e.LastActivated).Hours()
+			if hours > 0 {
+				weight = e.Weight * math.Exp(-ranking.DefaultDecayRate*hours)
+			}
+		}
+		if weight > threshold {
+			continue
+		}
+		if !opts.DryRun {
+			if err := scope.store.RemoveEdge(ctx, e.Source, e.Target, e.Kind); err != nil {
+				return fmt.Errorf("failed to remove edge %s -> %s: %w", e.Source, e.Target, err)
+			}
+		}
+		report.PrunedEdges = append(report.PrunedEdges, SleepPrunedEdge{
+			Scope: scope.name, Source: e.Source, Target: e.Target, Kind: e.Kind, Weight: round2(weight),
+		})
+	}
+	return nil
+}
+
+// allEdges returns every edge in s once, using GetAllEdges when available.
+func allEdges(ctx context.Context, s store.GraphStore) ([]store.Edge, error) {
+	if es, ok := s.(store.ExtendedGraphStore); ok {
+		return es.GetAllEdges(ctx)
+	}
+	nodes, err := s.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query behaviors: %w", err)
+	}
+	var edges []store.Edge
+	for _, n := range nodes {
+		out, err := s.GetEdges(ctx, n.ID, store.DirectionOutbound, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get edges for %s: %w", n.ID, err)
+		}
+		edges = append(edges, out...)
+	}
+	return edges, nil
+}
+
+// sleepConfidence recalibrates confidence toward the observed follow rate
+// and decays behaviors that have not activated within StaleAfter.
+// Constraints and authored or imported behaviors are deliberate and never
+// decay.
+func sleepConfidence(ctx context.Context, scope sleepScope, now time.Time, opts SleepOptions, report *SleepReport) error {
+	staleAfter := opts.StaleAfter
+	if staleAfter == 0 {
+		staleAfter = constants.DefaultStaleBehaviorDays * 24 * time.Hour
+	}
+	bounds := ranking.DefaultReinforcementConfig()
+
+	nodes, err := sortedBehaviorNodes(ctx, scope.store)
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		b := models.NodeToBehavior(node)
+		conf := b.Confidence
+
+		if !opts.Skip[SleepStepRecalibrate] {
+			outcomes := opts.Outcomes[b.ID]
+			if target, ok := recalibratedConfidence(b, outcomes, bounds); ok && math.Abs(target-conf) >= 0.01 {
+				reason := fmt.Sprintf("followed %d, confirmed %d, overridden %d", b.Stats.TimesFollowed, b.Stats.TimesConfirmed, b.Stats.TimesOverridden)
+				if outcomes.Total() > 0 {
+					reason += fmt.Sprintf(", outcomes %d good / %d bad", outcomes.Positive, outcomes.Negative)
+				}
+				report.Recalibrated = append(report.Recalibrated, ConfidenceChange{
+					BehaviorID: b.ID, Name: b.Name, From: round2(conf), To: target,
+					Reason: reason,
+				})
+				conf = target
+			}
+		}
+
+		if !opts.Skip[SleepStepDecay] && decay.Eligible(b) && conf > bounds.Floor {
+			lastUsed := decay.NodeLastUsed(node)
+			if !lastUsed.IsZero() && now.Sub(lastUsed) > staleAfter {
+				decayed := round2(math.Max(bounds.Floor, conf-constants.StaleConfidenceDecay))
+				report.Decayed = append(report.Decayed, ConfidenceChange{
+					BehaviorID: b.ID, Name: b.Name, From: round2(conf), To: decayed,
+					Reason: fmt.Sprintf("not activated or confirmed for %d days", int(now.Sub(lastUsed).Hours()/24)),
+				})
+				conf = decayed
+			}
+		}
+
+		if conf == b.Confidence || opts.DryRun {
+			continue
+		}
+		if err := setConfidence(ctx, scope.store, node, conf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recalibratedConfidence moves confidence halfway toward the smoothed rate at
+// which the behavior was followed or confirmed rather than overridden, and
+// its linked outcomes were good rather than bad, once there are enough
+// signals to trust it.
+func recalibratedConfidence(b models.Behavior, outcomes outcome.Stats, bounds ranking.ConfidenceReinforcementConfig) (float64, bool) {
+	positive := b.Stats.TimesFollowed + b.Stats.TimesConfirmed + outcomes.Positive
+	samples := positive + b.Stats.TimesOverridden + outcomes.Negative
+	if samples < constants.MinRecalibrationSamples {
+		return 0, false
+	}
+	observed := float64(positive+1) / float64(samples+2)
+	target := b.Confidence + (observed-b.Confidence)/2
+	return round2(math.Min(bounds.Ceiling, math.Max(bounds.Floor, target))), true
+}
+
+// setConfidence writes a new confidence, through UpdateConfidence when the
+// store supports it.
+func setConfidence(ctx context.Context, s store.GraphStore, node store.Node, conf float64) error {
+	if es, ok := s.(store.ExtendedGraphStore); ok {
+		return es.UpdateConfidence(ctx, node.ID, conf)
+	}
+	if node.Metadata == nil {
+		node.Metadata = make(map[string]interface{})
+	}
+	node.Metadata["confidence"] = conf
+	return s.UpdateNode(ctx, node)
+}
+
+// sleepSummarize gives a summary to every behavior that lacks one.
+func sleepSummarize(ctx context.Context, scope sleepScope, opts SleepOptions, report *SleepReport) error {
+	summarizer := summarization.NewRuleSummarizer(summarization.DefaultConfig())
+	nodes, err := sortedBehaviorNodes(ctx, scope.store)
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		b := models.NodeToBehavior(node)
+		if b.Content.Summary != "" || b.Content.Canonical == "" {
+			continue
+		}
+		summary, err := summarizer.Summarize(&b)
+		if err != nil || summary == "" {
+			continue
+		}
+		if !opts.DryRun {
+			switch content := node.Content["content"].(type) {
+			case map[string]interface{}:
+				content["summary"] = summary
+			default:
+				b.Content.Summary = summary
+				node.Content["content"] = b.Content
+			}
+			if err := scope.store.UpdateNode(ctx, node); err != nil {
+				return fmt.Errorf("failed to update summary for %s: %w", b.ID, err)
+			}
+		}
+		report.Summarized = append(report.Summarized, SummaryChange{BehaviorID: b.ID, Name: b.Name, Summary: summary})
+	}
+	return nil
+}
+
+// sortedBehaviorNodes returns the behaviors in s ordered by ID, so reports
+// are deterministic.
+func sortedBehaviorNodes(ctx context.Context, s store.GraphStore) ([]store.Node, error) {
+	nodes, err := s.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query behaviors: %w", err)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes, nil
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
+
+// RecordSleepReport appends a sleep report to floopDir.
+func RecordSleepReport(floopDir string, report *SleepReport) error {
+	f, err := os.OpenFile(filepath.Join(floopDir, SleepReportsFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open sleep reports: %w", err)
+	}
+	defer f.Close()
+	if err := json.NewEncoder(f).Encode(report); err != nil {
+		return fmt.Errorf("failed to write sleep report: %w", err)
+	}
+	return nil
+}
+
+// LastSleepRun returns when the last non-dry-run sleep phase recorded in
+// floopDir started, or the zero time if none has run.
+func LastSleepRun(floopDir string) (time.Time, error) {
+	f, err := os.Open(filepath.Join(floopDir, SleepReportsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("failed to open sleep reports: %w", err)
+	}
+	defer f.Close()
+
+	var last time.Time
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var r SleepReport
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil || r.DryRun {
+			continue
+		}
+		if r.StartedAt.After(last) {
+			last = r.StartedAt
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return time.Time{}, fmt.Errorf("failed to read sleep reports: %w", err)
+	}
+	return last, nil
+}
+
+// SleepDue reports whether a sleep phase should run now, given the last run
+// recorded in floopDir and the minimum interval between runs.
+func SleepDue(floopDir string, interval time.Duration, now time.Time) (bool, error) {
+	last, err := LastSleepRun(floopDir)
+	if err != nil {
+		return false, err
+	}
+	return last.IsZero() || now.Sub(last) >= interval, nil
+}
@@ -0,0 +1,238 @@
+package consolidation
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/outcome"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func sleepTestNode(id, canonical string, kind models.BehaviorKind, confidence float64, stats map[string]interface{}) store.Node {
+	return store.Node{
+		ID:   id,
+		Kind: store.NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    id,
+			"kind":    string(kind),
+			"content": map[string]interface{}{"canonical": canonical},
+		},
+		Metadata: map[string]interface{}{
+			"confidence": confidence,
+			"stats":      stats,
+			"provenance": map[string]interface{}{"source_type": string(models.SourceTypeLearned)},
+		},
+	}
+}
+
+func newSleepTestStore(t *testing.T, now time.Time) store.GraphStore {
+	t.Helper()
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+
+	old := now.AddDate(0, 0, -200).Format(time.RFC3339)
+	recent := now.AddDate(0, 0, -1).Format(time.RFC3339)
+	nodes := []store.Node{
+		// Often overridden: recalibrated down
+		sleepTestNode("overridden", "use tabs for indentation in Go files", models.BehaviorKindDirective, 0.9,
+			map[string]interface{}{"times_overridden": 5, "created_at": recent, "last_activated": recent}),
+		// Not activated for 200 days: decays
+		sleepTestNode("stale", "prefer pathlib over os.path in Python", models.BehaviorKindPreference, 0.6,
+			map[string]interface{}{"created_at": old}),
+		// Stale constraint: never decays
+		sleepTestNode("rule", "never commit secrets to the repository", models.BehaviorKindConstraint, 0.8,
+			map[string]interface{}{"created_at": old}),
+	}
+	for _, n := range nodes {
+		if _, err := s.AddNode(ctx, n); err != nil {
+			t.Fatalf("AddNode failed: %v", err)
+		}
+	}
+
+	lastMonth := now.AddDate(0, 0, -30)
+	yesterday := now.AddDate(0, 0, -1)
+	edges := []store.Edge{
+		{Source: "overridden", Target: "stale", Kind: store.EdgeKindSimilarTo, Weight: 0.5, CreatedAt: lastMonth, LastActivated: &lastMonth},
+		{Source: "stale", Target: "rule", Kind: store.EdgeKindCoActivated, Weight: 0.8, CreatedAt: lastMonth, LastActivated: &yesterday},
+		{Source: "rule", Target: "stale", Kind: store.EdgeKindRequires, Weight: 0.01, CreatedAt: lastMonth, LastActivated: &lastMonth},
+	}
+	for _, e := range edges {
+		if err := s.AddEdge(ctx, e); err != nil {
+			t.Fatalf("AddEdge failed: %v", err)
+		}
+	}
+	return s
+}
+
+func TestRunSleepPhase(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 6, 1, 3, 0, 0, 0, time.UTC)
+
+	t.Run("applies changes and reports them", func(t *testing.T) {
+		s := newSleepTestStore(t, now)
+		backupDir := t.TempDir()
+
+		report, err := RunSleepPhase(ctx, s, SleepOptions{Now: now, BackupDir: backupDir})
+		if err != nil {
+			t.Fatalf("RunSleepPhase failed: %v", err)
+		}
+		if len(report.Errors) > 0 {
+			t.Fatalf("unexpected errors: %v", report.Errors)
+		}
+
+		if report.Backup == "" {
+			t.Error("expected a backup path")
+		} else if _, err := os.Stat(report.Backup); err != nil {
+			t.Errorf("backup not written: %v", err)
+		}
+
+		if len(report.PrunedEdges) != 1 || report.PrunedEdges[0].Source != "overridden" {
+			t.Errorf("PrunedEdges = %+v, want only the decayed similar-to edge", report.PrunedEdges)
+		}
+		if edges, _ := s.GetEdges(ctx, "rule", store.DirectionOutbound, store.EdgeKindRequires); len(edges) != 1 {
+			t.Error("structural edges must never be pruned")
+		}
+
+		if len(report.Recalibrated) != 1 || report.Recalibrated[0].BehaviorID != "overridden" || report.Recalibrated[0].To >= 0.9 {
+			t.Errorf("Recalibrated = %+v, want overridden lowered", report.Recalibrated)
+		}
+		if len(report.Decayed) != 1 || report.Decayed[0].BehaviorID != "stale" || report.Decayed[0].To != 0.55 {
+			t.Errorf("Decayed = %+v, want stale 0.60 -> 0.55", report.Decayed)
+		}
+		if len(report.Summarized) != 3 {
+			t.Errorf("Summarized %d behaviors, want 3", len(report.Summarized))
+		}
+
+		node, _ := s.GetNode(ctx, "stale")
+		b := models.NodeToBehavior(*node)
+		if b.Confidence != 0.55 {
+			t.Errorf("stale confidence = %.2f, want 0.55", b.Confidence)
+		}
+		if b.Content.Summary == "" {
+			t.Error("stale summary not written")
+		}
+		if report.Changes() != 6 {
+			t.Errorf("Changes() = %d, want 6", report.Changes())
+		}
+	})
+
+	t.Run("dry run changes nothing", func(t *testing.T) {
+		s := newSleepTestStore(t, now)
+		backupDir := t.TempDir()
+
+		report, err := RunSleepPhase(ctx, s, SleepOptions{Now: now, BackupDir: backupDir, DryRun: true})
+		if err != nil {
+			t.Fatalf("RunSleepPhase failed: %v", err)
+		}
+		if report.Changes() == 0 {
+			t.Error("dry run should still report changes")
+		}
+		if report.Backup != "" {
+			t.Error("dry run should not back up")
+		}
+		node, _ := s.GetNode(ctx, "stale")
+		if b := models.NodeToBehavior(*node); b.Confidence != 0.6 || b.Content.Summary != "" {
+			t.Errorf("dry run modified stale: confidence %.2f, summary %q", b.Confidence, b.Content.Summary)
+		}
+		if edges, _ := s.GetEdges(ctx, "overridden", store.DirectionOutbound, ""); len(edges) != 1 {
+			t.Error("dry run pruned an edge")
+		}
+	})
+
+	t.Run("skipped steps do not run", func(t *testing.T) {
+		s := newSleepTestStore(t, now)
+		report, err := RunSleepPhase(ctx, s, SleepOptions{Now: now, Skip: map[string]bool{
+			SleepStepPrune: true, SleepStepSummarize: true, SleepStepDecay: true,
+		}})
+		if err != nil {
+			t.Fatalf("RunSleepPhase failed: %v", err)
+		}
+		if len(report.PrunedEdges)+len(report.Summarized)+len(report.Decayed) != 0 {
+			t.Errorf("skipped steps ran: %+v", report)
+		}
+		if len(report.Recalibrated) != 1 {
+			t.Errorf("Recalibrated = %+v, want 1", report.Recalibrated)
+		}
+	})
+
+	t.Run("outcomes feed recalibration", func(t *testing.T) {
+		s := newSleepTestStore(t, now)
+		report, err := RunSleepPhase(ctx, s, SleepOptions{Now: now,
+			Skip: map[string]bool{SleepStepPrune: true, SleepStepSummarize: true, SleepStepDecay: true},
+			Outcomes: map[string]outcome.Stats{
+				"stale": {Negative: 4},
+			},
+		})
+		if err != nil {
+			t.Fatalf("RunSleepPhase failed: %v", err)
+		}
+		var stale *ConfidenceChange
+		for i := range report.Recalibrated {
+			if report.Recalibrated[i].BehaviorID == "stale" {
+				stale = &report.Recalibrated[i]
+			}
+		}
+		if stale == nil || stale.To >= 0.6 || !strings.Contains(stale.Reason, "0 good / 4 bad") {
+			t.Errorf("Recalibrated = %+v, want stale lowered by bad outcomes", report.Recalibrated)
+		}
+	})
+
+	t.Run("merges duplicates", func(t *testing.T) {
+		s := store.NewInMemoryGraphStore()
+		duplicates := map[string]string{
+			"dup-a": "use uv instead of pip for package management",
+			"dup-b": "use uv instead of pip for Python package management",
+		}
+		for id, canonical := range duplicates {
+			n := sleepTestNode(id, canonical, models.BehaviorKindDirective, 0.7, map[string]interface{}{})
+			if _, err := s.AddNode(ctx, n); err != nil {
+				t.Fatalf("AddNode failed: %v", err)
+			}
+		}
+		report, err := RunSleepPhase(ctx, s, SleepOptions{Now: now, DedupThreshold: 0.5, Skip: map[string]bool{SleepStepSummarize: true}})
+		if err != nil {
+			t.Fatalf("RunSleepPhase failed: %v", err)
+		}
+		if len(report.Merged) != 1 || len(report.Merged[0].RemovedIDs) != 1 {
+			t.Fatalf("Merged = %+v, want one duplicate removed", report.Merged)
+		}
+		if node, _ := s.GetNode(ctx, report.Merged[0].RemovedIDs[0]); node != nil {
+			t.Errorf("duplicate %s still in store after merge", node.ID)
+		}
+	})
+}
+
+func TestSleepDue(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 6, 1, 3, 0, 0, 0, time.UTC)
+
+	due, err := SleepDue(dir, 24*time.Hour, now)
+	if err != nil || !due {
+		t.Fatalf("SleepDue() = %v, %v; want due with no prior run", due, err)
+	}
+
+	if err := RecordSleepReport(dir, &SleepReport{StartedAt: now.Add(-2 * time.Hour)}); err != nil {
+		t.Fatalf("RecordSleepReport failed: %v", err)
+	}
+	if err := RecordSleepReport(dir, &SleepReport{StartedAt: now, DryRun: true}); err != nil {
+		t.Fatalf("RecordSleepReport failed: %v", err)
+	}
+
+	last, err := LastSleepRun(dir)
+	if err != nil {
+		t.Fatalf("LastSleepRun failed: %v", err)
+	}
+	if !last.Equal(now.Add(-2 * time.Hour)) {
+		t.Errorf("LastSleepRun() = %v, want the last non-dry run", last)
+	}
+	if due, _ := SleepDue(dir, 24*time.Hour, now); due {
+		t.Error("SleepDue() = true two hours after a run, want false")
+	}
+	if due, _ := SleepDue(dir, time.Hour, now); !due {
+		t.Error("SleepDue() = false after the interval, want true")
+	}
+}
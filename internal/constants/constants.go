@@ -26,6 +26,10 @@ const (
 	// LowConfidenceThreshold is the threshold below which behaviors require review.
 	// Behaviors with confidence below this need human verification.
 	LowConfidenceThreshold = 0.6
+
+	// ReviewSimilarityThreshold is the similarity to an existing behavior
+	// above which a learned behavior requires review as a likely duplicate.
+	ReviewSimilarityThreshold = 0.85
 )
 
 // Similarity weight constants for behavior comparison
@@ -52,6 +56,13 @@ const (
 
 	// SimilarToUpperBound is the upper bound; above this, behaviors are potential duplicates.
 	SimilarToUpperBound = 0.9
+
+	// GeneralizeThreshold is the minimum content word overlap for grouping
+	// sibling behaviors under a suggested generalized parent.
+	GeneralizeThreshold = 0.5
+
+	// GeneralizeMinChildren is the minimum group size for a generalization suggestion.
+	GeneralizeMinChildren = 3
 )
 
 // Partial match constants control behavior matching with absent conditions.
@@ -60,6 +71,38 @@ const (
 	// when-conditions are all absent (none confirmed, none contradicted).
 	// This ensures they still participate in spreading activation at low priority.
 	AbsentFloorActivation = 0.15
+
+	// DefaultGradedMatchMinScore is the minimum weighted match score for a
+	// behavior with contradicted conditions to activate in graded mode.
+	DefaultGradedMatchMinScore = 0.5
+)
+
+// Temporal ranking constants (ranking.temporal).
+const (
+	// DefaultTemporalWeight is how far temporal ranking may scale a score
+	// when enabled without a configured weight.
+	DefaultTemporalWeight = 0.2
+
+	// DefaultTemporalBusyCalls is the number of tool calls within
+	// TemporalIntensityWindowMinutes at which a session counts as fully busy.
+	DefaultTemporalBusyCalls = 30
+
+	// TemporalIntensityWindowMinutes is the recent window session intensity
+	// is measured over.
+	TemporalIntensityWindowMinutes = 10
+)
+
+// Standing seed constants bound the activation injected by configured
+// project priorities (activation.standing_seeds).
+const (
+	// DefaultStandingSeedActivation is the activation a standing seed injects
+	// when none is configured.
+	DefaultStandingSeedActivation = 0.2
+
+	// MaxStandingSeedActivation caps a standing seed's activation. After
+	// sigmoid squashing it stays below FullTierActivationThreshold, so a
+	// standing seed alone never earns full injection.
+	MaxStandingSeedActivation = 0.35
 )
 
 // Activation tier thresholds determine which injection tier a behavior receives
@@ -102,6 +145,156 @@ const (
 	DefaultAutoAcceptThreshold = 0.8
 )
 
+// Correction quality gate thresholds control which corrections are extracted.
+const (
+	// DefaultMinCorrectionQuality is the minimum quality score for extracting a
+	// behavior from a correction. Lower-scoring corrections are held for review.
+	DefaultMinCorrectionQuality = 0.3
+)
+
+// Storage limits keep each store from growing without bound. When a limit is
+// reached, learning proposes consolidation instead of adding behaviors.
+// Zero disables a limit.
+const (
+	// DefaultMaxBehaviorsPerScope is the maximum number of behaviors in the
+	// local or global store.
+	DefaultMaxBehaviorsPerScope = 5000
+
+	// DefaultMaxCanonicalLength is the maximum length, in bytes, of a learned
+	// behavior's canonical content.
+	DefaultMaxCanonicalLength = 1500
+
+	// DefaultMaxEdgesPerNode is the maximum number of edges touching a single
+	// behavior. Further auto-generated edges are skipped.
+	DefaultMaxEdgesPerNode = 200
+)
+
+// Sleep-phase consolidation thresholds control the periodic maintenance pass.
+const (
+	// DefaultSleepPruneThreshold is the decayed weight at or below which an
+	// auto-derived edge (similar-to, co-activated) is pruned.
+	DefaultSleepPruneThreshold = 0.05
+
+	// DefaultStaleBehaviorDays is how long a behavior may go without
+	// activating before its confidence starts to decay.
+	DefaultStaleBehaviorDays = 90
+
+	// StaleConfidenceDecay is the confidence a stale behavior loses per
+	// sleep phase or decay pass.
+	StaleConfidenceDecay = 0.05
+
+	// DefaultDecayPruneDays is how long a behavior may go without activating
+	// or being confirmed before a decay pass retires it.
+	DefaultDecayPruneDays = 180
+
+	// MinRecalibrationSamples is the number of follow/confirm/override
+	// signals needed before confidence is recalibrated from them.
+	MinRecalibrationSamples = 3
+)
+
+// External scorer bounds keep a ranking hook from stalling activation.
+const (
+	// DefaultExternalScorerTimeoutMs is how long an external scorer may take
+	// before floop falls back to built-in ranking.
+	DefaultExternalScorerTimeoutMs = 300
+
+	// MaxExternalScorerTimeoutMs is the longest configurable scorer timeout.
+	MaxExternalScorerTimeoutMs = 5000
+
+	// MaxExternalScoreAdjustment bounds the adjustment an external scorer
+	// may apply to a behavior's [0, 1] score, in either direction.
+	MaxExternalScoreAdjustment = 0.5
+
+	// MaxExternalScorerResponseBytes is the largest scorer response read.
+	MaxExternalScorerResponseBytes = 1 << 20
+)
+
+// Promotion thresholds decide when a local behavior has proven itself
+// enough to move into the global store (floop promote).
+const (
+	// DefaultPromoteMinConfirmations is how many times a behavior must have
+	// been confirmed before it can be promoted without forcing.
+	DefaultPromoteMinConfirmations = 3
+
+	// DefaultPromoteMinConfidence is the lowest confidence of a behavior
+	// suggested for promotion.
+	DefaultPromoteMinConfidence = 0.7
+
+	// DefaultPromoteMaxOverrideRatio is the largest share of a suggested
+	// behavior's feedback that may be overrides.
+	DefaultPromoteMaxOverrideRatio = 0.2
+)
+
+// Webhook event sinks are bounded so a slow endpoint cannot back up the
+// event bus.
+const (
+	// DefaultWebhookTimeoutMs is how long a webhook sink waits for each
+	// event to be accepted.
+	DefaultWebhookTimeoutMs = 5000
+
+	// MaxWebhookTimeoutMs is the longest configurable webhook timeout.
+	MaxWebhookTimeoutMs = 30000
+)
+
+// Rate limit waits let the MCP server absorb sub-second limits instead of
+// failing the call.
+const (
+	// DefaultRateLimitMaxWaitMs is how long a rate-limited tool call may wait
+	// for its next token before it fails.
+	DefaultRateLimitMaxWaitMs = 500
+
+	// MaxRateLimitMaxWaitMs is the longest configurable wait.
+	MaxRateLimitMaxWaitMs = 1000
+
+	// DefaultRateLimitGlobalCeiling is how many clients' worth of each
+	// tool's rate limit all clients of a server may use together.
+	DefaultRateLimitGlobalCeiling = 4
+)
+
+// DefaultToolTimeoutSeconds is the deadline of MCP tool calls without a
+// built-in or configured one (tool_timeouts).
+const DefaultToolTimeoutSeconds = 30
+
+// Learning circuit breaker defaults stop a tight agent loop from flooding
+// the store with near-identical corrections.
+const (
+	// DefaultBreakerMaxPerSession is how many corrections one MCP session may
+	// learn.
+	DefaultBreakerMaxPerSession = 50
+
+	// DefaultBreakerWindow is how many recent corrections each new one is
+	// compared with.
+	DefaultBreakerWindow = 10
+
+	// DefaultBreakerSimilarity is the content similarity at which two
+	// corrections count as repeats.
+	DefaultBreakerSimilarity = 0.7
+
+	// DefaultBreakerMaxRepeats is how many repeats within the window trip
+	// the breaker.
+	DefaultBreakerMaxRepeats = 3
+
+	// DefaultBreakerCooldownMinutes is how long a tripped breaker rejects
+	// learn calls.
+	DefaultBreakerCooldownMinutes = 10
+)
+
+// DefaultSyncBranch is the branch "floop sync" pushes behaviors to when
+// sync.branch is not set.
+const DefaultSyncBranch = "floop-behaviors"
+
+// Retirement constants control the grace period of retired behaviors.
+const (
+	// DefaultRetirementGraceDays is how long a retired behavior is watched
+	// for recurring corrections before it is forgotten.
+	DefaultRetirementGraceDays = 14
+
+	// RetirementRecurrenceThreshold is the minimum similarity between a new
+	// correction's behavior and a retired one for the correction to count as
+	// recurring on the retired behavior's topic.
+	RetirementRecurrenceThreshold = 0.4
+)
+
 // Spreading activation sigmoid parameters control the squashing function
 // that maps raw activation into a sharp [0, 1] range.
 const (
@@ -119,6 +312,33 @@ const (
 	MaxBackupRotation = 10
 )
 
+// IntegrityCheckIntervalHours is how often opening a store runs SQLite's
+// structural integrity check. Opens in between skip it unless a schema
+// migration is pending.
+const IntegrityCheckIntervalHours = 24
+
+// MaxRestorePoints is how many restore points are kept in
+// .floop/restore-points/; older ones are removed when a new one is saved.
+const MaxRestorePoints = 20
+
+// Query constants bound the results returned by the floop_query MCP tool.
+const (
+	// DefaultQueryLimit is how many behaviors floop_query returns when no
+	// limit is given.
+	DefaultQueryLimit = 10
+
+	// MaxQueryLimit is the most behaviors a single floop_query call returns.
+	MaxQueryLimit = 50
+
+	// MaxQueryPreviewLen is the maximum length of the canonical text
+	// preview in a floop_query result.
+	MaxQueryPreviewLen = 160
+)
+
+// MaxChangesetFiles is the most files a single floop_active call or
+// `floop active --files` evaluates as one changeset.
+const MaxChangesetFiles = 50
+
 // Scoring constants used in the relevance scorer.
 const (
 	// NeutralScore is the default score returned when insufficient data is available.
@@ -0,0 +1,61 @@
+package corrections
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// ReadJSONL reads a corrections.jsonl file. A missing file has no
+// corrections. A correction logged more than once (deferred, then
+// processed) keeps its last entry, in the position of its first.
+func ReadJSONL(path string) ([]models.Correction, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var corrections []models.Correction
+	index := make(map[string]int)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var c models.Correction
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if i, ok := index[c.ID]; ok && c.ID != "" {
+			corrections[i] = c
+			continue
+		}
+		index[c.ID] = len(corrections)
+		corrections = append(corrections, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return corrections, nil
+}
+
+// WriteJSONL writes corrections to w, one JSON object per line.
+func WriteJSONL(w io.Writer, corrections []models.Correction) error {
+	enc := json.NewEncoder(w)
+	for _, c := range corrections {
+		if err := enc.Encode(c); err != nil {
+			return fmt.Errorf("failed to write correction %s: %w", c.ID, err)
+		}
+	}
+	return nil
+}
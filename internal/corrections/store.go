@@ -0,0 +1,496 @@
+// Package corrections stores captured corrections in the corrections table
+// of a .floop directory's floop.db, where they can be listed by time and
+// processed state a page at a time.
+//
+// Corrections used to be appended to corrections.jsonl. JSONL is now only
+// an export format: a corrections.jsonl found in a .floop directory, left
+// by an earlier floop or restored by 'floop import-all', is imported into
+// the table when the directory's corrections are opened, and then renamed
+// with ImportedSuffix.
+package corrections
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/utils"
+	_ "modernc.org/sqlite"
+)
+
+// File is the name of the JSONL export of a scope's corrections, and of
+// the log earlier floop versions kept them in.
+const File = "corrections.jsonl"
+
+// ImportedSuffix is appended to a corrections.jsonl once its corrections
+// have been imported into the database.
+const ImportedSuffix = ".imported"
+
+// Filter selects corrections for ListCorrections and CountCorrections.
+// The zero Filter selects every correction.
+type Filter struct {
+	// Since and Until bound the correction timestamp: at or after Since,
+	// and before Until. Zero means unbounded.
+	Since time.Time
+	Until time.Time
+
+	// Processed, when set, selects processed or unprocessed corrections.
+	Processed *bool
+
+	// Cursor continues a listing from the cursor a previous page returned.
+	// Limit is the page size; zero or less means no limit.
+	Cursor string
+	Limit  int
+}
+
+// Store is the persistence of captured corrections.
+type Store interface {
+	// AddCorrection inserts c, or updates the correction with its ID. A
+	// correction once processed stays processed.
+	AddCorrection(ctx context.Context, c models.Correction) error
+
+	// AddCorrections adds each of cs like AddCorrection, in one
+	// transaction.
+	AddCorrections(ctx context.Context, cs []models.Correction) error
+
+	// GetCorrection returns the correction with the given ID, or nil.
+	GetCorrection(ctx context.Context, id string) (*models.Correction, error)
+
+	// ListCorrections returns the corrections matching filter in time
+	// order, oldest first, and the cursor of the next page. An empty next
+	// cursor means this was the last page.
+	ListCorrections(ctx context.Context, filter Filter) ([]models.Correction, string, error)
+
+	// CountCorrections counts the corrections matching filter, ignoring
+	// its cursor and limit.
+	CountCorrections(ctx context.Context, filter Filter) (int, error)
+}
+
+// SQLiteStore implements Store using the corrections table of floop.db.
+type SQLiteStore struct {
+	db    *sql.DB
+	owned bool // db was opened by Open and is closed by Close
+}
+
+// NewSQLiteStore creates a SQLiteStore backed by the given database.
+func NewSQLiteStore(db *sql.DB) *SQLiteStore {
+	return &SQLiteStore{db: db}
+}
+
+// InitSchema creates the corrections table if it does not exist and brings
+// it up to date.
+func (s *SQLiteStore) InitSchema(ctx context.Context) error {
+	if err := store.InitCorrectionsSchema(ctx, s.db); err != nil {
+		return fmt.Errorf("initializing corrections schema: %w", err)
+	}
+	return nil
+}
+
+// Open opens the corrections of the .floop directory floopDir, creating
+// the directory and its floop.db if needed, and imports a corrections.jsonl
+// left in it.
+func Open(floopDir string) (*SQLiteStore, error) {
+	if err := os.MkdirAll(floopDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create .floop directory: %w", err)
+	}
+	if err := store.EnsureGitignore(floopDir); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+	dbPath := filepath.Join(floopDir, "floop.db")
+	db, err := sql.Open("sqlite", dbPath+"?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	s := &SQLiteStore{db: db, owned: true}
+
+	ctx := context.Background()
+	if err := s.InitSchema(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if _, err := s.ImportJSONL(ctx, filepath.Join(floopDir, File)); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close closes the database if Open opened it.
+func (s *SQLiteStore) Close() error {
+	if !s.owned {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// Append adds corrections to the .floop directory floopDir.
+func Append(floopDir string, cs ...models.Correction) error {
+	s, err := Open(floopDir)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	return s.AddCorrections(context.Background(), cs)
+}
+
+// Load returns every correction of the .floop directory floopDir, oldest
+// first. A directory with neither a floop.db nor a corrections.jsonl has
+// none, and is not created.
+func Load(floopDir string) ([]models.Correction, error) {
+	if !exists(filepath.Join(floopDir, "floop.db")) && !exists(filepath.Join(floopDir, File)) {
+		return nil, nil
+	}
+	s, err := Open(floopDir)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+	cs, _, err := s.ListCorrections(context.Background(), Filter{})
+	return cs, err
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// ImportJSONL adds the corrections of the JSONL file at path, then renames
+// the file with ImportedSuffix. A missing file imports nothing.
+func (s *SQLiteStore) ImportJSONL(ctx context.Context, path string) (int, error) {
+	cs, err := ReadJSONL(path)
+	if err != nil {
+		return 0, err
+	}
+	if cs == nil {
+		if _, statErr := os.Stat(path); errors.Is(statErr, os.ErrNotExist) {
+			return 0, nil
+		}
+	}
+	for i := range cs {
+		// Hand-written records may lack the ID floop would have given them
+		if cs[i].ID == "" {
+			cs[i].ID = fmt.Sprintf("c-%d", cs[i].Timestamp.UnixNano())
+		}
+	}
+	if err := s.AddCorrections(ctx, cs); err != nil {
+		return 0, fmt.Errorf("importing %s: %w", path, err)
+	}
+	// Another process may have imported and renamed it first
+	if err := os.Rename(path, path+ImportedSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, fmt.Errorf("renaming imported %s: %w", path, err)
+	}
+	return len(cs), nil
+}
+
+// upsertSQL inserts a correction or updates the one with its ID. Processed
+// only ever goes from 0 to 1, so re-adding an old copy of a correction,
+// such as one restored from an archive, does not undo its processing.
+const upsertSQL = `
+	INSERT INTO corrections (
+		id, timestamp, agent_action, corrected_action, human_response, context,
+		conversation_id, turn_number, corrector, extra_tags, extra_when,
+		failure_id, expires_at, processed, processed_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(id) DO UPDATE SET
+		timestamp = excluded.timestamp,
+		agent_action = excluded.agent_action,
+		corrected_action = excluded.corrected_action,
+		human_response = excluded.human_response,
+		context = excluded.context,
+		conversation_id = excluded.conversation_id,
+		turn_number = excluded.turn_number,
+		corrector = excluded.corrector,
+		extra_tags = excluded.extra_tags,
+		extra_when = excluded.extra_when,
+		failure_id = excluded.failure_id,
+		expires_at = excluded.expires_at,
+		processed = MAX(corrections.processed, excluded.processed),
+		processed_at = CASE WHEN corrections.processed = 1
+			THEN COALESCE(corrections.processed_at, excluded.processed_at)
+			ELSE excluded.processed_at END`
+
+// execer is implemented by *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// AddCorrection inserts c, or updates the correction with its ID.
+func (s *SQLiteStore) AddCorrection(ctx context.Context, c models.Correction) error {
+	return upsert(ctx, s.db, c)
+}
+
+// AddCorrections adds each of cs in one transaction.
+func (s *SQLiteStore) AddCorrections(ctx context.Context, cs []models.Correction) error {
+	if len(cs) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+	for _, c := range cs {
+		if err := upsert(ctx, tx, c); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing corrections: %w", err)
+	}
+	return nil
+}
+
+func upsert(ctx context.Context, db execer, c models.Correction) error {
+	if c.ID == "" {
+		return fmt.Errorf("correction has no ID")
+	}
+	contextJSON, err := json.Marshal(c.Context)
+	if err != nil {
+		return fmt.Errorf("marshaling context of %s: %w", c.ID, err)
+	}
+	tagsJSON, err := marshalNullable(len(c.ExtraTags) > 0, c.ExtraTags)
+	if err != nil {
+		return fmt.Errorf("marshaling extra tags of %s: %w", c.ID, err)
+	}
+	whenJSON, err := marshalNullable(len(c.ExtraWhen) > 0, c.ExtraWhen)
+	if err != nil {
+		return fmt.Errorf("marshaling extra when of %s: %w", c.ID, err)
+	}
+	processed := 0
+	if c.Processed {
+		processed = 1
+	}
+	_, err = db.ExecContext(ctx, upsertSQL,
+		c.ID, formatTime(c.Timestamp), c.AgentAction, c.CorrectedAction,
+		nullString(c.HumanResponse), string(contextJSON),
+		nullString(c.ConversationID), c.TurnNumber, nullString(c.Corrector),
+		tagsJSON, whenJSON, nullString(c.FailureID), nullTime(c.ExpiresAt),
+		processed, nullTime(c.ProcessedAt))
+	if err != nil {
+		return fmt.Errorf("adding correction %s: %w", c.ID, err)
+	}
+	return nil
+}
+
+const selectColumns = `SELECT id, timestamp, agent_action, corrected_action, human_response,
+	context, conversation_id, turn_number, corrector, extra_tags, extra_when,
+	failure_id, expires_at, processed, processed_at FROM corrections`
+
+// GetCorrection returns the correction with the given ID, or nil.
+func (s *SQLiteStore) GetCorrection(ctx context.Context, id string) (*models.Correction, error) {
+	rows, err := s.db.QueryContext(ctx, selectColumns+` WHERE id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("querying correction %s: %w", id, err)
+	}
+	cs, err := scanCorrections(rows)
+	if err != nil || len(cs) == 0 {
+		return nil, err
+	}
+	return &cs[0], nil
+}
+
+// ListCorrections returns a page of the corrections matching filter, oldest
+// first.
+func (s *SQLiteStore) ListCorrections(ctx context.Context, filter Filter) ([]models.Correction, string, error) {
+	where, args, err := filter.where(true)
+	if err != nil {
+		return nil, "", err
+	}
+	query := selectColumns + where + ` ORDER BY timestamp, id`
+	if filter.Limit > 0 {
+		// One more than the page tells whether another page follows
+		query += ` LIMIT ?`
+		args = append(args, filter.Limit+1)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("listing corrections: %w", err)
+	}
+	cs, err := scanCorrections(rows)
+	if err != nil {
+		return nil, "", err
+	}
+	next := ""
+	if filter.Limit > 0 && len(cs) > filter.Limit {
+		cs = cs[:filter.Limit]
+		last := cs[len(cs)-1]
+		next = encodeCursor(formatTime(last.Timestamp), last.ID)
+	}
+	return cs, next, nil
+}
+
+// CountCorrections counts the corrections matching filter.
+func (s *SQLiteStore) CountCorrections(ctx context.Context, filter Filter) (int, error) {
+	where, args, err := filter.where(false)
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM corrections`+where, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("counting corrections: %w", err)
+	}
+	return n, nil
+}
+
+// where builds the WHERE clause of f, with its cursor if withCursor.
+func (f Filter) where(withCursor bool) (string, []interface{}, error) {
+	var conds []string
+	var args []interface{}
+	if !f.Since.IsZero() {
+		conds = append(conds, `timestamp >= ?`)
+		args = append(args, formatTime(f.Since))
+	}
+	if !f.Until.IsZero() {
+		conds = append(conds, `timestamp < ?`)
+		args = append(args, formatTime(f.Until))
+	}
+	if f.Processed != nil {
+		if *f.Processed {
+			conds = append(conds, `processed = 1`)
+		} else {
+			conds = append(conds, `COALESCE(processed, 0) = 0`)
+		}
+	}
+	if withCursor && f.Cursor != "" {
+		ts, id, err := decodeCursor(f.Cursor)
+		if err != nil {
+			return "", nil, err
+		}
+		conds = append(conds, `(timestamp > ? OR (timestamp = ? AND id > ?))`)
+		args = append(args, ts, ts, id)
+	}
+	if len(conds) == 0 {
+		return "", nil, nil
+	}
+	return ` WHERE ` + strings.Join(conds, " AND "), args, nil
+}
+
+// ParseTimeBound parses a Since or Until bound given as an RFC 3339 time,
+// or as a duration such as 7d meaning that long ago.
+func ParseTimeBound(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	d, err := utils.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC 3339 time nor a duration such as 7d", value)
+	}
+	return time.Now().Add(-d), nil
+}
+
+// A cursor is the timestamp and ID of the last correction of a page.
+func encodeCursor(timestamp, id string) string {
+	return timestamp + "/" + id
+}
+
+func decodeCursor(cursor string) (string, string, error) {
+	ts, id, ok := strings.Cut(cursor, "/")
+	if !ok {
+		return "", "", fmt.Errorf("invalid corrections cursor %q", cursor)
+	}
+	if _, err := time.Parse(time.RFC3339, ts); err != nil {
+		return "", "", fmt.Errorf("invalid corrections cursor %q", cursor)
+	}
+	return ts, id, nil
+}
+
+func scanCorrections(rows *sql.Rows) ([]models.Correction, error) {
+	defer rows.Close()
+	var cs []models.Correction
+	for rows.Next() {
+		var (
+			c                                                   models.Correction
+			timestamp                                           string
+			humanResponse, contextJSON, conversationID          sql.NullString
+			corrector, tagsJSON, whenJSON, failureID, expiresAt sql.NullString
+			processedAt                                         sql.NullString
+			turnNumber, processed                               sql.NullInt64
+		)
+		if err := rows.Scan(&c.ID, &timestamp, &c.AgentAction, &c.CorrectedAction, &humanResponse,
+			&contextJSON, &conversationID, &turnNumber, &corrector, &tagsJSON, &whenJSON,
+			&failureID, &expiresAt, &processed, &processedAt); err != nil {
+			return nil, fmt.Errorf("scanning correction: %w", err)
+		}
+		c.Timestamp = parseTime(timestamp)
+		c.HumanResponse = humanResponse.String
+		c.ConversationID = conversationID.String
+		c.TurnNumber = int(turnNumber.Int64)
+		c.Corrector = corrector.String
+		c.FailureID = failureID.String
+		c.Processed = processed.Int64 == 1
+		if contextJSON.Valid && contextJSON.String != "" {
+			if err := json.Unmarshal([]byte(contextJSON.String), &c.Context); err != nil {
+				return nil, fmt.Errorf("unmarshaling context of %s: %w", c.ID, err)
+			}
+		}
+		if tagsJSON.Valid {
+			if err := json.Unmarshal([]byte(tagsJSON.String), &c.ExtraTags); err != nil {
+				return nil, fmt.Errorf("unmarshaling extra tags of %s: %w", c.ID, err)
+			}
+		}
+		if whenJSON.Valid {
+			if err := json.Unmarshal([]byte(whenJSON.String), &c.ExtraWhen); err != nil {
+				return nil, fmt.Errorf("unmarshaling extra when of %s: %w", c.ID, err)
+			}
+		}
+		if expiresAt.Valid {
+			t := parseTime(expiresAt.String)
+			c.ExpiresAt = &t
+		}
+		if processedAt.Valid {
+			t := parseTime(processedAt.String)
+			c.ProcessedAt = &t
+		}
+		cs = append(cs, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading corrections: %w", err)
+	}
+	return cs, nil
+}
+
+// timeLayout is RFC 3339 in UTC with fixed nanoseconds, so stored times
+// order as strings like the times they are.
+const timeLayout = "2006-01-02T15:04:05.000000000Z"
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+func nullTime(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: formatTime(*t), Valid: true}
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func marshalNullable(set bool, v interface{}) (sql.NullString, error) {
+	if !set {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
@@ -0,0 +1,300 @@
+package corrections
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	_ "modernc.org/sqlite"
+)
+
+func newTestStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("opening in-memory DB: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	s := NewSQLiteStore(db)
+	if err := s.InitSchema(context.Background()); err != nil {
+		t.Fatalf("InitSchema: %v", err)
+	}
+	return s
+}
+
+func ids(cs []models.Correction) []string {
+	out := []string{}
+	for _, c := range cs {
+		out = append(out, c.ID)
+	}
+	return out
+}
+
+func TestAddAndGetCorrection(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	ts := time.Date(2026, 3, 1, 9, 30, 0, 123456789, time.UTC)
+	expires := ts.Add(24 * time.Hour)
+	c := models.Correction{
+		ID:              "c-1",
+		Timestamp:       ts,
+		Context:         models.ContextSnapshot{FilePath: "main.go", Task: "refactor"},
+		AgentAction:     "used fmt.Println",
+		HumanResponse:   "use the logger",
+		CorrectedAction: "use slog",
+		ConversationID:  "conv-1",
+		TurnNumber:      3,
+		Corrector:       "ana",
+		ExtraTags:       []string{"logging"},
+		ExtraWhen:       map[string]interface{}{"language": "go"},
+		FailureID:       "f-1",
+		ExpiresAt:       &expires,
+	}
+	if err := s.AddCorrection(ctx, c); err != nil {
+		t.Fatalf("AddCorrection: %v", err)
+	}
+
+	got, err := s.GetCorrection(ctx, "c-1")
+	if err != nil || got == nil {
+		t.Fatalf("GetCorrection = %v, %v", got, err)
+	}
+	if !got.Timestamp.Equal(ts) || !got.ExpiresAt.Equal(expires) {
+		t.Errorf("times = %v, %v; want %v, %v", got.Timestamp, got.ExpiresAt, ts, expires)
+	}
+	got.Timestamp, got.ExpiresAt = c.Timestamp, c.ExpiresAt
+	if !reflect.DeepEqual(*got, c) {
+		t.Errorf("GetCorrection = %+v, want %+v", *got, c)
+	}
+
+	if missing, err := s.GetCorrection(ctx, "nope"); err != nil || missing != nil {
+		t.Errorf("GetCorrection(missing) = %v, %v; want nil, nil", missing, err)
+	}
+	if err := s.AddCorrection(ctx, models.Correction{}); err == nil {
+		t.Error("AddCorrection without an ID should fail")
+	}
+}
+
+func TestAddCorrection_ProcessedStaysProcessed(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	c := models.Correction{ID: "c-1", Timestamp: now, CorrectedAction: "first"}
+	s.AddCorrection(ctx, c)
+	c.Processed, c.ProcessedAt, c.CorrectedAction = true, &now, "second"
+	s.AddCorrection(ctx, c)
+
+	// An older copy, such as one restored from an archive
+	stale := models.Correction{ID: "c-1", Timestamp: now, CorrectedAction: "third"}
+	if err := s.AddCorrections(ctx, []models.Correction{stale}); err != nil {
+		t.Fatalf("AddCorrections: %v", err)
+	}
+	got, _ := s.GetCorrection(ctx, "c-1")
+	if !got.Processed || got.ProcessedAt == nil || !got.ProcessedAt.Equal(now) || got.CorrectedAction != "third" {
+		t.Errorf("correction = %+v, want processed at %v with the latest fields", got, now)
+	}
+}
+
+func TestListCorrections(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var cs []models.Correction
+	for i, id := range []string{"c-1", "c-2", "c-3", "c-4", "c-5"} {
+		cs = append(cs, models.Correction{
+			ID:        id,
+			Timestamp: base.Add(time.Duration(i) * 24 * time.Hour),
+			Processed: i%2 == 0,
+		})
+	}
+	// Same timestamp as c-3: ties order by ID
+	cs = append(cs, models.Correction{ID: "c-3b", Timestamp: cs[2].Timestamp})
+	// Added out of order, listed by time
+	if err := s.AddCorrections(ctx, []models.Correction{cs[4], cs[0], cs[5], cs[2], cs[1], cs[3]}); err != nil {
+		t.Fatalf("AddCorrections: %v", err)
+	}
+
+	yes, no := true, false
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"all", Filter{}, []string{"c-1", "c-2", "c-3", "c-3b", "c-4", "c-5"}},
+		{"since", Filter{Since: base.Add(48 * time.Hour)}, []string{"c-3", "c-3b", "c-4", "c-5"}},
+		{"until", Filter{Until: base.Add(48 * time.Hour)}, []string{"c-1", "c-2"}},
+		{"processed", Filter{Processed: &yes}, []string{"c-1", "c-3", "c-5"}},
+		{"unprocessed in range", Filter{Processed: &no, Since: base.Add(time.Hour), Until: base.Add(72 * time.Hour)}, []string{"c-2", "c-3b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, next, err := s.ListCorrections(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("ListCorrections: %v", err)
+			}
+			if !reflect.DeepEqual(ids(got), tt.want) || next != "" {
+				t.Errorf("ListCorrections = %v, %q; want %v, \"\"", ids(got), next, tt.want)
+			}
+			n, err := s.CountCorrections(ctx, tt.filter)
+			if err != nil || n != len(tt.want) {
+				t.Errorf("CountCorrections = %d, %v; want %d", n, err, len(tt.want))
+			}
+		})
+	}
+
+	t.Run("pages", func(t *testing.T) {
+		var pages [][]string
+		filter := Filter{Limit: 4}
+		for {
+			page, next, err := s.ListCorrections(ctx, filter)
+			if err != nil {
+				t.Fatalf("ListCorrections: %v", err)
+			}
+			pages = append(pages, ids(page))
+			if next == "" {
+				break
+			}
+			filter.Cursor = next
+		}
+		want := [][]string{{"c-1", "c-2", "c-3", "c-3b"}, {"c-4", "c-5"}}
+		if !reflect.DeepEqual(pages, want) {
+			t.Errorf("pages = %v, want %v", pages, want)
+		}
+	})
+
+	if _, _, err := s.ListCorrections(ctx, Filter{Cursor: "garbage"}); err == nil {
+		t.Error("ListCorrections with an invalid cursor should fail")
+	}
+}
+
+func TestOpen_ImportsJSONL(t *testing.T) {
+	floopDir := filepath.Join(t.TempDir(), ".floop")
+	os.MkdirAll(floopDir, 0700)
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.Encode(models.Correction{ID: "c-1", Timestamp: time.Now(), CorrectedAction: "deferred"})
+	enc.Encode(models.Correction{ID: "c-1", Timestamp: time.Now(), CorrectedAction: "processed", Processed: true})
+	path := filepath.Join(floopDir, File)
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Open(floopDir)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	got, _ := s.GetCorrection(context.Background(), "c-1")
+	s.Close()
+	if got == nil || !got.Processed || got.CorrectedAction != "processed" {
+		t.Fatalf("imported correction = %+v, want its last entry", got)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("%s still present after import", File)
+	}
+	if _, err := os.Stat(path + ImportedSuffix); err != nil {
+		t.Errorf("imported file not kept: %v", err)
+	}
+
+	// Reopening finds nothing more to import
+	s, err = Open(floopDir)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer s.Close()
+	if n, _ := s.CountCorrections(context.Background(), Filter{}); n != 1 {
+		t.Errorf("CountCorrections = %d, want 1", n)
+	}
+}
+
+func TestAppendAndLoad(t *testing.T) {
+	floopDir := filepath.Join(t.TempDir(), ".floop")
+	if got, err := Load(floopDir); err != nil || got != nil {
+		t.Fatalf("Load(empty) = %v, %v; want nil, nil", got, err)
+	}
+	if _, err := os.Stat(floopDir); !os.IsNotExist(err) {
+		t.Errorf("Load created %s", floopDir)
+	}
+
+	now := time.Now()
+	if err := Append(floopDir, models.Correction{ID: "c-2", Timestamp: now}, models.Correction{ID: "c-1", Timestamp: now.Add(-time.Minute)}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	got, err := Load(floopDir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if want := []string{"c-1", "c-2"}; !reflect.DeepEqual(ids(got), want) {
+		t.Errorf("Load = %v, want %v", ids(got), want)
+	}
+}
+
+func TestReadJSONL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "corrections.jsonl")
+	if got, err := ReadJSONL(path); err != nil || got != nil {
+		t.Fatalf("ReadJSONL(missing) = %v, %v; want nil, nil", got, err)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.Encode(models.Correction{ID: "c-1", CorrectedAction: "deferred"})
+	enc.Encode(models.Correction{ID: "c-2", CorrectedAction: "second"})
+	buf.WriteString("\n")
+	enc.Encode(models.Correction{ID: "c-1", CorrectedAction: "processed", Processed: true})
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadJSONL(path)
+	if err != nil {
+		t.Fatalf("ReadJSONL() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "c-1" || got[1].ID != "c-2" {
+		t.Fatalf("ReadJSONL() = %+v, want c-1 then c-2", got)
+	}
+	if !got[0].Processed || got[0].CorrectedAction != "processed" {
+		t.Errorf("c-1 = %+v, want its last entry", got[0])
+	}
+
+	os.WriteFile(path, []byte("{not json\n"), 0600)
+	if _, err := ReadJSONL(path); err == nil {
+		t.Error("ReadJSONL() of malformed line should fail")
+	}
+}
+
+func TestWriteJSONL(t *testing.T) {
+	cs := []models.Correction{{ID: "c-1", CorrectedAction: "a"}, {ID: "c-2", Processed: true}}
+	var buf bytes.Buffer
+	if err := WriteJSONL(&buf, cs); err != nil {
+		t.Fatalf("WriteJSONL: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), File)
+	os.WriteFile(path, buf.Bytes(), 0600)
+	got, err := ReadJSONL(path)
+	if err != nil || !reflect.DeepEqual(got, cs) {
+		t.Errorf("round trip = %+v, %v; want %+v", got, err, cs)
+	}
+}
+
+func TestParseTimeBound(t *testing.T) {
+	got, err := ParseTimeBound("2026-02-03T04:05:06Z")
+	if err != nil || !got.Equal(time.Date(2026, 2, 3, 4, 5, 6, 0, time.UTC)) {
+		t.Errorf("ParseTimeBound(RFC 3339) = %v, %v", got, err)
+	}
+	got, err = ParseTimeBound("7d")
+	if want := time.Now().Add(-7 * 24 * time.Hour); err != nil || got.Sub(want).Abs() > time.Minute {
+		t.Errorf("ParseTimeBound(7d) = %v, %v; want about %v", got, err, want)
+	}
+	if _, err := ParseTimeBound("last tuesday"); err == nil {
+		t.Error("ParseTimeBound of garbage should fail")
+	}
+}
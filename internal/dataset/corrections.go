@@ -0,0 +1,179 @@
+// Package dataset flattens floop's learning history into tabular datasets
+// for analysis outside floop.
+package dataset
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Source is the corrections log of one scope.
+type Source struct {
+	Scope       string // "local" or "global"
+	Corrections []models.Correction
+}
+
+// CorrectionRow is one correction paired with one behavior extracted from
+// it. A correction with no behavior on record has a single row with a nil
+// Behavior.
+type CorrectionRow struct {
+	Scope      string
+	Correction models.Correction
+	Behavior   *models.Behavior
+
+	// Status is the behavior's lifecycle state: active, forgotten,
+	// deprecated, merged, retired, or quarantined.
+	Status string
+}
+
+// Build pairs each correction with the behaviors in gs whose provenance
+// names it, including behaviors since forgotten, merged, or retired. Rows
+// follow the sources' order, then behavior ID.
+func Build(ctx context.Context, gs store.GraphStore, sources []Source) ([]CorrectionRow, error) {
+	nodes, err := gs.QueryNodes(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query behaviors: %w", err)
+	}
+
+	type extracted struct {
+		behavior models.Behavior
+		status   string
+	}
+	byCorrection := make(map[string][]extracted)
+	for _, node := range nodes {
+		status, ok := behaviorStatus(node.Kind)
+		if !ok {
+			continue
+		}
+		b := models.NodeToBehavior(node)
+		if b.Provenance.CorrectionID == "" {
+			continue
+		}
+		byCorrection[b.Provenance.CorrectionID] = append(byCorrection[b.Provenance.CorrectionID], extracted{b, status})
+	}
+	for _, list := range byCorrection {
+		sort.Slice(list, func(i, j int) bool { return list[i].behavior.ID < list[j].behavior.ID })
+	}
+
+	var rows []CorrectionRow
+	for _, src := range sources {
+		for _, c := range src.Corrections {
+			list := byCorrection[c.ID]
+			if len(list) == 0 {
+				rows = append(rows, CorrectionRow{Scope: src.Scope, Correction: c})
+				continue
+			}
+			for _, e := range list {
+				b := e.behavior
+				rows = append(rows, CorrectionRow{Scope: src.Scope, Correction: c, Behavior: &b, Status: e.status})
+			}
+		}
+	}
+	return rows, nil
+}
+
+// behaviorStatus maps a node kind to a lifecycle state, reporting false for
+// nodes that aren't behaviors.
+func behaviorStatus(kind store.NodeKind) (string, bool) {
+	switch kind {
+	case store.NodeKindBehavior:
+		return "active", true
+	case store.NodeKindForgotten, store.NodeKindDeprecated, store.NodeKindMerged,
+		store.NodeKindRetired, store.NodeKindQuarantined:
+		return strings.TrimSuffix(string(kind), "-behavior"), true
+	}
+	return "", false
+}
+
+// columnType is the type of a dataset column.
+type columnType int
+
+const (
+	typeString columnType = iota
+	typeInt
+	typeFloat
+	typeBool
+	typeTime
+)
+
+// column is one dataset column. value returns false for a null cell.
+type column struct {
+	name  string
+	typ   columnType
+	value func(r CorrectionRow) (any, bool)
+}
+
+// columns lists the dataset's columns in output order. List values are
+// joined with ";".
+var columns = []column{
+	{"correction_id", typeString, func(r CorrectionRow) (any, bool) { return r.Correction.ID, true }},
+	{"scope", typeString, func(r CorrectionRow) (any, bool) { return r.Scope, true }},
+	{"timestamp", typeTime, func(r CorrectionRow) (any, bool) { return r.Correction.Timestamp, !r.Correction.Timestamp.IsZero() }},
+	{"processed", typeBool, func(r CorrectionRow) (any, bool) { return r.Correction.Processed, true }},
+	{"processed_at", typeTime, func(r CorrectionRow) (any, bool) { return timeValue(r.Correction.ProcessedAt) }},
+	{"corrector", typeString, func(r CorrectionRow) (any, bool) { return r.Correction.Corrector, true }},
+	{"agent_action", typeString, func(r CorrectionRow) (any, bool) { return r.Correction.AgentAction, true }},
+	{"human_response", typeString, func(r CorrectionRow) (any, bool) { return r.Correction.HumanResponse, true }},
+	{"corrected_action", typeString, func(r CorrectionRow) (any, bool) { return r.Correction.CorrectedAction, true }},
+	{"failure_id", typeString, func(r CorrectionRow) (any, bool) { return r.Correction.FailureID, true }},
+	{"extra_tags", typeString, func(r CorrectionRow) (any, bool) { return strings.Join(r.Correction.ExtraTags, ";"), true }},
+	{"repo", typeString, func(r CorrectionRow) (any, bool) { return r.Correction.Context.Repo, true }},
+	{"branch", typeString, func(r CorrectionRow) (any, bool) { return r.Correction.Context.Branch, true }},
+	{"project_type", typeString, func(r CorrectionRow) (any, bool) { return string(r.Correction.Context.ProjectType), true }},
+	{"file_path", typeString, func(r CorrectionRow) (any, bool) { return r.Correction.Context.FilePath, true }},
+	{"file_language", typeString, func(r CorrectionRow) (any, bool) { return r.Correction.Context.FileLanguage, true }},
+	{"frameworks", typeString, func(r CorrectionRow) (any, bool) { return strings.Join(r.Correction.Context.Frameworks, ";"), true }},
+	{"task", typeString, func(r CorrectionRow) (any, bool) { return r.Correction.Context.Task, true }},
+	{"environment", typeString, func(r CorrectionRow) (any, bool) { return r.Correction.Context.Environment, true }},
+	{"agent", typeString, func(r CorrectionRow) (any, bool) { return r.Correction.Context.Agent, true }},
+	{"behavior_id", typeString, behaviorValue(func(b *models.Behavior) any { return b.ID })},
+	{"behavior_name", typeString, behaviorValue(func(b *models.Behavior) any { return b.Name })},
+	{"behavior_kind", typeString, behaviorValue(func(b *models.Behavior) any { return string(b.Kind) })},
+	{"behavior_status", typeString, func(r CorrectionRow) (any, bool) { return r.Status, r.Behavior != nil }},
+	{"behavior_tags", typeString, behaviorValue(func(b *models.Behavior) any { return strings.Join(b.Content.Tags, ";") })},
+	{"confidence", typeFloat, behaviorValue(func(b *models.Behavior) any { return b.Confidence })},
+	{"priority", typeInt, behaviorValue(func(b *models.Behavior) any { return int64(b.Priority) })},
+	{"times_activated", typeInt, behaviorValue(func(b *models.Behavior) any { return int64(b.Stats.TimesActivated) })},
+	{"times_followed", typeInt, behaviorValue(func(b *models.Behavior) any { return int64(b.Stats.TimesFollowed) })},
+	{"times_confirmed", typeInt, behaviorValue(func(b *models.Behavior) any { return int64(b.Stats.TimesConfirmed) })},
+	{"times_overridden", typeInt, behaviorValue(func(b *models.Behavior) any { return int64(b.Stats.TimesOverridden) })},
+	{"last_activated", typeTime, func(r CorrectionRow) (any, bool) {
+		if r.Behavior == nil {
+			return nil, false
+		}
+		return timeValue(r.Behavior.Stats.LastActivated)
+	}},
+}
+
+// behaviorValue wraps a behavior field as a column value, null for rows
+// without a behavior.
+func behaviorValue(get func(b *models.Behavior) any) func(r CorrectionRow) (any, bool) {
+	return func(r CorrectionRow) (any, bool) {
+		if r.Behavior == nil {
+			return nil, false
+		}
+		return get(r.Behavior), true
+	}
+}
+
+func timeValue(t *time.Time) (any, bool) {
+	if t == nil || t.IsZero() {
+		return nil, false
+	}
+	return *t, true
+}
+
+// ColumnNames returns the dataset's column names in output order.
+func ColumnNames() []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.name
+	}
+	return names
+}
@@ -0,0 +1,167 @@
+package dataset
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	"github.com/apache/arrow/go/v17/parquet/file"
+	"github.com/apache/arrow/go/v17/parquet/pqarrow"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestBuild(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+	addBehavior(t, s, "b-active", store.NodeKindBehavior, "c-1", 7)
+	addBehavior(t, s, "b-forgotten", store.NodeKindForgotten, "c-1", 0)
+	addBehavior(t, s, "b-other", store.NodeKindBehavior, "", 3)
+
+	rows, err := Build(ctx, s, []Source{
+		{Scope: "local", Corrections: []models.Correction{{ID: "c-1"}, {ID: "c-none"}}},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Build() = %d rows, want 3", len(rows))
+	}
+
+	want := []struct{ correction, behavior, status string }{
+		{"c-1", "b-active", "active"},
+		{"c-1", "b-forgotten", "forgotten"},
+		{"c-none", "", ""},
+	}
+	for i, w := range want {
+		r := rows[i]
+		behavior := ""
+		if r.Behavior != nil {
+			behavior = r.Behavior.ID
+		}
+		if r.Correction.ID != w.correction || behavior != w.behavior || r.Status != w.status || r.Scope != "local" {
+			t.Errorf("row %d = %s/%s/%s, want %s/%s/%s", i, r.Correction.ID, behavior, r.Status, w.correction, w.behavior, w.status)
+		}
+	}
+	if rows[0].Behavior.Stats.TimesActivated != 7 {
+		t.Errorf("times_activated = %d, want 7", rows[0].Behavior.Stats.TimesActivated)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	rows := testRows()
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, rows); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+	if len(records) != len(rows)+1 {
+		t.Fatalf("got %d records, want header + %d", len(records), len(rows))
+	}
+	cell := func(row int, name string) string {
+		for i, n := range records[0] {
+			if n == name {
+				return records[row][i]
+			}
+		}
+		t.Fatalf("missing column %s", name)
+		return ""
+	}
+	if cell(1, "behavior_id") != "b-1" || cell(1, "times_activated") != "4" || cell(1, "file_language") != "go" {
+		t.Errorf("row 1 = %v", records[1])
+	}
+	if cell(1, "timestamp") != "2026-10-01T12:00:00Z" || cell(1, "frameworks") != "gin;gorm" {
+		t.Errorf("row 1 timestamp/frameworks = %q/%q", cell(1, "timestamp"), cell(1, "frameworks"))
+	}
+	if cell(2, "behavior_id") != "" || cell(2, "times_activated") != "" {
+		t.Errorf("row 2 behavior cells = %q/%q, want empty", cell(2, "behavior_id"), cell(2, "times_activated"))
+	}
+}
+
+func TestWriteParquet(t *testing.T) {
+	rows := testRows()
+	var buf bytes.Buffer
+	if err := WriteParquet(&buf, rows); err != nil {
+		t.Fatalf("WriteParquet() error = %v", err)
+	}
+
+	pf, err := file.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("NewParquetReader() error = %v", err)
+	}
+	defer pf.Close()
+	reader, err := pqarrow.NewFileReader(pf, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		t.Fatalf("NewFileReader() error = %v", err)
+	}
+	table, err := reader.ReadTable(context.Background())
+	if err != nil {
+		t.Fatalf("ReadTable() error = %v", err)
+	}
+	defer table.Release()
+
+	if table.NumRows() != int64(len(rows)) {
+		t.Errorf("NumRows() = %d, want %d", table.NumRows(), len(rows))
+	}
+	if int(table.NumCols()) != len(ColumnNames()) {
+		t.Errorf("NumCols() = %d, want %d", table.NumCols(), len(ColumnNames()))
+	}
+	for i, name := range ColumnNames() {
+		if got := table.Schema().Field(i).Name; got != name {
+			t.Errorf("column %d = %s, want %s", i, got, name)
+		}
+	}
+}
+
+func TestWrite_UnknownFormat(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, "xlsx", nil); err == nil {
+		t.Error("Write() with unknown format should fail")
+	}
+}
+
+func testRows() []CorrectionRow {
+	ts := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	b := models.Behavior{ID: "b-1", Name: "use-gin", Kind: models.BehaviorKindDirective, Confidence: 0.8}
+	b.Stats.TimesActivated = 4
+	return []CorrectionRow{
+		{
+			Scope: "local",
+			Correction: models.Correction{
+				ID: "c-1", Timestamp: ts, CorrectedAction: "Use gin",
+				Context: models.ContextSnapshot{FileLanguage: "go", Frameworks: []string{"gin", "gorm"}},
+			},
+			Behavior: &b,
+			Status:   "active",
+		},
+		{Scope: "global", Correction: models.Correction{ID: "c-2", Timestamp: ts}},
+	}
+}
+
+func addBehavior(t *testing.T, s store.GraphStore, id string, kind store.NodeKind, correctionID string, activations int) {
+	t.Helper()
+	if _, err := s.AddNode(context.Background(), store.Node{
+		ID:   id,
+		Kind: kind,
+		Content: map[string]interface{}{
+			"name": id,
+			"kind": "directive",
+			"content": map[string]interface{}{
+				"canonical": id,
+			},
+		},
+		Metadata: map[string]interface{}{
+			"confidence": 0.7,
+			"provenance": map[string]interface{}{"source_type": "learned", "correction_id": correctionID},
+			"stats":      map[string]interface{}{"times_activated": activations},
+		},
+	}); err != nil {
+		t.Fatalf("AddNode(%s) error = %v", id, err)
+	}
+}
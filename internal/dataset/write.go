@@ -0,0 +1,133 @@
+package dataset
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	"github.com/apache/arrow/go/v17/parquet"
+	"github.com/apache/arrow/go/v17/parquet/compress"
+	"github.com/apache/arrow/go/v17/parquet/pqarrow"
+)
+
+// Formats lists the supported output formats.
+var Formats = []string{"csv", "parquet"}
+
+// Write writes rows in format ("csv" or "parquet").
+func Write(w io.Writer, format string, rows []CorrectionRow) error {
+	switch format {
+	case "csv":
+		return WriteCSV(w, rows)
+	case "parquet":
+		return WriteParquet(w, rows)
+	}
+	return fmt.Errorf("unknown format %q (valid: csv, parquet)", format)
+}
+
+// WriteCSV writes rows as CSV with a header row. Null cells are empty and
+// times are RFC3339.
+func WriteCSV(w io.Writer, rows []CorrectionRow) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(ColumnNames()); err != nil {
+		return err
+	}
+	record := make([]string, len(columns))
+	for _, r := range rows {
+		for i, c := range columns {
+			record[i] = ""
+			if v, ok := c.value(r); ok {
+				record[i] = formatCell(v)
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatCell(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprint(v)
+}
+
+// WriteParquet writes rows as a Snappy-compressed Parquet file. Every
+// column is nullable; times are UTC microsecond timestamps. w is not closed.
+func WriteParquet(w io.Writer, rows []CorrectionRow) error {
+	fields := make([]arrow.Field, len(columns))
+	for i, c := range columns {
+		fields[i] = arrow.Field{Name: c.name, Type: arrowType(c.typ), Nullable: true}
+	}
+	schema := arrow.NewSchema(fields, nil)
+
+	b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer b.Release()
+	for _, r := range rows {
+		for i, c := range columns {
+			v, ok := c.value(r)
+			if !ok {
+				b.Field(i).AppendNull()
+				continue
+			}
+			switch fb := b.Field(i).(type) {
+			case *array.StringBuilder:
+				fb.Append(v.(string))
+			case *array.Int64Builder:
+				fb.Append(v.(int64))
+			case *array.Float64Builder:
+				fb.Append(v.(float64))
+			case *array.BooleanBuilder:
+				fb.Append(v.(bool))
+			case *array.TimestampBuilder:
+				fb.Append(arrow.Timestamp(v.(time.Time).UnixMicro()))
+			}
+		}
+	}
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	props := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy))
+	fw, err := pqarrow.NewFileWriter(schema, nopCloser{w}, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return fmt.Errorf("failed to create parquet writer: %w", err)
+	}
+	if err := fw.Write(rec); err != nil {
+		fw.Close()
+		return fmt.Errorf("failed to write parquet rows: %w", err)
+	}
+	return fw.Close()
+}
+
+func arrowType(t columnType) arrow.DataType {
+	switch t {
+	case typeInt:
+		return arrow.PrimitiveTypes.Int64
+	case typeFloat:
+		return arrow.PrimitiveTypes.Float64
+	case typeBool:
+		return arrow.FixedWidthTypes.Boolean
+	case typeTime:
+		return &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}
+	}
+	return arrow.BinaryTypes.String
+}
+
+// nopCloser keeps the parquet writer from closing the caller's writer.
+type nopCloser struct{ io.Writer }
@@ -0,0 +1,275 @@
+// Package decay forgets behaviors gradually. A behavior that has not been
+// activated or confirmed for a while loses confidence, and one unused for
+// much longer is pruned: retired, so it is restored if a correction recurs
+// on its topic and forgotten when the retirement grace period ends.
+package decay
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/ranking"
+	"github.com/nvandessel/floop/internal/retirement"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// ReportsFile holds the reports of decay passes other than dry runs,
+// relative to the .floop directory.
+const ReportsFile = "decay_reports.jsonl"
+
+// Options configures a decay pass.
+type Options struct {
+	// DemoteAfter is how long a behavior may go unused before it loses
+	// confidence. Zero uses constants.DefaultStaleBehaviorDays.
+	DemoteAfter time.Duration
+
+	// PruneAfter is how long a behavior may go unused before it is retired.
+	// Zero disables pruning.
+	PruneAfter time.Duration
+
+	// Grace is the retirement grace period of pruned behaviors. Zero uses
+	// constants.DefaultRetirementGraceDays.
+	Grace time.Duration
+
+	// Now is the reference time; zero means time.Now().
+	Now time.Time
+
+	// DryRun reports what would change without writing anything.
+	DryRun bool
+}
+
+// Report records what a decay pass changed (or, in a dry run, would change).
+type Report struct {
+	StartedAt       time.Time `json:"started_at"`
+	DryRun          bool      `json:"dry_run"`
+	DemoteAfterDays int       `json:"demote_after_days"`
+	PruneAfterDays  int       `json:"prune_after_days,omitempty"`
+	Demoted         []Change  `json:"demoted,omitempty"`
+	Pruned          []Change  `json:"pruned,omitempty"`
+}
+
+// Change is a behavior that was demoted or pruned. To is the new
+// confidence of a demoted behavior; RetiredUntil is when a pruned one is
+// forgotten.
+type Change struct {
+	BehaviorID   string     `json:"behavior_id"`
+	Name         string     `json:"name,omitempty"`
+	Scope        string     `json:"scope,omitempty"`
+	LastUsed     time.Time  `json:"last_used"`
+	IdleDays     int        `json:"idle_days"`
+	From         float64    `json:"from"`
+	To           float64    `json:"to,omitempty"`
+	RetiredUntil *time.Time `json:"retired_until,omitempty"`
+}
+
+// Changes returns the number of behaviors demoted or pruned.
+func (r *Report) Changes() int {
+	return len(r.Demoted) + len(r.Pruned)
+}
+
+// Eligible reports whether a behavior may decay with disuse. Constraints
+// and authored, imported, or pack behaviors are deliberate and never decay.
+func Eligible(b models.Behavior) bool {
+	switch b.Provenance.SourceType {
+	case models.SourceTypeAuthored, models.SourceTypeImported, models.SourceTypePack:
+		return false
+	}
+	return b.Kind != models.BehaviorKindConstraint
+}
+
+// LastUsed returns when a behavior was last activated or confirmed, or
+// created if it has been neither.
+func LastUsed(b models.Behavior) time.Time {
+	last := b.Stats.CreatedAt
+	if last.IsZero() {
+		last = b.Provenance.CreatedAt
+	}
+	for _, t := range []*time.Time{b.Stats.LastActivated, b.Stats.LastConfirmed} {
+		if t != nil && t.After(last) {
+			last = *t
+		}
+	}
+	return last
+}
+
+// NodeLastUsed returns LastUsed for the behavior stored in node, counting
+// a restore from retirement as a use, so a restored behavior is not retired
+// again before it has been idle for PruneAfter since.
+func NodeLastUsed(node store.Node) time.Time {
+	last := LastUsed(models.NodeToBehavior(node))
+	if s, ok := node.Metadata["restored_at"].(string); ok {
+		if restored, err := time.Parse(time.RFC3339, s); err == nil && restored.After(last) {
+			last = restored
+		}
+	}
+	return last
+}
+
+// Run applies a decay pass to the behaviors in graphStore. Behaviors unused
+// for PruneAfter are retired; those unused for DemoteAfter lose
+// constants.StaleConfidenceDecay confidence, down to the reinforcement
+// floor. Behaviors from a read-only policy store are left alone.
+func Run(ctx context.Context, graphStore store.GraphStore, opts Options) (*Report, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	demoteAfter := opts.DemoteAfter
+	if demoteAfter == 0 {
+		demoteAfter = constants.DefaultStaleBehaviorDays * 24 * time.Hour
+	}
+	grace := opts.Grace
+	if grace == 0 {
+		grace = constants.DefaultRetirementGraceDays * 24 * time.Hour
+	}
+	floor := ranking.DefaultReinforcementConfig().Floor
+
+	report := &Report{
+		StartedAt:       now,
+		DryRun:          opts.DryRun,
+		DemoteAfterDays: days(demoteAfter),
+		PruneAfterDays:  days(opts.PruneAfter),
+	}
+
+	nodes, err := graphStore.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query behaviors: %w", err)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+	for _, node := range nodes {
+		if node.Origin.ReadOnly() {
+			continue
+		}
+		b := models.NodeToBehavior(node)
+		if !Eligible(b) {
+			continue
+		}
+		lastUsed := NodeLastUsed(node)
+		if lastUsed.IsZero() {
+			continue
+		}
+		idle := now.Sub(lastUsed)
+		change := Change{
+			BehaviorID: b.ID,
+			Name:       b.Name,
+			Scope:      string(node.Origin),
+			LastUsed:   lastUsed,
+			IdleDays:   days(idle),
+			From:       round2(b.Confidence),
+		}
+
+		switch {
+		case opts.PruneAfter > 0 && idle > opts.PruneAfter:
+			until := now.Add(grace)
+			change.RetiredUntil = &until
+			if !opts.DryRun {
+				reason := fmt.Sprintf("decay: not activated or confirmed for %d days", change.IdleDays)
+				retirement.Retire(&node, reason, grace, now)
+				if err := graphStore.UpdateNode(ctx, node); err != nil {
+					return report, fmt.Errorf("failed to retire behavior %s: %w", b.ID, err)
+				}
+			}
+			report.Pruned = append(report.Pruned, change)
+
+		case idle > demoteAfter && b.Confidence > floor:
+			change.To = round2(math.Max(floor, b.Confidence-constants.StaleConfidenceDecay))
+			if !opts.DryRun {
+				if err := setConfidence(ctx, graphStore, node, change.To); err != nil {
+					return report, fmt.Errorf("failed to demote behavior %s: %w", b.ID, err)
+				}
+			}
+			report.Demoted = append(report.Demoted, change)
+		}
+	}
+
+	if !opts.DryRun && report.Changes() > 0 {
+		if err := graphStore.Sync(ctx); err != nil {
+			return report, fmt.Errorf("failed to sync store: %w", err)
+		}
+	}
+	return report, nil
+}
+
+// setConfidence writes a new confidence, through UpdateConfidence when the
+// store supports it.
+func setConfidence(ctx context.Context, s store.GraphStore, node store.Node, conf float64) error {
+	if es, ok := s.(store.ExtendedGraphStore); ok {
+		return es.UpdateConfidence(ctx, node.ID, conf)
+	}
+	if node.Metadata == nil {
+		node.Metadata = make(map[string]interface{})
+	}
+	node.Metadata["confidence"] = conf
+	return s.UpdateNode(ctx, node)
+}
+
+func days(d time.Duration) int {
+	return int(d.Hours() / 24)
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
+
+// RecordReport appends report to floopDir.
+func RecordReport(floopDir string, report *Report) error {
+	f, err := os.OpenFile(filepath.Join(floopDir, ReportsFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open decay reports: %w", err)
+	}
+	defer f.Close()
+	if err := json.NewEncoder(f).Encode(report); err != nil {
+		return fmt.Errorf("failed to write decay report: %w", err)
+	}
+	return nil
+}
+
+// LastRun returns when the last non-dry-run decay pass recorded in floopDir
+// started, or the zero time if none has run.
+func LastRun(floopDir string) (time.Time, error) {
+	f, err := os.Open(filepath.Join(floopDir, ReportsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("failed to open decay reports: %w", err)
+	}
+	defer f.Close()
+
+	var last time.Time
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var r Report
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil || r.DryRun {
+			continue
+		}
+		if r.StartedAt.After(last) {
+			last = r.StartedAt
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return time.Time{}, fmt.Errorf("failed to read decay reports: %w", err)
+	}
+	return last, nil
+}
+
+// Due reports whether a decay pass should run now, given the last run
+// recorded in floopDir and the minimum interval between runs.
+func Due(floopDir string, interval time.Duration, now time.Time) (bool, error) {
+	last, err := LastRun(floopDir)
+	if err != nil {
+		return false, err
+	}
+	return last.IsZero() || now.Sub(last) >= interval, nil
+}
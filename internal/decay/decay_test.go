@@ -0,0 +1,236 @@
+package decay
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/retirement"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func decayTestNode(id string, kind models.BehaviorKind, source models.SourceType, confidence float64, stats map[string]interface{}) store.Node {
+	return store.Node{
+		ID:   id,
+		Kind: store.NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    id,
+			"kind":    string(kind),
+			"content": map[string]interface{}{"canonical": id + " canonical"},
+		},
+		Metadata: map[string]interface{}{
+			"confidence": confidence,
+			"stats":      stats,
+			"provenance": map[string]interface{}{"source_type": string(source)},
+		},
+	}
+}
+
+func newDecayTestStore(t *testing.T, now time.Time) store.GraphStore {
+	t.Helper()
+	daysAgo := func(n int) string { return now.AddDate(0, 0, -n).Format(time.RFC3339) }
+
+	s := store.NewInMemoryGraphStore()
+	nodes := []store.Node{
+		// Activated yesterday: untouched
+		decayTestNode("fresh", models.BehaviorKindDirective, models.SourceTypeLearned, 0.8,
+			map[string]interface{}{"created_at": daysAgo(300), "last_activated": daysAgo(1)}),
+		// Activated long ago but confirmed last week: untouched
+		decayTestNode("confirmed", models.BehaviorKindDirective, models.SourceTypeLearned, 0.8,
+			map[string]interface{}{"created_at": daysAgo(300), "last_activated": daysAgo(200), "last_confirmed": daysAgo(7)}),
+		// Unused for 100 days: demoted
+		decayTestNode("stale", models.BehaviorKindPreference, models.SourceTypeLearned, 0.6,
+			map[string]interface{}{"created_at": daysAgo(100)}),
+		// Unused for 100 days but already at the floor: untouched
+		decayTestNode("floored", models.BehaviorKindPreference, models.SourceTypeLearned, 0.3,
+			map[string]interface{}{"created_at": daysAgo(100)}),
+		// Unused for 200 days: pruned
+		decayTestNode("abandoned", models.BehaviorKindDirective, models.SourceTypeLearned, 0.7,
+			map[string]interface{}{"created_at": daysAgo(400), "last_activated": daysAgo(200)}),
+		// Never decay
+		decayTestNode("rule", models.BehaviorKindConstraint, models.SourceTypeLearned, 0.8,
+			map[string]interface{}{"created_at": daysAgo(400)}),
+		decayTestNode("authored", models.BehaviorKindDirective, models.SourceTypeAuthored, 0.8,
+			map[string]interface{}{"created_at": daysAgo(400)}),
+	}
+	for _, n := range nodes {
+		if _, err := s.AddNode(context.Background(), n); err != nil {
+			t.Fatalf("AddNode(%s) error = %v", n.ID, err)
+		}
+	}
+	return s
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 6, 1, 3, 0, 0, 0, time.UTC)
+	opts := Options{DemoteAfter: 90 * 24 * time.Hour, PruneAfter: 180 * 24 * time.Hour, Now: now}
+
+	t.Run("demotes and prunes unused behaviors", func(t *testing.T) {
+		s := newDecayTestStore(t, now)
+		report, err := Run(ctx, s, opts)
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		if len(report.Demoted) != 1 || report.Demoted[0].BehaviorID != "stale" {
+			t.Fatalf("Demoted = %+v, want only stale", report.Demoted)
+		}
+		if d := report.Demoted[0]; d.From != 0.6 || d.To != 0.55 || d.IdleDays != 100 {
+			t.Errorf("stale change = %+v, want 0.60 -> 0.55 after 100 days", d)
+		}
+		if len(report.Pruned) != 1 || report.Pruned[0].BehaviorID != "abandoned" {
+			t.Fatalf("Pruned = %+v, want only abandoned", report.Pruned)
+		}
+		if until := report.Pruned[0].RetiredUntil; until == nil || !until.Equal(now.AddDate(0, 0, 14)) {
+			t.Errorf("RetiredUntil = %v, want %v", until, now.AddDate(0, 0, 14))
+		}
+
+		stale, _ := s.GetNode(ctx, "stale")
+		if got := models.NodeToBehavior(*stale).Confidence; got != 0.55 {
+			t.Errorf("stale confidence = %v, want 0.55", got)
+		}
+		abandoned, _ := s.GetNode(ctx, "abandoned")
+		if abandoned.Kind != store.NodeKindRetired {
+			t.Errorf("abandoned kind = %s, want %s", abandoned.Kind, store.NodeKindRetired)
+		}
+		if reason, _ := abandoned.Metadata["retire_reason"].(string); reason != "decay: not activated or confirmed for 200 days" {
+			t.Errorf("retire_reason = %q", reason)
+		}
+		for _, id := range []string{"fresh", "confirmed", "floored", "rule", "authored"} {
+			node, _ := s.GetNode(ctx, id)
+			if node.Kind != store.NodeKindBehavior || node.Metadata["confidence"] != decayTestConfidence(id) {
+				t.Errorf("%s changed: kind %s, confidence %v", id, node.Kind, node.Metadata["confidence"])
+			}
+		}
+	})
+
+	t.Run("restored behavior is not retired again", func(t *testing.T) {
+		s := newDecayTestStore(t, now)
+		if _, err := Run(ctx, s, opts); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		// A recurring correction on its topic brings abandoned back
+		restoredAt := now.AddDate(0, 0, 3)
+		candidate := &models.Behavior{Name: "abandoned", Content: models.BehaviorContent{Canonical: "abandoned canonical"}}
+		notices, err := retirement.RestoreRecurring(ctx, s, candidate, restoredAt)
+		if err != nil || len(notices) != 1 {
+			t.Fatalf("RestoreRecurring() = %+v, %v; want abandoned restored", notices, err)
+		}
+
+		report, err := Run(ctx, s, Options{DemoteAfter: opts.DemoteAfter, PruneAfter: opts.PruneAfter, Now: restoredAt.Add(24 * time.Hour)})
+		if err != nil {
+			t.Fatalf("second Run() error = %v", err)
+		}
+		for _, c := range append(report.Pruned, report.Demoted...) {
+			if c.BehaviorID == "abandoned" {
+				t.Errorf("restored behavior decayed again: %+v", c)
+			}
+		}
+		if abandoned, _ := s.GetNode(ctx, "abandoned"); abandoned.Kind != store.NodeKindBehavior {
+			t.Errorf("abandoned kind = %s after the next pass, want %s", abandoned.Kind, store.NodeKindBehavior)
+		}
+	})
+
+	t.Run("dry run changes nothing", func(t *testing.T) {
+		s := newDecayTestStore(t, now)
+		dry := opts
+		dry.DryRun = true
+		report, err := Run(ctx, s, dry)
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if !report.DryRun || report.Changes() != 2 {
+			t.Errorf("report = %+v, want a dry run with 2 changes", report)
+		}
+		stale, _ := s.GetNode(ctx, "stale")
+		abandoned, _ := s.GetNode(ctx, "abandoned")
+		if stale.Metadata["confidence"] != 0.6 || abandoned.Kind != store.NodeKindBehavior {
+			t.Error("dry run modified the store")
+		}
+	})
+
+	t.Run("zero prune after only demotes", func(t *testing.T) {
+		s := newDecayTestStore(t, now)
+		report, err := Run(ctx, s, Options{Now: now})
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if len(report.Pruned) != 0 || len(report.Demoted) != 2 || report.DemoteAfterDays != 90 {
+			t.Errorf("report = %+v, want abandoned and stale demoted after the default 90 days", report)
+		}
+	})
+}
+
+func decayTestConfidence(id string) float64 {
+	if id == "floored" {
+		return 0.3
+	}
+	return 0.8
+}
+
+func TestLastUsed(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	activated := created.AddDate(0, 1, 0)
+	confirmed := created.AddDate(0, 2, 0)
+
+	tests := []struct {
+		name  string
+		stats models.BehaviorStats
+		want  time.Time
+	}{
+		{"created only", models.BehaviorStats{CreatedAt: created}, created},
+		{"activated", models.BehaviorStats{CreatedAt: created, LastActivated: &activated}, activated},
+		{"confirmed after activation", models.BehaviorStats{CreatedAt: created, LastActivated: &activated, LastConfirmed: &confirmed}, confirmed},
+		{"activated after confirmation", models.BehaviorStats{CreatedAt: created, LastActivated: &confirmed, LastConfirmed: &activated}, confirmed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LastUsed(models.Behavior{Stats: tt.stats}); !got.Equal(tt.want) {
+				t.Errorf("LastUsed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	b := models.Behavior{Provenance: models.Provenance{CreatedAt: created}}
+	if got := LastUsed(b); !got.Equal(created) {
+		t.Errorf("LastUsed() without stats = %v, want provenance created_at %v", got, created)
+	}
+}
+
+func TestRecordReportAndDue(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 6, 1, 3, 0, 0, 0, time.UTC)
+
+	if due, err := Due(dir, 24*time.Hour, now); err != nil || !due {
+		t.Fatalf("Due() with no reports = %v, %v; want true", due, err)
+	}
+
+	if err := RecordReport(dir, &Report{StartedAt: now}); err != nil {
+		t.Fatalf("RecordReport() error = %v", err)
+	}
+	if err := RecordReport(dir, &Report{StartedAt: now.Add(12 * time.Hour), DryRun: true}); err != nil {
+		t.Fatalf("RecordReport() error = %v", err)
+	}
+
+	if last, err := LastRun(dir); err != nil || !last.Equal(now) {
+		t.Errorf("LastRun() = %v, %v; want %v (dry runs ignored)", last, err, now)
+	}
+	if due, _ := Due(dir, 24*time.Hour, now.Add(23*time.Hour)); due {
+		t.Error("Due() within the interval = true")
+	}
+	if due, _ := Due(dir, 24*time.Hour, now.Add(24*time.Hour)); !due {
+		t.Error("Due() after the interval = false")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ReportsFile), []byte("not json\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if last, err := LastRun(dir); err != nil || !last.IsZero() {
+		t.Errorf("LastRun() with a corrupt line = %v, %v; want zero", last, err)
+	}
+}
@@ -0,0 +1,160 @@
+package dedup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/vecmath"
+	"github.com/nvandessel/floop/internal/vectorsearch"
+)
+
+// Similarity backends selectable with deduplication.similarity.
+const (
+	// SimilarityAuto uses ComputeSimilarity's embedding → LLM → Jaccard chain.
+	SimilarityAuto = "auto"
+
+	// SimilarityEmbedding compares cached behavior embeddings by cosine similarity.
+	SimilarityEmbedding = "embedding"
+
+	// SimilarityJaccard compares behaviors by weighted word overlap only.
+	SimilarityJaccard = "jaccard"
+)
+
+// Similarity is a pluggable backend for comparing two behaviors. When set in
+// SimilarityConfig.Backend, ComputeSimilarity uses it instead of the default
+// chain, falling back to the chain if Compare returns an error.
+type Similarity interface {
+	Compare(ctx context.Context, a, b *models.Behavior) (SimilarityResult, error)
+}
+
+// NewSimilarity returns the backend named by method. It returns nil for
+// "auto" (or ""), leaving the default chain in place. The embedding backend
+// requires a client that supports embeddings, and caches vectors in any of
+// stores that implement store.EmbeddingStore.
+func NewSimilarity(method string, client llm.Client, model string, stores ...store.GraphStore) (Similarity, error) {
+	switch method {
+	case "", SimilarityAuto:
+		return nil, nil
+	case SimilarityJaccard:
+		return JaccardSimilarity{}, nil
+	case SimilarityEmbedding:
+		ec, ok := client.(llm.EmbeddingComparer)
+		if !ok || client == nil || !client.Available() {
+			return nil, fmt.Errorf("embedding similarity needs an LLM provider that supports embeddings (local, or openai/ollama with llm.embedding_model)")
+		}
+		var caches []store.EmbeddingStore
+		for _, s := range stores {
+			if es, ok := s.(store.EmbeddingStore); ok {
+				caches = append(caches, es)
+			}
+		}
+		return NewEmbeddingSimilarity(ec, model, caches...), nil
+	default:
+		return nil, fmt.Errorf("unknown similarity backend: %s (valid: auto, embedding, jaccard)", method)
+	}
+}
+
+// JaccardSimilarity scores behaviors by weighted word overlap of their
+// when-conditions, content, and tags.
+type JaccardSimilarity struct{}
+
+// Compare implements Similarity.
+func (JaccardSimilarity) Compare(_ context.Context, a, b *models.Behavior) (SimilarityResult, error) {
+	return SimilarityResult{Score: jaccardScore(a, b), Method: "jaccard"}, nil
+}
+
+// EmbeddingSimilarity scores behaviors by the cosine similarity of their
+// canonical content embeddings. Vectors are read from the embedding stores
+// when they were produced by the same model, and otherwise computed once and
+// written back, so repeated runs don't re-embed unchanged behaviors.
+type EmbeddingSimilarity struct {
+	ec     llm.EmbeddingComparer
+	model  string
+	caches []store.EmbeddingStore
+
+	mu      sync.Mutex
+	loaded  bool
+	vectors map[string][]float32
+}
+
+// NewEmbeddingSimilarity creates an EmbeddingSimilarity embedding with ec.
+// model is recorded with stored vectors; vectors from other models are
+// ignored.
+func NewEmbeddingSimilarity(ec llm.EmbeddingComparer, model string, caches ...store.EmbeddingStore) *EmbeddingSimilarity {
+	return &EmbeddingSimilarity{
+		ec:      ec,
+		model:   model,
+		caches:  caches,
+		vectors: make(map[string][]float32),
+	}
+}
+
+// Compare implements Similarity.
+func (s *EmbeddingSimilarity) Compare(ctx context.Context, a, b *models.Behavior) (SimilarityResult, error) {
+	vecA, err := s.vector(ctx, a)
+	if err != nil {
+		return SimilarityResult{}, err
+	}
+	vecB, err := s.vector(ctx, b)
+	if err != nil {
+		return SimilarityResult{}, err
+	}
+	return SimilarityResult{Score: vecmath.CosineSimilarity(vecA, vecB), Method: "embedding"}, nil
+}
+
+// vector returns b's embedding, from the cache if possible.
+func (s *EmbeddingSimilarity) vector(ctx context.Context, b *models.Behavior) ([]float32, error) {
+	s.mu.Lock()
+	if !s.loaded {
+		s.load(ctx)
+	}
+	vec, ok := s.vectors[b.ID]
+	s.mu.Unlock()
+	if ok && b.ID != "" {
+		return vec, nil
+	}
+
+	vec, err := s.ec.Embed(ctx, vectorsearch.DocumentPrefix+b.Content.Canonical)
+	if err != nil {
+		return nil, fmt.Errorf("embed behavior %s: %w", b.ID, err)
+	}
+	if b.ID == "" {
+		return vec, nil
+	}
+
+	s.mu.Lock()
+	s.vectors[b.ID] = vec
+	s.mu.Unlock()
+
+	// Best-effort: the behavior may not be in a given store (or any yet)
+	for _, es := range s.caches {
+		if es.StoreEmbedding(ctx, b.ID, vec, s.model) == nil {
+			break
+		}
+	}
+	return vec, nil
+}
+
+// load reads the stored vectors produced by this backend's model.
+// Caller must hold s.mu.
+func (s *EmbeddingSimilarity) load(ctx context.Context) {
+	s.loaded = true
+	for _, es := range s.caches {
+		embeddings, err := es.GetAllEmbeddings(ctx)
+		if err != nil {
+			continue
+		}
+		for _, e := range embeddings {
+			if e.Model != s.model {
+				continue
+			}
+			if _, ok := s.vectors[e.BehaviorID]; !ok {
+				s.vectors[e.BehaviorID] = e.Embedding
+			}
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package dedup
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/vectorsearch"
+)
+
+// textEmbedder embeds texts from a fixed table and counts calls.
+type textEmbedder struct {
+	vectors map[string][]float32
+	calls   int
+}
+
+func (e *textEmbedder) Embed(_ context.Context, text string) ([]float32, error) {
+	e.calls++
+	vec, ok := e.vectors[text]
+	if !ok {
+		return nil, errors.New("no vector for " + text)
+	}
+	return vec, nil
+}
+
+func (e *textEmbedder) CompareEmbeddings(ctx context.Context, a, b string) (float64, error) {
+	return 0, errors.New("not used")
+}
+
+func TestEmbeddingSimilarity(t *testing.T) {
+	ctx := context.Background()
+	a := &models.Behavior{ID: "a", Content: models.BehaviorContent{Canonical: "use pathlib"}}
+	b := &models.Behavior{ID: "b", Content: models.BehaviorContent{Canonical: "prefer pathlib"}}
+	s := store.NewInMemoryGraphStore()
+	for _, beh := range []*models.Behavior{a, b} {
+		if _, err := s.AddNode(ctx, models.BehaviorToNode(beh)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// b already has a vector from this model; a has one from another model
+	if err := s.StoreEmbedding(ctx, "b", []float32{1, 1}, "nomic"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.StoreEmbedding(ctx, "a", []float32{0, 1}, "other"); err != nil {
+		t.Fatal(err)
+	}
+	ec := &textEmbedder{vectors: map[string][]float32{
+		vectorsearch.DocumentPrefix + "use pathlib": {1, 0},
+	}}
+
+	sim := NewEmbeddingSimilarity(ec, "nomic", s)
+	result, err := sim.Compare(ctx, a, b)
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	if result.Method != "embedding" || math.Abs(result.Score-math.Sqrt2/2) > 1e-6 {
+		t.Errorf("Compare() = %+v, want embedding cosine of 0.707", result)
+	}
+	if ec.calls != 1 {
+		t.Errorf("Embed called %d times, want 1 (b is cached)", ec.calls)
+	}
+
+	// The new vector is written back and reused by a fresh backend
+	if _, err := NewEmbeddingSimilarity(ec, "nomic", s).Compare(ctx, a, b); err != nil {
+		t.Fatal(err)
+	}
+	if ec.calls != 1 {
+		t.Errorf("Embed called %d times, want a's vector read from the store", ec.calls)
+	}
+}
+
+func TestComputeSimilarity_BackendFallsBack(t *testing.T) {
+	a := &models.Behavior{ID: "a", Content: models.BehaviorContent{Canonical: "use pathlib"}}
+	b := &models.Behavior{ID: "b", Content: models.BehaviorContent{Canonical: "use pathlib"}}
+	failing := NewEmbeddingSimilarity(&textEmbedder{}, "nomic")
+
+	result := ComputeSimilarity(a, b, SimilarityConfig{Backend: failing})
+	if result.Method != "jaccard" {
+		t.Errorf("method = %q, want jaccard fallback", result.Method)
+	}
+}
+
+func TestNewSimilarity(t *testing.T) {
+	embeddings := llm.NewMockClient()
+	tests := []struct {
+		name    string
+		method  string
+		client  llm.Client
+		want    string
+		wantErr bool
+	}{
+		{"auto", "auto", embeddings, "", false},
+		{"empty", "", nil, "", false},
+		{"jaccard", "jaccard", nil, "jaccard", false},
+		{"embedding", "embedding", embeddings, "embedding", false},
+		{"embedding without client", "embedding", nil, "", true},
+		{"embedding unavailable", "embedding", llm.NewMockClient().WithAvailable(false), "", true},
+		{"unknown", "cosine", embeddings, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim, err := NewSimilarity(tt.method, tt.client, "nomic", store.NewInMemoryGraphStore())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSimilarity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got := ""
+			switch sim.(type) {
+			case JaccardSimilarity:
+				got = "jaccard"
+			case *EmbeddingSimilarity:
+				got = "embedding"
+			}
+			if got != tt.want {
+				t.Errorf("NewSimilarity() = %T, want %s", sim, tt.want)
+			}
+		})
+	}
+}
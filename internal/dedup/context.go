@@ -0,0 +1,177 @@
+package dedup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/similarity"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// ContextsCompatible reports whether two behaviors can apply in the same
+// context. Behaviors with matching content but incompatible when-conditions
+// (language "go" vs "python") are context variants, not duplicates, and are
+// never merged.
+func ContextsCompatible(a, b *models.Behavior) bool {
+	return similarity.WhenCompatible(a.When, b.When)
+}
+
+// attachSpecializes fills Specializes on each behavior from the outbound
+// specializes edges in every given store.
+func attachSpecializes(ctx context.Context, behaviors []models.Behavior, stores ...store.GraphStore) error {
+	for i := range behaviors {
+		b := &behaviors[i]
+		for _, s := range stores {
+			edges, err := s.GetEdges(ctx, b.ID, store.DirectionOutbound, store.EdgeKindSpecializes)
+			if err != nil {
+				return fmt.Errorf("failed to load specializes edges for %s: %w", b.ID, err)
+			}
+			for _, e := range edges {
+				if !containsString(b.Specializes, e.Target) {
+					b.Specializes = append(b.Specializes, e.Target)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// SpecializesRelated reports whether a and b are already linked by the
+// specializes mechanism: one specializes the other, or both specialize the
+// same parent. Such behaviors are deliberately separate and are not
+// deduplicated. Both must have Specializes populated.
+func SpecializesRelated(a, b *models.Behavior) bool {
+	if containsString(a.Specializes, b.ID) || containsString(b.Specializes, a.ID) {
+		return true
+	}
+	for _, parent := range a.Specializes {
+		if containsString(b.Specializes, parent) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// LinkContextVariants creates a generalized parent for behaviors that share
+// content but apply in incompatible contexts, and links each variant to it
+// with a specializes edge. The parent keeps only the when-conditions all
+// variants share; activation prefers a matching variant over the parent.
+func LinkContextVariants(ctx context.Context, s store.GraphStore, variants []*models.Behavior) (*models.Behavior, error) {
+	if len(variants) < 2 {
+		return nil, fmt.Errorf("at least two context variants are required")
+	}
+
+	parent := sharedParent(variants)
+	node := models.BehaviorToNode(parent)
+	var err error
+	if sw, ok := s.(scopedWriter); ok {
+		_, err = sw.AddNodeToScope(ctx, node, models.ClassifyScope(parent))
+	} else {
+		_, err = s.AddNode(ctx, node)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to add shared parent: %w", err)
+	}
+	for _, v := range variants {
+		edge := store.Edge{
+			Source:    v.ID,
+			Target:    parent.ID,
+			Kind:      store.EdgeKindSpecializes,
+			Weight:    1.0,
+			CreatedAt: parent.Provenance.CreatedAt,
+			Metadata: map[string]interface{}{
+				"reason": "context-variant",
+			},
+		}
+		if err := s.AddEdge(ctx, edge); err != nil {
+			return nil, fmt.Errorf("failed to link %s: %w", v.ID, err)
+		}
+	}
+	return parent, nil
+}
+
+// sharedParent builds the generalized parent for a group of context variants.
+// Its ID is derived from the variant IDs.
+func sharedParent(variants []*models.Behavior) *models.Behavior {
+	ids := make([]string, len(variants))
+	var confidence float64
+	for i, v := range variants {
+		ids[i] = v.ID
+		confidence += v.Confidence
+	}
+	sort.Strings(ids)
+	hash := sha256.Sum256([]byte(strings.Join(ids, ",")))
+	digest := hex.EncodeToString(hash[:])
+
+	primary := variants[0]
+	now := time.Now()
+	return &models.Behavior{
+		ID:   "behavior-" + digest[:12],
+		Name: "general-" + primary.Name,
+		Kind: primary.Kind,
+		When: sharedWhen(variants),
+		Content: models.BehaviorContent{
+			Canonical: primary.Content.Canonical,
+			Summary:   primary.Content.Summary,
+			Tags:      sharedTags(variants),
+		},
+		Provenance: models.Provenance{SourceType: models.SourceTypeGeneralized, CreatedAt: now},
+		Confidence: confidence / float64(len(variants)),
+		Priority:   primary.Priority,
+		Stats:      models.BehaviorStats{CreatedAt: now, UpdatedAt: now},
+	}
+}
+
+// sharedWhen returns the when-conditions every variant has with an equal
+// value, or nil if there are none.
+func sharedWhen(variants []*models.Behavior) map[string]interface{} {
+	shared := make(map[string]interface{})
+	for key, value := range variants[0].When {
+		common := true
+		for _, v := range variants[1:] {
+			if other, ok := v.When[key]; !ok || !similarity.ValuesEqual(value, other) {
+				common = false
+				break
+			}
+		}
+		if common {
+			shared[key] = value
+		}
+	}
+	if len(shared) == 0 {
+		return nil
+	}
+	return shared
+}
+
+// sharedTags returns the tags present on every variant.
+func sharedTags(variants []*models.Behavior) []string {
+	counts := make(map[string]int)
+	for _, v := range variants {
+		for _, t := range v.Content.Tags {
+			counts[t]++
+		}
+	}
+	var tags []string
+	for _, t := range variants[0].Content.Tags {
+		if counts[t] == len(variants) {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,158 @@
+package dedup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/progress"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// contextVariants returns two behaviors with identical content scoped to
+// different languages.
+func contextVariants() []models.Behavior {
+	return []models.Behavior{
+		{ID: "go-ctx", Name: "ctx-first", Kind: models.BehaviorKindDirective, Confidence: 0.8,
+			When:    map[string]interface{}{"language": "go", "task": "coding"},
+			Content: models.BehaviorContent{Canonical: "pass the request context as the first argument", Tags: []string{"context", "go"}}},
+		{ID: "py-ctx", Name: "ctx-first", Kind: models.BehaviorKindDirective, Confidence: 0.6,
+			When:    map[string]interface{}{"language": "python", "task": "coding"},
+			Content: models.BehaviorContent{Canonical: "pass the request context as the first argument", Tags: []string{"context", "python"}}},
+	}
+}
+
+func TestFindDuplicates_SkipsIncompatibleContexts(t *testing.T) {
+	behaviors := contextVariants()
+	s := createTestStore(behaviors[1:])
+	d := NewStoreDeduplicator(s, NewBehaviorMerger(MergerConfig{}), DeduplicatorConfig{SimilarityThreshold: 0.5})
+
+	matches, err := d.FindDuplicates(context.Background(), &behaviors[0])
+	if err != nil {
+		t.Fatalf("FindDuplicates() error = %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("FindDuplicates() = %+v, want no matches across languages", matches)
+	}
+}
+
+func TestDeduplicateStore_LinksContextVariants(t *testing.T) {
+	ctx := context.Background()
+	s := createTestStore(contextVariants())
+	d := NewStoreDeduplicator(s, NewBehaviorMerger(MergerConfig{}), DeduplicatorConfig{SimilarityThreshold: 0.5, AutoMerge: true})
+
+	report, err := d.DeduplicateStore(ctx, s)
+	if err != nil {
+		t.Fatalf("DeduplicateStore() error = %v", err)
+	}
+	if report.MergesPerformed != 0 || report.DuplicatesFound != 0 {
+		t.Errorf("report = %+v, want no merges", report)
+	}
+	if report.ContextVariantsFound != 1 || len(report.SharedParents) != 1 {
+		t.Fatalf("report = %+v, want one context variant linked under one parent", report)
+	}
+
+	parent := report.SharedParents[0]
+	if want := map[string]interface{}{"task": "coding"}; len(parent.When) != 1 || parent.When["task"] != want["task"] {
+		t.Errorf("parent When = %v, want %v", parent.When, want)
+	}
+	if len(parent.Content.Tags) != 1 || parent.Content.Tags[0] != "context" {
+		t.Errorf("parent Tags = %v, want [context]", parent.Content.Tags)
+	}
+	if parent.Provenance.SourceType != models.SourceTypeGeneralized {
+		t.Errorf("parent SourceType = %s, want %s", parent.Provenance.SourceType, models.SourceTypeGeneralized)
+	}
+	for _, id := range []string{"go-ctx", "py-ctx"} {
+		edges, err := s.GetEdges(ctx, id, store.DirectionOutbound, store.EdgeKindSpecializes)
+		if err != nil {
+			t.Fatalf("GetEdges() error = %v", err)
+		}
+		if len(edges) != 1 || edges[0].Target != parent.ID {
+			t.Errorf("%s specializes edges = %+v, want one to %s", id, edges, parent.ID)
+		}
+	}
+	for _, id := range []string{"go-ctx", "py-ctx", parent.ID} {
+		if node, _ := s.GetNode(ctx, id); node == nil {
+			t.Errorf("behavior %s should exist", id)
+		}
+	}
+
+	// A second run leaves the linked variants and their parent alone
+	again, err := d.DeduplicateStore(ctx, s)
+	if err != nil {
+		t.Fatalf("second DeduplicateStore() error = %v", err)
+	}
+	if again.MergesPerformed != 0 || again.ContextVariantsFound != 0 || len(again.SharedParents) != 0 {
+		t.Errorf("second report = %+v, want no changes", again)
+	}
+}
+
+func TestDeduplicateStore_DryRunReportsContextVariants(t *testing.T) {
+	ctx := context.Background()
+	s := createTestStore(contextVariants())
+	d := NewStoreDeduplicator(s, NewBehaviorMerger(MergerConfig{}), DeduplicatorConfig{SimilarityThreshold: 0.5})
+
+	report, err := d.DeduplicateStore(ctx, s)
+	if err != nil {
+		t.Fatalf("DeduplicateStore() error = %v", err)
+	}
+	if report.ContextVariantsFound != 1 || len(report.SharedParents) != 0 {
+		t.Errorf("report = %+v, want one variant and no parent without AutoMerge", report)
+	}
+}
+
+func TestSpecializesRelated(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b models.Behavior
+		want bool
+	}{
+		{"unrelated", models.Behavior{ID: "a"}, models.Behavior{ID: "b"}, false},
+		{"child of", models.Behavior{ID: "a", Specializes: []string{"b"}}, models.Behavior{ID: "b"}, true},
+		{"parent of", models.Behavior{ID: "a"}, models.Behavior{ID: "b", Specializes: []string{"a"}}, true},
+		{"siblings", models.Behavior{ID: "a", Specializes: []string{"p"}}, models.Behavior{ID: "b", Specializes: []string{"p"}}, true},
+		{"different parents", models.Behavior{ID: "a", Specializes: []string{"p"}}, models.Behavior{ID: "b", Specializes: []string{"q"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SpecializesRelated(&tt.a, &tt.b); got != tt.want {
+				t.Errorf("SpecializesRelated() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeduplicateAcrossStores_SkipsIncompatibleContexts(t *testing.T) {
+	behaviors := contextVariants()
+	local := createTestStore(behaviors[:1])
+	global := createTestStore(behaviors[1:])
+	d := NewCrossStoreDeduplicatorWithConfig(local, global, NewBehaviorMerger(MergerConfig{}), DeduplicatorConfig{SimilarityThreshold: 0.5})
+
+	results, err := d.DeduplicateAcrossStores(context.Background())
+	if err != nil {
+		t.Fatalf("DeduplicateAcrossStores() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Action != "none" {
+		t.Errorf("results = %+v, want no merge across languages", results)
+	}
+}
+
+func TestDeduplicateAcrossStores_Cancelled(t *testing.T) {
+	behaviors := contextVariants()
+	var events bytes.Buffer
+	cfg := DeduplicatorConfig{SimilarityThreshold: 0.5, Progress: progress.New(&events, progress.FormatJSON)}
+	d := NewCrossStoreDeduplicatorWithConfig(createTestStore(behaviors[:1]), createTestStore(behaviors[1:]), NewBehaviorMerger(MergerConfig{}), cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := d.DeduplicateAcrossStores(ctx)
+	if !errors.Is(err, context.Canceled) || len(results) != 0 {
+		t.Errorf("DeduplicateAcrossStores() = %+v, %v, want no results and context.Canceled", results, err)
+	}
+	if !strings.Contains(events.String(), `"done":true`) {
+		t.Errorf("progress events = %q, want the operation finished", events.String())
+	}
+}
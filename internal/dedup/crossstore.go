@@ -96,7 +96,12 @@ func NewCrossStoreDeduplicatorWithLLM(localStore, globalStore store.GraphStore,
 //   - Same ID in both stores: Local wins (skip, no action needed)
 //   - Semantic duplicates with different IDs: Use merger to merge, update edges
 //     to point to survivor
+//   - Behaviors with incompatible when-conditions, or already linked by
+//     specializes edges, are never merged
 //   - Compare behaviors from local store against global store
+//
+// Once ctx is cancelled, no further local behavior is started: the results
+// so far are returned with ctx's error.
 func (d *CrossStoreDeduplicator) DeduplicateAcrossStores(ctx context.Context) ([]DeduplicationResult, error) {
 	// Get all behaviors from the local store
 	localBehaviors, err := d.getBehaviorsFromStore(ctx, d.localStore)
@@ -116,13 +121,28 @@ func (d *CrossStoreDeduplicator) DeduplicateAcrossStores(ctx context.Context) ([
 		globalByID[globalBehaviors[i].ID] = &globalBehaviors[i]
 	}
 
+	// Cross-store specializes edges live in the global store
+	if err := attachSpecializes(ctx, localBehaviors, d.localStore, d.globalStore); err != nil {
+		return nil, err
+	}
+	if err := attachSpecializes(ctx, globalBehaviors, d.globalStore); err != nil {
+		return nil, err
+	}
+
 	results := make([]DeduplicationResult, 0, len(localBehaviors))
 
-	// Process each local behavior
+	// Process each local behavior; a merge in progress is not cut short
+	work := context.WithoutCancel(ctx)
+	d.config.Progress.Start("deduplicate", len(localBehaviors))
+	defer d.config.Progress.Finish()
 	for i := range localBehaviors {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
 		local := &localBehaviors[i]
-		result := d.deduplicateBehavior(ctx, local, globalBehaviors, globalByID)
+		result := d.deduplicateBehavior(work, local, globalBehaviors, globalByID)
 		results = append(results, result)
+		d.config.Progress.Step(local.ID)
 	}
 
 	return results, nil
@@ -159,6 +179,11 @@ func (d *CrossStoreDeduplicator) deduplicateBehavior(
 			continue
 		}
 
+		// Context variants and behaviors linked by specializes are not duplicates
+		if !ContextsCompatible(local, global) || SpecializesRelated(local, global) {
+			continue
+		}
+
 		similarity := d.computeSimilarity(local, global)
 		if similarity > bestSimilarity {
 			bestSimilarity = similarity
@@ -216,6 +241,7 @@ func (d *CrossStoreDeduplicator) computeSimilarity(a, b *models.Behavior) float6
 		UseLLM:              d.config.UseLLM,
 		LLMClient:           d.llmClient,
 		SimilarityThreshold: d.config.SimilarityThreshold,
+		Backend:             d.config.Similarity,
 	})
 	return result.Score
 }
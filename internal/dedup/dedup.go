@@ -7,6 +7,7 @@ import (
 
 	"github.com/nvandessel/floop/internal/constants"
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/progress"
 	"github.com/nvandessel/floop/internal/store"
 )
 
@@ -45,6 +46,30 @@ type DeduplicationReport struct {
 	// DeletedIDs contains the IDs of behaviors removed during merging.
 	DeletedIDs []string `json:"deleted_ids,omitempty" yaml:"deleted_ids,omitempty"`
 
+	// MergedFrom maps the ID of each merged behavior to the IDs of the
+	// duplicates merged into it.
+	MergedFrom map[string][]string `json:"merged_from,omitempty" yaml:"merged_from,omitempty"`
+
+	// ContextVariantsFound is the number of similar pairs left unmerged
+	// because their when-conditions are incompatible (see ContextsCompatible).
+	ContextVariantsFound int `json:"context_variants_found" yaml:"context_variants_found"`
+
+	// SharedParents contains the generalized parents created to link context
+	// variants through specializes edges.
+	SharedParents []*models.Behavior `json:"shared_parents,omitempty" yaml:"shared_parents,omitempty"`
+
+	// BlockedMerges explains each group of duplicates left unmerged because
+	// their when-conditions cannot be combined (see MergeWhen).
+	BlockedMerges []string `json:"blocked_merges,omitempty" yaml:"blocked_merges,omitempty"`
+
+	// Differential reports that only behaviors new or changed since the
+	// last run were compared (see DeduplicatorConfig.Full).
+	Differential bool `json:"differential" yaml:"differential"`
+
+	// ComparedBehaviors is the number of behaviors compared against the
+	// rest of the store: all of them on a full pass.
+	ComparedBehaviors int `json:"compared_behaviors" yaml:"compared_behaviors"`
+
 	// Errors contains any errors encountered during processing.
 	Errors []string `json:"errors,omitempty" yaml:"errors,omitempty"`
 }
@@ -72,6 +97,19 @@ type DeduplicatorConfig struct {
 	// MaxBatchSize limits the number of behaviors to process at once.
 	// Use 0 for no limit.
 	MaxBatchSize int `json:"max_batch_size,omitempty" yaml:"max_batch_size,omitempty"`
+
+	// Full makes DeduplicateStore compare every pair. Otherwise, when the
+	// store implements store.StateStore, a run that merges records a
+	// watermark and the next run only compares behaviors that are new or
+	// changed since, against the whole store.
+	Full bool `json:"full,omitempty" yaml:"full,omitempty"`
+
+	// Similarity overrides how behaviors are compared (see NewSimilarity).
+	// When nil, the embedding → LLM → Jaccard chain is used.
+	Similarity Similarity `json:"-" yaml:"-"`
+
+	// Progress, when set, is told about each behavior as it is compared.
+	Progress *progress.Reporter `json:"-" yaml:"-"`
 }
 
 // DefaultConfig returns a DeduplicatorConfig with sensible defaults.
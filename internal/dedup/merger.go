@@ -72,21 +72,41 @@ func (m *BehaviorMerger) Merge(ctx context.Context, behaviors []*models.Behavior
 		ids[i] = b.ID
 	}
 
+	// Conditions that cannot be combined block the merge outright
+	when, conflicts, err := mergeWhenConditions(behaviors)
+	if err != nil {
+		if m.decisions != nil {
+			m.decisions.Log(map[string]any{
+				"event":        "merge_decision",
+				"strategy":     "blocked",
+				"behavior_ids": ids,
+				"reason":       err.Error(),
+			})
+		}
+		return nil, err
+	}
+	conflictKeys := make([]string, len(conflicts))
+	for i, c := range conflicts {
+		conflictKeys[i] = c.String()
+	}
+
 	// Try LLM-assisted merge if available
 	llmAttempted := false
 	if m.shouldUseLLM() {
 		llmAttempted = true
 		result, err := m.llmMerge(ctx, behaviors)
 		if err == nil {
+			result.When = when
 			if m.logger != nil {
 				m.logger.Debug("merge completed", "strategy", "llm", "behavior_count", len(behaviors))
 			}
 			if m.decisions != nil {
 				m.decisions.Log(map[string]any{
-					"event":         "merge_decision",
-					"strategy":      "llm",
-					"behavior_ids":  ids,
-					"llm_available": true,
+					"event":          "merge_decision",
+					"strategy":       "llm",
+					"behavior_ids":   ids,
+					"llm_available":  true,
+					"when_conflicts": conflictKeys,
 				})
 			}
 			return result, nil
@@ -111,16 +131,19 @@ func (m *BehaviorMerger) Merge(ctx context.Context, behaviors []*models.Behavior
 	}
 	if m.decisions != nil {
 		m.decisions.Log(map[string]any{
-			"event":         "merge_decision",
-			"strategy":      "rule",
-			"behavior_ids":  ids,
-			"llm_available": llmAvailable,
-			"reason":        reason,
+			"event":          "merge_decision",
+			"strategy":       "rule",
+			"behavior_ids":   ids,
+			"llm_available":  llmAvailable,
+			"reason":         reason,
+			"when_conflicts": conflictKeys,
 		})
 	}
 
 	// Rule-based merge
-	return m.ruleMerge(behaviors), nil
+	merged := m.ruleMerge(behaviors)
+	merged.When = when
+	return merged, nil
 }
 
 // shouldUseLLM checks if LLM merging should be attempted.
@@ -157,9 +180,6 @@ func (m *BehaviorMerger) llmMerge(ctx context.Context, behaviors []*models.Behav
 	merged.ID = generateMergedID(behaviors)
 	merged.Provenance = createMergeProvenance(behaviors)
 
-	// Merge when conditions from all sources
-	merged.When = mergeWhenConditions(behaviors)
-
 	// Track merge relationships
 	for _, b := range behaviors {
 		if b.ID != "" {
@@ -182,7 +202,6 @@ func (m *BehaviorMerger) ruleMerge(behaviors []*models.Behavior) *models.Behavio
 		ID:   generateMergedID(behaviors),
 		Name: generateMergedName(behaviors),
 		Kind: selectBestKind(behaviors),
-		When: mergeWhenConditions(behaviors),
 		Content: models.BehaviorContent{
 			Canonical: mergeCanonicalContent(behaviors),
 		},
@@ -262,12 +281,13 @@ func selectBestKind(behaviors []*models.Behavior) models.BehaviorKind {
 	return best
 }
 
-// mergeWhenConditions unions all when conditions from the behaviors.
-// Keys and string values are sanitized to prevent stored prompt injection.
-func mergeWhenConditions(behaviors []*models.Behavior) map[string]interface{} {
-	result := make(map[string]interface{})
-
+// mergeWhenConditions combines the when conditions of the behaviors with
+// MergeWhen. Keys and string values are sanitized first to prevent stored
+// prompt injection.
+func mergeWhenConditions(behaviors []*models.Behavior) (map[string]interface{}, []WhenConflict, error) {
+	whens := make([]map[string]interface{}, 0, len(behaviors))
 	for _, b := range behaviors {
+		clean := make(map[string]interface{}, len(b.When))
 		for key, value := range b.When {
 			// Sanitize the key to prevent injection via condition keys.
 			cleanKey := sanitize.SanitizeBehaviorName(key)
@@ -275,17 +295,11 @@ func mergeWhenConditions(behaviors []*models.Behavior) map[string]interface{} {
 				continue
 			}
 			// Sanitize the value to prevent injection via condition values.
-			cleanValue := sanitizeWhenValue(value)
-			if existing, ok := result[cleanKey]; ok {
-				// Try to merge values
-				result[cleanKey] = mergeConditionValues(existing, cleanValue)
-			} else {
-				result[cleanKey] = cleanValue
-			}
+			clean[cleanKey] = sanitizeWhenValue(value)
 		}
+		whens = append(whens, clean)
 	}
-
-	return result
+	return MergeWhen(whens...)
 }
 
 // sanitizeWhenValue sanitizes a when condition value. String values are
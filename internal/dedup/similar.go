@@ -0,0 +1,123 @@
+package dedup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/similarity"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/vecmath"
+)
+
+// SimilarMatch is a stored behavior ranked against example text.
+type SimilarMatch struct {
+	// Behavior is the matching behavior found in the store.
+	Behavior *models.Behavior `json:"behavior" yaml:"behavior"`
+
+	// Score is the similarity score between 0.0 and 1.0.
+	Score float64 `json:"score" yaml:"score"`
+
+	// Method describes how the score was computed: "embedding" or "jaccard".
+	Method string `json:"method" yaml:"method"`
+
+	// WouldDuplicate is true when the score reaches the dedup threshold for
+	// its method, i.e. learning the example would be flagged as a duplicate.
+	WouldDuplicate bool `json:"would_duplicate" yaml:"would_duplicate"`
+}
+
+// SimilarOptions configures FindSimilar.
+type SimilarOptions struct {
+	// SimilarityThreshold and EmbeddingThreshold decide WouldDuplicate, as in
+	// DeduplicatorConfig. Zero values use DefaultConfig.
+	SimilarityThreshold float64
+	EmbeddingThreshold  float64
+
+	// LLMClient is used for embeddings when it implements
+	// llm.EmbeddingComparer and is available. Pairwise LLM comparison is
+	// never used; it is too expensive to run against the whole store.
+	LLMClient llm.Client
+
+	// MinScore drops matches scoring below it.
+	MinScore float64
+
+	// Limit caps the number of matches returned. Use 0 for no limit.
+	Limit int
+}
+
+// FindSimilar ranks the behaviors in s by similarity to text using the same
+// scoring as deduplication: embedding cosine similarity when available,
+// otherwise Jaccard word overlap on the canonical content. Matches are
+// sorted by score, highest first.
+func FindSimilar(ctx context.Context, s store.GraphStore, text string, opts SimilarOptions) ([]SimilarMatch, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("example text is required")
+	}
+
+	defaults := DefaultConfig()
+	if opts.SimilarityThreshold <= 0 {
+		opts.SimilarityThreshold = defaults.SimilarityThreshold
+	}
+	if opts.EmbeddingThreshold <= 0 {
+		opts.EmbeddingThreshold = defaults.EmbeddingThreshold
+	}
+
+	nodes, err := s.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query behaviors: %w", err)
+	}
+
+	// Embed the example once; fall back to Jaccard if that fails.
+	var ec llm.EmbeddingComparer
+	var cache *EmbeddingCache
+	var queryVec []float32
+	if c, ok := opts.LLMClient.(llm.EmbeddingComparer); ok && opts.LLMClient.Available() {
+		cache = NewEmbeddingCache()
+		if vec, err := cache.GetOrCompute(ctx, c, text); err == nil {
+			ec, queryVec = c, vec
+		}
+	}
+
+	matches := make([]SimilarMatch, 0, len(nodes))
+	for _, node := range nodes {
+		b := models.NodeToBehavior(node)
+
+		score, method := 0.0, "jaccard"
+		if ec != nil {
+			if vec, err := cache.GetOrCompute(ctx, ec, b.Content.Canonical); err == nil {
+				score, method = vecmath.CosineSimilarity(queryVec, vec), "embedding"
+			}
+		}
+		if method == "jaccard" {
+			// The example has no when conditions or tags, so the weighted
+			// score reduces to content similarity.
+			score = similarity.WeightedScoreWithTags(-1, similarity.ComputeContentSimilarity(text, b.Content.Canonical), -1)
+		}
+		if score < opts.MinScore {
+			continue
+		}
+
+		threshold := opts.SimilarityThreshold
+		if method == "embedding" {
+			threshold = opts.EmbeddingThreshold
+		}
+		matches = append(matches, SimilarMatch{
+			Behavior:       &b,
+			Score:          score,
+			Method:         method,
+			WouldDuplicate: score >= threshold,
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if opts.Limit > 0 && len(matches) > opts.Limit {
+		matches = matches[:opts.Limit]
+	}
+	return matches, nil
+}
@@ -0,0 +1,92 @@
+package dedup
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestFindSimilar(t *testing.T) {
+	ctx := context.Background()
+	s := createTestStore([]models.Behavior{
+		{ID: "ctx", Name: "ctx-first", Content: models.BehaviorContent{Canonical: "prefer context.Context as the first argument"}},
+		{ID: "errs", Name: "wrap-errors", Content: models.BehaviorContent{Canonical: "wrap errors with fmt.Errorf and %w"}},
+		{ID: "paths", Name: "pathlib", Content: models.BehaviorContent{Canonical: "use pathlib for file operations"}},
+	})
+
+	tests := []struct {
+		name       string
+		text       string
+		opts       SimilarOptions
+		wantIDs    []string
+		wantMethod string
+		wantDup    bool
+	}{
+		{
+			name:       "jaccard ranks closest first",
+			text:       "prefer context.Context as first argument",
+			opts:       SimilarOptions{MinScore: 0.1},
+			wantIDs:    []string{"ctx"},
+			wantMethod: "jaccard",
+		},
+		{
+			name:       "exact text would duplicate",
+			text:       "wrap errors with fmt.Errorf and %w",
+			opts:       SimilarOptions{Limit: 1},
+			wantIDs:    []string{"errs"},
+			wantMethod: "jaccard",
+			wantDup:    true,
+		},
+		{
+			name:       "embeddings used when available",
+			text:       "anything",
+			opts:       SimilarOptions{LLMClient: llm.NewMockClient().WithEmbedResult([]float32{1, 0, 0}), Limit: 2},
+			wantIDs:    []string{"ctx", "errs"},
+			wantMethod: "embedding",
+			wantDup:    true,
+		},
+		{
+			name:       "embed failure falls back to jaccard",
+			text:       "use pathlib",
+			opts:       SimilarOptions{LLMClient: llm.NewMockClient().WithEmbedError(errors.New("down")), MinScore: 0.1},
+			wantIDs:    []string{"paths"},
+			wantMethod: "jaccard",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := FindSimilar(ctx, s, tt.text, tt.opts)
+			if err != nil {
+				t.Fatalf("FindSimilar() error = %v", err)
+			}
+			if len(matches) != len(tt.wantIDs) {
+				t.Fatalf("FindSimilar() returned %d matches, want %d: %+v", len(matches), len(tt.wantIDs), matches)
+			}
+			for i, id := range tt.wantIDs {
+				if tt.wantMethod == "jaccard" && matches[i].Behavior.ID != id {
+					t.Errorf("matches[%d] = %s, want %s", i, matches[i].Behavior.ID, id)
+				}
+				if matches[i].Method != tt.wantMethod {
+					t.Errorf("matches[%d].Method = %s, want %s", i, matches[i].Method, tt.wantMethod)
+				}
+			}
+			if matches[0].WouldDuplicate != tt.wantDup {
+				t.Errorf("WouldDuplicate = %v, want %v (score %.2f)", matches[0].WouldDuplicate, tt.wantDup, matches[0].Score)
+			}
+			for i := 1; i < len(matches); i++ {
+				if matches[i].Score > matches[i-1].Score {
+					t.Errorf("matches not sorted by score: %+v", matches)
+				}
+			}
+		})
+	}
+}
+
+func TestFindSimilar_RequiresText(t *testing.T) {
+	if _, err := FindSimilar(context.Background(), createTestStore(nil), "  ", SimilarOptions{}); err == nil {
+		t.Error("FindSimilar() with empty text should fail")
+	}
+}
@@ -22,6 +22,7 @@ type SimilarityConfig struct {
 	Logger              *slog.Logger
 	Decisions           *logging.DecisionLogger
 	EmbeddingCache      *EmbeddingCache // nil = no caching
+	Backend             Similarity      // nil = use the fallback chain
 }
 
 // SimilarityResult holds the score and method used for a similarity computation.
@@ -33,7 +34,21 @@ type SimilarityResult struct {
 // ComputeSimilarity calculates similarity between two behaviors using a 3-tier
 // fallback chain: embedding → LLM → Jaccard. This is the single source of truth
 // for similarity computation across all dedup paths (store, cross-store, CLI).
+// A configured Backend is tried first, and the chain is used if it fails.
 func ComputeSimilarity(a, b *models.Behavior, cfg SimilarityConfig) SimilarityResult {
+	if cfg.Backend != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		result, err := cfg.Backend.Compare(ctx, a, b)
+		cancel()
+		if err == nil {
+			logSimilarity(a, b, result.Score, result.Method, cfg)
+			return result
+		}
+		if cfg.Logger != nil {
+			cfg.Logger.Debug("similarity backend failed, falling back", "error", err)
+		}
+	}
+
 	if cfg.UseLLM && cfg.LLMClient != nil && cfg.LLMClient.Available() {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
@@ -66,13 +81,18 @@ func ComputeSimilarity(a, b *models.Behavior, cfg SimilarityConfig) SimilarityRe
 	}
 
 	// Fallback: weighted Jaccard similarity with tag enhancement
+	score := jaccardScore(a, b)
+	logSimilarity(a, b, score, "jaccard", cfg)
+	return SimilarityResult{Score: score, Method: "jaccard"}
+}
+
+// jaccardScore is the weighted word-overlap similarity of a and b's
+// when-conditions, content, and tags.
+func jaccardScore(a, b *models.Behavior) float64 {
 	whenOverlap := similarity.ComputeWhenOverlap(a.When, b.When)
 	contentSim := similarity.ComputeContentSimilarity(a.Content.Canonical, b.Content.Canonical)
 	tagSim := similarity.ComputeTagSimilarity(a.Content.Tags, b.Content.Tags)
-	score := similarity.WeightedScoreWithTags(whenOverlap, contentSim, tagSim)
-
-	logSimilarity(a, b, score, "jaccard", cfg)
-	return SimilarityResult{Score: score, Method: "jaccard"}
+	return similarity.WeightedScoreWithTags(whenOverlap, contentSim, tagSim)
 }
 
 // computeEmbeddingSimilarity uses the EmbeddingCache if available, otherwise
@@ -3,6 +3,7 @@ package dedup
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sort"
@@ -57,7 +58,9 @@ func (d *StoreDeduplicator) SetLogger(logger *slog.Logger, decisions *logging.De
 }
 
 // FindDuplicates finds potential duplicates of a behavior in the store.
-// Returns a list of matches sorted by similarity score (highest first).
+// Behaviors whose when-conditions are incompatible with it are never
+// duplicates. Returns a list of matches sorted by similarity score (highest
+// first).
 func (d *StoreDeduplicator) FindDuplicates(ctx context.Context, behavior *models.Behavior) ([]DuplicateMatch, error) {
 	// Get all behaviors from the store
 	nodes, err := d.store.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
@@ -74,6 +77,9 @@ func (d *StoreDeduplicator) FindDuplicates(ctx context.Context, behavior *models
 		}
 
 		other := models.NodeToBehavior(node)
+		if !ContextsCompatible(behavior, &other) {
+			continue
+		}
 		sim := d.computeSimilarity(behavior, &other)
 
 		if sim.score >= d.effectiveThreshold(sim.method) {
@@ -128,6 +134,15 @@ func (d *StoreDeduplicator) MergeDuplicates(ctx context.Context, matches []Dupli
 // DeduplicateStore performs deduplication on the entire store.
 // Analyzes all behaviors, finds duplicates, and optionally merges them
 // based on the configuration provided at construction time.
+//
+// Runs are differential unless DeduplicatorConfig.Full is set: after a run
+// that merges without errors, the next run skips pairs of behaviors that
+// were both already compared and have not changed since.
+//
+// Similar behaviors with incompatible when-conditions are context variants:
+// they are not merged, and with AutoMerge they are linked under a shared
+// parent instead (see LinkContextVariants). Behaviors already linked by
+// specializes edges are left alone.
 func (d *StoreDeduplicator) DeduplicateStore(ctx context.Context, s store.GraphStore) (*DeduplicationReport, error) {
 	// Get all behaviors from the store
 	nodes, err := s.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
@@ -149,6 +164,33 @@ func (d *StoreDeduplicator) DeduplicateStore(ctx context.Context, s store.GraphS
 	for _, node := range nodes {
 		behaviors = append(behaviors, models.NodeToBehavior(node))
 	}
+	if err := attachSpecializes(ctx, behaviors, s); err != nil {
+		return nil, err
+	}
+
+	// A differential run only compares pairs involving a behavior that is
+	// new or changed since the watermark; nil compares every pair
+	hashes := make(map[string]string, len(behaviors))
+	for i := range behaviors {
+		hashes[behaviors[i].ID] = dedupHash(&behaviors[i])
+	}
+	var changed map[string]bool
+	if prev, ok := d.loadWatermark(ctx, s); ok && !d.config.Full {
+		changed = make(map[string]bool)
+		for id, h := range hashes {
+			if prev[id] != h {
+				changed[id] = true
+			}
+		}
+		report.Differential = true
+		report.ComparedBehaviors = len(changed)
+	} else {
+		report.ComparedBehaviors = len(behaviors)
+	}
+
+	// Context variants grouped by the first behavior they were found with
+	var variantGroups [][]*models.Behavior
+	grouped := make(map[string]bool)
 
 	// Create embedding cache for batch pairwise comparisons so each
 	// behavior's canonical text is embedded at most once.
@@ -168,23 +210,48 @@ func (d *StoreDeduplicator) DeduplicateStore(ctx context.Context, s store.GraphS
 
 		// Find duplicates for this behavior
 		var duplicates []DuplicateMatch
+		var variants []*models.Behavior
 		for j := range behaviors {
 			if i == j || processed[behaviors[j].ID] {
 				continue
 			}
 
 			other := &behaviors[j]
+			if changed != nil && !changed[behavior.ID] && !changed[other.ID] {
+				continue
+			}
+			if SpecializesRelated(behavior, other) {
+				continue
+			}
+			compatible := ContextsCompatible(behavior, other)
+			if !compatible && (grouped[behavior.ID] || grouped[other.ID]) {
+				continue
+			}
 			sim := d.computeSimilarity(behavior, other)
+			if sim.score < d.effectiveThreshold(sim.method) {
+				continue
+			}
+
+			if !compatible {
+				report.ContextVariantsFound++
+				variants = append(variants, other)
+				continue
+			}
+			duplicates = append(duplicates, DuplicateMatch{
+				Behavior:         other,
+				Similarity:       sim.score,
+				SimilarityMethod: sim.method,
+				MergeRecommended: sim.score >= 0.95,
+			})
+			processed[other.ID] = true
+		}
 
-			if sim.score >= d.effectiveThreshold(sim.method) {
-				duplicates = append(duplicates, DuplicateMatch{
-					Behavior:         other,
-					Similarity:       sim.score,
-					SimilarityMethod: sim.method,
-					MergeRecommended: sim.score >= 0.95,
-				})
-				processed[other.ID] = true
+		if len(variants) > 0 {
+			group := append([]*models.Behavior{behavior}, variants...)
+			for _, v := range group {
+				grouped[v.ID] = true
 			}
+			variantGroups = append(variantGroups, group)
 		}
 
 		if len(duplicates) == 0 {
@@ -196,6 +263,11 @@ func (d *StoreDeduplicator) DeduplicateStore(ctx context.Context, s store.GraphS
 		// Merge if auto-merge is enabled
 		if d.config.AutoMerge {
 			merged, err := d.MergeDuplicates(ctx, duplicates, behavior)
+			var whenErr *WhenMergeError
+			if errors.As(err, &whenErr) {
+				report.BlockedMerges = append(report.BlockedMerges, fmt.Sprintf("%s: %v", behavior.ID, whenErr))
+				continue
+			}
 			if err != nil {
 				report.Errors = append(report.Errors, fmt.Sprintf("failed to merge %s: %v", behavior.ID, err))
 				continue
@@ -203,8 +275,12 @@ func (d *StoreDeduplicator) DeduplicateStore(ctx context.Context, s store.GraphS
 
 			report.MergesPerformed++
 			report.MergedBehaviors = append(report.MergedBehaviors, merged)
+			if report.MergedFrom == nil {
+				report.MergedFrom = make(map[string][]string)
+			}
 			for _, dup := range duplicates {
 				report.DeletedIDs = append(report.DeletedIDs, dup.Behavior.ID)
+				report.MergedFrom[merged.ID] = append(report.MergedFrom[merged.ID], dup.Behavior.ID)
 			}
 
 			// Update the store with the merged behavior, routing to correct scope
@@ -222,6 +298,41 @@ func (d *StoreDeduplicator) DeduplicateStore(ctx context.Context, s store.GraphS
 		}
 	}
 
+	if d.config.AutoMerge {
+		deleted := make(map[string]bool, len(report.DeletedIDs))
+		for _, id := range report.DeletedIDs {
+			deleted[id] = true
+		}
+		for _, group := range variantGroups {
+			var live []*models.Behavior
+			for _, b := range group {
+				if !deleted[b.ID] {
+					live = append(live, b)
+				}
+			}
+			if len(live) < 2 {
+				continue
+			}
+			parent, err := LinkContextVariants(ctx, s, live)
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("failed to link context variants of %s: %v", live[0].ID, err))
+				continue
+			}
+			report.SharedParents = append(report.SharedParents, parent)
+		}
+
+		// Record what this run compared. A run with errors leaves the old
+		// watermark, so anything it failed on is compared again next time.
+		if len(report.Errors) == 0 {
+			for _, id := range report.DeletedIDs {
+				delete(hashes, id)
+			}
+			if err := d.saveWatermark(ctx, s, hashes); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("failed to record dedup watermark: %v", err))
+			}
+		}
+	}
+
 	return report, nil
 }
 
@@ -258,5 +369,6 @@ func (d *StoreDeduplicator) similarityConfig() SimilarityConfig {
 		Logger:              d.logger,
 		Decisions:           d.decisions,
 		EmbeddingCache:      d.embeddingCache,
+		Backend:             d.config.Similarity,
 	}
 }
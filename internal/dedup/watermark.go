@@ -0,0 +1,92 @@
+package dedup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// watermarkStateKey is the store.StateStore key of the dedup watermark.
+const watermarkStateKey = "dedup_watermark"
+
+// watermarkVersion is bumped when dedupHash changes, forcing a full pass.
+const watermarkVersion = 1
+
+// watermark records what the last completed DeduplicateStore run compared,
+// so the next run only needs to compare behaviors that are new or changed.
+type watermark struct {
+	Version int `json:"version"`
+
+	// Config fingerprints the comparison settings; a different threshold
+	// or backend can change any pair's outcome.
+	Config string `json:"config"`
+
+	// Hashes maps each behavior ID to its dedupHash at the last run.
+	Hashes map[string]string `json:"hashes"`
+}
+
+// dedupHash summarizes the fields that decide whether a behavior duplicates
+// another: its kind, canonical content, when-conditions, and specializes
+// links.
+func dedupHash(b *models.Behavior) string {
+	when, _ := json.Marshal(b.When) // map keys are marshaled in sorted order
+	specializes := append([]string(nil), b.Specializes...)
+	sort.Strings(specializes)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%v", b.Kind, b.Content.Canonical, when, specializes)
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// configFingerprint identifies the settings that affect comparisons.
+func (d *StoreDeduplicator) configFingerprint() string {
+	return fmt.Sprintf("threshold=%g embedding=%g llm=%t backend=%T",
+		d.config.SimilarityThreshold, d.config.EmbeddingThreshold,
+		d.config.UseLLM && d.llmClient != nil, d.config.Similarity)
+}
+
+// loadWatermark returns the behavior hashes recorded by the last run, or
+// false when s keeps no state, none was recorded, or it was recorded with
+// different settings.
+func (d *StoreDeduplicator) loadWatermark(ctx context.Context, s store.GraphStore) (map[string]string, bool) {
+	ss, ok := s.(store.StateStore)
+	if !ok {
+		return nil, false
+	}
+	raw, err := ss.GetState(ctx, watermarkStateKey)
+	if err != nil || raw == "" {
+		return nil, false
+	}
+	var wm watermark
+	if err := json.Unmarshal([]byte(raw), &wm); err != nil {
+		return nil, false
+	}
+	if wm.Version != watermarkVersion || wm.Config != d.configFingerprint() {
+		return nil, false
+	}
+	return wm.Hashes, true
+}
+
+// saveWatermark records hashes as compared. Stores that keep no state are
+// skipped; every run against them is a full pass.
+func (d *StoreDeduplicator) saveWatermark(ctx context.Context, s store.GraphStore, hashes map[string]string) error {
+	ss, ok := s.(store.StateStore)
+	if !ok {
+		return nil
+	}
+	raw, err := json.Marshal(watermark{
+		Version: watermarkVersion,
+		Config:  d.configFingerprint(),
+		Hashes:  hashes,
+	})
+	if err != nil {
+		return err
+	}
+	return ss.SetState(ctx, watermarkStateKey, string(raw))
+}
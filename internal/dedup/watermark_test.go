@@ -0,0 +1,147 @@
+package dedup
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// countingSimilarity scores behaviors whose canonical text matches ignoring
+// case as duplicates, and counts comparisons.
+type countingSimilarity struct {
+	calls atomic.Int64
+}
+
+func (c *countingSimilarity) Compare(ctx context.Context, a, b *models.Behavior) (SimilarityResult, error) {
+	c.calls.Add(1)
+	if strings.EqualFold(a.Content.Canonical, b.Content.Canonical) {
+		return SimilarityResult{Score: 1, Method: "jaccard"}, nil
+	}
+	return SimilarityResult{Score: 0, Method: "jaccard"}, nil
+}
+
+func TestDeduplicateStore_Differential(t *testing.T) {
+	ctx := context.Background()
+	s := createTestStore([]models.Behavior{
+		{ID: "b1", Content: models.BehaviorContent{Canonical: "use pathlib"}},
+		{ID: "b2", Content: models.BehaviorContent{Canonical: "wrap errors"}},
+		{ID: "b3", Content: models.BehaviorContent{Canonical: "no panics"}},
+		{ID: "b4", Content: models.BehaviorContent{Canonical: "table tests"}},
+	})
+	sim := &countingSimilarity{}
+	cfg := DeduplicatorConfig{SimilarityThreshold: 0.9, AutoMerge: true, Similarity: sim}
+
+	run := func(t *testing.T, cfg DeduplicatorConfig) (*DeduplicationReport, int64) {
+		t.Helper()
+		sim.calls.Store(0)
+		d := NewStoreDeduplicator(s, NewBehaviorMerger(MergerConfig{}), cfg)
+		report, err := d.DeduplicateStore(ctx, s)
+		if err != nil {
+			t.Fatalf("DeduplicateStore() error = %v", err)
+		}
+		if len(report.Errors) > 0 {
+			t.Fatalf("DeduplicateStore() errors = %v", report.Errors)
+		}
+		return report, sim.calls.Load()
+	}
+
+	// Without a watermark every pair is compared
+	report, calls := run(t, cfg)
+	if report.Differential || report.ComparedBehaviors != 4 || calls != 6 {
+		t.Errorf("first run: differential=%v compared=%d calls=%d, want a full pass of 6 pairs",
+			report.Differential, report.ComparedBehaviors, calls)
+	}
+
+	// Nothing changed, nothing to compare
+	report, calls = run(t, cfg)
+	if !report.Differential || report.ComparedBehaviors != 0 || calls != 0 {
+		t.Errorf("unchanged run: differential=%v compared=%d calls=%d, want no comparisons",
+			report.Differential, report.ComparedBehaviors, calls)
+	}
+
+	// A new behavior is compared against the rest of the store, at most once
+	// per pair; the comparisons stop once it is merged
+	node := models.BehaviorToNode(&models.Behavior{ID: "b5", Content: models.BehaviorContent{Canonical: "Use Pathlib"}})
+	if _, err := s.AddNode(ctx, node); err != nil {
+		t.Fatal(err)
+	}
+	report, calls = run(t, cfg)
+	if report.ComparedBehaviors != 1 || calls < 1 || calls > 4 {
+		t.Errorf("run after learning: compared=%d calls=%d, want b5 against up to 4 others", report.ComparedBehaviors, calls)
+	}
+	if report.DuplicatesFound != 1 || report.MergesPerformed != 1 {
+		t.Errorf("run after learning: found=%d merged=%d, want b5 merged with b1", report.DuplicatesFound, report.MergesPerformed)
+	}
+
+	// Full ignores the watermark
+	remaining, _ := s.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	n := int64(len(remaining))
+	full := cfg
+	full.Full = true
+	if report, calls = run(t, full); report.Differential || calls != n*(n-1)/2 {
+		t.Errorf("full run: differential=%v calls=%d, want all %d pairs", report.Differential, calls, n*(n-1)/2)
+	}
+
+	// Different settings invalidate the watermark
+	changed := cfg
+	changed.SimilarityThreshold = 0.8
+	if report, _ = run(t, changed); report.Differential {
+		t.Error("run with a new threshold was differential, want a full pass")
+	}
+}
+
+func TestDeduplicateStore_DryRunKeepsWatermark(t *testing.T) {
+	ctx := context.Background()
+	s := createTestStore([]models.Behavior{
+		{ID: "b1", Content: models.BehaviorContent{Canonical: "use pathlib"}},
+		{ID: "b2", Content: models.BehaviorContent{Canonical: "wrap errors"}},
+	})
+	sim := &countingSimilarity{}
+	for i := 0; i < 2; i++ {
+		d := NewStoreDeduplicator(s, NewBehaviorMerger(MergerConfig{}), DeduplicatorConfig{SimilarityThreshold: 0.9, Similarity: sim})
+		report, err := d.DeduplicateStore(ctx, s)
+		if err != nil {
+			t.Fatalf("DeduplicateStore() error = %v", err)
+		}
+		if report.Differential {
+			t.Errorf("report-only run %d was differential, want no watermark recorded", i+1)
+		}
+	}
+	if raw, _ := s.(store.StateStore).GetState(ctx, watermarkStateKey); raw != "" {
+		t.Errorf("report-only runs recorded a watermark: %s", raw)
+	}
+}
+
+func TestDedupHash(t *testing.T) {
+	base := models.Behavior{
+		ID:      "b1",
+		Kind:    models.BehaviorKindDirective,
+		Content: models.BehaviorContent{Canonical: "wrap errors"},
+		When:    map[string]interface{}{"language": "go", "task": "coding"},
+	}
+	tests := []struct {
+		name   string
+		modify func(b *models.Behavior)
+		same   bool
+	}{
+		{"identical", func(b *models.Behavior) {}, true},
+		{"stats ignored", func(b *models.Behavior) { b.Confidence = 0.9 }, true},
+		{"canonical", func(b *models.Behavior) { b.Content.Canonical = "wrap all errors" }, false},
+		{"when", func(b *models.Behavior) { b.When = map[string]interface{}{"language": "python"} }, false},
+		{"kind", func(b *models.Behavior) { b.Kind = models.BehaviorKindConstraint }, false},
+		{"specializes", func(b *models.Behavior) { b.Specializes = []string{"parent"} }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := base
+			tt.modify(&b)
+			if got := dedupHash(&b) == dedupHash(&base); got != tt.same {
+				t.Errorf("hash unchanged = %v, want %v", got, tt.same)
+			}
+		})
+	}
+}
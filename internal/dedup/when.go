@@ -0,0 +1,205 @@
+package dedup
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// Resolutions of a when-condition conflict, as reported in WhenConflict.
+const (
+	// WhenResolvedUnion means the values were combined into one list, which
+	// matches any of them.
+	WhenResolvedUnion = "union"
+
+	// WhenResolvedAny means the conditions were moved into an "any"
+	// OR-group with one alternative per behavior (see models.WhenAny).
+	WhenResolvedAny = "any"
+)
+
+// WhenConflict is a when-condition key that merged behaviors give different
+// values, and how the merge combined them.
+type WhenConflict struct {
+	Key        string        `json:"key"`
+	Values     []interface{} `json:"values"`
+	Resolution string        `json:"resolution"`
+}
+
+// String describes the conflict, e.g. `language: "go" | "python" (union)`.
+func (c WhenConflict) String() string {
+	values := make([]string, len(c.Values))
+	for i, v := range c.Values {
+		values[i] = models.DescribeWhen(map[string]interface{}{c.Key: v})
+		values[i] = strings.TrimPrefix(values[i], c.Key+": ")
+	}
+	return fmt.Sprintf("%s: %s (%s)", c.Key, strings.Join(values, " | "), c.Resolution)
+}
+
+// WhenMergeError reports when-conditions that cannot be combined into one
+// behavior. A merge that would need it is refused.
+type WhenMergeError struct {
+	// Key is the condition key the behaviors could not agree on.
+	Key string
+
+	// Reason explains why the conditions cannot be combined.
+	Reason string
+}
+
+func (e *WhenMergeError) Error() string {
+	return fmt.Sprintf("when-conditions cannot be merged on %q: %s", e.Key, e.Reason)
+}
+
+// MergeWhen combines the when-conditions of behaviors being merged into one
+// behavior, so the result applies wherever the conditions of each source
+// did rather than nowhere.
+//
+// A key set by only some behaviors, or set to the same value by all of
+// them, is kept as is. When behaviors set a key to different values, those
+// values are in conflict: taking either would drop the contexts of the
+// other, and requiring both (language go AND python) could never hold.
+// When only one key conflicts and its values are plain, they are combined
+// into one list, which matches any of them. Otherwise (operators, globs,
+// temporal and non-string values, or several conflicting keys) the
+// conflicting conditions move into an "any" OR-group with one alternative
+// per behavior. Every conflict is returned with its resolution.
+//
+// MergeWhen refuses with a *WhenMergeError when the OR-group would collide
+// with one the behaviors already have, since when-maps hold one "any" key.
+func MergeWhen(whens ...map[string]interface{}) (map[string]interface{}, []WhenConflict, error) {
+	distinct := make(map[string][]interface{})
+	for _, when := range whens {
+		for key, value := range when {
+			if !containsValue(distinct[key], value) {
+				distinct[key] = append(distinct[key], value)
+			}
+		}
+	}
+	keys := make([]string, 0, len(distinct))
+	for key := range distinct {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make(map[string]interface{}, len(keys))
+	var conflicting []string
+	for _, key := range keys {
+		values := distinct[key]
+		if len(values) == 1 {
+			result[key] = values[0]
+			continue
+		}
+		if key == models.WhenAny {
+			return nil, nil, &WhenMergeError{
+				Key:    key,
+				Reason: "the behaviors have different OR-groups, and combining them would need nested OR-groups; make their conditions agree or keep them apart",
+			}
+		}
+		conflicting = append(conflicting, key)
+	}
+
+	// One conflicting key can be listed; with more, listing each would also
+	// match combinations no behavior had (go with *.py), so each behavior's
+	// conflicting conditions become one alternative of an OR-group instead
+	var conflicts []WhenConflict
+	if len(conflicting) == 1 {
+		key := conflicting[0]
+		if union, ok := unionPlainValues(key, distinct[key]); ok {
+			result[key] = union
+			conflicts = append(conflicts, WhenConflict{Key: key, Values: distinct[key], Resolution: WhenResolvedUnion})
+			conflicting = nil
+		}
+	}
+	for _, key := range conflicting {
+		conflicts = append(conflicts, WhenConflict{Key: key, Values: distinct[key], Resolution: WhenResolvedAny})
+	}
+
+	if len(conflicting) == 0 {
+		return result, conflicts, nil
+	}
+	if _, ok := result[models.WhenAny]; ok {
+		return nil, nil, &WhenMergeError{
+			Key:    conflicting[0],
+			Reason: "the values can only be combined as an OR-group, and the behaviors already have one; make their conditions agree or keep them apart",
+		}
+	}
+	var alternatives []interface{}
+	for _, when := range whens {
+		alt := make(map[string]interface{})
+		for _, key := range conflicting {
+			if value, ok := when[key]; ok {
+				alt[key] = value
+			}
+		}
+		if len(alt) > 0 && !containsValue(alternatives, alt) {
+			alternatives = append(alternatives, alt)
+		}
+	}
+	result[models.WhenAny] = alternatives
+	return result, conflicts, nil
+}
+
+// unionPlainValues combines values that list membership can match exactly
+// into one list: strings without glob characters, and lists of strings.
+func unionPlainValues(key string, values []interface{}) (interface{}, bool) {
+	if models.IsTemporalKey(key) {
+		return nil, false
+	}
+	// Start from a new list so no behavior's list is appended to
+	var union interface{} = []string{}
+	for _, v := range values {
+		plain, ok := plainValue(v)
+		if !ok {
+			return nil, false
+		}
+		union = mergeConditionValues(union, plain)
+	}
+	return union, true
+}
+
+// plainValue returns v as a string or []string if it is a plain value.
+func plainValue(v interface{}) (interface{}, bool) {
+	switch val := v.(type) {
+	case string:
+		return val, !strings.Contains(val, "*")
+	case []string:
+		return val, true
+	case []interface{}:
+		list := make([]string, 0, len(val))
+		for _, item := range val {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			list = append(list, s)
+		}
+		return list, true
+	default:
+		return nil, false
+	}
+}
+
+// containsValue reports whether values holds a value deeply equal to v.
+// Lists compare equal whether they are []string or []interface{}.
+func containsValue(values []interface{}, v interface{}) bool {
+	for _, existing := range values {
+		if reflect.DeepEqual(normalizeWhenValue(existing), normalizeWhenValue(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeWhenValue converts []string to []interface{} for comparison.
+func normalizeWhenValue(v interface{}) interface{} {
+	if list, ok := v.([]string); ok {
+		out := make([]interface{}, len(list))
+		for i, s := range list {
+			out[i] = s
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,167 @@
+package dedup
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestMergeWhen(t *testing.T) {
+	not := func(v string) map[string]interface{} { return map[string]interface{}{"not": v} }
+	tests := []struct {
+		name      string
+		whens     []map[string]interface{}
+		want      map[string]interface{}
+		conflicts []WhenConflict
+	}{
+		{
+			name:  "same value",
+			whens: []map[string]interface{}{{"language": "go"}, {"language": "go"}},
+			want:  map[string]interface{}{"language": "go"},
+		},
+		{
+			name:  "orthogonal keys are all required",
+			whens: []map[string]interface{}{{"language": "go"}, {"task": "testing"}},
+			want:  map[string]interface{}{"language": "go", "task": "testing"},
+		},
+		{
+			name:  "one plain conflict is listed",
+			whens: []map[string]interface{}{{"language": "go", "task": "testing"}, {"language": []interface{}{"python", "go"}, "task": "testing"}},
+			want:  map[string]interface{}{"language": []string{"go", "python"}, "task": "testing"},
+			conflicts: []WhenConflict{
+				{Key: "language", Values: []interface{}{"go", []interface{}{"python", "go"}}, Resolution: WhenResolvedUnion},
+			},
+		},
+		{
+			name:  "operator conflict becomes an OR-group",
+			whens: []map[string]interface{}{{"language": not("go"), "task": "testing"}, {"language": "go", "task": "testing"}},
+			want: map[string]interface{}{
+				"task": "testing",
+				"any":  []interface{}{map[string]interface{}{"language": not("go")}, map[string]interface{}{"language": "go"}},
+			},
+			conflicts: []WhenConflict{
+				{Key: "language", Values: []interface{}{not("go"), "go"}, Resolution: WhenResolvedAny},
+			},
+		},
+		{
+			name:  "glob conflict becomes an OR-group",
+			whens: []map[string]interface{}{{"file_path": "*.go"}, {"file_path": "Makefile"}},
+			want: map[string]interface{}{
+				"any": []interface{}{map[string]interface{}{"file_path": "*.go"}, map[string]interface{}{"file_path": "Makefile"}},
+			},
+			conflicts: []WhenConflict{
+				{Key: "file_path", Values: []interface{}{"*.go", "Makefile"}, Resolution: WhenResolvedAny},
+			},
+		},
+		{
+			name: "several conflicts keep each behavior's combination",
+			whens: []map[string]interface{}{
+				{"language": "go", "file_path": "*.go"},
+				{"language": "python", "file_path": "*.py"},
+				{"language": "go"},
+			},
+			want: map[string]interface{}{
+				"any": []interface{}{
+					map[string]interface{}{"language": "go", "file_path": "*.go"},
+					map[string]interface{}{"language": "python", "file_path": "*.py"},
+					map[string]interface{}{"language": "go"},
+				},
+			},
+			conflicts: []WhenConflict{
+				{Key: "file_path", Values: []interface{}{"*.go", "*.py"}, Resolution: WhenResolvedAny},
+				{Key: "language", Values: []interface{}{"go", "python"}, Resolution: WhenResolvedAny},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts, err := MergeWhen(tt.whens...)
+			if err != nil {
+				t.Fatalf("MergeWhen() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeWhen() = %#v, want %#v", got, tt.want)
+			}
+			if !reflect.DeepEqual(conflicts, tt.conflicts) {
+				t.Errorf("conflicts = %+v, want %+v", conflicts, tt.conflicts)
+			}
+			if err := models.ValidateWhen(got); err != nil {
+				t.Errorf("merged conditions are invalid: %v", err)
+			}
+		})
+	}
+}
+
+func TestMergeWhen_Blocked(t *testing.T) {
+	goAlt := []interface{}{map[string]interface{}{"language": "go"}}
+	tests := []struct {
+		name  string
+		whens []map[string]interface{}
+		key   string
+	}{
+		{"different OR-groups", []map[string]interface{}{{"any": goAlt}, {"any": []interface{}{map[string]interface{}{"task": "testing"}}}}, "any"},
+		{"OR-group needed beside one", []map[string]interface{}{{"any": goAlt, "file_path": "*.go"}, {"file_path": "*.py"}}, "file_path"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := MergeWhen(tt.whens...)
+			var whenErr *WhenMergeError
+			if !errors.As(err, &whenErr) || whenErr.Key != tt.key {
+				t.Fatalf("MergeWhen() error = %v, want a WhenMergeError on %q", err, tt.key)
+			}
+		})
+	}
+}
+
+func TestWhenConflict_String(t *testing.T) {
+	c := WhenConflict{Key: "language", Values: []interface{}{"go", map[string]interface{}{"not": "rust"}}, Resolution: WhenResolvedAny}
+	if got, want := c.String(), `language: "go" | not "rust" (any)`; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestMerge_BlocksIncompatibleWhen(t *testing.T) {
+	merger := NewBehaviorMerger(MergerConfig{})
+	behaviors := []*models.Behavior{
+		{ID: "a", When: map[string]interface{}{"any": []interface{}{map[string]interface{}{"language": "go"}}}},
+		{ID: "b", When: map[string]interface{}{"any": []interface{}{map[string]interface{}{"language": "rust"}}}},
+	}
+	if _, err := merger.Merge(context.Background(), behaviors); err == nil || !strings.Contains(err.Error(), "cannot be merged") {
+		t.Fatalf("Merge() error = %v, want the merge blocked", err)
+	}
+
+	behaviors[1].When = map[string]interface{}{"language": "rust"}
+	merged, err := merger.Merge(context.Background(), behaviors)
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if _, ok := merged.When["any"]; !ok || merged.When["language"] != "rust" {
+		t.Errorf("merged when = %v, want the OR-group and language", merged.When)
+	}
+}
+
+func TestDeduplicateStore_ReportsBlockedMerges(t *testing.T) {
+	content := models.BehaviorContent{Canonical: "keep handlers small"}
+	s := createTestStore([]models.Behavior{
+		{ID: "a", Name: "small", Kind: models.BehaviorKindDirective, Content: content,
+			When: map[string]interface{}{"any": []interface{}{map[string]interface{}{"language": "go"}}}},
+		{ID: "b", Name: "small", Kind: models.BehaviorKindDirective, Content: content,
+			When: map[string]interface{}{"any": []interface{}{map[string]interface{}{"task": "review"}}}},
+	})
+	d := NewStoreDeduplicator(s, NewBehaviorMerger(MergerConfig{}), DeduplicatorConfig{SimilarityThreshold: 0.5, AutoMerge: true})
+
+	report, err := d.DeduplicateStore(context.Background(), s)
+	if err != nil {
+		t.Fatalf("DeduplicateStore() error = %v", err)
+	}
+	if report.MergesPerformed != 0 || len(report.BlockedMerges) != 1 || len(report.Errors) != 0 {
+		t.Fatalf("report = %+v, want one blocked merge and no errors", report)
+	}
+	if node, _ := s.GetNode(context.Background(), "b"); node == nil {
+		t.Error("blocked duplicate was deleted")
+	}
+}
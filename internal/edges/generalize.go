@@ -0,0 +1,311 @@
+package edges
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/similarity"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// patternGap marks the varying part of a shared pattern ("use … not …").
+const patternGap = "…"
+
+// GeneralizationSuggestion proposes a generalized parent behavior for a
+// group of sibling behaviors that share a pattern.
+type GeneralizationSuggestion struct {
+	ID       string          `json:"id"`
+	Pattern  string          `json:"pattern"`
+	Score    float64         `json:"score"`
+	Parent   models.Behavior `json:"parent"`
+	Children []string        `json:"children"`
+}
+
+// SuggestGeneralizations groups behaviors of the same kind whose content
+// overlaps (>= constants.GeneralizeThreshold) and proposes a generalized parent
+// for each group of at least minChildren. Behaviors that already specialize a
+// parent, or are themselves a parent, are skipped. Behaviors must have
+// Specializes populated (see AttachRelationships).
+func SuggestGeneralizations(behaviors []models.Behavior, minChildren int) []GeneralizationSuggestion {
+	if minChildren < 2 {
+		minChildren = constants.GeneralizeMinChildren
+	}
+
+	isParent := make(map[string]bool)
+	for _, b := range behaviors {
+		for _, p := range b.Specializes {
+			isParent[p] = true
+		}
+	}
+
+	byKind := make(map[models.BehaviorKind][]*models.Behavior)
+	for i := range behaviors {
+		b := &behaviors[i]
+		if len(b.Specializes) > 0 || isParent[b.ID] {
+			continue
+		}
+		byKind[b.Kind] = append(byKind[b.Kind], b)
+	}
+
+	var suggestions []GeneralizationSuggestion
+	for _, group := range byKind {
+		for _, cluster := range clusterBySimilarity(group) {
+			if len(cluster) < minChildren {
+				continue
+			}
+			if s, ok := buildSuggestion(cluster); ok {
+				suggestions = append(suggestions, s)
+			}
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].ID < suggestions[j].ID
+	})
+	return suggestions
+}
+
+// AcceptGeneralization creates the suggested parent behavior and links each
+// child to it with a specializes edge.
+func AcceptGeneralization(ctx context.Context, graphStore store.GraphStore, s GeneralizationSuggestion) error {
+	now := time.Now()
+	parent := s.Parent
+	parent.Provenance.CreatedAt = now
+	parent.Stats.CreatedAt = now
+	parent.Stats.UpdatedAt = now
+
+	if _, err := graphStore.AddNode(ctx, models.BehaviorToNode(&parent)); err != nil {
+		return fmt.Errorf("failed to add generalized behavior: %w", err)
+	}
+
+	for _, childID := range s.Children {
+		edge := store.Edge{
+			Source:    childID,
+			Target:    parent.ID,
+			Kind:      store.EdgeKindSpecializes,
+			Weight:    1.0,
+			CreatedAt: now,
+			Metadata: map[string]interface{}{
+				"suggestion_id": s.ID,
+			},
+		}
+		if err := graphStore.AddEdge(ctx, edge); err != nil {
+			return fmt.Errorf("failed to link %s: %w", childID, err)
+		}
+	}
+
+	if err := graphStore.Sync(ctx); err != nil {
+		return fmt.Errorf("failed to sync store: %w", err)
+	}
+	return nil
+}
+
+// clusterBySimilarity returns connected components of behaviors linked by
+// pairwise content overlap at or above constants.GeneralizeThreshold.
+func clusterBySimilarity(group []*models.Behavior) [][]*models.Behavior {
+	parent := make([]int, len(group))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := 0; i < len(group); i++ {
+		for j := i + 1; j < len(group); j++ {
+			score := overlapCoefficient(group[i].Content.Canonical, group[j].Content.Canonical)
+			if score >= constants.GeneralizeThreshold {
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+
+	components := make(map[int][]*models.Behavior)
+	var roots []int
+	for i, b := range group {
+		r := find(i)
+		if _, ok := components[r]; !ok {
+			roots = append(roots, r)
+		}
+		components[r] = append(components[r], b)
+	}
+
+	clusters := make([][]*models.Behavior, 0, len(roots))
+	for _, r := range roots {
+		clusters = append(clusters, components[r])
+	}
+	return clusters
+}
+
+// buildSuggestion derives the shared pattern and parent behavior for a
+// cluster. Clusters sharing fewer than two words have no usable pattern.
+func buildSuggestion(cluster []*models.Behavior) (GeneralizationSuggestion, bool) {
+	sort.Slice(cluster, func(i, j int) bool { return cluster[i].ID < cluster[j].ID })
+
+	pattern, shared := sharedPattern(cluster)
+	if len(shared) < 2 {
+		return GeneralizationSuggestion{}, false
+	}
+
+	children := make([]string, len(cluster))
+	var confidence, pairScore float64
+	pairs := 0
+	for i, b := range cluster {
+		children[i] = b.ID
+		confidence += b.Confidence
+		for _, other := range cluster[i+1:] {
+			pairScore += overlapCoefficient(b.Content.Canonical, other.Content.Canonical)
+			pairs++
+		}
+	}
+
+	hash := sha256.Sum256([]byte(strings.Join(children, ",")))
+	digest := hex.EncodeToString(hash[:])
+
+	s := GeneralizationSuggestion{
+		ID:       "gen-" + digest[:8],
+		Pattern:  pattern,
+		Score:    pairScore / float64(pairs),
+		Children: children,
+		Parent: models.Behavior{
+			ID:   "behavior-" + digest[:12],
+			Name: "general-" + strings.Join(shared, "-"),
+			Kind: cluster[0].Kind,
+			When: commonWhen(cluster),
+			Content: models.BehaviorContent{
+				Canonical: pattern,
+				Tags:      commonTags(cluster),
+			},
+			Provenance: models.Provenance{SourceType: models.SourceTypeGeneralized},
+			Confidence: confidence / float64(len(cluster)),
+		},
+	}
+	return s, true
+}
+
+// sharedPattern returns the first child's canonical words that appear in
+// every child, with runs of varying words collapsed to patternGap. Leading
+// and trailing gaps are dropped.
+func sharedPattern(cluster []*models.Behavior) (string, []string) {
+	counts := make(map[string]int)
+	for _, b := range cluster {
+		seen := make(map[string]bool)
+		for _, w := range similarity.Tokenize(b.Content.Canonical) {
+			w = strings.ToLower(w)
+			if !seen[w] {
+				seen[w] = true
+				counts[w]++
+			}
+		}
+	}
+
+	var parts, shared []string
+	gap := false
+	for _, w := range similarity.Tokenize(cluster[0].Content.Canonical) {
+		lw := strings.ToLower(w)
+		if counts[lw] == len(cluster) {
+			if gap && len(parts) > 0 {
+				parts = append(parts, patternGap)
+			}
+			parts = append(parts, lw)
+			if !contains(shared, lw) {
+				shared = append(shared, lw)
+			}
+			gap = false
+		} else {
+			gap = true
+		}
+	}
+	return strings.Join(parts, " "), shared
+}
+
+// commonWhen returns the when-conditions shared, with equal values, by every
+// behavior in the cluster. The result is never more specific than a child.
+func commonWhen(cluster []*models.Behavior) map[string]interface{} {
+	common := make(map[string]interface{})
+	for key, value := range cluster[0].When {
+		shared := true
+		for _, b := range cluster[1:] {
+			if other, ok := b.When[key]; !ok || !similarity.ValuesEqual(value, other) {
+				shared = false
+				break
+			}
+		}
+		if shared {
+			common[key] = value
+		}
+	}
+	if len(common) == 0 {
+		return nil
+	}
+	return common
+}
+
+// commonTags returns the tags present on every behavior in the cluster.
+func commonTags(cluster []*models.Behavior) []string {
+	counts := make(map[string]int)
+	for _, b := range cluster {
+		for _, t := range b.Content.Tags {
+			counts[t]++
+		}
+	}
+	var tags []string
+	for _, t := range cluster[0].Content.Tags {
+		if counts[t] == len(cluster) {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// overlapCoefficient is |A∩B| / min(|A|,|B|) over lowercased word sets.
+// Unlike Jaccard it stays high when short templates ("use X not Y") differ
+// only in their varying words.
+func overlapCoefficient(a, b string) float64 {
+	setA := make(map[string]bool)
+	for _, w := range similarity.Tokenize(a) {
+		setA[strings.ToLower(w)] = true
+	}
+	setB := make(map[string]bool)
+	for _, w := range similarity.Tokenize(b) {
+		setB[strings.ToLower(w)] = true
+	}
+	smaller := len(setA)
+	if len(setB) < smaller {
+		smaller = len(setB)
+	}
+	if smaller == 0 {
+		return 0
+	}
+
+	shared := 0
+	for w := range setA {
+		if setB[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(smaller)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package edges
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func libraryBehaviors() []models.Behavior {
+	return []models.Behavior{
+		{
+			ID: "b-httpx", Name: "use-httpx", Kind: models.BehaviorKindPreference,
+			When:       map[string]interface{}{"language": "python", "task": "http"},
+			Content:    models.BehaviorContent{Canonical: "use httpx not requests", Tags: []string{"python", "http"}},
+			Confidence: 0.8,
+		},
+		{
+			ID: "b-pathlib", Name: "use-pathlib", Kind: models.BehaviorKindPreference,
+			When:       map[string]interface{}{"language": "python"},
+			Content:    models.BehaviorContent{Canonical: "use pathlib not os.path", Tags: []string{"python"}},
+			Confidence: 0.6,
+		},
+		{
+			ID: "b-pytest", Name: "use-pytest", Kind: models.BehaviorKindPreference,
+			When:       map[string]interface{}{"language": "python", "task": "testing"},
+			Content:    models.BehaviorContent{Canonical: "use pytest not unittest", Tags: []string{"python", "testing"}},
+			Confidence: 0.7,
+		},
+		{
+			ID: "b-unrelated", Name: "wrap-errors", Kind: models.BehaviorKindDirective,
+			Content: models.BehaviorContent{Canonical: "wrap errors with fmt.Errorf and %w"},
+		},
+	}
+}
+
+func TestSuggestGeneralizations(t *testing.T) {
+	suggestions := SuggestGeneralizations(libraryBehaviors(), 3)
+	if len(suggestions) != 1 {
+		t.Fatalf("got %d suggestions, want 1: %+v", len(suggestions), suggestions)
+	}
+
+	s := suggestions[0]
+	if s.Pattern != "use … not" {
+		t.Errorf("Pattern = %q, want %q", s.Pattern, "use … not")
+	}
+	if len(s.Children) != 3 {
+		t.Errorf("Children = %v, want 3", s.Children)
+	}
+	if s.Parent.Kind != models.BehaviorKindPreference {
+		t.Errorf("Parent.Kind = %q", s.Parent.Kind)
+	}
+	if s.Parent.Provenance.SourceType != models.SourceTypeGeneralized {
+		t.Errorf("Parent.Provenance.SourceType = %q", s.Parent.Provenance.SourceType)
+	}
+	// Only the shared condition survives, so the parent is less specific
+	if len(s.Parent.When) != 1 || s.Parent.When["language"] != "python" {
+		t.Errorf("Parent.When = %v, want {language: python}", s.Parent.When)
+	}
+	if len(s.Parent.Content.Tags) != 1 || s.Parent.Content.Tags[0] != "python" {
+		t.Errorf("Parent.Content.Tags = %v, want [python]", s.Parent.Content.Tags)
+	}
+
+	// IDs are stable across runs
+	again := SuggestGeneralizations(libraryBehaviors(), 3)
+	if again[0].ID != s.ID || again[0].Parent.ID != s.Parent.ID {
+		t.Error("suggestion IDs should be deterministic")
+	}
+}
+
+func TestSuggestGeneralizations_SkipsLinkedAndSmallGroups(t *testing.T) {
+	behaviors := libraryBehaviors()
+	if got := SuggestGeneralizations(behaviors, 4); len(got) != 0 {
+		t.Errorf("min children 4: got %d suggestions, want 0", len(got))
+	}
+
+	behaviors[0].Specializes = []string{"b-parent"}
+	if got := SuggestGeneralizations(behaviors, 3); len(got) != 0 {
+		t.Errorf("already-linked child: got %d suggestions, want 0", len(got))
+	}
+}
+
+func TestAcceptGeneralization(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+
+	behaviors := libraryBehaviors()
+	for i := range behaviors {
+		if _, err := s.AddNode(ctx, models.BehaviorToNode(&behaviors[i])); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+
+	suggestion := SuggestGeneralizations(behaviors, 3)[0]
+	if err := AcceptGeneralization(ctx, s, suggestion); err != nil {
+		t.Fatalf("AcceptGeneralization() error = %v", err)
+	}
+
+	parent, err := s.GetNode(ctx, suggestion.Parent.ID)
+	if err != nil || parent == nil {
+		t.Fatalf("parent node not created: %v", err)
+	}
+
+	loaded, err := LoadBehaviorsFromStore(ctx, s)
+	if err != nil {
+		t.Fatalf("LoadBehaviorsFromStore() error = %v", err)
+	}
+	if err := AttachRelationships(ctx, s, loaded); err != nil {
+		t.Fatalf("AttachRelationships() error = %v", err)
+	}
+
+	linked := 0
+	for _, b := range loaded {
+		if len(b.Specializes) == 1 && b.Specializes[0] == suggestion.Parent.ID {
+			linked++
+		}
+	}
+	if linked != 3 {
+		t.Errorf("linked children = %d, want 3", linked)
+	}
+
+	// Accepted groups are not suggested again
+	if got := SuggestGeneralizations(loaded, 3); len(got) != 0 {
+		t.Errorf("got %d suggestions after accept, want 0", len(got))
+	}
+}
@@ -5,6 +5,7 @@ package edges
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/nvandessel/floop/internal/dedup"
 	"github.com/nvandessel/floop/internal/llm"
@@ -30,6 +31,33 @@ func LoadBehaviorsFromStore(ctx context.Context, graphStore store.GraphStore) ([
 	return behaviors, nil
 }
 
+// AttachRelationships fills Requires, Overrides, Conflicts, and Specializes
+// on each behavior from the edges in graphStore, so the resolver can enforce
+// them.
+func AttachRelationships(ctx context.Context, graphStore store.GraphStore, behaviors []models.Behavior) error {
+	if es, ok := graphStore.(interface {
+		GetAllEdges(ctx context.Context) ([]store.Edge, error)
+	}); ok {
+		all, err := es.GetAllEdges(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load edges: %w", err)
+		}
+		models.ApplyRelationshipEdges(behaviors, all)
+		return nil
+	}
+
+	var all []store.Edge
+	for _, b := range behaviors {
+		out, err := graphStore.GetEdges(ctx, b.ID, store.DirectionOutbound, "")
+		if err != nil {
+			return fmt.Errorf("failed to load edges for %s: %w", b.ID, err)
+		}
+		all = append(all, out...)
+	}
+	models.ApplyRelationshipEdges(behaviors, all)
+	return nil
+}
+
 // ComputeBehaviorSimilarity calculates similarity between two behaviors.
 // Delegates to the unified dedup.ComputeSimilarity function.
 // Extracted from cmd/floop/cmd_dedup.go:computeBehaviorSimilarity.
@@ -3,6 +3,7 @@ package edges
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/store"
@@ -117,3 +118,52 @@ func TestComputeBehaviorSimilarity(t *testing.T) {
 		t.Errorf("different behaviors similarity = %.4f, want < 0.5", score2)
 	}
 }
+
+func TestAttachRelationships(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+
+	for _, id := range []string{"a", "b", "c", "d"} {
+		b := models.Behavior{ID: id, Name: id, Kind: models.BehaviorKindDirective}
+		if _, err := s.AddNode(ctx, models.BehaviorToNode(&b)); err != nil {
+			t.Fatalf("AddNode(%s) error = %v", id, err)
+		}
+	}
+	for _, e := range []store.Edge{
+		{Source: "a", Target: "b", Kind: store.EdgeKindRequires, Weight: 1},
+		{Source: "a", Target: "c", Kind: store.EdgeKindOverrides, Weight: 1},
+		{Source: "b", Target: "d", Kind: store.EdgeKindConflicts, Weight: 1},
+		{Source: "c", Target: "d", Kind: store.EdgeKindSpecializes, Weight: 1},
+		{Source: "d", Target: "a", Kind: store.EdgeKindSimilarTo, Weight: 1},
+	} {
+		e.CreatedAt = time.Now()
+		if err := s.AddEdge(ctx, e); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+
+	behaviors, err := LoadBehaviorsFromStore(ctx, s)
+	if err != nil {
+		t.Fatalf("LoadBehaviorsFromStore() error = %v", err)
+	}
+	if err := AttachRelationships(ctx, s, behaviors); err != nil {
+		t.Fatalf("AttachRelationships() error = %v", err)
+	}
+
+	byID := make(map[string]models.Behavior)
+	for _, b := range behaviors {
+		byID[b.ID] = b
+	}
+	if got := byID["a"]; len(got.Requires) != 1 || got.Requires[0] != "b" || len(got.Overrides) != 1 || got.Overrides[0] != "c" {
+		t.Errorf("a: requires=%v overrides=%v, want [b] [c]", got.Requires, got.Overrides)
+	}
+	if got := byID["b"]; len(got.Conflicts) != 1 || got.Conflicts[0] != "d" {
+		t.Errorf("b: conflicts=%v, want [d]", got.Conflicts)
+	}
+	if got := byID["c"]; len(got.Specializes) != 1 || got.Specializes[0] != "d" {
+		t.Errorf("c: specializes=%v, want [d]", got.Specializes)
+	}
+	if got := byID["d"]; len(got.Requires)+len(got.Overrides)+len(got.Conflicts)+len(got.Specializes) != 0 {
+		t.Errorf("d should have no relationships, got %+v", got)
+	}
+}
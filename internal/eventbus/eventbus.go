@@ -0,0 +1,227 @@
+// Package eventbus emits structured events about floop activity, such as
+// behaviors being learned, merged, or forgotten, to configurable sinks: a
+// JSONL audit file, a webhook, or a writer such as stdout.
+package eventbus
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Event types.
+const (
+	// BehaviorLearned is emitted when a correction adds a new behavior.
+	BehaviorLearned = "behavior.learned"
+
+	// BehaviorMerged is emitted when behaviors are merged into one.
+	BehaviorMerged = "behavior.merged"
+
+	// BehaviorForgotten is emitted when a decay pass prunes a behavior and
+	// when a retired behavior's grace period ends without a restore.
+	BehaviorForgotten = "behavior.forgotten"
+
+	// BackupCreated is emitted when a backup of the stores is written.
+	BackupCreated = "backup.created"
+)
+
+// Types lists every event type.
+var Types = []string{BehaviorLearned, BehaviorMerged, BehaviorForgotten, BackupCreated}
+
+// queueSize is how many events may wait for delivery before new ones are
+// dropped.
+const queueSize = 256
+
+// Event is one structured event. Fields that do not apply to its type are
+// omitted.
+type Event struct {
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+
+	// Source is the tool or background task that caused the event, e.g.
+	// floop_learn or retirement-sweep.
+	Source string `json:"source,omitempty"`
+
+	// Project is the ID of the project the server runs in.
+	Project string `json:"project,omitempty"`
+
+	// BehaviorID and BehaviorName identify the behavior the event is about:
+	// the one learned, forgotten, or merged into.
+	BehaviorID   string `json:"behavior_id,omitempty"`
+	BehaviorName string `json:"behavior_name,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+
+	// MergedIDs are the behaviors merged into BehaviorID.
+	MergedIDs []string `json:"merged_ids,omitempty"`
+
+	// Reason explains a merge or forget.
+	Reason string `json:"reason,omitempty"`
+
+	// Path, Nodes, and Edges describe a backup.
+	Path  string `json:"path,omitempty"`
+	Nodes int    `json:"nodes,omitempty"`
+	Edges int    `json:"edges,omitempty"`
+}
+
+// Sink delivers events somewhere. Send is called from a single goroutine.
+type Sink interface {
+	// Send delivers one event.
+	Send(ctx context.Context, e Event) error
+
+	// Close releases the sink's resources.
+	Close() error
+}
+
+// subscription is a sink and the event types it receives (all if empty).
+type subscription struct {
+	name  string
+	sink  Sink
+	types []string
+}
+
+func (s subscription) wants(eventType string) bool {
+	return len(s.types) == 0 || slices.Contains(s.types, eventType)
+}
+
+// Bus fans events out to its sinks in the background, so a slow webhook
+// never delays the caller. It is safe for concurrent use. A nil Bus is
+// safe to use; all methods are no-ops on a nil receiver.
+type Bus struct {
+	subs    []subscription
+	queue   chan Event
+	logger  *slog.Logger
+	done    chan struct{}
+	dropped atomic.Int64
+
+	mu      sync.RWMutex
+	started bool
+	closed  bool
+}
+
+// New returns a bus with no sinks. Add sinks with Subscribe, then Start it.
+// Delivery failures are logged to logger.
+func New(logger *slog.Logger) *Bus {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Bus{
+		queue:  make(chan Event, queueSize),
+		logger: logger,
+		done:   make(chan struct{}),
+	}
+}
+
+// Subscribe adds a sink receiving the given event types, or all of them if
+// none are given. name identifies the sink in log messages. Subscribe must
+// be called before Start.
+func (b *Bus) Subscribe(name string, sink Sink, types ...string) {
+	if b == nil {
+		return
+	}
+	b.subs = append(b.subs, subscription{name: name, sink: sink, types: types})
+}
+
+// Len returns the number of sinks.
+func (b *Bus) Len() int {
+	if b == nil {
+		return 0
+	}
+	return len(b.subs)
+}
+
+// Start begins delivering events.
+func (b *Bus) Start() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.started || b.closed {
+		return
+	}
+	b.started = true
+	go b.deliver()
+}
+
+// deliver sends queued events to their sinks until the queue is closed.
+func (b *Bus) deliver() {
+	defer close(b.done)
+	for e := range b.queue {
+		for _, sub := range b.subs {
+			if !sub.wants(e.Type) {
+				continue
+			}
+			if err := sub.sink.Send(context.Background(), e); err != nil {
+				b.logger.Warn("failed to deliver event", "sink", sub.name, "event", e.Type, "error", err)
+			}
+		}
+	}
+}
+
+// Emit queues e for delivery, stamping its time if unset. When the queue
+// is full, e is dropped and counted rather than blocking the caller.
+func (b *Bus) Emit(e Event) {
+	if b == nil || len(b.subs) == 0 {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return
+	}
+	select {
+	case b.queue <- e:
+	default:
+		if b.dropped.Add(1) == 1 {
+			b.logger.Warn("event queue full, dropping events", "event", e.Type)
+		}
+	}
+}
+
+// Dropped returns the number of events dropped because the queue was full.
+func (b *Bus) Dropped() int64 {
+	if b == nil {
+		return 0
+	}
+	return b.dropped.Load()
+}
+
+// Close delivers the events already queued, waiting up to timeout, then
+// closes the sinks. Events emitted after Close are discarded.
+func (b *Bus) Close(timeout time.Duration) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	close(b.queue)
+	started := b.started
+	b.mu.Unlock()
+
+	if started {
+		select {
+		case <-b.done:
+		case <-time.After(timeout):
+			b.logger.Warn("timed out delivering queued events", "pending", len(b.queue))
+		}
+	}
+
+	var firstErr error
+	for _, sub := range b.subs {
+		if err := sub.sink.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,194 @@
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+)
+
+// recordingSink keeps the events it is sent.
+type recordingSink struct {
+	mu     sync.Mutex
+	events []Event
+	err    error
+	closed bool
+}
+
+func (s *recordingSink) Send(_ context.Context, e Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, e)
+	return s.err
+}
+
+func (s *recordingSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func (s *recordingSink) types() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []string
+	for _, e := range s.events {
+		out = append(out, e.Type)
+	}
+	return out
+}
+
+func TestBus_FiltersAndDelivers(t *testing.T) {
+	all, merges, failing := &recordingSink{}, &recordingSink{}, &recordingSink{err: errors.New("down")}
+	bus := New(nil)
+	bus.Subscribe("all", all)
+	bus.Subscribe("merges", merges, BehaviorMerged)
+	bus.Subscribe("failing", failing)
+	bus.Start()
+
+	bus.Emit(Event{Type: BehaviorLearned, BehaviorID: "b1"})
+	bus.Emit(Event{Type: BehaviorMerged, BehaviorID: "b1", MergedIDs: []string{"b2"}})
+	bus.Emit(Event{Type: BackupCreated, Path: "/tmp/x"})
+	if err := bus.Close(time.Second); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if got := strings.Join(all.types(), ","); got != "behavior.learned,behavior.merged,backup.created" {
+		t.Errorf("all sink got %s", got)
+	}
+	if got := strings.Join(merges.types(), ","); got != "behavior.merged" {
+		t.Errorf("filtered sink got %s", got)
+	}
+	// A failing sink does not stop delivery to the others
+	if len(failing.types()) != 3 || !all.closed || !failing.closed {
+		t.Errorf("failing sink got %v, closed %v %v", failing.types(), all.closed, failing.closed)
+	}
+	if all.events[0].Time.IsZero() {
+		t.Error("Emit() did not stamp the event time")
+	}
+
+	// Events after Close are discarded
+	bus.Emit(Event{Type: BehaviorLearned})
+	if len(all.types()) != 3 {
+		t.Error("event emitted after Close was delivered")
+	}
+}
+
+func TestBus_DropsWhenFull(t *testing.T) {
+	sink := &recordingSink{}
+	bus := New(nil)
+	bus.Subscribe("sink", sink)
+	// Not started: nothing drains the queue
+	for range queueSize + 3 {
+		bus.Emit(Event{Type: BehaviorLearned})
+	}
+	if got := bus.Dropped(); got != 3 {
+		t.Errorf("Dropped() = %d, want 3", got)
+	}
+	if err := bus.Close(time.Second); err != nil || !sink.closed {
+		t.Errorf("Close() error = %v, closed = %v", err, sink.closed)
+	}
+}
+
+func TestBus_Nil(t *testing.T) {
+	var bus *Bus
+	bus.Subscribe("sink", &recordingSink{})
+	bus.Start()
+	bus.Emit(Event{Type: BehaviorLearned})
+	if bus.Len() != 0 || bus.Dropped() != 0 || bus.Close(time.Second) != nil {
+		t.Error("nil Bus is not a no-op")
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "events.jsonl")
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("NewFileSink() error = %v", err)
+	}
+	for _, id := range []string{"a", "b"} {
+		if err := sink.Send(context.Background(), Event{Type: BehaviorForgotten, BehaviorID: id}); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var ids []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", scanner.Text(), err)
+		}
+		ids = append(ids, e.BehaviorID)
+	}
+	if strings.Join(ids, ",") != "a,b" {
+		t.Errorf("file holds %v, want a,b", ids)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("file mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	var sb strings.Builder
+	sink := NewWriterSink(&sb)
+	if err := sink.Send(context.Background(), Event{Type: BackupCreated, Nodes: 3}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if got := sb.String(); !strings.Contains(got, `"type":"backup.created"`) || !strings.HasSuffix(got, "\n") || strings.Contains(got, "behavior_id") {
+		t.Errorf("wrote %q", got)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var got Event
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if got.BehaviorID == "reject" {
+			http.Error(w, "nope", http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	sink := NewWebhookSink(ts.URL, time.Second)
+	defer sink.Close()
+	if err := sink.Send(context.Background(), Event{Type: BehaviorMerged, BehaviorID: "b1", MergedIDs: []string{"b2"}}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if got.Type != BehaviorMerged || len(got.MergedIDs) != 1 {
+		t.Errorf("webhook received %+v", got)
+	}
+	if err := sink.Send(context.Background(), Event{Type: BehaviorMerged, BehaviorID: "reject"}); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Send() error = %v, want the 503", err)
+	}
+}
+
+func TestTypesMatchConfig(t *testing.T) {
+	if strings.Join(Types, ",") != strings.Join(config.EventTypes, ",") {
+		t.Errorf("Types = %v, config.EventTypes = %v", Types, config.EventTypes)
+	}
+}
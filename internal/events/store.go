@@ -61,7 +61,7 @@ func (s *SQLiteEventStore) Add(ctx context.Context, event Event) error {
 	`,
 		event.ID,
 		event.SessionID,
-		event.Timestamp.Format(time.RFC3339Nano),
+		event.Timestamp.UTC().Format(time.RFC3339Nano),
 		event.Source,
 		string(event.Actor),
 		string(event.Kind),
@@ -69,7 +69,7 @@ func (s *SQLiteEventStore) Add(ctx context.Context, event Event) error {
 		metadataJSON,
 		nullString(event.ProjectID),
 		provenanceJSON,
-		event.CreatedAt.Format(time.RFC3339Nano),
+		event.CreatedAt.UTC().Format(time.RFC3339Nano),
 	)
 	if err != nil {
 		return fmt.Errorf("inserting event: %w", err)
@@ -108,7 +108,7 @@ func (s *SQLiteEventStore) AddBatch(ctx context.Context, events []Event) error {
 		_, err = stmt.ExecContext(ctx,
 			event.ID,
 			event.SessionID,
-			event.Timestamp.Format(time.RFC3339Nano),
+			event.Timestamp.UTC().Format(time.RFC3339Nano),
 			event.Source,
 			string(event.Actor),
 			string(event.Kind),
@@ -116,7 +116,7 @@ func (s *SQLiteEventStore) AddBatch(ctx context.Context, events []Event) error {
 			metadataJSON,
 			nullString(event.ProjectID),
 			provenanceJSON,
-			event.CreatedAt.Format(time.RFC3339Nano),
+			event.CreatedAt.UTC().Format(time.RFC3339Nano),
 		)
 		if err != nil {
 			return fmt.Errorf("inserting event %s: %w", event.ID, err)
@@ -170,7 +170,7 @@ func (s *SQLiteEventStore) GetSince(ctx context.Context, since time.Time) ([]Eve
 		FROM events
 		WHERE timestamp >= ? AND consolidated = 0
 		ORDER BY timestamp ASC
-	`, since.Format(time.RFC3339Nano))
+	`, since.UTC().Format(time.RFC3339Nano))
 	if err != nil {
 		return nil, fmt.Errorf("querying events since %v: %w", since, err)
 	}
@@ -223,7 +223,7 @@ func (s *SQLiteEventStore) Prune(ctx context.Context, olderThan time.Duration) (
 	result, err := s.db.ExecContext(ctx, `
 		DELETE FROM events
 		WHERE timestamp < ?
-	`, cutoff.Format(time.RFC3339Nano))
+	`, cutoff.UTC().Format(time.RFC3339Nano))
 	if err != nil {
 		return 0, fmt.Errorf("pruning events: %w", err)
 	}
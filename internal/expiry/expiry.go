@@ -0,0 +1,210 @@
+// Package expiry deprecates time-boxed behaviors once their expires_at passes
+// and records a notice for each so the next digest can report it.
+package expiry
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// NoticesFile holds expiry notices not yet shown in a digest, relative to
+// the .floop directory.
+const NoticesFile = "expiry_notices.jsonl"
+
+// DeprecatedBy is recorded as deprecated_by on behaviors the sweep deprecates.
+const DeprecatedBy = "floop-expiry"
+
+// Notice records a behavior deprecated because its expiry passed.
+type Notice struct {
+	BehaviorID   string    `json:"behavior_id"`
+	Name         string    `json:"name"`
+	Canonical    string    `json:"canonical"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	DeprecatedAt time.Time `json:"deprecated_at"`
+}
+
+// Parse parses an expiry value: an RFC3339 timestamp, a date (YYYY-MM-DD,
+// expiring at the end of that day in UTC, so the stored instant does not
+// depend on the host's timezone), or a duration from now such as 72h or 14d.
+func Parse(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if d, err := time.Parse("2006-01-02", value); err == nil {
+		return d.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return now.AddDate(0, 0, n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid expiry %q (use RFC3339, YYYY-MM-DD, or a duration like 72h or 14d)", value)
+}
+
+// Expired returns the behaviors in graphStore whose expiry is at or before now.
+func Expired(ctx context.Context, graphStore store.GraphStore, now time.Time) ([]models.Behavior, error) {
+	nodes, err := graphStore.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query behaviors: %w", err)
+	}
+
+	var expired []models.Behavior
+	for _, node := range nodes {
+		b := models.NodeToBehavior(node)
+		if b.IsExpired(now) {
+			expired = append(expired, b)
+		}
+	}
+	return expired, nil
+}
+
+// Sweep deprecates every behavior in graphStore whose expiry is at or before
+// now, using the same metadata as 'floop deprecate' so 'floop restore' can
+// undo it. With dryRun set nothing is changed. Returns a notice per expired
+// behavior.
+func Sweep(ctx context.Context, graphStore store.GraphStore, now time.Time, dryRun bool) ([]Notice, error) {
+	expired, err := Expired(ctx, graphStore, now)
+	if err != nil {
+		return nil, err
+	}
+
+	notices := make([]Notice, 0, len(expired))
+	for _, b := range expired {
+		notice := Notice{
+			BehaviorID:   b.ID,
+			Name:         b.Name,
+			Canonical:    b.Content.Canonical,
+			ExpiresAt:    *b.ExpiresAt,
+			DeprecatedAt: now,
+		}
+		if dryRun {
+			notices = append(notices, notice)
+			continue
+		}
+
+		node, err := graphStore.GetNode(ctx, b.ID)
+		if err != nil {
+			return notices, fmt.Errorf("failed to get behavior %s: %w", b.ID, err)
+		}
+		if node == nil || node.Kind != store.NodeKindBehavior {
+			continue
+		}
+
+		if node.Metadata == nil {
+			node.Metadata = make(map[string]interface{})
+		}
+		node.Metadata["original_kind"] = string(node.Kind)
+		node.Metadata["deprecated_at"] = now.UTC().Format(time.RFC3339)
+		node.Metadata["deprecated_by"] = DeprecatedBy
+		node.Metadata["deprecation_reason"] = fmt.Sprintf("expired at %s", b.ExpiresAt.Format(time.RFC3339))
+		node.Kind = store.NodeKindDeprecated
+
+		if err := graphStore.UpdateNode(ctx, *node); err != nil {
+			return notices, fmt.Errorf("failed to deprecate behavior %s: %w", b.ID, err)
+		}
+		notices = append(notices, notice)
+	}
+
+	if !dryRun && len(notices) > 0 {
+		if err := graphStore.Sync(ctx); err != nil {
+			return notices, fmt.Errorf("failed to sync store: %w", err)
+		}
+	}
+	return notices, nil
+}
+
+// RecordNotices appends notices to the pending notices in floopDir.
+func RecordNotices(floopDir string, notices []Notice) error {
+	if len(notices) == 0 {
+		return nil
+	}
+
+	path := filepath.Join(floopDir, NoticesFile)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open expiry notices: %w", err)
+	}
+	defer f.Close()
+
+	encoder := json.NewEncoder(f)
+	for _, n := range notices {
+		if err := encoder.Encode(n); err != nil {
+			return fmt.Errorf("failed to write expiry notice: %w", err)
+		}
+	}
+	return nil
+}
+
+// LoadNotices reads the pending notices in floopDir, oldest first. A missing
+// file yields no notices.
+func LoadNotices(floopDir string) ([]Notice, error) {
+	f, err := os.Open(filepath.Join(floopDir, NoticesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open expiry notices: %w", err)
+	}
+	defer f.Close()
+
+	var notices []Notice
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var n Notice
+		if err := json.Unmarshal(line, &n); err != nil {
+			continue
+		}
+		notices = append(notices, n)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read expiry notices: %w", err)
+	}
+	return notices, nil
+}
+
+// TakeNotices returns the pending notices in floopDir and clears them, so
+// each notice appears in exactly one digest.
+func TakeNotices(floopDir string) ([]Notice, error) {
+	notices, err := LoadNotices(floopDir)
+	if err != nil || len(notices) == 0 {
+		return notices, err
+	}
+	if err := os.Remove(filepath.Join(floopDir, NoticesFile)); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to clear expiry notices: %w", err)
+	}
+	return notices, nil
+}
+
+// FormatDigest renders notices as a short digest section, or "" if there are
+// none.
+func FormatDigest(notices []Notice) string {
+	if len(notices) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Expired behaviors deprecated (%d):\n", len(notices)))
+	for _, n := range notices {
+		sb.WriteString(fmt.Sprintf("- %s (%s, expired %s): %s\n",
+			n.Name, n.BehaviorID, n.ExpiresAt.Format("2006-01-02"), n.Canonical))
+	}
+	sb.WriteString("Run 'floop restore <id>' to bring one back without its expiry.\n")
+	return sb.String()
+}
@@ -0,0 +1,170 @@
+package expiry
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"rfc3339", "2026-06-01T09:00:00Z", time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC), false},
+		{"date is end of day", "2026-06-01", time.Date(2026, 6, 1, 23, 59, 59, 0, time.UTC), false},
+		{"hours", "72h", now.Add(72 * time.Hour), false},
+		{"days", "14d", now.AddDate(0, 0, 14), false},
+		{"zero days", "0d", time.Time{}, true},
+		{"negative duration", "-1h", time.Time{}, true},
+		{"garbage", "next tuesday", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.value, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_DateIgnoresLocalZone(t *testing.T) {
+	orig := time.Local
+	time.Local = time.FixedZone("UTC+10", 10*60*60)
+	defer func() { time.Local = orig }()
+
+	got, err := Parse("2026-06-01", time.Now())
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if want := time.Date(2026, 6, 1, 23, 59, 59, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Parse() = %v, want %v", got, want)
+	}
+}
+
+func addBehavior(t *testing.T, s store.GraphStore, id string, expiresAt *time.Time) {
+	t.Helper()
+	b := models.Behavior{
+		ID:        id,
+		Name:      id,
+		Kind:      models.BehaviorKindDirective,
+		Content:   models.BehaviorContent{Canonical: "content of " + id},
+		ExpiresAt: expiresAt,
+	}
+	if _, err := s.AddNode(context.Background(), models.BehaviorToNode(&b)); err != nil {
+		t.Fatalf("AddNode(%s) error = %v", id, err)
+	}
+}
+
+func TestSweep(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	s := store.NewInMemoryGraphStore()
+	addBehavior(t, s, "expired", &past)
+	addBehavior(t, s, "current", &future)
+	addBehavior(t, s, "forever", nil)
+
+	notices, err := Sweep(ctx, s, now, true)
+	if err != nil {
+		t.Fatalf("Sweep(dryRun) error = %v", err)
+	}
+	if len(notices) != 1 || notices[0].BehaviorID != "expired" {
+		t.Fatalf("Sweep(dryRun) notices = %+v, want only expired", notices)
+	}
+	if node, _ := s.GetNode(ctx, "expired"); node.Kind != store.NodeKindBehavior {
+		t.Fatalf("dry run changed kind to %s", node.Kind)
+	}
+
+	notices, err = Sweep(ctx, s, now, false)
+	if err != nil {
+		t.Fatalf("Sweep() error = %v", err)
+	}
+	if len(notices) != 1 {
+		t.Fatalf("Sweep() notices = %+v, want 1", notices)
+	}
+	if !notices[0].ExpiresAt.Equal(past) {
+		t.Errorf("notice ExpiresAt = %v, want %v", notices[0].ExpiresAt, past)
+	}
+
+	node, _ := s.GetNode(ctx, "expired")
+	if node.Kind != store.NodeKindDeprecated {
+		t.Errorf("expired kind = %s, want deprecated", node.Kind)
+	}
+	if node.Metadata["deprecated_by"] != DeprecatedBy {
+		t.Errorf("deprecated_by = %v, want %s", node.Metadata["deprecated_by"], DeprecatedBy)
+	}
+	if reason, _ := node.Metadata["deprecation_reason"].(string); !strings.HasPrefix(reason, "expired at") {
+		t.Errorf("deprecation_reason = %q, want expiry reason", reason)
+	}
+	for _, id := range []string{"current", "forever"} {
+		if node, _ := s.GetNode(ctx, id); node.Kind != store.NodeKindBehavior {
+			t.Errorf("%s kind = %s, want behavior", id, node.Kind)
+		}
+	}
+
+	// A second sweep finds nothing new
+	notices, err = Sweep(ctx, s, now, false)
+	if err != nil {
+		t.Fatalf("second Sweep() error = %v", err)
+	}
+	if len(notices) != 0 {
+		t.Errorf("second Sweep() notices = %+v, want none", notices)
+	}
+}
+
+func TestNotices_RecordAndTake(t *testing.T) {
+	dir := t.TempDir()
+
+	if got, err := TakeNotices(dir); err != nil || got != nil {
+		t.Fatalf("TakeNotices(empty) = %v, %v; want nil, nil", got, err)
+	}
+
+	notices := []Notice{
+		{BehaviorID: "b1", Name: "v2-migration", Canonical: "run migrations with --v2", ExpiresAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{BehaviorID: "b2", Name: "freeze", Canonical: "no deploys this week"},
+	}
+	if err := RecordNotices(dir, notices[:1]); err != nil {
+		t.Fatalf("RecordNotices() error = %v", err)
+	}
+	if err := RecordNotices(dir, notices[1:]); err != nil {
+		t.Fatalf("RecordNotices() error = %v", err)
+	}
+
+	got, err := TakeNotices(dir)
+	if err != nil {
+		t.Fatalf("TakeNotices() error = %v", err)
+	}
+	if len(got) != 2 || got[0].BehaviorID != "b1" || got[1].BehaviorID != "b2" {
+		t.Fatalf("TakeNotices() = %+v, want b1, b2", got)
+	}
+
+	digest := FormatDigest(got)
+	for _, want := range []string{"(2)", "v2-migration", "b1", "2026-03-01", "floop restore"} {
+		if !strings.Contains(digest, want) {
+			t.Errorf("digest missing %q:\n%s", want, digest)
+		}
+	}
+
+	if got, _ := TakeNotices(dir); len(got) != 0 {
+		t.Errorf("notices should be cleared after TakeNotices, got %+v", got)
+	}
+	if FormatDigest(nil) != "" {
+		t.Error("FormatDigest(nil) should be empty")
+	}
+}
@@ -0,0 +1,169 @@
+// Package graphembed computes structural embeddings of the behavior graph,
+// so users can experiment with ranking models outside floop.
+//
+// Two methods are provided:
+//   - spectral: the leading non-trivial eigenvectors of the normalized
+//     adjacency matrix. Deterministic and fast for small graphs.
+//   - node2vec: skip-gram embeddings trained on biased random walks
+//     (Grover & Leskovec, 2016). Captures neighborhood structure beyond
+//     direct edges.
+//
+// Both treat the graph as undirected, using edge weights as affinities.
+package graphembed
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Method names an embedding algorithm.
+type Method string
+
+const (
+	MethodSpectral Method = "spectral"
+	MethodNode2Vec Method = "node2vec"
+)
+
+// Methods lists the supported methods.
+var Methods = []Method{MethodSpectral, MethodNode2Vec}
+
+// Config controls embedding computation.
+type Config struct {
+	// Method selects the algorithm.
+	Method Method
+
+	// Dimensions is the embedding size.
+	Dimensions int
+
+	// Seed makes random initialization and walks reproducible.
+	Seed uint64
+
+	// node2vec parameters
+	WalksPerNode int     // walks started from each node
+	WalkLength   int     // nodes per walk
+	Window       int     // skip-gram context window
+	Epochs       int     // passes over the walks
+	P            float64 // return parameter: higher = less likely to backtrack
+	Q            float64 // in-out parameter: higher = stay local (BFS-like)
+}
+
+// DefaultConfig returns the default configuration for method.
+func DefaultConfig(method Method) Config {
+	return Config{
+		Method:       method,
+		Dimensions:   16,
+		Seed:         42,
+		WalksPerNode: 10,
+		WalkLength:   20,
+		Window:       5,
+		Epochs:       5,
+		P:            1,
+		Q:            1,
+	}
+}
+
+// Graph is an undirected weighted graph of behaviors, indexed by position
+// in IDs.
+type Graph struct {
+	IDs []string
+	adj []map[int]float64
+}
+
+// NewGraph returns a graph of the given nodes with no edges.
+func NewGraph(ids []string) *Graph {
+	g := &Graph{IDs: ids, adj: make([]map[int]float64, len(ids))}
+	for i := range g.adj {
+		g.adj[i] = make(map[int]float64)
+	}
+	return g
+}
+
+// AddEdge adds weight to the undirected edge between nodes i and j.
+// Self-loops are ignored.
+func (g *Graph) AddEdge(i, j int, weight float64) {
+	if i == j || weight <= 0 {
+		return
+	}
+	g.adj[i][j] += weight
+	g.adj[j][i] += weight
+}
+
+// adjacency returns each node's neighbors in index order with their
+// weights, so iteration (and floating-point summation) order is fixed.
+func (g *Graph) adjacency() ([][]int, [][]float64) {
+	nbrs := make([][]int, len(g.adj))
+	weights := make([][]float64, len(g.adj))
+	for i, m := range g.adj {
+		for j := range m {
+			nbrs[i] = append(nbrs[i], j)
+		}
+		sort.Ints(nbrs[i])
+		weights[i] = make([]float64, len(nbrs[i]))
+		for k, j := range nbrs[i] {
+			weights[i][k] = m[j]
+		}
+	}
+	return nbrs, weights
+}
+
+// Load builds the behavior graph from s. Nodes are sorted by ID; edges to
+// non-behavior nodes are ignored, and edges without a weight count as 1.
+func Load(ctx context.Context, s store.GraphStore) (*Graph, error) {
+	nodes, err := s.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query behaviors: %w", err)
+	}
+	ids := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		ids = append(ids, n.ID)
+	}
+	sort.Strings(ids)
+
+	index := make(map[string]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+	g := NewGraph(ids)
+	for i, id := range ids {
+		edges, err := s.GetEdges(ctx, id, store.DirectionOutbound, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get edges for %s: %w", id, err)
+		}
+		for _, e := range edges {
+			j, ok := index[e.Target]
+			if !ok {
+				continue
+			}
+			weight := e.Weight
+			if weight <= 0 {
+				weight = 1
+			}
+			g.AddEdge(i, j, weight)
+		}
+	}
+	return g, nil
+}
+
+// Embed computes an embedding for every node in g, in the order of g.IDs.
+func Embed(g *Graph, cfg Config) ([][]float64, error) {
+	if cfg.Dimensions <= 0 {
+		return nil, fmt.Errorf("dimensions must be positive, got %d", cfg.Dimensions)
+	}
+	switch cfg.Method {
+	case MethodSpectral:
+		return spectral(g, cfg), nil
+	case MethodNode2Vec:
+		if cfg.WalksPerNode <= 0 || cfg.WalkLength <= 0 || cfg.Window <= 0 || cfg.Epochs <= 0 {
+			return nil, fmt.Errorf("node2vec walks, walk length, window, and epochs must be positive")
+		}
+		if cfg.P <= 0 || cfg.Q <= 0 {
+			return nil, fmt.Errorf("node2vec p and q must be positive")
+		}
+		return node2vec(g, cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown embedding method %q (valid: spectral, node2vec)", cfg.Method)
+	}
+}
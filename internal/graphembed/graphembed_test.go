@@ -0,0 +1,142 @@
+package graphembed
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// twoClusters returns two 4-node cliques joined by a single weak edge.
+func twoClusters() *Graph {
+	g := NewGraph([]string{"a0", "a1", "a2", "a3", "b0", "b1", "b2", "b3"})
+	for _, offset := range []int{0, 4} {
+		for i := range 4 {
+			for j := i + 1; j < 4; j++ {
+				g.AddEdge(offset+i, offset+j, 1)
+			}
+		}
+	}
+	g.AddEdge(3, 4, 0.1)
+	return g
+}
+
+func cosine(a, b []float64) float64 {
+	return dot(a, b) / (math.Sqrt(dot(a, a)) * math.Sqrt(dot(b, b)))
+}
+
+func TestEmbed_SeparatesClusters(t *testing.T) {
+	for _, method := range Methods {
+		t.Run(string(method), func(t *testing.T) {
+			cfg := DefaultConfig(method)
+			cfg.Dimensions = 4
+			vecs, err := Embed(twoClusters(), cfg)
+			if err != nil {
+				t.Fatalf("Embed failed: %v", err)
+			}
+			if len(vecs) != 8 || len(vecs[0]) != 4 {
+				t.Fatalf("got %d vectors of %d dims, want 8 of 4", len(vecs), len(vecs[0]))
+			}
+
+			within := cosine(vecs[0], vecs[1])
+			across := cosine(vecs[0], vecs[5])
+			if within <= across {
+				t.Errorf("within-cluster similarity %.3f <= across-cluster %.3f", within, across)
+			}
+
+			again, _ := Embed(twoClusters(), cfg)
+			if !reflect.DeepEqual(vecs, again) {
+				t.Error("embedding is not deterministic for a fixed seed")
+			}
+		})
+	}
+}
+
+func TestEmbed_SmallGraphs(t *testing.T) {
+	for _, method := range Methods {
+		cfg := DefaultConfig(method)
+		cfg.Dimensions = 3
+
+		vecs, err := Embed(NewGraph(nil), cfg)
+		if err != nil || len(vecs) != 0 {
+			t.Errorf("%s empty graph: got %v, %v", method, vecs, err)
+		}
+
+		// More dimensions than nodes, with an isolated node
+		g := NewGraph([]string{"a", "b", "c"})
+		g.AddEdge(0, 1, 1)
+		vecs, err = Embed(g, cfg)
+		if err != nil {
+			t.Fatalf("%s: Embed failed: %v", method, err)
+		}
+		for i, v := range vecs {
+			for _, x := range v {
+				if math.IsNaN(x) || math.IsInf(x, 0) {
+					t.Errorf("%s: vector %d has non-finite value %v", method, i, v)
+				}
+			}
+		}
+	}
+}
+
+func TestEmbed_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"unknown method", func(c *Config) { c.Method = "pca" }},
+		{"zero dimensions", func(c *Config) { c.Dimensions = 0 }},
+		{"zero walk length", func(c *Config) { c.WalkLength = 0 }},
+		{"zero q", func(c *Config) { c.Q = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig(MethodNode2Vec)
+			tt.modify(&cfg)
+			if _, err := Embed(twoClusters(), cfg); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+	for _, id := range []string{"b", "a", "c"} {
+		if _, err := s.AddNode(ctx, store.Node{ID: id, Kind: store.NodeKindBehavior, Content: map[string]interface{}{"name": id}}); err != nil {
+			t.Fatalf("AddNode failed: %v", err)
+		}
+	}
+	if _, err := s.AddNode(ctx, store.Node{ID: "corr", Kind: store.NodeKindCorrection}); err != nil {
+		t.Fatalf("AddNode failed: %v", err)
+	}
+	now := time.Now()
+	edges := []store.Edge{
+		{Source: "a", Target: "b", Kind: store.EdgeKindSimilarTo, Weight: 0.5, CreatedAt: now},
+		{Source: "b", Target: "a", Kind: store.EdgeKindCoActivated, Weight: 0.25, CreatedAt: now},
+		{Source: "c", Target: "corr", Kind: store.EdgeKindLearnedFrom, Weight: 1, CreatedAt: now},
+	}
+	for _, e := range edges {
+		if err := s.AddEdge(ctx, e); err != nil {
+			t.Fatalf("AddEdge failed: %v", err)
+		}
+	}
+
+	g, err := Load(ctx, s)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !reflect.DeepEqual(g.IDs, []string{"a", "b", "c"}) {
+		t.Errorf("IDs = %v, want sorted behaviors only", g.IDs)
+	}
+	if w := g.adj[0][1]; w != 0.75 {
+		t.Errorf("a-b weight = %v, want both directions summed to 0.75", w)
+	}
+	if len(g.adj[2]) != 0 {
+		t.Errorf("c has edges %v, want none to non-behavior nodes", g.adj[2])
+	}
+}
@@ -0,0 +1,156 @@
+package graphembed
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// Skip-gram training parameters.
+const (
+	negativeSamples = 5
+	learningRate    = 0.025
+	minLearningRate = 0.0001
+	maxExp          = 6.0
+)
+
+// node2vec trains skip-gram embeddings with negative sampling on biased
+// second-order random walks. The walk from t to v moves to x with weight
+// w(v,x)/p when x == t, w(v,x) when x neighbors t, and w(v,x)/q otherwise.
+func node2vec(g *Graph, cfg Config) [][]float64 {
+	n := len(g.IDs)
+	rng := rand.New(rand.NewPCG(cfg.Seed, cfg.Seed))
+	nbrs, weights := g.adjacency()
+
+	in := make([][]float64, n)
+	outv := make([][]float64, n)
+	for i := range n {
+		in[i] = make([]float64, cfg.Dimensions)
+		for d := range in[i] {
+			in[i][d] = (rng.Float64() - 0.5) / float64(cfg.Dimensions)
+		}
+		outv[i] = make([]float64, cfg.Dimensions)
+	}
+
+	walks := make([][]int, 0, n*cfg.WalksPerNode)
+	for range cfg.WalksPerNode {
+		for _, start := range rng.Perm(n) {
+			walks = append(walks, walk(g, nbrs, weights, start, cfg, rng))
+		}
+	}
+
+	// Negative samples follow the unigram distribution of walk visits
+	// raised to 3/4, as in word2vec.
+	counts := make([]float64, n)
+	for _, w := range walks {
+		for _, v := range w {
+			counts[v]++
+		}
+	}
+	cumulative := make([]float64, n)
+	var total float64
+	for i, c := range counts {
+		total += math.Pow(c, 0.75)
+		cumulative[i] = total
+	}
+	sampleNegative := func() int {
+		r := rng.Float64() * total
+		lo, hi := 0, n-1
+		for lo < hi {
+			mid := (lo + hi) / 2
+			if cumulative[mid] < r {
+				lo = mid + 1
+			} else {
+				hi = mid
+			}
+		}
+		return lo
+	}
+
+	steps := cfg.Epochs * len(walks)
+	step := 0
+	grad := make([]float64, cfg.Dimensions)
+	for range cfg.Epochs {
+		for _, w := range walks {
+			lr := max(minLearningRate, learningRate*(1-float64(step)/float64(steps)))
+			step++
+			for pos, center := range w {
+				lo, hi := max(0, pos-cfg.Window), min(len(w)-1, pos+cfg.Window)
+				for c := lo; c <= hi; c++ {
+					if c == pos {
+						continue
+					}
+					clear(grad)
+					ctxNode := w[c]
+					train(in[center], outv[ctxNode], 1, lr, grad)
+					for range negativeSamples {
+						if neg := sampleNegative(); neg != ctxNode {
+							train(in[center], outv[neg], 0, lr, grad)
+						}
+					}
+					for d := range grad {
+						in[center][d] += grad[d]
+					}
+				}
+			}
+		}
+	}
+	return in
+}
+
+// walk returns a biased random walk of up to cfg.WalkLength nodes from start.
+func walk(g *Graph, nbrs [][]int, weights [][]float64, start int, cfg Config, rng *rand.Rand) []int {
+	path := []int{start}
+	for len(path) < cfg.WalkLength {
+		cur := path[len(path)-1]
+		if len(nbrs[cur]) == 0 {
+			break
+		}
+		probs := make([]float64, len(nbrs[cur]))
+		for k, x := range nbrs[cur] {
+			probs[k] = weights[cur][k]
+			if len(path) > 1 {
+				prev := path[len(path)-2]
+				switch {
+				case x == prev:
+					probs[k] /= cfg.P
+				case g.adj[prev][x] == 0:
+					probs[k] /= cfg.Q
+				}
+			}
+		}
+		path = append(path, nbrs[cur][pick(probs, rng)])
+	}
+	return path
+}
+
+// pick returns an index drawn with probability proportional to weights.
+func pick(weights []float64, rng *rand.Rand) int {
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	r := rng.Float64() * total
+	for i, w := range weights {
+		r -= w
+		if r < 0 {
+			return i
+		}
+	}
+	return len(weights) - 1
+}
+
+// train applies one skip-gram update for the pair (center, context) with the
+// given label, accumulating the center gradient in grad.
+func train(center, context []float64, label, lr float64, grad []float64) {
+	f := dot(center, context)
+	if f > maxExp {
+		f = maxExp
+	} else if f < -maxExp {
+		f = -maxExp
+	}
+	g := (label - 1/(1+math.Exp(-f))) * lr
+	for d := range center {
+		grad[d] += g * context[d]
+		context[d] += g * center[d]
+	}
+}
@@ -0,0 +1,116 @@
+package graphembed
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// spectralIterations bounds the subspace iteration.
+const spectralIterations = 200
+
+// spectral embeds nodes with the leading eigenvectors of the lazy normalized
+// adjacency (I + D^-1/2 (A+I) D^-1/2) / 2, skipping the trivial first one.
+// Self-loops keep isolated nodes well defined, and the lazy form keeps every
+// eigenvalue non-negative so subspace iteration converges to the largest.
+// Dimensions beyond what the graph supports are left zero.
+func spectral(g *Graph, cfg Config) [][]float64 {
+	n := len(g.IDs)
+	out := make([][]float64, n)
+	for i := range out {
+		out[i] = make([]float64, cfg.Dimensions)
+	}
+	k := min(cfg.Dimensions+1, n)
+	if k < 2 {
+		return out
+	}
+
+	// invSqrtDeg[i] = 1/sqrt(degree(i) + 1), counting the self-loop
+	nbrs, weights := g.adjacency()
+	invSqrtDeg := make([]float64, n)
+	for i := range n {
+		deg := 1.0
+		for _, w := range weights[i] {
+			deg += w
+		}
+		invSqrtDeg[i] = 1 / math.Sqrt(deg)
+	}
+	multiply := func(x, y []float64) {
+		for i := range n {
+			sum := x[i] * invSqrtDeg[i] // self-loop
+			for k, j := range nbrs[i] {
+				sum += weights[i][k] * x[j] * invSqrtDeg[j]
+			}
+			y[i] = (x[i] + sum*invSqrtDeg[i]) / 2
+		}
+	}
+
+	rng := rand.New(rand.NewPCG(cfg.Seed, cfg.Seed))
+	basis := make([][]float64, k)
+	for c := range basis {
+		basis[c] = make([]float64, n)
+		for i := range basis[c] {
+			basis[c][i] = rng.Float64() - 0.5
+		}
+	}
+	orthonormalize(basis)
+
+	next := make([][]float64, k)
+	for c := range next {
+		next[c] = make([]float64, n)
+	}
+	for range spectralIterations {
+		for c := range basis {
+			multiply(basis[c], next[c])
+		}
+		basis, next = next, basis
+		orthonormalize(basis)
+	}
+
+	for c := 1; c < k; c++ {
+		vec := basis[c]
+		// Fix the sign so output is stable: largest-magnitude entry positive
+		largest := 0
+		for i := range vec {
+			if math.Abs(vec[i]) > math.Abs(vec[largest]) {
+				largest = i
+			}
+		}
+		sign := 1.0
+		if vec[largest] < 0 {
+			sign = -1
+		}
+		for i := range n {
+			out[i][c-1] = sign * vec[i]
+		}
+	}
+	return out
+}
+
+// orthonormalize applies modified Gram-Schmidt to the vectors in place.
+// Vectors that collapse to zero are left zero.
+func orthonormalize(vecs [][]float64) {
+	for c := range vecs {
+		for p := range c {
+			d := dot(vecs[c], vecs[p])
+			for i := range vecs[c] {
+				vecs[c][i] -= d * vecs[p][i]
+			}
+		}
+		norm := math.Sqrt(dot(vecs[c], vecs[c]))
+		if norm < 1e-12 {
+			clear(vecs[c])
+			continue
+		}
+		for i := range vecs[c] {
+			vecs[c][i] /= norm
+		}
+	}
+}
+
+func dot(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
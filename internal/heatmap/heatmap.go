@@ -0,0 +1,208 @@
+// Package heatmap maps where in a repository floop's guidance lands and
+// where the agent gets corrected. Activations are appended to a log in the
+// .floop directory with the file they were for; corrections already carry
+// their file in the corrections log. Build aggregates both per path, so areas
+// that draw corrections but little guidance stand out.
+package heatmap
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// LogFile is the activation log, relative to the .floop directory.
+const LogFile = "activations.jsonl"
+
+// Activation is one activation for a file.
+type Activation struct {
+	File      string    `json:"file"`      // Slash-separated, relative to the repo root
+	Behaviors int       `json:"behaviors"` // Behaviors active for the file
+	At        time.Time `json:"at"`
+}
+
+// RelPath returns path relative to root with forward slashes. It reports
+// false for an empty path or one outside root.
+func RelPath(root, path string) (string, bool) {
+	if path == "" {
+		return "", false
+	}
+	if filepath.IsAbs(path) {
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			return "", false
+		}
+		rel, err := filepath.Rel(absRoot, path)
+		if err != nil {
+			return "", false
+		}
+		path = rel
+	}
+	path = filepath.ToSlash(filepath.Clean(path))
+	if path == "." || path == ".." || strings.HasPrefix(path, "../") {
+		return "", false
+	}
+	return path, true
+}
+
+// RecordActivation appends an activation for file to the log in floopDir.
+// Files outside root are not recorded.
+func RecordActivation(floopDir, root, file string, behaviors int, at time.Time) error {
+	rel, ok := RelPath(root, file)
+	if !ok {
+		return nil
+	}
+	path := filepath.Join(floopDir, LogFile)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open activation log: %w", err)
+	}
+	defer f.Close()
+
+	if err := json.NewEncoder(f).Encode(Activation{File: rel, Behaviors: behaviors, At: at}); err != nil {
+		return fmt.Errorf("failed to write activation: %w", err)
+	}
+	return nil
+}
+
+// LoadActivations reads the activation log in floopDir, oldest first. A
+// missing file yields no activations; malformed lines are skipped.
+func LoadActivations(floopDir string) ([]Activation, error) {
+	f, err := os.Open(filepath.Join(floopDir, LogFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open activation log: %w", err)
+	}
+	defer f.Close()
+
+	var activations []Activation
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var a Activation
+		if err := json.Unmarshal(line, &a); err != nil || a.File == "" {
+			continue
+		}
+		activations = append(activations, a)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read activation log: %w", err)
+	}
+	return activations, nil
+}
+
+// Options controls how Build groups and filters.
+type Options struct {
+	// Depth groups paths by their first Depth components; 0 keeps whole
+	// file paths.
+	Depth int
+	// Since drops activations and corrections before it when non-zero.
+	Since time.Time
+}
+
+// Row is one path's share of guidance and corrections.
+type Row struct {
+	Path        string `json:"path"`
+	Activations int    `json:"activations"`
+	Guidance    int    `json:"guidance"` // Behaviors active, summed over activations
+	Corrections int    `json:"corrections"`
+	// GuidanceDensity and CorrectionDensity are the path's share (0.0-1.0)
+	// of all guidance and all corrections in the report.
+	GuidanceDensity   float64 `json:"guidance_density"`
+	CorrectionDensity float64 `json:"correction_density"`
+	// Gap marks a path that was corrected but never had a behavior active.
+	Gap bool `json:"gap"`
+}
+
+// Group returns the first depth components of a slash-separated path, or
+// the whole path when depth is 0 or the path is shorter.
+func Group(path string, depth int) string {
+	if depth <= 0 {
+		return path
+	}
+	parts := strings.Split(path, "/")
+	if len(parts) <= depth {
+		return path
+	}
+	return strings.Join(parts[:depth], "/")
+}
+
+// Build aggregates activations and corrections per path under root.
+// Corrections without a file, or with one outside root, are left out.
+// Rows are ordered by how far correction density exceeds guidance density,
+// then by corrections and path.
+func Build(root string, activations []Activation, corrections []models.Correction, opts Options) []Row {
+	rows := make(map[string]*Row)
+	row := func(path string) *Row {
+		key := Group(path, opts.Depth)
+		r, ok := rows[key]
+		if !ok {
+			r = &Row{Path: key}
+			rows[key] = r
+		}
+		return r
+	}
+
+	totalGuidance, totalCorrections := 0, 0
+	for _, a := range activations {
+		if !opts.Since.IsZero() && a.At.Before(opts.Since) {
+			continue
+		}
+		rel, ok := RelPath(root, a.File)
+		if !ok {
+			continue
+		}
+		r := row(rel)
+		r.Activations++
+		r.Guidance += a.Behaviors
+		totalGuidance += a.Behaviors
+	}
+	for _, c := range corrections {
+		if !opts.Since.IsZero() && c.Timestamp.Before(opts.Since) {
+			continue
+		}
+		rel, ok := RelPath(root, c.Context.FilePath)
+		if !ok {
+			continue
+		}
+		row(rel).Corrections++
+		totalCorrections++
+	}
+
+	out := make([]Row, 0, len(rows))
+	for _, r := range rows {
+		if totalGuidance > 0 {
+			r.GuidanceDensity = float64(r.Guidance) / float64(totalGuidance)
+		}
+		if totalCorrections > 0 {
+			r.CorrectionDensity = float64(r.Corrections) / float64(totalCorrections)
+		}
+		r.Gap = r.Corrections > 0 && r.Guidance == 0
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		di := out[i].CorrectionDensity - out[i].GuidanceDensity
+		dj := out[j].CorrectionDensity - out[j].GuidanceDensity
+		if di != dj {
+			return di > dj
+		}
+		if out[i].Corrections != out[j].Corrections {
+			return out[i].Corrections > out[j].Corrections
+		}
+		return out[i].Path < out[j].Path
+	})
+	return out
+}
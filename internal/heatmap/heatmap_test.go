@@ -0,0 +1,132 @@
+package heatmap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestRelPath(t *testing.T) {
+	root := t.TempDir()
+	tests := []struct {
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{"", "", false},
+		{"internal/store/file.go", "internal/store/file.go", true},
+		{"./cmd/../main.go", "main.go", true},
+		{filepath.Join(root, "internal", "mcp", "server.go"), "internal/mcp/server.go", true},
+		{filepath.Join(filepath.Dir(root), "elsewhere.go"), "", false},
+		{"../outside.go", "", false},
+		{".", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := RelPath(root, tt.path)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("RelPath(%q) = %q, %v, want %q, %v", tt.path, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestRecordAndLoadActivations(t *testing.T) {
+	root := t.TempDir()
+	floopDir := filepath.Join(root, ".floop")
+	if err := os.MkdirAll(floopDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	if err := RecordActivation(floopDir, root, filepath.Join(root, "cmd", "main.go"), 3, now); err != nil {
+		t.Fatalf("RecordActivation() error = %v", err)
+	}
+	if err := RecordActivation(floopDir, root, "/somewhere/else.go", 1, now); err != nil {
+		t.Fatalf("RecordActivation() outside root error = %v", err)
+	}
+	if err := RecordActivation(floopDir, root, "internal/x.go", 0, now); err != nil {
+		t.Fatalf("RecordActivation() error = %v", err)
+	}
+
+	got, err := LoadActivations(floopDir)
+	if err != nil {
+		t.Fatalf("LoadActivations() error = %v", err)
+	}
+	if len(got) != 2 || got[0].File != "cmd/main.go" || got[0].Behaviors != 3 || got[1].File != "internal/x.go" {
+		t.Errorf("LoadActivations() = %+v, want cmd/main.go and internal/x.go", got)
+	}
+
+	empty, err := LoadActivations(t.TempDir())
+	if err != nil || len(empty) != 0 {
+		t.Errorf("LoadActivations() of a missing log = %v, %v", empty, err)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	tests := []struct {
+		path  string
+		depth int
+		want  string
+	}{
+		{"internal/store/file.go", 0, "internal/store/file.go"},
+		{"internal/store/file.go", 1, "internal"},
+		{"internal/store/file.go", 2, "internal/store"},
+		{"internal/store/file.go", 5, "internal/store/file.go"},
+		{"main.go", 2, "main.go"},
+	}
+	for _, tt := range tests {
+		if got := Group(tt.path, tt.depth); got != tt.want {
+			t.Errorf("Group(%q, %d) = %q, want %q", tt.path, tt.depth, got, tt.want)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	root := t.TempDir()
+	now := time.Now()
+	old := now.Add(-30 * 24 * time.Hour)
+	activations := []Activation{
+		{File: "internal/store/file.go", Behaviors: 4, At: now},
+		{File: "internal/store/sqlite.go", Behaviors: 2, At: now},
+		{File: "cmd/floop/main.go", Behaviors: 2, At: now},
+		{File: "docs/README.md", Behaviors: 0, At: now},
+		{File: "cmd/floop/old.go", Behaviors: 10, At: old},
+	}
+	correction := func(file string, at time.Time) models.Correction {
+		return models.Correction{Timestamp: at, Context: models.ContextSnapshot{FilePath: file}}
+	}
+	corrections := []models.Correction{
+		correction("docs/README.md", now),
+		correction("docs/guide.md", now),
+		correction(filepath.Join(root, "cmd", "floop", "main.go"), now),
+		correction("", now),
+		correction("internal/store/file.go", old),
+	}
+
+	rows := Build(root, activations, corrections, Options{Depth: 1, Since: now.Add(-time.Hour)})
+	if len(rows) != 3 {
+		t.Fatalf("Build() = %+v, want 3 rows", rows)
+	}
+
+	// Corrected but never guided comes first, most guided and never corrected last
+	docs, cmd, internal := rows[0], rows[1], rows[2]
+	if docs.Path != "docs" || cmd.Path != "cmd" || internal.Path != "internal" {
+		t.Fatalf("row order = %s, %s, %s, want docs, cmd, internal", docs.Path, cmd.Path, internal.Path)
+	}
+	if docs.Activations != 1 || docs.Guidance != 0 || docs.Corrections != 2 || !docs.Gap {
+		t.Errorf("docs = %+v, want 1 activation, 2 corrections, gap", docs)
+	}
+	if cmd.Activations != 1 || cmd.Guidance != 2 || cmd.Corrections != 1 || cmd.Gap {
+		t.Errorf("cmd = %+v, want old activation excluded and the absolute-path correction counted", cmd)
+	}
+	if internal.Guidance != 6 || internal.Corrections != 0 || internal.GuidanceDensity != 0.75 || internal.CorrectionDensity != 0 {
+		t.Errorf("internal = %+v, want 6 guidance at 0.75 density and the old correction excluded", internal)
+	}
+
+	files := Build(root, activations, corrections, Options{})
+	if len(files) != 6 {
+		t.Errorf("Build() by file = %d rows, want 6", len(files))
+	}
+}
@@ -0,0 +1,294 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/pack"
+	"github.com/nvandessel/floop/internal/tagging"
+)
+
+// ADRConfidence is the confidence of a behavior imported from an accepted
+// ADR: a reviewed team decision, but not yet seen to hold in practice.
+const ADRConfidence = 0.8
+
+// ADRImporter reads architecture decision records written in Markdown,
+// in the Nygard layout adr-tools generates (Status, Context, Decision,
+// Consequences sections) or in MADR (status in frontmatter or a bullet
+// list, and a Decision Outcome section). Each accepted ADR becomes a
+// behavior whose content is its decision; ADRs that are proposed,
+// rejected, deprecated, or superseded are skipped.
+type ADRImporter struct{}
+
+func init() {
+	Register(ADRImporter{})
+}
+
+// Format returns the importer format name.
+func (ADRImporter) Format() string { return "adr" }
+
+// adrAccepted are the statuses of a decision in force. An ADR without a
+// status counts as accepted.
+var adrAccepted = map[string]bool{
+	"":            true,
+	"accepted":    true,
+	"approved":    true,
+	"adopted":     true,
+	"decided":     true,
+	"implemented": true,
+}
+
+// adrDecisionSections are the headings, lowercased, of the section
+// holding the decision.
+var adrDecisionSections = []string{"decision", "decision outcome", "the decision", "decisions"}
+
+// adrIgnored are files found next to ADRs that are not ADRs.
+var adrIgnored = regexp.MustCompile(`(?i)^(readme|index|template|adr-template|_.*)\.md$`)
+
+// Read reads the ADR file at path, or every .md file under the directory
+// at path.
+func (ADRImporter) Read(path string) (*Batch, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading ADRs: %w", err)
+	}
+	files := []string{filepath.Base(path)}
+	root := filepath.Dir(path)
+	if info.IsDir() {
+		root, files = path, nil
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !strings.EqualFold(filepath.Ext(p), ".md") || adrIgnored.MatchString(d.Name()) {
+				return nil
+			}
+			rel, err := filepath.Rel(path, p)
+			if err != nil {
+				return err
+			}
+			files = append(files, rel)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("reading ADRs in %s: %w", path, err)
+		}
+		sort.Strings(files)
+	}
+
+	dict := tagging.NewDictionary()
+	batch := &Batch{}
+	for _, rel := range files {
+		data, err := os.ReadFile(filepath.Join(root, rel))
+		if err != nil {
+			return nil, fmt.Errorf("reading ADR %s: %w", rel, err)
+		}
+		source := filepath.ToSlash(filepath.Join(root, rel))
+		adr := parseADR(data)
+		switch {
+		case adr.Title == "":
+			batch.Skipped = append(batch.Skipped, Skipped{File: source, Reason: "no title heading"})
+		case adr.Decision == "":
+			batch.Skipped = append(batch.Skipped, Skipped{File: source, Reason: "no Decision section"})
+		case !adrAccepted[adr.Status]:
+			batch.Skipped = append(batch.Skipped, Skipped{File: source, Reason: "status " + adr.Status})
+		default:
+			batch.Behaviors = append(batch.Behaviors, adr.behavior(rel, source, dict))
+		}
+	}
+	return batch, nil
+}
+
+// adr is the part of an ADR the importer uses.
+type adr struct {
+	Title    string
+	Status   string // First word of the status, lowercased
+	Date     time.Time
+	Decision string
+}
+
+// adrFrontmatter is the MADR frontmatter.
+type adrFrontmatter struct {
+	Status string `yaml:"status"`
+	Date   string `yaml:"date"`
+}
+
+var (
+	// adrNumber matches the numbering a title may start with: "5.",
+	// "ADR-0005:", "0005 -".
+	adrNumber = regexp.MustCompile(`(?i)^(adr[-\s]?)?\d+(\s*[.:)-]\s*|\s+)`)
+
+	// adrField matches a "Status: accepted" or "* Date: 2024-01-05" line
+	// before the first section.
+	adrField = regexp.MustCompile(`(?i)^[*-]?\s*(status|date)\s*:\s*(.+)$`)
+)
+
+// parseADR extracts the title, status, date, and decision of an ADR.
+func parseADR(data []byte) adr {
+	var (
+		a        adr
+		fm       adrFrontmatter
+		status   string
+		date     string
+		section  string
+		fenced   bool
+		sections = map[string][]string{}
+	)
+
+	body := data
+	if rest, ok := bytes.CutPrefix(data, []byte("---\n")); ok {
+		if end := bytes.Index(rest, []byte("\n---")); end >= 0 {
+			// Malformed frontmatter just leaves status and date to the body
+			_ = yaml.Unmarshal(rest[:end], &fm)
+			body = rest[end+len("\n---"):]
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+		}
+		switch {
+		case fenced:
+		case strings.HasPrefix(line, "# ") && a.Title == "":
+			a.Title = adrNumber.ReplaceAllString(strings.TrimSpace(line[2:]), "")
+			continue
+		case strings.HasPrefix(line, "## "):
+			section = strings.ToLower(strings.TrimSpace(strings.TrimRight(line[3:], ":")))
+			continue
+		case section == "":
+			if m := adrField.FindStringSubmatch(trimmed); m != nil {
+				if strings.EqualFold(m[1], "status") {
+					status = m[2]
+				} else {
+					date = m[2]
+				}
+			}
+			continue
+		}
+		sections[section] = append(sections[section], line)
+	}
+
+	if s := firstLine(sections["status"]); s != "" {
+		status = s
+	}
+	if fm.Status != "" {
+		status = fm.Status
+	}
+	if fm.Date != "" {
+		date = fm.Date
+	}
+	a.Status = statusWord(status)
+	if t, err := time.Parse("2006-01-02", strings.TrimSpace(date)); err == nil {
+		a.Date = t
+	}
+	for _, name := range adrDecisionSections {
+		if text := strings.TrimSpace(strings.Join(sections[name], "\n")); text != "" {
+			a.Decision = text
+			break
+		}
+	}
+	return a
+}
+
+// firstLine returns the first non-blank line of lines, trimmed.
+func firstLine(lines []string) string {
+	for _, l := range lines {
+		if l = strings.TrimSpace(l); l != "" {
+			return l
+		}
+	}
+	return ""
+}
+
+// statusWord reduces a status such as "**Superseded** by ADR-7" to
+// "superseded".
+func statusWord(status string) string {
+	fields := strings.Fields(strings.ToLower(status))
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.TrimFunc(fields[0], func(r rune) bool { return r < 'a' || r > 'z' })
+}
+
+// Kind signals in a decision, checked in order.
+var (
+	adrConstraintSignals = []string{"must not", "mustn't", "shall not", "never", "do not", "don't", "forbidden", "prohibited", "not allowed"}
+	adrPreferenceSignals = []string{"prefer", "instead of", "rather than", "in favor of"}
+)
+
+// kind infers the behavior kind of a decision: a constraint when it rules
+// something out, a preference when it picks one option over another, and
+// otherwise a directive.
+func (a adr) kind() models.BehaviorKind {
+	text := strings.ToLower(a.Decision)
+	for _, s := range adrConstraintSignals {
+		if strings.Contains(text, s) {
+			return models.BehaviorKindConstraint
+		}
+	}
+	for _, s := range adrPreferenceSignals {
+		if strings.Contains(text, s) {
+			return models.BehaviorKindPreference
+		}
+	}
+	return models.BehaviorKindDirective
+}
+
+// behavior builds the behavior for the ADR read from file rel, reported
+// as source.
+func (a adr) behavior(rel, source string, dict *tagging.Dictionary) pack.MarkdownBehavior {
+	base := slug(strings.TrimSuffix(filepath.Base(rel), filepath.Ext(rel)))
+	id := base
+	if !strings.HasPrefix(id, "adr-") {
+		id = "adr-" + id
+	}
+	createdAt := a.Date
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	tags := tagging.MergeTags(tagging.ExtractTags(a.Title+" "+a.Decision, dict), []string{"adr"}, dict)
+	return pack.MarkdownBehavior{
+		ID:         id,
+		Name:       slug(a.Title),
+		Kind:       a.kind(),
+		Summary:    a.Title,
+		Tags:       tags,
+		Confidence: ADRConfidence,
+		Canonical:  a.Decision,
+		File:       rel,
+		Provenance: models.Provenance{
+			SourceType: models.SourceTypeImported,
+			CreatedAt:  createdAt,
+			Importer:   ADRImporter{}.Format(),
+			SourcePath: source,
+		},
+	}
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slug lowercases s and joins its words with dashes, keeping at most 60
+// characters.
+func slug(s string) string {
+	s = strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	if len(s) > 60 {
+		s = strings.TrimRight(s[:60], "-")
+	}
+	return s
+}
@@ -0,0 +1,219 @@
+package importer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/pack"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func writeADR(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseADR(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want adr
+	}{
+		{
+			name: "nygard",
+			in: `# 5. Use PostgreSQL for persistence
+
+Date: 2024-03-01
+
+## Status
+
+Accepted
+
+## Context
+
+We need a relational store.
+
+## Decision
+
+We will use PostgreSQL for all service data.
+
+` + "```sql\n## not a heading\n```" + `
+
+## Consequences
+
+Ops must run PostgreSQL.
+`,
+			want: adr{
+				Title:    "Use PostgreSQL for persistence",
+				Status:   "accepted",
+				Date:     mustDate(t, "2024-03-01"),
+				Decision: "We will use PostgreSQL for all service data.\n\n```sql\n## not a heading\n```",
+			},
+		},
+		{
+			name: "madr frontmatter",
+			in: `---
+status: superseded by ADR-0009
+date: 2023-11-20
+---
+# ADR-0007: Log in JSON
+
+## Context and Problem Statement
+
+Logs are hard to search.
+
+## Decision Outcome
+
+Chosen option: structured JSON logs.
+`,
+			want: adr{
+				Title:    "Log in JSON",
+				Status:   "superseded",
+				Date:     mustDate(t, "2023-11-20"),
+				Decision: "Chosen option: structured JSON logs.",
+			},
+		},
+		{
+			name: "madr bullet status",
+			in: `# 2FA for admin accounts
+
+* Status: **Proposed**
+* Deciders: security
+
+## Decision Outcome
+
+Admins must not sign in without a second factor.
+`,
+			want: adr{
+				Title:    "2FA for admin accounts",
+				Status:   "proposed",
+				Decision: "Admins must not sign in without a second factor.",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseADR([]byte(tt.in)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseADR() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func mustDate(t *testing.T, s string) time.Time {
+	t.Helper()
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestADRKind(t *testing.T) {
+	tests := []struct {
+		decision string
+		want     models.BehaviorKind
+	}{
+		{"Services must not share databases.", models.BehaviorKindConstraint},
+		{"Never call the billing API synchronously.", models.BehaviorKindConstraint},
+		{"We will use gRPC instead of REST between services.", models.BehaviorKindPreference},
+		{"All services expose a /healthz endpoint.", models.BehaviorKindDirective},
+	}
+	for _, tt := range tests {
+		if got := (adr{Decision: tt.decision}).kind(); got != tt.want {
+			t.Errorf("kind(%q) = %s, want %s", tt.decision, got, tt.want)
+		}
+	}
+}
+
+func TestADRImporter_Read(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "adr")
+	writeADR(t, dir, "README.md", "# Decision log\n")
+	writeADR(t, dir, "0001-record-architecture-decisions.md", "# 1. Record architecture decisions\n\n## Status\n\nAccepted\n\n## Decision\n\nWe will keep ADRs in docs/adr.\n")
+	writeADR(t, dir, "0002-use-mongodb.md", "# 2. Use MongoDB\n\n## Status\n\nRejected\n\n## Decision\n\nUse MongoDB.\n")
+	writeADR(t, dir, "0003-notes.md", "# 3. Notes\n\nJust notes.\n")
+	writeADR(t, filepath.Join(dir, "api"), "0004-versioned-apis.md", "# 4. Versioned APIs\n\nDate: 2024-05-02\n\n## Decision\n\nNever break a published API version.\n")
+
+	batch, err := ADRImporter{}.Read(dir)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	var ids []string
+	for _, b := range batch.Behaviors {
+		ids = append(ids, b.ID)
+	}
+	if want := []string{"adr-0001-record-architecture-decisions", "adr-0004-versioned-apis"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("behaviors = %v, want %v", ids, want)
+	}
+	wantSkipped := []Skipped{
+		{File: filepath.ToSlash(filepath.Join(dir, "0002-use-mongodb.md")), Reason: "status rejected"},
+		{File: filepath.ToSlash(filepath.Join(dir, "0003-notes.md")), Reason: "no Decision section"},
+	}
+	if !reflect.DeepEqual(batch.Skipped, wantSkipped) {
+		t.Errorf("skipped = %+v, want %+v", batch.Skipped, wantSkipped)
+	}
+
+	b := batch.Behaviors[1]
+	if b.Kind != models.BehaviorKindConstraint || b.Name != "versioned-apis" || b.Summary != "Versioned APIs" ||
+		b.Canonical != "Never break a published API version." || b.Confidence != ADRConfidence {
+		t.Errorf("behavior = %+v", b)
+	}
+	p := b.Provenance
+	if p.SourceType != models.SourceTypeImported || p.Importer != "adr" ||
+		p.SourcePath != filepath.ToSlash(filepath.Join(dir, "api", "0004-versioned-apis.md")) ||
+		!p.CreatedAt.Equal(mustDate(t, "2024-05-02")) {
+		t.Errorf("provenance = %+v", p)
+	}
+
+	single, err := ADRImporter{}.Read(filepath.Join(dir, "api", "0004-versioned-apis.md"))
+	if err != nil || len(single.Behaviors) != 1 || single.Behaviors[0].ID != "adr-0004-versioned-apis" {
+		t.Errorf("Read(file) = %+v, %v; want the one ADR", single, err)
+	}
+	if _, err := (ADRImporter{}).Read(filepath.Join(dir, "missing")); err == nil {
+		t.Error("Read() of a missing path should fail")
+	}
+}
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeADR(t, dir, "0001-wrap-errors.md", "# 1. Wrap errors\n\n## Decision\n\nWrap errors with context before returning them.\n")
+
+	imp, ok := Get("adr")
+	if !ok {
+		t.Fatalf("adr importer not registered; formats = %v", Formats())
+	}
+	s := store.NewInMemoryGraphStore()
+	result, err := Import(ctx, s, imp, dir, pack.MarkdownImportOptions{})
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if !reflect.DeepEqual(result.Added, []string{"adr-0001-wrap-errors"}) || len(result.Skipped) != 0 {
+		t.Fatalf("Import() = %+v", result)
+	}
+	node, _ := s.GetNode(ctx, "adr-0001-wrap-errors")
+	if node == nil {
+		t.Fatal("behavior was not added")
+	}
+	if b := models.NodeToBehavior(*node); b.Provenance.Importer != "adr" || b.Content.Canonical != "Wrap errors with context before returning them." {
+		t.Errorf("stored behavior = %+v", b)
+	}
+
+	again, err := Import(ctx, s, imp, dir, pack.MarkdownImportOptions{})
+	if err != nil {
+		t.Fatalf("Import() again error = %v", err)
+	}
+	if len(again.Added) != 0 || !reflect.DeepEqual(again.Existing, []string{"adr-0001-wrap-errors"}) {
+		t.Errorf("second Import() = %+v, want the ADR already present", again)
+	}
+}
@@ -0,0 +1,89 @@
+// Package importer turns external sources of institutional knowledge, such
+// as architecture decision records, into behaviors, so a project's graph
+// starts from what the team has already written down instead of empty.
+//
+// Each source format has an Importer that reads files into behaviors with
+// kinds and provenance. Import then adds them to a store the way 'floop
+// import' adds a Markdown pack: behaviors already present or similar to an
+// existing one are left out.
+package importer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/nvandessel/floop/internal/pack"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Importer reads behaviors from one external source format.
+type Importer interface {
+	// Format is the name the importer is selected by, e.g. "adr".
+	Format() string
+
+	// Read reads the file or directory at path.
+	Read(path string) (*Batch, error)
+}
+
+// Batch is what an Importer read: the behaviors found, and the files it
+// passed over with the reason.
+type Batch struct {
+	Behaviors []pack.MarkdownBehavior
+	Skipped   []Skipped
+}
+
+// Skipped is a source file an importer did not turn into a behavior.
+type Skipped struct {
+	File   string `json:"file"`
+	Reason string `json:"reason"`
+}
+
+// importers is the global importer registry. Registration must happen
+// during init() — concurrent registration is not supported.
+var importers = map[string]Importer{}
+
+// Register registers an Importer by its format name.
+func Register(i Importer) {
+	importers[i.Format()] = i
+}
+
+// Get returns the importer registered for the given format, if any.
+func Get(format string) (Importer, bool) {
+	i, ok := importers[format]
+	return i, ok
+}
+
+// Formats returns the names of all registered importer formats.
+func Formats() []string {
+	formats := make([]string, 0, len(importers))
+	for f := range importers {
+		formats = append(formats, f)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// Result reports what Import did, or would do.
+type Result struct {
+	*pack.MarkdownImportResult
+	Skipped []Skipped `json:"skipped"`
+}
+
+// Import reads path with imp and adds the behaviors found to s, with the
+// deduplication of pack.ImportMarkdown.
+func Import(ctx context.Context, s store.GraphStore, imp Importer, path string, opts pack.MarkdownImportOptions) (*Result, error) {
+	batch, err := imp.Read(path)
+	if err != nil {
+		return nil, err
+	}
+	imported, err := pack.ImportMarkdown(ctx, s, &pack.MarkdownPack{Behaviors: batch.Behaviors}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("importing %s: %w", imp.Format(), err)
+	}
+	skipped := batch.Skipped
+	if skipped == nil {
+		skipped = []Skipped{}
+	}
+	return &Result{MarkdownImportResult: imported, Skipped: skipped}, nil
+}
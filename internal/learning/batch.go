@@ -0,0 +1,163 @@
+package learning
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// BatchEntry is one wrong/right pair submitted in a batch, as read from a
+// JSONL transcript by 'floop learn --from-file'.
+type BatchEntry struct {
+	Wrong     string   `json:"wrong,omitempty"`
+	Right     string   `json:"right"`
+	File      string   `json:"file,omitempty"`
+	Task      string   `json:"task,omitempty"`
+	Language  string   `json:"language,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	When      []string `json:"when,omitempty"`
+	ExpiresAt string   `json:"expires_at,omitempty"`
+}
+
+// ReadBatchEntries reads one BatchEntry per line. Blank lines are skipped;
+// a line that is not valid JSON or has no "right" fails the whole read so a
+// malformed transcript changes nothing.
+func ReadBatchEntries(r io.Reader) ([]BatchEntry, error) {
+	var entries []BatchEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var e BatchEntry
+		if err := json.Unmarshal([]byte(text), &e); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if strings.TrimSpace(e.Right) == "" {
+			return nil, fmt.Errorf("line %d: \"right\" is required", line)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading batch: %w", err)
+	}
+	return entries, nil
+}
+
+// Batch item statuses reported by ProcessBatch.
+const (
+	BatchAccepted     = "accepted"      // Learned and auto-accepted
+	BatchLearned      = "learned"       // Learned below the auto-accept threshold
+	BatchReview       = "review"        // Learned and queued for review
+	BatchMerged       = "merged"        // Merged into an existing behavior
+	BatchQuarantined  = "quarantined"   // Stored quarantined as a possible prompt injection
+	BatchDuplicate    = "duplicate"     // Repeats an earlier item in the batch
+	BatchHeld         = "held"          // Held by the quality gate
+	BatchLimitReached = "limit_reached" // Not learned: a storage limit was reached
+	BatchError        = "error"         // Rejected or failed
+)
+
+// BatchStatuses lists the batch item statuses in reporting order.
+var BatchStatuses = []string{
+	BatchAccepted, BatchLearned, BatchReview, BatchMerged, BatchQuarantined,
+	BatchDuplicate, BatchHeld, BatchLimitReached, BatchError,
+}
+
+// BatchItemResult reports what the learning loop did with one batch item.
+type BatchItemResult struct {
+	Index       int               // Position in the batch
+	Status      string            // One of the Batch* statuses
+	Correction  models.Correction // The correction as processed
+	Result      *LearningResult   // Nil for duplicate and error items
+	DuplicateOf int               // Index of the repeated item when Status is BatchDuplicate, else -1
+	Err         error             // Set when Status is BatchError
+}
+
+// ProcessBatch runs corrections through loop in order. An item repeating an
+// earlier item's correction in the same context is reported as a duplicate
+// without being processed; later items that are merely similar are merged
+// by the loop's deduplicator like any other correction. Each remaining item
+// must pass breaker, which may be nil. Per-item failures are reported, not
+// returned, so one bad item does not stop the rest.
+func ProcessBatch(ctx context.Context, loop LearningLoop, corrections []models.Correction, breaker *CircuitBreaker) []BatchItemResult {
+	results := make([]BatchItemResult, len(corrections))
+	seen := make(map[string]int, len(corrections))
+	for i, c := range corrections {
+		results[i] = BatchItemResult{Index: i, Correction: c, DuplicateOf: -1}
+
+		key := batchKey(c)
+		if first, ok := seen[key]; ok {
+			results[i].Status = BatchDuplicate
+			results[i].DuplicateOf = first
+			continue
+		}
+		seen[key] = i
+
+		if err := ctx.Err(); err != nil {
+			results[i].Status = BatchError
+			results[i].Err = err
+			continue
+		}
+		if err := breaker.Allow(c.CorrectedAction); err != nil {
+			results[i].Status = BatchError
+			results[i].Err = err
+			continue
+		}
+
+		result, err := loop.ProcessCorrection(ctx, c)
+		if err != nil {
+			results[i].Status = BatchError
+			results[i].Err = fmt.Errorf("failed to process correction: %w", err)
+			continue
+		}
+		results[i].Result = result
+		results[i].Status = batchStatus(result)
+	}
+	return results
+}
+
+// batchStatus classifies a learning result.
+func batchStatus(r *LearningResult) string {
+	switch {
+	case r.Held:
+		return BatchHeld
+	case r.Quota != nil:
+		return BatchLimitReached
+	case r.MergedIntoExisting:
+		return BatchMerged
+	case r.Injection != nil:
+		return BatchQuarantined
+	case r.RequiresReview:
+		return BatchReview
+	case r.AutoAccepted:
+		return BatchAccepted
+	default:
+		return BatchLearned
+	}
+}
+
+// batchKey identifies corrections that say the same thing in the same
+// context: the corrected action with case and spacing folded, plus the
+// language, file, and task.
+func batchKey(c models.Correction) string {
+	right := strings.Join(strings.Fields(strings.ToLower(c.CorrectedAction)), " ")
+	return strings.Join([]string{right, c.Context.FileLanguage, c.Context.FilePath, c.Context.Task}, "\x00")
+}
+
+// BatchCounts tallies batch results by status.
+func BatchCounts(results []BatchItemResult) map[string]int {
+	counts := make(map[string]int)
+	for _, r := range results {
+		counts[r.Status]++
+	}
+	return counts
+}
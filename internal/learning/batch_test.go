@@ -0,0 +1,127 @@
+package learning
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestReadBatchEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    int
+		wantErr string
+	}{
+		{"empty", "", 0, ""},
+		{"blank lines skipped", "\n{\"right\":\"use uv\"}\n\n{\"wrong\":\"pip\",\"right\":\"use uv for installs\"}\n", 2, ""},
+		{"invalid json", "{\"right\":\"use uv\"}\nnot json\n", 0, "line 2"},
+		{"missing right", "{\"wrong\":\"pip\"}\n", 0, "line 1: \"right\" is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := ReadBatchEntries(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ReadBatchEntries() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadBatchEntries() error = %v", err)
+			}
+			if len(entries) != tt.want {
+				t.Errorf("got %d entries, want %d", len(entries), tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessBatch(t *testing.T) {
+	s := store.NewInMemoryGraphStore()
+	loop := NewLearningLoop(s, nil)
+	ctx := context.Background()
+
+	now := time.Now()
+	correction := func(id, right, lang string) models.Correction {
+		return models.Correction{
+			ID:              id,
+			Timestamp:       now,
+			CorrectedAction: right,
+			Context:         models.ContextSnapshot{Timestamp: now, FileLanguage: lang},
+		}
+	}
+	corrections := []models.Correction{
+		correction("c-0", "use uv instead of pip for package management", "python"),
+		correction("c-1", "Use uv  instead of pip for package management", "python"),
+		correction("c-2", "use uv instead of pip for package management", "go"),
+		correction("c-3", "wrap errors with fmt.Errorf and %w", "go"),
+	}
+
+	results := ProcessBatch(ctx, loop, corrections, nil)
+	if len(results) != len(corrections) {
+		t.Fatalf("got %d results, want %d", len(results), len(corrections))
+	}
+
+	if results[1].Status != BatchDuplicate || results[1].DuplicateOf != 0 || results[1].Result != nil {
+		t.Errorf("results[1] = %+v, want duplicate of item 0", results[1])
+	}
+	for _, i := range []int{0, 2, 3} {
+		r := results[i]
+		if r.Status == BatchDuplicate || r.Status == BatchError {
+			t.Errorf("results[%d].Status = %s, err = %v", i, r.Status, r.Err)
+		}
+		if r.Result == nil || r.DuplicateOf != -1 || r.Index != i {
+			t.Errorf("results[%d] = %+v", i, r)
+		}
+	}
+
+	counts := BatchCounts(results)
+	if counts[BatchDuplicate] != 1 {
+		t.Errorf("counts = %v, want 1 duplicate", counts)
+	}
+}
+
+func TestProcessBatch_Errors(t *testing.T) {
+	s := store.NewInMemoryGraphStore()
+	loop := NewLearningLoop(s, nil)
+
+	now := time.Now()
+	corrections := []models.Correction{
+		{ID: "c-0", Timestamp: now, CorrectedAction: "use uv for python packages"},
+		{ID: "c-1", Timestamp: now, CorrectedAction: "wrap errors with %w"},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i, r := range ProcessBatch(ctx, loop, corrections, nil) {
+		if r.Status != BatchError || r.Err == nil {
+			t.Errorf("results[%d] = %+v, want error for canceled context", i, r)
+		}
+	}
+}
+
+func TestBatchStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		result LearningResult
+		want   string
+	}{
+		{"held", LearningResult{Held: true}, BatchHeld},
+		{"merged", LearningResult{MergedIntoExisting: true, RequiresReview: true}, BatchMerged},
+		{"review", LearningResult{RequiresReview: true}, BatchReview},
+		{"accepted", LearningResult{AutoAccepted: true}, BatchAccepted},
+		{"learned", LearningResult{}, BatchLearned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := batchStatus(&tt.result); got != tt.want {
+				t.Errorf("batchStatus() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package learning
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/nvandessel/floop/internal/similarity"
+)
+
+// BreakerConfig configures a CircuitBreaker.
+type BreakerConfig struct {
+	MaxPerSession int           // Learn calls allowed per session (0 = unlimited)
+	Window        int           // Recent corrections compared with each new one
+	Similarity    float64       // Content similarity at which two corrections repeat each other
+	MaxRepeats    int           // Repeats within the window that trip the breaker (0 = never)
+	Cooldown      time.Duration // How long a tripped breaker rejects learn calls
+}
+
+// Reasons a CircuitBreaker rejects a correction.
+const (
+	BreakerSessionCap = "session_cap"
+	BreakerRepetitive = "repetitive"
+	BreakerCoolingOff = "cooling_off"
+)
+
+// BreakerError is returned when the circuit breaker rejects a correction.
+// It tells the agent to stop learning rather than retry.
+type BreakerError struct {
+	Reason     string        // One of the Breaker* reasons
+	Detail     string        // What tripped the breaker
+	RetryAfter time.Duration // Time until learn calls are accepted again (0 = not this session)
+}
+
+func (e *BreakerError) Error() string {
+	msg := fmt.Sprintf("learning circuit breaker open (%s): %s; stop calling floop_learn", e.Reason, e.Detail)
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s for %.0fs", msg, e.RetryAfterSeconds())
+	}
+	return msg + " for the rest of this session"
+}
+
+// RetryAfterSeconds returns RetryAfter in whole seconds, rounded up.
+func (e *BreakerError) RetryAfterSeconds() float64 {
+	return math.Ceil(e.RetryAfter.Seconds())
+}
+
+// CircuitBreaker guards the learning loop against a tight agent loop
+// submitting the same correction over and over, which can flood the store
+// when each copy lands just under the dedup threshold. It caps learn calls
+// per session and, when a correction repeats too many of the recent ones,
+// rejects all learn calls for a cool-down period. It is safe for concurrent
+// use.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	cfg       BreakerConfig
+	learned   int
+	recent    []string
+	openUntil time.Time
+	nowFunc   func() time.Time
+}
+
+// NewCircuitBreaker creates a circuit breaker for one session.
+func NewCircuitBreaker(cfg BreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{cfg: cfg, nowFunc: time.Now}
+}
+
+// Allow records an attempt to learn correction and returns a *BreakerError
+// if the breaker rejects it. A nil CircuitBreaker allows everything.
+func (b *CircuitBreaker) Allow(correction string) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.nowFunc()
+	if now.Before(b.openUntil) {
+		return &BreakerError{
+			Reason:     BreakerCoolingOff,
+			Detail:     "repetitive corrections were submitted",
+			RetryAfter: b.openUntil.Sub(now),
+		}
+	}
+
+	if b.cfg.MaxPerSession > 0 && b.learned >= b.cfg.MaxPerSession {
+		return &BreakerError{
+			Reason: BreakerSessionCap,
+			Detail: fmt.Sprintf("%d corrections already learned this session", b.learned),
+		}
+	}
+
+	if b.cfg.MaxRepeats > 0 {
+		repeats := 0
+		for _, prev := range b.recent {
+			if similarity.ComputeContentSimilarity(prev, correction) >= b.cfg.Similarity {
+				repeats++
+			}
+		}
+		if repeats >= b.cfg.MaxRepeats {
+			window := len(b.recent)
+			b.openUntil = now.Add(b.cfg.Cooldown)
+			b.recent = nil
+			return &BreakerError{
+				Reason:     BreakerRepetitive,
+				Detail:     fmt.Sprintf("correction repeats %d of the last %d", repeats, window),
+				RetryAfter: b.cfg.Cooldown,
+			}
+		}
+	}
+
+	b.learned++
+	if b.cfg.Window > 0 {
+		b.recent = append(b.recent, correction)
+		if len(b.recent) > b.cfg.Window {
+			b.recent = b.recent[len(b.recent)-b.cfg.Window:]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package learning
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cfg := BreakerConfig{MaxPerSession: 6, Window: 4, Similarity: 0.8, MaxRepeats: 2, Cooldown: 10 * time.Minute}
+
+	tests := []struct {
+		name        string
+		corrections []string
+		advance     time.Duration
+		wantReasons []string // "" = allowed
+	}{
+		{
+			name:        "distinct corrections",
+			corrections: []string{"wrap errors with context", "use pathlib for paths", "prefer table driven tests"},
+			wantReasons: []string{"", "", ""},
+		},
+		{
+			name:        "repetition trips and cools off",
+			corrections: []string{"always run the linter", "always run the linter first", "always run the linter", "use pathlib for paths"},
+			wantReasons: []string{"", "", BreakerRepetitive, BreakerCoolingOff},
+		},
+		{
+			name:        "cool-down expires",
+			corrections: []string{"always run the linter", "always run the linter", "always run the linter", "always run the linter"},
+			advance:     11 * time.Minute,
+			wantReasons: []string{"", "", BreakerRepetitive, ""},
+		},
+		{
+			name:        "session cap",
+			corrections: []string{"a one", "b two", "c three", "d four", "e five", "f six", "g seven"},
+			wantReasons: []string{"", "", "", "", "", "", BreakerSessionCap},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := now
+			b := NewCircuitBreaker(cfg)
+			b.nowFunc = func() time.Time { return clock }
+			for i, c := range tt.corrections {
+				if i == len(tt.corrections)-1 {
+					clock = clock.Add(tt.advance)
+				}
+				err := b.Allow(c)
+				var breakerErr *BreakerError
+				got := ""
+				if errors.As(err, &breakerErr) {
+					got = breakerErr.Reason
+				} else if err != nil {
+					t.Fatalf("Allow(%q) error = %v", c, err)
+				}
+				if got != tt.wantReasons[i] {
+					t.Errorf("Allow(%q) reason = %q, want %q", c, got, tt.wantReasons[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker_Nil(t *testing.T) {
+	var b *CircuitBreaker
+	if err := b.Allow("anything"); err != nil {
+		t.Errorf("nil breaker Allow() = %v, want nil", err)
+	}
+}
+
+func TestBreakerError(t *testing.T) {
+	err := &BreakerError{Reason: BreakerRepetitive, Detail: "correction repeats 3 of the last 5", RetryAfter: 90500 * time.Millisecond}
+	if got := err.RetryAfterSeconds(); got != 91 {
+		t.Errorf("RetryAfterSeconds() = %v, want 91", got)
+	}
+	want := "learning circuit breaker open (repetitive): correction repeats 3 of the last 5; stop calling floop_learn for 91s"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+	capErr := &BreakerError{Reason: BreakerSessionCap, Detail: "50 corrections already learned this session"}
+	if got := capErr.Error(); got != "learning circuit breaker open (session_cap): 50 corrections already learned this session; stop calling floop_learn for the rest of this session" {
+		t.Errorf("Error() = %q", got)
+	}
+}
@@ -61,8 +61,11 @@ func (e *behaviorExtractor) Extract(correction models.Correction) (*models.Behav
 	// Generate content-addressed ID
 	id := e.generateID(correction)
 
-	// Infer the 'when' predicate from context
+	// Infer the 'when' predicate from context, then apply user-provided conditions
 	when := e.inferWhen(correction.Context)
+	for key, value := range correction.ExtraWhen {
+		when[key] = value
+	}
 
 	// Determine behavior kind
 	kind := e.inferKind(correction)
@@ -75,6 +78,11 @@ func (e *behaviorExtractor) Extract(correction models.Correction) (*models.Behav
 		SourceType:   models.SourceTypeLearned,
 		CreatedAt:    time.Now(),
 		CorrectionID: correction.ID,
+		SourceAgent:  correction.Context.Agent,
+	}
+	if correction.FailureID != "" {
+		provenance.SourceType = models.SourceTypeFailure
+		provenance.CorrectionID = correction.FailureID
 	}
 
 	// Generate a human-readable name
@@ -89,6 +97,7 @@ func (e *behaviorExtractor) Extract(correction models.Correction) (*models.Behav
 		Provenance: provenance,
 		Confidence: constants.DefaultLearnedConfidence,
 		Priority:   0,
+		ExpiresAt:  correction.ExpiresAt,
 		Stats: models.BehaviorStats{
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
@@ -786,3 +786,23 @@ func TestBehaviorExtractor_Extract_Stats(t *testing.T) {
 		t.Errorf("Stats.TimesOverridden = %d, want 0", behavior.Stats.TimesOverridden)
 	}
 }
+
+func TestBehaviorExtractor_Extract_ExtraWhen(t *testing.T) {
+	extractor := NewBehaviorExtractor()
+
+	behavior, err := extractor.Extract(models.Correction{
+		ID:              "c-freeze",
+		CorrectedAction: "do not bump dependencies during the release freeze",
+		Context:         models.ContextSnapshot{FileLanguage: "go"},
+		ExtraWhen:       map[string]interface{}{models.WhenBefore: "2026-06-01"},
+	})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if behavior.When["before"] != "2026-06-01" {
+		t.Errorf("When[before] = %v, want 2026-06-01", behavior.When["before"])
+	}
+	if behavior.When["language"] != "go" {
+		t.Errorf("inferred When[language] = %v, want go", behavior.When["language"])
+	}
+}
@@ -0,0 +1,232 @@
+package learning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/sanitize"
+	"github.com/nvandessel/floop/internal/tagging"
+)
+
+// Extractor names accepted by the learning.extractor setting.
+const (
+	ExtractorRules = "rules"
+	ExtractorLLM   = "llm"
+)
+
+// Extractor is the extraction step of the learning loop: it turns a
+// correction into a candidate behavior. Unlike BehaviorExtractor it takes a
+// context, since implementations may call out to an LLM.
+type Extractor interface {
+	Extract(ctx context.Context, correction models.Correction) (*models.Behavior, error)
+}
+
+// NewExtractor returns the extractor named by learning.extractor. "llm"
+// needs a client; without one, and for any other name, the rule-based
+// extractor is used.
+func NewExtractor(name string, client llm.Client) Extractor {
+	if name == ExtractorLLM && client != nil {
+		return NewLLMExtractor(client)
+	}
+	return NewRuleExtractor()
+}
+
+// NewRuleExtractor returns the rule-based BehaviorExtractor as an Extractor.
+func NewRuleExtractor() Extractor {
+	return ruleExtractor{rules: NewBehaviorExtractor()}
+}
+
+type ruleExtractor struct {
+	rules BehaviorExtractor
+}
+
+// Extract implements Extractor.
+func (e ruleExtractor) Extract(_ context.Context, correction models.Correction) (*models.Behavior, error) {
+	return e.rules.Extract(correction)
+}
+
+// NewLLMExtractor returns an extractor that asks an LLM to generalize the
+// correction into a reusable instruction and to propose its when-conditions,
+// kind, and tags. The rule-based extraction is the starting point: ID, name,
+// and provenance always come from it, and it is returned unchanged when the
+// client is unavailable or its answer cannot be used.
+func NewLLMExtractor(client llm.Client) Extractor {
+	return &llmExtractor{
+		client:  client,
+		rules:   NewBehaviorExtractor(),
+		tagDict: tagging.NewDictionary(),
+	}
+}
+
+type llmExtractor struct {
+	client  llm.Client
+	rules   BehaviorExtractor
+	tagDict *tagging.Dictionary
+}
+
+// Extract implements Extractor.
+func (e *llmExtractor) Extract(ctx context.Context, correction models.Correction) (*models.Behavior, error) {
+	behavior, err := e.rules.Extract(correction)
+	if err != nil {
+		return nil, err
+	}
+	if e.client == nil || !e.client.Available() {
+		return behavior, nil
+	}
+
+	response, err := e.client.Complete(ctx, []llm.Message{
+		{Role: "user", Content: BehaviorExtractionPrompt(correction)},
+	})
+	if err != nil {
+		return behavior, nil
+	}
+	proposal, err := ParseBehaviorExtractionResponse(response)
+	if err != nil {
+		return behavior, nil
+	}
+
+	canonical := sanitize.SanitizeBehaviorContent(proposal.Canonical)
+	if canonical == "" {
+		return behavior, nil
+	}
+	behavior.Content.Canonical = canonical
+	behavior.Content.Structured["prefer"] = canonical
+
+	inferred := tagging.MergeTags(tagging.ExtractTags(canonical, e.tagDict), proposal.Tags, e.tagDict)
+	behavior.Content.Tags = tagging.MergeTags(inferred, correction.ExtraTags, e.tagDict)
+
+	if proposal.Kind != "" {
+		behavior.Kind = proposal.Kind
+	}
+
+	// An explicit empty "when" means the instruction applies everywhere;
+	// user-provided conditions always win
+	if proposal.When != nil {
+		behavior.When = proposal.When
+		for key, value := range correction.ExtraWhen {
+			behavior.When[key] = value
+		}
+	}
+
+	return behavior, nil
+}
+
+// ExtractionProposal is an LLM's proposed generalization of a correction.
+type ExtractionProposal struct {
+	Canonical string
+	Kind      models.BehaviorKind
+	When      map[string]interface{}
+	Tags      []string
+}
+
+// llmWhenKeys are the when-conditions an LLM may propose. Other keys are
+// dropped: they would rarely match anything the activation context carries.
+var llmWhenKeys = map[string]bool{
+	"language":  true,
+	"file_path": true,
+	"task":      true,
+}
+
+// BehaviorExtractionPrompt generates a prompt asking an LLM to turn a
+// correction into a reusable behavior.
+func BehaviorExtractionPrompt(correction models.Correction) string {
+	var prompt strings.Builder
+
+	prompt.WriteString("You are turning a correction a user gave to an AI coding agent into a reusable instruction for future sessions. Generalize it: state the rule behind the correction, not the one-off fix, in one or two imperative sentences. Keep project-specific names the rule depends on.\n\n")
+	if correction.AgentAction != "" {
+		prompt.WriteString("## What the agent did\n")
+		prompt.WriteString(correction.AgentAction)
+		prompt.WriteString("\n\n")
+	}
+	prompt.WriteString("## What the user said to do instead\n")
+	prompt.WriteString(correction.CorrectedAction)
+	prompt.WriteString("\n\n## Context\n")
+	fmt.Fprintf(&prompt, "- File: %s\n", valueOrNone(correction.Context.FilePath))
+	fmt.Fprintf(&prompt, "- Language: %s\n", valueOrNone(correction.Context.FileLanguage))
+	fmt.Fprintf(&prompt, "- Task: %s\n", valueOrNone(correction.Context.Task))
+
+	tasks := make([]string, 0, len(constants.KnownTasks))
+	for task := range constants.KnownTasks {
+		tasks = append(tasks, task)
+	}
+	sort.Strings(tasks)
+	fmt.Fprintf(&prompt, `
+## Response Format
+Respond with ONLY a JSON object (no markdown code blocks, no additional text):
+{
+  "canonical": "<the generalized instruction>",
+  "kind": "<directive|constraint|preference|procedure>",
+  "when": {<conditions under which the instruction applies; {} if always>},
+  "tags": ["<up to 5 short lowercase topic tags>"]
+}
+
+"when" may use only these keys:
+- "language": a language name such as "go" or "python"
+- "file_path": a glob such as "cmd/**" or {"glob": "**/*_test.go"}
+- "task": one of %s
+Leave out any condition the correction does not clearly depend on.`, strings.Join(tasks, ", "))
+	return prompt.String()
+}
+
+// ParseBehaviorExtractionResponse parses an LLM extraction proposal. Unknown
+// kinds are ignored, and when-conditions outside language, file_path, and
+// task, or that do not validate, are dropped.
+func ParseBehaviorExtractionResponse(response string) (*ExtractionProposal, error) {
+	jsonStr := llm.ExtractJSON(response)
+	if jsonStr == "" {
+		return nil, fmt.Errorf("no JSON found in response")
+	}
+
+	var result struct {
+		Canonical string                 `json:"canonical"`
+		Kind      string                 `json:"kind"`
+		When      map[string]interface{} `json:"when"`
+		Tags      []string               `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+		return nil, fmt.Errorf("parsing extraction: %w", err)
+	}
+	if strings.TrimSpace(result.Canonical) == "" {
+		return nil, fmt.Errorf("extraction has no canonical text")
+	}
+
+	proposal := &ExtractionProposal{
+		Canonical: strings.TrimSpace(result.Canonical),
+		Tags:      result.Tags,
+	}
+	switch kind := models.BehaviorKind(result.Kind); kind {
+	case models.BehaviorKindDirective, models.BehaviorKindConstraint,
+		models.BehaviorKindPreference, models.BehaviorKindProcedure:
+		proposal.Kind = kind
+	}
+	if result.When != nil {
+		proposal.When = make(map[string]interface{})
+		for key, value := range result.When {
+			if !llmWhenKeys[key] {
+				continue
+			}
+			if task, ok := value.(string); key == "task" && (!ok || !constants.KnownTasks[task]) {
+				continue
+			}
+			if models.ValidateWhen(map[string]interface{}{key: value}) != nil {
+				continue
+			}
+			proposal.When[key] = value
+		}
+	}
+	return proposal, nil
+}
+
+// valueOrNone returns s, or "(none)" if it is empty.
+func valueOrNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
@@ -0,0 +1,135 @@
+package learning
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestNewExtractor(t *testing.T) {
+	client := llm.NewMockClient()
+	if _, ok := NewExtractor(ExtractorLLM, client).(*llmExtractor); !ok {
+		t.Error("NewExtractor(llm, client) should return the LLM extractor")
+	}
+	if _, ok := NewExtractor(ExtractorLLM, nil).(ruleExtractor); !ok {
+		t.Error("NewExtractor(llm, nil) should fall back to rules")
+	}
+	if _, ok := NewExtractor(ExtractorRules, client).(ruleExtractor); !ok {
+		t.Error("NewExtractor(rules, client) should return the rule extractor")
+	}
+}
+
+func TestLLMExtractor(t *testing.T) {
+	correction := models.Correction{
+		ID:              "c-1",
+		AgentAction:     "ran pip install requests",
+		CorrectedAction: "no, use uv add requests",
+		Context:         models.ContextSnapshot{FilePath: "scripts/fetch.py", FileLanguage: "python"},
+		ExtraTags:       []string{"tooling"},
+		ExtraWhen:       map[string]interface{}{"branch": "main"},
+	}
+	rules, err := NewBehaviorExtractor().Extract(correction)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("applies the proposal", func(t *testing.T) {
+		client := llm.NewMockClient().WithCompleteResponse("```json\n" + `{
+			"canonical": "Manage Python dependencies with uv (uv add), not pip",
+			"kind": "preference",
+			"when": {"language": "python", "task": "inventing", "os": "linux"},
+			"tags": ["python", "dependencies"]
+		}` + "\n```")
+		b, err := NewLLMExtractor(client).Extract(context.Background(), correction)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.Content.Canonical != "Manage Python dependencies with uv (uv add), not pip" || b.Content.Structured["prefer"] != b.Content.Canonical {
+			t.Errorf("Content = %+v, want the generalized instruction", b.Content)
+		}
+		if b.Kind != models.BehaviorKindPreference {
+			t.Errorf("Kind = %q, want preference", b.Kind)
+		}
+		want := map[string]interface{}{"language": "python", "branch": "main"}
+		if !reflect.DeepEqual(b.When, want) {
+			t.Errorf("When = %v, want %v (unknown keys and tasks dropped, user conditions kept)", b.When, want)
+		}
+		for _, tag := range []string{"python", "dependencies", "tooling"} {
+			if !strings.Contains(strings.Join(b.Content.Tags, ","), tag) {
+				t.Errorf("Tags = %v, missing %q", b.Content.Tags, tag)
+			}
+		}
+		if b.ID != rules.ID || b.Name != rules.Name || b.Provenance.CorrectionID != "c-1" {
+			t.Errorf("ID/Name/Provenance = %s/%s/%+v, want the rule-based ones", b.ID, b.Name, b.Provenance)
+		}
+		if client.CompleteCallCount() != 1 || !strings.Contains(client.CompleteCalls[0].Messages[0].Content, "ran pip install requests") {
+			t.Errorf("Complete calls = %+v, want one prompt with the correction", client.CompleteCalls)
+		}
+	})
+
+	t.Run("empty when applies everywhere", func(t *testing.T) {
+		client := llm.NewMockClient().WithCompleteResponse(`{"canonical": "Use uv for Python packages", "when": {}}`)
+		b, err := NewLLMExtractor(client).Extract(context.Background(), correction)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(b.When, map[string]interface{}{"branch": "main"}) {
+			t.Errorf("When = %v, want only the user condition", b.When)
+		}
+		if b.Kind != rules.Kind {
+			t.Errorf("Kind = %q, want the rule-based %q when none is proposed", b.Kind, rules.Kind)
+		}
+	})
+
+	fallbacks := []struct {
+		name   string
+		client *llm.MockClient
+	}{
+		{"unavailable", llm.NewMockClient().WithAvailable(false).WithCompleteResponse(`{"canonical": "x"}`)},
+		{"error", llm.NewMockClient().WithError(errors.New("boom"))},
+		{"not json", llm.NewMockClient().WithCompleteResponse("Sure! Use uv.")},
+		{"no canonical", llm.NewMockClient().WithCompleteResponse(`{"canonical": " ", "kind": "constraint"}`)},
+	}
+	for _, tt := range fallbacks {
+		t.Run("falls back to rules when "+tt.name, func(t *testing.T) {
+			b, err := NewLLMExtractor(tt.client).Extract(context.Background(), correction)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b.Provenance.CreatedAt, b.Stats = rules.Provenance.CreatedAt, rules.Stats
+			if !reflect.DeepEqual(b, rules) {
+				t.Errorf("Extract() = %+v, want the rule-based behavior %+v", b, rules)
+			}
+		})
+	}
+}
+
+func TestParseBehaviorExtractionResponse(t *testing.T) {
+	p, err := ParseBehaviorExtractionResponse(`{"canonical": " Wrap errors with %w ", "kind": "episodic", "when": {"file_path": {"glob": "internal/**"}, "language": {"regex": "("}, "task": "testing"}, "tags": ["go"]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Canonical != "Wrap errors with %w" || p.Kind != "" {
+		t.Errorf("proposal = %+v, want trimmed canonical and unsupported kind ignored", p)
+	}
+	want := map[string]interface{}{"file_path": map[string]interface{}{"glob": "internal/**"}, "task": "testing"}
+	if !reflect.DeepEqual(p.When, want) {
+		t.Errorf("When = %v, want %v (invalid regex dropped)", p.When, want)
+	}
+
+	p, err = ParseBehaviorExtractionResponse(`{"canonical": "x"}`)
+	if err != nil || p.When != nil {
+		t.Errorf("missing when = %v, %v, want nil so rule conditions are kept", p, err)
+	}
+
+	for _, bad := range []string{"", "no json", `{"kind": "directive"}`} {
+		if _, err := ParseBehaviorExtractionResponse(bad); err == nil {
+			t.Errorf("ParseBehaviorExtractionResponse(%q) should fail", bad)
+		}
+	}
+}
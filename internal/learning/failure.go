@@ -0,0 +1,153 @@
+package learning
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// NewFailure builds a pending failure report. The ID is a content-addressed
+// hash of the kind, command, error, and fix, so reporting the same failure
+// twice yields the same ID.
+func NewFailure(kind models.FailureKind, command, action, errText, fix string, ctx models.ContextSnapshot) *models.Failure {
+	hash := sha256.Sum256([]byte(strings.Join([]string{string(kind), command, errText, fix}, "|")))
+	return &models.Failure{
+		ID:        "failure-" + hex.EncodeToString(hash[:])[:12],
+		Timestamp: time.Now(),
+		Context:   ctx,
+		Kind:      kind,
+		Command:   command,
+		Action:    action,
+		Error:     errText,
+		Fix:       fix,
+		Status:    models.FailureStatusPending,
+	}
+}
+
+// RecordFailure adds a failure to the review queue in the local store.
+// If a failure with the same ID is already recorded it is left unchanged and
+// returned with duplicate set.
+func RecordFailure(ctx context.Context, s store.GraphStore, f *models.Failure) (recorded *models.Failure, duplicate bool, err error) {
+	existing, err := GetFailure(ctx, s, f.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return existing, true, nil
+	}
+
+	node, err := models.FailureToNode(f)
+	if err != nil {
+		return nil, false, err
+	}
+	if scoped, ok := s.(ScopedNodeAdder); ok {
+		_, err = scoped.AddNodeToScope(ctx, node, constants.ScopeLocal)
+	} else {
+		_, err = s.AddNode(ctx, node)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to record failure: %w", err)
+	}
+	return f, false, nil
+}
+
+// GetFailure returns the failure with the given ID, or nil if there is none.
+func GetFailure(ctx context.Context, s store.GraphStore, id string) (*models.Failure, error) {
+	node, err := s.GetNode(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get failure %s: %w", id, err)
+	}
+	if node == nil || node.Kind != store.NodeKindFailure {
+		return nil, nil
+	}
+	f, err := models.NodeToFailure(*node)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// ListFailures returns recorded failures with the given status, oldest
+// first. An empty status returns every failure.
+func ListFailures(ctx context.Context, s store.GraphStore, status models.FailureStatus) ([]models.Failure, error) {
+	nodes, err := s.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindFailure)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query failures: %w", err)
+	}
+
+	var failures []models.Failure
+	for _, node := range nodes {
+		f, err := models.NodeToFailure(node)
+		if err != nil {
+			continue
+		}
+		if status == "" || f.Status == status {
+			failures = append(failures, f)
+		}
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		if !failures[i].Timestamp.Equal(failures[j].Timestamp) {
+			return failures[i].Timestamp.Before(failures[j].Timestamp)
+		}
+		return failures[i].ID < failures[j].ID
+	})
+	return failures, nil
+}
+
+// LearnFailure extracts a behavior from a pending failure through loop and
+// marks the failure learned. When the correction is held or a storage limit
+// is reached, the failure stays pending and the result explains why.
+func LearnFailure(ctx context.Context, s store.GraphStore, loop LearningLoop, f *models.Failure) (*LearningResult, error) {
+	if f.Status != models.FailureStatusPending {
+		return nil, fmt.Errorf("failure %s is already %s", f.ID, f.Status)
+	}
+
+	result, err := loop.ProcessCorrection(ctx, f.Correction())
+	if err != nil {
+		return nil, fmt.Errorf("failed to learn from failure %s: %w", f.ID, err)
+	}
+	if result.Held || result.Quota != nil {
+		return result, nil
+	}
+
+	f.BehaviorID = result.CandidateBehavior.ID
+	if result.MergedIntoExisting {
+		f.BehaviorID = result.MergedBehaviorID
+	}
+	if err := reviewFailure(ctx, s, f, models.FailureStatusLearned); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DismissFailure removes a pending failure from the review queue without
+// learning from it.
+func DismissFailure(ctx context.Context, s store.GraphStore, f *models.Failure) error {
+	if f.Status != models.FailureStatusPending {
+		return fmt.Errorf("failure %s is already %s", f.ID, f.Status)
+	}
+	return reviewFailure(ctx, s, f, models.FailureStatusDismissed)
+}
+
+func reviewFailure(ctx context.Context, s store.GraphStore, f *models.Failure, status models.FailureStatus) error {
+	now := time.Now()
+	f.Status = status
+	f.ReviewedAt = &now
+
+	node, err := models.FailureToNode(f)
+	if err != nil {
+		return err
+	}
+	if err := s.UpdateNode(ctx, node); err != nil {
+		return fmt.Errorf("failed to update failure %s: %w", f.ID, err)
+	}
+	return nil
+}
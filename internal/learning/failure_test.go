@@ -0,0 +1,104 @@
+package learning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestNewFailure_ContentAddressedID(t *testing.T) {
+	a := NewFailure(models.FailureKindTest, "go test ./...", "", "FAIL: TestX", "run go generate before testing", models.ContextSnapshot{})
+	b := NewFailure(models.FailureKindTest, "go test ./...", "edited parser", "FAIL: TestX", "run go generate before testing", models.ContextSnapshot{})
+	c := NewFailure(models.FailureKindBuild, "go test ./...", "", "FAIL: TestX", "run go generate before testing", models.ContextSnapshot{})
+
+	if a.ID != b.ID {
+		t.Errorf("IDs differ by action only: %s vs %s", a.ID, b.ID)
+	}
+	if a.ID == c.ID {
+		t.Errorf("IDs should differ by kind, both %s", a.ID)
+	}
+	if a.Status != models.FailureStatusPending {
+		t.Errorf("Status = %s, want pending", a.Status)
+	}
+}
+
+func TestFailureQueue_RecordLearnDismiss(t *testing.T) {
+	ctx := context.Background()
+	s, err := store.NewSQLiteGraphStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	defer s.Close()
+
+	learnable := NewFailure(models.FailureKindBuild, "go build ./...", "renamed a package",
+		"undefined: config.Load", "update every import after renaming a package",
+		models.ContextSnapshot{FileLanguage: "go", FilePath: "cmd/main.go"})
+	noise := NewFailure(models.FailureKindLint, "golangci-lint run", "", "line too long", "wrap long lines", models.ContextSnapshot{})
+
+	for _, f := range []*models.Failure{learnable, noise} {
+		if _, duplicate, err := RecordFailure(ctx, s, f); err != nil || duplicate {
+			t.Fatalf("RecordFailure(%s) = duplicate %v, err %v", f.ID, duplicate, err)
+		}
+	}
+	if _, duplicate, err := RecordFailure(ctx, s, learnable); err != nil || !duplicate {
+		t.Errorf("re-recording = duplicate %v, err %v; want duplicate", duplicate, err)
+	}
+
+	pending, err := ListFailures(ctx, s, models.FailureStatusPending)
+	if err != nil {
+		t.Fatalf("ListFailures() error = %v", err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("pending = %d, want 2", len(pending))
+	}
+
+	got, err := GetFailure(ctx, s, learnable.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetFailure() = %v, %v", got, err)
+	}
+	if got.Fix != learnable.Fix || got.Kind != models.FailureKindBuild || got.Context.FilePath != "cmd/main.go" {
+		t.Errorf("GetFailure() = %+v, want round-tripped fields", got)
+	}
+
+	result, err := LearnFailure(ctx, s, NewLearningLoop(s, nil), got)
+	if err != nil {
+		t.Fatalf("LearnFailure() error = %v", err)
+	}
+	b := result.CandidateBehavior
+	if b.Provenance.SourceType != models.SourceTypeFailure || b.Provenance.CorrectionID != learnable.ID {
+		t.Errorf("provenance = %+v, want failure source for %s", b.Provenance, learnable.ID)
+	}
+	if b.Content.Canonical == "" {
+		t.Error("learned behavior should have content")
+	}
+
+	learned, _ := GetFailure(ctx, s, learnable.ID)
+	if learned.Status != models.FailureStatusLearned || learned.BehaviorID != b.ID || learned.ReviewedAt == nil {
+		t.Errorf("learned failure = %+v, want learned with behavior %s", learned, b.ID)
+	}
+	if _, err := LearnFailure(ctx, s, NewLearningLoop(s, nil), learned); err == nil {
+		t.Error("learning a reviewed failure should fail")
+	}
+
+	if err := DismissFailure(ctx, s, &pending[1]); err != nil {
+		t.Fatalf("DismissFailure() error = %v", err)
+	}
+	pending, _ = ListFailures(ctx, s, models.FailureStatusPending)
+	if len(pending) != 0 {
+		t.Errorf("pending after review = %d, want 0", len(pending))
+	}
+	all, _ := ListFailures(ctx, s, "")
+	if len(all) != 2 {
+		t.Errorf("all failures = %d, want 2", len(all))
+	}
+
+	// Failures are not behaviors
+	behaviors, _ := s.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	for _, n := range behaviors {
+		if n.ID == learnable.ID || n.ID == noise.ID {
+			t.Errorf("failure %s listed as a behavior", n.ID)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package learning
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// HeldCorrectionsFile is the holding area for corrections rejected by the
+// quality gate, relative to the .floop directory.
+const HeldCorrectionsFile = "held_corrections.jsonl"
+
+// HeldCorrection is a correction that scored below the quality threshold and
+// was held instead of being extracted into a behavior.
+type HeldCorrection struct {
+	Correction models.Correction `json:"correction"`
+	Quality    QualityScore      `json:"quality"`
+	MinScore   float64           `json:"min_score"`
+	HeldAt     time.Time         `json:"held_at"`
+}
+
+// HeldStats summarizes the holding area.
+type HeldStats struct {
+	Total        int            `json:"total"`
+	AverageScore float64        `json:"average_score"`
+	ByReason     map[string]int `json:"by_reason"`
+}
+
+// HoldCorrection appends a held correction to the holding area in floopDir.
+func HoldCorrection(floopDir string, held HeldCorrection) error {
+	path := filepath.Join(floopDir, HeldCorrectionsFile)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open held corrections: %w", err)
+	}
+	defer f.Close()
+
+	if err := json.NewEncoder(f).Encode(held); err != nil {
+		return fmt.Errorf("failed to write held correction: %w", err)
+	}
+	return nil
+}
+
+// LoadHeldCorrections reads the holding area in floopDir, oldest first.
+// A missing file yields no corrections.
+func LoadHeldCorrections(floopDir string) ([]HeldCorrection, error) {
+	f, err := os.Open(filepath.Join(floopDir, HeldCorrectionsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open held corrections: %w", err)
+	}
+	defer f.Close()
+
+	var held []HeldCorrection
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var h HeldCorrection
+		if err := json.Unmarshal(line, &h); err != nil {
+			continue
+		}
+		held = append(held, h)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read held corrections: %w", err)
+	}
+	return held, nil
+}
+
+// RemoveHeldCorrection removes the correction with the given ID from the
+// holding area and returns it. Returns nil if no such correction is held.
+func RemoveHeldCorrection(floopDir, correctionID string) (*HeldCorrection, error) {
+	held, err := LoadHeldCorrections(floopDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed *HeldCorrection
+	kept := make([]HeldCorrection, 0, len(held))
+	for i := range held {
+		if removed == nil && held[i].Correction.ID == correctionID {
+			removed = &held[i]
+			continue
+		}
+		kept = append(kept, held[i])
+	}
+	if removed == nil {
+		return nil, nil
+	}
+
+	path := filepath.Join(floopDir, HeldCorrectionsFile)
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create held corrections: %w", err)
+	}
+	encoder := json.NewEncoder(f)
+	for _, h := range kept {
+		if err := encoder.Encode(h); err != nil {
+			f.Close()
+			os.Remove(tmp)
+			return nil, fmt.Errorf("failed to write held correction: %w", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("failed to close held corrections: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("failed to replace held corrections: %w", err)
+	}
+	return removed, nil
+}
+
+// SummarizeHeld computes reject statistics for held corrections.
+func SummarizeHeld(held []HeldCorrection) HeldStats {
+	stats := HeldStats{Total: len(held), ByReason: make(map[string]int)}
+	if len(held) == 0 {
+		return stats
+	}
+
+	var total float64
+	for _, h := range held {
+		total += h.Quality.Score
+		for _, reason := range h.Quality.Reasons {
+			stats.ByReason[reason]++
+		}
+	}
+	stats.AverageScore = total / float64(len(held))
+	return stats
+}
+
+// SortedReasons returns the reasons in stats ordered by count, then name.
+func (s HeldStats) SortedReasons() []string {
+	reasons := make([]string, 0, len(s.ByReason))
+	for r := range s.ByReason {
+		reasons = append(reasons, r)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if s.ByReason[reasons[i]] != s.ByReason[reasons[j]] {
+			return s.ByReason[reasons[i]] > s.ByReason[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+	return reasons
+}
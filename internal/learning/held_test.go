@@ -0,0 +1,63 @@
+package learning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestHeldCorrections_HoldLoadRemove(t *testing.T) {
+	dir := t.TempDir()
+
+	if held, err := LoadHeldCorrections(dir); err != nil || held != nil {
+		t.Fatalf("LoadHeldCorrections(empty) = %v, %v; want nil, nil", held, err)
+	}
+
+	for _, h := range []HeldCorrection{
+		{Correction: models.Correction{ID: "c-1", CorrectedAction: "no"}, Quality: QualityScore{Score: 0, Reasons: []string{QualityReasonFiller}}},
+		{Correction: models.Correction{ID: "c-2", CorrectedAction: "good"}, Quality: QualityScore{Score: 0.2, Reasons: []string{QualityReasonTooShort, QualityReasonNotActionable}}},
+		{Correction: models.Correction{ID: "c-3", CorrectedAction: "fix"}, Quality: QualityScore{Score: 0.1, Reasons: []string{QualityReasonTooShort}}},
+	} {
+		h.HeldAt = time.Now()
+		if err := HoldCorrection(dir, h); err != nil {
+			t.Fatalf("HoldCorrection(%s) error = %v", h.Correction.ID, err)
+		}
+	}
+
+	held, err := LoadHeldCorrections(dir)
+	if err != nil {
+		t.Fatalf("LoadHeldCorrections() error = %v", err)
+	}
+	if len(held) != 3 {
+		t.Fatalf("held = %d, want 3", len(held))
+	}
+
+	stats := SummarizeHeld(held)
+	if stats.Total != 3 {
+		t.Errorf("Total = %d, want 3", stats.Total)
+	}
+	if stats.AverageScore < 0.099 || stats.AverageScore > 0.101 {
+		t.Errorf("AverageScore = %f, want 0.1", stats.AverageScore)
+	}
+	if got := stats.SortedReasons(); len(got) != 3 || got[0] != QualityReasonTooShort {
+		t.Errorf("SortedReasons() = %v, want %q first", got, QualityReasonTooShort)
+	}
+
+	removed, err := RemoveHeldCorrection(dir, "c-2")
+	if err != nil {
+		t.Fatalf("RemoveHeldCorrection() error = %v", err)
+	}
+	if removed == nil || removed.Correction.ID != "c-2" {
+		t.Fatalf("RemoveHeldCorrection() = %v, want c-2", removed)
+	}
+
+	held, _ = LoadHeldCorrections(dir)
+	if len(held) != 2 || held[0].Correction.ID != "c-1" || held[1].Correction.ID != "c-3" {
+		t.Errorf("remaining held = %+v, want c-1 and c-3", held)
+	}
+
+	if removed, err := RemoveHeldCorrection(dir, "missing"); err != nil || removed != nil {
+		t.Errorf("RemoveHeldCorrection(missing) = %v, %v; want nil, nil", removed, err)
+	}
+}
@@ -0,0 +1,118 @@
+package learning
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/quota"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Number of consolidation candidates proposed of each kind.
+const (
+	maxMergeCandidates  = 3
+	maxForgetCandidates = 5
+)
+
+// QuotaExceeded describes a correction that was not learned because a
+// storage limit was reached, with consolidation candidates that would make
+// room for it.
+type QuotaExceeded struct {
+	Usage      quota.Usage       `json:"usage"`
+	Candidates []quota.Candidate `json:"candidates"`
+}
+
+// targetScope returns the scope a behavior will be written to.
+func (l *learningLoop) targetScope(behavior *models.Behavior) constants.Scope {
+	if l.scopeOverride != nil {
+		return *l.scopeOverride
+	}
+	return ClassifyScope(behavior)
+}
+
+// scopeStore returns the store behind scope, or the whole store when it is
+// not split by scope.
+func (l *learningLoop) scopeStore(scope constants.Scope) store.GraphStore {
+	scoped, ok := l.store.(store.ScopedStore)
+	if !ok {
+		return l.store
+	}
+	if scope == constants.ScopeLocal {
+		return scoped.LocalStore()
+	}
+	return scoped.GlobalStore()
+}
+
+// checkLimits reports the first storage limit that committing candidate
+// would exceed, or nil when it fits.
+func (l *learningLoop) checkLimits(ctx context.Context, candidate *models.Behavior, placement *PlacementDecision) (*QuotaExceeded, error) {
+	if n := len(candidate.Content.Canonical); l.limits.MaxCanonicalLength > 0 && n > l.limits.MaxCanonicalLength {
+		return &QuotaExceeded{
+			Usage:      quota.NewUsage(quota.LimitCanonicalLength, "", n, l.limits.MaxCanonicalLength),
+			Candidates: mergeCandidates(placement),
+		}, nil
+	}
+
+	if l.limits.MaxBehaviorsPerScope <= 0 {
+		return nil, nil
+	}
+	scope := l.targetScope(candidate)
+	scopeStore := l.scopeStore(scope)
+	nodes, err := scopeStore.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count %s behaviors: %w", scope, err)
+	}
+	usage := quota.NewUsage(quota.LimitBehaviors, string(scope), len(nodes), l.limits.MaxBehaviorsPerScope)
+	if usage.Status != quota.StatusExceeded {
+		return nil, nil
+	}
+
+	behaviors := make([]models.Behavior, 0, len(nodes))
+	for _, n := range nodes {
+		behaviors = append(behaviors, models.NodeToBehavior(n))
+	}
+	candidates := append(mergeCandidates(placement), quota.ForgetCandidates(behaviors, maxForgetCandidates)...)
+	return &QuotaExceeded{Usage: usage, Candidates: candidates}, nil
+}
+
+// mergeCandidates proposes folding the new behavior into the most similar
+// existing ones.
+func mergeCandidates(placement *PlacementDecision) []quota.Candidate {
+	similar := append([]SimilarityMatch(nil), placement.SimilarBehaviors...)
+	sort.SliceStable(similar, func(i, j int) bool { return similar[i].Score > similar[j].Score })
+	if len(similar) > maxMergeCandidates {
+		similar = similar[:maxMergeCandidates]
+	}
+
+	candidates := make([]quota.Candidate, 0, len(similar))
+	for _, sim := range similar {
+		candidates = append(candidates, quota.Candidate{
+			Action:     "merge",
+			BehaviorID: sim.ID,
+			Score:      sim.Score,
+			Reason:     fmt.Sprintf("similar to the new behavior (%.2f)", sim.Score),
+		})
+	}
+	return candidates
+}
+
+// edgeAllowed reports whether both endpoints of an edge have room under the
+// edges-per-node limit.
+func (l *learningLoop) edgeAllowed(ctx context.Context, from, to string) (bool, error) {
+	if l.limits.MaxEdgesPerNode <= 0 {
+		return true, nil
+	}
+	for _, id := range []string{from, to} {
+		count, err := quota.EdgeCount(ctx, l.store, id)
+		if err != nil {
+			return false, err
+		}
+		if count >= l.limits.MaxEdgesPerNode {
+			return false, nil
+		}
+	}
+	return true, nil
+}
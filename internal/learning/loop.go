@@ -11,6 +11,11 @@ import (
 	"github.com/nvandessel/floop/internal/llm"
 	"github.com/nvandessel/floop/internal/logging"
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/quarantine"
+	"github.com/nvandessel/floop/internal/quota"
+	"github.com/nvandessel/floop/internal/retirement"
+	"github.com/nvandessel/floop/internal/review"
+	"github.com/nvandessel/floop/internal/sanitize"
 	"github.com/nvandessel/floop/internal/store"
 )
 
@@ -45,10 +50,60 @@ type LearningResult struct {
 
 	// MergeSimilarity is the similarity score with the merged behavior
 	MergeSimilarity float64
+
+	// Quality is the correction's quality score, if a quality gate is configured
+	Quality *QualityScore
+
+	// Held indicates the correction scored below the quality threshold and no
+	// behavior was extracted. Callers should place it in the holding area.
+	Held bool
+
+	// Quota is set when a storage limit was reached and no behavior was
+	// added. It lists consolidation candidates that would make room; callers
+	// should keep the correction unprocessed so it can be retried.
+	Quota *QuotaExceeded
+
+	// Restored lists retired behaviors restored because this correction
+	// recurred on their topic during their grace period.
+	Restored []retirement.Notice
+
+	// Injection is set when the extracted behavior looked like prompt
+	// injection. The behavior was stored quarantined instead of active and
+	// needs review before it can be injected.
+	Injection *sanitize.InjectionReport
+
+	// Policy is the policy applied to the candidate: the one for its target
+	// scope. Nil when the correction was held before extraction.
+	Policy *Policy
+}
+
+// Policy decides what the learning loop does with a candidate behavior:
+// whether it is merged into a duplicate, auto-accepted, or flagged for review.
+type Policy struct {
+	AutoAcceptThreshold float64        // Minimum placement confidence to auto-accept
+	AutoMerge           bool           // Merge near-duplicates instead of adding
+	AutoMergeThreshold  float64        // Minimum similarity to merge
+	Review              ReviewTriggers // What flags a candidate for review
+}
+
+// ReviewTriggers select the candidates that need human review.
+type ReviewTriggers struct {
+	Constraints   bool    // Constraint behaviors always need review
+	MinConfidence float64 // Placement confidence below this needs review
+	MaxSimilarity float64 // Similarity to an existing behavior above this needs review
+}
+
+// DefaultReviewTriggers returns the review triggers used when none are configured.
+func DefaultReviewTriggers() ReviewTriggers {
+	return ReviewTriggers{
+		Constraints:   true,
+		MinConfidence: constants.LowConfidenceThreshold,
+		MaxSimilarity: constants.ReviewSimilarityThreshold,
+	}
 }
 
 // LearningLoop orchestrates the correction -> behavior pipeline.
-// It coordinates CorrectionCapture, BehaviorExtractor, and GraphPlacer
+// It coordinates CorrectionCapture, Extractor, and GraphPlacer
 // to process corrections and produce learned behaviors.
 type LearningLoop interface {
 	// ProcessCorrection processes a single correction into a candidate behavior.
@@ -90,6 +145,31 @@ type LearningLoopConfig struct {
 
 	// DecisionLogger is the optional decision event logger.
 	DecisionLogger *logging.DecisionLogger
+
+	// QualityScorer is the optional correction quality gate. If nil, every
+	// correction is extracted.
+	QualityScorer QualityScorer
+
+	// MinQualityScore is the minimum quality score for extraction.
+	// Corrections scoring below it are held. Only used with QualityScorer.
+	MinQualityScore float64
+
+	// Limits caps store growth. When a limit is reached, the correction is
+	// not learned and consolidation candidates are proposed instead.
+	// Zero values disable a limit.
+	Limits quota.Limits
+
+	// Review selects the candidates flagged for review.
+	// If nil, DefaultReviewTriggers is used.
+	Review *ReviewTriggers
+
+	// ScopePolicies replaces the policy above for behaviors routed to a
+	// scope. Scopes without an entry use the policy above.
+	ScopePolicies map[constants.Scope]Policy
+
+	// Extractor turns corrections into candidate behaviors.
+	// If nil, the rule-based extractor is used.
+	Extractor Extractor
 }
 
 // DefaultLearningLoopConfig returns sensible defaults for the learning loop.
@@ -121,40 +201,90 @@ func NewLearningLoop(s store.GraphStore, config *LearningLoopConfig) LearningLoo
 		placer = NewGraphPlacer(s)
 	}
 
+	review := DefaultReviewTriggers()
+	if cfg.Review != nil {
+		review = *cfg.Review
+	}
+
+	extractor := cfg.Extractor
+	if extractor == nil {
+		extractor = NewRuleExtractor()
+	}
+
 	return &learningLoop{
-		store:               s,
-		capturer:            NewCorrectionCapture(),
-		extractor:           NewBehaviorExtractor(),
-		placer:              placer,
-		autoAcceptThreshold: cfg.AutoAcceptThreshold,
-		autoMerge:           cfg.AutoMerge,
-		autoMergeThreshold:  cfg.AutoMergeThreshold,
-		deduplicator:        cfg.Deduplicator,
-		scopeOverride:       cfg.ScopeOverride,
-		logger:              cfg.Logger,
-		decisions:           cfg.DecisionLogger,
+		store:     s,
+		capturer:  NewCorrectionCapture(),
+		extractor: extractor,
+		placer:    placer,
+		policy: Policy{
+			AutoAcceptThreshold: cfg.AutoAcceptThreshold,
+			AutoMerge:           cfg.AutoMerge,
+			AutoMergeThreshold:  cfg.AutoMergeThreshold,
+			Review:              review,
+		},
+		scopePolicies:   cfg.ScopePolicies,
+		deduplicator:    cfg.Deduplicator,
+		scopeOverride:   cfg.ScopeOverride,
+		logger:          cfg.Logger,
+		decisions:       cfg.DecisionLogger,
+		qualityScorer:   cfg.QualityScorer,
+		minQualityScore: cfg.MinQualityScore,
+		limits:          cfg.Limits,
 	}
 }
 
 // learningLoop is the concrete implementation of LearningLoop.
 type learningLoop struct {
-	store               store.GraphStore
-	capturer            CorrectionCapture
-	extractor           BehaviorExtractor
-	placer              GraphPlacer
-	autoAcceptThreshold float64
-	autoMerge           bool
-	autoMergeThreshold  float64
-	deduplicator        dedup.Deduplicator
-	scopeOverride       *constants.Scope
-	logger              *slog.Logger
-	decisions           *logging.DecisionLogger
+	store           store.GraphStore
+	capturer        CorrectionCapture
+	extractor       Extractor
+	placer          GraphPlacer
+	policy          Policy
+	scopePolicies   map[constants.Scope]Policy
+	deduplicator    dedup.Deduplicator
+	scopeOverride   *constants.Scope
+	logger          *slog.Logger
+	decisions       *logging.DecisionLogger
+	qualityScorer   QualityScorer
+	minQualityScore float64
+	limits          quota.Limits
+}
+
+// policyFor returns the policy for behaviors routed to scope.
+func (l *learningLoop) policyFor(scope constants.Scope) Policy {
+	if p, ok := l.scopePolicies[scope]; ok {
+		return p
+	}
+	return l.policy
 }
 
 // ProcessCorrection implements LearningLoop.
 func (l *learningLoop) ProcessCorrection(ctx context.Context, correction models.Correction) (*LearningResult, error) {
+	// Step 0: Quality gate — hold noisy corrections instead of extracting them
+	var quality *QualityScore
+	if l.qualityScorer != nil {
+		q := l.qualityScorer.Score(ctx, correction)
+		quality = &q
+		if q.Score < l.minQualityScore {
+			if l.decisions != nil {
+				l.decisions.Log(map[string]any{
+					"event":         "correction_held",
+					"correction_id": correction.ID,
+					"score":         q.Score,
+					"threshold":     l.minQualityScore,
+					"reasons":       q.Reasons,
+				})
+			}
+			return &LearningResult{
+				Correction: correction,
+				Quality:    quality,
+				Held:       true,
+			}, nil
+		}
+	}
+
 	// Step 1: Extract candidate behavior
-	candidate, err := l.extractor.Extract(correction)
+	candidate, err := l.extractor.Extract(ctx, correction)
 	if err != nil {
 		return nil, fmt.Errorf("extraction failed: %w", err)
 	}
@@ -163,10 +293,52 @@ func (l *learningLoop) ProcessCorrection(ctx context.Context, correction models.
 		l.logger.Debug("behavior extracted", "behavior_id", candidate.ID, "kind", candidate.Kind, "correction_id", correction.ID)
 	}
 
-	// Step 2: Check for duplicates and auto-merge if enabled
-	if l.autoMerge && l.deduplicator != nil {
-		mergeResult, err := l.tryAutoMerge(ctx, candidate)
+	// The policy follows the scope the behavior will be stored in
+	policy := l.policyFor(l.targetScope(candidate))
+
+	// Step 1a: Injection analysis — behaviors are injected into prompts
+	// verbatim, so one that tries to instruct the agent is quarantined
+	var injection *sanitize.InjectionReport
+	if report := quarantine.Analyze(candidate); report.Suspicious() {
+		injection = &report
+		if l.decisions != nil {
+			l.decisions.Log(map[string]any{
+				"event":         "behavior_quarantined",
+				"correction_id": correction.ID,
+				"behavior_id":   candidate.ID,
+				"rules":         report.Rules(),
+			})
+		}
+	}
+
+	// Step 1b: Restore retired behaviors this correction recurs on, so
+	// placement and dedup see them as active again. A quarantined
+	// correction restores nothing.
+	var restored []retirement.Notice
+	if injection == nil {
+		restored, err = retirement.RestoreRecurring(ctx, l.store, candidate, time.Now())
+		if err != nil && l.logger != nil {
+			l.logger.Warn("retirement recurrence check failed", "error", err)
+		}
+	}
+	if len(restored) > 0 && l.decisions != nil {
+		for _, n := range restored {
+			l.decisions.Log(map[string]any{
+				"event":         "retired_behavior_restored",
+				"correction_id": correction.ID,
+				"behavior_id":   n.BehaviorID,
+			})
+		}
+	}
+
+	// Step 2: Check for duplicates and auto-merge if enabled. Quarantined
+	// content is never merged into a trusted behavior.
+	if policy.AutoMerge && l.deduplicator != nil && injection == nil {
+		mergeResult, err := l.tryAutoMerge(ctx, candidate, policy.AutoMergeThreshold)
 		if err == nil && mergeResult != nil {
+			mergeResult.Quality = quality
+			mergeResult.Restored = restored
+			mergeResult.Policy = &policy
 			return mergeResult, nil
 		}
 		// Continue with normal flow if auto-merge didn't happen
@@ -182,12 +354,51 @@ func (l *learningLoop) ProcessCorrection(ctx context.Context, correction models.
 		l.logger.Debug("placement decided", "behavior_id", candidate.ID, "action", placement.Action, "confidence", placement.Confidence)
 	}
 
-	// Step 4: Decide if auto-accept or needs review
-	requiresReview, reasons := l.needsReview(candidate, placement)
-	autoAccepted := !requiresReview && placement.Confidence >= l.autoAcceptThreshold
+	// Step 4: Storage limits — propose consolidation instead of growing
+	exceeded, err := l.checkLimits(ctx, candidate, placement)
+	if err != nil {
+		return nil, fmt.Errorf("limit check failed: %w", err)
+	}
+	if exceeded != nil {
+		if l.decisions != nil {
+			l.decisions.Log(map[string]any{
+				"event":         "storage_limit_reached",
+				"correction_id": correction.ID,
+				"behavior_id":   candidate.ID,
+				"limit":         exceeded.Usage.Limit,
+				"scope":         exceeded.Usage.Scope,
+				"used":          exceeded.Usage.Used,
+				"max":           exceeded.Usage.Max,
+				"candidates":    len(exceeded.Candidates),
+			})
+		}
+		return &LearningResult{
+			Correction:        correction,
+			CandidateBehavior: *candidate,
+			Placement:         *placement,
+			Scope:             l.targetScope(candidate),
+			Quality:           quality,
+			Quota:             exceeded,
+			Restored:          restored,
+			Policy:            &policy,
+		}, nil
+	}
+
+	// Step 5: Decide if auto-accept or needs review
+	requiresReview, reasons := l.needsReview(candidate, placement, policy)
+	if injection != nil {
+		requiresReview = true
+		reasons = append(reasons, "Quarantined: "+quarantine.Reason(*injection))
+	}
+	autoAccepted := !requiresReview && placement.Confidence >= policy.AutoAcceptThreshold
 
-	// Step 5: Commit to graph
-	scope, err := l.commitBehavior(ctx, candidate, placement)
+	// Step 6: Commit to graph. A quarantined behavior waits in the
+	// quarantine queue instead of the review queue.
+	var pendingReview []string
+	if requiresReview && injection == nil {
+		pendingReview = reasons
+	}
+	scope, err := l.commitBehavior(ctx, candidate, placement, injection, pendingReview, reviewMergeTarget(placement, policy))
 	if err != nil {
 		return nil, fmt.Errorf("commit failed: %w", err)
 	}
@@ -200,12 +411,16 @@ func (l *learningLoop) ProcessCorrection(ctx context.Context, correction models.
 		AutoAccepted:      autoAccepted,
 		RequiresReview:    requiresReview,
 		ReviewReasons:     reasons,
+		Quality:           quality,
+		Restored:          restored,
+		Injection:         injection,
+		Policy:            &policy,
 	}, nil
 }
 
-// tryAutoMerge attempts to merge the candidate with existing duplicates.
-// Returns a LearningResult if merge occurred, nil otherwise.
-func (l *learningLoop) tryAutoMerge(ctx context.Context, candidate *models.Behavior) (*LearningResult, error) {
+// tryAutoMerge attempts to merge the candidate with an existing duplicate at
+// least threshold similar. Returns a LearningResult if merge occurred, nil otherwise.
+func (l *learningLoop) tryAutoMerge(ctx context.Context, candidate *models.Behavior, threshold float64) (*LearningResult, error) {
 	// Find duplicates
 	duplicates, err := l.deduplicator.FindDuplicates(ctx, candidate)
 	if err != nil {
@@ -215,7 +430,7 @@ func (l *learningLoop) tryAutoMerge(ctx context.Context, candidate *models.Behav
 	// Check if any duplicate exceeds the merge threshold
 	var bestMatch *dedup.DuplicateMatch
 	for i := range duplicates {
-		if duplicates[i].Similarity >= l.autoMergeThreshold {
+		if duplicates[i].Similarity >= threshold {
 			if bestMatch == nil || duplicates[i].Similarity > bestMatch.Similarity {
 				bestMatch = &duplicates[i]
 			}
@@ -231,7 +446,7 @@ func (l *learningLoop) tryAutoMerge(ctx context.Context, candidate *models.Behav
 				"event":            "auto_merge_skipped",
 				"behavior_id":      candidate.ID,
 				"duplicates_found": len(duplicates),
-				"threshold":        l.autoMergeThreshold,
+				"threshold":        threshold,
 				"reason":           "no duplicate above threshold",
 			})
 		}
@@ -247,7 +462,7 @@ func (l *learningLoop) tryAutoMerge(ctx context.Context, candidate *models.Behav
 			"behavior_id":  candidate.ID,
 			"merge_target": bestMatch.Behavior.ID,
 			"similarity":   bestMatch.Similarity,
-			"threshold":    l.autoMergeThreshold,
+			"threshold":    threshold,
 		})
 	}
 
@@ -277,12 +492,12 @@ func (l *learningLoop) tryAutoMerge(ctx context.Context, candidate *models.Behav
 	}, nil
 }
 
-// needsReview determines if human review is required.
-func (l *learningLoop) needsReview(candidate *models.Behavior, placement *PlacementDecision) (bool, []string) {
+// needsReview determines if human review is required under policy.
+func (l *learningLoop) needsReview(candidate *models.Behavior, placement *PlacementDecision, policy Policy) (bool, []string) {
 	var reasons []string
 
-	// Constraints always need review
-	if candidate.Kind == models.BehaviorKindConstraint {
+	// Constraints need review unless the policy trusts them
+	if policy.Review.Constraints && candidate.Kind == models.BehaviorKindConstraint {
 		reasons = append(reasons, "Constraints require human review")
 	}
 
@@ -297,13 +512,13 @@ func (l *learningLoop) needsReview(candidate *models.Behavior, placement *Placem
 	}
 
 	// Low confidence placements need review
-	if placement.Confidence < constants.LowConfidenceThreshold {
+	if placement.Confidence < policy.Review.MinConfidence {
 		reasons = append(reasons, fmt.Sprintf("Low placement confidence: %.2f", placement.Confidence))
 	}
 
 	// High similarity to existing might be duplicate
 	for _, sim := range placement.SimilarBehaviors {
-		if sim.Score > 0.85 {
+		if sim.Score > policy.Review.MaxSimilarity {
 			reasons = append(reasons, fmt.Sprintf("Very similar to existing: %s (%.2f)", sim.ID, sim.Score))
 		}
 	}
@@ -323,16 +538,16 @@ func (l *learningLoop) needsReview(candidate *models.Behavior, placement *Placem
 			})
 		}
 	} else {
-		accepted := placement.Confidence >= l.autoAcceptThreshold
+		accepted := placement.Confidence >= policy.AutoAcceptThreshold
 		if l.logger != nil {
-			l.logger.Debug("auto-accept check", "behavior_id", candidate.ID, "confidence", placement.Confidence, "threshold", l.autoAcceptThreshold, "accepted", accepted)
+			l.logger.Debug("auto-accept check", "behavior_id", candidate.ID, "confidence", placement.Confidence, "threshold", policy.AutoAcceptThreshold, "accepted", accepted)
 		}
 		if l.decisions != nil {
 			l.decisions.Log(map[string]any{
 				"event":       "auto_accept",
 				"behavior_id": candidate.ID,
 				"confidence":  placement.Confidence,
-				"threshold":   l.autoAcceptThreshold,
+				"threshold":   policy.AutoAcceptThreshold,
 				"accepted":    accepted,
 			})
 		}
@@ -347,9 +562,26 @@ type ScopedNodeAdder interface {
 	AddNodeToScope(ctx context.Context, node store.Node, scope constants.Scope) (string, error)
 }
 
-// commitBehavior saves the behavior to the graph.
-// Returns the scope the behavior was written to.
-func (l *learningLoop) commitBehavior(ctx context.Context, behavior *models.Behavior, placement *PlacementDecision) (constants.Scope, error) {
+// reviewMergeTarget returns the existing behavior a flagged candidate most
+// likely duplicates: the merge target of a merge placement, otherwise the
+// most similar behavior above the policy's review similarity.
+func reviewMergeTarget(placement *PlacementDecision, policy Policy) string {
+	if placement.Action == PlacementActionMerge {
+		return placement.TargetID
+	}
+	target, best := "", policy.Review.MaxSimilarity
+	for _, sim := range placement.SimilarBehaviors {
+		if sim.Score > best {
+			target, best = sim.ID, sim.Score
+		}
+	}
+	return target
+}
+
+// commitBehavior saves the behavior to the graph, flagging it for review
+// when reviewReasons is non-empty. Returns the scope the behavior was
+// written to.
+func (l *learningLoop) commitBehavior(ctx context.Context, behavior *models.Behavior, placement *PlacementDecision, injection *sanitize.InjectionReport, reviewReasons []string, mergeTarget string) (constants.Scope, error) {
 	// Convert behavior to node
 	node := store.Node{
 		ID:   behavior.ID,
@@ -370,12 +602,17 @@ func (l *learningLoop) commitBehavior(ctx context.Context, behavior *models.Beha
 			"stats":      behavior.Stats,
 		},
 	}
+	if behavior.ExpiresAt != nil {
+		node.Metadata["expires_at"] = behavior.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	if injection != nil {
+		quarantine.Quarantine(&node, *injection, time.Now())
+	} else if len(reviewReasons) > 0 {
+		review.Mark(&node, reviewReasons, mergeTarget, time.Now())
+	}
 
 	// Classify scope based on behavior's When conditions, with optional override
-	scope := ClassifyScope(behavior)
-	if l.scopeOverride != nil {
-		scope = *l.scopeOverride
-	}
+	scope := l.targetScope(behavior)
 
 	// Use scoped write if the store supports it; fall back to AddNode for plain stores (tests)
 	if scoped, ok := l.store.(ScopedNodeAdder); ok {
@@ -388,8 +625,25 @@ func (l *learningLoop) commitBehavior(ctx context.Context, behavior *models.Beha
 		}
 	}
 
-	// Add edges
+	// Add edges, skipping any that would push a node past the edge limit
 	for _, e := range placement.ProposedEdges {
+		allowed, err := l.edgeAllowed(ctx, e.From, e.To)
+		if err != nil {
+			return scope, err
+		}
+		if !allowed {
+			if l.decisions != nil {
+				l.decisions.Log(map[string]any{
+					"event":     "edge_skipped",
+					"source":    e.From,
+					"target":    e.To,
+					"kind":      e.Kind,
+					"reason":    "edge limit reached",
+					"max_edges": l.limits.MaxEdgesPerNode,
+				})
+			}
+			continue
+		}
 		edge := store.Edge{
 			Source:    e.From,
 			Target:    e.To,
@@ -0,0 +1,228 @@
+package learning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// Reasons reported for low-quality corrections.
+const (
+	QualityReasonFiller        = "filler only"
+	QualityReasonTooShort      = "too short"
+	QualityReasonNotActionable = "no actionable instruction"
+	QualityReasonNotSpecific   = "not specific"
+	QualityReasonLLMLow        = "low llm rating"
+)
+
+// Heuristic signal weights. They sum to 1.0.
+const (
+	qualityWeightLength      = 0.4
+	qualityWeightActionable  = 0.35
+	qualityWeightSpecificity = 0.25
+
+	// llmQualityWeight is the share of the final score taken from the LLM
+	// rating when one is available.
+	llmQualityWeight = 0.5
+)
+
+// QualityScore is the result of scoring a correction before extraction.
+type QualityScore struct {
+	// Score is the overall quality (0.0-1.0).
+	Score float64 `json:"score"`
+
+	// Length, Actionable, and Specificity are the heuristic signals (0.0-1.0).
+	Length      float64 `json:"length"`
+	Actionable  float64 `json:"actionable"`
+	Specificity float64 `json:"specificity"`
+
+	// LLMScore is the LLM rating, if one was obtained.
+	LLMScore *float64 `json:"llm_score,omitempty"`
+
+	// Reasons lists the weak signals that lowered the score.
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// QualityScorer rates how useful a correction is as a source for a behavior.
+type QualityScorer interface {
+	Score(ctx context.Context, correction models.Correction) QualityScore
+}
+
+// NewHeuristicQualityScorer returns a scorer based on length, actionable
+// verbs, and specificity of the corrected action.
+func NewHeuristicQualityScorer() QualityScorer {
+	return heuristicQualityScorer{}
+}
+
+type heuristicQualityScorer struct{}
+
+// fillerWords are words that carry no instruction on their own
+// ("no, stop", "that's wrong").
+var fillerWords = map[string]bool{
+	"no": true, "nope": true, "stop": true, "wrong": true, "bad": true,
+	"not": true, "that": true, "that's": true, "thats": true, "this": true,
+	"is": true, "it": true, "again": true, "please": true, "ugh": true,
+	"wait": true, "undo": true, "revert": true, "nah": true, "hmm": true,
+	"ok": true, "okay": true, "fine": true, "yes": true, "so": true,
+}
+
+// actionableWords signal an instruction the agent can follow.
+var actionableWords = map[string]bool{
+	"use": true, "prefer": true, "avoid": true, "always": true, "never": true,
+	"don't": true, "dont": true, "do": true, "should": true, "must": true,
+	"run": true, "add": true, "remove": true, "call": true, "write": true,
+	"check": true, "keep": true, "put": true, "set": true, "return": true,
+	"wrap": true, "handle": true, "create": true, "move": true, "rename": true,
+	"replace": true, "instead": true, "only": true, "ensure": true, "make": true,
+	"include": true, "exclude": true, "pass": true, "test": true, "validate": true,
+}
+
+// specificTokenPattern matches code-like tokens: paths, identifiers with dots
+// or underscores, calls, flags, backticks, and camelCase.
+var specificTokenPattern = regexp.MustCompile("[`/._()]|--?[a-zA-Z]|[a-z][A-Z]|[0-9]")
+
+// Score implements QualityScorer.
+func (heuristicQualityScorer) Score(_ context.Context, correction models.Correction) QualityScore {
+	text := strings.TrimSpace(correction.CorrectedAction)
+	words := strings.Fields(strings.ToLower(text))
+
+	var q QualityScore
+
+	meaningful := 0
+	for _, w := range words {
+		w = strings.Trim(w, ".,;:!?\"'")
+		if w != "" && !fillerWords[w] {
+			meaningful++
+		}
+	}
+	if meaningful == 0 {
+		q.Reasons = []string{QualityReasonFiller}
+		return q
+	}
+
+	switch {
+	case len(words) >= 6:
+		q.Length = 1.0
+	case len(words) >= 3:
+		q.Length = 0.5
+	default:
+		q.Reasons = append(q.Reasons, QualityReasonTooShort)
+	}
+
+	for _, w := range words {
+		if actionableWords[strings.Trim(w, ".,;:!?\"'")] {
+			q.Actionable = 1.0
+			break
+		}
+	}
+	if q.Actionable == 0 {
+		q.Reasons = append(q.Reasons, QualityReasonNotActionable)
+	}
+
+	switch {
+	case specificTokenPattern.MatchString(text):
+		q.Specificity = 1.0
+	case correction.AgentAction != "" || correction.Context.FilePath != "" || correction.Context.FileLanguage != "":
+		// Context about what went wrong, or where, narrows the behavior
+		q.Specificity = 0.5
+	default:
+		q.Reasons = append(q.Reasons, QualityReasonNotSpecific)
+	}
+
+	q.Score = qualityWeightLength*q.Length +
+		qualityWeightActionable*q.Actionable +
+		qualityWeightSpecificity*q.Specificity
+	return q
+}
+
+// NewQualityScorer returns the LLM-blended scorer when useLLM is set and a
+// client is given, and the heuristic scorer otherwise.
+func NewQualityScorer(useLLM bool, client llm.Client) QualityScorer {
+	if useLLM && client != nil {
+		return NewLLMQualityScorer(client)
+	}
+	return NewHeuristicQualityScorer()
+}
+
+// NewLLMQualityScorer returns a scorer that blends the heuristic score with an
+// LLM rating. If the client is unavailable or the rating fails, the heuristic
+// score is used alone.
+func NewLLMQualityScorer(client llm.Client) QualityScorer {
+	return &llmQualityScorer{client: client, heuristic: heuristicQualityScorer{}}
+}
+
+type llmQualityScorer struct {
+	client    llm.Client
+	heuristic heuristicQualityScorer
+}
+
+// Score implements QualityScorer.
+func (s *llmQualityScorer) Score(ctx context.Context, correction models.Correction) QualityScore {
+	q := s.heuristic.Score(ctx, correction)
+	if s.client == nil || !s.client.Available() {
+		return q
+	}
+	// Filler is never worth an LLM call
+	if len(q.Reasons) == 1 && q.Reasons[0] == QualityReasonFiller {
+		return q
+	}
+
+	response, err := s.client.Complete(ctx, []llm.Message{
+		{Role: "user", Content: CorrectionQualityPrompt(correction)},
+	})
+	if err != nil {
+		return q
+	}
+	rating, err := ParseCorrectionQualityResponse(response)
+	if err != nil {
+		return q
+	}
+
+	q.LLMScore = &rating
+	q.Score = (1-llmQualityWeight)*q.Score + llmQualityWeight*rating
+	if rating < 0.5 {
+		q.Reasons = append(q.Reasons, QualityReasonLLMLow)
+	}
+	return q
+}
+
+// CorrectionQualityPrompt generates a prompt asking an LLM to rate how
+// actionable a correction is.
+func CorrectionQualityPrompt(correction models.Correction) string {
+	var prompt strings.Builder
+
+	prompt.WriteString("You are rating a correction a user gave to an AI coding agent. Good corrections state a specific, reusable instruction. Bad corrections are vague or emotional (\"no, stop\", \"that's wrong\").\n\n## What the agent did\n")
+	prompt.WriteString(correction.AgentAction)
+	prompt.WriteString("\n\n## What the user said to do instead\n")
+	prompt.WriteString(correction.CorrectedAction)
+	prompt.WriteString(`
+
+## Response Format
+Respond with ONLY a JSON object (no markdown code blocks, no additional text):
+{"score": <float between 0.0 and 1.0>}`)
+	return prompt.String()
+}
+
+// ParseCorrectionQualityResponse parses an LLM quality rating.
+func ParseCorrectionQualityResponse(response string) (float64, error) {
+	jsonStr := llm.ExtractJSON(response)
+	if jsonStr == "" {
+		return 0, fmt.Errorf("no JSON found in response")
+	}
+
+	var result struct {
+		Score *float64 `json:"score"`
+	}
+	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+		return 0, fmt.Errorf("parsing quality rating: %w", err)
+	}
+	if result.Score == nil || *result.Score < 0 || *result.Score > 1 {
+		return 0, fmt.Errorf("quality rating missing or out of range")
+	}
+	return *result.Score, nil
+}
@@ -0,0 +1,141 @@
+package learning
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestHeuristicQualityScorer(t *testing.T) {
+	tests := []struct {
+		name        string
+		correction  models.Correction
+		wantMin     float64
+		wantMax     float64
+		wantReasons []string
+	}{
+		{
+			name:        "filler only",
+			correction:  models.Correction{CorrectedAction: "no, stop"},
+			wantMax:     0,
+			wantReasons: []string{QualityReasonFiller},
+		},
+		{
+			name:        "that's wrong",
+			correction:  models.Correction{CorrectedAction: "That's wrong!"},
+			wantMax:     0,
+			wantReasons: []string{QualityReasonFiller},
+		},
+		{
+			name:        "vague short",
+			correction:  models.Correction{CorrectedAction: "do better"},
+			wantMin:     0.3,
+			wantMax:     0.4,
+			wantReasons: []string{QualityReasonTooShort, QualityReasonNotSpecific},
+		},
+		{
+			name:        "no verb or specifics",
+			correction:  models.Correction{CorrectedAction: "good approach"},
+			wantMax:     0.01,
+			wantReasons: []string{QualityReasonTooShort, QualityReasonNotActionable, QualityReasonNotSpecific},
+		},
+		{
+			name:       "specific instruction",
+			correction: models.Correction{CorrectedAction: "use pathlib.Path instead of os.path for file paths"},
+			wantMin:    1.0,
+			wantMax:    1.0,
+		},
+		{
+			name: "context adds specificity",
+			correction: models.Correction{
+				AgentAction:     "printed debug output",
+				CorrectedAction: "use structured logging",
+			},
+			wantMin: 0.65,
+			wantMax: 0.7,
+		},
+	}
+
+	scorer := NewHeuristicQualityScorer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := scorer.Score(context.Background(), tt.correction)
+			if q.Score < tt.wantMin || q.Score > tt.wantMax {
+				t.Errorf("Score = %.3f, want in [%.2f, %.2f]", q.Score, tt.wantMin, tt.wantMax)
+			}
+			if strings.Join(q.Reasons, ",") != strings.Join(tt.wantReasons, ",") {
+				t.Errorf("Reasons = %v, want %v", q.Reasons, tt.wantReasons)
+			}
+		})
+	}
+}
+
+func TestLLMQualityScorer(t *testing.T) {
+	correction := models.Correction{CorrectedAction: "use structured logging with slog everywhere"}
+	heuristic := NewHeuristicQualityScorer().Score(context.Background(), correction)
+
+	t.Run("blends llm rating", func(t *testing.T) {
+		client := llm.NewMockClient().WithCompleteResponse(`{"score": 0.2}`)
+		q := NewLLMQualityScorer(client).Score(context.Background(), correction)
+		if q.LLMScore == nil || *q.LLMScore != 0.2 {
+			t.Fatalf("LLMScore = %v, want 0.2", q.LLMScore)
+		}
+		want := 0.5*heuristic.Score + 0.5*0.2
+		if q.Score != want {
+			t.Errorf("Score = %.3f, want %.3f", q.Score, want)
+		}
+		if len(q.Reasons) == 0 || q.Reasons[len(q.Reasons)-1] != QualityReasonLLMLow {
+			t.Errorf("Reasons = %v, want trailing %q", q.Reasons, QualityReasonLLMLow)
+		}
+	})
+
+	t.Run("falls back on error", func(t *testing.T) {
+		client := llm.NewMockClient().WithError(errors.New("boom"))
+		q := NewLLMQualityScorer(client).Score(context.Background(), correction)
+		if q.LLMScore != nil || q.Score != heuristic.Score {
+			t.Errorf("Score = %+v, want heuristic %+v", q, heuristic)
+		}
+	})
+
+	t.Run("skips filler", func(t *testing.T) {
+		client := llm.NewMockClient().WithCompleteResponse(`{"score": 0.9}`)
+		q := NewLLMQualityScorer(client).Score(context.Background(), models.Correction{CorrectedAction: "no"})
+		if client.CompleteCallCount() != 0 {
+			t.Errorf("Complete called %d times, want 0", client.CompleteCallCount())
+		}
+		if q.Score != 0 {
+			t.Errorf("Score = %.3f, want 0", q.Score)
+		}
+	})
+}
+
+func TestParseCorrectionQualityResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     float64
+		wantErr  bool
+	}{
+		{"plain", `{"score": 0.75}`, 0.75, false},
+		{"wrapped", "Here you go:\n```json\n{\"score\": 0.4}\n```", 0.4, false},
+		{"out of range", `{"score": 1.5}`, 0, true},
+		{"missing", `{"rating": 0.5}`, 0, true},
+		{"no json", "0.5", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCorrectionQualityResponse(tt.response)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("score = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
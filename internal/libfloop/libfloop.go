@@ -0,0 +1,435 @@
+// Package libfloop is the in-process API behind the libfloop C-shared
+// library. Each operation takes a JSON request and returns a JSON response,
+// so the C ABI in cmd/libfloop only moves strings across the boundary and
+// stays stable as requests and responses grow new fields.
+//
+// Every call opens the project's stores and closes them before returning,
+// like a CLI invocation, so callers need no handle or teardown.
+package libfloop
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/corrections"
+	"github.com/nvandessel/floop/internal/dedup"
+	"github.com/nvandessel/floop/internal/edges"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/prompt"
+	"github.com/nvandessel/floop/internal/quota"
+	"github.com/nvandessel/floop/internal/sanitize"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/tagging"
+)
+
+// ABIVersion is the version of the C ABI and of the JSON envelope. It
+// changes only when an exported symbol or the envelope changes
+// incompatibly, never for new request or response fields.
+const ABIVersion = 1
+
+// Operations dispatched by Call.
+const (
+	OpLearn  = "learn"
+	OpActive = "active"
+	OpPrompt = "prompt"
+)
+
+// ErrNotInitialized is returned for a root without a .floop directory.
+var ErrNotInitialized = errors.New(".floop not initialized, run 'floop init' first")
+
+// Response is the envelope every Call returns: the operation's result when
+// OK, else the error message.
+type Response struct {
+	OK     bool        `json:"ok"`
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// Call runs the operation op on the JSON request and returns the JSON
+// encoded Response. It never panics across the C boundary: a panic in the
+// operation is returned as an error response.
+func Call(op string, request []byte) (out []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			out = encodeResponse(Response{Error: fmt.Sprintf("internal error: %v", r)})
+		}
+	}()
+
+	result, err := dispatch(context.Background(), op, request)
+	if err != nil {
+		return encodeResponse(Response{Error: err.Error()})
+	}
+	return encodeResponse(Response{OK: true, Result: result})
+}
+
+func dispatch(ctx context.Context, op string, request []byte) (interface{}, error) {
+	switch op {
+	case OpLearn:
+		var req LearnRequest
+		if err := decodeRequest(request, &req); err != nil {
+			return nil, err
+		}
+		return Learn(ctx, req)
+	case OpActive:
+		var req ActiveRequest
+		if err := decodeRequest(request, &req); err != nil {
+			return nil, err
+		}
+		return Active(ctx, req)
+	case OpPrompt:
+		var req PromptRequest
+		if err := decodeRequest(request, &req); err != nil {
+			return nil, err
+		}
+		return Prompt(ctx, req)
+	}
+	return nil, fmt.Errorf("unknown operation %q", op)
+}
+
+// decodeRequest decodes a JSON request, treating an empty one as {}.
+func decodeRequest(request []byte, v interface{}) error {
+	if len(request) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(request, v); err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+	return nil
+}
+
+func encodeResponse(resp Response) []byte {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		data, _ = json.Marshal(Response{Error: fmt.Sprintf("encoding response: %v", err)})
+	}
+	return data
+}
+
+// Context describes where the agent is working. It is the JSON form of the
+// context flags of 'floop active' and 'floop prompt'.
+type Context struct {
+	Root        string   `json:"root,omitempty"` // Project root (default: current directory)
+	File        string   `json:"file,omitempty"`
+	Task        string   `json:"task,omitempty"`
+	Language    string   `json:"language,omitempty"`
+	Environment string   `json:"env,omitempty"`
+	Agent       string   `json:"agent,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+
+	// Kinds and ExcludeKinds narrow the active behaviors by kind, like
+	// --kinds and --exclude-kinds.
+	Kinds        []string `json:"kinds,omitempty"`
+	ExcludeKinds []string `json:"exclude_kinds,omitempty"`
+}
+
+// snapshot builds the activation context.
+func (c Context) snapshot(root string) models.ContextSnapshot {
+	builder := activation.NewContextBuilder().
+		WithFile(c.File).
+		WithTask(c.Task).
+		WithEnvironment(c.Environment).
+		WithAgent(c.Agent).
+		WithTags(c.Tags).
+		WithRepoRoot(root)
+	if c.Language != "" {
+		builder.WithLanguage(c.Language)
+	}
+	return builder.Build()
+}
+
+// ActiveRequest asks for the behaviors active in a context.
+type ActiveRequest struct {
+	Context
+}
+
+// ActiveResponse lists the active behaviors.
+type ActiveResponse struct {
+	Context   models.ContextSnapshot `json:"context"`
+	Behaviors []models.Behavior      `json:"behaviors"`
+	Count     int                    `json:"count"`
+}
+
+// Active evaluates the behaviors of the local and global stores against the
+// request's context, like 'floop active --json'.
+func Active(ctx context.Context, req ActiveRequest) (*ActiveResponse, error) {
+	actCtx, resolved, err := activate(ctx, req.Context)
+	if err != nil {
+		return nil, err
+	}
+	return &ActiveResponse{
+		Context:   actCtx,
+		Behaviors: resolved.Active,
+		Count:     len(resolved.Active),
+	}, nil
+}
+
+// PromptRequest asks for the prompt section of a context.
+type PromptRequest struct {
+	Context
+	Format      string `json:"format,omitempty"`       // markdown (default), xml, or plain
+	TokenBudget int    `json:"token_budget,omitempty"` // Fit behaviors into this many tokens; 0 includes all in full
+	Tiered      bool   `json:"tiered,omitempty"`       // Summarize and omit behaviors to fit the budget instead of dropping them
+	Order       string `json:"order,omitempty"`        // Section ordering (default: prompt.ordering config)
+	Packing     string `json:"packing,omitempty"`      // Budget packing (default: prompt.packing config)
+}
+
+// Prompt compiles the behaviors active in the request's context into a
+// prompt section, like 'floop prompt --json'. The result is an
+// assembly.CompiledPrompt, or an assembly.TieredCompiledPrompt when tiered
+// with a budget.
+func Prompt(ctx context.Context, req PromptRequest) (interface{}, error) {
+	format, err := prompt.ParseFormat(req.Format)
+	if err != nil {
+		return nil, err
+	}
+	if req.TokenBudget < 0 {
+		return nil, fmt.Errorf("token_budget must be 0 or more")
+	}
+
+	var promptCfg config.PromptConfig
+	if cfg, err := config.Load(); err == nil {
+		promptCfg = cfg.Prompt
+	}
+
+	_, resolved, err := activate(ctx, req.Context)
+	if err != nil {
+		return nil, err
+	}
+	result, err := prompt.Compile(*resolved, promptCfg, prompt.Options{
+		Format:      format,
+		Task:        req.Task,
+		Order:       req.Order,
+		Packing:     req.Packing,
+		TokenBudget: req.TokenBudget,
+		Tiered:      req.Tiered,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Tiered != nil {
+		return result.Tiered, nil
+	}
+	return result.Prompt, nil
+}
+
+// activate loads the behaviors of both stores and resolves those active in
+// c.
+func activate(ctx context.Context, c Context) (models.ContextSnapshot, *activation.ResolveResult, error) {
+	kinds, err := activation.ParseKindFilter(c.Kinds, c.ExcludeKinds)
+	if err != nil {
+		return models.ContextSnapshot{}, nil, err
+	}
+	root, err := projectRoot(c.Root)
+	if err != nil {
+		return models.ContextSnapshot{}, nil, err
+	}
+	graphStore, err := store.NewMultiGraphStore(root)
+	if err != nil {
+		return models.ContextSnapshot{}, nil, fmt.Errorf("failed to open graph store: %w", err)
+	}
+	defer graphStore.Close()
+
+	var behaviors []models.Behavior
+	err = store.WalkNodes(ctx, graphStore, map[string]interface{}{"kind": string(store.NodeKindBehavior)}, func(node store.Node) error {
+		behaviors = append(behaviors, models.NodeToBehavior(node))
+		return nil
+	})
+	if err != nil {
+		return models.ContextSnapshot{}, nil, fmt.Errorf("failed to query behaviors: %w", err)
+	}
+	if err := edges.AttachRelationships(ctx, graphStore, behaviors); err != nil {
+		return models.ContextSnapshot{}, nil, err
+	}
+
+	// A config that fails to load is nil and keeps the default match options
+	cfg, _ := config.Load()
+	actCtx := c.snapshot(root)
+	resolved := prompt.Resolve(prompt.NewEvaluator(cfg), actCtx, behaviors, kinds)
+	if resolved.Active == nil {
+		resolved.Active = []models.Behavior{}
+	}
+	return actCtx, &resolved, nil
+}
+
+// LearnRequest is a correction to learn from, the JSON form of the flags
+// of 'floop learn'.
+type LearnRequest struct {
+	Root     string   `json:"root,omitempty"` // Project root (default: current directory)
+	Wrong    string   `json:"wrong,omitempty"`
+	Right    string   `json:"right"`
+	File     string   `json:"file,omitempty"`
+	Task     string   `json:"task,omitempty"`
+	Language string   `json:"language,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Scope    string   `json:"scope,omitempty"` // local or global; classified automatically when empty
+}
+
+// Learn statuses.
+const (
+	StatusProcessed    = "processed"
+	StatusHeld         = "held"          // Below the quality gate, kept for 'floop held'
+	StatusLimitReached = "limit_reached" // At a storage limit, kept for 'floop reprocess'
+)
+
+// LearnResponse is the outcome of learning a correction.
+type LearnResponse struct {
+	Status         string                  `json:"status"`
+	Correction     models.Correction       `json:"correction"`
+	Behavior       *models.Behavior        `json:"behavior,omitempty"`
+	AutoAccepted   bool                    `json:"auto_accepted,omitempty"`
+	RequiresReview bool                    `json:"requires_review,omitempty"`
+	ReviewReasons  []string                `json:"review_reasons,omitempty"`
+	Quarantined    bool                    `json:"quarantined,omitempty"`
+	Quality        *learning.QualityScore  `json:"quality,omitempty"`
+	Quota          *learning.QuotaExceeded `json:"quota,omitempty"`
+}
+
+// Learn records a correction and extracts a behavior from it, like
+// 'floop learn --json': similar behaviors are merged, the configured
+// quality gate and storage limits apply, and the correction is recorded in
+// the corrections log. The LLM extractor and LLM quality scoring are not
+// used in-process; behaviors are extracted by the rule-based extractor.
+func Learn(ctx context.Context, req LearnRequest) (*LearnResponse, error) {
+	right := sanitize.SanitizeBehaviorContent(req.Right)
+	if right == "" {
+		return nil, fmt.Errorf("right is required and cannot be empty after sanitization")
+	}
+	if len(req.Tags) > tagging.MaxExtraTags {
+		return nil, fmt.Errorf("tags accepts at most %d tags, got %d", tagging.MaxExtraTags, len(req.Tags))
+	}
+	root, err := projectRoot(req.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	snapshot := models.ContextSnapshot{
+		Timestamp: now,
+		FilePath:  sanitize.SanitizeFilePath(req.File),
+		Task:      sanitize.SanitizeBehaviorContent(req.Task),
+	}
+	if snapshot.FilePath != "" {
+		snapshot.FileLanguage = models.InferLanguage(snapshot.FilePath)
+		snapshot.FileExt = filepath.Ext(snapshot.FilePath)
+	}
+	if req.Language != "" {
+		snapshot.FileLanguage = sanitize.SanitizeBehaviorContent(req.Language)
+	}
+	correction := models.Correction{
+		ID:              fmt.Sprintf("c-%d", now.UnixNano()),
+		Timestamp:       now,
+		Context:         snapshot,
+		AgentAction:     sanitize.SanitizeBehaviorContent(req.Wrong),
+		CorrectedAction: right,
+		ExtraTags:       req.Tags,
+	}
+
+	graphStore, err := store.NewMultiGraphStore(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open graph store: %w", err)
+	}
+	defer graphStore.Close()
+
+	loopConfig, err := learnLoopConfig(graphStore, req.Scope)
+	if err != nil {
+		return nil, err
+	}
+	result, err := learning.NewLearningLoop(graphStore, loopConfig).ProcessCorrection(ctx, correction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process correction: %w", err)
+	}
+
+	floopDir := filepath.Join(root, ".floop")
+	resp := &LearnResponse{Correction: correction, Quality: result.Quality}
+	switch {
+	case result.Held:
+		held := learning.HeldCorrection{Correction: result.Correction, HeldAt: now, MinScore: loopConfig.MinQualityScore}
+		if result.Quality != nil {
+			held.Quality = *result.Quality
+		}
+		if err := learning.HoldCorrection(floopDir, held); err != nil {
+			return nil, err
+		}
+		resp.Status = StatusHeld
+		return resp, nil
+	case result.Quota != nil:
+		if err := corrections.Append(floopDir, correction); err != nil {
+			return nil, err
+		}
+		resp.Status, resp.Quota = StatusLimitReached, result.Quota
+		return resp, nil
+	}
+
+	correction.Processed = true
+	processedAt := time.Now()
+	correction.ProcessedAt = &processedAt
+	if err := corrections.Append(floopDir, correction); err != nil {
+		return nil, err
+	}
+	resp.Status = StatusProcessed
+	resp.Correction = correction
+	resp.Behavior = &result.CandidateBehavior
+	resp.AutoAccepted = result.AutoAccepted
+	resp.RequiresReview = result.RequiresReview
+	resp.ReviewReasons = result.ReviewReasons
+	resp.Quarantined = result.Injection != nil
+	return resp, nil
+}
+
+// learnLoopConfig is the learning loop configuration of 'floop learn' with
+// its defaults: auto-merge, the quality gate, and storage limits.
+func learnLoopConfig(graphStore store.GraphStore, scope string) (*learning.LearningLoopConfig, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.Default()
+	}
+
+	loopConfig := learning.DefaultLearningLoopConfig()
+	loopConfig.AutoMerge = true
+	loopConfig.Deduplicator = dedup.NewStoreDeduplicator(graphStore, dedup.NewBehaviorMerger(dedup.MergerConfig{}), dedup.DeduplicatorConfig{
+		SimilarityThreshold: constants.DefaultAutoMergeThreshold,
+		AutoMerge:           true,
+	})
+	if scope != "" {
+		s := constants.Scope(scope)
+		if s != constants.ScopeLocal && s != constants.ScopeGlobal {
+			return nil, fmt.Errorf("scope must be 'local' or 'global'")
+		}
+		loopConfig.ScopeOverride = &s
+	}
+	if cfg.Quality.Enabled {
+		loopConfig.QualityScorer = learning.NewQualityScorer(false, nil)
+		loopConfig.MinQualityScore = cfg.Quality.MinScore
+	}
+	loopConfig.Limits = quota.Limits{
+		MaxBehaviorsPerScope: cfg.Limits.MaxBehaviorsPerScope,
+		MaxCanonicalLength:   cfg.Limits.MaxCanonicalLength,
+		MaxEdgesPerNode:      cfg.Limits.MaxEdgesPerNode,
+	}
+	return &loopConfig, nil
+}
+
+// projectRoot resolves root, defaulting to the current directory, and
+// checks that it has been initialized.
+func projectRoot(root string) (string, error) {
+	if root == "" {
+		root = "."
+	}
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return "", fmt.Errorf("resolving root: %w", err)
+	}
+	if _, err := os.Stat(filepath.Join(abs, ".floop")); err != nil {
+		return "", ErrNotInitialized
+	}
+	return abs, nil
+}
@@ -0,0 +1,156 @@
+package libfloop
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/corrections"
+)
+
+// newProject creates an initialized project root with an isolated home.
+func newProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	home := filepath.Join(dir, "home")
+	root := filepath.Join(dir, "project")
+	for _, d := range []string{home, filepath.Join(root, ".floop")} {
+		if err := os.MkdirAll(d, 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	return root
+}
+
+// call runs op and decodes the envelope, with the result into result.
+func call(t *testing.T, op string, request interface{}, result interface{}) Response {
+	t.Helper()
+	req, err := json.Marshal(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp struct {
+		Response
+		Result json.RawMessage `json:"result"`
+	}
+	out := Call(op, req)
+	if err := json.Unmarshal(out, &resp); err != nil {
+		t.Fatalf("Call(%s) returned invalid JSON %s: %v", op, out, err)
+	}
+	if resp.OK && result != nil {
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			t.Fatalf("decoding %s result %s: %v", op, resp.Result, err)
+		}
+	}
+	return resp.Response
+}
+
+func TestCall_LearnActivePrompt(t *testing.T) {
+	root := newProject(t)
+
+	var learned LearnResponse
+	resp := call(t, OpLearn, LearnRequest{
+		Root:  root,
+		Wrong: "used fmt.Println for diagnostics",
+		Right: "write diagnostics to the structured logger instead of fmt.Println",
+		File:  "main.go",
+		Scope: "local",
+	}, &learned)
+	if !resp.OK {
+		t.Fatalf("learn failed: %s", resp.Error)
+	}
+	if learned.Status != StatusProcessed || learned.Behavior == nil {
+		t.Fatalf("learn = status %q, behavior %v; want a processed behavior", learned.Status, learned.Behavior)
+	}
+	if logged, err := corrections.Load(filepath.Join(root, ".floop")); err != nil || len(logged) != 1 || !logged[0].Processed {
+		t.Errorf("corrections = %+v, %v; want the processed correction", logged, err)
+	}
+
+	var active ActiveResponse
+	if resp := call(t, OpActive, ActiveRequest{Context{Root: root, File: "main.go"}}, &active); !resp.OK {
+		t.Fatalf("active failed: %s", resp.Error)
+	}
+	found := false
+	for _, b := range active.Behaviors {
+		found = found || b.ID == learned.Behavior.ID
+	}
+	if !found || active.Count != len(active.Behaviors) {
+		t.Errorf("active = %d behaviors (count %d), want %s among them", len(active.Behaviors), active.Count, learned.Behavior.ID)
+	}
+
+	var prompt struct {
+		Text   string `json:"text"`
+		Format string `json:"format"`
+	}
+	if resp := call(t, OpPrompt, PromptRequest{Context: Context{Root: root, File: "main.go"}, Format: "xml"}, &prompt); !resp.OK {
+		t.Fatalf("prompt failed: %s", resp.Error)
+	}
+	if prompt.Format != "xml" || !strings.Contains(prompt.Text, "structured logger") {
+		t.Errorf("prompt = %q in %s, want the learned behavior as xml", prompt.Text, prompt.Format)
+	}
+
+	var budgeted struct {
+		ExcludedBehaviors []string `json:"excluded_behaviors"`
+	}
+	if resp := call(t, OpPrompt, PromptRequest{Context: Context{Root: root, File: "main.go"}, TokenBudget: 1}, &budgeted); !resp.OK {
+		t.Fatalf("budgeted prompt failed: %s", resp.Error)
+	}
+	if len(budgeted.ExcludedBehaviors) == 0 {
+		t.Errorf("budgeted prompt excluded no behaviors from a 1 token budget")
+	}
+
+	var tiered struct {
+		TotalTokens int `json:"total_tokens"`
+	}
+	if resp := call(t, OpPrompt, PromptRequest{Context: Context{Root: root, File: "main.go"}, TokenBudget: 500, Tiered: true}, &tiered); !resp.OK {
+		t.Fatalf("tiered prompt failed: %s", resp.Error)
+	}
+	if tiered.TotalTokens == 0 || tiered.TotalTokens > 500 {
+		t.Errorf("tiered prompt total_tokens = %d, want within (0, 500]", tiered.TotalTokens)
+	}
+
+	var filtered ActiveResponse
+	if resp := call(t, OpActive, ActiveRequest{Context: Context{Root: root, File: "main.go", ExcludeKinds: []string{string(learned.Behavior.Kind)}}}, &filtered); !resp.OK {
+		t.Fatalf("active with exclude_kinds failed: %s", resp.Error)
+	}
+	for _, b := range filtered.Behaviors {
+		if b.ID == learned.Behavior.ID {
+			t.Errorf("exclude_kinds %s kept %s", learned.Behavior.Kind, b.ID)
+		}
+	}
+}
+
+func TestCall_Errors(t *testing.T) {
+	root := newProject(t)
+
+	tests := []struct {
+		name    string
+		op      string
+		request string
+		want    string
+	}{
+		{"unknown operation", "forget", `{}`, "unknown operation"},
+		{"invalid JSON", OpActive, `{"root":`, "invalid request"},
+		{"uninitialized root", OpActive, `{"root":"` + filepath.ToSlash(t.TempDir()) + `"}`, "not initialized"},
+		{"empty right", OpLearn, `{"root":"` + filepath.ToSlash(root) + `","right":"  "}`, "right is required"},
+		{"invalid scope", OpLearn, `{"root":"` + filepath.ToSlash(root) + `","right":"use x","scope":"team"}`, "scope must be"},
+		{"invalid format", OpPrompt, `{"root":"` + filepath.ToSlash(root) + `","format":"html"}`, "invalid format"},
+		{"invalid order", OpPrompt, `{"root":"` + filepath.ToSlash(root) + `","order":"random"}`, "random"},
+		{"invalid kind", OpActive, `{"root":"` + filepath.ToSlash(root) + `","kinds":["rule"]}`, "rule"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp Response
+			if err := json.Unmarshal(Call(tt.op, []byte(tt.request)), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.OK || !strings.Contains(resp.Error, tt.want) {
+				t.Errorf("Call() = ok %v, error %q; want error containing %q", resp.OK, resp.Error, tt.want)
+			}
+		})
+	}
+}
@@ -28,6 +28,10 @@ type ClientConfig struct {
 	// Model is the model identifier to use for requests.
 	Model string `json:"model,omitempty" yaml:"model,omitempty"`
 
+	// EmbeddingModel is the model identifier for embedding requests.
+	// Providers that support embeddings only produce them when it is set.
+	EmbeddingModel string `json:"embedding_model,omitempty" yaml:"embedding_model,omitempty"`
+
 	// Timeout is the maximum duration to wait for a response.
 	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
 }
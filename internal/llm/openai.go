@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"os"
 	"time"
+
+	"github.com/nvandessel/floop/internal/vecmath"
 )
 
 const (
@@ -18,12 +20,14 @@ const (
 )
 
 // OpenAIClient implements the Client interface using the OpenAI API.
-// It also works with OpenAI-compatible APIs like Ollama.
+// It also works with OpenAI-compatible APIs like Ollama. When an embedding
+// model is configured, it implements EmbeddingComparer through /embeddings.
 type OpenAIClient struct {
 	provider string
 	apiKey   string
 	baseURL  string
 	model    string
+	embModel string
 	timeout  time.Duration
 	client   *http.Client
 }
@@ -63,6 +67,7 @@ func NewOpenAIClient(config ClientConfig) *OpenAIClient {
 		apiKey:   apiKey,
 		baseURL:  baseURL,
 		model:    model,
+		embModel: config.EmbeddingModel,
 		timeout:  timeout,
 		client:   &http.Client{Timeout: timeout},
 	}
@@ -171,3 +176,83 @@ func (c *OpenAIClient) Available() bool {
 	}
 	return c.apiKey != ""
 }
+
+// openAIEmbeddingRequest represents a request to the OpenAI embeddings API.
+type openAIEmbeddingRequest struct {
+	Model string `json:"model"`
+	Input string `json:"input"`
+}
+
+// openAIEmbeddingResponse represents a response from the OpenAI embeddings API.
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// Embed returns a dense vector embedding for the given text from the
+// configured embedding model.
+func (c *OpenAIClient) Embed(ctx context.Context, text string) ([]float32, error) {
+	if c.embModel == "" {
+		return nil, fmt.Errorf("no embedding model configured (set llm.embedding_model)")
+	}
+	if !c.Available() {
+		return nil, fmt.Errorf("openai client not available: missing API key")
+	}
+
+	jsonBody, err := json.Marshal(openAIEmbeddingRequest{Model: c.embModel, Input: text})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/embeddings", bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var embResp openAIEmbeddingResponse
+	if err := json.Unmarshal(body, &embResp); err != nil {
+		return nil, fmt.Errorf("parsing API response: %w", err)
+	}
+	if embResp.Error != nil {
+		return nil, fmt.Errorf("API error: %s", embResp.Error.Message)
+	}
+	if len(embResp.Data) == 0 || len(embResp.Data[0].Embedding) == 0 {
+		return nil, fmt.Errorf("no embedding in API response")
+	}
+	return embResp.Data[0].Embedding, nil
+}
+
+// CompareEmbeddings embeds both texts and returns their cosine similarity.
+func (c *OpenAIClient) CompareEmbeddings(ctx context.Context, a, b string) (float64, error) {
+	embA, err := c.Embed(ctx, a)
+	if err != nil {
+		return 0, fmt.Errorf("embedding text a: %w", err)
+	}
+	embB, err := c.Embed(ctx, b)
+	if err != nil {
+		return 0, fmt.Errorf("embedding text b: %w", err)
+	}
+	return vecmath.CosineSimilarity(embA, embB), nil
+}
@@ -301,3 +301,54 @@ func TestOpenAIClient_Complete_ContextCancelled(t *testing.T) {
 		t.Fatal("expected error for cancelled context")
 	}
 }
+
+func TestOpenAIClient_Embed(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embeddings" {
+			t.Errorf("path = %q, want /embeddings", r.URL.Path)
+		}
+		var reqBody openAIEmbeddingRequest
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		if reqBody.Model != "text-embedding-3-small" {
+			t.Errorf("model = %q, want text-embedding-3-small", reqBody.Model)
+		}
+		vec := []float32{1, 0}
+		if reqBody.Input == "b" {
+			vec = []float32{0, 1}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": []map[string]interface{}{{"embedding": vec}},
+		})
+	}))
+	defer ts.Close()
+
+	c := NewOpenAIClient(ClientConfig{
+		APIKey:         "test-key",
+		BaseURL:        ts.URL,
+		EmbeddingModel: "text-embedding-3-small",
+	})
+	vec, err := c.Embed(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if len(vec) != 2 || vec[0] != 1 {
+		t.Errorf("Embed() = %v, want [1 0]", vec)
+	}
+	sim, err := c.CompareEmbeddings(context.Background(), "a", "b")
+	if err != nil {
+		t.Fatalf("CompareEmbeddings() error = %v", err)
+	}
+	if sim != 0 {
+		t.Errorf("CompareEmbeddings() = %v, want 0 for orthogonal vectors", sim)
+	}
+}
+
+func TestOpenAIClient_Embed_NoModel(t *testing.T) {
+	c := NewOpenAIClient(ClientConfig{APIKey: "test-key"})
+	if _, err := c.Embed(context.Background(), "a"); err == nil {
+		t.Fatal("expected error without an embedding model")
+	}
+}
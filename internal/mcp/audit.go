@@ -2,11 +2,14 @@ package mcp
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/nvandessel/floop/internal/learning"
 )
 
 // AuditEntry represents a single audit log entry for an MCP tool invocation.
@@ -16,7 +19,7 @@ type AuditEntry struct {
 	Tool       string            `json:"tool"`
 	Scope      string            `json:"scope"` // "local" or "global"
 	DurationMs int64             `json:"duration_ms"`
-	Status     string            `json:"status"` // "success" or "error"
+	Status     string            `json:"status"` // "success", "error", or "circuit_open"
 	Error      string            `json:"error,omitempty"`
 	Params     map[string]string `json:"params,omitempty"` // sanitized metadata only
 }
@@ -159,33 +162,67 @@ func sanitizeToolParams(toolName string, params map[string]interface{}) map[stri
 
 	// Safe parameter names whose VALUES are safe to log
 	safeValueParams := map[string]bool{
-		"scope":         true,
-		"threshold":     true,
-		"dry_run":       true,
-		"format":        true,
-		"mode":          true,
-		"bidirectional": true,
-		"kind":          true,
-		"tag":           true,
-		"corrections":   true,
-		"signal":        true,
-		"language":      true,
+		"scope":             true,
+		"threshold":         true,
+		"dry_run":           true,
+		"format":            true,
+		"mode":              true,
+		"bidirectional":     true,
+		"kind":              true,
+		"kinds":             true,
+		"exclude_kinds":     true,
+		"tag":               true,
+		"corrections":       true,
+		"signal":            true,
+		"language":          true,
+		"expires_at":        true,
+		"tags":              true,
+		"min_confidence":    true,
+		"max_confidence":    true,
+		"limit":             true,
+		"min_score":         true,
+		"depth":             true,
+		"top_n":             true,
+		"min_weight":        true,
+		"items":             true,
+		"full":              true,
+		"ttl_seconds":       true,
+		"all":               true,
+		"strategy":          true,
+		"move":              true,
+		"force":             true,
+		"suggest":           true,
+		"min_confirmations": true,
 	}
 
 	// Parameters whose existence is safe to log but whose values may contain
 	// sensitive information (file paths, behavior IDs, content, etc.)
 	presenceOnlyParams := map[string]bool{
-		"wrong":       true,
-		"right":       true,
-		"file":        true,
-		"task":        true,
-		"source":      true,
-		"target":      true,
-		"input_path":  true,
-		"output_path": true,
-		"weight":      true,
-		"auto_merge":  true,
-		"behavior_id": true,
+		"wrong":        true,
+		"right":        true,
+		"file":         true,
+		"files":        true,
+		"task":         true,
+		"source":       true,
+		"target":       true,
+		"input_path":   true,
+		"output_path":  true,
+		"weight":       true,
+		"auto_merge":   true,
+		"behavior_id":  true,
+		"text":         true,
+		"related_to":   true,
+		"command":      true,
+		"action":       true,
+		"error":        true,
+		"fix":          true,
+		"behavior_ids": true,
+		"session_id":   true,
+		"ref":          true,
+		"note":         true,
+		"around":       true,
+		"pin":          true,
+		"release":      true,
 	}
 
 	for key, val := range params {
@@ -212,17 +249,26 @@ func (s *Server) auditTool(toolName string, start time.Time, err error, params m
 	if err != nil {
 		status = "error"
 		errMsg = err.Error()
+		var breakerErr *learning.BreakerError
+		if errors.As(err, &breakerErr) {
+			status = "circuit_open"
+		}
 	}
 
 	if scope == "" {
 		scope = "local"
 	}
 
+	duration := time.Since(start)
+	if s.metrics != nil {
+		s.metrics.ObserveCall(toolName, duration, err)
+	}
+
 	s.auditLogger.Log(AuditEntry{
 		Timestamp:  start,
 		Tool:       toolName,
 		Scope:      scope,
-		DurationMs: time.Since(start).Milliseconds(),
+		DurationMs: duration.Milliseconds(),
 		Status:     status,
 		Error:      errMsg,
 		Params:     params,
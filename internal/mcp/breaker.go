@@ -0,0 +1,38 @@
+package mcp
+
+import (
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/learning"
+)
+
+// newLearnBreaker creates the session's floop_learn circuit breaker, or nil
+// when learning.circuit_breaker is disabled.
+func newLearnBreaker(cfg *config.FloopConfig) *learning.CircuitBreaker {
+	if cfg == nil || !cfg.Learning.CircuitBreaker.Enabled {
+		return nil
+	}
+	cb := cfg.Learning.CircuitBreaker
+	return learning.NewCircuitBreaker(learning.BreakerConfig{
+		MaxPerSession: cb.MaxPerSession,
+		Window:        cb.Window,
+		Similarity:    cb.Similarity,
+		MaxRepeats:    cb.MaxRepeats,
+		Cooldown:      cb.Cooldown,
+	})
+}
+
+// circuitOpenErrorData converts a BreakerError to its wire form.
+func circuitOpenErrorData(e *learning.BreakerError) CircuitOpenErrorData {
+	msg := "Stop calling floop_learn for the rest of this session."
+	if e.RetryAfter > 0 {
+		msg = "Stop calling floop_learn until the cool-down ends; do not retry the same correction."
+	}
+	return CircuitOpenErrorData{
+		Error:             "circuit_open",
+		Tool:              "floop_learn",
+		Reason:            e.Reason,
+		Detail:            e.Detail,
+		RetryAfterSeconds: e.RetryAfterSeconds(),
+		Message:           msg,
+	}
+}
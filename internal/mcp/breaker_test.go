@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/ratelimit"
+)
+
+func TestLearnCircuitBreaker_StructuredError(t *testing.T) {
+	server, tmpDir := setupTestServer(t)
+	defer server.Close()
+	server.toolLimiters = ratelimit.Limits{}
+	server.learnBreaker = learning.NewCircuitBreaker(learning.BreakerConfig{
+		Window: 5, Similarity: 0.8, MaxRepeats: 2, Cooldown: 10 * time.Minute,
+	})
+
+	ctx := context.Background()
+	serverTransport, clientTransport := sdk.NewInMemoryTransports()
+	if _, err := server.server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("server Connect() error = %v", err)
+	}
+	client := sdk.NewClient(&sdk.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client Connect() error = %v", err)
+	}
+	defer session.Close()
+
+	params := &sdk.CallToolParams{Name: "floop_learn", Arguments: map[string]interface{}{
+		"wrong": "Ran the tests without the race detector",
+		"right": "Always run go tests with the race detector enabled",
+	}}
+	for i := 0; i < 2; i++ {
+		if result, err := session.CallTool(ctx, params); err != nil || result.IsError {
+			t.Fatalf("call %d = %+v, %v; want success", i+1, result, err)
+		}
+	}
+
+	result, err := session.CallTool(ctx, params)
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("third identical correction should trip the breaker")
+	}
+	if text, ok := result.Content[0].(*sdk.TextContent); !ok || !strings.Contains(text.Text, "stop calling floop_learn") {
+		t.Errorf("error content = %+v, want the stop instruction", result.Content)
+	}
+	data, ok := result.StructuredContent.(map[string]any)
+	if !ok {
+		t.Fatalf("StructuredContent = %#v, want an object", result.StructuredContent)
+	}
+	if data["error"] != "circuit_open" || data["reason"] != learning.BreakerRepetitive || data["retry_after_seconds"] != 600.0 {
+		t.Errorf("StructuredContent = %v", data)
+	}
+
+	audit, err := os.ReadFile(filepath.Join(tmpDir, ".floop", "audit.jsonl"))
+	if err != nil {
+		t.Fatalf("reading audit log: %v", err)
+	}
+	if !strings.Contains(string(audit), `"status":"circuit_open"`) {
+		t.Errorf("audit log missing the tripped call:\n%s", audit)
+	}
+}
+
+func TestNewLearnBreaker_Disabled(t *testing.T) {
+	cfg := config.Default()
+	cfg.Learning.CircuitBreaker.Enabled = false
+	if b := newLearnBreaker(cfg); b != nil {
+		t.Errorf("newLearnBreaker() = %v, want nil when disabled", b)
+	}
+}
@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/capabilities"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestInitialize_AdvertisesCapabilities(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	serverTransport, clientTransport := sdk.NewInMemoryTransports()
+	if _, err := server.server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("server Connect() error = %v", err)
+	}
+	client := sdk.NewClient(&sdk.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client Connect() error = %v", err)
+	}
+	defer session.Close()
+
+	caps := session.InitializeResult().Capabilities
+	if caps.Logging == nil || caps.Tools == nil || caps.Resources == nil {
+		t.Errorf("standard capabilities missing: %+v", caps)
+	}
+	floop, ok := caps.Experimental["floop"].(map[string]any)
+	if !ok {
+		t.Fatalf("experimental.floop = %#v, want an object", caps.Experimental["floop"])
+	}
+	if got := floop["schema_version"]; got != float64(store.SchemaVersion) {
+		t.Errorf("schema_version = %v, want %d", got, store.SchemaVersion)
+	}
+
+	// The advertised tool and resource lists match what is registered
+	tools, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+	var toolNames []string
+	for _, tool := range tools.Tools {
+		toolNames = append(toolNames, tool.Name)
+	}
+	assertSameSet(t, "tools", toolNames, capabilities.MCPTools)
+
+	resources, err := session.ListResources(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListResources() error = %v", err)
+	}
+	templates, err := session.ListResourceTemplates(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListResourceTemplates() error = %v", err)
+	}
+	var uris []string
+	for _, r := range resources.Resources {
+		uris = append(uris, r.URI)
+	}
+	for _, r := range templates.ResourceTemplates {
+		uris = append(uris, r.URITemplate)
+	}
+	assertSameSet(t, "resources", uris, capabilities.MCPResources)
+}
+
+func assertSameSet(t *testing.T, what string, got, want []string) {
+	t.Helper()
+	got = append([]string(nil), got...)
+	want = append([]string(nil), want...)
+	sort.Strings(got)
+	sort.Strings(want)
+	if len(got) != len(want) {
+		t.Errorf("%s = %v, want %v", what, got, want)
+		return
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("%s = %v, want %v", what, got, want)
+			return
+		}
+	}
+}
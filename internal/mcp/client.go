@@ -0,0 +1,33 @@
+package mcp
+
+import (
+	"strings"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/sanitize"
+)
+
+// clientName returns the calling client's name as reported in the MCP
+// initialize handshake (e.g. "claude-code", "cursor"), lowercased with
+// whitespace replaced by hyphens and sanitized. It returns "" when the request carries no session or the client
+// did not identify itself.
+func clientName(req *sdk.CallToolRequest) string {
+	if req == nil {
+		return ""
+	}
+	return sessionClientName(req.Session)
+}
+
+// sessionClientName returns the normalized client name for a session, as
+// clientName does for a request.
+func sessionClientName(ss *sdk.ServerSession) string {
+	if ss == nil {
+		return ""
+	}
+	params := ss.InitializeParams()
+	if params == nil || params.ClientInfo == nil {
+		return ""
+	}
+	name := strings.Join(strings.Fields(strings.ToLower(params.ClientInfo.Name)), "-")
+	return sanitize.SanitizeBehaviorName(name)
+}
@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestClientName_NoSession(t *testing.T) {
+	if got := clientName(nil); got != "" {
+		t.Errorf("clientName(nil) = %q, want empty", got)
+	}
+	if got := clientName(&sdk.CallToolRequest{}); got != "" {
+		t.Errorf("clientName(no session) = %q, want empty", got)
+	}
+}
+
+func TestHandleFloopActive_RecordsClientActivation(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+
+	node := store.Node{
+		ID:   "go-directive",
+		Kind: "behavior",
+		Content: map[string]interface{}{
+			"name": "go-directive",
+			"kind": "directive",
+			"content": map[string]interface{}{
+				"canonical": "Use gofmt",
+			},
+			"when": map[string]interface{}{
+				"language": "go",
+			},
+		},
+	}
+	if _, err := server.store.AddNode(ctx, node); err != nil {
+		t.Fatalf("Failed to add node: %v", err)
+	}
+
+	serverTransport, clientTransport := sdk.NewInMemoryTransports()
+	if _, err := server.server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("server Connect() error = %v", err)
+	}
+	client := sdk.NewClient(&sdk.Implementation{Name: "Claude Code", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client Connect() error = %v", err)
+	}
+	defer session.Close()
+
+	result, err := session.CallTool(ctx, &sdk.CallToolParams{
+		Name:      "floop_active",
+		Arguments: map[string]interface{}{"language": "go"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("floop_active returned an error result: %+v", result.Content)
+	}
+
+	// Wait for background activation recording
+	server.workerWg.Wait()
+
+	statsStore, ok := server.store.(store.ClientStatsStore)
+	if !ok {
+		t.Fatal("store should implement ClientStatsStore")
+	}
+	stats, err := statsStore.GetClientStats(ctx)
+	if err != nil {
+		t.Fatalf("GetClientStats() error = %v", err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("GetClientStats() = %+v, want one client", stats)
+	}
+	if stats[0].Client != "claude-code" || stats[0].Activations != 1 {
+		t.Errorf("stats[0] = %+v, want client claude-code with 1 activation", stats[0])
+	}
+}
@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// DeadlineError reports a tool call abandoned at its deadline. The handler
+// keeps running in the background, so writes it was making may still land.
+type DeadlineError struct {
+	Tool    string
+	Timeout time.Duration
+}
+
+func (e *DeadlineError) Error() string {
+	return fmt.Sprintf("%s did not finish within its %v deadline (tool_timeouts); it may still complete in the background", e.Tool, e.Timeout)
+}
+
+// Unwrap lets errors.Is match context.DeadlineExceeded.
+func (e *DeadlineError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// toolTimeout returns the deadline of tool, or zero if it has none.
+func (s *Server) toolTimeout(tool string) time.Duration {
+	if s.floopConfig == nil {
+		return 0
+	}
+	return s.floopConfig.ToolTimeouts.Timeout(tool)
+}
+
+// deadlineMiddleware runs each tool call under its configured deadline.
+// Handlers see the deadline on their context and may answer with a partial
+// result; one still running when it passes is abandoned, and the agent gets
+// a DeadlineError instead of waiting on a slow store or LLM provider.
+func (s *Server) deadlineMiddleware(next sdk.MethodHandler) sdk.MethodHandler {
+	return func(ctx context.Context, method string, req sdk.Request) (sdk.Result, error) {
+		call, ok := req.(*sdk.CallToolRequest)
+		if method != "tools/call" || !ok || call.Params == nil {
+			return next(ctx, method, req)
+		}
+		tool := call.Params.Name
+		timeout := s.toolTimeout(tool)
+		if timeout <= 0 {
+			return next(ctx, method, req)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		type outcome struct {
+			res sdk.Result
+			err error
+		}
+		done := make(chan outcome, 1)
+		go func() {
+			res, err := next(ctx, method, req)
+			done <- outcome{res, err}
+		}()
+
+		select {
+		case o := <-done:
+			return o.res, o.err
+		case <-ctx.Done():
+		}
+
+		// A handler finishing right at the deadline still gets its answer in
+		select {
+		case o := <-done:
+			return o.res, o.err
+		default:
+		}
+		if ctx.Err() != context.DeadlineExceeded {
+			return nil, ctx.Err()
+		}
+
+		s.logger.Warn("tool call abandoned at deadline", "tool", tool, "timeout", timeout)
+		if s.metrics != nil {
+			s.metrics.ObserveTimedOut(tool)
+		}
+		result := &sdk.CallToolResult{}
+		result.SetError(&DeadlineError{Tool: tool, Timeout: timeout})
+		return result, nil
+	}
+}
+
+// deadlineErrorData converts a DeadlineError to its wire form.
+func deadlineErrorData(e *DeadlineError) DeadlineErrorData {
+	return DeadlineErrorData{
+		Error:          "deadline_exceeded",
+		Tool:           e.Tool,
+		TimeoutSeconds: e.Timeout.Seconds(),
+	}
+}
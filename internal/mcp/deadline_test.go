@@ -0,0 +1,141 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/spreading"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// blockingActivator never finishes spreading before its context is done.
+type blockingActivator struct{}
+
+func (blockingActivator) Activate(ctx context.Context, _ []spreading.Seed) ([]spreading.Result, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func toolCall(name string) *sdk.CallToolRequest {
+	return &sdk.CallToolRequest{Params: &sdk.CallToolParamsRaw{Name: name}}
+}
+
+func TestDeadlineMiddleware_AbandonsSlowCall(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	server.floopConfig.ToolTimeouts.Tools = map[string]time.Duration{"floop_list": 20 * time.Millisecond}
+
+	release := make(chan struct{})
+	defer close(release)
+	slow := func(ctx context.Context, method string, req sdk.Request) (sdk.Result, error) {
+		<-release
+		return &sdk.CallToolResult{}, nil
+	}
+
+	handler := rateLimitMiddleware(server.deadlineMiddleware(slow))
+	res, err := handler(context.Background(), "tools/call", toolCall("floop_list"))
+	if err != nil {
+		t.Fatalf("middleware error = %v", err)
+	}
+	result, ok := res.(*sdk.CallToolResult)
+	if !ok || !result.IsError {
+		t.Fatalf("result = %#v, want a tool error", res)
+	}
+	var deadlineErr *DeadlineError
+	if !errors.As(result.GetError(), &deadlineErr) || deadlineErr.Tool != "floop_list" || deadlineErr.Timeout != 20*time.Millisecond {
+		t.Errorf("error = %v, want a floop_list DeadlineError", result.GetError())
+	}
+	if !errors.Is(result.GetError(), context.DeadlineExceeded) {
+		t.Error("DeadlineError should match context.DeadlineExceeded")
+	}
+	if data, ok := result.StructuredContent.(DeadlineErrorData); !ok || data.Error != "deadline_exceeded" || data.TimeoutSeconds != 0.02 {
+		t.Errorf("StructuredContent = %#v, want deadline_exceeded data", result.StructuredContent)
+	}
+	if snap := server.metrics.Snapshot(); len(snap.Tools) != 1 || snap.Tools[0].TimedOut != 1 {
+		t.Errorf("metrics = %+v, want one floop_list timeout", snap.Tools)
+	}
+}
+
+func TestDeadlineMiddleware_PassesDeadlineToHandler(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	server.floopConfig.ToolTimeouts.Tools = map[string]time.Duration{"floop_list": time.Minute, "floop_graph": -1}
+
+	var hasDeadline bool
+	next := func(ctx context.Context, method string, req sdk.Request) (sdk.Result, error) {
+		_, hasDeadline = ctx.Deadline()
+		return &sdk.CallToolResult{}, nil
+	}
+	handler := server.deadlineMiddleware(next)
+
+	res, err := handler(context.Background(), "tools/call", toolCall("floop_list"))
+	if err != nil || res.(*sdk.CallToolResult).IsError {
+		t.Fatalf("fast call = %+v, %v; want success", res, err)
+	}
+	if !hasDeadline {
+		t.Error("floop_list handler context has no deadline")
+	}
+
+	if _, err := handler(context.Background(), "tools/call", toolCall("floop_graph")); err != nil {
+		t.Fatal(err)
+	}
+	if hasDeadline {
+		t.Error("floop_graph has its deadline disabled but the handler context has one")
+	}
+}
+
+func TestHandleFloopActive_PartialAtDeadline(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	server.activator = blockingActivator{}
+
+	ctx := context.Background()
+	node := store.Node{
+		ID:   "go-errors",
+		Kind: "behavior",
+		Content: map[string]interface{}{
+			"name":    "go-errors",
+			"kind":    "directive",
+			"content": map[string]interface{}{"canonical": "Wrap errors with context"},
+			"when":    map[string]interface{}{"language": "go"},
+		},
+		Metadata: map[string]interface{}{"confidence": 0.9},
+	}
+	if _, err := server.store.AddNode(ctx, node); err != nil {
+		t.Fatalf("AddNode() error = %v", err)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, 400*time.Millisecond)
+	defer cancel()
+	_, out, err := server.handleFloopActive(callCtx, &sdk.CallToolRequest{}, FloopActiveInput{Language: "go"})
+	if err != nil {
+		t.Fatalf("handleFloopActive() error = %v", err)
+	}
+	if !out.Partial {
+		t.Error("output should be marked partial when spreading runs out of time")
+	}
+	if !activeIDs(out.Active)["go-errors"] {
+		t.Errorf("active = %+v, want the direct match go-errors", out.Active)
+	}
+}
+
+func TestSpreadingContext(t *testing.T) {
+	ctx, cancel := spreadingContext(context.Background())
+	cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("spreading context without a tool deadline should have none")
+	}
+
+	parent, cancelParent := context.WithTimeout(context.Background(), time.Minute)
+	defer cancelParent()
+	ctx, cancel = spreadingContext(parent)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+	parentDeadline, _ := parent.Deadline()
+	if reserve := parentDeadline.Sub(deadline); reserve < 14*time.Second || reserve > 16*time.Second {
+		t.Errorf("spreading leaves %v before the tool deadline, want about a quarter (15s)", reserve)
+	}
+}
@@ -0,0 +1,71 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/nvandessel/floop/internal/decay"
+	"github.com/nvandessel/floop/internal/utils"
+)
+
+// startDecaySchedule runs a decay pass whenever one is due while the server
+// is up. Passes are recorded in floopDir, so the interval holds across
+// server restarts and manual 'floop decay' runs.
+func (s *Server) startDecaySchedule(floopDir string) {
+	interval, err := utils.ParseDuration(s.floopConfig.Decay.Interval)
+	if err != nil || interval <= 0 {
+		s.logger.Warn("invalid decay interval, scheduled decay disabled", "interval", s.floopConfig.Decay.Interval)
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(min(interval, time.Hour))
+		defer ticker.Stop()
+		for {
+			s.maybeRunDecay(floopDir, interval)
+			select {
+			case <-s.done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// maybeRunDecay runs a decay pass in the background if one is due and not
+// already running.
+func (s *Server) maybeRunDecay(floopDir string, interval time.Duration) {
+	due, err := decay.Due(floopDir, interval, time.Now())
+	if err != nil {
+		s.logger.Warn("failed to check decay schedule", "error", err)
+		return
+	}
+	if !due {
+		return
+	}
+
+	s.runBackground("decay", func() {
+		if !s.decaying.CompareAndSwap(false, true) {
+			return
+		}
+		defer s.decaying.Store(false)
+
+		cfg := s.floopConfig.Decay
+		report, err := decay.Run(context.Background(), s.store, decay.Options{
+			DemoteAfter: time.Duration(cfg.DemoteAfterDays) * 24 * time.Hour,
+			PruneAfter:  time.Duration(cfg.PruneAfterDays) * 24 * time.Hour,
+		})
+		if err != nil {
+			s.logger.Warn("decay pass failed", "error", err)
+			return
+		}
+		if err := decay.RecordReport(floopDir, report); err != nil {
+			s.logger.Warn("failed to record decay report", "error", err)
+		}
+		s.logger.Info("decay pass complete", "demoted", len(report.Demoted), "pruned", len(report.Pruned))
+		s.emitPruned(report)
+		if report.Changes() > 0 {
+			s.debouncedRefreshPageRank()
+		}
+	})
+}
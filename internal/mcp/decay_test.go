@@ -0,0 +1,28 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/decay"
+)
+
+func TestMaybeRunDecay_RecordsPassAndWaitsForInterval(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	floopDir := t.TempDir()
+
+	server.maybeRunDecay(floopDir, 24*time.Hour)
+	server.workerWg.Wait()
+
+	last, err := decay.LastRun(floopDir)
+	if err != nil || last.IsZero() {
+		t.Fatalf("LastRun() = %v, %v; want a recorded pass", last, err)
+	}
+
+	server.maybeRunDecay(floopDir, 24*time.Hour)
+	server.workerWg.Wait()
+	if again, _ := decay.LastRun(floopDir); !again.Equal(last) {
+		t.Errorf("second pass ran within the interval: %v, then %v", last, again)
+	}
+}
@@ -254,6 +254,24 @@ func TestE2E_FullPipeline(t *testing.T) {
 			t.Errorf("EdgesRestored = %d, want >= 2", restoreOut.EdgesRestored)
 		}
 
+		// Merging the same backup again finds nothing to resolve
+		_, mergeOut, err := server2.handleFloopRestore(ctx, nil, FloopRestoreInput{
+			InputPath: backupPath2,
+			Strategy:  "take-backup",
+		})
+		if err != nil {
+			t.Fatalf("merge restore failed: %v", err)
+		}
+		if mergeOut.NodesRestored != 0 || mergeOut.NodesUpdated != 0 || len(mergeOut.Conflicts) != 0 {
+			t.Errorf("merge restore = %+v, want every node skipped without conflicts", mergeOut)
+		}
+		if _, _, err := server2.handleFloopRestore(ctx, nil, FloopRestoreInput{
+			InputPath: backupPath2,
+			Strategy:  "overwrite",
+		}); err == nil {
+			t.Error("restore with an invalid strategy should fail")
+		}
+
 		// Verify restored data is usable
 		_, listOut, err := server2.handleFloopList(ctx, nil, FloopListInput{})
 		if err != nil {
@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/consolidation"
+	"github.com/nvandessel/floop/internal/decay"
+	"github.com/nvandessel/floop/internal/dedup"
+	"github.com/nvandessel/floop/internal/eventbus"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/retirement"
+)
+
+// eventBusCloseTimeout bounds how long shutdown waits for queued events to
+// be delivered.
+const eventBusCloseTimeout = 5 * time.Second
+
+// defaultEventLog is where a file sink without a path writes, relative to
+// the project root.
+var defaultEventLog = filepath.Join(".floop", "events.jsonl")
+
+// newEventBus builds and starts the event bus for the configured sinks, or
+// returns nil when none are configured. stdout receives the events of
+// stdout sinks: the server passes its stderr, since its stdout carries the
+// MCP protocol. A sink that cannot be opened is skipped with a warning.
+func newEventBus(cfg config.EventBusConfig, root string, stdout io.Writer, logger *slog.Logger) *eventbus.Bus {
+	if len(cfg.Sinks) == 0 {
+		return nil
+	}
+	bus := eventbus.New(logger)
+	for i, sc := range cfg.Sinks {
+		name := fmt.Sprintf("%s[%d]", sc.Type, i)
+		switch sc.Type {
+		case "file":
+			path := sc.Path
+			if path == "" {
+				path = defaultEventLog
+			}
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(root, path)
+			}
+			sink, err := eventbus.NewFileSink(path)
+			if err != nil {
+				logger.Warn("event sink unavailable", "sink", name, "error", err)
+				continue
+			}
+			bus.Subscribe(name, sink, sc.Events...)
+		case "webhook":
+			bus.Subscribe(name, eventbus.NewWebhookSink(sc.URL, sc.Timeout), sc.Events...)
+		case "stdout":
+			bus.Subscribe(name, eventbus.NewWriterSink(stdout), sc.Events...)
+		default:
+			logger.Warn("unknown event sink type", "sink", name)
+		}
+	}
+	if bus.Len() == 0 {
+		return nil
+	}
+	bus.Start()
+	return bus
+}
+
+// emit sends e to the event bus, stamped with the server's project.
+func (s *Server) emit(e eventbus.Event) {
+	if s.eventBus == nil {
+		return
+	}
+	e.Project = s.projectID
+	s.eventBus.Emit(e)
+}
+
+// emitLearned emits the event for a learn result: behavior.merged when the
+// correction was merged into an existing behavior, else behavior.learned.
+func (s *Server) emitLearned(source string, res *learning.LearningResult) {
+	e := eventbus.Event{
+		Type:         eventbus.BehaviorLearned,
+		Source:       source,
+		BehaviorID:   res.CandidateBehavior.ID,
+		BehaviorName: res.CandidateBehavior.Name,
+		Scope:        string(res.Scope),
+	}
+	if res.MergedIntoExisting {
+		e.Type = eventbus.BehaviorMerged
+		e.MergedIDs = []string{res.MergedBehaviorID}
+		e.Reason = fmt.Sprintf("auto-merged on learn (similarity %.2f)", res.MergeSimilarity)
+	}
+	s.emit(e)
+}
+
+// emitMerged emits behavior.merged for each merge in a deduplication run.
+func (s *Server) emitMerged(source string, report *dedup.DeduplicationReport) {
+	for _, merged := range report.MergedBehaviors {
+		s.emit(eventbus.Event{
+			Type:         eventbus.BehaviorMerged,
+			Source:       source,
+			BehaviorID:   merged.ID,
+			BehaviorName: merged.Name,
+			MergedIDs:    report.MergedFrom[merged.ID],
+			Reason:       "duplicates merged by deduplication",
+		})
+	}
+}
+
+// emitSleepEvents emits the merges and the backup of a sleep phase run.
+func (s *Server) emitSleepEvents(report *consolidation.SleepReport) {
+	if report.Backup != "" {
+		s.emit(eventbus.Event{Type: eventbus.BackupCreated, Source: "sleep-phase", Path: report.Backup})
+	}
+	for _, m := range report.Merged {
+		for _, id := range m.KeptIDs {
+			s.emit(eventbus.Event{
+				Type:       eventbus.BehaviorMerged,
+				Source:     "sleep-phase",
+				BehaviorID: id,
+				Scope:      m.Scope,
+				MergedIDs:  m.MergedFrom[id],
+				Reason:     "duplicates merged by the sleep phase",
+			})
+		}
+	}
+}
+
+// emitForgotten emits behavior.forgotten for each retirement notice that
+// ended with the behavior forgotten.
+func (s *Server) emitForgotten(source string, notices []retirement.Notice) {
+	for _, n := range notices {
+		if n.Outcome != retirement.OutcomeForgotten {
+			continue
+		}
+		s.emit(eventbus.Event{
+			Type:         eventbus.BehaviorForgotten,
+			Time:         n.At,
+			Source:       source,
+			BehaviorID:   n.BehaviorID,
+			BehaviorName: n.Name,
+			Reason:       fmt.Sprintf("retired on %s and not restored", n.RetiredAt.Format("2006-01-02")),
+		})
+	}
+}
+
+// emitPruned emits behavior.forgotten for each behavior a decay pass pruned.
+func (s *Server) emitPruned(report *decay.Report) {
+	for _, c := range report.Pruned {
+		s.emit(eventbus.Event{
+			Type:         eventbus.BehaviorForgotten,
+			Time:         report.StartedAt,
+			Source:       "decay",
+			BehaviorID:   c.BehaviorID,
+			BehaviorName: c.Name,
+			Scope:        c.Scope,
+			Reason:       fmt.Sprintf("pruned by decay after %d idle days", c.IdleDays),
+		})
+	}
+}
+
+// emitBackup emits backup.created for a backup written to path.
+func (s *Server) emitBackup(source, path string, nodes, edges int) {
+	s.emit(eventbus.Event{Type: eventbus.BackupCreated, Source: source, Path: path, Nodes: nodes, Edges: edges})
+}
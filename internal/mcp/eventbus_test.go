@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/decay"
+	"github.com/nvandessel/floop/internal/dedup"
+	"github.com/nvandessel/floop/internal/eventbus"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/retirement"
+)
+
+func TestNewEventBus_None(t *testing.T) {
+	if bus := newEventBus(config.EventBusConfig{}, t.TempDir(), nil, slog.New(slog.DiscardHandler)); bus != nil {
+		t.Errorf("newEventBus() without sinks = %v, want nil", bus)
+	}
+}
+
+func TestServerEmitsEvents(t *testing.T) {
+	root := t.TempDir()
+	var stdout bytes.Buffer
+	bus := newEventBus(config.EventBusConfig{Sinks: []config.EventSinkConfig{
+		{Type: "file"},
+		{Type: "stdout", Events: []string{eventbus.BehaviorForgotten}},
+	}}, root, &stdout, slog.New(slog.DiscardHandler))
+	if bus.Len() != 2 {
+		t.Fatalf("newEventBus() has %d sinks, want 2", bus.Len())
+	}
+	s := &Server{eventBus: bus, projectID: "proj"}
+
+	s.emitLearned("floop_learn", &learning.LearningResult{
+		CandidateBehavior: models.Behavior{ID: "b1", Name: "use-gofmt"},
+		Scope:             constants.ScopeLocal,
+	})
+	s.emitLearned("floop_learn", &learning.LearningResult{
+		CandidateBehavior:  models.Behavior{ID: "b2", Name: "wrap-errors"},
+		MergedIntoExisting: true,
+		MergedBehaviorID:   "b0",
+		MergeSimilarity:    0.93,
+	})
+	s.emitMerged("floop_deduplicate", &dedup.DeduplicationReport{
+		MergedBehaviors: []*models.Behavior{{ID: "b3", Name: "small-funcs"}},
+		MergedFrom:      map[string][]string{"b3": {"b4", "b5"}},
+	})
+	s.emitForgotten("retirement-sweep", []retirement.Notice{
+		{BehaviorID: "b6", Name: "old", Outcome: retirement.OutcomeForgotten, RetiredAt: time.Now().AddDate(0, 0, -30)},
+		{BehaviorID: "b7", Name: "back", Outcome: retirement.OutcomeRestored},
+	})
+	s.emitPruned(&decay.Report{StartedAt: time.Now(), Pruned: []decay.Change{{BehaviorID: "b8", Name: "stale", IdleDays: 120}}})
+	s.emitBackup("floop_backup", "/backups/x.json.gz", 4, 2)
+	if err := bus.Close(time.Second); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	f, err := os.Open(filepath.Join(root, ".floop", "events.jsonl"))
+	if err != nil {
+		t.Fatalf("event log not written: %v", err)
+	}
+	defer f.Close()
+	var got []eventbus.Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e eventbus.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("invalid event line %q: %v", scanner.Text(), err)
+		}
+		got = append(got, e)
+	}
+
+	want := []struct{ typ, id, merged string }{
+		{eventbus.BehaviorLearned, "b1", ""},
+		{eventbus.BehaviorMerged, "b2", "b0"},
+		{eventbus.BehaviorMerged, "b3", "b4,b5"},
+		{eventbus.BehaviorForgotten, "b6", ""},
+		{eventbus.BehaviorForgotten, "b8", ""},
+		{eventbus.BackupCreated, "", ""},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		e := got[i]
+		if e.Type != w.typ || e.BehaviorID != w.id || strings.Join(e.MergedIDs, ",") != w.merged || e.Project != "proj" {
+			t.Errorf("event %d = %+v, want %s %s merged %q", i, e, w.typ, w.id, w.merged)
+		}
+	}
+	if got[0].Scope != "local" || got[4].Source != "decay" || got[5].Nodes != 4 || got[5].Path == "" {
+		t.Errorf("event details missing: %+v", got)
+	}
+
+	// The stdout sink only subscribed to forgets
+	if lines := strings.Split(strings.TrimSpace(stdout.String()), "\n"); len(lines) != 2 || !strings.Contains(lines[0], `"behavior_id":"b6"`) || !strings.Contains(lines[1], `"behavior_id":"b8"`) {
+		t.Errorf("stdout sink wrote %q", stdout.String())
+	}
+}
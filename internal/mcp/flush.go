@@ -0,0 +1,206 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// flushedTasks are the background tasks a flush waits for: the activation
+// hits, implicit confirmations, edge timestamps, and Hebbian weight updates
+// a floop_active call leaves behind. Slow maintenance such as embedding and
+// consolidation is not waited for.
+var flushedTasks = map[string]bool{
+	"activation-recording": true,
+	"edge-timestamp":       true,
+	"hebbian-update":       true,
+}
+
+// PendingDir is the directory of a .floop directory where running MCP
+// servers mark that they have background writes in flight, one file per
+// server process, for 'floop flush'.
+const PendingDir = "pending"
+
+// pendingWrites tracks the flushed tasks in flight. While any are, the
+// server's marker file exists under PendingDir.
+type pendingWrites struct {
+	mu     sync.Mutex
+	next   uint64
+	tasks  map[uint64]chan struct{}
+	marker string // marker file path, "" for none
+}
+
+// newPendingWrites returns a tracker that marks pending writes in
+// floopDir, or marks nothing if floopDir is "".
+func newPendingWrites(floopDir string) *pendingWrites {
+	p := &pendingWrites{tasks: make(map[uint64]chan struct{})}
+	if floopDir != "" {
+		p.marker = filepath.Join(floopDir, PendingDir, strconv.Itoa(os.Getpid())+".json")
+	}
+	return p
+}
+
+// start records a task in flight and returns the function that ends it.
+func (p *pendingWrites) start() func() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.next++
+	id, done := p.next, make(chan struct{})
+	p.tasks[id] = done
+	if len(p.tasks) == 1 {
+		p.writeMarkerLocked()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			delete(p.tasks, id)
+			close(done)
+			if len(p.tasks) == 0 {
+				p.removeMarker()
+			}
+		})
+	}
+}
+
+// wait blocks until the tasks in flight when it was called have finished,
+// or ctx is done. Tasks started while it waits are not waited for.
+func (p *pendingWrites) wait(ctx context.Context) error {
+	p.mu.Lock()
+	pending := make([]chan struct{}, 0, len(p.tasks))
+	for _, done := range p.tasks {
+		pending = append(pending, done)
+	}
+	p.mu.Unlock()
+
+	for _, done := range pending {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for background writes: %w", ctx.Err())
+		}
+	}
+	return nil
+}
+
+// pendingMarker is the content of a marker file.
+type pendingMarker struct {
+	PID   int       `json:"pid"`
+	Since time.Time `json:"since"`
+}
+
+func (p *pendingWrites) writeMarkerLocked() {
+	if p.marker == "" {
+		return
+	}
+	data, err := json.Marshal(pendingMarker{PID: os.Getpid(), Since: time.Now().UTC()})
+	if err != nil {
+		return
+	}
+	// Best effort: a missing marker only makes 'floop flush' return early
+	if err := os.MkdirAll(filepath.Dir(p.marker), 0700); err == nil {
+		os.WriteFile(p.marker, data, 0600)
+	}
+}
+
+func (p *pendingWrites) removeMarker() {
+	if p.marker != "" {
+		os.Remove(p.marker)
+	}
+}
+
+// pendingFloopDir returns the .floop directory a server for root marks its
+// pending writes in: the project's when it has one, otherwise the global
+// one.
+func pendingFloopDir(root string) string {
+	local := store.LocalFloopPath(root)
+	if info, err := os.Stat(local); err == nil && info.IsDir() {
+		return local
+	}
+	global, err := store.GlobalFloopPath()
+	if err != nil {
+		return ""
+	}
+	return global
+}
+
+// flush waits, when requested, for the background writes of earlier calls
+// so that a read sees them.
+func (s *Server) flush(ctx context.Context, requested bool) error {
+	if !requested {
+		return nil
+	}
+	return s.pending.wait(ctx)
+}
+
+// PendingServer describes an MCP server with background writes in flight.
+type PendingServer struct {
+	PID    int       `json:"pid"`
+	Since  time.Time `json:"since"`
+	Marker string    `json:"marker"`
+}
+
+// PendingServers lists the servers with background writes in flight, per
+// the marker files under the given .floop directories.
+func PendingServers(floopDirs ...string) ([]PendingServer, error) {
+	var servers []PendingServer
+	for _, dir := range floopDirs {
+		pendingDir := filepath.Join(dir, PendingDir)
+		entries, err := os.ReadDir(pendingDir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", pendingDir, err)
+		}
+		for _, e := range entries {
+			if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+				continue
+			}
+			path := filepath.Join(pendingDir, e.Name())
+			data, err := os.ReadFile(path)
+			if errors.Is(err, os.ErrNotExist) {
+				continue // finished while we looked
+			}
+			var m pendingMarker
+			if err != nil || json.Unmarshal(data, &m) != nil {
+				// Written but not yet readable; count it as pending
+				m = pendingMarker{}
+			}
+			servers = append(servers, PendingServer{PID: m.PID, Since: m.Since, Marker: path})
+		}
+	}
+	sort.Slice(servers, func(i, j int) bool { return servers[i].Marker < servers[j].Marker })
+	return servers, nil
+}
+
+// WaitForPendingServers polls PendingServers until none are left or ctx is
+// done, in which case it returns the servers still pending with ctx's
+// error.
+func WaitForPendingServers(ctx context.Context, interval time.Duration, floopDirs ...string) ([]PendingServer, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		servers, err := PendingServers(floopDirs...)
+		if err != nil || len(servers) == 0 {
+			return servers, err
+		}
+		select {
+		case <-ctx.Done():
+			return servers, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
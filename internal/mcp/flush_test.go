@@ -0,0 +1,102 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestPendingWrites_Wait(t *testing.T) {
+	dir := t.TempDir()
+	p := newPendingWrites(dir)
+
+	first := p.start()
+	second := p.start()
+	servers, err := PendingServers(dir)
+	if err != nil || len(servers) != 1 || servers[0].PID != os.Getpid() || servers[0].Since.IsZero() {
+		t.Fatalf("PendingServers() = %+v, %v; want this process", servers, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := p.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("wait() with writes in flight = %v, want the deadline", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		first()
+		second()
+	}()
+	if err := p.wait(context.Background()); err != nil {
+		t.Fatalf("wait() error = %v", err)
+	}
+	first() // ending a task twice is harmless
+	if servers, _ := PendingServers(dir); len(servers) != 0 {
+		t.Errorf("marker left after the writes finished: %+v", servers)
+	}
+
+	// A task started later holds up a later wait
+	late := p.start()
+	defer late()
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := p.wait(ctx); err == nil {
+		t.Error("wait() returned with a task in flight")
+	}
+}
+
+func TestWaitForPendingServers(t *testing.T) {
+	dir := t.TempDir()
+	done := newPendingWrites(dir).start()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	pending, err := WaitForPendingServers(ctx, time.Millisecond, dir, filepath.Join(dir, "missing"))
+	cancel()
+	if !errors.Is(err, context.DeadlineExceeded) || len(pending) != 1 {
+		t.Fatalf("WaitForPendingServers() = %+v, %v; want the pending server at the deadline", pending, err)
+	}
+
+	time.AfterFunc(10*time.Millisecond, done)
+	pending, err = WaitForPendingServers(context.Background(), time.Millisecond, dir)
+	if err != nil || len(pending) != 0 {
+		t.Errorf("WaitForPendingServers() = %+v, %v; want none pending", pending, err)
+	}
+}
+
+func TestFlush_WaitsForActivationRecording(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	release := make(chan struct{})
+	recorded := false
+	server.runBackground("activation-recording", func() {
+		<-release
+		recorded = true
+	})
+	server.runBackground("embedding-backfill", func() {
+		<-release
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := server.handleFloopList(ctx, &sdk.CallToolRequest{}, FloopListInput{Flush: true}); err == nil {
+		t.Fatal("floop_list with flush should wait for the activation recording")
+	}
+	if _, _, err := server.handleFloopList(context.Background(), &sdk.CallToolRequest{}, FloopListInput{}); err != nil {
+		t.Fatalf("floop_list without flush error = %v", err)
+	}
+
+	time.AfterFunc(10*time.Millisecond, func() { close(release) })
+	if _, _, err := server.handleFloopList(context.Background(), &sdk.CallToolRequest{}, FloopListInput{Flush: true}); err != nil {
+		t.Fatalf("floop_list with flush error = %v", err)
+	}
+	if !recorded {
+		t.Error("floop_list with flush returned before the activation recording finished")
+	}
+}
@@ -2,15 +2,19 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/edges"
+	"github.com/nvandessel/floop/internal/heatmap"
 	"github.com/nvandessel/floop/internal/models"
-	"github.com/nvandessel/floop/internal/ratelimit"
 	"github.com/nvandessel/floop/internal/sanitize"
 	"github.com/nvandessel/floop/internal/spreading"
 	"github.com/nvandessel/floop/internal/store"
@@ -22,90 +26,111 @@ func (s *Server) handleFloopActive(ctx context.Context, req *sdk.CallToolRequest
 	start := time.Now()
 	defer func() {
 		s.auditTool("floop_active", start, retErr, sanitizeToolParams("floop_active", map[string]interface{}{
-			"file": args.File, "task": args.Task, "language": args.Language,
+			"file": args.File, "files": args.Files, "task": args.Task, "language": args.Language, "tags": args.Tags,
 		}), "local")
 	}()
 
-	if err := ratelimit.CheckLimit(s.toolLimiters, "floop_active"); err != nil {
+	if err := s.checkRateLimit(ctx, "floop_active"); err != nil {
 		return nil, FloopActiveOutput{}, err
 	}
 
-	// Build context from parameters
-	ctxBuilder := activation.NewContextBuilder()
+	if args.Pin != "" {
+		out, err := s.pinnedActive(args.Pin, time.Now())
+		return nil, out, err
+	}
 
-	if args.File != "" {
-		// Resolve file path relative to project root
-		filePath := args.File
-		if !filepath.IsAbs(filePath) {
-			filePath = filepath.Join(s.root, filePath)
-		}
-		ctxBuilder.WithFile(filePath)
+	out, err := s.activeOutput(ctx, req, args)
+	if err == nil {
+		s.observeActiveSet(req, args, out)
 	}
+	return nil, out, err
+}
 
-	if args.Task != "" {
-		ctxBuilder.WithTask(args.Task)
+// observeActiveSet records the behaviors of out for the active-set
+// stability metric. Calls from the same client with the same arguments
+// share a context, so churn between them comes from the graph changing
+// underneath (Hebbian updates, PageRank refreshes, decay, new behaviors).
+func (s *Server) observeActiveSet(req *sdk.CallToolRequest, args FloopActiveInput, out FloopActiveOutput) {
+	if s.metrics == nil {
+		return
+	}
+	key, err := json.Marshal(struct {
+		Client string
+		Args   FloopActiveInput
+	}{clientName(req), args})
+	if err != nil {
+		return
 	}
+	ids := make([]string, len(out.Active))
+	for i, b := range out.Active {
+		ids[i] = b.ID
+	}
+	s.metrics.ObserveActiveSet(string(key), ids)
+}
 
-	if args.Language != "" {
-		ctxBuilder.WithLanguage(sanitize.SanitizeBehaviorContent(args.Language))
+// activeOutput computes the active behavior set for args: activation,
+// spreading, conflict resolution, and tiering. It also starts the
+// background edge, Hebbian, and activation-hit updates of a floop_active
+// call.
+func (s *Server) activeOutput(ctx context.Context, req *sdk.CallToolRequest, args FloopActiveInput) (FloopActiveOutput, error) {
+	kindFilter, err := activation.ParseKindFilter(args.Kinds, args.ExcludeKinds)
+	if err != nil {
+		return FloopActiveOutput{}, err
 	}
 
-	ctxBuilder.WithRepoRoot(s.root)
+	if len(args.Files) > constants.MaxChangesetFiles {
+		return FloopActiveOutput{}, fmt.Errorf("too many files: %d (max %d)", len(args.Files), constants.MaxChangesetFiles)
+	}
 
-	actCtx := ctxBuilder.Build()
+	client := clientName(req)
 
-	// Load behaviors — vector pre-filter when embedder is available, else load all
 	var (
-		nodes []store.Node
-		err   error
+		actCtx      models.ContextSnapshot
+		state       *activationState
+		files       []string
+		triggeredBy map[string][]string
 	)
-	if s.embedder != nil && s.embedder.Available() {
-		nodes, err = vectorRetrieve(ctx, s.embedder, s.vectorIndex, s.store, actCtx, vectorRetrieveTopK)
-		if err != nil {
-			nodes = nil // distinguish error from empty results
-			s.logger.Warn("vector retrieval failed, falling back to full scan", "error", err)
+	if len(args.Files) > 0 {
+		// Changeset: evaluate each file on its own, then union the results
+		files = changesetFiles(args.File, args.Files)
+		states := make([]*activationState, len(files))
+		for i, f := range files {
+			fileCtx := s.buildActiveContext(f, args, client)
+			if i == 0 {
+				actCtx = fileCtx
+			}
+			states[i], err = s.computeActivation(ctx, fileCtx)
+			if err != nil {
+				return FloopActiveOutput{}, err
+			}
 		}
-	}
-	if nodes == nil {
-		nodes, err = s.store.QueryNodes(ctx, map[string]interface{}{"kind": "behavior"})
-		if err != nil {
-			return nil, FloopActiveOutput{}, fmt.Errorf("failed to query behaviors: %w", err)
+		state, triggeredBy = mergeActivationStates(files, states)
+	} else {
+		actCtx = s.buildActiveContext(args.File, args, client)
+
+		// Reuse the result pre-computed at initialize for a default-context call,
+		// otherwise evaluate and spread now.
+		if args.File == "" && args.Task == "" && args.Language == "" && len(args.Tags) == 0 {
+			state = s.takePrewarmedActivation(client)
+		}
+		if state == nil {
+			state, err = s.computeActivation(ctx, actCtx)
+			if err != nil {
+				return FloopActiveOutput{}, err
+			}
 		}
 	}
+	matches, seeds, spreadResults := state.matches, state.seeds, state.spreadResults
 
-	// Convert nodes to behaviors
-	behaviors := make([]models.Behavior, 0, len(nodes))
-	for _, node := range nodes {
-		behavior := models.NodeToBehavior(node)
-		behaviors = append(behaviors, behavior)
-	}
-
-	// Evaluate which behaviors are active
-	evaluator := activation.NewEvaluator()
-	matches := evaluator.Evaluate(actCtx, behaviors)
-
-	// Spread activation through graph edges
-	seeds := matchesToSeeds(matches)
-
-	// Boost seeds with PageRank scores (15% blend — tiebreaker, not dominator)
-	s.pageRankMu.RLock()
-	prScores := s.pageRankCache
-	s.pageRankMu.RUnlock()
-	seeds = boostSeedsWithPageRank(seeds, prScores, 0.15)
-
-	var spreadResults []spreading.Result
 	if len(seeds) > 0 {
-		spreadResults, err = s.activator.Activate(ctx, seeds)
-		if err != nil {
-			s.logger.Warn("spreading activation failed", "error", err)
-		} else {
-			matches = mergeSpreadResults(ctx, s.store, matches, spreadResults)
-		}
-
-		// Background: stamp LastActivated on edges touching seed behaviors
-		seedIDs := make([]string, len(seeds))
-		for i, seed := range seeds {
-			seedIDs[i] = seed.BehaviorID
+		// Background: stamp LastActivated on edges touching seed behaviors.
+		// Standing seeds are configured, not observed, so they take no part
+		// in edge timestamps or co-activation learning.
+		seedIDs := make([]string, 0, len(seeds))
+		for _, seed := range seeds {
+			if !spreading.IsStandingSource(seed.Source) {
+				seedIDs = append(seedIDs, seed.BehaviorID)
+			}
 		}
 		s.runBackground("edge-timestamp", func() {
 			type edgeToucher interface {
@@ -138,9 +163,11 @@ func (s *Server) handleFloopActive(ctx context.Context, req *sdk.CallToolRequest
 		}
 	}
 
-	// Resolve conflicts and get final active set
-	resolver := activation.NewResolver()
+	// Resolve conflicts and get final active set, narrowed to the requested kinds.
+	// Filtering after resolution keeps overrides/conflicts from excluded kinds effective.
+	resolver := activation.NewResolver().WithOverrides(s.storeOverrides(ctx))
 	result := resolver.Resolve(matches)
+	result.Active = kindFilter.Apply(result.Active)
 
 	// Build spread metadata index for populating summaries
 	spreadIndex := buildSpreadIndex(seeds, matches, spreadResults)
@@ -168,6 +195,15 @@ func (s *Server) handleFloopActive(ctx context.Context, req *sdk.CallToolRequest
 		})
 	}
 
+	// Rank by time of day, day of week, and session workload, if enabled.
+	if weight := s.floopConfig.Ranking.Temporal.EffectiveWeight(); weight > 0 {
+		actCtx.Intensity = s.sessions.intensity(requestSession(req), s.floopConfig.Ranking.Temporal.EffectiveBusyCalls())
+		tierResults = tiering.ApplyTemporalSignals(&actCtx, tierResults, behaviorMap, weight)
+	}
+
+	// Merge external scorer adjustments, falling back to built-in ranking on failure.
+	tierResults = s.applyExternalScores(ctx, &actCtx, tierResults, behaviorMap)
+
 	// Apply token budget enforcement: tier and demote behaviors to fit budget.
 	mapper := tiering.NewActivationTierMapper(tiering.DefaultActivationTierConfig())
 	plan := mapper.MapResults(tierResults, behaviorMap, s.floopConfig.TokenBudget.Default)
@@ -187,7 +223,7 @@ func (s *Server) handleFloopActive(ctx context.Context, req *sdk.CallToolRequest
 		// - Summary/NameOnly: only the tier-appropriate content string
 		var content map[string]interface{}
 		if ib.Tier == models.TierFull {
-			content = behaviorContentToMap(b.Content)
+			content = behaviorContentToMap(b.Kind, b.Content)
 		} else {
 			content = map[string]interface{}{
 				"canonical": ib.Content,
@@ -203,12 +239,14 @@ func (s *Server) handleFloopActive(ctx context.Context, req *sdk.CallToolRequest
 			Confidence: b.Confidence,
 			When:       when,
 			Tags:       b.Content.Tags,
+			Origin:     b.Origin,
 		}
 		if meta, ok := spreadIndex[b.ID]; ok {
 			summary.Activation = meta.activation
 			summary.Distance = meta.distance
 			summary.SeedSource = meta.seedSource
 		}
+		summary.TriggeredBy = triggeredBy[b.ID]
 		summaries = append(summaries, summary)
 	}
 
@@ -219,25 +257,42 @@ func (s *Server) handleFloopActive(ctx context.Context, req *sdk.CallToolRequest
 		"task":     actCtx.Task,
 		"repo":     actCtx.RepoRoot,
 	}
+	if len(files) > 0 {
+		ctxMap["files"] = files
+	}
+	if len(actCtx.Tags) > 0 {
+		ctxMap["tags"] = actCtx.Tags
+	}
 
 	// Compute session-scoped implicit confirmations.
-	// Behaviors that are active and NOT yet confirmed this session get
-	// a single implicit confirmation. This bounds the signal to 1 per
-	// behavior per session instead of N-1 (where N = floop_active calls).
+	// Behaviors that are active and NOT yet confirmed in the caller's
+	// session get a single implicit confirmation. This bounds the signal to
+	// 1 per behavior per session instead of N-1 (where N = floop_active calls).
 	activeBehaviors := result.Active
 
-	var implicitConfirmIDs []string
-	s.confirmedSessionMu.Lock()
+	var usageIDs []string
 	for _, b := range activeBehaviors {
-		if strings.HasPrefix(b.ID, "seed-") {
-			continue
+		if tracksUsage(b) {
+			usageIDs = append(usageIDs, b.ID)
 		}
-		if _, already := s.confirmedThisSession[b.ID]; !already {
-			s.confirmedThisSession[b.ID] = struct{}{}
-			implicitConfirmIDs = append(implicitConfirmIDs, b.ID)
+	}
+	implicitConfirmIDs := s.sessions.confirm(requestSession(req), usageIDs)
+
+	// Count the active behaviors per file for the heatmap. Each changeset
+	// file counts the behaviors it triggered.
+	heatFiles := files
+	heatCounts := make([]int, len(files))
+	if len(files) == 0 && actCtx.FilePath != "" {
+		heatFiles = []string{actCtx.FilePath}
+		heatCounts = []int{len(activeBehaviors)}
+	}
+	for _, b := range activeBehaviors {
+		for _, f := range triggeredBy[b.ID] {
+			if i := slices.Index(files, f); i >= 0 {
+				heatCounts[i]++
+			}
 		}
 	}
-	s.confirmedSessionMu.Unlock()
 
 	// Record activation hits + implicit confirmations in background.
 	// Note: confidence reinforcement has been replaced by ACT-R base-level activation
@@ -248,7 +303,7 @@ func (s *Server) handleFloopActive(ctx context.Context, req *sdk.CallToolRequest
 		}
 		if recorder, ok := s.store.(activationRecorder); ok {
 			for _, b := range activeBehaviors {
-				if strings.HasPrefix(b.ID, "seed-") {
+				if !tracksUsage(b) {
 					continue
 				}
 				if err := recorder.RecordActivationHit(context.Background(), b.ID); err != nil {
@@ -257,6 +312,21 @@ func (s *Server) handleFloopActive(ctx context.Context, req *sdk.CallToolRequest
 			}
 		}
 
+		// Record per-client activation counts for cross-agent stats.
+		type clientActivationRecorder interface {
+			RecordClientActivation(ctx context.Context, behaviorID, client string) error
+		}
+		if recorder, ok := s.store.(clientActivationRecorder); ok && actCtx.Agent != "" {
+			for _, b := range activeBehaviors {
+				if !tracksUsage(b) {
+					continue
+				}
+				if err := recorder.RecordClientActivation(context.Background(), b.ID, actCtx.Agent); err != nil {
+					s.logger.Warn("client activation recording failed", "behavior_id", b.ID, "error", err)
+				}
+			}
+		}
+
 		// Record session-scoped implicit confirmations.
 		type confirmRecorder interface {
 			RecordConfirmed(ctx context.Context, behaviorID string) error
@@ -268,9 +338,16 @@ func (s *Server) handleFloopActive(ctx context.Context, req *sdk.CallToolRequest
 				}
 			}
 		}
+
+		floopDir := filepath.Join(s.root, ".floop")
+		for i, f := range heatFiles {
+			if err := heatmap.RecordActivation(floopDir, s.root, f, heatCounts[i], time.Now()); err != nil {
+				s.logger.Warn("heatmap activation recording failed", "file", f, "error", err)
+			}
+		}
 	})
 
-	return nil, FloopActiveOutput{
+	return FloopActiveOutput{
 		Context: ctxMap,
 		Active:  summaries,
 		Count:   len(summaries),
@@ -283,9 +360,235 @@ func (s *Server) handleFloopActive(ctx context.Context, req *sdk.CallToolRequest
 			NameOnlyCount:        len(plan.NameOnlyBehaviors),
 			OmittedCount:         len(plan.OmittedBehaviors),
 		},
+		Partial: state.partial,
 	}, nil
 }
 
+// tracksUsage reports whether activation and confirmation statistics are
+// recorded for b. Seeds are skipped, as are behaviors from read-only scope
+// and policy stores.
+func tracksUsage(b models.Behavior) bool {
+	return !strings.HasPrefix(b.ID, "seed-") && !store.Origin(b.Origin).ReadOnly()
+}
+
+// buildActiveContext builds the activation context for one file of a
+// floop_active call. Relative paths resolve against the project root, and
+// only files inside the project are sniffed for content signals.
+func (s *Server) buildActiveContext(file string, args FloopActiveInput, client string) models.ContextSnapshot {
+	task, language := args.Task, args.Language
+	ctxBuilder := activation.NewContextBuilder()
+
+	if file != "" {
+		filePath := file
+		if !filepath.IsAbs(filePath) {
+			filePath = filepath.Join(s.root, filePath)
+		}
+		ctxBuilder.WithFile(filePath)
+		if rel, err := filepath.Rel(s.root, filePath); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			ctxBuilder.WithContentSniffing(s.floopConfig.Activation.SniffContent)
+		}
+	}
+
+	if task != "" {
+		ctxBuilder.WithTask(task)
+	}
+
+	if language != "" {
+		ctxBuilder.WithLanguage(sanitize.SanitizeBehaviorContent(language))
+	}
+
+	if len(args.Tags) > 0 {
+		ctxBuilder.WithTags(args.Tags)
+	}
+
+	ctxBuilder.WithRepoRoot(s.root)
+
+	if client != "" {
+		ctxBuilder.WithAgent(client)
+	}
+
+	return ctxBuilder.Build()
+}
+
+// changesetFiles returns file followed by files, without blanks or repeats.
+func changesetFiles(file string, files []string) []string {
+	seen := make(map[string]bool, len(files)+1)
+	var out []string
+	for _, f := range append([]string{file}, files...) {
+		if f != "" && !seen[f] {
+			seen[f] = true
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// mergeActivationStates unions the per-file activation of a changeset.
+// Matches are deduplicated by behavior, and seeds and spread results keep
+// the strongest activation seen for each behavior. triggeredBy maps each
+// behavior ID to the files whose activation included it.
+func mergeActivationStates(files []string, states []*activationState) (*activationState, map[string][]string) {
+	perFile := make([][]activation.ActivationResult, len(states))
+	merged := &activationState{}
+	seedPos := make(map[string]int)
+	spreadPos := make(map[string]int)
+	for i, st := range states {
+		perFile[i] = st.matches
+		for _, seed := range st.seeds {
+			if pos, ok := seedPos[seed.BehaviorID]; !ok {
+				seedPos[seed.BehaviorID] = len(merged.seeds)
+				merged.seeds = append(merged.seeds, seed)
+			} else if seed.Activation > merged.seeds[pos].Activation {
+				merged.seeds[pos] = seed
+			}
+		}
+		for _, sr := range st.spreadResults {
+			if pos, ok := spreadPos[sr.BehaviorID]; !ok {
+				spreadPos[sr.BehaviorID] = len(merged.spreadResults)
+				merged.spreadResults = append(merged.spreadResults, sr)
+			} else if sr.Activation > merged.spreadResults[pos].Activation {
+				merged.spreadResults[pos] = sr
+			}
+		}
+		merged.partial = merged.partial || st.partial
+	}
+	var triggeredBy map[string][]string
+	merged.matches, triggeredBy = activation.MergeFileMatches(files, perFile)
+	return merged, triggeredBy
+}
+
+// activationState is what floop_active computes before conflict resolution
+// and tiering: the evaluated matches merged with spread-only behaviors, the
+// seeds they spread from, and the spreading results. It is partial when
+// spreading was cut short by the tool deadline, leaving only the matches.
+type activationState struct {
+	matches       []activation.ActivationResult
+	seeds         []spreading.Seed
+	spreadResults []spreading.Result
+	partial       bool
+}
+
+// computeActivation loads behaviors, evaluates them against actCtx, and
+// spreads activation from the matches through the graph.
+func (s *Server) computeActivation(ctx context.Context, actCtx models.ContextSnapshot) (*activationState, error) {
+	matches, seeds, err := s.activationSeeds(ctx, actCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	var spreadResults []spreading.Result
+	partial := false
+	if len(seeds) > 0 {
+		spreadCtx, cancel := spreadingContext(ctx)
+		spreadResults, err = s.activator.Activate(spreadCtx, seeds)
+		cancel()
+		switch {
+		case err != nil && spreadCtx.Err() != nil && ctx.Err() == nil:
+			// Out of time: answer with the direct matches alone
+			s.logger.Warn("spreading activation cut short by tool deadline", "seeds", len(seeds))
+			spreadResults, partial = nil, true
+		case err != nil:
+			s.logger.Warn("spreading activation failed", "error", err)
+		default:
+			matches = mergeSpreadResults(ctx, s.store, matches, spreadResults)
+		}
+	}
+
+	return &activationState{matches: matches, seeds: seeds, spreadResults: spreadResults, partial: partial}, nil
+}
+
+// activationSeeds loads behaviors, evaluates them against actCtx, and
+// returns the matches with the seeds spreading activation starts from.
+func (s *Server) activationSeeds(ctx context.Context, actCtx models.ContextSnapshot) ([]activation.ActivationResult, []spreading.Seed, error) {
+	// Load behaviors — vector pre-filter when embedder is available, else load all
+	var nodes []store.Node
+	var err error
+	if s.embedder != nil && s.embedder.Available() {
+		nodes, err = vectorRetrieve(ctx, s.embedder, s.vectorIndex, s.store, actCtx, vectorRetrieveTopK)
+		if err != nil {
+			nodes = nil // distinguish error from empty results
+			s.logger.Warn("vector retrieval failed, falling back to full scan", "error", err)
+		}
+	}
+
+	// Convert nodes to behaviors
+	var behaviors []models.Behavior
+	if nodes != nil {
+		behaviors = make([]models.Behavior, 0, len(nodes))
+		for _, node := range nodes {
+			behaviors = append(behaviors, models.NodeToBehavior(node))
+		}
+	} else {
+		// Partitions of the global store that cannot match are not opened,
+		// and the rest are read a page at a time
+		partCtx := store.WithPartitionFilter(ctx, func(when map[string]interface{}) bool {
+			return s.evaluator.MayMatch(actCtx, when)
+		})
+		err = store.WalkNodes(partCtx, s.store, map[string]interface{}{"kind": "behavior"}, func(node store.Node) error {
+			behaviors = append(behaviors, models.NodeToBehavior(node))
+			return nil
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to query behaviors: %w", err)
+		}
+	}
+
+	if err := edges.AttachRelationships(ctx, s.store, behaviors); err != nil {
+		s.logger.Warn("failed to load behavior relationships", "error", err)
+	}
+
+	// Evaluate which behaviors are active
+	matches := s.evaluator.Evaluate(actCtx, behaviors)
+
+	// Spread activation through graph edges
+	seeds := matchesToSeeds(matches)
+
+	// Boost seeds with PageRank scores (15% blend — tiebreaker, not dominator)
+	s.pageRankMu.RLock()
+	prScores := s.pageRankCache
+	s.pageRankMu.RUnlock()
+	seeds = boostSeedsWithPageRank(seeds, prScores, 0.15)
+
+	// Inject standing seeds for configured project priorities
+	standing, err := spreading.StandingSeedsFromStore(ctx, s.store, standingSeeds(s.floopConfig))
+	if err != nil {
+		s.logger.Warn("standing seeds unavailable", "error", err)
+	}
+	seeds = spreading.AddStandingSeeds(seeds, standing)
+
+	return matches, seeds, nil
+}
+
+// storeOverrides returns the IDs a behavior overrides according to the
+// store's edges, so override chains pass through behaviors that were not
+// loaded or did not match.
+func (s *Server) storeOverrides(ctx context.Context) func(id string) []string {
+	return func(id string) []string {
+		out, err := s.store.GetEdges(ctx, id, store.DirectionOutbound, store.EdgeKindOverrides)
+		if err != nil {
+			s.logger.Warn("failed to load overrides", "behavior_id", id, "error", err)
+			return nil
+		}
+		targets := make([]string, 0, len(out))
+		for _, e := range out {
+			targets = append(targets, e.Target)
+		}
+		return targets
+	}
+}
+
+// spreadingContext bounds spreading activation to the first three quarters
+// of the time ctx has left, keeping the rest for resolving and tiering the
+// matches, so a slow graph yields a partial active set rather than a
+// deadline error.
+func spreadingContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithDeadline(ctx, deadline.Add(-time.Until(deadline)/4))
+}
+
 // matchesToSeeds converts activation results to spreading seeds.
 func matchesToSeeds(matches []activation.ActivationResult) []spreading.Seed {
 	seeds := make([]spreading.Seed, len(matches))
@@ -392,12 +695,23 @@ func boostSeedsWithPageRank(seeds []spreading.Seed, pageRank map[string]float64,
 	return seeds
 }
 
+// applyExternalScores merges the configured external scorer's adjustments
+// into results. On failure it logs a warning and returns results unchanged.
+func (s *Server) applyExternalScores(ctx context.Context, actCtx *models.ContextSnapshot, results []spreading.Result, behaviors map[string]*models.Behavior) []spreading.Result {
+	adjusted, err := tiering.ApplyExternalScores(ctx, s.externalScorer, actCtx, results, behaviors)
+	if err != nil {
+		s.logger.Warn("external scorer failed, using built-in ranking", "error", err)
+	}
+	return adjusted
+}
+
 // behaviorContentToMap converts BehaviorContent to a map for JSON serialization.
-func behaviorContentToMap(content models.BehaviorContent) map[string]interface{} {
+// Structured content is limited to the fields allowed for kind.
+func behaviorContentToMap(kind models.BehaviorKind, content models.BehaviorContent) map[string]interface{} {
 	m := make(map[string]interface{})
 	m["canonical"] = content.Canonical
-	if content.Structured != nil && len(content.Structured) > 0 {
-		m["structured"] = content.Structured
+	if structured := models.FilterStructured(kind, content.Structured, sanitize.RenderText); structured != nil {
+		m["structured"] = structured
 	}
 	if len(content.Tags) > 0 {
 		m["tags"] = content.Tags
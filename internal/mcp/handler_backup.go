@@ -10,7 +10,7 @@ import (
 	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/nvandessel/floop/internal/backup"
 	"github.com/nvandessel/floop/internal/pathutil"
-	"github.com/nvandessel/floop/internal/ratelimit"
+	"github.com/nvandessel/floop/internal/restorepoint"
 )
 
 // handleFloopBackup implements the floop_backup tool.
@@ -22,7 +22,7 @@ func (s *Server) handleFloopBackup(ctx context.Context, req *sdk.CallToolRequest
 		}), "local")
 	}()
 
-	if err := ratelimit.CheckLimit(s.toolLimiters, "floop_backup"); err != nil {
+	if err := s.checkRateLimit(ctx, "floop_backup"); err != nil {
 		return nil, FloopBackupOutput{}, err
 	}
 
@@ -59,6 +59,8 @@ func (s *Server) handleFloopBackup(ctx context.Context, req *sdk.CallToolRequest
 		s.logger.Warn("failed to apply retention", "error", err)
 	}
 
+	s.emitBackup("floop_backup", outputPath, len(result.Nodes), len(result.Edges))
+
 	// Get file size for output
 	var sizeBytes int64
 	if info, err := os.Stat(outputPath); err == nil {
@@ -93,11 +95,11 @@ func (s *Server) handleFloopRestore(ctx context.Context, req *sdk.CallToolReques
 	start := time.Now()
 	defer func() {
 		s.auditTool("floop_restore", start, retErr, sanitizeToolParams("floop_restore", map[string]interface{}{
-			"input_path": args.InputPath, "mode": args.Mode,
+			"input_path": args.InputPath, "mode": args.Mode, "strategy": args.Strategy,
 		}), "local")
 	}()
 
-	if err := ratelimit.CheckLimit(s.toolLimiters, "floop_restore"); err != nil {
+	if err := s.checkRateLimit(ctx, "floop_restore"); err != nil {
 		return nil, FloopRestoreOutput{}, err
 	}
 
@@ -118,8 +120,25 @@ func (s *Server) handleFloopRestore(ctx context.Context, req *sdk.CallToolReques
 	if args.Mode == "replace" {
 		mode = backup.RestoreReplace
 	}
+	strategy, err := backup.ParseConflictStrategy(args.Strategy)
+	if err != nil {
+		return nil, FloopRestoreOutput{}, err
+	}
 
-	result, err := backup.Restore(ctx, s.store, args.InputPath, mode)
+	// Replace overwrites the stores: snapshot them first so it can be undone
+	var pointID string
+	if mode == backup.RestoreReplace {
+		point, err := restorepoint.Create(ctx, s.store, s.root, "restore-backup", "floop_restore --mode replace", nil)
+		if err != nil {
+			return nil, FloopRestoreOutput{}, fmt.Errorf("failed to create restore point: %w", err)
+		}
+		pointID = point.ID
+	}
+
+	result, err := backup.RestoreWithOptions(ctx, s.store, args.InputPath, backup.RestoreOptions{
+		Mode:     mode,
+		Strategy: strategy,
+	})
 	if err != nil {
 		return nil, FloopRestoreOutput{}, fmt.Errorf("restore failed: %w", err)
 	}
@@ -130,8 +149,11 @@ func (s *Server) handleFloopRestore(ctx context.Context, req *sdk.CallToolReques
 	return nil, FloopRestoreOutput{
 		NodesRestored: result.NodesRestored,
 		NodesSkipped:  result.NodesSkipped,
+		NodesUpdated:  result.NodesUpdated,
 		EdgesRestored: result.EdgesRestored,
 		EdgesSkipped:  result.EdgesSkipped,
-		Message:       fmt.Sprintf("Restore complete: %d nodes restored, %d skipped; %d edges restored, %d skipped", result.NodesRestored, result.NodesSkipped, result.EdgesRestored, result.EdgesSkipped),
+		RestorePoint:  pointID,
+		Conflicts:     result.Conflicts,
+		Message:       fmt.Sprintf("Restore complete: %d nodes restored, %d skipped, %d updated (%d conflicts); %d edges restored, %d skipped", result.NodesRestored, result.NodesSkipped, result.NodesUpdated, len(result.Conflicts), result.EdgesRestored, result.EdgesSkipped),
 	}, nil
 }
@@ -8,7 +8,7 @@ import (
 	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/nvandessel/floop/internal/constants"
 	"github.com/nvandessel/floop/internal/dedup"
-	"github.com/nvandessel/floop/internal/ratelimit"
+	"github.com/nvandessel/floop/internal/restorepoint"
 	"github.com/nvandessel/floop/internal/store"
 )
 
@@ -21,11 +21,11 @@ func (s *Server) handleFloopDeduplicate(ctx context.Context, req *sdk.CallToolRe
 			auditScope = "global"
 		}
 		s.auditTool("floop_deduplicate", start, retErr, sanitizeToolParams("floop_deduplicate", map[string]interface{}{
-			"dry_run": args.DryRun, "threshold": args.Threshold, "scope": args.Scope,
+			"dry_run": args.DryRun, "threshold": args.Threshold, "scope": args.Scope, "full": args.Full,
 		}), auditScope)
 	}()
 
-	if err := ratelimit.CheckLimit(s.toolLimiters, "floop_deduplicate"); err != nil {
+	if err := s.checkRateLimit(ctx, "floop_deduplicate"); err != nil {
 		return nil, FloopDeduplicateOutput{}, err
 	}
 
@@ -52,6 +52,8 @@ func (s *Server) handleFloopDeduplicate(ctx context.Context, req *sdk.CallToolRe
 		EmbeddingThreshold:  constants.DefaultEmbeddingDedupThreshold,
 		AutoMerge:           !args.DryRun,
 		UseLLM:              useLLM,
+		Full:                args.Full,
+		Similarity:          s.dedupSimilarity(),
 	}
 
 	merger := dedup.NewBehaviorMerger(dedup.MergerConfig{
@@ -66,6 +68,16 @@ func (s *Server) handleFloopDeduplicate(ctx context.Context, req *sdk.CallToolRe
 		deduplicator = dedup.NewStoreDeduplicator(s.store, merger, dedupConfig)
 	}
 
+	// Snapshot the stores before merging so the run can be undone
+	var pointID string
+	if !args.DryRun {
+		point, err := restorepoint.Create(ctx, s.store, s.root, "deduplicate", "floop_deduplicate", nil)
+		if err != nil {
+			return nil, FloopDeduplicateOutput{}, fmt.Errorf("failed to create restore point: %w", err)
+		}
+		pointID = point.ID
+	}
+
 	// Perform deduplication
 	report, err := deduplicator.DeduplicateStore(ctx, s.store)
 	if err != nil {
@@ -111,6 +123,7 @@ func (s *Server) handleFloopDeduplicate(ctx context.Context, req *sdk.CallToolRe
 
 		// Debounced PageRank refresh after graph mutation
 		s.debouncedRefreshPageRank()
+		s.emitMerged("floop_deduplicate", report)
 	}
 
 	// Convert results to output format
@@ -126,19 +139,62 @@ func (s *Server) handleFloopDeduplicate(ctx context.Context, req *sdk.CallToolRe
 		}
 	}
 
+	var parentIDs []string
+	for _, parent := range report.SharedParents {
+		parentIDs = append(parentIDs, parent.ID)
+	}
+
 	// Build message
 	var message string
 	if args.DryRun {
 		message = fmt.Sprintf("Dry run: found %d duplicate pairs (no changes made)", report.DuplicatesFound)
 	} else {
-		message = fmt.Sprintf("Deduplication complete: found %d duplicates, merged %d behaviors",
-			report.DuplicatesFound, report.MergesPerformed)
+		message = fmt.Sprintf("Deduplication complete: found %d duplicates, merged %d behaviors (restore point %s)",
+			report.DuplicatesFound, report.MergesPerformed, pointID)
+	}
+	if report.Differential {
+		message += fmt.Sprintf("; compared %d new or changed of %d behaviors", report.ComparedBehaviors, report.TotalBehaviors)
+	}
+	if report.ContextVariantsFound > 0 {
+		message += fmt.Sprintf("; %d similar pairs kept apart because their contexts are incompatible", report.ContextVariantsFound)
+		if len(parentIDs) > 0 {
+			message += fmt.Sprintf(", linked under %d shared parents", len(parentIDs))
+		}
+	}
+
+	if len(report.BlockedMerges) > 0 {
+		message += fmt.Sprintf("; %d merges blocked by when-conditions that cannot be combined", len(report.BlockedMerges))
 	}
 
 	return nil, FloopDeduplicateOutput{
 		DuplicatesFound: report.DuplicatesFound,
 		Merged:          report.MergesPerformed,
 		Results:         results,
+		ContextVariants: report.ContextVariantsFound,
+		SharedParents:   parentIDs,
+		BlockedMerges:   report.BlockedMerges,
+		RestorePoint:    pointID,
+		Differential:    report.Differential,
+		Compared:        report.ComparedBehaviors,
 		Message:         message,
 	}, nil
 }
+
+// dedupSimilarity returns the similarity backend selected by
+// deduplication.similarity, or nil for the default chain. Embeddings are
+// recorded under the same model name as vector retrieval uses.
+func (s *Server) dedupSimilarity() dedup.Similarity {
+	if s.floopConfig == nil {
+		return nil
+	}
+	model := s.floopConfig.LLM.EmbeddingModelName()
+	if s.embedder != nil {
+		model = s.embedder.ModelName()
+	}
+	sim, err := dedup.NewSimilarity(s.floopConfig.Deduplication.Similarity, s.llmClient, model, s.store)
+	if err != nil {
+		s.logger.Warn("similarity backend unavailable, using default", "error", err)
+		return nil
+	}
+	return sim
+}
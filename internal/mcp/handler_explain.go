@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/spreading"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// handleFloopExplain implements the floop_explain tool.
+func (s *Server) handleFloopExplain(ctx context.Context, req *sdk.CallToolRequest, args FloopExplainInput) (_ *sdk.CallToolResult, _ FloopExplainOutput, retErr error) {
+	start := time.Now()
+	defer func() {
+		s.auditTool("floop_explain", start, retErr, sanitizeToolParams("floop_explain", map[string]interface{}{
+			"behavior_id": args.BehaviorID, "file": args.File, "task": args.Task, "language": args.Language, "tags": args.Tags, "full": args.Full,
+		}), "local")
+	}()
+
+	if err := s.checkRateLimit(ctx, "floop_explain"); err != nil {
+		return nil, FloopExplainOutput{}, err
+	}
+	if err := s.flush(ctx, args.Flush); err != nil {
+		return nil, FloopExplainOutput{}, err
+	}
+
+	if strings.TrimSpace(args.BehaviorID) == "" {
+		return nil, FloopExplainOutput{}, fmt.Errorf("'behavior_id' parameter is required")
+	}
+	node, err := store.ResolveNode(ctx, s.store, args.BehaviorID)
+	if err != nil {
+		return nil, FloopExplainOutput{}, fmt.Errorf("failed to look up behavior: %w", err)
+	}
+	if node == nil || node.Kind != store.NodeKindBehavior {
+		return nil, FloopExplainOutput{}, fmt.Errorf("behavior not found: %s", args.BehaviorID)
+	}
+	behavior := models.NodeToBehavior(*node)
+
+	actCtx := s.buildActiveContext(args.File, FloopActiveInput{
+		Task: args.Task, Language: args.Language, Tags: args.Tags,
+	}, clientName(req))
+	_, seeds, err := s.activationSeeds(ctx, actCtx)
+	if err != nil {
+		return nil, FloopExplainOutput{}, err
+	}
+
+	// The native engine records nothing, so the run is repeated on the
+	// pure-Go engine with the same configuration.
+	trace, err := spreading.NewEngine(s.store, s.spreadConfig).Explain(ctx, seeds)
+	if err != nil {
+		return nil, FloopExplainOutput{}, fmt.Errorf("spreading activation failed: %w", err)
+	}
+
+	out := FloopExplainOutput{
+		BehaviorID: behavior.ID,
+		Name:       behavior.Name,
+		Context: map[string]interface{}{
+			"file":     actCtx.FilePath,
+			"language": actCtx.FileLanguage,
+			"task":     actCtx.Task,
+			"repo":     actCtx.RepoRoot,
+		},
+		Seeds:       make([]ExplainSeed, 0, len(trace.Seeds)),
+		Steps:       trace.Steps,
+		Explanation: trace.Explain(behavior.ID),
+	}
+	if len(actCtx.Tags) > 0 {
+		out.Context["tags"] = actCtx.Tags
+	}
+	for _, seed := range trace.Seeds {
+		out.Seeds = append(out.Seeds, ExplainSeed{BehaviorID: seed.BehaviorID, Activation: seed.Activation, Source: seed.Source})
+	}
+	if args.Full {
+		out.Firings = trace.Firings
+	}
+	out.Message = explainMessage(out.Explanation, len(seeds))
+
+	return nil, out, nil
+}
+
+// explainMessage summarizes x for the agent.
+func explainMessage(x spreading.Explanation, seeds int) string {
+	switch {
+	case seeds == 0:
+		return "No behavior matches this context, so spreading activation had no seeds"
+	case x.Seed && x.Reached:
+		return fmt.Sprintf("Seeded directly (%s), activation %.3f", x.SeedSource, x.Activation)
+	case x.Reached:
+		return fmt.Sprintf("Spread-only: reached %d hop(s) from seed %s over %d edge(s), activation %.3f", x.Distance, x.SeedSource, len(x.Path), x.Activation)
+	case len(x.Incoming) > 0:
+		return fmt.Sprintf("Reached by %d edge firing(s) but below the activation threshold", len(x.Incoming))
+	default:
+		return "Not reached: no edge from an activated behavior leads to this behavior"
+	}
+}
@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestHandleFloopExplain(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	for id, language := range map[string]string{"go-errors": "go", "python-errors": "python"} {
+		node := store.Node{
+			ID:   id,
+			Kind: "behavior",
+			Content: map[string]interface{}{
+				"name":    id,
+				"kind":    "directive",
+				"content": map[string]interface{}{"canonical": "Handle errors explicitly in " + language},
+				"when":    map[string]interface{}{"language": language},
+			},
+			Metadata: map[string]interface{}{"confidence": 0.9},
+		}
+		if _, err := server.store.AddNode(ctx, node); err != nil {
+			t.Fatalf("AddNode(%s) error = %v", id, err)
+		}
+	}
+	edge := store.Edge{Source: "go-errors", Target: "python-errors", Kind: store.EdgeKindRequires, Weight: 1.0, CreatedAt: time.Now()}
+	if err := server.store.AddEdge(ctx, edge); err != nil {
+		t.Fatalf("AddEdge() error = %v", err)
+	}
+
+	_, out, err := server.handleFloopExplain(ctx, &sdk.CallToolRequest{}, FloopExplainInput{BehaviorID: "python-errors", Language: "go"})
+	if err != nil {
+		t.Fatalf("handleFloopExplain() error = %v", err)
+	}
+	x := out.Explanation
+	if x.Seed || len(x.Path) != 1 || x.Path[0].From != "go-errors" || x.Path[0].EdgeKind != store.EdgeKindRequires {
+		t.Errorf("explanation = %+v, want a spread-only path from go-errors", x)
+	}
+	if x.Path[0].Energy <= 0 || x.Path[0].Decay <= 0 || x.Path[0].Weight != 1.0 {
+		t.Errorf("path hop = %+v, want positive energy and decay", x.Path[0])
+	}
+	if len(out.Seeds) == 0 || out.Seeds[0].BehaviorID != "go-errors" {
+		t.Errorf("seeds = %+v, want go-errors", out.Seeds)
+	}
+	if out.Firings != nil {
+		t.Error("firings should be omitted unless full is set")
+	}
+
+	_, out, err = server.handleFloopExplain(ctx, &sdk.CallToolRequest{}, FloopExplainInput{BehaviorID: "go-errors", Language: "go", Full: true})
+	if err != nil {
+		t.Fatalf("handleFloopExplain(full) error = %v", err)
+	}
+	if !out.Explanation.Seed || len(out.Firings) == 0 {
+		t.Errorf("full explanation of the seed = %+v with %d firings", out.Explanation, len(out.Firings))
+	}
+}
+
+func TestHandleFloopExplain_Validation(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	if _, _, err := server.handleFloopExplain(context.Background(), &sdk.CallToolRequest{}, FloopExplainInput{}); err == nil {
+		t.Error("expected an error without behavior_id")
+	}
+	if _, _, err := server.handleFloopExplain(context.Background(), &sdk.CallToolRequest{}, FloopExplainInput{BehaviorID: "missing"}); err == nil {
+		t.Error("expected an error for an unknown behavior")
+	}
+}
@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/sanitize"
+)
+
+// handleFloopReportFailure implements the floop_report_failure tool.
+// Failures are recorded in the local store and queued for review; a behavior
+// is only extracted once a human accepts one with 'floop failures learn'.
+func (s *Server) handleFloopReportFailure(ctx context.Context, req *sdk.CallToolRequest, args FloopReportFailureInput) (_ *sdk.CallToolResult, _ FloopReportFailureOutput, retErr error) {
+	start := time.Now()
+	defer func() {
+		s.auditTool("floop_report_failure", start, retErr, sanitizeToolParams("floop_report_failure", map[string]interface{}{
+			"kind": args.Kind, "command": args.Command, "action": args.Action, "error": args.Error, "fix": args.Fix,
+			"file": args.File, "task": args.Task, "language": args.Language,
+		}), "local")
+	}()
+
+	if err := s.checkRateLimit(ctx, "floop_report_failure"); err != nil {
+		return nil, FloopReportFailureOutput{}, err
+	}
+
+	if strings.TrimSpace(args.Error) == "" {
+		return nil, FloopReportFailureOutput{}, fmt.Errorf("'error' parameter is required")
+	}
+	if strings.TrimSpace(args.Fix) == "" {
+		return nil, FloopReportFailureOutput{}, fmt.Errorf("'fix' parameter is required")
+	}
+	kind, err := models.ParseFailureKind(args.Kind)
+	if err != nil {
+		return nil, FloopReportFailureOutput{}, err
+	}
+
+	// Sanitize inputs; failure output is agent-supplied content that may
+	// later become behavior text
+	args.Error = sanitize.SanitizeBehaviorContent(args.Error)
+	args.Fix = sanitize.SanitizeBehaviorContent(args.Fix)
+	if args.Command != "" {
+		args.Command = sanitize.SanitizeBehaviorContent(args.Command)
+	}
+	if args.Action != "" {
+		args.Action = sanitize.SanitizeBehaviorContent(args.Action)
+	}
+	if args.Task != "" {
+		args.Task = sanitize.SanitizeBehaviorContent(args.Task)
+	}
+	if args.File != "" {
+		args.File = sanitize.SanitizeFilePath(args.File)
+	}
+
+	ctxBuilder := activation.NewContextBuilder()
+	if args.File != "" {
+		filePath := args.File
+		if !filepath.IsAbs(filePath) {
+			filePath = filepath.Join(s.root, filePath)
+		}
+		ctxBuilder.WithFile(filePath)
+	}
+	if args.Task != "" {
+		ctxBuilder.WithTask(args.Task)
+	}
+	if args.Language != "" {
+		ctxBuilder.WithLanguage(sanitize.SanitizeBehaviorContent(args.Language))
+	}
+	ctxBuilder.WithRepoRoot(s.root)
+	if client := clientName(req); client != "" {
+		ctxBuilder.WithAgent(client)
+	}
+
+	failure := learning.NewFailure(kind, args.Command, args.Action, args.Error, args.Fix, ctxBuilder.Build())
+	recorded, duplicate, err := learning.RecordFailure(ctx, s.store, failure)
+	if err != nil {
+		return nil, FloopReportFailureOutput{}, err
+	}
+	if !duplicate {
+		if err := s.store.Sync(ctx); err != nil {
+			return nil, FloopReportFailureOutput{}, fmt.Errorf("failed to sync store: %w", err)
+		}
+	}
+
+	pending, err := learning.ListFailures(ctx, s.store, models.FailureStatusPending)
+	if err != nil {
+		return nil, FloopReportFailureOutput{}, err
+	}
+
+	message := fmt.Sprintf("Recorded %s failure %s for review (%d pending). Run 'floop failures learn %s' to extract a behavior from it.",
+		recorded.Kind, recorded.ID, len(pending), recorded.ID)
+	if duplicate {
+		message = fmt.Sprintf("Failure %s was already recorded (%s).", recorded.ID, recorded.Status)
+	}
+
+	return nil, FloopReportFailureOutput{
+		FailureID: recorded.ID,
+		Status:    string(recorded.Status),
+		Duplicate: duplicate,
+		Pending:   len(pending),
+		Message:   message,
+	}, nil
+}
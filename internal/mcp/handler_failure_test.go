@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestHandleFloopReportFailure(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	args := FloopReportFailureInput{
+		Kind:    "test",
+		Command: "go test ./internal/parser",
+		Action:  "changed the token output format",
+		Error:   "TestParse: golden file mismatch",
+		Fix:     "regenerate golden files with -update after changing output",
+		File:    "internal/parser/parser.go",
+	}
+	_, output, err := server.handleFloopReportFailure(ctx, &sdk.CallToolRequest{}, args)
+	if err != nil {
+		t.Fatalf("handleFloopReportFailure failed: %v", err)
+	}
+	if output.FailureID == "" || output.Status != string(models.FailureStatusPending) || output.Pending != 1 || output.Duplicate {
+		t.Fatalf("output = %+v, want one new pending failure", output)
+	}
+
+	failure, err := learning.GetFailure(ctx, server.store, output.FailureID)
+	if err != nil || failure == nil {
+		t.Fatalf("GetFailure() = %v, %v", failure, err)
+	}
+	if failure.Kind != models.FailureKindTest || failure.Context.FileLanguage != "go" {
+		t.Errorf("failure = %+v, want a test failure with go context", failure)
+	}
+
+	// Recording does not extract a behavior
+	behaviors, _ := server.store.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	for _, n := range behaviors {
+		if models.NodeToBehavior(n).Provenance.SourceType == models.SourceTypeFailure {
+			t.Errorf("behavior %s extracted before review", n.ID)
+		}
+	}
+
+	_, again, err := server.handleFloopReportFailure(ctx, &sdk.CallToolRequest{}, args)
+	if err != nil {
+		t.Fatalf("second report failed: %v", err)
+	}
+	if !again.Duplicate || again.FailureID != output.FailureID || again.Pending != 1 {
+		t.Errorf("second report = %+v, want duplicate of %s", again, output.FailureID)
+	}
+}
+
+func TestHandleFloopReportFailure_InvalidInput(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		args FloopReportFailureInput
+	}{
+		{"missing error", FloopReportFailureInput{Fix: "do x"}},
+		{"missing fix", FloopReportFailureInput{Error: "boom"}},
+		{"invalid kind", FloopReportFailureInput{Kind: "flaky", Error: "boom", Fix: "do x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := server.handleFloopReportFailure(context.Background(), &sdk.CallToolRequest{}, tt.args); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
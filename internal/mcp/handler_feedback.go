@@ -6,7 +6,7 @@ import (
 	"time"
 
 	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
-	"github.com/nvandessel/floop/internal/ratelimit"
+	"github.com/nvandessel/floop/internal/store"
 )
 
 // handleFloopFeedback implements the floop_feedback tool.
@@ -19,7 +19,7 @@ func (s *Server) handleFloopFeedback(ctx context.Context, req *sdk.CallToolReque
 		}), "local")
 	}()
 
-	if err := ratelimit.CheckLimit(s.toolLimiters, "floop_feedback"); err != nil {
+	if err := s.checkRateLimit(ctx, "floop_feedback"); err != nil {
 		return nil, FloopFeedbackOutput{}, err
 	}
 
@@ -65,6 +65,8 @@ func (s *Server) handleFloopFeedback(ctx context.Context, req *sdk.CallToolReque
 		}
 	}
 
+	s.invalidatePrewarm()
+
 	message := fmt.Sprintf("Feedback recorded: behavior %s marked as %s", args.BehaviorID, args.Signal)
 
 	return nil, FloopFeedbackOutput{
@@ -73,3 +75,104 @@ func (s *Server) handleFloopFeedback(ctx context.Context, req *sdk.CallToolReque
 		Message:    message,
 	}, nil
 }
+
+// maxFeedbackBatch caps the items in one floop_feedback_batch call, and
+// maxFeedbackStrength the times a single item may count its signal.
+const (
+	maxFeedbackBatch    = 100
+	maxFeedbackStrength = 5
+)
+
+// handleFloopFeedbackBatch implements the floop_feedback_batch tool.
+// It records feedback for many behaviors in one call and one write per
+// store, so an agent can reconcile a whole session at its end. Malformed
+// items fail the call; behaviors that don't exist are skipped and reported.
+func (s *Server) handleFloopFeedbackBatch(ctx context.Context, req *sdk.CallToolRequest, args FloopFeedbackBatchInput) (_ *sdk.CallToolResult, _ FloopFeedbackBatchOutput, retErr error) {
+	start := time.Now()
+	defer func() {
+		s.auditTool("floop_feedback_batch", start, retErr, sanitizeToolParams("floop_feedback_batch", map[string]interface{}{
+			"items": len(args.Items),
+		}), "local")
+	}()
+
+	if err := s.checkRateLimit(ctx, "floop_feedback_batch"); err != nil {
+		return nil, FloopFeedbackBatchOutput{}, err
+	}
+
+	if len(args.Items) == 0 {
+		return nil, FloopFeedbackBatchOutput{}, fmt.Errorf("'items' parameter is required")
+	}
+	if len(args.Items) > maxFeedbackBatch {
+		return nil, FloopFeedbackBatchOutput{}, fmt.Errorf("'items' must have at most %d entries, got %d", maxFeedbackBatch, len(args.Items))
+	}
+	for i, item := range args.Items {
+		if item.BehaviorID == "" {
+			return nil, FloopFeedbackBatchOutput{}, fmt.Errorf("items[%d]: 'behavior_id' is required", i)
+		}
+		if item.Signal != "confirmed" && item.Signal != "overridden" {
+			return nil, FloopFeedbackBatchOutput{}, fmt.Errorf("items[%d]: 'signal' must be 'confirmed' or 'overridden', got %q", i, item.Signal)
+		}
+		if item.Strength < 0 || item.Strength > maxFeedbackStrength {
+			return nil, FloopFeedbackBatchOutput{}, fmt.Errorf("items[%d]: 'strength' must be between 1 and %d, got %d", i, maxFeedbackStrength, item.Strength)
+		}
+	}
+
+	recorder, ok := s.store.(interface {
+		BatchRecordFeedback(ctx context.Context, updates []store.FeedbackUpdate) error
+	})
+	if !ok {
+		return nil, FloopFeedbackBatchOutput{}, fmt.Errorf("store does not support feedback recording")
+	}
+
+	// Combine items per behavior so each behavior is written once
+	var out FloopFeedbackBatchOutput
+	var updates []store.FeedbackUpdate
+	index := make(map[string]int)
+	missing := make(map[string]bool)
+	for _, item := range args.Items {
+		if missing[item.BehaviorID] {
+			continue
+		}
+		i, seen := index[item.BehaviorID]
+		if !seen {
+			node, err := s.store.GetNode(ctx, item.BehaviorID)
+			if err != nil {
+				return nil, FloopFeedbackBatchOutput{}, fmt.Errorf("failed to look up behavior: %w", err)
+			}
+			if node == nil {
+				missing[item.BehaviorID] = true
+				out.Skipped = append(out.Skipped, FeedbackBatchSkipped{BehaviorID: item.BehaviorID, Reason: "behavior not found"})
+				continue
+			}
+			i = len(updates)
+			index[item.BehaviorID] = i
+			updates = append(updates, store.FeedbackUpdate{BehaviorID: item.BehaviorID})
+		}
+
+		strength := item.Strength
+		if strength == 0 {
+			strength = 1
+		}
+		if item.Signal == "confirmed" {
+			updates[i].Confirmed += strength
+			out.Confirmed += strength
+		} else {
+			updates[i].Overridden += strength
+			out.Overridden += strength
+		}
+		out.Recorded++
+	}
+
+	if err := recorder.BatchRecordFeedback(ctx, updates); err != nil {
+		return nil, FloopFeedbackBatchOutput{}, fmt.Errorf("failed to record feedback: %w", err)
+	}
+	if len(updates) > 0 {
+		s.invalidatePrewarm()
+	}
+
+	out.Message = fmt.Sprintf("Feedback recorded for %d behavior(s): %d confirmed, %d overridden", len(updates), out.Confirmed, out.Overridden)
+	if len(out.Skipped) > 0 {
+		out.Message += fmt.Sprintf("; %d skipped", len(out.Skipped))
+	}
+	return nil, out, nil
+}
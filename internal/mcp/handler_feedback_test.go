@@ -7,7 +7,9 @@ import (
 	"time"
 
 	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/constants"
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/ratelimit"
 	"github.com/nvandessel/floop/internal/store"
 )
 
@@ -177,3 +179,93 @@ func TestHandleFloopFeedback_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleFloopFeedbackBatch(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	addTestBehavior(t, server, "fb-batch-1")
+	addTestBehavior(t, server, "fb-batch-2")
+
+	ctx := context.Background()
+	_, output, err := server.handleFloopFeedbackBatch(ctx, &sdk.CallToolRequest{}, FloopFeedbackBatchInput{
+		Items: []FeedbackBatchItem{
+			{BehaviorID: "fb-batch-1", Signal: "confirmed", Strength: 2},
+			{BehaviorID: "fb-batch-1", Signal: "confirmed"},
+			{BehaviorID: "fb-batch-2", Signal: "overridden"},
+			{BehaviorID: "nonexistent", Signal: "confirmed"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("handleFloopFeedbackBatch failed: %v", err)
+	}
+	if output.Recorded != 3 || output.Confirmed != 3 || output.Overridden != 1 {
+		t.Errorf("output = %+v, want 3 recorded, 3 confirmed, 1 overridden", output)
+	}
+	if len(output.Skipped) != 1 || output.Skipped[0].BehaviorID != "nonexistent" {
+		t.Errorf("Skipped = %+v, want nonexistent", output.Skipped)
+	}
+
+	for id, want := range map[string]map[string]int{
+		"fb-batch-1": {"times_confirmed": 3, "times_overridden": 0},
+		"fb-batch-2": {"times_confirmed": 0, "times_overridden": 1},
+	} {
+		node, err := server.store.GetNode(ctx, id)
+		if err != nil || node == nil {
+			t.Fatalf("GetNode(%s) = %v, %v", id, node, err)
+		}
+		stats, _ := node.Metadata["stats"].(map[string]interface{})
+		for field, n := range want {
+			if got, _ := stats[field].(int); got != n {
+				t.Errorf("%s %s = %v, want %d", id, field, stats[field], n)
+			}
+		}
+	}
+}
+
+func TestHandleFloopFeedbackBatch_Validation(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	addTestBehavior(t, server, "fb-batch-3")
+
+	tooMany := make([]FeedbackBatchItem, maxFeedbackBatch+1)
+	for i := range tooMany {
+		tooMany[i] = FeedbackBatchItem{BehaviorID: "fb-batch-3", Signal: "confirmed"}
+	}
+
+	tests := []struct {
+		name    string
+		items   []FeedbackBatchItem
+		wantErr string
+	}{
+		{"no items", nil, "'items' parameter is required"},
+		{"too many items", tooMany, "at most 100 entries"},
+		{"missing behavior_id", []FeedbackBatchItem{{Signal: "confirmed"}}, "items[0]: 'behavior_id' is required"},
+		{"invalid signal", []FeedbackBatchItem{{BehaviorID: "fb-batch-3", Signal: "maybe"}}, "items[0]: 'signal' must be"},
+		{"strength too high", []FeedbackBatchItem{{BehaviorID: "fb-batch-3", Signal: "confirmed", Strength: 6}}, "items[0]: 'strength' must be between 1 and 5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server.toolLimiters = ratelimit.NewLimits(constants.DefaultRateLimitGlobalCeiling) // The burst is smaller than the table
+			_, _, err := server.handleFloopFeedbackBatch(context.Background(), &sdk.CallToolRequest{}, FloopFeedbackBatchInput{Items: tt.items})
+			if err == nil {
+				t.Fatal("Expected error")
+			}
+			if got := err.Error(); !strings.Contains(got, tt.wantErr) {
+				t.Errorf("error = %q, want to contain %q", got, tt.wantErr)
+			}
+		})
+	}
+
+	// Nothing was recorded by the rejected calls
+	node, err := server.store.GetNode(context.Background(), "fb-batch-3")
+	if err != nil || node == nil {
+		t.Fatalf("GetNode() = %v, %v", node, err)
+	}
+	stats, _ := node.Metadata["stats"].(map[string]interface{})
+	if got, _ := stats["times_confirmed"].(int); got != 0 {
+		t.Errorf("times_confirmed = %d, want 0", got)
+	}
+}
@@ -7,7 +7,7 @@ import (
 	"time"
 
 	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
-	"github.com/nvandessel/floop/internal/ratelimit"
+	"github.com/nvandessel/floop/internal/ranking"
 	"github.com/nvandessel/floop/internal/store"
 	"github.com/nvandessel/floop/internal/visualization"
 )
@@ -22,7 +22,7 @@ func (s *Server) handleFloopConnect(ctx context.Context, req *sdk.CallToolReques
 		}), "local")
 	}()
 
-	if err := ratelimit.CheckLimit(s.toolLimiters, "floop_connect"); err != nil {
+	if err := s.checkRateLimit(ctx, "floop_connect"); err != nil {
 		return nil, FloopConnectOutput{}, err
 	}
 
@@ -40,7 +40,7 @@ func (s *Server) handleFloopConnect(ctx context.Context, req *sdk.CallToolReques
 	// Validate kind
 	edgeKind := store.EdgeKind(args.Kind)
 	if !store.ValidUserEdgeKinds[edgeKind] {
-		return nil, FloopConnectOutput{}, fmt.Errorf("invalid edge kind: %s (must be one of: requires, overrides, conflicts, similar-to, learned-from)", args.Kind)
+		return nil, FloopConnectOutput{}, fmt.Errorf("invalid edge kind: %s (must be one of: requires, overrides, conflicts, similar-to, learned-from, specializes)", args.Kind)
 	}
 
 	// Default weight
@@ -144,7 +144,7 @@ func (s *Server) handleFloopValidate(ctx context.Context, req *sdk.CallToolReque
 		s.auditTool("floop_validate", start, retErr, nil, "local")
 	}()
 
-	if err := ratelimit.CheckLimit(s.toolLimiters, "floop_validate"); err != nil {
+	if err := s.checkRateLimit(ctx, "floop_validate"); err != nil {
 		return nil, FloopValidateOutput{}, err
 	}
 
@@ -219,11 +219,15 @@ func (s *Server) handleFloopGraph(ctx context.Context, req *sdk.CallToolRequest,
 	start := time.Now()
 	defer func() {
 		s.auditTool("floop_graph", start, retErr, sanitizeToolParams("floop_graph", map[string]interface{}{
-			"format": args.Format,
+			"format": args.Format, "tags": args.Tags, "kinds": args.Kinds, "min_confidence": args.MinConfidence,
+			"around": args.Around, "depth": args.Depth, "top_n": args.TopN, "min_weight": args.MinWeight,
 		}), "local")
 	}()
 
-	if err := ratelimit.CheckLimit(s.toolLimiters, "floop_graph"); err != nil {
+	if err := s.checkRateLimit(ctx, "floop_graph"); err != nil {
+		return nil, FloopGraphOutput{}, err
+	}
+	if err := s.flush(ctx, args.Flush); err != nil {
 		return nil, FloopGraphOutput{}, err
 	}
 
@@ -232,16 +236,25 @@ func (s *Server) handleFloopGraph(ctx context.Context, req *sdk.CallToolRequest,
 		format = "json"
 	}
 
+	filter, err := graphFilter(args)
+	if err != nil {
+		return nil, FloopGraphOutput{}, err
+	}
+
+	s.pageRankMu.RLock()
+	pageRank := s.pageRankCache
+	s.pageRankMu.RUnlock()
+
 	switch visualization.Format(format) {
 	case visualization.FormatDOT:
-		dot, err := visualization.RenderDOT(ctx, s.store)
+		dot, err := visualization.RenderDOTFiltered(ctx, s.store, filter, pageRank, s.edgeDecayRate())
 		if err != nil {
 			return nil, FloopGraphOutput{}, fmt.Errorf("render DOT: %w", err)
 		}
 		// Count nodes for output metadata
-		nodes, err := s.store.QueryNodes(ctx, map[string]interface{}{"kind": "behavior"})
+		nodes, err := visualization.SelectNodes(ctx, s.store, filter, pageRank)
 		if err != nil {
-			return nil, FloopGraphOutput{}, fmt.Errorf("query nodes: %w", err)
+			return nil, FloopGraphOutput{}, err
 		}
 		return nil, FloopGraphOutput{
 			Format:    "dot",
@@ -250,7 +263,7 @@ func (s *Server) handleFloopGraph(ctx context.Context, req *sdk.CallToolRequest,
 		}, nil
 
 	case visualization.FormatJSON:
-		result, err := visualization.RenderJSON(ctx, s.store)
+		result, err := visualization.RenderJSONFiltered(ctx, s.store, filter, pageRank, s.edgeDecayRate())
 		if err != nil {
 			return nil, FloopGraphOutput{}, fmt.Errorf("render JSON: %w", err)
 		}
@@ -264,21 +277,17 @@ func (s *Server) handleFloopGraph(ctx context.Context, req *sdk.CallToolRequest,
 		}, nil
 
 	case visualization.FormatHTML:
-		s.pageRankMu.RLock()
-		pageRank := s.pageRankCache
-		s.pageRankMu.RUnlock()
-
-		enrichment := &visualization.EnrichmentData{PageRank: pageRank}
+		enrichment := &visualization.EnrichmentData{PageRank: pageRank, Filter: filter, EdgeDecayRate: s.edgeDecayRate()}
 		htmlBytes, err := visualization.RenderHTML(ctx, s.store, enrichment)
 		if err != nil {
 			return nil, FloopGraphOutput{}, fmt.Errorf("render HTML: %w", err)
 		}
 
-		nodes, err := s.store.QueryNodes(ctx, map[string]interface{}{"kind": "behavior"})
+		nodes, err := visualization.SelectNodes(ctx, s.store, filter, pageRank)
 		if err != nil {
-			return nil, FloopGraphOutput{}, fmt.Errorf("query nodes: %w", err)
+			return nil, FloopGraphOutput{}, err
 		}
-		edges, err := visualization.CollectEdges(ctx, s.store, nodes)
+		edges, err := visualization.CollectFilteredEdges(ctx, s.store, nodes, filter)
 		if err != nil {
 			return nil, FloopGraphOutput{}, fmt.Errorf("collect edges: %w", err)
 		}
@@ -294,3 +303,37 @@ func (s *Server) handleFloopGraph(ctx context.Context, req *sdk.CallToolRequest,
 		return nil, FloopGraphOutput{}, fmt.Errorf("unsupported format %q (use 'dot', 'json', or 'html')", format)
 	}
 }
+
+// graphFilter builds the floop_graph filter from args. Returns nil when no
+// filter is requested.
+func graphFilter(args FloopGraphInput) (*visualization.Filter, error) {
+	kinds := make([]string, 0, len(args.Kinds))
+	for _, k := range args.Kinds {
+		kinds = append(kinds, strings.ToLower(strings.TrimSpace(k)))
+	}
+	filter := &visualization.Filter{
+		Tags:          args.Tags,
+		Kinds:         kinds,
+		MinConfidence: args.MinConfidence,
+		Around:        args.Around,
+		Depth:         args.Depth,
+		TopN:          args.TopN,
+		MinWeight:     args.MinWeight,
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	if filter.IsZero() {
+		return nil, nil
+	}
+	return filter, nil
+}
+
+// edgeDecayRate returns the spreading edge decay rate for the configured
+// activation.edge_half_life.
+func (s *Server) edgeDecayRate() float64 {
+	if s.floopConfig == nil {
+		return ranking.DefaultDecayRate
+	}
+	return ranking.HalfLifeDecayRate(s.floopConfig.Activation.EdgeHalfLife)
+}
@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestHandleFloopGraph_Filters(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	seedQueryBehaviors(t, server)
+	ctx := context.Background()
+	req := &sdk.CallToolRequest{}
+
+	tests := []struct {
+		name      string
+		args      FloopGraphInput
+		wantNodes int
+		wantEdges int
+	}{
+		{"kinds", FloopGraphInput{Kinds: []string{"Constraint"}}, 1, 0},
+		{"tags", FloopGraphInput{Tags: []string{"errors"}}, 2, 1},
+		{"tags and min confidence", FloopGraphInput{Tags: []string{"errors"}, MinConfidence: 0.85}, 1, 0},
+		{"neighborhood", FloopGraphInput{Around: "q-panic"}, 2, 1},
+		{"neighborhood html", FloopGraphInput{Format: "html", Around: "q-panic"}, 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, out, err := server.handleFloopGraph(ctx, req, tt.args)
+			if err != nil {
+				t.Fatalf("handleFloopGraph() error = %v", err)
+			}
+			if out.NodeCount != tt.wantNodes || out.EdgeCount != tt.wantEdges {
+				t.Errorf("counts = %d nodes, %d edges; want %d, %d", out.NodeCount, out.EdgeCount, tt.wantNodes, tt.wantEdges)
+			}
+		})
+	}
+}
+
+func TestHandleFloopGraph_InvalidFilter(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	seedQueryBehaviors(t, server)
+	ctx := context.Background()
+	req := &sdk.CallToolRequest{}
+
+	tests := []struct {
+		name    string
+		args    FloopGraphInput
+		wantErr string
+	}{
+		{"bad kind", FloopGraphInput{Kinds: []string{"bogus"}}, "invalid kind"},
+		{"bad confidence", FloopGraphInput{MinConfidence: 2}, "min confidence"},
+		{"depth without around", FloopGraphInput{Depth: 2}, "depth requires"},
+		{"negative top", FloopGraphInput{TopN: -3}, "top N"},
+		{"unknown around", FloopGraphInput{Around: "missing"}, "behavior not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := server.handleFloopGraph(ctx, req, tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("handleFloopGraph() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHandleFloopGraph_MinWeight(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	seedQueryBehaviors(t, server)
+	ctx := context.Background()
+	req := &sdk.CallToolRequest{}
+
+	tests := []struct {
+		name      string
+		args      FloopGraphInput
+		wantNodes int
+		wantEdges int
+	}{
+		{"keeps strong edges", FloopGraphInput{Tags: []string{"errors"}, MinWeight: 0.8}, 2, 1},
+		{"drops weak edges", FloopGraphInput{Tags: []string{"errors"}, MinWeight: 0.9}, 2, 0},
+		{"neighborhood follows strong edges only", FloopGraphInput{Around: "q-panic", MinWeight: 0.9}, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, out, err := server.handleFloopGraph(ctx, req, tt.args)
+			if err != nil {
+				t.Fatalf("handleFloopGraph() error = %v", err)
+			}
+			if out.NodeCount != tt.wantNodes || out.EdgeCount != tt.wantEdges {
+				t.Errorf("counts = %d nodes, %d edges; want %d, %d", out.NodeCount, out.EdgeCount, tt.wantNodes, tt.wantEdges)
+			}
+		})
+	}
+
+	if _, _, err := server.handleFloopGraph(ctx, req, FloopGraphInput{MinWeight: 1.5}); err == nil || !strings.Contains(err.Error(), "min weight") {
+		t.Errorf("handleFloopGraph(min_weight 1.5) error = %v, want a range error", err)
+	}
+}
+
+func TestHandleFloopGraph_EdgeDecay(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	seedQueryBehaviors(t, server)
+	server.floopConfig.Activation.EdgeHalfLife = 24 * time.Hour
+
+	_, out, err := server.handleFloopGraph(context.Background(), &sdk.CallToolRequest{}, FloopGraphInput{Around: "q-panic"})
+	if err != nil {
+		t.Fatalf("handleFloopGraph() error = %v", err)
+	}
+	graph, _ := out.Graph.(map[string]interface{})
+	edges, _ := graph["edges"].([]map[string]interface{})
+	if len(edges) != 1 {
+		t.Fatalf("edges = %v, want 1", graph["edges"])
+	}
+	if _, ok := edges[0]["effective_weight"].(float64); !ok {
+		t.Errorf("edge = %v, want an effective_weight", edges[0])
+	}
+	if got, want := server.edgeDecayRate(), math.Ln2/24; math.Abs(got-want) > 1e-9 {
+		t.Errorf("edgeDecayRate() = %v, want %v for a 24h half-life", got, want)
+	}
+}
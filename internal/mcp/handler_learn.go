@@ -2,9 +2,7 @@ package mcp
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -12,11 +10,15 @@ import (
 	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/nvandessel/floop/internal/activation"
 	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/config"
 	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/corrections"
 	"github.com/nvandessel/floop/internal/dedup"
+	"github.com/nvandessel/floop/internal/expiry"
 	"github.com/nvandessel/floop/internal/learning"
 	"github.com/nvandessel/floop/internal/models"
-	"github.com/nvandessel/floop/internal/ratelimit"
+	"github.com/nvandessel/floop/internal/quota"
+	"github.com/nvandessel/floop/internal/retirement"
 	"github.com/nvandessel/floop/internal/sanitize"
 	"github.com/nvandessel/floop/internal/store"
 	"github.com/nvandessel/floop/internal/tagging"
@@ -31,11 +33,11 @@ func (s *Server) handleFloopLearn(ctx context.Context, req *sdk.CallToolRequest,
 			auditScope = "local" // fallback if error before scope is determined
 		}
 		s.auditTool("floop_learn", start, retErr, sanitizeToolParams("floop_learn", map[string]interface{}{
-			"wrong": args.Wrong, "right": args.Right, "file": args.File, "task": args.Task, "language": args.Language, "auto_merge": args.AutoMerge, "tags": args.Tags,
+			"wrong": args.Wrong, "right": args.Right, "file": args.File, "task": args.Task, "language": args.Language, "auto_merge": args.AutoMerge, "tags": args.Tags, "when": args.When, "expires_at": args.ExpiresAt,
 		}), auditScope)
 	}()
 
-	if err := ratelimit.CheckLimit(s.toolLimiters, "floop_learn"); err != nil {
+	if err := s.checkRateLimit(ctx, "floop_learn"); err != nil {
 		return nil, FloopLearnOutput{}, err
 	}
 
@@ -44,6 +46,128 @@ func (s *Server) handleFloopLearn(ctx context.Context, req *sdk.CallToolRequest,
 		return nil, FloopLearnOutput{}, fmt.Errorf("'right' parameter is required")
 	}
 
+	// Stop an agent stuck submitting the same correction before it reaches
+	// the store
+	if err := s.learnBreaker.Allow(args.Right); err != nil {
+		return nil, FloopLearnOutput{}, err
+	}
+
+	// Create correction with nanosecond-precision ID for uniqueness
+	now := time.Now()
+	correction, err := s.learnCorrection(req, args, now, fmt.Sprintf("c-%d", now.UnixNano()))
+	if err != nil {
+		return nil, FloopLearnOutput{}, err
+	}
+
+	loop, loopConfig, policyConfig := s.newLearningLoop()
+
+	learningResult, err := loop.ProcessCorrection(ctx, correction)
+	if err != nil {
+		return nil, FloopLearnOutput{}, fmt.Errorf("failed to process correction: %w", err)
+	}
+	auditScope = string(learningResult.Scope)
+
+	if learningResult.Held {
+		held := learning.HeldCorrection{
+			Correction: correction,
+			Quality:    *learningResult.Quality,
+			MinScore:   loopConfig.MinQualityScore,
+			HeldAt:     time.Now(),
+		}
+		if err := learning.HoldCorrection(filepath.Join(s.root, ".floop"), held); err != nil {
+			return nil, FloopLearnOutput{}, fmt.Errorf("failed to hold correction: %w", err)
+		}
+		return nil, FloopLearnOutput{
+			CorrectionID:   correction.ID,
+			Held:           true,
+			QualityScore:   learningResult.Quality.Score,
+			QualityReasons: learningResult.Quality.Reasons,
+			Message: fmt.Sprintf("Correction held for review (quality %.2f below %.2f): %s. Restate it as a specific instruction to learn it.",
+				learningResult.Quality.Score, loopConfig.MinQualityScore, strings.Join(learningResult.Quality.Reasons, ", ")),
+		}, nil
+	}
+
+	// At a storage limit, keep the correction unprocessed for 'floop reprocess'
+	if learningResult.Quota != nil {
+		s.logCorrections(correction)
+		return nil, FloopLearnOutput{
+			CorrectionID:  correction.ID,
+			Scope:         string(learningResult.Scope),
+			LimitReached:  learningResult.Quota.Usage.Limit,
+			Consolidation: learningResult.Quota.Candidates,
+			Policy:        appliedPolicy(policyConfig, learningResult),
+			Message: fmt.Sprintf("Storage limit reached (%s); behavior not added. Merge or forget the listed candidates, then run 'floop reprocess'.",
+				learningResult.Quota.Usage),
+		}, nil
+	}
+
+	// Sync store to persist changes
+	if err := s.store.Sync(ctx); err != nil {
+		return nil, FloopLearnOutput{}, fmt.Errorf("failed to sync store: %w", err)
+	}
+
+	s.scheduleAutoBackup()
+	s.indexLearned(ctx, learningResult)
+	s.emitLearned("floop_learn", learningResult)
+
+	// Debounced PageRank refresh after graph mutation
+	s.debouncedRefreshPageRank()
+
+	// Mark correction as processed and write to corrections log for audit trail
+	correction.Processed = true
+	processedAt := time.Now()
+	correction.ProcessedAt = &processedAt
+	s.logCorrections(correction)
+	// Note: We don't fail if the corrections log write fails - the behavior is already saved
+
+	// Build result message with scope info
+	scope := string(learningResult.Scope)
+	message := fmt.Sprintf("Learned behavior (%s): %s", scope, learningResult.CandidateBehavior.Name)
+	if learningResult.MergedIntoExisting {
+		message = fmt.Sprintf("Merged into existing behavior (%s): %s (similarity: %.2f)",
+			scope, learningResult.MergedBehaviorID, learningResult.MergeSimilarity)
+	} else if learningResult.Injection != nil {
+		message = fmt.Sprintf("Behavior quarantined as a possible prompt injection (%s): %s (%s). It will not be injected until a human releases it with 'floop restore %s'",
+			scope, learningResult.CandidateBehavior.Name,
+			strings.Join(learningResult.Injection.Rules(), ", "), learningResult.CandidateBehavior.ID)
+	} else if learningResult.RequiresReview {
+		message = fmt.Sprintf("Behavior requires review (%s): %s (%s). It is queued for 'floop review'",
+			scope, learningResult.CandidateBehavior.Name,
+			strings.Join(learningResult.ReviewReasons, ", "))
+	}
+
+	// Retired behaviors this correction recurred on were restored
+	restoredIDs := s.recordRestored(learningResult.Restored)
+	if len(restoredIDs) > 0 {
+		message += fmt.Sprintf("; restored retired behavior(s): %s", strings.Join(restoredIDs, ", "))
+	}
+
+	var qualityScore float64
+	if learningResult.Quality != nil {
+		qualityScore = learningResult.Quality.Score
+	}
+
+	return nil, FloopLearnOutput{
+		CorrectionID:    correction.ID,
+		BehaviorID:      learningResult.CandidateBehavior.ID,
+		QualityScore:    qualityScore,
+		Scope:           scope,
+		AutoAccepted:    learningResult.AutoAccepted,
+		Confidence:      learningResult.Placement.Confidence,
+		RequiresReview:  learningResult.RequiresReview,
+		ReviewReasons:   learningResult.ReviewReasons,
+		MergedIntoID:    learningResult.MergedBehaviorID,
+		MergeSimilarity: learningResult.MergeSimilarity,
+		RestoredIDs:     restoredIDs,
+		Quarantined:     learningResult.Injection != nil,
+		Policy:          appliedPolicy(policyConfig, learningResult),
+		Message:         message,
+	}, nil
+}
+
+// learnCorrection sanitizes a learn request and builds its correction with
+// the given ID, capturing the context of the calling client.
+func (s *Server) learnCorrection(req *sdk.CallToolRequest, args FloopLearnInput, now time.Time, id string) (models.Correction, error) {
 	// Sanitize inputs at the handler level as defense-in-depth.
 	// The extraction layer also sanitizes, but this protects against
 	// any code path that bypasses the learning loop.
@@ -78,77 +202,135 @@ func (s *Server) handleFloopLearn(ctx context.Context, req *sdk.CallToolRequest,
 	}
 
 	ctxBuilder.WithRepoRoot(s.root)
+	if client := clientName(req); client != "" {
+		ctxBuilder.WithAgent(client)
+	}
 	ctxSnapshot := ctxBuilder.Build()
 
-	// Create correction with nanosecond-precision ID for uniqueness
-	now := time.Now()
-
 	// Silently truncate extra tags to MaxExtraTags
 	extraTags := args.Tags
 	if len(extraTags) > tagging.MaxExtraTags {
 		extraTags = extraTags[:tagging.MaxExtraTags]
 	}
 
-	correction := models.Correction{
-		ID:              fmt.Sprintf("c-%d", now.UnixNano()),
+	var extraWhen map[string]interface{}
+	for _, w := range args.When {
+		key, value, err := models.ParseCondition(w)
+		if err != nil {
+			return models.Correction{}, fmt.Errorf("'when': %w", err)
+		}
+		if extraWhen == nil {
+			extraWhen = make(map[string]interface{})
+		}
+		extraWhen[key] = value
+	}
+
+	var expiresAt *time.Time
+	if args.ExpiresAt != "" {
+		t, err := expiry.Parse(args.ExpiresAt, now)
+		if err != nil {
+			return models.Correction{}, fmt.Errorf("'expires_at': %w", err)
+		}
+		if !t.After(now) {
+			return models.Correction{}, fmt.Errorf("'expires_at' must be in the future")
+		}
+		expiresAt = &t
+	}
+
+	return models.Correction{
+		ID:              id,
 		Timestamp:       now,
 		Context:         ctxSnapshot,
 		AgentAction:     args.Wrong,
 		CorrectedAction: args.Right,
 		Corrector:       "mcp-client",
 		ExtraTags:       extraTags,
+		ExtraWhen:       extraWhen,
+		ExpiresAt:       expiresAt,
 		Processed:       false,
-	}
+	}, nil
+}
 
-	// Configure learning loop - auto-merge is ON by default
-	// This prevents duplicate behaviors from accumulating
-	loopConfig := &learning.LearningLoopConfig{
-		AutoAcceptThreshold: constants.DefaultAutoAcceptThreshold,
-		AutoMerge:           true, // Always deduplicate
-		AutoMergeThreshold:  constants.DefaultAutoMergeThreshold,
+// newLearningLoop builds the learning loop for MCP learn calls, returning
+// it with its configuration and the learning config section it came from.
+func (s *Server) newLearningLoop() (learning.LearningLoop, *learning.LearningLoopConfig, config.LearningConfig) {
+	// Learning policy from config: thresholds, auto-merge, review triggers,
+	// scope routing, and per-scope overrides
+	policyConfig := config.Default().Learning
+	if s.floopConfig != nil {
+		policyConfig = s.floopConfig.Learning
 	}
+	loopConfig := learningLoopConfig(policyConfig)
 
-	// Create deduplicator for automatic merging
+	// Create deduplicator for automatic merging, finding candidates down to
+	// the lowest merge threshold any scope uses
 	merger := dedup.NewBehaviorMerger(dedup.MergerConfig{})
 	dedupConfig := dedup.DeduplicatorConfig{
-		SimilarityThreshold: constants.DefaultAutoMergeThreshold,
+		SimilarityThreshold: loopConfig.AutoMergeThreshold,
 		AutoMerge:           true,
+		Similarity:          s.dedupSimilarity(),
+	}
+	for _, p := range loopConfig.ScopePolicies {
+		dedupConfig.SimilarityThreshold = min(dedupConfig.SimilarityThreshold, p.AutoMergeThreshold)
 	}
 	loopConfig.Deduplicator = dedup.NewStoreDeduplicator(s.store, merger, dedupConfig)
 
-	// Process correction through learning loop
-	loop := learning.NewLearningLoop(s.store, loopConfig)
+	// Quality gate: hold noisy corrections instead of extracting them
+	if s.floopConfig != nil && s.floopConfig.Quality.Enabled {
+		loopConfig.QualityScorer = learning.NewQualityScorer(s.floopConfig.Quality.UseLLM, s.llmClient)
+		loopConfig.MinQualityScore = s.floopConfig.Quality.MinScore
+	}
 
-	learningResult, err := loop.ProcessCorrection(ctx, correction)
-	if err != nil {
-		return nil, FloopLearnOutput{}, fmt.Errorf("failed to process correction: %w", err)
+	// Extraction: generalize corrections with the LLM when configured
+	if s.floopConfig != nil {
+		loopConfig.Extractor = learning.NewExtractor(s.floopConfig.Learning.Extractor, s.llmClient)
 	}
-	auditScope = string(learningResult.Scope)
 
-	// Sync store to persist changes
-	if err := s.store.Sync(ctx); err != nil {
-		return nil, FloopLearnOutput{}, fmt.Errorf("failed to sync store: %w", err)
+	// Storage limits: propose consolidation instead of unbounded growth
+	if s.floopConfig != nil {
+		loopConfig.Limits = quota.Limits{
+			MaxBehaviorsPerScope: s.floopConfig.Limits.MaxBehaviorsPerScope,
+			MaxCanonicalLength:   s.floopConfig.Limits.MaxCanonicalLength,
+			MaxEdgesPerNode:      s.floopConfig.Limits.MaxEdgesPerNode,
+		}
 	}
 
-	// Auto-backup after successful learn (bounded background worker)
-	if s.backupConfig == nil || s.backupConfig.AutoBackup {
-		s.runBackground("auto-backup", func() {
-			backupDir, err := backup.DefaultBackupDir()
-			if err != nil {
-				s.logger.Warn("auto-backup failed (dir)", "error", err)
-				return
-			}
-			backupPath := backup.GenerateBackupPath(backupDir)
-			if _, err := backup.Backup(context.Background(), s.store, backupPath); err != nil {
-				s.logger.Warn("auto-backup failed", "error", err)
-				return
-			}
-			if _, err := backup.ApplyRetention(backupDir, s.retentionPolicy); err != nil {
-				s.logger.Warn("auto-backup retention failed", "error", err)
-			}
-		})
+	return learning.NewLearningLoop(s.store, loopConfig), loopConfig, policyConfig
+}
+
+// logCorrections appends corrections to the corrections log. Failures are
+// ignored: the log is an audit trail and the store already has the result.
+func (s *Server) logCorrections(cs ...models.Correction) {
+	_ = corrections.Append(filepath.Join(s.root, ".floop"), cs...)
+}
+
+// scheduleAutoBackup backs up the store after a successful learn (bounded
+// background worker) unless auto-backup is disabled.
+func (s *Server) scheduleAutoBackup() {
+	if s.backupConfig != nil && !s.backupConfig.AutoBackup {
+		return
 	}
+	s.runBackground("auto-backup", func() {
+		backupDir, err := backup.DefaultBackupDir()
+		if err != nil {
+			s.logger.Warn("auto-backup failed (dir)", "error", err)
+			return
+		}
+		backupPath := backup.GenerateBackupPath(backupDir)
+		result, err := backup.Backup(context.Background(), s.store, backupPath)
+		if err != nil {
+			s.logger.Warn("auto-backup failed", "error", err)
+			return
+		}
+		s.emitBackup("auto-backup", backupPath, len(result.Nodes), len(result.Edges))
+		if _, err := backup.ApplyRetention(backupDir, s.retentionPolicy); err != nil {
+			s.logger.Warn("auto-backup retention failed", "error", err)
+		}
+	})
+}
 
+// indexLearned keeps the vector index in step with a learn result.
+func (s *Server) indexLearned(ctx context.Context, learningResult *learning.LearningResult) {
 	// Remove the displaced behavior's vector from the index when auto-merge
 	// deletes the existing behavior from the store. Without this, LanceDB's
 	// persistence would accumulate ghost vectors on every auto-merge.
@@ -159,8 +341,9 @@ func (s *Server) handleFloopLearn(ctx context.Context, req *sdk.CallToolRequest,
 		}
 	}
 
-	// Background: embed the new/merged behavior for vector retrieval
-	if s.embedder != nil && s.embedder.Available() && learningResult.CandidateBehavior.ID != "" {
+	// Background: embed the new/merged behavior for vector retrieval.
+	// Quarantined behaviors are not retrievable until released.
+	if s.embedder != nil && s.embedder.Available() && learningResult.CandidateBehavior.ID != "" && learningResult.Injection == nil {
 		bid := learningResult.CandidateBehavior.ID
 		text := learningResult.CandidateBehavior.Content.Canonical
 		if text != "" {
@@ -178,44 +361,81 @@ func (s *Server) handleFloopLearn(ctx context.Context, req *sdk.CallToolRequest,
 			})
 		}
 	}
+}
 
-	// Debounced PageRank refresh after graph mutation
-	s.debouncedRefreshPageRank()
-
-	// Mark correction as processed and write to corrections log for audit trail
-	correction.Processed = true
-	processedAt := time.Now()
-	correction.ProcessedAt = &processedAt
+// recordRestored records notices for retired behaviors a learn restored and
+// returns their IDs.
+func (s *Server) recordRestored(notices []retirement.Notice) []string {
+	var restoredIDs []string
+	for _, n := range notices {
+		restoredIDs = append(restoredIDs, n.BehaviorID)
+	}
+	if len(restoredIDs) > 0 {
+		if err := retirement.RecordNotices(filepath.Join(s.root, ".floop"), notices); err != nil {
+			s.logger.Warn("failed to record retirement notices", "error", err)
+		}
+	}
+	return restoredIDs
+}
 
-	correctionsPath := filepath.Join(s.root, ".floop", "corrections.jsonl")
-	if f, err := os.OpenFile(correctionsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600); err == nil {
-		json.NewEncoder(f).Encode(correction)
-		f.Close()
+// learningLoopConfig builds the learning loop configuration for the
+// learning config section. Each configured scope override becomes a full
+// policy for that scope.
+func learningLoopConfig(c config.LearningConfig) *learning.LearningLoopConfig {
+	base := learningPolicy(c)
+	loopConfig := &learning.LearningLoopConfig{
+		AutoAcceptThreshold: base.AutoAcceptThreshold,
+		AutoMerge:           base.AutoMerge,
+		AutoMergeThreshold:  base.AutoMergeThreshold,
+		Review:              &base.Review,
+	}
+	for scope := range c.Scopes {
+		if loopConfig.ScopePolicies == nil {
+			loopConfig.ScopePolicies = make(map[constants.Scope]learning.Policy)
+		}
+		loopConfig.ScopePolicies[constants.Scope(scope)] = learningPolicy(c.ForScope(scope))
+	}
+	if c.Scope == string(constants.ScopeLocal) || c.Scope == string(constants.ScopeGlobal) {
+		scope := constants.Scope(c.Scope)
+		loopConfig.ScopeOverride = &scope
 	}
-	// Note: We don't fail if corrections.jsonl write fails - the behavior is already saved
+	return loopConfig
+}
 
-	// Build result message with scope info
-	scope := string(learningResult.Scope)
-	message := fmt.Sprintf("Learned behavior (%s): %s", scope, learningResult.CandidateBehavior.Name)
-	if learningResult.MergedIntoExisting {
-		message = fmt.Sprintf("Merged into existing behavior (%s): %s (similarity: %.2f)",
-			scope, learningResult.MergedBehaviorID, learningResult.MergeSimilarity)
-	} else if learningResult.RequiresReview {
-		message = fmt.Sprintf("Behavior requires review (%s): %s (%s)",
-			scope, learningResult.CandidateBehavior.Name,
-			strings.Join(learningResult.ReviewReasons, ", "))
+// learningPolicy converts a learning config section to a learning.Policy.
+func learningPolicy(c config.LearningConfig) learning.Policy {
+	return learning.Policy{
+		AutoAcceptThreshold: c.AutoAcceptThreshold,
+		AutoMerge:           c.AutoMerge,
+		AutoMergeThreshold:  c.AutoMergeThreshold,
+		Review: learning.ReviewTriggers{
+			Constraints:   c.Review.Constraints,
+			MinConfidence: c.Review.MinConfidence,
+			MaxSimilarity: c.Review.MaxSimilarity,
+		},
 	}
+}
 
-	return nil, FloopLearnOutput{
-		CorrectionID:    correction.ID,
-		BehaviorID:      learningResult.CandidateBehavior.ID,
-		Scope:           scope,
-		AutoAccepted:    learningResult.AutoAccepted,
-		Confidence:      learningResult.Placement.Confidence,
-		RequiresReview:  learningResult.RequiresReview,
-		ReviewReasons:   learningResult.ReviewReasons,
-		MergedIntoID:    learningResult.MergedBehaviorID,
-		MergeSimilarity: learningResult.MergeSimilarity,
-		Message:         message,
-	}, nil
+// appliedPolicy describes the policy a learn result was decided under, or
+// nil if none was applied.
+func appliedPolicy(c config.LearningConfig, result *learning.LearningResult) *LearnPolicy {
+	if result.Policy == nil {
+		return nil
+	}
+	p := result.Policy
+	routing := c.Scope
+	if routing == "" {
+		routing = "auto"
+	}
+	_, overridden := c.Scopes[string(result.Scope)]
+	return &LearnPolicy{
+		Routing:             routing,
+		ScopeOverride:       overridden,
+		AutoAcceptThreshold: p.AutoAcceptThreshold,
+		AutoMerge:           p.AutoMerge,
+		AutoMergeThreshold:  p.AutoMergeThreshold,
+		ReviewConstraints:   p.Review.Constraints,
+		ReviewMinConfidence: p.Review.MinConfidence,
+		ReviewMaxSimilarity: p.Review.MaxSimilarity,
+	}
 }
@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// maxLearnBatch caps the corrections in one floop_learn_batch call.
+const maxLearnBatch = 50
+
+// handleFloopLearnBatch implements the floop_learn_batch tool.
+// Every correction is validated before any is learned, so a malformed item
+// fails the call without touching the store. The rest run through the
+// learning loop in order, with repeats within the batch learned once, and
+// the store is synced, backed up, and re-ranked once for the whole batch.
+func (s *Server) handleFloopLearnBatch(ctx context.Context, req *sdk.CallToolRequest, args FloopLearnBatchInput) (_ *sdk.CallToolResult, _ FloopLearnBatchOutput, retErr error) {
+	start := time.Now()
+	defer func() {
+		s.auditTool("floop_learn_batch", start, retErr, sanitizeToolParams("floop_learn_batch", map[string]interface{}{
+			"corrections": len(args.Corrections),
+		}), "local")
+	}()
+
+	if err := s.checkRateLimit(ctx, "floop_learn_batch"); err != nil {
+		return nil, FloopLearnBatchOutput{}, err
+	}
+
+	if len(args.Corrections) == 0 {
+		return nil, FloopLearnBatchOutput{}, fmt.Errorf("'corrections' parameter is required")
+	}
+	if len(args.Corrections) > maxLearnBatch {
+		return nil, FloopLearnBatchOutput{}, fmt.Errorf("'corrections' must have at most %d entries, got %d", maxLearnBatch, len(args.Corrections))
+	}
+
+	now := time.Now()
+	corrections := make([]models.Correction, 0, len(args.Corrections))
+	for i, item := range args.Corrections {
+		if item.Right == "" {
+			return nil, FloopLearnBatchOutput{}, fmt.Errorf("corrections[%d]: 'right' is required", i)
+		}
+		c, err := s.learnCorrection(req, item, now, fmt.Sprintf("c-%d-%d", now.UnixNano(), i))
+		if err != nil {
+			return nil, FloopLearnBatchOutput{}, fmt.Errorf("corrections[%d]: %w", i, err)
+		}
+		if c.CorrectedAction == "" {
+			return nil, FloopLearnBatchOutput{}, fmt.Errorf("corrections[%d]: 'right' is empty after sanitization", i)
+		}
+		corrections = append(corrections, c)
+	}
+
+	loop, loopConfig, _ := s.newLearningLoop()
+	results := learning.ProcessBatch(ctx, loop, corrections, s.learnBreaker)
+
+	floopDir := filepath.Join(s.root, ".floop")
+	var logged []models.Correction
+	var restored []string
+	learned := 0
+	out := FloopLearnBatchOutput{Counts: make(map[string]int)}
+	for _, r := range results {
+		item := LearnBatchItem{Index: r.Index, Status: r.Status, CorrectionID: r.Correction.ID}
+		if r.Err != nil {
+			item.Error = r.Err.Error()
+		}
+		if r.Status == learning.BatchDuplicate {
+			dup := r.DuplicateOf
+			item.DuplicateOf = &dup
+		}
+
+		if res := r.Result; res != nil {
+			if res.Quality != nil {
+				item.QualityScore = res.Quality.Score
+			}
+			switch r.Status {
+			case learning.BatchHeld:
+				held := learning.HeldCorrection{
+					Correction: r.Correction,
+					Quality:    *res.Quality,
+					MinScore:   loopConfig.MinQualityScore,
+					HeldAt:     time.Now(),
+				}
+				if err := learning.HoldCorrection(floopDir, held); err != nil {
+					item.Status = learning.BatchError
+					item.Error = fmt.Sprintf("failed to hold correction: %v", err)
+				}
+			case learning.BatchLimitReached:
+				// Kept unprocessed for 'floop reprocess'
+				item.Scope = string(res.Scope)
+				item.LimitReached = res.Quota.Usage.Limit
+				logged = append(logged, r.Correction)
+			default:
+				item.BehaviorID = res.CandidateBehavior.ID
+				item.Scope = string(res.Scope)
+				item.MergedIntoID = res.MergedBehaviorID
+				item.MergeSimilarity = res.MergeSimilarity
+				item.ReviewReasons = res.ReviewReasons
+
+				c := r.Correction
+				processedAt := time.Now()
+				c.Processed = true
+				c.ProcessedAt = &processedAt
+				logged = append(logged, c)
+				restored = append(restored, s.recordRestored(res.Restored)...)
+				s.indexLearned(ctx, res)
+				s.emitLearned("floop_learn_batch", res)
+				learned++
+			}
+		}
+		out.Items = append(out.Items, item)
+		out.Counts[item.Status]++
+	}
+
+	if learned > 0 {
+		if err := s.store.Sync(ctx); err != nil {
+			return nil, FloopLearnBatchOutput{}, fmt.Errorf("failed to sync store: %w", err)
+		}
+		s.scheduleAutoBackup()
+		s.debouncedRefreshPageRank()
+	}
+	s.logCorrections(logged...)
+
+	out.Message = batchMessage(len(results), out.Counts)
+	if len(restored) > 0 {
+		out.Message += fmt.Sprintf("; restored retired behavior(s): %s", strings.Join(restored, ", "))
+	}
+	return nil, out, nil
+}
+
+// batchMessage summarizes batch counts in learning.BatchStatuses order.
+func batchMessage(total int, counts map[string]int) string {
+	var parts []string
+	for _, status := range learning.BatchStatuses {
+		if n := counts[status]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, status))
+		}
+	}
+	return fmt.Sprintf("Processed %d correction(s): %s", total, strings.Join(parts, ", "))
+}
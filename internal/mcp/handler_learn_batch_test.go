@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/corrections"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/ratelimit"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestHandleFloopLearnBatch(t *testing.T) {
+	server, tmpDir := setupTestServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	_, output, err := server.handleFloopLearnBatch(ctx, &sdk.CallToolRequest{}, FloopLearnBatchInput{
+		Corrections: []FloopLearnInput{
+			{Wrong: "used pip install", Right: "use uv instead of pip for package management", Language: "python"},
+			{Right: "Use uv instead of pip  for package management", Language: "python"},
+			{Right: "wrap errors with fmt.Errorf and %w", File: "main.go"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("handleFloopLearnBatch failed: %v", err)
+	}
+
+	if len(output.Items) != 3 {
+		t.Fatalf("got %d items, want 3", len(output.Items))
+	}
+	dup := output.Items[1]
+	if dup.Status != learning.BatchDuplicate || dup.DuplicateOf == nil || *dup.DuplicateOf != 0 || dup.BehaviorID != "" {
+		t.Errorf("items[1] = %+v, want duplicate of item 0", dup)
+	}
+	for _, i := range []int{0, 2} {
+		item := output.Items[i]
+		if item.BehaviorID == "" || item.CorrectionID == "" || item.Error != "" {
+			t.Errorf("items[%d] = %+v, want a learned behavior", i, item)
+		}
+	}
+	if output.Counts[learning.BatchDuplicate] != 1 {
+		t.Errorf("counts = %v, want 1 duplicate", output.Counts)
+	}
+	if !strings.Contains(output.Message, "Processed 3 correction(s)") {
+		t.Errorf("message = %q", output.Message)
+	}
+
+	for _, i := range []int{0, 2} {
+		node, err := server.store.GetNode(ctx, output.Items[i].BehaviorID)
+		if err != nil || node == nil {
+			t.Errorf("GetNode(%s) = %v, %v", output.Items[i].BehaviorID, node, err)
+		}
+	}
+
+	// Only the learned corrections are logged
+	logged, err := corrections.Load(filepath.Join(tmpDir, ".floop"))
+	if err != nil {
+		t.Fatalf("failed to read corrections log: %v", err)
+	}
+	if len(logged) != 2 {
+		t.Errorf("corrections log has %d corrections, want 2", len(logged))
+	}
+}
+
+func TestHandleFloopLearnBatch_Validation(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	behaviors := func() int {
+		nodes, err := server.store.QueryNodes(context.Background(), map[string]interface{}{"kind": store.NodeKindBehavior})
+		if err != nil {
+			t.Fatalf("QueryNodes() error = %v", err)
+		}
+		return len(nodes)
+	}
+	before := behaviors()
+
+	tooMany := make([]FloopLearnInput, maxLearnBatch+1)
+	for i := range tooMany {
+		tooMany[i] = FloopLearnInput{Right: "use uv"}
+	}
+
+	tests := []struct {
+		name        string
+		corrections []FloopLearnInput
+		wantErr     string
+	}{
+		{"no corrections", nil, "'corrections' parameter is required"},
+		{"too many corrections", tooMany, "at most 50 entries"},
+		{"missing right", []FloopLearnInput{{Right: "use uv"}, {Wrong: "pip"}}, "corrections[1]: 'right' is required"},
+		{"bad expiry", []FloopLearnInput{{Right: "use uv", ExpiresAt: "not a date"}}, "corrections[0]:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server.toolLimiters = ratelimit.NewLimits(constants.DefaultRateLimitGlobalCeiling) // The burst is smaller than the table
+			_, _, err := server.handleFloopLearnBatch(context.Background(), &sdk.CallToolRequest{}, FloopLearnBatchInput{Corrections: tt.corrections})
+			if err == nil {
+				t.Fatal("Expected error")
+			}
+			if got := err.Error(); !strings.Contains(got, tt.wantErr) {
+				t.Errorf("error = %q, want to contain %q", got, tt.wantErr)
+			}
+		})
+	}
+
+	// Nothing was learned by the rejected calls
+	if got := behaviors(); got != before {
+		t.Errorf("got %d behaviors after rejected batches, want %d", got, before)
+	}
+}
@@ -1,17 +1,17 @@
 package mcp
 
 import (
-	"bufio"
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
 	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/corrections"
 	"github.com/nvandessel/floop/internal/models"
-	"github.com/nvandessel/floop/internal/ratelimit"
+	"github.com/nvandessel/floop/internal/store"
 )
 
 // handleFloopList implements the floop_list tool.
@@ -19,98 +19,135 @@ func (s *Server) handleFloopList(ctx context.Context, req *sdk.CallToolRequest,
 	start := time.Now()
 	defer func() {
 		s.auditTool("floop_list", start, retErr, sanitizeToolParams("floop_list", map[string]interface{}{
-			"corrections": args.Corrections, "tag": args.Tag,
+			"corrections": args.Corrections, "tag": args.Tag, "limit": args.Limit,
+			"since": args.Since, "until": args.Until,
 		}), "local")
 	}()
 
-	if err := ratelimit.CheckLimit(s.toolLimiters, "floop_list"); err != nil {
+	if err := s.checkRateLimit(ctx, "floop_list"); err != nil {
+		return nil, FloopListOutput{}, err
+	}
+	if err := s.flush(ctx, args.Flush); err != nil {
 		return nil, FloopListOutput{}, err
 	}
 
 	if args.Corrections {
-		// List corrections from corrections.jsonl file (not graph store)
-		correctionsPath := filepath.Join(s.root, ".floop", "corrections.jsonl")
-		file, err := os.Open(correctionsPath)
+		return s.listCorrections(ctx, args)
+	}
+
+	// List behaviors a page at a time, so large stores are never held whole
+	paged := args.Limit > 0 || args.Cursor != ""
+	limit := args.Limit
+	if limit <= 0 {
+		limit = store.DefaultNodePageSize
+	}
+	predicate := map[string]interface{}{"kind": "behavior"}
+
+	behaviors := make([]BehaviorListItem, 0)
+	cursor, next := args.Cursor, ""
+	for {
+		nodes, pageNext, err := store.QueryNodesPage(ctx, s.store, predicate, cursor, limit)
 		if err != nil {
-			if os.IsNotExist(err) {
-				return nil, FloopListOutput{
-					Corrections: []CorrectionListItem{},
-					Count:       0,
-				}, nil
-			}
-			return nil, FloopListOutput{}, fmt.Errorf("failed to open corrections file: %w", err)
+			return nil, FloopListOutput{}, fmt.Errorf("failed to query behaviors: %w", err)
 		}
-		defer file.Close()
+		for _, node := range nodes {
+			behavior := models.NodeToBehavior(node)
 
-		var corrections []CorrectionListItem
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			line := scanner.Text()
-			if line == "" {
+			// Filter by tag if specified
+			if args.Tag != "" && !slices.Contains(behavior.Content.Tags, args.Tag) {
 				continue
 			}
-			var c models.Correction
-			if err := json.Unmarshal([]byte(line), &c); err != nil {
-				continue // Skip malformed lines
+			// A full page ends at the behavior after it, so a page
+			// filtered by tag still holds limit behaviors
+			if paged && len(behaviors) == limit {
+				next = behaviors[len(behaviors)-1].ID
+				break
 			}
-			corrections = append(corrections, CorrectionListItem{
-				ID:              c.ID,
-				Timestamp:       c.Timestamp,
-				AgentAction:     c.AgentAction,
-				CorrectedAction: c.CorrectedAction,
-				Processed:       c.Processed,
-			})
+			behaviors = append(behaviors, behaviorListItem(behavior))
 		}
-
-		return nil, FloopListOutput{
-			Corrections: corrections,
-			Count:       len(corrections),
-		}, nil
+		if next != "" || pageNext == "" {
+			break
+		}
+		cursor = pageNext
 	}
 
-	// List behaviors
-	nodes, err := s.store.QueryNodes(ctx, map[string]interface{}{"kind": "behavior"})
-	if err != nil {
-		return nil, FloopListOutput{}, fmt.Errorf("failed to query behaviors: %w", err)
+	return nil, FloopListOutput{
+		Behaviors:  behaviors,
+		Count:      len(behaviors),
+		NextCursor: next,
+	}, nil
+}
+
+// behaviorListItem returns the list view of behavior.
+func behaviorListItem(behavior models.Behavior) BehaviorListItem {
+	// Determine source
+	source := "unknown"
+	if behavior.Provenance.SourceType != "" {
+		source = string(behavior.Provenance.SourceType)
 	}
 
-	behaviors := make([]BehaviorListItem, 0, len(nodes))
-	for _, node := range nodes {
-		behavior := models.NodeToBehavior(node)
+	return BehaviorListItem{
+		ID:         behavior.ID,
+		Name:       behavior.Name,
+		Kind:       string(behavior.Kind),
+		Confidence: behavior.Confidence,
+		Tags:       behavior.Content.Tags,
+		Source:     source,
+		CreatedAt:  behavior.Provenance.CreatedAt,
+	}
+}
 
-		// Filter by tag if specified
-		if args.Tag != "" {
-			found := false
-			for _, t := range behavior.Content.Tags {
-				if t == args.Tag {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
-			}
+// listCorrections lists a page of the project's corrections (not the graph
+// store).
+func (s *Server) listCorrections(ctx context.Context, args FloopListInput) (*sdk.CallToolResult, FloopListOutput, error) {
+	filter := corrections.Filter{Cursor: args.Cursor, Limit: args.Limit}
+	for _, bound := range []struct {
+		name  string
+		value string
+		dst   *time.Time
+	}{{"since", args.Since, &filter.Since}, {"until", args.Until, &filter.Until}} {
+		if bound.value == "" {
+			continue
 		}
-
-		// Determine source
-		source := "unknown"
-		if behavior.Provenance.SourceType != "" {
-			source = string(behavior.Provenance.SourceType)
+		t, err := corrections.ParseTimeBound(bound.value)
+		if err != nil {
+			return nil, FloopListOutput{}, fmt.Errorf("invalid %s: %w", bound.name, err)
 		}
+		*bound.dst = t
+	}
+	switch {
+	case args.Processed && args.Unprocessed:
+		return nil, FloopListOutput{}, fmt.Errorf("processed and unprocessed are mutually exclusive")
+	case args.Processed || args.Unprocessed:
+		filter.Processed = &args.Processed
+	}
 
-		behaviors = append(behaviors, BehaviorListItem{
-			ID:         behavior.ID,
-			Name:       behavior.Name,
-			Kind:       string(behavior.Kind),
-			Confidence: behavior.Confidence,
-			Tags:       behavior.Content.Tags,
-			Source:     source,
-			CreatedAt:  behavior.Provenance.CreatedAt,
+	items := []CorrectionListItem{}
+	floopDir := filepath.Join(s.root, ".floop")
+	if _, err := os.Stat(floopDir); os.IsNotExist(err) {
+		return nil, FloopListOutput{Corrections: items}, nil
+	}
+	correctionLog, err := corrections.Open(floopDir)
+	if err != nil {
+		return nil, FloopListOutput{}, fmt.Errorf("failed to open corrections: %w", err)
+	}
+	defer correctionLog.Close()
+	page, next, err := correctionLog.ListCorrections(ctx, filter)
+	if err != nil {
+		return nil, FloopListOutput{}, err
+	}
+	for _, c := range page {
+		items = append(items, CorrectionListItem{
+			ID:              c.ID,
+			Timestamp:       c.Timestamp,
+			AgentAction:     c.AgentAction,
+			CorrectedAction: c.CorrectedAction,
+			Processed:       c.Processed,
 		})
 	}
-
 	return nil, FloopListOutput{
-		Behaviors: behaviors,
-		Count:     len(behaviors),
+		Corrections: items,
+		Count:       len(items),
+		NextCursor:  next,
 	}, nil
 }
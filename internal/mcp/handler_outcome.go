@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/outcome"
+	"github.com/nvandessel/floop/internal/sanitize"
+)
+
+// handleFloopRecordOutcome implements the floop_record_outcome tool.
+func (s *Server) handleFloopRecordOutcome(ctx context.Context, req *sdk.CallToolRequest, args FloopRecordOutcomeInput) (_ *sdk.CallToolResult, _ FloopRecordOutcomeOutput, retErr error) {
+	start := time.Now()
+	defer func() {
+		s.auditTool("floop_record_outcome", start, retErr, sanitizeToolParams("floop_record_outcome", map[string]interface{}{
+			"kind": args.Kind, "behavior_ids": args.BehaviorIDs, "session_id": args.SessionID, "ref": args.Ref, "note": args.Note,
+		}), "local")
+	}()
+
+	if err := s.checkRateLimit(ctx, "floop_record_outcome"); err != nil {
+		return nil, FloopRecordOutcomeOutput{}, err
+	}
+
+	kind, err := outcome.ParseKind(args.Kind)
+	if err != nil {
+		return nil, FloopRecordOutcomeOutput{}, err
+	}
+
+	linked := append(s.sessions.activeIDs(requestSession(req)), args.BehaviorIDs...)
+	event := outcome.NewEvent(kind, sanitize.SanitizeBehaviorContent(args.SessionID), linked,
+		sanitize.SanitizeBehaviorContent(args.Ref), sanitize.SanitizeBehaviorContent(args.Note), time.Now())
+	if len(event.BehaviorIDs) == 0 {
+		return nil, FloopRecordOutcomeOutput{}, fmt.Errorf("no behaviors to link: none were active this session; call floop_active first or pass 'behavior_ids'")
+	}
+	if err := outcome.Record(filepath.Join(s.root, ".floop"), event); err != nil {
+		return nil, FloopRecordOutcomeOutput{}, err
+	}
+
+	return nil, FloopRecordOutcomeOutput{
+		OutcomeID:   event.ID,
+		Kind:        string(event.Kind),
+		Positive:    event.Kind.Positive(),
+		BehaviorIDs: event.BehaviorIDs,
+		Message:     fmt.Sprintf("Recorded %s for %d behavior(s)", event.Kind, len(event.BehaviorIDs)),
+	}, nil
+}
@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/outcome"
+)
+
+func TestHandleFloopRecordOutcome(t *testing.T) {
+	server, tmpDir := setupTestServer(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	if _, _, err := server.handleFloopRecordOutcome(ctx, &sdk.CallToolRequest{}, FloopRecordOutcomeInput{Kind: "tests-passed"}); err == nil {
+		t.Error("expected error with no active or given behaviors")
+	}
+	if _, _, err := server.handleFloopRecordOutcome(ctx, &sdk.CallToolRequest{}, FloopRecordOutcomeInput{Kind: "shipped", BehaviorIDs: []string{"b-1"}}); err == nil {
+		t.Error("expected error for an invalid kind")
+	}
+
+	// Behaviors returned by floop_active this session are linked
+	server.sessions.confirm(nil, []string{"b-active"})
+
+	_, output, err := server.handleFloopRecordOutcome(ctx, &sdk.CallToolRequest{}, FloopRecordOutcomeInput{
+		Kind:        "bug-reopened",
+		BehaviorIDs: []string{"b-extra", "b-active"},
+		Ref:         "https://example.com/issues/7",
+	})
+	if err != nil {
+		t.Fatalf("handleFloopRecordOutcome failed: %v", err)
+	}
+	if output.Positive || len(output.BehaviorIDs) != 2 || output.BehaviorIDs[0] != "b-active" || output.BehaviorIDs[1] != "b-extra" {
+		t.Errorf("output = %+v, want negative outcome linked to b-active and b-extra", output)
+	}
+
+	stats, err := outcome.LoadStats(filepath.Join(tmpDir, ".floop"))
+	if err != nil {
+		t.Fatalf("LoadStats() error = %v", err)
+	}
+	if stats["b-active"].Negative != 1 || stats["b-extra"].Negative != 1 {
+		t.Errorf("stats = %+v, want one bad outcome each", stats)
+	}
+}
@@ -10,7 +10,6 @@ import (
 	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/nvandessel/floop/internal/pack"
 	"github.com/nvandessel/floop/internal/pathutil"
-	"github.com/nvandessel/floop/internal/ratelimit"
 )
 
 // handleFloopPackInstall implements the floop_pack_install tool.
@@ -28,7 +27,7 @@ func (s *Server) handleFloopPackInstall(ctx context.Context, req *sdk.CallToolRe
 		}), "local")
 	}()
 
-	if err := ratelimit.CheckLimit(s.toolLimiters, "floop_pack_install"); err != nil {
+	if err := s.checkRateLimit(ctx, "floop_pack_install"); err != nil {
 		return nil, FloopPackInstallOutput{}, err
 	}
 
@@ -68,8 +67,9 @@ func (s *Server) handleFloopPackInstall(ctx context.Context, req *sdk.CallToolRe
 			return nil, FloopPackInstallOutput{}, fmt.Errorf("pack install failed: %w", err)
 		}
 
-	case pack.SourceHTTP, pack.SourceGitHub:
-		// Remote sources bypass path validation, go through InstallFromSource
+	case pack.SourceHTTP, pack.SourceGitHub, pack.SourceRegistry:
+		// Remote sources, registry names, and starter packs bypass path
+		// validation, go through InstallFromSource
 		results, err := pack.InstallFromSource(ctx, s.store, source, cfg, pack.InstallFromSourceOptions{
 			DeriveEdges: true,
 		})
@@ -114,3 +114,20 @@ func TestHandleFloopPackInstall_LocalFileNotFound(t *testing.T) {
 		t.Errorf("error = %q, want it to contain 'pack install path rejected', 'not found', or 'no such file'", errMsg)
 	}
 }
+
+func TestHandleFloopPackInstall_Starter(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	req := &sdk.CallToolRequest{}
+	args := FloopPackInstallInput{Source: "python"}
+
+	_, output, err := server.handleFloopPackInstall(ctx, req, args)
+	if err != nil {
+		t.Fatalf("handleFloopPackInstall failed: %v", err)
+	}
+	if output.PackID != "floop/python" || len(output.Added) == 0 {
+		t.Errorf("output = %+v, want floop/python behaviors added", output)
+	}
+}
@@ -0,0 +1,163 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Pin lifetimes and the number of pins a server holds at once.
+const (
+	defaultPinTTL = 2 * time.Hour
+	maxPinTTL     = 24 * time.Hour
+	maxPins       = 32
+)
+
+// pinCounter provides unique suffixes for pin handles.
+var pinCounter atomic.Int64
+
+// activePin is an active set frozen by floop_pin_context.
+type activePin struct {
+	output    FloopActiveOutput
+	pinnedAt  time.Time
+	expiresAt time.Time
+}
+
+// info describes the pin for tool output.
+func (p *activePin) info(handle string) *PinInfo {
+	return &PinInfo{
+		Handle:    handle,
+		PinnedAt:  p.pinnedAt.UTC().Format(time.RFC3339),
+		ExpiresAt: p.expiresAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// handleFloopPinContext implements the floop_pin_context tool.
+func (s *Server) handleFloopPinContext(ctx context.Context, req *sdk.CallToolRequest, args FloopPinContextInput) (_ *sdk.CallToolResult, _ FloopPinContextOutput, retErr error) {
+	start := time.Now()
+	defer func() {
+		s.auditTool("floop_pin_context", start, retErr, sanitizeToolParams("floop_pin_context", map[string]interface{}{
+			"file": args.File, "files": args.Files, "task": args.Task, "language": args.Language, "tags": args.Tags,
+			"ttl_seconds": args.TTLSeconds, "release": args.Release,
+		}), "local")
+	}()
+
+	if err := s.checkRateLimit(ctx, "floop_pin_context"); err != nil {
+		return nil, FloopPinContextOutput{}, err
+	}
+
+	if args.Release != "" {
+		if !s.releasePin(args.Release) {
+			return nil, FloopPinContextOutput{}, fmt.Errorf("unknown or expired pin: %s", args.Release)
+		}
+		return nil, FloopPinContextOutput{
+			Released: true,
+			Message:  fmt.Sprintf("Released pin %s", args.Release),
+		}, nil
+	}
+
+	ttl, err := pinTTL(args.TTLSeconds)
+	if err != nil {
+		return nil, FloopPinContextOutput{}, err
+	}
+
+	out, err := s.activeOutput(ctx, req, FloopActiveInput{
+		File:         args.File,
+		Files:        args.Files,
+		Task:         args.Task,
+		Language:     args.Language,
+		Tags:         args.Tags,
+		Kinds:        args.Kinds,
+		ExcludeKinds: args.ExcludeKinds,
+	})
+	if err != nil {
+		return nil, FloopPinContextOutput{}, err
+	}
+
+	handle, pin, err := s.addPin(out, time.Now(), ttl)
+	if err != nil {
+		return nil, FloopPinContextOutput{}, err
+	}
+	info := pin.info(handle)
+	return nil, FloopPinContextOutput{
+		Pin:        info,
+		Context:    out.Context,
+		Active:     out.Active,
+		Count:      out.Count,
+		TokenStats: out.TokenStats,
+		Message: fmt.Sprintf("Pinned %d active behavior(s) until %s; pass pin %q to floop_active",
+			out.Count, info.ExpiresAt, handle),
+	}, nil
+}
+
+// pinTTL validates a requested pin lifetime in seconds. Zero selects the
+// default.
+func pinTTL(seconds int) (time.Duration, error) {
+	if seconds == 0 {
+		return defaultPinTTL, nil
+	}
+	ttl := time.Duration(seconds) * time.Second
+	if seconds < 0 || ttl > maxPinTTL {
+		return 0, fmt.Errorf("ttl_seconds must be between 1 and %d", int(maxPinTTL.Seconds()))
+	}
+	return ttl, nil
+}
+
+// addPin stores out as a new pin expiring ttl after now.
+func (s *Server) addPin(out FloopActiveOutput, now time.Time, ttl time.Duration) (string, *activePin, error) {
+	s.pinMu.Lock()
+	defer s.pinMu.Unlock()
+
+	s.evictExpiredPinsLocked(now)
+	if len(s.pins) >= maxPins {
+		return "", nil, fmt.Errorf("too many pins (max %d); release one with floop_pin_context", maxPins)
+	}
+	handle := fmt.Sprintf("pin-%d-%d", now.UnixNano(), pinCounter.Add(1))
+	pin := &activePin{output: out, pinnedAt: now, expiresAt: now.Add(ttl)}
+	s.pins[handle] = pin
+	return handle, pin, nil
+}
+
+// pinnedActive returns the active set frozen under handle.
+func (s *Server) pinnedActive(handle string, now time.Time) (FloopActiveOutput, error) {
+	s.pinMu.Lock()
+	defer s.pinMu.Unlock()
+
+	pin, ok := s.pins[handle]
+	if !ok {
+		return FloopActiveOutput{}, fmt.Errorf("unknown pin: %s (pins last until released, expired, or the server restarts)", handle)
+	}
+	if !now.Before(pin.expiresAt) {
+		delete(s.pins, handle)
+		return FloopActiveOutput{}, fmt.Errorf("pin %s expired at %s; create a new one with floop_pin_context",
+			handle, pin.expiresAt.UTC().Format(time.RFC3339))
+	}
+	out := pin.output
+	out.Pin = pin.info(handle)
+	return out, nil
+}
+
+// releasePin drops the pin under handle, reporting whether it was held.
+func (s *Server) releasePin(handle string) bool {
+	s.pinMu.Lock()
+	defer s.pinMu.Unlock()
+
+	s.evictExpiredPinsLocked(time.Now())
+	if _, ok := s.pins[handle]; !ok {
+		return false
+	}
+	delete(s.pins, handle)
+	return true
+}
+
+// evictExpiredPinsLocked drops pins expired at now. s.pinMu must be held.
+func (s *Server) evictExpiredPinsLocked(now time.Time) {
+	for handle, pin := range s.pins {
+		if !now.Before(pin.expiresAt) {
+			delete(s.pins, handle)
+		}
+	}
+}
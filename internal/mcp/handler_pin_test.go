@@ -0,0 +1,132 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func activeIDs(summaries []BehaviorSummary) map[string]bool {
+	ids := make(map[string]bool, len(summaries))
+	for _, b := range summaries {
+		ids[b.ID] = true
+	}
+	return ids
+}
+
+func TestHandleFloopPinContext(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	req := &sdk.CallToolRequest{}
+	addGoRule := func(id, canonical string) {
+		t.Helper()
+		node := store.Node{
+			ID:   id,
+			Kind: "behavior",
+			Content: map[string]interface{}{
+				"name":    id,
+				"kind":    "directive",
+				"content": map[string]interface{}{"canonical": canonical},
+				"when":    map[string]interface{}{"language": "go"},
+			},
+			Metadata: map[string]interface{}{"confidence": 0.9},
+		}
+		if _, err := server.store.AddNode(ctx, node); err != nil {
+			t.Fatalf("AddNode(%s) error = %v", id, err)
+		}
+	}
+	addGoRule("go-before", "Wrap errors with context")
+
+	_, pinned, err := server.handleFloopPinContext(ctx, req, FloopPinContextInput{Language: "go"})
+	if err != nil {
+		t.Fatalf("handleFloopPinContext() error = %v", err)
+	}
+	if pinned.Pin == nil || !strings.HasPrefix(pinned.Pin.Handle, "pin-") || !activeIDs(pinned.Active)["go-before"] {
+		t.Fatalf("pin output = %+v", pinned)
+	}
+	handle := pinned.Pin.Handle
+
+	// A behavior learned after pinning appears in a fresh activation only
+	addGoRule("go-after", "Prefer table-driven tests")
+	_, fresh, err := server.handleFloopActive(ctx, req, FloopActiveInput{Language: "go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !activeIDs(fresh.Active)["go-after"] {
+		t.Fatal("fresh activation is missing go-after")
+	}
+	_, frozen, err := server.handleFloopActive(ctx, req, FloopActiveInput{Pin: handle, Language: "python"})
+	if err != nil {
+		t.Fatalf("floop_active with pin error = %v", err)
+	}
+	if frozen.Count != pinned.Count || activeIDs(frozen.Active)["go-after"] || frozen.Pin == nil || frozen.Pin.Handle != handle {
+		t.Errorf("pinned active = %+v, want the %d pinned behaviors", frozen, pinned.Count)
+	}
+
+	// Releasing drops the pin
+	_, released, err := server.handleFloopPinContext(ctx, req, FloopPinContextInput{Release: handle})
+	if err != nil || !released.Released {
+		t.Fatalf("release = %+v, %v", released, err)
+	}
+	if _, _, err := server.handleFloopActive(ctx, req, FloopActiveInput{Pin: handle}); err == nil || !strings.Contains(err.Error(), "unknown pin") {
+		t.Errorf("floop_active with released pin error = %v", err)
+	}
+	if _, _, err := server.handleFloopPinContext(ctx, req, FloopPinContextInput{Release: handle}); err == nil {
+		t.Error("releasing twice should fail")
+	}
+}
+
+func TestPinExpiry(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	now := time.Now()
+	handle, _, err := server.addPin(FloopActiveOutput{Count: 1}, now, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := server.pinnedActive(handle, now.Add(30*time.Second)); err != nil || out.Count != 1 {
+		t.Fatalf("pinnedActive() before expiry = %+v, %v", out, err)
+	}
+	if _, err := server.pinnedActive(handle, now.Add(time.Minute)); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("pinnedActive() after expiry error = %v", err)
+	}
+
+	for i := 0; i < maxPins; i++ {
+		if _, _, err := server.addPin(FloopActiveOutput{}, now, time.Minute); err != nil {
+			t.Fatalf("addPin() %d error = %v", i, err)
+		}
+	}
+	if _, _, err := server.addPin(FloopActiveOutput{}, now, time.Minute); err == nil {
+		t.Error("addPin() beyond maxPins should fail")
+	}
+	// Expired pins make room
+	if _, _, err := server.addPin(FloopActiveOutput{}, now.Add(time.Hour), time.Minute); err != nil {
+		t.Errorf("addPin() after expiry error = %v", err)
+	}
+}
+
+func TestPinTTL(t *testing.T) {
+	tests := []struct {
+		seconds int
+		want    time.Duration
+		wantErr bool
+	}{
+		{0, defaultPinTTL, false},
+		{600, 10 * time.Minute, false},
+		{-1, 0, true},
+		{int(maxPinTTL.Seconds()) + 1, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := pinTTL(tt.seconds)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("pinTTL(%d) = %v, %v; want %v, error %v", tt.seconds, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/promote"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// handleFloopPromote implements the floop_promote tool.
+func (s *Server) handleFloopPromote(ctx context.Context, req *sdk.CallToolRequest, args FloopPromoteInput) (_ *sdk.CallToolResult, _ FloopPromoteOutput, retErr error) {
+	start := time.Now()
+	defer func() {
+		s.auditTool("floop_promote", start, retErr, sanitizeToolParams("floop_promote", map[string]interface{}{
+			"behavior_id": args.BehaviorID, "move": args.Move, "force": args.Force,
+			"suggest": args.Suggest, "min_confirmations": args.MinConfirmations,
+		}), "global")
+	}()
+
+	if err := s.checkRateLimit(ctx, "floop_promote"); err != nil {
+		return nil, FloopPromoteOutput{}, err
+	}
+
+	if args.Suggest == (args.BehaviorID != "") {
+		return nil, FloopPromoteOutput{}, fmt.Errorf("give behavior_id or suggest")
+	}
+	if args.MinConfirmations < 0 {
+		return nil, FloopPromoteOutput{}, fmt.Errorf("min_confirmations must not be negative")
+	}
+	stores, ok := s.store.(promote.Stores)
+	if !ok {
+		return nil, FloopPromoteOutput{}, fmt.Errorf("promotion needs separate local and global stores")
+	}
+
+	if args.Suggest {
+		thresholds := promote.DefaultThresholds()
+		if args.MinConfirmations > 0 {
+			thresholds.MinConfirmations = args.MinConfirmations
+		}
+		suggestions, err := promote.Suggest(ctx, stores, thresholds)
+		if err != nil {
+			return nil, FloopPromoteOutput{}, err
+		}
+		return nil, FloopPromoteOutput{
+			Suggestions: suggestions,
+			Message:     fmt.Sprintf("%d local behavior(s) ready for promotion", len(suggestions)),
+		}, nil
+	}
+
+	// The behavior may be given by name or slug
+	id := args.BehaviorID
+	if node, err := store.ResolveNode(ctx, stores.LocalStore(), id); err == nil && node != nil {
+		id = node.ID
+	}
+
+	result, err := promote.Promote(ctx, stores, id, promote.Options{
+		MinConfirmations: args.MinConfirmations,
+		Force:            args.Force,
+		Move:             args.Move,
+		Actor:            os.Getenv("USER"),
+		Project:          s.projectID,
+		Root:             s.root,
+	})
+	if err != nil {
+		return nil, FloopPromoteOutput{}, err
+	}
+	s.debouncedRefreshPageRank()
+
+	msg := fmt.Sprintf("Promoted '%s' to the global store as %s", result.Name, result.GlobalID)
+	switch {
+	case result.Existing:
+		msg = fmt.Sprintf("The global store already holds '%s' as %s; the local copy defers to it", result.Name, result.GlobalID)
+	case result.Moved:
+		msg = fmt.Sprintf("Moved '%s' to the global store", result.Name)
+	}
+	return nil, FloopPromoteOutput{Promoted: result, Message: msg}, nil
+}
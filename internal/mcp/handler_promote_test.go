@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/promote"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestHandleFloopPromote(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	stores := server.store.(promote.Stores)
+	for id, confirmed := range map[string]int{"wrap-errors": 5, "new-rule": 1} {
+		node := store.Node{
+			ID:   id,
+			Kind: store.NodeKindBehavior,
+			Content: map[string]interface{}{
+				"name":    id,
+				"kind":    "directive",
+				"content": map[string]interface{}{"canonical": "Rule " + id},
+			},
+			Metadata: map[string]interface{}{
+				"confidence": 0.9,
+				"stats":      map[string]interface{}{"times_confirmed": confirmed},
+			},
+		}
+		if _, err := stores.AddNodeToScope(ctx, node, store.ScopeLocal); err != nil {
+			t.Fatalf("AddNodeToScope(%s) error = %v", id, err)
+		}
+	}
+
+	_, out, err := server.handleFloopPromote(ctx, &sdk.CallToolRequest{}, FloopPromoteInput{Suggest: true})
+	if err != nil {
+		t.Fatalf("handleFloopPromote(suggest) error = %v", err)
+	}
+	if len(out.Suggestions) != 1 || out.Suggestions[0].ID != "wrap-errors" {
+		t.Errorf("suggestions = %+v, want wrap-errors", out.Suggestions)
+	}
+
+	if _, _, err := server.handleFloopPromote(ctx, &sdk.CallToolRequest{}, FloopPromoteInput{BehaviorID: "new-rule"}); err == nil {
+		t.Error("expected an error promoting an unconfirmed behavior")
+	}
+
+	_, out, err = server.handleFloopPromote(ctx, &sdk.CallToolRequest{}, FloopPromoteInput{BehaviorID: "wrap-errors", Move: true})
+	if err != nil {
+		t.Fatalf("handleFloopPromote() error = %v", err)
+	}
+	if out.Promoted == nil || !out.Promoted.Moved || out.Promoted.GlobalID != "wrap-errors" {
+		t.Errorf("promoted = %+v", out.Promoted)
+	}
+	if node, _ := server.store.GetNode(ctx, "wrap-errors"); node == nil || node.Origin != store.OriginGlobal {
+		t.Errorf("behavior after move = %+v, want it in the global store", node)
+	}
+}
+
+func TestHandleFloopPromote_Validation(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	for _, args := range []FloopPromoteInput{{}, {BehaviorID: "x", Suggest: true}, {Suggest: true, MinConfirmations: -1}} {
+		if _, _, err := server.handleFloopPromote(context.Background(), &sdk.CallToolRequest{}, args); err == nil {
+			t.Errorf("handleFloopPromote(%+v) expected an error", args)
+		}
+	}
+}
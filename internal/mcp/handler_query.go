@@ -0,0 +1,189 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// handleFloopQuery implements the floop_query tool.
+func (s *Server) handleFloopQuery(ctx context.Context, req *sdk.CallToolRequest, args FloopQueryInput) (_ *sdk.CallToolResult, _ FloopQueryOutput, retErr error) {
+	start := time.Now()
+	defer func() {
+		s.auditTool("floop_query", start, retErr, sanitizeToolParams("floop_query", map[string]interface{}{
+			"kinds": args.Kinds, "tags": args.Tags, "min_confidence": args.MinConfidence,
+			"max_confidence": args.MaxConfidence, "text": args.Text, "related_to": args.RelatedTo,
+			"limit": args.Limit,
+		}), "local")
+	}()
+
+	if err := s.checkRateLimit(ctx, "floop_query"); err != nil {
+		return nil, FloopQueryOutput{}, err
+	}
+	if err := s.flush(ctx, args.Flush); err != nil {
+		return nil, FloopQueryOutput{}, err
+	}
+
+	// Validate the filter
+	validKinds := map[models.BehaviorKind]bool{
+		models.BehaviorKindDirective: true, models.BehaviorKindConstraint: true,
+		models.BehaviorKindProcedure: true, models.BehaviorKindPreference: true,
+		models.BehaviorKindEpisodic: true, models.BehaviorKindWorkflow: true,
+	}
+	kinds := make(map[models.BehaviorKind]bool, len(args.Kinds))
+	for _, k := range args.Kinds {
+		kind := models.BehaviorKind(strings.ToLower(strings.TrimSpace(k)))
+		if !validKinds[kind] {
+			return nil, FloopQueryOutput{}, fmt.Errorf("invalid kind %q: must be directive, constraint, procedure, preference, episodic, or workflow", k)
+		}
+		kinds[kind] = true
+	}
+
+	maxConfidence := args.MaxConfidence
+	if maxConfidence == 0 {
+		maxConfidence = 1.0
+	}
+	if args.MinConfidence < 0 || args.MinConfidence > 1 || maxConfidence < 0 || maxConfidence > 1 {
+		return nil, FloopQueryOutput{}, fmt.Errorf("confidence bounds must be between 0.0 and 1.0")
+	}
+	if args.MinConfidence > maxConfidence {
+		return nil, FloopQueryOutput{}, fmt.Errorf("min_confidence (%.2f) is greater than max_confidence (%.2f)", args.MinConfidence, maxConfidence)
+	}
+
+	limit := args.Limit
+	if limit < 0 {
+		return nil, FloopQueryOutput{}, fmt.Errorf("'limit' must not be negative, got %d", limit)
+	}
+	if limit == 0 {
+		limit = constants.DefaultQueryLimit
+	}
+	if limit > constants.MaxQueryLimit {
+		limit = constants.MaxQueryLimit
+	}
+
+	// Restrict to neighbors of related_to when given
+	var related map[string]bool
+	if args.RelatedTo != "" {
+		node, err := s.store.GetNode(ctx, args.RelatedTo)
+		if err != nil {
+			return nil, FloopQueryOutput{}, fmt.Errorf("failed to look up behavior: %w", err)
+		}
+		if node == nil {
+			return nil, FloopQueryOutput{}, fmt.Errorf("behavior not found: %s", args.RelatedTo)
+		}
+		edges, err := s.store.GetEdges(ctx, args.RelatedTo, store.DirectionBoth, "")
+		if err != nil {
+			return nil, FloopQueryOutput{}, fmt.Errorf("failed to get edges: %w", err)
+		}
+		related = make(map[string]bool, len(edges))
+		for _, e := range edges {
+			related[e.Source] = true
+			related[e.Target] = true
+		}
+		delete(related, args.RelatedTo)
+	}
+
+	tags := make(map[string]bool, len(args.Tags))
+	for _, t := range args.Tags {
+		tags[strings.ToLower(t)] = true
+	}
+	text := strings.ToLower(strings.TrimSpace(args.Text))
+
+	var matches []models.Behavior
+	err := store.WalkNodes(ctx, s.store, map[string]interface{}{"kind": "behavior"}, func(node store.Node) error {
+		if related != nil && !related[node.ID] {
+			return nil
+		}
+		behavior := models.NodeToBehavior(node)
+		if len(kinds) > 0 && !kinds[behavior.Kind] {
+			return nil
+		}
+		if behavior.Confidence < args.MinConfidence || behavior.Confidence > maxConfidence {
+			return nil
+		}
+		if len(tags) > 0 && !hasAnyTag(behavior.Content.Tags, tags) {
+			return nil
+		}
+		if text != "" && !matchesText(behavior, text) {
+			return nil
+		}
+		matches = append(matches, behavior)
+		return nil
+	})
+	if err != nil {
+		return nil, FloopQueryOutput{}, fmt.Errorf("failed to query behaviors: %w", err)
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Confidence != matches[j].Confidence {
+			return matches[i].Confidence > matches[j].Confidence
+		}
+		return matches[i].ID < matches[j].ID
+	})
+
+	total := len(matches)
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	results := make([]QueryResultItem, 0, len(matches))
+	for _, b := range matches {
+		results = append(results, QueryResultItem{
+			ID:         b.ID,
+			Name:       b.Name,
+			Kind:       string(b.Kind),
+			Confidence: b.Confidence,
+			Preview:    queryPreview(b),
+			Tags:       b.Content.Tags,
+		})
+	}
+
+	return nil, FloopQueryOutput{
+		Results: results,
+		Count:   len(results),
+		Total:   total,
+	}, nil
+}
+
+// hasAnyTag reports whether any of tags is in want (lowercased).
+func hasAnyTag(tags []string, want map[string]bool) bool {
+	for _, t := range tags {
+		if want[strings.ToLower(t)] {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesText reports whether the lowercased text occurs in the behavior's
+// name, content, or tags.
+func matchesText(b models.Behavior, text string) bool {
+	fields := []string{b.Name, b.Content.Canonical, b.Content.Summary}
+	fields = append(fields, b.Content.Tags...)
+	for _, f := range fields {
+		if strings.Contains(strings.ToLower(f), text) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryPreview returns the behavior's summary, falling back to its canonical
+// content cut to constants.MaxQueryPreviewLen.
+func queryPreview(b models.Behavior) string {
+	preview := b.Content.Summary
+	if preview == "" {
+		preview = b.Content.Canonical
+	}
+	if runes := []rune(preview); len(runes) > constants.MaxQueryPreviewLen {
+		preview = string(runes[:constants.MaxQueryPreviewLen-3]) + "..."
+	}
+	return preview
+}
@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func seedQueryBehaviors(t *testing.T, s *Server) {
+	t.Helper()
+	ctx := context.Background()
+	behaviors := []models.Behavior{
+		{ID: "q-errors", Name: "wrap-errors", Kind: models.BehaviorKindDirective, Confidence: 0.9,
+			Content: models.BehaviorContent{Canonical: "Wrap errors with fmt.Errorf and %w", Tags: []string{"errors", "go"}}},
+		{ID: "q-panic", Name: "no-panic", Kind: models.BehaviorKindConstraint, Confidence: 0.7,
+			Content: models.BehaviorContent{Canonical: "Never panic in library code; return an error", Tags: []string{"errors"}}},
+		{ID: "q-tests", Name: "table-tests", Kind: models.BehaviorKindPreference, Confidence: 0.5,
+			Content: models.BehaviorContent{Canonical: "Prefer table-driven tests " + strings.Repeat("x", 300), Tags: []string{"testing"}}},
+	}
+	for i := range behaviors {
+		if _, err := s.store.AddNode(ctx, models.BehaviorToNode(&behaviors[i])); err != nil {
+			t.Fatalf("Failed to add behavior %s: %v", behaviors[i].ID, err)
+		}
+	}
+	edge := store.Edge{Source: "q-errors", Target: "q-panic", Kind: store.EdgeKindSimilarTo, Weight: 0.8, CreatedAt: time.Now()}
+	if err := s.store.AddEdge(ctx, edge); err != nil {
+		t.Fatalf("Failed to add edge: %v", err)
+	}
+}
+
+func TestHandleFloopQuery(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	seedQueryBehaviors(t, server)
+
+	tests := []struct {
+		name      string
+		args      FloopQueryInput
+		wantIDs   []string
+		wantTotal int
+	}{
+		{"any tag", FloopQueryInput{Tags: []string{"errors", "testing"}}, []string{"q-errors", "q-panic", "q-tests"}, 3},
+		{"text match", FloopQueryInput{Text: "ERROR"}, []string{"q-errors", "q-panic"}, 2},
+		{"kinds", FloopQueryInput{Kinds: []string{"constraint", "preference"}}, []string{"q-panic", "q-tests"}, 2},
+		{"tags", FloopQueryInput{Tags: []string{"testing"}}, []string{"q-tests"}, 1},
+		{"confidence range", FloopQueryInput{MinConfidence: 0.6, MaxConfidence: 0.8}, []string{"q-panic"}, 1},
+		{"related to", FloopQueryInput{RelatedTo: "q-errors"}, []string{"q-panic"}, 1},
+		{"limit", FloopQueryInput{Tags: []string{"errors", "testing"}, Limit: 1}, []string{"q-errors"}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, output, err := server.handleFloopQuery(context.Background(), &sdk.CallToolRequest{}, tt.args)
+			if err != nil {
+				t.Fatalf("handleFloopQuery failed: %v", err)
+			}
+			if output.Total != tt.wantTotal || output.Count != len(tt.wantIDs) {
+				t.Errorf("Total = %d, Count = %d; want %d, %d", output.Total, output.Count, tt.wantTotal, len(tt.wantIDs))
+			}
+			var got []string
+			for _, r := range output.Results {
+				got = append(got, r.ID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Errorf("Results = %v, want %v", got, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestHandleFloopQuery_Preview(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	seedQueryBehaviors(t, server)
+
+	_, output, err := server.handleFloopQuery(context.Background(), &sdk.CallToolRequest{}, FloopQueryInput{Tags: []string{"testing"}})
+	if err != nil {
+		t.Fatalf("handleFloopQuery failed: %v", err)
+	}
+	if len(output.Results) != 1 {
+		t.Fatalf("len(Results) = %d, want 1", len(output.Results))
+	}
+	preview := output.Results[0].Preview
+	if len([]rune(preview)) > 160 || !strings.HasSuffix(preview, "...") {
+		t.Errorf("Preview = %q, want truncated to 160 characters", preview)
+	}
+}
+
+func TestHandleFloopQuery_InvalidInput(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	seedQueryBehaviors(t, server)
+
+	tests := []struct {
+		name string
+		args FloopQueryInput
+	}{
+		{"unknown kind", FloopQueryInput{Kinds: []string{"merged"}}},
+		{"confidence out of range", FloopQueryInput{MinConfidence: 1.5}},
+		{"min above max", FloopQueryInput{MinConfidence: 0.8, MaxConfidence: 0.2}},
+		{"negative limit", FloopQueryInput{Limit: -1}},
+		{"unknown related_to", FloopQueryInput{RelatedTo: "missing"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := server.handleFloopQuery(context.Background(), &sdk.CallToolRequest{}, tt.args); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
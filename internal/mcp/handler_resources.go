@@ -8,13 +8,24 @@ import (
 	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/nvandessel/floop/internal/activation"
 	"github.com/nvandessel/floop/internal/assembly"
+	"github.com/nvandessel/floop/internal/edges"
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
 	"github.com/nvandessel/floop/internal/tiering"
 )
 
 // handleBehaviorsResource returns active behaviors formatted for context injection.
 // Uses tiered injection to optimize token usage while preserving critical behaviors.
 func (s *Server) handleBehaviorsResource(ctx context.Context, req *sdk.ReadResourceRequest) (*sdk.ReadResourceResult, error) {
+	if result := s.takePrewarmedResource(); result != nil {
+		return result, nil
+	}
+	return s.buildBehaviorsResource(ctx)
+}
+
+// buildBehaviorsResource compiles the floop://behaviors/active resource for
+// the default development context.
+func (s *Server) buildBehaviorsResource(ctx context.Context) (*sdk.ReadResourceResult, error) {
 	// Build context for activation (default task: development)
 	ctxBuilder := activation.NewContextBuilder()
 	ctxBuilder.WithRepoRoot(s.root)
@@ -22,24 +33,23 @@ func (s *Server) handleBehaviorsResource(ctx context.Context, req *sdk.ReadResou
 	actCtx := ctxBuilder.Build()
 
 	// Load all behaviors from store
-	nodes, err := s.store.QueryNodes(ctx, map[string]interface{}{"kind": "behavior"})
+	var behaviors []models.Behavior
+	err := store.WalkNodes(ctx, s.store, map[string]interface{}{"kind": "behavior"}, func(node store.Node) error {
+		behaviors = append(behaviors, models.NodeToBehavior(node))
+		return nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to query behaviors: %w", err)
 	}
-
-	// Convert nodes to behaviors
-	behaviors := make([]models.Behavior, 0, len(nodes))
-	for _, node := range nodes {
-		behavior := models.NodeToBehavior(node)
-		behaviors = append(behaviors, behavior)
+	if err := edges.AttachRelationships(ctx, s.store, behaviors); err != nil {
+		s.logger.Warn("failed to load behavior relationships", "error", err)
 	}
 
 	// Evaluate which behaviors are active
-	evaluator := activation.NewEvaluator()
-	matches := evaluator.Evaluate(actCtx, behaviors)
+	matches := s.evaluator.Evaluate(actCtx, behaviors)
 
 	// Resolve conflicts and get final active set
-	resolver := activation.NewResolver()
+	resolver := activation.NewResolver().WithBehaviors(behaviors)
 	result := resolver.Resolve(matches)
 
 	if len(result.Active) == 0 {
@@ -56,11 +66,20 @@ func (s *Server) handleBehaviorsResource(ctx context.Context, req *sdk.ReadResou
 
 	// Create tiered injection plan via bridge → ActivationTierMapper
 	results, behaviorMap := tiering.BehaviorsToResults(result.Active)
-	mapper := tiering.NewActivationTierMapper(tiering.DefaultActivationTierConfig())
+	results = s.applyExternalScores(ctx, &actCtx, results, behaviorMap)
+	mapper := tiering.NewActivationTierMapper(tiering.DefaultActivationTierConfig()).
+		WithParents(result.Generalized)
 	plan := mapper.MapResults(results, behaviorMap, s.floopConfig.TokenBudget.Default)
 
-	// Compile tiered prompt
-	compiler := assembly.NewCompiler()
+	// Compile tiered prompt using the configured section ordering
+	ordering, err := assembly.ParseOrderStrategy(s.floopConfig.Prompt.Ordering)
+	if err != nil {
+		s.logger.Warn("invalid prompt ordering, using default", "error", err)
+		ordering = assembly.OrderByKind
+	}
+	compiler := assembly.NewCompiler().
+		WithOrdering(ordering).
+		WithTask(actCtx.Task)
 	tieredPrompt := compiler.CompileTiered(plan)
 
 	// Build final output with header
@@ -109,20 +128,17 @@ func (s *Server) handleBehaviorExpandResource(ctx context.Context, req *sdk.Read
 		return nil, fmt.Errorf("behavior ID is required")
 	}
 
-	// Query for the specific behavior
-	nodes, err := s.store.QueryNodes(ctx, map[string]interface{}{
-		"kind": "behavior",
-		"id":   behaviorID,
-	})
+	// Look up the behavior by ID, name, or slug
+	node, err := store.ResolveNode(ctx, s.store, behaviorID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query behavior: %w", err)
 	}
 
-	if len(nodes) == 0 {
+	if node == nil || node.Kind != store.NodeKindBehavior {
 		return nil, fmt.Errorf("behavior not found: %s", behaviorID)
 	}
 
-	behavior := models.NodeToBehavior(nodes[0])
+	behavior := models.NodeToBehavior(*node)
 
 	// Format full behavior details
 	var sb strings.Builder
@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// handleFloopSearch implements the floop_search tool.
+func (s *Server) handleFloopSearch(ctx context.Context, req *sdk.CallToolRequest, args FloopSearchInput) (_ *sdk.CallToolResult, _ FloopSearchOutput, retErr error) {
+	start := time.Now()
+	defer func() {
+		s.auditTool("floop_search", start, retErr, sanitizeToolParams("floop_search", map[string]interface{}{
+			"query": args.Query, "limit": args.Limit,
+		}), "local")
+	}()
+
+	if err := s.checkRateLimit(ctx, "floop_search"); err != nil {
+		return nil, FloopSearchOutput{}, err
+	}
+	if err := s.flush(ctx, args.Flush); err != nil {
+		return nil, FloopSearchOutput{}, err
+	}
+
+	if strings.TrimSpace(args.Query) == "" {
+		return nil, FloopSearchOutput{}, fmt.Errorf("'query' parameter is required")
+	}
+	limit := args.Limit
+	if limit < 0 {
+		return nil, FloopSearchOutput{}, fmt.Errorf("'limit' must not be negative, got %d", limit)
+	}
+	if limit == 0 {
+		limit = constants.DefaultQueryLimit
+	}
+	if limit > constants.MaxQueryLimit {
+		limit = constants.MaxQueryLimit
+	}
+
+	hits, err := store.SearchBehaviors(ctx, s.store, args.Query, limit)
+	if err != nil {
+		return nil, FloopSearchOutput{}, fmt.Errorf("search failed: %w", err)
+	}
+
+	items := make([]SearchHitItem, 0, len(hits))
+	for _, h := range hits {
+		b := models.NodeToBehavior(h.Node)
+		items = append(items, SearchHitItem{
+			ID:      b.ID,
+			Name:    b.Name,
+			Kind:    string(b.Kind),
+			Preview: queryPreview(b),
+			Tags:    b.Content.Tags,
+			Score:   h.Score,
+		})
+	}
+
+	message := fmt.Sprintf("Found %d behaviors matching %q", len(items), args.Query)
+	if len(items) > 0 {
+		message += "; consider floop_feedback or updating an existing behavior instead of learning a new one"
+	}
+
+	return nil, FloopSearchOutput{
+		Results: items,
+		Count:   len(items),
+		Message: message,
+	}, nil
+}
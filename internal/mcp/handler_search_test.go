@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestHandleFloopSearch(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	seedQueryBehaviors(t, server)
+
+	_, output, err := server.handleFloopSearch(context.Background(), &sdk.CallToolRequest{}, FloopSearchInput{
+		Query: "error",
+	})
+	if err != nil {
+		t.Fatalf("handleFloopSearch failed: %v", err)
+	}
+	if output.Count != 2 || output.Count != len(output.Results) {
+		t.Fatalf("Count = %d, len(Results) = %d; want 2", output.Count, len(output.Results))
+	}
+	ids := map[string]bool{}
+	for _, r := range output.Results {
+		ids[r.ID] = true
+	}
+	if !ids["q-errors"] || !ids["q-panic"] {
+		t.Errorf("results = %+v, want q-errors and q-panic", output.Results)
+	}
+	for i := 1; i < len(output.Results); i++ {
+		if output.Results[i].Score > output.Results[i-1].Score {
+			t.Errorf("results not sorted by score: %+v", output.Results)
+		}
+	}
+
+	_, output, err = server.handleFloopSearch(context.Background(), &sdk.CallToolRequest{}, FloopSearchInput{
+		Query: "table tests", Limit: 1,
+	})
+	if err != nil {
+		t.Fatalf("handleFloopSearch failed: %v", err)
+	}
+	if output.Count != 1 || output.Results[0].ID != "q-tests" {
+		t.Errorf("results = %+v, want q-tests", output.Results)
+	}
+}
+
+func TestHandleFloopSearch_InvalidInput(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		args FloopSearchInput
+	}{
+		{"empty query", FloopSearchInput{Query: "  "}},
+		{"negative limit", FloopSearchInput{Query: "x", Limit: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := server.handleFloopSearch(context.Background(), &sdk.CallToolRequest{}, tt.args); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
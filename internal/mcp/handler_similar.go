@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/dedup"
+)
+
+// defaultSimilarMinScore hides matches that share almost no words with the
+// example.
+const defaultSimilarMinScore = 0.1
+
+// handleFloopSimilar implements the floop_similar tool.
+func (s *Server) handleFloopSimilar(ctx context.Context, req *sdk.CallToolRequest, args FloopSimilarInput) (_ *sdk.CallToolResult, _ FloopSimilarOutput, retErr error) {
+	start := time.Now()
+	defer func() {
+		s.auditTool("floop_similar", start, retErr, sanitizeToolParams("floop_similar", map[string]interface{}{
+			"text": args.Text, "min_score": args.MinScore, "limit": args.Limit,
+		}), "local")
+	}()
+
+	if err := s.checkRateLimit(ctx, "floop_similar"); err != nil {
+		return nil, FloopSimilarOutput{}, err
+	}
+	if err := s.flush(ctx, args.Flush); err != nil {
+		return nil, FloopSimilarOutput{}, err
+	}
+
+	if strings.TrimSpace(args.Text) == "" {
+		return nil, FloopSimilarOutput{}, fmt.Errorf("'text' parameter is required")
+	}
+	if args.MinScore < 0 || args.MinScore > 1 {
+		return nil, FloopSimilarOutput{}, fmt.Errorf("'min_score' must be between 0.0 and 1.0")
+	}
+	minScore := args.MinScore
+	if minScore == 0 {
+		minScore = defaultSimilarMinScore
+	}
+
+	limit := args.Limit
+	if limit < 0 {
+		return nil, FloopSimilarOutput{}, fmt.Errorf("'limit' must not be negative, got %d", limit)
+	}
+	if limit == 0 {
+		limit = constants.DefaultQueryLimit
+	}
+	if limit > constants.MaxQueryLimit {
+		limit = constants.MaxQueryLimit
+	}
+
+	matches, err := dedup.FindSimilar(ctx, s.store, args.Text, dedup.SimilarOptions{
+		SimilarityThreshold: constants.DefaultAutoMergeThreshold,
+		EmbeddingThreshold:  constants.DefaultEmbeddingDedupThreshold,
+		LLMClient:           s.llmClient,
+		MinScore:            minScore,
+		Limit:               limit,
+	})
+	if err != nil {
+		return nil, FloopSimilarOutput{}, fmt.Errorf("similarity search failed: %w", err)
+	}
+
+	items := make([]SimilarMatchItem, 0, len(matches))
+	duplicates := 0
+	for _, m := range matches {
+		if m.WouldDuplicate {
+			duplicates++
+		}
+		items = append(items, SimilarMatchItem{
+			ID:             m.Behavior.ID,
+			Name:           m.Behavior.Name,
+			Kind:           string(m.Behavior.Kind),
+			Preview:        queryPreview(*m.Behavior),
+			Score:          m.Score,
+			Method:         m.Method,
+			WouldDuplicate: m.WouldDuplicate,
+		})
+	}
+
+	message := fmt.Sprintf("Found %d similar behaviors", len(items))
+	if duplicates > 0 {
+		message += fmt.Sprintf(", %d would be flagged as duplicates; consider floop_feedback or updating the existing behavior instead of learning a new one", duplicates)
+	}
+
+	return nil, FloopSimilarOutput{
+		Matches: items,
+		Count:   len(items),
+		Message: message,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestHandleFloopSimilar(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	seedQueryBehaviors(t, server)
+
+	_, output, err := server.handleFloopSimilar(context.Background(), &sdk.CallToolRequest{}, FloopSimilarInput{
+		Text: "Wrap errors with fmt.Errorf and %w",
+	})
+	if err != nil {
+		t.Fatalf("handleFloopSimilar failed: %v", err)
+	}
+	if output.Count == 0 || output.Count != len(output.Matches) {
+		t.Fatalf("Count = %d, len(Matches) = %d; want at least one match", output.Count, len(output.Matches))
+	}
+	top := output.Matches[0]
+	if top.ID != "q-errors" || !top.WouldDuplicate || top.Method != "jaccard" {
+		t.Errorf("top match = %+v, want q-errors jaccard duplicate", top)
+	}
+	for i := 1; i < len(output.Matches); i++ {
+		if output.Matches[i].Score > output.Matches[i-1].Score {
+			t.Errorf("matches not sorted by score: %+v", output.Matches)
+		}
+	}
+}
+
+func TestHandleFloopSimilar_InvalidInput(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		args FloopSimilarInput
+	}{
+		{"empty text", FloopSimilarInput{Text: "  "}},
+		{"min score out of range", FloopSimilarInput{Text: "x", MinScore: 1.5}},
+		{"negative limit", FloopSimilarInput{Text: "x", Limit: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := server.handleFloopSimilar(context.Background(), &sdk.CallToolRequest{}, tt.args); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -12,18 +12,54 @@ func (s *Server) registerTools() error {
 		Description: "Get active behaviors for the current context (file, task, environment)",
 	}, s.handleFloopActive)
 
+	// Register floop_pin_context tool
+	sdk.AddTool(s.server, &sdk.Tool{
+		Name:        "floop_pin_context",
+		Description: "Freeze the current active behavior set for the rest of a task and return a handle; floop_active with the handle returns the same set until it is released or expires",
+	}, s.handleFloopPinContext)
+
 	// Register floop_learn tool
 	sdk.AddTool(s.server, &sdk.Tool{
 		Name:        "floop_learn",
 		Description: "Capture a correction and extract a reusable behavior",
 	}, s.handleFloopLearn)
 
+	// Register floop_learn_batch tool
+	sdk.AddTool(s.server, &sdk.Tool{
+		Name:        "floop_learn_batch",
+		Description: "Learn many corrections in one call, e.g. imported from a transcript, with per-item accept/review/merge status",
+	}, s.handleFloopLearnBatch)
+
+	// Register floop_report_failure tool
+	sdk.AddTool(s.server, &sdk.Tool{
+		Name:        "floop_report_failure",
+		Description: "Record a failure the agent detected in its own work (tests failed, build broke) and what fixed it, queued for review as a learning source",
+	}, s.handleFloopReportFailure)
+
+	// Register floop_record_outcome tool
+	sdk.AddTool(s.server, &sdk.Tool{
+		Name:        "floop_record_outcome",
+		Description: "Record a downstream result (tests passed, PR merged, bug reopened) for the behaviors active during this session",
+	}, s.handleFloopRecordOutcome)
+
+	// Register floop_session_info tool
+	sdk.AddTool(s.server, &sdk.Tool{
+		Name:        "floop_session_info",
+		Description: "Report per-session stats for the calling client (tool calls, floop_active calls, confirmed behaviors), or for every client sharing this server",
+	}, s.handleFloopSessionInfo)
+
 	// Register floop_list tool
 	sdk.AddTool(s.server, &sdk.Tool{
 		Name:        "floop_list",
 		Description: "List all behaviors or corrections",
 	}, s.handleFloopList)
 
+	// Register floop_query tool
+	sdk.AddTool(s.server, &sdk.Tool{
+		Name:        "floop_query",
+		Description: "Look up behaviors matching a filter (kinds, tags, confidence range, text, related behavior) and return compact results",
+	}, s.handleFloopQuery)
+
 	// Register floop_deduplicate tool
 	sdk.AddTool(s.server, &sdk.Tool{
 		Name:        "floop_deduplicate",
@@ -66,6 +102,12 @@ func (s *Server) registerTools() error {
 		Description: "Provide explicit feedback on a behavior: confirmed (helpful) or overridden (contradicted)",
 	}, s.handleFloopFeedback)
 
+	// Register floop_feedback_batch tool
+	sdk.AddTool(s.server, &sdk.Tool{
+		Name:        "floop_feedback_batch",
+		Description: "Provide feedback on many behaviors in one call, e.g. to reconcile every behavior used at the end of a session",
+	}, s.handleFloopFeedbackBatch)
+
 	// Register floop_pack_install tool
 	sdk.AddTool(s.server, &sdk.Tool{
 		Name:        "floop_pack_install",
@@ -84,6 +126,30 @@ func (s *Server) registerTools() error {
 		Description: "Run the memory consolidation pipeline to extract behavioral memories from recorded events",
 	}, s.handleFloopConsolidate)
 
+	// Register floop_similar tool
+	sdk.AddTool(s.server, &sdk.Tool{
+		Name:        "floop_similar",
+		Description: "Rank existing behaviors by similarity to example text; call before floop_learn to avoid creating near-duplicates",
+	}, s.handleFloopSimilar)
+
+	// Register floop_search tool
+	sdk.AddTool(s.server, &sdk.Tool{
+		Name:        "floop_search",
+		Description: "Find behaviors by keyword in their names, content, and tags; call before floop_learn to see what is already known about a topic",
+	}, s.handleFloopSearch)
+
+	// Register floop_explain tool
+	sdk.AddTool(s.server, &sdk.Tool{
+		Name:        "floop_explain",
+		Description: "Trace spreading activation for a context and show how activation reached a behavior: the edges it crossed from a seed, with weight, decay, and energy per hop. Use it to find out why a behavior whose conditions don't match appeared in floop_active",
+	}, s.handleFloopExplain)
+
+	// Register floop_promote tool
+	sdk.AddTool(s.server, &sdk.Tool{
+		Name:        "floop_promote",
+		Description: "Promote a local behavior confirmed often enough into the global store so every project gets it; the local copy defers to the global one. With suggest, list the local behaviors ready for promotion",
+	}, s.handleFloopPromote)
+
 	return nil
 }
 
@@ -102,7 +168,7 @@ func (s *Server) registerResources() error {
 	s.server.AddResourceTemplate(&sdk.ResourceTemplate{
 		URITemplate: "floop://behaviors/expand/{id}",
 		Name:        "floop-behavior-expand",
-		Description: "Get full details for a specific behavior. Use this when you need the complete content of a summarized behavior.",
+		Description: "Get full details for a specific behavior, given by ID, name, or slug. Use this when you need the complete content of a summarized behavior.",
 		MIMEType:    "text/markdown",
 	}, s.handleBehaviorExpandResource)
 
@@ -120,6 +120,7 @@ func (s *Server) applyHebbianUpdates(
 	}
 
 	var weightUpdates []store.EdgeWeightUpdate
+	var newEdges []store.Edge
 	changed := false
 
 	for _, pair := range pairs {
@@ -152,18 +153,28 @@ func (s *Server) applyHebbianUpdates(
 			if s.coActivationTracker.record(pair, cfg) {
 				// Gate met — create new edge with initial weight from Oja
 				initialWeight := spreading.OjaUpdate(0.1, pair.ActivationA, pair.ActivationB, cfg)
-				err := s.store.AddEdge(ctx, store.Edge{
+				newEdges = append(newEdges, store.Edge{
 					Source:    pair.BehaviorA,
 					Target:    pair.BehaviorB,
 					Kind:      store.EdgeKindCoActivated,
 					Weight:    initialWeight,
 					CreatedAt: time.Now(),
 				})
-				if err != nil {
-					s.logger.Warn("hebbian: create edge failed", "source", pair.BehaviorA, "target", pair.BehaviorB, "error", err)
-				} else {
-					changed = true
-				}
+			}
+		}
+	}
+
+	// Create the edges whose gate was met in one batch
+	if len(newEdges) > 0 {
+		rejected, err := store.BatchAddEdges(ctx, s.store, newEdges)
+		if err != nil {
+			s.logger.Warn("hebbian: create edges failed", "count", len(newEdges), "error", err)
+		} else {
+			for _, r := range rejected {
+				s.logger.Warn("hebbian: create edge failed", "source", r.Edge.Source, "target", r.Edge.Target, "error", r.Err)
+			}
+			if len(rejected) < len(newEdges) {
+				changed = true
 			}
 		}
 	}
@@ -176,9 +176,7 @@ func TestIntegration_LearnAndRetrieve(t *testing.T) {
 	})
 
 	// Step 3: List corrections
-	// Note: floop_learn saves corrections to .floop/corrections.jsonl but
-	// floop_list queries the node store for corrections, so learned corrections
-	// won't appear here unless explicitly added to the node store.
+	// floop_learn records corrections in the corrections log floop_list reads
 	t.Run("Step3_ListCorrections", func(t *testing.T) {
 		listInput := FloopListInput{Corrections: true}
 
@@ -187,8 +185,7 @@ func TestIntegration_LearnAndRetrieve(t *testing.T) {
 			t.Fatalf("floop_list failed: %v", err)
 		}
 
-		// Corrections are saved to a file, not the node store, so count may be 0
-		t.Logf("Found %d corrections in node store", output.Count)
+		t.Logf("Found %d corrections", output.Count)
 	})
 
 	// Step 4: Get active behaviors for Go context
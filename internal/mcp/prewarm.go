@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"context"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/activation"
+)
+
+// prewarmed holds results computed in the background when a client
+// initializes, so the session's first default-context floop_active call and
+// first read of floop://behaviors/active skip the cold-start cost of loading,
+// converting, and spreading over every behavior. Each result is used at most
+// once, and only if the graph has not changed since it was computed.
+type prewarmed struct {
+	generation uint64
+	agent      string
+	active     *activationState
+	resource   *sdk.ReadResourceResult
+}
+
+// prewarm computes the default-context activation for agent and the compiled
+// behaviors resource, and stores them for the next matching request.
+func (s *Server) prewarm(ctx context.Context, agent string) {
+	generation := s.graphGeneration.Load()
+
+	ctxBuilder := activation.NewContextBuilder()
+	ctxBuilder.WithRepoRoot(s.root)
+	if agent != "" {
+		ctxBuilder.WithAgent(agent)
+	}
+	state, err := s.computeActivation(ctx, ctxBuilder.Build())
+	if err != nil {
+		s.logger.Warn("activation prewarm failed", "error", err)
+		state = nil
+	}
+
+	resource, err := s.buildBehaviorsResource(ctx)
+	if err != nil {
+		s.logger.Warn("resource prewarm failed", "error", err)
+		resource = nil
+	}
+
+	s.prewarmMu.Lock()
+	s.prewarmed = prewarmed{
+		generation: generation,
+		agent:      agent,
+		active:     state,
+		resource:   resource,
+	}
+	s.prewarmMu.Unlock()
+}
+
+// takePrewarmedActivation returns and clears the pre-computed default-context
+// activation for agent, or nil if there is none or it is stale.
+func (s *Server) takePrewarmedActivation(agent string) *activationState {
+	s.prewarmMu.Lock()
+	defer s.prewarmMu.Unlock()
+
+	state := s.prewarmed.active
+	s.prewarmed.active = nil
+	if state == nil || s.prewarmed.agent != agent || s.prewarmed.generation != s.graphGeneration.Load() {
+		return nil
+	}
+	return state
+}
+
+// takePrewarmedResource returns and clears the pre-compiled behaviors
+// resource, or nil if there is none or it is stale.
+func (s *Server) takePrewarmedResource() *sdk.ReadResourceResult {
+	s.prewarmMu.Lock()
+	defer s.prewarmMu.Unlock()
+
+	resource := s.prewarmed.resource
+	s.prewarmed.resource = nil
+	if resource == nil || s.prewarmed.generation != s.graphGeneration.Load() {
+		return nil
+	}
+	return resource
+}
+
+// invalidatePrewarm marks any pre-computed results stale. It is called
+// whenever the server changes the graph.
+func (s *Server) invalidatePrewarm() {
+	s.graphGeneration.Add(1)
+}
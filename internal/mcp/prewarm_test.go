@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestPrewarm_ServesFirstDefaultCall(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	addTestBehavior(t, server, "warm-1")
+
+	ctx := context.Background()
+	_, cold, err := server.handleFloopActive(ctx, &sdk.CallToolRequest{}, FloopActiveInput{})
+	if err != nil {
+		t.Fatalf("handleFloopActive (cold) failed: %v", err)
+	}
+
+	server.prewarm(ctx, "")
+	server.prewarmMu.Lock()
+	if server.prewarmed.active == nil || server.prewarmed.resource == nil {
+		server.prewarmMu.Unlock()
+		t.Fatal("prewarm should store an activation and a resource")
+	}
+	server.prewarmMu.Unlock()
+
+	_, warm, err := server.handleFloopActive(ctx, &sdk.CallToolRequest{}, FloopActiveInput{})
+	if err != nil {
+		t.Fatalf("handleFloopActive (warm) failed: %v", err)
+	}
+	if warm.Count != cold.Count {
+		t.Errorf("warm Count = %d, want %d as without prewarming", warm.Count, cold.Count)
+	}
+
+	server.prewarmMu.Lock()
+	defer server.prewarmMu.Unlock()
+	if server.prewarmed.active != nil {
+		t.Error("prewarmed activation should be used only once")
+	}
+}
+
+func TestPrewarm_Resource(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	addTestBehavior(t, server, "warm-1")
+
+	ctx := context.Background()
+	want, err := server.buildBehaviorsResource(ctx)
+	if err != nil {
+		t.Fatalf("buildBehaviorsResource failed: %v", err)
+	}
+
+	server.prewarm(ctx, "")
+	got, err := server.handleBehaviorsResource(ctx, &sdk.ReadResourceRequest{})
+	if err != nil {
+		t.Fatalf("handleBehaviorsResource failed: %v", err)
+	}
+	if got.Contents[0].Text != want.Contents[0].Text {
+		t.Errorf("prewarmed resource text differs:\n%s\nwant:\n%s", got.Contents[0].Text, want.Contents[0].Text)
+	}
+	if server.takePrewarmedResource() != nil {
+		t.Error("prewarmed resource should be used only once")
+	}
+}
+
+func TestPrewarm_Stale(t *testing.T) {
+	tests := []struct {
+		name   string
+		agent  string
+		change func(s *Server)
+	}{
+		{"graph changed", "", func(s *Server) { s.invalidatePrewarm() }},
+		{"feedback recorded", "", func(s *Server) {
+			if _, _, err := s.handleFloopFeedback(context.Background(), &sdk.CallToolRequest{}, FloopFeedbackInput{BehaviorID: "warm-1", Signal: "confirmed"}); err != nil {
+				t.Fatalf("handleFloopFeedback failed: %v", err)
+			}
+		}},
+		{"other agent", "cursor", func(s *Server) {}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := setupTestServer(t)
+			defer server.Close()
+			addTestBehavior(t, server, "warm-1")
+
+			server.prewarm(context.Background(), tt.agent)
+			tt.change(server)
+
+			if server.takePrewarmedActivation("") != nil {
+				t.Error("stale activation should not be used")
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/ratelimit"
+)
+
+type rateLimitClientKey struct{}
+
+// withRateLimitClient returns a context whose tool calls are rate limited
+// as those of client.
+func withRateLimitClient(ctx context.Context, client string) context.Context {
+	return context.WithValue(ctx, rateLimitClientKey{}, client)
+}
+
+// rateLimitClient returns the client a tool call is rate limited as: its
+// session ID, or "" for calls that did not come through the session
+// middleware.
+func rateLimitClient(ctx context.Context) string {
+	client, _ := ctx.Value(rateLimitClientKey{}).(string)
+	return client
+}
+
+// checkRateLimit applies tool's rate limit for the calling client. A call
+// whose next token is within rate_limit.max_wait waits for it instead of
+// failing, so an agent doesn't spend a turn retrying a sub-second limit.
+func (s *Server) checkRateLimit(ctx context.Context, tool string) error {
+	var maxWait time.Duration
+	if s.floopConfig != nil {
+		maxWait = s.floopConfig.RateLimit.MaxWait
+	}
+	err := s.toolLimiters.Wait(ctx, tool, rateLimitClient(ctx), maxWait)
+	var limitErr *ratelimit.LimitError
+	if errors.As(err, &limitErr) && s.metrics != nil {
+		s.metrics.ObserveRateLimited(tool)
+	}
+	return err
+}
+
+// rateLimitMiddleware attaches RateLimitErrorData as the structured content
+// of tool results that failed with a *ratelimit.LimitError,
+// CircuitOpenErrorData to those rejected by the learning circuit breaker,
+// and DeadlineErrorData to those abandoned at their deadline.
+// The error text stays in the content for clients that only read text.
+func rateLimitMiddleware(next sdk.MethodHandler) sdk.MethodHandler {
+	return func(ctx context.Context, method string, req sdk.Request) (sdk.Result, error) {
+		res, err := next(ctx, method, req)
+		if err != nil || method != "tools/call" {
+			return res, err
+		}
+		result, ok := res.(*sdk.CallToolResult)
+		if !ok || !result.IsError {
+			return res, err
+		}
+		var limitErr *ratelimit.LimitError
+		var breakerErr *learning.BreakerError
+		var deadlineErr *DeadlineError
+		switch {
+		case errors.As(result.GetError(), &limitErr):
+			result.StructuredContent = rateLimitErrorData(limitErr)
+		case errors.As(result.GetError(), &breakerErr):
+			result.StructuredContent = circuitOpenErrorData(breakerErr)
+		case errors.As(result.GetError(), &deadlineErr):
+			result.StructuredContent = deadlineErrorData(deadlineErr)
+		}
+		return res, err
+	}
+}
+
+// rateLimitErrorData converts a LimitError to its wire form.
+func rateLimitErrorData(e *ratelimit.LimitError) RateLimitErrorData {
+	return RateLimitErrorData{
+		Error:             "rate_limited",
+		Tool:              e.Tool,
+		Rule:              e.Rule,
+		RetryAfterSeconds: e.RetryAfterSeconds(),
+		Global:            e.Global,
+	}
+}
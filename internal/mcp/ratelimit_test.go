@@ -0,0 +1,132 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/ratelimit"
+)
+
+func TestRateLimitedCall_StructuredError(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	server.toolLimiters = ratelimit.Limits{Client: ratelimit.ToolLimiters{"floop_list": ratelimit.NewLimiter(1.0/60.0, 1)}}
+
+	ctx := context.Background()
+	serverTransport, clientTransport := sdk.NewInMemoryTransports()
+	if _, err := server.server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("server Connect() error = %v", err)
+	}
+	client := sdk.NewClient(&sdk.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client Connect() error = %v", err)
+	}
+	defer session.Close()
+
+	params := &sdk.CallToolParams{Name: "floop_list", Arguments: map[string]interface{}{}}
+	if result, err := session.CallTool(ctx, params); err != nil || result.IsError {
+		t.Fatalf("first CallTool() = %+v, %v; want success", result, err)
+	}
+
+	result, err := session.CallTool(ctx, params)
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("second call should be rate limited")
+	}
+	if text, ok := result.Content[0].(*sdk.TextContent); !ok || !strings.Contains(text.Text, "rate limit exceeded for floop_list") {
+		t.Errorf("error content = %+v, want the rate limit message", result.Content)
+	}
+
+	data, ok := result.StructuredContent.(map[string]any)
+	if !ok {
+		t.Fatalf("StructuredContent = %#v, want an object", result.StructuredContent)
+	}
+	if data["error"] != "rate_limited" || data["tool"] != "floop_list" || data["rule"] != "1/minute, burst 1" {
+		t.Errorf("StructuredContent = %v", data)
+	}
+	if retry, _ := data["retry_after_seconds"].(float64); retry < 59 || retry > 60 {
+		t.Errorf("retry_after_seconds = %v, want about 60", data["retry_after_seconds"])
+	}
+
+	snap := server.metrics.Snapshot()
+	if len(snap.Tools) != 1 || snap.Tools[0].Tool != "floop_list" {
+		t.Fatalf("metrics tools = %+v, want floop_list", snap.Tools)
+	}
+	if got := snap.Tools[0]; got.Calls != 2 || got.Errors != 1 || got.RateLimited != 1 {
+		t.Errorf("floop_list metrics = %d calls, %d errors, %d rate limited; want 2, 1, 1", got.Calls, got.Errors, got.RateLimited)
+	}
+}
+
+func TestCheckRateLimit_WaitsForSubSecondLimit(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	server.toolLimiters = ratelimit.Limits{Client: ratelimit.ToolLimiters{"floop_list": ratelimit.NewLimiter(20.0, 1)}}
+	ctx := context.Background()
+
+	server.floopConfig.RateLimit.MaxWait = 500 * time.Millisecond
+	server.checkRateLimit(ctx, "floop_list")
+	if err := server.checkRateLimit(ctx, "floop_list"); err != nil {
+		t.Errorf("checkRateLimit() within max_wait error = %v", err)
+	}
+
+	server.floopConfig.RateLimit.MaxWait = 0
+	if err := server.checkRateLimit(ctx, "floop_list"); err == nil {
+		t.Error("checkRateLimit() with max_wait 0 should fail immediately")
+	}
+}
+
+func TestRateLimit_PerClientSession(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	server.toolLimiters = ratelimit.Limits{
+		Client: ratelimit.ToolLimiters{"floop_list": ratelimit.NewLimiter(1.0/60.0, 1)},
+		Global: ratelimit.ToolLimiters{"floop_list": ratelimit.NewLimiter(1.0/60.0, 3)},
+	}
+
+	ctx := context.Background()
+	connect := func(name string) *sdk.ClientSession {
+		serverTransport, clientTransport := sdk.NewInMemoryTransports()
+		if _, err := server.server.Connect(ctx, serverTransport, nil); err != nil {
+			t.Fatalf("server Connect() error = %v", err)
+		}
+		client := sdk.NewClient(&sdk.Implementation{Name: name, Version: "1.0.0"}, nil)
+		session, err := client.Connect(ctx, clientTransport, nil)
+		if err != nil {
+			t.Fatalf("client Connect() error = %v", err)
+		}
+		t.Cleanup(func() { session.Close() })
+		return session
+	}
+	chatty, quiet := connect("chatty"), connect("quiet")
+
+	params := &sdk.CallToolParams{Name: "floop_list", Arguments: map[string]interface{}{}}
+	if result, err := chatty.CallTool(ctx, params); err != nil || result.IsError {
+		t.Fatalf("chatty first call = %+v, %v; want success", result, err)
+	}
+	if result, err := chatty.CallTool(ctx, params); err != nil || !result.IsError {
+		t.Fatalf("chatty second call = %+v, %v; want rate limited", result, err)
+	}
+	if result, err := quiet.CallTool(ctx, params); err != nil || result.IsError {
+		t.Fatalf("quiet first call = %+v, %v; want success despite chatty being throttled", result, err)
+	}
+
+	info, err := quiet.CallTool(ctx, &sdk.CallToolParams{Name: "floop_session_info", Arguments: map[string]interface{}{"rate_limits": true}})
+	if err != nil || info.IsError {
+		t.Fatalf("floop_session_info = %+v, %v", info, err)
+	}
+	data, _ := info.StructuredContent.(map[string]any)
+	states, _ := data["rate_limits"].([]any)
+	if len(states) != 1 {
+		t.Fatalf("rate_limits = %v, want the floop_list limiter", data["rate_limits"])
+	}
+	state, _ := states[0].(map[string]any)
+	if state["tool"] != "floop_list" || state["tokens"].(float64) > 0.1 || state["global_tokens"].(float64) > 1.1 {
+		t.Errorf("floop_list state = %v, want quiet's token spent and one global token left", state)
+	}
+}
@@ -3,13 +3,24 @@ package mcp
 
 import (
 	"time"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/promote"
+	"github.com/nvandessel/floop/internal/quota"
+	"github.com/nvandessel/floop/internal/ratelimit"
+	"github.com/nvandessel/floop/internal/spreading"
 )
 
 // FloopActiveInput defines the input for floop_active tool.
 type FloopActiveInput struct {
-	File     string `json:"file,omitempty" jsonschema:"Current file path (relative to project root)"`
-	Task     string `json:"task,omitempty" jsonschema:"Current task type (e.g. 'development', 'testing', 'refactoring')"`
-	Language string `json:"language,omitempty" jsonschema:"Programming language (e.g. 'go', 'python'). Overrides file extension inference"`
+	File         string   `json:"file,omitempty" jsonschema:"Current file path (relative to project root)"`
+	Files        []string `json:"files,omitempty" jsonschema:"Files in the current changeset (relative to project root). Active behaviors are the union across files, each annotated with the files that triggered it"`
+	Task         string   `json:"task,omitempty" jsonschema:"Current task type (e.g. 'development', 'testing', 'refactoring')"`
+	Language     string   `json:"language,omitempty" jsonschema:"Programming language (e.g. 'go', 'python'). Overrides file extension inference"`
+	Tags         []string `json:"tags,omitempty" jsonschema:"Tags describing the current work (e.g. ['testing/unit']). Activates behaviors whose 'tags' when-condition names one of them or a parent such as 'testing'"`
+	Kinds        []string `json:"kinds,omitempty" jsonschema:"Only return these behavior kinds (e.g. ['constraint'] for read-only review). Default: all kinds"`
+	ExcludeKinds []string `json:"exclude_kinds,omitempty" jsonschema:"Behavior kinds to leave out (e.g. ['episodic'])"`
+	Pin          string   `json:"pin,omitempty" jsonschema:"Handle from floop_pin_context. Returns the pinned active set instead of recomputing it; the other arguments are ignored"`
 }
 
 // TokenStats provides token budget awareness for active behaviors.
@@ -29,6 +40,39 @@ type FloopActiveOutput struct {
 	Active     []BehaviorSummary      `json:"active" jsonschema:"List of active behaviors"`
 	Count      int                    `json:"count" jsonschema:"Number of active behaviors"`
 	TokenStats *TokenStats            `json:"token_stats,omitempty"`
+	Pin        *PinInfo               `json:"pin,omitempty" jsonschema:"Set when the active set was returned from a pin"`
+	Partial    bool                   `json:"partial,omitempty" jsonschema:"True when spreading activation ran out of time and only directly matching behaviors were returned"`
+}
+
+// PinInfo identifies a pinned active set.
+type PinInfo struct {
+	Handle    string `json:"handle"`
+	PinnedAt  string `json:"pinned_at"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// FloopPinContextInput defines the input for floop_pin_context tool.
+type FloopPinContextInput struct {
+	File         string   `json:"file,omitempty" jsonschema:"Current file path (relative to project root)"`
+	Files        []string `json:"files,omitempty" jsonschema:"Files in the current changeset (relative to project root)"`
+	Task         string   `json:"task,omitempty" jsonschema:"Current task type (e.g. 'development', 'testing', 'refactoring')"`
+	Language     string   `json:"language,omitempty" jsonschema:"Programming language (e.g. 'go', 'python'). Overrides file extension inference"`
+	Tags         []string `json:"tags,omitempty" jsonschema:"Tags describing the current work (e.g. ['testing/unit'])"`
+	Kinds        []string `json:"kinds,omitempty" jsonschema:"Only pin these behavior kinds. Default: all kinds"`
+	ExcludeKinds []string `json:"exclude_kinds,omitempty" jsonschema:"Behavior kinds to leave out"`
+	TTLSeconds   int      `json:"ttl_seconds,omitempty" jsonschema:"How long the pin lasts, in seconds (default 7200, max 86400)"`
+	Release      string   `json:"release,omitempty" jsonschema:"Handle of a pin to release instead of creating one"`
+}
+
+// FloopPinContextOutput defines the output for floop_pin_context tool.
+type FloopPinContextOutput struct {
+	Pin        *PinInfo               `json:"pin,omitempty" jsonschema:"The new pin; pass pin.handle to floop_active"`
+	Released   bool                   `json:"released,omitempty" jsonschema:"True when a pin was released"`
+	Context    map[string]interface{} `json:"context,omitempty" jsonschema:"Context used for activation"`
+	Active     []BehaviorSummary      `json:"active,omitempty" jsonschema:"The pinned active behaviors"`
+	Count      int                    `json:"count" jsonschema:"Number of pinned behaviors"`
+	TokenStats *TokenStats            `json:"token_stats,omitempty"`
+	Message    string                 `json:"message"`
 }
 
 // BehaviorSummary provides a simplified view of a behavior.
@@ -41,9 +85,13 @@ type BehaviorSummary struct {
 	Confidence float64                `json:"confidence"`
 	When       map[string]interface{} `json:"when,omitempty"`
 	Tags       []string               `json:"tags,omitempty"`
+	Origin     string                 `json:"origin,omitempty"` // Store the behavior came from: local, global, scope:<name>, or policy
 	Activation float64                `json:"activation,omitempty"`
 	Distance   int                    `json:"distance,omitempty"`
 	SeedSource string                 `json:"seed_source,omitempty"`
+	// TriggeredBy lists the changeset files that activated the behavior.
+	// Set only when floop_active is called with files.
+	TriggeredBy []string `json:"triggered_by,omitempty"`
 }
 
 // FloopLearnInput defines the input for floop_learn tool.
@@ -55,26 +103,148 @@ type FloopLearnInput struct {
 	Language  string   `json:"language,omitempty" jsonschema:"Programming language (e.g. 'go', 'python'). Overrides file extension inference"`
 	AutoMerge bool     `json:"auto_merge,omitempty" jsonschema:"Enable automatic merging of duplicate behaviors (default: false)"`
 	Tags      []string `json:"tags,omitempty" jsonschema:"Additional tags to apply to the behavior, merged with inferred tags (max 5)"`
+	When      []string `json:"when,omitempty" jsonschema:"Extra conditions as key=value. Temporal keys are evaluated against the activation time: weekday=friday, date=2026-05-15..2026-06-01, after=2026-06-01, before=2026-06-01. Other keys take a string or a JSON operator: language={\"not\":\"go\"}, file_path={\"glob\":\"**/*_test.go\"}, branch={\"regex\":\"^release/\"}, any=[{\"language\":\"go\"},{\"task\":\"testing\"}]"`
+	ExpiresAt string   `json:"expires_at,omitempty" jsonschema:"Time-box the behavior: RFC3339 time, date (YYYY-MM-DD), or duration (72h, 14d). It stops activating after this and is then deprecated"`
 }
 
 // FloopLearnOutput defines the output for floop_learn tool.
 type FloopLearnOutput struct {
-	CorrectionID    string   `json:"correction_id" jsonschema:"ID of the captured correction"`
-	BehaviorID      string   `json:"behavior_id" jsonschema:"ID of the extracted behavior"`
-	Scope           string   `json:"scope" jsonschema:"Where the behavior was stored: 'local' (project-specific) or 'global' (universal)"`
-	AutoAccepted    bool     `json:"auto_accepted" jsonschema:"Whether behavior was automatically accepted"`
-	Confidence      float64  `json:"confidence" jsonschema:"Placement confidence (0.0-1.0)"`
-	RequiresReview  bool     `json:"requires_review" jsonschema:"Whether behavior requires manual review"`
-	ReviewReasons   []string `json:"review_reasons,omitempty" jsonschema:"Reasons why review is needed"`
-	MergedIntoID    string   `json:"merged_into_id,omitempty" jsonschema:"ID of behavior this was merged into (if auto-merged)"`
+	CorrectionID    string            `json:"correction_id" jsonschema:"ID of the captured correction"`
+	BehaviorID      string            `json:"behavior_id" jsonschema:"ID of the extracted behavior"`
+	Scope           string            `json:"scope" jsonschema:"Where the behavior was stored: 'local' (project-specific) or 'global' (universal)"`
+	AutoAccepted    bool              `json:"auto_accepted" jsonschema:"Whether behavior was automatically accepted"`
+	Confidence      float64           `json:"confidence" jsonschema:"Placement confidence (0.0-1.0)"`
+	RequiresReview  bool              `json:"requires_review" jsonschema:"Whether behavior requires manual review"`
+	ReviewReasons   []string          `json:"review_reasons,omitempty" jsonschema:"Reasons why review is needed"`
+	MergedIntoID    string            `json:"merged_into_id,omitempty" jsonschema:"ID of behavior this was merged into (if auto-merged)"`
+	MergeSimilarity float64           `json:"merge_similarity,omitempty" jsonschema:"Similarity score with merged behavior (0.0-1.0)"`
+	Held            bool              `json:"held,omitempty" jsonschema:"Whether the correction was held by the quality gate instead of extracted"`
+	QualityScore    float64           `json:"quality_score,omitempty" jsonschema:"Correction quality score (0.0-1.0), when the quality gate is enabled"`
+	QualityReasons  []string          `json:"quality_reasons,omitempty" jsonschema:"Weak signals that lowered the quality score"`
+	LimitReached    string            `json:"limit_reached,omitempty" jsonschema:"Storage limit that stopped the behavior from being added (e.g. max_behaviors_per_scope)"`
+	Consolidation   []quota.Candidate `json:"consolidation,omitempty" jsonschema:"Merge or forget candidates that would make room when a storage limit is reached"`
+	RestoredIDs     []string          `json:"restored_ids,omitempty" jsonschema:"Retired behaviors restored because this correction recurred on their topic"`
+	Quarantined     bool              `json:"quarantined,omitempty" jsonschema:"Whether the behavior was quarantined as a possible prompt injection instead of activated"`
+	Policy          *LearnPolicy      `json:"policy,omitempty" jsonschema:"The learning policy the behavior was decided under"`
+	Message         string            `json:"message" jsonschema:"Human-readable result message"`
+}
+
+// FloopLearnBatchInput defines the input for floop_learn_batch tool.
+type FloopLearnBatchInput struct {
+	Corrections []FloopLearnInput `json:"corrections" jsonschema:"Corrections to learn in order, each with the fields of floop_learn (max 50),required"`
+}
+
+// LearnBatchItem reports what happened to one floop_learn_batch correction.
+type LearnBatchItem struct {
+	Index           int      `json:"index" jsonschema:"Position of the correction in the batch"`
+	Status          string   `json:"status" jsonschema:"Outcome: accepted, learned (below the auto-accept threshold), review, merged, quarantined, duplicate (repeats an earlier item), held, limit_reached, or error"`
+	CorrectionID    string   `json:"correction_id,omitempty" jsonschema:"ID of the captured correction"`
+	BehaviorID      string   `json:"behavior_id,omitempty" jsonschema:"ID of the extracted behavior"`
+	Scope           string   `json:"scope,omitempty" jsonschema:"Where the behavior was stored: 'local' or 'global'"`
+	DuplicateOf     *int     `json:"duplicate_of,omitempty" jsonschema:"Index of the earlier item this one repeats"`
+	MergedIntoID    string   `json:"merged_into_id,omitempty" jsonschema:"ID of behavior this was merged into"`
 	MergeSimilarity float64  `json:"merge_similarity,omitempty" jsonschema:"Similarity score with merged behavior (0.0-1.0)"`
-	Message         string   `json:"message" jsonschema:"Human-readable result message"`
+	ReviewReasons   []string `json:"review_reasons,omitempty" jsonschema:"Reasons why review is needed"`
+	QualityScore    float64  `json:"quality_score,omitempty" jsonschema:"Correction quality score (0.0-1.0), when the quality gate is enabled"`
+	LimitReached    string   `json:"limit_reached,omitempty" jsonschema:"Storage limit that stopped the behavior from being added"`
+	Error           string   `json:"error,omitempty" jsonschema:"Why the correction was rejected or failed"`
+}
+
+// FloopLearnBatchOutput defines the output for floop_learn_batch tool.
+type FloopLearnBatchOutput struct {
+	Items   []LearnBatchItem `json:"items" jsonschema:"Outcome of each correction, in batch order"`
+	Counts  map[string]int   `json:"counts" jsonschema:"Number of corrections per status"`
+	Message string           `json:"message" jsonschema:"Human-readable result message"`
+}
+
+// LearnPolicy is the learning policy applied to a floop_learn call.
+type LearnPolicy struct {
+	Routing             string  `json:"routing" jsonschema:"Scope routing: auto (classified by the behavior's conditions), local, or global"`
+	ScopeOverride       bool    `json:"scope_override" jsonschema:"Whether a per-scope override for the behavior's scope applied"`
+	AutoAcceptThreshold float64 `json:"auto_accept_threshold" jsonschema:"Minimum placement confidence to auto-accept"`
+	AutoMerge           bool    `json:"auto_merge" jsonschema:"Whether near-duplicates are merged instead of added"`
+	AutoMergeThreshold  float64 `json:"auto_merge_threshold" jsonschema:"Minimum similarity to auto-merge"`
+	ReviewConstraints   bool    `json:"review_constraints" jsonschema:"Whether constraint behaviors always need review"`
+	ReviewMinConfidence float64 `json:"review_min_confidence" jsonschema:"Placement confidence below which review is needed"`
+	ReviewMaxSimilarity float64 `json:"review_max_similarity" jsonschema:"Similarity to an existing behavior above which review is needed"`
+}
+
+// FloopReportFailureInput defines the input for floop_report_failure tool.
+type FloopReportFailureInput struct {
+	Kind     string `json:"kind,omitempty" jsonschema:"Failure kind: test, build, lint, runtime, or other (default: other)"`
+	Command  string `json:"command,omitempty" jsonschema:"The command that failed, e.g. 'go test ./...'"`
+	Action   string `json:"action,omitempty" jsonschema:"What the agent did before the failure"`
+	Error    string `json:"error" jsonschema:"The failure output or a summary of it,required"`
+	Fix      string `json:"fix" jsonschema:"What resolved the failure, phrased as what to do instead,required"`
+	File     string `json:"file,omitempty" jsonschema:"Relevant file path for context"`
+	Task     string `json:"task,omitempty" jsonschema:"Current task type for context"`
+	Language string `json:"language,omitempty" jsonschema:"Programming language (e.g. 'go', 'python'). Overrides file extension inference"`
+}
+
+// FloopReportFailureOutput defines the output for floop_report_failure tool.
+type FloopReportFailureOutput struct {
+	FailureID string `json:"failure_id" jsonschema:"ID of the recorded failure"`
+	Status    string `json:"status" jsonschema:"Review status: pending, learned, or dismissed"`
+	Duplicate bool   `json:"duplicate,omitempty" jsonschema:"Whether the same failure was already recorded"`
+	Pending   int    `json:"pending" jsonschema:"Number of failures awaiting review"`
+	Message   string `json:"message" jsonschema:"Human-readable result message"`
+}
+
+// FloopRecordOutcomeInput defines the input for floop_record_outcome tool.
+type FloopRecordOutcomeInput struct {
+	Kind        string   `json:"kind" jsonschema:"Outcome: tests-passed, build-passed, pr-merged, tests-failed, build-failed, pr-rejected, bug-reopened, or reverted,required"`
+	BehaviorIDs []string `json:"behavior_ids,omitempty" jsonschema:"Behaviors to link in addition to those active during this session"`
+	SessionID   string   `json:"session_id,omitempty" jsonschema:"Optional session ID to record with the outcome"`
+	Ref         string   `json:"ref,omitempty" jsonschema:"Reference for the outcome, e.g. a PR URL or commit"`
+	Note        string   `json:"note,omitempty" jsonschema:"Free-form detail"`
+}
+
+// FloopRecordOutcomeOutput defines the output for floop_record_outcome tool.
+type FloopRecordOutcomeOutput struct {
+	OutcomeID   string   `json:"outcome_id" jsonschema:"ID of the recorded outcome"`
+	Kind        string   `json:"kind" jsonschema:"The recorded outcome"`
+	Positive    bool     `json:"positive" jsonschema:"Whether the outcome is a good result"`
+	BehaviorIDs []string `json:"behavior_ids" jsonschema:"Behaviors the outcome is linked to"`
+	Message     string   `json:"message" jsonschema:"Human-readable result message"`
+}
+
+// FloopSessionInfoInput defines the input for floop_session_info tool.
+type FloopSessionInfoInput struct {
+	All        bool `json:"all,omitempty" jsonschema:"Report every client session the server is tracking, not just the caller's (default: false)"`
+	RateLimits bool `json:"rate_limits,omitempty" jsonschema:"Also report the caller's rate limiter state per tool, for debugging throttled calls (default: false)"`
+	Flush      bool `json:"flush,omitempty" jsonschema:"Wait for the background writes of earlier calls (activation hits, confirmations, Hebbian updates) before reading (default: false)"`
+}
+
+// FloopSessionInfoOutput defines the output for floop_session_info tool.
+type FloopSessionInfoOutput struct {
+	Sessions   []SessionInfo     `json:"sessions" jsonschema:"Client sessions, oldest first"`
+	Count      int               `json:"count" jsonschema:"Number of sessions reported"`
+	RateLimits []ratelimit.State `json:"rate_limits,omitempty" jsonschema:"Per tool: the caller's budget and tokens left, and the ceiling shared by all clients and its tokens left, when rate_limits is set"`
+}
+
+// SessionInfo describes one client session of the server.
+type SessionInfo struct {
+	ID          string `json:"id" jsonschema:"Session ID: the transport's session ID, or session-N for stdio"`
+	Client      string `json:"client,omitempty" jsonschema:"Client name from the initialize handshake"`
+	Current     bool   `json:"current,omitempty" jsonschema:"True for the caller's own session"`
+	StartedAt   string `json:"started_at" jsonschema:"When the session started (RFC 3339)"`
+	LastSeenAt  string `json:"last_seen_at" jsonschema:"When the session last called a tool (RFC 3339)"`
+	ToolCalls   int    `json:"tool_calls" jsonschema:"Tool calls made in the session"`
+	ActiveCalls int    `json:"active_calls" jsonschema:"floop_active and floop_pin_context calls that computed an active set"`
+	Confirmed   int    `json:"confirmed" jsonschema:"Distinct behaviors implicitly confirmed in the session"`
 }
 
 // FloopListInput defines the input for floop_list tool.
 type FloopListInput struct {
 	Corrections bool   `json:"corrections,omitempty" jsonschema:"List corrections instead of behaviors (default: false)"`
 	Tag         string `json:"tag,omitempty" jsonschema:"Filter behaviors by tag (exact match)"`
+	Limit       int    `json:"limit,omitempty" jsonschema:"Return at most this many behaviors, in ID order, or corrections, in time order, with a next_cursor when more follow (default: all)"`
+	Cursor      string `json:"cursor,omitempty" jsonschema:"Continue from the next_cursor of a previous page"`
+	Since       string `json:"since,omitempty" jsonschema:"With corrections, only those at or after this RFC 3339 time or duration ago (e.g. 7d)"`
+	Until       string `json:"until,omitempty" jsonschema:"With corrections, only those before this RFC 3339 time or duration ago"`
+	Processed   bool   `json:"processed,omitempty" jsonschema:"With corrections, only those already learned (default: false)"`
+	Unprocessed bool   `json:"unprocessed,omitempty" jsonschema:"With corrections, only those not yet learned (default: false)"`
+	Flush       bool   `json:"flush,omitempty" jsonschema:"Wait for the background writes of earlier calls (activation hits, confirmations, Hebbian updates) before reading (default: false)"`
 }
 
 // FloopListOutput defines the output for floop_list tool.
@@ -82,6 +252,7 @@ type FloopListOutput struct {
 	Behaviors   []BehaviorListItem   `json:"behaviors,omitempty" jsonschema:"List of behaviors"`
 	Corrections []CorrectionListItem `json:"corrections,omitempty" jsonschema:"List of corrections"`
 	Count       int                  `json:"count" jsonschema:"Number of items"`
+	NextCursor  string               `json:"next_cursor,omitempty" jsonschema:"Cursor of the next page, empty after the last page"`
 }
 
 // BehaviorListItem provides a list view of a behavior.
@@ -104,11 +275,41 @@ type CorrectionListItem struct {
 	Processed       bool      `json:"processed"`
 }
 
+// FloopQueryInput defines the input for floop_query tool.
+type FloopQueryInput struct {
+	Kinds         []string `json:"kinds,omitempty" jsonschema:"Only return behaviors of these kinds (directive, constraint, procedure, preference, episodic, workflow)"`
+	Tags          []string `json:"tags,omitempty" jsonschema:"Only return behaviors carrying at least one of these tags"`
+	MinConfidence float64  `json:"min_confidence,omitempty" jsonschema:"Minimum confidence (0.0-1.0)"`
+	MaxConfidence float64  `json:"max_confidence,omitempty" jsonschema:"Maximum confidence (0.0-1.0, default: 1.0)"`
+	Text          string   `json:"text,omitempty" jsonschema:"Case-insensitive text to match against name, content, and tags"`
+	RelatedTo     string   `json:"related_to,omitempty" jsonschema:"Only return behaviors connected by an edge to this behavior ID"`
+	Limit         int      `json:"limit,omitempty" jsonschema:"Maximum number of results (default: 10, max: 50)"`
+	Flush         bool     `json:"flush,omitempty" jsonschema:"Wait for the background writes of earlier calls (activation hits, confirmations, Hebbian updates) before reading (default: false)"`
+}
+
+// FloopQueryOutput defines the output for floop_query tool.
+type FloopQueryOutput struct {
+	Results []QueryResultItem `json:"results" jsonschema:"Matching behaviors, highest confidence first"`
+	Count   int               `json:"count" jsonschema:"Number of results returned"`
+	Total   int               `json:"total" jsonschema:"Number of behaviors matching the filter before the limit"`
+}
+
+// QueryResultItem provides a compact view of a behavior matched by floop_query.
+type QueryResultItem struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Kind       string   `json:"kind"`
+	Confidence float64  `json:"confidence"`
+	Preview    string   `json:"preview" jsonschema:"Summary, or the start of the canonical content"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
 // FloopDeduplicateInput defines the input for floop_deduplicate tool.
 type FloopDeduplicateInput struct {
 	DryRun    bool    `json:"dry_run,omitempty" jsonschema:"If true, only report duplicates without merging (default: false)"`
 	Threshold float64 `json:"threshold,omitempty" jsonschema:"Similarity threshold for duplicate detection (0.0-1.0, default: 0.9)"`
 	Scope     string  `json:"scope,omitempty" jsonschema:"Scope of deduplication: 'local', 'global', or 'both' (default: 'both')"`
+	Full      bool    `json:"full,omitempty" jsonschema:"If true, compare every pair instead of only behaviors new or changed since the last run (default: false)"`
 }
 
 // FloopDeduplicateOutput defines the output for floop_deduplicate tool.
@@ -116,6 +317,12 @@ type FloopDeduplicateOutput struct {
 	DuplicatesFound int                   `json:"duplicates_found" jsonschema:"Number of duplicate pairs found"`
 	Merged          int                   `json:"merged" jsonschema:"Number of behaviors merged"`
 	Results         []DeduplicationResult `json:"results,omitempty" jsonschema:"Details of each deduplication action"`
+	ContextVariants int                   `json:"context_variants,omitempty" jsonschema:"Similar pairs not merged because their when-conditions are incompatible"`
+	SharedParents   []string              `json:"shared_parents,omitempty" jsonschema:"IDs of generalized parents created to link context variants"`
+	BlockedMerges   []string              `json:"blocked_merges,omitempty" jsonschema:"Duplicates left unmerged because their when-conditions cannot be combined, with the reason"`
+	RestorePoint    string                `json:"restore_point,omitempty" jsonschema:"Restore point saved before merging; undo with 'floop restore-point apply'"`
+	Differential    bool                  `json:"differential,omitempty" jsonschema:"Only behaviors new or changed since the last run were compared"`
+	Compared        int                   `json:"compared" jsonschema:"Number of behaviors compared against the rest of the store"`
 	Message         string                `json:"message" jsonschema:"Human-readable summary"`
 }
 
@@ -153,22 +360,27 @@ type FloopBackupOutput struct {
 type FloopRestoreInput struct {
 	InputPath string `json:"input_path" jsonschema:"Path to backup file to restore,required"`
 	Mode      string `json:"mode,omitempty" jsonschema:"Restore mode: merge (skip existing, default) or replace (clear first)"`
+	Strategy  string `json:"strategy,omitempty" jsonschema:"Merge mode: how to resolve nodes that exist with different content: keep-local (default), take-backup, or merge-fields (keep existing fields, add those only the backup has)"`
 }
 
 // FloopRestoreOutput defines the output for floop_restore tool.
 type FloopRestoreOutput struct {
 	NodesRestored int    `json:"nodes_restored" jsonschema:"Number of nodes restored"`
 	NodesSkipped  int    `json:"nodes_skipped" jsonschema:"Number of nodes skipped (merge mode)"`
+	NodesUpdated  int    `json:"nodes_updated" jsonschema:"Number of existing nodes updated by conflict resolution (merge mode)"`
 	EdgesRestored int    `json:"edges_restored" jsonschema:"Number of edges restored"`
 	EdgesSkipped  int    `json:"edges_skipped" jsonschema:"Number of edges skipped"`
+	RestorePoint  string `json:"restore_point,omitempty" jsonschema:"Restore point saved before a replace restore; undo with 'floop restore-point apply'"`
 	Message       string `json:"message" jsonschema:"Human-readable result message"`
+
+	Conflicts []backup.Conflict `json:"conflicts,omitempty" jsonschema:"Nodes that exist with different content (merge mode): differing fields and how each was resolved"`
 }
 
 // FloopConnectInput defines the input for floop_connect tool.
 type FloopConnectInput struct {
 	Source        string  `json:"source" jsonschema:"Source behavior ID,required"`
 	Target        string  `json:"target" jsonschema:"Target behavior ID,required"`
-	Kind          string  `json:"kind" jsonschema:"Edge type: requires, overrides, conflicts, similar-to, learned-from,required"`
+	Kind          string  `json:"kind" jsonschema:"Edge type: requires, overrides, conflicts, similar-to, learned-from, specializes,required"`
 	Weight        float64 `json:"weight,omitempty" jsonschema:"Edge weight (0.0-1.0, default 0.8)"`
 	Bidirectional bool    `json:"bidirectional,omitempty" jsonschema:"Create edges in both directions (default: false)"`
 }
@@ -185,7 +397,15 @@ type FloopConnectOutput struct {
 
 // FloopGraphInput defines the input for floop_graph tool.
 type FloopGraphInput struct {
-	Format string `json:"format,omitempty" jsonschema:"Output format: dot, json, or html (default: json)"`
+	Format        string   `json:"format,omitempty" jsonschema:"Output format: dot, json, or html (default: json)"`
+	Tags          []string `json:"tags,omitempty" jsonschema:"Only include behaviors carrying at least one of these tags"`
+	Kinds         []string `json:"kinds,omitempty" jsonschema:"Only include behaviors of these kinds (directive, constraint, procedure, preference, episodic, workflow)"`
+	MinConfidence float64  `json:"min_confidence,omitempty" jsonschema:"Minimum confidence (0.0-1.0)"`
+	Around        string   `json:"around,omitempty" jsonschema:"Only include the neighborhood of this behavior ID"`
+	Depth         int      `json:"depth,omitempty" jsonschema:"Hops from 'around' to include (default: 1, max: 5)"`
+	TopN          int      `json:"top_n,omitempty" jsonschema:"Only include the N behaviors with the highest PageRank"`
+	MinWeight     float64  `json:"min_weight,omitempty" jsonschema:"Only include edges with at least this weight (0.0-1.0); 'around' follows only these"`
+	Flush         bool     `json:"flush,omitempty" jsonschema:"Wait for the background writes of earlier calls (activation hits, confirmations, Hebbian updates) before reading (default: false)"`
 }
 
 // FloopGraphOutput defines the output for floop_graph tool.
@@ -230,9 +450,64 @@ type FloopFeedbackOutput struct {
 	Message    string `json:"message" jsonschema:"Human-readable result message"`
 }
 
+// FeedbackBatchItem is one feedback signal in a floop_feedback_batch call.
+type FeedbackBatchItem struct {
+	BehaviorID string `json:"behavior_id" jsonschema:"ID of the behavior to provide feedback on,required"`
+	Signal     string `json:"signal" jsonschema:"Feedback signal: confirmed (behavior was helpful) or overridden (behavior was contradicted),required"`
+	Strength   int    `json:"strength,omitempty" jsonschema:"How many times to count the signal, 1-5 (default: 1). Use more when the behavior mattered repeatedly during the session"`
+}
+
+// FloopFeedbackBatchInput defines the input for floop_feedback_batch tool.
+type FloopFeedbackBatchInput struct {
+	Items []FeedbackBatchItem `json:"items" jsonschema:"Feedback to record, one entry per behavior signal (max 100),required"`
+}
+
+// FeedbackBatchSkipped describes a feedback item that was not recorded.
+type FeedbackBatchSkipped struct {
+	BehaviorID string `json:"behavior_id" jsonschema:"ID of the behavior"`
+	Reason     string `json:"reason" jsonschema:"Why the feedback was not recorded"`
+}
+
+// FloopFeedbackBatchOutput defines the output for floop_feedback_batch tool.
+type FloopFeedbackBatchOutput struct {
+	Recorded   int                    `json:"recorded" jsonschema:"Number of feedback items recorded"`
+	Confirmed  int                    `json:"confirmed" jsonschema:"Confirmations recorded, counting strength"`
+	Overridden int                    `json:"overridden" jsonschema:"Overrides recorded, counting strength"`
+	Skipped    []FeedbackBatchSkipped `json:"skipped,omitempty" jsonschema:"Items that were not recorded, with reasons"`
+	Message    string                 `json:"message" jsonschema:"Human-readable result message"`
+}
+
+// RateLimitErrorData is the structured content of a rate-limited tool call.
+type RateLimitErrorData struct {
+	Error             string  `json:"error"`               // Always "rate_limited"
+	Tool              string  `json:"tool"`                // Rate-limited tool
+	Rule              string  `json:"rule"`                // The limit that applied, e.g. "10/minute, burst 3"
+	RetryAfterSeconds float64 `json:"retry_after_seconds"` // Wait this long before retrying (0 = unknown)
+	Global            bool    `json:"global,omitempty"`    // The ceiling shared by all clients applied, not the caller's budget
+}
+
+// DeadlineErrorData is the structured content of a tool call abandoned at
+// its deadline (tool_timeouts).
+type DeadlineErrorData struct {
+	Error          string  `json:"error"`           // Always "deadline_exceeded"
+	Tool           string  `json:"tool"`            // Tool that ran out of time
+	TimeoutSeconds float64 `json:"timeout_seconds"` // The tool's deadline
+}
+
+// CircuitOpenErrorData is the structured content of a floop_learn call
+// rejected by the learning circuit breaker.
+type CircuitOpenErrorData struct {
+	Error             string  `json:"error"`               // Always "circuit_open"
+	Tool              string  `json:"tool"`                // Always "floop_learn"
+	Reason            string  `json:"reason"`              // "session_cap", "repetitive", or "cooling_off"
+	Detail            string  `json:"detail"`              // What tripped the breaker
+	RetryAfterSeconds float64 `json:"retry_after_seconds"` // Cool-down remaining (0 = not this session)
+	Message           string  `json:"message"`             // Instruction for the agent
+}
+
 // FloopPackInstallInput defines the input for floop_pack_install tool.
 type FloopPackInstallInput struct {
-	Source   string `json:"source" jsonschema:"Pack source: local path, URL (https://...), or GitHub shorthand (gh:owner/repo[@version]),required"`
+	Source   string `json:"source" jsonschema:"Pack source: local path, URL (https://...), GitHub shorthand (gh:owner/repo[@version]), or pack name such as the starter pack go-backend,required"`
 	FilePath string `json:"file_path,omitempty" jsonschema:"Deprecated: use source instead. Path to .fpack file to install"`
 }
 
@@ -248,3 +523,99 @@ type FloopPackInstallOutput struct {
 	DerivedEdges int      `json:"derived_edges" jsonschema:"Number of edges automatically derived between pack and existing behaviors"`
 	Message      string   `json:"message" jsonschema:"Human-readable result message"`
 }
+
+// FloopSimilarInput defines the input for floop_similar tool.
+type FloopSimilarInput struct {
+	Text     string  `json:"text" jsonschema:"Example behavior text to compare against the store,required"`
+	MinScore float64 `json:"min_score,omitempty" jsonschema:"Minimum similarity score (0.0-1.0, default: 0.1)"`
+	Limit    int     `json:"limit,omitempty" jsonschema:"Maximum number of matches (default: 10, max: 50)"`
+	Flush    bool    `json:"flush,omitempty" jsonschema:"Wait for the background writes of earlier calls (activation hits, confirmations, Hebbian updates) before reading (default: false)"`
+}
+
+// FloopSimilarOutput defines the output for floop_similar tool.
+type FloopSimilarOutput struct {
+	Matches []SimilarMatchItem `json:"matches" jsonschema:"Matching behaviors, most similar first"`
+	Count   int                `json:"count" jsonschema:"Number of matches returned"`
+	Message string             `json:"message" jsonschema:"Human-readable summary"`
+}
+
+// SimilarMatchItem provides a compact view of a behavior matched by floop_similar.
+type SimilarMatchItem struct {
+	ID             string  `json:"id"`
+	Name           string  `json:"name"`
+	Kind           string  `json:"kind"`
+	Preview        string  `json:"preview" jsonschema:"Summary, or the start of the canonical content"`
+	Score          float64 `json:"score" jsonschema:"Similarity score (0.0-1.0)"`
+	Method         string  `json:"method" jsonschema:"How the score was computed: embedding or jaccard"`
+	WouldDuplicate bool    `json:"would_duplicate" jsonschema:"True if learning the text would be flagged as a duplicate of this behavior"`
+}
+
+// FloopSearchInput defines the input for floop_search tool.
+type FloopSearchInput struct {
+	Query string `json:"query" jsonschema:"Keywords to look up in behavior names, content, and tags; words match by prefix and stem,required"`
+	Limit int    `json:"limit,omitempty" jsonschema:"Maximum number of results (default: 10, max: 50)"`
+	Flush bool   `json:"flush,omitempty" jsonschema:"Wait for the background writes of earlier calls (activation hits, confirmations, Hebbian updates) before reading (default: false)"`
+}
+
+// FloopSearchOutput defines the output for floop_search tool.
+type FloopSearchOutput struct {
+	Results []SearchHitItem `json:"results" jsonschema:"Matching behaviors, most relevant first"`
+	Count   int             `json:"count" jsonschema:"Number of results returned"`
+	Message string          `json:"message" jsonschema:"Human-readable summary"`
+}
+
+// SearchHitItem provides a compact view of a behavior found by floop_search.
+type SearchHitItem struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Kind    string   `json:"kind"`
+	Preview string   `json:"preview" jsonschema:"Summary, or the start of the canonical content"`
+	Tags    []string `json:"tags,omitempty"`
+	Score   float64  `json:"score" jsonschema:"Relevance; higher is better, comparable within one search only"`
+}
+
+// FloopExplainInput defines the input for floop_explain tool.
+type FloopExplainInput struct {
+	BehaviorID string   `json:"behavior_id" jsonschema:"Behavior to explain, by ID, name, or slug,required"`
+	File       string   `json:"file,omitempty" jsonschema:"Current file path (relative to project root)"`
+	Task       string   `json:"task,omitempty" jsonschema:"Current task type (e.g. 'development', 'testing', 'refactoring')"`
+	Language   string   `json:"language,omitempty" jsonschema:"Programming language (e.g. 'go', 'python'). Overrides file extension inference"`
+	Tags       []string `json:"tags,omitempty" jsonschema:"Tags describing the current work, as for floop_active"`
+	Full       bool     `json:"full,omitempty" jsonschema:"Also return every edge firing of the run, not only those reaching the behavior"`
+	Flush      bool     `json:"flush,omitempty" jsonschema:"Wait for the background writes of earlier calls (activation hits, confirmations, Hebbian updates) before reading (default: false)"`
+}
+
+// FloopExplainOutput defines the output for floop_explain tool.
+type FloopExplainOutput struct {
+	BehaviorID  string                 `json:"behavior_id"`
+	Name        string                 `json:"name"`
+	Context     map[string]interface{} `json:"context" jsonschema:"Context used for activation"`
+	Seeds       []ExplainSeed          `json:"seeds" jsonschema:"Behaviors spreading started from: direct matches and standing seeds"`
+	Steps       int                    `json:"steps" jsonschema:"Propagation steps run"`
+	Explanation spreading.Explanation  `json:"explanation" jsonschema:"How activation reached the behavior: the path of edges from a seed, with weight, decay, and energy per hop, and every edge that reached it"`
+	Firings     []spreading.Firing     `json:"firings,omitempty" jsonschema:"Every edge firing of the run, when full is set"`
+	Message     string                 `json:"message" jsonschema:"Human-readable summary"`
+}
+
+// ExplainSeed is one seed of a floop_explain run.
+type ExplainSeed struct {
+	BehaviorID string  `json:"behavior_id"`
+	Activation float64 `json:"activation"`
+	Source     string  `json:"source"`
+}
+
+// FloopPromoteInput defines the input for floop_promote tool.
+type FloopPromoteInput struct {
+	BehaviorID       string `json:"behavior_id,omitempty" jsonschema:"Local behavior to promote to the global store, by ID, name, or slug"`
+	Move             bool   `json:"move,omitempty" jsonschema:"Move the behavior, keeping its ID, instead of leaving a deprecated local copy that defers to the global one"`
+	Force            bool   `json:"force,omitempty" jsonschema:"Promote regardless of confirmations"`
+	Suggest          bool   `json:"suggest,omitempty" jsonschema:"List local behaviors ready for promotion instead of promoting one"`
+	MinConfirmations int    `json:"min_confirmations,omitempty" jsonschema:"Confirmations needed to promote, and to be suggested (default 3)"`
+}
+
+// FloopPromoteOutput defines the output for floop_promote tool.
+type FloopPromoteOutput struct {
+	Promoted    *promote.Result      `json:"promoted,omitempty" jsonschema:"The promotion: local and global IDs, whether it moved, and the restore point saved first"`
+	Suggestions []promote.Suggestion `json:"suggestions,omitempty" jsonschema:"Local behaviors ready for promotion, most confirmed first"`
+	Message     string               `json:"message" jsonschema:"Human-readable result message"`
+}
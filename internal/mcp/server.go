@@ -7,20 +7,30 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/activation"
 	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/capabilities"
 	"github.com/nvandessel/floop/internal/config"
 	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/eventbus"
 	"github.com/nvandessel/floop/internal/events"
+	"github.com/nvandessel/floop/internal/expiry"
+	"github.com/nvandessel/floop/internal/learning"
 	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/metrics"
 	"github.com/nvandessel/floop/internal/project"
 	"github.com/nvandessel/floop/internal/ranking"
 	"github.com/nvandessel/floop/internal/ratelimit"
+	"github.com/nvandessel/floop/internal/retirement"
 	"github.com/nvandessel/floop/internal/seed"
 	"github.com/nvandessel/floop/internal/session"
 	"github.com/nvandessel/floop/internal/setup"
@@ -33,6 +43,10 @@ import (
 // maxBackgroundWorkers is the maximum number of concurrent background goroutines.
 const maxBackgroundWorkers = 5
 
+// metricsSnapshotInterval is how often the metrics snapshot read by
+// 'floop stats --tools' is rewritten.
+const metricsSnapshotInterval = 10 * time.Second
+
 // Server wraps the MCP SDK server and provides floop-specific functionality.
 type Server struct {
 	server        *sdk.Server
@@ -43,11 +57,25 @@ type Server struct {
 	pageRankMu    sync.RWMutex
 	pageRankCache map[string]float64
 
+	// Optional external scorer merged into activation ranking (nil when disabled)
+	externalScorer *ranking.ExternalScorer
+
 	// Audit logging
 	auditLogger *AuditLogger
 
-	// Rate limiting
-	toolLimiters ratelimit.ToolLimiters
+	// Structured events for learn, merge, forget, and backup, delivered to
+	// the configured sinks (nil when none are configured)
+	eventBus *eventbus.Bus
+
+	// Rate limiting, per client session under a global ceiling per tool
+	toolLimiters ratelimit.Limits
+
+	// Circuit breaker for repetitive floop_learn calls (nil when disabled)
+	learnBreaker *learning.CircuitBreaker
+
+	// Per-tool call metrics, served on Config.MetricsAddr when set
+	metrics     *metrics.Registry
+	metricsAddr string
 
 	// PageRank debounce
 	pageRankDebounce   *time.Timer
@@ -61,16 +89,26 @@ type Server struct {
 	workerPool chan struct{}
 	workerWg   sync.WaitGroup
 
-	// Session-scoped implicit confirmation tracking.
-	// Each behavior gets at most 1 implicit confirmation per session (not per
-	// floop_active call). This measures "how many distinct work sessions
-	// involved this behavior" — a far better usefulness proxy than per-call counting.
-	confirmedSessionMu   sync.Mutex
-	confirmedThisSession map[string]struct{}
+	// Background writes a read with flush waits for
+	pending *pendingWrites
+
+	// Per-client session state, including each session's implicit
+	// confirmations, so agents sharing one server don't share sessions
+	sessions *sessionTracker
+
+	// Active sets frozen by floop_pin_context, by handle
+	pinMu sync.Mutex
+	pins  map[string]*activePin
 
 	// Spreading activation engine (NativeEngine if available, else pure-Go Engine)
 	activator spreading.Activator
 
+	// Configuration of activator, for the pure-Go engine floop_explain runs
+	spreadConfig spreading.Config
+
+	// Shared evaluator so compiled when-conditions are reused across calls
+	evaluator *activation.Evaluator
+
 	// Hebbian co-activation learning
 	coActivationTracker *coActivationTracker
 	hebbianConfig       spreading.HebbianConfig
@@ -96,6 +134,18 @@ type Server struct {
 	// Shutdown coordination
 	done      chan struct{} // closed on shutdown
 	closeOnce sync.Once
+
+	// sleeping is set while a scheduled sleep phase runs
+	sleeping atomic.Bool
+
+	// decaying is set while a scheduled decay pass runs
+	decaying atomic.Bool
+
+	// Results pre-computed on client initialize (see prewarm.go).
+	// graphGeneration is bumped on every graph write to invalidate them.
+	prewarmMu       sync.Mutex
+	prewarmed       prewarmed
+	graphGeneration atomic.Uint64
 }
 
 // Config holds server configuration.
@@ -103,6 +153,34 @@ type Config struct {
 	Name    string // Server name (e.g., "floop")
 	Version string // Server version
 	Root    string // Project root directory
+
+	// MetricsAddr, when set, serves Prometheus metrics at /metrics over
+	// HTTP on this address (e.g., "127.0.0.1:9464").
+	MetricsAddr string
+}
+
+// newEvaluator creates the shared activation evaluator with the configured
+// match mode, falling back to strict matching for an unknown mode.
+func newEvaluator(cfg *config.FloopConfig) *activation.Evaluator {
+	evaluator := activation.NewEvaluator()
+	mode, err := activation.ParseMatchMode(cfg.Ranking.Match.Mode)
+	if err != nil {
+		return evaluator
+	}
+	return evaluator.WithMatchOptions(activation.MatchOptions{
+		Mode:         mode,
+		MinScore:     cfg.Ranking.Match.MinScore,
+		FieldWeights: cfg.Ranking.Match.FieldWeights,
+	})
+}
+
+// standingSeeds converts the configured standing seeds for spreading.
+func standingSeeds(cfg *config.FloopConfig) []spreading.StandingSeed {
+	seeds := make([]spreading.StandingSeed, 0, len(cfg.Activation.StandingSeeds))
+	for _, sc := range cfg.Activation.StandingSeeds {
+		seeds = append(seeds, spreading.StandingSeed{Tag: sc.Tag, BehaviorID: sc.Behavior, Activation: sc.Activation})
+	}
+	return seeds
 }
 
 // NewServer creates a new MCP server with floop tools.
@@ -113,8 +191,22 @@ func NewServer(cfg *Config) (*Server, error) {
 		return nil, fmt.Errorf("failed to create graph store: %w", err)
 	}
 
+	// The server syncs after every write; append to nodes.log.jsonl instead of
+	// rewriting nodes.jsonl each time. The log is compacted on Close.
+	if err := graphStore.EnableAppendLog(store.DefaultAppendLogCompactThreshold); err != nil {
+		slog.Warn("append log unavailable, using full JSONL rewrites", "error", err)
+	}
+
+	// Load floop config (non-fatal: use defaults on error)
+	floopCfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to load config, using defaults: %v\n", err)
+		floopCfg = config.Default()
+	}
+
 	// Build spreading activation engine (prefer native sproink FFI, fall back to pure-Go).
 	spreadConfig := spreading.DefaultConfig()
+	spreadConfig.TemporalDecayRate = ranking.HalfLifeDecayRate(floopCfg.Activation.EdgeHalfLife)
 	affinityConfig := spreading.DefaultAffinityConfig()
 	spreadConfig.Affinity = &affinityConfig
 	spreadConfig.TagProvider = spreading.NewStoreTagProvider(graphStore)
@@ -132,13 +224,34 @@ func NewServer(cfg *Config) (*Server, error) {
 		activator = spreading.NewEngine(graphStore, spreadConfig)
 	}
 
-	// Create MCP server
+	// Create MCP server. s is assigned below, before the server can run.
+	var s *Server
 	mcpServer := sdk.NewServer(&sdk.Implementation{
 		Name:    cfg.Name,
 		Version: cfg.Version,
 	}, &sdk.ServerOptions{
+		// Advertise floop's own capabilities under experimental.floop so
+		// clients can feature-detect. Logging matches the SDK default that
+		// a nil Capabilities would have given.
+		Capabilities: &sdk.ServerCapabilities{
+			Logging: &sdk.LoggingCapabilities{},
+			Experimental: map[string]any{
+				"floop": capabilities.Describe(cfg.Version),
+			},
+		},
 		InitializedHandler: func(ctx context.Context, req *sdk.InitializedRequest) {
-			// Client initialized, ready to serve
+			// Client initialized: start tracking its session, and pre-compute
+			// the session's first activation and resource read in the
+			// background.
+			var ss *sdk.ServerSession
+			if req != nil {
+				ss = req.Session
+			}
+			s.sessionStarted(ss)
+			agent := sessionClientName(ss)
+			s.runBackground("context-prewarm", func() {
+				s.prewarm(context.Background(), agent)
+			})
 		},
 	})
 
@@ -149,12 +262,6 @@ func NewServer(cfg *Config) (*Server, error) {
 		homeDir = "" // NewAuditLogger handles empty dir gracefully
 	}
 
-	// Load floop config (non-fatal: use defaults on error)
-	floopCfg, err := config.Load()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to load config, using defaults: %v\n", err)
-		floopCfg = config.Default()
-	}
 	retPolicy := buildRetentionPolicy(&floopCfg.Backup)
 
 	// Initialize shared event store for consolidation MCP tools.
@@ -184,29 +291,43 @@ func NewServer(cfg *Config) (*Server, error) {
 	// Resolve project ID for event stamping (non-fatal — empty means universal scope)
 	resolvedProjectID, _ := project.ResolveProjectID(cfg.Root)
 
-	s := &Server{
-		server:               mcpServer,
-		store:                graphStore,
-		root:                 cfg.Root,
-		floopVersion:         cfg.Version,
-		floopConfig:          floopCfg,
-		session:              session.NewState(session.DefaultConfig()),
-		auditLogger:          NewAuditLogger(cfg.Root, homeDir),
-		pageRankCache:        make(map[string]float64),
-		toolLimiters:         ratelimit.NewToolLimiters(),
-		backupConfig:         &floopCfg.Backup,
-		retentionPolicy:      retPolicy,
-		workerPool:           make(chan struct{}, maxBackgroundWorkers),
-		confirmedThisSession: make(map[string]struct{}),
-		activator:            activator,
-		coActivationTracker:  initCoActivationTracker(graphStore),
-		hebbianConfig:        spreading.DefaultHebbianConfig(),
-		eventStore:           eventStore,
-		eventDB:              eventDB,
-		projectID:            resolvedProjectID,
-		logger:               slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})),
-		done:                 make(chan struct{}),
+	s = &Server{
+		server:              mcpServer,
+		store:               graphStore,
+		root:                cfg.Root,
+		floopVersion:        cfg.Version,
+		floopConfig:         floopCfg,
+		session:             session.NewState(session.DefaultConfig()),
+		auditLogger:         NewAuditLogger(cfg.Root, homeDir),
+		pageRankCache:       make(map[string]float64),
+		toolLimiters:        ratelimit.NewLimits(floopCfg.RateLimit.Ceiling()),
+		learnBreaker:        newLearnBreaker(floopCfg),
+		metrics:             metrics.NewRegistry(),
+		metricsAddr:         cfg.MetricsAddr,
+		backupConfig:        &floopCfg.Backup,
+		retentionPolicy:     retPolicy,
+		workerPool:          make(chan struct{}, maxBackgroundWorkers),
+		pending:             newPendingWrites(pendingFloopDir(cfg.Root)),
+		sessions:            newSessionTracker(),
+		pins:                make(map[string]*activePin),
+		activator:           activator,
+		spreadConfig:        spreadConfig,
+		evaluator:           newEvaluator(floopCfg),
+		coActivationTracker: initCoActivationTracker(graphStore),
+		hebbianConfig:       spreading.DefaultHebbianConfig(),
+		eventStore:          eventStore,
+		eventDB:             eventDB,
+		projectID:           resolvedProjectID,
+		logger:              slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})),
+		done:                make(chan struct{}),
 	}
+	// A session evicted to make room never ends, so drop its rate limit
+	// budgets here as sessionEnded would
+	s.sessions.onEvict = func(id string) { s.toolLimiters.Forget(id) }
+	s.metrics.SetQueue(func() (int, int) {
+		return len(s.workerPool), cap(s.workerPool)
+	})
+	s.eventBus = newEventBus(floopCfg.EventBus, cfg.Root, os.Stderr, s.logger)
 
 	// Initialize local embedding client.
 	// Priority: explicit config > auto-detect from ~/.floop/
@@ -267,6 +388,7 @@ func NewServer(cfg *Config) (*Server, error) {
 	autoSeedGlobalStore(graphStore)
 
 	// Register tools
+	mcpServer.AddReceivingMiddleware(rateLimitMiddleware, s.sessionMiddleware, s.deadlineMiddleware)
 	if err := s.registerTools(); err != nil {
 		graphStore.Close()
 		return nil, fmt.Errorf("failed to register tools: %w", err)
@@ -284,6 +406,59 @@ func NewServer(cfg *Config) (*Server, error) {
 		s.logger.Warn("failed to compute initial PageRank", "error", err)
 	}
 
+	// Background sweep: deprecate behaviors whose expiry has passed
+	s.runBackground("expiry-sweep", func() {
+		notices, err := expiry.Sweep(context.Background(), s.store, time.Now(), false)
+		if err != nil {
+			s.logger.Warn("expiry sweep failed", "error", err)
+			return
+		}
+		if len(notices) == 0 {
+			return
+		}
+		s.invalidatePrewarm()
+		s.logger.Info("deprecated expired behaviors", "count", len(notices))
+		if err := expiry.RecordNotices(filepath.Join(s.root, ".floop"), notices); err != nil {
+			s.logger.Warn("failed to record expiry notices", "error", err)
+		}
+	})
+
+	// Background sweep: forget retired behaviors whose grace period ended
+	s.runBackground("retirement-sweep", func() {
+		notices, err := retirement.Sweep(context.Background(), s.store, time.Now(), false)
+		if err != nil {
+			s.logger.Warn("retirement sweep failed", "error", err)
+			return
+		}
+		if len(notices) == 0 {
+			return
+		}
+		s.invalidatePrewarm()
+		s.logger.Info("forgot retired behaviors", "count", len(notices))
+		s.emitForgotten("retirement-sweep", notices)
+		if err := retirement.RecordNotices(filepath.Join(s.root, ".floop"), notices); err != nil {
+			s.logger.Warn("failed to record retirement notices", "error", err)
+		}
+	})
+
+	if ext := floopCfg.Ranking.External; ext.Enabled {
+		s.externalScorer = ranking.NewExternalScorer(ranking.ExternalScorerConfig{
+			Command: ext.Command,
+			URL:     ext.URL,
+			Timeout: ext.Timeout,
+		})
+	}
+
+	// Scheduled sleep phase (opt-in): dedup, prune, recalibrate, decay, summarize
+	if floopCfg.Consolidation.Sleep.Enabled && homeDir != "" {
+		s.startSleepSchedule(filepath.Join(homeDir, ".floop"))
+	}
+
+	// Scheduled decay (opt-in): demote and prune unused behaviors
+	if floopCfg.Decay.Enabled && homeDir != "" {
+		s.startDecaySchedule(filepath.Join(homeDir, ".floop"))
+	}
+
 	// Background backfill: embed behaviors that don't yet have vectors
 	if s.embedder != nil && s.embedder.Available() {
 		if ng, ok := s.store.(vectorsearch.NodeGetter); ok {
@@ -301,7 +476,7 @@ func NewServer(cfg *Config) (*Server, error) {
 	return s, nil
 }
 
-// initVectorIndex creates the vector index (LanceDB or BruteForce fallback)
+// initVectorIndex creates the vector index (LanceDB, or the HNSW fallback)
 // and populates it from stored embeddings.
 func (s *Server) initVectorIndex(graphStore *store.MultiGraphStore, vectorDir string) vectorindex.VectorIndex {
 	allEmb, loadErr := graphStore.GetAllEmbeddings(context.Background())
@@ -313,7 +488,7 @@ func (s *Server) initVectorIndex(graphStore *store.MultiGraphStore, vectorDir st
 	// On fresh installs (no embeddings yet), this creates the table with 768 dims.
 	// If a future model has different dims, the first Add will fail with a clear
 	// dimension mismatch error, and on restart the schema validation will catch the
-	// mismatch and fall back to BruteForce until the user deletes .floop/vectors/.
+	// mismatch and fall back to HNSW until the user deletes .floop/vectors/.
 	dims := 768
 	for _, emb := range allEmb {
 		if len(emb.Embedding) > 0 {
@@ -327,20 +502,8 @@ func (s *Server) initVectorIndex(graphStore *store.MultiGraphStore, vectorDir st
 		Dims: dims,
 	})
 	if err != nil {
-		s.logger.Warn("LanceDB init failed, falling back to brute-force", "error", err)
-		bfIdx := vectorindex.NewBruteForceIndex()
-		if loadErr == nil {
-			var addErrs int
-			for _, emb := range allEmb {
-				if err := bfIdx.Add(context.Background(), emb.BehaviorID, emb.Embedding); err != nil {
-					addErrs++
-				}
-			}
-			if addErrs > 0 {
-				s.logger.Warn("some embeddings failed to load into brute-force index", "errors", addErrs, "total", len(allEmb))
-			}
-		}
-		return bfIdx
+		s.logger.Warn("LanceDB init failed, falling back to HNSW", "error", err)
+		return s.initHNSWIndex(allEmb, loadErr, filepath.Join(vectorDir, "hnsw.gob"))
 	}
 
 	// Sync SQLite embeddings to LanceDB.
@@ -372,6 +535,57 @@ func (s *Server) initVectorIndex(graphStore *store.MultiGraphStore, vectorDir st
 	return idx
 }
 
+// initHNSWIndex loads the persisted HNSW index and reconciles it with the
+// stored embeddings, so only vectors added, changed, or removed since the
+// last shutdown are applied. An unreadable index file is discarded and
+// rebuilt from the embeddings.
+func (s *Server) initHNSWIndex(allEmb []store.BehaviorEmbedding, loadErr error, path string) vectorindex.VectorIndex {
+	idx, err := vectorindex.NewHNSWIndex(vectorindex.HNSWConfig{Path: path})
+	if err != nil {
+		s.logger.Warn("discarding unreadable HNSW index", "path", path, "error", err)
+		if err := os.Remove(path); err != nil {
+			s.logger.Warn("failed to remove HNSW index, using brute-force", "path", path, "error", err)
+			return s.initBruteForceIndex(allEmb, loadErr)
+		}
+		if idx, err = vectorindex.NewHNSWIndex(vectorindex.HNSWConfig{Path: path}); err != nil {
+			return s.initBruteForceIndex(allEmb, loadErr)
+		}
+	}
+	if loadErr != nil {
+		return idx
+	}
+
+	vectors := make(map[string][]float32, len(allEmb))
+	for _, emb := range allEmb {
+		vectors[emb.BehaviorID] = emb.Embedding
+	}
+	added, removed := idx.Reconcile(context.Background(), vectors)
+	if added > 0 || removed > 0 {
+		s.logger.Info("updated HNSW index from SQLite", "added", added, "removed", removed, "total", idx.Len())
+		if err := idx.Save(context.Background()); err != nil {
+			s.logger.Warn("failed to save HNSW index", "error", err)
+		}
+	}
+	return idx
+}
+
+// initBruteForceIndex creates an in-memory BruteForceIndex holding allEmb.
+func (s *Server) initBruteForceIndex(allEmb []store.BehaviorEmbedding, loadErr error) vectorindex.VectorIndex {
+	bfIdx := vectorindex.NewBruteForceIndex()
+	if loadErr == nil {
+		var addErrs int
+		for _, emb := range allEmb {
+			if err := bfIdx.Add(context.Background(), emb.BehaviorID, emb.Embedding); err != nil {
+				addErrs++
+			}
+		}
+		if addErrs > 0 {
+			s.logger.Warn("some embeddings failed to load into brute-force index", "errors", addErrs, "total", len(allEmb))
+		}
+	}
+	return bfIdx
+}
+
 // refreshPageRank recomputes the PageRank cache from the current graph state.
 // This should be called after any operation that modifies the behavior graph
 // (e.g., floop_learn, floop_deduplicate).
@@ -390,7 +604,10 @@ func (s *Server) refreshPageRank(ctx context.Context) error {
 
 // debouncedRefreshPageRank schedules a PageRank refresh after a short delay.
 // Multiple rapid calls coalesce into a single recomputation.
+// Graph writes schedule a refresh here, so it also invalidates prewarmed results.
 func (s *Server) debouncedRefreshPageRank() {
+	s.invalidatePrewarm()
+
 	s.pageRankDebounceMu.Lock()
 	defer s.pageRankDebounceMu.Unlock()
 
@@ -412,16 +629,23 @@ func (s *Server) debouncedRefreshPageRank() {
 // runBackground executes fn in a bounded goroutine pool.
 // If the pool is full, the task is dropped with a warning.
 // If the server is shutting down, the task is not started.
+// Reads with flush wait for the tasks named in flushedTasks.
 func (s *Server) runBackground(name string, fn func()) {
+	finished := func() {}
+	if flushedTasks[name] {
+		finished = s.pending.start()
+	}
 	s.workerWg.Add(1)
 	select {
 	case <-s.done:
 		s.workerWg.Done()
+		finished()
 		return // server is shutting down
 	case s.workerPool <- struct{}{}:
 		go func() {
 			defer s.workerWg.Done()
 			defer func() { <-s.workerPool }()
+			defer finished()
 			select {
 			case <-s.done:
 				return
@@ -431,6 +655,10 @@ func (s *Server) runBackground(name string, fn func()) {
 		}()
 	default:
 		s.workerWg.Done()
+		finished()
+		if s.metrics != nil {
+			s.metrics.ObserveDropped()
+		}
 		s.logger.Warn("background worker pool full, skipping task", "task", name)
 	}
 }
@@ -451,15 +679,73 @@ func (s *Server) Run(ctx context.Context) error {
 		cancel()
 	}()
 
+	if s.metricsAddr != "" {
+		ln, err := net.Listen("tcp", s.metricsAddr)
+		if err != nil {
+			return fmt.Errorf("failed to listen for metrics on %s: %w", s.metricsAddr, err)
+		}
+		fmt.Fprintf(os.Stderr, "floop: serving metrics on http://%s/metrics\n", ln.Addr())
+		defer s.serveMetrics(ln)()
+	}
+	go s.writeMetricsSnapshots(ctx)
+
 	// Run server (blocks)
 	err := s.server.Run(ctx, &sdk.StdioTransport{})
 
 	// Clean up (idempotent — safe if Close() was already called)
 	s.Close()
+	s.writeMetricsSnapshot()
 
 	return err
 }
 
+// serveMetrics serves Prometheus metrics at /metrics on ln until the
+// returned stop function is called.
+func (s *Server) serveMetrics(ln net.Listener) func() {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", s.metrics.Handler())
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			s.logger.Warn("metrics server stopped", "error", err)
+		}
+	}()
+	return func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}
+}
+
+// writeMetricsSnapshots rewrites the metrics snapshot every
+// metricsSnapshotInterval until ctx is done.
+func (s *Server) writeMetricsSnapshots(ctx context.Context) {
+	ticker := time.NewTicker(metricsSnapshotInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.writeMetricsSnapshot()
+		}
+	}
+}
+
+// writeMetricsSnapshot writes the metrics to .floop/metrics.json for
+// 'floop stats --tools'. Nothing is written for a project without a store.
+func (s *Server) writeMetricsSnapshot() {
+	floopDir := filepath.Join(s.root, ".floop")
+	if _, err := os.Stat(floopDir); err != nil {
+		return
+	}
+	if err := s.metrics.WriteSnapshotFile(filepath.Join(floopDir, metrics.SnapshotFile)); err != nil {
+		s.logger.Warn("failed to write metrics snapshot", "error", err)
+	}
+}
+
 // autoSeedGlobalStore seeds meta-behaviors into the global store.
 // This is non-fatal: errors are logged to stderr but do not block startup.
 func autoSeedGlobalStore(graphStore *store.MultiGraphStore) {
@@ -532,6 +818,10 @@ func (s *Server) Close() error {
 			s.auditLogger.Close()
 		}
 
+		if err := s.eventBus.Close(eventBusCloseTimeout); err != nil {
+			s.logger.Warn("failed to close event sinks", "error", err)
+		}
+
 		if s.vectorIndex != nil {
 			if err := s.vectorIndex.Close(); err != nil {
 				s.logger.Warn("failed to close vector index", "error", err)
@@ -2,11 +2,20 @@ package mcp
 
 import (
 	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/nvandessel/floop/internal/metrics"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/vectorindex"
 )
 
 // isolateHome sets HOME to a temp directory to avoid touching real ~/.floop/
@@ -141,7 +150,7 @@ func TestNewServer_HasRateLimiters(t *testing.T) {
 	}
 	defer server.Close()
 
-	if server.toolLimiters == nil {
+	if server.toolLimiters.Client == nil || server.toolLimiters.Global == nil {
 		t.Error("toolLimiters should be initialized")
 	}
 
@@ -151,9 +160,12 @@ func TestNewServer_HasRateLimiters(t *testing.T) {
 		"floop_list", "floop_validate",
 	}
 	for _, tool := range expectedTools {
-		if _, ok := server.toolLimiters[tool]; !ok {
+		if _, ok := server.toolLimiters.Client[tool]; !ok {
 			t.Errorf("missing rate limiter for %s", tool)
 		}
+		if _, ok := server.toolLimiters.Global[tool]; !ok {
+			t.Errorf("missing global ceiling for %s", tool)
+		}
 	}
 }
 
@@ -262,6 +274,55 @@ func TestRun_CancelledContext(t *testing.T) {
 	}
 }
 
+func TestRun_WritesMetricsSnapshot(t *testing.T) {
+	server, tmpDir := setupTestServer(t)
+	defer server.Close()
+	server.metrics.ObserveCall("floop_active", 5*time.Millisecond, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	server.Run(ctx)
+
+	snap, err := metrics.ReadSnapshotFile(filepath.Join(tmpDir, ".floop", metrics.SnapshotFile))
+	if err != nil {
+		t.Fatalf("ReadSnapshotFile() error = %v", err)
+	}
+	if len(snap.Tools) != 1 || snap.Tools[0].Tool != "floop_active" {
+		t.Errorf("snapshot tools = %+v, want floop_active", snap.Tools)
+	}
+	if snap.Background.QueueCapacity != maxBackgroundWorkers {
+		t.Errorf("queue capacity = %d, want %d", snap.Background.QueueCapacity, maxBackgroundWorkers)
+	}
+}
+
+func TestServeMetrics(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	server.metrics.ObserveCall("floop_learn", 5*time.Millisecond, nil)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	stop := server.serveMetrics(ln)
+	defer stop()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	for _, want := range []string{
+		`floop_tool_calls_total{tool="floop_learn"} 1`,
+		"floop_background_queue_capacity 5",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
+
 func TestClose_GracefulShutdownStopsDebounce(t *testing.T) {
 	tmpDir := t.TempDir()
 	isolateHome(t, tmpDir)
@@ -336,3 +397,42 @@ func TestRunBackground_SkipsAfterClose(t *testing.T) {
 		t.Error("expected background task to be skipped after Close()")
 	}
 }
+
+func TestInitHNSWIndex_PersistsAndReconciles(t *testing.T) {
+	ctx := context.Background()
+	s := &Server{logger: slog.New(slog.DiscardHandler)}
+	path := filepath.Join(t.TempDir(), "hnsw.gob")
+
+	idx := s.initHNSWIndex([]store.BehaviorEmbedding{
+		{BehaviorID: "b1", Embedding: []float32{1, 0}},
+		{BehaviorID: "b2", Embedding: []float32{0, 1}},
+	}, nil, path)
+	if _, ok := idx.(*vectorindex.HNSWIndex); !ok {
+		t.Fatalf("initHNSWIndex() = %T, want *vectorindex.HNSWIndex", idx)
+	}
+	if err := idx.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// b2 was deleted and b3 learned while the index was on disk
+	idx = s.initHNSWIndex([]store.BehaviorEmbedding{
+		{BehaviorID: "b1", Embedding: []float32{1, 0}},
+		{BehaviorID: "b3", Embedding: []float32{0, 1}},
+	}, nil, path)
+	if idx.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", idx.Len())
+	}
+	results, err := idx.Search(ctx, []float32{0, 1}, 1)
+	if err != nil || len(results) != 1 || results[0].BehaviorID != "b3" {
+		t.Errorf("Search() = %+v, %v; want b3", results, err)
+	}
+
+	// An unreadable index file is rebuilt from the embeddings
+	if err := os.WriteFile(path, []byte("garbage"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	idx = s.initHNSWIndex([]store.BehaviorEmbedding{{BehaviorID: "b1", Embedding: []float32{1, 0}}}, nil, path)
+	if _, ok := idx.(*vectorindex.HNSWIndex); !ok || idx.Len() != 1 {
+		t.Errorf("initHNSWIndex() on a corrupt file = %T with %d vectors, want a rebuilt HNSW index", idx, idx.Len())
+	}
+}
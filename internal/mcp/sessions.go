@@ -0,0 +1,302 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/ranking"
+)
+
+// maxSessions bounds the client sessions a server tracks at once. When it is
+// reached, the least recently seen session is dropped to make room.
+const maxSessions = 64
+
+// clientSession is what the server tracks for one connected MCP client.
+type clientSession struct {
+	id          string
+	client      string
+	startedAt   time.Time
+	lastSeen    time.Time
+	toolCalls   int
+	activeCalls int
+
+	// recentCalls holds the times of the tool calls made within the
+	// temporal intensity window, oldest first.
+	recentCalls []time.Time
+
+	// Each behavior gets at most 1 implicit confirmation per session (not
+	// per floop_active call). This measures "how many distinct work sessions
+	// involved this behavior" — a far better usefulness proxy than per-call
+	// counting.
+	confirmed map[string]struct{}
+}
+
+// sessionTracker keeps per-client session state, keyed by the SDK session
+// of the client's connection. Calls that carry no session (in-process
+// callers and tests) share one session under the nil key.
+type sessionTracker struct {
+	mu       sync.Mutex
+	sessions map[*sdk.ServerSession]*clientSession
+	counter  int
+	now      func() time.Time
+
+	// onEvict, if set, is called with the ID of each session dropped to make
+	// room, with t.mu held.
+	onEvict func(id string)
+}
+
+// newSessionTracker returns an empty tracker.
+func newSessionTracker() *sessionTracker {
+	return &sessionTracker{
+		sessions: make(map[*sdk.ServerSession]*clientSession),
+		now:      time.Now,
+	}
+}
+
+// start begins tracking ss, if it is not tracked already, and returns its
+// state.
+func (t *sessionTracker) start(ss *sdk.ServerSession) *clientSession {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.getLocked(ss)
+}
+
+// end stops tracking ss and returns its final state, or nil if ss was not
+// tracked.
+func (t *sessionTracker) end(ss *sdk.ServerSession) *clientSession {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cs := t.sessions[ss]
+	delete(t.sessions, ss)
+	return cs
+}
+
+// getLocked returns the state of ss, creating it if needed. t.mu must be
+// held.
+func (t *sessionTracker) getLocked(ss *sdk.ServerSession) *clientSession {
+	if cs, ok := t.sessions[ss]; ok {
+		return cs
+	}
+	if len(t.sessions) >= maxSessions {
+		t.evictOldestLocked()
+	}
+	now := t.now()
+	t.counter++
+	cs := &clientSession{
+		id:        fmt.Sprintf("session-%d", t.counter),
+		client:    sessionClientName(ss),
+		startedAt: now,
+		lastSeen:  now,
+		confirmed: make(map[string]struct{}),
+	}
+	if ss != nil && ss.ID() != "" {
+		cs.id = ss.ID()
+	}
+	t.sessions[ss] = cs
+	return cs
+}
+
+// evictOldestLocked drops the least recently seen session and reports it to
+// onEvict. t.mu must be held.
+func (t *sessionTracker) evictOldestLocked() {
+	var (
+		oldestKey *sdk.ServerSession
+		oldest    *clientSession
+	)
+	for key, cs := range t.sessions {
+		if oldest == nil || cs.lastSeen.Before(oldest.lastSeen) {
+			oldestKey, oldest = key, cs
+		}
+	}
+	if oldest == nil {
+		return
+	}
+	delete(t.sessions, oldestKey)
+	if t.onEvict != nil {
+		t.onEvict(oldest.id)
+	}
+}
+
+// recordCall counts a tool call made in ss and returns the session's ID.
+func (t *sessionTracker) recordCall(ss *sdk.ServerSession) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cs := t.getLocked(ss)
+	cs.toolCalls++
+	cs.lastSeen = t.now()
+	cs.recentCalls = append(cs.trimRecent(cs.lastSeen), cs.lastSeen)
+	return cs.id
+}
+
+// intensity returns the workload intensity of ss: its tool calls within the
+// temporal intensity window relative to busyCalls.
+func (t *sessionTracker) intensity(ss *sdk.ServerSession, busyCalls int) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cs := t.getLocked(ss)
+	cs.recentCalls = cs.trimRecent(t.now())
+	return ranking.SessionIntensity(len(cs.recentCalls), busyCalls)
+}
+
+// trimRecent drops the recent calls that fell out of the intensity window
+// as of now and returns the rest.
+func (cs *clientSession) trimRecent(now time.Time) []time.Time {
+	cutoff := now.Add(-constants.TemporalIntensityWindowMinutes * time.Minute)
+	i := 0
+	for i < len(cs.recentCalls) && cs.recentCalls[i].Before(cutoff) {
+		i++
+	}
+	return cs.recentCalls[i:]
+}
+
+// confirm records a floop_active call in ss that returned ids and marks
+// them confirmed for the session. It returns the ids not confirmed in the
+// session before, in order.
+func (t *sessionTracker) confirm(ss *sdk.ServerSession, ids []string) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cs := t.getLocked(ss)
+	cs.activeCalls++
+	cs.lastSeen = t.now()
+
+	var fresh []string
+	for _, id := range ids {
+		if _, already := cs.confirmed[id]; !already {
+			cs.confirmed[id] = struct{}{}
+			fresh = append(fresh, id)
+		}
+	}
+	return fresh
+}
+
+// activeIDs returns the behaviors floop_active has returned in ss, sorted.
+func (t *sessionTracker) activeIDs(ss *sdk.ServerSession) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cs, ok := t.sessions[ss]
+	if !ok {
+		return nil
+	}
+	ids := make([]string, 0, len(cs.confirmed))
+	for id := range cs.confirmed {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// info describes the session ss, or every tracked session if all is set,
+// oldest first. The caller's session is marked current.
+func (t *sessionTracker) info(ss *sdk.ServerSession, all bool) []SessionInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	current := t.getLocked(ss)
+	if !all {
+		return []SessionInfo{current.info(true)}
+	}
+	infos := make([]SessionInfo, 0, len(t.sessions))
+	for _, cs := range t.sessions {
+		infos = append(infos, cs.info(cs == current))
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].StartedAt != infos[j].StartedAt {
+			return infos[i].StartedAt < infos[j].StartedAt
+		}
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
+}
+
+// info describes the session for tool output.
+func (cs *clientSession) info(current bool) SessionInfo {
+	return SessionInfo{
+		ID:          cs.id,
+		Client:      cs.client,
+		Current:     current,
+		StartedAt:   cs.startedAt.UTC().Format(time.RFC3339),
+		LastSeenAt:  cs.lastSeen.UTC().Format(time.RFC3339),
+		ToolCalls:   cs.toolCalls,
+		ActiveCalls: cs.activeCalls,
+		Confirmed:   len(cs.confirmed),
+	}
+}
+
+// requestSession returns the SDK session a tool call arrived on, or nil.
+func requestSession(req *sdk.CallToolRequest) *sdk.ServerSession {
+	if req == nil {
+		return nil
+	}
+	return req.Session
+}
+
+// sessionStarted is the lifecycle hook run when a client finishes the
+// initialize handshake. It starts tracking the session and arranges for
+// sessionEnded to run when the connection closes.
+func (s *Server) sessionStarted(ss *sdk.ServerSession) {
+	cs := s.sessions.start(ss)
+	s.logger.Info("client session started", "session", cs.id, "client", cs.client)
+	if ss == nil {
+		return
+	}
+	go func() {
+		ss.Wait()
+		s.sessionEnded(ss)
+	}()
+}
+
+// sessionEnded is the lifecycle hook run when a client's connection closes.
+// It drops the session's state, so its implicit confirmations and rate
+// limit budgets start over if the client reconnects.
+func (s *Server) sessionEnded(ss *sdk.ServerSession) {
+	cs := s.sessions.end(ss)
+	if cs == nil {
+		return
+	}
+	s.toolLimiters.Forget(cs.id)
+	s.logger.Info("client session ended", "session", cs.id, "client", cs.client,
+		"tool_calls", cs.toolCalls, "active_calls", cs.activeCalls, "confirmed", len(cs.confirmed))
+}
+
+// sessionMiddleware counts each tool call against the session it arrived on,
+// and rate limits it as a call of that session.
+func (s *Server) sessionMiddleware(next sdk.MethodHandler) sdk.MethodHandler {
+	return func(ctx context.Context, method string, req sdk.Request) (sdk.Result, error) {
+		if method == "tools/call" {
+			ss, _ := req.GetSession().(*sdk.ServerSession)
+			ctx = withRateLimitClient(ctx, s.sessions.recordCall(ss))
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// handleFloopSessionInfo implements the floop_session_info tool.
+func (s *Server) handleFloopSessionInfo(ctx context.Context, req *sdk.CallToolRequest, args FloopSessionInfoInput) (_ *sdk.CallToolResult, _ FloopSessionInfoOutput, retErr error) {
+	start := time.Now()
+	defer func() {
+		s.auditTool("floop_session_info", start, retErr, sanitizeToolParams("floop_session_info", map[string]interface{}{
+			"all": args.All, "rate_limits": args.RateLimits,
+		}), "local")
+	}()
+
+	if err := s.checkRateLimit(ctx, "floop_session_info"); err != nil {
+		return nil, FloopSessionInfoOutput{}, err
+	}
+	if err := s.flush(ctx, args.Flush); err != nil {
+		return nil, FloopSessionInfoOutput{}, err
+	}
+
+	infos := s.sessions.info(requestSession(req), args.All)
+	out := FloopSessionInfoOutput{
+		Sessions: infos,
+		Count:    len(infos),
+	}
+	if args.RateLimits {
+		out.RateLimits = s.toolLimiters.State(rateLimitClient(ctx))
+	}
+	return nil, out, nil
+}
@@ -0,0 +1,206 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestSessionTracker_Confirm(t *testing.T) {
+	tracker := newSessionTracker()
+	a, b := new(sdk.ServerSession), new(sdk.ServerSession)
+
+	if got := tracker.confirm(a, []string{"b-1", "b-2"}); len(got) != 2 {
+		t.Errorf("first confirm in a = %v, want b-1 and b-2", got)
+	}
+	if got := tracker.confirm(a, []string{"b-2", "b-3"}); len(got) != 1 || got[0] != "b-3" {
+		t.Errorf("second confirm in a = %v, want only b-3", got)
+	}
+	// Another session confirms the same behaviors on its own
+	if got := tracker.confirm(b, []string{"b-1"}); len(got) != 1 || got[0] != "b-1" {
+		t.Errorf("confirm in b = %v, want b-1", got)
+	}
+
+	if ids := tracker.activeIDs(a); len(ids) != 3 || ids[0] != "b-1" || ids[2] != "b-3" {
+		t.Errorf("activeIDs(a) = %v, want b-1, b-2, b-3", ids)
+	}
+	if ids := tracker.activeIDs(nil); len(ids) != 0 {
+		t.Errorf("activeIDs(nil) = %v, want none", ids)
+	}
+
+	infos := tracker.info(a, true)
+	if len(infos) != 2 || !infos[0].Current || infos[1].Current {
+		t.Fatalf("info(a, all) = %+v, want a (current) then b", infos)
+	}
+	if infos[0].ActiveCalls != 2 || infos[0].Confirmed != 3 || infos[1].Confirmed != 1 {
+		t.Errorf("info(a, all) = %+v, want 2 calls and 3 confirmed in a, 1 confirmed in b", infos)
+	}
+
+	if cs := tracker.end(a); cs == nil || len(cs.confirmed) != 3 {
+		t.Errorf("end(a) = %+v, want a's final state", cs)
+	}
+	if ids := tracker.activeIDs(a); len(ids) != 0 {
+		t.Errorf("activeIDs(a) after end = %v, want none", ids)
+	}
+}
+
+func TestSessionTracker_EvictsLeastRecentlySeen(t *testing.T) {
+	tracker := newSessionTracker()
+	now := time.Now()
+	tracker.now = func() time.Time { return now }
+	var evicted []string
+	tracker.onEvict = func(id string) { evicted = append(evicted, id) }
+
+	first := new(sdk.ServerSession)
+	tracker.recordCall(first)
+	for i := 1; i < maxSessions; i++ {
+		now = now.Add(time.Second)
+		tracker.recordCall(new(sdk.ServerSession))
+	}
+	// Seen again, first is no longer the stalest
+	now = now.Add(time.Second)
+	tracker.recordCall(first)
+
+	now = now.Add(time.Second)
+	tracker.recordCall(new(sdk.ServerSession))
+	if len(tracker.sessions) != maxSessions {
+		t.Errorf("tracked %d sessions, want %d", len(tracker.sessions), maxSessions)
+	}
+	if _, ok := tracker.sessions[first]; !ok {
+		t.Error("recently seen session was evicted")
+	}
+	if len(evicted) != 1 || evicted[0] != "session-2" {
+		t.Errorf("evicted %v, want [session-2]", evicted)
+	}
+}
+
+func TestSessionTracker_Intensity(t *testing.T) {
+	tracker := newSessionTracker()
+	now := time.Now()
+	tracker.now = func() time.Time { return now }
+	ss := new(sdk.ServerSession)
+
+	if got := tracker.intensity(ss, 4); got != 0 {
+		t.Errorf("intensity before any call = %f, want 0", got)
+	}
+	for i := 0; i < 2; i++ {
+		tracker.recordCall(ss)
+		now = now.Add(time.Minute)
+	}
+	if got := tracker.intensity(ss, 4); got != 0.5 {
+		t.Errorf("intensity after 2 of 4 busy calls = %f, want 0.5", got)
+	}
+	for i := 0; i < 4; i++ {
+		tracker.recordCall(ss)
+	}
+	if got := tracker.intensity(ss, 4); got != 1 {
+		t.Errorf("intensity past busy = %f, want 1", got)
+	}
+
+	// Calls older than the window no longer count
+	now = now.Add(time.Duration(constants.TemporalIntensityWindowMinutes)*time.Minute + time.Second)
+	tracker.recordCall(ss)
+	if got := tracker.intensity(ss, 4); got != 0.25 {
+		t.Errorf("intensity after the window = %f, want 0.25", got)
+	}
+}
+
+func TestHandleFloopSessionInfo_IsolatesClients(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	node := store.Node{
+		ID:   "go-directive",
+		Kind: "behavior",
+		Content: map[string]interface{}{
+			"name":    "go-directive",
+			"kind":    "directive",
+			"content": map[string]interface{}{"canonical": "Use gofmt"},
+		},
+	}
+	if _, err := server.store.AddNode(ctx, node); err != nil {
+		t.Fatalf("Failed to add node: %v", err)
+	}
+
+	connect := func(name string) *sdk.ClientSession {
+		serverTransport, clientTransport := sdk.NewInMemoryTransports()
+		if _, err := server.server.Connect(ctx, serverTransport, nil); err != nil {
+			t.Fatalf("server Connect() error = %v", err)
+		}
+		client := sdk.NewClient(&sdk.Implementation{Name: name, Version: "1.0.0"}, nil)
+		session, err := client.Connect(ctx, clientTransport, nil)
+		if err != nil {
+			t.Fatalf("client Connect() error = %v", err)
+		}
+		return session
+	}
+	call := func(session *sdk.ClientSession, tool string, args map[string]interface{}) *sdk.CallToolResult {
+		t.Helper()
+		result, err := session.CallTool(ctx, &sdk.CallToolParams{Name: tool, Arguments: args})
+		if err != nil {
+			t.Fatalf("CallTool(%s) error = %v", tool, err)
+		}
+		if result.IsError {
+			t.Fatalf("%s returned an error result: %+v", tool, result.Content)
+		}
+		return result
+	}
+
+	alpha := connect("alpha")
+	defer alpha.Close()
+	beta := connect("beta")
+
+	call(alpha, "floop_active", map[string]interface{}{"task": "development"})
+	call(alpha, "floop_active", map[string]interface{}{"task": "development"})
+	call(beta, "floop_active", map[string]interface{}{"task": "development"})
+	server.workerWg.Wait()
+
+	var out FloopSessionInfoOutput
+	result := call(alpha, "floop_session_info", map[string]interface{}{"all": true})
+	data, _ := json.Marshal(result.StructuredContent)
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("decode floop_session_info output: %v", err)
+	}
+	if out.Count != 2 {
+		t.Fatalf("sessions = %+v, want alpha and beta", out.Sessions)
+	}
+	byClient := map[string]SessionInfo{}
+	for _, info := range out.Sessions {
+		byClient[info.Client] = info
+	}
+	if a := byClient["alpha"]; !a.Current || a.ActiveCalls != 2 || a.Confirmed != 1 || a.ToolCalls != 3 {
+		t.Errorf("alpha = %+v, want current, 2 active calls, 1 confirmed, 3 tool calls", a)
+	}
+	if b := byClient["beta"]; b.Current || b.ActiveCalls != 1 || b.Confirmed != 1 {
+		t.Errorf("beta = %+v, want 1 active call and its own confirmation", b)
+	}
+
+	// Closing a connection ends its session
+	beta.Close()
+	tracked := func(client string) bool {
+		server.sessions.mu.Lock()
+		defer server.sessions.mu.Unlock()
+		for _, cs := range server.sessions.sessions {
+			if cs.client == client {
+				return true
+			}
+		}
+		return false
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for tracked("beta") {
+		if time.Now().After(deadline) {
+			t.Fatal("beta's session is still tracked after its connection closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !tracked("alpha") {
+		t.Error("alpha's session ended with beta's")
+	}
+}
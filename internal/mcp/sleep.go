@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/consolidation"
+	"github.com/nvandessel/floop/internal/outcome"
+	"github.com/nvandessel/floop/internal/utils"
+)
+
+// startSleepSchedule runs the sleep phase whenever it is due while the
+// server is up. Runs are recorded in floopDir, so the interval holds across
+// server restarts and manual 'floop consolidate sleep' runs.
+func (s *Server) startSleepSchedule(floopDir string) {
+	interval, err := utils.ParseDuration(s.floopConfig.Consolidation.Sleep.Interval)
+	if err != nil || interval <= 0 {
+		s.logger.Warn("invalid sleep interval, sleep phase disabled", "interval", s.floopConfig.Consolidation.Sleep.Interval)
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(min(interval, time.Hour))
+		defer ticker.Stop()
+		for {
+			s.maybeRunSleepPhase(floopDir, interval)
+			select {
+			case <-s.done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// maybeRunSleepPhase runs the sleep phase in the background if it is due and
+// not already running.
+func (s *Server) maybeRunSleepPhase(floopDir string, interval time.Duration) {
+	due, err := consolidation.SleepDue(floopDir, interval, time.Now())
+	if err != nil {
+		s.logger.Warn("failed to check sleep schedule", "error", err)
+		return
+	}
+	if !due {
+		return
+	}
+
+	s.runBackground("sleep-phase", func() {
+		if !s.sleeping.CompareAndSwap(false, true) {
+			return
+		}
+		defer s.sleeping.Store(false)
+
+		backupDir, err := backup.DefaultBackupDir()
+		if err != nil {
+			s.logger.Warn("sleep phase skipped: no backup directory", "error", err)
+			return
+		}
+		outcomes, err := outcome.LoadStats(filepath.Join(s.root, ".floop"))
+		if err != nil {
+			s.logger.Warn("failed to load outcomes for recalibration", "error", err)
+		}
+		report, err := consolidation.RunSleepPhase(context.Background(), s.store, consolidation.SleepOptions{
+			BackupDir:  backupDir,
+			Retention:  s.retentionPolicy,
+			Outcomes:   outcomes,
+			StaleAfter: time.Duration(s.floopConfig.Decay.DemoteAfterDays) * 24 * time.Hour,
+		})
+		if err != nil {
+			s.logger.Warn("sleep phase failed", "error", err)
+			return
+		}
+		if err := consolidation.RecordSleepReport(floopDir, report); err != nil {
+			s.logger.Warn("failed to record sleep report", "error", err)
+		}
+		s.logger.Info("sleep phase complete", "changes", report.Changes(), "errors", len(report.Errors), "duration", report.Duration)
+		s.emitSleepEvents(report)
+		if report.Changes() > 0 {
+			s.debouncedRefreshPageRank()
+		}
+	})
+}
@@ -0,0 +1,381 @@
+// Package metrics records per-tool call counts, latencies, rate-limit
+// rejections, deadline overruns, background-worker queue depth, and active-set stability for
+// the MCP server, and exposes them in the Prometheus text format or as a
+// JSON snapshot.
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SnapshotFile is the name of the snapshot the MCP server writes under the
+// project's .floop/ directory.
+const SnapshotFile = "metrics.json"
+
+// maxStabilityContexts bounds how many distinct contexts the registry
+// remembers the last active set of.
+const maxStabilityContexts = 256
+
+// LatencyBuckets are the upper bounds, in seconds, of the latency histogram.
+var LatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Registry accumulates tool metrics. It is safe for concurrent use.
+type Registry struct {
+	mu        sync.Mutex
+	tools     map[string]*toolStats
+	dropped   uint64
+	queue     func() (depth, capacity int)
+	lastSets  map[string]map[string]struct{} // last active set per context key
+	stability stabilityStats
+	startedAt time.Time
+	nowFunc   func() time.Time // injectable clock for testing
+}
+
+type stabilityStats struct {
+	comparisons uint64
+	changed     uint64
+	churnSum    float64
+	maxChurn    float64
+}
+
+type toolStats struct {
+	calls       uint64
+	errors      uint64
+	rateLimited uint64
+	timedOut    uint64
+	sum         time.Duration
+	max         time.Duration
+	buckets     []uint64 // per LatencyBuckets, not cumulative
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		tools:     make(map[string]*toolStats),
+		lastSets:  make(map[string]map[string]struct{}),
+		startedAt: time.Now(),
+		nowFunc:   time.Now,
+	}
+}
+
+func (r *Registry) tool(name string) *toolStats {
+	t, ok := r.tools[name]
+	if !ok {
+		t = &toolStats{buckets: make([]uint64, len(LatencyBuckets))}
+		r.tools[name] = t
+	}
+	return t
+}
+
+// ObserveCall records one call of tool that took d, failing if err is set.
+func (r *Registry) ObserveCall(tool string, d time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := r.tool(tool)
+	t.calls++
+	if err != nil {
+		t.errors++
+	}
+	t.sum += d
+	if d > t.max {
+		t.max = d
+	}
+	secs := d.Seconds()
+	for i, le := range LatencyBuckets {
+		if secs <= le {
+			t.buckets[i]++
+			break
+		}
+	}
+}
+
+// ObserveRateLimited records a call of tool rejected by its rate limit. The
+// call itself is still recorded, as an error, by ObserveCall.
+func (r *Registry) ObserveRateLimited(tool string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tool(tool).rateLimited++
+}
+
+// ObserveTimedOut records a call of tool abandoned at its deadline. The
+// call itself is still recorded by ObserveCall when it finishes.
+func (r *Registry) ObserveTimedOut(tool string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tool(tool).timedOut++
+}
+
+// ObserveDropped records a background task skipped because the worker pool
+// was full.
+func (r *Registry) ObserveDropped() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.dropped++
+}
+
+// ObserveActiveSet records the IDs of the behaviors an activation call
+// returned for the context identified by key. A call repeating an earlier
+// call's context is compared with the last one: its churn is the share of
+// the two sets' behaviors not in both (Jaccard distance), so 0 means the
+// same guidance and 1 none in common.
+func (r *Registry) ObserveActiveSet(key string, ids []string) {
+	set := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prev, seen := r.lastSets[key]
+	if !seen && len(r.lastSets) >= maxStabilityContexts {
+		for k := range r.lastSets {
+			delete(r.lastSets, k)
+			break
+		}
+	}
+	r.lastSets[key] = set
+	if !seen {
+		return
+	}
+
+	churn := setChurn(prev, set)
+	r.stability.comparisons++
+	if churn > 0 {
+		r.stability.changed++
+	}
+	r.stability.churnSum += churn
+	r.stability.maxChurn = math.Max(r.stability.maxChurn, churn)
+}
+
+// setChurn returns the Jaccard distance between a and b; two empty sets do
+// not differ.
+func setChurn(a, b map[string]struct{}) float64 {
+	common := 0
+	for id := range a {
+		if _, ok := b[id]; ok {
+			common++
+		}
+	}
+	union := len(a) + len(b) - common
+	if union == 0 {
+		return 0
+	}
+	return 1 - float64(common)/float64(union)
+}
+
+// SetQueue registers the function reporting the background worker queue's
+// current depth and capacity.
+func (r *Registry) SetQueue(fn func() (depth, capacity int)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queue = fn
+}
+
+// Snapshot is a point-in-time copy of the registry.
+type Snapshot struct {
+	StartedAt  time.Time          `json:"started_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+	Tools      []ToolSnapshot     `json:"tools"`
+	Background BackgroundSnapshot `json:"background"`
+	Stability  StabilitySnapshot  `json:"stability"`
+}
+
+// ToolSnapshot holds one tool's metrics. Percentiles are estimated from the
+// latency histogram, as the upper bound of the bucket they fall in.
+type ToolSnapshot struct {
+	Tool        string    `json:"tool"`
+	Calls       uint64    `json:"calls"`
+	Errors      uint64    `json:"errors"`
+	RateLimited uint64    `json:"rate_limited"`
+	TimedOut    uint64    `json:"timed_out"`
+	AvgMs       float64   `json:"avg_ms"`
+	P50Ms       float64   `json:"p50_ms"`
+	P95Ms       float64   `json:"p95_ms"`
+	MaxMs       float64   `json:"max_ms"`
+	TotalMs     float64   `json:"total_ms"`
+	Buckets     []uint64  `json:"buckets"` // per LatencyBuckets, not cumulative
+	BucketsLe   []float64 `json:"buckets_le"`
+}
+
+// BackgroundSnapshot describes the background worker pool.
+type BackgroundSnapshot struct {
+	QueueDepth    int    `json:"queue_depth"`
+	QueueCapacity int    `json:"queue_capacity"`
+	Dropped       uint64 `json:"dropped"`
+}
+
+// StabilitySnapshot describes how much the active set changed between
+// consecutive activation calls with the same context.
+type StabilitySnapshot struct {
+	Comparisons uint64  `json:"comparisons"` // calls that repeated an earlier call's context
+	Changed     uint64  `json:"changed"`     // of those, calls whose active set differed
+	MeanChurn   float64 `json:"mean_churn"`
+	MaxChurn    float64 `json:"max_churn"`
+	Score       float64 `json:"score"` // 1 - MeanChurn; 1 before any comparison
+}
+
+// Snapshot returns the current metrics, with tools sorted by name.
+func (r *Registry) Snapshot() Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snap := Snapshot{
+		StartedAt: r.startedAt.UTC(),
+		UpdatedAt: r.nowFunc().UTC(),
+		Tools:     make([]ToolSnapshot, 0, len(r.tools)),
+	}
+	for name, t := range r.tools {
+		ts := ToolSnapshot{
+			Tool:        name,
+			Calls:       t.calls,
+			Errors:      t.errors,
+			RateLimited: t.rateLimited,
+			TimedOut:    t.timedOut,
+			MaxMs:       durationMs(t.max),
+			TotalMs:     durationMs(t.sum),
+			Buckets:     append([]uint64(nil), t.buckets...),
+			BucketsLe:   LatencyBuckets,
+		}
+		if t.calls > 0 {
+			ts.AvgMs = ts.TotalMs / float64(t.calls)
+			ts.P50Ms = t.quantileMs(0.5)
+			ts.P95Ms = t.quantileMs(0.95)
+		}
+		snap.Tools = append(snap.Tools, ts)
+	}
+	sort.Slice(snap.Tools, func(i, j int) bool { return snap.Tools[i].Tool < snap.Tools[j].Tool })
+
+	snap.Background.Dropped = r.dropped
+	if r.queue != nil {
+		snap.Background.QueueDepth, snap.Background.QueueCapacity = r.queue()
+	}
+
+	st := r.stability
+	snap.Stability = StabilitySnapshot{Comparisons: st.comparisons, Changed: st.changed, MaxChurn: st.maxChurn, Score: 1}
+	if st.comparisons > 0 {
+		snap.Stability.MeanChurn = st.churnSum / float64(st.comparisons)
+		snap.Stability.Score = 1 - snap.Stability.MeanChurn
+	}
+	return snap
+}
+
+// quantileMs estimates quantile q of the call latencies in milliseconds.
+// Calls slower than the largest bucket report the maximum latency seen.
+func (t *toolStats) quantileMs(q float64) float64 {
+	rank := uint64(math.Ceil(q * float64(t.calls)))
+	var seen uint64
+	for i, n := range t.buckets {
+		seen += n
+		if seen >= rank {
+			return math.Min(LatencyBuckets[i]*1000, durationMs(t.max))
+		}
+	}
+	return durationMs(t.max)
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition
+// format.
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	snap := r.Snapshot()
+	var b strings.Builder
+
+	counter := func(name, help string, value func(ToolSnapshot) uint64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+		for _, t := range snap.Tools {
+			fmt.Fprintf(&b, "%s{tool=%q} %d\n", name, t.Tool, value(t))
+		}
+	}
+	counter("floop_tool_calls_total", "MCP tool calls.", func(t ToolSnapshot) uint64 { return t.Calls })
+	counter("floop_tool_errors_total", "MCP tool calls that returned an error.", func(t ToolSnapshot) uint64 { return t.Errors })
+	counter("floop_tool_rate_limited_total", "MCP tool calls rejected by a rate limit.", func(t ToolSnapshot) uint64 { return t.RateLimited })
+	counter("floop_tool_timed_out_total", "MCP tool calls abandoned at their deadline.", func(t ToolSnapshot) uint64 { return t.TimedOut })
+
+	const hist = "floop_tool_duration_seconds"
+	fmt.Fprintf(&b, "# HELP %s MCP tool call latency.\n# TYPE %s histogram\n", hist, hist)
+	for _, t := range snap.Tools {
+		var cumulative uint64
+		for i, le := range LatencyBuckets {
+			cumulative += t.Buckets[i]
+			fmt.Fprintf(&b, "%s_bucket{tool=%q,le=%q} %d\n", hist, t.Tool, formatFloat(le), cumulative)
+		}
+		fmt.Fprintf(&b, "%s_bucket{tool=%q,le=\"+Inf\"} %d\n", hist, t.Tool, t.Calls)
+		fmt.Fprintf(&b, "%s_sum{tool=%q} %s\n", hist, t.Tool, formatFloat(t.TotalMs/1000))
+		fmt.Fprintf(&b, "%s_count{tool=%q} %d\n", hist, t.Tool, t.Calls)
+	}
+
+	fmt.Fprintf(&b, "# HELP floop_background_queue_depth Background tasks currently running.\n# TYPE floop_background_queue_depth gauge\n")
+	fmt.Fprintf(&b, "floop_background_queue_depth %d\n", snap.Background.QueueDepth)
+	fmt.Fprintf(&b, "# HELP floop_background_queue_capacity Background worker pool size.\n# TYPE floop_background_queue_capacity gauge\n")
+	fmt.Fprintf(&b, "floop_background_queue_capacity %d\n", snap.Background.QueueCapacity)
+	fmt.Fprintf(&b, "# HELP floop_background_dropped_total Background tasks skipped because the pool was full.\n# TYPE floop_background_dropped_total counter\n")
+	fmt.Fprintf(&b, "floop_background_dropped_total %d\n", snap.Background.Dropped)
+
+	fmt.Fprintf(&b, "# HELP floop_active_set_comparisons_total Activation calls compared with the last call for the same context.\n# TYPE floop_active_set_comparisons_total counter\n")
+	fmt.Fprintf(&b, "floop_active_set_comparisons_total %d\n", snap.Stability.Comparisons)
+	fmt.Fprintf(&b, "# HELP floop_active_set_changed_total Compared activation calls whose active set changed.\n# TYPE floop_active_set_changed_total counter\n")
+	fmt.Fprintf(&b, "floop_active_set_changed_total %d\n", snap.Stability.Changed)
+	fmt.Fprintf(&b, "# HELP floop_active_set_stability One minus the mean churn between active sets for the same context.\n# TYPE floop_active_set_stability gauge\n")
+	fmt.Fprintf(&b, "floop_active_set_stability %s\n", formatFloat(snap.Stability.Score))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := r.WritePrometheus(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// WriteSnapshotFile writes the current metrics to path atomically via temp
+// file + rename.
+func (r *Registry) WriteSnapshotFile(path string) error {
+	data, err := json.MarshalIndent(r.Snapshot(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal metrics: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	return nil
+}
+
+// ReadSnapshotFile reads a snapshot written by WriteSnapshotFile.
+func ReadSnapshotFile(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("failed to parse metrics %s: %w", path, err)
+	}
+	return &snap, nil
+}
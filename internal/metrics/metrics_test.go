@@ -0,0 +1,192 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistry_Snapshot(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveCall("floop_learn", 3*time.Millisecond, nil)
+	for i := 0; i < 18; i++ {
+		r.ObserveCall("floop_active", 20*time.Millisecond, nil)
+	}
+	r.ObserveCall("floop_active", 80*time.Millisecond, nil)
+	r.ObserveCall("floop_active", 30*time.Second, errors.New("timeout"))
+	r.ObserveRateLimited("floop_active")
+	r.ObserveTimedOut("floop_active")
+	r.ObserveDropped()
+	r.SetQueue(func() (int, int) { return 2, 10 })
+
+	snap := r.Snapshot()
+	if len(snap.Tools) != 2 || snap.Tools[0].Tool != "floop_active" || snap.Tools[1].Tool != "floop_learn" {
+		t.Fatalf("tools = %+v, want floop_active then floop_learn", snap.Tools)
+	}
+
+	active := snap.Tools[0]
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"calls", float64(active.Calls), 20},
+		{"errors", float64(active.Errors), 1},
+		{"rate limited", float64(active.RateLimited), 1},
+		{"timed out", float64(active.TimedOut), 1},
+		{"p50", active.P50Ms, 25},
+		{"p95", active.P95Ms, 100},
+		{"max", active.MaxMs, 30000},
+		{"learn p50 capped at max", snap.Tools[1].P50Ms, 3},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	if want := (18*20 + 80 + 30000) / 20.0; active.AvgMs != want {
+		t.Errorf("avg = %v, want %v", active.AvgMs, want)
+	}
+	if snap.Background != (BackgroundSnapshot{QueueDepth: 2, QueueCapacity: 10, Dropped: 1}) {
+		t.Errorf("background = %+v", snap.Background)
+	}
+}
+
+func TestRegistry_ObserveActiveSet(t *testing.T) {
+	r := NewRegistry()
+	if st := r.Snapshot().Stability; st.Score != 1 || st.Comparisons != 0 {
+		t.Errorf("initial stability = %+v, want score 1 with no comparisons", st)
+	}
+
+	r.ObserveActiveSet("go", []string{"a", "b"})
+	r.ObserveActiveSet("py", []string{"c"})
+	r.ObserveActiveSet("go", []string{"b", "a"})
+	r.ObserveActiveSet("go", []string{"a", "c"})
+	r.ObserveActiveSet("py", nil)
+
+	st := r.Snapshot().Stability
+	// Comparisons: go unchanged (0), go {a,b}->{a,c} (2/3), py {c}->{} (1)
+	if st.Comparisons != 3 || st.Changed != 2 {
+		t.Errorf("comparisons/changed = %d/%d, want 3/2", st.Comparisons, st.Changed)
+	}
+	if want := (0 + 2.0/3 + 1) / 3; math.Abs(st.MeanChurn-want) > 1e-9 || math.Abs(st.Score-(1-want)) > 1e-9 {
+		t.Errorf("mean churn/score = %v/%v, want %v/%v", st.MeanChurn, st.Score, want, 1-want)
+	}
+	if st.MaxChurn != 1 {
+		t.Errorf("max churn = %v, want 1", st.MaxChurn)
+	}
+
+	empty := NewRegistry()
+	empty.ObserveActiveSet("none", nil)
+	empty.ObserveActiveSet("none", nil)
+	if st := empty.Snapshot().Stability; st.Changed != 0 || st.Score != 1 {
+		t.Errorf("empty sets = %+v, want unchanged", st)
+	}
+}
+
+func TestRegistry_ObserveActiveSet_BoundsContexts(t *testing.T) {
+	r := NewRegistry()
+	for i := 0; i < maxStabilityContexts+10; i++ {
+		r.ObserveActiveSet(fmt.Sprintf("ctx-%d", i), []string{"a"})
+	}
+	if len(r.lastSets) != maxStabilityContexts {
+		t.Errorf("remembered %d contexts, want at most %d", len(r.lastSets), maxStabilityContexts)
+	}
+}
+
+func TestRegistry_WritePrometheus(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveCall("floop_active", 20*time.Millisecond, nil)
+	r.ObserveCall("floop_active", 2*time.Second, errors.New("boom"))
+	r.ObserveRateLimited("floop_active")
+	r.ObserveTimedOut("floop_active")
+	r.SetQueue(func() (int, int) { return 1, 10 })
+
+	var b strings.Builder
+	if err := r.WritePrometheus(&b); err != nil {
+		t.Fatalf("WritePrometheus() error = %v", err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		"# TYPE floop_tool_calls_total counter\n",
+		`floop_tool_calls_total{tool="floop_active"} 2`,
+		`floop_tool_errors_total{tool="floop_active"} 1`,
+		`floop_tool_rate_limited_total{tool="floop_active"} 1`,
+		`floop_tool_timed_out_total{tool="floop_active"} 1`,
+		"# TYPE floop_tool_duration_seconds histogram\n",
+		`floop_tool_duration_seconds_bucket{tool="floop_active",le="0.01"} 0`,
+		`floop_tool_duration_seconds_bucket{tool="floop_active",le="0.025"} 1`,
+		`floop_tool_duration_seconds_bucket{tool="floop_active",le="2.5"} 2`,
+		`floop_tool_duration_seconds_bucket{tool="floop_active",le="+Inf"} 2`,
+		`floop_tool_duration_seconds_sum{tool="floop_active"} 2.02`,
+		`floop_tool_duration_seconds_count{tool="floop_active"} 2`,
+		"floop_background_queue_depth 1\n",
+		"floop_background_queue_capacity 10\n",
+		"floop_background_dropped_total 0\n",
+		"floop_active_set_comparisons_total 0\n",
+		"floop_active_set_stability 1\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveCall("floop_list", time.Millisecond, nil)
+
+	srv := httptest.NewServer(r.Handler())
+	defer srv.Close()
+
+	resp, err := srv.Client().Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics error = %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q, want text/plain", ct)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), `floop_tool_calls_total{tool="floop_list"} 1`) {
+		t.Errorf("body missing floop_list calls:\n%s", body)
+	}
+}
+
+func TestSnapshotFile_RoundTrip(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveCall("floop_learn", 40*time.Millisecond, nil)
+	path := filepath.Join(t.TempDir(), SnapshotFile)
+
+	if err := r.WriteSnapshotFile(path); err != nil {
+		t.Fatalf("WriteSnapshotFile() error = %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temp file left behind: %v", err)
+	}
+
+	snap, err := ReadSnapshotFile(path)
+	if err != nil {
+		t.Fatalf("ReadSnapshotFile() error = %v", err)
+	}
+	if len(snap.Tools) != 1 || snap.Tools[0].Tool != "floop_learn" || snap.Tools[0].Calls != 1 {
+		t.Errorf("tools = %+v, want one floop_learn call", snap.Tools)
+	}
+
+	if _, err := ReadSnapshotFile(filepath.Join(t.TempDir(), "missing.json")); !os.IsNotExist(err) {
+		t.Errorf("missing file error = %v, want not exist", err)
+	}
+	os.WriteFile(path, []byte("{"), 0600)
+	if _, err := ReadSnapshotFile(path); err == nil {
+		t.Error("expected error for corrupt snapshot")
+	}
+}
@@ -21,9 +21,11 @@ const (
 // Behavior status kinds represent lifecycle states set by curation commands.
 // Values are defined in internal/store as NodeKind constants.
 const (
-	BehaviorKindForgotten  BehaviorKind = BehaviorKind(store.NodeKindForgotten)
-	BehaviorKindDeprecated BehaviorKind = BehaviorKind(store.NodeKindDeprecated)
-	BehaviorKindMerged     BehaviorKind = BehaviorKind(store.NodeKindMerged)
+	BehaviorKindForgotten   BehaviorKind = BehaviorKind(store.NodeKindForgotten)
+	BehaviorKindDeprecated  BehaviorKind = BehaviorKind(store.NodeKindDeprecated)
+	BehaviorKindMerged      BehaviorKind = BehaviorKind(store.NodeKindMerged)
+	BehaviorKindRetired     BehaviorKind = BehaviorKind(store.NodeKindRetired)
+	BehaviorKindQuarantined BehaviorKind = BehaviorKind(store.NodeKindQuarantined)
 )
 
 // MemoryType classifies behaviors by cognitive category.
@@ -117,14 +119,33 @@ type Behavior struct {
 	// Priority for conflict resolution (higher wins)
 	Priority int `json:"priority" yaml:"priority"`
 
+	// ExpiresAt time-boxes the behavior ("during the v2 migration, always...").
+	// After it passes the behavior no longer activates, and the expiry sweep
+	// deprecates it.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" yaml:"expires_at,omitempty"`
+
+	// Pinned behaviors are always included in a compiled prompt, even past
+	// its token budget.
+	Pinned bool `json:"pinned,omitempty" yaml:"pinned,omitempty"`
+
 	// Graph relationships (IDs of other behaviors)
-	Requires  []string         `json:"requires,omitempty" yaml:"requires,omitempty"`   // Hard dependencies
-	Overrides []string         `json:"overrides,omitempty" yaml:"overrides,omitempty"` // This supersedes those
-	Conflicts []string         `json:"conflicts,omitempty" yaml:"conflicts,omitempty"` // Mutual exclusion
-	SimilarTo []SimilarityLink `json:"similar_to,omitempty" yaml:"similar_to,omitempty"`
+	Requires    []string         `json:"requires,omitempty" yaml:"requires,omitempty"`       // Hard dependencies
+	Overrides   []string         `json:"overrides,omitempty" yaml:"overrides,omitempty"`     // This supersedes those
+	Conflicts   []string         `json:"conflicts,omitempty" yaml:"conflicts,omitempty"`     // Mutual exclusion
+	Specializes []string         `json:"specializes,omitempty" yaml:"specializes,omitempty"` // Generalized parents (from specializes edges)
+	SimilarTo   []SimilarityLink `json:"similar_to,omitempty" yaml:"similar_to,omitempty"`
 
 	// Statistics (updated over time)
 	Stats BehaviorStats `json:"stats" yaml:"stats"`
+
+	// Origin is the store the behavior was read from ("local", "global",
+	// "scope:<name>", or "policy"). Set on read, never persisted.
+	Origin string `json:"origin,omitempty" yaml:"-"`
+}
+
+// IsExpired reports whether the behavior has an expiry at or before now.
+func (b *Behavior) IsExpired(now time.Time) bool {
+	return b.ExpiresAt != nil && !b.ExpiresAt.After(now)
 }
 
 // SimilarityLink represents a similarity relationship with a score
@@ -1,6 +1,11 @@
 package models
 
-import "testing"
+import (
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
 
 func TestNewBehaviorKinds(t *testing.T) {
 	tests := []struct {
@@ -23,3 +28,75 @@ func TestNewBehaviorKinds(t *testing.T) {
 		})
 	}
 }
+
+func TestBehavior_ExpiresAt(t *testing.T) {
+	expires := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	b := Behavior{ID: "b1", Kind: BehaviorKindDirective, ExpiresAt: &expires}
+
+	if b.IsExpired(expires.Add(-time.Second)) {
+		t.Error("behavior should not be expired before its expiry")
+	}
+	if !b.IsExpired(expires) {
+		t.Error("behavior should be expired at its expiry")
+	}
+	if (&Behavior{}).IsExpired(expires) {
+		t.Error("behavior without expiry should never expire")
+	}
+
+	// Round-trips through the RFC3339 string the SQLite store persists
+	got := NodeToBehavior(BehaviorToNode(&b))
+	if got.ExpiresAt == nil || !got.ExpiresAt.Equal(expires) {
+		t.Errorf("round-tripped ExpiresAt = %v, want %v", got.ExpiresAt, expires)
+	}
+
+	// valid_until is accepted as an alias
+	node := store.Node{ID: "b2", Metadata: map[string]interface{}{"valid_until": expires}}
+	if got := NodeToBehavior(node); got.ExpiresAt == nil || !got.ExpiresAt.Equal(expires) {
+		t.Errorf("valid_until ExpiresAt = %v, want %v", got.ExpiresAt, expires)
+	}
+}
+
+func TestBehavior_PinnedRoundTrip(t *testing.T) {
+	node := BehaviorToNode(&Behavior{ID: "b1", Kind: BehaviorKindDirective, Pinned: true})
+	if node.Metadata["pinned"] != true {
+		t.Fatalf("BehaviorToNode() pinned = %v, want true", node.Metadata["pinned"])
+	}
+	if !NodeToBehavior(node).Pinned {
+		t.Error("round-tripped Pinned = false, want true")
+	}
+	if _, ok := BehaviorToNode(&Behavior{ID: "b2"}).Metadata["pinned"]; ok {
+		t.Error("unpinned behavior should not set pinned metadata")
+	}
+}
+
+func TestBehavior_OriginRoundTrip(t *testing.T) {
+	node := store.Node{ID: "b1", Kind: store.NodeKindBehavior, Origin: store.OriginGlobal}
+	b := NodeToBehavior(node)
+	if b.Origin != "global" {
+		t.Fatalf("NodeToBehavior() origin = %q, want global", b.Origin)
+	}
+	if got := BehaviorToNode(&b).Origin; got != store.OriginGlobal {
+		t.Errorf("BehaviorToNode() origin = %q, want global", got)
+	}
+}
+
+func TestBehavior_ProvenanceRoundTrip(t *testing.T) {
+	node := store.Node{ID: "b1", Metadata: map[string]interface{}{
+		"provenance": map[string]interface{}{
+			"source_type":     "learned",
+			"correction_id":   "c-1",
+			"package":         "team/go",
+			"package_version": "1.2.0",
+			"importer":        "adr",
+			"source_path":     "docs/adr/0001-use-go.md",
+		},
+	}}
+	b := NodeToBehavior(node)
+	if b.Provenance.CorrectionID != "c-1" || b.Provenance.Package != "team/go" || b.Provenance.PackageVersion != "1.2.0" ||
+		b.Provenance.Importer != "adr" || b.Provenance.SourcePath != "docs/adr/0001-use-go.md" {
+		t.Fatalf("NodeToBehavior() provenance = %+v", b.Provenance)
+	}
+	if got := NodeToBehavior(BehaviorToNode(&b)).Provenance; got.CorrectionID != "c-1" {
+		t.Errorf("round-tripped CorrectionID = %q, want c-1", got.CorrectionID)
+	}
+}
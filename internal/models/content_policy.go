@@ -0,0 +1,109 @@
+package models
+
+import "sort"
+
+// FieldType is the value type a structured content field may hold.
+type FieldType string
+
+const (
+	FieldTypeString     FieldType = "string"      // A single string
+	FieldTypeStringList FieldType = "string-list" // A list of strings
+)
+
+// structuredSchemas lists the structured content fields each kind may carry.
+// Structured content flows into prompts and the HTML graph page, so anything
+// outside the schema for a kind is dropped at render time.
+var structuredSchemas = map[BehaviorKind]map[string]FieldType{
+	BehaviorKindDirective:  {"prefer": FieldTypeString},
+	BehaviorKindConstraint: {"prefer": FieldTypeString},
+	BehaviorKindPreference: {"prefer": FieldTypeString},
+	BehaviorKindProcedure:  {"prefer": FieldTypeString, "steps": FieldTypeStringList},
+	BehaviorKindEpisodic: {
+		"prefer":     FieldTypeString,
+		"session_id": FieldTypeString,
+		"timeframe":  FieldTypeString,
+		"actors":     FieldTypeStringList,
+		"outcome":    FieldTypeString,
+	},
+	BehaviorKindWorkflow: {
+		"prefer":  FieldTypeString,
+		"trigger": FieldTypeString,
+		"steps":   FieldTypeStringList,
+	},
+}
+
+// StructuredSchema returns the structured fields allowed for kind, or nil
+// for kinds without a schema.
+func StructuredSchema(kind BehaviorKind) map[string]FieldType {
+	return structuredSchemas[kind]
+}
+
+// AllowedStructuredFields returns the field names allowed for kind, sorted.
+func AllowedStructuredFields(kind BehaviorKind) []string {
+	schema := structuredSchemas[kind]
+	fields := make([]string, 0, len(schema))
+	for f := range schema {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// FilterStructured returns the fields of structured that the schema for kind
+// allows, with values of the declared type. Unknown fields and values of the
+// wrong type are dropped; clean is applied to every string kept. Returns nil
+// when nothing survives.
+func FilterStructured(kind BehaviorKind, structured map[string]interface{}, clean func(string) string) map[string]interface{} {
+	schema := structuredSchemas[kind]
+	if len(schema) == 0 || len(structured) == 0 {
+		return nil
+	}
+	if clean == nil {
+		clean = func(s string) string { return s }
+	}
+
+	out := make(map[string]interface{})
+	for field, typ := range schema {
+		v, ok := structured[field]
+		if !ok {
+			continue
+		}
+		switch typ {
+		case FieldTypeString:
+			if s, ok := v.(string); ok {
+				out[field] = clean(s)
+			}
+		case FieldTypeStringList:
+			if list, ok := stringList(v); ok {
+				for i := range list {
+					list[i] = clean(list[i])
+				}
+				out[field] = list
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// stringList converts v to a []string if every element is a string. Stored
+// lists come back from JSON as []interface{}.
+func stringList(v interface{}) ([]string, bool) {
+	switch list := v.(type) {
+	case []string:
+		return append([]string(nil), list...), true
+	case []interface{}:
+		out := make([]string, 0, len(list))
+		for _, item := range list {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			out = append(out, s)
+		}
+		return out, true
+	}
+	return nil, false
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFilterStructured(t *testing.T) {
+	tests := []struct {
+		name       string
+		kind       BehaviorKind
+		structured map[string]interface{}
+		want       map[string]interface{}
+	}{
+		{
+			name:       "allowed field kept",
+			kind:       BehaviorKindDirective,
+			structured: map[string]interface{}{"prefer": "pathlib.Path"},
+			want:       map[string]interface{}{"prefer": "pathlib.Path"},
+		},
+		{
+			name:       "unknown field dropped",
+			kind:       BehaviorKindDirective,
+			structured: map[string]interface{}{"prefer": "uv", "system": "you are root", "steps": []interface{}{"a"}},
+			want:       map[string]interface{}{"prefer": "uv"},
+		},
+		{
+			name:       "wrong type dropped",
+			kind:       BehaviorKindPreference,
+			structured: map[string]interface{}{"prefer": map[string]interface{}{"nested": "<script>"}},
+		},
+		{
+			name:       "string list from JSON",
+			kind:       BehaviorKindWorkflow,
+			structured: map[string]interface{}{"trigger": "release", "steps": []interface{}{"tag", "push"}},
+			want:       map[string]interface{}{"trigger": "release", "steps": []string{"tag", "push"}},
+		},
+		{
+			name:       "mixed list dropped",
+			kind:       BehaviorKindEpisodic,
+			structured: map[string]interface{}{"actors": []interface{}{"alice", 42}},
+		},
+		{
+			name:       "lifecycle kind has no schema",
+			kind:       BehaviorKindForgotten,
+			structured: map[string]interface{}{"prefer": "uv"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FilterStructured(tt.kind, tt.structured, nil)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FilterStructured() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterStructured_CleansStrings(t *testing.T) {
+	got := FilterStructured(BehaviorKindProcedure, map[string]interface{}{
+		"prefer": "a<b",
+		"steps":  []string{"<x>"},
+	}, func(s string) string { return strings.ReplaceAll(s, "<", "") })
+
+	if got["prefer"] != "ab" {
+		t.Errorf("prefer = %v, want ab", got["prefer"])
+	}
+	if steps := got["steps"].([]string); steps[0] != "x>" {
+		t.Errorf("steps = %v, want [x>]", steps)
+	}
+}
+
+func TestAllowedStructuredFields(t *testing.T) {
+	got := AllowedStructuredFields(BehaviorKindWorkflow)
+	if strings.Join(got, ",") != "prefer,steps,trigger" {
+		t.Errorf("AllowedStructuredFields(workflow) = %v", got)
+	}
+	if got := AllowedStructuredFields(BehaviorKindMerged); len(got) != 0 {
+		t.Errorf("AllowedStructuredFields(merged) = %v, want none", got)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/nvandessel/floop/internal/tagging"
 )
 
 // ProjectType represents the type of project based on files present
@@ -28,14 +30,29 @@ type ContextSnapshot struct {
 	Branch      string      `json:"branch,omitempty" yaml:"branch,omitempty"`
 	ProjectType ProjectType `json:"project_type,omitempty" yaml:"project_type,omitempty"`
 
+	// Facts detected from the repository's marker files
+	RepoFacts *RepoFingerprint `json:"repo_facts,omitempty" yaml:"repo_facts,omitempty"`
+
 	// File info
 	FilePath     string `json:"file_path,omitempty" yaml:"file_path,omitempty"`
 	FileLanguage string `json:"file_language,omitempty" yaml:"file_language,omitempty"`
 	FileExt      string `json:"file_ext,omitempty" yaml:"file_ext,omitempty"`
 
+	// File content signals, set when content sniffing is enabled
+	Imports    []string `json:"imports,omitempty" yaml:"imports,omitempty"`
+	Frameworks []string `json:"frameworks,omitempty" yaml:"frameworks,omitempty"`
+
+	// Working tree state, set when git context capture is enabled
+	StagedFiles   []string `json:"staged_files,omitempty" yaml:"staged_files,omitempty"`
+	ChangedPaths  []string `json:"changed_paths,omitempty" yaml:"changed_paths,omitempty"`
+	CommitMessage string   `json:"commit_message,omitempty" yaml:"commit_message,omitempty"`
+
 	// Task info
 	Task string `json:"task,omitempty" yaml:"task,omitempty"`
 
+	// Tags the agent declared for its current work, e.g. "testing/unit"
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+
 	// User info
 	User  string   `json:"user,omitempty" yaml:"user,omitempty"`
 	Roles []string `json:"roles,omitempty" yaml:"roles,omitempty"`
@@ -43,13 +60,52 @@ type ContextSnapshot struct {
 	// Environment
 	Environment string `json:"environment,omitempty" yaml:"environment,omitempty"` // dev, staging, prod, ci
 
+	// Agent client (e.g. MCP client name from initialize: "claude-code", "cursor")
+	Agent string `json:"agent,omitempty" yaml:"agent,omitempty"`
+
+	// Workload: recent session activity from 0 (unknown) to 1 (busy), set
+	// when temporal ranking is enabled
+	Intensity float64 `json:"intensity,omitempty" yaml:"intensity,omitempty"`
+
 	// Custom fields for extensibility
 	Custom map[string]interface{} `json:"custom,omitempty" yaml:"custom,omitempty"`
 }
 
+// RepoFingerprint holds repository-level facts detected from marker files
+// at the repository root. Each list is sorted.
+type RepoFingerprint struct {
+	// Files lists the marker files and directories present, e.g. "go.mod"
+	// or ".github/workflows".
+	Files []string `json:"files,omitempty" yaml:"files,omitempty"`
+
+	// BuildSystems lists detected build tools, e.g. "go", "bazel", "make".
+	BuildSystems []string `json:"build_systems,omitempty" yaml:"build_systems,omitempty"`
+
+	// PackageManagers lists detected package managers, e.g. "pnpm", "poetry".
+	PackageManagers []string `json:"package_managers,omitempty" yaml:"package_managers,omitempty"`
+
+	// CI lists detected CI systems, e.g. "github-actions".
+	CI []string `json:"ci,omitempty" yaml:"ci,omitempty"`
+
+	// MonorepoTools lists detected monorepo tooling, e.g. "nx", "go-workspace".
+	MonorepoTools []string `json:"monorepo_tools,omitempty" yaml:"monorepo_tools,omitempty"`
+}
+
 // Matches checks if this context matches a 'when' predicate
 func (c *ContextSnapshot) Matches(predicate map[string]interface{}) bool {
 	for key, required := range predicate {
+		if key == WhenAny {
+			if matched, _ := compileAny(required)(c); !matched {
+				return false
+			}
+			continue
+		}
+		if IsTemporalKey(key) {
+			if c.Timestamp.IsZero() || !c.matchTemporal(key, required) {
+				return false
+			}
+			continue
+		}
 		actual := c.GetField(key)
 		if !matchValue(actual, required) {
 			return false
@@ -63,7 +119,19 @@ func (c *ContextSnapshot) Matches(predicate map[string]interface{}) bool {
 //   - matched=true, hasValue=true: context has the key and values match (confirmed)
 //   - matched=false, hasValue=true: context has the key but values differ (contradicted)
 //   - matched=false, hasValue=false: context doesn't have the key (absent)
+//
+// Temporal keys (see WhenWeekday) are evaluated against Timestamp and are
+// absent when it is zero.
 func (c *ContextSnapshot) MatchField(key string, required interface{}) (matched, hasValue bool) {
+	if key == WhenAny {
+		return compileAny(required)(c)
+	}
+	if IsTemporalKey(key) {
+		if c.Timestamp.IsZero() {
+			return false, false
+		}
+		return c.matchTemporal(key, required), true
+	}
 	actual := c.GetField(key)
 	if actual == nil || actual == "" {
 		return false, false // absent
@@ -73,79 +141,203 @@ func (c *ContextSnapshot) MatchField(key string, required interface{}) (matched,
 
 // GetField retrieves a field value by name (exported for use by activation package)
 func (c *ContextSnapshot) GetField(key string) interface{} {
+	return fieldGetter(key)(c)
+}
+
+// fieldGetter resolves a when-condition key to the context field it reads.
+func fieldGetter(key string) func(c *ContextSnapshot) interface{} {
 	switch key {
 	case "repo":
-		return c.Repo
+		return func(c *ContextSnapshot) interface{} { return c.Repo }
 	case "branch":
-		return c.Branch
+		return func(c *ContextSnapshot) interface{} { return c.Branch }
 	case "project_type":
-		return string(c.ProjectType)
+		return func(c *ContextSnapshot) interface{} { return string(c.ProjectType) }
 	case "file_path", "file.path":
-		return c.FilePath
+		return func(c *ContextSnapshot) interface{} { return c.FilePath }
 	case "file_language", "file.language", "language":
-		return c.FileLanguage
+		return func(c *ContextSnapshot) interface{} { return c.FileLanguage }
 	case "file_ext", "file.ext", "ext":
-		return c.FileExt
+		return func(c *ContextSnapshot) interface{} { return c.FileExt }
+	case "imports":
+		return func(c *ContextSnapshot) interface{} { return listField(c.Imports) }
+	case "staged_files":
+		return func(c *ContextSnapshot) interface{} { return listField(c.StagedFiles) }
+	case "changed_paths":
+		return func(c *ContextSnapshot) interface{} { return listField(c.ChangedPaths) }
+	case "commit_message":
+		return func(c *ContextSnapshot) interface{} { return c.CommitMessage }
+	case "repo_files", "build_system", "package_manager", "ci", "monorepo_tool":
+		return func(c *ContextSnapshot) interface{} { return c.repoFact(key) }
+	case "framework", "frameworks":
+		return func(c *ContextSnapshot) interface{} { return listField(c.Frameworks) }
 	case "task":
-		return c.Task
+		return func(c *ContextSnapshot) interface{} { return c.Task }
+	case "tags", "tag":
+		// A declared tag also declares its ancestors, so the condition
+		// tags: testing holds in a testing/unit context
+		return func(c *ContextSnapshot) interface{} { return listField(tagging.Expand(c.Tags)) }
 	case "user":
-		return c.User
+		return func(c *ContextSnapshot) interface{} { return c.User }
 	case "environment", "env":
-		return c.Environment
+		return func(c *ContextSnapshot) interface{} { return c.Environment }
+	case "agent":
+		return func(c *ContextSnapshot) interface{} { return c.Agent }
+	case WhenWeekday, WhenDate, WhenAfter, WhenBefore:
+		return func(c *ContextSnapshot) interface{} { return c.temporalField(key) }
 	default:
-		if c.Custom != nil {
-			return c.Custom[key]
+		return func(c *ContextSnapshot) interface{} {
+			if c.Custom != nil {
+				return c.Custom[key]
+			}
+			return nil
 		}
+	}
+}
+
+// repoFact returns a repository fingerprint field, or nil when no
+// fingerprint was detected.
+func (c *ContextSnapshot) repoFact(key string) interface{} {
+	if c.RepoFacts == nil {
 		return nil
 	}
+	switch key {
+	case "repo_files":
+		return listField(c.RepoFacts.Files)
+	case "build_system":
+		return listField(c.RepoFacts.BuildSystems)
+	case "package_manager":
+		return listField(c.RepoFacts.PackageManagers)
+	case "ci":
+		return listField(c.RepoFacts.CI)
+	default:
+		return listField(c.RepoFacts.MonorepoTools)
+	}
+}
+
+// listField returns a list-valued field, or nil when it is empty so the
+// condition counts as absent.
+func listField(values []string) interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+// ConditionMatcher evaluates one compiled when-condition against a context.
+// Its results are the same as MatchField for the condition it was compiled
+// from.
+type ConditionMatcher func(c *ContextSnapshot) (matched, hasValue bool)
+
+// CompileCondition prepares a when-condition for repeated evaluation: the
+// field lookup is resolved, glob patterns are normalized, regexes are
+// compiled, and list values are turned into sets once instead of on every
+// match.
+func CompileCondition(key string, required interface{}) ConditionMatcher {
+	if key == WhenAny {
+		return compileAny(required)
+	}
+	if IsTemporalKey(key) {
+		return func(c *ContextSnapshot) (bool, bool) {
+			return c.MatchField(key, required)
+		}
+	}
+	get := fieldGetter(key)
+	match := compileValue(required)
+	return func(c *ContextSnapshot) (bool, bool) {
+		actual := get(c)
+		if actual == nil || actual == "" {
+			return false, false // absent
+		}
+		return match(actual), true
+	}
 }
 
 // matchValue checks if an actual value matches a required value
-// Supports: exact match, array membership, glob patterns
+// Supports: exact match, array membership, glob patterns, and operator
+// objects (see WhenAny). A list-valued actual (such as imports) matches when
+// any of its elements does.
 func matchValue(actual interface{}, required interface{}) bool {
-	if actual == nil {
+	return compileValue(required)(actual)
+}
+
+// compileValue builds the matcher matchValue applies for a required value.
+func compileValue(required interface{}) func(actual interface{}) bool {
+	if op, ok := required.(map[string]interface{}); ok {
+		return compileOperator(op)
+	}
+	match := compileScalarValue(required)
+	return func(actual interface{}) bool {
+		list, ok := actual.([]string)
+		if !ok {
+			return match(actual)
+		}
+		for _, v := range list {
+			if match(v) {
+				return true
+			}
+		}
 		return false
 	}
+}
 
-	actualStr, actualIsStr := actual.(string)
-
+// compileScalarValue builds the matcher for a single actual value.
+func compileScalarValue(required interface{}) func(actual interface{}) bool {
 	switch req := required.(type) {
 	case string:
-		if !actualIsStr {
-			return false
+		// Patterns with ** match across directories, as the glob operator does
+		if strings.Contains(req, "**") {
+			return func(actual interface{}) bool {
+				actualStr, ok := actual.(string)
+				return ok && globMatch(req, actualStr)
+			}
 		}
 		// Support glob patterns
 		if strings.Contains(req, "*") {
 			// Normalize path separators for cross-platform glob matching
-			matched, _ := filepath.Match(filepath.FromSlash(req), filepath.FromSlash(actualStr))
-			return matched
+			pattern := filepath.FromSlash(req)
+			return func(actual interface{}) bool {
+				actualStr, ok := actual.(string)
+				if !ok {
+					return false
+				}
+				matched, _ := filepath.Match(pattern, filepath.FromSlash(actualStr))
+				return matched
+			}
+		}
+		return func(actual interface{}) bool {
+			actualStr, ok := actual.(string)
+			return ok && actualStr == req
 		}
-		return actualStr == req
 
 	case []interface{}:
 		// Value must be one of the options
+		options := make(map[string]bool, len(req))
 		for _, option := range req {
-			if optStr, ok := option.(string); ok && actualIsStr {
-				if actualStr == optStr {
-					return true
-				}
+			if optStr, ok := option.(string); ok {
+				options[optStr] = true
 			}
 		}
-		return false
+		return stringSetMatcher(options)
 
 	case []string:
-		if !actualIsStr {
-			return false
-		}
+		options := make(map[string]bool, len(req))
 		for _, option := range req {
-			if actualStr == option {
-				return true
-			}
+			options[option] = true
 		}
-		return false
+		return stringSetMatcher(options)
 
 	default:
-		return actual == required
+		return func(actual interface{}) bool {
+			return actual != nil && actual == required
+		}
+	}
+}
+
+func stringSetMatcher(options map[string]bool) func(actual interface{}) bool {
+	return func(actual interface{}) bool {
+		actualStr, ok := actual.(string)
+		return ok && options[actualStr]
 	}
 }
 
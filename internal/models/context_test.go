@@ -147,6 +147,26 @@ func TestContextSnapshot_Matches(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "agent match",
+			context: ContextSnapshot{
+				Agent: "claude-code",
+			},
+			predicate: map[string]interface{}{
+				"agent": "claude-code",
+			},
+			want: true,
+		},
+		{
+			name: "agent mismatch",
+			context: ContextSnapshot{
+				Agent: "cursor",
+			},
+			predicate: map[string]interface{}{
+				"agent": "claude-code",
+			},
+			want: false,
+		},
 		{
 			name: "custom field match",
 			context: ContextSnapshot{
@@ -236,6 +256,46 @@ func TestContextSnapshot_MatchField(t *testing.T) {
 			wantMatched:  false,
 			wantHasValue: false,
 		},
+		{
+			name:         "confirmed - declared tag matches",
+			ctx:          ContextSnapshot{Tags: []string{"go", "testing"}},
+			key:          "tags",
+			required:     "testing",
+			wantMatched:  true,
+			wantHasValue: true,
+		},
+		{
+			name:         "confirmed - declared sub-tag matches its parent",
+			ctx:          ContextSnapshot{Tags: []string{"testing/unit"}},
+			key:          "tags",
+			required:     "testing",
+			wantMatched:  true,
+			wantHasValue: true,
+		},
+		{
+			name:         "contradicted - parent tag does not match a sub-tag",
+			ctx:          ContextSnapshot{Tags: []string{"testing"}},
+			key:          "tags",
+			required:     "testing/unit",
+			wantMatched:  false,
+			wantHasValue: true,
+		},
+		{
+			name:         "confirmed - tag glob",
+			ctx:          ContextSnapshot{Tags: []string{"testing/unit"}},
+			key:          "tags",
+			required:     map[string]interface{}{"glob": "testing/*"},
+			wantMatched:  true,
+			wantHasValue: true,
+		},
+		{
+			name:         "absent - no tags declared",
+			ctx:          ContextSnapshot{},
+			key:          "tags",
+			required:     "testing",
+			wantMatched:  false,
+			wantHasValue: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -247,6 +307,12 @@ func TestContextSnapshot_MatchField(t *testing.T) {
 			if hasValue != tt.wantHasValue {
 				t.Errorf("hasValue = %v, want %v", hasValue, tt.wantHasValue)
 			}
+
+			// A compiled condition must agree with MatchField
+			matched, hasValue = CompileCondition(tt.key, tt.required)(&tt.ctx)
+			if matched != tt.wantMatched || hasValue != tt.wantHasValue {
+				t.Errorf("compiled = (%v, %v), want (%v, %v)", matched, hasValue, tt.wantMatched, tt.wantHasValue)
+			}
 		})
 	}
 }
@@ -308,6 +374,10 @@ func TestMatchValue(t *testing.T) {
 		{"non-string actual with array", 123, []interface{}{"a", "b"}, false},
 		{"equal non-string values", 42, 42, true},
 		{"unequal non-string values", 42, 43, false},
+		{"list actual with matching element", []string{"fmt", "react"}, "react", true},
+		{"list actual with glob", []string{"github.com/gin-gonic/gin"}, "github.com/*/gin", true},
+		{"list actual against options", []string{"vue"}, []interface{}{"react", "vue"}, true},
+		{"list actual without match", []string{"fmt"}, "react", false},
 	}
 
 	for _, tt := range tests {
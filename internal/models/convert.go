@@ -11,7 +11,8 @@ import (
 // from the graph store.
 func NodeToBehavior(node store.Node) Behavior {
 	b := Behavior{
-		ID: node.ID,
+		ID:     node.ID,
+		Origin: string(node.Origin),
 	}
 
 	// Extract kind
@@ -66,8 +67,26 @@ func NodeToBehavior(node store.Node) Behavior {
 		b.Priority = priority
 	}
 
-	// Extract provenance from metadata
-	if provenance, ok := node.Metadata["provenance"].(map[string]interface{}); ok {
+	// Extract expiry from metadata (valid_until is accepted as an alias)
+	for _, key := range []string{"expires_at", "valid_until"} {
+		if t, ok := parseMetadataTime(node.Metadata[key]); ok {
+			b.ExpiresAt = &t
+			break
+		}
+	}
+
+	// Extract pinning from metadata
+	if pinned, ok := node.Metadata["pinned"].(bool); ok {
+		b.Pinned = pinned
+	}
+
+	// Extract provenance from metadata (falling back to content, where the
+	// SQLite store places it)
+	provenance, ok := node.Metadata["provenance"].(map[string]interface{})
+	if !ok {
+		provenance, ok = node.Content["provenance"].(map[string]interface{})
+	}
+	if ok {
 		if sourceType, ok := provenance["source_type"].(string); ok {
 			b.Provenance.SourceType = SourceType(sourceType)
 		}
@@ -81,6 +100,27 @@ func NodeToBehavior(node store.Node) Behavior {
 		if author, ok := provenance["author"].(string); ok {
 			b.Provenance.Author = author
 		}
+		if agent, ok := provenance["source_agent"].(string); ok {
+			b.Provenance.SourceAgent = agent
+		}
+		if correctionID, ok := provenance["correction_id"].(string); ok {
+			b.Provenance.CorrectionID = correctionID
+		}
+		if pkg, ok := provenance["package"].(string); ok {
+			b.Provenance.Package = pkg
+		}
+		if version, ok := provenance["package_version"].(string); ok {
+			b.Provenance.PackageVersion = version
+		}
+		if importer, ok := provenance["importer"].(string); ok {
+			b.Provenance.Importer = importer
+		}
+		if sourcePath, ok := provenance["source_path"].(string); ok {
+			b.Provenance.SourcePath = sourcePath
+		}
+	} else if p, ok := node.Metadata["provenance"].(Provenance); ok {
+		// Stored unconverted by BehaviorToNode (in-memory stores)
+		b.Provenance = p
 	}
 
 	// Extract stats from metadata
@@ -124,9 +164,66 @@ func NodeToBehavior(node store.Node) Behavior {
 	return b
 }
 
+// ApplyRelationshipEdges fills Requires, Overrides, Conflicts, and
+// Specializes on each behavior from the outbound edges of those kinds,
+// replacing any previous values. Edges of other kinds are ignored.
+func ApplyRelationshipEdges(behaviors []Behavior, edges []store.Edge) {
+	requires := make(map[string][]string)
+	overrides := make(map[string][]string)
+	conflicts := make(map[string][]string)
+	specializes := make(map[string][]string)
+	for _, e := range edges {
+		switch e.Kind {
+		case store.EdgeKindRequires:
+			requires[e.Source] = appendUnique(requires[e.Source], e.Target)
+		case store.EdgeKindOverrides:
+			overrides[e.Source] = appendUnique(overrides[e.Source], e.Target)
+		case store.EdgeKindConflicts:
+			conflicts[e.Source] = appendUnique(conflicts[e.Source], e.Target)
+		case store.EdgeKindSpecializes:
+			specializes[e.Source] = appendUnique(specializes[e.Source], e.Target)
+		}
+	}
+
+	for i := range behaviors {
+		id := behaviors[i].ID
+		behaviors[i].Requires = requires[id]
+		behaviors[i].Overrides = overrides[id]
+		behaviors[i].Conflicts = conflicts[id]
+		behaviors[i].Specializes = specializes[id]
+	}
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// parseMetadataTime reads a time stored either as a time.Time or, as the
+// SQLite store does, as an RFC3339 string.
+func parseMetadataTime(v interface{}) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, true
+	case *time.Time:
+		if t != nil {
+			return *t, true
+		}
+	case string:
+		if parsed, err := time.Parse(time.RFC3339, t); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
 // BehaviorToNode converts a Behavior to a store.Node.
 func BehaviorToNode(b *Behavior) store.Node {
-	return store.Node{
+	node := store.Node{
 		ID:   b.ID,
 		Kind: store.NodeKindBehavior,
 		Content: map[string]interface{}{
@@ -142,4 +239,12 @@ func BehaviorToNode(b *Behavior) store.Node {
 			"provenance": b.Provenance,
 		},
 	}
+	if b.ExpiresAt != nil {
+		node.Metadata["expires_at"] = b.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	if b.Pinned {
+		node.Metadata["pinned"] = true
+	}
+	node.Origin = store.Origin(b.Origin)
+	return node
 }
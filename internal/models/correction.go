@@ -34,6 +34,16 @@ type Correction struct {
 	// Extra tags provided by the user (merged with inferred tags during extraction)
 	ExtraTags []string `json:"extra_tags,omitempty" yaml:"extra_tags,omitempty"`
 
+	// Extra when-conditions provided by the user, such as temporal conditions
+	// (merged with inferred conditions during extraction)
+	ExtraWhen map[string]interface{} `json:"extra_when,omitempty" yaml:"extra_when,omitempty"`
+
+	// Set when the correction was derived from an agent-reported failure
+	FailureID string `json:"failure_id,omitempty" yaml:"failure_id,omitempty"`
+
+	// Optional expiry for the extracted behavior
+	ExpiresAt *time.Time `json:"expires_at,omitempty" yaml:"expires_at,omitempty"`
+
 	// Processing state
 	Processed   bool       `json:"processed" yaml:"processed"`
 	ProcessedAt *time.Time `json:"processed_at,omitempty" yaml:"processed_at,omitempty"`
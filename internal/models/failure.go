@@ -0,0 +1,137 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// FailureKind classifies a failure an agent detected in its own work.
+type FailureKind string
+
+const (
+	FailureKindTest    FailureKind = "test"    // Tests failed after a change
+	FailureKindBuild   FailureKind = "build"   // The build or type check broke
+	FailureKindLint    FailureKind = "lint"    // A linter or formatter rejected the change
+	FailureKindRuntime FailureKind = "runtime" // The program crashed or misbehaved
+	FailureKindOther   FailureKind = "other"   // Anything else
+)
+
+// ParseFailureKind validates a failure kind. Empty means FailureKindOther.
+func ParseFailureKind(s string) (FailureKind, error) {
+	switch k := FailureKind(s); k {
+	case "":
+		return FailureKindOther, nil
+	case FailureKindTest, FailureKindBuild, FailureKindLint, FailureKindRuntime, FailureKindOther:
+		return k, nil
+	default:
+		return "", fmt.Errorf("invalid failure kind %q (use test, build, lint, runtime, or other)", s)
+	}
+}
+
+// FailureStatus tracks a failure through the review queue.
+type FailureStatus string
+
+const (
+	FailureStatusPending   FailureStatus = "pending"   // Awaiting review
+	FailureStatusLearned   FailureStatus = "learned"   // Extracted into a behavior
+	FailureStatusDismissed FailureStatus = "dismissed" // Reviewed and discarded
+)
+
+// Failure is a failure an agent detected in its own work, such as tests
+// failing after a change, together with what resolved it. Failures are
+// queued for review and, once accepted, feed extraction like corrections.
+type Failure struct {
+	// Unique identifier
+	ID string `json:"id" yaml:"id"`
+
+	// When the failure was reported
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+
+	// The context when the failure happened
+	Context ContextSnapshot `json:"context" yaml:"context"`
+
+	Kind FailureKind `json:"kind" yaml:"kind"`
+
+	// The command that failed, e.g. "go test ./..."
+	Command string `json:"command,omitempty" yaml:"command,omitempty"`
+
+	// What the agent did before the failure
+	Action string `json:"action,omitempty" yaml:"action,omitempty"`
+
+	// The failure output or a summary of it
+	Error string `json:"error" yaml:"error"`
+
+	// What resolved the failure, phrased as what to do instead
+	Fix string `json:"fix" yaml:"fix"`
+
+	// Review state
+	Status     FailureStatus `json:"status" yaml:"status"`
+	BehaviorID string        `json:"behavior_id,omitempty" yaml:"behavior_id,omitempty"`
+	ReviewedAt *time.Time    `json:"reviewed_at,omitempty" yaml:"reviewed_at,omitempty"`
+}
+
+// Correction converts the failure into a correction for extraction. The
+// agent's action and the failure become what went wrong, the fix becomes
+// what to do instead.
+func (f Failure) Correction() Correction {
+	wrong := f.Action
+	if f.Error != "" {
+		failure := fmt.Sprintf("%s failure: %s", f.Kind, f.Error)
+		if wrong == "" {
+			wrong = failure
+		} else {
+			wrong = fmt.Sprintf("%s (%s)", wrong, failure)
+		}
+	}
+	return Correction{
+		ID:              f.ID,
+		Timestamp:       f.Timestamp,
+		Context:         f.Context,
+		AgentAction:     wrong,
+		CorrectedAction: f.Fix,
+		Corrector:       "agent",
+		FailureID:       f.ID,
+	}
+}
+
+// FailureToNode converts a Failure to a store.Node of kind failure.
+func FailureToNode(f *Failure) (store.Node, error) {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return store.Node{}, fmt.Errorf("failed to encode failure: %w", err)
+	}
+	var content map[string]interface{}
+	if err := json.Unmarshal(data, &content); err != nil {
+		return store.Node{}, fmt.Errorf("failed to encode failure: %w", err)
+	}
+	return store.Node{
+		ID:       f.ID,
+		Kind:     store.NodeKindFailure,
+		Content:  content,
+		Metadata: map[string]interface{}{},
+	}, nil
+}
+
+// NodeToFailure converts a store.Node of kind failure back to a Failure.
+func NodeToFailure(node store.Node) (Failure, error) {
+	var f Failure
+	content := node.Content
+	// The SQLite store returns generic node content under content.structured
+	if inner, ok := content["content"].(map[string]interface{}); ok {
+		if structured, ok := inner["structured"].(map[string]interface{}); ok {
+			content = structured
+		}
+	}
+	data, err := json.Marshal(content)
+	if err != nil {
+		return f, fmt.Errorf("failed to decode failure %s: %w", node.ID, err)
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return f, fmt.Errorf("failed to decode failure %s: %w", node.ID, err)
+	}
+	f.ID = node.ID
+	return f, nil
+}
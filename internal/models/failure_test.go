@@ -0,0 +1,73 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestParseFailureKind(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    FailureKind
+		wantErr bool
+	}{
+		{"", FailureKindOther, false},
+		{"test", FailureKindTest, false},
+		{"build", FailureKindBuild, false},
+		{"lint", FailureKindLint, false},
+		{"runtime", FailureKindRuntime, false},
+		{"other", FailureKindOther, false},
+		{"flaky", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFailureKind(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseFailureKind(%q) = %q, %v; want %q, err %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFailure_Correction(t *testing.T) {
+	f := Failure{ID: "failure-1", Kind: FailureKindTest, Action: "changed the parser", Error: "TestParse failed", Fix: "update golden files after changing output"}
+	c := f.Correction()
+	if c.ID != f.ID || c.FailureID != f.ID {
+		t.Errorf("IDs = %s/%s, want %s", c.ID, c.FailureID, f.ID)
+	}
+	if want := "changed the parser (test failure: TestParse failed)"; c.AgentAction != want {
+		t.Errorf("AgentAction = %q, want %q", c.AgentAction, want)
+	}
+	if c.CorrectedAction != f.Fix || c.Corrector != "agent" {
+		t.Errorf("CorrectedAction = %q, Corrector = %q", c.CorrectedAction, c.Corrector)
+	}
+
+	f.Action = ""
+	if want := "test failure: TestParse failed"; f.Correction().AgentAction != want {
+		t.Errorf("AgentAction without action = %q, want %q", f.Correction().AgentAction, want)
+	}
+}
+
+func TestFailureNodeRoundTrip(t *testing.T) {
+	f := &Failure{ID: "failure-1", Kind: FailureKindBuild, Error: "undefined: x", Fix: "declare x", Status: FailureStatusPending}
+	node, err := FailureToNode(f)
+	if err != nil {
+		t.Fatalf("FailureToNode() error = %v", err)
+	}
+	if node.Kind != store.NodeKindFailure {
+		t.Errorf("Kind = %s, want %s", node.Kind, store.NodeKindFailure)
+	}
+
+	// Both the flat form and the SQLite nested form decode
+	nested := store.Node{ID: node.ID, Kind: node.Kind, Content: map[string]interface{}{
+		"content": map[string]interface{}{"structured": node.Content},
+	}}
+	for _, n := range []store.Node{node, nested} {
+		got, err := NodeToFailure(n)
+		if err != nil {
+			t.Fatalf("NodeToFailure() error = %v", err)
+		}
+		if got.ID != f.ID || got.Fix != f.Fix || got.Kind != f.Kind || got.Status != f.Status {
+			t.Errorf("NodeToFailure() = %+v, want %+v", got, *f)
+		}
+	}
+}
@@ -62,6 +62,10 @@ type InjectionPlan struct {
 	// OmittedBehaviors are behaviors referenced but not included
 	OmittedBehaviors []InjectedBehavior `json:"omitted_behaviors"`
 
+	// GroupHeaders are generalized parents rendered once above two or more
+	// full-tier children that specialize them. Their cost is in TotalTokens.
+	GroupHeaders []InjectedBehavior `json:"group_headers,omitempty"`
+
 	// TotalTokens is the total token cost of the plan
 	TotalTokens int `json:"total_tokens"`
 
@@ -0,0 +1,458 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Operator when-conditions go beyond equality, list membership, and simple
+// globs. A condition's value may be an object with a single operator:
+//
+//	language:  {"not": "go"}                  anything but Go
+//	file_path: {"glob": "**/*_test.go"}       ** matches any number of directories
+//	branch:    {"regex": "^release/"}         RE2 syntax, unanchored
+//	language:  {"any": ["go", {"glob": "py*"}]}
+//
+// Operators nest, so {"not": {"glob": "vendor/**"}} excludes a tree. The
+// top-level key "any" holds alternative when-maps, at least one of which
+// must hold:
+//
+//	any: [{"language": "go"}, {"file_path": {"glob": "cmd/**"}}]
+//
+// Like a behavior's own conditions, an alternative holds unless the context
+// contradicts it; conditions on fields the context lacks are neutral.
+const WhenAny = "any"
+
+// Value operators of when-conditions.
+const (
+	OpNot   = "not"
+	OpGlob  = "glob"
+	OpRegex = "regex"
+	OpAny   = "any"
+)
+
+// Condition statuses, as reported by ActivationExplanation and ClauseResult.
+const (
+	StatusConfirmed    = "confirmed"
+	StatusContradicted = "contradicted"
+	StatusAbsent       = "absent"
+)
+
+// ClauseResult explains one sub-clause of an operator or "any" condition.
+type ClauseResult struct {
+	Clause  string         `json:"clause"`
+	Status  string         `json:"status"` // "confirmed", "contradicted", "absent"
+	Clauses []ClauseResult `json:"clauses,omitempty"`
+}
+
+// ValidateWhen parses when, returning an error naming the first malformed
+// condition: an unknown operator, a bad glob or regex, or an "any" that is
+// not a non-empty list.
+func ValidateWhen(when map[string]interface{}) error {
+	for key, required := range when {
+		if key == WhenAny {
+			clauses, ok := listItems(required)
+			if !ok || len(clauses) == 0 {
+				return fmt.Errorf("%s: must be a non-empty list of condition objects", key)
+			}
+			for i, clause := range clauses {
+				sub, ok := clause.(map[string]interface{})
+				if !ok {
+					return fmt.Errorf("%s[%d]: must be a condition object", key, i)
+				}
+				if err := ValidateWhen(sub); err != nil {
+					return fmt.Errorf("%s[%d].%w", key, i, err)
+				}
+			}
+			continue
+		}
+		if IsTemporalKey(key) {
+			continue
+		}
+		if err := validateValue(required); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// ParseCondition parses a "key=value" when-condition as given on the command
+// line or to floop_learn. Temporal keys take ParseTemporalCondition's forms;
+// any other value that starts with "{" or "[" is JSON, so operators can be
+// written inline:
+//
+//	language={"not": "go"}
+//	file_path={"glob": "**/*_test.go"}
+//	any=[{"language": "go"}, {"task": "testing"}]
+//
+// Other values are matched as plain strings.
+func ParseCondition(s string) (string, interface{}, error) {
+	key, value, ok := strings.Cut(s, "=")
+	key = strings.TrimSpace(key)
+	value = strings.TrimSpace(value)
+	if IsTemporalKey(key) {
+		return ParseTemporalCondition(s)
+	}
+	if !ok || key == "" || value == "" {
+		return "", nil, fmt.Errorf("invalid condition %q (want key=value)", s)
+	}
+
+	var required interface{} = value
+	if strings.HasPrefix(value, "{") || strings.HasPrefix(value, "[") {
+		if err := json.Unmarshal([]byte(value), &required); err != nil {
+			return "", nil, fmt.Errorf("invalid JSON in condition %q: %w", s, err)
+		}
+	}
+	if err := ValidateWhen(map[string]interface{}{key: required}); err != nil {
+		return "", nil, err
+	}
+	return key, required, nil
+}
+
+// validateValue checks one condition value.
+func validateValue(v interface{}) error {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		op, arg, err := operator(val)
+		if err != nil {
+			return err
+		}
+		switch op {
+		case OpNot:
+			return validateValue(arg)
+		case OpGlob:
+			pattern, ok := arg.(string)
+			if !ok {
+				return fmt.Errorf("glob must be a string")
+			}
+			if _, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+				return fmt.Errorf("invalid glob %q: %w", pattern, err)
+			}
+		case OpRegex:
+			pattern, ok := arg.(string)
+			if !ok {
+				return fmt.Errorf("regex must be a string")
+			}
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid regex %q: %w", pattern, err)
+			}
+		default:
+			options, ok := listItems(arg)
+			if !ok || len(options) == 0 {
+				return fmt.Errorf("any must be a non-empty list")
+			}
+			for _, option := range options {
+				if err := validateValue(option); err != nil {
+					return err
+				}
+			}
+		}
+	case []interface{}:
+		for _, option := range val {
+			if _, ok := option.(map[string]interface{}); ok {
+				return fmt.Errorf(`lists cannot hold operators; use {"any": [...]}`)
+			}
+		}
+	}
+	return nil
+}
+
+// operator returns the single operator of an operator object.
+func operator(m map[string]interface{}) (string, interface{}, error) {
+	if len(m) != 1 {
+		return "", nil, fmt.Errorf("operator object must have exactly one key (not, glob, regex, or any)")
+	}
+	for op, arg := range m {
+		switch op {
+		case OpNot, OpGlob, OpRegex, OpAny:
+			return op, arg, nil
+		}
+		return "", nil, fmt.Errorf("unknown operator %q (valid: not, glob, regex, any)", op)
+	}
+	return "", nil, nil
+}
+
+// listItems returns the elements of a list value.
+func listItems(v interface{}) ([]interface{}, bool) {
+	switch list := v.(type) {
+	case []interface{}:
+		return list, true
+	case []string:
+		items := make([]interface{}, len(list))
+		for i, s := range list {
+			items[i] = s
+		}
+		return items, true
+	case []map[string]interface{}:
+		items := make([]interface{}, len(list))
+		for i, m := range list {
+			items[i] = m
+		}
+		return items, true
+	}
+	return nil, false
+}
+
+// compileOperator builds the matcher for an operator object. A malformed
+// operator never matches.
+func compileOperator(m map[string]interface{}) func(actual interface{}) bool {
+	op, arg, err := operator(m)
+	if err != nil {
+		return func(interface{}) bool { return false }
+	}
+	switch op {
+	case OpNot:
+		inner := compileValue(arg)
+		return func(actual interface{}) bool { return !inner(actual) }
+	case OpGlob:
+		pattern, _ := arg.(string)
+		return anyElement(func(s string) bool { return globMatch(pattern, s) })
+	case OpRegex:
+		pattern, _ := arg.(string)
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return func(interface{}) bool { return false }
+		}
+		return anyElement(re.MatchString)
+	default:
+		options, _ := listItems(arg)
+		matchers := make([]func(interface{}) bool, 0, len(options))
+		for _, option := range options {
+			matchers = append(matchers, compileValue(option))
+		}
+		return func(actual interface{}) bool {
+			for _, match := range matchers {
+				if match(actual) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+}
+
+// anyElement adapts a string predicate to string and list-valued fields.
+func anyElement(match func(string) bool) func(actual interface{}) bool {
+	return func(actual interface{}) bool {
+		switch v := actual.(type) {
+		case string:
+			return match(v)
+		case []string:
+			for _, s := range v {
+				if match(s) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// globMatch matches a slash-separated name against pattern, where a "**"
+// segment matches zero or more segments and other segments follow
+// path.Match. A pattern not starting with "/" may match the end of the
+// name, so "cmd/**" matches files under any cmd directory whether the
+// context path is relative or absolute. Backslashes in name are treated as
+// separators.
+func globMatch(pattern, name string) bool {
+	if !strings.HasPrefix(pattern, "/") && !strings.HasPrefix(pattern, "**") {
+		pattern = "**/" + pattern
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(strings.ReplaceAll(name, `\`, "/"), "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// compileAny builds the matcher for the top-level "any" condition: confirmed
+// when some alternative is, otherwise absent when some alternative is
+// neither confirmed nor contradicted, otherwise contradicted.
+func compileAny(required interface{}) ConditionMatcher {
+	items, _ := listItems(required)
+	clauses := make([][]ConditionMatcher, 0, len(items))
+	for _, item := range items {
+		sub, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		clause := make([]ConditionMatcher, 0, len(sub))
+		for key, req := range sub {
+			clause = append(clause, CompileCondition(key, req))
+		}
+		clauses = append(clauses, clause)
+	}
+	return func(c *ContextSnapshot) (bool, bool) {
+		absent := false
+		for _, clause := range clauses {
+			matched, hasValue := matchClause(c, clause)
+			if matched {
+				return true, true
+			}
+			absent = absent || !hasValue
+		}
+		return false, !absent
+	}
+}
+
+// matchClause evaluates one alternative of an "any" condition: contradicted
+// when any of its conditions is, confirmed when at least one is confirmed,
+// absent otherwise.
+func matchClause(c *ContextSnapshot, clause []ConditionMatcher) (matched, hasValue bool) {
+	confirmed := false
+	for _, match := range clause {
+		m, has := match(c)
+		if has && !m {
+			return false, true
+		}
+		confirmed = confirmed || (has && m)
+	}
+	return confirmed, confirmed
+}
+
+// conditionStatus names a (matched, hasValue) result.
+func conditionStatus(matched, hasValue bool) string {
+	switch {
+	case !hasValue:
+		return StatusAbsent
+	case matched:
+		return StatusConfirmed
+	default:
+		return StatusContradicted
+	}
+}
+
+// ExplainCondition breaks an operator or "any" condition into the
+// sub-clauses that matched and didn't. It returns nil for plain conditions.
+func (c *ContextSnapshot) ExplainCondition(key string, required interface{}) []ClauseResult {
+	if key == WhenAny {
+		items, _ := listItems(required)
+		results := make([]ClauseResult, 0, len(items))
+		for _, item := range items {
+			sub, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			clause := make([]ConditionMatcher, 0, len(sub))
+			result := ClauseResult{Clause: DescribeWhen(sub)}
+			for _, k := range sortedKeys(sub) {
+				match := CompileCondition(k, sub[k])
+				clause = append(clause, match)
+				result.Clauses = append(result.Clauses, ClauseResult{
+					Clause:  k + ": " + describeValue(sub[k]),
+					Status:  conditionStatus(match(c)),
+					Clauses: c.ExplainCondition(k, sub[k]),
+				})
+			}
+			result.Status = conditionStatus(matchClause(c, clause))
+			results = append(results, result)
+		}
+		return results
+	}
+
+	m, ok := required.(map[string]interface{})
+	if !ok || IsTemporalKey(key) {
+		return nil
+	}
+	actual := c.GetField(key)
+	return []ClauseResult{explainValue(m, actual, actual != nil && actual != "")}
+}
+
+// explainValue explains how a condition value fared against actual.
+func explainValue(required interface{}, actual interface{}, present bool) ClauseResult {
+	result := ClauseResult{
+		Clause: describeValue(required),
+		Status: conditionStatus(present && compileValue(required)(actual), present),
+	}
+	m, ok := required.(map[string]interface{})
+	if !ok {
+		return result
+	}
+	op, arg, err := operator(m)
+	if err != nil {
+		return result
+	}
+	switch op {
+	case OpNot:
+		result.Clauses = []ClauseResult{explainValue(arg, actual, present)}
+	case OpAny:
+		options, _ := listItems(arg)
+		for _, option := range options {
+			result.Clauses = append(result.Clauses, explainValue(option, actual, present))
+		}
+	}
+	return result
+}
+
+// DescribeWhen renders when-conditions compactly, e.g.
+// `language: go, file_path: glob "**/*_test.go"`.
+func DescribeWhen(when map[string]interface{}) string {
+	parts := make([]string, 0, len(when))
+	for _, key := range sortedKeys(when) {
+		parts = append(parts, key+": "+describeValue(when[key]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeValue renders a condition value, spelling out operators.
+func describeValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return strconv.Quote(val)
+	case map[string]interface{}:
+		op, arg, err := operator(val)
+		if err != nil {
+			break
+		}
+		switch op {
+		case OpGlob, OpRegex:
+			return op + " " + describeValue(arg)
+		case OpNot:
+			return "not " + describeValue(arg)
+		default:
+			options, _ := listItems(arg)
+			parts := make([]string, 0, len(options))
+			for _, option := range options {
+				parts = append(parts, describeValue(option))
+			}
+			return "any(" + strings.Join(parts, " | ") + ")"
+		}
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,219 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchField_Operators(t *testing.T) {
+	ctx := &ContextSnapshot{
+		FilePath:     "/home/dev/proj/internal/store/file_test.go",
+		FileLanguage: "go",
+		Branch:       "release/1.2",
+		Imports:      []string{"fmt", "github.com/spf13/cobra"},
+		ChangedPaths: []string{"README.md", "db/migrations/0001_users.sql"},
+	}
+
+	tests := []struct {
+		name         string
+		key          string
+		required     interface{}
+		wantMatched  bool
+		wantHasValue bool
+	}{
+		{"not matches other value", "language", map[string]interface{}{"not": "python"}, true, true},
+		{"not contradicts same value", "language", map[string]interface{}{"not": "go"}, false, true},
+		{"not on absent field", "task", map[string]interface{}{"not": "testing"}, false, false},
+		{"not over list field", "imports", map[string]interface{}{"not": "github.com/spf13/cobra"}, false, true},
+		{"doublestar glob", "file_path", map[string]interface{}{"glob": "**/*_test.go"}, true, true},
+		{"relative glob matches absolute path", "file_path", map[string]interface{}{"glob": "internal/**"}, true, true},
+		{"rooted glob", "file_path", map[string]interface{}{"glob": "/internal/**"}, false, true},
+		{"glob mismatch", "file_path", map[string]interface{}{"glob": "cmd/**"}, false, true},
+		{"negated glob", "file_path", map[string]interface{}{"not": map[string]interface{}{"glob": "vendor/**"}}, true, true},
+		{"regex", "branch", map[string]interface{}{"regex": "^release/"}, true, true},
+		{"regex mismatch", "branch", map[string]interface{}{"regex": "^main$"}, false, true},
+		{"regex over list field", "imports", map[string]interface{}{"regex": "cobra$"}, true, true},
+		{"value any", "language", map[string]interface{}{"any": []interface{}{"rust", map[string]interface{}{"glob": "g*"}}}, true, true},
+		{"value any mismatch", "language", map[string]interface{}{"any": []interface{}{"rust", "python"}}, false, true},
+		{"unknown operator never matches", "language", map[string]interface{}{"like": "go"}, false, true},
+		{"plain value still works", "language", "go", true, true},
+		{"plain doublestar over list field", "changed_paths", "migrations/**", true, true},
+		{"plain doublestar mismatch", "changed_paths", "cmd/**", false, true},
+		{"absent git field", "staged_files", "migrations/**", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, hasValue := ctx.MatchField(tt.key, tt.required)
+			if matched != tt.wantMatched || hasValue != tt.wantHasValue {
+				t.Errorf("MatchField(%s, %v) = (%v, %v), want (%v, %v)", tt.key, tt.required, matched, hasValue, tt.wantMatched, tt.wantHasValue)
+			}
+			if m, h := CompileCondition(tt.key, tt.required)(ctx); m != matched || h != hasValue {
+				t.Errorf("CompileCondition(%s, %v) = (%v, %v), MatchField = (%v, %v)", tt.key, tt.required, m, h, matched, hasValue)
+			}
+		})
+	}
+}
+
+func TestMatchField_Any(t *testing.T) {
+	ctx := &ContextSnapshot{FileLanguage: "go", Task: "refactor"}
+
+	tests := []struct {
+		name         string
+		alternatives []interface{}
+		wantMatched  bool
+		wantHasValue bool
+	}{
+		{"one alternative confirmed", []interface{}{
+			map[string]interface{}{"language": "python"},
+			map[string]interface{}{"task": "refactor"},
+		}, true, true},
+		{"all contradicted", []interface{}{
+			map[string]interface{}{"language": "python"},
+			map[string]interface{}{"task": "testing"},
+		}, false, true},
+		{"contradicted and absent", []interface{}{
+			map[string]interface{}{"language": "python"},
+			map[string]interface{}{"environment": "ci"},
+		}, false, false},
+		{"alternative with a contradicted condition", []interface{}{
+			map[string]interface{}{"language": "go", "task": "testing"},
+		}, false, true},
+		{"alternative with an absent condition", []interface{}{
+			map[string]interface{}{"language": "go", "environment": "ci"},
+		}, true, true},
+		{"nested operators", []interface{}{
+			map[string]interface{}{"language": map[string]interface{}{"not": "python"}},
+		}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, hasValue := ctx.MatchField(WhenAny, tt.alternatives)
+			if matched != tt.wantMatched || hasValue != tt.wantHasValue {
+				t.Errorf("MatchField(any) = (%v, %v), want (%v, %v)", matched, hasValue, tt.wantMatched, tt.wantHasValue)
+			}
+			if got := ctx.Matches(map[string]interface{}{WhenAny: tt.alternatives}); got != tt.wantMatched {
+				t.Errorf("Matches(any) = %v, want %v", got, tt.wantMatched)
+			}
+		})
+	}
+}
+
+func TestValidateWhen(t *testing.T) {
+	tests := []struct {
+		name    string
+		when    map[string]interface{}
+		wantErr string
+	}{
+		{"plain", map[string]interface{}{"language": "go", "task": []interface{}{"a", "b"}}, ""},
+		{"operators", map[string]interface{}{
+			"language":  map[string]interface{}{"not": "go"},
+			"file_path": map[string]interface{}{"glob": "**/*_test.go"},
+			"branch":    map[string]interface{}{"regex": "^release/"},
+			"any":       []interface{}{map[string]interface{}{"task": map[string]interface{}{"any": []interface{}{"a", "b"}}}},
+		}, ""},
+		{"temporal range", map[string]interface{}{"date": map[string]interface{}{"from": "2026-05-15"}}, ""},
+		{"unknown operator", map[string]interface{}{"language": map[string]interface{}{"like": "go"}}, `language: unknown operator "like"`},
+		{"two operators", map[string]interface{}{"language": map[string]interface{}{"not": "go", "glob": "*"}}, "exactly one key"},
+		{"bad regex", map[string]interface{}{"branch": map[string]interface{}{"regex": "("}}, "invalid regex"},
+		{"bad glob", map[string]interface{}{"file_path": map[string]interface{}{"glob": "[a"}}, "invalid glob"},
+		{"glob not a string", map[string]interface{}{"file_path": map[string]interface{}{"glob": 3.0}}, "glob must be a string"},
+		{"operator in list", map[string]interface{}{"language": []interface{}{map[string]interface{}{"not": "go"}}}, "lists cannot hold operators"},
+		{"empty any", map[string]interface{}{"any": []interface{}{}}, "non-empty list"},
+		{"any of scalars", map[string]interface{}{"any": []interface{}{"go"}}, "any[0]: must be a condition object"},
+		{"nested error", map[string]interface{}{"any": []interface{}{map[string]interface{}{"task": map[string]interface{}{"not": map[string]interface{}{"regex": "("}}}}}, "any[0].task: invalid regex"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWhen(tt.when)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateWhen() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateWhen() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantKey  string
+		wantDesc string
+		wantErr  bool
+	}{
+		{`language={"not": "go"}`, "language", `not "go"`, false},
+		{`file_path={"glob":"**/*_test.go"}`, "file_path", `glob "**/*_test.go"`, false},
+		{`any=[{"language": "go"}, {"task": "testing"}]`, "any", `[{"language":"go"},{"task":"testing"}]`, false},
+		{"task=testing", "task", `"testing"`, false},
+		{"weekday=friday", "weekday", `"friday"`, false},
+		{`language={"not": `, "", "", true},
+		{`language={"like": "go"}`, "", "", true},
+		{"task", "", "", true},
+		{"weekday=someday", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			key, value, err := ParseCondition(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCondition(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if key != tt.wantKey || describeValue(value) != tt.wantDesc {
+				t.Errorf("ParseCondition(%q) = %s, %s; want %s, %s", tt.input, key, describeValue(value), tt.wantKey, tt.wantDesc)
+			}
+		})
+	}
+}
+
+func TestExplainCondition(t *testing.T) {
+	ctx := &ContextSnapshot{FileLanguage: "go", FilePath: "cmd/floop/main.go"}
+
+	if got := ctx.ExplainCondition("language", "go"); got != nil {
+		t.Errorf("plain condition clauses = %+v, want nil", got)
+	}
+
+	notGo := ctx.ExplainCondition("language", map[string]interface{}{"not": map[string]interface{}{"any": []interface{}{"go", "rust"}}})
+	if len(notGo) != 1 || notGo[0].Clause != `not any("go" | "rust")` || notGo[0].Status != StatusContradicted {
+		t.Fatalf("not clauses = %+v", notGo)
+	}
+	inner := notGo[0].Clauses[0].Clauses
+	if len(inner) != 2 || inner[0].Status != StatusConfirmed || inner[1].Status != StatusContradicted {
+		t.Errorf("any options = %+v, want go confirmed and rust contradicted", inner)
+	}
+
+	alternatives := ctx.ExplainCondition(WhenAny, []interface{}{
+		map[string]interface{}{"language": "python"},
+		map[string]interface{}{"file_path": map[string]interface{}{"glob": "cmd/**"}, "task": "release"},
+	})
+	if len(alternatives) != 2 {
+		t.Fatalf("any clauses = %+v, want 2", alternatives)
+	}
+	if alternatives[0].Clause != `language: "python"` || alternatives[0].Status != StatusContradicted {
+		t.Errorf("first alternative = %+v", alternatives[0])
+	}
+	second := alternatives[1]
+	if second.Status != StatusConfirmed || len(second.Clauses) != 2 {
+		t.Fatalf("second alternative = %+v", second)
+	}
+	if second.Clauses[0].Clause != `file_path: glob "cmd/**"` || second.Clauses[0].Status != StatusConfirmed || len(second.Clauses[0].Clauses) != 1 {
+		t.Errorf("file_path clause = %+v", second.Clauses[0])
+	}
+	if second.Clauses[1].Status != StatusAbsent {
+		t.Errorf("task clause = %+v, want absent", second.Clauses[1])
+	}
+}
+
+func TestClassifyScope_Any(t *testing.T) {
+	b := &Behavior{When: map[string]interface{}{
+		"any": []interface{}{map[string]interface{}{"language": "go"}, map[string]interface{}{"file_path": map[string]interface{}{"glob": "cmd/**"}}},
+	}}
+	if got := ClassifyScope(b); got != "local" {
+		t.Errorf("ClassifyScope() = %s, want local for a file_path alternative", got)
+	}
+}
@@ -12,6 +12,9 @@ const (
 	SourceTypeLearned      SourceType = "learned"      // Extracted from a correction
 	SourceTypeImported     SourceType = "imported"     // From an external package
 	SourceTypeConsolidated SourceType = "consolidated" // Consolidated from multiple events
+	SourceTypeGeneralized  SourceType = "generalized"  // Generalized from similar behaviors
+	SourceTypeFailure      SourceType = "failure"      // Extracted from an agent-reported failure
+	SourceTypePack         SourceType = "pack"         // Installed from a skill pack
 )
 
 // Provenance tracks where a behavior came from
@@ -22,13 +25,18 @@ type Provenance struct {
 	// For authored behaviors
 	Author string `json:"author,omitempty" yaml:"author,omitempty"`
 
-	// For learned behaviors
+	// For learned behaviors; for failure behaviors, the failure ID
 	CorrectionID string `json:"correction_id,omitempty" yaml:"correction_id,omitempty"`
 
 	// For imported behaviors
 	Package        string `json:"package,omitempty" yaml:"package,omitempty"`
 	PackageVersion string `json:"package_version,omitempty" yaml:"package_version,omitempty"`
 
+	// For behaviors imported from external sources such as ADRs: the
+	// importer's format and the file the behavior was read from
+	Importer   string `json:"importer,omitempty" yaml:"importer,omitempty"`
+	SourcePath string `json:"source_path,omitempty" yaml:"source_path,omitempty"`
+
 	// Consolidation lineage
 	ConsolidatedBy string     `json:"consolidated_by,omitempty" yaml:"consolidated_by,omitempty"`
 	ConsolidatedAt *time.Time `json:"consolidated_at,omitempty" yaml:"consolidated_at,omitempty"`
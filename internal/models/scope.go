@@ -9,16 +9,28 @@ import (
 var localScopeKeys = []string{"file_path"}
 
 // ClassifyScope determines whether a behavior should be stored locally or globally
-// based on its When conditions. Behaviors with project-specific conditions (file_path)
-// are local; everything else (language-only, task-only, empty) is global.
+// based on its When conditions. Behaviors with project-specific conditions (file_path),
+// including inside an "any" alternative, are local; everything else
+// (language-only, task-only, empty) is global.
 func ClassifyScope(behavior *Behavior) constants.Scope {
-	if behavior.When == nil {
-		return constants.ScopeGlobal
+	if hasLocalKey(behavior.When) {
+		return constants.ScopeLocal
 	}
+	return constants.ScopeGlobal
+}
+
+// hasLocalKey reports whether when has a project-specific condition.
+func hasLocalKey(when map[string]interface{}) bool {
 	for _, key := range localScopeKeys {
-		if _, ok := behavior.When[key]; ok {
-			return constants.ScopeLocal
+		if _, ok := when[key]; ok {
+			return true
 		}
 	}
-	return constants.ScopeGlobal
+	alternatives, _ := listItems(when[WhenAny])
+	for _, alt := range alternatives {
+		if sub, ok := alt.(map[string]interface{}); ok && hasLocalKey(sub) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,237 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Temporal when-condition keys. They are evaluated against the context's
+// Timestamp (the activation time), so a behavior such as "freeze period: no
+// dependency bumps until the June 1 release" switches itself on and off.
+//
+//	weekday: "friday" or ["saturday", "sunday"]
+//	date:    "2026-06-01", "2026-06-*", ["2026-06-01", ...], or a range
+//	         {"from": "2026-05-15", "until": "2026-06-01"} (both inclusive)
+//	after:   "2026-06-01" (active once that day has passed)
+//	before:  "2026-06-01" (active until that day begins)
+//
+// Dates are YYYY-MM-DD in the timestamp's location; after, before, and range
+// bounds also accept RFC3339 times for a precise cut-over.
+const (
+	WhenWeekday = "weekday"
+	WhenDate    = "date"
+	WhenAfter   = "after"
+	WhenBefore  = "before"
+)
+
+// dateLayout is the layout of date values in temporal conditions.
+const dateLayout = "2006-01-02"
+
+// IsTemporalKey reports whether key is a temporal when-condition.
+func IsTemporalKey(key string) bool {
+	switch key {
+	case WhenWeekday, WhenDate, WhenAfter, WhenBefore:
+		return true
+	}
+	return false
+}
+
+// ParseTemporalCondition parses a "key=value" temporal condition as given on
+// the command line:
+//
+//	weekday=saturday,sunday
+//	date=2026-06-01
+//	date=2026-05-15..2026-06-01   (inclusive range; either end may be empty)
+//	after=2026-06-01
+//	before=2026-06-01T17:00:00Z
+func ParseTemporalCondition(s string) (string, interface{}, error) {
+	key, value, ok := strings.Cut(s, "=")
+	key = strings.TrimSpace(key)
+	value = strings.TrimSpace(value)
+	if !ok || value == "" {
+		return "", nil, fmt.Errorf("invalid condition %q (want key=value)", s)
+	}
+
+	switch key {
+	case WhenWeekday:
+		var days []interface{}
+		for _, d := range strings.Split(value, ",") {
+			d = strings.ToLower(strings.TrimSpace(d))
+			if !validWeekday(d) {
+				return "", nil, fmt.Errorf("invalid weekday %q", d)
+			}
+			days = append(days, d)
+		}
+		if len(days) == 1 {
+			return key, days[0], nil
+		}
+		return key, days, nil
+	case WhenDate:
+		if from, until, isRange := strings.Cut(value, ".."); isRange {
+			bounds := make(map[string]interface{})
+			if from != "" {
+				if !validBound(from) {
+					return "", nil, fmt.Errorf("invalid date %q", from)
+				}
+				bounds["from"] = from
+			}
+			if until != "" {
+				if !validBound(until) {
+					return "", nil, fmt.Errorf("invalid date %q", until)
+				}
+				bounds["until"] = until
+			}
+			if len(bounds) == 0 {
+				return "", nil, fmt.Errorf("date range %q has no bounds", value)
+			}
+			return key, bounds, nil
+		}
+		if _, err := time.Parse(dateLayout, value); err != nil && !strings.Contains(value, "*") {
+			return "", nil, fmt.Errorf("invalid date %q (want YYYY-MM-DD)", value)
+		}
+		return key, value, nil
+	case WhenAfter, WhenBefore:
+		if !validBound(value) {
+			return "", nil, fmt.Errorf("invalid %s %q (want YYYY-MM-DD or RFC3339)", key, value)
+		}
+		return key, value, nil
+	default:
+		return "", nil, fmt.Errorf("unknown temporal condition %q (want weekday, date, after, or before)", key)
+	}
+}
+
+func validWeekday(d string) bool {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.ToLower(day.String()) == d {
+			return true
+		}
+	}
+	return false
+}
+
+func validBound(s string) bool {
+	if _, err := time.Parse(dateLayout, s); err == nil {
+		return true
+	}
+	_, err := time.Parse(time.RFC3339, s)
+	return err == nil
+}
+
+// temporalField returns the context value a temporal key is compared with:
+// the lowercase weekday name or the YYYY-MM-DD date. A zero timestamp has no
+// value.
+func (c *ContextSnapshot) temporalField(key string) interface{} {
+	if c.Timestamp.IsZero() {
+		return nil
+	}
+	if key == WhenWeekday {
+		return strings.ToLower(c.Timestamp.Weekday().String())
+	}
+	return c.Timestamp.Format(dateLayout)
+}
+
+// matchTemporal checks a temporal condition against the timestamp. Values
+// that cannot be parsed never match, so a malformed condition keeps the
+// behavior inactive rather than always on.
+func (c *ContextSnapshot) matchTemporal(key string, required interface{}) bool {
+	switch key {
+	case WhenWeekday:
+		return matchValue(c.temporalField(key), lowerStrings(required))
+	case WhenDate:
+		if bounds, ok := required.(map[string]interface{}); ok {
+			return c.inDateRange(bounds)
+		}
+		return matchValue(c.temporalField(key), required)
+	case WhenAfter:
+		s, _ := required.(string)
+		return c.afterBound(s, false)
+	case WhenBefore:
+		s, _ := required.(string)
+		return c.beforeBound(s, false)
+	}
+	return false
+}
+
+// inDateRange checks an inclusive {"from", "until"} range. Either bound may
+// be omitted, but not both.
+func (c *ContextSnapshot) inDateRange(bounds map[string]interface{}) bool {
+	from, hasFrom := bounds["from"].(string)
+	until, hasUntil := bounds["until"].(string)
+	if !hasFrom && !hasUntil {
+		return false
+	}
+	if hasFrom && !c.afterBound(from, true) {
+		return false
+	}
+	if hasUntil && !c.beforeBound(until, true) {
+		return false
+	}
+	return true
+}
+
+// afterBound reports whether the timestamp is after bound: after the whole
+// day for a date, or at/after the instant for an RFC3339 time. With inclusive
+// set, the bound's own day counts.
+func (c *ContextSnapshot) afterBound(bound string, inclusive bool) bool {
+	if t, err := time.Parse(time.RFC3339, bound); err == nil {
+		return !c.Timestamp.Before(t)
+	}
+	day, ok := c.parseDay(bound)
+	if !ok {
+		return false
+	}
+	if inclusive {
+		return !c.Timestamp.Before(day)
+	}
+	return !c.Timestamp.Before(day.AddDate(0, 0, 1))
+}
+
+// beforeBound reports whether the timestamp is before bound: before the day
+// begins for a date, or before the instant for an RFC3339 time. With inclusive
+// set, the bound's own day counts.
+func (c *ContextSnapshot) beforeBound(bound string, inclusive bool) bool {
+	if t, err := time.Parse(time.RFC3339, bound); err == nil {
+		return c.Timestamp.Before(t)
+	}
+	day, ok := c.parseDay(bound)
+	if !ok {
+		return false
+	}
+	if inclusive {
+		return c.Timestamp.Before(day.AddDate(0, 0, 1))
+	}
+	return c.Timestamp.Before(day)
+}
+
+// parseDay parses a YYYY-MM-DD date at midnight in the timestamp's location.
+func (c *ContextSnapshot) parseDay(s string) (time.Time, bool) {
+	day, err := time.ParseInLocation(dateLayout, s, c.Timestamp.Location())
+	return day, err == nil
+}
+
+// lowerStrings lowercases a string or list of strings so weekday names match
+// regardless of case.
+func lowerStrings(v interface{}) interface{} {
+	switch vals := v.(type) {
+	case string:
+		return strings.ToLower(vals)
+	case []interface{}:
+		out := make([]interface{}, len(vals))
+		for i, val := range vals {
+			if s, ok := val.(string); ok {
+				out[i] = strings.ToLower(s)
+			} else {
+				out[i] = val
+			}
+		}
+		return out
+	case []string:
+		out := make([]string, len(vals))
+		for i, s := range vals {
+			out[i] = strings.ToLower(s)
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestContextSnapshot_MatchField_Temporal(t *testing.T) {
+	// Friday, 2026-05-29, mid-afternoon UTC
+	ts := time.Date(2026, 5, 29, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		key      string
+		required interface{}
+		want     bool
+	}{
+		{"weekday match", WhenWeekday, "friday", true},
+		{"weekday case-insensitive", WhenWeekday, "Friday", true},
+		{"weekday mismatch", WhenWeekday, "monday", false},
+		{"weekday list", WhenWeekday, []interface{}{"thursday", "friday"}, true},
+		{"weekend list", WhenWeekday, []interface{}{"saturday", "sunday"}, false},
+		{"date exact", WhenDate, "2026-05-29", true},
+		{"date glob", WhenDate, "2026-05-*", true},
+		{"date other", WhenDate, "2026-05-30", false},
+		{"date range inside", WhenDate, map[string]interface{}{"from": "2026-05-15", "until": "2026-06-01"}, true},
+		{"date range inclusive until", WhenDate, map[string]interface{}{"from": "2026-05-01", "until": "2026-05-29"}, true},
+		{"date range inclusive from", WhenDate, map[string]interface{}{"from": "2026-05-29"}, true},
+		{"date range ended", WhenDate, map[string]interface{}{"until": "2026-05-28"}, false},
+		{"date range empty", WhenDate, map[string]interface{}{}, false},
+		{"after earlier day", WhenAfter, "2026-05-28", true},
+		{"after same day", WhenAfter, "2026-05-29", false},
+		{"after instant", WhenAfter, "2026-05-29T14:00:00Z", true},
+		{"before later day", WhenBefore, "2026-06-01", true},
+		{"before same day", WhenBefore, "2026-05-29", false},
+		{"before instant", WhenBefore, "2026-05-29T14:00:00Z", false},
+		{"malformed never matches", WhenBefore, "June 1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ContextSnapshot{Timestamp: ts}
+			matched, hasValue := ctx.MatchField(tt.key, tt.required)
+			if !hasValue {
+				t.Fatalf("MatchField(%s) should have a value when Timestamp is set", tt.key)
+			}
+			if matched != tt.want {
+				t.Errorf("MatchField(%s, %v) = %v, want %v", tt.key, tt.required, matched, tt.want)
+			}
+		})
+	}
+
+	t.Run("zero timestamp is absent", func(t *testing.T) {
+		ctx := ContextSnapshot{}
+		if matched, hasValue := ctx.MatchField(WhenBefore, "2026-06-01"); matched || hasValue {
+			t.Errorf("MatchField() = %v, %v; want absent", matched, hasValue)
+		}
+	})
+
+	t.Run("GetField reports weekday and date", func(t *testing.T) {
+		ctx := ContextSnapshot{Timestamp: ts}
+		if got := ctx.GetField(WhenWeekday); got != "friday" {
+			t.Errorf("GetField(weekday) = %v, want friday", got)
+		}
+		if got := ctx.GetField(WhenDate); got != "2026-05-29" {
+			t.Errorf("GetField(date) = %v, want 2026-05-29", got)
+		}
+	})
+}
+
+func TestParseTemporalCondition(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantKey   string
+		wantValue interface{}
+		wantErr   bool
+	}{
+		{"weekday=Friday", WhenWeekday, "friday", false},
+		{"weekday=saturday,sunday", WhenWeekday, []interface{}{"saturday", "sunday"}, false},
+		{"date=2026-06-01", WhenDate, "2026-06-01", false},
+		{"date=2026-06-*", WhenDate, "2026-06-*", false},
+		{"date=2026-05-15..2026-06-01", WhenDate, map[string]interface{}{"from": "2026-05-15", "until": "2026-06-01"}, false},
+		{"date=..2026-06-01", WhenDate, map[string]interface{}{"until": "2026-06-01"}, false},
+		{"after=2026-06-01", WhenAfter, "2026-06-01", false},
+		{"before=2026-06-01T17:00:00Z", WhenBefore, "2026-06-01T17:00:00Z", false},
+		{"weekday=funday", "", nil, true},
+		{"date=..", "", nil, true},
+		{"before=soon", "", nil, true},
+		{"language=go", "", nil, true},
+		{"after", "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			key, value, err := ParseTemporalCondition(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTemporalCondition(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if key != tt.wantKey {
+				t.Errorf("key = %q, want %q", key, tt.wantKey)
+			}
+			if !valuesEqual(value, tt.wantValue) {
+				t.Errorf("value = %#v, want %#v", value, tt.wantValue)
+			}
+		})
+	}
+}
+
+func valuesEqual(a, b interface{}) bool {
+	switch av := a.(type) {
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if av[i] != bv[i] {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			if bv[k] != v {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
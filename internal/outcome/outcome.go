@@ -0,0 +1,199 @@
+// Package outcome links downstream results (tests passed, a PR merged, a bug
+// reopened) to the behaviors that were active when the work was done.
+// Outcomes are appended to a log in the .floop directory and aggregated per
+// behavior, so calibration and reports can judge guidance by its results
+// rather than only by whether it was followed.
+package outcome
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LogFile is the outcome log, relative to the .floop directory.
+const LogFile = "outcomes.jsonl"
+
+// Kind is a downstream result. Every kind is either positive or negative.
+type Kind string
+
+const (
+	KindTestsPassed Kind = "tests-passed"
+	KindTestsFailed Kind = "tests-failed"
+	KindBuildPassed Kind = "build-passed"
+	KindBuildFailed Kind = "build-failed"
+	KindPRMerged    Kind = "pr-merged"
+	KindPRRejected  Kind = "pr-rejected"
+	KindBugReopened Kind = "bug-reopened"
+	KindReverted    Kind = "reverted"
+)
+
+// Kinds lists every outcome kind, positive kinds first.
+var Kinds = []Kind{
+	KindTestsPassed, KindBuildPassed, KindPRMerged,
+	KindTestsFailed, KindBuildFailed, KindPRRejected, KindBugReopened, KindReverted,
+}
+
+// Positive reports whether the outcome is a good result.
+func (k Kind) Positive() bool {
+	switch k {
+	case KindTestsPassed, KindBuildPassed, KindPRMerged:
+		return true
+	}
+	return false
+}
+
+// ParseKind validates an outcome kind.
+func ParseKind(s string) (Kind, error) {
+	for _, k := range Kinds {
+		if string(k) == s {
+			return k, nil
+		}
+	}
+	names := make([]string, len(Kinds))
+	for i, k := range Kinds {
+		names[i] = string(k)
+	}
+	return "", fmt.Errorf("invalid outcome %q (use %s)", s, strings.Join(names, ", "))
+}
+
+// Event is one recorded outcome and the behaviors it is linked to.
+type Event struct {
+	ID          string    `json:"id"`
+	Kind        Kind      `json:"kind"`
+	SessionID   string    `json:"session_id,omitempty"`
+	BehaviorIDs []string  `json:"behavior_ids"`
+	Ref         string    `json:"ref,omitempty"`  // e.g. a PR URL or commit
+	Note        string    `json:"note,omitempty"` // free-form detail
+	At          time.Time `json:"at"`
+}
+
+// Record appends an outcome to the log in floopDir.
+func Record(floopDir string, e Event) error {
+	path := filepath.Join(floopDir, LogFile)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open outcome log: %w", err)
+	}
+	defer f.Close()
+
+	if err := json.NewEncoder(f).Encode(e); err != nil {
+		return fmt.Errorf("failed to write outcome: %w", err)
+	}
+	return nil
+}
+
+// Load reads the outcome log in floopDir, oldest first. A missing file
+// yields no outcomes; malformed lines are skipped.
+func Load(floopDir string) ([]Event, error) {
+	f, err := os.Open(filepath.Join(floopDir, LogFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open outcome log: %w", err)
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(line, &e); err != nil {
+			continue
+		}
+		events = append(events, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read outcome log: %w", err)
+	}
+	return events, nil
+}
+
+// Stats aggregates the outcomes linked to one behavior.
+type Stats struct {
+	Positive int          `json:"positive"`
+	Negative int          `json:"negative"`
+	ByKind   map[Kind]int `json:"by_kind"`
+	LastAt   time.Time    `json:"last_at"`
+}
+
+// Total returns the number of linked outcomes.
+func (s Stats) Total() int {
+	return s.Positive + s.Negative
+}
+
+// SuccessRate returns the share of linked outcomes that were positive, or 0
+// when there are none.
+func (s Stats) SuccessRate() float64 {
+	if s.Total() == 0 {
+		return 0
+	}
+	return float64(s.Positive) / float64(s.Total())
+}
+
+// Aggregate computes outcome statistics per behavior ID.
+func Aggregate(events []Event) map[string]Stats {
+	stats := make(map[string]Stats)
+	for _, e := range events {
+		for _, id := range e.BehaviorIDs {
+			s := stats[id]
+			if s.ByKind == nil {
+				s.ByKind = make(map[Kind]int)
+			}
+			if e.Kind.Positive() {
+				s.Positive++
+			} else {
+				s.Negative++
+			}
+			s.ByKind[e.Kind]++
+			if e.At.After(s.LastAt) {
+				s.LastAt = e.At
+			}
+			stats[id] = s
+		}
+	}
+	return stats
+}
+
+// LoadStats loads and aggregates the outcome log in floopDir.
+func LoadStats(floopDir string) (map[string]Stats, error) {
+	events, err := Load(floopDir)
+	if err != nil {
+		return nil, err
+	}
+	return Aggregate(events), nil
+}
+
+// NewEvent builds an outcome linked to the given behaviors, deduplicated and
+// sorted. The ID is derived from the time.
+func NewEvent(kind Kind, sessionID string, behaviorIDs []string, ref, note string, now time.Time) Event {
+	seen := make(map[string]bool, len(behaviorIDs))
+	ids := make([]string, 0, len(behaviorIDs))
+	for _, id := range behaviorIDs {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return Event{
+		ID:          fmt.Sprintf("o-%d", now.UnixNano()),
+		Kind:        kind,
+		SessionID:   sessionID,
+		BehaviorIDs: ids,
+		Ref:         ref,
+		Note:        note,
+		At:          now,
+	}
+}
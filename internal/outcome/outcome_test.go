@@ -0,0 +1,92 @@
+package outcome
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseKind(t *testing.T) {
+	for _, k := range Kinds {
+		if got, err := ParseKind(string(k)); err != nil || got != k {
+			t.Errorf("ParseKind(%q) = %q, %v", k, got, err)
+		}
+	}
+	if _, err := ParseKind("shipped"); err == nil {
+		t.Error("ParseKind(shipped) should fail")
+	}
+}
+
+func TestKindPositive(t *testing.T) {
+	tests := []struct {
+		kind Kind
+		want bool
+	}{
+		{KindTestsPassed, true},
+		{KindBuildPassed, true},
+		{KindPRMerged, true},
+		{KindTestsFailed, false},
+		{KindBuildFailed, false},
+		{KindPRRejected, false},
+		{KindBugReopened, false},
+		{KindReverted, false},
+	}
+	for _, tt := range tests {
+		if got := tt.kind.Positive(); got != tt.want {
+			t.Errorf("%s.Positive() = %v, want %v", tt.kind, got, tt.want)
+		}
+	}
+}
+
+func TestRecordLoadAggregate(t *testing.T) {
+	dir := t.TempDir()
+
+	if events, err := Load(dir); err != nil || events != nil {
+		t.Fatalf("Load(empty) = %v, %v; want nil, nil", events, err)
+	}
+
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, e := range []Event{
+		NewEvent(KindTestsPassed, "s1", []string{"b-1", "b-2", "b-1"}, "", "", base),
+		NewEvent(KindPRMerged, "s1", []string{"b-1"}, "https://example.com/pr/1", "", base.Add(time.Hour)),
+		NewEvent(KindBugReopened, "s2", []string{"b-2"}, "", "regression in parser", base.Add(2*time.Hour)),
+	} {
+		if err := Record(dir, e); err != nil {
+			t.Fatalf("Record(%d) error = %v", i, err)
+		}
+	}
+	// Malformed lines are skipped
+	f, _ := os.OpenFile(filepath.Join(dir, LogFile), os.O_APPEND|os.O_WRONLY, 0600)
+	f.WriteString("not json\n")
+	f.Close()
+
+	events, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("events = %d, want 3", len(events))
+	}
+	if got := events[0].BehaviorIDs; len(got) != 2 || got[0] != "b-1" || got[1] != "b-2" {
+		t.Errorf("BehaviorIDs = %v, want deduplicated [b-1 b-2]", got)
+	}
+
+	stats, err := LoadStats(dir)
+	if err != nil {
+		t.Fatalf("LoadStats() error = %v", err)
+	}
+	b1, b2 := stats["b-1"], stats["b-2"]
+	if b1.Positive != 2 || b1.Negative != 0 || b1.SuccessRate() != 1 {
+		t.Errorf("b-1 = %+v, want 2 positive", b1)
+	}
+	if b2.Positive != 1 || b2.Negative != 1 || b2.SuccessRate() != 0.5 || b2.ByKind[KindBugReopened] != 1 {
+		t.Errorf("b-2 = %+v, want 1 positive and 1 bug-reopened", b2)
+	}
+	if !b2.LastAt.Equal(base.Add(2 * time.Hour)) {
+		t.Errorf("b-2 LastAt = %v, want latest outcome", b2.LastAt)
+	}
+	if (Stats{}).SuccessRate() != 0 {
+		t.Error("empty SuccessRate() should be 0")
+	}
+}
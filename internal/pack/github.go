@@ -15,9 +15,11 @@ import (
 
 // GitHubRelease represents a GitHub release.
 type GitHubRelease struct {
-	TagName string        `json:"tag_name"`
-	Name    string        `json:"name"`
-	Assets  []GitHubAsset `json:"assets"`
+	TagName    string        `json:"tag_name"`
+	Name       string        `json:"name"`
+	Draft      bool          `json:"draft"`
+	Prerelease bool          `json:"prerelease"`
+	Assets     []GitHubAsset `json:"assets"`
 }
 
 // GitHubAsset represents a file attached to a release.
@@ -69,27 +71,12 @@ func (c *GitHubClient) ResolveRelease(ctx context.Context, owner, repo, version
 		endpoint = fmt.Sprintf("%s/repos/%s/%s/releases/tags/%s", c.baseURL, owner, repo, version)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	body, status, err := c.get(ctx, endpoint)
 	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-	req.Header.Set("Accept", "application/vnd.github+json")
-	if c.token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.token)
+		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("fetching release: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20)) // 1MB limit for JSON response
-	if err != nil {
-		return nil, fmt.Errorf("reading response: %w", err)
-	}
-
-	switch resp.StatusCode {
+	switch status {
 	case http.StatusOK:
 		// success
 	case http.StatusNotFound:
@@ -100,7 +87,7 @@ func (c *GitHubClient) ResolveRelease(ctx context.Context, owner, repo, version
 	case http.StatusForbidden:
 		return nil, fmt.Errorf("GitHub API rate limit exceeded for %s/%s; set GITHUB_TOKEN env var to authenticate", owner, repo)
 	default:
-		return nil, fmt.Errorf("GitHub API error %d for %s/%s: %s", resp.StatusCode, owner, repo, string(body))
+		return nil, fmt.Errorf("GitHub API error %d for %s/%s: %s", status, owner, repo, string(body))
 	}
 
 	var release GitHubRelease
@@ -111,6 +98,61 @@ func (c *GitHubClient) ResolveRelease(ctx context.Context, owner, repo, version
 	return &release, nil
 }
 
+// ListReleases fetches the most recent releases, newest first, including
+// prereleases (drafts are only visible with push access).
+func (c *GitHubClient) ListReleases(ctx context.Context, owner, repo string) ([]GitHubRelease, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/releases?per_page=30", c.baseURL, owner, repo)
+
+	body, status, err := c.get(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case http.StatusOK:
+		// success
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("repository %s/%s not found", owner, repo)
+	case http.StatusForbidden:
+		return nil, fmt.Errorf("GitHub API rate limit exceeded for %s/%s; set GITHUB_TOKEN env var to authenticate", owner, repo)
+	default:
+		return nil, fmt.Errorf("GitHub API error %d for %s/%s: %s", status, owner, repo, string(body))
+	}
+
+	var releases []GitHubRelease
+	if err := json.Unmarshal(body, &releases); err != nil {
+		return nil, fmt.Errorf("parsing releases JSON: %w", err)
+	}
+
+	return releases, nil
+}
+
+// get performs an authenticated GET against the GitHub API and returns the
+// response body (up to 1MB) and status code.
+func (c *GitHubClient) get(ctx context.Context, endpoint string) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("fetching release: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20)) // 1MB limit for JSON response
+	if err != nil {
+		return nil, 0, fmt.Errorf("reading response: %w", err)
+	}
+
+	return body, resp.StatusCode, nil
+}
+
 // FindPackAssets returns all .fpack assets from a release.
 func FindPackAssets(release *GitHubRelease) []GitHubAsset {
 	var assets []GitHubAsset
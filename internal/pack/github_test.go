@@ -201,3 +201,32 @@ func TestHTTPCachePath(t *testing.T) {
 }
 
 // contains is defined in format_test.go (same package)
+
+func TestListReleases(t *testing.T) {
+	releases := []GitHubRelease{
+		{TagName: "v1.1.0-rc.1", Prerelease: true},
+		{TagName: "v1.0.0"},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/releases" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(releases)
+	}))
+	defer srv.Close()
+
+	client := newGitHubClientForTest(srv.URL, "")
+	got, err := client.ListReleases(context.Background(), "owner", "repo")
+	if err != nil {
+		t.Fatalf("ListReleases() error = %v", err)
+	}
+	if len(got) != 2 || !got[0].Prerelease || got[1].TagName != "v1.0.0" {
+		t.Errorf("ListReleases() = %+v, want the prerelease then v1.0.0", got)
+	}
+
+	if _, err := client.ListReleases(context.Background(), "owner", "missing"); err == nil {
+		t.Error("ListReleases() on a missing repo should fail")
+	}
+}
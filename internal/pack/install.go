@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/nvandessel/floop/internal/backup"
 	"github.com/nvandessel/floop/internal/config"
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/store"
@@ -38,7 +39,12 @@ func Install(ctx context.Context, s store.GraphStore, filePath string, cfg *conf
 	if err != nil {
 		return nil, fmt.Errorf("reading pack file: %w", err)
 	}
+	return installPack(ctx, s, data, manifest, cfg, opts)
+}
 
+// installPack installs the nodes and edges of a pack read by Install or
+// InstallStarter.
+func installPack(ctx context.Context, s store.GraphStore, data *backup.BackupFormat, manifest *PackManifest, cfg *config.FloopConfig, opts InstallOptions) (*InstallResult, error) {
 	result := &InstallResult{
 		PackID:  string(manifest.ID),
 		Version: manifest.Version,
@@ -87,15 +93,16 @@ func Install(ctx context.Context, s store.GraphStore, filePath string, cfg *conf
 	}
 
 	// 3. Install edges
-	for _, edge := range data.Edges {
-		if err := s.AddEdge(ctx, edge); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to add edge %s -> %s (%s): %v\n",
-				edge.Source, edge.Target, edge.Kind, err)
-			result.EdgesSkipped++
-			continue
-		}
-		result.EdgesAdded++
+	rejected, err := store.BatchAddEdges(ctx, s, data.Edges)
+	if err != nil {
+		return nil, fmt.Errorf("adding edges: %w", err)
+	}
+	for _, r := range rejected {
+		fmt.Fprintf(os.Stderr, "warning: failed to add edge %s -> %s (%s): %v\n",
+			r.Edge.Source, r.Edge.Target, r.Edge.Kind, r.Err)
 	}
+	result.EdgesSkipped += len(rejected)
+	result.EdgesAdded += len(data.Edges) - len(rejected)
 
 	// 4. Sync store
 	if err := s.Sync(ctx); err != nil {
@@ -123,7 +130,8 @@ func Install(ctx context.Context, s store.GraphStore, filePath string, cfg *conf
 	return result, nil
 }
 
-// stampProvenance sets package and package_version in the node's provenance metadata.
+// stampProvenance sets source_type, package, and package_version in the
+// node's provenance metadata.
 func stampProvenance(node *store.Node, manifest *PackManifest) {
 	if node.Metadata == nil {
 		node.Metadata = make(map[string]interface{})
@@ -134,6 +142,7 @@ func stampProvenance(node *store.Node, manifest *PackManifest) {
 		prov = make(map[string]interface{})
 	}
 
+	prov["source_type"] = string(models.SourceTypePack)
 	prov["package"] = string(manifest.ID)
 	prov["package_version"] = manifest.Version
 	node.Metadata["provenance"] = prov
@@ -173,6 +182,7 @@ func recordInstall(cfg *config.FloopConfig, manifest *PackManifest, result *Inst
 type InstallFromSourceOptions struct {
 	DeriveEdges bool
 	AllAssets   bool // install all .fpack assets from a multi-asset GitHub release
+	Registry    RegistryOptions
 }
 
 // InstallFromSource resolves a source string, fetches remote packs if needed,
@@ -182,6 +192,8 @@ type InstallFromSourceOptions struct {
 //   - Local path: ./pack.fpack, /abs/path.fpack
 //   - HTTP URL: https://example.com/pack.fpack
 //   - GitHub shorthand: gh:owner/repo, gh:owner/repo@v1.2.3
+//   - Registry pack: go-standards, go-standards@1
+//   - Starter pack: go-backend, python, react (embedded; see StarterPacks)
 func InstallFromSource(ctx context.Context, s store.GraphStore, source string, cfg *config.FloopConfig, opts InstallFromSourceOptions) ([]*InstallResult, error) {
 	resolved, err := ResolveSource(source)
 	if err != nil {
@@ -219,6 +231,27 @@ func InstallFromSource(ctx context.Context, s store.GraphStore, source string, c
 		}
 		return []*InstallResult{result}, nil
 
+	case SourceRegistry:
+		// Starter packs ship with floop; --registry looks past them
+		if opts.Registry.Registry == "" && IsStarterPack(resolved.Name) {
+			result, err := InstallStarter(ctx, s, resolved.Name, resolved.Version, cfg, installOpts)
+			if err != nil {
+				return nil, err
+			}
+			return []*InstallResult{result}, nil
+		}
+
+		path, _, err := FetchRegistryPack(ctx, cfg, resolved.Name, resolved.Version, opts.Registry)
+		if err != nil {
+			return nil, fmt.Errorf("resolving %s: %w", resolved.Raw, err)
+		}
+
+		result, err := Install(ctx, s, path, cfg, installOpts)
+		if err != nil {
+			return nil, err
+		}
+		return []*InstallResult{result}, nil
+
 	case SourceGitHub:
 		gh := NewGitHubClient()
 
@@ -0,0 +1,394 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// MarkdownFormat identifies a Markdown behavior pack in its pack.md.
+const MarkdownFormat = "floop-behavior-pack/v1"
+
+// MarkdownManifestFile is the file in a Markdown pack directory that holds
+// the pack's metadata and index.
+const MarkdownManifestFile = "pack.md"
+
+// MarkdownManifest is the frontmatter of a Markdown pack's pack.md.
+type MarkdownManifest struct {
+	Format       string    `yaml:"format"`
+	ID           string    `yaml:"id,omitempty"`
+	Version      string    `yaml:"version,omitempty"`
+	Description  string    `yaml:"description,omitempty"`
+	Author       string    `yaml:"author,omitempty"`
+	Tags         []string  `yaml:"tags,omitempty"`
+	ExportedAt   time.Time `yaml:"exported_at"`
+	FloopVersion string    `yaml:"floop_version,omitempty"`
+	Behaviors    int       `yaml:"behaviors"`
+}
+
+// MarkdownEdge is an outbound edge of a behavior in a Markdown pack.
+type MarkdownEdge struct {
+	Kind   store.EdgeKind `yaml:"kind"`
+	Target string         `yaml:"target"`
+	Weight float64        `yaml:"weight"`
+}
+
+// MarkdownBehavior is one behavior file of a Markdown pack: YAML frontmatter
+// followed by the canonical content as the Markdown body.
+type MarkdownBehavior struct {
+	ID         string                 `yaml:"id"`
+	Name       string                 `yaml:"name"`
+	Kind       models.BehaviorKind    `yaml:"kind"`
+	When       map[string]interface{} `yaml:"when,omitempty"`
+	Summary    string                 `yaml:"summary,omitempty"`
+	Tags       []string               `yaml:"tags,omitempty"`
+	Structured map[string]interface{} `yaml:"structured,omitempty"`
+	Confidence float64                `yaml:"confidence"`
+	Priority   int                    `yaml:"priority"`
+	ExpiresAt  *time.Time             `yaml:"expires_at,omitempty"`
+	Edges      []MarkdownEdge         `yaml:"edges,omitempty"`
+	Provenance models.Provenance      `yaml:"provenance"`
+	Stats      models.BehaviorStats   `yaml:"stats"`
+
+	// Canonical is the file's body, not frontmatter.
+	Canonical string `yaml:"-"`
+
+	// File is the file the behavior was read from or written to, relative
+	// to the pack directory.
+	File string `yaml:"-"`
+}
+
+// MarkdownPack is a Markdown behavior pack: a directory with a pack.md and
+// one .md file per behavior, meant to be reviewed and shared through a repo.
+type MarkdownPack struct {
+	Manifest  MarkdownManifest
+	Behaviors []MarkdownBehavior
+}
+
+// MarkdownExportOptions configures ExportMarkdown.
+type MarkdownExportOptions struct {
+	FloopVersion string
+
+	// Force clears existing .md files from a non-empty output directory.
+	Force bool
+}
+
+// ExportMarkdown writes the active behaviors in s that pass filter, with the
+// edges between them, as a Markdown pack in dir.
+func ExportMarkdown(ctx context.Context, s store.GraphStore, filter CreateFilter, manifest PackManifest, dir string, opts MarkdownExportOptions) (*MarkdownPack, error) {
+	if manifest.ID != "" {
+		if err := ValidatePackID(string(manifest.ID)); err != nil {
+			return nil, err
+		}
+	}
+
+	nodes, err := s.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, fmt.Errorf("querying nodes: %w", err)
+	}
+
+	included := make(map[string]bool)
+	var selected []store.Node
+	for _, node := range nodes {
+		if matchesFilter(node, filter) {
+			included[node.ID] = true
+			selected = append(selected, node)
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].ID < selected[j].ID })
+
+	p := &MarkdownPack{
+		Manifest: MarkdownManifest{
+			Format:       MarkdownFormat,
+			ID:           string(manifest.ID),
+			Version:      manifest.Version,
+			Description:  manifest.Description,
+			Author:       manifest.Author,
+			Tags:         manifest.Tags,
+			ExportedAt:   time.Now().UTC().Truncate(time.Second),
+			FloopVersion: opts.FloopVersion,
+			Behaviors:    len(selected),
+		},
+	}
+	used := make(map[string]bool)
+	for _, node := range selected {
+		b := models.NodeToBehavior(node)
+		mb := MarkdownBehavior{
+			ID:         b.ID,
+			Name:       b.Name,
+			Kind:       b.Kind,
+			When:       b.When,
+			Summary:    b.Content.Summary,
+			Tags:       b.Content.Tags,
+			Structured: b.Content.Structured,
+			Confidence: b.Confidence,
+			Priority:   b.Priority,
+			ExpiresAt:  b.ExpiresAt,
+			Provenance: b.Provenance,
+			Stats:      b.Stats,
+			Canonical:  b.Content.Canonical,
+			File:       behaviorFileName(b, used),
+		}
+
+		edges, err := s.GetEdges(ctx, node.ID, store.DirectionOutbound, "")
+		if err != nil {
+			return nil, fmt.Errorf("getting edges for %s: %w", node.ID, err)
+		}
+		for _, e := range edges {
+			if included[e.Target] {
+				mb.Edges = append(mb.Edges, MarkdownEdge{Kind: e.Kind, Target: e.Target, Weight: e.Weight})
+			}
+		}
+		sort.Slice(mb.Edges, func(i, j int) bool {
+			if mb.Edges[i].Target != mb.Edges[j].Target {
+				return mb.Edges[i].Target < mb.Edges[j].Target
+			}
+			return mb.Edges[i].Kind < mb.Edges[j].Kind
+		})
+		p.Behaviors = append(p.Behaviors, mb)
+	}
+
+	if err := WriteMarkdownPack(dir, p, opts.Force); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// WriteMarkdownPack writes p to dir, which must be empty or missing unless
+// force is set, in which case its .md files are removed first.
+func WriteMarkdownPack(dir string, p *MarkdownPack, force bool) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading %s: %w", dir, err)
+	}
+	if len(entries) > 0 {
+		if !force {
+			return fmt.Errorf("%s is not empty (use --force to replace its .md files)", dir)
+		}
+		for _, e := range entries {
+			if !e.IsDir() && strings.HasSuffix(e.Name(), ".md") {
+				if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+					return fmt.Errorf("removing %s: %w", e.Name(), err)
+				}
+			}
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", dir, err)
+	}
+
+	for _, b := range p.Behaviors {
+		data, err := marshalFrontmatter(b, b.Canonical)
+		if err != nil {
+			return fmt.Errorf("encoding %s: %w", b.ID, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, b.File), data, 0644); err != nil {
+			return fmt.Errorf("writing %s: %w", b.File, err)
+		}
+	}
+
+	data, err := marshalFrontmatter(p.Manifest, manifestBody(p))
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", MarkdownManifestFile, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, MarkdownManifestFile), data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", MarkdownManifestFile, err)
+	}
+	return nil
+}
+
+// ReadMarkdownPack reads the Markdown pack in dir. Behavior files are read
+// in name order; .md files without frontmatter, such as a README, are
+// ignored.
+func ReadMarkdownPack(dir string) (*MarkdownPack, error) {
+	p, err := ReadMarkdownPackFS(os.DirFS(dir), ".")
+	if errors.Is(err, errNoManifest) {
+		return nil, fmt.Errorf("%s is not a behavior pack: missing %s", dir, MarkdownManifestFile)
+	}
+	return p, err
+}
+
+// errNoManifest is returned for a directory without a pack.md.
+var errNoManifest = errors.New("missing " + MarkdownManifestFile)
+
+// ReadMarkdownPackFS reads the Markdown pack in dir of fsys, as
+// ReadMarkdownPack does.
+func ReadMarkdownPackFS(fsys fs.FS, dir string) (*MarkdownPack, error) {
+	p := &MarkdownPack{}
+	if _, err := readFrontmatter(fsys, path.Join(dir, MarkdownManifestFile), &p.Manifest); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%s: %w", dir, errNoManifest)
+		}
+		return nil, err
+	}
+	if p.Manifest.Format != MarkdownFormat {
+		return nil, fmt.Errorf("%s: unsupported pack format %q (want %s)", MarkdownManifestFile, p.Manifest.Format, MarkdownFormat)
+	}
+
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", dir, err)
+	}
+	seen := make(map[string]string)
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".md") || name == MarkdownManifestFile {
+			continue
+		}
+		var b MarkdownBehavior
+		body, err := readFrontmatter(fsys, path.Join(dir, name), &b)
+		if errors.Is(err, errNoFrontmatter) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		b.Canonical = body
+		b.File = name
+		if b.ID == "" || b.Name == "" || b.Kind == "" {
+			return nil, fmt.Errorf("%s: id, name, and kind are required", name)
+		}
+		if b.Canonical == "" {
+			return nil, fmt.Errorf("%s: behavior content is empty", name)
+		}
+		if other, ok := seen[b.ID]; ok {
+			return nil, fmt.Errorf("%s: behavior %s is also defined in %s", name, b.ID, other)
+		}
+		seen[b.ID] = name
+		p.Behaviors = append(p.Behaviors, b)
+	}
+	return p, nil
+}
+
+// Behavior converts b to a behavior, dropping its usage stats: they describe
+// use in the exporting store, not the importing one.
+func (b MarkdownBehavior) Behavior() models.Behavior {
+	return models.Behavior{
+		ID:   b.ID,
+		Name: b.Name,
+		Kind: b.Kind,
+		When: b.When,
+		Content: models.BehaviorContent{
+			Canonical:  b.Canonical,
+			Summary:    b.Summary,
+			Tags:       b.Tags,
+			Structured: b.Structured,
+		},
+		Provenance: b.Provenance,
+		Confidence: b.Confidence,
+		Priority:   b.Priority,
+		ExpiresAt:  b.ExpiresAt,
+	}
+}
+
+var errNoFrontmatter = errors.New("no frontmatter")
+
+// marshalFrontmatter renders v as YAML frontmatter followed by body.
+func marshalFrontmatter(v interface{}, body string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	buf.WriteString("---\n\n")
+	buf.WriteString(strings.TrimSpace(body))
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// readFrontmatter decodes the YAML frontmatter of the named file of fsys
+// into v and returns the trimmed body.
+func readFrontmatter(fsys fs.FS, name string, v interface{}) (string, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return "", err
+	}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if !strings.HasPrefix(text, "---\n") {
+		return "", errNoFrontmatter
+	}
+	rest := text[len("---\n"):]
+	end := strings.Index(rest, "\n---\n")
+	var front, body string
+	switch {
+	case end >= 0:
+		front, body = rest[:end], rest[end+len("\n---\n"):]
+	case strings.HasSuffix(rest, "\n---"):
+		front = strings.TrimSuffix(rest, "\n---")
+	default:
+		return "", fmt.Errorf("%s: unterminated frontmatter", path.Base(name))
+	}
+	if err := yaml.Unmarshal([]byte(front), v); err != nil {
+		return "", fmt.Errorf("%s: invalid frontmatter: %w", path.Base(name), err)
+	}
+	return strings.TrimSpace(body), nil
+}
+
+// manifestBody renders pack.md's body: the description and an index of the
+// pack's behaviors.
+func manifestBody(p *MarkdownPack) string {
+	var sb strings.Builder
+	title := p.Manifest.ID
+	if title == "" {
+		title = "Behavior pack"
+	}
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+	if p.Manifest.Description != "" {
+		fmt.Fprintf(&sb, "%s\n\n", p.Manifest.Description)
+	}
+	sb.WriteString("## Behaviors\n\n")
+	if len(p.Behaviors) == 0 {
+		sb.WriteString("_None._\n")
+	}
+	for _, b := range p.Behaviors {
+		line := b.Summary
+		if line == "" {
+			line, _, _ = strings.Cut(b.Canonical, "\n")
+		}
+		fmt.Fprintf(&sb, "- [%s](%s) (%s): %s\n", b.Name, b.File, b.Kind, line)
+	}
+	return sb.String()
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// behaviorFileName returns a file name for b derived from its name, unique
+// among used.
+func behaviorFileName(b models.Behavior, used map[string]bool) string {
+	base := strings.Trim(unsafeFileChars.ReplaceAllString(strings.ToLower(b.Name), "-"), "-.")
+	if base == "" {
+		base = "behavior"
+	}
+	if len(base) > 64 {
+		base = strings.TrimRight(base[:64], "-.")
+	}
+	name := base + ".md"
+	if used[name] || name == MarkdownManifestFile {
+		short := unsafeFileChars.ReplaceAllString(strings.ToLower(b.ID), "-")
+		if len(short) > 12 {
+			short = short[len(short)-12:]
+		}
+		name = base + "-" + short + ".md"
+	}
+	used[name] = true
+	return name
+}
@@ -0,0 +1,168 @@
+package pack
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/dedup"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// MarkdownImportOptions configures ImportMarkdown.
+type MarkdownImportOptions struct {
+	// DryRun reports what would be imported without writing.
+	DryRun bool
+
+	// Scope is the store new behaviors are written to when s supports
+	// scoped writes. Empty means the store's default.
+	Scope store.StoreScope
+
+	// SimilarityThreshold is the score at or above which a pack behavior
+	// duplicates an existing one. Zero uses the dedup default.
+	SimilarityThreshold float64
+}
+
+// MarkdownDuplicate is a pack behavior skipped because an existing behavior
+// already covers it.
+type MarkdownDuplicate struct {
+	ID         string  `json:"id"`
+	ExistingID string  `json:"existing_id"`
+	Similarity float64 `json:"similarity"`
+}
+
+// MarkdownImportResult reports what ImportMarkdown did, or would do.
+type MarkdownImportResult struct {
+	Added      []string            `json:"added"`
+	Existing   []string            `json:"existing"`   // Same ID already in the store
+	Duplicates []MarkdownDuplicate `json:"duplicates"` // Similar behavior already in the store
+	EdgesAdded int                 `json:"edges_added"`
+	DryRun     bool                `json:"dry_run"`
+}
+
+// scopedAdder is implemented by stores that can write to a chosen scope.
+type scopedAdder interface {
+	AddNodeToScope(ctx context.Context, node store.Node, scope store.StoreScope) (string, error)
+}
+
+// ImportMarkdown adds the behaviors of p to s. A behavior whose ID is
+// already in the store (even forgotten) is left alone, and one similar to an
+// existing behavior is skipped as a duplicate; edges pointing at a skipped
+// behavior are redirected to the one already there. Behaviors are stamped
+// with the pack's ID and version when it has them.
+func ImportMarkdown(ctx context.Context, s store.GraphStore, p *MarkdownPack, opts MarkdownImportOptions) (*MarkdownImportResult, error) {
+	threshold := opts.SimilarityThreshold
+	if threshold == 0 {
+		threshold = constants.DefaultAutoMergeThreshold
+	}
+	dd := dedup.NewStoreDeduplicator(s, nil, dedup.DeduplicatorConfig{SimilarityThreshold: threshold})
+
+	result := &MarkdownImportResult{DryRun: opts.DryRun}
+	resolved := make(map[string]string, len(p.Behaviors))
+	added := make(map[string]bool)
+	for _, mb := range p.Behaviors {
+		existing, err := s.GetNode(ctx, mb.ID)
+		if err != nil {
+			return nil, fmt.Errorf("checking node %s: %w", mb.ID, err)
+		}
+		if existing != nil {
+			result.Existing = append(result.Existing, mb.ID)
+			if existing.Kind == store.NodeKindBehavior {
+				resolved[mb.ID] = mb.ID
+			}
+			continue
+		}
+
+		b := mb.Behavior()
+		matches, err := dd.FindDuplicates(ctx, &b)
+		if err != nil {
+			return nil, fmt.Errorf("checking duplicates of %s: %w", mb.ID, err)
+		}
+		if match := firstPreexisting(matches, added); match != nil {
+			result.Duplicates = append(result.Duplicates, MarkdownDuplicate{
+				ID:         mb.ID,
+				ExistingID: match.Behavior.ID,
+				Similarity: match.Similarity,
+			})
+			resolved[mb.ID] = match.Behavior.ID
+			continue
+		}
+
+		resolved[mb.ID] = mb.ID
+		added[mb.ID] = true
+		result.Added = append(result.Added, mb.ID)
+		if opts.DryRun {
+			continue
+		}
+		if p.Manifest.ID != "" {
+			b.Provenance.Package = p.Manifest.ID
+			b.Provenance.PackageVersion = p.Manifest.Version
+		}
+		if b.Provenance.SourceType == "" {
+			b.Provenance.SourceType = models.SourceTypeImported
+		}
+		if err := addNode(ctx, s, models.BehaviorToNode(&b), opts.Scope); err != nil {
+			return nil, fmt.Errorf("adding node %s: %w", mb.ID, err)
+		}
+	}
+
+	// Edges are added once every endpoint exists. Only edges touching an
+	// added behavior are new; the rest are already in the store or were
+	// deliberately left out of it.
+	now := time.Now()
+	var newEdges []store.Edge
+	for _, mb := range p.Behaviors {
+		for _, e := range mb.Edges {
+			source, target := resolved[mb.ID], resolved[e.Target]
+			if source == "" || target == "" || source == target || (!added[mb.ID] && !added[e.Target]) {
+				continue
+			}
+			result.EdgesAdded++
+			if opts.DryRun {
+				continue
+			}
+			weight := e.Weight
+			if weight == 0 {
+				weight = 1 // Hand-written edges may omit it
+			}
+			newEdges = append(newEdges, store.Edge{Source: source, Target: target, Kind: e.Kind, Weight: weight, CreatedAt: now})
+		}
+	}
+	rejected, err := store.BatchAddEdges(ctx, s, newEdges)
+	if err != nil {
+		return nil, fmt.Errorf("adding edges: %w", err)
+	}
+	if len(rejected) > 0 {
+		r := rejected[0]
+		return nil, fmt.Errorf("adding edge %s -> %s (%s): %w", r.Edge.Source, r.Edge.Target, r.Edge.Kind, r.Err)
+	}
+
+	if !opts.DryRun {
+		if err := s.Sync(ctx); err != nil {
+			return nil, fmt.Errorf("syncing after import: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// firstPreexisting returns the best match that isn't from this import, so a
+// dry run, which adds nothing, finds the same duplicates as a real one.
+func firstPreexisting(matches []dedup.DuplicateMatch, added map[string]bool) *dedup.DuplicateMatch {
+	for i := range matches {
+		if !added[matches[i].Behavior.ID] {
+			return &matches[i]
+		}
+	}
+	return nil
+}
+
+func addNode(ctx context.Context, s store.GraphStore, node store.Node, scope store.StoreScope) error {
+	if sa, ok := s.(scopedAdder); ok && scope != "" {
+		_, err := sa.AddNodeToScope(ctx, node, scope)
+		return err
+	}
+	_, err := s.AddNode(ctx, node)
+	return err
+}
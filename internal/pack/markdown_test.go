@@ -0,0 +1,233 @@
+package pack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestMarkdownPack_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	s := makeTestStore(t)
+	node, _ := s.GetNode(ctx, "b-2")
+	node.Content["when"] = map[string]interface{}{"language": "python"}
+	s.UpdateNode(ctx, *node)
+
+	dir := filepath.Join(t.TempDir(), "pack")
+	manifest := PackManifest{ID: "test-org/shared", Version: "1.0.0", Description: "Shared team behaviors"}
+	exported, err := ExportMarkdown(ctx, s, CreateFilter{}, manifest, dir, MarkdownExportOptions{FloopVersion: "v1.2.3"})
+	if err != nil {
+		t.Fatalf("ExportMarkdown() error = %v", err)
+	}
+	if len(exported.Behaviors) != 3 {
+		t.Fatalf("exported %d behaviors, want 3", len(exported.Behaviors))
+	}
+
+	index, err := os.ReadFile(filepath.Join(dir, MarkdownManifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"format: " + MarkdownFormat, "# test-org/shared", "[prefer-pathlib](prefer-pathlib.md)"} {
+		if !strings.Contains(string(index), want) {
+			t.Errorf("pack.md missing %q:\n%s", want, index)
+		}
+	}
+
+	got, err := ReadMarkdownPack(dir)
+	if err != nil {
+		t.Fatalf("ReadMarkdownPack() error = %v", err)
+	}
+	if got.Manifest.ID != "test-org/shared" || got.Manifest.FloopVersion != "v1.2.3" || got.Manifest.Behaviors != 3 {
+		t.Errorf("manifest = %+v", got.Manifest)
+	}
+	if len(got.Behaviors) != 3 {
+		t.Fatalf("read %d behaviors, want 3", len(got.Behaviors))
+	}
+
+	byID := make(map[string]MarkdownBehavior)
+	for _, b := range got.Behaviors {
+		byID[b.ID] = b
+	}
+	b1 := byID["b-1"]
+	if b1.Canonical != "Use go test for testing" || b1.Confidence != 0.9 || !reflect.DeepEqual(b1.Tags, []string{"go", "testing"}) {
+		t.Errorf("b-1 = %+v", b1)
+	}
+	if b1.Provenance.Package != "test-org/go-pack" {
+		t.Errorf("b-1 provenance = %+v", b1.Provenance)
+	}
+	wantEdges := []MarkdownEdge{
+		{Kind: store.EdgeKindSimilarTo, Target: "b-2", Weight: 0.5},
+		{Kind: store.EdgeKindSimilarTo, Target: "b-3", Weight: 0.8},
+	}
+	if !reflect.DeepEqual(b1.Edges, wantEdges) {
+		t.Errorf("b-1 edges = %+v, want %+v", b1.Edges, wantEdges)
+	}
+	if lang := byID["b-2"].When["language"]; lang != "python" {
+		t.Errorf("b-2 when = %v", byID["b-2"].When)
+	}
+}
+
+func TestExportMarkdown_NonEmptyDir(t *testing.T) {
+	ctx := context.Background()
+	s := makeTestStore(t)
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "stale.md"), []byte("---\nid: old\n---\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("keep"), 0644)
+
+	if _, err := ExportMarkdown(ctx, s, CreateFilter{}, PackManifest{}, dir, MarkdownExportOptions{}); err == nil {
+		t.Fatal("ExportMarkdown() into a non-empty dir should fail without Force")
+	}
+	if _, err := ExportMarkdown(ctx, s, CreateFilter{Tags: []string{"python"}}, PackManifest{}, dir, MarkdownExportOptions{Force: true}); err != nil {
+		t.Fatalf("ExportMarkdown(Force) error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "stale.md")); !os.IsNotExist(err) {
+		t.Error("stale.md should have been removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Error("notes.txt should have been kept")
+	}
+	got, err := ReadMarkdownPack(dir)
+	if err != nil {
+		t.Fatalf("ReadMarkdownPack() error = %v", err)
+	}
+	if len(got.Behaviors) != 1 || got.Behaviors[0].ID != "b-2" {
+		t.Errorf("behaviors = %+v, want only b-2", got.Behaviors)
+	}
+}
+
+func TestReadMarkdownPack_HandWritten(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := ReadMarkdownPack(dir); err == nil {
+		t.Error("ReadMarkdownPack() without pack.md should fail")
+	}
+
+	write(MarkdownManifestFile, "---\nformat: "+MarkdownFormat+"\n---\n")
+	write("README.md", "# About this pack\n")
+	write("wrap-errors.md", "---\r\nid: wrap-errors\r\nname: wrap-errors\r\nkind: directive\r\nedges:\r\n  - kind: requires\r\n    target: other\r\n---\r\n\r\nWrap errors with %w.\r\n")
+
+	got, err := ReadMarkdownPack(dir)
+	if err != nil {
+		t.Fatalf("ReadMarkdownPack() error = %v", err)
+	}
+	if len(got.Behaviors) != 1 {
+		t.Fatalf("read %d behaviors, want 1 (README ignored)", len(got.Behaviors))
+	}
+	if b := got.Behaviors[0]; b.Canonical != "Wrap errors with %w." || len(b.Edges) != 1 || b.File != "wrap-errors.md" {
+		t.Errorf("behavior = %+v", b)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"missing kind", "---\nid: x\nname: x\n---\n\nText\n"},
+		{"empty body", "---\nid: x\nname: x\nkind: directive\n---\n"},
+		{"unterminated", "---\nid: x\nname: x\n"},
+		{"duplicate id", "---\nid: wrap-errors\nname: again\nkind: directive\n---\n\nText\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			write("z-bad.md", tt.content)
+			defer os.Remove(filepath.Join(dir, "z-bad.md"))
+			if _, err := ReadMarkdownPack(dir); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestImportMarkdown(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "pack")
+	if _, err := ExportMarkdown(ctx, makeTestStore(t), CreateFilter{}, PackManifest{ID: "test-org/shared", Version: "2.0.0"}, dir, MarkdownExportOptions{}); err != nil {
+		t.Fatalf("ExportMarkdown() error = %v", err)
+	}
+	p, err := ReadMarkdownPack(dir)
+	if err != nil {
+		t.Fatalf("ReadMarkdownPack() error = %v", err)
+	}
+
+	// Target store: b-3 by ID, and b-2 under a different ID
+	target := store.NewInMemoryGraphStore()
+	target.AddNode(ctx, store.Node{ID: "b-3", Kind: store.NodeKindBehavior, Content: map[string]interface{}{
+		"name": "no-panic", "kind": "constraint", "content": map[string]interface{}{"canonical": "Never use panic"},
+	}})
+	target.AddNode(ctx, store.Node{ID: "local-pathlib", Kind: store.NodeKindBehavior, Content: map[string]interface{}{
+		"name": "prefer-pathlib", "kind": "preference",
+		"content": map[string]interface{}{"canonical": "Prefer pathlib over os.path", "tags": []interface{}{"python"}},
+	}})
+
+	check := func(t *testing.T, result *MarkdownImportResult) {
+		t.Helper()
+		if !reflect.DeepEqual(result.Added, []string{"b-1"}) {
+			t.Errorf("Added = %v, want [b-1]", result.Added)
+		}
+		if !reflect.DeepEqual(result.Existing, []string{"b-3"}) {
+			t.Errorf("Existing = %v, want [b-3]", result.Existing)
+		}
+		if len(result.Duplicates) != 1 || result.Duplicates[0].ID != "b-2" || result.Duplicates[0].ExistingID != "local-pathlib" {
+			t.Errorf("Duplicates = %+v, want b-2 -> local-pathlib", result.Duplicates)
+		}
+		if result.EdgesAdded != 2 {
+			t.Errorf("EdgesAdded = %d, want 2", result.EdgesAdded)
+		}
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		result, err := ImportMarkdown(ctx, target, p, MarkdownImportOptions{DryRun: true})
+		if err != nil {
+			t.Fatalf("ImportMarkdown() error = %v", err)
+		}
+		check(t, result)
+		if n, _ := target.GetNode(ctx, "b-1"); n != nil {
+			t.Error("dry run added b-1")
+		}
+	})
+
+	t.Run("import", func(t *testing.T) {
+		result, err := ImportMarkdown(ctx, target, p, MarkdownImportOptions{})
+		if err != nil {
+			t.Fatalf("ImportMarkdown() error = %v", err)
+		}
+		check(t, result)
+
+		node, _ := target.GetNode(ctx, "b-1")
+		if node == nil {
+			t.Fatal("b-1 was not added")
+		}
+		b := models.NodeToBehavior(*node)
+		if b.Provenance.Package != "test-org/shared" || b.Provenance.PackageVersion != "2.0.0" || b.Stats.TimesActivated != 0 {
+			t.Errorf("b-1 = %+v", b)
+		}
+		edges, _ := target.GetEdges(ctx, "b-1", store.DirectionOutbound, "")
+		targets := make(map[string]bool)
+		for _, e := range edges {
+			targets[e.Target] = true
+		}
+		if !targets["b-3"] || !targets["local-pathlib"] {
+			t.Errorf("b-1 edges = %+v, want b-3 and local-pathlib", edges)
+		}
+
+		// Importing again finds everything already present
+		again, err := ImportMarkdown(ctx, target, p, MarkdownImportOptions{})
+		if err != nil {
+			t.Fatalf("ImportMarkdown() again error = %v", err)
+		}
+		if len(again.Added) != 0 || again.EdgesAdded != 0 {
+			t.Errorf("second import = %+v, want nothing added", again)
+		}
+	})
+}
@@ -0,0 +1,400 @@
+package pack
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/config"
+)
+
+// RegistryIndexFile is the file at the root of a registry listing its packs.
+const RegistryIndexFile = "index.json"
+
+// RegistryIndex lists the packs a registry offers.
+type RegistryIndex struct {
+	Packs []RegistryPack `json:"packs"`
+}
+
+// RegistryPack is one pack in a registry index.
+type RegistryPack struct {
+	Name        string            `json:"name"` // short name used to install, e.g. "go-standards"
+	ID          string            `json:"id"`   // pack ID in namespace/name format
+	Description string            `json:"description,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Versions    []RegistryVersion `json:"versions"`
+}
+
+// RegistryVersion is one published version of a registry pack.
+type RegistryVersion struct {
+	Version   string `json:"version"`
+	File      string `json:"file"`                // .fpack path relative to the registry root
+	SHA256    string `json:"sha256"`              // hex digest of the .fpack file
+	Signature string `json:"signature,omitempty"` // base64 ed25519 signature of SignedPayload
+}
+
+// RegistryOptions configures installing and listing registry packs.
+type RegistryOptions struct {
+	// Registry selects a configured registry by name, or is a registry URL
+	// or directory used directly. Empty searches every configured registry.
+	Registry string
+	// PublicKey overrides the signing key of the selected registry.
+	PublicKey string
+	// AllowUnsigned installs packs that are unsigned or from a registry
+	// without a public key. Checksums are verified regardless.
+	AllowUnsigned bool
+}
+
+// AvailablePack is a registry pack offered for install.
+type AvailablePack struct {
+	Registry    string   `json:"registry"`
+	Name        string   `json:"name"`
+	ID          string   `json:"id"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Latest      string   `json:"latest"`
+	Versions    []string `json:"versions"`
+	Signed      bool     `json:"signed"` // the latest version carries a signature
+}
+
+// SelectRegistries returns the registries opts.Registry selects from cfg.
+func SelectRegistries(cfg *config.FloopConfig, opts RegistryOptions) ([]config.Registry, error) {
+	var registries []config.Registry
+	if cfg != nil {
+		registries = cfg.Packs.Registries
+	}
+
+	if opts.Registry == "" {
+		if len(registries) == 0 {
+			return nil, fmt.Errorf("no pack registries configured; add one under packs.registries in config or pass --registry")
+		}
+		selected := append([]config.Registry(nil), registries...)
+		if opts.PublicKey != "" {
+			for i := range selected {
+				selected[i].PublicKey = opts.PublicKey
+			}
+		}
+		return selected, nil
+	}
+
+	for _, r := range registries {
+		if r.Name == opts.Registry {
+			if opts.PublicKey != "" {
+				r.PublicKey = opts.PublicKey
+			}
+			return []config.Registry{r}, nil
+		}
+	}
+
+	// Not a configured name: use it as a registry location
+	if !isRemoteRegistry(opts.Registry) && !strings.ContainsAny(opts.Registry, `/\`) {
+		return nil, fmt.Errorf("unknown registry %q", opts.Registry)
+	}
+	return []config.Registry{{Name: opts.Registry, URL: opts.Registry, PublicKey: opts.PublicKey}}, nil
+}
+
+// LoadRegistryIndex reads the index of a registry, fetching it fresh for
+// remote registries.
+func LoadRegistryIndex(ctx context.Context, reg config.Registry) (*RegistryIndex, error) {
+	var data []byte
+	if isRemoteRegistry(reg.URL) {
+		indexURL, err := url.JoinPath(reg.URL, RegistryIndexFile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid registry URL %q: %w", reg.URL, err)
+		}
+		cacheDir, err := DefaultCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("getting cache directory: %w", err)
+		}
+		fetched, err := Fetch(ctx, indexURL, registryCachePath(cacheDir, reg.URL, RegistryIndexFile), FetchOptions{Force: true})
+		if err != nil {
+			return nil, fmt.Errorf("fetching registry index: %w", err)
+		}
+		data, err = os.ReadFile(fetched.LocalPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading registry index: %w", err)
+		}
+	} else {
+		var err error
+		data, err = os.ReadFile(filepath.Join(reg.URL, RegistryIndexFile))
+		if err != nil {
+			return nil, fmt.Errorf("reading registry index: %w", err)
+		}
+	}
+
+	var index RegistryIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("parsing registry index of %s: %w", reg.Name, err)
+	}
+	return &index, nil
+}
+
+// Find returns the highest version of the named pack matching constraint.
+// See MatchVersion for the constraint syntax.
+func (idx *RegistryIndex) Find(name, constraint string) (*RegistryPack, *RegistryVersion, error) {
+	for i := range idx.Packs {
+		p := &idx.Packs[i]
+		if p.Name != name {
+			continue
+		}
+		var best *RegistryVersion
+		for j := range p.Versions {
+			v := &p.Versions[j]
+			if !MatchVersion(v.Version, constraint) {
+				continue
+			}
+			if best == nil || compareVersions(v.Version, best.Version) > 0 {
+				best = v
+			}
+		}
+		if best == nil {
+			return nil, nil, fmt.Errorf("pack %s has no version matching %q", name, constraint)
+		}
+		return p, best, nil
+	}
+	return nil, nil, fmt.Errorf("pack %s not found", name)
+}
+
+// MatchVersion reports whether version satisfies constraint. An empty
+// constraint matches any version; otherwise each dot-separated component of
+// the constraint must equal the version's, so "1" matches 1.x.y and "1.2"
+// matches 1.2.y. A leading "v" is ignored on both.
+func MatchVersion(version, constraint string) bool {
+	constraint = strings.TrimPrefix(constraint, "v")
+	if constraint == "" {
+		return true
+	}
+	want := strings.Split(constraint, ".")
+	have := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(have) < len(want) {
+		return false
+	}
+	for i := range want {
+		if have[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// compareVersions compares dotted versions component-wise, numerically where
+// both components are numbers, returning -1, 0, or 1.
+func compareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xErr := strconv.Atoi(x)
+		yn, yErr := strconv.Atoi(y)
+		switch {
+		case xErr == nil && yErr == nil:
+			if xn != yn {
+				return cmpInt(xn, yn)
+			}
+		case x != y:
+			return strings.Compare(x, y)
+		}
+	}
+	return 0
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// SignedPayload returns the bytes a registry signs for version v of pack
+// name. It binds the name and version to the file's checksum, so an index
+// entry cannot serve another pack, or an older version, under a signature
+// made for something else.
+func SignedPayload(name string, v RegistryVersion) []byte {
+	payload, _ := json.Marshal(struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		SHA256  string `json:"sha256"`
+	}{name, v.Version, strings.ToLower(v.SHA256)})
+	return payload
+}
+
+// VerifyPack checks pack file bytes against the checksum and signature its
+// registry published for version v of pack name. publicKey is the
+// registry's base64 ed25519 key.
+func VerifyPack(data []byte, name string, v RegistryVersion, publicKey string, allowUnsigned bool) error {
+	sum := sha256.Sum256(data)
+	if !strings.EqualFold(hex.EncodeToString(sum[:]), v.SHA256) {
+		return fmt.Errorf("checksum mismatch for %s", v.File)
+	}
+
+	if v.Signature == "" || publicKey == "" {
+		if allowUnsigned {
+			return nil
+		}
+		if v.Signature == "" {
+			return fmt.Errorf("%s is unsigned; use --allow-unsigned to install it anyway", v.File)
+		}
+		return fmt.Errorf("registry has no public_key to verify %s; configure one or use --allow-unsigned", v.File)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid registry public key: want base64 ed25519 key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(v.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature for %s: %w", v.File, err)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), SignedPayload(name, v), sig) {
+		return fmt.Errorf("signature verification failed for %s %s", name, v.Version)
+	}
+	return nil
+}
+
+// FetchRegistryPack resolves name@constraint in the selected registries, in
+// order, and returns the path of a verified local copy of the pack file.
+func FetchRegistryPack(ctx context.Context, cfg *config.FloopConfig, name, constraint string, opts RegistryOptions) (string, *RegistryVersion, error) {
+	registries, err := SelectRegistries(cfg, opts)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var lastErr error
+	for _, reg := range registries {
+		index, err := LoadRegistryIndex(ctx, reg)
+		if err != nil {
+			lastErr = fmt.Errorf("registry %s: %w", reg.Name, err)
+			continue
+		}
+		_, version, err := index.Find(name, constraint)
+		if err != nil {
+			lastErr = fmt.Errorf("registry %s: %w", reg.Name, err)
+			continue
+		}
+		path, err := fetchRegistryFile(ctx, reg, name, *version, opts.AllowUnsigned)
+		if err != nil {
+			return "", nil, fmt.Errorf("registry %s: %w", reg.Name, err)
+		}
+		return path, version, nil
+	}
+	return "", nil, lastErr
+}
+
+// fetchRegistryFile returns the local path of a registry pack file after
+// verifying it. Remote files are cached; a cached copy failing verification
+// is downloaded again.
+func fetchRegistryFile(ctx context.Context, reg config.Registry, name string, v RegistryVersion, allowUnsigned bool) (string, error) {
+	if !filepath.IsLocal(v.File) {
+		return "", fmt.Errorf("invalid pack file path %q in registry index", v.File)
+	}
+
+	if !isRemoteRegistry(reg.URL) {
+		path := filepath.Join(reg.URL, v.File)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("reading %s: %w", v.File, err)
+		}
+		if err := VerifyPack(data, name, v, reg.PublicKey, allowUnsigned); err != nil {
+			return "", err
+		}
+		return path, nil
+	}
+
+	fileURL, err := url.JoinPath(reg.URL, filepath.ToSlash(v.File))
+	if err != nil {
+		return "", fmt.Errorf("invalid registry URL %q: %w", reg.URL, err)
+	}
+	cacheDir, err := DefaultCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("getting cache directory: %w", err)
+	}
+	cachePath := registryCachePath(cacheDir, reg.URL, v.File)
+
+	for _, force := range []bool{false, true} {
+		fetched, err := Fetch(ctx, fileURL, cachePath, FetchOptions{Force: force})
+		if err != nil {
+			return "", fmt.Errorf("fetching %s: %w", v.File, err)
+		}
+		data, err := os.ReadFile(fetched.LocalPath)
+		if err != nil {
+			return "", fmt.Errorf("reading %s: %w", v.File, err)
+		}
+		err = VerifyPack(data, name, v, reg.PublicKey, allowUnsigned)
+		if err == nil {
+			return fetched.LocalPath, nil
+		}
+		if !fetched.Cached {
+			os.Remove(fetched.LocalPath)
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("verifying %s failed", v.File)
+}
+
+// ListAvailable lists the packs offered by the selected registries.
+// Registries that cannot be read are reported with a warning on stderr.
+func ListAvailable(ctx context.Context, cfg *config.FloopConfig, opts RegistryOptions) ([]AvailablePack, error) {
+	registries, err := SelectRegistries(cfg, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var available []AvailablePack
+	for _, reg := range registries {
+		index, err := LoadRegistryIndex(ctx, reg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: skipping registry %s: %v\n", reg.Name, err)
+			continue
+		}
+		for _, p := range index.Packs {
+			_, latest, err := index.Find(p.Name, "")
+			if err != nil {
+				continue
+			}
+			versions := make([]string, 0, len(p.Versions))
+			for _, v := range p.Versions {
+				versions = append(versions, v.Version)
+			}
+			available = append(available, AvailablePack{
+				Registry:    reg.Name,
+				Name:        p.Name,
+				ID:          p.ID,
+				Description: p.Description,
+				Tags:        p.Tags,
+				Latest:      latest.Version,
+				Versions:    versions,
+				Signed:      latest.Signature != "",
+			})
+		}
+	}
+	return available, nil
+}
+
+// isRemoteRegistry reports whether a registry location is an HTTP(S) URL.
+func isRemoteRegistry(location string) bool {
+	return strings.HasPrefix(location, "https://") || strings.HasPrefix(location, "http://")
+}
+
+// registryCachePath returns the cache path of a file from a remote registry.
+func registryCachePath(cacheDir, registryURL, file string) string {
+	return filepath.Join(cacheDir, "registry", fmt.Sprintf("%x", fnvHash(registryURL)), filepath.FromSlash(file))
+}
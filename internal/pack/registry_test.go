@@ -0,0 +1,269 @@
+package pack
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// writeTestRegistry publishes versions of a go-standards pack into a
+// registry directory, signing them with priv when it is non-nil.
+func writeTestRegistry(t *testing.T, dir string, priv ed25519.PrivateKey, versions ...string) {
+	t.Helper()
+	entry := RegistryPack{Name: "go-standards", ID: "floop/go-standards", Description: "Go conventions"}
+	for _, v := range versions {
+		packDir := t.TempDir()
+		path := writeTestPack(t, packDir, []store.Node{{
+			ID:       "go-wrap-errors",
+			Kind:     "behavior",
+			Content:  map[string]interface{}{"name": "go-wrap-errors", "kind": "directive", "version": v},
+			Metadata: map[string]interface{}{},
+		}}, nil, PackManifest{ID: "floop/go-standards", Version: v})
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file := "go-standards-" + v + ".fpack"
+		if err := os.WriteFile(filepath.Join(dir, file), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		sum := sha256.Sum256(data)
+		rv := RegistryVersion{Version: v, File: file, SHA256: hex.EncodeToString(sum[:])}
+		if priv != nil {
+			rv.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(priv, SignedPayload(entry.Name, rv)))
+		}
+		entry.Versions = append(entry.Versions, rv)
+	}
+	index, err := json.Marshal(RegistryIndex{Packs: []RegistryPack{entry}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, RegistryIndexFile), index, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMatchVersion(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"1.2.3", "", true},
+		{"1.2.3", "1", true},
+		{"1.2.3", "v1.2", true},
+		{"1.2.3", "1.2.3", true},
+		{"v1.2.3", "1", true},
+		{"1.2.3", "2", false},
+		{"10.0.0", "1", false},
+		{"1.2", "1.2.3", false},
+	}
+	for _, tt := range tests {
+		if got := MatchVersion(tt.version, tt.constraint); got != tt.want {
+			t.Errorf("MatchVersion(%q, %q) = %v, want %v", tt.version, tt.constraint, got, tt.want)
+		}
+	}
+}
+
+func TestRegistryIndex_Find(t *testing.T) {
+	index := &RegistryIndex{Packs: []RegistryPack{{
+		Name: "go-standards",
+		Versions: []RegistryVersion{
+			{Version: "1.2.0"}, {Version: "1.10.0"}, {Version: "2.0.0"}, {Version: "1.9.1"},
+		},
+	}}}
+
+	tests := []struct {
+		constraint string
+		want       string
+		wantErr    bool
+	}{
+		{"", "2.0.0", false},
+		{"1", "1.10.0", false},
+		{"1.9", "1.9.1", false},
+		{"3", "", true},
+	}
+	for _, tt := range tests {
+		_, v, err := index.Find("go-standards", tt.constraint)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Find(%q) error = %v, wantErr %v", tt.constraint, err, tt.wantErr)
+			continue
+		}
+		if err == nil && v.Version != tt.want {
+			t.Errorf("Find(%q) = %s, want %s", tt.constraint, v.Version, tt.want)
+		}
+	}
+	if _, _, err := index.Find("rust-standards", ""); err == nil {
+		t.Error("Find() of an unknown pack should fail")
+	}
+}
+
+func TestVerifyPack(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("pack bytes")
+	sum := sha256.Sum256(data)
+	signed := RegistryVersion{Version: "1.1.0", File: "p.fpack", SHA256: hex.EncodeToString(sum[:])}
+	signed.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(priv, SignedPayload("p", signed)))
+	unsigned := RegistryVersion{Version: "1.1.0", File: "p.fpack", SHA256: signed.SHA256}
+	// The index relabels the signed 1.1.0 file as 2.0.0
+	relabeled := signed
+	relabeled.Version = "2.0.0"
+	key := base64.StdEncoding.EncodeToString(pub)
+
+	tests := []struct {
+		name          string
+		pack          string
+		data          []byte
+		version       RegistryVersion
+		publicKey     string
+		allowUnsigned bool
+		wantErr       string
+	}{
+		{"valid signature", "p", data, signed, key, false, ""},
+		{"tampered data", "p", []byte("other bytes"), signed, key, true, "checksum mismatch"},
+		{"wrong key", "p", data, signed, base64.StdEncoding.EncodeToString(otherPub), false, "signature verification failed"},
+		{"other pack name", "q", data, signed, key, false, "signature verification failed"},
+		{"other version", "p", data, relabeled, key, false, "signature verification failed"},
+		{"unsigned rejected", "p", data, unsigned, key, false, "unsigned"},
+		{"unsigned allowed", "p", data, unsigned, key, true, ""},
+		{"no key rejected", "p", data, signed, "", false, "no public_key"},
+		{"invalid key", "p", data, signed, "not-a-key", false, "invalid registry public key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyPack(tt.data, tt.pack, tt.version, tt.publicKey, tt.allowUnsigned)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("VerifyPack() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("VerifyPack() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInstallFromSource_Registry(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	regDir := t.TempDir()
+	writeTestRegistry(t, regDir, priv, "1.0.0", "1.1.0", "2.0.0")
+
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+	cfg := config.Default()
+	cfg.Packs.Registries = []config.Registry{{
+		Name: "test", URL: regDir, PublicKey: base64.StdEncoding.EncodeToString(pub),
+	}}
+
+	results, err := InstallFromSource(ctx, s, "go-standards@1", cfg, InstallFromSourceOptions{})
+	if err != nil {
+		t.Fatalf("InstallFromSource() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Version != "1.1.0" {
+		t.Fatalf("results = %+v, want go-standards 1.1.0", results)
+	}
+	node, err := s.GetNode(ctx, "go-wrap-errors")
+	if err != nil || node == nil {
+		t.Fatalf("GetNode() = %v, %v", node, err)
+	}
+	prov, _ := node.Metadata["provenance"].(map[string]interface{})
+	if prov["source_type"] != string(models.SourceTypePack) || prov["package"] != "floop/go-standards" {
+		t.Errorf("provenance = %v, want source_type pack from floop/go-standards", prov)
+	}
+	if len(cfg.Packs.Installed) != 1 || cfg.Packs.Installed[0].Source != "go-standards@1" {
+		t.Errorf("installed = %+v, want source go-standards@1", cfg.Packs.Installed)
+	}
+
+	// An untrusted registry location is refused without --allow-unsigned
+	untrusted := InstallFromSourceOptions{Registry: RegistryOptions{Registry: regDir}}
+	if _, err := InstallFromSource(ctx, s, "go-standards", cfg, untrusted); err == nil || !strings.Contains(err.Error(), "public_key") {
+		t.Errorf("install from registry without key error = %v", err)
+	}
+	untrusted.Registry.AllowUnsigned = true
+	if results, err := InstallFromSource(ctx, s, "go-standards", cfg, untrusted); err != nil || results[0].Version != "2.0.0" {
+		t.Errorf("install with --allow-unsigned = %+v, %v", results, err)
+	}
+
+	if _, err := InstallFromSource(ctx, s, "go-standards@3", cfg, InstallFromSourceOptions{}); err == nil {
+		t.Error("install of a missing version should fail")
+	}
+}
+
+func TestFetchRegistryPack_HTTP(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	regDir := t.TempDir()
+	writeTestRegistry(t, regDir, priv, "1.0.0")
+	srv := httptest.NewServer(http.FileServer(http.Dir(regDir)))
+	defer srv.Close()
+
+	cfg := config.Default()
+	cfg.Packs.Registries = []config.Registry{{
+		Name: "remote", URL: srv.URL, PublicKey: base64.StdEncoding.EncodeToString(pub),
+	}}
+
+	path, version, err := FetchRegistryPack(context.Background(), cfg, "go-standards", "", RegistryOptions{})
+	if err != nil {
+		t.Fatalf("FetchRegistryPack() error = %v", err)
+	}
+	if version.Version != "1.0.0" || !IsPackFile(path) {
+		t.Errorf("FetchRegistryPack() = %s, %+v", path, version)
+	}
+
+	available, err := ListAvailable(context.Background(), cfg, RegistryOptions{})
+	if err != nil {
+		t.Fatalf("ListAvailable() error = %v", err)
+	}
+	if len(available) != 1 || available[0].Registry != "remote" || available[0].Latest != "1.0.0" || !available[0].Signed {
+		t.Errorf("ListAvailable() = %+v", available)
+	}
+}
+
+func TestSelectRegistries(t *testing.T) {
+	cfg := config.Default()
+	if _, err := SelectRegistries(cfg, RegistryOptions{}); err == nil {
+		t.Error("SelectRegistries() without registries should fail")
+	}
+
+	cfg.Packs.Registries = []config.Registry{{Name: "team", URL: "https://example.com/packs", PublicKey: "k"}}
+	got, err := SelectRegistries(cfg, RegistryOptions{Registry: "team", PublicKey: "override"})
+	if err != nil || len(got) != 1 || got[0].PublicKey != "override" {
+		t.Errorf("SelectRegistries(team) = %+v, %v", got, err)
+	}
+	if _, err := SelectRegistries(cfg, RegistryOptions{Registry: "nope"}); err == nil {
+		t.Error("SelectRegistries() of an unknown name should fail")
+	}
+	got, err = SelectRegistries(cfg, RegistryOptions{Registry: "./local-registry"})
+	if err != nil || len(got) != 1 || got[0].URL != "./local-registry" || got[0].PublicKey != "" {
+		t.Errorf("SelectRegistries(./local-registry) = %+v, %v", got, err)
+	}
+}
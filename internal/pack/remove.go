@@ -16,7 +16,19 @@ type RemoveResult struct {
 }
 
 // Remove marks pack behaviors as forgotten and removes the pack from config.
+// Forgotten behaviors stay out of the store if the pack is installed again.
 func Remove(ctx context.Context, s store.GraphStore, packID string, cfg *config.FloopConfig) (*RemoveResult, error) {
+	return remove(ctx, s, packID, cfg, false)
+}
+
+// Purge deletes pack behaviors and their edges and removes the pack from
+// config, leaving the store as it was before the pack was installed.
+func Purge(ctx context.Context, s store.GraphStore, packID string, cfg *config.FloopConfig) (*RemoveResult, error) {
+	return remove(ctx, s, packID, cfg, true)
+}
+
+// remove implements Remove and Purge.
+func remove(ctx context.Context, s store.GraphStore, packID string, cfg *config.FloopConfig, purge bool) (*RemoveResult, error) {
 	if err := ValidatePackID(packID); err != nil {
 		return nil, fmt.Errorf("invalid pack ID: %w", err)
 	}
@@ -33,11 +45,21 @@ func Remove(ctx context.Context, s store.GraphStore, packID string, cfg *config.
 
 	for _, node := range nodes {
 		pkgName := models.ExtractPackageName(node.Metadata)
-		if pkgName != packID {
+		if pkgName != packID || node.Origin.ReadOnly() {
 			continue
 		}
 
-		// 2. Mark as forgotten-behavior
+		// 2. Delete, or mark as forgotten-behavior
+		if purge {
+			if err := s.DeleteNode(ctx, node.ID); err != nil {
+				return nil, fmt.Errorf("deleting node %s: %w", node.ID, err)
+			}
+			result.BehaviorsRemoved++
+			continue
+		}
+		if node.Kind == store.NodeKindForgotten {
+			continue
+		}
 		node.Kind = store.NodeKindForgotten
 		if err := s.UpdateNode(ctx, node); err != nil {
 			return nil, fmt.Errorf("marking node %s as forgotten: %w", node.ID, err)
@@ -109,3 +109,43 @@ func TestRemove_UnknownPack(t *testing.T) {
 		t.Errorf("BehaviorsRemoved = %d, want 0", result.BehaviorsRemoved)
 	}
 }
+
+func TestPurge_AllowsCleanReinstall(t *testing.T) {
+	s := store.NewInMemoryGraphStore()
+	ctx := context.Background()
+	cfg := config.Default()
+
+	packPath := writeTestPack(t, t.TempDir(), []store.Node{
+		{ID: "b-purge-1", Kind: "behavior", Content: map[string]interface{}{"name": "purge-1"}, Metadata: map[string]interface{}{}},
+		{ID: "b-purge-2", Kind: "behavior", Content: map[string]interface{}{"name": "purge-2"}, Metadata: map[string]interface{}{}},
+	}, []store.Edge{
+		{Source: "b-purge-1", Target: "b-purge-2", Kind: "similar-to", Weight: 0.8},
+	}, PackManifest{ID: "test-org/purge-pack", Version: "1.0.0"})
+
+	if _, err := Install(ctx, s, packPath, cfg, InstallOptions{}); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	result, err := Purge(ctx, s, "test-org/purge-pack", cfg)
+	if err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if result.BehaviorsRemoved != 2 || len(cfg.Packs.Installed) != 0 {
+		t.Errorf("Purge() = %+v, installed %v", result, cfg.Packs.Installed)
+	}
+	if n, _ := s.GetNode(ctx, "b-purge-1"); n != nil {
+		t.Errorf("b-purge-1 = %+v after purge, want deleted", n)
+	}
+	if edges, _ := s.GetEdges(ctx, "b-purge-1", store.DirectionOutbound, ""); len(edges) != 0 {
+		t.Errorf("edges after purge = %v, want none", edges)
+	}
+
+	// Unlike Remove, purged behaviors come back on reinstall
+	reinstalled, err := Install(ctx, s, packPath, cfg, InstallOptions{})
+	if err != nil {
+		t.Fatalf("reinstall error = %v", err)
+	}
+	if len(reinstalled.Added) != 2 {
+		t.Errorf("reinstall Added = %v, want both behaviors", reinstalled.Added)
+	}
+}
@@ -3,9 +3,13 @@ package pack
 import (
 	"fmt"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
+// registryNamePattern validates registry pack names.
+var registryNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
 // SourceKind classifies the type of pack source.
 type SourceKind int
 
@@ -16,6 +20,8 @@ const (
 	SourceHTTP
 	// SourceGitHub is a GitHub shorthand (gh:owner/repo[@version]).
 	SourceGitHub
+	// SourceRegistry is a pack name looked up in a registry (name[@version]).
+	SourceRegistry
 )
 
 // String returns a human-readable name for the source kind.
@@ -27,6 +33,8 @@ func (k SourceKind) String() string {
 		return "http"
 	case SourceGitHub:
 		return "github"
+	case SourceRegistry:
+		return "registry"
 	default:
 		return "unknown"
 	}
@@ -41,7 +49,8 @@ type ResolvedSource struct {
 	URL       string // for SourceHTTP: full URL
 	Owner     string // for SourceGitHub
 	Repo      string // for SourceGitHub
-	Version   string // for SourceGitHub ("" = latest)
+	Name      string // for SourceRegistry
+	Version   string // for SourceGitHub and SourceRegistry ("" = latest)
 }
 
 // ResolveSource parses a source string into its components.
@@ -51,7 +60,11 @@ type ResolvedSource struct {
 //   - gh:owner/repo@v1.2.3   → SourceGitHub (specific version)
 //   - https://example.com/x  → SourceHTTP
 //   - http://example.com/x   → SourceHTTP
+//   - go-standards@1         → SourceRegistry (highest 1.x in a registry)
 //   - ./path or /abs/path    → SourceLocal
+//
+// A bare name without a path separator or .fpack extension is a registry
+// pack; prefix it with ./ to install a local file of that name.
 func ResolveSource(source string) (*ResolvedSource, error) {
 	if source == "" {
 		return nil, fmt.Errorf("source is required")
@@ -72,10 +85,33 @@ func ResolveSource(source string) (*ResolvedSource, error) {
 		}, nil
 	}
 
+	// Registry pack: name[@version]
+	if !strings.ContainsAny(source, `/\`) && !strings.HasSuffix(source, ".fpack") {
+		return resolveRegistry(source)
+	}
+
 	// Everything else is a local path
 	return resolveLocal(source)
 }
 
+// resolveRegistry parses name[@version].
+func resolveRegistry(source string) (*ResolvedSource, error) {
+	name, version, hasVersion := strings.Cut(source, "@")
+	if !registryNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid registry pack %q: expected name[@version]", source)
+	}
+	if hasVersion && version == "" {
+		return nil, fmt.Errorf("invalid registry pack %q: version after @ is empty", source)
+	}
+	return &ResolvedSource{
+		Kind:      SourceRegistry,
+		Raw:       source,
+		Canonical: source,
+		Name:      name,
+		Version:   version,
+	}, nil
+}
+
 // resolveGitHub parses gh:owner/repo[@version].
 func resolveGitHub(source string) (*ResolvedSource, error) {
 	rest := strings.TrimPrefix(source, "gh:")
@@ -88,6 +88,29 @@ func TestResolveSource(t *testing.T) {
 			source:   "my-pack.fpack",
 			wantKind: SourceLocal,
 		},
+		{
+			name:     "registry name",
+			source:   "go-standards",
+			wantKind: SourceRegistry,
+			wantRepo: "go-standards",
+		},
+		{
+			name:     "registry name with version",
+			source:   "go-standards@1",
+			wantKind: SourceRegistry,
+			wantRepo: "go-standards",
+			wantVer:  "1",
+		},
+		{
+			name:    "registry empty version after @",
+			source:  "go-standards@",
+			wantErr: true,
+		},
+		{
+			name:    "registry invalid name",
+			source:  "Go Standards",
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -118,6 +141,10 @@ func TestResolveSource(t *testing.T) {
 				if got.Version != tt.wantVer {
 					t.Errorf("Version = %q, want %q", got.Version, tt.wantVer)
 				}
+			case SourceRegistry:
+				if got.Name != tt.wantRepo || got.Version != tt.wantVer {
+					t.Errorf("Name, Version = %q, %q; want %q, %q", got.Name, got.Version, tt.wantRepo, tt.wantVer)
+				}
 			case SourceHTTP:
 				if got.URL != tt.wantURL {
 					t.Errorf("URL = %q, want %q", got.URL, tt.wantURL)
@@ -0,0 +1,135 @@
+package pack
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"time"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// starterFS holds the starter packs, one Markdown pack per directory.
+//
+//go:embed starter
+var starterFS embed.FS
+
+// starterDir is the directory of starterFS holding the packs.
+const starterDir = "starter"
+
+// StarterPack describes a curated pack of conventions for a common stack
+// that ships inside the floop binary. Starter packs install by name, like
+// registry packs, without network access.
+type StarterPack struct {
+	Name        string   `json:"name"`
+	ID          string   `json:"id"`
+	Version     string   `json:"version"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Behaviors   int      `json:"behaviors"`
+}
+
+// StarterPacks lists the starter packs, sorted by name.
+func StarterPacks() ([]StarterPack, error) {
+	entries, err := fs.ReadDir(starterFS, starterDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading starter packs: %w", err)
+	}
+	var packs []StarterPack
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		p, err := readStarter(e.Name())
+		if err != nil {
+			return nil, err
+		}
+		packs = append(packs, StarterPack{
+			Name:        e.Name(),
+			ID:          p.Manifest.ID,
+			Version:     p.Manifest.Version,
+			Description: p.Manifest.Description,
+			Tags:        p.Manifest.Tags,
+			Behaviors:   len(p.Behaviors),
+		})
+	}
+	sort.Slice(packs, func(i, j int) bool { return packs[i].Name < packs[j].Name })
+	return packs, nil
+}
+
+// IsStarterPack reports whether name is a starter pack.
+func IsStarterPack(name string) bool {
+	if !registryNamePattern.MatchString(name) {
+		return false
+	}
+	info, err := fs.Stat(starterFS, starterDir+"/"+name)
+	return err == nil && info.IsDir()
+}
+
+// InstallStarter installs the named starter pack like Install installs a
+// pack file. A non-empty constraint must match the pack's version, as in
+// "go-backend@1".
+func InstallStarter(ctx context.Context, s store.GraphStore, name, constraint string, cfg *config.FloopConfig, opts InstallOptions) (*InstallResult, error) {
+	if !IsStarterPack(name) {
+		return nil, fmt.Errorf("no starter pack named %q", name)
+	}
+	p, err := readStarter(name)
+	if err != nil {
+		return nil, err
+	}
+	if constraint != "" && !MatchVersion(p.Manifest.Version, constraint) {
+		return nil, fmt.Errorf("starter pack %s is version %s, which does not match %q", name, p.Manifest.Version, constraint)
+	}
+	data, manifest := starterData(p)
+	if opts.Source == "" {
+		opts.Source = name
+	}
+	return installPack(ctx, s, data, manifest, cfg, opts)
+}
+
+// readStarter reads the named starter pack, which must carry an ID and a
+// version.
+func readStarter(name string) (*MarkdownPack, error) {
+	p, err := ReadMarkdownPackFS(starterFS, starterDir+"/"+name)
+	if err != nil {
+		return nil, fmt.Errorf("reading starter pack %s: %w", name, err)
+	}
+	if err := ValidatePackID(p.Manifest.ID); err != nil {
+		return nil, fmt.Errorf("starter pack %s: %w", name, err)
+	}
+	if p.Manifest.Version == "" {
+		return nil, fmt.Errorf("starter pack %s has no version", name)
+	}
+	return p, nil
+}
+
+// starterData converts a starter pack to the nodes and edges of a pack
+// file. Edges without a weight get full weight.
+func starterData(p *MarkdownPack) (*backup.BackupFormat, *PackManifest) {
+	now := time.Now()
+	data := &backup.BackupFormat{Version: backup.FormatV2, CreatedAt: now}
+	for _, mb := range p.Behaviors {
+		b := mb.Behavior()
+		data.Nodes = append(data.Nodes, backup.BackupNode{Node: models.BehaviorToNode(&b)})
+		for _, e := range mb.Edges {
+			weight := e.Weight
+			if weight == 0 {
+				weight = 1
+			}
+			data.Edges = append(data.Edges, store.Edge{Source: mb.ID, Target: e.Target, Kind: e.Kind, Weight: weight, CreatedAt: now})
+		}
+	}
+	manifest := &PackManifest{
+		ID:          PackID(p.Manifest.ID),
+		Version:     p.Manifest.Version,
+		Description: p.Manifest.Description,
+		Author:      p.Manifest.Author,
+		Tags:        p.Manifest.Tags,
+	}
+	return data, manifest
+}
@@ -0,0 +1,144 @@
+package pack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestStarterPacks(t *testing.T) {
+	packs, err := StarterPacks()
+	if err != nil {
+		t.Fatalf("StarterPacks() error = %v", err)
+	}
+	names := make([]string, len(packs))
+	for i, p := range packs {
+		names[i] = p.Name
+		if p.Behaviors == 0 || p.Description == "" {
+			t.Errorf("starter pack %s = %+v, want behaviors and a description", p.Name, p)
+		}
+		if !IsStarterPack(p.Name) {
+			t.Errorf("IsStarterPack(%q) = false", p.Name)
+		}
+	}
+	want := []string{"go-backend", "python", "react"}
+	if len(names) != len(want) {
+		t.Fatalf("starter packs = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("starter packs = %v, want %v", names, want)
+		}
+	}
+
+	for _, name := range []string{"", "rust", "../starter", "go-backend/pack.md"} {
+		if IsStarterPack(name) {
+			t.Errorf("IsStarterPack(%q) = true, want false", name)
+		}
+	}
+}
+
+func TestStarterPacks_EdgesResolve(t *testing.T) {
+	packs, err := StarterPacks()
+	if err != nil {
+		t.Fatalf("StarterPacks() error = %v", err)
+	}
+	for _, sp := range packs {
+		p, err := readStarter(sp.Name)
+		if err != nil {
+			t.Fatalf("readStarter(%s) error = %v", sp.Name, err)
+		}
+		ids := make(map[string]bool, len(p.Behaviors))
+		for _, b := range p.Behaviors {
+			ids[b.ID] = true
+		}
+		for _, b := range p.Behaviors {
+			if len(b.When) == 0 {
+				t.Errorf("%s/%s has no when-condition", sp.Name, b.ID)
+			}
+			for _, e := range b.Edges {
+				if !ids[e.Target] {
+					t.Errorf("%s/%s has an edge to %s, which is not in the pack", sp.Name, b.ID, e.Target)
+				}
+			}
+		}
+	}
+}
+
+func TestInstallStarter(t *testing.T) {
+	s := store.NewInMemoryGraphStore()
+	ctx := context.Background()
+	cfg := config.Default()
+
+	result, err := InstallStarter(ctx, s, "go-backend", "", cfg, InstallOptions{})
+	if err != nil {
+		t.Fatalf("InstallStarter() error = %v", err)
+	}
+	if result.PackID != "floop/go-backend" || len(result.Added) == 0 || result.EdgesAdded == 0 {
+		t.Fatalf("result = %+v, want floop/go-backend behaviors and edges", result)
+	}
+
+	node, err := s.GetNode(ctx, result.Added[0])
+	if err != nil || node == nil {
+		t.Fatalf("GetNode(%s) = %v, %v", result.Added[0], node, err)
+	}
+	b := models.NodeToBehavior(*node)
+	if b.Provenance.SourceType != models.SourceTypePack || b.Provenance.Package != "floop/go-backend" {
+		t.Errorf("provenance = %+v, want source_type pack from floop/go-backend", b.Provenance)
+	}
+	if len(cfg.Packs.Installed) != 1 || cfg.Packs.Installed[0].Source != "go-backend" {
+		t.Errorf("installed = %+v, want go-backend recorded by name", cfg.Packs.Installed)
+	}
+
+	// Installing again skips everything
+	again, err := InstallStarter(ctx, s, "go-backend", "1", cfg, InstallOptions{})
+	if err != nil {
+		t.Fatalf("InstallStarter() again error = %v", err)
+	}
+	if len(again.Added) != 0 || len(again.Skipped) != len(result.Added) {
+		t.Errorf("second install = %+v, want everything skipped", again)
+	}
+
+	if _, err := InstallStarter(ctx, s, "go-backend", "2", cfg, InstallOptions{}); err == nil {
+		t.Error("InstallStarter() with a non-matching version should fail")
+	}
+	if _, err := InstallStarter(ctx, s, "cobol", "", cfg, InstallOptions{}); err == nil {
+		t.Error("InstallStarter() of an unknown pack should fail")
+	}
+
+	// Removing the pack forgets every behavior it installed
+	removed, err := Remove(ctx, s, "floop/go-backend", cfg)
+	if err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if removed.BehaviorsRemoved != len(result.Added) {
+		t.Errorf("removed %d behaviors, want %d", removed.BehaviorsRemoved, len(result.Added))
+	}
+}
+
+func TestInstallFromSource_Starter(t *testing.T) {
+	s := store.NewInMemoryGraphStore()
+	ctx := context.Background()
+	cfg := config.Default()
+
+	results, err := InstallFromSource(ctx, s, "python@1", cfg, InstallFromSourceOptions{})
+	if err != nil {
+		t.Fatalf("InstallFromSource() error = %v", err)
+	}
+	if len(results) != 1 || results[0].PackID != "floop/python" {
+		t.Fatalf("results = %+v, want floop/python", results)
+	}
+	if got := cfg.Packs.Installed[0].Source; got != "python@1" {
+		t.Errorf("recorded source = %q, want python@1", got)
+	}
+
+	// An explicit registry is searched instead of the starter packs
+	if _, err := InstallFromSource(ctx, s, "python", cfg, InstallFromSourceOptions{
+		Registry: RegistryOptions{Registry: t.TempDir()},
+	}); err == nil {
+		t.Error("InstallFromSource() with --registry should not fall back to the starter pack")
+	}
+}
@@ -0,0 +1,396 @@
+package portability
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/corrections"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// MaxEntrySize bounds each decompressed archive entry.
+const MaxEntrySize = backup.MaxDecompressedSize
+
+// maxLineSize bounds a single JSONL line when merging logs.
+const maxLineSize = 16 * 1024 * 1024
+
+// Config outcomes reported in ImportResult.Config.
+const (
+	ConfigWritten      = "written"
+	ConfigKeptExisting = "kept-existing"
+	ConfigNotIncluded  = "not-included"
+)
+
+// ImportOptions controls where Import restores an archive.
+type ImportOptions struct {
+	// HomeDir receives the global scope.
+	HomeDir string
+
+	// ProjectRoot receives the local scope. The local scope is skipped when
+	// empty.
+	ProjectRoot string
+
+	// OverwriteConfig replaces an existing config.yaml. By default an
+	// existing config is kept.
+	OverwriteConfig bool
+
+	// DryRun verifies the archive and reports its contents without
+	// changing anything.
+	DryRun bool
+}
+
+// ImportResult reports what Import restored.
+type ImportResult struct {
+	Manifest *Manifest `json:"manifest"`
+
+	// Stores maps scope to the merge result for its store.
+	Stores map[string]*backup.RestoreResult `json:"stores,omitempty"`
+
+	// LinesAppended maps archive paths of JSONL logs to the number of new
+	// lines appended at the destination.
+	LinesAppended map[string]int `json:"lines_appended,omitempty"`
+
+	// Config is ConfigWritten, ConfigKeptExisting, or ConfigNotIncluded.
+	Config string `json:"config"`
+
+	// SkippedScopes lists scopes present in the archive but not restored.
+	SkippedScopes []string `json:"skipped_scopes,omitempty"`
+
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// Import restores an export-all archive. Stores are merged (existing nodes
+// and edges are kept), JSONL logs gain only lines they don't already have,
+// and config.yaml is written only when absent unless OverwriteConfig is set.
+func Import(ctx context.Context, archivePath string, opts ImportOptions) (*ImportResult, error) {
+	if opts.HomeDir == "" {
+		return nil, fmt.Errorf("home directory is required")
+	}
+
+	staging, err := os.MkdirTemp("", "floop-import-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	manifest, err := extractArchive(archivePath, staging)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{
+		Manifest: manifest,
+		Config:   ConfigNotIncluded,
+		DryRun:   opts.DryRun,
+	}
+
+	roots := map[string]string{ScopeGlobal: opts.HomeDir}
+	if opts.ProjectRoot != "" {
+		roots[ScopeLocal] = opts.ProjectRoot
+	}
+	for _, scope := range []string{ScopeGlobal, ScopeLocal} {
+		if _, ok := roots[scope]; !ok && manifest.HasScope(scope) {
+			result.SkippedScopes = append(result.SkippedScopes, scope)
+		}
+	}
+
+	if opts.DryRun {
+		if hasEntry(manifest, ScopeGlobal+"/"+configEntry) {
+			result.Config = ConfigWritten
+			if _, err := os.Stat(filepath.Join(opts.HomeDir, ".floop", configEntry)); err == nil && !opts.OverwriteConfig {
+				result.Config = ConfigKeptExisting
+			}
+		}
+		return result, nil
+	}
+
+	result.Stores = make(map[string]*backup.RestoreResult)
+	result.LinesAppended = make(map[string]int)
+	for _, scope := range []string{ScopeGlobal, ScopeLocal} {
+		root, ok := roots[scope]
+		if !ok || !manifest.HasScope(scope) {
+			continue
+		}
+		restored, err := restoreStore(ctx, root, filepath.Join(staging, scope, storeEntry))
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore %s store: %w", scope, err)
+		}
+		result.Stores[scope] = restored
+
+		for _, name := range jsonlFiles {
+			entry := scope + "/" + name
+			if !hasEntry(manifest, entry) {
+				continue
+			}
+			var n int
+			if name == corrections.File {
+				n, err = importCorrections(ctx, filepath.Join(staging, scope, name), filepath.Join(root, ".floop"))
+			} else {
+				n, err = appendNewLines(filepath.Join(staging, scope, name), filepath.Join(root, ".floop", name))
+			}
+			if err != nil {
+				return nil, err
+			}
+			result.LinesAppended[entry] = n
+		}
+	}
+
+	if hasEntry(manifest, ScopeGlobal+"/"+configEntry) {
+		dst := filepath.Join(opts.HomeDir, ".floop", configEntry)
+		if _, err := os.Stat(dst); err == nil && !opts.OverwriteConfig {
+			result.Config = ConfigKeptExisting
+		} else {
+			if err := copyIfExists(filepath.Join(staging, ScopeGlobal, configEntry), dst); err != nil {
+				return nil, err
+			}
+			result.Config = ConfigWritten
+		}
+	}
+
+	return result, nil
+}
+
+// restoreStore merges a staged store backup into the store at root.
+func restoreStore(ctx context.Context, root, backupPath string) (*backup.RestoreResult, error) {
+	graphStore, err := store.NewSQLiteGraphStore(root)
+	if err != nil {
+		return nil, err
+	}
+	result, err := backup.Restore(ctx, graphStore, backupPath, backup.RestoreMerge)
+	closeErr := graphStore.Close()
+	if err != nil {
+		return nil, err
+	}
+	if closeErr != nil {
+		return nil, closeErr
+	}
+	return result, nil
+}
+
+// extractArchive unpacks archivePath into staging, accepting only the
+// entries an export-all archive can contain, and verifies each against the
+// manifest.
+func extractArchive(archivePath, staging string) (*Manifest, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("not an export-all archive: %w", err)
+	}
+	defer gz.Close()
+
+	var manifest *Manifest
+	sums := make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("unexpected archive entry %q", hdr.Name)
+		}
+
+		data, err := io.ReadAll(io.LimitReader(tr, MaxEntrySize+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", hdr.Name, err)
+		}
+		if int64(len(data)) > MaxEntrySize {
+			return nil, fmt.Errorf("archive entry %s exceeds maximum size (%dMB)", hdr.Name, MaxEntrySize>>20)
+		}
+
+		if hdr.Name == ManifestFile {
+			manifest = &Manifest{}
+			if err := json.Unmarshal(data, manifest); err != nil {
+				return nil, fmt.Errorf("invalid manifest: %w", err)
+			}
+			continue
+		}
+		if !allowedEntry(hdr.Name) {
+			return nil, fmt.Errorf("unexpected archive entry %q", hdr.Name)
+		}
+
+		dst := filepath.Join(staging, filepath.FromSlash(hdr.Name))
+		if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+			return nil, fmt.Errorf("failed to create staging directory: %w", err)
+		}
+		if err := os.WriteFile(dst, data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to stage %s: %w", hdr.Name, err)
+		}
+		sum := sha256.Sum256(data)
+		sums[hdr.Name] = hex.EncodeToString(sum[:])
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("not an export-all archive: %s missing", ManifestFile)
+	}
+	if manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("archive format version %d is newer than supported (%d); upgrade floop", manifest.FormatVersion, FormatVersion)
+	}
+	for _, fe := range manifest.Files {
+		got, ok := sums[fe.Path]
+		if !ok {
+			return nil, fmt.Errorf("archive is missing %s listed in the manifest", fe.Path)
+		}
+		if got != fe.SHA256 {
+			return nil, fmt.Errorf("checksum mismatch for %s", fe.Path)
+		}
+		delete(sums, fe.Path)
+	}
+	if len(sums) > 0 {
+		extra := make([]string, 0, len(sums))
+		for name := range sums {
+			extra = append(extra, name)
+		}
+		sort.Strings(extra)
+		return nil, fmt.Errorf("archive entries not listed in the manifest: %s", strings.Join(extra, ", "))
+	}
+	return manifest, nil
+}
+
+// allowedEntry reports whether name is a file an export-all archive holds.
+func allowedEntry(name string) bool {
+	scope, file := path.Split(name)
+	switch scope {
+	case ScopeGlobal + "/":
+		if file == configEntry {
+			return true
+		}
+	case ScopeLocal + "/":
+	default:
+		return false
+	}
+	if file == storeEntry {
+		return true
+	}
+	for _, n := range jsonlFiles {
+		if file == n {
+			return true
+		}
+	}
+	return false
+}
+
+func hasEntry(m *Manifest, p string) bool {
+	for _, f := range m.Files {
+		if f.Path == p {
+			return true
+		}
+	}
+	return false
+}
+
+// importCorrections adds the staged corrections at src missing from the
+// corrections of floopDir, and returns how many were added. Corrections
+// already present keep their processed state.
+func importCorrections(ctx context.Context, src, floopDir string) (int, error) {
+	staged, err := corrections.ReadJSONL(src)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read staged %s: %w", corrections.File, err)
+	}
+	correctionLog, err := corrections.Open(floopDir)
+	if err != nil {
+		return 0, err
+	}
+	defer correctionLog.Close()
+
+	var added []models.Correction
+	for _, c := range staged {
+		existing, err := correctionLog.GetCorrection(ctx, c.ID)
+		if err != nil {
+			return 0, err
+		}
+		if existing == nil {
+			added = append(added, c)
+		}
+	}
+	if err := correctionLog.AddCorrections(ctx, added); err != nil {
+		return 0, err
+	}
+	return len(added), nil
+}
+
+// appendNewLines appends the lines of src missing from dst, creating dst if
+// needed, and returns how many were appended.
+func appendNewLines(src, dst string) (int, error) {
+	existing := make(map[string]bool)
+	if data, err := os.ReadFile(dst); err == nil {
+		if err := eachLine(data, func(line string) { existing[line] = true }); err != nil {
+			return 0, fmt.Errorf("failed to read %s: %w", dst, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return 0, fmt.Errorf("failed to read %s: %w", dst, err)
+	}
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read staged %s: %w", filepath.Base(src), err)
+	}
+	var added []string
+	if err := eachLine(data, func(line string) {
+		if !existing[line] {
+			existing[line] = true
+			added = append(added, line)
+		}
+	}); err != nil {
+		return 0, fmt.Errorf("failed to read staged %s: %w", filepath.Base(src), err)
+	}
+	if len(added) == 0 {
+		return 0, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return 0, fmt.Errorf("failed to create %s: %w", filepath.Dir(dst), err)
+	}
+	out, err := os.OpenFile(dst, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %s: %w", dst, err)
+	}
+	w := bufio.NewWriter(out)
+	for _, line := range added {
+		w.WriteString(line)
+		w.WriteByte('\n')
+	}
+	if err := w.Flush(); err != nil {
+		out.Close()
+		return 0, fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	if err := out.Close(); err != nil {
+		return 0, fmt.Errorf("failed to close %s: %w", dst, err)
+	}
+	return len(added), nil
+}
+
+// eachLine calls fn for every non-empty line in data.
+func eachLine(data []byte, fn func(string)) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			fn(line)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,479 @@
+// Package portability exports and imports a whole floop installation — the
+// global and project stores, config, corrections, audit logs, and the backup
+// index — as a single archive for moving to another machine.
+//
+// Unlike backups (one graph) and pack bundles (shareable behaviors), an
+// export-all archive captures everything floop keeps on disk except secrets
+// and the backup files themselves.
+package portability
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/corrections"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/store"
+	"gopkg.in/yaml.v3"
+)
+
+// FormatVersion is the export-all archive format written by Export.
+const FormatVersion = 1
+
+// ManifestFile is the archive entry describing its contents.
+const ManifestFile = "manifest.json"
+
+// Archive scopes: entries live under global/ or local/.
+const (
+	ScopeGlobal = "global"
+	ScopeLocal  = "local"
+)
+
+// storeEntry is the per-scope graph, written as a V2 backup.
+const storeEntry = "store.json.gz"
+
+// configEntry is the global config.yaml with secrets removed.
+const configEntry = "config.yaml"
+
+// jsonlFiles are the per-scope JSONL entries. The corrections are exported
+// from the corrections table; the other logs are carried verbatim.
+var jsonlFiles = []string{
+	corrections.File,
+	learning.HeldCorrectionsFile,
+	"audit.jsonl",
+}
+
+// Manifest describes an export-all archive.
+type Manifest struct {
+	FormatVersion int       `json:"format_version"`
+	FloopVersion  string    `json:"floop_version,omitempty"`
+	SchemaVersion int       `json:"schema_version"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// ProjectRoot is the project whose local store was exported, as a path
+	// on the source machine. Empty when only the global scope was exported.
+	ProjectRoot string `json:"project_root,omitempty"`
+
+	// Files lists every other archive entry with its size and SHA-256.
+	Files []FileEntry `json:"files"`
+
+	// ExcludedSecrets names config keys removed from config.yaml.
+	ExcludedSecrets []string `json:"excluded_secrets,omitempty"`
+
+	// Backups indexes ~/.floop/backups on the source machine. The backup
+	// files themselves are not included.
+	Backups []BackupIndexEntry `json:"backups,omitempty"`
+}
+
+// FileEntry is one archive entry listed in the manifest.
+type FileEntry struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// BackupIndexEntry records one backup present on the source machine.
+type BackupIndexEntry struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size_bytes"`
+	CreatedAt time.Time `json:"created_at"`
+	NodeCount int       `json:"node_count,omitempty"`
+	EdgeCount int       `json:"edge_count,omitempty"`
+	Checksum  string    `json:"checksum,omitempty"`
+}
+
+// HasScope reports whether the archive contains a store for scope.
+func (m *Manifest) HasScope(scope string) bool {
+	for _, f := range m.Files {
+		if f.Path == scope+"/"+storeEntry {
+			return true
+		}
+	}
+	return false
+}
+
+// ExportOptions selects what Export includes.
+type ExportOptions struct {
+	// HomeDir holds the global .floop directory.
+	HomeDir string
+
+	// ProjectRoot holds the local .floop directory. The local scope is
+	// skipped when empty, equal to HomeDir, or without a .floop directory.
+	ProjectRoot string
+
+	// FloopVersion is recorded in the manifest and store backups.
+	FloopVersion string
+}
+
+// Export writes an export-all archive of the installation to outputPath.
+func Export(ctx context.Context, outputPath string, opts ExportOptions) (*Manifest, error) {
+	if opts.HomeDir == "" {
+		return nil, fmt.Errorf("home directory is required")
+	}
+
+	staging, err := os.MkdirTemp("", "floop-export-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	manifest := &Manifest{
+		FormatVersion: FormatVersion,
+		FloopVersion:  opts.FloopVersion,
+		SchemaVersion: store.SchemaVersion,
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	scopes := map[string]string{ScopeGlobal: opts.HomeDir}
+	if includeLocal(opts) {
+		scopes[ScopeLocal] = opts.ProjectRoot
+		manifest.ProjectRoot = opts.ProjectRoot
+	}
+
+	for _, scope := range []string{ScopeGlobal, ScopeLocal} {
+		root, ok := scopes[scope]
+		if !ok {
+			continue
+		}
+		if err := stageScope(ctx, staging, scope, root, opts.FloopVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	manifest.ExcludedSecrets, err = stageConfig(staging, opts.HomeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest.Backups, err = indexBackups(filepath.Join(opts.HomeDir, ".floop", "backups"))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeArchive(outputPath, staging, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// GenerateExportPath creates a timestamped export-all filename in dir.
+func GenerateExportPath(dir string) string {
+	ts := time.Now().Format("20060102-150405")
+	return filepath.Join(dir, fmt.Sprintf("floop-export-%s.tar.gz", ts))
+}
+
+// includeLocal reports whether opts names a project store distinct from the
+// global one.
+func includeLocal(opts ExportOptions) bool {
+	if opts.ProjectRoot == "" {
+		return false
+	}
+	root, err := filepath.Abs(opts.ProjectRoot)
+	if err != nil {
+		return false
+	}
+	home, err := filepath.Abs(opts.HomeDir)
+	if err == nil && root == home {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(root, ".floop"))
+	return err == nil && info.IsDir()
+}
+
+// stageScope writes scope's store backup and JSONL logs under staging/scope.
+func stageScope(ctx context.Context, staging, scope, root, floopVersion string) error {
+	dir := filepath.Join(staging, scope)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+
+	graphStore, err := store.NewSQLiteGraphStore(root)
+	if err != nil {
+		return fmt.Errorf("failed to open %s store: %w", scope, err)
+	}
+	_, err = backup.BackupWithOptions(ctx, graphStore, filepath.Join(dir, storeEntry), backup.BackupOptions{
+		Compress:     true,
+		FloopVersion: floopVersion,
+		Metadata:     map[string]string{"scope": scope, "source": "export-all"},
+	})
+	closeErr := graphStore.Close()
+	if err != nil {
+		return fmt.Errorf("failed to export %s store: %w", scope, err)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("failed to close %s store: %w", scope, closeErr)
+	}
+
+	floopDir := filepath.Join(root, ".floop")
+	for _, name := range jsonlFiles {
+		if name == corrections.File {
+			if err := stageCorrections(floopDir, filepath.Join(dir, name)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := copyIfExists(filepath.Join(floopDir, name), filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stageCorrections writes the corrections of floopDir to dst as JSONL, or
+// nothing if it has none.
+func stageCorrections(floopDir, dst string) error {
+	cs, err := corrections.Load(floopDir)
+	if err != nil {
+		return fmt.Errorf("failed to read corrections: %w", err)
+	}
+	if len(cs) == 0 {
+		return nil
+	}
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to stage %s: %w", corrections.File, err)
+	}
+	if err := corrections.WriteJSONL(f, cs); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to stage %s: %w", corrections.File, err)
+	}
+	return nil
+}
+
+// stageConfig copies the global config.yaml with secrets removed, returning
+// the keys that were dropped.
+func stageConfig(staging, homeDir string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(homeDir, ".floop", "config.yaml"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	cleaned, excluded, err := stripSecrets(data)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(staging, ScopeGlobal)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, configEntry), cleaned, 0600); err != nil {
+		return nil, fmt.Errorf("failed to stage config: %w", err)
+	}
+	return excluded, nil
+}
+
+// secretKeys are config paths holding credentials. A value that is purely a
+// ${VAR} reference is kept, since it names a variable rather than a secret.
+var secretKeys = [][]string{
+	{"llm", "api_key"},
+}
+
+// stripSecrets removes secretKeys from a config.yaml document.
+func stripSecrets(data []byte) ([]byte, []string, error) {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("parsing config file: %w", err)
+	}
+	if doc == nil {
+		return data, nil, nil
+	}
+
+	var excluded []string
+	for _, path := range secretKeys {
+		parent := doc
+		for _, key := range path[:len(path)-1] {
+			next, ok := parent[key].(map[string]interface{})
+			if !ok {
+				parent = nil
+				break
+			}
+			parent = next
+		}
+		if parent == nil {
+			continue
+		}
+		leaf := path[len(path)-1]
+		value, ok := parent[leaf]
+		if !ok {
+			continue
+		}
+		if s, isString := value.(string); isString && isEnvReference(s) {
+			continue
+		}
+		delete(parent, leaf)
+		excluded = append(excluded, strings.Join(path, "."))
+	}
+
+	if len(excluded) == 0 {
+		return data, nil, nil
+	}
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding config: %w", err)
+	}
+	return out, excluded, nil
+}
+
+// isEnvReference reports whether s is exactly one ${VAR} reference.
+func isEnvReference(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "${") && strings.HasSuffix(s, "}") && strings.Count(s, "${") == 1
+}
+
+// indexBackups lists the backups in dir, with header details for V2 files.
+func indexBackups(dir string) ([]BackupIndexEntry, error) {
+	infos, err := backup.ListBackups(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+	entries := make([]BackupIndexEntry, 0, len(infos))
+	for _, b := range infos {
+		entry := BackupIndexEntry{
+			Name:      filepath.Base(b.Path),
+			Size:      b.Size,
+			CreatedAt: b.CreatedAt,
+		}
+		if b.Version == backup.FormatV2 {
+			if header, err := backup.ReadV2Header(b.Path); err == nil {
+				entry.NodeCount = header.NodeCount
+				entry.EdgeCount = header.EdgeCount
+				entry.Checksum = header.Checksum
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// copyIfExists copies src to dst, doing nothing if src does not exist.
+func copyIfExists(src, dst string) error {
+	in, err := os.Open(src)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	return out.Close()
+}
+
+// writeArchive packs the staged files and manifest into a .tar.gz at
+// outputPath, writing to a temp file first so a failure leaves no partial
+// archive behind.
+func writeArchive(outputPath, staging string, manifest *Manifest) error {
+	var paths []string
+	for _, scope := range []string{ScopeGlobal, ScopeLocal} {
+		entries, err := os.ReadDir(filepath.Join(staging, scope))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read staging directory: %w", err)
+		}
+		for _, e := range entries {
+			paths = append(paths, scope+"/"+e.Name())
+		}
+	}
+
+	for _, p := range paths {
+		data, err := os.ReadFile(filepath.Join(staging, filepath.FromSlash(p)))
+		if err != nil {
+			return fmt.Errorf("failed to read staged %s: %w", p, err)
+		}
+		sum := sha256.Sum256(data)
+		manifest.Files = append(manifest.Files, FileEntry{Path: p, Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:])})
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0700); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(outputPath), ".floop-export-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op after a successful rename
+
+	gz := gzip.NewWriter(tmp)
+	tw := tar.NewWriter(gz)
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := writeTarEntry(tw, ManifestFile, manifestData); err != nil {
+		tmp.Close()
+		return err
+	}
+	for _, p := range paths {
+		data, err := os.ReadFile(filepath.Join(staging, filepath.FromSlash(p)))
+		if err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to read staged %s: %w", p, err)
+		}
+		if err := writeTarEntry(tw, p, data); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close archive: %w", err)
+	}
+	if err := os.Rename(tmpPath, outputPath); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+func writeTarEntry(tw *tar.Writer, name string, data []byte) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to write %s header: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
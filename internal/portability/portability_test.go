@@ -0,0 +1,254 @@
+package portability
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// seedInstallation creates a store with one behavior under root/.floop and
+// writes the given files next to it.
+func seedInstallation(t *testing.T, root, behaviorID string, files map[string]string) {
+	t.Helper()
+	s, err := store.NewSQLiteGraphStore(root)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	ctx := context.Background()
+	if _, err := s.AddNode(ctx, store.Node{
+		ID:   behaviorID,
+		Kind: store.NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    behaviorID,
+			"kind":    "directive",
+			"content": map[string]interface{}{"canonical": "Use " + behaviorID},
+		},
+	}); err != nil {
+		t.Fatalf("AddNode() error = %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, ".floop", name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func hasNode(t *testing.T, root, id string) bool {
+	t.Helper()
+	s, err := store.NewSQLiteGraphStore(root)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	defer s.Close()
+	node, err := s.GetNode(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetNode() error = %v", err)
+	}
+	return node != nil
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	srcHome, srcProject := t.TempDir(), t.TempDir()
+
+	seedInstallation(t, srcHome, "global-behavior", map[string]string{
+		"config.yaml": "llm:\n  provider: anthropic\n  api_key: sk-secret-value\n",
+		"audit.jsonl": `{"tool":"floop_active"}` + "\n",
+	})
+	seedInstallation(t, srcProject, "local-behavior", map[string]string{
+		"corrections.jsonl": `{"id":"c1"}` + "\n" + `{"id":"c2"}` + "\n",
+	})
+
+	archive := filepath.Join(t.TempDir(), "export.tar.gz")
+	manifest, err := Export(ctx, archive, ExportOptions{HomeDir: srcHome, ProjectRoot: srcProject, FloopVersion: "1.0.0"})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if !manifest.HasScope(ScopeGlobal) || !manifest.HasScope(ScopeLocal) {
+		t.Errorf("manifest files = %+v, want both scopes", manifest.Files)
+	}
+	if len(manifest.ExcludedSecrets) != 1 || manifest.ExcludedSecrets[0] != "llm.api_key" {
+		t.Errorf("ExcludedSecrets = %v, want [llm.api_key]", manifest.ExcludedSecrets)
+	}
+
+	// Import on a "new machine" that already has one local correction
+	dstHome, dstProject := t.TempDir(), t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dstProject, ".floop"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dstProject, ".floop", "corrections.jsonl"), []byte(`{"id":"c1"}`+"\n"), 0600)
+
+	result, err := Import(ctx, archive, ImportOptions{HomeDir: dstHome, ProjectRoot: dstProject})
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if result.Stores[ScopeGlobal].NodesRestored != 1 || result.Stores[ScopeLocal].NodesRestored != 1 {
+		t.Errorf("Stores = %+v, want one node restored per scope", result.Stores)
+	}
+	if !hasNode(t, dstHome, "global-behavior") || !hasNode(t, dstProject, "local-behavior") {
+		t.Error("behaviors should be restored into their own scopes")
+	}
+	if hasNode(t, dstHome, "local-behavior") {
+		t.Error("local behavior should not land in the global store")
+	}
+	if got := result.LinesAppended["local/corrections.jsonl"]; got != 1 {
+		t.Errorf("corrections appended = %d, want 1 (c1 already present)", got)
+	}
+	if result.Config != ConfigWritten {
+		t.Errorf("Config = %q, want %q", result.Config, ConfigWritten)
+	}
+	cfg, _ := os.ReadFile(filepath.Join(dstHome, ".floop", "config.yaml"))
+	if strings.Contains(string(cfg), "sk-secret") || !strings.Contains(string(cfg), "anthropic") {
+		t.Errorf("imported config = %q, want provider without api_key", cfg)
+	}
+
+	// Importing again changes nothing
+	again, err := Import(ctx, archive, ImportOptions{HomeDir: dstHome, ProjectRoot: dstProject})
+	if err != nil {
+		t.Fatalf("second Import() error = %v", err)
+	}
+	if again.Stores[ScopeGlobal].NodesRestored != 0 || again.LinesAppended["global/audit.jsonl"] != 0 || again.Config != ConfigKeptExisting {
+		t.Errorf("second import = %+v, want no changes", again)
+	}
+}
+
+func TestImport_SkipsLocalWithoutProject(t *testing.T) {
+	ctx := context.Background()
+	srcHome, srcProject := t.TempDir(), t.TempDir()
+	seedInstallation(t, srcHome, "g", nil)
+	seedInstallation(t, srcProject, "l", nil)
+
+	archive := filepath.Join(t.TempDir(), "export.tar.gz")
+	if _, err := Export(ctx, archive, ExportOptions{HomeDir: srcHome, ProjectRoot: srcProject}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	dstHome := t.TempDir()
+	result, err := Import(ctx, archive, ImportOptions{HomeDir: dstHome, DryRun: true})
+	if err != nil {
+		t.Fatalf("Import(dry run) error = %v", err)
+	}
+	if len(result.SkippedScopes) != 1 || result.SkippedScopes[0] != ScopeLocal {
+		t.Errorf("SkippedScopes = %v, want [local]", result.SkippedScopes)
+	}
+	if _, err := os.Stat(filepath.Join(dstHome, ".floop")); !os.IsNotExist(err) {
+		t.Error("dry run should not create anything")
+	}
+}
+
+func TestImport_RejectsTamperedArchive(t *testing.T) {
+	ctx := context.Background()
+	home := t.TempDir()
+	seedInstallation(t, home, "g", map[string]string{"audit.jsonl": "{}\n"})
+
+	archive := filepath.Join(t.TempDir(), "export.tar.gz")
+	if _, err := Export(ctx, archive, ExportOptions{HomeDir: home}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	// Rewrite the archive with a modified audit log
+	tampered := filepath.Join(t.TempDir(), "tampered.tar.gz")
+	rewriteArchive(t, archive, tampered, func(name string, data []byte) []byte {
+		if name == "global/audit.jsonl" {
+			return []byte(`{"injected":true}` + "\n")
+		}
+		return data
+	})
+	if _, err := Import(ctx, tampered, ImportOptions{HomeDir: t.TempDir()}); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Import(tampered) error = %v, want checksum mismatch", err)
+	}
+
+	// Entries outside the known layout are rejected
+	escaped := filepath.Join(t.TempDir(), "escaped.tar.gz")
+	rewriteArchive(t, archive, escaped, nil, "../evil")
+	if _, err := Import(ctx, escaped, ImportOptions{HomeDir: t.TempDir()}); err == nil || !strings.Contains(err.Error(), "unexpected archive entry") {
+		t.Errorf("Import(escaped) error = %v, want unexpected entry", err)
+	}
+}
+
+// rewriteArchive copies src to dst, passing each entry through edit and
+// appending extra empty entries.
+func rewriteArchive(t *testing.T, src, dst string, edit func(string, []byte) []byte, extra ...string) {
+	t.Helper()
+	in, err := os.Open(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	gzr, err := gzip.NewReader(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gzr)
+
+	out, err := os.Create(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	gzw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gzw)
+
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if edit != nil {
+			data = edit(hdr.Name, data)
+		}
+		hdr.Size = int64(len(data))
+		tw.WriteHeader(hdr)
+		tw.Write(data)
+	}
+	for _, name := range extra {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Typeflag: tar.TypeReg})
+	}
+	tw.Close()
+	gzw.Close()
+}
+
+func TestStripSecrets(t *testing.T) {
+	tests := []struct {
+		name         string
+		in           string
+		wantExcluded bool
+		wantContains string
+	}{
+		{"literal key removed", "llm:\n  api_key: sk-abc\n  provider: openai\n", true, "provider: openai"},
+		{"env reference kept", "llm:\n  api_key: ${ANTHROPIC_API_KEY}\n", false, "${ANTHROPIC_API_KEY}"},
+		{"no llm section", "prompt:\n  ordering: kind\n", false, "ordering: kind"},
+		{"empty", "", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, excluded, err := stripSecrets([]byte(tt.in))
+			if err != nil {
+				t.Fatalf("stripSecrets() error = %v", err)
+			}
+			if (len(excluded) > 0) != tt.wantExcluded {
+				t.Errorf("excluded = %v, want excluded=%v", excluded, tt.wantExcluded)
+			}
+			if strings.Contains(string(out), "sk-abc") {
+				t.Errorf("output still contains the secret: %q", out)
+			}
+			if !strings.Contains(string(out), tt.wantContains) {
+				t.Errorf("output = %q, want containing %q", out, tt.wantContains)
+			}
+		})
+	}
+}
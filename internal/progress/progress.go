@@ -0,0 +1,205 @@
+// Package progress reports the progress of long-running operations, either
+// as a terminal progress bar or as JSONL events.
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Format selects how a Reporter renders progress.
+type Format int
+
+const (
+	// FormatBar redraws a single-line progress bar in place.
+	FormatBar Format = iota
+	// FormatJSON writes one JSON Event per line.
+	FormatJSON
+)
+
+// redrawInterval limits how often a Reporter writes while an operation is
+// running. The first and last update of each operation are always written.
+const redrawInterval = 100 * time.Millisecond
+
+// barWidth is the number of cells in the progress bar.
+const barWidth = 24
+
+// maxItemWidth is how much of the current item a progress bar shows.
+const maxItemWidth = 40
+
+// Event is one progress update.
+type Event struct {
+	Operation  string  `json:"operation"`
+	Current    int     `json:"current"`
+	Total      int     `json:"total"`
+	Percent    float64 `json:"percent"`
+	Item       string  `json:"item,omitempty"`
+	ETASeconds float64 `json:"eta_seconds,omitempty"`
+	Done       bool    `json:"done,omitempty"`
+}
+
+// Reporter tracks an operation over a known number of items and renders its
+// progress. A nil *Reporter is valid and reports nothing, so callers need
+// not check whether progress was requested.
+type Reporter struct {
+	w      io.Writer
+	format Format
+	now    func() time.Time
+
+	mu       sync.Mutex
+	op       string
+	total    int
+	current  int
+	started  time.Time
+	lastDraw time.Time
+	drawn    bool
+}
+
+// New returns a Reporter writing to w in the given format.
+func New(w io.Writer, format Format) *Reporter {
+	return &Reporter{w: w, format: format, now: time.Now}
+}
+
+// ForWriter returns the Reporter for a command's progress stream: JSON
+// events when jsonOut is set, a progress bar when w is a terminal, and nil
+// (no progress) otherwise, so piped and captured output stays clean.
+func ForWriter(w io.Writer, jsonOut bool) *Reporter {
+	if jsonOut {
+		return New(w, FormatJSON)
+	}
+	if isTerminal(w) {
+		return New(w, FormatBar)
+	}
+	return nil
+}
+
+// isTerminal reports whether w is a character device.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Start begins an operation over total items, ending any operation still
+// in progress.
+func (r *Reporter) Start(operation string, total int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.drawn {
+		r.finishLocked()
+	}
+	r.op, r.total, r.current = operation, total, 0
+	r.started = r.now()
+	r.drawLocked("", false, r.started)
+}
+
+// Step records that item, the next of the operation's items, is done.
+func (r *Reporter) Step(item string) {
+	r.Advance(1, item)
+}
+
+// Advance records that the next n items are done, the last of them item.
+func (r *Reporter) Advance(n int, item string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current += n
+	if r.current > r.total {
+		r.total = r.current
+	}
+	now := r.now()
+	if r.current < r.total && now.Sub(r.lastDraw) < redrawInterval {
+		return
+	}
+	r.drawLocked(item, false, now)
+}
+
+// Finish ends the operation, whether or not every item was done.
+func (r *Reporter) Finish() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.drawn {
+		r.finishLocked()
+	}
+}
+
+// finishLocked writes the operation's final update.
+func (r *Reporter) finishLocked() {
+	r.drawLocked("", true, r.now())
+	if r.format == FormatBar {
+		fmt.Fprintln(r.w)
+	}
+	r.drawn = false
+}
+
+// drawLocked writes the state of the operation as of now.
+func (r *Reporter) drawLocked(item string, done bool, now time.Time) {
+	r.lastDraw = now
+	r.drawn = true
+	ev := r.event(item, done, now)
+	if r.format == FormatJSON {
+		json.NewEncoder(r.w).Encode(ev)
+		return
+	}
+	fmt.Fprintf(r.w, "\r\033[K%s", renderBar(ev))
+}
+
+// event describes the operation as of now.
+func (r *Reporter) event(item string, done bool, now time.Time) Event {
+	ev := Event{
+		Operation: r.op,
+		Current:   r.current,
+		Total:     r.total,
+		Percent:   100,
+		Item:      item,
+		Done:      done,
+	}
+	if r.total > 0 {
+		ev.Percent = float64(r.current) * 100 / float64(r.total)
+	}
+	if !done && r.current > 0 && r.current < r.total {
+		perItem := now.Sub(r.started).Seconds() / float64(r.current)
+		ev.ETASeconds = perItem * float64(r.total-r.current)
+	}
+	return ev
+}
+
+// renderBar formats ev as a single line, e.g.
+// "deduplicate [#########---------------]  38% 19/50 ETA 12s b-1234".
+func renderBar(ev Event) string {
+	filled := int(ev.Percent / 100 * barWidth)
+	if filled > barWidth {
+		filled = barWidth
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s [%s%s] %3.0f%% %d/%d", ev.Operation,
+		strings.Repeat("#", filled), strings.Repeat("-", barWidth-filled),
+		ev.Percent, ev.Current, ev.Total)
+	if ev.ETASeconds > 0 {
+		fmt.Fprintf(&b, " ETA %s", time.Duration(ev.ETASeconds*float64(time.Second)).Round(time.Second))
+	}
+	if ev.Item != "" {
+		item := ev.Item
+		if len(item) > maxItemWidth {
+			item = item[:maxItemWidth-3] + "..."
+		}
+		b.WriteString(" " + item)
+	}
+	return b.String()
+}
@@ -0,0 +1,106 @@
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestReporter returns a Reporter whose clock advances by step on
+// every reading.
+func newTestReporter(format Format, step time.Duration) (*Reporter, *bytes.Buffer) {
+	var buf bytes.Buffer
+	r := New(&buf, format)
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time {
+		clock = clock.Add(step)
+		return clock
+	}
+	return r, &buf
+}
+
+func decodeEvents(t *testing.T, buf *bytes.Buffer) []Event {
+	t.Helper()
+	var events []Event
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var ev Event
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("line %q is not an event: %v", line, err)
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+func TestReporterJSON(t *testing.T) {
+	r, buf := newTestReporter(FormatJSON, time.Second)
+	r.Start("reprocess", 4)
+	for _, item := range []string{"c-1", "c-2", "c-3"} {
+		r.Step(item)
+	}
+	r.Finish()
+
+	events := decodeEvents(t, buf)
+	if len(events) != 5 {
+		t.Fatalf("events = %+v, want start, three steps, and done", events)
+	}
+	third := events[3]
+	if third.Operation != "reprocess" || third.Current != 3 || third.Total != 4 || third.Percent != 75 || third.Item != "c-3" {
+		t.Errorf("third step = %+v", third)
+	}
+	// One clock reading per update: a second per item, one item left
+	if third.ETASeconds != 1 {
+		t.Errorf("ETASeconds = %v, want 1", third.ETASeconds)
+	}
+	if last := events[4]; !last.Done || last.Current != 3 || last.ETASeconds != 0 {
+		t.Errorf("done event = %+v, want the partial count without an ETA", last)
+	}
+}
+
+func TestReporterThrottles(t *testing.T) {
+	r, buf := newTestReporter(FormatJSON, time.Millisecond)
+	r.Start("deduplicate", 50)
+	for i := 0; i < 50; i++ {
+		r.Step("b")
+	}
+	r.Finish()
+
+	events := decodeEvents(t, buf)
+	if len(events) > 5 {
+		t.Errorf("got %d events for 50 fast steps, want them throttled", len(events))
+	}
+	if ev := events[len(events)-2]; ev.Current != 50 || ev.Percent != 100 {
+		t.Errorf("last step = %+v, want the final item always reported", ev)
+	}
+}
+
+func TestReporterBar(t *testing.T) {
+	r, buf := newTestReporter(FormatBar, time.Second)
+	r.Start("restore nodes", 2)
+	r.Step("b-1")
+	r.Finish()
+
+	out := buf.String()
+	if !strings.Contains(out, "restore nodes [############------------]  50% 1/2 ETA 1s b-1") {
+		t.Errorf("bar output = %q", out)
+	}
+	if !strings.HasSuffix(out, "\n") {
+		t.Errorf("bar output = %q, want a newline after the operation", out)
+	}
+}
+
+func TestNilReporter(t *testing.T) {
+	var r *Reporter
+	r.Start("x", 1)
+	r.Step("a")
+	r.Finish()
+
+	if got := ForWriter(&bytes.Buffer{}, false); got != nil {
+		t.Errorf("ForWriter(buffer, false) = %v, want nil for a non-terminal", got)
+	}
+	if got := ForWriter(&bytes.Buffer{}, true); got == nil || got.format != FormatJSON {
+		t.Errorf("ForWriter(buffer, true) = %v, want JSON events", got)
+	}
+}
@@ -0,0 +1,322 @@
+// Package promote moves behaviors that have proven themselves in one project
+// into the global store, so every project benefits from them. The local
+// behavior either moves outright or stays behind as a deprecated copy that
+// defers to the global one.
+package promote
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"time"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/restorepoint"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Reason is recorded as deprecation_reason on a local behavior that defers
+// to its promoted global copy.
+const Reason = "promoted to the global store"
+
+// Stores is a graph store with separate local and global stores, such as
+// store.MultiGraphStore.
+type Stores interface {
+	store.GraphStore
+	store.ScopedStore
+	AddNodeToScope(ctx context.Context, node store.Node, scope store.StoreScope) (string, error)
+}
+
+// Options controls a promotion.
+type Options struct {
+	// MinConfirmations is how many times the behavior must have been
+	// confirmed. Zero uses constants.DefaultPromoteMinConfirmations.
+	MinConfirmations int
+
+	// Force promotes regardless of confirmations.
+	Force bool
+
+	// Move deletes the local behavior instead of keeping it as a deprecated
+	// copy that defers to the global one.
+	Move bool
+
+	// Actor is recorded as promoted_by, Project as promoted_from_project.
+	Actor   string
+	Project string
+
+	// Root is the project root the restore point is saved under.
+	Root string
+
+	// Now is the promotion time; zero uses time.Now.
+	Now time.Time
+}
+
+// Result describes a promotion.
+type Result struct {
+	LocalID       string `json:"local_id"`
+	GlobalID      string `json:"global_id"`
+	Name          string `json:"name"`
+	Moved         bool   `json:"moved"`
+	Confirmations int    `json:"confirmations"`
+
+	// Existing is set when the global store already held a behavior with
+	// the same content, which the local behavior now defers to instead.
+	Existing bool `json:"existing,omitempty"`
+
+	// RestorePoint is the restore point saved before the promotion.
+	RestorePoint string `json:"restore_point,omitempty"`
+}
+
+// GlobalID returns the ID a copied behavior takes in the global store.
+// Moved behaviors keep their ID.
+func GlobalID(localID string) string {
+	sum := sha256.Sum256([]byte("promoted:" + localID))
+	return "behavior-" + hex.EncodeToString(sum[:])[:12]
+}
+
+// Promote copies the local behavior id into the global store and makes the
+// local behavior defer to it: by default the local behavior is deprecated
+// with a deprecated-to edge to the global copy, which floop restore can
+// undo; with opts.Move it is deleted and its edges follow the global copy.
+// A restore point is saved first; applying it removes the global copy and
+// restores the local behavior.
+func Promote(ctx context.Context, s Stores, id string, opts Options) (*Result, error) {
+	node, err := s.LocalStore().GetNode(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get behavior: %w", err)
+	}
+	if node == nil {
+		global, err := s.GlobalStore().GetNode(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get behavior: %w", err)
+		}
+		if global != nil {
+			return nil, fmt.Errorf("behavior %s is already in the global store", id)
+		}
+		return nil, fmt.Errorf("behavior not found in the local store: %s", id)
+	}
+	node.Origin = store.OriginLocal
+	if node.Kind != store.NodeKindBehavior {
+		if to, ok := node.Metadata["promoted_to"].(string); ok {
+			return nil, fmt.Errorf("behavior %s was already promoted to %s", id, to)
+		}
+		return nil, fmt.Errorf("not an active behavior (current kind: %s)", node.Kind)
+	}
+
+	b := models.NodeToBehavior(*node)
+	minConfirmations := opts.MinConfirmations
+	if minConfirmations <= 0 {
+		minConfirmations = constants.DefaultPromoteMinConfirmations
+	}
+	if b.Stats.TimesConfirmed < minConfirmations && !opts.Force {
+		return nil, fmt.Errorf("behavior %s has %d confirmations, %d needed for promotion (use force to promote anyway)",
+			id, b.Stats.TimesConfirmed, minConfirmations)
+	}
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	result := &Result{LocalID: id, Name: b.Name, Moved: opts.Move, Confirmations: b.Stats.TimesConfirmed}
+
+	// The global copy's ID is captured while still absent, so applying the
+	// restore point removes the copy. A moved behavior keeps its ID.
+	captured := []string{id}
+	if !opts.Move {
+		captured = append(captured, GlobalID(id))
+	}
+	point, err := restorepoint.Create(ctx, s, opts.Root, "promote", "promote "+id, captured)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create restore point: %w", err)
+	}
+	result.RestorePoint = point.ID
+
+	global := store.Node{
+		ID:       id,
+		Kind:     store.NodeKindBehavior,
+		Content:  maps.Clone(node.Content),
+		Metadata: maps.Clone(node.Metadata),
+	}
+	if global.Metadata == nil {
+		global.Metadata = make(map[string]interface{})
+	}
+	if !opts.Move {
+		global.ID = GlobalID(id)
+	}
+	global.Metadata["promoted_from"] = id
+	global.Metadata["promoted_at"] = now.UTC().Format(time.RFC3339)
+	if opts.Project != "" {
+		global.Metadata["promoted_from_project"] = opts.Project
+	}
+	if opts.Actor != "" {
+		global.Metadata["promoted_by"] = opts.Actor
+	}
+
+	globalID, err := s.AddNodeToScope(ctx, global, store.ScopeGlobal)
+	var dup *store.DuplicateContentError
+	switch {
+	case errors.As(err, &dup):
+		globalID = dup.ExistingID
+		result.Existing = true
+	case err != nil:
+		return nil, fmt.Errorf("failed to add global behavior: %w", err)
+	}
+	result.GlobalID = globalID
+
+	if opts.Move {
+		if err := moveEdges(ctx, s, id, globalID, now); err != nil {
+			return nil, err
+		}
+	} else if err := deferTo(ctx, s, node, globalID, opts.Actor, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.Sync(ctx); err != nil {
+		return nil, fmt.Errorf("failed to sync changes: %w", err)
+	}
+	return result, nil
+}
+
+// moveEdges deletes the local behavior id and re-adds its edges on globalID.
+// Deleting the local node also deletes its local edges; the re-added edges
+// cross stores, so they land in the global store.
+func moveEdges(ctx context.Context, s Stores, id, globalID string, now time.Time) error {
+	edges, err := s.LocalStore().GetEdges(ctx, id, store.DirectionBoth, "")
+	if err != nil {
+		return fmt.Errorf("failed to get edges: %w", err)
+	}
+	if err := s.LocalStore().DeleteNode(ctx, id); err != nil {
+		return fmt.Errorf("failed to remove local behavior: %w", err)
+	}
+	for _, e := range edges {
+		if e.Source == id {
+			e.Source = globalID
+		}
+		if e.Target == id {
+			e.Target = globalID
+		}
+		// Defensive fallback for legacy edges missing Weight/CreatedAt
+		if e.Weight <= 0 {
+			e.Weight = 1.0
+		}
+		if e.CreatedAt.IsZero() {
+			e.CreatedAt = now
+		}
+		if err := s.AddEdge(ctx, e); err != nil {
+			return fmt.Errorf("failed to move edge %s -> %s: %w", e.Source, e.Target, err)
+		}
+	}
+	return nil
+}
+
+// deferTo deprecates the local node in favor of globalID, the way floop
+// deprecate --replacement does, and records the promotion.
+func deferTo(ctx context.Context, s Stores, node *store.Node, globalID, actor string, now time.Time) error {
+	if node.Metadata == nil {
+		node.Metadata = make(map[string]interface{})
+	}
+	node.Metadata["original_kind"] = node.Kind
+	node.Metadata["deprecated_at"] = now.UTC().Format(time.RFC3339)
+	node.Metadata["deprecated_by"] = actor
+	node.Metadata["deprecation_reason"] = Reason
+	node.Metadata["replacement_id"] = globalID
+	node.Metadata["promoted_to"] = globalID
+	node.Kind = store.NodeKindDeprecated
+
+	if err := s.UpdateNode(ctx, *node); err != nil {
+		return fmt.Errorf("failed to update local behavior: %w", err)
+	}
+	edge := store.Edge{
+		Source:    node.ID,
+		Target:    globalID,
+		Kind:      store.EdgeKindDeprecatedTo,
+		Weight:    1.0,
+		CreatedAt: now,
+		Metadata: map[string]interface{}{
+			"created_at": now.UTC().Format(time.RFC3339),
+		},
+	}
+	if err := s.AddEdge(ctx, edge); err != nil {
+		return fmt.Errorf("failed to add deprecation edge: %w", err)
+	}
+	return nil
+}
+
+// Thresholds decide which local behaviors Suggest proposes for promotion.
+type Thresholds struct {
+	// MinConfirmations is the fewest confirmations a suggestion has.
+	MinConfirmations int
+
+	// MinConfidence is the lowest confidence a suggestion has.
+	MinConfidence float64
+
+	// MaxOverrideRatio is the largest share of a suggestion's feedback
+	// (confirmations, follows, and overrides) that may be overrides.
+	MaxOverrideRatio float64
+}
+
+// DefaultThresholds returns the default suggestion thresholds.
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		MinConfirmations: constants.DefaultPromoteMinConfirmations,
+		MinConfidence:    constants.DefaultPromoteMinConfidence,
+		MaxOverrideRatio: constants.DefaultPromoteMaxOverrideRatio,
+	}
+}
+
+// Suggestion is a local behavior ready for promotion.
+type Suggestion struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	Confirmations int     `json:"confirmations"`
+	Overrides     int     `json:"overrides"`
+	Activations   int     `json:"activations"`
+	Confidence    float64 `json:"confidence"`
+	OverrideRatio float64 `json:"override_ratio"`
+}
+
+// Suggest returns the active local behaviors that meet t, most confirmed
+// first.
+func Suggest(ctx context.Context, s Stores, t Thresholds) ([]Suggestion, error) {
+	nodes, err := s.LocalStore().QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query behaviors: %w", err)
+	}
+
+	var out []Suggestion
+	for _, n := range nodes {
+		b := models.NodeToBehavior(n)
+		st := b.Stats
+		if st.TimesConfirmed < t.MinConfirmations || b.Confidence < t.MinConfidence {
+			continue
+		}
+		var ratio float64
+		if feedback := st.TimesConfirmed + st.TimesFollowed + st.TimesOverridden; feedback > 0 {
+			ratio = float64(st.TimesOverridden) / float64(feedback)
+		}
+		if ratio > t.MaxOverrideRatio {
+			continue
+		}
+		out = append(out, Suggestion{
+			ID:            b.ID,
+			Name:          b.Name,
+			Confirmations: st.TimesConfirmed,
+			Overrides:     st.TimesOverridden,
+			Activations:   st.TimesActivated,
+			Confidence:    b.Confidence,
+			OverrideRatio: ratio,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Confirmations != out[j].Confirmations {
+			return out[i].Confirmations > out[j].Confirmations
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
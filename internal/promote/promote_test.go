@@ -0,0 +1,223 @@
+package promote
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/restorepoint"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// newStores opens a MultiGraphStore over a temporary project and home.
+func newStores(t *testing.T) (*store.MultiGraphStore, string) {
+	t.Helper()
+	root := t.TempDir()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	if err := os.MkdirAll(filepath.Join(root, ".floop"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	s, err := store.NewMultiGraphStore(root)
+	if err != nil {
+		t.Fatalf("NewMultiGraphStore() error = %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s, root
+}
+
+// addLocal adds an active local behavior with the given feedback counts.
+func addLocal(t *testing.T, s *store.MultiGraphStore, id string, confirmed, overridden int, confidence float64) {
+	t.Helper()
+	node := store.Node{
+		ID:   id,
+		Kind: store.NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    id,
+			"kind":    "directive",
+			"content": map[string]interface{}{"canonical": "Rule for " + id},
+		},
+		Metadata: map[string]interface{}{
+			"confidence": confidence,
+			"stats": map[string]interface{}{
+				"times_activated":  confirmed + overridden,
+				"times_confirmed":  confirmed,
+				"times_overridden": overridden,
+			},
+		},
+	}
+	if _, err := s.AddNodeToScope(context.Background(), node, store.ScopeLocal); err != nil {
+		t.Fatalf("AddNodeToScope(%s) error = %v", id, err)
+	}
+}
+
+func TestPromote_Copy(t *testing.T) {
+	ctx := context.Background()
+	s, root := newStores(t)
+	addLocal(t, s, "b-local", 4, 0, 0.9)
+	addLocal(t, s, "b-other", 0, 0, 0.5)
+	if err := s.AddEdge(ctx, store.Edge{Source: "b-other", Target: "b-local", Kind: store.EdgeKindRequires, Weight: 1, CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Promote(ctx, s, "b-local", Options{Root: root, Actor: "alice", Project: "proj"})
+	if err != nil {
+		t.Fatalf("Promote() error = %v", err)
+	}
+	if res.GlobalID != GlobalID("b-local") || res.Moved || res.Existing || res.Confirmations != 4 || res.RestorePoint == "" {
+		t.Errorf("Promote() = %+v", res)
+	}
+
+	global, _ := s.GlobalStore().GetNode(ctx, res.GlobalID)
+	if global == nil || global.Kind != store.NodeKindBehavior {
+		t.Fatalf("global copy = %+v", global)
+	}
+	if global.Metadata["promoted_from"] != "b-local" || global.Metadata["promoted_from_project"] != "proj" || global.Metadata["scope"] != "global" {
+		t.Errorf("global metadata = %v", global.Metadata)
+	}
+
+	// The local behavior stays, deprecated in favor of the global copy
+	local, _ := s.LocalStore().GetNode(ctx, "b-local")
+	if local.Kind != store.NodeKindDeprecated || local.Metadata["replacement_id"] != res.GlobalID || local.Metadata["deprecation_reason"] != Reason {
+		t.Errorf("local behavior = %+v", local)
+	}
+	edges, _ := s.GetEdges(ctx, "b-local", store.DirectionOutbound, store.EdgeKindDeprecatedTo)
+	if len(edges) != 1 || edges[0].Target != res.GlobalID {
+		t.Errorf("deprecated-to edges = %+v", edges)
+	}
+
+	if _, err := Promote(ctx, s, "b-local", Options{Root: root}); err == nil || !strings.Contains(err.Error(), "already promoted") {
+		t.Errorf("second Promote() error = %v, want already promoted", err)
+	}
+}
+
+func TestPromote_RestorePoint(t *testing.T) {
+	ctx := context.Background()
+	for _, move := range []bool{false, true} {
+		s, root := newStores(t)
+		addLocal(t, s, "b-local", 3, 0, 0.9)
+
+		res, err := Promote(ctx, s, "b-local", Options{Root: root, Move: move})
+		if err != nil {
+			t.Fatalf("Promote(move %v) error = %v", move, err)
+		}
+		dir, err := restorepoint.Dir(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		point, err := restorepoint.Load(dir, res.RestorePoint)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if _, err := restorepoint.Apply(ctx, s, point); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+
+		if global, _ := s.GlobalStore().GetNode(ctx, res.GlobalID); global != nil {
+			t.Errorf("move %v: global copy %s left after applying the restore point", move, res.GlobalID)
+		}
+		if local, _ := s.LocalStore().GetNode(ctx, "b-local"); local == nil || local.Kind != store.NodeKindBehavior {
+			t.Errorf("move %v: local behavior after applying the restore point = %+v", move, local)
+		}
+	}
+}
+
+func TestPromote_Move(t *testing.T) {
+	ctx := context.Background()
+	s, root := newStores(t)
+	addLocal(t, s, "b-local", 3, 0, 0.9)
+	addLocal(t, s, "b-other", 0, 0, 0.5)
+	if err := s.AddEdge(ctx, store.Edge{Source: "b-other", Target: "b-local", Kind: store.EdgeKindRequires, Weight: 1, CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Promote(ctx, s, "b-local", Options{Root: root, Move: true})
+	if err != nil {
+		t.Fatalf("Promote() error = %v", err)
+	}
+	if res.GlobalID != "b-local" || !res.Moved {
+		t.Errorf("Promote() = %+v", res)
+	}
+	if local, _ := s.LocalStore().GetNode(ctx, "b-local"); local != nil {
+		t.Errorf("local behavior still present: %+v", local)
+	}
+	node, _ := s.GetNode(ctx, "b-local")
+	if node == nil || node.Origin != store.OriginGlobal {
+		t.Fatalf("GetNode() after move = %+v", node)
+	}
+	edges, _ := s.GetEdges(ctx, "b-local", store.DirectionInbound, store.EdgeKindRequires)
+	if len(edges) != 1 || edges[0].Source != "b-other" {
+		t.Errorf("moved edges = %+v", edges)
+	}
+
+	if _, err := Promote(ctx, s, "b-local", Options{Root: root}); err == nil || !strings.Contains(err.Error(), "already in the global store") {
+		t.Errorf("Promote() of a global behavior error = %v", err)
+	}
+}
+
+func TestPromote_Confirmations(t *testing.T) {
+	ctx := context.Background()
+	s, root := newStores(t)
+	addLocal(t, s, "b-new", 1, 0, 0.6)
+
+	if _, err := Promote(ctx, s, "b-new", Options{Root: root}); err == nil || !strings.Contains(err.Error(), "1 confirmations, 3 needed") {
+		t.Errorf("Promote() error = %v, want a confirmations error", err)
+	}
+	if _, err := Promote(ctx, s, "b-new", Options{Root: root, MinConfirmations: 1}); err != nil {
+		t.Errorf("Promote() with MinConfirmations 1 error = %v", err)
+	}
+}
+
+func TestPromote_ExistingGlobal(t *testing.T) {
+	ctx := context.Background()
+	s, root := newStores(t)
+	addLocal(t, s, "b-local", 0, 0, 0.6)
+	existing := store.Node{
+		ID:      "b-global",
+		Kind:    store.NodeKindBehavior,
+		Content: map[string]interface{}{"name": "b-global", "content": map[string]interface{}{"canonical": "Rule for b-local"}},
+	}
+	if _, err := s.AddNodeToScope(ctx, existing, store.ScopeGlobal); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Promote(ctx, s, "b-local", Options{Root: root, Force: true})
+	if err != nil {
+		t.Fatalf("Promote() error = %v", err)
+	}
+	if !res.Existing || res.GlobalID != "b-global" {
+		t.Errorf("Promote() = %+v, want deferring to b-global", res)
+	}
+	if local, _ := s.LocalStore().GetNode(ctx, "b-local"); local.Metadata["replacement_id"] != "b-global" {
+		t.Errorf("local replacement = %v", local.Metadata["replacement_id"])
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	s, _ := newStores(t)
+	addLocal(t, s, "b-ready", 5, 1, 0.9)
+	addLocal(t, s, "b-also", 3, 0, 0.8)
+	addLocal(t, s, "b-same", 3, 0, 0.8)
+	addLocal(t, s, "b-few", 2, 0, 0.9)
+	addLocal(t, s, "b-unsure", 6, 0, 0.5)
+	addLocal(t, s, "b-disputed", 6, 3, 0.9)
+
+	got, err := Suggest(context.Background(), s, DefaultThresholds())
+	if err != nil {
+		t.Fatalf("Suggest() error = %v", err)
+	}
+	var ids []string
+	for _, sg := range got {
+		ids = append(ids, sg.ID)
+	}
+	if strings.Join(ids, ",") != "b-ready,b-also,b-same" {
+		t.Errorf("Suggest() = %v, want b-ready,b-also,b-same", ids)
+	}
+	if got[0].Confirmations != 5 || got[0].Overrides != 1 {
+		t.Errorf("Suggest()[0] = %+v", got[0])
+	}
+}
@@ -0,0 +1,166 @@
+// Package prompt is the pipeline from stored behaviors to a prompt section
+// shared by 'floop prompt', 'floop serve', and libfloop: evaluate the
+// behaviors against a context, resolve the active ones, and compile them
+// within a token budget with the configured ordering, packing, and per-kind
+// budgets.
+package prompt
+
+import (
+	"fmt"
+
+	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/assembly"
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/spreading"
+	"github.com/nvandessel/floop/internal/tiering"
+)
+
+// NewEvaluator returns an evaluator with the match options of cfg. A nil
+// cfg or an invalid match mode keeps the default options.
+func NewEvaluator(cfg *config.FloopConfig) *activation.Evaluator {
+	evaluator := activation.NewEvaluator()
+	if cfg == nil {
+		return evaluator
+	}
+	mode, err := activation.ParseMatchMode(cfg.Ranking.Match.Mode)
+	if err != nil {
+		return evaluator
+	}
+	return evaluator.WithMatchOptions(activation.MatchOptions{
+		Mode:         mode,
+		MinScore:     cfg.Ranking.Match.MinScore,
+		FieldWeights: cfg.Ranking.Match.FieldWeights,
+	})
+}
+
+// Resolve evaluates behaviors against actCtx and resolves the active ones,
+// then narrows them to kinds. Filtering after resolution keeps overrides and
+// conflicts from excluded kinds effective.
+func Resolve(evaluator *activation.Evaluator, actCtx models.ContextSnapshot, behaviors []models.Behavior, kinds activation.KindFilter) activation.ResolveResult {
+	matches := evaluator.Evaluate(actCtx, behaviors)
+	resolved := activation.NewResolver().WithBehaviors(behaviors).Resolve(matches)
+	resolved.Active = kinds.Apply(resolved.Active)
+	return resolved
+}
+
+// ParseFormat parses an output format name. Empty is markdown.
+func ParseFormat(s string) (assembly.Format, error) {
+	switch s {
+	case "", "markdown":
+		return assembly.FormatMarkdown, nil
+	case "xml":
+		return assembly.FormatXML, nil
+	case "plain":
+		return assembly.FormatPlain, nil
+	}
+	return "", fmt.Errorf("invalid format %q (want markdown, xml, or plain)", s)
+}
+
+// Options controls how Compile renders the active behaviors.
+type Options struct {
+	Format assembly.Format
+	Task   string
+
+	// Order and Packing override prompt.ordering and prompt.packing when set.
+	Order   string
+	Packing string
+
+	// TokenBudget caps the prompt's size; 0 includes every behavior in full.
+	TokenBudget int
+
+	// Tiered fits the budget by summarizing and omitting behaviors by
+	// activation instead of dropping them whole with the optimizer.
+	Tiered bool
+
+	// Rerank, if set, adjusts the activation of tiered behaviors before
+	// they are mapped to tiers, e.g. with an external scorer.
+	Rerank func(results []spreading.Result, behaviors map[string]*models.Behavior) []spreading.Result
+}
+
+// Result is a compiled prompt: Prompt, or Tiered and Plan when tiered.
+type Result struct {
+	Prompt *assembly.CompiledPrompt
+	Tiered *assembly.TieredCompiledPrompt
+	Plan   *models.InjectionPlan
+
+	// Included lists the behaviors compiled into an untiered prompt, after
+	// the optimizer dropped those over the budget.
+	Included []models.Behavior
+}
+
+// Compile compiles the resolved behaviors with the prompt configuration
+// cfg. An invalid ordering, packing strategy, or per-kind budget is an
+// error.
+func Compile(resolved activation.ResolveResult, cfg config.PromptConfig, opts Options) (*Result, error) {
+	if opts.Order != "" {
+		cfg.Ordering = opts.Order
+	}
+	if opts.Packing != "" {
+		cfg.Packing = opts.Packing
+	}
+	ordering, err := assembly.ParseOrderStrategy(cfg.Ordering)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := assembly.ParsePackingStrategy(cfg.Packing); err != nil {
+		return nil, err
+	}
+
+	compiler := assembly.NewCompiler().
+		WithFormat(opts.Format).
+		WithOrdering(ordering).
+		WithTask(opts.Task).
+		WithParents(resolved.Generalized)
+
+	if opts.Tiered && opts.TokenBudget > 0 {
+		results, behaviorMap := tiering.BehaviorsToResults(resolved.Active)
+		if opts.Rerank != nil {
+			results = opts.Rerank(results, behaviorMap)
+		}
+		plan := tiering.NewActivationTierMapper(tiering.DefaultActivationTierConfig()).
+			WithParents(resolved.Generalized).
+			MapResults(results, behaviorMap, opts.TokenBudget)
+		return &Result{Tiered: compiler.CompileTiered(plan), Plan: plan}, nil
+	}
+
+	included := resolved.Active
+	var excluded []models.Behavior
+	if opts.TokenBudget > 0 {
+		optimizer, err := newOptimizer(opts.TokenBudget, cfg, resolved.Active)
+		if err != nil {
+			return nil, err
+		}
+		optimized := optimizer.Optimize(resolved.Active)
+		included, excluded = optimized.Included, optimized.Excluded
+	}
+	compiled := compiler.Compile(included)
+	for _, b := range excluded {
+		compiled.ExcludedBehaviors = append(compiled.ExcludedBehaviors, b.ID)
+	}
+	return &Result{Prompt: compiled, Included: included}, nil
+}
+
+// newOptimizer returns the optimizer fitting active behaviors into
+// maxTokens with the configured per-kind budgets and packing strategy. Value
+// packing scores the behaviors with the default relevance scorer.
+func newOptimizer(maxTokens int, cfg config.PromptConfig, active []models.Behavior) (*assembly.Optimizer, error) {
+	budgets, err := assembly.ParseKindBudgets(cfg.Budgets)
+	if err != nil {
+		return nil, err
+	}
+	packing, err := assembly.ParsePackingStrategy(cfg.Packing)
+	if err != nil {
+		return nil, err
+	}
+	optimizer := assembly.NewOptimizer(maxTokens).WithKindBudgets(budgets).WithPacking(packing)
+	if packing == assembly.PackValue {
+		results, _ := tiering.BehaviorsToResults(active)
+		scores := make(map[string]float64, len(results))
+		for _, r := range results {
+			scores[r.BehaviorID] = r.Activation
+		}
+		optimizer.WithScores(scores)
+	}
+	return optimizer, nil
+}
@@ -0,0 +1,80 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func testResolved() activation.ResolveResult {
+	return activation.ResolveResult{Active: []models.Behavior{
+		{ID: "b-constraint", Name: "no-secrets", Kind: models.BehaviorKindConstraint, Confidence: 0.9,
+			Content: models.BehaviorContent{Canonical: "Never commit secrets to the repository"}},
+		{ID: "b-directive", Name: "use-logger", Kind: models.BehaviorKindDirective, Confidence: 0.8,
+			Content: models.BehaviorContent{Canonical: "Write diagnostics to the structured logger instead of printing them"}},
+	}}
+}
+
+func TestCompile_InvalidStrategies(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.PromptConfig
+		opts Options
+		want string
+	}{
+		{"configured ordering", config.PromptConfig{Ordering: "random"}, Options{}, "random"},
+		{"requested ordering", config.PromptConfig{}, Options{Order: "random"}, "random"},
+		{"configured packing", config.PromptConfig{Packing: "greedy"}, Options{}, "greedy"},
+		{"configured budgets", config.PromptConfig{Budgets: map[string]string{"directive": "lots"}}, Options{TokenBudget: 100}, "lots"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Compile(testResolved(), tt.cfg, tt.opts); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Compile() error = %v, want one naming %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompile_Budgets(t *testing.T) {
+	// A 1 token budget fits nothing, so only the always-included kind stays
+	cfg := config.PromptConfig{Budgets: map[string]string{"directive": "always"}}
+	res, err := Compile(testResolved(), cfg, Options{Format: "markdown", TokenBudget: 1})
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	if res.Tiered != nil || len(res.Included) != 1 || res.Included[0].ID != "b-directive" {
+		t.Errorf("Compile() included %+v, want only b-directive", res.Included)
+	}
+	if got := res.Prompt.ExcludedBehaviors; len(got) != 1 || got[0] != "b-constraint" {
+		t.Errorf("ExcludedBehaviors = %v, want [b-constraint]", got)
+	}
+}
+
+func TestCompile_Tiered(t *testing.T) {
+	res, err := Compile(testResolved(), config.PromptConfig{}, Options{Format: "markdown", TokenBudget: 500, Tiered: true})
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	if res.Tiered == nil || res.Plan == nil || res.Prompt != nil {
+		t.Fatalf("Compile() = %+v, want a tiered prompt", res)
+	}
+	if res.Plan.IncludedCount() != 2 {
+		t.Errorf("plan included %d behaviors, want 2", res.Plan.IncludedCount())
+	}
+}
+
+func TestResolve_Kinds(t *testing.T) {
+	behaviors := testResolved().Active
+	kinds, err := activation.ParseKindFilter([]string{"constraint"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved := Resolve(NewEvaluator(nil), models.ContextSnapshot{}, behaviors, kinds)
+	if len(resolved.Active) != 1 || resolved.Active[0].ID != "b-constraint" {
+		t.Errorf("Resolve() active = %+v, want only b-constraint", resolved.Active)
+	}
+}
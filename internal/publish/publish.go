@@ -0,0 +1,379 @@
+// Package publish writes read-only, versioned snapshots of the behavior graph
+// for consumption by other tooling (docs sites, dashboards) without exposing
+// the live store.
+package publish
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/assembly"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// ManifestVersion is the current snapshot manifest format version.
+const ManifestVersion = 1
+
+// Snapshot layout within a target:
+//
+//	latest.json                     pointer to the newest snapshot (written last)
+//	snapshots/<id>/manifest.json    file list with checksums
+//	snapshots/<id>/nodes.jsonl
+//	snapshots/<id>/edges.jsonl
+//	snapshots/<id>/prompts/<name>.md
+const (
+	LatestFile    = "latest.json"
+	SnapshotsDir  = "snapshots"
+	ManifestFile  = "manifest.json"
+	NodesFile     = "nodes.jsonl"
+	EdgesFile     = "edges.jsonl"
+	PromptsDir    = "prompts"
+	snapshotIDFmt = "20060102T150405.000Z"
+)
+
+// Options configures a publish run.
+type Options struct {
+	// Format is the prompt output format. Defaults to markdown.
+	Format assembly.Format
+
+	// Ordering is the prompt section ordering strategy. Defaults to kind.
+	Ordering assembly.OrderStrategy
+
+	// FloopVersion is recorded in the manifest.
+	FloopVersion string
+
+	// Now overrides the snapshot timestamp (for tests).
+	Now time.Time
+}
+
+// PromptContext is a named activation context a prompt is compiled for.
+type PromptContext struct {
+	Name     string `json:"name"`
+	Language string `json:"language,omitempty"`
+	Task     string `json:"task,omitempty"`
+}
+
+// ManifestFileEntry describes one file in a snapshot.
+type ManifestFileEntry struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	Size   int    `json:"size"`
+}
+
+// ManifestPrompt describes one compiled prompt in a snapshot.
+type ManifestPrompt struct {
+	PromptContext
+	Path          string `json:"path"`
+	BehaviorCount int    `json:"behavior_count"`
+	TotalTokens   int    `json:"total_tokens"`
+}
+
+// Manifest describes a published snapshot.
+type Manifest struct {
+	Version      int                 `json:"version"`
+	SnapshotID   string              `json:"snapshot_id"`
+	CreatedAt    time.Time           `json:"created_at"`
+	FloopVersion string              `json:"floop_version,omitempty"`
+	NodeCount    int                 `json:"node_count"`
+	EdgeCount    int                 `json:"edge_count"`
+	Prompts      []ManifestPrompt    `json:"prompts"`
+	Files        []ManifestFileEntry `json:"files"`
+}
+
+// Latest is the content of latest.json.
+type Latest struct {
+	SnapshotID string    `json:"snapshot_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	Manifest   string    `json:"manifest"`
+}
+
+// Publish collects the graph from graphStore and writes a snapshot to target.
+// Snapshot files are written before manifest.json, and latest.json is updated
+// last, so readers that follow latest.json never observe a partial snapshot.
+func Publish(ctx context.Context, graphStore store.GraphStore, target Target, opts Options) (*Manifest, error) {
+	if opts.Format == "" {
+		opts.Format = assembly.FormatMarkdown
+	}
+	if opts.Ordering == "" {
+		opts.Ordering = assembly.OrderByKind
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	now = now.UTC()
+
+	nodes, edges, err := collectGraph(ctx, graphStore)
+	if err != nil {
+		return nil, err
+	}
+
+	id := now.Format(snapshotIDFmt)
+	base := path.Join(SnapshotsDir, id)
+	if err := checkUnused(ctx, target, base); err != nil {
+		return nil, err
+	}
+	manifest := &Manifest{
+		Version:      ManifestVersion,
+		SnapshotID:   id,
+		CreatedAt:    now,
+		FloopVersion: opts.FloopVersion,
+		NodeCount:    len(nodes),
+		EdgeCount:    len(edges),
+	}
+
+	put := func(name string, data []byte) error {
+		full := path.Join(base, name)
+		if err := target.Put(ctx, full, data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", full, err)
+		}
+		sum := sha256.Sum256(data)
+		manifest.Files = append(manifest.Files, ManifestFileEntry{
+			Path:   name,
+			SHA256: hex.EncodeToString(sum[:]),
+			Size:   len(data),
+		})
+		return nil
+	}
+
+	nodesData, err := encodeJSONL(nodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode nodes: %w", err)
+	}
+	if err := put(NodesFile, nodesData); err != nil {
+		return nil, err
+	}
+
+	edgesData, err := encodeJSONL(edges)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode edges: %w", err)
+	}
+	if err := put(EdgesFile, edgesData); err != nil {
+		return nil, err
+	}
+
+	behaviors := behaviorsFromNodes(nodes, edges)
+	compiler := assembly.NewCompiler().WithFormat(opts.Format).WithOrdering(opts.Ordering)
+	evaluator := activation.NewEvaluator()
+	resolver := activation.NewResolver().WithBehaviors(behaviors)
+	index := activation.NewIndex(behaviors)
+	for _, pc := range CommonContexts(behaviors) {
+		compiled := compilePrompt(compiler, evaluator, resolver, index, pc)
+		name := path.Join(PromptsDir, pc.Name+promptExt(opts.Format))
+		if err := put(name, []byte(compiled.Text)); err != nil {
+			return nil, err
+		}
+		manifest.Prompts = append(manifest.Prompts, ManifestPrompt{
+			PromptContext: pc,
+			Path:          name,
+			BehaviorCount: len(compiled.IncludedBehaviors),
+			TotalTokens:   compiled.TotalTokens,
+		})
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := target.Put(ctx, path.Join(base, ManifestFile), manifestData); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	latestData, err := json.MarshalIndent(Latest{
+		SnapshotID: id,
+		CreatedAt:  now,
+		Manifest:   path.Join(base, ManifestFile),
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode latest pointer: %w", err)
+	}
+	if err := target.Put(ctx, LatestFile, latestData); err != nil {
+		return nil, fmt.Errorf("failed to write latest pointer: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// checkUnused refuses to reuse a snapshot directory that already has a
+// manifest, since readers treat published snapshots as immutable. Targets
+// that cannot be read back are not checked.
+func checkUnused(ctx context.Context, target Target, base string) error {
+	reader, ok := target.(Reader)
+	if !ok {
+		return nil
+	}
+	name := path.Join(base, ManifestFile)
+	_, err := reader.Get(ctx, name)
+	switch {
+	case err == nil:
+		return fmt.Errorf("snapshot %s already exists", name)
+	case errors.Is(err, fs.ErrNotExist):
+		return nil
+	default:
+		return fmt.Errorf("failed to check for existing snapshot: %w", err)
+	}
+}
+
+// CommonContexts returns the contexts prompts are compiled for: a default
+// context with no file or task, plus one per language and per task that
+// appears in a behavior's when-condition.
+func CommonContexts(behaviors []models.Behavior) []PromptContext {
+	languages := make(map[string]bool)
+	tasks := make(map[string]bool)
+	for _, b := range behaviors {
+		for _, v := range whenValues(b.When["language"]) {
+			languages[v] = true
+		}
+		for _, v := range whenValues(b.When["task"]) {
+			tasks[v] = true
+		}
+	}
+
+	contexts := []PromptContext{{Name: "default"}}
+	for _, lang := range sortedKeys(languages) {
+		contexts = append(contexts, PromptContext{Name: "language-" + safeName(lang), Language: lang})
+	}
+	for _, task := range sortedKeys(tasks) {
+		contexts = append(contexts, PromptContext{Name: "task-" + safeName(task), Task: task})
+	}
+	return contexts
+}
+
+// compilePrompt evaluates, resolves, and compiles the indexed behaviors for
+// one context.
+func compilePrompt(compiler *assembly.Compiler, evaluator *activation.Evaluator, resolver *activation.Resolver, index *activation.Index, pc PromptContext) *assembly.CompiledPrompt {
+	snapshot := activation.NewContextBuilder().
+		WithLanguage(pc.Language).
+		WithTask(pc.Task).
+		Build()
+
+	matches := evaluator.EvaluateIndex(snapshot, index)
+	resolved := resolver.Resolve(matches)
+	return compiler.WithTask(pc.Task).WithParents(resolved.Generalized).Compile(resolved.Active)
+}
+
+// collectGraph gathers all nodes and edges, sorted for reproducible output.
+func collectGraph(ctx context.Context, graphStore store.GraphStore) ([]store.Node, []store.Edge, error) {
+	nodes, err := graphStore.QueryNodes(ctx, map[string]interface{}{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query nodes: %w", err)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+	edgeSet := make(map[string]store.Edge)
+	for _, node := range nodes {
+		edges, err := graphStore.GetEdges(ctx, node.ID, store.DirectionOutbound, "")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get edges for %s: %w", node.ID, err)
+		}
+		for _, e := range edges {
+			edgeSet[fmt.Sprintf("%s:%s:%s", e.Source, e.Target, e.Kind)] = e
+		}
+	}
+
+	keys := make([]string, 0, len(edgeSet))
+	for k := range edgeSet {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	edges := make([]store.Edge, len(keys))
+	for i, k := range keys {
+		edges[i] = edgeSet[k]
+	}
+
+	return nodes, edges, nil
+}
+
+// behaviorsFromNodes converts behavior nodes, skipping other node kinds, and
+// fills their relationships from edges so the resolver can enforce them.
+func behaviorsFromNodes(nodes []store.Node, edges []store.Edge) []models.Behavior {
+	behaviors := make([]models.Behavior, 0, len(nodes))
+	for _, n := range nodes {
+		if n.Kind != store.NodeKindBehavior {
+			continue
+		}
+		behaviors = append(behaviors, models.NodeToBehavior(n))
+	}
+	models.ApplyRelationshipEdges(behaviors, edges)
+	return behaviors
+}
+
+// encodeJSONL encodes each element of items as one JSON line.
+func encodeJSONL[T any](items []T) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, item := range items {
+		if err := encoder.Encode(item); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// whenValues extracts string values from a when-condition entry.
+func whenValues(v interface{}) []string {
+	switch val := v.(type) {
+	case string:
+		return []string{val}
+	case []string:
+		return val
+	case []interface{}:
+		out := make([]string, 0, len(val))
+		for _, item := range val {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		if k != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// safeName makes a context value safe for use in a file name.
+func safeName(s string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('-')
+		}
+	}
+	return sb.String()
+}
+
+// promptExt returns the file extension for a prompt format.
+func promptExt(format assembly.Format) string {
+	switch format {
+	case assembly.FormatXML:
+		return ".xml"
+	case assembly.FormatPlain:
+		return ".txt"
+	default:
+		return ".md"
+	}
+}
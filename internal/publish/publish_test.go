@@ -0,0 +1,193 @@
+package publish
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// memTarget records writes in order for inspection.
+type memTarget struct {
+	mu    sync.Mutex
+	files map[string][]byte
+	order []string
+}
+
+func newMemTarget() *memTarget {
+	return &memTarget{files: make(map[string][]byte)}
+}
+
+func (m *memTarget) Put(_ context.Context, name string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[name] = append([]byte(nil), data...)
+	m.order = append(m.order, name)
+	return nil
+}
+
+func (m *memTarget) Get(_ context.Context, name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.files[name]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return data, nil
+}
+
+func (m *memTarget) String() string { return "mem" }
+
+func addBehavior(t *testing.T, s store.GraphStore, id, kind string, when map[string]interface{}) {
+	t.Helper()
+	node := store.Node{
+		ID:   id,
+		Kind: store.NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name": id,
+			"kind": kind,
+			"content": map[string]interface{}{
+				"canonical": "Behavior " + id,
+			},
+		},
+		Metadata: map[string]interface{}{"confidence": 0.8},
+	}
+	if when != nil {
+		node.Content["when"] = when
+	}
+	if _, err := s.AddNode(context.Background(), node); err != nil {
+		t.Fatalf("AddNode(%s) failed: %v", id, err)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+	addBehavior(t, s, "always", "directive", nil)
+	addBehavior(t, s, "go-only", "constraint", map[string]interface{}{"language": "go"})
+	addBehavior(t, s, "py-only", "constraint", map[string]interface{}{"language": "python"})
+	addBehavior(t, s, "testing-only", "procedure", map[string]interface{}{"task": "testing"})
+	if err := s.AddEdge(ctx, store.Edge{Source: "go-only", Target: "always", Kind: store.EdgeKindRequires, Weight: 1, CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("AddEdge failed: %v", err)
+	}
+
+	target := newMemTarget()
+	now := time.Date(2026, 3, 1, 12, 30, 0, 250*int(time.Millisecond), time.UTC)
+	manifest, err := Publish(ctx, s, target, Options{FloopVersion: "v1.2.3", Now: now})
+	if err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	if manifest.SnapshotID != "20260301T123000.250Z" {
+		t.Errorf("SnapshotID = %q, want 20260301T123000.250Z", manifest.SnapshotID)
+	}
+	if manifest.NodeCount != 4 || manifest.EdgeCount != 1 {
+		t.Errorf("counts = %d nodes, %d edges, want 4, 1", manifest.NodeCount, manifest.EdgeCount)
+	}
+
+	// latest.json must be written last, after the manifest
+	if got := target.order[len(target.order)-1]; got != LatestFile {
+		t.Errorf("last write = %q, want %q", got, LatestFile)
+	}
+	if got := target.order[len(target.order)-2]; !strings.HasSuffix(got, ManifestFile) {
+		t.Errorf("second-to-last write = %q, want manifest", got)
+	}
+
+	var latest Latest
+	if err := json.Unmarshal(target.files[LatestFile], &latest); err != nil {
+		t.Fatalf("failed to parse latest.json: %v", err)
+	}
+	if latest.SnapshotID != manifest.SnapshotID {
+		t.Errorf("latest.SnapshotID = %q, want %q", latest.SnapshotID, manifest.SnapshotID)
+	}
+
+	// Checksums in the manifest match the written files
+	base := SnapshotsDir + "/" + manifest.SnapshotID + "/"
+	for _, f := range manifest.Files {
+		data, ok := target.files[base+f.Path]
+		if !ok {
+			t.Errorf("manifest lists %s but it was not written", f.Path)
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != f.SHA256 {
+			t.Errorf("checksum mismatch for %s", f.Path)
+		}
+	}
+
+	prompts := make(map[string]ManifestPrompt)
+	for _, p := range manifest.Prompts {
+		prompts[p.Name] = p
+	}
+	for _, name := range []string{"default", "language-go", "language-python", "task-testing"} {
+		if _, ok := prompts[name]; !ok {
+			t.Errorf("missing prompt for context %q", name)
+		}
+	}
+	// Absent context keys are neutral, so only py-only is contradicted
+	if got := prompts["language-go"].BehaviorCount; got != 3 {
+		t.Errorf("language-go behavior count = %d, want 3", got)
+	}
+	goPrompt := string(target.files[base+prompts["language-go"].Path])
+	if !strings.Contains(goPrompt, "Behavior go-only") {
+		t.Errorf("language-go prompt missing go-only behavior:\n%s", goPrompt)
+	}
+	if strings.Contains(goPrompt, "Behavior py-only") {
+		t.Errorf("language-go prompt should not contain py-only behavior:\n%s", goPrompt)
+	}
+}
+
+func TestPublish_ExistingSnapshot(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+	addBehavior(t, s, "always", "directive", nil)
+
+	target := newMemTarget()
+	now := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	first, err := Publish(ctx, s, target, Options{Now: now})
+	if err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	manifestPath := SnapshotsDir + "/" + first.SnapshotID + "/" + ManifestFile
+	written := string(target.files[manifestPath])
+
+	addBehavior(t, s, "later", "directive", nil)
+	if _, err := Publish(ctx, s, target, Options{Now: now}); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("second Publish error = %v, want an existing snapshot error", err)
+	}
+	if got := string(target.files[manifestPath]); got != written {
+		t.Errorf("existing manifest was overwritten:\n%s", got)
+	}
+}
+
+func TestCommonContexts(t *testing.T) {
+	behaviors := []models.Behavior{
+		{ID: "a", When: map[string]interface{}{"language": []interface{}{"Go", "python"}}},
+		{ID: "b", When: map[string]interface{}{"task": "code review"}},
+		{ID: "c"},
+	}
+
+	got := CommonContexts(behaviors)
+	want := []PromptContext{
+		{Name: "default"},
+		{Name: "language-go", Language: "Go"},
+		{Name: "language-python", Language: "python"},
+		{Name: "task-code-review", Task: "code review"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("CommonContexts() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("CommonContexts()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// Target is a destination that snapshot files are written to.
+// Names are slash-separated paths relative to the target root.
+type Target interface {
+	Put(ctx context.Context, name string, data []byte) error
+	String() string
+}
+
+// Reader is implemented by targets whose files can be read back. Get
+// returns an error wrapping fs.ErrNotExist for a missing file.
+type Reader interface {
+	Get(ctx context.Context, name string) ([]byte, error)
+}
+
+// ParseTarget resolves a --to value into a Target. Values of the form
+// s3://bucket/prefix select S3; anything else is a local directory.
+func ParseTarget(to string) (Target, error) {
+	if to == "" {
+		return nil, fmt.Errorf("publish target is required")
+	}
+
+	if rest, ok := strings.CutPrefix(to, "s3://"); ok {
+		bucket, prefix, _ := strings.Cut(rest, "/")
+		if bucket == "" {
+			return nil, fmt.Errorf("invalid S3 target %q: missing bucket", to)
+		}
+		return &S3Target{Bucket: bucket, Prefix: strings.Trim(prefix, "/")}, nil
+	}
+
+	if strings.Contains(to, "://") {
+		return nil, fmt.Errorf("unsupported publish target %q (use a directory or s3://bucket/prefix)", to)
+	}
+
+	abs, err := filepath.Abs(to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve target directory: %w", err)
+	}
+	return &DirTarget{Root: abs}, nil
+}
+
+// DirTarget writes snapshot files under a local directory.
+type DirTarget struct {
+	Root string
+}
+
+// Put writes data to Root/name via a temp file and rename, so readers never
+// observe a partially written file.
+func (t *DirTarget) Put(_ context.Context, name string, data []byte) error {
+	dest := filepath.Join(t.Root, filepath.FromSlash(name))
+	if rel, err := filepath.Rel(t.Root, dest); err != nil || strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("path %q escapes target directory", name)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), filepath.Base(dest)+".tmp.*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	// Published snapshots are meant to be read by other tools
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to set permissions: %w", err)
+	}
+	if err := os.Rename(tmpPath, dest); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return nil
+}
+
+// Get reads Root/name.
+func (t *DirTarget) Get(_ context.Context, name string) ([]byte, error) {
+	src := filepath.Join(t.Root, filepath.FromSlash(name))
+	if rel, err := filepath.Rel(t.Root, src); err != nil || strings.HasPrefix(rel, "..") {
+		return nil, fmt.Errorf("path %q escapes target directory", name)
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	return data, nil
+}
+
+func (t *DirTarget) String() string {
+	return t.Root
+}
+
+// S3Target writes snapshot files to an S3 bucket under an optional prefix.
+// Credentials and region come from the standard AWS environment and config
+// files.
+type S3Target struct {
+	Bucket string
+	Prefix string
+
+	client *s3.Client
+}
+
+// Put uploads data to s3://Bucket/Prefix/name.
+func (t *S3Target) Put(ctx context.Context, name string, data []byte) error {
+	if err := t.connect(ctx); err != nil {
+		return err
+	}
+
+	key := t.key(name)
+
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(t.Bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(data),
+	}
+	if ct := contentType(name); ct != "" {
+		input.ContentType = aws.String(ct)
+	}
+
+	if _, err := t.client.PutObject(ctx, input); err != nil {
+		return fmt.Errorf("failed to upload s3://%s/%s: %w", t.Bucket, key, err)
+	}
+	return nil
+}
+
+// Get downloads s3://Bucket/Prefix/name.
+func (t *S3Target) Get(ctx context.Context, name string) ([]byte, error) {
+	if err := t.connect(ctx); err != nil {
+		return nil, err
+	}
+
+	key := t.key(name)
+	out, err := t.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(t.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var missing *types.NoSuchKey
+		if errors.As(err, &missing) {
+			return nil, fmt.Errorf("s3://%s/%s: %w", t.Bucket, key, fs.ErrNotExist)
+		}
+		return nil, fmt.Errorf("failed to download s3://%s/%s: %w", t.Bucket, key, err)
+	}
+	defer out.Body.Close()
+
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download s3://%s/%s: %w", t.Bucket, key, err)
+	}
+	return data, nil
+}
+
+// connect creates the S3 client on first use.
+func (t *S3Target) connect(ctx context.Context) error {
+	if t.client != nil {
+		return nil
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	t.client = s3.NewFromConfig(cfg)
+	return nil
+}
+
+// key returns the object key for name.
+func (t *S3Target) key(name string) string {
+	if t.Prefix == "" {
+		return name
+	}
+	return path.Join(t.Prefix, name)
+}
+
+func (t *S3Target) String() string {
+	if t.Prefix == "" {
+		return "s3://" + t.Bucket
+	}
+	return "s3://" + t.Bucket + "/" + t.Prefix
+}
+
+// contentType returns the MIME type for a snapshot file.
+func contentType(name string) string {
+	switch path.Ext(name) {
+	case ".jsonl":
+		return "application/x-ndjson"
+	case ".md":
+		return "text/markdown; charset=utf-8"
+	default:
+		return mime.TypeByExtension(path.Ext(name))
+	}
+}
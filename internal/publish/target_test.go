@@ -0,0 +1,86 @@
+package publish
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		to      string
+		wantS3  *S3Target
+		wantDir bool
+		wantErr bool
+	}{
+		{"empty", "", nil, false, true},
+		{"directory", "out/site", nil, true, false},
+		{"s3 bucket", "s3://bucket", &S3Target{Bucket: "bucket"}, false, false},
+		{"s3 with prefix", "s3://bucket/floop/prod/", &S3Target{Bucket: "bucket", Prefix: "floop/prod"}, false, false},
+		{"s3 missing bucket", "s3:///prefix", nil, false, true},
+		{"unsupported scheme", "gs://bucket", nil, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTarget(tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTarget(%q) err = %v, wantErr %v", tt.to, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantS3 != nil {
+				s3t, ok := got.(*S3Target)
+				if !ok {
+					t.Fatalf("ParseTarget(%q) = %T, want *S3Target", tt.to, got)
+				}
+				if s3t.Bucket != tt.wantS3.Bucket || s3t.Prefix != tt.wantS3.Prefix {
+					t.Errorf("ParseTarget(%q) = %s/%s, want %s/%s", tt.to, s3t.Bucket, s3t.Prefix, tt.wantS3.Bucket, tt.wantS3.Prefix)
+				}
+			}
+			if tt.wantDir {
+				dt, ok := got.(*DirTarget)
+				if !ok {
+					t.Fatalf("ParseTarget(%q) = %T, want *DirTarget", tt.to, got)
+				}
+				if !filepath.IsAbs(dt.Root) {
+					t.Errorf("DirTarget.Root = %q, want absolute path", dt.Root)
+				}
+			}
+		})
+	}
+}
+
+func TestDirTarget_Put(t *testing.T) {
+	root := t.TempDir()
+	target := &DirTarget{Root: root}
+	ctx := context.Background()
+
+	if err := target.Put(ctx, "snapshots/1/nodes.jsonl", []byte("{}\n")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "snapshots", "1", "nodes.jsonl"))
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if string(data) != "{}\n" {
+		t.Errorf("content = %q, want %q", data, "{}\n")
+	}
+
+	if err := target.Put(ctx, "../escape.txt", []byte("x")); err == nil {
+		t.Error("expected error for path escaping the target directory")
+	}
+
+	got, err := target.Get(ctx, "snapshots/1/nodes.jsonl")
+	if err != nil || string(got) != "{}\n" {
+		t.Errorf("Get() = %q, %v, want what was put", got, err)
+	}
+	if _, err := target.Get(ctx, "snapshots/2/nodes.jsonl"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Get(missing) error = %v, want fs.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,164 @@
+// Package quarantine keeps behaviors that look like prompt injection out of
+// agent prompts. Behaviors are injected verbatim, so a malicious correction,
+// pack, or import could smuggle instructions to the agent. A quarantined
+// behavior gets kind quarantined-behavior, is never activated, and waits
+// for a human to release it with 'floop restore' or forget it.
+package quarantine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/sanitize"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Actor is recorded as quarantined_by on quarantined behaviors.
+const Actor = "floop-quarantine"
+
+// Entry describes a quarantined (or, in a dry run, suspicious) behavior.
+type Entry struct {
+	BehaviorID    string                      `json:"behavior_id"`
+	Name          string                      `json:"name"`
+	Canonical     string                      `json:"canonical"`
+	Origin        store.Origin                `json:"origin,omitempty"`
+	Reason        string                      `json:"reason"`
+	Findings      []sanitize.InjectionFinding `json:"findings,omitempty"`
+	QuarantinedAt time.Time                   `json:"quarantined_at,omitempty"`
+}
+
+// Analyze checks every piece of b that is injected into prompts: name,
+// canonical and summary content, and structured values.
+func Analyze(b *models.Behavior) sanitize.InjectionReport {
+	parts := []string{b.Name, b.Content.Canonical, b.Content.Summary}
+	keys := make([]string, 0, len(b.Content.Structured))
+	for k := range b.Content.Structured {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if s, ok := b.Content.Structured[k].(string); ok {
+			parts = append(parts, s)
+		}
+	}
+
+	var report sanitize.InjectionReport
+	for _, p := range parts {
+		report.Findings = append(report.Findings, sanitize.AnalyzeInjection(p).Findings...)
+	}
+	return report
+}
+
+// Reason summarizes report for the quarantine_reason metadata field.
+func Reason(report sanitize.InjectionReport) string {
+	parts := make([]string, 0, len(report.Findings))
+	for _, f := range report.Findings {
+		parts = append(parts, fmt.Sprintf("%s: %q", f.Rule, f.Excerpt))
+	}
+	return "possible prompt injection (" + strings.Join(parts, "; ") + ")"
+}
+
+// Quarantine marks node as quarantined for the findings in report. The
+// caller writes the node back to the store.
+func Quarantine(node *store.Node, report sanitize.InjectionReport, now time.Time) {
+	if node.Metadata == nil {
+		node.Metadata = make(map[string]interface{})
+	}
+	node.Metadata["original_kind"] = string(node.Kind)
+	node.Metadata["quarantined_at"] = now.UTC().Format(time.RFC3339)
+	node.Metadata["quarantined_by"] = Actor
+	node.Metadata["quarantine_reason"] = Reason(report)
+	node.Metadata["quarantine_rules"] = strings.Join(report.Rules(), ",")
+	node.Kind = store.NodeKindQuarantined
+}
+
+// ClearMetadata removes the quarantine metadata from node, for callers
+// releasing it.
+func ClearMetadata(node *store.Node) {
+	delete(node.Metadata, "quarantined_at")
+	delete(node.Metadata, "quarantined_by")
+	delete(node.Metadata, "quarantine_reason")
+	delete(node.Metadata, "quarantine_rules")
+}
+
+// Quarantined returns the quarantined behaviors in graphStore, oldest first.
+func Quarantined(ctx context.Context, graphStore store.GraphStore) ([]Entry, error) {
+	nodes, err := graphStore.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindQuarantined)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query quarantined behaviors: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(nodes))
+	for _, node := range nodes {
+		b := models.NodeToBehavior(node)
+		entry := Entry{
+			BehaviorID: node.ID,
+			Name:       b.Name,
+			Canonical:  b.Content.Canonical,
+			Origin:     node.Origin,
+			Findings:   Analyze(&b).Findings,
+		}
+		entry.Reason, _ = node.Metadata["quarantine_reason"].(string)
+		if s, ok := node.Metadata["quarantined_at"].(string); ok {
+			entry.QuarantinedAt, _ = time.Parse(time.RFC3339, s)
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].QuarantinedAt.Equal(entries[j].QuarantinedAt) {
+			return entries[i].QuarantinedAt.Before(entries[j].QuarantinedAt)
+		}
+		return entries[i].BehaviorID < entries[j].BehaviorID
+	})
+	return entries, nil
+}
+
+// Scan analyzes every active behavior in graphStore and quarantines the
+// suspicious ones, catching content that arrived without going through the
+// learning loop (packs, imports, older versions). With dryRun set nothing is
+// changed. Returns an entry per suspicious behavior.
+func Scan(ctx context.Context, graphStore store.GraphStore, now time.Time, dryRun bool) ([]Entry, error) {
+	nodes, err := graphStore.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query behaviors: %w", err)
+	}
+
+	var entries []Entry
+	for _, node := range nodes {
+		b := models.NodeToBehavior(node)
+		report := Analyze(&b)
+		if !report.Suspicious() {
+			continue
+		}
+		entry := Entry{
+			BehaviorID: node.ID,
+			Name:       b.Name,
+			Canonical:  b.Content.Canonical,
+			Origin:     node.Origin,
+			Reason:     Reason(report),
+			Findings:   report.Findings,
+		}
+		if dryRun {
+			entries = append(entries, entry)
+			continue
+		}
+
+		Quarantine(&node, report, now)
+		entry.QuarantinedAt = now
+		if err := graphStore.UpdateNode(ctx, node); err != nil {
+			return entries, fmt.Errorf("failed to quarantine behavior %s: %w", node.ID, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if !dryRun && len(entries) > 0 {
+		if err := graphStore.Sync(ctx); err != nil {
+			return entries, fmt.Errorf("failed to sync store: %w", err)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,179 @@
+// Package quota enforces storage guardrails: limits on behaviors per scope,
+// canonical content length, and edges per node. When a limit is reached,
+// learning proposes consolidation candidates instead of growing the store.
+package quota
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// WarnRatio is the fraction of a limit at which usage is reported as
+// approaching the limit.
+const WarnRatio = 0.9
+
+// Limit names, as used in config and reports.
+const (
+	LimitBehaviors       = "max_behaviors_per_scope"
+	LimitCanonicalLength = "max_canonical_length"
+	LimitEdgesPerNode    = "max_edges_per_node"
+)
+
+// Limits caps store growth. A zero value disables that limit.
+type Limits struct {
+	MaxBehaviorsPerScope int
+	MaxCanonicalLength   int
+	MaxEdgesPerNode      int
+}
+
+// Status classifies usage against a limit.
+type Status string
+
+const (
+	StatusOK       Status = "ok"
+	StatusWarning  Status = "warning"
+	StatusExceeded Status = "exceeded"
+)
+
+// Usage reports how much of a limit is in use.
+type Usage struct {
+	Limit  string `json:"limit"`
+	Scope  string `json:"scope,omitempty"`
+	Used   int    `json:"used"`
+	Max    int    `json:"max"`
+	Status Status `json:"status"`
+}
+
+// NewUsage builds a Usage and classifies it. Reaching the limit counts as
+// exceeded, since the next addition would go over it.
+func NewUsage(limit, scope string, used, max int) Usage {
+	u := Usage{Limit: limit, Scope: scope, Used: used, Max: max, Status: StatusOK}
+	switch {
+	case max <= 0:
+	case used >= max:
+		u.Status = StatusExceeded
+	case float64(used) >= WarnRatio*float64(max):
+		u.Status = StatusWarning
+	}
+	return u
+}
+
+// String formats the usage for display.
+func (u Usage) String() string {
+	name := u.Limit
+	if u.Scope != "" {
+		name = fmt.Sprintf("%s (%s)", u.Limit, u.Scope)
+	}
+	return fmt.Sprintf("%s: %d/%d", name, u.Used, u.Max)
+}
+
+// CountBehaviors returns the number of active behaviors in graphStore.
+func CountBehaviors(ctx context.Context, graphStore store.GraphStore) (int, error) {
+	nodes, err := graphStore.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count behaviors: %w", err)
+	}
+	return len(nodes), nil
+}
+
+// EdgeCount returns the number of edges touching nodeID.
+func EdgeCount(ctx context.Context, graphStore store.GraphStore, nodeID string) (int, error) {
+	edges, err := graphStore.GetEdges(ctx, nodeID, store.DirectionBoth, "")
+	if err != nil {
+		return 0, fmt.Errorf("failed to count edges for %s: %w", nodeID, err)
+	}
+	return len(edges), nil
+}
+
+// Report returns usage of every enabled limit for the given stores, keyed by
+// scope name. The edges-per-node entry reports the busiest node.
+func Report(ctx context.Context, stores map[string]store.GraphStore, limits Limits) ([]Usage, error) {
+	scopes := make([]string, 0, len(stores))
+	for scope := range stores {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+
+	var usage []Usage
+	for _, scope := range scopes {
+		gs := stores[scope]
+		nodes, err := gs.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s behaviors: %w", scope, err)
+		}
+		if limits.MaxBehaviorsPerScope > 0 {
+			usage = append(usage, NewUsage(LimitBehaviors, scope, len(nodes), limits.MaxBehaviorsPerScope))
+		}
+		if limits.MaxCanonicalLength > 0 {
+			longest := 0
+			for _, n := range nodes {
+				b := models.NodeToBehavior(n)
+				longest = max(longest, len(b.Content.Canonical))
+			}
+			usage = append(usage, NewUsage(LimitCanonicalLength, scope, longest, limits.MaxCanonicalLength))
+		}
+		if limits.MaxEdgesPerNode > 0 {
+			busiest := 0
+			for _, n := range nodes {
+				count, err := EdgeCount(ctx, gs, n.ID)
+				if err != nil {
+					return nil, err
+				}
+				busiest = max(busiest, count)
+			}
+			usage = append(usage, NewUsage(LimitEdgesPerNode, scope, busiest, limits.MaxEdgesPerNode))
+		}
+	}
+	return usage, nil
+}
+
+// Candidate is a consolidation proposal that would free room in a store.
+type Candidate struct {
+	// Action is "merge" (fold the new behavior into BehaviorID) or "forget".
+	Action     string  `json:"action"`
+	BehaviorID string  `json:"behavior_id"`
+	Name       string  `json:"name,omitempty"`
+	Score      float64 `json:"score,omitempty"`
+	Reason     string  `json:"reason"`
+}
+
+// ForgetCandidates returns up to n behaviors that are the weakest
+// candidates for forgetting: never or rarely activated, low confidence, and
+// oldest first. Constraints are never proposed.
+func ForgetCandidates(behaviors []models.Behavior, n int) []Candidate {
+	pool := make([]models.Behavior, 0, len(behaviors))
+	for _, b := range behaviors {
+		if b.Kind != models.BehaviorKindConstraint {
+			pool = append(pool, b)
+		}
+	}
+	sort.SliceStable(pool, func(i, j int) bool {
+		a, b := pool[i], pool[j]
+		if a.Stats.TimesActivated != b.Stats.TimesActivated {
+			return a.Stats.TimesActivated < b.Stats.TimesActivated
+		}
+		if a.Confidence != b.Confidence {
+			return a.Confidence < b.Confidence
+		}
+		return a.Provenance.CreatedAt.Before(b.Provenance.CreatedAt)
+	})
+	if len(pool) > n {
+		pool = pool[:n]
+	}
+
+	candidates := make([]Candidate, 0, len(pool))
+	for _, b := range pool {
+		candidates = append(candidates, Candidate{
+			Action:     "forget",
+			BehaviorID: b.ID,
+			Name:       b.Name,
+			Score:      b.Confidence,
+			Reason:     fmt.Sprintf("activated %d times, confidence %.2f", b.Stats.TimesActivated, b.Confidence),
+		})
+	}
+	return candidates
+}
@@ -0,0 +1,100 @@
+package quota
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestNewUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		used int
+		max  int
+		want Status
+	}{
+		{"well below", 10, 100, StatusOK},
+		{"approaching", 90, 100, StatusWarning},
+		{"at limit", 100, 100, StatusExceeded},
+		{"over limit", 120, 100, StatusExceeded},
+		{"disabled", 1000, 0, StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewUsage(LimitBehaviors, "local", tt.used, tt.max).Status; got != tt.want {
+				t.Errorf("NewUsage(%d, %d).Status = %s, want %s", tt.used, tt.max, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	ctx := context.Background()
+	local := store.NewInMemoryGraphStore()
+	global := store.NewInMemoryGraphStore()
+
+	for _, id := range []string{"a", "b", "c"} {
+		n := models.BehaviorToNode(&models.Behavior{ID: id, Name: id, Kind: models.BehaviorKindDirective,
+			Content: models.BehaviorContent{Canonical: "use " + id}})
+		if _, err := local.AddNode(ctx, n); err != nil {
+			t.Fatalf("AddNode failed: %v", err)
+		}
+	}
+	for _, target := range []string{"b", "c"} {
+		if err := local.AddEdge(ctx, store.Edge{Source: "a", Target: target, Kind: store.EdgeKindSimilarTo, Weight: 1, CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("AddEdge failed: %v", err)
+		}
+	}
+
+	usage, err := Report(ctx, map[string]store.GraphStore{"local": local, "global": global},
+		Limits{MaxBehaviorsPerScope: 3, MaxCanonicalLength: 100, MaxEdgesPerNode: 20})
+	if err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	if len(usage) != 6 {
+		t.Fatalf("got %d usage entries, want 6: %+v", len(usage), usage)
+	}
+
+	got := make(map[string]Usage)
+	for _, u := range usage {
+		got[u.Scope+"/"+u.Limit] = u
+	}
+	if u := got["local/"+LimitBehaviors]; u.Used != 3 || u.Status != StatusExceeded {
+		t.Errorf("local behaviors = %+v, want 3 exceeded", u)
+	}
+	if u := got["global/"+LimitBehaviors]; u.Used != 0 || u.Status != StatusOK {
+		t.Errorf("global behaviors = %+v, want 0 ok", u)
+	}
+	if u := got["local/"+LimitEdgesPerNode]; u.Used != 2 {
+		t.Errorf("local edges per node = %+v, want busiest node at 2", u)
+	}
+	if u := got["local/"+LimitCanonicalLength]; u.Used != len("use a") {
+		t.Errorf("local canonical length = %+v, want %d", u, len("use a"))
+	}
+}
+
+func TestForgetCandidates(t *testing.T) {
+	now := time.Now()
+	behaviors := []models.Behavior{
+		{ID: "used", Confidence: 0.9, Stats: models.BehaviorStats{TimesActivated: 12}},
+		{ID: "weak", Confidence: 0.4},
+		{ID: "old", Confidence: 0.6, Provenance: models.Provenance{CreatedAt: now.Add(-48 * time.Hour)}},
+		{ID: "new", Confidence: 0.6, Provenance: models.Provenance{CreatedAt: now}},
+		{ID: "rule", Kind: models.BehaviorKindConstraint},
+	}
+
+	got := ForgetCandidates(behaviors, 3)
+	want := []string{"weak", "old", "new"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d candidates, want %d: %+v", len(got), len(want), got)
+	}
+	for i, c := range got {
+		if c.BehaviorID != want[i] || c.Action != "forget" {
+			t.Errorf("candidate %d = %s %s, want forget %s", i, c.Action, c.BehaviorID, want[i])
+		}
+	}
+}
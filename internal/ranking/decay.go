@@ -31,6 +31,16 @@ func ExponentialDecay(t time.Time, halfLife time.Duration) float64 {
 // At 0.01, edges lose ~1% of effective weight per hour, ~21% per day.
 const DefaultDecayRate = 0.01
 
+// HalfLifeDecayRate returns the decay rate under which EdgeDecay halves an
+// edge's weight every halfLife. A halfLife of zero or less returns
+// DefaultDecayRate, a half-life of about 69 hours.
+func HalfLifeDecayRate(halfLife time.Duration) float64 {
+	if halfLife <= 0 {
+		return DefaultDecayRate
+	}
+	return math.Ln2 / halfLife.Hours()
+}
+
 // EdgeDecay calculates the effective weight of an edge based on temporal decay.
 // Returns weight * e^(-rho * elapsed_hours) where rho is the decay rate.
 // A rho of 0.01 means ~1% decay per hour, ~21% decay per day.
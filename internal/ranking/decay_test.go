@@ -106,3 +106,13 @@ func TestDefaultDecayRate(t *testing.T) {
 		t.Errorf("1-day decay at DefaultDecayRate = %v, want ~0.786", oneDayDecay)
 	}
 }
+
+func TestHalfLifeDecayRate(t *testing.T) {
+	rate := HalfLifeDecayRate(48 * time.Hour)
+	if got := EdgeDecay(1.0, time.Now().Add(-48*time.Hour), rate); math.Abs(got-0.5) > 0.001 {
+		t.Errorf("EdgeDecay after one 48h half-life = %v, want 0.5", got)
+	}
+	if got := HalfLifeDecayRate(0); got != DefaultDecayRate {
+		t.Errorf("HalfLifeDecayRate(0) = %v, want DefaultDecayRate", got)
+	}
+}
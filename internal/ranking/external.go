@@ -0,0 +1,207 @@
+package ranking
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// ExternalScorerConfig configures an ExternalScorer. Exactly one of Command
+// and URL is used; Command wins if both are set.
+type ExternalScorerConfig struct {
+	// Command is split on whitespace and run without a shell. It reads an
+	// ExternalRequest on stdin and writes an ExternalResponse to stdout.
+	Command string
+
+	// URL receives an ExternalRequest as a JSON POST and replies with an
+	// ExternalResponse.
+	URL string
+
+	// Timeout bounds each call. Zero uses constants.DefaultExternalScorerTimeoutMs.
+	Timeout time.Duration
+}
+
+// ExternalCandidate is a behavior offered to an external scorer, with the
+// score built-in ranking gave it.
+type ExternalCandidate struct {
+	BehaviorID string   `json:"behavior_id"`
+	Name       string   `json:"name"`
+	Kind       string   `json:"kind"`
+	Canonical  string   `json:"canonical"`
+	Tags       []string `json:"tags,omitempty"`
+	Priority   int      `json:"priority"`
+	Confidence float64  `json:"confidence"`
+	Score      float64  `json:"score"`
+}
+
+// ExternalRequest is sent to an external scorer.
+type ExternalRequest struct {
+	Context    *models.ContextSnapshot `json:"context,omitempty"`
+	Candidates []ExternalCandidate     `json:"candidates"`
+}
+
+// ExternalResponse is returned by an external scorer. Adjustments maps
+// behavior IDs to amounts added to their built-in scores; behaviors it
+// leaves out keep their scores.
+type ExternalResponse struct {
+	Adjustments map[string]float64 `json:"adjustments"`
+}
+
+// ExternalScorer asks an external command or HTTP endpoint for score
+// adjustments to merge with built-in ranking (ACT-R base-level activation,
+// spreading activation, and PageRank).
+type ExternalScorer struct {
+	command []string
+	url     string
+	timeout time.Duration
+	client  *http.Client
+}
+
+// NewExternalScorer returns a scorer for cfg, or nil when neither a command
+// nor a URL is configured.
+func NewExternalScorer(cfg ExternalScorerConfig) *ExternalScorer {
+	command := strings.Fields(cfg.Command)
+	if len(command) == 0 && cfg.URL == "" {
+		return nil
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = constants.DefaultExternalScorerTimeoutMs * time.Millisecond
+	}
+	return &ExternalScorer{
+		command: command,
+		url:     cfg.URL,
+		timeout: timeout,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Adjust sends req to the external scorer and returns its adjustments for
+// the candidates in req. Each adjustment is clamped to
+// ±constants.MaxExternalScoreAdjustment; unknown IDs and non-finite values
+// are dropped. Any failure returns an error, and callers should keep their
+// built-in scores.
+func (e *ExternalScorer) Adjust(ctx context.Context, req ExternalRequest) (map[string]float64, error) {
+	if len(req.Candidates) == 0 {
+		return nil, nil
+	}
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("encoding scorer request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	var body []byte
+	if len(e.command) > 0 {
+		body, err = e.runCommand(ctx, payload)
+	} else {
+		body, err = e.post(ctx, payload)
+	}
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("external scorer timed out after %v", e.timeout)
+		}
+		return nil, err
+	}
+
+	var resp ExternalResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("decoding scorer response: %w", err)
+	}
+
+	known := make(map[string]bool, len(req.Candidates))
+	for _, c := range req.Candidates {
+		known[c.BehaviorID] = true
+	}
+	adjustments := make(map[string]float64, len(resp.Adjustments))
+	for id, adj := range resp.Adjustments {
+		if !known[id] || math.IsNaN(adj) || math.IsInf(adj, 0) {
+			continue
+		}
+		adjustments[id] = max(-constants.MaxExternalScoreAdjustment, min(constants.MaxExternalScoreAdjustment, adj))
+	}
+	return adjustments, nil
+}
+
+// runCommand runs the scorer command with payload on stdin.
+func (e *ExternalScorer) runCommand(ctx context.Context, payload []byte) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, e.command[0], e.command[1:]...)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.WaitDelay = 100 * time.Millisecond
+	var stdout, stderr limitedBuffer
+	stdout.limit = constants.MaxExternalScorerResponseBytes
+	stderr.limit = 4096
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("external scorer command failed: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("external scorer command failed: %w", err)
+	}
+	if stdout.truncated {
+		return nil, fmt.Errorf("external scorer response exceeds %d bytes", constants.MaxExternalScorerResponseBytes)
+	}
+	return stdout.Bytes(), nil
+}
+
+// post sends payload to the scorer URL.
+func (e *ExternalScorer) post(ctx context.Context, payload []byte) ([]byte, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("building scorer request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("external scorer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("external scorer returned %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, constants.MaxExternalScorerResponseBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading scorer response: %w", err)
+	}
+	if len(body) > constants.MaxExternalScorerResponseBytes {
+		return nil, fmt.Errorf("external scorer response exceeds %d bytes", constants.MaxExternalScorerResponseBytes)
+	}
+	return body, nil
+}
+
+// ApplyAdjustment adds an external adjustment to a built-in score, keeping
+// the result in [0, 1].
+func ApplyAdjustment(score, adjustment float64) float64 {
+	return max(0, min(1, score+adjustment))
+}
+
+// limitedBuffer is a bytes.Buffer that keeps at most limit bytes and
+// discards the rest, so a runaway scorer cannot exhaust memory.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); len(p) > room {
+		b.truncated = true
+		b.Buffer.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
@@ -0,0 +1,149 @@
+package ranking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestExternalScorerHelperProcess is run as the external scorer command by
+// the tests below. It does nothing when run as a normal test.
+func TestExternalScorerHelperProcess(t *testing.T) {
+	mode := os.Getenv("FLOOP_TEST_SCORER_MODE")
+	if mode == "" {
+		return
+	}
+	var req ExternalRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintln(os.Stderr, "bad request:", err)
+		os.Exit(2)
+	}
+	switch mode {
+	case "boost-first":
+		json.NewEncoder(os.Stdout).Encode(ExternalResponse{Adjustments: map[string]float64{
+			req.Candidates[0].BehaviorID: 3, // clamped
+			"unknown":                    0.2,
+		}})
+	case "slow":
+		time.Sleep(5 * time.Second)
+	case "fail":
+		fmt.Fprintln(os.Stderr, "model not loaded")
+		os.Exit(1)
+	case "garbage":
+		fmt.Print("not json")
+	}
+	os.Exit(0)
+}
+
+func helperCommand(t *testing.T, mode string) string {
+	t.Helper()
+	t.Setenv("FLOOP_TEST_SCORER_MODE", mode)
+	return os.Args[0] + " -test.run=^TestExternalScorerHelperProcess$"
+}
+
+func testRequest() ExternalRequest {
+	return ExternalRequest{Candidates: []ExternalCandidate{
+		{BehaviorID: "a", Name: "a", Score: 0.4},
+		{BehaviorID: "b", Name: "b", Score: 0.6},
+	}}
+}
+
+func TestNewExternalScorer_Unconfigured(t *testing.T) {
+	if s := NewExternalScorer(ExternalScorerConfig{Command: "  "}); s != nil {
+		t.Error("expected nil scorer without a command or URL")
+	}
+}
+
+func TestExternalScorer_Command(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		timeout time.Duration
+		wantErr string
+	}{
+		{"adjusts", "boost-first", 0, ""},
+		{"times out", "slow", 200 * time.Millisecond, "timed out"},
+		{"exit status", "fail", 0, "model not loaded"},
+		{"invalid response", "garbage", 0, "decoding"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeout := tt.timeout
+			if timeout == 0 {
+				timeout = 5 * time.Second // allow for test binary startup
+			}
+			scorer := NewExternalScorer(ExternalScorerConfig{Command: helperCommand(t, tt.mode), Timeout: timeout})
+
+			start := time.Now()
+			adj, err := scorer.Adjust(context.Background(), testRequest())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Adjust() error = %v, want %q", err, tt.wantErr)
+				}
+				if elapsed := time.Since(start); elapsed > 2*time.Second {
+					t.Errorf("Adjust() took %v, want it bounded by the timeout", elapsed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Adjust() error = %v", err)
+			}
+			if len(adj) != 1 || adj["a"] != 0.5 {
+				t.Errorf("Adjust() = %v, want a clamped to 0.5 and unknown IDs dropped", adj)
+			}
+		})
+	}
+}
+
+func TestExternalScorer_URL(t *testing.T) {
+	var got ExternalRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		if got.Candidates[0].BehaviorID == "down" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"adjustments": {"b": -0.25}}`))
+	}))
+	defer ts.Close()
+
+	scorer := NewExternalScorer(ExternalScorerConfig{URL: ts.URL, Timeout: time.Second})
+	adj, err := scorer.Adjust(context.Background(), testRequest())
+	if err != nil {
+		t.Fatalf("Adjust() error = %v", err)
+	}
+	if adj["b"] != -0.25 || len(got.Candidates) != 2 || got.Candidates[1].Score != 0.6 {
+		t.Errorf("Adjust() = %v with request %+v", adj, got)
+	}
+
+	req := testRequest()
+	req.Candidates[0].BehaviorID = "down"
+	if _, err := scorer.Adjust(context.Background(), req); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Adjust() error = %v, want the HTTP status", err)
+	}
+}
+
+func TestApplyAdjustment(t *testing.T) {
+	tests := []struct {
+		score, adj, want float64
+	}{
+		{0.5, 0.2, 0.7},
+		{0.9, 0.5, 1},
+		{0.1, -0.5, 0},
+	}
+	for _, tt := range tests {
+		if got := ApplyAdjustment(tt.score, tt.adj); got != tt.want {
+			t.Errorf("ApplyAdjustment(%v, %v) = %v, want %v", tt.score, tt.adj, got, tt.want)
+		}
+	}
+}
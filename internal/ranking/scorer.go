@@ -36,6 +36,11 @@ type ScorerConfig struct {
 
 	// KindBoosts are score multipliers for behavior kinds
 	KindBoosts map[models.BehaviorKind]float64
+
+	// TemporalWeight scales the score of behaviors with temporal tags by up
+	// to this fraction (see TemporalScore and ApplyTemporal). Zero disables
+	// temporal ranking.
+	TemporalWeight float64
 }
 
 // DefaultScorerConfig returns the default scoring configuration.
@@ -97,6 +102,7 @@ type ScoredBehavior struct {
 	FeedbackScore  float64
 	PriorityScore  float64
 	KindBoost      float64
+	TemporalScore  float64
 
 	// Deprecated: kept for backward compatibility with tests that reference old fields.
 	// These map to new signals: UsageScore→BaseLevelScore, RecencyScore→0, ConfidenceScore→FeedbackScore.
@@ -134,6 +140,15 @@ func (s *RelevanceScorer) Score(behavior *models.Behavior, ctx *models.ContextSn
 	// Apply kind boost
 	scored.Score = baseScore * scored.KindBoost
 
+	// Apply time-of-day and workload fit
+	scored.TemporalScore = constants.NeutralScore
+	if s.config.TemporalWeight > 0 {
+		if temporal, ok := TemporalScore(behavior, ctx); ok {
+			scored.TemporalScore = temporal
+			scored.Score = ApplyTemporal(scored.Score, temporal, s.config.TemporalWeight)
+		}
+	}
+
 	return scored
 }
 
@@ -192,6 +207,8 @@ func (s *RelevanceScorer) predicateMatches(key string, ctx *models.ContextSnapsh
 		return ctx.Environment != ""
 	case "repo", "repository":
 		return ctx.RepoRoot != ""
+	case models.WhenWeekday, models.WhenDate, models.WhenAfter, models.WhenBefore:
+		return !ctx.Timestamp.IsZero()
 	default:
 		if ctx.Custom != nil {
 			_, ok := ctx.Custom[key]
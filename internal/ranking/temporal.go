@@ -0,0 +1,132 @@
+package ranking
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// DayPart is a coarse time of day.
+type DayPart string
+
+const (
+	DayPartMorning   DayPart = "morning"   // 05:00-12:00
+	DayPartAfternoon DayPart = "afternoon" // 12:00-17:00
+	DayPartEvening   DayPart = "evening"   // 17:00-22:00
+	DayPartNight     DayPart = "night"     // 22:00-05:00
+)
+
+// DayPartOf returns the part of the day t falls in, in t's location.
+func DayPartOf(t time.Time) DayPart {
+	switch h := t.Hour(); {
+	case h >= 5 && h < 12:
+		return DayPartMorning
+	case h >= 12 && h < 17:
+		return DayPartAfternoon
+	case h >= 17 && h < 22:
+		return DayPartEvening
+	}
+	return DayPartNight
+}
+
+// Tag namespaces through which behaviors opt into temporal ranking:
+//
+//	time/morning, time/afternoon, time/evening, time/night
+//	time/weekday, time/weekend
+//	workload/busy, workload/quiet
+//
+// Unlike weekday or date when-conditions, these never keep a behavior from
+// activating; they only move it up or down the ranking.
+const (
+	TimeTagPrefix     = "time/"
+	WorkloadTagPrefix = "workload/"
+)
+
+// TemporalScore scores how well the behavior's temporal tags fit the
+// context's time and workload, from 0 (contradicted) to 1 (matched). Each
+// tag group counts once: the time-of-day tags, the weekday/weekend tags, and
+// the workload tags, which score the context's Intensity for busy and its
+// complement for quiet. Groups the context cannot judge (a zero Timestamp,
+// or no Intensity) are skipped. The second result is false, with a neutral
+// score, when no group applies.
+func TemporalScore(b *models.Behavior, ctx *models.ContextSnapshot) (float64, bool) {
+	if b == nil || ctx == nil {
+		return constants.NeutralScore, false
+	}
+
+	var dayParts, days, workloads []string
+	for _, tag := range b.Content.Tags {
+		tag = strings.ToLower(tag)
+		switch {
+		case strings.HasPrefix(tag, TimeTagPrefix):
+			switch v := strings.TrimPrefix(tag, TimeTagPrefix); v {
+			case "weekday", "weekend":
+				days = append(days, v)
+			default:
+				dayParts = append(dayParts, v)
+			}
+		case strings.HasPrefix(tag, WorkloadTagPrefix):
+			workloads = append(workloads, strings.TrimPrefix(tag, WorkloadTagPrefix))
+		}
+	}
+
+	var sum float64
+	var groups int
+	if !ctx.Timestamp.IsZero() {
+		if len(dayParts) > 0 {
+			groups++
+			if slices.Contains(dayParts, string(DayPartOf(ctx.Timestamp))) {
+				sum++
+			}
+		}
+		if len(days) > 0 {
+			groups++
+			day := "weekday"
+			if wd := ctx.Timestamp.Weekday(); wd == time.Saturday || wd == time.Sunday {
+				day = "weekend"
+			}
+			if slices.Contains(days, day) {
+				sum++
+			}
+		}
+	}
+	if ctx.Intensity > 0 && len(workloads) > 0 {
+		intensity := min(1, ctx.Intensity)
+		best := 0.0
+		for _, w := range workloads {
+			switch w {
+			case "busy":
+				best = max(best, intensity)
+			case "quiet":
+				best = max(best, 1-intensity)
+			}
+		}
+		groups++
+		sum += best
+	}
+
+	if groups == 0 {
+		return constants.NeutralScore, false
+	}
+	return sum / float64(groups), true
+}
+
+// ApplyTemporal scales score by the temporal score's distance from
+// neutral: a matched behavior gains up to weight times its score and a
+// contradicted one loses as much. Weight ranges over [0, 1].
+func ApplyTemporal(score, temporal, weight float64) float64 {
+	return score * (1 + weight*2*(temporal-constants.NeutralScore))
+}
+
+// SessionIntensity maps the tool calls of a session within the recent
+// window to a workload intensity in (0, 1], where busyCalls or more is
+// fully busy.
+func SessionIntensity(recentCalls, busyCalls int) float64 {
+	if recentCalls <= 0 || busyCalls <= 0 {
+		return 0
+	}
+	return min(1, float64(recentCalls)/float64(busyCalls))
+}
@@ -0,0 +1,106 @@
+package ranking
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestDayPartOf(t *testing.T) {
+	tests := []struct {
+		hour int
+		want DayPart
+	}{
+		{4, DayPartNight},
+		{5, DayPartMorning},
+		{11, DayPartMorning},
+		{12, DayPartAfternoon},
+		{17, DayPartEvening},
+		{21, DayPartEvening},
+		{22, DayPartNight},
+	}
+	for _, tt := range tests {
+		if got := DayPartOf(time.Date(2026, 10, 14, tt.hour, 30, 0, 0, time.UTC)); got != tt.want {
+			t.Errorf("DayPartOf(%02d:30) = %s, want %s", tt.hour, got, tt.want)
+		}
+	}
+}
+
+func TestTemporalScore(t *testing.T) {
+	// 2026-10-14 is a Wednesday, 2026-10-17 a Saturday
+	wedEvening := time.Date(2026, 10, 14, 18, 0, 0, 0, time.UTC)
+	satMorning := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		tags   []string
+		ctx    models.ContextSnapshot
+		want   float64
+		wantOK bool
+	}{
+		{"no temporal tags", []string{"testing"}, models.ContextSnapshot{Timestamp: wedEvening}, constants.NeutralScore, false},
+		{"day part match", []string{"time/evening"}, models.ContextSnapshot{Timestamp: wedEvening}, 1, true},
+		{"day part mismatch", []string{"Time/Morning"}, models.ContextSnapshot{Timestamp: wedEvening}, 0, true},
+		{"any of several day parts", []string{"time/morning", "time/evening"}, models.ContextSnapshot{Timestamp: wedEvening}, 1, true},
+		{"weekend", []string{"time/weekend"}, models.ContextSnapshot{Timestamp: satMorning}, 1, true},
+		{"groups averaged", []string{"time/evening", "time/weekday"}, models.ContextSnapshot{Timestamp: satMorning}, 0, true},
+		{"half the groups", []string{"time/morning", "time/weekday"}, models.ContextSnapshot{Timestamp: satMorning}, 0.5, true},
+		{"no timestamp", []string{"time/evening"}, models.ContextSnapshot{}, constants.NeutralScore, false},
+		{"busy", []string{"workload/busy"}, models.ContextSnapshot{Intensity: 0.8}, 0.8, true},
+		{"quiet", []string{"workload/quiet"}, models.ContextSnapshot{Intensity: 0.8}, 0.2, true},
+		{"no intensity", []string{"workload/busy"}, models.ContextSnapshot{Timestamp: wedEvening}, constants.NeutralScore, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &models.Behavior{Content: models.BehaviorContent{Tags: tt.tags}}
+			got, ok := TemporalScore(b, &tt.ctx)
+			if ok != tt.wantOK || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("TemporalScore() = %f, %v, want %f, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestApplyTemporal(t *testing.T) {
+	if got := ApplyTemporal(0.5, 1, 0.2); math.Abs(got-0.6) > 1e-9 {
+		t.Errorf("matched = %f, want 0.6", got)
+	}
+	if got := ApplyTemporal(0.5, 0, 0.2); math.Abs(got-0.4) > 1e-9 {
+		t.Errorf("contradicted = %f, want 0.4", got)
+	}
+	if got := ApplyTemporal(0.5, constants.NeutralScore, 0.2); got != 0.5 {
+		t.Errorf("neutral = %f, want 0.5", got)
+	}
+}
+
+func TestSessionIntensity(t *testing.T) {
+	if got := SessionIntensity(0, 30); got != 0 {
+		t.Errorf("idle = %f, want 0", got)
+	}
+	if got := SessionIntensity(15, 30); got != 0.5 {
+		t.Errorf("half busy = %f, want 0.5", got)
+	}
+	if got := SessionIntensity(60, 30); got != 1 {
+		t.Errorf("over busy = %f, want 1", got)
+	}
+}
+
+func TestRelevanceScorer_TemporalWeight(t *testing.T) {
+	ctx := &models.ContextSnapshot{Timestamp: time.Date(2026, 10, 14, 18, 0, 0, 0, time.UTC)}
+	tagged := &models.Behavior{ID: "b", Kind: models.BehaviorKindDirective, Content: models.BehaviorContent{Tags: []string{"time/evening"}}}
+
+	off := NewRelevanceScorer(DefaultScorerConfig()).Score(tagged, ctx)
+	cfg := DefaultScorerConfig()
+	cfg.TemporalWeight = 0.2
+	on := NewRelevanceScorer(cfg).Score(tagged, ctx)
+
+	if off.TemporalScore != constants.NeutralScore {
+		t.Errorf("disabled TemporalScore = %f, want neutral", off.TemporalScore)
+	}
+	if on.TemporalScore != 1 || math.Abs(on.Score-off.Score*1.2) > 1e-9 {
+		t.Errorf("enabled = %f (temporal %f), want %f", on.Score, on.TemporalScore, off.Score*1.2)
+	}
+}
@@ -2,7 +2,12 @@
 package ratelimit
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -37,6 +42,14 @@ func NewLimiter(rate float64, burst int) *Limiter {
 // Allow checks if a request for the given key should be allowed.
 // Returns true if allowed, false if rate limited.
 func (l *Limiter) Allow(key string) bool {
+	ok, _ := l.Take(key)
+	return ok
+}
+
+// Take consumes a token for key if one is available. When none is, it
+// returns false and how long until the next token; the wait is zero if the
+// limiter never refills.
+func (l *Limiter) Take(key string) (bool, time.Duration) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -52,7 +65,23 @@ func (l *Limiter) Allow(key string) bool {
 		l.buckets[key] = b
 	}
 
-	// Refill tokens based on elapsed time
+	l.refillLocked(b, now)
+
+	// Check if we have at least 1 token
+	if b.tokens < 1.0 {
+		if l.rate <= 0 {
+			return false, 0
+		}
+		return false, time.Duration(math.Ceil((1.0 - b.tokens) / l.rate * float64(time.Second)))
+	}
+
+	b.tokens--
+	return true, 0
+}
+
+// refillLocked adds the tokens earned since b was last checked. l.mu must
+// be held.
+func (l *Limiter) refillLocked(b *bucket, now time.Time) {
 	elapsed := now.Sub(b.lastCheck).Seconds()
 	if elapsed > 0 {
 		b.tokens += l.rate * elapsed
@@ -61,14 +90,52 @@ func (l *Limiter) Allow(key string) bool {
 		}
 		b.lastCheck = now
 	}
+}
 
-	// Check if we have at least 1 token
-	if b.tokens < 1.0 {
-		return false
+// Tokens returns the tokens key has available now, without consuming one.
+// A key never seen has a full burst.
+func (l *Limiter) Tokens(key string) float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[key]
+	if !ok {
+		return float64(l.burst)
 	}
+	l.refillLocked(b, l.nowFunc())
+	return b.tokens
+}
 
-	b.tokens--
-	return true
+// refund returns a token taken for key, for a call that did not go ahead.
+func (l *Limiter) refund(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.buckets[key]; ok {
+		b.tokens = math.Min(b.tokens+1, float64(l.burst))
+	}
+}
+
+// Forget drops the bucket of key; its next request starts with a full
+// burst.
+func (l *Limiter) Forget(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.buckets, key)
+}
+
+// scaled returns an empty limiter with factor times l's rate and burst.
+func (l *Limiter) scaled(factor float64) *Limiter {
+	burst := int(math.Ceil(float64(l.burst) * factor))
+	return &Limiter{
+		buckets: make(map[string]*bucket),
+		rate:    l.rate * factor,
+		burst:   burst,
+		nowFunc: l.nowFunc,
+	}
+}
+
+// Rule describes the limit, e.g. "10/minute, burst 3".
+func (l *Limiter) Rule() string {
+	return fmt.Sprintf("%s/minute, burst %d", strconv.FormatFloat(math.Round(l.rate*6000)/100, 'f', -1, 64), l.burst)
 }
 
 // ToolLimiters maps tool names to their rate limiters.
@@ -78,22 +145,59 @@ type ToolLimiters map[string]*Limiter
 // These limits are generous enough for normal usage but prevent abuse.
 func NewToolLimiters() ToolLimiters {
 	return ToolLimiters{
-		"floop_learn":        NewLimiter(10.0/60.0, 3), // 10/minute, burst 3
-		"floop_active":       NewLimiter(1.0, 10),      // 60/minute, burst 10
-		"floop_backup":       NewLimiter(5.0/60.0, 2),  // 5/minute, burst 2
-		"floop_restore":      NewLimiter(5.0/60.0, 2),  // 5/minute, burst 2
-		"floop_connect":      NewLimiter(30.0/60.0, 5), // 30/minute, burst 5
-		"floop_deduplicate":  NewLimiter(5.0/60.0, 1),  // 5/minute, burst 1
-		"floop_list":         NewLimiter(1.0, 10),      // 60/minute, burst 10
-		"floop_validate":     NewLimiter(10.0/60.0, 5), // 10/minute, burst 5
-		"floop_graph":        NewLimiter(30.0/60.0, 5), // 30/minute, burst 5
-		"floop_feedback":     NewLimiter(30.0/60.0, 5), // 30/minute, burst 5
-		"floop_pack_install": NewLimiter(5.0/60.0, 2),  // 5/minute, burst 2
+		"floop_learn":          NewLimiter(10.0/60.0, 3), // 10/minute, burst 3
+		"floop_learn_batch":    NewLimiter(2.0/60.0, 1),  // 2/minute, burst 1
+		"floop_report_failure": NewLimiter(10.0/60.0, 3), // 10/minute, burst 3
+		"floop_record_outcome": NewLimiter(30.0/60.0, 5), // 30/minute, burst 5
+		"floop_active":         NewLimiter(1.0, 10),      // 60/minute, burst 10
+		"floop_pin_context":    NewLimiter(30.0/60.0, 5), // 30/minute, burst 5
+		"floop_backup":         NewLimiter(5.0/60.0, 2),  // 5/minute, burst 2
+		"floop_restore":        NewLimiter(5.0/60.0, 2),  // 5/minute, burst 2
+		"floop_connect":        NewLimiter(30.0/60.0, 5), // 30/minute, burst 5
+		"floop_deduplicate":    NewLimiter(5.0/60.0, 1),  // 5/minute, burst 1
+		"floop_list":           NewLimiter(1.0, 10),      // 60/minute, burst 10
+		"floop_query":          NewLimiter(1.0, 10),      // 60/minute, burst 10
+		"floop_validate":       NewLimiter(10.0/60.0, 5), // 10/minute, burst 5
+		"floop_graph":          NewLimiter(30.0/60.0, 5), // 30/minute, burst 5
+		"floop_feedback":       NewLimiter(30.0/60.0, 5), // 30/minute, burst 5
+		"floop_feedback_batch": NewLimiter(10.0/60.0, 3), // 10/minute, burst 3
+		"floop_pack_install":   NewLimiter(5.0/60.0, 2),  // 5/minute, burst 2
+		"floop_similar":        NewLimiter(30.0/60.0, 5), // 30/minute, burst 5
+		"floop_search":         NewLimiter(1.0, 10),      // 60/minute, burst 10
+		"floop_explain":        NewLimiter(30.0/60.0, 5), // 30/minute, burst 5
+		"floop_session_info":   NewLimiter(1.0, 10),      // 60/minute, burst 10
+		"floop_promote":        NewLimiter(10.0/60.0, 3), // 10/minute, burst 3
 	}
 }
 
+// LimitError is returned when a tool call is rate limited. It carries what
+// a client needs to retry without guessing.
+type LimitError struct {
+	Tool       string        // Rate-limited tool
+	Rule       string        // The limit that applied, e.g. "10/minute, burst 3"
+	RetryAfter time.Duration // Time until the next call is allowed (0 = unknown)
+	Global     bool          // The global ceiling applied, not the caller's own budget
+}
+
+func (e *LimitError) Error() string {
+	msg := fmt.Sprintf("rate limit exceeded for %s (%s)", e.Tool, e.Rule)
+	if e.Global {
+		msg = fmt.Sprintf("global rate limit exceeded for %s (%s across all clients)", e.Tool, e.Rule)
+	}
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s, retry after %.1fs", msg, e.RetryAfterSeconds())
+	}
+	return msg + ", please try again shortly"
+}
+
+// RetryAfterSeconds returns RetryAfter in seconds, rounded up to a tenth so
+// a client waiting that long is not rejected again.
+func (e *LimitError) RetryAfterSeconds() float64 {
+	return math.Ceil(e.RetryAfter.Seconds()*10) / 10
+}
+
 // CheckLimit checks the rate limit for a given tool name.
-// Returns nil if allowed, or an error if rate limited.
+// Returns nil if allowed, or a *LimitError if rate limited.
 // Tools without a configured limiter are always allowed.
 func CheckLimit(limiters ToolLimiters, toolName string) error {
 	limiter, ok := limiters[toolName]
@@ -101,9 +205,125 @@ func CheckLimit(limiters ToolLimiters, toolName string) error {
 		return nil // No limiter configured = no limit
 	}
 
-	if !limiter.Allow(toolName) {
-		return fmt.Errorf("rate limit exceeded for %s, please try again shortly", toolName)
+	if ok, retryAfter := limiter.Take(toolName); !ok {
+		return &LimitError{Tool: toolName, Rule: limiter.Rule(), RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// WaitLimit is CheckLimit that, when the next token is at most maxWait away,
+// waits for it instead of failing. The total wait never exceeds maxWait; a
+// cancelled ctx returns its error.
+func WaitLimit(ctx context.Context, limiters ToolLimiters, toolName string, maxWait time.Duration) error {
+	return wait(ctx, maxWait, func() error { return CheckLimit(limiters, toolName) })
+}
+
+// wait retries check while it fails with a *LimitError whose next token is
+// within maxWait of the first try.
+func wait(ctx context.Context, maxWait time.Duration, check func() error) error {
+	deadline := time.Now().Add(maxWait)
+	for {
+		err := check()
+		var limitErr *LimitError
+		if !errors.As(err, &limitErr) || limitErr.RetryAfter <= 0 || time.Now().Add(limitErr.RetryAfter).After(deadline) {
+			return err
+		}
+
+		timer := time.NewTimer(limitErr.RetryAfter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Limits applies each tool's limit to every client separately, under a
+// global ceiling per tool shared by all clients, so one chatty agent in a
+// shared server exhausts its own budget rather than everyone's.
+type Limits struct {
+	Client ToolLimiters // Per-client budgets, with buckets keyed by client
+	Global ToolLimiters // Ceilings across clients, with buckets keyed by tool
+}
+
+// NewLimits creates the default per-client limits of NewToolLimiters, with
+// global ceilings of ceiling times each tool's budget. A ceiling below 1
+// leaves the tools without one.
+func NewLimits(ceiling float64) Limits {
+	client := NewToolLimiters()
+	global := make(ToolLimiters, len(client))
+	if ceiling >= 1 {
+		for tool, l := range client {
+			global[tool] = l.scaled(ceiling)
+		}
 	}
+	return Limits{Client: client, Global: global}
+}
 
+// Check takes a token for a call of tool by client from both the client's
+// budget and the global ceiling. It returns nil if the call is allowed, or
+// a *LimitError naming the limit that denied it. A call the global ceiling
+// denies is not charged to the client.
+func (l Limits) Check(tool, client string) error {
+	clientLimiter, ok := l.Client[tool]
+	if ok {
+		if allowed, retryAfter := clientLimiter.Take(client); !allowed {
+			return &LimitError{Tool: tool, Rule: clientLimiter.Rule(), RetryAfter: retryAfter}
+		}
+	}
+	if global, ok := l.Global[tool]; ok {
+		if allowed, retryAfter := global.Take(tool); !allowed {
+			if clientLimiter != nil {
+				clientLimiter.refund(client)
+			}
+			return &LimitError{Tool: tool, Rule: global.Rule(), RetryAfter: retryAfter, Global: true}
+		}
+	}
 	return nil
 }
+
+// Wait is Check that, when the next token is at most maxWait away, waits
+// for it instead of failing, as WaitLimit does.
+func (l Limits) Wait(ctx context.Context, tool, client string, maxWait time.Duration) error {
+	return wait(ctx, maxWait, func() error { return l.Check(tool, client) })
+}
+
+// Forget drops the buckets of client, for a client that disconnected.
+func (l Limits) Forget(client string) {
+	for _, limiter := range l.Client {
+		limiter.Forget(client)
+	}
+}
+
+// State describes one tool's limits as they stand for a client. The
+// Global fields are empty for a tool without a global ceiling.
+type State struct {
+	Tool         string   `json:"tool"`
+	Rule         string   `json:"rule"`
+	Tokens       float64  `json:"tokens"`
+	GlobalRule   string   `json:"global_rule,omitempty"`
+	GlobalTokens *float64 `json:"global_tokens,omitempty"`
+}
+
+// State returns the state of every tool with a per-client limit for client,
+// sorted by tool.
+// Tokens are rounded to hundredths.
+func (l Limits) State(client string) []State {
+	states := make([]State, 0, len(l.Client))
+	for tool, limiter := range l.Client {
+		st := State{Tool: tool, Rule: limiter.Rule(), Tokens: roundTokens(limiter.Tokens(client))}
+		if global, ok := l.Global[tool]; ok {
+			tokens := roundTokens(global.Tokens(tool))
+			st.GlobalRule, st.GlobalTokens = global.Rule(), &tokens
+		}
+		states = append(states, st)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Tool < states[j].Tool })
+	return states
+}
+
+func roundTokens(tokens float64) float64 {
+	return math.Round(tokens*100) / 100
+}
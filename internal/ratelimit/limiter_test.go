@@ -1,6 +1,9 @@
 package ratelimit
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -185,7 +188,15 @@ func TestNewToolLimiters(t *testing.T) {
 		"floop_connect",
 		"floop_deduplicate",
 		"floop_list",
+		"floop_query",
 		"floop_validate",
+		"floop_similar",
+		"floop_search",
+		"floop_explain",
+		"floop_report_failure",
+		"floop_record_outcome",
+		"floop_feedback_batch",
+		"floop_promote",
 	}
 
 	for _, tool := range expectedTools {
@@ -211,6 +222,11 @@ func TestToolRateLimits(t *testing.T) {
 		{"deduplicate burst", "floop_deduplicate", 1},
 		{"list burst", "floop_list", 10},
 		{"validate burst", "floop_validate", 5},
+		{"similar burst", "floop_similar", 5},
+		{"report failure burst", "floop_report_failure", 3},
+		{"record outcome burst", "floop_record_outcome", 5},
+		{"feedback batch burst", "floop_feedback_batch", 3},
+		{"promote burst", "floop_promote", 3},
 	}
 
 	for _, tt := range tests {
@@ -245,3 +261,187 @@ func TestCheckLimit(t *testing.T) {
 		t.Error("expected rate limit error after burst exhaustion")
 	}
 }
+
+func TestTake_RetryAfter(t *testing.T) {
+	now := time.Now()
+	l := NewLimiter(2.0, 1) // 2 tokens/sec
+	l.nowFunc = func() time.Time { return now }
+
+	if ok, _ := l.Take("key1"); !ok {
+		t.Fatal("first request should be allowed")
+	}
+	ok, retryAfter := l.Take("key1")
+	if ok {
+		t.Fatal("second request should be rejected")
+	}
+	if retryAfter != 500*time.Millisecond {
+		t.Errorf("retryAfter = %v, want 500ms", retryAfter)
+	}
+
+	now = now.Add(200 * time.Millisecond)
+	if _, retryAfter = l.Take("key1"); retryAfter != 300*time.Millisecond {
+		t.Errorf("retryAfter after 200ms = %v, want 300ms", retryAfter)
+	}
+
+	zero := NewLimiter(0, 0)
+	if ok, retryAfter := zero.Take("key1"); ok || retryAfter != 0 {
+		t.Errorf("zero-rate Take() = %v, %v; want false, 0", ok, retryAfter)
+	}
+}
+
+func TestLimiterRule(t *testing.T) {
+	tests := []struct {
+		rate  float64
+		burst int
+		want  string
+	}{
+		{10.0 / 60.0, 3, "10/minute, burst 3"},
+		{1.0, 10, "60/minute, burst 10"},
+		{0.5 / 60.0, 1, "0.5/minute, burst 1"},
+	}
+	for _, tt := range tests {
+		if got := NewLimiter(tt.rate, tt.burst).Rule(); got != tt.want {
+			t.Errorf("Rule() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestCheckLimit_LimitError(t *testing.T) {
+	limiters := ToolLimiters{"tool": NewLimiter(10.0/60.0, 1)}
+	CheckLimit(limiters, "tool")
+
+	err := CheckLimit(limiters, "tool")
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("CheckLimit() error = %v, want *LimitError", err)
+	}
+	if limitErr.Tool != "tool" || limitErr.Rule != "10/minute, burst 1" {
+		t.Errorf("LimitError = %+v", limitErr)
+	}
+	if limitErr.RetryAfterSeconds() < 5.9 || limitErr.RetryAfterSeconds() > 6.0 {
+		t.Errorf("RetryAfterSeconds() = %v, want about 6", limitErr.RetryAfterSeconds())
+	}
+	if !strings.Contains(err.Error(), "rate limit exceeded for tool") || !strings.Contains(err.Error(), "retry after") {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
+
+func TestWaitLimit(t *testing.T) {
+	ctx := context.Background()
+
+	// 20 tokens/sec: the next token is 50ms away, within maxWait
+	limiters := ToolLimiters{"fast": NewLimiter(20.0, 1)}
+	CheckLimit(limiters, "fast")
+	if err := WaitLimit(ctx, limiters, "fast", time.Second); err != nil {
+		t.Errorf("WaitLimit() within maxWait error = %v", err)
+	}
+
+	// The next token is further away than maxWait: fail without waiting
+	limiters = ToolLimiters{"slow": NewLimiter(1.0/60.0, 1)}
+	CheckLimit(limiters, "slow")
+	start := time.Now()
+	err := WaitLimit(ctx, limiters, "slow", 100*time.Millisecond)
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) {
+		t.Errorf("WaitLimit() beyond maxWait error = %v, want *LimitError", err)
+	}
+	if time.Since(start) > 50*time.Millisecond {
+		t.Errorf("WaitLimit() waited %v for a token beyond maxWait", time.Since(start))
+	}
+
+	// Zero maxWait disables waiting
+	limiters = ToolLimiters{"fast": NewLimiter(20.0, 1)}
+	CheckLimit(limiters, "fast")
+	if err := WaitLimit(ctx, limiters, "fast", 0); err == nil {
+		t.Error("WaitLimit() with zero maxWait should fail immediately")
+	}
+
+	// Cancellation ends the wait
+	limiters = ToolLimiters{"medium": NewLimiter(2.0, 1)}
+	CheckLimit(limiters, "medium")
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := WaitLimit(cctx, limiters, "medium", time.Second); !errors.Is(err, context.Canceled) {
+		t.Errorf("WaitLimit() with cancelled ctx error = %v, want context.Canceled", err)
+	}
+}
+
+func TestLimits_PerClient(t *testing.T) {
+	limits := Limits{
+		Client: ToolLimiters{"tool": NewLimiter(1.0/60.0, 2)},
+		Global: ToolLimiters{"tool": NewLimiter(1.0/60.0, 3)},
+	}
+
+	// A chatty client exhausts its own budget, not another client's
+	for i := 0; i < 2; i++ {
+		if err := limits.Check("tool", "chatty"); err != nil {
+			t.Fatalf("call %d of chatty error = %v", i+1, err)
+		}
+	}
+	err := limits.Check("tool", "chatty")
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || limitErr.Global || limitErr.Rule != "1/minute, burst 2" {
+		t.Fatalf("third call of chatty error = %v, want its own budget exhausted", err)
+	}
+	if err := limits.Check("tool", "quiet"); err != nil {
+		t.Fatalf("first call of quiet error = %v", err)
+	}
+
+	// The global ceiling stops the clients together, without charging them
+	err = limits.Check("tool", "other")
+	if !errors.As(err, &limitErr) || !limitErr.Global {
+		t.Fatalf("call over the ceiling error = %v, want a global LimitError", err)
+	}
+	if !strings.Contains(err.Error(), "global rate limit exceeded for tool") || !strings.Contains(err.Error(), "across all clients") {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if tokens := limits.Client["tool"].Tokens("other"); tokens < 1.99 {
+		t.Errorf("other has %v tokens after a call the ceiling denied, want 2", tokens)
+	}
+
+	// Tools without limiters are always allowed
+	if err := limits.Check("unknown", "chatty"); err != nil {
+		t.Errorf("unlimited tool error = %v", err)
+	}
+}
+
+func TestLimits_StateAndForget(t *testing.T) {
+	limits := NewLimits(4)
+	if l := limits.Global["floop_learn"]; l == nil || l.Rule() != "40/minute, burst 12" {
+		t.Fatalf("floop_learn ceiling = %v, want four times the per-client budget", l)
+	}
+	if len(NewLimits(0).Global) != 0 {
+		t.Error("a ceiling below 1 should leave tools without one")
+	}
+
+	limits.Check("floop_learn", "a")
+	var learn State
+	for _, st := range limits.State("a") {
+		if st.Tool == "floop_learn" {
+			learn = st
+		}
+	}
+	if learn.Rule != "10/minute, burst 3" || learn.Tokens < 1.99 || learn.Tokens > 2.01 {
+		t.Errorf("floop_learn state = %+v, want 2 of 3 tokens left", learn)
+	}
+	if learn.GlobalTokens == nil || *learn.GlobalTokens < 10.99 || *learn.GlobalTokens > 11.01 {
+		t.Errorf("floop_learn global tokens = %v, want 11 of 12 left", learn.GlobalTokens)
+	}
+
+	limits.Forget("a")
+	if tokens := limits.Client["floop_learn"].Tokens("a"); tokens != 3 {
+		t.Errorf("tokens after Forget = %v, want a full burst", tokens)
+	}
+}
+
+func TestLimits_Wait(t *testing.T) {
+	limits := Limits{Client: ToolLimiters{"fast": NewLimiter(20.0, 1)}}
+	limits.Check("fast", "a")
+	if err := limits.Wait(context.Background(), "fast", "a", time.Second); err != nil {
+		t.Errorf("Wait() within maxWait error = %v", err)
+	}
+	limits.Check("fast", "a")
+	if err := limits.Wait(context.Background(), "fast", "a", 0); err == nil {
+		t.Error("Wait() with zero maxWait should fail immediately")
+	}
+}
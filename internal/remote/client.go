@@ -0,0 +1,95 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds a client request when NewClient is given none.
+const DefaultTimeout = 10 * time.Second
+
+// maxResponseBytes bounds the response body a client reads.
+const maxResponseBytes = 8 << 20
+
+// ErrNotModified is returned by Client.Prompt when the server reports that
+// the prompt still matches the ETag the client sent.
+var ErrNotModified = errors.New("prompt not modified")
+
+// Client fetches compiled prompts from a floop server.
+type Client struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewClient returns a client for the server at baseURL (for example
+// http://floop.internal:8765). A non-positive timeout uses DefaultTimeout.
+func NewClient(baseURL string, timeout time.Duration) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid server URL %q: want http(s)://host[:port]", baseURL)
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Prompt fetches the compiled prompt for req. If etag is non-empty it is
+// sent as If-None-Match, and ErrNotModified is returned when the server's
+// prompt still matches it.
+func (c *Client) Prompt(ctx context.Context, req Request, etag string) (*Response, error) {
+	endpoint := c.baseURL + PromptPath
+	if q := req.Query().Encode(); q != "" {
+		endpoint += "?" + q
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if etag != "" {
+		httpReq.Header.Set("If-None-Match", etag)
+	}
+
+	httpResp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("contacting floop server: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode == http.StatusNotModified {
+		return nil, ErrNotModified
+	}
+	body, err := io.ReadAll(io.LimitReader(httpResp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("reading server response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(body, &e) == nil && e.Error != "" {
+			return nil, fmt.Errorf("floop server returned %d: %s", httpResp.StatusCode, e.Error)
+		}
+		return nil, fmt.Errorf("floop server returned %d", httpResp.StatusCode)
+	}
+
+	var resp Response
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("decoding server response: %w", err)
+	}
+	if resp.Version != ProtocolVersion {
+		return nil, fmt.Errorf("unsupported protocol version %d (this client speaks %d)", resp.Version, ProtocolVersion)
+	}
+	return &resp, nil
+}
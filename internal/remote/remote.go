@@ -0,0 +1,143 @@
+// Package remote defines the compact, read-only wire format a central floop
+// server uses to hand compiled prompts to thin clients (remote dev boxes,
+// codespaces) that have no local store.
+//
+// A client sends GET /v1/prompt with its context as query parameters and
+// receives the compiled prompt, the top active behaviors, and an ETag. When
+// the client repeats the request with If-None-Match set to that ETag and
+// nothing has changed, the server answers 304 Not Modified.
+package remote
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// ProtocolVersion is the wire format version carried in every response.
+const ProtocolVersion = 1
+
+// PromptPath is the endpoint that serves compiled prompts.
+const PromptPath = "/v1/prompt"
+
+// DefaultTop is the number of behaviors a response carries when the request
+// does not set Top.
+const DefaultTop = 10
+
+// MaxTop bounds the number of behaviors a request may ask for.
+const MaxTop = 100
+
+// Request is the context a client compiles a prompt for. It travels as the
+// query string of a GET request.
+type Request struct {
+	File        string `json:"file,omitempty"`
+	Language    string `json:"language,omitempty"`
+	Task        string `json:"task,omitempty"`
+	Env         string `json:"env,omitempty"`
+	Agent       string `json:"agent,omitempty"`
+	Format      string `json:"format,omitempty"`       // markdown (default), xml, or plain
+	TokenBudget int    `json:"token_budget,omitempty"` // 0 means unlimited
+	Top         int    `json:"top,omitempty"`          // 0 means DefaultTop
+}
+
+// Response is the compiled prompt for a request.
+type Response struct {
+	Version   int        `json:"v"`
+	ETag      string     `json:"etag"`
+	Prompt    string     `json:"prompt"`
+	Format    string     `json:"format"`
+	Tokens    int        `json:"tokens"`
+	Behaviors []Behavior `json:"behaviors"`
+}
+
+// Behavior is the compact form of an active behavior.
+type Behavior struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Kind       string  `json:"kind"`
+	Content    string  `json:"content"`
+	Confidence float64 `json:"confidence"`
+}
+
+// CompileFunc compiles the prompt for a request. Handlers fill in the
+// response's Version and ETag.
+type CompileFunc func(ctx context.Context, req Request) (*Response, error)
+
+// Query encodes the request as URL query parameters, omitting empty fields.
+func (r Request) Query() url.Values {
+	q := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	set("file", r.File)
+	set("language", r.Language)
+	set("task", r.Task)
+	set("env", r.Env)
+	set("agent", r.Agent)
+	set("format", r.Format)
+	if r.TokenBudget > 0 {
+		q.Set("token_budget", strconv.Itoa(r.TokenBudget))
+	}
+	if r.Top > 0 {
+		q.Set("top", strconv.Itoa(r.Top))
+	}
+	return q
+}
+
+// ParseRequest decodes a request from URL query parameters.
+func ParseRequest(q url.Values) (Request, error) {
+	r := Request{
+		File:     q.Get("file"),
+		Language: q.Get("language"),
+		Task:     q.Get("task"),
+		Env:      q.Get("env"),
+		Agent:    q.Get("agent"),
+		Format:   q.Get("format"),
+	}
+	switch r.Format {
+	case "", "markdown", "xml", "plain":
+	default:
+		return Request{}, fmt.Errorf("invalid format %q (valid: markdown, xml, plain)", r.Format)
+	}
+
+	var err error
+	if r.TokenBudget, err = nonNegativeInt(q, "token_budget"); err != nil {
+		return Request{}, err
+	}
+	if r.Top, err = nonNegativeInt(q, "top"); err != nil {
+		return Request{}, err
+	}
+	if r.Top > MaxTop {
+		return Request{}, fmt.Errorf("top must be at most %d", MaxTop)
+	}
+	return r, nil
+}
+
+// nonNegativeInt parses an optional non-negative integer parameter.
+func nonNegativeInt(q url.Values, key string) (int, error) {
+	s := q.Get(key)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer, got %q", key, s)
+	}
+	return n, nil
+}
+
+// ComputeETag returns a strong ETag for the response's content, ignoring
+// the ETag field itself.
+func ComputeETag(resp *Response) string {
+	c := *resp
+	c.ETag = ""
+	data, _ := json.Marshal(c)
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
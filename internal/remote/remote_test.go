@@ -0,0 +1,163 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequest_QueryRoundTrip(t *testing.T) {
+	req := Request{File: "main.go", Task: "testing", Agent: "cursor", Format: "xml", TokenBudget: 500, Top: 3}
+	got, err := ParseRequest(req.Query())
+	if err != nil {
+		t.Fatalf("ParseRequest() error = %v", err)
+	}
+	if got != req {
+		t.Errorf("round trip = %+v, want %+v", got, req)
+	}
+	if q := (Request{}).Query(); len(q) != 0 {
+		t.Errorf("empty request query = %v, want no parameters", q)
+	}
+}
+
+func TestParseRequest_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"unknown format", "format=html"},
+		{"negative budget", "token_budget=-1"},
+		{"non-numeric top", "top=many"},
+		{"top over limit", "top=101"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, _ := url.ParseQuery(tt.query)
+			if _, err := ParseRequest(q); err == nil {
+				t.Errorf("ParseRequest(%q) succeeded, want error", tt.query)
+			}
+		})
+	}
+}
+
+func newTestServer(t *testing.T, prompt *string) (*httptest.Server, *Request) {
+	t.Helper()
+	var last Request
+	srv := httptest.NewServer(NewHandler(func(ctx context.Context, req Request) (*Response, error) {
+		last = req
+		if *prompt == "fail" {
+			return nil, errors.New("store unavailable")
+		}
+		return &Response{
+			Prompt:    *prompt,
+			Format:    "markdown",
+			Tokens:    4,
+			Behaviors: []Behavior{{ID: "b1", Name: "use-pathlib", Kind: "directive", Content: *prompt, Confidence: 0.8}},
+		}, nil
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &last
+}
+
+func TestClient_Prompt(t *testing.T) {
+	prompt := "Use pathlib"
+	srv, last := newTestServer(t, &prompt)
+	client, err := NewClient(srv.URL+"/", time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	ctx := context.Background()
+
+	resp, err := client.Prompt(ctx, Request{File: "app.py"}, "")
+	if err != nil {
+		t.Fatalf("Prompt() error = %v", err)
+	}
+	if resp.Version != ProtocolVersion || resp.Prompt != prompt || resp.ETag == "" || len(resp.Behaviors) != 1 {
+		t.Errorf("Prompt() = %+v", resp)
+	}
+	if last.File != "app.py" || last.Top != DefaultTop {
+		t.Errorf("server saw request %+v, want file app.py with the default top", *last)
+	}
+
+	if _, err := client.Prompt(ctx, Request{File: "app.py"}, resp.ETag); !errors.Is(err, ErrNotModified) {
+		t.Errorf("Prompt() with current etag error = %v, want ErrNotModified", err)
+	}
+
+	prompt = "Use pathlib, not os.path"
+	changed, err := client.Prompt(ctx, Request{File: "app.py"}, resp.ETag)
+	if err != nil {
+		t.Fatalf("Prompt() after change error = %v", err)
+	}
+	if changed.ETag == resp.ETag || changed.Prompt != prompt {
+		t.Errorf("changed prompt = %+v, want a new etag", changed)
+	}
+
+	prompt = "fail"
+	if _, err := client.Prompt(ctx, Request{}, ""); err == nil || !strings.Contains(err.Error(), "store unavailable") {
+		t.Errorf("Prompt() on server error = %v, want the server's message", err)
+	}
+}
+
+func TestHandler_ReadOnly(t *testing.T) {
+	prompt := "Use pathlib"
+	srv, _ := newTestServer(t, &prompt)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+	}{
+		{"get", http.MethodGet, PromptPath, http.StatusOK},
+		{"head", http.MethodHead, PromptPath, http.StatusOK},
+		{"post", http.MethodPost, PromptPath, http.StatusMethodNotAllowed},
+		{"delete", http.MethodDelete, PromptPath, http.StatusMethodNotAllowed},
+		{"bad parameter", http.MethodGet, PromptPath + "?top=0x10", http.StatusBadRequest},
+		{"unknown path", http.MethodGet, "/v1/behaviors", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, srv.URL+tt.path, nil)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("%s %s: %v", tt.method, tt.path, err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("%s %s status = %d, want %d", tt.method, tt.path, resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClient_InvalidURL(t *testing.T) {
+	for _, u := range []string{"", "floop.internal:8765", "ftp://floop.internal", "http://"} {
+		if _, err := NewClient(u, 0); err == nil {
+			t.Errorf("NewClient(%q) succeeded, want error", u)
+		}
+	}
+}
+
+func TestEtagMatches(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"xyz", "abc"`, true},
+		{`*`, true},
+		{`"xyz"`, false},
+		{``, false},
+	}
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, `"abc"`); got != tt.want {
+			t.Errorf("etagMatches(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
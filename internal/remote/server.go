@@ -0,0 +1,71 @@
+package remote
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// NewHandler returns a read-only HTTP handler serving PromptPath with
+// compile. Only GET and HEAD are accepted.
+func NewHandler(compile CompileFunc) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(PromptPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		req, err := ParseRequest(r.URL.Query())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if req.Top == 0 {
+			req.Top = DefaultTop
+		}
+
+		resp, err := compile(r.Context(), req)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		resp.Version = ProtocolVersion
+		if resp.Behaviors == nil {
+			resp.Behaviors = []Behavior{}
+		}
+		resp.ETag = ComputeETag(resp)
+
+		w.Header().Set("ETag", resp.ETag)
+		w.Header().Set("Cache-Control", "no-cache")
+		if etagMatches(r.Header.Get("If-None-Match"), resp.ETag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodHead {
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+	return mux
+}
+
+// etagMatches reports whether an If-None-Match header lists etag.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeError writes a JSON error body with the given status.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
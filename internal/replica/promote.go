@@ -0,0 +1,161 @@
+package replica
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/publish"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// savedDirFmt names the directory a promotion moves the replaced store
+// files to, relative to the .floop directory.
+const savedDirFmt = "failover-20060102T150405Z"
+
+// replacedFiles are the files of a .floop directory a promotion moves aside,
+// besides store.SyncedFileNames: the database, which is rebuilt from the
+// promoted JSONL on the next open.
+var replacedFiles = []string{"floop.db", "floop.db-wal", "floop.db-shm"}
+
+// Promotion is a verified replica of a store and its partitions, ready to
+// replace the store.
+type Promotion struct {
+	Store    string
+	Manifest *Manifest
+
+	files      map[string][]byte
+	partitions []*Promotion // by partition key, for a partitioned store
+	key        string       // partition key, for a partition
+}
+
+// Partitions returns the promotions of the store's partitions.
+func (p *Promotion) Partitions() []*Promotion {
+	return p.partitions
+}
+
+// Files returns the names of the files the promotion writes, sorted.
+func (p *Promotion) Files() []string {
+	names := make([]string, 0, len(p.files))
+	for name := range p.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Plan reads the replica of store name from target, with the replica of
+// every partition its partition manifest lists, and verifies each file
+// against its manifest. Nothing is written, so Plan doubles as a check that
+// the replica can be promoted.
+func Plan(ctx context.Context, target publish.Reader, name string) (*Promotion, error) {
+	m, err := ReadManifest(ctx, target, name)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, fmt.Errorf("no replica of the %s store at %v", name, target)
+	}
+
+	p := &Promotion{Store: name, Manifest: m, files: make(map[string][]byte, len(m.Files))}
+	for file, want := range m.Files {
+		if !slices.Contains(store.SyncedFileNames, file) {
+			return nil, fmt.Errorf("replica manifest for %s lists unexpected file %q", name, file)
+		}
+		data, err := target.Get(ctx, path.Join(name, file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read replica file %s/%s: %w", name, file, err)
+		}
+		if got := fileEntry(data); got != want {
+			return nil, fmt.Errorf("replica file %s/%s does not match its manifest (the replica may be mid-update; retry)", name, file)
+		}
+		p.files[file] = data
+	}
+
+	data, ok := p.files[path.Join(store.PartitionsDir, store.PartitionManifestFile)]
+	if !ok {
+		return p, nil
+	}
+	var pm store.PartitionManifest
+	if err := json.Unmarshal(data, &pm); err != nil {
+		return nil, fmt.Errorf("failed to parse replicated partition manifest for %s: %w", name, err)
+	}
+	for _, info := range pm.Partitions {
+		if info.Key == "" || pathSegment(info.Key) != info.Key {
+			return nil, fmt.Errorf("replicated partition manifest for %s has invalid key %q", name, info.Key)
+		}
+		part, err := Plan(ctx, target, path.Join(name, store.PartitionsDir, info.Key))
+		if err != nil {
+			return nil, err
+		}
+		part.key = info.Key
+		p.partitions = append(p.partitions, part)
+	}
+	return p, nil
+}
+
+// Apply replaces the store in floopDir with the promotion. The store's
+// database, JSONL, and partitions are first moved to a failover-<time>
+// directory inside floopDir, whose path is returned, so nothing is lost.
+// The database is rebuilt from the promoted JSONL when the store is next
+// opened. The store must not be open while Apply runs.
+func (p *Promotion) Apply(floopDir string, now time.Time) (string, error) {
+	saved := filepath.Join(floopDir, now.UTC().Format(savedDirFmt))
+	if err := os.MkdirAll(saved, 0700); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", saved, err)
+	}
+
+	// The partitions directory is moved whole, partition manifest included
+	moved := append([]string(nil), replacedFiles...)
+	for _, name := range store.SyncedFileNames {
+		if !strings.HasPrefix(name, store.PartitionsDir+"/") {
+			moved = append(moved, name)
+		}
+	}
+	moved = append(moved, store.PartitionsDir)
+	for _, name := range moved {
+		src := filepath.Join(floopDir, filepath.FromSlash(name))
+		if _, err := os.Lstat(src); os.IsNotExist(err) {
+			continue
+		}
+		dst := filepath.Join(saved, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+			return saved, fmt.Errorf("failed to create %s: %w", filepath.Dir(dst), err)
+		}
+		if err := os.Rename(src, dst); err != nil {
+			return saved, fmt.Errorf("failed to move %s aside: %w", src, err)
+		}
+	}
+
+	if err := p.write(floopDir); err != nil {
+		return saved, err
+	}
+	return saved, nil
+}
+
+// write writes the promoted files of the store and its partitions under
+// floopDir.
+func (p *Promotion) write(floopDir string) error {
+	for _, name := range p.Files() {
+		dst := filepath.Join(floopDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(dst), err)
+		}
+		if err := os.WriteFile(dst, p.files[name], 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", dst, err)
+		}
+	}
+	for _, part := range p.partitions {
+		if err := part.write(filepath.Join(floopDir, store.PartitionsDir, part.key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package replica
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/publish"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestPlan_RejectsMismatchedFile(t *testing.T) {
+	ctx := context.Background()
+	globalDir := filepath.Join(t.TempDir(), ".floop")
+	target := &publish.DirTarget{Root: t.TempDir()}
+
+	if _, err := Plan(ctx, target, GlobalStore); err == nil {
+		t.Error("Plan() with no replica succeeded")
+	}
+
+	synced := store.SyncedFiles{Dir: globalDir, Files: map[string][]byte{"nodes.jsonl": []byte("{}\n")}}
+	if _, err := New(target, ScopeGlobal, globalDir).Ship(ctx, synced); err != nil {
+		t.Fatalf("Ship() error = %v", err)
+	}
+	if _, err := Plan(ctx, target, GlobalStore); err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	// A file overwritten after its manifest no longer matches
+	if err := target.Put(ctx, "global/nodes.jsonl", []byte("{\"id\":\"x\"}\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Plan(ctx, target, GlobalStore); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("Plan() error = %v, want a checksum mismatch", err)
+	}
+}
+
+func TestPromotion_Apply(t *testing.T) {
+	ctx := context.Background()
+	home := t.TempDir()
+	globalDir := filepath.Join(home, ".floop")
+	target := &publish.DirTarget{Root: t.TempDir()}
+	r := New(target, ScopeGlobal, globalDir)
+
+	pm, _ := json.Marshal(store.PartitionManifest{Partitions: []store.PartitionInfo{{Key: "go"}}})
+	shipped := map[string]store.SyncedFiles{
+		"global": {Dir: globalDir, Files: map[string][]byte{
+			"nodes.jsonl":              []byte("{\"id\":\"core\"}\n"),
+			"partitions/manifest.json": pm,
+		}},
+		"go": {Dir: filepath.Join(globalDir, "partitions", "go"), Files: map[string][]byte{
+			"nodes.jsonl": []byte("{\"id\":\"go\"}\n"),
+		}},
+	}
+	for _, synced := range shipped {
+		if _, err := r.Ship(ctx, synced); err != nil {
+			t.Fatalf("Ship() error = %v", err)
+		}
+	}
+
+	// The store being replaced
+	write := func(name, content string) {
+		t.Helper()
+		p := filepath.Join(globalDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("floop.db", "corrupt")
+	write("nodes.jsonl", "damaged")
+	write("partitions/stale/nodes.jsonl", "stale")
+	write("config.yaml", "kept")
+
+	p, err := Plan(ctx, target, GlobalStore)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if len(p.Partitions()) != 1 {
+		t.Fatalf("Plan() found %d partitions, want 1", len(p.Partitions()))
+	}
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	saved, err := p.Apply(globalDir, now)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if saved != filepath.Join(globalDir, "failover-20260301T120000Z") {
+		t.Errorf("saved = %q", saved)
+	}
+
+	read := func(dir, name string) string {
+		data, _ := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		return string(data)
+	}
+	checks := []struct{ dir, name, want string }{
+		{globalDir, "nodes.jsonl", "{\"id\":\"core\"}\n"},
+		{globalDir, "partitions/go/nodes.jsonl", "{\"id\":\"go\"}\n"},
+		{globalDir, "partitions/manifest.json", string(pm)},
+		{globalDir, "config.yaml", "kept"},
+		{globalDir, "floop.db", ""},
+		{globalDir, "partitions/stale/nodes.jsonl", ""},
+		{saved, "floop.db", "corrupt"},
+		{saved, "nodes.jsonl", "damaged"},
+		{saved, "partitions/stale/nodes.jsonl", "stale"},
+	}
+	for _, c := range checks {
+		if got := read(c.dir, c.name); got != c.want {
+			t.Errorf("%s in %s = %q, want %q", c.name, c.dir, got, c.want)
+		}
+	}
+}
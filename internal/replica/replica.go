@@ -0,0 +1,244 @@
+// Package replica keeps a warm standby copy of floop stores. Every Sync of a
+// replicated store ships the files that changed since its last shipment to
+// a replica target (a directory such as a mounted NAS share, or S3), and a
+// Promotion restores a store from its replica.
+package replica
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nvandessel/floop/internal/project"
+	"github.com/nvandessel/floop/internal/publish"
+	"github.com/nvandessel/floop/internal/sanitize"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// ManifestVersion is the current replica manifest format version.
+const ManifestVersion = 1
+
+// Replica layout within a target:
+//
+//	global/manifest.json              file list with checksums (written last)
+//	global/nodes.jsonl
+//	global/nodes.log.jsonl
+//	global/edges.jsonl
+//	global/partitions/manifest.json   for a partitioned global store
+//	global/partitions/<key>/...       one replica per partition
+//	local/<project>/...               local stores, with scope "all"
+const (
+	ManifestFile = "manifest.json"
+	GlobalStore  = "global"
+	localPrefix  = "local"
+)
+
+// Target is a replica destination that files can be read back from.
+type Target interface {
+	publish.Target
+	publish.Reader
+}
+
+// Scope selects the stores that are replicated.
+type Scope string
+
+const (
+	// ScopeGlobal replicates the global store, with its partitions.
+	ScopeGlobal Scope = "global"
+	// ScopeAll also replicates the local store of every project.
+	ScopeAll Scope = "all"
+)
+
+// ParseScope validates a scope name. The empty name selects ScopeGlobal.
+func ParseScope(s string) (Scope, error) {
+	switch scope := Scope(s); scope {
+	case "":
+		return ScopeGlobal, nil
+	case ScopeGlobal, ScopeAll:
+		return scope, nil
+	}
+	return "", fmt.Errorf("invalid replication stores %q (must be global or all)", s)
+}
+
+// Manifest lists the files of one replicated store. It is written after the
+// files it lists, so a reader that finds a file matching its checksum has
+// the shipped version.
+type Manifest struct {
+	Version int    `json:"version"`
+	Store   string `json:"store"`
+	// Source is the .floop directory the replica was shipped from.
+	Source string `json:"source"`
+	Host   string `json:"host,omitempty"`
+	// Sequence counts the shipments to the replica.
+	Sequence  int64                `json:"sequence"`
+	UpdatedAt time.Time            `json:"updated_at"`
+	Files     map[string]FileEntry `json:"files"`
+}
+
+// FileEntry describes one replicated file.
+type FileEntry struct {
+	SHA256 string `json:"sha256"`
+	Size   int    `json:"size"`
+}
+
+// Replicator ships synced store files to a target.
+type Replicator struct {
+	target    Target
+	scope     Scope
+	globalDir string
+	now       func() time.Time
+
+	// mu serializes shipments, so manifests are written in sequence.
+	// shipped holds the last manifest written or read per store name.
+	mu      sync.Mutex
+	shipped map[string]*Manifest
+}
+
+// New returns a Replicator shipping the stores scope selects to target.
+// globalDir is the global .floop directory.
+func New(target Target, scope Scope, globalDir string) *Replicator {
+	return &Replicator{
+		target:    target,
+		scope:     scope,
+		globalDir: globalDir,
+		now:       time.Now,
+		shipped:   make(map[string]*Manifest),
+	}
+}
+
+// StoreName returns the name the store in floopDir is replicated under:
+// "global" for the global store, "global/partitions/<key>" for one of its
+// partitions, and "local/<project>" for a project's local store, named by
+// its project ID or, without one, its directory.
+func StoreName(floopDir, globalDir string) string {
+	if rel, err := filepath.Rel(globalDir, floopDir); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		if rel == "." {
+			return GlobalStore
+		}
+		return path.Join(GlobalStore, filepath.ToSlash(rel))
+	}
+
+	projectRoot := filepath.Dir(floopDir)
+	if id, err := project.ResolveProjectID(projectRoot); err == nil && id != "" {
+		if name := pathSegment(id); name != "" {
+			return path.Join(localPrefix, name)
+		}
+	}
+	abs, err := filepath.Abs(projectRoot)
+	if err != nil {
+		abs = projectRoot
+	}
+	sum := sha256.Sum256([]byte(abs))
+	base := pathSegment(strings.ToLower(filepath.Base(abs)))
+	if base == "" {
+		base = "project"
+	}
+	return path.Join(localPrefix, base+"-"+hex.EncodeToString(sum[:4]))
+}
+
+// pathSegment reduces s to a single safe path segment.
+func pathSegment(s string) string {
+	return strings.Trim(strings.ReplaceAll(sanitize.SanitizeBehaviorName(s), "/", "-"), "-")
+}
+
+// Ship sends the files of synced that changed since the store's last
+// shipment, then the store's manifest. It returns the names of the files
+// sent, or nil if nothing changed or the scope leaves the store out. The
+// first shipment of a store in a process reads the manifest already at the
+// target to find what changed.
+func (r *Replicator) Ship(ctx context.Context, synced store.SyncedFiles) ([]string, error) {
+	name := StoreName(synced.Dir, r.globalDir)
+	if r.scope != ScopeAll && name != GlobalStore && !strings.HasPrefix(name, GlobalStore+"/") {
+		return nil, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prev, ok := r.shipped[name]
+	if !ok {
+		var err error
+		if prev, err = ReadManifest(ctx, r.target, name); err != nil {
+			return nil, err
+		}
+	}
+
+	next := &Manifest{
+		Version:   ManifestVersion,
+		Store:     name,
+		Source:    synced.Dir,
+		Sequence:  1,
+		UpdatedAt: r.now().UTC(),
+		Files:     make(map[string]FileEntry, len(synced.Files)),
+	}
+	next.Host, _ = os.Hostname()
+
+	var changed []string
+	for file, data := range synced.Files {
+		entry := fileEntry(data)
+		next.Files[file] = entry
+		if prev == nil || prev.Files[file] != entry {
+			changed = append(changed, file)
+		}
+	}
+	if prev != nil {
+		next.Sequence = prev.Sequence + 1
+		if len(changed) == 0 && len(prev.Files) == len(next.Files) {
+			r.shipped[name] = prev
+			return nil, nil
+		}
+	}
+	sort.Strings(changed)
+
+	for _, file := range changed {
+		if err := r.target.Put(ctx, path.Join(name, file), synced.Files[file]); err != nil {
+			return nil, fmt.Errorf("failed to replicate %s/%s: %w", name, file, err)
+		}
+	}
+	data, err := json.MarshalIndent(next, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode replica manifest: %w", err)
+	}
+	if err := r.target.Put(ctx, path.Join(name, ManifestFile), append(data, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to write replica manifest for %s: %w", name, err)
+	}
+	r.shipped[name] = next
+	return changed, nil
+}
+
+// ReadManifest reads the manifest of the replica of store name. It returns
+// nil if target holds no replica of the store.
+func ReadManifest(ctx context.Context, target publish.Reader, name string) (*Manifest, error) {
+	data, err := target.Get(ctx, path.Join(name, ManifestFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replica manifest for %s: %w", name, err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse replica manifest for %s: %w", name, err)
+	}
+	if m.Version > ManifestVersion {
+		return nil, fmt.Errorf("replica manifest for %s has version %d; this floop reads up to %d", name, m.Version, ManifestVersion)
+	}
+	return &m, nil
+}
+
+// fileEntry describes data for a manifest.
+func fileEntry(data []byte) FileEntry {
+	sum := sha256.Sum256(data)
+	return FileEntry{SHA256: hex.EncodeToString(sum[:]), Size: len(data)}
+}
@@ -0,0 +1,142 @@
+package replica
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/publish"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// countingTarget records the names put to a DirTarget.
+type countingTarget struct {
+	*publish.DirTarget
+	puts []string
+}
+
+func (t *countingTarget) Put(ctx context.Context, name string, data []byte) error {
+	t.puts = append(t.puts, name)
+	return t.DirTarget.Put(ctx, name, data)
+}
+
+func TestParseScope(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Scope
+		wantErr bool
+	}{
+		{"", ScopeGlobal, false},
+		{"global", ScopeGlobal, false},
+		{"all", ScopeAll, false},
+		{"local", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseScope(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseScope(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestStoreName(t *testing.T) {
+	globalDir := filepath.Join(t.TempDir(), ".floop")
+	projectRoot := filepath.Join(t.TempDir(), "My Project")
+	if err := os.MkdirAll(projectRoot, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		floopDir string
+		want     string
+	}{
+		{"global", globalDir, "global"},
+		{"partition", filepath.Join(globalDir, "partitions", "go"), "global/partitions/go"},
+		{"sibling of global", globalDir + "-other", ""},
+		{"local", store.LocalFloopPath(projectRoot), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := StoreName(tt.floopDir, globalDir)
+			if tt.want != "" {
+				if got != tt.want {
+					t.Errorf("StoreName() = %q, want %q", got, tt.want)
+				}
+				return
+			}
+			if filepath.Dir(got) != localPrefix {
+				t.Errorf("StoreName() = %q, want a name under %s/", got, localPrefix)
+			}
+			if got != StoreName(tt.floopDir, globalDir) {
+				t.Error("StoreName() is not stable")
+			}
+		})
+	}
+}
+
+func TestReplicator_ShipsOnlyChanges(t *testing.T) {
+	ctx := context.Background()
+	globalDir := filepath.Join(t.TempDir(), ".floop")
+	target := &countingTarget{DirTarget: &publish.DirTarget{Root: t.TempDir()}}
+	r := New(target, ScopeGlobal, globalDir)
+
+	synced := store.SyncedFiles{Dir: globalDir, Files: map[string][]byte{
+		"nodes.jsonl": []byte("{\"id\":\"b-1\"}\n"),
+		"edges.jsonl": []byte(""),
+	}}
+	sent, err := r.Ship(ctx, synced)
+	if err != nil {
+		t.Fatalf("Ship() error = %v", err)
+	}
+	if len(sent) != 2 {
+		t.Errorf("first Ship() sent %v, want both files", sent)
+	}
+
+	// Unchanged files are not shipped again
+	target.puts = nil
+	if sent, err := r.Ship(ctx, synced); err != nil || sent != nil || len(target.puts) != 0 {
+		t.Errorf("unchanged Ship() = %v, %v with puts %v; want nothing sent", sent, err, target.puts)
+	}
+
+	synced.Files["nodes.jsonl"] = []byte("{\"id\":\"b-1\"}\n{\"id\":\"b-2\"}\n")
+	sent, err = r.Ship(ctx, synced)
+	if err != nil {
+		t.Fatalf("Ship() error = %v", err)
+	}
+	if len(sent) != 1 || sent[0] != "nodes.jsonl" {
+		t.Errorf("Ship() sent %v, want only nodes.jsonl", sent)
+	}
+	if last := target.puts[len(target.puts)-1]; last != "global/manifest.json" {
+		t.Errorf("last put = %q, want the manifest", last)
+	}
+
+	// A new process picks up from the manifest at the target
+	fresh := New(target, ScopeGlobal, globalDir)
+	if sent, err := fresh.Ship(ctx, synced); err != nil || sent != nil {
+		t.Errorf("Ship() after restart = %v, %v; want nothing sent", sent, err)
+	}
+	m, err := ReadManifest(ctx, target, GlobalStore)
+	if err != nil || m == nil {
+		t.Fatalf("ReadManifest() = %v, %v", m, err)
+	}
+	if m.Sequence != 2 || m.Source != globalDir || len(m.Files) != 2 {
+		t.Errorf("manifest = %+v, want sequence 2 of both files from %s", m, globalDir)
+	}
+}
+
+func TestReplicator_ScopeSkipsLocalStores(t *testing.T) {
+	ctx := context.Background()
+	globalDir := filepath.Join(t.TempDir(), ".floop")
+	localDir := store.LocalFloopPath(t.TempDir())
+	synced := store.SyncedFiles{Dir: localDir, Files: map[string][]byte{"nodes.jsonl": []byte("{}\n")}}
+
+	target := &countingTarget{DirTarget: &publish.DirTarget{Root: t.TempDir()}}
+	if sent, err := New(target, ScopeGlobal, globalDir).Ship(ctx, synced); err != nil || sent != nil || len(target.puts) != 0 {
+		t.Errorf("global scope shipped local store: %v, %v", sent, err)
+	}
+	if sent, err := New(target, ScopeAll, globalDir).Ship(ctx, synced); err != nil || len(sent) != 1 {
+		t.Errorf("all scope Ship() = %v, %v; want nodes.jsonl sent", sent, err)
+	}
+}
@@ -0,0 +1,391 @@
+// Package restorepoint snapshots the nodes and edges a destructive operation
+// is about to change, so the operation can be undone with
+// 'floop restore-point apply' even when it is otherwise permanent.
+package restorepoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// DirName is the restore point directory inside a .floop directory.
+const DirName = "restore-points"
+
+// Snapshot holds nodes and the edges touching them, as read from one store.
+type Snapshot struct {
+	// Scope is the store the snapshot was read from: local or global.
+	Scope store.StoreScope `json:"scope"`
+
+	// Full is set when the snapshot holds every node in the store. Applying
+	// a full snapshot also deletes nodes added after it was taken.
+	Full bool `json:"full,omitempty"`
+
+	Nodes []store.Node `json:"nodes"`
+	Edges []store.Edge `json:"edges"`
+
+	// Absent lists the requested IDs the store did not hold. Applying the
+	// snapshot removes them, undoing nodes the operation went on to add.
+	Absent []string `json:"absent,omitempty"`
+}
+
+// Point is a saved set of snapshots taken before one operation.
+type Point struct {
+	ID          string     `json:"id"`
+	Operation   string     `json:"operation"`
+	Description string     `json:"description,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	Snapshots   []Snapshot `json:"snapshots"`
+}
+
+// NodeCount returns the number of nodes across p's snapshots.
+func (p *Point) NodeCount() int {
+	n := 0
+	for _, s := range p.Snapshots {
+		n += len(s.Nodes)
+	}
+	return n
+}
+
+// EdgeCount returns the number of edges across p's snapshots.
+func (p *Point) EdgeCount() int {
+	n := 0
+	for _, s := range p.Snapshots {
+		n += len(s.Edges)
+	}
+	return n
+}
+
+// ApplyResult counts the changes made by Apply.
+type ApplyResult struct {
+	NodesRestored int `json:"nodes_restored"`
+	NodesRemoved  int `json:"nodes_removed"`
+	EdgesRestored int `json:"edges_restored"`
+	EdgesRemoved  int `json:"edges_removed"`
+	EdgesSkipped  int `json:"edges_skipped"`
+}
+
+// Dir returns the restore point directory for a project: the project's
+// .floop directory when it exists, otherwise the global ~/.floop.
+func Dir(root string) (string, error) {
+	local := store.LocalFloopPath(root)
+	if _, err := os.Stat(local); err == nil {
+		return filepath.Join(local, DirName), nil
+	}
+	global, err := store.GlobalFloopPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(global, DirName), nil
+}
+
+// Capture snapshots the nodes in ids, or every node when ids is nil, along
+// with the edges touching them. A MultiGraphStore is captured per underlying
+// store so Apply writes each node back where it came from; IDs missing from a
+// store are recorded as absent from it.
+func Capture(ctx context.Context, graphStore store.GraphStore, ids []string) ([]Snapshot, error) {
+	multi, ok := graphStore.(*store.MultiGraphStore)
+	if !ok {
+		snap, err := CaptureStore(ctx, graphStore, store.ScopeLocal, ids)
+		if err != nil {
+			return nil, err
+		}
+		return []Snapshot{snap}, nil
+	}
+
+	var snaps []Snapshot
+	for _, sub := range []struct {
+		scope store.StoreScope
+		gs    store.GraphStore
+	}{
+		{store.ScopeLocal, multi.LocalStore()},
+		{store.ScopeGlobal, multi.GlobalStore()},
+	} {
+		snap, err := CaptureStore(ctx, sub.gs, sub.scope, ids)
+		if err != nil {
+			return nil, err
+		}
+		if len(snap.Nodes) > 0 || len(snap.Absent) > 0 || snap.Full {
+			snaps = append(snaps, snap)
+		}
+	}
+	return snaps, nil
+}
+
+// CaptureStore snapshots the nodes in ids, or every node when ids is nil,
+// from a single store, labelling the snapshot with scope.
+func CaptureStore(ctx context.Context, graphStore store.GraphStore, scope store.StoreScope, ids []string) (Snapshot, error) {
+	snap := Snapshot{Scope: scope, Full: ids == nil}
+
+	if ids == nil {
+		nodes, err := graphStore.QueryNodes(ctx, map[string]interface{}{})
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("failed to query nodes: %w", err)
+		}
+		snap.Nodes = nodes
+	} else {
+		captured := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			if captured[id] {
+				continue
+			}
+			captured[id] = true
+			node, err := graphStore.GetNode(ctx, id)
+			if err != nil {
+				return Snapshot{}, fmt.Errorf("failed to get node %s: %w", id, err)
+			}
+			if node != nil {
+				snap.Nodes = append(snap.Nodes, *node)
+			} else {
+				snap.Absent = append(snap.Absent, id)
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i := range snap.Nodes {
+		snap.Nodes[i].Origin = ""
+		edges, err := graphStore.GetEdges(ctx, snap.Nodes[i].ID, store.DirectionBoth, "")
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("failed to get edges for %s: %w", snap.Nodes[i].ID, err)
+		}
+		for _, e := range edges {
+			if key := edgeKey(e); !seen[key] {
+				seen[key] = true
+				snap.Edges = append(snap.Edges, e)
+			}
+		}
+	}
+	return snap, nil
+}
+
+// Save writes a restore point for operation to dir and prunes the oldest
+// points beyond constants.MaxRestorePoints.
+func Save(dir, operation, description string, snaps []Snapshot) (*Point, error) {
+	now := time.Now()
+	p := &Point{
+		ID:          fmt.Sprintf("rp-%d", now.UnixNano()),
+		Operation:   operation,
+		Description: description,
+		CreatedAt:   now,
+		Snapshots:   snaps,
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create restore point directory: %w", err)
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode restore point: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, p.ID+".json"), data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write restore point: %w", err)
+	}
+
+	if err := prune(dir, constants.MaxRestorePoints); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// Create captures ids from graphStore and saves them as a restore point in
+// the restore point directory for root. It is the one call destructive
+// commands make before changing anything.
+func Create(ctx context.Context, graphStore store.GraphStore, root, operation, description string, ids []string) (*Point, error) {
+	snaps, err := Capture(ctx, graphStore, ids)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := Dir(root)
+	if err != nil {
+		return nil, err
+	}
+	return Save(dir, operation, description, snaps)
+}
+
+// List returns the restore points in dir, newest first. A missing directory
+// yields no points.
+func List(dir string) ([]Point, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read restore points: %w", err)
+	}
+
+	var points []Point
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		p, err := Load(dir, strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+		points = append(points, *p)
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].CreatedAt.After(points[j].CreatedAt)
+	})
+	return points, nil
+}
+
+// Load reads the restore point with id from dir.
+func Load(dir, id string) (*Point, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.Contains(id, "..") {
+		return nil, fmt.Errorf("invalid restore point id: %q", id)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, id+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("restore point not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to read restore point: %w", err)
+	}
+	var p Point
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to decode restore point %s: %w", id, err)
+	}
+	return &p, nil
+}
+
+// Apply writes p's snapshots back to graphStore. Each node is put back as
+// it was, and edges touching it that the snapshot does not hold are removed.
+// Nodes captured as absent, and for full snapshots any node added since, are
+// removed. With a MultiGraphStore each
+// snapshot goes to the store it was read from.
+func Apply(ctx context.Context, graphStore store.GraphStore, p *Point) (*ApplyResult, error) {
+	result := &ApplyResult{}
+	multi, isMulti := graphStore.(*store.MultiGraphStore)
+
+	for _, snap := range p.Snapshots {
+		target := graphStore
+		if isMulti {
+			switch snap.Scope {
+			case store.ScopeLocal:
+				target = multi.LocalStore()
+			case store.ScopeGlobal:
+				target = multi.GlobalStore()
+			default:
+				return result, fmt.Errorf("restore point %s has a snapshot with unknown scope %q", p.ID, snap.Scope)
+			}
+		}
+		if err := applySnapshot(ctx, target, snap, result); err != nil {
+			return result, err
+		}
+		if err := target.Sync(ctx); err != nil {
+			return result, fmt.Errorf("failed to sync %s store: %w", snap.Scope, err)
+		}
+	}
+	return result, nil
+}
+
+// applySnapshot writes one snapshot back to graphStore.
+func applySnapshot(ctx context.Context, graphStore store.GraphStore, snap Snapshot, result *ApplyResult) error {
+	keep := make(map[string]bool, len(snap.Nodes))
+	for _, n := range snap.Nodes {
+		keep[n.ID] = true
+	}
+
+	if snap.Full {
+		current, err := graphStore.QueryNodes(ctx, map[string]interface{}{})
+		if err != nil {
+			return fmt.Errorf("failed to query nodes: %w", err)
+		}
+		for _, n := range current {
+			if keep[n.ID] {
+				continue
+			}
+			if err := graphStore.DeleteNode(ctx, n.ID); err != nil {
+				return fmt.Errorf("failed to remove node %s: %w", n.ID, err)
+			}
+			result.NodesRemoved++
+		}
+	}
+
+	for _, id := range snap.Absent {
+		existing, err := graphStore.GetNode(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to check node %s: %w", id, err)
+		}
+		if existing == nil {
+			continue
+		}
+		if err := graphStore.DeleteNode(ctx, id); err != nil {
+			return fmt.Errorf("failed to remove node %s: %w", id, err)
+		}
+		result.NodesRemoved++
+	}
+
+	for _, n := range snap.Nodes {
+		existing, err := graphStore.GetNode(ctx, n.ID)
+		if err != nil {
+			return fmt.Errorf("failed to check node %s: %w", n.ID, err)
+		}
+		if existing != nil {
+			err = graphStore.UpdateNode(ctx, n)
+		} else {
+			_, err = graphStore.AddNode(ctx, n)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to restore node %s: %w", n.ID, err)
+		}
+		result.NodesRestored++
+	}
+
+	want := make(map[string]bool, len(snap.Edges))
+	for _, e := range snap.Edges {
+		want[edgeKey(e)] = true
+	}
+	for _, n := range snap.Nodes {
+		edges, err := graphStore.GetEdges(ctx, n.ID, store.DirectionBoth, "")
+		if err != nil {
+			return fmt.Errorf("failed to get edges for %s: %w", n.ID, err)
+		}
+		for _, e := range edges {
+			if want[edgeKey(e)] {
+				continue
+			}
+			if err := graphStore.RemoveEdge(ctx, e.Source, e.Target, e.Kind); err != nil {
+				return fmt.Errorf("failed to remove edge %s->%s: %w", e.Source, e.Target, err)
+			}
+			result.EdgesRemoved++
+		}
+	}
+
+	// An edge whose other endpoint has been removed since is skipped
+	rejected, err := store.BatchAddEdges(ctx, graphStore, snap.Edges)
+	if err != nil {
+		return fmt.Errorf("failed to restore edges: %w", err)
+	}
+	result.EdgesSkipped += len(rejected)
+	result.EdgesRestored += len(snap.Edges) - len(rejected)
+	return nil
+}
+
+// prune removes the oldest restore points in dir beyond keep.
+func prune(dir string, keep int) error {
+	points, err := List(dir)
+	if err != nil {
+		return err
+	}
+	for i := keep; i < len(points); i++ {
+		if err := os.Remove(filepath.Join(dir, points[i].ID+".json")); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove old restore point %s: %w", points[i].ID, err)
+		}
+	}
+	return nil
+}
+
+func edgeKey(e store.Edge) string {
+	return fmt.Sprintf("%s:%s:%s", e.Source, e.Target, e.Kind)
+}
@@ -0,0 +1,187 @@
+package restorepoint
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func addNode(t *testing.T, s store.GraphStore, id string) {
+	t.Helper()
+	node := store.Node{
+		ID:      id,
+		Kind:    store.NodeKindBehavior,
+		Content: map[string]interface{}{"name": id, "canonical": "content of " + id},
+	}
+	if _, err := s.AddNode(context.Background(), node); err != nil {
+		t.Fatalf("AddNode(%s) error = %v", id, err)
+	}
+}
+
+func addEdge(t *testing.T, s store.GraphStore, source, target string) {
+	t.Helper()
+	edge := store.Edge{Source: source, Target: target, Kind: store.EdgeKindSimilarTo, Weight: 0.8, CreatedAt: time.Now()}
+	if err := s.AddEdge(context.Background(), edge); err != nil {
+		t.Fatalf("AddEdge(%s->%s) error = %v", source, target, err)
+	}
+}
+
+func edgeCount(t *testing.T, s store.GraphStore, id string) int {
+	t.Helper()
+	edges, err := s.GetEdges(context.Background(), id, store.DirectionBoth, "")
+	if err != nil {
+		t.Fatalf("GetEdges(%s) error = %v", id, err)
+	}
+	return len(edges)
+}
+
+func TestCaptureAndApply(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+	for _, id := range []string{"a", "b", "c"} {
+		addNode(t, s, id)
+	}
+	addEdge(t, s, "c", "b")
+
+	snaps, err := Capture(ctx, s, []string{"a", "b", "b"})
+	if err != nil {
+		t.Fatalf("Capture() error = %v", err)
+	}
+	p := &Point{ID: "rp-test", Snapshots: snaps}
+	if p.NodeCount() != 2 || p.EdgeCount() != 1 {
+		t.Fatalf("captured %d nodes, %d edges; want 2, 1", p.NodeCount(), p.EdgeCount())
+	}
+
+	// Simulate a merge of b into a: b is marked merged, its edge redirected.
+	node, _ := s.GetNode(ctx, "b")
+	node.Kind = store.NodeKindMerged
+	if err := s.UpdateNode(ctx, *node); err != nil {
+		t.Fatalf("UpdateNode() error = %v", err)
+	}
+	if err := s.RemoveEdge(ctx, "c", "b", store.EdgeKindSimilarTo); err != nil {
+		t.Fatalf("RemoveEdge() error = %v", err)
+	}
+	addEdge(t, s, "c", "a")
+	if err := s.DeleteNode(ctx, "a"); err != nil {
+		t.Fatalf("DeleteNode() error = %v", err)
+	}
+
+	result, err := Apply(ctx, s, p)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if result.NodesRestored != 2 || result.EdgesRestored != 1 {
+		t.Errorf("Apply() = %+v, want 2 nodes and 1 edge restored", result)
+	}
+
+	if node, _ := s.GetNode(ctx, "a"); node == nil {
+		t.Error("deleted node a was not restored")
+	}
+	if node, _ := s.GetNode(ctx, "b"); node.Kind != store.NodeKindBehavior {
+		t.Errorf("b kind = %s, want %s", node.Kind, store.NodeKindBehavior)
+	}
+	if got := edgeCount(t, s, "a"); got != 0 {
+		t.Errorf("a has %d edges, want the redirected edge removed", got)
+	}
+	if got := edgeCount(t, s, "b"); got != 1 {
+		t.Errorf("b has %d edges, want its original edge back", got)
+	}
+}
+
+func TestApply_FullSnapshotRemovesNewNodes(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+	addNode(t, s, "a")
+
+	snaps, err := Capture(ctx, s, nil)
+	if err != nil {
+		t.Fatalf("Capture() error = %v", err)
+	}
+	if !snaps[0].Full {
+		t.Fatal("Capture(nil) should take a full snapshot")
+	}
+
+	addNode(t, s, "added")
+	result, err := Apply(ctx, s, &Point{ID: "rp-test", Snapshots: snaps})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if result.NodesRemoved != 1 {
+		t.Errorf("NodesRemoved = %d, want 1", result.NodesRemoved)
+	}
+	if node, _ := s.GetNode(ctx, "added"); node != nil {
+		t.Error("node added after a full snapshot should be removed")
+	}
+}
+
+func TestApply_RemovesAbsentNodes(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+	addNode(t, s, "a")
+
+	snaps, err := Capture(ctx, s, []string{"a", "copy"})
+	if err != nil {
+		t.Fatalf("Capture() error = %v", err)
+	}
+	if len(snaps[0].Absent) != 1 || snaps[0].Absent[0] != "copy" {
+		t.Fatalf("Absent = %v, want [copy]", snaps[0].Absent)
+	}
+
+	addNode(t, s, "copy")
+	addNode(t, s, "other")
+	result, err := Apply(ctx, s, &Point{ID: "rp-test", Snapshots: snaps})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if result.NodesRemoved != 1 || result.NodesRestored != 1 {
+		t.Errorf("Apply() = %+v, want 1 node restored and 1 removed", result)
+	}
+	if node, _ := s.GetNode(ctx, "copy"); node != nil {
+		t.Error("node captured as absent should be removed")
+	}
+	if node, _ := s.GetNode(ctx, "other"); node == nil {
+		t.Error("node not captured should be left alone")
+	}
+}
+
+func TestSaveListLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), DirName)
+
+	var last *Point
+	for i := 0; i < constants.MaxRestorePoints+2; i++ {
+		p, err := Save(dir, "merge", "test", []Snapshot{{Scope: store.ScopeLocal, Nodes: []store.Node{{ID: "a"}}}})
+		if err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		last = p
+	}
+
+	points, err := List(dir)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(points) != constants.MaxRestorePoints {
+		t.Errorf("List() = %d points, want pruned to %d", len(points), constants.MaxRestorePoints)
+	}
+	if points[0].ID != last.ID {
+		t.Errorf("List()[0] = %s, want newest %s", points[0].ID, last.ID)
+	}
+
+	p, err := Load(dir, last.ID)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if p.Operation != "merge" || p.NodeCount() != 1 {
+		t.Errorf("Load() = %+v, want the saved merge point", p)
+	}
+
+	for _, id := range []string{"", "../escape", "rp-missing"} {
+		if _, err := Load(dir, id); err == nil {
+			t.Errorf("Load(%q) should fail", id)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+// Package retirement is a gentler alternative to forgetting. A retired
+// behavior stops being injected but stays under watch for a grace period:
+// if a new correction recurs on its topic, it is restored automatically
+// with a note; if none does, it is forgotten when the grace period ends.
+package retirement
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/similarity"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// NoticesFile holds retirement notices not yet shown in a digest, relative
+// to the .floop directory.
+const NoticesFile = "retirement_notices.jsonl"
+
+// Actor is recorded as restored_by or forgotten_by when retirement acts on
+// a behavior automatically.
+const Actor = "floop-retirement"
+
+// Outcome is how a retirement ended.
+type Outcome string
+
+const (
+	// OutcomeRestored means a correction recurred on the behavior's topic
+	// during the grace period and it was restored.
+	OutcomeRestored Outcome = "restored"
+
+	// OutcomeForgotten means the grace period ended without a recurrence
+	// and the behavior was forgotten.
+	OutcomeForgotten Outcome = "forgotten"
+)
+
+// Notice records the end of a behavior's retirement.
+type Notice struct {
+	BehaviorID string    `json:"behavior_id"`
+	Name       string    `json:"name"`
+	Canonical  string    `json:"canonical"`
+	Outcome    Outcome   `json:"outcome"`
+	RetiredAt  time.Time `json:"retired_at"`
+	At         time.Time `json:"at"`
+	Note       string    `json:"note,omitempty"`
+}
+
+// Retire marks node as retired until now+grace. The caller writes the node
+// back to the store.
+func Retire(node *store.Node, reason string, grace time.Duration, now time.Time) {
+	if node.Metadata == nil {
+		node.Metadata = make(map[string]interface{})
+	}
+	node.Metadata["original_kind"] = string(node.Kind)
+	node.Metadata["retired_at"] = now.UTC().Format(time.RFC3339)
+	node.Metadata["retired_by"] = os.Getenv("USER")
+	node.Metadata["retired_until"] = now.Add(grace).UTC().Format(time.RFC3339)
+	if reason != "" {
+		node.Metadata["retire_reason"] = reason
+	}
+	node.Kind = store.NodeKindRetired
+}
+
+// GraceEnd returns when node's retirement grace period ends.
+func GraceEnd(node store.Node) (time.Time, bool) {
+	return metadataTime(node, "retired_until")
+}
+
+// ClearMetadata removes the retirement metadata from node, for callers
+// restoring it by hand.
+func ClearMetadata(node *store.Node) {
+	delete(node.Metadata, "retired_at")
+	delete(node.Metadata, "retired_by")
+	delete(node.Metadata, "retired_until")
+	delete(node.Metadata, "retire_reason")
+}
+
+// Retired returns the retired behaviors in graphStore.
+func Retired(ctx context.Context, graphStore store.GraphStore) ([]store.Node, error) {
+	nodes, err := graphStore.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindRetired)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query retired behaviors: %w", err)
+	}
+	return nodes, nil
+}
+
+// RestoreRecurring restores every behavior still in its grace period whose
+// topic candidate recurs on, as measured by the rule-based similarity the
+// learning loop uses for placement. Each restored behavior records a
+// restore_note naming the recurring correction. Returns a notice per
+// restored behavior.
+func RestoreRecurring(ctx context.Context, graphStore store.GraphStore, candidate *models.Behavior, now time.Time) ([]Notice, error) {
+	retired, err := Retired(ctx, graphStore)
+	if err != nil {
+		return nil, err
+	}
+
+	var notices []Notice
+	for _, node := range retired {
+		end, ok := GraceEnd(node)
+		if !ok || !now.Before(end) {
+			continue
+		}
+		b := models.NodeToBehavior(node)
+		if topicSimilarity(candidate, &b) < constants.RetirementRecurrenceThreshold {
+			continue
+		}
+
+		retiredAt, _ := metadataTime(node, "retired_at")
+		note := fmt.Sprintf("Restored automatically on %s: a correction recurred on its topic during the retirement grace period (%q).",
+			now.Format("2006-01-02"), candidate.Content.Canonical)
+
+		node.Kind = originalKind(node)
+		node.Metadata["restored_at"] = now.UTC().Format(time.RFC3339)
+		node.Metadata["restored_by"] = Actor
+		node.Metadata["restore_note"] = note
+		delete(node.Metadata, "original_kind")
+		ClearMetadata(&node)
+
+		if err := graphStore.UpdateNode(ctx, node); err != nil {
+			return notices, fmt.Errorf("failed to restore behavior %s: %w", node.ID, err)
+		}
+		notices = append(notices, Notice{
+			BehaviorID: b.ID,
+			Name:       b.Name,
+			Canonical:  b.Content.Canonical,
+			Outcome:    OutcomeRestored,
+			RetiredAt:  retiredAt,
+			At:         now,
+			Note:       note,
+		})
+	}
+
+	if len(notices) > 0 {
+		if err := graphStore.Sync(ctx); err != nil {
+			return notices, fmt.Errorf("failed to sync store: %w", err)
+		}
+	}
+	return notices, nil
+}
+
+// Sweep forgets every retired behavior whose grace period ended at or
+// before now, using the same metadata as 'floop forget' so 'floop restore'
+// can undo it. With dryRun set nothing is changed. Returns a notice per
+// forgotten behavior.
+func Sweep(ctx context.Context, graphStore store.GraphStore, now time.Time, dryRun bool) ([]Notice, error) {
+	retired, err := Retired(ctx, graphStore)
+	if err != nil {
+		return nil, err
+	}
+
+	var notices []Notice
+	for _, node := range retired {
+		end, ok := GraceEnd(node)
+		if !ok || now.Before(end) {
+			continue
+		}
+		b := models.NodeToBehavior(node)
+		retiredAt, _ := metadataTime(node, "retired_at")
+		notice := Notice{
+			BehaviorID: b.ID,
+			Name:       b.Name,
+			Canonical:  b.Content.Canonical,
+			Outcome:    OutcomeForgotten,
+			RetiredAt:  retiredAt,
+			At:         now,
+		}
+		if dryRun {
+			notices = append(notices, notice)
+			continue
+		}
+
+		reason := fmt.Sprintf("retired on %s; no corrections recurred before %s",
+			retiredAt.Format("2006-01-02"), end.Format("2006-01-02"))
+		if r, ok := node.Metadata["retire_reason"].(string); ok && r != "" {
+			reason = r + " (" + reason + ")"
+		}
+		node.Metadata["forgotten_at"] = now.UTC().Format(time.RFC3339)
+		node.Metadata["forgotten_by"] = Actor
+		node.Metadata["forget_reason"] = reason
+		ClearMetadata(&node)
+		node.Kind = store.NodeKindForgotten
+
+		if err := graphStore.UpdateNode(ctx, node); err != nil {
+			return notices, fmt.Errorf("failed to forget behavior %s: %w", node.ID, err)
+		}
+		notices = append(notices, notice)
+	}
+
+	if !dryRun && len(notices) > 0 {
+		if err := graphStore.Sync(ctx); err != nil {
+			return notices, fmt.Errorf("failed to sync store: %w", err)
+		}
+	}
+	return notices, nil
+}
+
+// RecordNotices appends notices to the pending notices in floopDir.
+func RecordNotices(floopDir string, notices []Notice) error {
+	if len(notices) == 0 {
+		return nil
+	}
+
+	path := filepath.Join(floopDir, NoticesFile)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open retirement notices: %w", err)
+	}
+	defer f.Close()
+
+	encoder := json.NewEncoder(f)
+	for _, n := range notices {
+		if err := encoder.Encode(n); err != nil {
+			return fmt.Errorf("failed to write retirement notice: %w", err)
+		}
+	}
+	return nil
+}
+
+// TakeNotices returns the pending notices in floopDir, oldest first, and
+// clears them, so each notice appears in exactly one digest. A missing file
+// yields no notices.
+func TakeNotices(floopDir string) ([]Notice, error) {
+	path := filepath.Join(floopDir, NoticesFile)
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open retirement notices: %w", err)
+	}
+
+	var notices []Notice
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var n Notice
+		if err := json.Unmarshal(line, &n); err != nil {
+			continue
+		}
+		notices = append(notices, n)
+	}
+	f.Close()
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read retirement notices: %w", err)
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to clear retirement notices: %w", err)
+	}
+	return notices, nil
+}
+
+// FormatDigest renders notices as a short digest section, or "" if there are
+// none.
+func FormatDigest(notices []Notice) string {
+	if len(notices) == 0 {
+		return ""
+	}
+	var restored, forgotten []Notice
+	for _, n := range notices {
+		if n.Outcome == OutcomeRestored {
+			restored = append(restored, n)
+		} else {
+			forgotten = append(forgotten, n)
+		}
+	}
+
+	var sb strings.Builder
+	if len(restored) > 0 {
+		sb.WriteString(fmt.Sprintf("Retired behaviors restored (%d):\n", len(restored)))
+		for _, n := range restored {
+			sb.WriteString(fmt.Sprintf("- %s (%s): %s\n", n.Name, n.BehaviorID, n.Canonical))
+		}
+		sb.WriteString("A correction recurred on their topic during the grace period.\n")
+	}
+	if len(forgotten) > 0 {
+		sb.WriteString(fmt.Sprintf("Retired behaviors forgotten after their grace period (%d):\n", len(forgotten)))
+		for _, n := range forgotten {
+			sb.WriteString(fmt.Sprintf("- %s (%s, retired %s): %s\n",
+				n.Name, n.BehaviorID, n.RetiredAt.Format("2006-01-02"), n.Canonical))
+		}
+		sb.WriteString("Run 'floop restore <id>' to bring one back.\n")
+	}
+	return sb.String()
+}
+
+// topicSimilarity scores how closely candidate's topic matches b's, using
+// when-condition overlap, canonical content, and tags.
+func topicSimilarity(candidate, b *models.Behavior) float64 {
+	return similarity.WeightedScoreWithTags(
+		similarity.ComputeWhenOverlap(candidate.When, b.When),
+		similarity.ComputeContentSimilarity(candidate.Content.Canonical, b.Content.Canonical),
+		similarity.ComputeTagSimilarity(candidate.Content.Tags, b.Content.Tags),
+	)
+}
+
+// originalKind returns the kind node had before it was retired.
+func originalKind(node store.Node) store.NodeKind {
+	if k, ok := node.Metadata["original_kind"].(string); ok && k != "" {
+		return store.NodeKind(k)
+	}
+	return store.NodeKindBehavior
+}
+
+// metadataTime parses an RFC3339 timestamp from node metadata.
+func metadataTime(node store.Node, key string) (time.Time, bool) {
+	s, ok := node.Metadata[key].(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,170 @@
+package retirement
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func addRetired(t *testing.T, s store.GraphStore, id, canonical string, tags []string, retiredAt time.Time, grace time.Duration) {
+	t.Helper()
+	b := models.Behavior{
+		ID:      id,
+		Name:    id,
+		Kind:    models.BehaviorKindDirective,
+		When:    map[string]interface{}{"language": "go"},
+		Content: models.BehaviorContent{Canonical: canonical, Tags: tags},
+	}
+	node := models.BehaviorToNode(&b)
+	Retire(&node, "", grace, retiredAt)
+	if _, err := s.AddNode(context.Background(), node); err != nil {
+		t.Fatalf("AddNode(%s) error = %v", id, err)
+	}
+}
+
+func TestRetire(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	node := store.Node{ID: "b1", Kind: store.NodeKindBehavior}
+
+	Retire(&node, "superseded", 14*24*time.Hour, now)
+
+	if node.Kind != store.NodeKindRetired {
+		t.Errorf("Kind = %s, want %s", node.Kind, store.NodeKindRetired)
+	}
+	if got := originalKind(node); got != store.NodeKindBehavior {
+		t.Errorf("originalKind = %s, want %s", got, store.NodeKindBehavior)
+	}
+	end, ok := GraceEnd(node)
+	if !ok || !end.Equal(now.AddDate(0, 0, 14)) {
+		t.Errorf("GraceEnd = %v, %v; want %v", end, ok, now.AddDate(0, 0, 14))
+	}
+	if node.Metadata["retire_reason"] != "superseded" {
+		t.Errorf("retire_reason = %v, want superseded", node.Metadata["retire_reason"])
+	}
+
+	ClearMetadata(&node)
+	if _, ok := GraceEnd(node); ok {
+		t.Error("GraceEnd should be unset after ClearMetadata")
+	}
+}
+
+func TestRestoreRecurring(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	grace := 14 * 24 * time.Hour
+
+	s := store.NewInMemoryGraphStore()
+	addRetired(t, s, "on-topic", "Wrap errors with fmt.Errorf and %w", []string{"errors"}, now.AddDate(0, 0, -3), grace)
+	addRetired(t, s, "off-topic", "Prefer table-driven tests", []string{"testing"}, now.AddDate(0, 0, -3), grace)
+	addRetired(t, s, "lapsed", "Wrap errors using fmt.Errorf with %w", []string{"errors"}, now.AddDate(0, 0, -20), grace)
+
+	candidate := &models.Behavior{
+		When:    map[string]interface{}{"language": "go"},
+		Content: models.BehaviorContent{Canonical: "Wrap errors with fmt.Errorf and %w verb", Tags: []string{"errors"}},
+	}
+
+	notices, err := RestoreRecurring(ctx, s, candidate, now)
+	if err != nil {
+		t.Fatalf("RestoreRecurring() error = %v", err)
+	}
+	if len(notices) != 1 || notices[0].BehaviorID != "on-topic" {
+		t.Fatalf("notices = %+v, want only on-topic", notices)
+	}
+	if notices[0].Outcome != OutcomeRestored || notices[0].Note == "" {
+		t.Errorf("notice = %+v, want restored with a note", notices[0])
+	}
+
+	node, _ := s.GetNode(ctx, "on-topic")
+	if node.Kind != store.NodeKindBehavior {
+		t.Errorf("on-topic kind = %s, want %s", node.Kind, store.NodeKindBehavior)
+	}
+	if node.Metadata["restored_by"] != Actor || node.Metadata["restore_note"] == nil {
+		t.Errorf("on-topic metadata = %v, want restored_by and restore_note", node.Metadata)
+	}
+	if _, ok := node.Metadata["retired_until"]; ok {
+		t.Error("retirement metadata should be cleared on restore")
+	}
+
+	for _, id := range []string{"off-topic", "lapsed"} {
+		if node, _ := s.GetNode(ctx, id); node.Kind != store.NodeKindRetired {
+			t.Errorf("%s kind = %s, want still retired", id, node.Kind)
+		}
+	}
+}
+
+func TestSweep(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	grace := 14 * 24 * time.Hour
+
+	s := store.NewInMemoryGraphStore()
+	addRetired(t, s, "lapsed", "Old advice", nil, now.AddDate(0, 0, -15), grace)
+	addRetired(t, s, "pending", "Recent advice", nil, now.AddDate(0, 0, -1), grace)
+
+	notices, err := Sweep(ctx, s, now, true)
+	if err != nil {
+		t.Fatalf("Sweep(dryRun) error = %v", err)
+	}
+	if len(notices) != 1 || notices[0].BehaviorID != "lapsed" {
+		t.Fatalf("Sweep(dryRun) notices = %+v, want only lapsed", notices)
+	}
+	if node, _ := s.GetNode(ctx, "lapsed"); node.Kind != store.NodeKindRetired {
+		t.Fatalf("dry run changed kind to %s", node.Kind)
+	}
+
+	notices, err = Sweep(ctx, s, now, false)
+	if err != nil {
+		t.Fatalf("Sweep() error = %v", err)
+	}
+	if len(notices) != 1 || notices[0].Outcome != OutcomeForgotten {
+		t.Fatalf("Sweep() notices = %+v, want one forgotten", notices)
+	}
+
+	node, _ := s.GetNode(ctx, "lapsed")
+	if node.Kind != store.NodeKindForgotten {
+		t.Errorf("lapsed kind = %s, want %s", node.Kind, store.NodeKindForgotten)
+	}
+	if node.Metadata["forgotten_by"] != Actor || node.Metadata["original_kind"] != string(store.NodeKindBehavior) {
+		t.Errorf("lapsed metadata = %v, want forgotten_by and original_kind for restore", node.Metadata)
+	}
+	if node, _ := s.GetNode(ctx, "pending"); node.Kind != store.NodeKindRetired {
+		t.Errorf("pending kind = %s, want still retired", node.Kind)
+	}
+}
+
+func TestNotices_RecordAndTake(t *testing.T) {
+	dir := t.TempDir()
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := RecordNotices(dir, []Notice{
+		{BehaviorID: "a", Name: "alpha", Canonical: "Do A", Outcome: OutcomeRestored, At: at},
+		{BehaviorID: "b", Name: "beta", Canonical: "Do B", Outcome: OutcomeForgotten, RetiredAt: at.AddDate(0, 0, -14), At: at},
+	}); err != nil {
+		t.Fatalf("RecordNotices() error = %v", err)
+	}
+
+	notices, err := TakeNotices(dir)
+	if err != nil {
+		t.Fatalf("TakeNotices() error = %v", err)
+	}
+	if len(notices) != 2 {
+		t.Fatalf("TakeNotices() = %d notices, want 2", len(notices))
+	}
+	if again, _ := TakeNotices(dir); len(again) != 0 {
+		t.Errorf("second TakeNotices() = %d notices, want 0", len(again))
+	}
+
+	digest := FormatDigest(notices)
+	for _, want := range []string{"restored (1)", "alpha (a)", "forgotten after their grace period (1)", "beta (b, retired 2026-02-15)", "floop restore"} {
+		if !strings.Contains(digest, want) {
+			t.Errorf("digest missing %q:\n%s", want, digest)
+		}
+	}
+	if FormatDigest(nil) != "" {
+		t.Error("FormatDigest(nil) should be empty")
+	}
+}
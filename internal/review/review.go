@@ -0,0 +1,126 @@
+// Package review keeps the queue of behaviors the learning loop flagged for
+// human review: constraints, low-confidence placements, near-duplicates, and
+// would-be merges. A flagged behavior stays active; the flag records why it
+// was flagged until someone accepts, edits, rejects, or merges it with
+// 'floop review'.
+package review
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Metadata keys recorded on behaviors pending review.
+const (
+	metaRequestedAt = "review_requested_at"
+	metaReasons     = "review_reasons"
+	metaMergeTarget = "review_merge_target"
+)
+
+// Entry describes a behavior pending review.
+type Entry struct {
+	BehaviorID  string                 `json:"behavior_id"`
+	Name        string                 `json:"name"`
+	Kind        string                 `json:"kind"`
+	Canonical   string                 `json:"canonical"`
+	When        map[string]interface{} `json:"when,omitempty"`
+	Origin      store.Origin           `json:"origin,omitempty"`
+	Reasons     []string               `json:"reasons"`
+	MergeTarget string                 `json:"merge_target,omitempty"`
+	RequestedAt time.Time              `json:"requested_at"`
+}
+
+// Mark flags node for review for reasons. mergeTarget, if set, is the
+// existing behavior the node most likely duplicates, offered as the default
+// merge target. The caller writes the node to the store.
+func Mark(node *store.Node, reasons []string, mergeTarget string, now time.Time) {
+	if node.Metadata == nil {
+		node.Metadata = make(map[string]interface{})
+	}
+	node.Metadata[metaRequestedAt] = now.UTC().Format(time.RFC3339)
+	node.Metadata[metaReasons] = append([]string(nil), reasons...)
+	if mergeTarget != "" {
+		node.Metadata[metaMergeTarget] = mergeTarget
+	}
+}
+
+// IsPending reports whether node is an active behavior waiting for review.
+func IsPending(node store.Node) bool {
+	if node.Kind != store.NodeKindBehavior {
+		return false
+	}
+	_, ok := node.Metadata[metaRequestedAt].(string)
+	return ok
+}
+
+// Clear removes the review flag from node, for callers that resolved it.
+func Clear(node *store.Node) {
+	delete(node.Metadata, metaRequestedAt)
+	delete(node.Metadata, metaReasons)
+	delete(node.Metadata, metaMergeTarget)
+}
+
+// NewEntry describes a pending node.
+func NewEntry(node store.Node) Entry {
+	b := models.NodeToBehavior(node)
+	entry := Entry{
+		BehaviorID: node.ID,
+		Name:       b.Name,
+		Kind:       string(b.Kind),
+		Canonical:  b.Content.Canonical,
+		When:       b.When,
+		Origin:     node.Origin,
+		Reasons:    reasonsOf(node),
+	}
+	entry.MergeTarget, _ = node.Metadata[metaMergeTarget].(string)
+	if s, ok := node.Metadata[metaRequestedAt].(string); ok {
+		entry.RequestedAt, _ = time.Parse(time.RFC3339, s)
+	}
+	return entry
+}
+
+// reasonsOf returns the recorded review reasons, which come back from the
+// store as []interface{}.
+func reasonsOf(node store.Node) []string {
+	switch v := node.Metadata[metaReasons].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		reasons := make([]string, 0, len(v))
+		for _, r := range v {
+			if s, ok := r.(string); ok {
+				reasons = append(reasons, s)
+			}
+		}
+		return reasons
+	}
+	return []string{}
+}
+
+// Pending returns the behaviors in graphStore waiting for review, oldest
+// first.
+func Pending(ctx context.Context, graphStore store.GraphStore) ([]Entry, error) {
+	nodes, err := graphStore.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query behaviors: %w", err)
+	}
+
+	entries := []Entry{}
+	for _, node := range nodes {
+		if IsPending(node) {
+			entries = append(entries, NewEntry(node))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].RequestedAt.Equal(entries[j].RequestedAt) {
+			return entries[i].RequestedAt.Before(entries[j].RequestedAt)
+		}
+		return entries[i].BehaviorID < entries[j].BehaviorID
+	})
+	return entries, nil
+}
@@ -0,0 +1,84 @@
+package review
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func reviewTestNode(id string) store.Node {
+	return store.Node{
+		ID:   id,
+		Kind: store.NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    id,
+			"kind":    "constraint",
+			"content": map[string]interface{}{"canonical": id + " canonical"},
+			"when":    map[string]interface{}{"language": "go"},
+		},
+		Metadata: map[string]interface{}{"confidence": 0.6},
+	}
+}
+
+func TestPending(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	newer := reviewTestNode("b-newer")
+	Mark(&newer, []string{"Constraints require human review"}, "", now)
+	older := reviewTestNode("b-older")
+	Mark(&older, []string{"Very similar to existing: b-x (0.95)"}, "b-x", now.Add(-time.Hour))
+	resolved := reviewTestNode("b-resolved")
+	Mark(&resolved, []string{"Low placement confidence: 0.40"}, "", now)
+	Clear(&resolved)
+	forgotten := reviewTestNode("b-forgotten")
+	Mark(&forgotten, []string{"Low placement confidence: 0.40"}, "", now)
+	forgotten.Kind = store.NodeKindForgotten
+
+	for _, n := range []store.Node{newer, older, resolved, forgotten, reviewTestNode("b-plain")} {
+		if _, err := s.AddNode(ctx, n); err != nil {
+			t.Fatalf("AddNode(%s) error = %v", n.ID, err)
+		}
+	}
+
+	pending, err := Pending(ctx, s)
+	if err != nil {
+		t.Fatalf("Pending() error = %v", err)
+	}
+	if len(pending) != 2 || pending[0].BehaviorID != "b-older" || pending[1].BehaviorID != "b-newer" {
+		t.Fatalf("Pending() = %+v, want b-older then b-newer", pending)
+	}
+	got := pending[0]
+	if got.MergeTarget != "b-x" || len(got.Reasons) != 1 || !got.RequestedAt.Equal(now.Add(-time.Hour)) {
+		t.Errorf("entry = %+v", got)
+	}
+	if got.Kind != "constraint" || got.Canonical != "b-older canonical" || got.When["language"] != "go" {
+		t.Errorf("entry behavior fields = %+v", got)
+	}
+}
+
+func TestReasonsOf(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  int
+	}{
+		{"string slice", []string{"a", "b"}, 2},
+		{"decoded JSON", []interface{}{"a", 1, "b"}, 2},
+		{"missing", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := store.Node{Metadata: map[string]interface{}{}}
+			if tt.value != nil {
+				node.Metadata[metaReasons] = tt.value
+			}
+			if got := reasonsOf(node); len(got) != tt.want {
+				t.Errorf("reasonsOf() = %v, want %d reasons", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package sanitize
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Injection rules reported by AnalyzeInjection.
+const (
+	// RuleInstructionOverride matches attempts to cancel earlier instructions
+	// ("ignore all previous instructions").
+	RuleInstructionOverride = "instruction-override"
+
+	// RuleRoleHijack matches attempts to give the agent a new identity or
+	// mode ("you are now", "developer mode").
+	RuleRoleHijack = "role-hijack"
+
+	// RulePromptExtraction matches requests to reveal the system prompt or
+	// hidden instructions.
+	RulePromptExtraction = "prompt-extraction"
+
+	// RuleFakeRoleMarker matches chat-template delimiters and role prefixes
+	// that try to open a new system or assistant turn.
+	RuleFakeRoleMarker = "fake-role-marker"
+
+	// RuleExfiltration matches instructions to send secrets or credentials
+	// somewhere.
+	RuleExfiltration = "exfiltration"
+
+	// RuleInvisibleChars matches zero-width characters, bidirectional
+	// overrides, and Unicode tag characters that hide text from a reviewer.
+	RuleInvisibleChars = "invisible-characters"
+
+	// RuleMixedScript matches words mixing Latin letters with Cyrillic or
+	// Greek lookalikes, a way to slip keywords past filters.
+	RuleMixedScript = "mixed-script"
+)
+
+// InjectionFinding is one match of an injection rule.
+type InjectionFinding struct {
+	Rule    string `json:"rule"`
+	Excerpt string `json:"excerpt"`
+}
+
+// InjectionReport is the result of analyzing text for prompt injection.
+type InjectionReport struct {
+	Findings []InjectionFinding `json:"findings,omitempty"`
+}
+
+// Suspicious reports whether any rule matched.
+func (r InjectionReport) Suspicious() bool {
+	return len(r.Findings) > 0
+}
+
+// Rules returns the distinct rules that matched, in match order.
+func (r InjectionReport) Rules() []string {
+	var rules []string
+	seen := make(map[string]bool)
+	for _, f := range r.Findings {
+		if !seen[f.Rule] {
+			seen[f.Rule] = true
+			rules = append(rules, f.Rule)
+		}
+	}
+	return rules
+}
+
+// maxExcerptLength bounds the excerpt stored with a finding.
+const maxExcerptLength = 80
+
+// injectionPatterns are matched against normalized text: invisible
+// characters removed, lookalikes folded to Latin, lowercased, and
+// whitespace collapsed.
+var injectionPatterns = []struct {
+	rule string
+	re   *regexp.Regexp
+}{
+	{RuleInstructionOverride, regexp.MustCompile(`\b(?:ignore|disregard|forget|override|bypass|skip)\s+(?:all\s+|any\s+|the\s+|your\s+|my\s+|every\s+)*(?:previous|prior|above|earlier|preceding|other|existing|system|original)\s+(?:instructions?|rules?|prompts?|directions?|guidelines?|guidance|context|messages?|behaviors?)\b`)},
+	{RuleInstructionOverride, regexp.MustCompile(`\b(?:ignore|disregard|forget)\s+(?:(?:any|all|and|of|everything|anything|that|was|said)\s+)+(?:the\s+)?above\b`)},
+	{RuleInstructionOverride, regexp.MustCompile(`\b(?:new|updated|real|actual)\s+(?:system\s+)?instructions?\s*:`)},
+	{RuleInstructionOverride, regexp.MustCompile(`\bdo\s+not\s+follow\s+(?:any\s+|the\s+|your\s+)*(?:previous|prior|above|other|system)\s+(?:instructions?|rules?|behaviors?)\b`)},
+	{RuleRoleHijack, regexp.MustCompile(`\byou\s+are\s+(?:now|no\s+longer)\b`)},
+	{RuleRoleHijack, regexp.MustCompile(`\bfrom\s+now\s+on,?\s+you\s+(?:are|will|must)\b`)},
+	{RuleRoleHijack, regexp.MustCompile(`\b(?:developer|god|jailbreak|dan|unrestricted)\s+mode\b`)},
+	{RuleRoleHijack, regexp.MustCompile(`\bpretend\s+(?:to\s+be|you\s+are)\b`)},
+	{RulePromptExtraction, regexp.MustCompile(`\b(?:reveal|print|show|output|repeat|leak|dump|display)\s+(?:me\s+)?(?:your|the)\s+(?:full\s+|entire\s+|original\s+|hidden\s+)*(?:system\s+prompt|instructions|initial\s+prompt|hidden\s+instructions)\b`)},
+	{RuleFakeRoleMarker, regexp.MustCompile(`<\|[a-z_]+\|>|\[/?inst\]|<<\s*/?sys\s*>>`)},
+	{RuleFakeRoleMarker, regexp.MustCompile(`(?m)^\s*(?:system|assistant)\s*:`)},
+	{RuleExfiltration, regexp.MustCompile(`\b(?:send|post|upload|exfiltrate|forward|email|transmit|leak)\s+(?:\S+\s+){0,4}?(?:secrets?|credentials?|passwords?|tokens?|api\s+keys?|private\s+keys?|ssh\s+keys?|env(?:ironment)?\s+variables?|\.env)\b(?:\s+\S+){0,6}?\s+to\s+(?:https?://|\S+@|\S+\.\S+)`)},
+}
+
+// AnalyzeInjection checks text for prompt-injection content: instruction
+// overrides, role hijacks, prompt extraction, fake chat-template markers,
+// exfiltration requests, and Unicode tricks that hide or disguise text.
+//
+// It reports findings and never modifies text; SanitizeBehaviorContent
+// neutralizes markup, and callers decide what to do with suspicious content.
+func AnalyzeInjection(text string) InjectionReport {
+	var report InjectionReport
+	if text == "" {
+		return report
+	}
+
+	if excerpt, ok := findInvisible(text); ok {
+		report.Findings = append(report.Findings, InjectionFinding{Rule: RuleInvisibleChars, Excerpt: excerpt})
+	}
+	if word, ok := findMixedScript(text); ok {
+		report.Findings = append(report.Findings, InjectionFinding{Rule: RuleMixedScript, Excerpt: word})
+	}
+
+	normalized := normalizeForAnalysis(text)
+	for _, p := range injectionPatterns {
+		if m := p.re.FindString(normalized); m != "" {
+			report.Findings = append(report.Findings, InjectionFinding{Rule: p.rule, Excerpt: truncateExcerpt(strings.TrimSpace(m))})
+		}
+	}
+	return report
+}
+
+// StripInvisible removes zero-width characters, bidirectional controls, and
+// Unicode tag characters from s.
+func StripInvisible(s string) string {
+	if !strings.ContainsFunc(s, isInvisible) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if !isInvisible(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isInvisible reports whether r renders as nothing (or reorders text) while
+// still being read by a model.
+func isInvisible(r rune) bool {
+	switch {
+	case r >= 0x200B && r <= 0x200F: // zero-width space/joiners, LRM, RLM
+		return true
+	case r >= 0x202A && r <= 0x202E: // bidi embeddings and overrides
+		return true
+	case r >= 0x2060 && r <= 0x2064: // word joiner, invisible operators
+		return true
+	case r >= 0x2066 && r <= 0x2069: // bidi isolates
+		return true
+	case r == 0xFEFF, r == 0x00AD, r == 0x180E: // BOM, soft hyphen, Mongolian vowel separator
+		return true
+	case r >= 0xE0000 && r <= 0xE007F: // tag characters
+		return true
+	}
+	return false
+}
+
+// findInvisible returns the text around the first invisible character, with
+// the character shown as its code point.
+func findInvisible(text string) (string, bool) {
+	runes := []rune(text)
+	for i, r := range runes {
+		if !isInvisible(r) {
+			continue
+		}
+		start := i - 20
+		if start < 0 {
+			start = 0
+		}
+		end := i + 21
+		if end > len(runes) {
+			end = len(runes)
+		}
+		excerpt := StripInvisible(string(runes[start:i])) + fmt.Sprintf("[U+%04X]", r) + StripInvisible(string(runes[i+1:end]))
+		return truncateExcerpt(excerpt), true
+	}
+	return "", false
+}
+
+// confusables maps Cyrillic, Greek, and other letters that look like Latin
+// ones.
+var confusables = map[rune]rune{
+	'а': 'a', 'е': 'e', 'о': 'o', 'р': 'p', 'с': 'c', 'у': 'y', 'х': 'x',
+	'і': 'i', 'ј': 'j', 'ѕ': 's', 'һ': 'h', 'ԁ': 'd', 'ɡ': 'g', 'ո': 'n',
+	'А': 'a', 'В': 'b', 'Е': 'e', 'К': 'k', 'М': 'm', 'Н': 'h', 'О': 'o',
+	'Р': 'p', 'С': 'c', 'Т': 't', 'Х': 'x', 'І': 'i', 'Ј': 'j', 'Ѕ': 's',
+	'α': 'a', 'ε': 'e', 'ι': 'i', 'ο': 'o', 'ρ': 'p', 'τ': 't', 'υ': 'u',
+	'ν': 'v', 'κ': 'k', 'Α': 'a', 'Β': 'b', 'Ε': 'e', 'Ι': 'i', 'Κ': 'k',
+	'Μ': 'm', 'Ν': 'n', 'Ο': 'o', 'Ρ': 'p', 'Τ': 't', 'Χ': 'x', 'Υ': 'y',
+}
+
+// findMixedScript returns the first word that mixes Latin letters with
+// lookalikes from other scripts.
+func findMixedScript(text string) (string, bool) {
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		var latin, lookalike bool
+		for _, r := range word {
+			if r < unicode.MaxASCII {
+				latin = true
+			} else if _, ok := confusables[r]; ok {
+				lookalike = true
+			}
+		}
+		if latin && lookalike {
+			return truncateExcerpt(word), true
+		}
+	}
+	return "", false
+}
+
+// normalizeForAnalysis removes invisible characters, folds lookalikes and
+// fullwidth forms to ASCII, lowercases, and collapses runs of spaces so
+// obfuscated phrases match the plain patterns.
+func normalizeForAnalysis(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	space := false
+	for _, r := range text {
+		if isInvisible(r) {
+			continue
+		}
+		if folded, ok := confusables[r]; ok {
+			r = folded
+		} else if r >= 0xFF01 && r <= 0xFF5E { // fullwidth ASCII
+			r -= 0xFEE0
+		}
+		if r == '\n' {
+			b.WriteRune(r)
+			space = false
+			continue
+		}
+		if unicode.IsSpace(r) {
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+func truncateExcerpt(s string) string {
+	if runes := []rune(s); len(runes) > maxExcerptLength {
+		return string(runes[:maxExcerptLength]) + "..."
+	}
+	return s
+}
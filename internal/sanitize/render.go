@@ -0,0 +1,50 @@
+package sanitize
+
+import (
+	"regexp"
+	"strings"
+)
+
+// reBlockMarker matches markdown block syntax at the start of a line:
+// headings, code fences, blockquotes, horizontal rules, and setext underlines.
+var reBlockMarker = regexp.MustCompile("^(#|```|~~~|>|[-*_]{3,}\\s*$|={3,}\\s*$)")
+
+// RenderText prepares stored text for display at render time. Content may
+// reach the store without going through SanitizeBehaviorContent (packs,
+// imports, older versions), so renderers clean it again: control characters
+// and invisible characters are stripped, and CRLF and Unicode line
+// separators become plain newlines.
+func RenderText(s string) string {
+	if s == "" {
+		return ""
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.NewReplacer("\r", "\n", "\u2028", "\n", "\u2029", "\n").Replace(s)
+	s = stripControlChars(s)
+	return StripInvisible(s)
+}
+
+// RenderPromptItem prepares stored text for a single item in a markdown or
+// plain-text prompt. Besides RenderText's cleanup, continuation lines are
+// indented so multi-line content stays inside its item, and markdown block
+// markers at the start of a line are backslash-escaped so content can't
+// open a section, fence, or quote of its own.
+func RenderPromptItem(s string) string {
+	s = strings.TrimSpace(RenderText(s))
+	if !strings.Contains(s, "\n") && !reBlockMarker.MatchString(s) {
+		return s
+	}
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if reBlockMarker.MatchString(line) {
+			line = `\` + line
+		}
+		if i > 0 && line != "" {
+			line = "  " + line
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
@@ -2,6 +2,8 @@
 // through the floop_learn pipeline. It strips control characters, markdown
 // hierarchy markers, XML/HTML tags, and excessive backtick sequences to
 // prevent stored prompt injection attacks while preserving semantic content.
+// AnalyzeInjection flags content that is well-formed but still tries to
+// instruct the agent, so callers can quarantine it.
 package sanitize
 
 import (
@@ -51,11 +53,13 @@ var (
 
 // SanitizeBehaviorContent sanitizes behavior content text for safe storage
 // and later injection into agent system prompts. It strips control characters,
-// markdown headings, horizontal rules, XML/HTML tags, and excessive backticks
-// while preserving the semantic meaning of the content.
+// invisible Unicode characters, markdown headings, horizontal rules, XML/HTML
+// tags, and excessive backticks while preserving the semantic meaning of the
+// content.
 //
 // The sanitization pipeline runs in this order:
-//  1. Strip null bytes and ASCII control characters (except \n, \t)
+//  1. Strip null bytes, ASCII control characters (except \n, \t), and
+//     invisible characters (zero-width, bidi controls, tag characters)
 //  2. Strip XML/HTML tags
 //  3. Replace markdown headings with list markers
 //  4. Remove markdown horizontal rules
@@ -72,6 +76,7 @@ func SanitizeBehaviorContent(input string) string {
 
 	// 1. Strip null bytes and ASCII control characters (0x00-0x1F) except \n (0x0A) and \t (0x09).
 	s = stripControlChars(s)
+	s = StripInvisible(s)
 
 	// 2. Strip HTML comments, CDATA sections, and XML/HTML-like tags.
 	s = reHTMLComment.ReplaceAllString(s, "")
@@ -29,6 +29,11 @@ func TestSanitizeBehaviorContent(t *testing.T) {
 			input: "Use\x01 uv\x02 ins\x03tead\x07",
 			want:  "Use uv instead",
 		},
+		{
+			name:  "strip zero-width and bidi characters",
+			input: "ig\u200bnore\u202e previous\ufeff",
+			want:  "ignore previous",
+		},
 		{
 			name:  "preserve newlines and tabs",
 			input: "Line one\nLine two\n\tIndented",
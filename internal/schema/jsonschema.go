@@ -0,0 +1,142 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect of the documents this package emits.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document or subschema. Only the keywords floop's
+// formats need are modeled.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 interface{}        `json:"type,omitempty"` // a type name, or a list of them
+	Format               string             `json:"format,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+}
+
+// nullable allows null in place of s.
+func (s *Schema) nullable() *Schema {
+	if name, ok := s.Type.(string); ok {
+		s.Type = []string{name, "null"}
+	}
+	return s
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	rawType      = reflect.TypeOf(json.RawMessage(nil))
+)
+
+// reflector derives schemas from Go types the way encoding/json encodes
+// them.
+type reflector struct {
+	// enums lists the values of string types that form a closed set
+	enums map[reflect.Type][]string
+	// visiting guards against recursive types
+	visiting map[reflect.Type]bool
+}
+
+// Reflect returns the JSON Schema of the encoding/json encoding of v's type.
+// Values of the string types in enums are restricted to the listed values.
+func Reflect(v interface{}, enums map[reflect.Type][]string) *Schema {
+	r := &reflector{enums: enums, visiting: make(map[reflect.Type]bool)}
+	s := r.schema(reflect.TypeOf(v))
+	s.Schema = Draft
+	return s
+}
+
+func (r *reflector) schema(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer", Description: "nanoseconds"}
+	case rawType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return r.schema(t.Elem()).nullable()
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string", Enum: r.enums[t]}
+	case reflect.Slice:
+		// Nil slices and maps encode as null
+		if t.Elem().Kind() == reflect.Uint8 {
+			return (&Schema{Type: "string", ContentEncoding: "base64"}).nullable()
+		}
+		return (&Schema{Type: "array", Items: r.schema(t.Elem())}).nullable()
+	case reflect.Array:
+		return &Schema{Type: "array", Items: r.schema(t.Elem())}
+	case reflect.Map:
+		s := &Schema{Type: "object"}
+		if t.Elem().Kind() != reflect.Interface {
+			s.AdditionalProperties = r.schema(t.Elem())
+		}
+		return s.nullable()
+	case reflect.Struct:
+		if r.visiting[t] {
+			return &Schema{Type: "object"}
+		}
+		r.visiting[t] = true
+		defer delete(r.visiting, t)
+		s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		r.fields(s, t)
+		return s
+	}
+	// Interfaces hold any value
+	return &Schema{}
+}
+
+// fields adds the encoded fields of struct type t to s, flattening embedded
+// structs as encoding/json does.
+func (r *reflector) fields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				r.fields(s, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s.Properties[name] = r.schema(f.Type)
+		if !strings.Contains(","+opts+",", ",omitempty,") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
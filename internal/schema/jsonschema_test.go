@@ -0,0 +1,74 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type reflectKind string
+
+type reflectInner struct {
+	Note string `json:"note"`
+}
+
+type reflectSample struct {
+	reflectInner
+	ID       string                 `json:"id"`
+	Kind     reflectKind            `json:"kind"`
+	Count    int                    `json:"count,omitempty"`
+	Weight   float64                `json:"weight"`
+	At       time.Time              `json:"at"`
+	Until    *time.Time             `json:"until,omitempty"`
+	Tags     []string               `json:"tags"`
+	Labels   map[string]string      `json:"labels,omitempty"`
+	Extra    map[string]interface{} `json:"extra"`
+	Data     []byte                 `json:"data,omitempty"`
+	Next     *reflectSample         `json:"next,omitempty"`
+	Internal string                 `json:"-"`
+	hidden   string
+}
+
+func TestReflect(t *testing.T) {
+	s := Reflect(reflectSample{}, map[reflect.Type][]string{reflect.TypeOf(reflectKind("")): {"a", "b"}})
+
+	if s.Schema != Draft || s.Type != "object" {
+		t.Errorf("root = %q %v, want a %s object", s.Schema, s.Type, Draft)
+	}
+	wantRequired := []string{"note", "id", "kind", "weight", "at", "tags", "extra"}
+	if !reflect.DeepEqual(s.Required, wantRequired) {
+		t.Errorf("Required = %v, want %v", s.Required, wantRequired)
+	}
+	for _, name := range []string{"Internal", "hidden", "reflectInner"} {
+		if _, ok := s.Properties[name]; ok {
+			t.Errorf("property %s should not be present", name)
+		}
+	}
+
+	tests := []struct {
+		prop string
+		want Schema
+	}{
+		{"note", Schema{Type: "string"}},
+		{"kind", Schema{Type: "string", Enum: []string{"a", "b"}}},
+		{"count", Schema{Type: "integer"}},
+		{"weight", Schema{Type: "number"}},
+		{"at", Schema{Type: "string", Format: "date-time"}},
+		{"until", Schema{Type: []string{"string", "null"}, Format: "date-time"}},
+		{"tags", Schema{Type: []string{"array", "null"}, Items: &Schema{Type: "string"}}},
+		{"labels", Schema{Type: []string{"object", "null"}, AdditionalProperties: &Schema{Type: "string"}}},
+		{"extra", Schema{Type: []string{"object", "null"}}},
+		{"data", Schema{Type: []string{"string", "null"}, ContentEncoding: "base64"}},
+	}
+	for _, tt := range tests {
+		if got := s.Properties[tt.prop]; got == nil || !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("property %s = %+v, want %+v", tt.prop, got, tt.want)
+		}
+	}
+
+	// A recursive type stops at the repeated struct
+	next := s.Properties["next"]
+	if next == nil || next.Properties != nil || !reflect.DeepEqual(next.Type, []string{"object", "null"}) {
+		t.Errorf("next = %+v, want a nullable object without properties", next)
+	}
+}
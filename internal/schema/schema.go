@@ -0,0 +1,186 @@
+// Package schema describes the on-disk formats of a .floop directory (the
+// SQLite store, its JSONL files, and backup files) as JSON Schema documents
+// generated from the types that read and write them, so tools that read
+// .floop directly can check their compatibility at runtime.
+package schema
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/capabilities"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Sections of a Document, for callers emitting only one of them.
+const (
+	SectionSQLite = "sqlite"
+	SectionJSONL  = "jsonl"
+	SectionBackup = "backup"
+)
+
+// Sections lists the sections of a Document.
+var Sections = []string{SectionSQLite, SectionJSONL, SectionBackup}
+
+// Document describes every on-disk format of this floop build.
+type Document struct {
+	FloopVersion string       `json:"floop_version"`
+	SQLite       *SQLite      `json:"sqlite"`
+	JSONL        []JSONLFile  `json:"jsonl"`
+	Backup       []BackupFile `json:"backup"`
+}
+
+// SQLite describes the store database: its tables, indexes, and triggers,
+// and the JSON Schema of a row of each table.
+type SQLite struct {
+	*store.SQLiteSchema
+	Rows map[string]*Schema `json:"rows"`
+}
+
+// JSONLFile describes a JSONL file: every line is one Record.
+type JSONLFile struct {
+	File        string  `json:"file"`
+	Description string  `json:"description"`
+	Record      *Schema `json:"record"`
+}
+
+// BackupFile describes one backup format version. A V1 backup is a single
+// JSON document; a V2 backup is a Header line followed by the Payload, gzip
+// compressed when the header says so.
+type BackupFile struct {
+	Version int     `json:"version"`
+	Layout  string  `json:"layout"`
+	Header  *Schema `json:"header,omitempty"`
+	Payload *Schema `json:"payload"`
+}
+
+// enums lists the closed sets of values floop writes.
+func enums() map[reflect.Type][]string {
+	edgeKinds := make([]string, 0, len(capabilities.EdgeKinds))
+	for _, k := range capabilities.EdgeKinds {
+		edgeKinds = append(edgeKinds, string(k))
+	}
+	return map[reflect.Type][]string{
+		reflect.TypeOf(store.EdgeKind("")): edgeKinds,
+	}
+}
+
+// Describe builds the Document for this build, reporting floopVersion.
+func Describe(ctx context.Context, floopVersion string) (*Document, error) {
+	sqlite, err := store.DescribeSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	doc := &Document{
+		FloopVersion: floopVersion,
+		SQLite:       &SQLite{SQLiteSchema: sqlite, Rows: make(map[string]*Schema, len(sqlite.Tables))},
+	}
+	for _, table := range sqlite.Tables {
+		doc.SQLite.Rows[table.Name] = rowSchema(table)
+	}
+
+	e := enums()
+	doc.JSONL = []JSONLFile{
+		{
+			File:        "nodes.jsonl",
+			Description: "Store nodes (behaviors, corrections, and curated behaviors), one per line",
+			Record:      titled(Reflect(store.Node{}, e), "nodes.jsonl record"),
+		},
+		{
+			File:        "nodes.log.jsonl",
+			Description: "Append log of node upserts and deletes since nodes.jsonl was last compacted; op is upsert or delete",
+			Record:      titled(Reflect(store.NodeLogEntry{}, e), "nodes.log.jsonl record"),
+		},
+		{
+			File:        "edges.jsonl",
+			Description: "Store edges, one per line",
+			Record:      titled(Reflect(store.Edge{}, e), "edges.jsonl record"),
+		},
+		{
+			File:        "corrections.jsonl",
+			Description: "Captured corrections, one per line, oldest first, as exported by 'floop export-corrections --format jsonl' and 'floop export-all'; floop keeps them in floop.db and imports a corrections.jsonl found in a .floop directory",
+			Record:      titled(Reflect(models.Correction{}, e), "corrections.jsonl record"),
+		},
+	}
+
+	payload := Reflect(backup.BackupFormat{}, e)
+	doc.Backup = []BackupFile{
+		{
+			Version: backup.FormatV1,
+			Layout:  "A single JSON document",
+			Payload: titled(payload, "Backup payload"),
+		},
+		{
+			Version: backup.FormatV2,
+			Layout:  "A JSON header line, then the payload, gzip-compressed when the header's compressed is true; checksum is \"sha256:\" and the hex SHA-256 of the bytes after the header line",
+			Header:  titled(Reflect(backup.BackupHeader{}, e), "Backup header"),
+			Payload: titled(payload, "Backup payload"),
+		},
+	}
+	return doc, nil
+}
+
+// Section returns the named section of d, or nil for an unknown name.
+func (d *Document) Section(name string) interface{} {
+	switch name {
+	case SectionSQLite:
+		return d.SQLite
+	case SectionJSONL:
+		return d.JSONL
+	case SectionBackup:
+		return d.Backup
+	}
+	return nil
+}
+
+// titled sets the title of s.
+func titled(s *Schema, title string) *Schema {
+	s.Title = title
+	return s
+}
+
+// rowSchema returns the JSON Schema of a row of table, mapping column types
+// by SQLite's type affinity rules, except that DATE and TIME columns, which
+// floop writes as text, are strings. Columns that may be NULL accept null, and
+// columns without a default that may not be NULL are required.
+func rowSchema(table store.TableSchema) *Schema {
+	s := &Schema{
+		Schema:     Draft,
+		Title:      table.Name + " row",
+		Type:       "object",
+		Properties: make(map[string]*Schema, len(table.Columns)),
+	}
+	for _, col := range table.Columns {
+		prop := columnSchema(col.Type)
+		if !col.NotNull && col.PrimaryKey == 0 {
+			prop.nullable()
+		}
+		if col.NotNull && col.Default == nil {
+			s.Required = append(s.Required, col.Name)
+		}
+		s.Properties[col.Name] = prop
+	}
+	return s
+}
+
+// columnSchema maps a declared SQLite column type to a JSON Schema.
+func columnSchema(declared string) *Schema {
+	t := strings.ToUpper(declared)
+	switch {
+	case strings.Contains(t, "INT"):
+		return &Schema{Type: "integer"}
+	case strings.Contains(t, "CHAR"), strings.Contains(t, "CLOB"), strings.Contains(t, "TEXT"):
+		return &Schema{Type: "string"}
+	case t == "" || strings.Contains(t, "BLOB"):
+		return &Schema{Type: "string", ContentEncoding: "base64"}
+	case strings.Contains(t, "REAL"), strings.Contains(t, "FLOA"), strings.Contains(t, "DOUB"):
+		return &Schema{Type: "number"}
+	case strings.Contains(t, "DATE"), strings.Contains(t, "TIME"):
+		return &Schema{Type: "string"}
+	}
+	// NUMERIC affinity
+	return &Schema{Type: "number"}
+}
@@ -0,0 +1,66 @@
+package schema
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestDescribe(t *testing.T) {
+	doc, err := Describe(context.Background(), "1.2.3")
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	if doc.FloopVersion != "1.2.3" || doc.SQLite.Version != store.SchemaVersion {
+		t.Errorf("versions = %q, %d", doc.FloopVersion, doc.SQLite.Version)
+	}
+
+	row := doc.SQLite.Rows["behaviors"]
+	if row == nil {
+		t.Fatal("no row schema for behaviors")
+	}
+	if !slices.Contains(row.Required, "name") {
+		t.Errorf("behaviors row required = %v, want name", row.Required)
+	}
+	if got := row.Properties["content_summary"]; got == nil || !slices.Equal(got.Type.([]string), []string{"string", "null"}) {
+		t.Errorf("content_summary = %+v, want a nullable string", got)
+	}
+
+	files := make(map[string]JSONLFile)
+	for _, f := range doc.JSONL {
+		files[f.File] = f
+	}
+	for _, name := range []string{"nodes.jsonl", "nodes.log.jsonl", "edges.jsonl", "corrections.jsonl"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("JSONL file %s not described", name)
+		}
+	}
+	edge := files["edges.jsonl"].Record
+	if kind := edge.Properties["kind"]; kind == nil || !slices.Contains(kind.Enum, string(store.EdgeKindRequires)) {
+		t.Errorf("edge kind = %+v, want an enum of edge kinds", kind)
+	}
+	if _, ok := files["corrections.jsonl"].Record.Properties["corrected_action"]; !ok {
+		t.Error("corrections.jsonl record lacks corrected_action")
+	}
+
+	if len(doc.Backup) != 2 || doc.Backup[1].Version != backup.FormatV2 || doc.Backup[1].Header == nil {
+		t.Fatalf("Backup = %+v, want V1 and V2 with a header", doc.Backup)
+	}
+	// Backup nodes flatten the embedded store node
+	nodes := doc.Backup[1].Payload.Properties["nodes"]
+	if nodes == nil || nodes.Items == nil || nodes.Items.Properties["id"] == nil {
+		t.Errorf("payload nodes = %+v, want flattened node fields", nodes)
+	}
+
+	for _, section := range Sections {
+		if doc.Section(section) == nil {
+			t.Errorf("Section(%q) = nil", section)
+		}
+	}
+	if doc.Section("events") != nil {
+		t.Error("Section(events) should be nil")
+	}
+}
@@ -0,0 +1,53 @@
+//go:build windows
+
+package selfupdate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ReplaceExecutable replaces the file at exePath with binary. Windows cannot
+// overwrite a running executable but can rename it, so the current binary is
+// moved aside to exePath+".old" and the new one renamed into place; if that
+// fails the old binary is moved back. CleanupOld removes the .old file on a
+// later run.
+func ReplaceExecutable(exePath string, binary []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(exePath), filepath.Base(exePath)+".new-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file next to %s: %w", exePath, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op after a successful rename
+
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to fsync new binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close new binary: %w", err)
+	}
+
+	oldPath := exePath + ".old"
+	_ = os.Remove(oldPath)
+	if err := os.Rename(exePath, oldPath); err != nil {
+		return fmt.Errorf("failed to move %s aside: %w", exePath, err)
+	}
+	if err := os.Rename(tmpPath, exePath); err != nil {
+		if restoreErr := os.Rename(oldPath, exePath); restoreErr != nil {
+			return fmt.Errorf("failed to replace %s: %w (and restoring the old binary failed: %v)", exePath, err, restoreErr)
+		}
+		return fmt.Errorf("failed to replace %s: %w", exePath, err)
+	}
+	return nil
+}
+
+// CleanupOld removes the binary a previous update moved aside.
+func CleanupOld(exePath string) {
+	_ = os.Remove(exePath + ".old")
+}
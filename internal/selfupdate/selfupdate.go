@@ -0,0 +1,329 @@
+// Package selfupdate replaces the running floop binary with a release
+// downloaded from GitHub, after verifying the archive against the release's
+// checksums.txt.
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/pack"
+)
+
+// Release repository and checksum asset, matching .goreleaser.yml.
+const (
+	Owner          = "nvandessel"
+	Repo           = "floop"
+	ChecksumsAsset = "checksums.txt"
+)
+
+// MaxArchiveSize is the largest release archive that will be downloaded.
+const MaxArchiveSize = 200 << 20
+
+// DownloadTimeout bounds each asset download.
+const DownloadTimeout = 5 * time.Minute
+
+// Channel selects which releases self-update considers.
+type Channel string
+
+const (
+	// ChannelStable follows the latest full release.
+	ChannelStable Channel = "stable"
+
+	// ChannelEdge follows the newest release, including prereleases.
+	ChannelEdge Channel = "edge"
+)
+
+// ParseChannel validates a --channel value.
+func ParseChannel(s string) (Channel, error) {
+	switch Channel(s) {
+	case ChannelStable, ChannelEdge:
+		return Channel(s), nil
+	default:
+		return "", fmt.Errorf("invalid channel %q: must be stable or edge", s)
+	}
+}
+
+// ReleaseSource looks up floop releases. *pack.GitHubClient implements it.
+type ReleaseSource interface {
+	ResolveRelease(ctx context.Context, owner, repo, version string) (*pack.GitHubRelease, error)
+	ListReleases(ctx context.Context, owner, repo string) ([]pack.GitHubRelease, error)
+}
+
+// LatestRelease returns the newest release on channel.
+func LatestRelease(ctx context.Context, src ReleaseSource, channel Channel) (*pack.GitHubRelease, error) {
+	if channel != ChannelEdge {
+		return src.ResolveRelease(ctx, Owner, Repo, "")
+	}
+
+	releases, err := src.ListReleases(ctx, Owner, Repo)
+	if err != nil {
+		return nil, err
+	}
+	var newest *pack.GitHubRelease
+	for i := range releases {
+		r := &releases[i]
+		if r.Draft {
+			continue
+		}
+		if newest == nil || CompareVersions(pack.ReleaseVersion(r), pack.ReleaseVersion(newest)) > 0 {
+			newest = r
+		}
+	}
+	if newest == nil {
+		return nil, fmt.Errorf("no releases found for %s/%s", Owner, Repo)
+	}
+	return newest, nil
+}
+
+// CompareVersions compares two semantic versions (with or without a leading
+// "v"), returning -1, 0, or 1. A prerelease sorts before its release;
+// prerelease identifiers compare numerically where both are numbers.
+func CompareVersions(a, b string) int {
+	aCore, aPre := splitVersion(a)
+	bCore, bPre := splitVersion(b)
+
+	for i := 0; i < len(aCore) || i < len(bCore); i++ {
+		var x, y int
+		if i < len(aCore) {
+			x = aCore[i]
+		}
+		if i < len(bCore) {
+			y = bCore[i]
+		}
+		if x != y {
+			return cmpInt(x, y)
+		}
+	}
+
+	switch {
+	case aPre == "" && bPre == "":
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+
+	aIDs, bIDs := strings.Split(aPre, "."), strings.Split(bPre, ".")
+	for i := 0; i < len(aIDs) && i < len(bIDs); i++ {
+		if aIDs[i] == bIDs[i] {
+			continue
+		}
+		x, xErr := strconv.Atoi(aIDs[i])
+		y, yErr := strconv.Atoi(bIDs[i])
+		if xErr == nil && yErr == nil {
+			return cmpInt(x, y)
+		}
+		return strings.Compare(aIDs[i], bIDs[i])
+	}
+	return cmpInt(len(aIDs), len(bIDs))
+}
+
+// splitVersion parses "v1.2.3-rc.1+meta" into [1 2 3] and "rc.1".
+func splitVersion(v string) ([]int, string) {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexByte(v, '+'); i >= 0 {
+		v = v[:i]
+	}
+	pre := ""
+	if i := strings.IndexByte(v, '-'); i >= 0 {
+		v, pre = v[:i], v[i+1:]
+	}
+	var core []int
+	for _, part := range strings.Split(v, ".") {
+		n, _ := strconv.Atoi(part)
+		core = append(core, n)
+	}
+	return core, pre
+}
+
+func cmpInt(x, y int) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// FindArchive returns the release archive for goos/goarch, named
+// floop-<version>-<os>-<arch>.tar.gz (or .zip).
+func FindArchive(release *pack.GitHubRelease, goos, goarch string) (pack.GitHubAsset, error) {
+	prefix := fmt.Sprintf("%s-%s-%s-%s", Repo, pack.ReleaseVersion(release), goos, goarch)
+	for _, a := range release.Assets {
+		if a.Name == prefix+".tar.gz" || a.Name == prefix+".zip" {
+			return a, nil
+		}
+	}
+	return pack.GitHubAsset{}, fmt.Errorf("release %s has no archive for %s/%s", release.TagName, goos, goarch)
+}
+
+// findAsset returns the release asset called name.
+func findAsset(release *pack.GitHubRelease, name string) (pack.GitHubAsset, error) {
+	for _, a := range release.Assets {
+		if a.Name == name {
+			return a, nil
+		}
+	}
+	return pack.GitHubAsset{}, fmt.Errorf("release %s has no %s", release.TagName, name)
+}
+
+// Download fetches url into memory, failing if it exceeds limit bytes.
+func Download(ctx context.Context, client *http.Client, url string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating download request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download failed: HTTP %d from %s", resp.StatusCode, url)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading download: %w", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("download exceeds maximum size (%dMB)", limit>>20)
+	}
+	return data, nil
+}
+
+// VerifyChecksum checks data against the sha256 listed for name in a
+// checksums.txt file ("<hex>  <name>" per line).
+func VerifyChecksum(data, checksums []byte, name string) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, fields[0]) {
+			return fmt.Errorf("checksum mismatch for %s: got %s, want %s", name, got, fields[0])
+		}
+		return nil
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading checksums: %w", err)
+	}
+	return fmt.Errorf("no checksum listed for %s", name)
+}
+
+// ExtractBinary returns the file called binary from a .tar.gz or .zip
+// archive, searching every directory level.
+func ExtractBinary(archive []byte, archiveName, binary string) ([]byte, error) {
+	if strings.HasSuffix(archiveName, ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, fmt.Errorf("opening zip archive: %w", err)
+		}
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() || path.Base(f.Name) != binary {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("opening %s in archive: %w", f.Name, err)
+			}
+			defer rc.Close()
+			return readEntry(rc, f.Name, MaxArchiveSize)
+		}
+		return nil, fmt.Errorf("%s not found in %s", binary, archiveName)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("opening gzip archive: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading tar archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || path.Base(hdr.Name) != binary {
+			continue
+		}
+		return readEntry(tr, hdr.Name, MaxArchiveSize)
+	}
+	return nil, fmt.Errorf("%s not found in %s", binary, archiveName)
+}
+
+// readEntry reads the archive entry name from r, failing rather than
+// truncating it when it is larger than limit.
+func readEntry(r io.Reader, name string, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s in archive: %w", name, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s in archive exceeds maximum size (%dMB)", name, limit>>20)
+	}
+	return data, nil
+}
+
+// Apply downloads release's archive for goos/goarch, verifies it against the
+// release checksums, and replaces the executable at exePath with the binary
+// inside it.
+func Apply(ctx context.Context, client *http.Client, release *pack.GitHubRelease, goos, goarch, exePath string) error {
+	archiveAsset, err := FindArchive(release, goos, goarch)
+	if err != nil {
+		return err
+	}
+	checksumsAsset, err := findAsset(release, ChecksumsAsset)
+	if err != nil {
+		return err
+	}
+
+	checksums, err := Download(ctx, client, pack.AssetDownloadURL(checksumsAsset), 1<<20)
+	if err != nil {
+		return fmt.Errorf("fetching checksums: %w", err)
+	}
+	archive, err := Download(ctx, client, pack.AssetDownloadURL(archiveAsset), MaxArchiveSize)
+	if err != nil {
+		return fmt.Errorf("fetching archive: %w", err)
+	}
+	if err := VerifyChecksum(archive, checksums, archiveAsset.Name); err != nil {
+		return err
+	}
+
+	binaryName := Repo
+	if goos == "windows" {
+		binaryName += ".exe"
+	}
+	binary, err := ExtractBinary(archive, archiveAsset.Name, binaryName)
+	if err != nil {
+		return err
+	}
+	if len(binary) == 0 {
+		return fmt.Errorf("%s in %s is empty", binaryName, archiveAsset.Name)
+	}
+
+	return ReplaceExecutable(exePath, binary)
+}
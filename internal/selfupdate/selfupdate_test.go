@@ -0,0 +1,214 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/pack"
+)
+
+type fakeSource struct {
+	latest   *pack.GitHubRelease
+	releases []pack.GitHubRelease
+}
+
+func (f *fakeSource) ResolveRelease(_ context.Context, _, _, _ string) (*pack.GitHubRelease, error) {
+	return f.latest, nil
+}
+
+func (f *fakeSource) ListReleases(_ context.Context, _, _ string) ([]pack.GitHubRelease, error) {
+	return f.releases, nil
+}
+
+// makeTarGz builds a .tar.gz containing the given files.
+func makeTarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func sha(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestParseChannel(t *testing.T) {
+	for _, tt := range []struct {
+		in      string
+		want    Channel
+		wantErr bool
+	}{
+		{"stable", ChannelStable, false},
+		{"edge", ChannelEdge, false},
+		{"nightly", "", true},
+		{"", "", true},
+	} {
+		got, err := ParseChannel(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseChannel(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.2.4", "1.2.3", 1},
+		{"1.10.0", "1.9.9", 1},
+		{"2.0.0", "10.0.0", -1},
+		{"1.2.3-rc.1", "1.2.3", -1},
+		{"1.2.3-rc.2", "1.2.3-rc.10", -1},
+		{"1.2.3-beta", "1.2.3-alpha", 1},
+		{"1.2.3+build.5", "1.2.3", 0},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestLatestRelease(t *testing.T) {
+	src := &fakeSource{
+		latest: &pack.GitHubRelease{TagName: "v1.4.0"},
+		releases: []pack.GitHubRelease{
+			{TagName: "v1.4.0"},
+			{TagName: "v1.6.0-rc.1", Draft: true},
+			{TagName: "v1.5.0-rc.2", Prerelease: true},
+			{TagName: "v1.5.0-rc.1", Prerelease: true},
+		},
+	}
+
+	stable, err := LatestRelease(context.Background(), src, ChannelStable)
+	if err != nil || stable.TagName != "v1.4.0" {
+		t.Errorf("stable = %v, %v; want v1.4.0", stable, err)
+	}
+	edge, err := LatestRelease(context.Background(), src, ChannelEdge)
+	if err != nil || edge.TagName != "v1.5.0-rc.2" {
+		t.Errorf("edge = %v, %v; want v1.5.0-rc.2 (drafts skipped)", edge, err)
+	}
+
+	if _, err := LatestRelease(context.Background(), &fakeSource{}, ChannelEdge); err == nil {
+		t.Error("edge with no releases should fail")
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("archive bytes")
+	name := "floop-1.0.0-linux-amd64.tar.gz"
+	checksums := []byte(fmt.Sprintf("%s  other.tar.gz\n%s  %s\n", sha([]byte("x")), sha(data), name))
+
+	if err := VerifyChecksum(data, checksums, name); err != nil {
+		t.Errorf("VerifyChecksum() error = %v", err)
+	}
+	if err := VerifyChecksum([]byte("tampered"), checksums, name); err == nil || !strings.Contains(err.Error(), "mismatch") {
+		t.Errorf("tampered data error = %v, want mismatch", err)
+	}
+	if err := VerifyChecksum(data, checksums, "missing.tar.gz"); err == nil {
+		t.Error("missing entry should fail")
+	}
+}
+
+func TestExtractBinary(t *testing.T) {
+	// goreleaser puts the binary at the archive root next to LICENSE and README
+	archive := makeTarGz(t, map[string]string{"LICENSE": "MIT", "floop": "#!binary"})
+	got, err := ExtractBinary(archive, "floop-1.0.0-linux-amd64.tar.gz", "floop")
+	if err != nil || string(got) != "#!binary" {
+		t.Errorf("ExtractBinary() = %q, %v", got, err)
+	}
+	if _, err := ExtractBinary(archive, "floop-1.0.0-windows-amd64.tar.gz", "floop.exe"); err == nil {
+		t.Error("missing binary should fail")
+	}
+}
+
+func TestReadEntry(t *testing.T) {
+	if got, err := readEntry(strings.NewReader("12345678"), "floop", 8); err != nil || string(got) != "12345678" {
+		t.Errorf("readEntry() at the limit = %q, %v", got, err)
+	}
+	if got, err := readEntry(strings.NewReader("123456789"), "floop", 8); err == nil || !strings.Contains(err.Error(), "exceeds maximum size") {
+		t.Errorf("readEntry() past the limit = %q, %v; want a size error, not a truncated binary", got, err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	archiveName := "floop-1.2.0-linux-amd64.tar.gz"
+	archive := makeTarGz(t, map[string]string{"floop": "new binary", "LICENSE": "MIT"})
+	checksums := fmt.Sprintf("%s  %s\n", sha(archive), archiveName)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + archiveName:
+			w.Write(archive)
+		case "/checksums.txt":
+			w.Write([]byte(checksums))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	release := &pack.GitHubRelease{
+		TagName: "v1.2.0",
+		Assets: []pack.GitHubAsset{
+			{Name: archiveName, BrowserDownloadURL: srv.URL + "/" + archiveName},
+			{Name: "checksums.txt", BrowserDownloadURL: srv.URL + "/checksums.txt"},
+		},
+	}
+
+	exe := filepath.Join(t.TempDir(), "floop")
+	if err := os.WriteFile(exe, []byte("old binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Apply(context.Background(), srv.Client(), release, "linux", "amd64", exe); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	got, _ := os.ReadFile(exe)
+	if string(got) != "new binary" {
+		t.Errorf("executable = %q, want new binary", got)
+	}
+	if info, _ := os.Stat(exe); info.Mode().Perm()&0100 == 0 {
+		t.Errorf("executable mode = %v, want executable", info.Mode())
+	}
+
+	if err := Apply(context.Background(), srv.Client(), release, "darwin", "arm64", exe); err == nil {
+		t.Error("Apply() for a platform without an archive should fail")
+	}
+
+	// A tampered checksum leaves the existing binary untouched
+	checksums = fmt.Sprintf("%s  %s\n", sha([]byte("other")), archiveName)
+	os.WriteFile(exe, []byte("old binary"), 0755)
+	if err := Apply(context.Background(), srv.Client(), release, "linux", "amd64", exe); err == nil {
+		t.Error("Apply() with a bad checksum should fail")
+	}
+	if got, _ := os.ReadFile(exe); string(got) != "old binary" {
+		t.Errorf("executable after failed Apply = %q, want old binary", got)
+	}
+}
@@ -6,6 +6,7 @@
 package session
 
 import (
+	"sort"
 	"sync"
 	"time"
 
@@ -110,6 +111,19 @@ func (s *State) GetInjection(behaviorID string) *InjectionRecord {
 	return &cp
 }
 
+// InjectedIDs returns the IDs of every behavior injected this session, sorted.
+func (s *State) InjectedIDs() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]string, 0, len(s.injections))
+	for id := range s.injections {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
 // IncrementPromptCount should be called on each user prompt to track prompt cadence.
 func (s *State) IncrementPromptCount() {
 	s.mu.Lock()
@@ -66,6 +66,22 @@ func TestState_RecordInjection(t *testing.T) {
 	}
 }
 
+func TestState_InjectedIDs(t *testing.T) {
+	s := NewState(DefaultConfig())
+	if ids := s.InjectedIDs(); len(ids) != 0 {
+		t.Errorf("InjectedIDs() = %v, want empty", ids)
+	}
+
+	s.RecordInjection("b2", models.TierFull, 0.9, 10)
+	s.RecordInjection("b1", models.TierSummary, 0.5, 5)
+	s.RecordInjection("b2", models.TierFull, 0.9, 10)
+
+	ids := s.InjectedIDs()
+	if len(ids) != 2 || ids[0] != "b1" || ids[1] != "b2" {
+		t.Errorf("InjectedIDs() = %v, want [b1 b2]", ids)
+	}
+}
+
 func TestState_ShouldInject_NeverInjected(t *testing.T) {
 	s := NewState(DefaultConfig())
 
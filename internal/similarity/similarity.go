@@ -42,6 +42,56 @@ func ComputeWhenOverlap(a, b map[string]interface{}) float64 {
 	return float64(matches) / float64(total)
 }
 
+// WhenCompatible reports whether two when predicates can hold at the same
+// time. They are incompatible when a key present in both has values with
+// nothing in common, e.g. language "go" vs "python". Keys present in only one
+// map never conflict. Glob values (containing *, ? or [) and operator
+// objects such as {"not": "go"} cannot be compared statically and are
+// treated as compatible.
+func WhenCompatible(a, b map[string]interface{}) bool {
+	for key, valueA := range a {
+		valueB, exists := b[key]
+		if !exists {
+			continue
+		}
+		if !valuesOverlap(valueA, valueB) {
+			return false
+		}
+	}
+	return true
+}
+
+// valuesOverlap reports whether two condition values share a value, treating
+// a scalar as a one-element list.
+func valuesOverlap(a, b interface{}) bool {
+	listA, ok := toInterfaceSlice(a)
+	if !ok {
+		listA = []interface{}{a}
+	}
+	listB, ok := toInterfaceSlice(b)
+	if !ok {
+		listB = []interface{}{b}
+	}
+	for _, av := range listA {
+		for _, bv := range listB {
+			if isOpaque(av) || isOpaque(bv) || ValuesEqual(av, bv) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isOpaque reports whether v is a glob or operator object, whose overlap
+// with another value is unknown without a context to match.
+func isOpaque(v interface{}) bool {
+	if _, ok := v.(map[string]interface{}); ok {
+		return true
+	}
+	s, ok := v.(string)
+	return ok && strings.ContainsAny(s, "*?[")
+}
+
 // CountSharedTags returns the number of tags that appear in both slices.
 func CountSharedTags(a, b []string) int {
 	if len(a) == 0 || len(b) == 0 {
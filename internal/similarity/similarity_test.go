@@ -77,6 +77,38 @@ func TestComputeWhenOverlap(t *testing.T) {
 	}
 }
 
+func TestWhenCompatible(t *testing.T) {
+	tests := []struct {
+		name string
+		a    map[string]interface{}
+		b    map[string]interface{}
+		want bool
+	}{
+		{"both nil", nil, nil, true},
+		{"one unscoped", map[string]interface{}{"language": "go"}, nil, true},
+		{"same value", map[string]interface{}{"language": "go"}, map[string]interface{}{"language": "go"}, true},
+		{"disjoint values", map[string]interface{}{"language": "go"}, map[string]interface{}{"language": "python"}, false},
+		{"orthogonal keys", map[string]interface{}{"language": "go"}, map[string]interface{}{"task": "testing"}, true},
+		{"scalar in list", map[string]interface{}{"language": "go"}, map[string]interface{}{"language": []interface{}{"python", "go"}}, true},
+		{"disjoint lists", map[string]interface{}{"language": []string{"go", "rust"}}, map[string]interface{}{"language": []interface{}{"python"}}, false},
+		{"glob is compatible", map[string]interface{}{"file_path": "*.go"}, map[string]interface{}{"file_path": "cmd/main.go"}, true},
+		{"one conflicting key", map[string]interface{}{"language": "go", "task": "testing"}, map[string]interface{}{"language": "go", "task": "refactor"}, false},
+		{"operator is compatible", map[string]interface{}{"language": map[string]interface{}{"not": "go"}}, map[string]interface{}{"language": "python"}, true},
+		{"any groups are compatible", map[string]interface{}{"any": []interface{}{map[string]interface{}{"language": "go"}}}, map[string]interface{}{"any": []interface{}{map[string]interface{}{"task": "testing"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WhenCompatible(tt.a, tt.b); got != tt.want {
+				t.Errorf("WhenCompatible() = %v, want %v", got, tt.want)
+			}
+			if got := WhenCompatible(tt.b, tt.a); got != tt.want {
+				t.Errorf("WhenCompatible() reversed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestComputeContentSimilarity(t *testing.T) {
 	tests := []struct {
 		name string
@@ -1,5 +1,7 @@
 package similarity
 
+import "reflect"
+
 // toInterfaceSlice converts []interface{} or []string to []interface{}.
 // Returns the slice and true if the value is a supported slice type.
 func toInterfaceSlice(v interface{}) ([]interface{}, bool) {
@@ -42,6 +44,7 @@ func ValuesEqual(a, b interface{}) bool {
 		return false
 	}
 
-	// Fallback to direct equality
-	return a == b
+	// Fallback to deep equality, which also compares operator objects
+	// without the panic == gives on maps
+	return reflect.DeepEqual(a, b)
 }
@@ -0,0 +1,170 @@
+package simulation_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/simulation"
+	"github.com/nvandessel/floop/internal/spreading"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// edgeRulesSpreadConfig returns a spreading config with inhibition and
+// temporal decay off, so per-kind rules are the only difference between runs.
+func edgeRulesSpreadConfig(rules map[store.EdgeKind]spreading.EdgeKindRule) spreading.Config {
+	return spreading.Config{
+		MaxSteps:      3,
+		DecayFactor:   0.7,
+		SpreadFactor:  0.85,
+		MinActivation: 0.001,
+		EdgeRules:     rules,
+	}
+}
+
+// TestEdgeKindTransmission validates that per-kind transmission shapes how
+// far activation travels along different edge kinds.
+//
+// Setup: a seed with two equal-weight, two-hop chains:
+//
+//	seed -requires-> req-1 -requires-> req-2
+//	seed -similar-to-> sim-1 -similar-to-> sim-2
+//
+// Without rules both chains use DecayFactor and activate identically. With
+// DefaultEdgeKindRules, requires transmits fully and similar-to at 0.5, so
+// the requires chain reaches further than the global decay allowed and the
+// similar-to chain falls short of it.
+func TestEdgeKindTransmission(t *testing.T) {
+	behaviors := []simulation.BehaviorSpec{
+		{ID: "seed", Name: "Seed", Kind: models.BehaviorKindDirective, Canonical: "Edge rules seed"},
+		{ID: "req-1", Name: "Required 1", Kind: models.BehaviorKindDirective, Canonical: "Required by seed"},
+		{ID: "req-2", Name: "Required 2", Kind: models.BehaviorKindDirective, Canonical: "Required by required 1"},
+		{ID: "sim-1", Name: "Similar 1", Kind: models.BehaviorKindDirective, Canonical: "Similar to seed"},
+		{ID: "sim-2", Name: "Similar 2", Kind: models.BehaviorKindDirective, Canonical: "Similar to similar 1"},
+	}
+	edges := []simulation.EdgeSpec{
+		{Source: "seed", Target: "req-1", Kind: "requires", Weight: 0.9},
+		{Source: "req-1", Target: "req-2", Kind: "requires", Weight: 0.9},
+		{Source: "seed", Target: "sim-1", Kind: "similar-to", Weight: 0.9},
+		{Source: "sim-1", Target: "sim-2", Kind: "similar-to", Weight: 0.9},
+	}
+
+	run := func(name string, rules map[store.EdgeKind]spreading.EdgeKindRule) simulation.SessionResult {
+		spreadCfg := edgeRulesSpreadConfig(rules)
+		result := simulation.NewRunner(t).Run(simulation.Scenario{
+			Name:         name,
+			Behaviors:    behaviors,
+			Edges:        edges,
+			Sessions:     make([]simulation.SessionContext, 1),
+			SpreadConfig: &spreadCfg,
+			SeedOverride: func(int) []spreading.Seed {
+				return []spreading.Seed{{BehaviorID: "seed", Activation: 1.0, Source: "test"}}
+			},
+		})
+		return result.Sessions[0]
+	}
+
+	uniform := run("edge-rules-uniform", nil)
+	for _, pair := range [][2]string{{"req-1", "sim-1"}, {"req-2", "sim-2"}} {
+		req, sim := getBehaviorActivation(uniform, pair[0]), getBehaviorActivation(uniform, pair[1])
+		if math.Abs(req-sim) > 1e-9 {
+			t.Errorf("without rules, %s (%.4f) and %s (%.4f) should match", pair[0], req, pair[1], sim)
+		}
+	}
+
+	ruled := run("edge-rules-default", spreading.DefaultEdgeKindRules())
+	t.Logf("with rules: %s", simulation.FormatSessionDebug(ruled))
+
+	assertActivationHigher(t, ruled, "req-1", "sim-1")
+	assertActivationHigher(t, ruled, "req-2", "sim-2")
+
+	// Full transmission lets requires reach further than the global decay did.
+	if got, base := getBehaviorActivation(ruled, "req-2"), getBehaviorActivation(uniform, "req-2"); got <= base {
+		t.Errorf("req-2 with full transmission = %.4f, want above uniform decay %.4f", got, base)
+	}
+	// Half transmission weakens similar-to relative to the global decay.
+	if got, base := getBehaviorActivation(ruled, "sim-2"), getBehaviorActivation(uniform, "sim-2"); got >= base {
+		t.Errorf("sim-2 with half transmission = %.4f, want below uniform decay %.4f", got, base)
+	}
+}
+
+// TestCoActivatedRecencyCap validates that co-activated edges carry
+// activation in proportion to how recently they fired.
+//
+// Setup: a seed with two co-activated edges of equal weight. The edge to
+// fresh was activated just now; the edge to stale, three weeks ago. With a
+// one-week half-life, stale's edge transmits 1/8 of fresh's.
+//
+// Phase 1 (session 0): stale is well below fresh.
+// Phase 2 (sessions 1-4): the runner touches the seed's edges after session 0,
+// so stale's edge is fresh again and stale recovers to match fresh.
+// Phase 3 (sessions 5-6): stale's edge is backdated again and drops back.
+func TestCoActivatedRecencyCap(t *testing.T) {
+	behaviors := []simulation.BehaviorSpec{
+		{ID: "seed", Name: "Seed", Kind: models.BehaviorKindDirective, Canonical: "Recency cap seed"},
+		{ID: "fresh", Name: "Fresh", Kind: models.BehaviorKindDirective, Canonical: "Recently co-activated"},
+		{ID: "stale", Name: "Stale", Kind: models.BehaviorKindDirective, Canonical: "Co-activated weeks ago"},
+	}
+	edges := []simulation.EdgeSpec{
+		{Source: "seed", Target: "fresh", Kind: "co-activated", Weight: 0.8, LastActivated: simulation.TimeAgoPtr(0)},
+		{Source: "seed", Target: "stale", Kind: "co-activated", Weight: 0.8, LastActivated: simulation.TimeAgoPtr(21 * 24 * time.Hour)},
+	}
+
+	spreadCfg := edgeRulesSpreadConfig(map[store.EdgeKind]spreading.EdgeKindRule{
+		store.EdgeKindCoActivated: {Transmission: 0.7, RecencyHalfLife: 7 * 24 * time.Hour},
+	})
+
+	result := simulation.NewRunner(t).Run(simulation.Scenario{
+		Name:         "co-activated-recency-cap",
+		Behaviors:    behaviors,
+		Edges:        edges,
+		Sessions:     make([]simulation.SessionContext, 7),
+		SpreadConfig: &spreadCfg,
+		SeedOverride: func(int) []spreading.Seed {
+			return []spreading.Seed{{BehaviorID: "seed", Activation: 1.0, Source: "test"}}
+		},
+		BeforeSession: func(sessionIndex int, s *store.SQLiteGraphStore) {
+			if sessionIndex != 5 {
+				return
+			}
+			threeWeeksAgo := simulation.TimeAgo(21 * 24 * time.Hour)
+			if err := s.AddEdge(context.Background(), store.Edge{
+				Source:        "seed",
+				Target:        "stale",
+				Kind:          store.EdgeKindCoActivated,
+				Weight:        0.8,
+				CreatedAt:     simulation.TimeAgo(30 * 24 * time.Hour),
+				LastActivated: &threeWeeksAgo,
+			}); err != nil {
+				t.Fatalf("BeforeSession(%d): backdating stale edge: %v", sessionIndex, err)
+			}
+		},
+	})
+
+	gap := func(session int) float64 {
+		sr := result.Sessions[session]
+		return getBehaviorActivation(sr, "fresh") - getBehaviorActivation(sr, "stale")
+	}
+
+	// Phase 1: the stale edge is capped.
+	if g := gap(0); g < 0.05 {
+		t.Errorf("session 0: fresh-stale gap = %.4f, want stale clearly capped", g)
+	}
+
+	// Phase 2: firing refreshes the edge, lifting the cap.
+	for i := 1; i <= 4; i++ {
+		if g := gap(i); math.Abs(g) > 0.01 {
+			t.Errorf("session %d: fresh-stale gap = %.4f, want recovered to ~0", i, g)
+		}
+	}
+
+	// Phase 3: dormancy caps it again.
+	if g := gap(5); g < 0.05 {
+		t.Errorf("session 5: fresh-stale gap = %.4f, want stale capped after backdating", g)
+	}
+	if g := gap(6); math.Abs(g) > 0.01 {
+		t.Errorf("session 6: fresh-stale gap = %.4f, want recovered after firing", g)
+	}
+}
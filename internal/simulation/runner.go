@@ -184,13 +184,18 @@ func (r *Runner) runSession(
 		}
 	}
 
-	// Step 4: Optionally run tiering.
+	// Step 4: Optionally rank by time of day and workload.
+	if scenario.TemporalWeight > 0 {
+		results = tiering.ApplyTemporalSignals(&sessCtx.ContextSnapshot, results, r.loadBehaviors(ctx, scenario), scenario.TemporalWeight)
+	}
+
+	// Step 5: Optionally run tiering.
 	if tierMapper != nil && scenario.TokenBudget > 0 {
 		behaviors := r.loadBehaviors(ctx, scenario)
 		_ = tierMapper.MapResults(results, behaviors, scenario.TokenBudget)
 	}
 
-	// Step 5: Snapshot edge weights.
+	// Step 6: Snapshot edge weights.
 	edgeWeights := r.snapshotEdgeWeights(ctx, scenario)
 
 	return SessionResult{
@@ -18,7 +18,8 @@ type Scenario struct {
 	HebbianConfig  *spreading.HebbianConfig
 	TokenBudget    int // 0 = skip tiering
 	HebbianEnabled bool
-	CreateEdges    bool // When true, Hebbian creates new co-activated edges for novel pairs
+	CreateEdges    bool    // When true, Hebbian creates new co-activated edges for novel pairs
+	TemporalWeight float64 // 0 = no temporal ranking; otherwise ranking.temporal.weight
 
 	// SeedOverride, when non-nil, is called with the session index to produce
 	// seeds directly, bypassing the real SeedSelector. Use this for scenarios
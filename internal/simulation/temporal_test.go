@@ -0,0 +1,111 @@
+package simulation_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/simulation"
+)
+
+// TestTemporalRanking validates that time-of-day, day-of-week, and workload
+// signals reorder otherwise identical behaviors without changing which
+// behaviors activate.
+//
+// Setup:
+//   - Four behaviors with the same when-condition (task: commit), so they
+//     seed with equal activation
+//   - "full-suite" is tagged time/evening, "quick-check" time/morning,
+//     "deep-review" workload/quiet, and "plain" has no temporal tags
+//   - Sessions: weekday evening, weekday morning, and a busy evening
+//
+// Expected: the evening-tagged behavior ranks first in the evening and
+// below the morning-tagged one in the morning; the untagged behavior keeps its activation; the quiet
+// behavior drops when the session is busy. Without a weight, the order
+// and activations are unchanged.
+func TestTemporalRanking(t *testing.T) {
+	when := map[string]interface{}{"task": "commit"}
+	behaviors := []simulation.BehaviorSpec{
+		{ID: "full-suite", Name: "Full Suite", Kind: models.BehaviorKindDirective, When: when,
+			Canonical: "Run the full test suite before pushing", Tags: []string{"testing", "time/evening"}},
+		{ID: "quick-check", Name: "Quick Check", Kind: models.BehaviorKindDirective, When: when,
+			Canonical: "Run the quick checks before the first commit", Tags: []string{"time/morning"}},
+		{ID: "deep-review", Name: "Deep Review", Kind: models.BehaviorKindDirective, When: when,
+			Canonical: "Review the whole diff line by line", Tags: []string{"workload/quiet"}},
+		{ID: "plain", Name: "Plain", Kind: models.BehaviorKindDirective, When: when,
+			Canonical: "Write a descriptive commit message"},
+	}
+
+	// 2026-10-14 is a Wednesday
+	evening := time.Date(2026, 10, 14, 18, 30, 0, 0, time.UTC)
+	morning := time.Date(2026, 10, 14, 8, 0, 0, 0, time.UTC)
+	sessions := []simulation.SessionContext{
+		{Label: "evening", ContextSnapshot: models.ContextSnapshot{Task: "commit", Timestamp: evening}},
+		{Label: "morning", ContextSnapshot: models.ContextSnapshot{Task: "commit", Timestamp: morning}},
+		{Label: "busy evening", ContextSnapshot: models.ContextSnapshot{Task: "commit", Timestamp: evening, Intensity: 1}},
+	}
+
+	scenario := func(weight float64) simulation.Scenario {
+		return simulation.Scenario{
+			Name:           "temporal-ranking",
+			Behaviors:      behaviors,
+			Sessions:       sessions,
+			TemporalWeight: weight,
+		}
+	}
+
+	activations := func(sr simulation.SessionResult) map[string]float64 {
+		m := make(map[string]float64, len(sr.Results))
+		for _, r := range sr.Results {
+			m[r.BehaviorID] = r.Activation
+		}
+		return m
+	}
+
+	baseline := simulation.NewRunner(t).Run(scenario(0))
+	base := activations(baseline.Sessions[0])
+	if len(base) != len(behaviors) {
+		t.Fatalf("baseline activated %d behaviors, want %d", len(base), len(behaviors))
+	}
+	if base["full-suite"] != base["plain"] || base["quick-check"] != base["plain"] {
+		t.Fatalf("baseline activations differ: %v", base)
+	}
+
+	result := simulation.NewRunner(t).Run(scenario(0.2))
+	for i, sr := range result.Sessions {
+		if len(sr.Results) != len(behaviors) {
+			t.Errorf("session %s activated %d behaviors, want %d", sessions[i].Label, len(sr.Results), len(behaviors))
+		}
+	}
+
+	eve := result.Sessions[0]
+	if got := eve.Results[0].BehaviorID; got != "full-suite" {
+		t.Errorf("evening: top behavior = %s, want full-suite", got)
+	}
+	eveAct := activations(eve)
+	if eveAct["plain"] != base["plain"] {
+		t.Errorf("evening: plain activation = %f, want unchanged %f", eveAct["plain"], base["plain"])
+	}
+	if eveAct["quick-check"] >= eveAct["plain"] {
+		t.Errorf("evening: quick-check %f should rank below plain %f", eveAct["quick-check"], eveAct["plain"])
+	}
+	// Workload tags are not judged without an intensity
+	if eveAct["deep-review"] != base["deep-review"] {
+		t.Errorf("evening: deep-review activation = %f, want unchanged %f", eveAct["deep-review"], base["deep-review"])
+	}
+
+	morn := activations(result.Sessions[1])
+	if morn["quick-check"] <= morn["full-suite"] {
+		t.Errorf("morning: quick-check %f should outrank full-suite %f", morn["quick-check"], morn["full-suite"])
+	}
+
+	busy := activations(result.Sessions[2])
+	if busy["deep-review"] >= base["deep-review"] {
+		t.Errorf("busy: deep-review activation = %f, want below %f", busy["deep-review"], base["deep-review"])
+	}
+	for id, act := range busy {
+		if act < 0 || act > 1 {
+			t.Errorf("busy: %s activation %f out of [0, 1]", id, act)
+		}
+	}
+}
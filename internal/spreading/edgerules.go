@@ -0,0 +1,56 @@
+package spreading
+
+import (
+	"math"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// EdgeKindRule controls how much activation an edge kind can carry.
+type EdgeKindRule struct {
+	// Transmission is the fraction of energy kept per hop across edges of
+	// this kind, replacing Config.DecayFactor for them. 1.0 transmits fully.
+	Transmission float64
+
+	// RecencyHalfLife, when positive, caps transmission by edge recency: it
+	// halves for every half-life since the edge was last activated (or
+	// created, if never activated). Stale edges fade out even when their
+	// stored weight is high.
+	RecencyHalfLife time.Duration
+}
+
+// DefaultEdgeKindRules returns per-kind rules that favor structural edges
+// over associative ones: requires and specializes transmit fully,
+// similar-to at half strength, and co-activated edges fade with a one-week
+// half-life. Kinds without a rule keep using Config.DecayFactor.
+func DefaultEdgeKindRules() map[store.EdgeKind]EdgeKindRule {
+	return map[store.EdgeKind]EdgeKindRule{
+		store.EdgeKindRequires:    {Transmission: 1.0},
+		store.EdgeKindSpecializes: {Transmission: 1.0},
+		store.EdgeKindSimilarTo:   {Transmission: 0.5},
+		store.EdgeKindCoActivated: {Transmission: 0.7, RecencyHalfLife: 7 * 24 * time.Hour},
+	}
+}
+
+// transmission returns the per-hop energy retention for edge at time now:
+// the edge kind's rule if one is configured, otherwise DecayFactor.
+func (c Config) transmission(edge store.Edge, now time.Time) float64 {
+	rule, ok := c.EdgeRules[edge.Kind]
+	if !ok {
+		return c.DecayFactor
+	}
+	t := rule.Transmission
+	if rule.RecencyHalfLife > 0 {
+		last := edgeLastActivated(edge)
+		if last.IsZero() {
+			last = edge.CreatedAt
+		}
+		if !last.IsZero() {
+			if age := now.Sub(last); age > 0 {
+				t *= math.Exp2(-float64(age) / float64(rule.RecencyHalfLife))
+			}
+		}
+	}
+	return t
+}
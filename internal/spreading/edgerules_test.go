@@ -0,0 +1,107 @@
+package spreading
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestConfig_Transmission(t *testing.T) {
+	now := time.Now()
+	cfg := Config{
+		DecayFactor: 0.7,
+		EdgeRules: map[store.EdgeKind]EdgeKindRule{
+			store.EdgeKindRequires:    {Transmission: 1.0},
+			store.EdgeKindCoActivated: {Transmission: 0.8, RecencyHalfLife: 24 * time.Hour},
+		},
+	}
+
+	tests := []struct {
+		name string
+		edge store.Edge
+		want float64
+	}{
+		{
+			name: "kind without rule uses DecayFactor",
+			edge: store.Edge{Kind: store.EdgeKindSimilarTo},
+			want: 0.7,
+		},
+		{
+			name: "rule replaces DecayFactor",
+			edge: store.Edge{Kind: store.EdgeKindRequires, CreatedAt: now.Add(-30 * 24 * time.Hour)},
+			want: 1.0,
+		},
+		{
+			name: "fresh edge keeps full transmission",
+			edge: store.Edge{Kind: store.EdgeKindCoActivated, LastActivated: timePtr(now)},
+			want: 0.8,
+		},
+		{
+			name: "one half-life halves transmission",
+			edge: store.Edge{Kind: store.EdgeKindCoActivated, LastActivated: timePtr(now.Add(-24 * time.Hour))},
+			want: 0.4,
+		},
+		{
+			name: "never-activated edge ages from creation",
+			edge: store.Edge{Kind: store.EdgeKindCoActivated, CreatedAt: now.Add(-48 * time.Hour)},
+			want: 0.2,
+		},
+		{
+			name: "no timestamps is not capped",
+			edge: store.Edge{Kind: store.EdgeKindCoActivated},
+			want: 0.8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.transmission(tt.edge, now); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("transmission() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngine_EdgeRules(t *testing.T) {
+	s := store.NewInMemoryGraphStore()
+	for _, id := range []string{"a", "b", "c"} {
+		addNode(t, s, id)
+	}
+	addEdge(t, s, "a", "b", store.EdgeKindRequires, 1.0, nil)
+	addEdge(t, s, "a", "c", store.EdgeKindSimilarTo, 1.0, nil)
+
+	seeds := []Seed{{BehaviorID: "a", Activation: 1.0, Source: "test"}}
+	activate := func(cfg Config) (b, c float64) {
+		t.Helper()
+		results, err := NewEngine(s, cfg).Activate(t.Context(), seeds)
+		if err != nil {
+			t.Fatalf("Activate: %v", err)
+		}
+		if r := findResult(results, "b"); r != nil {
+			b = r.Activation
+		}
+		if r := findResult(results, "c"); r != nil {
+			c = r.Activation
+		}
+		return b, c
+	}
+
+	cfg := DefaultConfig()
+	cfg.Inhibition = nil
+	cfg.EdgeRules = nil
+	b, c := activate(cfg)
+	if math.Abs(b-c) > 1e-9 {
+		t.Fatalf("without rules, equal-weight edges should spread equally: b=%v c=%v", b, c)
+	}
+
+	cfg.EdgeRules = map[store.EdgeKind]EdgeKindRule{
+		store.EdgeKindRequires:  {Transmission: 1.0},
+		store.EdgeKindSimilarTo: {Transmission: 0.5},
+	}
+	b, c = activate(cfg)
+	if b <= c {
+		t.Errorf("requires (1.0) should carry more than similar-to (0.5): b=%v c=%v", b, c)
+	}
+}
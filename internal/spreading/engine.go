@@ -48,6 +48,10 @@ type Config struct {
 	// TagProvider supplies behavior tags for feature affinity.
 	// Required when Affinity is enabled; ignored otherwise.
 	TagProvider TagProvider
+
+	// EdgeRules sets per-kind propagation rules. Edge kinds without a rule
+	// use DecayFactor. Default: DefaultEdgeKindRules().
+	EdgeRules map[store.EdgeKind]EdgeKindRule
 }
 
 // DefaultConfig returns the default spreading activation configuration.
@@ -60,6 +64,7 @@ func DefaultConfig() Config {
 		MinActivation:     0.01,
 		TemporalDecayRate: ranking.DefaultDecayRate,
 		Inhibition:        &inh,
+		EdgeRules:         DefaultEdgeKindRules(),
 	}
 }
 
@@ -112,11 +117,13 @@ type StepSnapshot struct {
 
 // propagateStep performs one step of spreading activation.
 // It reads from activation and writes to newActivation (synchronous update).
-// If distance and seedSource are non-nil, it also tracks shortest paths.
+// If distance and seedSource are non-nil, it also tracks shortest paths,
+// and if rec is non-nil, every edge that transmits energy is recorded.
 func (e *Engine) propagateStep(ctx context.Context, activation, newActivation map[string]float64,
 	distance map[string]int, seedSource map[string]string,
-	allTags map[string][]string, affinityEnabled bool) error {
+	allTags map[string][]string, affinityEnabled bool, rec *traceRecorder) error {
 
+	now := time.Now()
 	for nodeID, nodeAct := range activation {
 		if nodeAct < e.config.MinActivation {
 			continue
@@ -166,6 +173,7 @@ func (e *Engine) propagateStep(ctx context.Context, activation, newActivation ma
 			neighbor := neighborID(nodeID, edge)
 
 			effectiveWeight := ranking.EdgeDecay(edge.Weight, edgeLastActivated(edge), e.config.TemporalDecayRate)
+			decay := e.config.transmission(edge, now)
 
 			// Track whether this edge actually spread or suppressed energy,
 			// so we only update distance for edges that did real work.
@@ -176,7 +184,8 @@ func (e *Engine) propagateStep(ctx context.Context, activation, newActivation ma
 				// Conflicts are symmetric — suppress in both directions.
 				// Use conflictCount as the denominator, independent of directional edges.
 				energy := nodeAct * e.config.SpreadFactor * effectiveWeight / float64(conflictCount)
-				energy *= e.config.DecayFactor
+				energy *= decay
+				rec.record(nodeID, neighbor, edge, nodeAct, effectiveWeight, decay, -energy, false)
 				newActivation[neighbor] -= energy
 				if newActivation[neighbor] < 0 {
 					newActivation[neighbor] = 0
@@ -187,7 +196,8 @@ func (e *Engine) propagateStep(ctx context.Context, activation, newActivation ma
 				// Seeding a deprecated node should NOT suppress its replacement.
 				if edge.Source == nodeID {
 					energy := nodeAct * e.config.SpreadFactor * effectiveWeight / float64(directionalSuppressiveCount)
-					energy *= e.config.DecayFactor
+					energy *= decay
+					rec.record(nodeID, neighbor, edge, nodeAct, effectiveWeight, decay, -energy, false)
 					newActivation[neighbor] -= energy
 					if newActivation[neighbor] < 0 {
 						newActivation[neighbor] = 0
@@ -207,7 +217,8 @@ func (e *Engine) propagateStep(ctx context.Context, activation, newActivation ma
 				}
 				// Normal edges spread: use max to prevent runaway activation.
 				energy := nodeAct * e.config.SpreadFactor * effectiveWeight / outDegree
-				energy *= e.config.DecayFactor
+				energy *= decay
+				rec.record(nodeID, neighbor, edge, nodeAct, effectiveWeight, decay, energy, energy > newActivation[neighbor])
 				if energy > newActivation[neighbor] {
 					newActivation[neighbor] = energy
 				}
@@ -274,7 +285,7 @@ func (e *Engine) ActivateWithSteps(ctx context.Context, seeds []Seed) ([]StepSna
 	for step := 0; step < e.config.MaxSteps; step++ {
 		newActivation := copyActivation(activation)
 
-		if err := e.propagateStep(ctx, activation, newActivation, nil, nil, allTags, affinityEnabled); err != nil {
+		if err := e.propagateStep(ctx, activation, newActivation, nil, nil, allTags, affinityEnabled, nil); err != nil {
 			return nil, err
 		}
 
@@ -304,7 +315,14 @@ func (e *Engine) Activate(ctx context.Context, seeds []Seed) ([]Result, error) {
 	if len(seeds) == 0 {
 		return []Result{}, nil
 	}
+	results, _, err := e.activate(ctx, seeds, nil)
+	return results, err
+}
 
+// activate runs spreading activation from seeds, recording edge firings in
+// rec when it is non-nil. Besides the results it returns the activation
+// after propagation, before inhibition and sigmoid.
+func (e *Engine) activate(ctx context.Context, seeds []Seed, rec *traceRecorder) ([]Result, map[string]float64, error) {
 	// Step 1: Initialize maps from seeds.
 	activation := make(map[string]float64)
 	distance := make(map[string]int)
@@ -325,19 +343,30 @@ func (e *Engine) Activate(ctx context.Context, seeds []Seed) ([]Result, error) {
 
 	// Step 2: Propagation loop.
 	for step := 0; step < e.config.MaxSteps; step++ {
+		// Stop between steps once the caller gives up, e.g. at a tool deadline.
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
 		// Create a snapshot of current activations. New activations are
 		// written into a fresh map so that updates within a single step
 		// do not affect each other (synchronous update).
 		newActivation := copyActivation(activation)
 
-		if err := e.propagateStep(ctx, activation, newActivation, distance, seedSource, allTags, affinityEnabled); err != nil {
-			return nil, err
+		rec.startStep(step + 1)
+		if err := e.propagateStep(ctx, activation, newActivation, distance, seedSource, allTags, affinityEnabled, rec); err != nil {
+			return nil, nil, err
 		}
 
 		activation = newActivation
 	}
 
 	// Step 3: Post-process (inhibition + sigmoid + filter).
+	// Post-processing works in place, so a trace keeps its own copy.
+	var raw map[string]float64
+	if rec != nil {
+		raw = copyActivation(activation)
+	}
 	activation = e.postProcess(activation)
 
 	// Step 4: Build results.
@@ -356,7 +385,7 @@ func (e *Engine) Activate(ctx context.Context, seeds []Seed) ([]Result, error) {
 		return results[i].Activation > results[j].Activation
 	})
 
-	return results, nil
+	return results, raw, nil
 }
 
 // copyActivation returns an independent copy of an activation map.
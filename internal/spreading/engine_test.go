@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/nvandessel/floop/internal/ranking"
 	"github.com/nvandessel/floop/internal/store"
 )
 
@@ -338,6 +339,34 @@ func TestEngine_TemporalDecay(t *testing.T) {
 	}
 }
 
+func TestEngine_TemporalDecay_HalfLife(t *testing.T) {
+	// A -> B last activated two days ago: a one-day half-life leaves it a
+	// quarter of its weight, a month-long one nearly all of it.
+	s := store.NewInMemoryGraphStore()
+	addNode(t, s, "A")
+	addNode(t, s, "B")
+	addEdge(t, s, "A", "B", store.EdgeKindRequires, 1.0, timePtr(time.Now().Add(-48*time.Hour)))
+	seeds := []Seed{{BehaviorID: "A", Activation: 1.0, Source: "test"}}
+
+	activationOfB := func(halfLife time.Duration) float64 {
+		cfg := DefaultConfig()
+		cfg.TemporalDecayRate = ranking.HalfLifeDecayRate(halfLife)
+		results, err := NewEngine(s, cfg).Activate(context.Background(), seeds)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if r := findResult(results, "B"); r != nil {
+			return r.Activation
+		}
+		return 0
+	}
+
+	short, long := activationOfB(24*time.Hour), activationOfB(30*24*time.Hour)
+	if short >= long {
+		t.Errorf("B activation with 1d half-life (%f) should be below 30d half-life (%f)", short, long)
+	}
+}
+
 func TestEngine_DepthLimit(t *testing.T) {
 	// Chain of 10 nodes: N0 -> N1 -> N2 -> ... -> N9
 	// With MaxSteps=3, nodes beyond hop 3 should have very low activation.
@@ -0,0 +1,214 @@
+package spreading
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Firing records one edge transmitting energy during propagation.
+type Firing struct {
+	Step     int            `json:"step"` // Propagation step, from 1
+	From     string         `json:"from"`
+	To       string         `json:"to"`
+	EdgeKind store.EdgeKind `json:"edge_kind"`
+
+	// Weight is the stored edge weight; EffectiveWeight is what remains of
+	// it after temporal edge decay.
+	Weight          float64 `json:"weight"`
+	EffectiveWeight float64 `json:"effective_weight"`
+
+	// Decay is the share of energy the edge kind keeps per hop.
+	Decay float64 `json:"decay"`
+
+	// SourceActivation is From's activation going into the step. Energy is
+	// what reached To; it is negative for suppressive edges.
+	SourceActivation float64 `json:"source_activation"`
+	Energy           float64 `json:"energy"`
+
+	// Raised is true when the energy became To's activation, being more
+	// than To had so far in the step.
+	Raised bool `json:"raised,omitempty"`
+}
+
+// Trace is the full record of one spreading activation run: every edge
+// that fired, and the activation before and after post-processing.
+type Trace struct {
+	Seeds []Seed
+	Steps int
+
+	// Firings are ordered by step, then source, then target.
+	Firings []Firing
+
+	// Raw is the activation after the last step, before inhibition and
+	// sigmoid.
+	Raw map[string]float64
+
+	// Results are what Activate would have returned.
+	Results []Result
+}
+
+// Explain performs spreading activation from seeds like Activate, recording
+// the propagation for inspection.
+func (e *Engine) Explain(ctx context.Context, seeds []Seed) (*Trace, error) {
+	trace := &Trace{Seeds: seeds, Steps: e.config.MaxSteps, Raw: map[string]float64{}, Results: []Result{}}
+	if len(seeds) == 0 {
+		return trace, nil
+	}
+	rec := &traceRecorder{}
+	results, raw, err := e.activate(ctx, seeds, rec)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(rec.firings, func(i, j int) bool {
+		a, b := rec.firings[i], rec.firings[j]
+		if a.Step != b.Step {
+			return a.Step < b.Step
+		}
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+	trace.Firings, trace.Raw, trace.Results = rec.firings, raw, results
+	return trace, nil
+}
+
+// Explanation is how a trace accounts for one behavior.
+type Explanation struct {
+	BehaviorID string `json:"behavior_id"`
+
+	// Reached is true when the behavior is among the results. Seed is true
+	// when it was seeded directly.
+	Reached bool `json:"reached"`
+	Seed    bool `json:"seed"`
+
+	// Activation is the final activation; RawActivation is the activation
+	// before inhibition and sigmoid.
+	Activation    float64 `json:"activation"`
+	RawActivation float64 `json:"raw_activation"`
+	Distance      int     `json:"distance"`
+	SeedSource    string  `json:"seed_source,omitempty"`
+
+	// Path is the chain of firings from a seed (see Trace.Path); Incoming
+	// is every firing that reached the behavior.
+	Path     []Firing `json:"path"`
+	Incoming []Firing `json:"incoming"`
+}
+
+// Explain accounts for behaviorID in the trace.
+func (t *Trace) Explain(behaviorID string) Explanation {
+	x := Explanation{
+		BehaviorID:    behaviorID,
+		RawActivation: t.Raw[behaviorID],
+		Path:          t.Path(behaviorID),
+		Incoming:      t.Into(behaviorID),
+	}
+	for _, seed := range t.Seeds {
+		if seed.BehaviorID == behaviorID {
+			x.Seed = true
+		}
+	}
+	if r, ok := t.Result(behaviorID); ok {
+		x.Reached = true
+		x.Activation, x.Distance, x.SeedSource = r.Activation, r.Distance, r.SeedSource
+	}
+	if x.Path == nil {
+		x.Path = []Firing{}
+	}
+	if x.Incoming == nil {
+		x.Incoming = []Firing{}
+	}
+	return x
+}
+
+// Result returns the result for behaviorID, if the run activated it.
+func (t *Trace) Result(behaviorID string) (Result, bool) {
+	for _, r := range t.Results {
+		if r.BehaviorID == behaviorID {
+			return r, true
+		}
+	}
+	return Result{}, false
+}
+
+// Into returns the firings that reached behaviorID, in step order.
+func (t *Trace) Into(behaviorID string) []Firing {
+	var into []Firing
+	for _, f := range t.Firings {
+		if f.To == behaviorID {
+			into = append(into, f)
+		}
+	}
+	return into
+}
+
+// Path returns the chain of firings that carried activation from a seed to
+// behaviorID, seed first: for each node, the strongest firing that raised
+// it in the latest step that did. It is empty for seeds no edge raised
+// and for behaviors the run never reached.
+func (t *Trace) Path(behaviorID string) []Firing {
+	var path []Firing
+	visited := make(map[string]bool)
+	node, step := behaviorID, t.Steps
+	for !visited[node] {
+		visited[node] = true
+		f, ok := t.strongestRaise(node, step)
+		if !ok {
+			break
+		}
+		path = append(path, f)
+		node, step = f.From, f.Step-1
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// strongestRaise finds the strongest firing that raised node in the latest
+// step up to maxStep with one.
+func (t *Trace) strongestRaise(node string, maxStep int) (Firing, bool) {
+	var best Firing
+	found := false
+	for _, f := range t.Firings {
+		if f.To != node || !f.Raised || f.Step > maxStep {
+			continue
+		}
+		if !found || f.Step > best.Step || (f.Step == best.Step && f.Energy > best.Energy) {
+			best, found = f, true
+		}
+	}
+	return best, found
+}
+
+// traceRecorder collects firings during Explain. A nil recorder records
+// nothing, so Activate pays only for the nil checks.
+type traceRecorder struct {
+	step    int
+	firings []Firing
+}
+
+func (r *traceRecorder) startStep(step int) {
+	if r != nil {
+		r.step = step
+	}
+}
+
+func (r *traceRecorder) record(from, to string, edge store.Edge, sourceActivation, effectiveWeight, decay, energy float64, raised bool) {
+	if r == nil {
+		return
+	}
+	r.firings = append(r.firings, Firing{
+		Step:             r.step,
+		From:             from,
+		To:               to,
+		EdgeKind:         edge.Kind,
+		Weight:           edge.Weight,
+		EffectiveWeight:  effectiveWeight,
+		Decay:            decay,
+		SourceActivation: sourceActivation,
+		Energy:           energy,
+		Raised:           raised,
+	})
+}
@@ -0,0 +1,106 @@
+package spreading
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestEngine_Explain(t *testing.T) {
+	// A -> B -> C, and A conflicts with X
+	s := store.NewInMemoryGraphStore()
+	for _, id := range []string{"A", "B", "C", "X"} {
+		addNode(t, s, id)
+	}
+	now := time.Now()
+	addEdge(t, s, "A", "B", store.EdgeKindRequires, 1.0, timePtr(now))
+	addEdge(t, s, "B", "C", store.EdgeKindRequires, 0.8, timePtr(now))
+	addEdge(t, s, "A", "X", store.EdgeKindConflicts, 1.0, timePtr(now))
+
+	cfg := DefaultConfig()
+	eng := NewEngine(s, cfg)
+	seeds := []Seed{{BehaviorID: "A", Activation: 1.0, Source: "test"}}
+
+	trace, err := eng.Explain(context.Background(), seeds)
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+	results, err := eng.Activate(context.Background(), seeds)
+	if err != nil {
+		t.Fatalf("Activate() error = %v", err)
+	}
+
+	// Explaining a run does not change it
+	if len(trace.Results) != len(results) {
+		t.Fatalf("trace has %d results, Activate %d", len(trace.Results), len(results))
+	}
+	for _, r := range results {
+		got, ok := trace.Result(r.BehaviorID)
+		if !ok || math.Abs(got.Activation-r.Activation) > 1e-9 || got.Distance != r.Distance {
+			t.Errorf("trace result %s = %+v, want %+v", r.BehaviorID, got, r)
+		}
+	}
+
+	path := trace.Path("C")
+	if len(path) != 2 || path[0].From != "A" || path[0].To != "B" || path[0].Step != 1 ||
+		path[1].From != "B" || path[1].To != "C" || path[1].Step != 2 {
+		t.Fatalf("Path(C) = %+v, want A->B at step 1, B->C at step 2", path)
+	}
+	hop := path[0]
+	if hop.SourceActivation != 1.0 || hop.Weight != 1.0 || !hop.Raised {
+		t.Errorf("first hop = %+v, want a raising firing from full activation", hop)
+	}
+	want := hop.SourceActivation * cfg.SpreadFactor * hop.EffectiveWeight * hop.Decay
+	if math.Abs(hop.Energy-want) > 1e-9 {
+		t.Errorf("first hop energy = %f, want %f", hop.Energy, want)
+	}
+	if path[1].Weight != 0.8 || path[1].EffectiveWeight > path[1].Weight {
+		t.Errorf("second hop weights = %f effective %f, want 0.8 or less", path[1].Weight, path[1].EffectiveWeight)
+	}
+
+	if p := trace.Path("A"); len(p) != 0 {
+		t.Errorf("Path(A) = %+v, want empty for the seed", p)
+	}
+	if p := trace.Path("missing"); len(p) != 0 {
+		t.Errorf("Path(missing) = %+v, want empty", p)
+	}
+
+	into := trace.Into("X")
+	if len(into) == 0 || into[0].From != "A" || into[0].EdgeKind != store.EdgeKindConflicts || into[0].Energy >= 0 || into[0].Raised {
+		t.Errorf("Into(X) = %+v, want a suppressive firing from A", into)
+	}
+
+	if trace.Raw["B"] <= 0 || trace.Steps != cfg.MaxSteps {
+		t.Errorf("raw B = %f, steps = %d", trace.Raw["B"], trace.Steps)
+	}
+	x := trace.Explain("C")
+	if x.Seed || len(x.Path) != 2 || len(x.Incoming) == 0 || x.RawActivation <= 0 {
+		t.Errorf("Explain(C) = %+v, want a spread-only behavior two hops from A", x)
+	}
+	if _, ok := trace.Result("C"); ok != x.Reached {
+		t.Errorf("Explain(C).Reached = %v, want %v", x.Reached, ok)
+	}
+	if x := trace.Explain("A"); !x.Seed || !x.Reached || x.Distance != 0 || x.SeedSource != "test" {
+		t.Errorf("Explain(A) = %+v, want the reached seed", x)
+	}
+
+	for i := 1; i < len(trace.Firings); i++ {
+		if trace.Firings[i].Step < trace.Firings[i-1].Step {
+			t.Fatalf("firings out of step order at %d", i)
+		}
+	}
+}
+
+func TestEngine_ExplainNoSeeds(t *testing.T) {
+	eng := NewEngine(store.NewInMemoryGraphStore(), DefaultConfig())
+	trace, err := eng.Explain(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+	if len(trace.Firings) != 0 || len(trace.Results) != 0 {
+		t.Errorf("trace = %+v, want empty", trace)
+	}
+}
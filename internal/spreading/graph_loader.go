@@ -10,14 +10,15 @@ import "C"
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/nvandessel/floop/internal/ranking"
 	"github.com/nvandessel/floop/internal/store"
 )
 
 // loadGraph loads all edges from the store, builds the IDMap, pre-materializes
-// affinity edges, pre-applies temporal decay, and calls sproink_graph_build to
-// create the CSR graph.
+// affinity edges, pre-applies temporal decay and per-kind edge rules, and
+// calls sproink_graph_build to create the CSR graph.
 func loadGraph(ctx context.Context, s store.ExtendedGraphStore, config Config) (*C.SproinkGraph, *IDMap, error) {
 	// Step a: bulk load all edges
 	edges, err := s.GetAllEdges(ctx)
@@ -88,6 +89,7 @@ func loadGraph(ctx context.Context, s store.ExtendedGraphStore, config Config) (
 	weights := make([]float64, numEdges)
 	kinds := make([]uint8, numEdges)
 
+	now := time.Now()
 	for i, e := range edges {
 		sources[i] = idmap.GetOrAssign(e.Source)
 		targets[i] = idmap.GetOrAssign(e.Target)
@@ -97,6 +99,11 @@ func loadGraph(ctx context.Context, s store.ExtendedGraphStore, config Config) (
 		if e.LastActivated != nil {
 			w = ranking.EdgeDecay(e.Weight, *e.LastActivated, config.TemporalDecayRate)
 		}
+		// Pre-apply per-kind rules: sproink applies DecayFactor to every
+		// edge, so scale the weight by the rule's transmission relative to it.
+		if _, ok := config.EdgeRules[e.Kind]; ok && config.DecayFactor > 0 {
+			w *= config.transmission(e, now) / config.DecayFactor
+		}
 		weights[i] = w
 
 		kinds[i] = edgeKindToU8(e.Kind)
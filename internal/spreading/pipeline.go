@@ -25,6 +25,12 @@ func NewPipeline(s store.GraphStore, config Config) *Pipeline {
 	}
 }
 
+// WithStandingSeeds sets project priorities injected into every run.
+func (p *Pipeline) WithStandingSeeds(standing []StandingSeed) *Pipeline {
+	p.selector.WithStandingSeeds(standing)
+	return p
+}
+
 // Run performs the full activation pipeline for the given context.
 // Returns activated behaviors sorted by activation level.
 func (p *Pipeline) Run(ctx context.Context, actCtx models.ContextSnapshot) ([]Result, error) {
@@ -37,3 +43,13 @@ func (p *Pipeline) Run(ctx context.Context, actCtx models.ContextSnapshot) ([]Re
 	}
 	return p.engine.Activate(ctx, seeds)
 }
+
+// Explain runs the pipeline for the given context like Run, recording the
+// propagation for inspection.
+func (p *Pipeline) Explain(ctx context.Context, actCtx models.ContextSnapshot) (*Trace, error) {
+	seeds, err := p.selector.SelectSeeds(ctx, actCtx)
+	if err != nil {
+		return nil, fmt.Errorf("seed selection: %w", err)
+	}
+	return p.engine.Explain(ctx, seeds)
+}
@@ -17,6 +17,7 @@ import (
 type SeedSelector struct {
 	store     store.GraphStore
 	evaluator *activation.Evaluator
+	standing  []StandingSeed
 }
 
 // NewSeedSelector creates a new seed selector.
@@ -27,6 +28,13 @@ func NewSeedSelector(s store.GraphStore) *SeedSelector {
 	}
 }
 
+// WithStandingSeeds sets project priorities injected into every seed set
+// on top of the context matches.
+func (s *SeedSelector) WithStandingSeeds(standing []StandingSeed) *SeedSelector {
+	s.standing = standing
+	return s
+}
+
 // SelectSeeds determines seed nodes from the current context.
 // It uses the existing activation.Evaluator to find behaviors matching
 // the context, then converts matches to seeds with activation proportional
@@ -38,9 +46,15 @@ func NewSeedSelector(s store.GraphStore) *SeedSelector {
 //   - Specificity 3+ (three+ conditions matched) -> activation 0.8-1.0
 //   - No 'when' conditions (always-active) -> activation 0.3 (lower, less specific)
 //
+// Standing seeds then add their activation to the behaviors they target.
+//
 // Returns seeds sorted by activation descending.
 func (s *SeedSelector) SelectSeeds(ctx context.Context, actCtx models.ContextSnapshot) ([]Seed, error) {
-	// Step 1: Query all behaviors from the store.
+	// Step 1: Query all behaviors from the store, skipping partitions that
+	// cannot match the context.
+	ctx = store.WithPartitionFilter(ctx, func(when map[string]interface{}) bool {
+		return s.evaluator.MayMatch(actCtx, when)
+	})
 	nodes, err := s.store.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
 	if err != nil {
 		return nil, fmt.Errorf("querying behavior nodes: %w", err)
@@ -58,7 +72,7 @@ func (s *SeedSelector) SelectSeeds(ctx context.Context, actCtx models.ContextSna
 
 	// Step 3: Evaluate which behaviors match the context.
 	matches := s.evaluator.Evaluate(actCtx, behaviors)
-	if len(matches) == 0 {
+	if len(matches) == 0 && len(s.standing) == 0 {
 		return []Seed{}, nil
 	}
 
@@ -74,6 +88,8 @@ func (s *SeedSelector) SelectSeeds(ctx context.Context, actCtx models.ContextSna
 		})
 	}
 
+	seeds = AddStandingSeeds(seeds, ResolveStandingSeeds(behaviors, s.standing))
+
 	// Step 5: Sort seeds by activation descending.
 	sort.Slice(seeds, func(i, j int) bool {
 		return seeds[i].Activation > seeds[j].Activation
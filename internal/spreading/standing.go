@@ -0,0 +1,129 @@
+package spreading
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// standingSourcePrefix starts the source label of every standing seed.
+const standingSourcePrefix = "standing:"
+
+// StandingSeed is a project priority injected into every spreading run, so
+// organizationally critical behaviors stay near the surface without being
+// pinned. It targets either every behavior carrying Tag or the single
+// behavior BehaviorID.
+type StandingSeed struct {
+	Tag        string
+	BehaviorID string
+
+	// Activation is the energy injected. Zero means
+	// constants.DefaultStandingSeedActivation.
+	Activation float64
+}
+
+// Source returns the seed source label, e.g. "standing:tag=security".
+func (s StandingSeed) Source() string {
+	if s.Tag != "" {
+		return standingSourcePrefix + "tag=" + s.Tag
+	}
+	return standingSourcePrefix + "behavior=" + s.BehaviorID
+}
+
+// IsStandingSource reports whether a seed source label came from a
+// standing seed rather than the context.
+func IsStandingSource(source string) bool {
+	return strings.HasPrefix(source, standingSourcePrefix)
+}
+
+func (s StandingSeed) activation() float64 {
+	if s.Activation <= 0 {
+		return constants.DefaultStandingSeedActivation
+	}
+	return s.Activation
+}
+
+// ResolveStandingSeeds expands standing seeds into seeds for the behaviors
+// they target. A behavior targeted by several standing seeds receives the
+// strongest of them.
+func ResolveStandingSeeds(behaviors []models.Behavior, standing []StandingSeed) []Seed {
+	if len(standing) == 0 {
+		return nil
+	}
+	var seeds []Seed
+	index := make(map[string]int)
+	add := func(id string, ss StandingSeed) {
+		seed := Seed{BehaviorID: id, Activation: ss.activation(), Source: ss.Source()}
+		if i, ok := index[id]; !ok {
+			index[id] = len(seeds)
+			seeds = append(seeds, seed)
+		} else if seed.Activation > seeds[i].Activation {
+			seeds[i] = seed
+		}
+	}
+	for _, b := range behaviors {
+		for _, ss := range standing {
+			if ss.BehaviorID != "" && ss.BehaviorID == b.ID {
+				add(b.ID, ss)
+			} else if ss.Tag != "" && hasTag(b.Content.Tags, ss.Tag) {
+				add(b.ID, ss)
+			}
+		}
+	}
+	return seeds
+}
+
+// StandingSeedsFromStore resolves standing seeds against every behavior in s.
+// It queries nothing when no standing seeds are configured.
+func StandingSeedsFromStore(ctx context.Context, s store.GraphStore, standing []StandingSeed) ([]Seed, error) {
+	if len(standing) == 0 {
+		return nil, nil
+	}
+	nodes, err := s.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, fmt.Errorf("querying behavior nodes: %w", err)
+	}
+	behaviors := make([]models.Behavior, 0, len(nodes))
+	for _, node := range nodes {
+		behaviors = append(behaviors, models.NodeToBehavior(node))
+	}
+	return ResolveStandingSeeds(behaviors, standing), nil
+}
+
+// AddStandingSeeds injects standing seeds into context seeds. A behavior
+// that is already a seed keeps its source and gains the standing activation
+// on top, capped at 1.0; other targets are appended as new seeds.
+func AddStandingSeeds(seeds, standing []Seed) []Seed {
+	if len(standing) == 0 {
+		return seeds
+	}
+	index := make(map[string]int, len(seeds))
+	for i, s := range seeds {
+		index[s.BehaviorID] = i
+	}
+	for _, st := range standing {
+		if i, ok := index[st.BehaviorID]; ok {
+			seeds[i].Activation += st.Activation
+			if seeds[i].Activation > 1.0 {
+				seeds[i].Activation = 1.0
+			}
+			continue
+		}
+		index[st.BehaviorID] = len(seeds)
+		seeds = append(seeds, st)
+	}
+	return seeds
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
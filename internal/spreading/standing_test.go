@@ -0,0 +1,120 @@
+package spreading
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestResolveStandingSeeds(t *testing.T) {
+	behaviors := []models.Behavior{
+		{ID: "sec-1", Content: models.BehaviorContent{Tags: []string{"security", "go"}}},
+		{ID: "sec-2", Content: models.BehaviorContent{Tags: []string{"security"}}},
+		{ID: "docs", Content: models.BehaviorContent{Tags: []string{"docs"}}},
+		{ID: "pinned"},
+	}
+	standing := []StandingSeed{
+		{Tag: "security"},
+		{BehaviorID: "pinned", Activation: 0.3},
+		{BehaviorID: "sec-2", Activation: 0.4},
+		{BehaviorID: "missing", Activation: 0.4},
+	}
+
+	seeds := ResolveStandingSeeds(behaviors, standing)
+
+	want := map[string]struct {
+		activation float64
+		source     string
+	}{
+		"sec-1":  {constants.DefaultStandingSeedActivation, "standing:tag=security"},
+		"sec-2":  {0.4, "standing:behavior=sec-2"},
+		"pinned": {0.3, "standing:behavior=pinned"},
+	}
+	if len(seeds) != len(want) {
+		t.Fatalf("ResolveStandingSeeds() = %+v, want %d seeds", seeds, len(want))
+	}
+	for _, seed := range seeds {
+		w, ok := want[seed.BehaviorID]
+		if !ok {
+			t.Errorf("unexpected seed %s", seed.BehaviorID)
+			continue
+		}
+		if seed.Activation != w.activation || seed.Source != w.source {
+			t.Errorf("seed %s = (%v, %s), want (%v, %s)", seed.BehaviorID, seed.Activation, seed.Source, w.activation, w.source)
+		}
+		if !IsStandingSource(seed.Source) {
+			t.Errorf("IsStandingSource(%q) = false, want true", seed.Source)
+		}
+	}
+}
+
+func TestAddStandingSeeds(t *testing.T) {
+	seeds := []Seed{
+		{BehaviorID: "a", Activation: 0.6, Source: "context:language=go"},
+		{BehaviorID: "b", Activation: 0.9, Source: "context:task=testing"},
+	}
+	standing := []Seed{
+		{BehaviorID: "a", Activation: 0.2, Source: "standing:tag=security"},
+		{BehaviorID: "b", Activation: 0.2, Source: "standing:tag=security"},
+		{BehaviorID: "c", Activation: 0.2, Source: "standing:tag=security"},
+	}
+
+	got := AddStandingSeeds(seeds, standing)
+
+	if len(got) != 3 {
+		t.Fatalf("AddStandingSeeds() returned %d seeds, want 3", len(got))
+	}
+	if math.Abs(got[0].Activation-0.8) > 1e-9 || got[0].Source != "context:language=go" {
+		t.Errorf("seed a = %+v, want activation 0.8 with its context source", got[0])
+	}
+	if got[1].Activation != 1.0 {
+		t.Errorf("seed b activation = %v, want capped at 1.0", got[1].Activation)
+	}
+	if got[2].BehaviorID != "c" || got[2].Activation != 0.2 {
+		t.Errorf("seed c = %+v, want appended at 0.2", got[2])
+	}
+	if IsStandingSource(got[0].Source) {
+		t.Error("context seed should keep a non-standing source")
+	}
+}
+
+func TestPipeline_StandingSeeds(t *testing.T) {
+	s := store.NewInMemoryGraphStore()
+	addBehaviorNode(t, s, "go-directive", "go-directive", map[string]interface{}{"language": "go"})
+	addBehaviorNode(t, s, "python-security", "python-security", map[string]interface{}{"language": "python"})
+	ctx := context.Background()
+
+	actCtx := models.ContextSnapshot{FileLanguage: "go"}
+
+	results, err := NewPipeline(s, DefaultConfig()).Run(ctx, actCtx)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if findResult(results, "python-security") != nil {
+		t.Fatal("python-security should not activate without a standing seed")
+	}
+
+	standing := []StandingSeed{{BehaviorID: "python-security", Activation: constants.MaxStandingSeedActivation}}
+	results, err = NewPipeline(s, DefaultConfig()).WithStandingSeeds(standing).Run(ctx, actCtx)
+	if err != nil {
+		t.Fatalf("Run() with standing seeds error = %v", err)
+	}
+	r := findResult(results, "python-security")
+	if r == nil {
+		t.Fatal("expected python-security in results with a standing seed")
+	}
+	if r.SeedSource != "standing:behavior=python-security" {
+		t.Errorf("SeedSource = %q, want standing:behavior=python-security", r.SeedSource)
+	}
+}
+
+func TestStandingSeedsFromStore_None(t *testing.T) {
+	seeds, err := StandingSeedsFromStore(context.Background(), store.NewInMemoryGraphStore(), nil)
+	if err != nil || seeds != nil {
+		t.Errorf("StandingSeedsFromStore(nil) = %v, %v, want nil, nil", seeds, err)
+	}
+}
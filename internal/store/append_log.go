@@ -0,0 +1,211 @@
+package store
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// DefaultAppendLogCompactThreshold is the number of log entries after which
+// the append log is compacted into nodes.jsonl.
+const DefaultAppendLogCompactThreshold = 1000
+
+// Append log operations.
+const (
+	logOpUpsert = "upsert"
+	logOpDelete = "delete"
+)
+
+// NodeLogEntry is a single line in nodes.log.jsonl: Op is "upsert", with
+// the node, or "delete".
+type NodeLogEntry struct {
+	Op   string `json:"op"`
+	ID   string `json:"id"`
+	Node *Node  `json:"node,omitempty"`
+}
+
+// EnableAppendLog switches incremental syncs from rewriting nodes.jsonl to
+// appending upserts and deletes to nodes.log.jsonl. The log is compacted into
+// nodes.jsonl once it holds compactThreshold entries, and again on Close.
+// A compactThreshold <= 0 uses DefaultAppendLogCompactThreshold.
+//
+// This is intended for long-running processes that sync frequently (e.g. the
+// MCP server); one-shot commands gain nothing from it.
+func (s *SQLiteGraphStore) EnableAppendLog(compactThreshold int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if compactThreshold <= 0 {
+		compactThreshold = DefaultAppendLogCompactThreshold
+	}
+
+	// Count entries left behind by a previous process so compaction still
+	// triggers at the right size.
+	entries, err := readNodeLog(s.nodesLogFile)
+	if err != nil {
+		return fmt.Errorf("failed to read append log: %w", err)
+	}
+
+	s.appendLog = true
+	s.logCompactAt = compactThreshold
+	s.logEntries = len(entries)
+	return nil
+}
+
+// syncNodeLog is the append-log variant of the node export in Sync.
+// Caller must hold the write lock.
+func (s *SQLiteGraphStore) syncNodeLog(ctx context.Context, dirtyOps []dirtyOperation) error {
+	// The log is only meaningful on top of an existing nodes.jsonl
+	if _, err := os.Stat(s.nodesFile); os.IsNotExist(err) {
+		return s.rewriteNodes(ctx)
+	}
+
+	if len(dirtyOps) > 0 {
+		if err := s.appendNodeLog(ctx, dirtyOps); err != nil {
+			// Fall back to full export on error
+			return s.rewriteNodes(ctx)
+		}
+	}
+
+	if s.logEntries >= s.logCompactAt {
+		if err := s.compactNodeLog(ctx); err != nil {
+			return fmt.Errorf("failed to compact append log: %w", err)
+		}
+	}
+	return nil
+}
+
+// appendNodeLog appends one entry per dirty operation to the append log.
+// Caller must hold the write lock.
+func (s *SQLiteGraphStore) appendNodeLog(ctx context.Context, dirtyOps []dirtyOperation) error {
+	entries := make([]NodeLogEntry, 0, len(dirtyOps))
+	for _, op := range dirtyOps {
+		if op.Operation == "delete" {
+			entries = append(entries, NodeLogEntry{Op: logOpDelete, ID: op.BehaviorID})
+			continue
+		}
+		node, err := s.getNodeUnlocked(ctx, op.BehaviorID)
+		if err != nil {
+			return fmt.Errorf("failed to get updated node %s: %w", op.BehaviorID, err)
+		}
+		if node == nil {
+			// Inserted then deleted before this sync
+			entries = append(entries, NodeLogEntry{Op: logOpDelete, ID: op.BehaviorID})
+			continue
+		}
+		s.enrichNodeWithEmbedding(ctx, node)
+		entries = append(entries, NodeLogEntry{Op: logOpUpsert, ID: node.ID, Node: node})
+	}
+
+	f, err := os.OpenFile(s.nodesLogFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open append log: %w", err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	encoder := json.NewEncoder(w)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("failed to encode log entry: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write append log: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed to fsync append log: %w", err)
+	}
+
+	s.logEntries += len(entries)
+	return nil
+}
+
+// compactNodeLog folds the append log into nodes.jsonl and removes it.
+// SQLite is the source of truth, so compaction is a full export.
+// Caller must hold the write lock.
+func (s *SQLiteGraphStore) compactNodeLog(ctx context.Context) error {
+	if _, err := os.Stat(s.nodesLogFile); os.IsNotExist(err) {
+		s.logEntries = 0
+		return nil
+	}
+	return s.rewriteNodes(ctx)
+}
+
+// rewriteNodes performs a full export to nodes.jsonl and discards the log.
+// Caller must hold the write lock.
+func (s *SQLiteGraphStore) rewriteNodes(ctx context.Context) error {
+	if err := s.exportNodesToJSONL(ctx); err != nil {
+		return fmt.Errorf("failed to export nodes: %w", err)
+	}
+	if err := os.Remove(s.nodesLogFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove append log: %w", err)
+	}
+
+	s.logEntries = 0
+	return nil
+}
+
+// replayNodeLog applies the append log on top of the imported nodes.jsonl.
+func (s *SQLiteGraphStore) replayNodeLog(ctx context.Context) error {
+	entries, err := readNodeLog(s.nodesLogFile)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		switch entry.Op {
+		case logOpUpsert:
+			if entry.Node == nil {
+				continue
+			}
+			if err := s.importNode(ctx, *entry.Node); err != nil {
+				return err
+			}
+		case logOpDelete:
+			if _, err := s.db.ExecContext(ctx, `DELETE FROM behaviors WHERE id = ?`, entry.ID); err != nil {
+				return fmt.Errorf("failed to delete node %s: %w", entry.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// readNodeLog reads all entries from an append log. A missing log yields no
+// entries. Unparseable lines (e.g. a torn final write) are skipped.
+func readNodeLog(path string) ([]NodeLogEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open append log: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 1024*1024) // 1MB max line length
+
+	var entries []NodeLogEntry
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry NodeLogEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to parse nodes.log.jsonl line %d: %v\n", lineNum, err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return entries, fmt.Errorf("error reading append log: %w", err)
+	}
+	return entries, nil
+}
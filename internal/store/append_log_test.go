@@ -0,0 +1,182 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func appendLogTestNode(id string) Node {
+	return Node{
+		ID:   id,
+		Kind: NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name": id,
+			"kind": "directive",
+			"content": map[string]interface{}{
+				"canonical": "Behavior " + id,
+			},
+		},
+	}
+}
+
+func newAppendLogStore(t *testing.T, dir string, threshold int) *SQLiteGraphStore {
+	t.Helper()
+	s, err := NewSQLiteGraphStore(dir)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	if err := s.EnableAppendLog(threshold); err != nil {
+		t.Fatalf("EnableAppendLog() error = %v", err)
+	}
+	return s
+}
+
+func readFileString(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(%s) error = %v", path, err)
+	}
+	return string(data)
+}
+
+func TestAppendLog_SyncAppendsAndCloseCompacts(t *testing.T) {
+	tmpDir := t.TempDir()
+	s := newAppendLogStore(t, tmpDir, 0)
+	ctx := context.Background()
+
+	nodesFile := filepath.Join(tmpDir, ".floop", "nodes.jsonl")
+	logFile := filepath.Join(tmpDir, ".floop", "nodes.log.jsonl")
+
+	mustAddNode(t, s, ctx, appendLogTestNode("b-1"))
+	mustAddNode(t, s, ctx, appendLogTestNode("b-2"))
+
+	// First sync has no nodes.jsonl to append to, so it does a full export
+	if err := s.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if _, err := os.Stat(logFile); !os.IsNotExist(err) {
+		t.Error("append log should not exist after initial full export")
+	}
+
+	mustAddNode(t, s, ctx, appendLogTestNode("b-3"))
+	if err := s.DeleteNode(ctx, "b-1"); err != nil {
+		t.Fatalf("DeleteNode() error = %v", err)
+	}
+	if err := s.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	entries, err := readNodeLog(logFile)
+	if err != nil {
+		t.Fatalf("readNodeLog() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("log entries = %d, want 2", len(entries))
+	}
+	if strings.Contains(readFileString(t, nodesFile), "b-3") {
+		t.Error("nodes.jsonl should not be rewritten by an appending sync")
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if _, err := os.Stat(logFile); !os.IsNotExist(err) {
+		t.Error("append log should be removed after Close")
+	}
+	content := readFileString(t, nodesFile)
+	if strings.Contains(content, `"b-1"`) {
+		t.Error("nodes.jsonl should not contain deleted b-1 after compaction")
+	}
+	if !strings.Contains(content, `"b-2"`) || !strings.Contains(content, `"b-3"`) {
+		t.Error("nodes.jsonl should contain b-2 and b-3 after compaction")
+	}
+}
+
+func TestAppendLog_CompactsAtThreshold(t *testing.T) {
+	tmpDir := t.TempDir()
+	s := newAppendLogStore(t, tmpDir, 2)
+	defer s.Close()
+	ctx := context.Background()
+
+	logFile := filepath.Join(tmpDir, ".floop", "nodes.log.jsonl")
+
+	mustAddNode(t, s, ctx, appendLogTestNode("b-1"))
+	if err := s.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	mustAddNode(t, s, ctx, appendLogTestNode("b-2"))
+	if err := s.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if _, err := os.Stat(logFile); err != nil {
+		t.Fatalf("append log should exist below threshold: %v", err)
+	}
+
+	mustAddNode(t, s, ctx, appendLogTestNode("b-3"))
+	if err := s.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if _, err := os.Stat(logFile); !os.IsNotExist(err) {
+		t.Error("append log should be compacted once it reaches the threshold")
+	}
+	content := readFileString(t, filepath.Join(tmpDir, ".floop", "nodes.jsonl"))
+	for _, id := range []string{"b-1", "b-2", "b-3"} {
+		if !strings.Contains(content, `"`+id+`"`) {
+			t.Errorf("nodes.jsonl missing %s after compaction", id)
+		}
+	}
+}
+
+func TestAppendLog_ReplayedOnImport(t *testing.T) {
+	srcDir := t.TempDir()
+	s := newAppendLogStore(t, srcDir, 0)
+	defer s.Close()
+	ctx := context.Background()
+
+	mustAddNode(t, s, ctx, appendLogTestNode("b-1"))
+	mustAddNode(t, s, ctx, appendLogTestNode("b-2"))
+	if err := s.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	mustAddNode(t, s, ctx, appendLogTestNode("b-3"))
+	if err := s.DeleteNode(ctx, "b-1"); err != nil {
+		t.Fatalf("DeleteNode() error = %v", err)
+	}
+	if err := s.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	// Copy the uncompacted JSONL files into a fresh project (no database)
+	dstDir := t.TempDir()
+	dstFloop := filepath.Join(dstDir, ".floop")
+	if err := os.MkdirAll(dstFloop, 0700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	for _, name := range []string{"nodes.jsonl", "nodes.log.jsonl"} {
+		data := readFileString(t, filepath.Join(srcDir, ".floop", name))
+		if err := os.WriteFile(filepath.Join(dstFloop, name), []byte(data), 0600); err != nil {
+			t.Fatalf("WriteFile(%s) error = %v", name, err)
+		}
+	}
+
+	dst, err := NewSQLiteGraphStore(dstDir)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	defer dst.Close()
+
+	if n := mustGetNode(t, dst, ctx, "b-1"); n != nil {
+		t.Error("b-1 should have been deleted by log replay")
+	}
+	for _, id := range []string{"b-2", "b-3"} {
+		if n := mustGetNode(t, dst, ctx, id); n == nil {
+			t.Errorf("%s should have been imported", id)
+		}
+	}
+}
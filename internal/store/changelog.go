@@ -0,0 +1,251 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ChangelogFile is the behavior changelog kept in a store's .floop
+// directory when the changelog is enabled.
+const ChangelogFile = "CHANGELOG.md"
+
+// changelogHeader starts a new changelog file.
+const changelogHeader = `# Behavior changelog
+
+Changes to the behaviors in this store, appended by floop when
+changelog.enabled is set. One line per change, oldest first.
+
+`
+
+// changelogEnabled is read when a SQLite store is opened.
+var changelogEnabled atomic.Bool
+
+// SetChangelogEnabled makes every SQLite store opened from now on append its
+// behavior changes to CHANGELOG.md in its .floop directory, meant to be
+// committed alongside the code.
+func SetChangelogEnabled(enabled bool) {
+	changelogEnabled.Store(enabled)
+}
+
+// ChangelogEntry is one line of the behavior changelog.
+type ChangelogEntry struct {
+	Time time.Time
+	// Action is what happened: learned, added, imported, updated, forgotten,
+	// deprecated, merged, retired, quarantined, restored, or deleted.
+	Action  string
+	ID      string
+	Name    string
+	Summary string
+	Target  string // Name of the behavior merged into or replacing this one
+	Reason  string
+}
+
+// String renders the entry as a Markdown list item.
+func (e ChangelogEntry) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "- %s **%s** `%s`", e.Time.UTC().Format("2006-01-02 15:04"), e.Action, e.Name)
+	if e.Target != "" {
+		if e.Action == "merged" {
+			sb.WriteString(" into")
+		} else {
+			sb.WriteString(", replaced by")
+		}
+		fmt.Fprintf(&sb, " `%s`", e.Target)
+	}
+	if e.Summary != "" {
+		fmt.Fprintf(&sb, ": %s", e.Summary)
+	}
+	if e.Reason != "" {
+		fmt.Fprintf(&sb, " (%s)", e.Reason)
+	}
+	fmt.Fprintf(&sb, " [%s]", e.ID)
+	return sb.String()
+}
+
+// noteChange records the state of node id before its first change since the
+// last sync. Caller must hold the write lock.
+func (s *SQLiteGraphStore) noteChange(ctx context.Context, id string) {
+	if s.changelogFile == "" {
+		return
+	}
+	if _, ok := s.changedBefore[id]; ok {
+		return
+	}
+	before, err := s.getNodeUnlocked(ctx, id)
+	if err != nil {
+		before = nil
+	}
+	if s.changedBefore == nil {
+		s.changedBefore = make(map[string]*Node)
+	}
+	s.changedBefore[id] = before
+	s.changeOrder = append(s.changeOrder, id)
+}
+
+// flushChangelog appends an entry for each behavior changed since the last
+// sync. Caller must hold the write lock.
+func (s *SQLiteGraphStore) flushChangelog(ctx context.Context) error {
+	if len(s.changeOrder) == 0 {
+		return nil
+	}
+	order, before := s.changeOrder, s.changedBefore
+	s.changeOrder, s.changedBefore = nil, nil
+
+	now := time.Now()
+	var lines []string
+	for _, id := range order {
+		after, err := s.getNodeUnlocked(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get changed node %s: %w", id, err)
+		}
+		entry, ok := changelogEntry(before[id], after, now)
+		if !ok {
+			continue
+		}
+		if targetID := changelogTarget(after); targetID != "" {
+			entry.Target = targetID
+			if target, err := s.getNodeUnlocked(ctx, targetID); err == nil && target != nil {
+				entry.Target = nodeName(target)
+			}
+		}
+		lines = append(lines, entry.String())
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	f, err := os.OpenFile(s.changelogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open changelog: %w", err)
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil && info.Size() == 0 {
+		if _, err := f.WriteString(changelogHeader); err != nil {
+			return fmt.Errorf("failed to write changelog: %w", err)
+		}
+	}
+	if _, err := f.WriteString(strings.Join(lines, "\n") + "\n"); err != nil {
+		return fmt.Errorf("failed to write changelog: %w", err)
+	}
+	return nil
+}
+
+// changelogActions maps the kind a behavior moved to onto the action logged.
+var changelogActions = map[NodeKind]string{
+	NodeKindForgotten:   "forgotten",
+	NodeKindDeprecated:  "deprecated",
+	NodeKindMerged:      "merged",
+	NodeKindRetired:     "retired",
+	NodeKindQuarantined: "quarantined",
+	NodeKindBehavior:    "restored",
+}
+
+// changelogReasons lists, per action, the metadata key holding its reason.
+var changelogReasons = map[string]string{
+	"forgotten":   "forget_reason",
+	"deprecated":  "deprecation_reason",
+	"merged":      "merged_reason",
+	"retired":     "retire_reason",
+	"quarantined": "quarantine_reason",
+}
+
+// changelogEntry describes the change from before to after, either of which
+// may be nil. It reports false for changes not worth logging: non-behavior
+// nodes and changes to confidence, priority, or stats alone.
+func changelogEntry(before, after *Node, now time.Time) (ChangelogEntry, bool) {
+	switch {
+	case after == nil && before == nil:
+		return ChangelogEntry{}, false
+	case after == nil:
+		if !isBehaviorKind(before.Kind) {
+			return ChangelogEntry{}, false
+		}
+		return ChangelogEntry{Time: now, Action: "deleted", ID: before.ID, Name: nodeName(before)}, true
+	case !isBehaviorKind(after.Kind):
+		return ChangelogEntry{}, false
+	}
+
+	entry := ChangelogEntry{Time: now, ID: after.ID, Name: nodeName(after)}
+	switch {
+	case before == nil:
+		entry.Action = "added"
+		if prov, ok := after.Content["provenance"].(map[string]interface{}); ok {
+			switch prov["source_type"] {
+			case "learned", "failure":
+				entry.Action = "learned"
+			case "imported", "pack":
+				entry.Action = "imported"
+			}
+		}
+		if after.Kind != NodeKindBehavior {
+			entry.Action = changelogActions[after.Kind]
+		}
+		entry.Summary = nodeSummary(after)
+	case before.Kind != after.Kind:
+		entry.Action = changelogActions[after.Kind]
+		if key, ok := changelogReasons[entry.Action]; ok {
+			entry.Reason, _ = after.Metadata[key].(string)
+		}
+	case !sameJSONFields(before.Content, after.Content, "name", "kind", "content", "when"):
+		entry.Action = "updated"
+		entry.Summary = nodeSummary(after)
+	default:
+		return ChangelogEntry{}, false
+	}
+	return entry, true
+}
+
+// changelogTarget returns the ID of the behavior that absorbed or replaces n.
+func changelogTarget(n *Node) string {
+	if n == nil {
+		return ""
+	}
+	switch n.Kind {
+	case NodeKindMerged:
+		id, _ := n.Metadata["merged_into"].(string)
+		return id
+	case NodeKindDeprecated:
+		id, _ := n.Metadata["replacement_id"].(string)
+		return id
+	}
+	return ""
+}
+
+func nodeName(n *Node) string {
+	if name, ok := n.Content["name"].(string); ok && name != "" {
+		return name
+	}
+	return n.ID
+}
+
+// nodeSummary returns a one-line description of n: its summary, or the
+// first line of its canonical content, shortened to 100 characters.
+func nodeSummary(n *Node) string {
+	content, _ := n.Content["content"].(map[string]interface{})
+	text, _ := content["summary"].(string)
+	if text == "" {
+		text, _ = content["canonical"].(string)
+	}
+	text, _, _ = strings.Cut(strings.TrimSpace(text), "\n")
+	if r := []rune(text); len(r) > 100 {
+		text = strings.TrimSpace(string(r[:99])) + "…"
+	}
+	return text
+}
+
+// sameJSONFields compares fields of a and b by their JSON encoding.
+func sameJSONFields(a, b map[string]interface{}, fields ...string) bool {
+	for _, f := range fields {
+		aj, errA := json.Marshal(a[f])
+		bj, errB := json.Marshal(b[f])
+		if errA != nil || errB != nil || string(aj) != string(bj) {
+			return false
+		}
+	}
+	return true
+}
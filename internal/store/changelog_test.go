@@ -0,0 +1,169 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func changelogNode(id, name, canonical string) Node {
+	return Node{
+		ID:   id,
+		Kind: NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":       name,
+			"kind":       "directive",
+			"content":    map[string]interface{}{"canonical": canonical},
+			"provenance": map[string]interface{}{"source_type": "learned"},
+		},
+		Metadata: map[string]interface{}{"confidence": 0.6},
+	}
+}
+
+func TestSQLiteGraphStore_Changelog(t *testing.T) {
+	SetChangelogEnabled(true)
+	t.Cleanup(func() { SetChangelogEnabled(false) })
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, err := NewSQLiteGraphStore(dir)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	defer s.Close()
+
+	for _, n := range []Node{
+		changelogNode("b-1", "wrap-errors", "Wrap errors with %w"),
+		changelogNode("b-2", "wrap-errors-fmt", "Wrap errors using fmt.Errorf"),
+		changelogNode("b-3", "no-panic", "Never use panic"),
+	} {
+		if _, err := s.AddNode(ctx, n); err != nil {
+			t.Fatalf("AddNode(%s) error = %v", n.ID, err)
+		}
+	}
+	if err := s.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	// A content update, a merge, a forget, and a confidence-only change
+	updated := changelogNode("b-1", "wrap-errors", "Wrap errors with %w and context")
+	if err := s.UpdateNode(ctx, updated); err != nil {
+		t.Fatal(err)
+	}
+	merged := changelogNode("b-2", "wrap-errors-fmt", "Wrap errors using fmt.Errorf")
+	merged.Kind = NodeKindMerged
+	merged.Metadata["merged_into"] = "b-1"
+	if err := s.UpdateNode(ctx, merged); err != nil {
+		t.Fatal(err)
+	}
+	forgotten := changelogNode("b-3", "no-panic", "Never use panic")
+	forgotten.Kind = NodeKindForgotten
+	forgotten.Metadata["forget_reason"] = "too strict"
+	if err := s.UpdateNode(ctx, forgotten); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateConfidence(ctx, "b-1", 0.9); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ".floop", ChangelogFile))
+	if err != nil {
+		t.Fatalf("reading changelog: %v", err)
+	}
+	got := string(data)
+	if !strings.HasPrefix(got, "# Behavior changelog") {
+		t.Errorf("changelog missing header:\n%s", got)
+	}
+	wants := []string{
+		"**learned** `wrap-errors`: Wrap errors with %w [b-1]",
+		"**learned** `no-panic`: Never use panic [b-3]",
+		"**updated** `wrap-errors`: Wrap errors with %w and context [b-1]",
+		"**merged** `wrap-errors-fmt` into `wrap-errors` [b-2]",
+		"**forgotten** `no-panic` (too strict) [b-3]",
+	}
+	for _, want := range wants {
+		if !strings.Contains(got, want) {
+			t.Errorf("changelog missing %q:\n%s", want, got)
+		}
+	}
+	if n := strings.Count(got, "\n- "); n != 6 {
+		t.Errorf("changelog has %d entries, want 6:\n%s", n, got)
+	}
+}
+
+func TestSQLiteGraphStore_ChangelogDisabled(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, err := NewSQLiteGraphStore(dir)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	defer s.Close()
+
+	if _, err := s.AddNode(ctx, changelogNode("b-1", "wrap-errors", "Wrap errors with %w")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".floop", ChangelogFile)); !os.IsNotExist(err) {
+		t.Error("changelog written while disabled")
+	}
+}
+
+func TestChangelogEntry(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	base := changelogNode("b-1", "wrap-errors", "Wrap errors with %w\nso callers can inspect them")
+	withKind := func(kind NodeKind, meta map[string]interface{}) *Node {
+		n := changelogNode("b-1", "wrap-errors", "Wrap errors with %w\nso callers can inspect them")
+		n.Kind = kind
+		for k, v := range meta {
+			n.Metadata[k] = v
+		}
+		return &n
+	}
+	reworded := changelogNode("b-1", "wrap-errors", "Always wrap errors")
+	imported := changelogNode("b-1", "wrap-errors", "Wrap errors with %w")
+	imported.Content["provenance"] = map[string]interface{}{"source_type": "imported"}
+	retuned := changelogNode("b-1", "wrap-errors", "Wrap errors with %w\nso callers can inspect them")
+	retuned.Metadata["confidence"] = 0.95
+
+	tests := []struct {
+		name   string
+		before *Node
+		after  *Node
+		want   string
+	}{
+		{"learned", nil, &base, "- 2026-03-01 09:30 **learned** `wrap-errors`: Wrap errors with %w [b-1]"},
+		{"imported", nil, &imported, "- 2026-03-01 09:30 **imported** `wrap-errors`: Wrap errors with %w [b-1]"},
+		{"updated", &base, &reworded, "- 2026-03-01 09:30 **updated** `wrap-errors`: Always wrap errors [b-1]"},
+		{"deprecated", &base, withKind(NodeKindDeprecated, map[string]interface{}{"deprecation_reason": "outdated"}), "- 2026-03-01 09:30 **deprecated** `wrap-errors` (outdated) [b-1]"},
+		{"restored", withKind(NodeKindForgotten, nil), &base, "- 2026-03-01 09:30 **restored** `wrap-errors` [b-1]"},
+		{"deleted", &base, nil, "- 2026-03-01 09:30 **deleted** `wrap-errors` [b-1]"},
+		{"confidence only", &base, &retuned, ""},
+		{"not a behavior", nil, &Node{ID: "c-1", Kind: NodeKindCorrection}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, ok := changelogEntry(tt.before, tt.after, now)
+			if tt.want == "" {
+				if ok {
+					t.Errorf("changelogEntry() = %q, want no entry", entry)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("changelogEntry() reported no entry")
+			}
+			if got := entry.String(); got != tt.want {
+				t.Errorf("changelogEntry() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
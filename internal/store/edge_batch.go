@@ -0,0 +1,179 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrEdgeEndpointNotFound is reported for an edge whose source or target is
+// not in the store.
+var ErrEdgeEndpointNotFound = errors.New("edge endpoint not found")
+
+// EdgeError reports why one edge of a batch was not added.
+type EdgeError struct {
+	Index int  // Position of the edge in the batch
+	Edge  Edge // The rejected edge
+	Err   error
+}
+
+// Error implements error.
+func (e EdgeError) Error() string {
+	return fmt.Sprintf("edge %d (%s -> %s, %s): %v", e.Index, e.Edge.Source, e.Edge.Target, e.Edge.Kind, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e EdgeError) Unwrap() error {
+	return e.Err
+}
+
+// EdgeBatcher is implemented by stores that add many edges at once.
+type EdgeBatcher interface {
+	// BatchAddEdges validates edges and adds the valid ones in a single
+	// transaction per underlying database. Edges with an invalid weight, no
+	// CreatedAt, or a missing endpoint are skipped and reported, one
+	// EdgeError each. A non-nil error means the batch failed and no edge of
+	// the failing transaction was written.
+	BatchAddEdges(ctx context.Context, edges []Edge) ([]EdgeError, error)
+}
+
+// edgeInserter is implemented by stores that can write already validated
+// edges in one transaction.
+type edgeInserter interface {
+	insertEdges(ctx context.Context, edges []Edge) error
+}
+
+// BatchAddEdges adds edges to s with the semantics of EdgeBatcher. Stores
+// that are not EdgeBatchers get the same validation, but their edges are
+// added one AddEdge call at a time.
+func BatchAddEdges(ctx context.Context, s GraphStore, edges []Edge) ([]EdgeError, error) {
+	if batcher, ok := s.(EdgeBatcher); ok {
+		return batcher.BatchAddEdges(ctx, edges)
+	}
+
+	var rejected []EdgeError
+	for i, edge := range edges {
+		if err := validateEdge(edge); err != nil {
+			rejected = append(rejected, EdgeError{Index: i, Edge: edge, Err: err})
+			continue
+		}
+		found, err := endpointsExist(ctx, s, edge)
+		if err != nil {
+			return rejected, err
+		}
+		if !found {
+			rejected = append(rejected, EdgeError{Index: i, Edge: edge, Err: danglingEdgeError(edge)})
+			continue
+		}
+		if err := s.AddEdge(ctx, edge); err != nil {
+			rejected = append(rejected, EdgeError{Index: i, Edge: edge, Err: err})
+		}
+	}
+	return rejected, nil
+}
+
+// validateEdge checks the fields every store requires of a new edge.
+func validateEdge(edge Edge) error {
+	if edge.Weight <= 0 || edge.Weight > 1.0 {
+		return fmt.Errorf("edge weight must be in (0.0, 1.0], got %f", edge.Weight)
+	}
+	if edge.CreatedAt.IsZero() {
+		return fmt.Errorf("edge CreatedAt must be set")
+	}
+	return nil
+}
+
+// danglingEdgeError describes an edge with a missing endpoint.
+func danglingEdgeError(edge Edge) error {
+	return fmt.Errorf("%w: source=%s, target=%s", ErrEdgeEndpointNotFound, edge.Source, edge.Target)
+}
+
+// endpointsExist reports whether both endpoints of edge are nodes of s.
+func endpointsExist(ctx context.Context, s GraphStore, edge Edge) (bool, error) {
+	for _, id := range []string{edge.Source, edge.Target} {
+		node, err := s.GetNode(ctx, id)
+		if err != nil {
+			return false, fmt.Errorf("failed to check edge endpoint %s: %w", id, err)
+		}
+		if node == nil {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// batchAddRouted implements EdgeBatcher for stores that spread edges over
+// several underlying stores: route picks the store an edge is written to.
+func batchAddRouted(ctx context.Context, edges []Edge, route func(Edge) (GraphStore, error)) ([]EdgeError, error) {
+	groups, rejected, err := routeEdgeBatch(edges, route)
+	if err != nil {
+		return rejected, err
+	}
+	failed, err := insertEdgeGroups(ctx, groups)
+	rejected = append(rejected, failed...)
+	sort.Slice(rejected, func(i, j int) bool { return rejected[i].Index < rejected[j].Index })
+	return rejected, err
+}
+
+// edgeGroup is the valid edges of a batch bound for one store, with their
+// positions in the batch.
+type edgeGroup struct {
+	store   GraphStore
+	edges   []Edge
+	indices []int
+}
+
+// routeEdgeBatch validates edges and groups the valid ones by the store
+// route picks for them. route returns a nil store for an edge with a missing
+// endpoint. Groups keep the order in which their stores were first picked.
+func routeEdgeBatch(edges []Edge, route func(Edge) (GraphStore, error)) ([]*edgeGroup, []EdgeError, error) {
+	var (
+		groups   []*edgeGroup
+		rejected []EdgeError
+	)
+	byStore := make(map[GraphStore]*edgeGroup)
+	for i, edge := range edges {
+		if err := validateEdge(edge); err != nil {
+			rejected = append(rejected, EdgeError{Index: i, Edge: edge, Err: err})
+			continue
+		}
+		dst, err := route(edge)
+		if err != nil {
+			return nil, rejected, err
+		}
+		if dst == nil {
+			rejected = append(rejected, EdgeError{Index: i, Edge: edge, Err: danglingEdgeError(edge)})
+			continue
+		}
+		g, ok := byStore[dst]
+		if !ok {
+			g = &edgeGroup{store: dst}
+			byStore[dst] = g
+			groups = append(groups, g)
+		}
+		g.edges = append(g.edges, edge)
+		g.indices = append(g.indices, i)
+	}
+	return groups, rejected, nil
+}
+
+// insertEdgeGroups writes each group to its store, in one transaction per
+// store where the store supports it.
+func insertEdgeGroups(ctx context.Context, groups []*edgeGroup) ([]EdgeError, error) {
+	var rejected []EdgeError
+	for _, g := range groups {
+		if inserter, ok := g.store.(edgeInserter); ok {
+			if err := inserter.insertEdges(ctx, g.edges); err != nil {
+				return rejected, err
+			}
+			continue
+		}
+		for j, edge := range g.edges {
+			if err := g.store.AddEdge(ctx, edge); err != nil {
+				rejected = append(rejected, EdgeError{Index: g.indices[j], Edge: edge, Err: err})
+			}
+		}
+	}
+	return rejected, nil
+}
@@ -0,0 +1,126 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// plainStore hides the EdgeBatcher implementation of the store it wraps.
+type plainStore struct {
+	GraphStore
+}
+
+func TestBatchAddEdges(t *testing.T) {
+	ctx := context.Background()
+	stores := map[string]func(t *testing.T) GraphStore{
+		"memory": func(t *testing.T) GraphStore { return NewInMemoryGraphStore() },
+		"sqlite": func(t *testing.T) GraphStore {
+			s, err := NewSQLiteGraphStore(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { s.Close() })
+			return s
+		},
+		"multi":         func(t *testing.T) GraphStore { return newTestMultiStore(t) },
+		"without batch": func(t *testing.T) GraphStore { return plainStore{NewInMemoryGraphStore()} },
+		"multi wrapped": func(t *testing.T) GraphStore { return plainStore{newTestMultiStore(t)} },
+	}
+
+	now := time.Now()
+	batch := []Edge{
+		{Source: "a", Target: "b", Kind: EdgeKindRequires, Weight: 1, CreatedAt: now},
+		{Source: "a", Target: "c", Kind: EdgeKindSimilarTo, Weight: 1.5, CreatedAt: now},
+		{Source: "a", Target: "missing", Kind: EdgeKindSimilarTo, Weight: 0.5, CreatedAt: now},
+		{Source: "b", Target: "c", Kind: EdgeKindSimilarTo, Weight: 0.5},
+		{Source: "a", Target: "c", Kind: EdgeKindSimilarTo, Weight: 0.7, CreatedAt: now},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			s := newStore(t)
+			for _, id := range []string{"a", "b", "c"} {
+				mustAddNode(t, s, ctx, namedNode(id, id, NodeKindBehavior))
+			}
+
+			rejected, err := BatchAddEdges(ctx, s, batch)
+			if err != nil {
+				t.Fatalf("BatchAddEdges() error = %v", err)
+			}
+			var indices []int
+			for _, r := range rejected {
+				indices = append(indices, r.Index)
+			}
+			if len(indices) != 3 || indices[0] != 1 || indices[1] != 2 || indices[2] != 3 {
+				t.Fatalf("rejected = %v, want edges 1, 2, and 3", rejected)
+			}
+			if !errors.Is(rejected[1], ErrEdgeEndpointNotFound) || errors.Is(rejected[0], ErrEdgeEndpointNotFound) {
+				t.Errorf("rejected errors = %v, want only edge 2 dangling", rejected)
+			}
+
+			out := mustGetEdges(t, s, ctx, "a", DirectionOutbound, "")
+			if len(out) != 2 {
+				t.Errorf("edges from a = %v, want a->b and a->c", out)
+			}
+		})
+	}
+}
+
+func TestMultiGraphStore_BatchAddEdges_Routing(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMultiStore(t)
+	mustAddNode(t, m.localStore, ctx, namedNode("l1", "l1", NodeKindBehavior))
+	mustAddNode(t, m.localStore, ctx, namedNode("l2", "l2", NodeKindBehavior))
+	mustAddNode(t, m.globalStore, ctx, namedNode("g1", "g1", NodeKindBehavior))
+
+	now := time.Now()
+	rejected, err := m.BatchAddEdges(ctx, []Edge{
+		{Source: "l1", Target: "l2", Kind: EdgeKindRequires, Weight: 1, CreatedAt: now},
+		{Source: "l1", Target: "g1", Kind: EdgeKindSimilarTo, Weight: 0.5, CreatedAt: now},
+	})
+	if err != nil || len(rejected) != 0 {
+		t.Fatalf("BatchAddEdges() = %v, %v", rejected, err)
+	}
+	if local := mustGetEdges(t, m.localStore, ctx, "l1", DirectionOutbound, ""); len(local) != 1 || local[0].Target != "l2" {
+		t.Errorf("local edges = %v, want l1->l2", local)
+	}
+	if global := mustGetEdges(t, m.globalStore, ctx, "l1", DirectionOutbound, ""); len(global) != 1 || global[0].Target != "g1" {
+		t.Errorf("global edges = %v, want the cross-store l1->g1", global)
+	}
+}
+
+func TestPartitionedGraphStore_BatchAddEdges(t *testing.T) {
+	ctx := context.Background()
+	root := newPartitionTestStore(t)
+	if _, err := RebalancePartitions(ctx, root, RebalanceOptions{Strategy: PartitionByLanguage, MinSize: 2}); err != nil {
+		t.Fatalf("RebalancePartitions() error = %v", err)
+	}
+	p, err := NewPartitionedGraphStore(root)
+	if err != nil {
+		t.Fatalf("NewPartitionedGraphStore() error = %v", err)
+	}
+	defer p.Close()
+
+	now := time.Now()
+	rejected, err := p.BatchAddEdges(ctx, []Edge{
+		{Source: "go-2", Target: "go-3", Kind: EdgeKindRequires, Weight: 1, CreatedAt: now},
+		{Source: "go-2", Target: "any-1", Kind: EdgeKindSimilarTo, Weight: 0.5, CreatedAt: now},
+		{Source: "go-2", Target: "missing", Kind: EdgeKindSimilarTo, Weight: 0.5, CreatedAt: now},
+	})
+	if err != nil || len(rejected) != 1 || rejected[0].Index != 2 {
+		t.Fatalf("BatchAddEdges() = %v, %v, want edge 2 rejected", rejected, err)
+	}
+
+	goPart, err := p.partition("lang-go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edges := mustGetEdges(t, goPart, ctx, "go-2", DirectionOutbound, EdgeKindRequires); len(edges) != 1 {
+		t.Errorf("lang-go edges = %v, want go-2->go-3", edges)
+	}
+	if edges := mustGetEdges(t, p.core, ctx, "go-2", DirectionOutbound, EdgeKindSimilarTo); len(edges) != 1 || edges[0].Target != "any-1" {
+		t.Errorf("core edges = %v, want the cross-partition go-2->any-1", edges)
+	}
+}
@@ -43,52 +43,62 @@ func (s *SQLiteGraphStore) ImportNodesFromJSONL(ctx context.Context, path string
 			continue
 		}
 
-		// Extract embedding data before adding node (addBehavior strips unknown metadata)
-		var embStr string
-		var embModel string
-		if node.Metadata != nil {
-			if v, ok := node.Metadata["embedding"].(string); ok && v != "" {
-				embStr = v
-			}
-			if v, ok := node.Metadata["embedding_model"].(string); ok {
-				embModel = v
-			}
-			// Remove embedding keys so they don't pollute metadata_extra
-			delete(node.Metadata, "embedding")
-			delete(node.Metadata, "embedding_model")
+		if err := s.importNode(ctx, node); err != nil {
+			return err
 		}
+	}
 
-		// Add the node (uses INSERT OR REPLACE)
-		if isBehaviorKind(node.Kind) {
-			if _, err := s.addBehavior(ctx, node); err != nil {
-				return fmt.Errorf("failed to import node %s: %w", node.ID, err)
-			}
-		} else {
-			if _, err := s.addGenericNode(ctx, node); err != nil {
-				return fmt.Errorf("failed to import node %s: %w", node.ID, err)
-			}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("scanner error: %w", err)
+	}
+
+	return nil
+}
+
+// importNode adds a single exported node to the database, restoring any
+// base64-encoded embedding carried in its metadata.
+func (s *SQLiteGraphStore) importNode(ctx context.Context, node Node) error {
+	// Extract embedding data before adding node (addBehavior strips unknown metadata)
+	var embStr string
+	var embModel string
+	if node.Metadata != nil {
+		if v, ok := node.Metadata["embedding"].(string); ok && v != "" {
+			embStr = v
+		}
+		if v, ok := node.Metadata["embedding_model"].(string); ok {
+			embModel = v
+		}
+		// Remove embedding keys so they don't pollute metadata_extra
+		delete(node.Metadata, "embedding")
+		delete(node.Metadata, "embedding_model")
+	}
+
+	// Add the node (uses INSERT OR REPLACE)
+	if isBehaviorKind(node.Kind) {
+		if _, err := s.addBehavior(ctx, node); err != nil {
+			return fmt.Errorf("failed to import node %s: %w", node.ID, err)
 		}
+	} else {
+		if _, err := s.addGenericNode(ctx, node); err != nil {
+			return fmt.Errorf("failed to import node %s: %w", node.ID, err)
+		}
+	}
 
-		// Restore embedding if present in JSONL metadata
-		if embStr != "" {
-			embBytes, err := base64.StdEncoding.DecodeString(embStr)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "warning: failed to decode embedding for %s: %v\n", node.ID, err)
-			} else {
-				vector := decodeEmbedding(embBytes)
-				if vector != nil {
-					if err := s.storeEmbeddingUnlocked(ctx, node.ID, vector, embModel); err != nil {
-						fmt.Fprintf(os.Stderr, "warning: failed to store embedding for %s: %v\n", node.ID, err)
-					}
+	// Restore embedding if present in JSONL metadata
+	if embStr != "" {
+		embBytes, err := base64.StdEncoding.DecodeString(embStr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to decode embedding for %s: %v\n", node.ID, err)
+		} else {
+			vector := decodeEmbedding(embBytes)
+			if vector != nil {
+				if err := s.storeEmbeddingUnlocked(ctx, node.ID, vector, embModel); err != nil {
+					fmt.Fprintf(os.Stderr, "warning: failed to store embedding for %s: %v\n", node.ID, err)
 				}
 			}
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("scanner error: %w", err)
-	}
-
 	return nil
 }
 
@@ -135,12 +145,12 @@ func (s *SQLiteGraphStore) ImportEdgesFromJSONL(ctx context.Context, path string
 
 		var createdAtStr sql.NullString
 		if !edge.CreatedAt.IsZero() {
-			createdAtStr = sql.NullString{String: edge.CreatedAt.Format(time.RFC3339), Valid: true}
+			createdAtStr = sql.NullString{String: formatTimestamp(edge.CreatedAt), Valid: true}
 		}
 
 		var lastActivatedStr sql.NullString
 		if edge.LastActivated != nil && !edge.LastActivated.IsZero() {
-			lastActivatedStr = sql.NullString{String: edge.LastActivated.Format(time.RFC3339), Valid: true}
+			lastActivatedStr = sql.NullString{String: formatTimestamp(*edge.LastActivated), Valid: true}
 		}
 
 		_, err := s.db.ExecContext(ctx, `
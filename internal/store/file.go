@@ -350,7 +350,7 @@ func (s *FileGraphStore) Sync(ctx context.Context) error {
 	return nil
 }
 
-// writeNodes writes all nodes to the JSONL file.
+// writeNodes writes all nodes to the JSONL file, ordered by ID.
 func (s *FileGraphStore) writeNodes() error {
 	f, err := os.Create(s.nodesFile)
 	if err != nil {
@@ -358,8 +358,14 @@ func (s *FileGraphStore) writeNodes() error {
 	}
 	defer f.Close()
 
-	encoder := json.NewEncoder(f)
+	nodes := make([]Node, 0, len(s.nodes))
 	for _, node := range s.nodes {
+		nodes = append(nodes, node)
+	}
+	sortNodes(nodes)
+
+	encoder := json.NewEncoder(f)
+	for _, node := range nodes {
 		if err := encoder.Encode(node); err != nil {
 			return err
 		}
@@ -367,7 +373,8 @@ func (s *FileGraphStore) writeNodes() error {
 	return nil
 }
 
-// writeEdges writes all edges to the JSONL file.
+// writeEdges writes all edges to the JSONL file, ordered by source, target,
+// and kind.
 func (s *FileGraphStore) writeEdges() error {
 	f, err := os.Create(s.edgesFile)
 	if err != nil {
@@ -375,8 +382,11 @@ func (s *FileGraphStore) writeEdges() error {
 	}
 	defer f.Close()
 
+	edges := append([]Edge(nil), s.edges...)
+	sortEdges(edges)
+
 	encoder := json.NewEncoder(f)
-	for _, edge := range s.edges {
+	for _, edge := range edges {
 		if err := encoder.Encode(edge); err != nil {
 			return err
 		}
@@ -0,0 +1,474 @@
+package store
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Files a GitSyncStore keeps on its sync branch.
+const (
+	SyncNodesFile = "nodes.jsonl"
+	SyncEdgesFile = "edges.jsonl"
+)
+
+// GitSyncConfig configures a GitSyncStore.
+type GitSyncConfig struct {
+	// Remote is the git URL or path of the repository behaviors are shared
+	// through.
+	Remote string
+
+	// Branch is the branch holding the shared nodes.jsonl and edges.jsonl.
+	Branch string
+
+	// Dir is the working clone used for syncing, conventionally
+	// .floop/sync. It is created on first use.
+	Dir string
+}
+
+// GitSyncResult reports what a pull or push did.
+type GitSyncResult struct {
+	Added      []string `json:"added"`   // Shared behaviors new to this store
+	Updated    []string `json:"updated"` // Shared behaviors newer than the local copy
+	Kept       []string `json:"kept"`    // Local behaviors newer than, or conflicting with, the shared copy
+	EdgesAdded int      `json:"edges_added"`
+
+	// Commit is the commit pushed, empty when there was nothing to push.
+	Commit string `json:"commit,omitempty"`
+}
+
+// GitSyncStore layers git-backed sharing over a GraphStore. Pull merges the
+// nodes and edges on a git branch into the store, and Push merges the store
+// back onto the branch, so a team can share learned behaviors without
+// copying SQLite files.
+//
+// Conflicts are resolved per node: records with the same content hash are
+// equal, and otherwise the one with the later updated_at wins. Usage stats
+// are never shared. Deletions are not propagated; forget a behavior instead.
+type GitSyncStore struct {
+	GraphStore
+	cfg GitSyncConfig
+}
+
+// NewGitSyncStore returns a GitSyncStore syncing s through cfg.
+func NewGitSyncStore(s GraphStore, cfg GitSyncConfig) (*GitSyncStore, error) {
+	if cfg.Remote == "" {
+		return nil, fmt.Errorf("git sync remote is required")
+	}
+	if cfg.Branch == "" {
+		return nil, fmt.Errorf("git sync branch is required")
+	}
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("git sync directory is required")
+	}
+	// git runs in Dir, so a relative path to a local remote must be resolved first
+	if _, err := os.Stat(cfg.Remote); err == nil {
+		if abs, err := filepath.Abs(cfg.Remote); err == nil {
+			cfg.Remote = abs
+		}
+	}
+	return &GitSyncStore{GraphStore: s, cfg: cfg}, nil
+}
+
+// Pull fetches the sync branch and merges it into the store.
+func (g *GitSyncStore) Pull(ctx context.Context) (*GitSyncResult, error) {
+	shared, err := g.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return g.merge(ctx, shared)
+}
+
+// Push pulls, then commits the merged nodes and edges to the sync branch
+// and pushes it. A push rejected because someone else pushed first can be
+// retried.
+func (g *GitSyncStore) Push(ctx context.Context) (*GitSyncResult, error) {
+	shared, err := g.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := g.merge(ctx, shared)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, edges, err := g.syncRecords(ctx, shared)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeSyncFiles(g.cfg.Dir, nodes, edges); err != nil {
+		return nil, err
+	}
+
+	if _, err := g.git(ctx, "add", SyncNodesFile, SyncEdgesFile); err != nil {
+		return nil, err
+	}
+	status, err := g.git(ctx, "status", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+	if status == "" {
+		return result, nil
+	}
+	msg := fmt.Sprintf("floop sync: %d nodes, %d edges", len(nodes), len(edges))
+	if _, err := g.git(ctx, append(g.identity(ctx), "commit", "-q", "-m", msg)...); err != nil {
+		return nil, err
+	}
+	if _, err := g.git(ctx, "push", "-q", "origin", "HEAD:refs/heads/"+g.cfg.Branch); err != nil {
+		return nil, fmt.Errorf("%w (run the push again to merge the new changes)", err)
+	}
+	if result.Commit, err = g.git(ctx, "rev-parse", "--short", "HEAD"); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// syncShared is the content of the sync branch.
+type syncShared struct {
+	nodes map[string]Node
+	edges []Edge
+}
+
+// fetch prepares the working clone and checks out the remote sync branch,
+// returning its nodes and edges. A branch that doesn't exist yet is empty.
+func (g *GitSyncStore) fetch(ctx context.Context) (*syncShared, error) {
+	if _, err := os.Stat(filepath.Join(g.cfg.Dir, ".git")); os.IsNotExist(err) {
+		if err := os.MkdirAll(g.cfg.Dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create sync directory: %w", err)
+		}
+		if _, err := g.git(ctx, "init", "-q"); err != nil {
+			return nil, err
+		}
+		if _, err := g.git(ctx, "remote", "add", "origin", g.cfg.Remote); err != nil {
+			return nil, err
+		}
+	} else if _, err := g.git(ctx, "remote", "set-url", "origin", g.cfg.Remote); err != nil {
+		return nil, err
+	}
+
+	heads, err := g.git(ctx, "ls-remote", "--heads", "origin", g.cfg.Branch)
+	if err != nil {
+		return nil, err
+	}
+	if heads == "" {
+		// Nothing shared yet; the first push creates the branch
+		if _, err := g.git(ctx, "symbolic-ref", "HEAD", "refs/heads/"+g.cfg.Branch); err != nil {
+			return nil, err
+		}
+		return &syncShared{nodes: map[string]Node{}}, nil
+	}
+	if _, err := g.git(ctx, "fetch", "-q", "origin", g.cfg.Branch); err != nil {
+		return nil, err
+	}
+	if _, err := g.git(ctx, "checkout", "-q", "-f", "-B", g.cfg.Branch, "FETCH_HEAD"); err != nil {
+		return nil, err
+	}
+	return readSyncFiles(g.cfg.Dir)
+}
+
+// merge applies the shared nodes and edges to the store.
+func (g *GitSyncStore) merge(ctx context.Context, shared *syncShared) (*GitSyncResult, error) {
+	result := &GitSyncResult{}
+	ids := make([]string, 0, len(shared.nodes))
+	for id := range shared.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		remote := shared.nodes[id]
+		local, err := g.GetNode(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get node %s: %w", id, err)
+		}
+		switch {
+		case local == nil:
+			remote.Metadata = withoutStats(remote.Metadata)
+			if _, err := g.AddNode(ctx, remote); err != nil {
+				var dupErr *DuplicateContentError
+				if errors.As(err, &dupErr) {
+					result.Kept = append(result.Kept, id)
+					continue
+				}
+				return nil, fmt.Errorf("failed to add node %s: %w", id, err)
+			}
+			result.Added = append(result.Added, id)
+		case syncHash(*local) == syncHash(remote):
+		case syncUpdatedAt(remote).After(syncUpdatedAt(*local)):
+			// Keep this store's usage stats
+			remote.Metadata = withoutStats(remote.Metadata)
+			if stats, ok := local.Metadata["stats"]; ok {
+				remote.Metadata["stats"] = stats
+			}
+			if err := g.UpdateNode(ctx, remote); err != nil {
+				return nil, fmt.Errorf("failed to update node %s: %w", id, err)
+			}
+			result.Updated = append(result.Updated, id)
+		default:
+			result.Kept = append(result.Kept, id)
+		}
+	}
+
+	for _, e := range shared.edges {
+		present, err := g.hasEdge(ctx, e)
+		if err != nil {
+			return nil, err
+		}
+		if present {
+			continue
+		}
+		if err := g.AddEdge(ctx, e); err != nil {
+			return nil, fmt.Errorf("failed to add edge %s -> %s (%s): %w", e.Source, e.Target, e.Kind, err)
+		}
+		result.EdgesAdded++
+	}
+
+	if len(result.Added)+len(result.Updated)+result.EdgesAdded > 0 {
+		if err := g.Sync(ctx); err != nil {
+			return nil, fmt.Errorf("failed to sync store: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// hasEdge reports whether the store has an edge like e, or lacks one of
+// its endpoints, in which case it is not added either.
+func (g *GitSyncStore) hasEdge(ctx context.Context, e Edge) (bool, error) {
+	for _, id := range []string{e.Source, e.Target} {
+		n, err := g.GetNode(ctx, id)
+		if err != nil {
+			return false, fmt.Errorf("failed to get node %s: %w", id, err)
+		}
+		if n == nil {
+			return true, nil
+		}
+	}
+	edges, err := g.GetEdges(ctx, e.Source, DirectionOutbound, e.Kind)
+	if err != nil {
+		return false, fmt.Errorf("failed to get edges of %s: %w", e.Source, err)
+	}
+	for _, existing := range edges {
+		if existing.Target == e.Target {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// syncRecords returns the nodes and edges to commit: the store's, plus any
+// shared ones it doesn't hold. A node unchanged from its shared record keeps
+// that record, so a push doesn't rewrite timestamps it didn't change.
+func (g *GitSyncStore) syncRecords(ctx context.Context, shared *syncShared) ([]Node, []Edge, error) {
+	local, err := g.QueryNodes(ctx, map[string]interface{}{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query nodes: %w", err)
+	}
+
+	byID := make(map[string]Node, len(local)+len(shared.nodes))
+	for id, n := range shared.nodes {
+		byID[id] = n
+	}
+	edgeKey := func(e Edge) string { return e.Source + "\x00" + e.Target + "\x00" + string(e.Kind) }
+	edgesByKey := make(map[string]Edge)
+	for _, e := range shared.edges {
+		edgesByKey[edgeKey(e)] = e
+	}
+
+	for _, n := range local {
+		if sn, ok := shared.nodes[n.ID]; !ok || syncHash(sn) != syncHash(n) {
+			byID[n.ID] = syncNode(n)
+		}
+		edges, err := g.GetEdges(ctx, n.ID, DirectionOutbound, "")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get edges of %s: %w", n.ID, err)
+		}
+		for _, e := range edges {
+			e.LastActivated = nil
+			edgesByKey[edgeKey(e)] = e
+		}
+	}
+
+	nodes := make([]Node, 0, len(byID))
+	for _, n := range byID {
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+	keys := make([]string, 0, len(edgesByKey))
+	for k := range edgesByKey {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	edges := make([]Edge, 0, len(keys))
+	for _, k := range keys {
+		edges = append(edges, edgesByKey[k])
+	}
+	return nodes, edges, nil
+}
+
+// git runs a git command in the working clone.
+func (g *GitSyncStore) git(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", g.cfg.Dir}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// identity returns git options supplying a committer identity when none is
+// configured, so pushing works on machines that never set one.
+func (g *GitSyncStore) identity(ctx context.Context) []string {
+	if email, err := g.git(ctx, "config", "user.email"); err == nil && email != "" {
+		return nil
+	}
+	return []string{"-c", "user.name=floop", "-c", "user.email=floop@localhost"}
+}
+
+// syncNode returns n as shared: without usage stats other than updated_at.
+func syncNode(n Node) Node {
+	updatedAt := syncUpdatedAt(n)
+	n.Content = withoutNulls(n.Content)
+	n.Metadata = withoutStats(n.Metadata)
+	if !updatedAt.IsZero() {
+		n.Metadata["stats"] = map[string]interface{}{"updated_at": updatedAt.UTC().Format(time.RFC3339)}
+	}
+	return n
+}
+
+// withoutStats copies metadata without its stats.
+func withoutStats(metadata map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		if k != "stats" {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// withoutNulls copies content without null fields, which a store may or may
+// not keep.
+func withoutNulls(content map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(content))
+	for k, v := range content {
+		if v != nil {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// syncHash hashes the shared content of n: everything but usage stats.
+func syncHash(n Node) string {
+	data, _ := json.Marshal(struct {
+		Kind     NodeKind               `json:"kind"`
+		Content  map[string]interface{} `json:"content"`
+		Metadata map[string]interface{} `json:"metadata"`
+	}{n.Kind, withoutNulls(n.Content), withoutStats(n.Metadata)})
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:8])
+}
+
+// syncUpdatedAt returns when n was last changed, or the zero time.
+func syncUpdatedAt(n Node) time.Time {
+	stats, _ := n.Metadata["stats"].(map[string]interface{})
+	switch t := stats["updated_at"].(type) {
+	case time.Time:
+		return t
+	case string:
+		parsed, _ := time.Parse(time.RFC3339, t)
+		return parsed
+	}
+	return time.Time{}
+}
+
+// readSyncFiles reads the nodes and edges checked out in dir.
+func readSyncFiles(dir string) (*syncShared, error) {
+	shared := &syncShared{nodes: make(map[string]Node)}
+	err := readJSONLines(filepath.Join(dir, SyncNodesFile), func(line []byte) error {
+		var n Node
+		if err := json.Unmarshal(line, &n); err != nil {
+			return err
+		}
+		shared.nodes[n.ID] = n
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = readJSONLines(filepath.Join(dir, SyncEdgesFile), func(line []byte) error {
+		var e Edge
+		if err := json.Unmarshal(line, &e); err != nil {
+			return err
+		}
+		shared.edges = append(shared.edges, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shared, nil
+}
+
+// readJSONLines calls fn for each non-empty line of path. A missing file
+// has no lines.
+func readJSONLines(path string, fn func(line []byte) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to open %s: %w", filepath.Base(path), err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		if err := fn(scanner.Bytes()); err != nil {
+			return fmt.Errorf("failed to parse %s line %d: %w", filepath.Base(path), lineNum, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// writeSyncFiles writes nodes and edges to dir, one JSON record per line.
+func writeSyncFiles(dir string, nodes []Node, edges []Edge) error {
+	if err := atomicWriteFile(filepath.Join(dir, SyncNodesFile), func(f *os.File) error {
+		enc := json.NewEncoder(f)
+		for _, n := range nodes {
+			if err := enc.Encode(n); err != nil {
+				return fmt.Errorf("failed to encode node: %w", err)
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return atomicWriteFile(filepath.Join(dir, SyncEdgesFile), func(f *os.File) error {
+		enc := json.NewEncoder(f)
+		for _, e := range edges {
+			if err := enc.Encode(e); err != nil {
+				return fmt.Errorf("failed to encode edge: %w", err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,209 @@
+package store
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newGitSyncPeer opens a SQLite store in its own project, syncing through
+// remote.
+func newGitSyncPeer(t *testing.T, remote string) (*SQLiteGraphStore, *GitSyncStore) {
+	t.Helper()
+	root := t.TempDir()
+	s, err := NewSQLiteGraphStore(root)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	g, err := NewGitSyncStore(s, GitSyncConfig{
+		Remote: remote,
+		Branch: "floop-behaviors",
+		Dir:    filepath.Join(root, ".floop", "sync"),
+	})
+	if err != nil {
+		t.Fatalf("NewGitSyncStore() error = %v", err)
+	}
+	return s, g
+}
+
+func syncBehavior(id, name, canonical string) Node {
+	return Node{
+		ID:   id,
+		Kind: NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    name,
+			"kind":    "directive",
+			"content": map[string]interface{}{"canonical": canonical},
+		},
+		Metadata: map[string]interface{}{"confidence": 0.7},
+	}
+}
+
+// setUpdatedAt backdates a behavior so conflict resolution is deterministic.
+func setUpdatedAt(t *testing.T, s *SQLiteGraphStore, id string, at time.Time) {
+	t.Helper()
+	if _, err := s.DB().Exec(`UPDATE behaviors SET updated_at = ? WHERE id = ?`, at.Format(time.RFC3339), id); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGitSyncStore(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+	remote := filepath.Join(t.TempDir(), "shared.git")
+	if out, err := exec.Command("git", "init", "-q", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v: %s", err, out)
+	}
+
+	alice, aliceSync := newGitSyncPeer(t, remote)
+	bob, bobSync := newGitSyncPeer(t, remote)
+
+	// Pulling before anything is shared is a no-op
+	result, err := bobSync.Pull(ctx)
+	if err != nil {
+		t.Fatalf("Pull() from empty remote error = %v", err)
+	}
+	if len(result.Added) != 0 {
+		t.Errorf("Pull() from empty remote added %v", result.Added)
+	}
+
+	mustAddNode(t, alice, ctx, syncBehavior("b-1", "wrap-errors", "Wrap errors with %w"))
+	mustAddNode(t, alice, ctx, syncBehavior("b-2", "no-panic", "Never use panic"))
+	mustAddEdge(t, alice, ctx, Edge{Source: "b-1", Target: "b-2", Kind: EdgeKindSimilarTo, Weight: 0.5, CreatedAt: time.Now()})
+	if err := alice.RecordActivationHit(ctx, "b-1"); err != nil {
+		t.Fatal(err)
+	}
+	result, err = aliceSync.Push(ctx)
+	if err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	if result.Commit == "" {
+		t.Error("Push() made no commit")
+	}
+
+	data, err := os.ReadFile(filepath.Join(aliceSync.cfg.Dir, SyncNodesFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "times_activated") {
+		t.Errorf("shared nodes carry usage stats:\n%s", data)
+	}
+
+	t.Run("pull adds shared behaviors", func(t *testing.T) {
+		result, err := bobSync.Pull(ctx)
+		if err != nil {
+			t.Fatalf("Pull() error = %v", err)
+		}
+		if !reflect.DeepEqual(result.Added, []string{"b-1", "b-2"}) || result.EdgesAdded != 1 {
+			t.Errorf("Pull() = %+v, want b-1 and b-2 with 1 edge", result)
+		}
+		if edges := mustGetEdges(t, bob, ctx, "b-1", DirectionOutbound, EdgeKindSimilarTo); len(edges) != 1 {
+			t.Errorf("bob's b-1 edges = %+v", edges)
+		}
+
+		// Nothing changed, so nothing to push
+		result, err = bobSync.Push(ctx)
+		if err != nil {
+			t.Fatalf("Push() error = %v", err)
+		}
+		if result.Commit != "" || len(result.Added)+len(result.Updated) != 0 {
+			t.Errorf("unchanged Push() = %+v, want no commit", result)
+		}
+	})
+
+	t.Run("newer edit wins", func(t *testing.T) {
+		updated := syncBehavior("b-1", "wrap-errors", "Wrap errors with %w and context")
+		if err := bob.UpdateNode(ctx, updated); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := bobSync.Push(ctx); err != nil {
+			t.Fatalf("Push() error = %v", err)
+		}
+		setUpdatedAt(t, alice, "b-1", time.Now().Add(-time.Hour))
+
+		result, err := aliceSync.Pull(ctx)
+		if err != nil {
+			t.Fatalf("Pull() error = %v", err)
+		}
+		if !reflect.DeepEqual(result.Updated, []string{"b-1"}) {
+			t.Errorf("Pull() = %+v, want b-1 updated", result)
+		}
+		node := mustGetNode(t, alice, ctx, "b-1")
+		content, _ := node.Content["content"].(map[string]interface{})
+		if content["canonical"] != "Wrap errors with %w and context" {
+			t.Errorf("alice's b-1 = %v", content["canonical"])
+		}
+		stats, _ := node.Metadata["stats"].(map[string]interface{})
+		if stats["times_activated"] != 1 {
+			t.Errorf("alice's b-1 stats = %v, want local stats kept", stats)
+		}
+	})
+
+	t.Run("older edit is kept locally and overwritten on push", func(t *testing.T) {
+		stale := syncBehavior("b-2", "no-panic", "Avoid panic")
+		if err := bob.UpdateNode(ctx, stale); err != nil {
+			t.Fatal(err)
+		}
+		setUpdatedAt(t, bob, "b-2", time.Now().Add(-time.Hour))
+		newer := syncBehavior("b-2", "no-panic", "Never use panic outside main")
+		if err := alice.UpdateNode(ctx, newer); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := aliceSync.Push(ctx); err != nil {
+			t.Fatalf("Push() error = %v", err)
+		}
+
+		result, err := bobSync.Push(ctx)
+		if err != nil {
+			t.Fatalf("Push() error = %v", err)
+		}
+		if !reflect.DeepEqual(result.Updated, []string{"b-2"}) {
+			t.Errorf("Push() = %+v, want b-2 updated from the shared copy", result)
+		}
+		node := mustGetNode(t, bob, ctx, "b-2")
+		content, _ := node.Content["content"].(map[string]interface{})
+		if content["canonical"] != "Never use panic outside main" {
+			t.Errorf("bob's b-2 = %v", content["canonical"])
+		}
+	})
+}
+
+func TestSyncHash(t *testing.T) {
+	a := syncBehavior("b-1", "wrap-errors", "Wrap errors with %w")
+	b := syncBehavior("b-1", "wrap-errors", "Wrap errors with %w")
+	b.Metadata["stats"] = map[string]interface{}{"times_activated": 4, "updated_at": "2026-01-01T00:00:00Z"}
+	if syncHash(a) != syncHash(b) {
+		t.Error("syncHash() differs on usage stats alone")
+	}
+	b.Metadata["confidence"] = 0.9
+	if syncHash(a) == syncHash(b) {
+		t.Error("syncHash() equal despite a confidence change")
+	}
+}
+
+func TestNewGitSyncStore_Validation(t *testing.T) {
+	s := NewInMemoryGraphStore()
+	tests := []struct {
+		name string
+		cfg  GitSyncConfig
+	}{
+		{"no remote", GitSyncConfig{Branch: "b", Dir: "d"}},
+		{"no branch", GitSyncConfig{Remote: "r", Dir: "d"}},
+		{"no dir", GitSyncConfig{Remote: "r", Branch: "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewGitSyncStore(s, tt.cfg); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// jsonlRecord is one line of a nodes.jsonl or edges.jsonl file being merged.
+type jsonlRecord struct {
+	line    []byte    // the line as written, without the newline
+	full    string    // canonical JSON of the whole record
+	hash    string    // content hash, ignoring usage data
+	updated time.Time // when the usage data last changed
+}
+
+// MergeJSONL performs a three-way merge of a nodes.jsonl or edges.jsonl file,
+// matching nodes by ID and edges by source, target, and kind. A record
+// changed on one side takes that side's version, and records added on either
+// side are kept. When both sides changed a record, usage data (node stats,
+// edge weight and last activation) is resolved by taking the most recently
+// updated copy; any other difference is a conflict. Conflicting records are
+// written between git-style conflict markers, and their keys are returned.
+func MergeJSONL(base, ours, theirs []byte) ([]byte, []string, error) {
+	baseRecs, _, err := parseJSONLRecords(base)
+	if err != nil {
+		return nil, nil, fmt.Errorf("base: %w", err)
+	}
+	ourRecs, ourKeys, err := parseJSONLRecords(ours)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ours: %w", err)
+	}
+	theirRecs, theirKeys, err := parseJSONLRecords(theirs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("theirs: %w", err)
+	}
+
+	// Our order first, then records only they have, in their order
+	keys := ourKeys
+	for _, k := range theirKeys {
+		if _, ok := ourRecs[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	var out bytes.Buffer
+	var conflicts []string
+	for _, k := range keys {
+		b, o, t := baseRecs[k], ourRecs[k], theirRecs[k]
+		merged, ok := mergeJSONLRecord(b, o, t)
+		if !ok {
+			conflicts = append(conflicts, k)
+			out.WriteString("<<<<<<< ours\n")
+			writeJSONLRecord(&out, o)
+			out.WriteString("=======\n")
+			writeJSONLRecord(&out, t)
+			out.WriteString(">>>>>>> theirs\n")
+			continue
+		}
+		writeJSONLRecord(&out, merged)
+	}
+	return out.Bytes(), conflicts, nil
+}
+
+// mergeJSONLRecord resolves one record; nil means absent. It reports false
+// for a conflict.
+func mergeJSONLRecord(b, o, t *jsonlRecord) (*jsonlRecord, bool) {
+	same := func(x, y *jsonlRecord) bool {
+		if x == nil || y == nil {
+			return x == y
+		}
+		return x.full == y.full
+	}
+	switch {
+	case same(o, t), same(b, t):
+		return o, true
+	case same(b, o):
+		return t, true
+	case o == nil || t == nil:
+		return nil, false // changed on one side, deleted on the other
+	case o.hash == t.hash:
+		if t.updated.After(o.updated) {
+			return t, true
+		}
+		return o, true
+	case b != nil && o.hash == b.hash:
+		return t, true
+	case b != nil && t.hash == b.hash:
+		return o, true
+	}
+	return nil, false
+}
+
+func writeJSONLRecord(buf *bytes.Buffer, r *jsonlRecord) {
+	if r != nil {
+		buf.Write(r.line)
+		buf.WriteByte('\n')
+	}
+}
+
+// parseJSONLRecords parses data into records by key, and the keys in order.
+func parseJSONLRecords(data []byte) (map[string]*jsonlRecord, []string, error) {
+	recs := make(map[string]*jsonlRecord)
+	var keys []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		key, rec, err := parseJSONLRecord(line)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		if _, ok := recs[key]; !ok {
+			keys = append(keys, key)
+		}
+		recs[key] = rec
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return recs, keys, nil
+}
+
+// parseJSONLRecord parses a node or edge line and returns its merge key.
+// Other lines are keyed by their content, so they merge as a set.
+func parseJSONLRecord(line []byte) (string, *jsonlRecord, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return "", nil, err
+	}
+	full, _ := json.Marshal(fields)
+	rec := &jsonlRecord{line: append([]byte(nil), line...), full: string(full)}
+
+	if id, ok := fields["id"].(string); ok {
+		var node Node
+		if err := json.Unmarshal(line, &node); err != nil {
+			return "", nil, err
+		}
+		rec.hash, rec.updated = syncHash(node), syncUpdatedAt(node)
+		return "node " + id, rec, nil
+	}
+	if _, ok := fields["source"].(string); ok {
+		var edge Edge
+		if err := json.Unmarshal(line, &edge); err != nil {
+			return "", nil, err
+		}
+		rec.hash, rec.updated = edgeMergeHash(edge), edge.CreatedAt
+		if edge.LastActivated != nil {
+			rec.updated = *edge.LastActivated
+		}
+		return fmt.Sprintf("edge %s -%s-> %s", edge.Source, edge.Kind, edge.Target), rec, nil
+	}
+	rec.hash = rec.full
+	return "line " + rec.full, rec, nil
+}
+
+// edgeMergeHash hashes an edge without its weight and last activation,
+// which change with use.
+func edgeMergeHash(e Edge) string {
+	data, _ := json.Marshal(struct {
+		Source    string                 `json:"source"`
+		Target    string                 `json:"target"`
+		Kind      EdgeKind               `json:"kind"`
+		CreatedAt time.Time              `json:"created_at"`
+		Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	}{e.Source, e.Target, e.Kind, e.CreatedAt, e.Metadata})
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:8])
+}
@@ -0,0 +1,114 @@
+package store
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMergeJSONL(t *testing.T) {
+	node := func(id, canonical, updatedAt string, activated int) string {
+		return `{"id":"` + id + `","kind":"behavior","content":{"name":"` + id + `","content":{"canonical":"` + canonical + `"}},` +
+			`"metadata":{"confidence":0.7,"stats":{"times_activated":` + strconv.Itoa(activated) + `,"updated_at":"` + updatedAt + `"}}}`
+	}
+	edge := func(weight string) string {
+		return `{"source":"b-1","target":"b-2","kind":"similar-to","weight":` + weight + `,"created_at":"2026-01-01T00:00:00Z"}`
+	}
+	lines := func(ls ...string) string {
+		if len(ls) == 0 {
+			return ""
+		}
+		return strings.Join(ls, "\n") + "\n"
+	}
+	const t0, t1, t2 = "2026-01-01T00:00:00Z", "2026-02-01T00:00:00Z", "2026-03-01T00:00:00Z"
+	b1 := node("b-1", "Wrap errors", t0, 0)
+
+	tests := []struct {
+		name          string
+		base          string
+		ours          string
+		theirs        string
+		want          string
+		wantConflicts []string
+	}{
+		{
+			name:   "additions on both sides",
+			base:   lines(b1),
+			ours:   lines(b1, node("b-2", "No panic", t1, 0)),
+			theirs: lines(b1, node("b-3", "Use pathlib", t1, 0)),
+			want:   lines(b1, node("b-2", "No panic", t1, 0), node("b-3", "Use pathlib", t1, 0)),
+		},
+		{
+			name:   "change on one side",
+			base:   lines(b1),
+			ours:   lines(b1),
+			theirs: lines(node("b-1", "Wrap errors with %w", t1, 0)),
+			want:   lines(node("b-1", "Wrap errors with %w", t1, 0)),
+		},
+		{
+			name:   "deletion on one side",
+			base:   lines(b1, edge("0.5")),
+			ours:   lines(b1),
+			theirs: lines(b1, edge("0.5")),
+			want:   lines(b1),
+		},
+		{
+			name:   "usage-only divergence takes the newer copy",
+			base:   lines(b1),
+			ours:   lines(node("b-1", "Wrap errors", t2, 3)),
+			theirs: lines(node("b-1", "Wrap errors", t1, 2)),
+			want:   lines(node("b-1", "Wrap errors", t2, 3)),
+		},
+		{
+			name:   "their content change beats our usage change",
+			base:   lines(b1),
+			ours:   lines(node("b-1", "Wrap errors", t2, 3)),
+			theirs: lines(node("b-1", "Wrap errors with %w", t1, 0)),
+			want:   lines(node("b-1", "Wrap errors with %w", t1, 0)),
+		},
+		{
+			name:   "edge weight divergence",
+			base:   lines(edge("0.5")),
+			ours:   lines(edge("0.6")),
+			theirs: lines(edge("0.7")),
+			want:   lines(edge("0.6")),
+		},
+		{
+			name:          "content changed on both sides",
+			base:          lines(b1),
+			ours:          lines(node("b-1", "Wrap errors with %w", t1, 0)),
+			theirs:        lines(node("b-1", "Wrap errors with context", t2, 0)),
+			want:          "<<<<<<< ours\n" + lines(node("b-1", "Wrap errors with %w", t1, 0)) + "=======\n" + lines(node("b-1", "Wrap errors with context", t2, 0)) + ">>>>>>> theirs\n",
+			wantConflicts: []string{"node b-1"},
+		},
+		{
+			name:          "changed on one side, deleted on the other",
+			base:          lines(b1),
+			ours:          "",
+			theirs:        lines(node("b-1", "Wrap errors with %w", t1, 0)),
+			want:          "<<<<<<< ours\n=======\n" + lines(node("b-1", "Wrap errors with %w", t1, 0)) + ">>>>>>> theirs\n",
+			wantConflicts: []string{"node b-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts, err := MergeJSONL([]byte(tt.base), []byte(tt.ours), []byte(tt.theirs))
+			if err != nil {
+				t.Fatalf("MergeJSONL() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("MergeJSONL() =\n%s\nwant\n%s", got, tt.want)
+			}
+			if !reflect.DeepEqual(conflicts, tt.wantConflicts) {
+				t.Errorf("conflicts = %v, want %v", conflicts, tt.wantConflicts)
+			}
+		})
+	}
+}
+
+func TestMergeJSONL_InvalidLine(t *testing.T) {
+	if _, _, err := MergeJSONL(nil, []byte("{not json\n"), nil); err == nil {
+		t.Error("expected an error for an invalid line")
+	}
+}
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"context"
+	"strings"
+)
+
+// learnedNamePrefix is the namespace the learning loop puts generated
+// behavior names in. A behavior's slug is its name without it.
+const learnedNamePrefix = "learned/"
+
+// NameLookup is implemented by stores that can find a node by name without
+// scanning every node.
+type NameLookup interface {
+	// GetNodeByName returns the node with the given name, preferring an
+	// active behavior when several share it. Returns nil if none does.
+	GetNodeByName(ctx context.Context, name string) (*Node, error)
+}
+
+// ResolveNode finds the node a user refers to by ID, name, or slug (a
+// learned behavior's name without "learned/"), tried in that order.
+// Returns nil if nothing matches.
+func ResolveNode(ctx context.Context, s GraphStore, ref string) (*Node, error) {
+	if ref == "" {
+		return nil, nil
+	}
+	node, err := s.GetNode(ctx, ref)
+	if err != nil || node != nil {
+		return node, err
+	}
+	node, err = getNodeByName(ctx, s, ref)
+	if err != nil || node != nil || strings.Contains(ref, "/") {
+		return node, err
+	}
+	return getNodeByName(ctx, s, learnedNamePrefix+ref)
+}
+
+// getNodeByName uses the store's name lookup when it has one and falls
+// back to scanning its nodes.
+func getNodeByName(ctx context.Context, s GraphStore, name string) (*Node, error) {
+	if lookup, ok := s.(NameLookup); ok {
+		return lookup.GetNodeByName(ctx, name)
+	}
+	nodes, err := s.QueryNodes(ctx, map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	return pickNodeByName(nodes, name), nil
+}
+
+// pickNodeByName returns the node named name, preferring active behaviors
+// and then the lowest ID, so the choice does not depend on node order.
+func pickNodeByName(nodes []Node, name string) *Node {
+	var best *Node
+	for i := range nodes {
+		n := &nodes[i]
+		if nodeName, _ := n.Content["name"].(string); nodeName != name {
+			continue
+		}
+		if best == nil || namedNodeLess(n, best) {
+			best = n
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	node := *best
+	return &node
+}
+
+// namedNodeLess orders nodes sharing a name: active behaviors first, then
+// by ID.
+func namedNodeLess(a, b *Node) bool {
+	aActive, bActive := a.Kind == NodeKindBehavior, b.Kind == NodeKindBehavior
+	if aActive != bActive {
+		return aActive
+	}
+	return a.ID < b.ID
+}
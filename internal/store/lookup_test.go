@@ -0,0 +1,90 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func namedNode(id, name string, kind NodeKind) Node {
+	return Node{
+		ID:   id,
+		Kind: kind,
+		Content: map[string]interface{}{
+			"name":    name,
+			"kind":    "directive",
+			"content": map[string]interface{}{"canonical": "canonical for " + id},
+		},
+		Metadata: map[string]interface{}{},
+	}
+}
+
+func TestResolveNode(t *testing.T) {
+	ctx := context.Background()
+	stores := map[string]func(t *testing.T) GraphStore{
+		"memory": func(t *testing.T) GraphStore { return NewInMemoryGraphStore() },
+		"sqlite": func(t *testing.T) GraphStore {
+			s, err := NewSQLiteGraphStore(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { s.Close() })
+			return s
+		},
+		"multi": func(t *testing.T) GraphStore { return newTestMultiStore(t) },
+	}
+
+	tests := []struct {
+		ref    string
+		wantID string
+	}{
+		{"b-pip", "b-pip"},
+		{"learned/use-uv", "b-uv"},
+		{"use-uv", "b-uv"},
+		{"pin-pip", "b-pip"},
+		{"learned/pin-pip", ""},
+		{"missing", ""},
+		{"", ""},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			s := newStore(t)
+			mustAddNode(t, s, ctx, namedNode("b-old", "learned/use-uv", NodeKindForgotten))
+			mustAddNode(t, s, ctx, namedNode("b-uv", "learned/use-uv", NodeKindBehavior))
+			mustAddNode(t, s, ctx, namedNode("b-pip", "pin-pip", NodeKindBehavior))
+			// A name that is also another node's ID loses to the ID
+			mustAddNode(t, s, ctx, namedNode("b-other", "b-pip", NodeKindBehavior))
+
+			for _, tt := range tests {
+				node, err := ResolveNode(ctx, s, tt.ref)
+				if err != nil {
+					t.Fatalf("ResolveNode(%q) error = %v", tt.ref, err)
+				}
+				gotID := ""
+				if node != nil {
+					gotID = node.ID
+				}
+				if gotID != tt.wantID {
+					t.Errorf("ResolveNode(%q) = %q, want %q", tt.ref, gotID, tt.wantID)
+				}
+			}
+		})
+	}
+}
+
+func TestMultiGraphStore_GetNodeByName(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMultiStore(t)
+	mustAddNode(t, m.globalStore, ctx, namedNode("b-global", "learned/shared", NodeKindBehavior))
+	mustAddNode(t, m.globalStore, ctx, namedNode("b-only-global", "learned/global", NodeKindBehavior))
+	mustAddNode(t, m.localStore, ctx, namedNode("b-local", "learned/shared", NodeKindBehavior))
+
+	node, err := m.GetNodeByName(ctx, "learned/shared")
+	if err != nil || node == nil || node.ID != "b-local" || node.Origin != OriginLocal {
+		t.Errorf("GetNodeByName(shared) = %+v, %v, want b-local from the local store", node, err)
+	}
+	node, err = m.GetNodeByName(ctx, "learned/global")
+	if err != nil || node == nil || node.ID != "b-only-global" || node.Origin != OriginGlobal {
+		t.Errorf("GetNodeByName(global) = %+v, %v, want b-only-global from the global store", node, err)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"sync"
+
+	"github.com/nvandessel/floop/internal/tagging"
 )
 
 // canonicalContent extracts the canonical string from a node's nested content map.
@@ -28,6 +30,7 @@ type InMemoryGraphStore struct {
 	nodes      map[string]Node
 	edges      []Edge
 	embeddings map[string]embeddingEntry
+	state      map[string]string
 }
 
 // NewInMemoryGraphStore creates a new in-memory store.
@@ -36,6 +39,7 @@ func NewInMemoryGraphStore() *InMemoryGraphStore {
 		nodes:      make(map[string]Node),
 		edges:      make([]Edge, 0),
 		embeddings: make(map[string]embeddingEntry),
+		state:      make(map[string]string),
 	}
 }
 
@@ -86,6 +90,19 @@ func (s *InMemoryGraphStore) GetNode(ctx context.Context, id string) (*Node, err
 	return &node, nil
 }
 
+// GetNodeByName retrieves a node by name, preferring an active behavior when
+// several share it. Returns nil if not found.
+func (s *InMemoryGraphStore) GetNodeByName(ctx context.Context, name string) (*Node, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	nodes := make([]Node, 0, len(s.nodes))
+	for _, node := range s.nodes {
+		nodes = append(nodes, node)
+	}
+	return pickNodeByName(nodes, name), nil
+}
+
 // DeleteNode removes a node and its associated edges.
 func (s *InMemoryGraphStore) DeleteNode(ctx context.Context, id string) error {
 	s.mu.Lock()
@@ -136,6 +153,38 @@ func (s *InMemoryGraphStore) AddEdge(ctx context.Context, edge Edge) error {
 	return nil
 }
 
+// BatchAddEdges implements EdgeBatcher. Endpoints must be nodes of this
+// store; the valid edges are added together.
+func (s *InMemoryGraphStore) BatchAddEdges(ctx context.Context, edges []Edge) ([]EdgeError, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rejected []EdgeError
+	for i, edge := range edges {
+		if err := validateEdge(edge); err != nil {
+			rejected = append(rejected, EdgeError{Index: i, Edge: edge, Err: err})
+			continue
+		}
+		_, srcOK := s.nodes[edge.Source]
+		_, tgtOK := s.nodes[edge.Target]
+		if !srcOK || !tgtOK {
+			rejected = append(rejected, EdgeError{Index: i, Edge: edge, Err: danglingEdgeError(edge)})
+			continue
+		}
+		s.edges = append(s.edges, edge)
+	}
+	return rejected, nil
+}
+
+// insertEdges implements edgeInserter.
+func (s *InMemoryGraphStore) insertEdges(ctx context.Context, edges []Edge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.edges = append(s.edges, edges...)
+	return nil
+}
+
 // RemoveEdge removes an edge matching source, target, and kind.
 func (s *InMemoryGraphStore) RemoveEdge(ctx context.Context, source, target string, kind EdgeKind) error {
 	s.mu.Lock()
@@ -258,6 +307,7 @@ func (s *InMemoryGraphStore) GetAllEmbeddings(ctx context.Context) ([]BehaviorEm
 		results = append(results, BehaviorEmbedding{
 			BehaviorID: id,
 			Embedding:  entry.embedding,
+			Model:      entry.modelName,
 		})
 	}
 	return results, nil
@@ -279,6 +329,21 @@ func (s *InMemoryGraphStore) GetBehaviorIDsWithoutEmbeddings(ctx context.Context
 	return ids, nil
 }
 
+// GetState returns the value stored under key, or "" if there is none.
+func (s *InMemoryGraphStore) GetState(ctx context.Context, key string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state[key], nil
+}
+
+// SetState stores value under key.
+func (s *InMemoryGraphStore) SetState(ctx context.Context, key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state[key] = value
+	return nil
+}
+
 // Sync is a no-op for in-memory storage.
 func (s *InMemoryGraphStore) Sync(ctx context.Context) error {
 	return nil
@@ -304,6 +369,11 @@ func matchesPredicate(node Node, predicate map[string]interface{}) bool {
 			continue
 		case "id":
 			actual = node.ID
+		case "tag":
+			if !tagging.HasTag(nodeTags(node), fmt.Sprintf("%v", required)) {
+				return false
+			}
+			continue
 		default:
 			// Check content first, then metadata
 			if val, ok := node.Content[key]; ok {
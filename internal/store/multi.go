@@ -27,10 +27,25 @@ const (
 // Thread-safe through delegation to thread-safe underlying stores.
 //
 // AddNode defaults to the global store. Use AddNodeToScope for explicit routing.
+//
+// Below local and global, reads fall through to the read-only scope chain
+// (see SetScopeChain) in precedence order, then to the policy store.
+//
+// Reads record each node's Origin (local, global, scope:<name>, or policy),
+// and UpdateNode routes a node carrying an Origin back to that store.
 type MultiGraphStore struct {
 	mu          sync.RWMutex
 	localStore  GraphStore
 	globalStore GraphStore
+	scopeStores []scopeLayer // optional, read-only, highest precedence first
+	policyStore GraphStore   // optional, read-only
+}
+
+// readOnlyLayer is a read-only store below local and global, with the
+// origin its nodes carry.
+type readOnlyLayer struct {
+	origin Origin
+	store  GraphStore
 }
 
 // NewMultiGraphStore creates a MultiGraphStore with local and global stores.
@@ -49,7 +64,7 @@ func NewMultiGraphStore(projectRoot string) (*MultiGraphStore, error) {
 		return nil, fmt.Errorf("failed to get home directory: %w", err)
 	}
 
-	globalStore, err := NewSQLiteGraphStore(homeDir)
+	globalStore, err := NewGlobalGraphStore(homeDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create global store: %w", err)
 	}
@@ -57,9 +72,56 @@ func NewMultiGraphStore(projectRoot string) (*MultiGraphStore, error) {
 	return &MultiGraphStore{
 		localStore:  localStore,
 		globalStore: globalStore,
+		scopeStores: openScopeStores(projectRoot),
+		policyStore: openPolicyStore(projectRoot),
 	}, nil
 }
 
+// AddScopeStore appends a read-only scope store to the chain, below the
+// scopes already added. Its nodes carry ScopeOrigin(name), and local and
+// global nodes with the same ID win. The MultiGraphStore takes ownership
+// and closes it on Close.
+func (m *MultiGraphStore) AddScopeStore(name string, gs GraphStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.scopeStores = append(m.scopeStores, scopeLayer{name: name, store: gs})
+}
+
+// Scopes returns the names of the scope stores in the chain, in precedence
+// order.
+func (m *MultiGraphStore) Scopes() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, len(m.scopeStores))
+	for i, layer := range m.scopeStores {
+		names[i] = layer.name
+	}
+	return names
+}
+
+// readOnlyLayers returns the scope stores followed by the policy store.
+// The caller must hold m.mu.
+func (m *MultiGraphStore) readOnlyLayers() []readOnlyLayer {
+	layers := make([]readOnlyLayer, 0, len(m.scopeStores)+1)
+	for _, scope := range m.scopeStores {
+		layers = append(layers, readOnlyLayer{origin: ScopeOrigin(scope.name), store: scope.store})
+	}
+	if m.policyStore != nil {
+		layers = append(layers, readOnlyLayer{origin: OriginPolicy, store: m.policyStore})
+	}
+	return layers
+}
+
+// SetPolicyStore attaches a read-only store of policy behaviors. Policy nodes
+// are visible to reads with the lowest precedence (local and global nodes
+// with the same ID win) and cannot be updated through this store. The
+// MultiGraphStore takes ownership and closes it on Close.
+func (m *MultiGraphStore) SetPolicyStore(policy GraphStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.policyStore = policy
+}
+
 // AddNode adds a node to the global store.
 // Sets metadata["scope"] to "global". Use AddNodeToScope for explicit routing.
 func (m *MultiGraphStore) AddNode(ctx context.Context, node Node) (string, error) {
@@ -95,11 +157,22 @@ func (m *MultiGraphStore) AddNodeToScope(ctx context.Context, node Node, scope S
 	}
 }
 
-// UpdateNode updates a node in whichever store contains it.
+// UpdateNode updates a node in the store it was read from (node.Origin), or
+// in whichever store contains it when the origin is unknown. Scope and
+// policy nodes are read-only.
 func (m *MultiGraphStore) UpdateNode(ctx context.Context, node Node) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	switch {
+	case node.Origin == OriginLocal:
+		return m.updateInStore(ctx, m.localStore, node)
+	case node.Origin == OriginGlobal:
+		return m.updateInStore(ctx, m.globalStore, node)
+	case node.Origin.ReadOnly():
+		return fmt.Errorf("update %s: %w", node.ID, ErrReadOnlyOrigin)
+	}
+
 	// Try local first
 	localNode, err := m.localStore.GetNode(ctx, node.ID)
 	if err != nil {
@@ -118,10 +191,30 @@ func (m *MultiGraphStore) UpdateNode(ctx context.Context, node Node) error {
 		return m.globalStore.UpdateNode(ctx, node)
 	}
 
+	for _, layer := range m.readOnlyLayers() {
+		if existing, err := layer.store.GetNode(ctx, node.ID); err == nil && existing != nil {
+			return fmt.Errorf("update %s: %w", node.ID, ErrReadOnlyOrigin)
+		}
+	}
+
 	return fmt.Errorf("node not found in either store: %s", node.ID)
 }
 
-// GetNode retrieves a node by ID, checking local first, then global.
+// updateInStore updates node in gs, which must already hold it.
+// The caller must hold m.mu.
+func (m *MultiGraphStore) updateInStore(ctx context.Context, gs GraphStore, node Node) error {
+	existing, err := gs.GetNode(ctx, node.ID)
+	if err != nil {
+		return fmt.Errorf("error checking %s store: %w", node.Origin, err)
+	}
+	if existing == nil {
+		return fmt.Errorf("node not found in %s store: %s", node.Origin, node.ID)
+	}
+	return gs.UpdateNode(ctx, node)
+}
+
+// GetNode retrieves a node by ID, checking local first, then global, then
+// the scope chain and the policy store.
 func (m *MultiGraphStore) GetNode(ctx context.Context, id string) (*Node, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -132,6 +225,7 @@ func (m *MultiGraphStore) GetNode(ctx context.Context, id string) (*Node, error)
 		return nil, fmt.Errorf("error checking local store: %w", err)
 	}
 	if node != nil {
+		node.Origin = OriginLocal
 		return node, nil
 	}
 
@@ -140,7 +234,45 @@ func (m *MultiGraphStore) GetNode(ctx context.Context, id string) (*Node, error)
 	if err != nil {
 		return nil, fmt.Errorf("error checking global store: %w", err)
 	}
-	return node, nil
+	if node != nil {
+		node.Origin = OriginGlobal
+		return node, nil
+	}
+
+	for _, layer := range m.readOnlyLayers() {
+		node, err = layer.store.GetNode(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("error checking %s store: %w", layer.origin, err)
+		}
+		if node != nil {
+			node.Origin = layer.origin
+			return node, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetNodeByName retrieves a node by name, checking local first, then global,
+// then the read-only layers. Sets Origin on the returned node.
+func (m *MultiGraphStore) GetNodeByName(ctx context.Context, name string) (*Node, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	layers := append([]readOnlyLayer{
+		{origin: OriginLocal, store: m.localStore},
+		{origin: OriginGlobal, store: m.globalStore},
+	}, m.readOnlyLayers()...)
+	for _, layer := range layers {
+		node, err := getNodeByName(ctx, layer.store, name)
+		if err != nil {
+			return nil, fmt.Errorf("error checking %s store: %w", layer.origin, err)
+		}
+		if node != nil {
+			node.Origin = layer.origin
+			return node, nil
+		}
+	}
+	return nil, nil
 }
 
 // DeleteNode removes a node from both stores (idempotent).
@@ -160,7 +292,8 @@ func (m *MultiGraphStore) DeleteNode(ctx context.Context, id string) error {
 	return nil
 }
 
-// QueryNodes queries both stores and merges results, with local winning on conflicts.
+// QueryNodes queries every store and merges results. On ID conflicts local
+// wins over global, global over the scope chain, and the policy store loses.
 func (m *MultiGraphStore) QueryNodes(ctx context.Context, predicate map[string]interface{}) ([]Node, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -194,7 +327,71 @@ func (m *MultiGraphStore) QueryNodes(ctx context.Context, predicate map[string]i
 		return nil, fmt.Errorf("global query failed: %w", globalResult.err)
 	}
 
-	return mergeNodes(localResult.nodes, globalResult.nodes), nil
+	merged := mergeNodes(withOrigin(localResult.nodes, OriginLocal), withOrigin(globalResult.nodes, OriginGlobal))
+	for _, layer := range m.readOnlyLayers() {
+		nodes, err := layer.store.QueryNodes(ctx, predicate)
+		if err != nil {
+			return nil, fmt.Errorf("%s query failed: %w", layer.origin, err)
+		}
+		merged = mergeNodes(merged, withOrigin(nodes, layer.origin))
+	}
+	return merged, nil
+}
+
+// QueryNodesPage implements NodePager. Each store is read from the cursor
+// and the pages are merged with the precedence of QueryNodes.
+func (m *MultiGraphStore) QueryNodesPage(ctx context.Context, predicate map[string]interface{}, cursor string, limit int) ([]Node, string, error) {
+	if limit <= 0 {
+		limit = DefaultNodePageSize
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	layers := append([]readOnlyLayer{
+		{origin: OriginLocal, store: m.localStore},
+		{origin: OriginGlobal, store: m.globalStore},
+	}, m.readOnlyLayers()...)
+	pages := make([][]Node, 0, len(layers))
+	more := false
+	for _, layer := range layers {
+		nodes, next, err := QueryNodesPage(ctx, layer.store, predicate, cursor, limit)
+		if err != nil {
+			return nil, "", fmt.Errorf("%s query failed: %w", layer.origin, err)
+		}
+		pages = append(pages, withOrigin(nodes, layer.origin))
+		more = more || next != ""
+	}
+	page, next := mergePages(pages, limit, more)
+	return page, next, nil
+}
+
+// SearchBehaviors implements BehaviorSearcher across all layers. On ID
+// conflicts the hit from the higher-precedence layer is kept.
+func (m *MultiGraphStore) SearchBehaviors(ctx context.Context, query string, limit int) ([]SearchHit, error) {
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	layers := append([]readOnlyLayer{
+		{origin: OriginLocal, store: m.localStore},
+		{origin: OriginGlobal, store: m.globalStore},
+	}, m.readOnlyLayers()...)
+	var hits []SearchHit
+	for _, layer := range layers {
+		layerHits, err := SearchBehaviors(ctx, layer.store, query, limit)
+		if err != nil {
+			return nil, fmt.Errorf("%s search failed: %w", layer.origin, err)
+		}
+		for _, h := range layerHits {
+			h.Node.Origin = layer.origin
+			hits = append(hits, h)
+		}
+	}
+	return rankHits(hits, limit), nil
 }
 
 // AddEdge adds an edge, routing it based on endpoint locations:
@@ -204,22 +401,47 @@ func (m *MultiGraphStore) AddEdge(ctx context.Context, edge Edge) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	dst, err := m.edgeStore(ctx, edge)
+	if err != nil {
+		return err
+	}
+	if dst == nil {
+		return fmt.Errorf("source or target not found in either store: source=%s, target=%s", edge.Source, edge.Target)
+	}
+	return dst.AddEdge(ctx, edge)
+}
+
+// BatchAddEdges implements EdgeBatcher. Each edge goes to the store AddEdge
+// would pick, and each store's edges are written in one transaction.
+func (m *MultiGraphStore) BatchAddEdges(ctx context.Context, edges []Edge) ([]EdgeError, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return batchAddRouted(ctx, edges, func(edge Edge) (GraphStore, error) {
+		return m.edgeStore(ctx, edge)
+	})
+}
+
+// edgeStore returns the store a new edge belongs in: the store holding both
+// endpoints, or the global store for a cross-store edge. Returns nil if an
+// endpoint is in neither store. The caller must hold m.mu.
+func (m *MultiGraphStore) edgeStore(ctx context.Context, edge Edge) (GraphStore, error) {
 	// Determine which store(s) have source and target
 	srcLocal, err := m.localStore.GetNode(ctx, edge.Source)
 	if err != nil {
-		return fmt.Errorf("error checking local store for source: %w", err)
+		return nil, fmt.Errorf("error checking local store for source: %w", err)
 	}
 	srcGlobal, err := m.globalStore.GetNode(ctx, edge.Source)
 	if err != nil {
-		return fmt.Errorf("error checking global store for source: %w", err)
+		return nil, fmt.Errorf("error checking global store for source: %w", err)
 	}
 	tgtLocal, err := m.localStore.GetNode(ctx, edge.Target)
 	if err != nil {
-		return fmt.Errorf("error checking local store for target: %w", err)
+		return nil, fmt.Errorf("error checking local store for target: %w", err)
 	}
 	tgtGlobal, err := m.globalStore.GetNode(ctx, edge.Target)
 	if err != nil {
-		return fmt.Errorf("error checking global store for target: %w", err)
+		return nil, fmt.Errorf("error checking global store for target: %w", err)
 	}
 
 	srcInLocal := srcLocal != nil
@@ -229,18 +451,17 @@ func (m *MultiGraphStore) AddEdge(ctx context.Context, edge Edge) error {
 
 	// Both in local → local store
 	if srcInLocal && tgtInLocal {
-		return m.localStore.AddEdge(ctx, edge)
+		return m.localStore, nil
 	}
 	// Both in global → global store
 	if srcInGlobal && tgtInGlobal {
-		return m.globalStore.AddEdge(ctx, edge)
+		return m.globalStore, nil
 	}
 	// Cross-store → global store
 	if (srcInLocal || srcInGlobal) && (tgtInLocal || tgtInGlobal) {
-		return m.globalStore.AddEdge(ctx, edge)
+		return m.globalStore, nil
 	}
-
-	return fmt.Errorf("source or target not found in either store: source=%s, target=%s", edge.Source, edge.Target)
+	return nil, nil
 }
 
 // RemoveEdge removes an edge from both stores.
@@ -260,7 +481,7 @@ func (m *MultiGraphStore) RemoveEdge(ctx context.Context, source, target string,
 	return nil
 }
 
-// GetEdges returns edges from both stores, merged and deduplicated.
+// GetEdges returns edges from every store, merged and deduplicated.
 func (m *MultiGraphStore) GetEdges(ctx context.Context, nodeID string, direction Direction, kind EdgeKind) ([]Edge, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -275,7 +496,15 @@ func (m *MultiGraphStore) GetEdges(ctx context.Context, nodeID string, direction
 		return nil, fmt.Errorf("global GetEdges failed: %w", err)
 	}
 
-	return mergeEdges(localEdges, globalEdges), nil
+	merged := mergeEdges(localEdges, globalEdges)
+	for _, layer := range m.readOnlyLayers() {
+		layerEdges, err := layer.store.GetEdges(ctx, nodeID, direction, kind)
+		if err != nil {
+			return nil, fmt.Errorf("%s GetEdges failed: %w", layer.origin, err)
+		}
+		merged = mergeEdges(merged, layerEdges)
+	}
+	return merged, nil
 }
 
 // Traverse traverses the graph starting from a node.
@@ -290,7 +519,8 @@ func (m *MultiGraphStore) Traverse(ctx context.Context, start string, edgeKinds
 		return nil, fmt.Errorf("error checking local store: %w", err)
 	}
 	if localNode != nil {
-		return m.localStore.Traverse(ctx, start, edgeKinds, direction, maxDepth)
+		nodes, err := m.localStore.Traverse(ctx, start, edgeKinds, direction, maxDepth)
+		return withOrigin(nodes, OriginLocal), err
 	}
 
 	// Try global
@@ -299,7 +529,8 @@ func (m *MultiGraphStore) Traverse(ctx context.Context, start string, edgeKinds
 		return nil, fmt.Errorf("error checking global store: %w", err)
 	}
 	if globalNode != nil {
-		return m.globalStore.Traverse(ctx, start, edgeKinds, direction, maxDepth)
+		nodes, err := m.globalStore.Traverse(ctx, start, edgeKinds, direction, maxDepth)
+		return withOrigin(nodes, OriginGlobal), err
 	}
 
 	return nil, fmt.Errorf("start node not found in either store: %s", start)
@@ -340,6 +571,9 @@ func (m *MultiGraphStore) Close() error {
 
 	localErr := m.localStore.Close()
 	globalErr := m.globalStore.Close()
+	for _, layer := range m.readOnlyLayers() {
+		_ = layer.store.Close()
+	}
 
 	if localErr != nil && globalErr != nil {
 		return fmt.Errorf("failed to close both stores: local=%v, global=%v", localErr, globalErr)
@@ -397,8 +631,9 @@ func (m *MultiGraphStore) forEachExtendedStore(scope string, fn func(ExtendedGra
 	return nil
 }
 
-// GetAllEdges returns edges from both local and global stores, ensuring
-// NativeEngine sees the same graph as the pure-Go Engine.
+// GetAllEdges returns edges from the local and global stores and the
+// read-only layers below them, ensuring NativeEngine sees the same graph as
+// the pure-Go Engine.
 func (m *MultiGraphStore) GetAllEdges(ctx context.Context) ([]Edge, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -412,19 +647,26 @@ func (m *MultiGraphStore) GetAllEdges(ctx context.Context) ([]Edge, error) {
 		return nil, fmt.Errorf("GetAllEdges local: %w", err)
 	}
 
-	if m.globalStore == nil {
-		return localEdges, nil
-	}
-	globalES, ok := m.globalStore.(ExtendedGraphStore)
-	if !ok {
-		return localEdges, nil
+	all := localEdges
+	if globalES, ok := m.globalStore.(ExtendedGraphStore); ok {
+		globalEdges, err := globalES.GetAllEdges(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("GetAllEdges global: %w", err)
+		}
+		all = append(all, globalEdges...)
 	}
-	globalEdges, err := globalES.GetAllEdges(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("GetAllEdges global: %w", err)
+	for _, layer := range m.readOnlyLayers() {
+		es, ok := layer.store.(ExtendedGraphStore)
+		if !ok {
+			continue
+		}
+		layerEdges, err := es.GetAllEdges(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("GetAllEdges %s: %w", layer.origin, err)
+		}
+		all = append(all, layerEdges...)
 	}
-
-	return append(localEdges, globalEdges...), nil
+	return all, nil
 }
 
 // Version returns the combined version from both stores so NativeEngine
@@ -481,6 +723,46 @@ func (m *MultiGraphStore) RecordOverridden(ctx context.Context, behaviorID strin
 	})
 }
 
+// BatchRecordFeedback splits updates by the store holding each behavior and
+// records each store's share in one transaction. Updates for a behavior in
+// neither store fail the whole batch before anything is written.
+func (m *MultiGraphStore) BatchRecordFeedback(ctx context.Context, updates []FeedbackUpdate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var local, global []FeedbackUpdate
+	for _, u := range updates {
+		node, err := m.localStore.GetNode(ctx, u.BehaviorID)
+		if err != nil {
+			return fmt.Errorf("error checking local store: %w", err)
+		}
+		if node != nil {
+			local = append(local, u)
+			continue
+		}
+		node, err = m.globalStore.GetNode(ctx, u.BehaviorID)
+		if err != nil {
+			return fmt.Errorf("error checking global store: %w", err)
+		}
+		if node == nil {
+			return fmt.Errorf("behavior not found in either store: %s", u.BehaviorID)
+		}
+		global = append(global, u)
+	}
+
+	if es, ok := m.localStore.(ExtendedGraphStore); ok && len(local) > 0 {
+		if err := es.BatchRecordFeedback(ctx, local); err != nil {
+			return fmt.Errorf("local BatchRecordFeedback: %w", err)
+		}
+	}
+	if es, ok := m.globalStore.(ExtendedGraphStore); ok && len(global) > 0 {
+		if err := es.BatchRecordFeedback(ctx, global); err != nil {
+			return fmt.Errorf("global BatchRecordFeedback: %w", err)
+		}
+	}
+	return nil
+}
+
 // TouchEdges delegates to both stores.
 func (m *MultiGraphStore) TouchEdges(ctx context.Context, behaviorIDs []string) error {
 	m.mu.Lock()
@@ -522,6 +804,37 @@ func (m *MultiGraphStore) PruneWeakEdges(ctx context.Context, kind EdgeKind, thr
 	return total, nil
 }
 
+// appendLogStore is implemented by SQLiteGraphStore and PartitionedGraphStore.
+type appendLogStore interface {
+	EnableAppendLog(compactThreshold int) error
+}
+
+// externalValidator is implemented by SQLiteGraphStore and
+// PartitionedGraphStore.
+type externalValidator interface {
+	AllBehaviorIDs(ctx context.Context) (map[string]bool, error)
+	ValidateWithExternalIDs(ctx context.Context, externalIDs map[string]bool) ([]ValidationError, error)
+}
+
+// EnableAppendLog enables the JSONL append log on both underlying stores.
+// See SQLiteGraphStore.EnableAppendLog.
+func (m *MultiGraphStore) EnableAppendLog(compactThreshold int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if sqlStore, ok := m.localStore.(appendLogStore); ok {
+		if err := sqlStore.EnableAppendLog(compactThreshold); err != nil {
+			return fmt.Errorf("local store: %w", err)
+		}
+	}
+	if sqlStore, ok := m.globalStore.(appendLogStore); ok {
+		if err := sqlStore.EnableAppendLog(compactThreshold); err != nil {
+			return fmt.Errorf("global store: %w", err)
+		}
+	}
+	return nil
+}
+
 // ValidateBehaviorGraph validates both stores with cross-store awareness.
 // Each store validates with the other store's IDs as externalIDs, so
 // cross-store edges aren't falsely reported as dangling.
@@ -533,14 +846,14 @@ func (m *MultiGraphStore) ValidateBehaviorGraph(ctx context.Context) ([]Validati
 	// Collect IDs from both stores for cross-store resolution
 	var localIDs, globalIDs map[string]bool
 
-	if sqlStore, ok := m.localStore.(*SQLiteGraphStore); ok {
+	if sqlStore, ok := m.localStore.(externalValidator); ok {
 		var err error
 		localIDs, err = sqlStore.AllBehaviorIDs(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get local behavior IDs: %w", err)
 		}
 	}
-	if sqlStore, ok := m.globalStore.(*SQLiteGraphStore); ok {
+	if sqlStore, ok := m.globalStore.(externalValidator); ok {
 		var err error
 		globalIDs, err = sqlStore.AllBehaviorIDs(ctx)
 		if err != nil {
@@ -551,7 +864,7 @@ func (m *MultiGraphStore) ValidateBehaviorGraph(ctx context.Context) ([]Validati
 	var allErrors []ValidationError
 
 	// Local store: validate with global IDs as external (in case of any cross-store edges)
-	if sqlStore, ok := m.localStore.(*SQLiteGraphStore); ok {
+	if sqlStore, ok := m.localStore.(externalValidator); ok {
 		errors, err := sqlStore.ValidateWithExternalIDs(ctx, globalIDs)
 		if err != nil {
 			return nil, fmt.Errorf("failed to validate local store: %w", err)
@@ -563,7 +876,7 @@ func (m *MultiGraphStore) ValidateBehaviorGraph(ctx context.Context) ([]Validati
 	}
 
 	// Global store: validate with local IDs as external (cross-store edges reference local behaviors)
-	if sqlStore, ok := m.globalStore.(*SQLiteGraphStore); ok {
+	if sqlStore, ok := m.globalStore.(externalValidator); ok {
 		errors, err := sqlStore.ValidateWithExternalIDs(ctx, localIDs)
 		if err != nil {
 			return nil, fmt.Errorf("failed to validate global store: %w", err)
@@ -577,6 +890,79 @@ func (m *MultiGraphStore) ValidateBehaviorGraph(ctx context.Context) ([]Validati
 	return allErrors, nil
 }
 
+// RecordClientActivation records a client activation in whichever store
+// contains the behavior.
+func (m *MultiGraphStore) RecordClientActivation(ctx context.Context, behaviorID, client string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, gs := range []GraphStore{m.localStore, m.globalStore} {
+		cs, ok := gs.(ClientStatsStore)
+		if !ok {
+			continue
+		}
+		node, err := gs.GetNode(ctx, behaviorID)
+		if err != nil {
+			return fmt.Errorf("error checking store: %w", err)
+		}
+		if node != nil {
+			return cs.RecordClientActivation(ctx, behaviorID, client)
+		}
+	}
+	return fmt.Errorf("behavior not found in either store: %s", behaviorID)
+}
+
+// GetClientStats returns per-client stats from both stores, merged by client.
+func (m *MultiGraphStore) GetClientStats(ctx context.Context) ([]ClientStats, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var sets [][]ClientStats
+	if cs, ok := m.localStore.(ClientStatsStore); ok {
+		stats, err := cs.GetClientStats(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("local GetClientStats: %w", err)
+		}
+		sets = append(sets, stats)
+	}
+	if cs, ok := m.globalStore.(ClientStatsStore); ok {
+		stats, err := cs.GetClientStats(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("global GetClientStats: %w", err)
+		}
+		sets = append(sets, stats)
+	}
+	return mergeClientStats(sets...), nil
+}
+
+// multiStateKeyPrefix namespaces state recorded for the combined view, which
+// lives in the local store next to that store's own state.
+const multiStateKeyPrefix = "both/"
+
+// GetState returns state recorded for the combined view of both stores.
+func (m *MultiGraphStore) GetState(ctx context.Context, key string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ss, ok := m.localStore.(StateStore)
+	if !ok {
+		return "", nil
+	}
+	return ss.GetState(ctx, multiStateKeyPrefix+key)
+}
+
+// SetState records state for the combined view of both stores.
+func (m *MultiGraphStore) SetState(ctx context.Context, key, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ss, ok := m.localStore.(StateStore)
+	if !ok {
+		return fmt.Errorf("local store does not support state")
+	}
+	return ss.SetState(ctx, multiStateKeyPrefix+key, value)
+}
+
 // StoreEmbedding stores an embedding in whichever store contains the behavior.
 func (m *MultiGraphStore) StoreEmbedding(ctx context.Context, behaviorID string, embedding []float32, modelName string) error {
 	m.mu.Lock()
@@ -660,6 +1046,14 @@ func (m *MultiGraphStore) withEmbeddingStore(ctx context.Context, behaviorID str
 	return fmt.Errorf("behavior not found in either store: %s", behaviorID)
 }
 
+// withOrigin stamps origin on every node and returns the slice.
+func withOrigin(nodes []Node, origin Origin) []Node {
+	for i := range nodes {
+		nodes[i].Origin = origin
+	}
+	return nodes
+}
+
 // mergeNodes merges two slices of nodes, with local winning on ID conflicts.
 func mergeNodes(local, global []Node) []Node {
 	// Build map of local IDs
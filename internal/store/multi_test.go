@@ -2,8 +2,10 @@ package store
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"testing"
 	"time"
@@ -1381,6 +1383,51 @@ func TestMultiGraphStore_ExtendedStore_RecordOverridden(t *testing.T) {
 	}
 }
 
+func TestMultiGraphStore_ExtendedStore_BatchRecordFeedback(t *testing.T) {
+	m := newTestMultiStore(t)
+	ctx := context.Background()
+
+	m.localStore.AddNode(ctx, Node{ID: "b-local", Kind: NodeKindBehavior, Content: map[string]interface{}{"right": "do this"}})
+	m.globalStore.AddNode(ctx, Node{ID: "b-global", Kind: NodeKindBehavior, Content: map[string]interface{}{"right": "do that"}})
+
+	err := m.BatchRecordFeedback(ctx, []FeedbackUpdate{
+		{BehaviorID: "b-local", Confirmed: 2},
+		{BehaviorID: "b-global", Overridden: 1},
+	})
+	if err != nil {
+		t.Fatalf("BatchRecordFeedback() error = %v", err)
+	}
+
+	stat := func(id, field string) int {
+		t.Helper()
+		node, err := m.GetNode(ctx, id)
+		if err != nil || node == nil {
+			t.Fatalf("GetNode(%s) = %v, %v", id, node, err)
+		}
+		stats, _ := node.Metadata["stats"].(map[string]interface{})
+		n, _ := stats[field].(int)
+		return n
+	}
+	if got := stat("b-local", "times_confirmed"); got != 2 {
+		t.Errorf("b-local times_confirmed = %d, want 2", got)
+	}
+	if got := stat("b-global", "times_overridden"); got != 1 {
+		t.Errorf("b-global times_overridden = %d, want 1", got)
+	}
+
+	// An unknown behavior fails the batch before anything is written
+	err = m.BatchRecordFeedback(ctx, []FeedbackUpdate{
+		{BehaviorID: "b-local", Confirmed: 1},
+		{BehaviorID: "nonexistent", Confirmed: 1},
+	})
+	if err == nil {
+		t.Error("BatchRecordFeedback() should error for non-existent behavior")
+	}
+	if got := stat("b-local", "times_confirmed"); got != 2 {
+		t.Errorf("b-local times_confirmed after failed batch = %d, want 2", got)
+	}
+}
+
 func TestMultiGraphStore_ExtendedStore_TouchEdges(t *testing.T) {
 	m := newTestMultiStore(t)
 	ctx := context.Background()
@@ -1606,3 +1653,133 @@ func TestMultiGraphStore_Sync_BothStoresHaveData(t *testing.T) {
 		t.Fatalf("Sync() error = %v", err)
 	}
 }
+
+func TestMultiGraphStore_Origin(t *testing.T) {
+	m := newTestMultiStoreInMemory(t)
+	ctx := context.Background()
+
+	m.localStore.AddNode(ctx, Node{ID: "l", Kind: NodeKindBehavior, Content: map[string]interface{}{"name": "local"}})
+	m.globalStore.AddNode(ctx, Node{ID: "g", Kind: NodeKindBehavior, Content: map[string]interface{}{"name": "global"}})
+	// "shadowed" exists in every store; local wins on read
+	m.localStore.AddNode(ctx, Node{ID: "shadowed", Kind: NodeKindBehavior})
+	m.globalStore.AddNode(ctx, Node{ID: "shadowed", Kind: NodeKindBehavior})
+
+	policy := NewInMemoryGraphStore()
+	policy.AddNode(ctx, Node{ID: "p", Kind: NodeKindBehavior, Content: map[string]interface{}{"name": "policy"}})
+	policy.AddNode(ctx, Node{ID: "shadowed", Kind: NodeKindBehavior})
+	m.SetPolicyStore(policy)
+
+	t.Run("reads record origin", func(t *testing.T) {
+		want := map[string]Origin{"l": OriginLocal, "g": OriginGlobal, "p": OriginPolicy, "shadowed": OriginLocal}
+
+		nodes, err := m.QueryNodes(ctx, map[string]interface{}{"kind": string(NodeKindBehavior)})
+		if err != nil {
+			t.Fatalf("QueryNodes() error = %v", err)
+		}
+		if len(nodes) != len(want) {
+			t.Fatalf("QueryNodes() returned %d nodes, want %d", len(nodes), len(want))
+		}
+		for _, n := range nodes {
+			if n.Origin != want[n.ID] {
+				t.Errorf("QueryNodes() %s origin = %q, want %q", n.ID, n.Origin, want[n.ID])
+			}
+		}
+
+		for id, origin := range want {
+			n, err := m.GetNode(ctx, id)
+			if err != nil || n == nil {
+				t.Fatalf("GetNode(%s) = %v, %v", id, n, err)
+			}
+			if n.Origin != origin {
+				t.Errorf("GetNode(%s) origin = %q, want %q", id, n.Origin, origin)
+			}
+		}
+	})
+
+	t.Run("writes route by origin", func(t *testing.T) {
+		n, _ := m.GetNode(ctx, "g")
+		n.Content["name"] = "renamed"
+		if err := m.UpdateNode(ctx, *n); err != nil {
+			t.Fatalf("UpdateNode(g) error = %v", err)
+		}
+		got, _ := m.globalStore.GetNode(ctx, "g")
+		if got.Content["name"] != "renamed" {
+			t.Errorf("global g name = %v, want renamed", got.Content["name"])
+		}
+		if inLocal, _ := m.localStore.GetNode(ctx, "g"); inLocal != nil {
+			t.Error("update leaked into local store")
+		}
+	})
+
+	t.Run("origin must match the store", func(t *testing.T) {
+		err := m.UpdateNode(ctx, Node{ID: "g", Kind: NodeKindBehavior, Origin: OriginLocal})
+		if err == nil {
+			t.Error("UpdateNode() with wrong origin should fail")
+		}
+	})
+
+	t.Run("policy nodes are read-only", func(t *testing.T) {
+		n, _ := m.GetNode(ctx, "p")
+		if err := m.UpdateNode(ctx, *n); !errors.Is(err, ErrReadOnlyOrigin) {
+			t.Errorf("UpdateNode(policy) error = %v, want ErrReadOnlyOrigin", err)
+		}
+		if err := m.UpdateNode(ctx, Node{ID: "p", Kind: NodeKindBehavior}); !errors.Is(err, ErrReadOnlyOrigin) {
+			t.Errorf("UpdateNode(policy, no origin) error = %v, want ErrReadOnlyOrigin", err)
+		}
+	})
+}
+
+func TestMultiGraphStore_ScopeChain(t *testing.T) {
+	m := newTestMultiStoreInMemory(t)
+	ctx := context.Background()
+
+	m.globalStore.AddNode(ctx, Node{ID: "shared", Kind: NodeKindBehavior, Content: map[string]interface{}{"name": "global"}})
+	team := NewInMemoryGraphStore()
+	team.AddNode(ctx, Node{ID: "shared", Kind: NodeKindBehavior})
+	team.AddNode(ctx, Node{ID: "both", Kind: NodeKindBehavior, Content: map[string]interface{}{"name": "team"}})
+	team.AddNode(ctx, Node{ID: "t", Kind: NodeKindBehavior})
+	golang := NewInMemoryGraphStore()
+	golang.AddNode(ctx, Node{ID: "both", Kind: NodeKindBehavior, Content: map[string]interface{}{"name": "go"}})
+	golang.AddNode(ctx, Node{ID: "go", Kind: NodeKindBehavior})
+	policy := NewInMemoryGraphStore()
+	policy.AddNode(ctx, Node{ID: "go", Kind: NodeKindBehavior})
+	m.AddScopeStore("team", team)
+	m.AddScopeStore("go", golang)
+	m.SetPolicyStore(policy)
+
+	if got := m.Scopes(); !reflect.DeepEqual(got, []string{"team", "go"}) {
+		t.Errorf("Scopes() = %v, want [team go]", got)
+	}
+
+	// Earlier scopes win over later ones; global wins over every scope
+	want := map[string]Origin{
+		"shared": OriginGlobal,
+		"both":   ScopeOrigin("team"),
+		"t":      ScopeOrigin("team"),
+		"go":     ScopeOrigin("go"),
+	}
+	nodes, err := m.QueryNodes(ctx, map[string]interface{}{"kind": string(NodeKindBehavior)})
+	if err != nil {
+		t.Fatalf("QueryNodes() error = %v", err)
+	}
+	got := make(map[string]Origin, len(nodes))
+	for _, n := range nodes {
+		got[n.ID] = n.Origin
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("QueryNodes() origins = %v, want %v", got, want)
+	}
+	for id, origin := range want {
+		if n, err := m.GetNode(ctx, id); err != nil || n == nil || n.Origin != origin {
+			t.Errorf("GetNode(%s) = %+v, %v; want origin %s", id, n, err, origin)
+		}
+	}
+
+	n, _ := m.GetNode(ctx, "t")
+	if err := m.UpdateNode(ctx, *n); !errors.Is(err, ErrReadOnlyOrigin) {
+		t.Errorf("UpdateNode(scope) error = %v, want ErrReadOnlyOrigin", err)
+	}
+	if err := m.UpdateNode(ctx, Node{ID: "t", Kind: NodeKindBehavior}); !errors.Is(err, ErrReadOnlyOrigin) {
+		t.Errorf("UpdateNode(scope, no origin) error = %v, want ErrReadOnlyOrigin", err)
+	}
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+// DefaultNodePageSize is the page size WalkNodes reads nodes in, and the
+// limit QueryNodesPage uses when given none.
+const DefaultNodePageSize = 500
+
+// ErrStopWalk can be returned by a WalkNodes callback to stop the walk
+// early without an error.
+var ErrStopWalk = errors.New("stop walk")
+
+// NodePager is implemented by stores that can read matching nodes a page
+// at a time instead of loading them all.
+type NodePager interface {
+	// QueryNodesPage returns up to limit nodes matching the predicate whose
+	// IDs sort after cursor, in ID order, and the cursor of the next page.
+	// An empty cursor starts at the first node; an empty next cursor means
+	// this was the last page. A limit of zero or less means
+	// DefaultNodePageSize.
+	QueryNodesPage(ctx context.Context, predicate map[string]interface{}, cursor string, limit int) ([]Node, string, error)
+}
+
+// QueryNodesPage reads a page of nodes from s with the semantics of
+// NodePager. Stores that are not NodePagers are queried in full and the
+// page is cut from the sorted result.
+func QueryNodesPage(ctx context.Context, s GraphStore, predicate map[string]interface{}, cursor string, limit int) ([]Node, string, error) {
+	if limit <= 0 {
+		limit = DefaultNodePageSize
+	}
+	if pager, ok := s.(NodePager); ok {
+		return pager.QueryNodesPage(ctx, predicate, cursor, limit)
+	}
+
+	nodes, err := s.QueryNodes(ctx, predicate)
+	if err != nil {
+		return nil, "", err
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	start := sort.Search(len(nodes), func(i int) bool { return nodes[i].ID > cursor })
+	page, next := pageOf(nodes[start:], limit, false)
+	return page, next, nil
+}
+
+// WalkNodes calls fn for each node of s matching the predicate, in ID
+// order, reading DefaultNodePageSize nodes at a time so that no more than a
+// page is held in memory. The store is not locked while fn runs, so fn may
+// use it. The walk stops at the first error fn returns, which WalkNodes
+// returns unless it is ErrStopWalk.
+func WalkNodes(ctx context.Context, s GraphStore, predicate map[string]interface{}, fn func(Node) error) error {
+	cursor := ""
+	for {
+		nodes, next, err := QueryNodesPage(ctx, s, predicate, cursor, DefaultNodePageSize)
+		if err != nil {
+			return err
+		}
+		for _, node := range nodes {
+			if err := fn(node); err != nil {
+				if errors.Is(err, ErrStopWalk) {
+					return nil
+				}
+				return err
+			}
+		}
+		if next == "" {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// pageOf cuts a page of limit nodes from nodes sorted by ID and returns it
+// with the cursor of the next page, which is set when nodes run past the
+// page or more is known to follow.
+func pageOf(nodes []Node, limit int, more bool) ([]Node, string) {
+	if len(nodes) > limit {
+		nodes, more = nodes[:limit], true
+	}
+	if !more || len(nodes) == 0 {
+		return nodes, ""
+	}
+	return nodes, nodes[len(nodes)-1].ID
+}
+
+// mergePages merges the same page read from several stores, each sorted by
+// ID, into one page of limit nodes. On ID conflicts the earlier store wins.
+// more reports whether any store had a page after its own.
+func mergePages(pages [][]Node, limit int, more bool) ([]Node, string) {
+	var merged []Node
+	seen := make(map[string]bool)
+	for _, page := range pages {
+		for _, node := range page {
+			if !seen[node.ID] {
+				seen[node.ID] = true
+				merged = append(merged, node)
+			}
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].ID < merged[j].ID })
+	return pageOf(merged, limit, more)
+}
@@ -0,0 +1,203 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// pageIDs pages through s with the given limit and returns the IDs in
+// order, failing if a page is larger than the limit.
+func pageIDs(t *testing.T, s GraphStore, predicate map[string]interface{}, limit int) []string {
+	t.Helper()
+	ctx := context.Background()
+	var ids []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 100 {
+			t.Fatal("QueryNodesPage() did not terminate")
+		}
+		nodes, next, err := QueryNodesPage(ctx, s, predicate, cursor, limit)
+		if err != nil {
+			t.Fatalf("QueryNodesPage(%q) error = %v", cursor, err)
+		}
+		if len(nodes) > limit {
+			t.Fatalf("QueryNodesPage(%q) returned %d nodes, limit %d", cursor, len(nodes), limit)
+		}
+		for _, n := range nodes {
+			ids = append(ids, n.ID)
+		}
+		if next == "" {
+			return ids
+		}
+		cursor = next
+	}
+}
+
+func TestQueryNodesPage(t *testing.T) {
+	ctx := context.Background()
+	stores := map[string]func(t *testing.T) GraphStore{
+		"memory": func(t *testing.T) GraphStore { return NewInMemoryGraphStore() },
+		"sqlite": func(t *testing.T) GraphStore {
+			s, err := NewSQLiteGraphStore(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { s.Close() })
+			return s
+		},
+		"multi": func(t *testing.T) GraphStore { return newTestMultiStore(t) },
+	}
+
+	var want []string
+	for i := 0; i < 7; i++ {
+		want = append(want, fmt.Sprintf("b-%02d", i))
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			s := newStore(t)
+			// Added out of order, with a node of another kind in between
+			for i := len(want) - 1; i >= 0; i-- {
+				mustAddNode(t, s, ctx, namedNode(want[i], want[i], NodeKindBehavior))
+			}
+			mustAddNode(t, s, ctx, namedNode("b-03x", "forgotten", NodeKindForgotten))
+
+			behaviors := map[string]interface{}{"kind": string(NodeKindBehavior)}
+			for _, limit := range []int{1, 3, 7, 10} {
+				if got := pageIDs(t, s, behaviors, limit); !reflect.DeepEqual(got, want) {
+					t.Errorf("limit %d: pages = %v, want %v", limit, got, want)
+				}
+			}
+
+			// A full last page has no next cursor
+			nodes, next, err := QueryNodesPage(ctx, s, behaviors, "b-03", 3)
+			if err != nil || len(nodes) != 3 || next != "" {
+				t.Errorf("last page = %d nodes, next %q, %v; want 3 nodes and no next", len(nodes), next, err)
+			}
+
+			var walked []string
+			err = WalkNodes(ctx, s, behaviors, func(n Node) error {
+				walked = append(walked, n.ID)
+				return nil
+			})
+			if err != nil || !reflect.DeepEqual(walked, want) {
+				t.Errorf("WalkNodes() = %v, %v; want %v", walked, err, want)
+			}
+		})
+	}
+}
+
+func TestQueryNodesPage_MultiPrecedence(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMultiStore(t)
+	mustAddNode(t, m.localStore, ctx, namedNode("a", "local a", NodeKindBehavior))
+	mustAddNode(t, m.localStore, ctx, namedNode("c", "local c", NodeKindBehavior))
+	mustAddNode(t, m.globalStore, ctx, namedNode("a", "global a", NodeKindBehavior))
+	mustAddNode(t, m.globalStore, ctx, namedNode("b", "global b", NodeKindBehavior))
+	mustAddNode(t, m.globalStore, ctx, namedNode("d", "global d", NodeKindBehavior))
+
+	nodes, next, err := m.QueryNodesPage(ctx, nil, "", 2)
+	if err != nil {
+		t.Fatalf("QueryNodesPage() error = %v", err)
+	}
+	if len(nodes) != 2 || nodes[0].ID != "a" || nodes[1].ID != "b" || next != "b" {
+		t.Fatalf("first page = %v, next %q; want a, b and next b", nodes, next)
+	}
+	if nodes[0].Content["name"] != "local a" || nodes[0].Origin != OriginLocal {
+		t.Errorf("a = %v from %s, want the local node", nodes[0].Content["name"], nodes[0].Origin)
+	}
+	if nodes[1].Origin != OriginGlobal {
+		t.Errorf("b origin = %s, want global", nodes[1].Origin)
+	}
+
+	if got := pageIDs(t, m, nil, 2); !reflect.DeepEqual(got, []string{"a", "b", "c", "d"}) {
+		t.Errorf("pages = %v, want a, b, c, d", got)
+	}
+}
+
+func TestQueryNodesPage_Partitioned(t *testing.T) {
+	ctx := context.Background()
+	root := newPartitionTestStore(t)
+	if _, err := RebalancePartitions(ctx, root, RebalanceOptions{Strategy: PartitionByLanguage, MinSize: 2}); err != nil {
+		t.Fatalf("RebalancePartitions() error = %v", err)
+	}
+	p, err := NewPartitionedGraphStore(root)
+	if err != nil {
+		t.Fatalf("NewPartitionedGraphStore() error = %v", err)
+	}
+	defer p.Close()
+
+	want := []string{"any-1", "go-1", "go-2", "go-3", "py-1", "py-2"}
+	if got := pageIDs(t, p, nil, 4); !reflect.DeepEqual(got, want) {
+		t.Errorf("pages = %v, want %v", got, want)
+	}
+
+	tagged := map[string]interface{}{"tag": "testing"}
+	if got := pageIDs(t, p, tagged, 1); !reflect.DeepEqual(got, []string{"any-1", "go-1", "py-1"}) {
+		t.Errorf("tagged pages = %v, want any-1, go-1, py-1", got)
+	}
+}
+
+func TestSQLiteQueryNodes_MatchesGetNode(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewSQLiteGraphStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	withWhen := namedNode("w", "with when", NodeKindBehavior)
+	withWhen.Content["when"] = map[string]interface{}{"language": "go", "task": []interface{}{"testing", "review"}}
+	mustAddNode(t, s, ctx, withWhen)
+	mustAddNode(t, s, ctx, namedNode("plain", "plain", NodeKindBehavior))
+	if err := s.RecordActivationHit(ctx, "w"); err != nil {
+		t.Fatalf("RecordActivationHit() error = %v", err)
+	}
+
+	nodes, err := s.QueryNodes(ctx, nil)
+	if err != nil || len(nodes) != 2 {
+		t.Fatalf("QueryNodes() = %d nodes, %v; want 2", len(nodes), err)
+	}
+	for _, node := range nodes {
+		single, err := s.GetNode(ctx, node.ID)
+		if err != nil || single == nil {
+			t.Fatalf("GetNode(%s) = %v, %v", node.ID, single, err)
+		}
+		if !reflect.DeepEqual(node, *single) {
+			t.Errorf("QueryNodes() node %s = %+v, want GetNode() %+v", node.ID, node, *single)
+		}
+	}
+	stats, _ := nodes[1].Metadata["stats"].(map[string]interface{})
+	if nodes[1].ID != "w" || stats["times_activated"] != 1 {
+		t.Errorf("w stats = %v, want one activation", stats)
+	}
+}
+
+func TestWalkNodes_Stop(t *testing.T) {
+	ctx := context.Background()
+	s := NewInMemoryGraphStore()
+	for _, id := range []string{"a", "b", "c"} {
+		mustAddNode(t, s, ctx, namedNode(id, id, NodeKindBehavior))
+	}
+
+	var seen []string
+	err := WalkNodes(ctx, s, nil, func(n Node) error {
+		seen = append(seen, n.ID)
+		if n.ID == "b" {
+			return ErrStopWalk
+		}
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(seen, []string{"a", "b"}) {
+		t.Errorf("WalkNodes() = %v, %v; want a, b and no error", seen, err)
+	}
+
+	boom := errors.New("boom")
+	err = WalkNodes(ctx, s, nil, func(Node) error { return boom })
+	if !errors.Is(err, boom) {
+		t.Errorf("WalkNodes() error = %v, want %v", err, boom)
+	}
+}
@@ -0,0 +1,1174 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Partitioning splits a large global store into separate SQLite databases
+// under .floop/partitions/, one per language, tag, or graph community. The
+// .floop directory itself remains the core partition: it holds every
+// behavior not assigned to a partition, every non-behavior node, and edges
+// between partitions. Partitions are opened on first use, and a query
+// carrying a PartitionFilter skips those whose members cannot match.
+
+const (
+	// PartitionsDir holds the partition databases, relative to the .floop
+	// directory.
+	PartitionsDir = "partitions"
+
+	// PartitionManifestFile describes the partitions, relative to
+	// PartitionsDir. A global store is partitioned when it exists.
+	PartitionManifestFile = "manifest.json"
+
+	// DefaultMinPartitionSize is the fewest behaviors a partition is created
+	// for; smaller groups stay in the core partition.
+	DefaultMinPartitionSize = 25
+)
+
+// PartitionStrategy decides which partition a behavior belongs to.
+type PartitionStrategy string
+
+const (
+	// PartitionByNone keeps every behavior in the core partition.
+	PartitionByNone PartitionStrategy = "none"
+	// PartitionByLanguage groups behaviors by their language condition.
+	PartitionByLanguage PartitionStrategy = "language"
+	// PartitionByTag groups behaviors by their first tag.
+	PartitionByTag PartitionStrategy = "tag"
+	// PartitionByCommunity groups behaviors that are densely connected in
+	// the behavior graph.
+	PartitionByCommunity PartitionStrategy = "community"
+)
+
+// ParsePartitionStrategy validates a strategy name.
+func ParsePartitionStrategy(s string) (PartitionStrategy, error) {
+	switch strategy := PartitionStrategy(s); strategy {
+	case PartitionByNone, PartitionByLanguage, PartitionByTag, PartitionByCommunity:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("invalid partition strategy %q (must be none, language, tag, or community)", s)
+}
+
+// PartitionManifest describes how a global store is partitioned.
+type PartitionManifest struct {
+	Strategy   PartitionStrategy `json:"strategy"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	Partitions []PartitionInfo   `json:"partitions"`
+}
+
+// PartitionInfo describes one partition. When holds the when-conditions
+// every behavior in the partition shares; a context that contradicts them
+// cannot activate any of its behaviors.
+type PartitionInfo struct {
+	Key       string                 `json:"key"`
+	When      map[string]interface{} `json:"when,omitempty"`
+	Behaviors int                    `json:"behaviors"`
+}
+
+// ReadPartitionManifest reads the partition manifest of floopDir. It returns
+// nil if the store is not partitioned.
+func ReadPartitionManifest(floopDir string) (*PartitionManifest, error) {
+	data, err := os.ReadFile(filepath.Join(floopDir, PartitionsDir, PartitionManifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read partition manifest: %w", err)
+	}
+	var m PartitionManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse partition manifest: %w", err)
+	}
+	return &m, nil
+}
+
+func writePartitionManifest(floopDir string, m *PartitionManifest) error {
+	dir := filepath.Join(floopDir, PartitionsDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create partitions directory: %w", err)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode partition manifest: %w", err)
+	}
+	return atomicWriteFile(filepath.Join(dir, PartitionManifestFile), func(f *os.File) error {
+		_, err := f.Write(append(data, '\n'))
+		return err
+	})
+}
+
+// PartitionDirs returns the directories of the partitions listed in the
+// manifest of floopDir, or nil if the store is not partitioned.
+func PartitionDirs(floopDir string) []string {
+	m, err := ReadPartitionManifest(floopDir)
+	if err != nil || m == nil {
+		return nil
+	}
+	dirs := make([]string, 0, len(m.Partitions))
+	for _, p := range m.Partitions {
+		dirs = append(dirs, filepath.Join(floopDir, PartitionsDir, p.Key))
+	}
+	return dirs
+}
+
+// PartitionFilter reports whether a partition whose behaviors all share the
+// when-conditions when may hold behaviors active in the caller's context.
+type PartitionFilter func(when map[string]interface{}) bool
+
+type partitionFilterKey struct{}
+
+// WithPartitionFilter returns a context that makes QueryNodes on a
+// partitioned store skip partitions filter rules out. Stores that are not
+// partitioned ignore it.
+func WithPartitionFilter(ctx context.Context, filter PartitionFilter) context.Context {
+	return context.WithValue(ctx, partitionFilterKey{}, filter)
+}
+
+func partitionFilterFrom(ctx context.Context) PartitionFilter {
+	filter, _ := ctx.Value(partitionFilterKey{}).(PartitionFilter)
+	return filter
+}
+
+// NewGlobalGraphStore opens the global store rooted at homeDir: a
+// PartitionedGraphStore if it has been partitioned with RebalancePartitions,
+// otherwise a SQLiteGraphStore.
+func NewGlobalGraphStore(homeDir string) (ExtendedGraphStore, error) {
+	m, err := ReadPartitionManifest(filepath.Join(homeDir, ".floop"))
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return NewSQLiteGraphStore(homeDir)
+	}
+	return NewPartitionedGraphStore(homeDir)
+}
+
+// PartitionedGraphStore implements GraphStore over a core SQLiteGraphStore
+// and the partition stores listed in its manifest. Partition stores are
+// opened lazily. The core's state indexes which partition holds each
+// partitioned node, so a lookup opens at most the partition holding the ID.
+//
+// New behaviors are added to the partition the strategy assigns them to if
+// it exists and they share its when-conditions, and to the core otherwise.
+// An edge is kept in the partition holding both endpoints, or in the core.
+type PartitionedGraphStore struct {
+	projectRoot string
+	floopDir    string
+	core        *SQLiteGraphStore
+
+	mu          sync.Mutex
+	manifest    PartitionManifest
+	parts       map[string]*SQLiteGraphStore
+	owner       map[string]string // node ID -> partition key, "" for the core
+	appendLogAt int               // > 0 once EnableAppendLog is called
+	quiet       bool              // opened by RebalancePartitions: no changelog
+	indexed     bool              // the core's partition index has been checked
+}
+
+// The partition index maps the ID of every node held by a partition to the
+// partition key, as state entries of the core. Nodes in the core have no
+// entry. partitionIndexBuilt marks a store whose index is complete; stores
+// partitioned before the index existed build it on first lookup.
+const (
+	partitionIndexPrefix = "partition/"
+	partitionIndexBuilt  = "partition-index"
+)
+
+// NewPartitionedGraphStore opens the partitioned store rooted at
+// projectRoot. A store without a manifest opens with only the core
+// partition.
+func NewPartitionedGraphStore(projectRoot string) (*PartitionedGraphStore, error) {
+	floopDir := filepath.Join(projectRoot, ".floop")
+	m, err := ReadPartitionManifest(floopDir)
+	if err != nil {
+		return nil, err
+	}
+	core, err := NewSQLiteGraphStore(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	p := &PartitionedGraphStore{
+		projectRoot: projectRoot,
+		floopDir:    floopDir,
+		core:        core,
+		manifest:    PartitionManifest{Strategy: PartitionByNone},
+		parts:       make(map[string]*SQLiteGraphStore),
+		owner:       make(map[string]string),
+	}
+	if m != nil {
+		p.manifest = *m
+	}
+	return p, nil
+}
+
+// Manifest returns a copy of the store's partition manifest.
+func (p *PartitionedGraphStore) Manifest() PartitionManifest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	m := p.manifest
+	m.Partitions = append([]PartitionInfo(nil), m.Partitions...)
+	return m
+}
+
+// open returns the store of partition key, opening it on first use. The
+// empty key is the core. The caller must hold p.mu.
+func (p *PartitionedGraphStore) open(key string) (*SQLiteGraphStore, error) {
+	if key == "" {
+		return p.core, nil
+	}
+	if s, ok := p.parts[key]; ok {
+		return s, nil
+	}
+	s, err := openSQLiteGraphStore(p.projectRoot, filepath.Join(p.floopDir, PartitionsDir, key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open partition %s: %w", key, err)
+	}
+	if p.quiet {
+		s.changelogFile = ""
+	}
+	if p.appendLogAt > 0 {
+		if err := s.EnableAppendLog(p.appendLogAt); err != nil {
+			s.Close()
+			return nil, fmt.Errorf("partition %s: %w", key, err)
+		}
+	}
+	p.parts[key] = s
+	return s, nil
+}
+
+// partition returns the store of partition key, opening it on first use.
+func (p *PartitionedGraphStore) partition(key string) (*SQLiteGraphStore, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.open(key)
+}
+
+// keys returns the core key followed by the key of every partition that
+// passes filter, or of every partition if filter is nil.
+func (p *PartitionedGraphStore) keys(filter PartitionFilter) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	keys := []string{""}
+	for _, info := range p.manifest.Partitions {
+		if filter == nil || filter(info.When) {
+			keys = append(keys, info.Key)
+		}
+	}
+	return keys
+}
+
+// openStores returns the core and the partitions opened so far.
+func (p *PartitionedGraphStore) openStores() []*SQLiteGraphStore {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stores := []*SQLiteGraphStore{p.core}
+	for _, info := range p.manifest.Partitions {
+		if s, ok := p.parts[info.Key]; ok {
+			stores = append(stores, s)
+		}
+	}
+	return stores
+}
+
+// allStores opens and returns the core and every partition.
+func (p *PartitionedGraphStore) allStores() ([]*SQLiteGraphStore, error) {
+	var stores []*SQLiteGraphStore
+	for _, key := range p.keys(nil) {
+		s, err := p.partition(key)
+		if err != nil {
+			return nil, err
+		}
+		stores = append(stores, s)
+	}
+	return stores, nil
+}
+
+func (p *PartitionedGraphStore) setOwner(id, key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.owner[id] = key
+}
+
+// find returns the partition key and store holding id, and the node. The
+// store is nil if no partition holds it. Only the core, the cached owner,
+// and the partition the index names are opened.
+func (p *PartitionedGraphStore) find(ctx context.Context, id string) (string, *SQLiteGraphStore, *Node, error) {
+	p.mu.Lock()
+	known, ok := p.owner[id]
+	p.mu.Unlock()
+
+	keys := []string{""}
+	if ok && known != "" {
+		keys = append([]string{known}, keys...)
+	}
+	for _, key := range keys {
+		s, node, err := p.lookup(ctx, key, id)
+		if err != nil || node != nil {
+			return key, s, node, err
+		}
+	}
+
+	key, err := p.indexedKey(ctx, id)
+	if err != nil || key == "" || (ok && key == known) {
+		return "", nil, nil, err
+	}
+	s, node, err := p.lookup(ctx, key, id)
+	if err != nil || node == nil {
+		return "", nil, nil, err
+	}
+	return key, s, node, nil
+}
+
+// lookup returns id's node and the store of partition key if it holds it.
+func (p *PartitionedGraphStore) lookup(ctx context.Context, key, id string) (*SQLiteGraphStore, *Node, error) {
+	s, err := p.partition(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	node, err := s.GetNode(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error checking partition %s: %w", partitionName(key), err)
+	}
+	if node == nil {
+		return nil, nil, nil
+	}
+	p.setOwner(id, key)
+	return s, node, nil
+}
+
+// indexedKey returns the partition the index places id in, or "" if it
+// names none that is still in the manifest.
+func (p *PartitionedGraphStore) indexedKey(ctx context.Context, id string) (string, error) {
+	if len(p.keys(nil)) == 1 {
+		return "", nil
+	}
+	if err := p.ensureIndex(ctx); err != nil {
+		return "", err
+	}
+	key, err := p.core.GetState(ctx, partitionIndexPrefix+id)
+	if err != nil || key == "" {
+		return "", err
+	}
+	for _, k := range p.keys(nil)[1:] {
+		if k == key {
+			return key, nil
+		}
+	}
+	return "", nil
+}
+
+// ensureIndex builds the partition index of a store partitioned before it
+// existed, reading every partition once.
+func (p *PartitionedGraphStore) ensureIndex(ctx context.Context) error {
+	p.mu.Lock()
+	indexed := p.indexed
+	p.mu.Unlock()
+	if indexed {
+		return nil
+	}
+
+	built, err := p.core.GetState(ctx, partitionIndexBuilt)
+	if err != nil {
+		return err
+	}
+	if built == "" {
+		index := make(map[string]string)
+		for _, key := range p.keys(nil)[1:] {
+			s, err := p.partition(key)
+			if err != nil {
+				return err
+			}
+			nodes, err := s.QueryNodes(ctx, map[string]interface{}{})
+			if err != nil {
+				return fmt.Errorf("failed to index partition %s: %w", partitionName(key), err)
+			}
+			for _, n := range nodes {
+				index[n.ID] = key
+			}
+		}
+		if err := p.writeIndex(ctx, index); err != nil {
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	p.indexed = true
+	p.mu.Unlock()
+	return nil
+}
+
+// writeIndex replaces the partition index with index (node ID -> partition
+// key, "" for the core) and marks it complete.
+func (p *PartitionedGraphStore) writeIndex(ctx context.Context, index map[string]string) error {
+	c := p.core
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to write partition index: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM config WHERE key LIKE ?`, stateKeyPrefix+partitionIndexPrefix+"%"); err != nil {
+		return fmt.Errorf("failed to clear partition index: %w", err)
+	}
+	for id, key := range index {
+		if key == "" {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO config (key, value) VALUES (?, ?)`,
+			stateKeyPrefix+partitionIndexPrefix+id, key); err != nil {
+			return fmt.Errorf("failed to index %s: %w", id, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO config (key, value) VALUES (?, ?)`,
+		stateKeyPrefix+partitionIndexBuilt, "1"); err != nil {
+		return fmt.Errorf("failed to write partition index: %w", err)
+	}
+	return tx.Commit()
+}
+
+// route returns the partition a new node is added to.
+func (p *PartitionedGraphStore) route(node Node) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !isBehaviorKind(node.Kind) {
+		return ""
+	}
+	key := partitionKey(p.manifest.Strategy, node)
+	for _, info := range p.manifest.Partitions {
+		if info.Key == key && sharesConditions(nodeWhen(node), info.When) {
+			return key
+		}
+	}
+	return ""
+}
+
+// AddNode adds a node to the partition the strategy assigns it to.
+func (p *PartitionedGraphStore) AddNode(ctx context.Context, node Node) (string, error) {
+	key := p.route(node)
+	s, err := p.partition(key)
+	if err != nil {
+		return "", err
+	}
+	id, err := s.AddNode(ctx, node)
+	if err != nil {
+		return "", err
+	}
+	p.setOwner(id, key)
+	if key != "" {
+		if err := p.core.SetState(ctx, partitionIndexPrefix+id, key); err != nil {
+			return "", err
+		}
+	}
+	return id, nil
+}
+
+// UpdateNode updates a node in the partition holding it. If the node no
+// longer shares its partition's when-conditions, the conditions it does not
+// share are dropped from the manifest.
+func (p *PartitionedGraphStore) UpdateNode(ctx context.Context, node Node) error {
+	key, s, _, err := p.find(ctx, node.ID)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return fmt.Errorf("node not found: %s", node.ID)
+	}
+	if err := s.UpdateNode(ctx, node); err != nil {
+		return err
+	}
+	if key == "" {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, info := range p.manifest.Partitions {
+		if info.Key != key || sharesConditions(nodeWhen(node), info.When) {
+			continue
+		}
+		p.manifest.Partitions[i].When = commonConditions(info.When, nodeWhen(node))
+		return writePartitionManifest(p.floopDir, &p.manifest)
+	}
+	return nil
+}
+
+// GetNode retrieves a node from whichever partition holds it.
+func (p *PartitionedGraphStore) GetNode(ctx context.Context, id string) (*Node, error) {
+	_, _, node, err := p.find(ctx, id)
+	return node, err
+}
+
+// DeleteNode removes a node and the edges involving it (idempotent).
+func (p *PartitionedGraphStore) DeleteNode(ctx context.Context, id string) error {
+	key, s, _, err := p.find(ctx, id)
+	if err != nil || s == nil {
+		return err
+	}
+	if err := s.DeleteNode(ctx, id); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	delete(p.owner, id)
+	p.mu.Unlock()
+	if key == "" {
+		return nil
+	}
+	if err := p.core.SetState(ctx, partitionIndexPrefix+id, ""); err != nil {
+		return err
+	}
+	// Edges to other partitions are kept in the core
+	return p.core.deleteEdgesOf(ctx, id)
+}
+
+// QueryNodes queries the core and the partitions, skipping those the
+// context's PartitionFilter rules out.
+func (p *PartitionedGraphStore) QueryNodes(ctx context.Context, predicate map[string]interface{}) ([]Node, error) {
+	var all []Node
+	for _, key := range p.keys(partitionFilterFrom(ctx)) {
+		s, err := p.partition(key)
+		if err != nil {
+			return nil, err
+		}
+		nodes, err := s.QueryNodes(ctx, predicate)
+		if err != nil {
+			return nil, fmt.Errorf("partition %s query failed: %w", partitionName(key), err)
+		}
+		p.mu.Lock()
+		for _, node := range nodes {
+			p.owner[node.ID] = key
+		}
+		p.mu.Unlock()
+		all = append(all, nodes...)
+	}
+	return all, nil
+}
+
+// QueryNodesPage implements NodePager, reading the page from the core and
+// each partition the context's PartitionFilter does not rule out.
+func (p *PartitionedGraphStore) QueryNodesPage(ctx context.Context, predicate map[string]interface{}, cursor string, limit int) ([]Node, string, error) {
+	if limit <= 0 {
+		limit = DefaultNodePageSize
+	}
+	var pages [][]Node
+	more := false
+	for _, key := range p.keys(partitionFilterFrom(ctx)) {
+		s, err := p.partition(key)
+		if err != nil {
+			return nil, "", err
+		}
+		nodes, next, err := s.QueryNodesPage(ctx, predicate, cursor, limit)
+		if err != nil {
+			return nil, "", fmt.Errorf("partition %s query failed: %w", partitionName(key), err)
+		}
+		p.mu.Lock()
+		for _, node := range nodes {
+			p.owner[node.ID] = key
+		}
+		p.mu.Unlock()
+		pages = append(pages, nodes)
+		more = more || next != ""
+	}
+	page, next := mergePages(pages, limit, more)
+	return page, next, nil
+}
+
+// SearchBehaviors implements BehaviorSearcher across the partitions.
+func (p *PartitionedGraphStore) SearchBehaviors(ctx context.Context, query string, limit int) ([]SearchHit, error) {
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	var hits []SearchHit
+	for _, key := range p.keys(partitionFilterFrom(ctx)) {
+		s, err := p.partition(key)
+		if err != nil {
+			return nil, err
+		}
+		partHits, err := s.SearchBehaviors(ctx, query, limit)
+		if err != nil {
+			return nil, fmt.Errorf("partition %s search failed: %w", partitionName(key), err)
+		}
+		p.mu.Lock()
+		for _, h := range partHits {
+			p.owner[h.Node.ID] = key
+		}
+		p.mu.Unlock()
+		hits = append(hits, partHits...)
+	}
+	return rankHits(hits, limit), nil
+}
+
+// AddEdge adds an edge to the partition holding both endpoints, or to the
+// core. Endpoints need not be in this store at all: edges from local
+// behaviors to global ones are kept in the global core.
+func (p *PartitionedGraphStore) AddEdge(ctx context.Context, edge Edge) error {
+	srcKey, src, _, err := p.find(ctx, edge.Source)
+	if err != nil {
+		return err
+	}
+	tgtKey, tgt, _, err := p.find(ctx, edge.Target)
+	if err != nil {
+		return err
+	}
+	if src != nil && tgt != nil && srcKey == tgtKey {
+		return src.AddEdge(ctx, edge)
+	}
+	return p.core.AddEdge(ctx, edge)
+}
+
+// BatchAddEdges implements EdgeBatcher. Each edge goes where AddEdge would
+// put it, and each partition's edges are written in one transaction; an edge
+// with an endpoint in no partition is rejected.
+func (p *PartitionedGraphStore) BatchAddEdges(ctx context.Context, edges []Edge) ([]EdgeError, error) {
+	return batchAddRouted(ctx, edges, func(edge Edge) (GraphStore, error) {
+		srcKey, src, _, err := p.find(ctx, edge.Source)
+		if err != nil {
+			return nil, err
+		}
+		tgtKey, tgt, _, err := p.find(ctx, edge.Target)
+		if err != nil {
+			return nil, err
+		}
+		if src == nil || tgt == nil {
+			return nil, nil
+		}
+		if srcKey == tgtKey {
+			return src, nil
+		}
+		return p.core, nil
+	})
+}
+
+// RemoveEdge removes an edge from the core and the endpoints' partitions.
+func (p *PartitionedGraphStore) RemoveEdge(ctx context.Context, source, target string, kind EdgeKind) error {
+	stores, err := p.edgeStores(ctx, source, target)
+	if err != nil {
+		return err
+	}
+	for _, s := range stores {
+		if err := s.RemoveEdge(ctx, source, target, kind); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// edgeStores returns the stores that may hold edges of the given nodes:
+// the core and the partitions holding them.
+func (p *PartitionedGraphStore) edgeStores(ctx context.Context, ids ...string) ([]*SQLiteGraphStore, error) {
+	stores := []*SQLiteGraphStore{p.core}
+	for _, id := range ids {
+		key, s, _, err := p.find(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if s != nil && key != "" && !containsStore(stores, s) {
+			stores = append(stores, s)
+		}
+	}
+	return stores, nil
+}
+
+func containsStore(stores []*SQLiteGraphStore, s *SQLiteGraphStore) bool {
+	for _, existing := range stores {
+		if existing == s {
+			return true
+		}
+	}
+	return false
+}
+
+// GetEdges returns the edges of a node from its partition and the core.
+func (p *PartitionedGraphStore) GetEdges(ctx context.Context, nodeID string, direction Direction, kind EdgeKind) ([]Edge, error) {
+	stores, err := p.edgeStores(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	var merged []Edge
+	for _, s := range stores {
+		edges, err := s.GetEdges(ctx, nodeID, direction, kind)
+		if err != nil {
+			return nil, err
+		}
+		merged = mergeEdges(merged, edges)
+	}
+	return merged, nil
+}
+
+// Traverse returns all nodes reachable from start, following edges across
+// partitions.
+func (p *PartitionedGraphStore) Traverse(ctx context.Context, start string, edgeKinds []EdgeKind, direction Direction, maxDepth int) ([]Node, error) {
+	visited := make(map[string]bool)
+	var results []Node
+	if err := p.traverse(ctx, start, edgeKinds, direction, maxDepth, 0, visited, &results); err != nil {
+		return nil, fmt.Errorf("traverse from %s: %w", start, err)
+	}
+	return results, nil
+}
+
+func (p *PartitionedGraphStore) traverse(ctx context.Context, current string, edgeKinds []EdgeKind, direction Direction, maxDepth, depth int, visited map[string]bool, results *[]Node) error {
+	if depth > maxDepth || visited[current] {
+		return nil
+	}
+	visited[current] = true
+
+	node, err := p.GetNode(ctx, current)
+	if err != nil {
+		return fmt.Errorf("get node %s: %w", current, err)
+	}
+	if node != nil {
+		*results = append(*results, *node)
+	}
+
+	edges, err := p.GetEdges(ctx, current, direction, "")
+	if err != nil {
+		return fmt.Errorf("get edges for %s: %w", current, err)
+	}
+	for _, e := range edges {
+		if !edgeKindMatches(e.Kind, edgeKinds) {
+			continue
+		}
+		next := ""
+		switch {
+		case e.Source == current && direction != DirectionInbound:
+			next = e.Target
+		case e.Target == current && direction != DirectionOutbound:
+			next = e.Source
+		}
+		if next != "" {
+			if err := p.traverse(ctx, next, edgeKinds, direction, maxDepth, depth+1, visited, results); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Sync syncs the core and the partitions opened so far. Partitions that
+// were never opened have nothing to export.
+func (p *PartitionedGraphStore) Sync(ctx context.Context) error {
+	for _, s := range p.openStores() {
+		if err := s.Sync(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close syncs and closes the core and every opened partition.
+func (p *PartitionedGraphStore) Close() error {
+	var firstErr error
+	for _, s := range p.openStores() {
+		if err := s.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// withOwner calls fn with the store holding behaviorID.
+func (p *PartitionedGraphStore) withOwner(ctx context.Context, behaviorID string, fn func(*SQLiteGraphStore) error) error {
+	_, s, _, err := p.find(ctx, behaviorID)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return fmt.Errorf("behavior not found: %s", behaviorID)
+	}
+	return fn(s)
+}
+
+// UpdateConfidence updates the confidence of a behavior in its partition.
+func (p *PartitionedGraphStore) UpdateConfidence(ctx context.Context, behaviorID string, newConfidence float64) error {
+	return p.withOwner(ctx, behaviorID, func(s *SQLiteGraphStore) error {
+		return s.UpdateConfidence(ctx, behaviorID, newConfidence)
+	})
+}
+
+// RecordActivationHit delegates to the partition holding the behavior.
+func (p *PartitionedGraphStore) RecordActivationHit(ctx context.Context, behaviorID string) error {
+	return p.withOwner(ctx, behaviorID, func(s *SQLiteGraphStore) error {
+		return s.RecordActivationHit(ctx, behaviorID)
+	})
+}
+
+// RecordConfirmed delegates to the partition holding the behavior.
+func (p *PartitionedGraphStore) RecordConfirmed(ctx context.Context, behaviorID string) error {
+	return p.withOwner(ctx, behaviorID, func(s *SQLiteGraphStore) error {
+		return s.RecordConfirmed(ctx, behaviorID)
+	})
+}
+
+// RecordOverridden delegates to the partition holding the behavior.
+func (p *PartitionedGraphStore) RecordOverridden(ctx context.Context, behaviorID string) error {
+	return p.withOwner(ctx, behaviorID, func(s *SQLiteGraphStore) error {
+		return s.RecordOverridden(ctx, behaviorID)
+	})
+}
+
+// BatchRecordFeedback splits updates by partition and records each share in
+// one transaction. Updates for an unknown behavior fail the whole batch
+// before anything is written.
+func (p *PartitionedGraphStore) BatchRecordFeedback(ctx context.Context, updates []FeedbackUpdate) error {
+	byStore := make(map[*SQLiteGraphStore][]FeedbackUpdate)
+	var order []*SQLiteGraphStore
+	for _, u := range updates {
+		_, s, _, err := p.find(ctx, u.BehaviorID)
+		if err != nil {
+			return err
+		}
+		if s == nil {
+			return fmt.Errorf("behavior not found: %s", u.BehaviorID)
+		}
+		if _, ok := byStore[s]; !ok {
+			order = append(order, s)
+		}
+		byStore[s] = append(byStore[s], u)
+	}
+	for _, s := range order {
+		if err := s.BatchRecordFeedback(ctx, byStore[s]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TouchEdges updates edges of the given behaviors in the core and their
+// partitions.
+func (p *PartitionedGraphStore) TouchEdges(ctx context.Context, behaviorIDs []string) error {
+	stores, err := p.edgeStores(ctx, behaviorIDs...)
+	if err != nil {
+		return err
+	}
+	for _, s := range stores {
+		if err := s.TouchEdges(ctx, behaviorIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BatchUpdateEdgeWeights applies the updates in the core and the partitions
+// holding their endpoints.
+func (p *PartitionedGraphStore) BatchUpdateEdgeWeights(ctx context.Context, updates []EdgeWeightUpdate) error {
+	ids := make([]string, 0, 2*len(updates))
+	for _, u := range updates {
+		ids = append(ids, u.Source, u.Target)
+	}
+	stores, err := p.edgeStores(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	for _, s := range stores {
+		if err := s.BatchUpdateEdgeWeights(ctx, updates); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PruneWeakEdges prunes every partition and returns the total count pruned.
+func (p *PartitionedGraphStore) PruneWeakEdges(ctx context.Context, kind EdgeKind, threshold float64) (int, error) {
+	stores, err := p.allStores()
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, s := range stores {
+		n, err := s.PruneWeakEdges(ctx, kind, threshold)
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// GetAllEdges returns the edges of every partition.
+func (p *PartitionedGraphStore) GetAllEdges(ctx context.Context) ([]Edge, error) {
+	stores, err := p.allStores()
+	if err != nil {
+		return nil, err
+	}
+	var all []Edge
+	for _, s := range stores {
+		edges, err := s.GetAllEdges(ctx)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, edges...)
+	}
+	return all, nil
+}
+
+// Version sums the versions of the opened stores, plus their count so that
+// opening a partition also counts as a change.
+func (p *PartitionedGraphStore) Version() uint64 {
+	stores := p.openStores()
+	v := uint64(len(stores))
+	for _, s := range stores {
+		v += s.Version()
+	}
+	return v
+}
+
+// EnableAppendLog enables the JSONL append log on every partition, including
+// those opened later. See SQLiteGraphStore.EnableAppendLog.
+func (p *PartitionedGraphStore) EnableAppendLog(compactThreshold int) error {
+	p.mu.Lock()
+	p.appendLogAt = compactThreshold
+	p.mu.Unlock()
+	for _, s := range p.openStores() {
+		if err := s.EnableAppendLog(compactThreshold); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateBehaviorGraph checks every partition for consistency issues.
+func (p *PartitionedGraphStore) ValidateBehaviorGraph(ctx context.Context) ([]ValidationError, error) {
+	return p.ValidateWithExternalIDs(ctx, nil)
+}
+
+// AllBehaviorIDs returns the IDs of the behaviors in every partition.
+func (p *PartitionedGraphStore) AllBehaviorIDs(ctx context.Context) (map[string]bool, error) {
+	stores, err := p.allStores()
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool)
+	for _, s := range stores {
+		partIDs, err := s.AllBehaviorIDs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for id := range partIDs {
+			ids[id] = true
+		}
+	}
+	return ids, nil
+}
+
+// ValidateWithExternalIDs validates every partition, treating behaviors in
+// the other partitions and externalIDs as known references.
+func (p *PartitionedGraphStore) ValidateWithExternalIDs(ctx context.Context, externalIDs map[string]bool) ([]ValidationError, error) {
+	known, err := p.AllBehaviorIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for id := range externalIDs {
+		known[id] = true
+	}
+	stores, err := p.allStores()
+	if err != nil {
+		return nil, err
+	}
+	var all []ValidationError
+	for _, s := range stores {
+		errs, err := s.ValidateWithExternalIDs(ctx, known)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, errs...)
+	}
+	return all, nil
+}
+
+// RecordClientActivation records a client activation in the partition
+// holding the behavior.
+func (p *PartitionedGraphStore) RecordClientActivation(ctx context.Context, behaviorID, client string) error {
+	return p.withOwner(ctx, behaviorID, func(s *SQLiteGraphStore) error {
+		return s.RecordClientActivation(ctx, behaviorID, client)
+	})
+}
+
+// GetClientStats returns per-client stats from every partition, merged by
+// client.
+func (p *PartitionedGraphStore) GetClientStats(ctx context.Context) ([]ClientStats, error) {
+	stores, err := p.allStores()
+	if err != nil {
+		return nil, err
+	}
+	var sets [][]ClientStats
+	for _, s := range stores {
+		stats, err := s.GetClientStats(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, stats)
+	}
+	return mergeClientStats(sets...), nil
+}
+
+// GetState returns state recorded in the core.
+func (p *PartitionedGraphStore) GetState(ctx context.Context, key string) (string, error) {
+	return p.core.GetState(ctx, key)
+}
+
+// SetState records state in the core.
+func (p *PartitionedGraphStore) SetState(ctx context.Context, key, value string) error {
+	return p.core.SetState(ctx, key, value)
+}
+
+// StoreEmbedding stores an embedding in the partition holding the behavior.
+func (p *PartitionedGraphStore) StoreEmbedding(ctx context.Context, behaviorID string, embedding []float32, modelName string) error {
+	return p.withOwner(ctx, behaviorID, func(s *SQLiteGraphStore) error {
+		return s.StoreEmbedding(ctx, behaviorID, embedding, modelName)
+	})
+}
+
+// GetAllEmbeddings returns the embeddings of every partition.
+func (p *PartitionedGraphStore) GetAllEmbeddings(ctx context.Context) ([]BehaviorEmbedding, error) {
+	stores, err := p.allStores()
+	if err != nil {
+		return nil, err
+	}
+	var all []BehaviorEmbedding
+	for _, s := range stores {
+		embeddings, err := s.GetAllEmbeddings(ctx)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, embeddings...)
+	}
+	return all, nil
+}
+
+// GetBehaviorIDsWithoutEmbeddings returns the IDs from every partition.
+func (p *PartitionedGraphStore) GetBehaviorIDsWithoutEmbeddings(ctx context.Context) ([]string, error) {
+	stores, err := p.allStores()
+	if err != nil {
+		return nil, err
+	}
+	var all []string
+	for _, s := range stores {
+		ids, err := s.GetBehaviorIDsWithoutEmbeddings(ctx)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, ids...)
+	}
+	return all, nil
+}
+
+// deleteEdgesOf removes every edge involving id.
+func (s *SQLiteGraphStore) deleteEdgesOf(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM edges WHERE source = ? OR target = ?`, id, id); err != nil {
+		return fmt.Errorf("failed to delete edges: %w", err)
+	}
+	s.bumpVersion()
+	return nil
+}
+
+func partitionName(key string) string {
+	if key == "" {
+		return "core"
+	}
+	return key
+}
+
+// partitionKey returns the partition strategy assigns node to, or "" for
+// the core. Communities are only assigned by RebalancePartitions.
+func partitionKey(strategy PartitionStrategy, node Node) string {
+	switch strategy {
+	case PartitionByLanguage:
+		when := nodeWhen(node)
+		for _, key := range []string{"language", "file_language", "file.language"} {
+			if lang, ok := when[key].(string); ok && lang != "" {
+				return partitionSlug("lang", lang)
+			}
+		}
+	case PartitionByTag:
+		if tags := nodeTags(node); len(tags) > 0 {
+			return partitionSlug("tag", tags[0])
+		}
+	}
+	return ""
+}
+
+// partitionSlug builds a partition key usable as a directory name.
+func partitionSlug(prefix, value string) string {
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			return r
+		}
+		return '-'
+	}, strings.ToLower(value))
+	return prefix + "-" + strings.Trim(slug, "-.")
+}
+
+func nodeWhen(node Node) map[string]interface{} {
+	when, _ := node.Content["when"].(map[string]interface{})
+	return when
+}
+
+// nodeTags returns a behavior's tags, sorted.
+func nodeTags(node Node) []string {
+	content, ok := node.Content["content"].(map[string]interface{})
+	if !ok && node.Content["content"] != nil {
+		// Content structs (e.g. from models.BehaviorToNode) via JSON round-trip
+		if data, err := json.Marshal(node.Content["content"]); err == nil {
+			_ = json.Unmarshal(data, &content)
+		}
+	}
+	var tags []string
+	switch raw := content["tags"].(type) {
+	case []string:
+		tags = append(tags, raw...)
+	case []interface{}:
+		for _, t := range raw {
+			if s, ok := t.(string); ok && s != "" {
+				tags = append(tags, s)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// sharesConditions reports whether when includes every condition in shared.
+func sharesConditions(when, shared map[string]interface{}) bool {
+	for key, value := range shared {
+		v, ok := when[key]
+		if !ok || !sameValue(v, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// commonConditions returns the conditions of a that b has too.
+func commonConditions(a, b map[string]interface{}) map[string]interface{} {
+	common := make(map[string]interface{})
+	for key, value := range a {
+		if v, ok := b[key]; ok && sameValue(v, value) {
+			common[key] = value
+		}
+	}
+	if len(common) == 0 {
+		return nil
+	}
+	return common
+}
+
+// sameValue compares condition values by their JSON encoding, so a []string
+// equals the []interface{} it decodes to.
+func sameValue(a, b interface{}) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
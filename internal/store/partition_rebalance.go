@@ -0,0 +1,346 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// communityRounds bounds the label propagation that finds communities.
+const communityRounds = 20
+
+// RebalanceOptions configures RebalancePartitions.
+type RebalanceOptions struct {
+	// Strategy assigns behaviors to partitions. PartitionByNone merges every
+	// partition back into the core and removes the partitions directory.
+	Strategy PartitionStrategy
+
+	// MinSize is the fewest behaviors a partition is kept for. Zero uses
+	// DefaultMinPartitionSize.
+	MinSize int
+
+	// DryRun reports the resulting layout without moving anything.
+	DryRun bool
+}
+
+// RebalanceReport describes the layout a rebalance produced (or, in a dry
+// run, would produce).
+type RebalanceReport struct {
+	Strategy   PartitionStrategy `json:"strategy"`
+	DryRun     bool              `json:"dry_run"`
+	Core       int               `json:"core"`
+	Partitions []PartitionInfo   `json:"partitions"`
+	Moved      int               `json:"moved"`
+	Removed    []string          `json:"removed,omitempty"`
+}
+
+// placedNode and placedEdge pair a node or edge with the partition it was
+// read from.
+type placedNode struct {
+	node Node
+	from string
+}
+
+type placedEdge struct {
+	edge Edge
+	from string
+}
+
+// RebalancePartitions redistributes the behaviors of the global store rooted
+// at projectRoot according to opts.Strategy, moving each behavior with its
+// stats and embedding, and rewrites the partition manifest. Edges follow
+// their endpoints. Moves are not recorded in the behavior changelog.
+func RebalancePartitions(ctx context.Context, projectRoot string, opts RebalanceOptions) (*RebalanceReport, error) {
+	if _, err := ParsePartitionStrategy(string(opts.Strategy)); err != nil {
+		return nil, err
+	}
+	minSize := opts.MinSize
+	if minSize <= 0 {
+		minSize = DefaultMinPartitionSize
+	}
+
+	p, err := NewPartitionedGraphStore(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	defer p.Close()
+	p.quiet = true
+	p.core.changelogFile = ""
+
+	stores, err := p.allStores()
+	if err != nil {
+		return nil, err
+	}
+	keys := p.keys(nil)
+
+	var nodes []placedNode
+	var edges []placedEdge
+	embeddings := make(map[string]BehaviorEmbedding)
+	for i, s := range stores {
+		partNodes, err := s.QueryNodes(ctx, map[string]interface{}{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read partition %s: %w", partitionName(keys[i]), err)
+		}
+		for _, n := range partNodes {
+			nodes = append(nodes, placedNode{node: n, from: keys[i]})
+		}
+		partEdges, err := s.GetAllEdges(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read edges of partition %s: %w", partitionName(keys[i]), err)
+		}
+		for _, e := range partEdges {
+			edges = append(edges, placedEdge{edge: e, from: keys[i]})
+		}
+		partEmbeddings, err := s.GetAllEmbeddings(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read embeddings of partition %s: %w", partitionName(keys[i]), err)
+		}
+		for _, e := range partEmbeddings {
+			embeddings[e.BehaviorID] = e
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].node.ID < nodes[j].node.ID })
+
+	target := assignPartitions(opts.Strategy, nodes, edges, minSize)
+	manifest := PartitionManifest{Strategy: opts.Strategy, UpdatedAt: time.Now().UTC()}
+	byKey := make(map[string]*PartitionInfo)
+	report := &RebalanceReport{Strategy: opts.Strategy, DryRun: opts.DryRun}
+	for _, pn := range nodes {
+		key := target[pn.node.ID]
+		if key != pn.from {
+			report.Moved++
+		}
+		if !isBehaviorKind(pn.node.Kind) {
+			continue
+		}
+		if key == "" {
+			report.Core++
+			continue
+		}
+		info, ok := byKey[key]
+		if !ok {
+			manifest.Partitions = append(manifest.Partitions, PartitionInfo{Key: key, When: nodeWhen(pn.node)})
+			info = &manifest.Partitions[len(manifest.Partitions)-1]
+			byKey[key] = info
+		}
+		info.When = commonConditions(info.When, nodeWhen(pn.node))
+		info.Behaviors++
+	}
+	ordered := make([]PartitionInfo, 0, len(byKey))
+	for _, info := range byKey {
+		ordered = append(ordered, *info)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].Key < ordered[j].Key })
+	manifest.Partitions = ordered
+	report.Partitions = ordered
+
+	current := make(map[string]bool)
+	for _, key := range keys[1:] {
+		current[key] = true
+	}
+	for key := range current {
+		if _, ok := byKey[key]; !ok {
+			report.Removed = append(report.Removed, key)
+		}
+	}
+	sort.Strings(report.Removed)
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	// Move nodes, then place each edge that touched a moved node
+	moved := make(map[string]bool)
+	for _, pn := range nodes {
+		to := target[pn.node.ID]
+		if to == pn.from {
+			continue
+		}
+		if err := p.moveNode(ctx, pn, to, embeddings); err != nil {
+			return nil, err
+		}
+		moved[pn.node.ID] = true
+	}
+	for _, pe := range edges {
+		if !moved[pe.edge.Source] && !moved[pe.edge.Target] {
+			continue
+		}
+		to := ""
+		src, srcOK := target[pe.edge.Source]
+		tgt, tgtOK := target[pe.edge.Target]
+		if srcOK && tgtOK && src == tgt {
+			to = src
+		}
+		if to != pe.from {
+			from, err := p.partition(pe.from)
+			if err != nil {
+				return nil, err
+			}
+			if err := from.RemoveEdge(ctx, pe.edge.Source, pe.edge.Target, pe.edge.Kind); err != nil {
+				return nil, err
+			}
+		}
+		dst, err := p.partition(to)
+		if err != nil {
+			return nil, err
+		}
+		dst.mu.Lock()
+		err = dst.insertEdge(ctx, pe.edge)
+		dst.mu.Unlock()
+		if err != nil {
+			return nil, fmt.Errorf("failed to move edge %s -> %s: %w", pe.edge.Source, pe.edge.Target, err)
+		}
+	}
+
+	if err := p.Sync(ctx); err != nil {
+		return nil, err
+	}
+	if err := p.writeIndex(ctx, target); err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	p.manifest = manifest
+	for _, key := range report.Removed {
+		if s, ok := p.parts[key]; ok {
+			s.Close()
+			delete(p.parts, key)
+		}
+	}
+	p.mu.Unlock()
+
+	partitionsDir := filepath.Join(p.floopDir, PartitionsDir)
+	for _, key := range report.Removed {
+		if err := os.RemoveAll(filepath.Join(partitionsDir, key)); err != nil {
+			return nil, fmt.Errorf("failed to remove partition %s: %w", key, err)
+		}
+	}
+	if opts.Strategy == PartitionByNone {
+		if err := os.RemoveAll(partitionsDir); err != nil {
+			return nil, fmt.Errorf("failed to remove partitions directory: %w", err)
+		}
+		return report, nil
+	}
+	if err := writePartitionManifest(p.floopDir, &manifest); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// moveNode copies a node, its creation time, and its embedding into
+// partition to and deletes it from the partition it was read from.
+func (p *PartitionedGraphStore) moveNode(ctx context.Context, pn placedNode, to string, embeddings map[string]BehaviorEmbedding) error {
+	from, err := p.partition(pn.from)
+	if err != nil {
+		return err
+	}
+	dst, err := p.partition(to)
+	if err != nil {
+		return err
+	}
+
+	if _, err := dst.AddNode(ctx, pn.node); err != nil {
+		return fmt.Errorf("failed to move %s to partition %s: %w", pn.node.ID, partitionName(to), err)
+	}
+	stats, _ := pn.node.Metadata["stats"].(map[string]interface{})
+	if createdAt, ok := stats["created_at"].(string); ok && createdAt != "" {
+		if _, err := dst.db.ExecContext(ctx, `UPDATE behaviors SET created_at = ? WHERE id = ?`, createdAt, pn.node.ID); err != nil {
+			return fmt.Errorf("failed to keep creation time of %s: %w", pn.node.ID, err)
+		}
+	}
+	if e, ok := embeddings[pn.node.ID]; ok {
+		if err := dst.StoreEmbedding(ctx, e.BehaviorID, e.Embedding, e.Model); err != nil {
+			return fmt.Errorf("failed to move embedding of %s: %w", pn.node.ID, err)
+		}
+	}
+	if err := from.DeleteNode(ctx, pn.node.ID); err != nil {
+		return fmt.Errorf("failed to remove %s from partition %s: %w", pn.node.ID, partitionName(pn.from), err)
+	}
+	p.setOwner(pn.node.ID, to)
+	return nil
+}
+
+// assignPartitions returns the target partition of every node. Non-behavior
+// nodes and partitions with fewer than minSize behaviors stay in the core.
+func assignPartitions(strategy PartitionStrategy, nodes []placedNode, edges []placedEdge, minSize int) map[string]string {
+	target := make(map[string]string, len(nodes))
+	var behaviorIDs []string
+	for _, pn := range nodes {
+		target[pn.node.ID] = ""
+		if isBehaviorKind(pn.node.Kind) {
+			behaviorIDs = append(behaviorIDs, pn.node.ID)
+			target[pn.node.ID] = partitionKey(strategy, pn.node)
+		}
+	}
+	if strategy == PartitionByCommunity {
+		for id, label := range communities(behaviorIDs, edges) {
+			target[id] = partitionSlug("community", label)
+		}
+	}
+
+	sizes := make(map[string]int)
+	for _, key := range target {
+		sizes[key]++
+	}
+	for id, key := range target {
+		if key != "" && sizes[key] < minSize {
+			target[id] = ""
+		}
+	}
+	return target
+}
+
+// communities labels each behavior with the community found by weighted
+// label propagation: every behavior repeatedly adopts the label carrying
+// the most edge weight among its neighbors, ties going to the smallest
+// label. ids must be sorted, which makes the result deterministic.
+func communities(ids []string, edges []placedEdge) map[string]string {
+	known := make(map[string]bool, len(ids))
+	labels := make(map[string]string, len(ids))
+	for _, id := range ids {
+		known[id] = true
+		labels[id] = id
+	}
+	type neighbor struct {
+		id     string
+		weight float64
+	}
+	adjacent := make(map[string][]neighbor)
+	for _, pe := range edges {
+		e := pe.edge
+		if e.Source == e.Target || !known[e.Source] || !known[e.Target] {
+			continue
+		}
+		adjacent[e.Source] = append(adjacent[e.Source], neighbor{e.Target, e.Weight})
+		adjacent[e.Target] = append(adjacent[e.Target], neighbor{e.Source, e.Weight})
+	}
+
+	for round := 0; round < communityRounds; round++ {
+		changed := false
+		for _, id := range ids {
+			if len(adjacent[id]) == 0 {
+				continue
+			}
+			weights := make(map[string]float64)
+			for _, n := range adjacent[id] {
+				weights[labels[n.id]] += n.weight
+			}
+			best, bestWeight := labels[id], weights[labels[id]]
+			for label, w := range weights {
+				if w > bestWeight || (w == bestWeight && label < best) {
+					best, bestWeight = label, w
+				}
+			}
+			if best != labels[id] {
+				labels[id] = best
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return labels
+}
@@ -0,0 +1,331 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func partitionTestNode(id, language string, tags ...string) Node {
+	content := map[string]interface{}{
+		"name":    id,
+		"kind":    "directive",
+		"content": map[string]interface{}{"canonical": id + " canonical", "tags": tags},
+	}
+	if language != "" {
+		content["when"] = map[string]interface{}{"language": language}
+	}
+	return Node{ID: id, Kind: NodeKindBehavior, Content: content}
+}
+
+// newPartitionTestStore creates an unpartitioned global store with three Go
+// behaviors, two Python behaviors, and one without a language.
+func newPartitionTestStore(t *testing.T) string {
+	t.Helper()
+	ctx := context.Background()
+	root := t.TempDir()
+	s, err := NewSQLiteGraphStore(root)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	for _, n := range []Node{
+		partitionTestNode("go-1", "go", "testing"),
+		partitionTestNode("go-2", "go", "errors"),
+		partitionTestNode("go-3", "go"),
+		partitionTestNode("py-1", "python", "testing"),
+		partitionTestNode("py-2", "python"),
+		partitionTestNode("any-1", "", "testing"),
+	} {
+		mustAddNode(t, s, ctx, n)
+	}
+	now := time.Now()
+	mustAddEdge(t, s, ctx, Edge{Source: "go-1", Target: "go-2", Kind: EdgeKindSimilarTo, Weight: 0.8, CreatedAt: now})
+	mustAddEdge(t, s, ctx, Edge{Source: "go-1", Target: "py-1", Kind: EdgeKindSimilarTo, Weight: 0.5, CreatedAt: now})
+	if err := s.StoreEmbedding(ctx, "py-1", []float32{0.1, 0.2}, "test-model"); err != nil {
+		t.Fatalf("StoreEmbedding() error = %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return root
+}
+
+func TestRebalancePartitions(t *testing.T) {
+	ctx := context.Background()
+	root := newPartitionTestStore(t)
+	floopDir := filepath.Join(root, ".floop")
+	opts := RebalanceOptions{Strategy: PartitionByLanguage, MinSize: 2}
+
+	dry := opts
+	dry.DryRun = true
+	report, err := RebalancePartitions(ctx, root, dry)
+	if err != nil {
+		t.Fatalf("RebalancePartitions(dry run) error = %v", err)
+	}
+	if report.Moved != 5 || report.Core != 1 || len(report.Partitions) != 2 {
+		t.Errorf("dry run report = %+v, want 5 moved into 2 partitions and 1 left in the core", report)
+	}
+	if m, _ := ReadPartitionManifest(floopDir); m != nil {
+		t.Fatal("dry run wrote a manifest")
+	}
+
+	report, err = RebalancePartitions(ctx, root, opts)
+	if err != nil {
+		t.Fatalf("RebalancePartitions() error = %v", err)
+	}
+	want := []PartitionInfo{
+		{Key: "lang-go", When: map[string]interface{}{"language": "go"}, Behaviors: 3},
+		{Key: "lang-python", When: map[string]interface{}{"language": "python"}, Behaviors: 2},
+	}
+	m, err := ReadPartitionManifest(floopDir)
+	if err != nil || m == nil {
+		t.Fatalf("ReadPartitionManifest() = %v, %v", m, err)
+	}
+	for i, w := range want {
+		if i >= len(m.Partitions) || m.Partitions[i].Key != w.Key || m.Partitions[i].Behaviors != w.Behaviors || !sameValue(m.Partitions[i].When, w.When) {
+			t.Fatalf("partitions = %+v, want %+v", m.Partitions, want)
+		}
+	}
+
+	gs, err := NewGlobalGraphStore(root)
+	if err != nil {
+		t.Fatalf("NewGlobalGraphStore() error = %v", err)
+	}
+	p, ok := gs.(*PartitionedGraphStore)
+	if !ok {
+		t.Fatalf("NewGlobalGraphStore() = %T, want *PartitionedGraphStore", gs)
+	}
+	defer p.Close()
+
+	nodes, err := p.QueryNodes(ctx, map[string]interface{}{"kind": string(NodeKindBehavior)})
+	if err != nil || len(nodes) != 6 {
+		t.Fatalf("QueryNodes() = %d nodes, %v; want 6", len(nodes), err)
+	}
+	if edges := mustGetEdges(t, p, ctx, "go-1", DirectionOutbound, ""); len(edges) != 2 {
+		t.Errorf("go-1 edges = %+v, want the in-partition and the cross-partition edge", edges)
+	}
+	if reached, err := p.Traverse(ctx, "go-2", nil, DirectionBoth, 2); err != nil || len(reached) != 3 {
+		t.Errorf("Traverse() = %d nodes, %v; want go-2, go-1, and py-1", len(reached), err)
+	}
+	embeddings, err := p.GetAllEmbeddings(ctx)
+	if err != nil || len(embeddings) != 1 || embeddings[0].BehaviorID != "py-1" {
+		t.Errorf("GetAllEmbeddings() = %+v, %v; want py-1's embedding", embeddings, err)
+	}
+	if errs, err := p.ValidateBehaviorGraph(ctx); err != nil || len(errs) != 0 {
+		t.Errorf("ValidateBehaviorGraph() = %+v, %v; want no issues", errs, err)
+	}
+}
+
+func TestPartitionedGraphStore_Routing(t *testing.T) {
+	ctx := context.Background()
+	root := newPartitionTestStore(t)
+	if _, err := RebalancePartitions(ctx, root, RebalanceOptions{Strategy: PartitionByLanguage, MinSize: 2}); err != nil {
+		t.Fatalf("RebalancePartitions() error = %v", err)
+	}
+
+	p, err := NewPartitionedGraphStore(root)
+	if err != nil {
+		t.Fatalf("NewPartitionedGraphStore() error = %v", err)
+	}
+	defer p.Close()
+
+	// A Go context never opens the Python partition
+	goOnly := WithPartitionFilter(ctx, func(when map[string]interface{}) bool {
+		lang, ok := when["language"]
+		return !ok || lang == "go"
+	})
+	nodes, err := p.QueryNodes(goOnly, map[string]interface{}{"kind": string(NodeKindBehavior)})
+	if err != nil || len(nodes) != 4 {
+		t.Fatalf("filtered QueryNodes() = %d nodes, %v; want 4", len(nodes), err)
+	}
+	if _, opened := p.parts["lang-python"]; opened || len(p.parts) != 1 {
+		t.Errorf("opened partitions = %v, want only lang-go", p.parts)
+	}
+
+	tests := []struct {
+		name string
+		node Node
+		want string
+	}{
+		{"existing language partition", partitionTestNode("go-4", "go"), "lang-go"},
+		{"language without a partition", partitionTestNode("rust-1", "rust"), ""},
+		{"no language", partitionTestNode("any-2", ""), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mustAddNode(t, p, ctx, tt.node)
+			if key, s, _, err := p.find(ctx, tt.node.ID); err != nil || s == nil || key != tt.want {
+				t.Errorf("partition of %s = %q, %v; want %q", tt.node.ID, key, err, tt.want)
+			}
+		})
+	}
+
+	// An update that drops the shared condition widens the partition
+	moved := partitionTestNode("go-3", "rust")
+	if err := p.UpdateNode(ctx, moved); err != nil {
+		t.Fatalf("UpdateNode() error = %v", err)
+	}
+	m, _ := ReadPartitionManifest(p.floopDir)
+	for _, info := range m.Partitions {
+		if info.Key == "lang-go" && len(info.When) != 0 {
+			t.Errorf("lang-go when = %v, want no shared conditions", info.When)
+		}
+	}
+}
+
+func TestPartitionedGraphStore_LazyLookup(t *testing.T) {
+	ctx := context.Background()
+	home := newPartitionTestStore(t)
+	if _, err := RebalancePartitions(ctx, home, RebalanceOptions{Strategy: PartitionByLanguage, MinSize: 2}); err != nil {
+		t.Fatalf("RebalancePartitions() error = %v", err)
+	}
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	project := t.TempDir()
+
+	m, err := NewMultiGraphStore(project)
+	if err != nil {
+		t.Fatalf("NewMultiGraphStore() error = %v", err)
+	}
+	defer m.Close()
+	p, ok := m.GlobalStore().(*PartitionedGraphStore)
+	if !ok {
+		t.Fatalf("global store = %T, want *PartitionedGraphStore", m.GlobalStore())
+	}
+
+	// Lookups of local IDs never open a global partition
+	for _, id := range []string{"local-1", "local-2"} {
+		if _, err := m.AddNodeToScope(ctx, partitionTestNode(id, "go"), ScopeLocal); err != nil {
+			t.Fatalf("AddNodeToScope(%s) error = %v", id, err)
+		}
+	}
+	mustAddEdge(t, m, ctx, Edge{Source: "local-1", Target: "local-2", Kind: EdgeKindSimilarTo, Weight: 0.5, CreatedAt: time.Now()})
+	if node, err := m.GetNode(ctx, "local-1"); err != nil || node == nil {
+		t.Fatalf("GetNode(local-1) = %v, %v", node, err)
+	}
+	if edges := mustGetEdges(t, m, ctx, "local-1", DirectionBoth, ""); len(edges) != 1 {
+		t.Errorf("local-1 edges = %+v, want one", edges)
+	}
+	if err := m.TouchEdges(ctx, []string{"local-1", "local-2"}); err != nil {
+		t.Fatalf("TouchEdges() error = %v", err)
+	}
+	if node, err := p.GetNode(ctx, "missing"); err != nil || node != nil {
+		t.Errorf("GetNode(missing) = %v, %v; want nil", node, err)
+	}
+	if len(p.parts) != 0 {
+		t.Errorf("opened partitions = %v, want none", p.parts)
+	}
+
+	// A partitioned ID opens only its own partition
+	if node, err := m.GetNode(ctx, "py-1"); err != nil || node == nil {
+		t.Fatalf("GetNode(py-1) = %v, %v", node, err)
+	}
+	if _, opened := p.parts["lang-go"]; opened || len(p.parts) != 1 {
+		t.Errorf("opened partitions = %v, want only lang-python", p.parts)
+	}
+
+	// Behaviors added later are indexed too, and a store partitioned before
+	// the index existed builds it on first lookup
+	mustAddNode(t, p, ctx, partitionTestNode("go-4", "go"))
+	if err := p.core.SetState(ctx, partitionIndexBuilt, ""); err != nil {
+		t.Fatalf("SetState() error = %v", err)
+	}
+	for _, id := range []string{"go-4", "py-2"} {
+		fresh, err := NewPartitionedGraphStore(home)
+		if err != nil {
+			t.Fatalf("NewPartitionedGraphStore() error = %v", err)
+		}
+		if node, err := fresh.GetNode(ctx, id); err != nil || node == nil {
+			t.Errorf("GetNode(%s) = %v, %v", id, node, err)
+		}
+		fresh.Close()
+	}
+	fresh, err := NewPartitionedGraphStore(home)
+	if err != nil {
+		t.Fatalf("NewPartitionedGraphStore() error = %v", err)
+	}
+	defer fresh.Close()
+	if node, err := fresh.GetNode(ctx, "go-4"); err != nil || node == nil || len(fresh.parts) != 1 {
+		t.Errorf("GetNode(go-4) = %v, %v; opened %v, want only lang-go", node, err, fresh.parts)
+	}
+}
+
+func TestRebalancePartitions_MergeBack(t *testing.T) {
+	ctx := context.Background()
+	root := newPartitionTestStore(t)
+	if _, err := RebalancePartitions(ctx, root, RebalanceOptions{Strategy: PartitionByTag, MinSize: 3}); err != nil {
+		t.Fatalf("RebalancePartitions(tag) error = %v", err)
+	}
+	m, _ := ReadPartitionManifest(filepath.Join(root, ".floop"))
+	if m == nil || len(m.Partitions) != 1 || m.Partitions[0].Key != "tag-testing" || len(m.Partitions[0].When) != 0 {
+		t.Fatalf("manifest = %+v, want one tag-testing partition without shared conditions", m)
+	}
+
+	report, err := RebalancePartitions(ctx, root, RebalanceOptions{Strategy: PartitionByNone})
+	if err != nil {
+		t.Fatalf("RebalancePartitions(none) error = %v", err)
+	}
+	if report.Moved != 3 || len(report.Removed) != 1 {
+		t.Errorf("report = %+v, want 3 moved back and tag-testing removed", report)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".floop", PartitionsDir)); !os.IsNotExist(err) {
+		t.Errorf("partitions directory still exists: %v", err)
+	}
+
+	gs, err := NewGlobalGraphStore(root)
+	if err != nil {
+		t.Fatalf("NewGlobalGraphStore() error = %v", err)
+	}
+	defer gs.Close()
+	if _, ok := gs.(*SQLiteGraphStore); !ok {
+		t.Fatalf("NewGlobalGraphStore() = %T, want *SQLiteGraphStore", gs)
+	}
+	nodes, _ := gs.QueryNodes(ctx, map[string]interface{}{"kind": string(NodeKindBehavior)})
+	if len(nodes) != 6 {
+		t.Errorf("behaviors = %d, want 6", len(nodes))
+	}
+	if edges := mustGetEdges(t, gs, ctx, "go-1", DirectionOutbound, ""); len(edges) != 2 {
+		t.Errorf("go-1 edges = %d, want 2", len(edges))
+	}
+}
+
+func TestCommunities(t *testing.T) {
+	edge := func(src, tgt string, w float64) placedEdge {
+		return placedEdge{edge: Edge{Source: src, Target: tgt, Weight: w}}
+	}
+	ids := []string{"a1", "a2", "a3", "b1", "b2", "b3", "lone"}
+	edges := []placedEdge{
+		edge("a1", "a2", 0.9), edge("a2", "a3", 0.9), edge("a1", "a3", 0.9),
+		edge("b1", "b2", 0.9), edge("b2", "b3", 0.9), edge("b1", "b3", 0.9),
+		edge("a3", "b1", 0.1),
+		edge("a1", "outside", 1.0),
+	}
+
+	labels := communities(ids, edges)
+	for _, group := range [][]string{{"a1", "a2", "a3"}, {"b1", "b2", "b3"}} {
+		for _, id := range group[1:] {
+			if labels[id] != labels[group[0]] {
+				t.Errorf("%s and %s in different communities: %v", id, group[0], labels)
+			}
+		}
+	}
+	if labels["a1"] == labels["b1"] {
+		t.Errorf("weakly linked groups merged: %v", labels)
+	}
+	if labels["lone"] != "lone" {
+		t.Errorf("lone label = %q, want its own", labels["lone"])
+	}
+}
+
+func TestParsePartitionStrategy(t *testing.T) {
+	for _, s := range []string{"none", "language", "tag", "community"} {
+		if got, err := ParsePartitionStrategy(s); err != nil || string(got) != s {
+			t.Errorf("ParsePartitionStrategy(%q) = %q, %v", s, got, err)
+		}
+	}
+	if _, err := ParsePartitionStrategy("size"); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+}
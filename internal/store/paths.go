@@ -47,6 +47,24 @@ floop.db-wal
 
 # Audit logs (runtime data, not version controlled)
 audit.jsonl
+
+# Corrections log of earlier floop versions, since imported into floop.db
+corrections.jsonl.imported
+
+# Detected repository facts (recomputed when marker files change)
+context-cache.json
+
+# MCP tool metrics snapshot (rewritten while mcp-server runs)
+metrics.json
+
+# Event log of mcp-server event_bus file sinks
+events.jsonl
+
+# Markers of mcp-server background writes in flight, for floop flush
+pending/
+
+# Working clone for floop sync push/pull
+sync/
 `
 
 // EnsureGitignore creates a .gitignore in the given .floop directory if one
@@ -7,10 +7,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"time"
+
+	"github.com/nvandessel/floop/internal/constants"
 )
 
 // SchemaVersion is the current schema version.
-const SchemaVersion = 10
+const SchemaVersion = 16
 
 // EventsTableDDL is the canonical DDL for the events table.
 // Both the initial schema and migrations reference this constant.
@@ -35,6 +37,81 @@ CREATE INDEX IF NOT EXISTS idx_events_timestamp ON events(timestamp);
 CREATE INDEX IF NOT EXISTS idx_events_project ON events(project_id);
 CREATE INDEX IF NOT EXISTS idx_events_consolidated ON events(consolidated)`
 
+// CorrectionsTableDDL is the canonical DDL for the corrections table.
+// Both the initial schema and migrations reference this constant.
+const CorrectionsTableDDL = `CREATE TABLE IF NOT EXISTS corrections (
+    id TEXT PRIMARY KEY,
+    timestamp TEXT NOT NULL,
+    agent_action TEXT NOT NULL,
+    corrected_action TEXT NOT NULL,
+    human_response TEXT,
+    context TEXT,  -- JSON
+    conversation_id TEXT,
+    turn_number INTEGER,
+    corrector TEXT,
+    extra_tags TEXT,  -- JSON
+    extra_when TEXT,  -- JSON
+    failure_id TEXT,
+    expires_at TEXT,
+    processed INTEGER DEFAULT 0,
+    processed_at TEXT
+)`
+
+// CorrectionsIndexesDDL is the canonical DDL for the corrections table
+// indexes (V16), which serve listing by time and by processed state.
+const CorrectionsIndexesDDL = `CREATE INDEX IF NOT EXISTS idx_corrections_timestamp ON corrections(timestamp, id);
+CREATE INDEX IF NOT EXISTS idx_corrections_processed ON corrections(processed)`
+
+// correctionsV16Columns are the columns V16 adds to corrections tables
+// created before it.
+var correctionsV16Columns = []struct{ name, def string }{
+	{"extra_tags", "TEXT"},
+	{"extra_when", "TEXT"},
+	{"failure_id", "TEXT"},
+	{"expires_at", "TEXT"},
+}
+
+// BehaviorSearchDDL creates the full-text index over behavior names and
+// content (V15) and the triggers that keep it in step with the behaviors
+// table. The index is an external-content FTS5 table keyed by the behaviors
+// rowid. INSERT OR REPLACE does not fire delete triggers, so the stale entry
+// of a replaced behavior is removed before the insert instead.
+const BehaviorSearchDDL = `CREATE VIRTUAL TABLE IF NOT EXISTS behaviors_fts USING fts5(
+    name, content_canonical, content_expanded, content_summary, content_tags,
+    content='behaviors', content_rowid='rowid', tokenize='porter unicode61'
+);
+
+CREATE TRIGGER IF NOT EXISTS behavior_fts_replace
+BEFORE INSERT ON behaviors
+BEGIN
+    INSERT INTO behaviors_fts (behaviors_fts, rowid, name, content_canonical, content_expanded, content_summary, content_tags)
+    SELECT 'delete', rowid, name, content_canonical, content_expanded, content_summary, content_tags
+    FROM behaviors WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS behavior_fts_insert
+AFTER INSERT ON behaviors
+BEGIN
+    INSERT INTO behaviors_fts (rowid, name, content_canonical, content_expanded, content_summary, content_tags)
+    VALUES (NEW.rowid, NEW.name, NEW.content_canonical, NEW.content_expanded, NEW.content_summary, NEW.content_tags);
+END;
+
+CREATE TRIGGER IF NOT EXISTS behavior_fts_update
+AFTER UPDATE ON behaviors
+BEGIN
+    INSERT INTO behaviors_fts (behaviors_fts, rowid, name, content_canonical, content_expanded, content_summary, content_tags)
+    VALUES ('delete', OLD.rowid, OLD.name, OLD.content_canonical, OLD.content_expanded, OLD.content_summary, OLD.content_tags);
+    INSERT INTO behaviors_fts (rowid, name, content_canonical, content_expanded, content_summary, content_tags)
+    VALUES (NEW.rowid, NEW.name, NEW.content_canonical, NEW.content_expanded, NEW.content_summary, NEW.content_tags);
+END;
+
+CREATE TRIGGER IF NOT EXISTS behavior_fts_delete
+AFTER DELETE ON behaviors
+BEGIN
+    INSERT INTO behaviors_fts (behaviors_fts, rowid, name, content_canonical, content_expanded, content_summary, content_tags)
+    VALUES ('delete', OLD.rowid, OLD.name, OLD.content_canonical, OLD.content_expanded, OLD.content_summary, OLD.content_tags);
+END`
+
 // schemaV1 is the initial schema for the SQLite store.
 const schemaV1 = `
 -- Core behavior table (denormalized for single-query retrieval)
@@ -55,6 +132,7 @@ CREATE TABLE IF NOT EXISTS behaviors (
     provenance_source_type TEXT,
     provenance_correction_id TEXT,
     provenance_created_at TEXT,
+    provenance_source_agent TEXT,  -- MCP client that learned the behavior (V11)
 
     -- Relationships (JSON arrays)
     requires TEXT,
@@ -80,6 +158,7 @@ CREATE TABLE IF NOT EXISTS behaviors (
     updated_at TEXT NOT NULL,
     content_hash TEXT UNIQUE
 );
+CREATE INDEX IF NOT EXISTS idx_behaviors_name ON behaviors(name);
 
 -- Context conditions (enables indexed lookups)
 CREATE TABLE IF NOT EXISTS behavior_when (
@@ -91,6 +170,14 @@ CREATE TABLE IF NOT EXISTS behavior_when (
 );
 CREATE INDEX IF NOT EXISTS idx_when_field_value ON behavior_when(field, value);
 
+-- Behavior tags, one row per tag (V14), for indexed tag filtering
+CREATE TABLE IF NOT EXISTS behavior_tags (
+    behavior_id TEXT NOT NULL REFERENCES behaviors(id) ON DELETE CASCADE,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (behavior_id, tag)
+);
+CREATE INDEX IF NOT EXISTS idx_behavior_tags_tag ON behavior_tags(tag);
+
 -- Stats (frequently updated, kept separate)
 CREATE TABLE IF NOT EXISTS behavior_stats (
     behavior_id TEXT PRIMARY KEY REFERENCES behaviors(id) ON DELETE CASCADE,
@@ -102,20 +189,9 @@ CREATE TABLE IF NOT EXISTS behavior_stats (
     last_confirmed TEXT
 );
 
--- Corrections
-CREATE TABLE IF NOT EXISTS corrections (
-    id TEXT PRIMARY KEY,
-    timestamp TEXT NOT NULL,
-    agent_action TEXT NOT NULL,
-    corrected_action TEXT NOT NULL,
-    human_response TEXT,
-    context TEXT,  -- JSON
-    conversation_id TEXT,
-    turn_number INTEGER,
-    corrector TEXT,
-    processed INTEGER DEFAULT 0,
-    processed_at TEXT
-);
+-- Corrections (columns and indexes as of V16)
+` + CorrectionsTableDDL + `;
+` + CorrectionsIndexesDDL + `;
 
 -- Edges (graph relationships)
 CREATE TABLE IF NOT EXISTS edges (
@@ -180,6 +256,20 @@ CREATE TABLE IF NOT EXISTS consolidation_runs (
 CREATE INDEX IF NOT EXISTS idx_consolidation_runs_project ON consolidation_runs(project_id);
 CREATE INDEX IF NOT EXISTS idx_consolidation_runs_session ON consolidation_runs(session_id);
 
+-- Per-client activation stats (V11). No FK: INSERT OR REPLACE on behaviors
+-- would cascade-delete these rows on every update.
+CREATE TABLE IF NOT EXISTS behavior_client_stats (
+    behavior_id TEXT NOT NULL,
+    client TEXT NOT NULL,
+    times_activated INTEGER DEFAULT 0,
+    last_activated TEXT,
+    PRIMARY KEY (behavior_id, client)
+);
+CREATE INDEX IF NOT EXISTS idx_behavior_client_stats_client ON behavior_client_stats(client);
+
+-- Full-text search over behaviors (V15)
+` + BehaviorSearchDDL + `;
+
 -- Schema version
 CREATE TABLE IF NOT EXISTS schema_version (
     version INTEGER PRIMARY KEY,
@@ -229,6 +319,13 @@ func InitSchema(ctx context.Context, db *sql.DB) error {
 // The projectID is passed through to migrateSchema and used by the V9
 // migration to transform scope values (local -> project:<id>).
 func initSchemaWithProject(ctx context.Context, db *sql.DB, projectID string) error {
+	return initSchemaTimed(ctx, db, projectID, nil)
+}
+
+// initSchemaTimed is initSchemaWithProject, recording the integrity check in
+// timing when it is non-nil. The structural integrity check runs at most once
+// per constants.IntegrityCheckIntervalHours, and always before migrations.
+func initSchemaTimed(ctx context.Context, db *sql.DB, projectID string, timing *OpenTiming) error {
 	// Check current schema version
 	currentVersion, err := getSchemaVersion(ctx, db)
 	if err != nil {
@@ -259,6 +356,8 @@ func initSchemaWithProject(ctx context.Context, db *sql.DB, projectID string) er
 		if err := createSchema(ctx, db); err != nil {
 			return fmt.Errorf("failed to create schema: %w", err)
 		}
+		// A database created just now has nothing to check yet.
+		recordIntegrityCheck(ctx, db, time.Now())
 		return nil
 	}
 
@@ -266,8 +365,17 @@ func initSchemaWithProject(ctx context.Context, db *sql.DB, projectID string) er
 	// Only runs PRAGMA integrity_check, NOT foreign_key_check — FK violations
 	// are data-level issues that shouldn't block schema migration or startup.
 	// Use ValidateIntegrity() or floop_validate for full validation including FK.
-	if err := validateStructuralIntegrity(ctx, db); err != nil {
-		return fmt.Errorf("database integrity check failed: %w", err)
+	checked := false
+	if currentVersion < SchemaVersion || integrityCheckDue(ctx, db, time.Now()) {
+		start := time.Now()
+		if err := validateStructuralIntegrity(ctx, db); err != nil {
+			return fmt.Errorf("database integrity check failed: %w", err)
+		}
+		checked = true
+		if timing != nil {
+			timing.IntegrityChecked = true
+			timing.Integrity = time.Since(start)
+		}
 	}
 
 	// Apply migrations if needed
@@ -277,9 +385,39 @@ func initSchemaWithProject(ctx context.Context, db *sql.DB, projectID string) er
 		}
 	}
 
+	if checked {
+		recordIntegrityCheck(ctx, db, time.Now())
+	}
+
 	return nil
 }
 
+// integrityCheckKey is the config table key holding when the structural
+// integrity check last passed.
+const integrityCheckKey = "last_integrity_check"
+
+// integrityCheckDue reports whether the structural integrity check has not
+// passed within constants.IntegrityCheckIntervalHours of now. A missing or
+// unreadable record counts as due.
+func integrityCheckDue(ctx context.Context, db *sql.DB, now time.Time) bool {
+	var value string
+	if err := db.QueryRowContext(ctx, `SELECT value FROM config WHERE key = ?`, integrityCheckKey).Scan(&value); err != nil {
+		return true
+	}
+	last, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return true
+	}
+	return now.Sub(last) >= time.Duration(constants.IntegrityCheckIntervalHours)*time.Hour
+}
+
+// recordIntegrityCheck stores now as the last passing integrity check.
+// Failures are ignored; the check simply runs again on the next open.
+func recordIntegrityCheck(ctx context.Context, db *sql.DB, now time.Time) {
+	_, _ = db.ExecContext(ctx, `INSERT OR REPLACE INTO config (key, value) VALUES (?, ?)`,
+		integrityCheckKey, now.UTC().Format(time.RFC3339))
+}
+
 // tableExists checks if a table exists in the database.
 func tableExists(ctx context.Context, db *sql.DB, table string) bool {
 	var name string
@@ -372,6 +510,36 @@ func migrateSchema(ctx context.Context, db *sql.DB, currentVersion int, projectI
 			return fmt.Errorf("migrate v9 to v10: %w", err)
 		}
 	}
+	if currentVersion < 11 {
+		if err := migrateV10ToV11(ctx, db); err != nil {
+			return fmt.Errorf("migrate v10 to v11: %w", err)
+		}
+	}
+	if currentVersion < 12 {
+		if err := migrateV11ToV12(ctx, db); err != nil {
+			return fmt.Errorf("migrate v11 to v12: %w", err)
+		}
+	}
+	if currentVersion < 13 {
+		if err := migrateV12ToV13(ctx, db); err != nil {
+			return fmt.Errorf("migrate v12 to v13: %w", err)
+		}
+	}
+	if currentVersion < 14 {
+		if err := migrateV13ToV14(ctx, db); err != nil {
+			return fmt.Errorf("migrate v13 to v14: %w", err)
+		}
+	}
+	if currentVersion < 15 {
+		if err := migrateV14ToV15(ctx, db); err != nil {
+			return fmt.Errorf("migrate v14 to v15: %w", err)
+		}
+	}
+	if currentVersion < 16 {
+		if err := migrateV15ToV16(ctx, db); err != nil {
+			return fmt.Errorf("migrate v15 to v16: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -491,7 +659,7 @@ func migrateV2ToV3(ctx context.Context, db *sql.DB) error {
 	}
 
 	// Backfill existing edges: weight=1.0, created_at=now (RFC3339 format)
-	now := time.Now().Format(time.RFC3339)
+	now := formatTimestamp(time.Now())
 	_, err = tx.ExecContext(ctx, `UPDATE edges SET weight = 1.0 WHERE weight IS NULL`)
 	if err != nil {
 		return fmt.Errorf("backfill weight: %w", err)
@@ -903,6 +1071,374 @@ func migrateV9ToV10(ctx context.Context, db *sql.DB) error {
 	return tx.Commit()
 }
 
+// migrateV10ToV11 adds cross-agent attribution: the provenance_source_agent
+// column on behaviors and the behavior_client_stats table.
+func migrateV10ToV11(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Add provenance_source_agent if missing (idempotent)
+	hasColumn := false
+	rows, err := tx.QueryContext(ctx, `PRAGMA table_info(behaviors)`)
+	if err != nil {
+		return fmt.Errorf("check table info: %w", err)
+	}
+	for rows.Next() {
+		var cid int
+		var name, ctype string
+		var notnull, pk int
+		var dfltValue interface{}
+		if err := rows.Scan(&cid, &name, &ctype, &notnull, &dfltValue, &pk); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan table info: %w", err)
+		}
+		if name == "provenance_source_agent" {
+			hasColumn = true
+		}
+	}
+	rows.Close()
+
+	if !hasColumn {
+		if _, err := tx.ExecContext(ctx, `ALTER TABLE behaviors ADD COLUMN provenance_source_agent TEXT`); err != nil {
+			return fmt.Errorf("add provenance_source_agent column: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS behavior_client_stats (
+			behavior_id TEXT NOT NULL,
+			client TEXT NOT NULL,
+			times_activated INTEGER DEFAULT 0,
+			last_activated TEXT,
+			PRIMARY KEY (behavior_id, client)
+		)`); err != nil {
+		return fmt.Errorf("create behavior_client_stats table: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`CREATE INDEX IF NOT EXISTS idx_behavior_client_stats_client ON behavior_client_stats(client)`); err != nil {
+		return fmt.Errorf("create behavior_client_stats client index: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO schema_version (version, applied_at) VALUES (?, datetime('now'))`, 11)
+	if err != nil {
+		return fmt.Errorf("record schema version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// timestampColumns lists the timestamp columns rewritten in UTC by the V12
+// migration.
+var timestampColumns = []struct{ table, column string }{
+	{"behaviors", "created_at"},
+	{"behaviors", "updated_at"},
+	{"behaviors", "provenance_created_at"},
+	{"behavior_stats", "last_activated"},
+	{"behavior_stats", "last_confirmed"},
+	{"behavior_client_stats", "last_activated"},
+	{"edges", "created_at"},
+	{"edges", "last_activated"},
+	{"co_activations", "activated_at"},
+	{"corrections", "timestamp"},
+	{"corrections", "processed_at"},
+	{"events", "timestamp"},
+	{"events", "created_at"},
+}
+
+// migrateV11ToV12 rewrites timestamps stored with a local UTC offset in UTC,
+// so string comparisons order them correctly across machine timezones. This
+// covers the timestamp columns and the timestamps in behaviors.metadata_extra.
+// Rewritten behaviors are marked dirty by the triggers, so the next export
+// normalizes the JSONL files too.
+func migrateV11ToV12(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, c := range timestampColumns {
+		if err := normalizeTimestampColumn(ctx, tx, c.table, c.column); err != nil {
+			return fmt.Errorf("normalize %s.%s: %w", c.table, c.column, err)
+		}
+	}
+	if err := normalizeMetadataExtraTimestamps(ctx, tx); err != nil {
+		return fmt.Errorf("normalize behaviors.metadata_extra: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO schema_version (version, applied_at) VALUES (?, datetime('now'))`, 12)
+	if err != nil {
+		return fmt.Errorf("record schema version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// migrateV12ToV13 indexes behavior names, so behaviors can be looked up by
+// name without scanning the table.
+func migrateV12ToV13(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`CREATE INDEX IF NOT EXISTS idx_behaviors_name ON behaviors(name)`); err != nil {
+		return fmt.Errorf("create idx_behaviors_name: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO schema_version (version, applied_at) VALUES (?, datetime('now'))`, 13)
+	if err != nil {
+		return fmt.Errorf("record schema version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// migrateV13ToV14 adds the behavior_tags table and fills it from the tags
+// stored with each behavior.
+func migrateV13ToV14(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS behavior_tags (
+		behavior_id TEXT NOT NULL REFERENCES behaviors(id) ON DELETE CASCADE,
+		tag TEXT NOT NULL,
+		PRIMARY KEY (behavior_id, tag)
+	)`); err != nil {
+		return fmt.Errorf("create behavior_tags: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`CREATE INDEX IF NOT EXISTS idx_behavior_tags_tag ON behavior_tags(tag)`); err != nil {
+		return fmt.Errorf("create idx_behavior_tags_tag: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, content_tags FROM behaviors WHERE content_tags IS NOT NULL`)
+	if err != nil {
+		return fmt.Errorf("read behavior tags: %w", err)
+	}
+	tagsByID := make(map[string][]string)
+	for rows.Next() {
+		var id, tagsJSON string
+		if err := rows.Scan(&id, &tagsJSON); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan behavior tags: %w", err)
+		}
+		var tags []string
+		if err := json.Unmarshal([]byte(tagsJSON), &tags); err != nil {
+			continue // Not a list of strings; there is nothing to index
+		}
+		tagsByID[id] = tags
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("read behavior tags: %w", err)
+	}
+	for id, tags := range tagsByID {
+		if err := insertTags(ctx, tx, id, tags); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO schema_version (version, applied_at) VALUES (?, datetime('now'))`, 14)
+	if err != nil {
+		return fmt.Errorf("record schema version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// migrateV14ToV15 adds the behaviors_fts full-text index and its triggers,
+// and builds the index from the existing behaviors.
+func migrateV14ToV15(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, BehaviorSearchDDL); err != nil {
+		return fmt.Errorf("create behaviors_fts: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO behaviors_fts (behaviors_fts) VALUES ('rebuild')`); err != nil {
+		return fmt.Errorf("build behaviors_fts: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO schema_version (version, applied_at) VALUES (?, datetime('now'))`, 15)
+	if err != nil {
+		return fmt.Errorf("record schema version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// migrateV15ToV16 makes the corrections table the store of record for
+// corrections: it adds the columns corrections.jsonl records carried that
+// the table lacked, and indexes for listing by time and processed state.
+func migrateV15ToV16(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := upgradeCorrectionsTable(ctx, tx); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO schema_version (version, applied_at) VALUES (?, datetime('now'))`, 16)
+	if err != nil {
+		return fmt.Errorf("record schema version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// InitCorrectionsSchema creates the corrections table and its indexes if
+// they do not exist, and adds the V16 columns to a table created before
+// them. It lets a corrections store use a database no graph store has
+// opened, or not yet migrated.
+func InitCorrectionsSchema(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := upgradeCorrectionsTable(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// upgradeCorrectionsTable brings the corrections table to its V16 form.
+func upgradeCorrectionsTable(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, CorrectionsTableDDL); err != nil {
+		return fmt.Errorf("create corrections: %w", err)
+	}
+	for _, col := range correctionsV16Columns {
+		var n int
+		if err := tx.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM pragma_table_info('corrections') WHERE name = ?`, col.name).Scan(&n); err != nil {
+			return fmt.Errorf("check corrections columns: %w", err)
+		}
+		if n > 0 {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+			`ALTER TABLE corrections ADD COLUMN %s %s`, col.name, col.def)); err != nil {
+			return fmt.Errorf("add corrections.%s column: %w", col.name, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, CorrectionsIndexesDDL); err != nil {
+		return fmt.Errorf("create corrections indexes: %w", err)
+	}
+	return nil
+}
+
+// normalizeTimestampColumn rewrites the non-UTC RFC3339 values of one column.
+// Columns missing from a partially migrated database are skipped.
+func normalizeTimestampColumn(ctx context.Context, tx *sql.Tx, table, column string) error {
+	var n int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column).Scan(&n); err != nil {
+		return err
+	}
+	if n == 0 {
+		return nil
+	}
+
+	// G201: table and column come from the fixed timestampColumns list.
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf( //nolint:gosec // constant identifiers
+		`SELECT rowid, %[2]s FROM %[1]s WHERE %[2]s IS NOT NULL AND %[2]s NOT LIKE '%%Z'`, table, column))
+	if err != nil {
+		return err
+	}
+	updates := make(map[int64]string)
+	for rows.Next() {
+		var rowid int64
+		var value string
+		if err := rows.Scan(&rowid, &value); err != nil {
+			rows.Close()
+			return err
+		}
+		if normalized := normalizeTimestamp(value); normalized != value {
+			updates[rowid] = normalized
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// OR REPLACE: co_activations keys on the timestamp, and two offsets of
+	// the same instant collapse into one row
+	update := fmt.Sprintf(`UPDATE OR REPLACE %s SET %s = ? WHERE rowid = ?`, table, column) //nolint:gosec // constant identifiers
+	for rowid, value := range updates {
+		if _, err := tx.ExecContext(ctx, update, value, rowid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeMetadataExtraTimestamps rewrites the timestamps in each
+// behavior's metadata_extra JSON (retired_at, expires_at, and so on).
+func normalizeMetadataExtraTimestamps(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `SELECT id, metadata_extra FROM behaviors WHERE metadata_extra IS NOT NULL`)
+	if err != nil {
+		return err
+	}
+	updates := make(map[string][]byte)
+	for rows.Next() {
+		var id, extraJSON string
+		if err := rows.Scan(&id, &extraJSON); err != nil {
+			rows.Close()
+			return err
+		}
+		var extra map[string]interface{}
+		if json.Unmarshal([]byte(extraJSON), &extra) != nil {
+			continue
+		}
+		before := fmt.Sprint(extra)
+		normalizeMetadataTimestamps(extra)
+		if fmt.Sprint(extra) == before {
+			continue
+		}
+		data, err := json.Marshal(extra)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		updates[id] = data
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, data := range updates {
+		if _, err := tx.ExecContext(ctx, `UPDATE behaviors SET metadata_extra = ? WHERE id = ?`, string(data), id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // validateStructuralIntegrity checks for SQLite database corruption.
 // It only runs PRAGMA integrity_check — not foreign_key_check.
 // Use ValidateIntegrity for full validation including FK checks.
@@ -974,6 +1510,7 @@ func ValidateIntegrity(ctx context.Context, db *sql.DB) error {
 func ResetSchema(ctx context.Context, db *sql.DB) error {
 	// Drop all tables
 	tables := []string{
+		"behavior_client_stats",
 		"consolidation_runs",
 		"events",
 		"co_activations",
@@ -982,6 +1519,7 @@ func ResetSchema(ctx context.Context, db *sql.DB) error {
 		"behavior_when",
 		"edges",
 		"corrections",
+		"behaviors_fts",
 		"behaviors",
 		"export_state",
 		"config",
@@ -994,6 +1532,10 @@ func ResetSchema(ctx context.Context, db *sql.DB) error {
 		"behavior_update_dirty",
 		"behavior_delete_dirty",
 		"behavior_stats_dirty",
+		"behavior_fts_replace",
+		"behavior_fts_insert",
+		"behavior_fts_update",
+		"behavior_fts_delete",
 	}
 
 	for _, trigger := range triggers {
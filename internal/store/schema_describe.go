@@ -0,0 +1,133 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// SQLiteSchema describes the tables, indexes, and triggers of the current
+// store schema, as a freshly initialized database has them.
+type SQLiteSchema struct {
+	Version  int           `json:"version"`
+	Tables   []TableSchema `json:"tables"`
+	Triggers []SQLObject   `json:"triggers"`
+}
+
+// TableSchema describes one table and its indexes.
+type TableSchema struct {
+	Name    string         `json:"name"`
+	SQL     string         `json:"sql"`
+	Columns []ColumnSchema `json:"columns"`
+	Indexes []SQLObject    `json:"indexes,omitempty"`
+}
+
+// ColumnSchema describes one table column, as PRAGMA table_info reports it.
+type ColumnSchema struct {
+	Name    string  `json:"name"`
+	Type    string  `json:"type"`
+	NotNull bool    `json:"not_null"`
+	Default *string `json:"default,omitempty"`
+	// PrimaryKey is the column's position in the primary key, from 1, or 0
+	// if it is not part of it.
+	PrimaryKey int `json:"primary_key,omitempty"`
+}
+
+// SQLObject is an index or trigger and the table it belongs to.
+type SQLObject struct {
+	Name  string `json:"name"`
+	Table string `json:"table"`
+	SQL   string `json:"sql"`
+}
+
+// DescribeSchema initializes an in-memory database with the current schema
+// and describes it.
+func DescribeSchema(ctx context.Context) (*SQLiteSchema, error) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open in-memory database: %w", err)
+	}
+	defer db.Close()
+	// Every connection to :memory: is a separate database
+	db.SetMaxOpenConns(1)
+
+	if err := InitSchema(ctx, db); err != nil {
+		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	}
+	return describeSchema(ctx, db)
+}
+
+// describeSchema reads the schema of db from sqlite_master.
+func describeSchema(ctx context.Context, db *sql.DB) (*SQLiteSchema, error) {
+	version, err := getSchemaVersion(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT type, name, tbl_name, sql FROM sqlite_master
+		WHERE name NOT LIKE 'sqlite_%' AND sql IS NOT NULL ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sqlite_master: %w", err)
+	}
+	schema := &SQLiteSchema{Version: version}
+	var indexes []SQLObject
+	for rows.Next() {
+		var typ string
+		var obj SQLObject
+		if err := rows.Scan(&typ, &obj.Name, &obj.Table, &obj.SQL); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan sqlite_master: %w", err)
+		}
+		switch typ {
+		case "table":
+			schema.Tables = append(schema.Tables, TableSchema{Name: obj.Name, SQL: obj.SQL})
+		case "index":
+			indexes = append(indexes, obj)
+		case "trigger":
+			schema.Triggers = append(schema.Triggers, obj)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read sqlite_master: %w", err)
+	}
+
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+		if table.Columns, err = describeColumns(ctx, db, table.Name); err != nil {
+			return nil, err
+		}
+		for _, idx := range indexes {
+			if idx.Table == table.Name {
+				table.Indexes = append(table.Indexes, idx)
+			}
+		}
+	}
+	return schema, nil
+}
+
+// describeColumns reads the columns of table.
+func describeColumns(ctx context.Context, db *sql.DB, table string) ([]ColumnSchema, error) {
+	rows, err := db.QueryContext(ctx, `SELECT name, type, "notnull", dflt_value, pk FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []ColumnSchema
+	for rows.Next() {
+		var col ColumnSchema
+		var dflt sql.NullString
+		if err := rows.Scan(&col.Name, &col.Type, &col.NotNull, &dflt, &col.PrimaryKey); err != nil {
+			return nil, fmt.Errorf("failed to scan columns of %s: %w", table, err)
+		}
+		if dflt.Valid {
+			col.Default = &dflt.String
+		}
+		columns = append(columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	return columns, nil
+}
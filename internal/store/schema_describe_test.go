@@ -0,0 +1,44 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDescribeSchema(t *testing.T) {
+	s, err := DescribeSchema(context.Background())
+	if err != nil {
+		t.Fatalf("DescribeSchema() error = %v", err)
+	}
+	if s.Version != SchemaVersion {
+		t.Errorf("Version = %d, want %d", s.Version, SchemaVersion)
+	}
+
+	tables := make(map[string]TableSchema)
+	for _, table := range s.Tables {
+		tables[table.Name] = table
+	}
+	behaviors, ok := tables["behaviors"]
+	if !ok {
+		t.Fatalf("tables = %v, want behaviors", s.Tables)
+	}
+	columns := make(map[string]ColumnSchema)
+	for _, col := range behaviors.Columns {
+		columns[col.Name] = col
+	}
+	if id := columns["id"]; id.Type != "TEXT" || id.PrimaryKey != 1 {
+		t.Errorf("behaviors.id = %+v, want TEXT primary key", id)
+	}
+	if _, ok := columns["metadata_extra"]; !ok {
+		t.Error("behaviors lacks metadata_extra, a column added by migration")
+	}
+	if name := columns["name"]; !name.NotNull || name.Default != nil {
+		t.Errorf("behaviors.name = %+v, want NOT NULL without default", name)
+	}
+	if len(behaviors.Indexes) == 0 {
+		t.Error("behaviors has no indexes")
+	}
+	if len(s.Triggers) == 0 {
+		t.Error("no triggers described")
+	}
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"testing"
 
 	_ "modernc.org/sqlite"
@@ -864,3 +865,296 @@ func getColumns(t *testing.T, db *sql.DB, table string) map[string]bool {
 	}
 	return cols
 }
+
+func TestMigrateV10ToV11_AddsClientAttribution(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	if err := InitSchema(ctx, db); err != nil {
+		t.Fatalf("InitSchema failed: %v", err)
+	}
+
+	// Roll back to a v10 database
+	for _, stmt := range []string{
+		`DROP TABLE behavior_client_stats`,
+		`ALTER TABLE behaviors DROP COLUMN provenance_source_agent`,
+		`DELETE FROM schema_version WHERE version >= 11`,
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	if err := InitSchema(ctx, db); err != nil {
+		t.Fatalf("InitSchema (migrate) failed: %v", err)
+	}
+
+	var name string
+	err = db.QueryRowContext(ctx, `SELECT name FROM sqlite_master WHERE type='table' AND name='behavior_client_stats'`).Scan(&name)
+	if err != nil {
+		t.Fatalf("behavior_client_stats table should exist: %v", err)
+	}
+
+	if _, err := db.ExecContext(ctx, `SELECT provenance_source_agent FROM behaviors`); err != nil {
+		t.Errorf("provenance_source_agent column should exist: %v", err)
+	}
+
+	var version int
+	db.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_version`).Scan(&version)
+	if version != SchemaVersion {
+		t.Errorf("schema version = %d, want %d", version, SchemaVersion)
+	}
+}
+
+func TestMigrateV11ToV12_NormalizesTimestamps(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	if err := InitSchema(ctx, db); err != nil {
+		t.Fatalf("InitSchema failed: %v", err)
+	}
+
+	// A v11 database written on a machine at UTC-5 (and one at UTC+2)
+	for _, stmt := range []string{
+		`INSERT INTO behaviors (id, name, kind, content_canonical, provenance_created_at, metadata_extra, created_at, updated_at)
+		 VALUES ('b-1', 'b-1', 'behavior', 'Wrap errors', '2026-03-08T01:30:00.5-05:00',
+		         '{"retired_at":"2026-03-08T01:30:00-05:00","forget_reason":"stale"}',
+		         '2026-03-08T01:30:00-05:00', '2026-03-08 07:00:00')`,
+		`INSERT INTO behavior_stats (behavior_id, last_activated) VALUES ('b-1', '2026-03-29T03:30:00+02:00')`,
+		`INSERT INTO edges (source, target, kind, created_at) VALUES ('b-1', 'b-2', 'similar-to', '2026-11-01T01:30:00-05:00')`,
+		`INSERT INTO co_activations (pair_key, activated_at) VALUES ('b-1|b-2', '2026-11-01T01:30:00-04:00')`,
+		`INSERT INTO co_activations (pair_key, activated_at) VALUES ('b-1|b-2', '2026-11-01T05:30:00Z')`,
+		`DELETE FROM schema_version WHERE version >= 12`,
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	if err := InitSchema(ctx, db); err != nil {
+		t.Fatalf("InitSchema (migrate) failed: %v", err)
+	}
+
+	checks := []struct {
+		query string
+		want  string
+	}{
+		{`SELECT provenance_created_at FROM behaviors WHERE id = 'b-1'`, "2026-03-08T06:30:00.5Z"},
+		{`SELECT created_at FROM behaviors WHERE id = 'b-1'`, "2026-03-08T06:30:00Z"},
+		{`SELECT updated_at FROM behaviors WHERE id = 'b-1'`, "2026-03-08 07:00:00"},
+		{`SELECT metadata_extra FROM behaviors WHERE id = 'b-1'`, `{"forget_reason":"stale","retired_at":"2026-03-08T06:30:00Z"}`},
+		{`SELECT last_activated FROM behavior_stats WHERE behavior_id = 'b-1'`, "2026-03-29T01:30:00Z"},
+		{`SELECT created_at FROM edges`, "2026-11-01T06:30:00Z"},
+		{`SELECT group_concat(activated_at) FROM co_activations`, "2026-11-01T05:30:00Z"},
+	}
+	for _, c := range checks {
+		var got string
+		if err := db.QueryRowContext(ctx, c.query).Scan(&got); err != nil {
+			t.Fatalf("%s: %v", c.query, err)
+		}
+		if got != c.want {
+			t.Errorf("%s = %q, want %q", c.query, got, c.want)
+		}
+	}
+
+	var version int
+	db.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_version`).Scan(&version)
+	if version != SchemaVersion {
+		t.Errorf("schema version = %d, want %d", version, SchemaVersion)
+	}
+}
+
+func TestMigrateV12ToV13_IndexesBehaviorNames(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	if err := InitSchema(ctx, db); err != nil {
+		t.Fatalf("InitSchema failed: %v", err)
+	}
+
+	// Roll back to a v12 database
+	for _, stmt := range []string{
+		`DROP INDEX idx_behaviors_name`,
+		`DELETE FROM schema_version WHERE version >= 13`,
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	if err := InitSchema(ctx, db); err != nil {
+		t.Fatalf("InitSchema (migrate) failed: %v", err)
+	}
+
+	var detail string
+	err = db.QueryRowContext(ctx, `EXPLAIN QUERY PLAN SELECT id FROM behaviors WHERE name = 'x'`).Scan(new(int), new(int), new(int), &detail)
+	if err != nil {
+		t.Fatalf("explain query plan: %v", err)
+	}
+	if !strings.Contains(detail, "idx_behaviors_name") {
+		t.Errorf("name lookup plan = %q, want it to use idx_behaviors_name", detail)
+	}
+
+	var version int
+	db.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_version`).Scan(&version)
+	if version != SchemaVersion {
+		t.Errorf("schema version = %d, want %d", version, SchemaVersion)
+	}
+}
+
+func TestMigrateV13ToV14_BackfillsBehaviorTags(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	if err := InitSchema(ctx, db); err != nil {
+		t.Fatalf("InitSchema failed: %v", err)
+	}
+
+	// Roll back to a v13 database holding tagged behaviors
+	for _, stmt := range []string{
+		`DROP TABLE behavior_tags`,
+		`DELETE FROM schema_version WHERE version >= 14`,
+		`INSERT INTO behaviors (id, name, kind, content_canonical, content_tags, created_at, updated_at)
+		 VALUES ('b1', 'one', 'behavior', 'one', '["go","testing/unit"]', '2026-01-01T00:00:00Z', '2026-01-01T00:00:00Z')`,
+		`INSERT INTO behaviors (id, name, kind, content_canonical, content_tags, created_at, updated_at)
+		 VALUES ('b2', 'two', 'behavior', 'two', 'not json', '2026-01-01T00:00:00Z', '2026-01-01T00:00:00Z')`,
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	if err := InitSchema(ctx, db); err != nil {
+		t.Fatalf("InitSchema (migrate) failed: %v", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT behavior_id || ':' || tag FROM behavior_tags ORDER BY behavior_id, tag`)
+	if err != nil {
+		t.Fatalf("query behavior_tags: %v", err)
+	}
+	defer rows.Close()
+	var got []string
+	for rows.Next() {
+		var row string
+		if err := rows.Scan(&row); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, row)
+	}
+	if want := "b1:go,b1:testing/unit"; strings.Join(got, ",") != want {
+		t.Errorf("behavior_tags = %v, want %s", got, want)
+	}
+
+	var detail string
+	err = db.QueryRowContext(ctx, `EXPLAIN QUERY PLAN SELECT behavior_id FROM behavior_tags WHERE tag = 'go'`).Scan(new(int), new(int), new(int), &detail)
+	if err != nil {
+		t.Fatalf("explain query plan: %v", err)
+	}
+	if !strings.Contains(detail, "idx_behavior_tags_tag") {
+		t.Errorf("tag lookup plan = %q, want it to use idx_behavior_tags_tag", detail)
+	}
+}
+
+func TestMigrateV14ToV15_BuildsSearchIndex(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	if err := InitSchema(ctx, db); err != nil {
+		t.Fatalf("InitSchema failed: %v", err)
+	}
+
+	// Roll back to a v14 database holding a behavior the index has not seen
+	for _, stmt := range []string{
+		`DROP TRIGGER behavior_fts_replace`,
+		`DROP TRIGGER behavior_fts_insert`,
+		`DROP TRIGGER behavior_fts_update`,
+		`DROP TRIGGER behavior_fts_delete`,
+		`DROP TABLE behaviors_fts`,
+		`DELETE FROM schema_version WHERE version >= 15`,
+		`INSERT INTO behaviors (id, name, kind, content_canonical, created_at, updated_at)
+		 VALUES ('b1', 'lint', 'behavior', 'Run golangci-lint before committing', '2026-01-01T00:00:00Z', '2026-01-01T00:00:00Z')`,
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	if err := InitSchema(ctx, db); err != nil {
+		t.Fatalf("InitSchema (migrate) failed: %v", err)
+	}
+
+	var id string
+	err = db.QueryRowContext(ctx, `SELECT b.id FROM behaviors_fts
+		JOIN behaviors b ON b.rowid = behaviors_fts.rowid
+		WHERE behaviors_fts MATCH 'commit*'`).Scan(&id)
+	if err != nil || id != "b1" {
+		t.Errorf("search after migration = %q, %v; want b1", id, err)
+	}
+}
+
+func TestMigrateV15ToV16_UpgradesCorrections(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	if err := InitSchema(ctx, db); err != nil {
+		t.Fatalf("InitSchema failed: %v", err)
+	}
+
+	// Roll back to a v15 corrections table holding one correction
+	for _, stmt := range []string{
+		`DROP TABLE corrections`,
+		`CREATE TABLE corrections (id TEXT PRIMARY KEY, timestamp TEXT NOT NULL, agent_action TEXT NOT NULL, corrected_action TEXT NOT NULL, human_response TEXT, context TEXT, conversation_id TEXT, turn_number INTEGER, corrector TEXT, processed INTEGER DEFAULT 0, processed_at TEXT)`,
+		`INSERT INTO corrections (id, timestamp, agent_action, corrected_action) VALUES ('c1', '2026-01-01T00:00:00Z', 'used os.path', 'use pathlib')`,
+		`DELETE FROM schema_version WHERE version >= 16`,
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	if err := InitSchema(ctx, db); err != nil {
+		t.Fatalf("InitSchema (migrate) failed: %v", err)
+	}
+
+	var id string
+	var failureID sql.NullString
+	if err := db.QueryRowContext(ctx, `SELECT id, failure_id FROM corrections INDEXED BY idx_corrections_timestamp
+		WHERE timestamp >= '2026-01-01T00:00:00Z'`).Scan(&id, &failureID); err != nil || id != "c1" || failureID.Valid {
+		t.Errorf("correction after migration = %q, %v, %v; want c1 with no failure_id", id, failureID, err)
+	}
+
+	// InitCorrectionsSchema is idempotent on an up-to-date table
+	if err := InitCorrectionsSchema(ctx, db); err != nil {
+		t.Errorf("InitCorrectionsSchema() error = %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ScopesDir is the directory under the global .floop directory holding
+// named scope stores, e.g. ~/.floop/scopes/go/.
+const ScopesDir = "scopes"
+
+// scopeOriginPrefix starts the Origin of nodes read from a scope store.
+const scopeOriginPrefix = "scope:"
+
+// ScopeOrigin returns the Origin of nodes read from the named scope store.
+func ScopeOrigin(name string) Origin {
+	return Origin(scopeOriginPrefix + name)
+}
+
+// ScopeName returns the scope store name of a scope origin, and whether o
+// is one.
+func (o Origin) ScopeName() (string, bool) {
+	return strings.CutPrefix(string(o), scopeOriginPrefix)
+}
+
+// ReadOnly reports whether nodes of this origin come from a read-only store:
+// a scope store or the policy store.
+func (o Origin) ReadOnly() bool {
+	_, scoped := o.ScopeName()
+	return o == OriginPolicy || scoped
+}
+
+// scopeChain holds the entries set with SetScopeChain.
+var scopeChain struct {
+	mu      sync.RWMutex
+	entries []string
+}
+
+// SetScopeChain sets the scope stores every MultiGraphStore opened from now
+// on reads below its local and global stores, in precedence order. See
+// ResolveScope for the entry syntax.
+func SetScopeChain(entries []string) {
+	scopeChain.mu.Lock()
+	defer scopeChain.mu.Unlock()
+	scopeChain.entries = append([]string(nil), entries...)
+}
+
+// ScopeChain returns the entries set with SetScopeChain.
+func ScopeChain() []string {
+	scopeChain.mu.RLock()
+	defer scopeChain.mu.RUnlock()
+	return append([]string(nil), scopeChain.entries...)
+}
+
+// ScopeStore is one resolved entry of the scope chain.
+type ScopeStore struct {
+	Name string `json:"name"`
+	Dir  string `json:"dir"` // Directory holding the store's nodes.jsonl
+}
+
+// ResolveScope resolves a scope chain entry. A bare name like "go" is the
+// store in ~/.floop/scopes/go; anything else is a directory path, with ~
+// expanded and relative paths resolved against projectRoot, named after its
+// last element. A directory containing .floop holds its store there.
+func ResolveScope(projectRoot, entry string) (ScopeStore, error) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" || entry == "." || entry == ".." {
+		return ScopeStore{}, fmt.Errorf("invalid scope %q", entry)
+	}
+
+	if !strings.ContainsAny(entry, `/\`) && !strings.HasPrefix(entry, "~") {
+		globalPath, err := GlobalFloopPath()
+		if err != nil {
+			return ScopeStore{}, err
+		}
+		return ScopeStore{Name: entry, Dir: filepath.Join(globalPath, ScopesDir, entry)}, nil
+	}
+
+	dir, err := resolveStoreDir(projectRoot, entry)
+	if err != nil {
+		return ScopeStore{}, err
+	}
+	name := filepath.Base(dir)
+	if info, err := os.Stat(filepath.Join(dir, ".floop")); err == nil && info.IsDir() {
+		dir = filepath.Join(dir, ".floop")
+	}
+	return ScopeStore{Name: name, Dir: dir}, nil
+}
+
+// resolveStoreDir expands ~ in a store directory path and resolves a
+// relative one against projectRoot.
+func resolveStoreDir(projectRoot, dir string) (string, error) {
+	if dir == "~" || strings.HasPrefix(dir, "~/") || strings.HasPrefix(dir, `~\`) {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		dir = filepath.Join(homeDir, dir[1:])
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(projectRoot, dir)
+	}
+	return filepath.Clean(dir), nil
+}
+
+// scopeLayer is an opened scope store.
+type scopeLayer struct {
+	name  string
+	store GraphStore
+}
+
+// openScopeStores opens the stores of the scope chain that exist. Entries
+// that cannot be resolved or opened, and later entries repeating a name, are
+// skipped with a warning on stderr so a missing team store never blocks
+// local work.
+func openScopeStores(projectRoot string) []scopeLayer {
+	var layers []scopeLayer
+	seen := make(map[string]bool)
+	for _, entry := range ScopeChain() {
+		scope, err := ResolveScope(projectRoot, entry)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: skipping scope store: %v\n", err)
+			continue
+		}
+		if seen[scope.Name] {
+			fmt.Fprintf(os.Stderr, "warning: skipping scope store %s: scope %q is already in the chain\n", scope.Dir, scope.Name)
+			continue
+		}
+		if info, err := os.Stat(scope.Dir); err != nil || !info.IsDir() {
+			fmt.Fprintf(os.Stderr, "warning: skipping scope store %s: not a directory\n", scope.Dir)
+			continue
+		}
+		gs, err := openSQLiteGraphStore(projectRoot, scope.Dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: skipping scope store %s: %v\n", scope.Dir, err)
+			continue
+		}
+		seen[scope.Name] = true
+		layers = append(layers, scopeLayer{name: scope.Name, store: gs})
+	}
+	return layers
+}
+
+// policyDir holds the directory set with SetPolicyStoreDir.
+var policyDir struct {
+	mu  sync.RWMutex
+	dir string
+}
+
+// SetPolicyStoreDir sets the policy store every MultiGraphStore opened from
+// now on reads below its scope chain: a directory of store files (or one
+// with a .floop subdirectory), with ~ expanded and relative paths resolved
+// against the project root. Empty sets none.
+func SetPolicyStoreDir(dir string) {
+	policyDir.mu.Lock()
+	defer policyDir.mu.Unlock()
+	policyDir.dir = strings.TrimSpace(dir)
+}
+
+// openPolicyStore opens the policy store set with SetPolicyStoreDir. It
+// returns nil if none is set, and skips one that is missing or cannot be
+// opened with a warning on stderr.
+func openPolicyStore(projectRoot string) GraphStore {
+	policyDir.mu.RLock()
+	entry := policyDir.dir
+	policyDir.mu.RUnlock()
+	if entry == "" {
+		return nil
+	}
+
+	dir, err := resolveStoreDir(projectRoot, entry)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: skipping policy store: %v\n", err)
+		return nil
+	}
+	if info, err := os.Stat(filepath.Join(dir, ".floop")); err == nil && info.IsDir() {
+		dir = filepath.Join(dir, ".floop")
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		fmt.Fprintf(os.Stderr, "warning: skipping policy store %s: not a directory\n", dir)
+		return nil
+	}
+	gs, err := openSQLiteGraphStore(projectRoot, dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: skipping policy store %s: %v\n", dir, err)
+		return nil
+	}
+	return gs
+}
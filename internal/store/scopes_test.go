@@ -0,0 +1,138 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveScope(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "org", ".floop"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		entry    string
+		wantName string
+		wantDir  string
+		wantErr  bool
+	}{
+		{"go", "go", filepath.Join(home, ".floop", ScopesDir, "go"), false},
+		{"~/team-floop", "team-floop", filepath.Join(home, "team-floop"), false},
+		{"./shared", "shared", filepath.Join(root, "shared"), false},
+		{"org", "org", filepath.Join(home, ".floop", ScopesDir, "org"), false},
+		{"./org", "org", filepath.Join(root, "org", ".floop"), false},
+		{filepath.Join(root, "abs"), "abs", filepath.Join(root, "abs"), false},
+		{" ", "", "", true},
+		{"..", "", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ResolveScope(root, tt.entry)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ResolveScope(%q) error = %v, wantErr %v", tt.entry, err, tt.wantErr)
+			continue
+		}
+		if got.Name != tt.wantName || got.Dir != tt.wantDir {
+			t.Errorf("ResolveScope(%q) = %+v, want {%s %s}", tt.entry, got, tt.wantName, tt.wantDir)
+		}
+	}
+}
+
+func TestNewMultiGraphStore_OpensScopeChain(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	root := t.TempDir()
+	ctx := context.Background()
+
+	// A scope store is any directory of store files
+	scopeDir := filepath.Join(home, ".floop", ScopesDir, "go")
+	scope, err := openSQLiteGraphStore(root, scopeDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := scope.AddNode(ctx, Node{ID: "go-rule", Kind: NodeKindBehavior, Content: map[string]interface{}{
+		"name": "go-rule", "kind": "directive", "content": map[string]interface{}{"canonical": "Wrap errors"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := scope.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	SetScopeChain([]string{"go", "missing", "go"})
+	t.Cleanup(func() { SetScopeChain(nil) })
+
+	m, err := NewMultiGraphStore(root)
+	if err != nil {
+		t.Fatalf("NewMultiGraphStore() error = %v", err)
+	}
+	defer m.Close()
+
+	if got := m.Scopes(); len(got) != 1 || got[0] != "go" {
+		t.Errorf("Scopes() = %v, want [go]", got)
+	}
+	node, err := m.GetNode(ctx, "go-rule")
+	if err != nil || node == nil || node.Origin != ScopeOrigin("go") {
+		t.Fatalf("GetNode(go-rule) = %+v, %v; want origin scope:go", node, err)
+	}
+	if name, ok := node.Origin.ScopeName(); !ok || name != "go" || !node.Origin.ReadOnly() {
+		t.Errorf("origin %q: ScopeName() = %q, %v", node.Origin, name, ok)
+	}
+}
+
+func TestNewMultiGraphStore_OpensPolicyStore(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	root := t.TempDir()
+	ctx := context.Background()
+
+	// A policy checkout keeps its store in a .floop subdirectory
+	policyDir := filepath.Join(home, "org-policy")
+	policy, err := openSQLiteGraphStore(root, filepath.Join(policyDir, ".floop"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := policy.AddNode(ctx, Node{ID: "org-rule", Kind: NodeKindBehavior, Content: map[string]interface{}{
+		"name": "org-rule", "kind": "constraint", "content": map[string]interface{}{"canonical": "Never commit secrets"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := policy.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	SetPolicyStoreDir("~/org-policy")
+	t.Cleanup(func() { SetPolicyStoreDir("") })
+
+	m, err := NewMultiGraphStore(root)
+	if err != nil {
+		t.Fatalf("NewMultiGraphStore() error = %v", err)
+	}
+	defer m.Close()
+
+	node, err := m.GetNode(ctx, "org-rule")
+	if err != nil || node == nil || node.Origin != OriginPolicy {
+		t.Fatalf("GetNode(org-rule) = %+v, %v; want origin policy", node, err)
+	}
+	if err := m.UpdateNode(ctx, *node); err == nil {
+		t.Error("UpdateNode() of a policy behavior should fail")
+	}
+
+	// A missing policy store is skipped
+	SetPolicyStoreDir(filepath.Join(home, "missing"))
+	m2, err := NewMultiGraphStore(root)
+	if err != nil {
+		t.Fatalf("NewMultiGraphStore() with a missing policy store error = %v", err)
+	}
+	defer m2.Close()
+	if node, _ := m2.GetNode(ctx, "org-rule"); node != nil {
+		t.Errorf("GetNode(org-rule) = %+v, want nil without a policy store", node)
+	}
+}
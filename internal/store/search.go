@@ -0,0 +1,137 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/nvandessel/floop/internal/utils"
+)
+
+// DefaultSearchLimit is the number of hits SearchBehaviors returns when
+// given no limit.
+const DefaultSearchLimit = 20
+
+// SearchHit is a behavior matching a keyword search.
+type SearchHit struct {
+	Node Node `json:"node"`
+	// Score ranks the hit; higher is more relevant. Scores are comparable
+	// within one search only.
+	Score float64 `json:"score"`
+}
+
+// BehaviorSearcher is implemented by stores with a full-text index over
+// behavior names and content.
+type BehaviorSearcher interface {
+	// SearchBehaviors returns up to limit active behaviors matching any
+	// keyword of query, most relevant first. Words match by prefix and by
+	// stem, so "test" finds "testing". A query without words matches
+	// nothing. A limit of zero or less means DefaultSearchLimit.
+	SearchBehaviors(ctx context.Context, query string, limit int) ([]SearchHit, error)
+}
+
+// SearchBehaviors searches s with the semantics of BehaviorSearcher. Stores
+// that are not BehaviorSearchers are scanned, scoring each behavior by the
+// keywords found in its name, content, and tags.
+func SearchBehaviors(ctx context.Context, s GraphStore, query string, limit int) ([]SearchHit, error) {
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if searcher, ok := s.(BehaviorSearcher); ok {
+		return searcher.SearchBehaviors(ctx, query, limit)
+	}
+
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	var hits []SearchHit
+	err := WalkNodes(ctx, s, map[string]interface{}{"kind": string(NodeKindBehavior)}, func(node Node) error {
+		if score := scanScore(node, terms); score > 0 {
+			hits = append(hits, SearchHit{Node: node, Score: score})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rankHits(hits, limit), nil
+}
+
+// searchTerms splits a query into lowercase words, dropping punctuation and
+// FTS5 operators so that any user text is a valid query.
+func searchTerms(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	seen := make(map[string]bool, len(words))
+	terms := words[:0]
+	for _, w := range words {
+		if !seen[w] {
+			seen[w] = true
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
+
+// ftsMatch builds an FTS5 MATCH expression matching any of terms by prefix.
+func ftsMatch(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, t := range terms {
+		quoted[i] = `"` + t + `"*`
+	}
+	return strings.Join(quoted, " OR ")
+}
+
+// scanScore counts the terms found in a behavior's text, counting a match
+// in the name twice.
+func scanScore(node Node, terms []string) float64 {
+	name := strings.ToLower(utils.GetString(node.Content, "name", ""))
+	var body strings.Builder
+	if content, ok := node.Content["content"].(map[string]interface{}); ok {
+		for _, key := range []string{"canonical", "expanded", "summary"} {
+			body.WriteString(strings.ToLower(utils.GetString(content, key, "")))
+			body.WriteByte(' ')
+		}
+		for _, tag := range utils.GetStringSlice(content, "tags") {
+			body.WriteString(strings.ToLower(tag))
+			body.WriteByte(' ')
+		}
+	}
+	text := body.String()
+
+	var score float64
+	for _, t := range terms {
+		if strings.Contains(name, t) {
+			score += 2
+		} else if strings.Contains(text, t) {
+			score++
+		}
+	}
+	return score
+}
+
+// rankHits sorts hits by score, most relevant first with ties broken by ID,
+// and keeps the first limit. On ID conflicts the earlier hit wins.
+func rankHits(hits []SearchHit, limit int) []SearchHit {
+	seen := make(map[string]bool, len(hits))
+	ranked := make([]SearchHit, 0, len(hits))
+	for _, h := range hits {
+		if !seen[h.Node.ID] {
+			seen[h.Node.ID] = true
+			ranked = append(ranked, h)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Node.ID < ranked[j].Node.ID
+	})
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
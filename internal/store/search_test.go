@@ -0,0 +1,161 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+// searchNode is a behavior with the given name, canonical content, and tags.
+func searchNode(id, name, canonical string, tags ...string) Node {
+	node := namedNode(id, name, NodeKindBehavior)
+	content := map[string]interface{}{"canonical": canonical}
+	if len(tags) > 0 {
+		content["tags"] = tags
+	}
+	node.Content["content"] = content
+	return node
+}
+
+func hitIDs(hits []SearchHit) []string {
+	ids := make([]string, len(hits))
+	for i, h := range hits {
+		ids[i] = h.Node.ID
+	}
+	return ids
+}
+
+func TestSearchBehaviors(t *testing.T) {
+	ctx := context.Background()
+	stores := map[string]func(t *testing.T) GraphStore{
+		"memory": func(t *testing.T) GraphStore { return NewInMemoryGraphStore() },
+		"sqlite": func(t *testing.T) GraphStore {
+			s, err := NewSQLiteGraphStore(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { s.Close() })
+			return s
+		},
+		"multi": func(t *testing.T) GraphStore { return newTestMultiStore(t) },
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			s := newStore(t)
+			mustAddNode(t, s, ctx, searchNode("tests", "run-tests", "Run the tests before pushing"))
+			mustAddNode(t, s, ctx, searchNode("errors", "wrap-errors", "Wrap errors with context", "go"))
+			mustAddNode(t, s, ctx, searchNode("lint", "lint-go", "Lint with golangci before pushing", "go"))
+			forgotten := searchNode("old", "old-tests", "Skip the tests")
+			forgotten.Kind = NodeKindForgotten
+			mustAddNode(t, s, ctx, forgotten)
+
+			hits, err := SearchBehaviors(ctx, s, "test", 0)
+			if err != nil {
+				t.Fatalf("SearchBehaviors() error = %v", err)
+			}
+			if got := hitIDs(hits); len(got) != 1 || got[0] != "tests" {
+				t.Errorf("test hits = %v, want only tests", got)
+			}
+
+			hits, err = SearchBehaviors(ctx, s, "pushing; errors!", 0)
+			if err != nil {
+				t.Fatalf("SearchBehaviors() error = %v", err)
+			}
+			if got := hitIDs(hits); len(got) != 3 || got[0] != "errors" {
+				t.Errorf("pushing/errors hits = %v, want errors first of three", got)
+			}
+			for i := 1; i < len(hits); i++ {
+				if hits[i].Score > hits[i-1].Score {
+					t.Errorf("hits not ranked by score: %v", hits)
+				}
+			}
+
+			hits, err = SearchBehaviors(ctx, s, "go", 1)
+			if err != nil {
+				t.Fatalf("SearchBehaviors() error = %v", err)
+			}
+			if len(hits) != 1 {
+				t.Errorf("limited hits = %v, want 1", hitIDs(hits))
+			}
+
+			hits, err = SearchBehaviors(ctx, s, `"*" ( -`, 0)
+			if err != nil || len(hits) != 0 {
+				t.Errorf("punctuation search = %v, %v; want no hits", hitIDs(hits), err)
+			}
+		})
+	}
+}
+
+func TestSQLiteSearchBehaviors_FollowsWrites(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewSQLiteGraphStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	mustAddNode(t, s, ctx, searchNode("b1", "format", "Run gofmt on save"))
+	search := func(query string) []string {
+		t.Helper()
+		hits, err := s.SearchBehaviors(ctx, query, 0)
+		if err != nil {
+			t.Fatalf("SearchBehaviors(%q) error = %v", query, err)
+		}
+		return hitIDs(hits)
+	}
+
+	// AddNode replaces a behavior with the same ID
+	mustAddNode(t, s, ctx, searchNode("b1", "format", "Run goimports on save"))
+	if got := search("gofmt"); len(got) != 0 {
+		t.Errorf("gofmt after replace = %v, want none", got)
+	}
+	if got := search("goimports save"); len(got) != 1 {
+		t.Errorf("goimports after replace = %v, want b1 once", got)
+	}
+
+	if err := s.UpdateNode(ctx, searchNode("b1", "format", "Format with gofumpt")); err != nil {
+		t.Fatalf("UpdateNode() error = %v", err)
+	}
+	if got := search("goimports"); len(got) != 0 {
+		t.Errorf("goimports after update = %v, want none", got)
+	}
+	if got := search("gofumpt"); len(got) != 1 {
+		t.Errorf("gofumpt after update = %v, want b1", got)
+	}
+
+	if err := s.DeleteNode(ctx, "b1"); err != nil {
+		t.Fatalf("DeleteNode() error = %v", err)
+	}
+	if got := search("format"); len(got) != 0 {
+		t.Errorf("format after delete = %v, want none", got)
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO behaviors_fts (behaviors_fts, rank) VALUES ('integrity-check', 1)`); err != nil {
+		t.Errorf("behaviors_fts integrity check: %v", err)
+	}
+}
+
+func TestMultiGraphStore_SearchBehaviorsPrecedence(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMultiStore(t)
+	mustAddNode(t, m.localStore, ctx, searchNode("a", "local", "Prefer table tests"))
+	mustAddNode(t, m.globalStore, ctx, searchNode("a", "global", "Prefer table tests everywhere"))
+	mustAddNode(t, m.globalStore, ctx, searchNode("b", "other", "Name tests after behavior"))
+
+	hits, err := m.SearchBehaviors(ctx, "tests", 0)
+	if err != nil {
+		t.Fatalf("SearchBehaviors() error = %v", err)
+	}
+	if len(hits) != 2 {
+		t.Fatalf("hits = %v, want a and b", hitIDs(hits))
+	}
+	for _, h := range hits {
+		if h.Node.ID == "a" && (h.Node.Origin != OriginLocal || h.Node.Content["name"] != "local") {
+			t.Errorf("a = %v from %s, want the local node", h.Node.Content["name"], h.Node.Origin)
+		}
+		if h.Node.ID == "b" && h.Node.Origin != OriginGlobal {
+			t.Errorf("b origin = %s, want global", h.Node.Origin)
+		}
+	}
+}
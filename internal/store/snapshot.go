@@ -0,0 +1,90 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LoadJSONLSnapshot reads the graph exported under floopDir (nodes.jsonl,
+// any pending nodes.log.jsonl entries, and edges.jsonl) into an in-memory
+// store. Unlike opening a SQLiteGraphStore it never writes: the database is
+// not created or imported and nothing is synced back, so it is safe on a
+// read-only checkout. Missing files load as an empty graph.
+func LoadJSONLSnapshot(floopDir string) (*InMemoryGraphStore, error) {
+	// Records are stored as exported, bypassing the duplicate-content and
+	// edge checks of AddNode and AddEdge: the snapshot mirrors the files.
+	s := NewInMemoryGraphStore()
+
+	nodesFile := filepath.Join(floopDir, "nodes.jsonl")
+	if err := readJSONLFile(nodesFile, func(line []byte) error {
+		var node Node
+		if err := json.Unmarshal(line, &node); err != nil {
+			return err
+		}
+		s.nodes[node.ID] = node
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", nodesFile, err)
+	}
+
+	// Apply the append log on top, as compaction would
+	entries, err := readNodeLog(filepath.Join(floopDir, "nodes.log.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		switch entry.Op {
+		case logOpUpsert:
+			if entry.Node != nil {
+				s.nodes[entry.Node.ID] = *entry.Node
+			}
+		case logOpDelete:
+			delete(s.nodes, entry.ID)
+		}
+	}
+
+	edgesFile := filepath.Join(floopDir, "edges.jsonl")
+	if err := readJSONLFile(edgesFile, func(line []byte) error {
+		var edge Edge
+		if err := json.Unmarshal(line, &edge); err != nil {
+			return err
+		}
+		s.edges = append(s.edges, edge)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", edgesFile, err)
+	}
+
+	return s, nil
+}
+
+// readJSONLFile calls fn for each non-empty line of path, reporting the line
+// number of the first failure. A missing file has no lines.
+func readJSONLFile(path string, fn func(line []byte) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024) // 1MB max line length
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return fmt.Errorf("line %d: %w", lineNum, err)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadJSONLSnapshot(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	s, err := NewSQLiteGraphStore(root)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	for _, id := range []string{"b-1", "b-2"} {
+		mustAddNode(t, s, ctx, Node{
+			ID:   id,
+			Kind: NodeKindBehavior,
+			Content: map[string]interface{}{
+				"name":    id,
+				"kind":    "directive",
+				"content": map[string]interface{}{"canonical": "Behavior " + id},
+			},
+		})
+	}
+	mustAddEdge(t, s, ctx, Edge{Source: "b-1", Target: "b-2", Kind: EdgeKindRequires, Weight: 1, CreatedAt: time.Now()})
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Only the exported files, as in a fresh checkout
+	checkout := filepath.Join(t.TempDir(), ".floop")
+	if err := os.MkdirAll(checkout, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"nodes.jsonl", "edges.jsonl"} {
+		data, err := os.ReadFile(filepath.Join(root, ".floop", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(checkout, name), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snap, err := LoadJSONLSnapshot(checkout)
+	if err != nil {
+		t.Fatalf("LoadJSONLSnapshot() error = %v", err)
+	}
+	nodes, _ := snap.QueryNodes(ctx, map[string]interface{}{"kind": string(NodeKindBehavior)})
+	if len(nodes) != 2 {
+		t.Errorf("loaded %d behaviors, want 2", len(nodes))
+	}
+	edges, _ := snap.GetEdges(ctx, "b-1", DirectionOutbound, EdgeKindRequires)
+	if len(edges) != 1 || edges[0].Target != "b-2" {
+		t.Errorf("edges = %+v, want b-1 requires b-2", edges)
+	}
+	if _, err := os.Stat(filepath.Join(checkout, "floop.db")); !os.IsNotExist(err) {
+		t.Errorf("snapshot created a database: %v", err)
+	}
+}
+
+func TestLoadJSONLSnapshot_AppendLog(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("nodes.jsonl", `{"id":"a","kind":"behavior","content":{"name":"a"}}`+"\n"+
+		`{"id":"b","kind":"behavior","content":{"name":"b"}}`+"\n")
+	write("nodes.log.jsonl", `{"op":"delete","id":"a"}`+"\n"+
+		`{"op":"upsert","id":"b","node":{"id":"b","kind":"behavior","content":{"name":"b2"}}}`+"\n"+
+		`{"op":"upsert","id":"c","node":{"id":"c","kind":"behavior","content":{"name":"c"}}}`+"\n")
+
+	snap, err := LoadJSONLSnapshot(dir)
+	if err != nil {
+		t.Fatalf("LoadJSONLSnapshot() error = %v", err)
+	}
+	ctx := context.Background()
+	if n, _ := snap.GetNode(ctx, "a"); n != nil {
+		t.Error("deleted node a is still present")
+	}
+	if n, _ := snap.GetNode(ctx, "b"); n == nil || n.Content["name"] != "b2" {
+		t.Errorf("node b = %+v, want the logged upsert", n)
+	}
+	if n, _ := snap.GetNode(ctx, "c"); n == nil {
+		t.Error("logged node c is missing")
+	}
+}
+
+func TestLoadJSONLSnapshot_Errors(t *testing.T) {
+	empty, err := LoadJSONLSnapshot(filepath.Join(t.TempDir(), "missing"))
+	if err != nil {
+		t.Fatalf("LoadJSONLSnapshot() of a missing dir error = %v", err)
+	}
+	if nodes, _ := empty.QueryNodes(context.Background(), nil); len(nodes) != 0 {
+		t.Errorf("missing dir loaded %d nodes, want 0", len(nodes))
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "nodes.jsonl"), []byte("{\"id\":\"a\"}\nnot json\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadJSONLSnapshot(dir); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("LoadJSONLSnapshot() error = %v, want a line 2 failure", err)
+	}
+}
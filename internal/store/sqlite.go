@@ -13,6 +13,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -20,6 +21,7 @@ import (
 
 	"github.com/nvandessel/floop/internal/constants"
 	"github.com/nvandessel/floop/internal/project"
+	"github.com/nvandessel/floop/internal/tagging"
 	"github.com/nvandessel/floop/internal/utils"
 	_ "modernc.org/sqlite" // SQLite driver
 )
@@ -34,6 +36,19 @@ type SQLiteGraphStore struct {
 	nodesFile string
 	edgesFile string
 	version   uint64
+
+	// Append log state (see EnableAppendLog)
+	nodesLogFile string
+	appendLog    bool
+	logCompactAt int
+	logEntries   int
+
+	// Changelog state (see SetChangelogEnabled). changedBefore holds each
+	// changed node as it was before its first change since the last sync
+	// (nil for new nodes); changeOrder keeps the order of first changes.
+	changelogFile string
+	changedBefore map[string]*Node
+	changeOrder   []string
 }
 
 // DB returns the underlying *sql.DB for direct SQL access (e.g., persistRun).
@@ -44,7 +59,14 @@ func (s *SQLiteGraphStore) DB() *sql.DB {
 // NewSQLiteGraphStore creates a new SQLiteGraphStore rooted at projectRoot.
 // It creates the database at .floop/floop.db and auto-imports existing JSONL files.
 func NewSQLiteGraphStore(projectRoot string) (*SQLiteGraphStore, error) {
-	floopDir := filepath.Join(projectRoot, ".floop")
+	return openSQLiteGraphStore(projectRoot, filepath.Join(projectRoot, ".floop"))
+}
+
+// openSQLiteGraphStore opens the store kept in floopDir. projectRoot
+// resolves the project ID recorded by schema migrations.
+func openSQLiteGraphStore(projectRoot, floopDir string) (*SQLiteGraphStore, error) {
+	start := time.Now()
+	timing := OpenTiming{Dir: floopDir}
 
 	// Ensure .floop directory exists
 	if err := os.MkdirAll(floopDir, 0700); err != nil {
@@ -83,79 +105,120 @@ func NewSQLiteGraphStore(projectRoot string) (*SQLiteGraphStore, error) {
 	}
 
 	// Initialize schema with project context
-	if err := initSchemaWithProject(ctx, db, projectID); err != nil {
+	if err := initSchemaTimed(ctx, db, projectID, &timing); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("failed to initialize schema: %w", err)
 	}
+	timing.Schema = time.Since(start)
 
 	s := &SQLiteGraphStore{
-		db:        db,
-		floopDir:  floopDir,
-		dbPath:    dbPath,
-		nodesFile: nodesFile,
-		edgesFile: edgesFile,
+		db:           db,
+		floopDir:     floopDir,
+		dbPath:       dbPath,
+		nodesFile:    nodesFile,
+		edgesFile:    edgesFile,
+		nodesLogFile: filepath.Join(floopDir, "nodes.log.jsonl"),
+	}
+	if changelogEnabled.Load() {
+		s.changelogFile = filepath.Join(floopDir, ChangelogFile)
 	}
 
-	// Auto-import existing JSONL if database is empty or JSONL is newer
-	if err := s.autoImport(ctx); err != nil {
+	// Auto-import existing JSONL if database is empty or JSONL has changed
+	importStart := time.Now()
+	imported, err := s.autoImport(ctx)
+	if err != nil {
 		db.Close()
 		return nil, fmt.Errorf("failed to auto-import JSONL: %w", err)
 	}
+	timing.Import = time.Since(importStart)
+	timing.Imported = imported
+	timing.Total = time.Since(start)
+	reportOpenTiming(timing)
 
 	return s, nil
 }
 
-// autoImport imports existing JSONL files if the database is empty or JSONL is newer.
-func (s *SQLiteGraphStore) autoImport(ctx context.Context) error {
+// autoImport imports existing JSONL files if the database is empty or the
+// JSONL files changed since the last import or export, as detected by their
+// recorded fingerprint. It reports whether an import ran.
+func (s *SQLiteGraphStore) autoImport(ctx context.Context) (bool, error) {
 	// Check if database has any behaviors
 	var count int
 	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM behaviors`).Scan(&count); err != nil {
-		return fmt.Errorf("failed to count behaviors: %w", err)
+		return false, fmt.Errorf("failed to count behaviors: %w", err)
 	}
 
-	// If database already has data, check if we need to import newer JSONL
-	if count > 0 {
-		// Get DB modification time
-		dbInfo, err := os.Stat(s.dbPath)
-		if err != nil {
-			return fmt.Errorf("failed to stat database: %w", err)
-		}
+	fingerprint, err := s.jsonlFingerprint()
+	if err != nil {
+		return false, err
+	}
+
+	// Skip the import when the JSONL is exactly as this database last left it
+	stored := s.storedJSONLFingerprint(ctx)
+	if stored != "" && stored == fingerprint {
+		return false, nil
+	}
 
-		// Check if nodes.jsonl is newer
+	// If database already has data, check if we need to import the JSONL
+	if count > 0 {
+		// Check if nodes.jsonl (or its append log) exists
 		nodesInfo, err := os.Stat(s.nodesFile)
-		if err != nil {
-			if os.IsNotExist(err) {
-				return nil // No JSONL file, nothing to import
-			}
-			return fmt.Errorf("failed to stat nodes.jsonl: %w", err)
+		if err != nil && !os.IsNotExist(err) {
+			return false, fmt.Errorf("failed to stat nodes.jsonl: %w", err)
+		}
+		logInfo, logErr := os.Stat(s.nodesLogFile)
+		if logErr != nil && !os.IsNotExist(logErr) {
+			return false, fmt.Errorf("failed to stat nodes.log.jsonl: %w", logErr)
+		}
+		if nodesInfo == nil && logInfo == nil {
+			return false, nil // No JSONL file, nothing to import
 		}
 
-		// If JSONL is older than DB, no need to import
-		if nodesInfo.ModTime().Before(dbInfo.ModTime()) {
-			return nil
+		// Without a recorded fingerprint (older databases), fall back to
+		// importing only if the JSONL is newer than the DB
+		if stored == "" {
+			dbInfo, err := os.Stat(s.dbPath)
+			if err != nil {
+				return false, fmt.Errorf("failed to stat database: %w", err)
+			}
+			newest := time.Time{}
+			if nodesInfo != nil {
+				newest = nodesInfo.ModTime()
+			}
+			if logInfo != nil && logInfo.ModTime().After(newest) {
+				newest = logInfo.ModTime()
+			}
+			if newest.Before(dbInfo.ModTime()) {
+				return false, s.recordJSONLFingerprint(ctx)
+			}
 		}
 	}
 
 	// Import nodes.jsonl if it exists
 	if _, err := os.Stat(s.nodesFile); err == nil {
 		if err := s.ImportNodesFromJSONL(ctx, s.nodesFile); err != nil {
-			return fmt.Errorf("failed to import nodes: %w", err)
+			return false, fmt.Errorf("failed to import nodes: %w", err)
 		}
 	}
 
+	// Replay the append log on top of nodes.jsonl
+	if err := s.replayNodeLog(ctx); err != nil {
+		return false, fmt.Errorf("failed to replay append log: %w", err)
+	}
+
 	// Import edges.jsonl if it exists
 	if _, err := os.Stat(s.edgesFile); err == nil {
 		if err := s.ImportEdgesFromJSONL(ctx, s.edgesFile); err != nil {
-			return fmt.Errorf("failed to import edges: %w", err)
+			return false, fmt.Errorf("failed to import edges: %w", err)
 		}
 	}
 
 	// Clear dirty flags since we just imported
 	if _, err := s.db.ExecContext(ctx, `DELETE FROM dirty_behaviors`); err != nil {
-		return fmt.Errorf("failed to clear dirty flags: %w", err)
+		return false, fmt.Errorf("failed to clear dirty flags: %w", err)
 	}
 
-	return nil
+	return true, s.recordJSONLFingerprint(ctx)
 }
 
 // AddNode adds a node to the store.
@@ -169,6 +232,7 @@ func (s *SQLiteGraphStore) AddNode(ctx context.Context, node Node) (string, erro
 
 	// Use addBehavior for all behavior-related kinds
 	if isBehaviorKind(node.Kind) {
+		s.noteChange(ctx, node.ID)
 		id, err := s.addBehavior(ctx, node)
 		if err != nil {
 			return "", err
@@ -193,7 +257,9 @@ func isBehaviorKind(kind NodeKind) bool {
 	case NodeKindBehavior,
 		NodeKindForgotten,
 		NodeKindDeprecated,
-		NodeKindMerged:
+		NodeKindMerged,
+		NodeKindRetired,
+		NodeKindQuarantined:
 		return true
 	default:
 		return false
@@ -302,7 +368,8 @@ func (s *SQLiteGraphStore) addBehaviorWith(ctx context.Context, q dbQuerier, nod
 	}
 	sourceType := utils.GetString(provenance, "source_type", "")
 	correctionID := utils.GetString(provenance, "correction_id", "")
-	createdAtStr := utils.GetString(provenance, "created_at", "")
+	createdAtStr := normalizeTimestamp(utils.GetString(provenance, "created_at", ""))
+	sourceAgent := utils.GetString(provenance, "source_agent", "")
 
 	// Relationships
 	requiresRaw, _ := content["requires"]
@@ -334,7 +401,7 @@ func (s *SQLiteGraphStore) addBehaviorWith(ctx context.Context, q dbQuerier, nod
 
 	// Metadata
 	confidence := utils.GetFloat64(metadata, "confidence", 0.6)
-	priority := utils.GetFloat64(metadata, "priority", 0)
+	priority := utils.GetInt(metadata, "priority", 0)
 	scope := utils.GetString(metadata, "scope", string(constants.ScopeLocal))
 
 	// Collect extra metadata fields (not confidence, priority, scope, stats)
@@ -350,6 +417,7 @@ func (s *SQLiteGraphStore) addBehaviorWith(ctx context.Context, q dbQuerier, nod
 			extraMetadata[k] = v
 		}
 	}
+	normalizeMetadataTimestamps(extraMetadata)
 	var extraMetadataJSON []byte
 	if len(extraMetadata) > 0 {
 		var err error
@@ -376,7 +444,7 @@ func (s *SQLiteGraphStore) addBehaviorWith(ctx context.Context, q dbQuerier, nod
 	}
 	// err == sql.ErrNoRows means no duplicate found, proceed with insert
 
-	now := time.Now().Format(time.RFC3339)
+	now := formatTimestamp(time.Now())
 
 	// Insert behavior (OR REPLACE handles same-ID updates)
 	_, err = q.ExecContext(ctx, `
@@ -384,15 +452,17 @@ func (s *SQLiteGraphStore) addBehaviorWith(ctx context.Context, q dbQuerier, nod
 			id, name, kind, behavior_type,
 			content_canonical, content_summary, content_structured, content_tags,
 			provenance_source_type, provenance_correction_id, provenance_created_at,
+			provenance_source_agent,
 			requires, overrides, conflicts,
 			confidence, priority, scope, metadata_extra,
 			created_at, updated_at, content_hash
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, node.ID, name, kind, behaviorType,
 		canonical, nullString(summary), nullBytes(structuredJSON), nullBytes(tagsJSON),
 		nullString(sourceType), nullString(correctionID), nullString(createdAtStr),
+		nullString(sourceAgent),
 		nullBytes(requiresJSON), nullBytes(overridesJSON), nullBytes(conflictsJSON),
-		confidence, int(priority), scope, nullBytes(extraMetadataJSON),
+		confidence, priority, scope, nullBytes(extraMetadataJSON),
 		now, now, contentHash)
 	if err != nil {
 		return "", fmt.Errorf("failed to insert behavior: %w", err)
@@ -414,6 +484,11 @@ func (s *SQLiteGraphStore) addBehaviorWith(ctx context.Context, q dbQuerier, nod
 		}
 	}
 
+	// Index tags
+	if err := insertTags(ctx, q, node.ID, utils.GetStringSlice(behaviorContent, "tags")); err != nil {
+		return "", err
+	}
+
 	// Insert stats - handle both map and struct types
 	var stats map[string]interface{}
 	if s, ok := metadata["stats"].(map[string]interface{}); ok {
@@ -431,19 +506,20 @@ func (s *SQLiteGraphStore) addBehaviorWith(ctx context.Context, q dbQuerier, nod
 	if stats == nil {
 		stats = make(map[string]interface{})
 	}
-	timesActivated := utils.GetFloat64(stats, "times_activated", 0)
-	timesFollowed := utils.GetFloat64(stats, "times_followed", 0)
-	timesOverridden := utils.GetFloat64(stats, "times_overridden", 0)
-	timesConfirmed := utils.GetFloat64(stats, "times_confirmed", 0)
-	lastActivated := utils.GetString(stats, "last_activated", "")
-	lastConfirmed := utils.GetString(stats, "last_confirmed", "")
+	// GetInt accepts both the ints read from the database and JSON's float64s
+	timesActivated := utils.GetInt(stats, "times_activated", 0)
+	timesFollowed := utils.GetInt(stats, "times_followed", 0)
+	timesOverridden := utils.GetInt(stats, "times_overridden", 0)
+	timesConfirmed := utils.GetInt(stats, "times_confirmed", 0)
+	lastActivated := normalizeTimestamp(utils.GetString(stats, "last_activated", ""))
+	lastConfirmed := normalizeTimestamp(utils.GetString(stats, "last_confirmed", ""))
 
 	_, err = q.ExecContext(ctx, `
 		INSERT OR REPLACE INTO behavior_stats (
 			behavior_id, times_activated, times_followed, times_overridden, times_confirmed,
 			last_activated, last_confirmed
 		) VALUES (?, ?, ?, ?, ?, ?, ?)
-	`, node.ID, int(timesActivated), int(timesFollowed), int(timesOverridden), int(timesConfirmed),
+	`, node.ID, timesActivated, timesFollowed, timesOverridden, timesConfirmed,
 		nullString(lastActivated), nullString(lastConfirmed))
 	if err != nil {
 		return "", fmt.Errorf("failed to insert stats: %w", err)
@@ -452,6 +528,20 @@ func (s *SQLiteGraphStore) addBehaviorWith(ctx context.Context, q dbQuerier, nod
 	return node.ID, nil
 }
 
+// insertTags adds a behavior's tags to the tag index.
+func insertTags(ctx context.Context, q dbQuerier, id string, tags []string) error {
+	for _, tag := range tags {
+		if tag == "" {
+			continue
+		}
+		if _, err := q.ExecContext(ctx,
+			`INSERT OR IGNORE INTO behavior_tags (behavior_id, tag) VALUES (?, ?)`, id, tag); err != nil {
+			return fmt.Errorf("failed to insert tag: %w", err)
+		}
+	}
+	return nil
+}
+
 // addGenericNode adds a non-behavior node to the behaviors table using s.db.
 func (s *SQLiteGraphStore) addGenericNode(ctx context.Context, node Node) (string, error) {
 	return s.addGenericNodeWith(ctx, s.db, node)
@@ -464,7 +554,7 @@ func (s *SQLiteGraphStore) addGenericNodeWith(ctx context.Context, q dbQuerier,
 		return "", fmt.Errorf("failed to marshal content: %w", err)
 	}
 
-	now := time.Now().Format(time.RFC3339)
+	now := formatTimestamp(time.Now())
 
 	_, err = q.ExecContext(ctx, `
 		INSERT OR REPLACE INTO behaviors (
@@ -490,6 +580,8 @@ func (s *SQLiteGraphStore) UpdateNode(ctx context.Context, node Node) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.noteChange(ctx, node.ID)
+
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
@@ -506,10 +598,13 @@ func (s *SQLiteGraphStore) UpdateNode(ctx context.Context, node Node) error {
 		return fmt.Errorf("failed to check node existence: %w", err)
 	}
 
-	// Delete existing when conditions (they'll be re-inserted)
+	// Delete existing when conditions and tags (they'll be re-inserted)
 	if _, err := tx.ExecContext(ctx, `DELETE FROM behavior_when WHERE behavior_id = ?`, node.ID); err != nil {
 		return fmt.Errorf("failed to delete when conditions: %w", err)
 	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM behavior_tags WHERE behavior_id = ?`, node.ID); err != nil {
+		return fmt.Errorf("failed to delete tags: %w", err)
+	}
 
 	// Re-add the node using the transaction
 	if isBehaviorKind(node.Kind) {
@@ -536,39 +631,33 @@ func (s *SQLiteGraphStore) GetNode(ctx context.Context, id string) (*Node, error
 	return s.getNodeUnlocked(ctx, id)
 }
 
-// getNodeUnlocked retrieves a node without locking (caller must hold lock).
-func (s *SQLiteGraphStore) getNodeUnlocked(ctx context.Context, id string) (*Node, error) {
-	var (
-		name, kind                                    string
-		behaviorType                                  sql.NullString
-		canonical, summary                            sql.NullString
-		structuredJSON, tagsJSON                      sql.NullString
-		sourceType, correctionID, provenanceCreatedAt sql.NullString
-		requiresJSON, overridesJSON, conflictsJSON    sql.NullString
-		confidence                                    float64
-		priority                                      int
-		scope                                         sql.NullString
-		metadataExtraJSON                             sql.NullString
-		createdAt, updatedAt                          string
-	)
+// GetNodeByName retrieves a node by name using the name index, preferring an
+// active behavior when several share the name. Returns nil if not found.
+func (s *SQLiteGraphStore) GetNodeByName(ctx context.Context, name string) (*Node, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
+	var id string
 	err := s.db.QueryRowContext(ctx, `
-		SELECT
-			name, kind, behavior_type,
-			content_canonical, content_summary, content_structured, content_tags,
-			provenance_source_type, provenance_correction_id, provenance_created_at,
-			requires, overrides, conflicts,
-			confidence, priority, scope, metadata_extra,
-			created_at, updated_at
-		FROM behaviors WHERE id = ?
-	`, id).Scan(
-		&name, &kind, &behaviorType,
-		&canonical, &summary, &structuredJSON, &tagsJSON,
-		&sourceType, &correctionID, &provenanceCreatedAt,
-		&requiresJSON, &overridesJSON, &conflictsJSON,
-		&confidence, &priority, &scope, &metadataExtraJSON,
-		&createdAt, &updatedAt,
-	)
+		SELECT id FROM behaviors WHERE name = ?
+		ORDER BY kind = ? DESC, id LIMIT 1
+	`, name, string(NodeKindBehavior)).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up node by name: %w", err)
+	}
+	return s.getNodeUnlocked(ctx, id)
+}
+
+// getNodeUnlocked retrieves a node without locking (caller must hold lock).
+func (s *SQLiteGraphStore) getNodeUnlocked(ctx context.Context, id string) (*Node, error) {
+	var row nodeRow
+	err := s.db.QueryRowContext(ctx, `SELECT `+nodeColumns+`
+		FROM behaviors b LEFT JOIN behavior_stats st ON st.behavior_id = b.id
+		WHERE b.id = ?
+	`, id).Scan(row.dest()...)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -599,42 +688,85 @@ func (s *SQLiteGraphStore) getNodeUnlocked(ctx context.Context, id string) (*Nod
 		when[field] = deserializedValue
 	}
 
-	// Query stats
-	var timesActivated, timesFollowed, timesOverridden, timesConfirmed int
-	var lastActivated, lastConfirmed sql.NullString
-	err = s.db.QueryRowContext(ctx, `
-		SELECT times_activated, times_followed, times_overridden, times_confirmed,
-		       last_activated, last_confirmed
-		FROM behavior_stats WHERE behavior_id = ?
-	`, id).Scan(&timesActivated, &timesFollowed, &timesOverridden, &timesConfirmed,
-		&lastActivated, &lastConfirmed)
-	if err != nil && err != sql.ErrNoRows {
-		return nil, fmt.Errorf("failed to get stats: %w", err)
+	return row.node(when)
+}
+
+// nodeColumns are the columns of a behavior and its stats a nodeRow scans,
+// selected from behaviors b LEFT JOIN behavior_stats st.
+const nodeColumns = `
+	b.id, b.name, b.kind, b.behavior_type,
+	b.content_canonical, b.content_summary, b.content_structured, b.content_tags,
+	b.provenance_source_type, b.provenance_correction_id, b.provenance_created_at,
+	b.provenance_source_agent,
+	b.requires, b.overrides, b.conflicts,
+	b.confidence, b.priority, b.scope, b.metadata_extra,
+	b.created_at, b.updated_at,
+	st.times_activated, st.times_followed, st.times_overridden, st.times_confirmed,
+	st.last_activated, st.last_confirmed`
+
+// nodeRow is one behavior row with its stats, as selected by nodeColumns.
+type nodeRow struct {
+	id, name, kind                                string
+	behaviorType                                  sql.NullString
+	canonical, summary                            sql.NullString
+	structuredJSON, tagsJSON                      sql.NullString
+	sourceType, correctionID, provenanceCreatedAt sql.NullString
+	sourceAgent                                   sql.NullString
+	requiresJSON, overridesJSON, conflictsJSON    sql.NullString
+	confidence                                    float64
+	priority                                      int
+	scope                                         sql.NullString
+	metadataExtraJSON                             sql.NullString
+	createdAt, updatedAt                          string
+
+	// Stats are NULL for a behavior without a behavior_stats row
+	timesActivated, timesFollowed   sql.NullInt64
+	timesOverridden, timesConfirmed sql.NullInt64
+	lastActivated, lastConfirmed    sql.NullString
+}
+
+// dest returns the scan destinations of the row, in nodeColumns order.
+func (r *nodeRow) dest() []interface{} {
+	return []interface{}{
+		&r.id, &r.name, &r.kind, &r.behaviorType,
+		&r.canonical, &r.summary, &r.structuredJSON, &r.tagsJSON,
+		&r.sourceType, &r.correctionID, &r.provenanceCreatedAt,
+		&r.sourceAgent,
+		&r.requiresJSON, &r.overridesJSON, &r.conflictsJSON,
+		&r.confidence, &r.priority, &r.scope, &r.metadataExtraJSON,
+		&r.createdAt, &r.updatedAt,
+		&r.timesActivated, &r.timesFollowed, &r.timesOverridden, &r.timesConfirmed,
+		&r.lastActivated, &r.lastConfirmed,
 	}
+}
+
+// node builds the Node of the row with the given when conditions.
+func (r *nodeRow) node(when map[string]interface{}) (*Node, error) {
+	id := r.id
 
 	// Build content map
 	content := make(map[string]interface{})
-	content["name"] = name
+	content["name"] = r.name
 	// Use behavior_type for content["kind"] (directive, constraint, etc.)
-	if behaviorType.Valid {
-		content["kind"] = behaviorType.String
+	if r.behaviorType.Valid {
+		content["kind"] = r.behaviorType.String
 	}
 
 	behaviorContent := make(map[string]interface{})
-	behaviorContent["canonical"] = canonical.String
-	if summary.Valid {
-		behaviorContent["summary"] = summary.String
+	behaviorContent["canonical"] = r.canonical.String
+	if r.summary.Valid {
+		behaviorContent["summary"] = r.summary.String
 	}
-	if structuredJSON.Valid {
+	if r.structuredJSON.Valid {
 		var structured interface{}
-		if err := json.Unmarshal([]byte(structuredJSON.String), &structured); err != nil {
+		if err := json.Unmarshal([]byte(r.structuredJSON.String), &structured); err != nil {
 			return nil, fmt.Errorf("unmarshal structured content for %s: %w", id, err)
 		}
 		behaviorContent["structured"] = structured
 	}
-	if tagsJSON.Valid {
+	if r.tagsJSON.Valid {
 		var tags interface{}
-		if err := json.Unmarshal([]byte(tagsJSON.String), &tags); err != nil {
+		if err := json.Unmarshal([]byte(r.tagsJSON.String), &tags); err != nil {
 			return nil, fmt.Errorf("unmarshal tags for %s: %w", id, err)
 		}
 		behaviorContent["tags"] = tags
@@ -643,39 +775,42 @@ func (s *SQLiteGraphStore) getNodeUnlocked(ctx context.Context, id string) (*Nod
 
 	// Provenance
 	provenance := make(map[string]interface{})
-	if sourceType.Valid {
-		provenance["source_type"] = sourceType.String
+	if r.sourceType.Valid {
+		provenance["source_type"] = r.sourceType.String
 	}
-	if correctionID.Valid {
-		provenance["correction_id"] = correctionID.String
+	if r.correctionID.Valid {
+		provenance["correction_id"] = r.correctionID.String
 	}
-	if provenanceCreatedAt.Valid {
-		if t, err := time.Parse(time.RFC3339, provenanceCreatedAt.String); err == nil {
+	if r.provenanceCreatedAt.Valid {
+		if t, err := time.Parse(time.RFC3339, r.provenanceCreatedAt.String); err == nil {
 			provenance["created_at"] = t
 		} else {
-			provenance["created_at"] = provenanceCreatedAt.String
+			provenance["created_at"] = r.provenanceCreatedAt.String
 		}
 	}
+	if r.sourceAgent.Valid {
+		provenance["source_agent"] = r.sourceAgent.String
+	}
 	content["provenance"] = provenance
 
 	// Relationships
-	if requiresJSON.Valid {
+	if r.requiresJSON.Valid {
 		var requires interface{}
-		if err := json.Unmarshal([]byte(requiresJSON.String), &requires); err != nil {
+		if err := json.Unmarshal([]byte(r.requiresJSON.String), &requires); err != nil {
 			return nil, fmt.Errorf("unmarshal requires for %s: %w", id, err)
 		}
 		content["requires"] = requires
 	}
-	if overridesJSON.Valid {
+	if r.overridesJSON.Valid {
 		var overrides interface{}
-		if err := json.Unmarshal([]byte(overridesJSON.String), &overrides); err != nil {
+		if err := json.Unmarshal([]byte(r.overridesJSON.String), &overrides); err != nil {
 			return nil, fmt.Errorf("unmarshal overrides for %s: %w", id, err)
 		}
 		content["overrides"] = overrides
 	}
-	if conflictsJSON.Valid {
+	if r.conflictsJSON.Valid {
 		var conflicts interface{}
-		if err := json.Unmarshal([]byte(conflictsJSON.String), &conflicts); err != nil {
+		if err := json.Unmarshal([]byte(r.conflictsJSON.String), &conflicts); err != nil {
 			return nil, fmt.Errorf("unmarshal conflicts for %s: %w", id, err)
 		}
 		content["conflicts"] = conflicts
@@ -688,33 +823,33 @@ func (s *SQLiteGraphStore) getNodeUnlocked(ctx context.Context, id string) (*Nod
 
 	// Build metadata map
 	metadata := make(map[string]interface{})
-	metadata["confidence"] = confidence
-	metadata["priority"] = priority
-	if scope.Valid {
-		metadata["scope"] = scope.String
+	metadata["confidence"] = r.confidence
+	metadata["priority"] = r.priority
+	if r.scope.Valid {
+		metadata["scope"] = r.scope.String
 	}
 
 	// Stats
 	stats := map[string]interface{}{
-		"times_activated":  timesActivated,
-		"times_followed":   timesFollowed,
-		"times_overridden": timesOverridden,
-		"times_confirmed":  timesConfirmed,
-		"created_at":       createdAt,
-		"updated_at":       updatedAt,
+		"times_activated":  int(r.timesActivated.Int64),
+		"times_followed":   int(r.timesFollowed.Int64),
+		"times_overridden": int(r.timesOverridden.Int64),
+		"times_confirmed":  int(r.timesConfirmed.Int64),
+		"created_at":       r.createdAt,
+		"updated_at":       r.updatedAt,
 	}
-	if lastActivated.Valid {
-		stats["last_activated"] = lastActivated.String
+	if r.lastActivated.Valid {
+		stats["last_activated"] = r.lastActivated.String
 	}
-	if lastConfirmed.Valid {
-		stats["last_confirmed"] = lastConfirmed.String
+	if r.lastConfirmed.Valid {
+		stats["last_confirmed"] = r.lastConfirmed.String
 	}
 	metadata["stats"] = stats
 
 	// Merge extra metadata fields (forget_reason, deprecation_reason, merged_into, etc.)
-	if metadataExtraJSON.Valid {
+	if r.metadataExtraJSON.Valid {
 		var extraMetadata map[string]interface{}
-		if err := json.Unmarshal([]byte(metadataExtraJSON.String), &extraMetadata); err == nil {
+		if err := json.Unmarshal([]byte(r.metadataExtraJSON.String), &extraMetadata); err == nil {
 			for k, v := range extraMetadata {
 				metadata[k] = v
 			}
@@ -725,7 +860,7 @@ func (s *SQLiteGraphStore) getNodeUnlocked(ctx context.Context, id string) (*Nod
 	// (can be "behavior", "forgotten-behavior", "merged-behavior", "correction", etc.)
 	return &Node{
 		ID:       id,
-		Kind:     NodeKind(kind),
+		Kind:     NodeKind(r.kind),
 		Content:  content,
 		Metadata: metadata,
 	}, nil
@@ -736,6 +871,8 @@ func (s *SQLiteGraphStore) DeleteNode(ctx context.Context, id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.noteChange(ctx, id)
+
 	// Delete the behavior (cascades to when and stats via foreign keys)
 	if _, err := s.db.ExecContext(ctx, `DELETE FROM behaviors WHERE id = ?`, id); err != nil {
 		return fmt.Errorf("failed to delete behavior: %w", err)
@@ -746,15 +883,46 @@ func (s *SQLiteGraphStore) DeleteNode(ctx context.Context, id string) error {
 		return fmt.Errorf("failed to delete edges: %w", err)
 	}
 
+	// Client stats have no FK cascade (see schema)
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM behavior_client_stats WHERE behavior_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete client stats: %w", err)
+	}
+
 	s.bumpVersion()
 	return nil
 }
 
-// QueryNodes returns nodes matching the predicate.
+// QueryNodes returns nodes matching the predicate, in ID order.
 func (s *SQLiteGraphStore) QueryNodes(ctx context.Context, predicate map[string]interface{}) ([]Node, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	return s.queryNodesUnlocked(ctx, predicate, "", 0)
+}
+
+// QueryNodesPage implements NodePager.
+func (s *SQLiteGraphStore) QueryNodesPage(ctx context.Context, predicate map[string]interface{}, cursor string, limit int) ([]Node, string, error) {
+	if limit <= 0 {
+		limit = DefaultNodePageSize
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// One node past the page tells whether another page follows
+	nodes, err := s.queryNodesUnlocked(ctx, predicate, cursor, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+	page, next := pageOf(nodes, limit, false)
+	return page, next, nil
+}
+
+// queryNodesUnlocked returns the nodes matching the predicate whose IDs
+// sort after the given ID, in ID order, at most limit of them unless limit
+// is zero. Nodes, stats, and when conditions are read with a single query.
+// Caller must hold lock.
+func (s *SQLiteGraphStore) queryNodesUnlocked(ctx context.Context, predicate map[string]interface{}, after string, limit int) ([]Node, error) {
 	// Build WHERE clause from predicate
 	var whereClauses []string
 	var args []interface{}
@@ -770,43 +938,87 @@ func (s *SQLiteGraphStore) QueryNodes(ctx context.Context, predicate map[string]
 		case "scope":
 			whereClauses = append(whereClauses, "scope = ?")
 			args = append(args, value)
+		case "tag":
+			// The tag or one below it: "testing/" < "testing/unit" < "testing0"
+			tag := fmt.Sprintf("%v", value)
+			whereClauses = append(whereClauses,
+				"id IN (SELECT behavior_id FROM behavior_tags WHERE tag = ? OR (tag > ? AND tag < ?))")
+			args = append(args, tag, tag+tagging.Separator, tag+"0")
 		}
 	}
+	if after != "" {
+		whereClauses = append(whereClauses, "id > ?")
+		args = append(args, after)
+	}
 
-	query := `SELECT id FROM behaviors`
+	// The page of behaviors is selected first so the limit counts
+	// behaviors, not their joined when rows
+	matched := `SELECT * FROM behaviors`
 	if len(whereClauses) > 0 {
-		query += " WHERE " + joinStrings(whereClauses, " AND ") //nolint:gosec // G202: whereClauses contains only hardcoded column filters, not user input
+		matched += " WHERE " + joinStrings(whereClauses, " AND ") //nolint:gosec // G202: whereClauses contains only hardcoded column filters, not user input
+	}
+	matched += " ORDER BY id"
+	if limit > 0 {
+		matched += " LIMIT ?"
+		args = append(args, limit)
 	}
 
+	query := `SELECT ` + nodeColumns + `, w.field, w.value, w.value_type
+		FROM (` + matched + `) b
+		LEFT JOIN behavior_stats st ON st.behavior_id = b.id
+		LEFT JOIN behavior_when w ON w.behavior_id = b.id
+		ORDER BY b.id`
+
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query nodes: %w", err)
 	}
+	defer rows.Close()
 
-	// Collect IDs first, then close rows before nested queries
-	var ids []string
-	for rows.Next() {
-		var id string
-		if err := rows.Scan(&id); err != nil {
-			rows.Close()
-			return nil, fmt.Errorf("failed to scan node ID: %w", err)
+	var (
+		nodes   []Node
+		current *nodeRow
+		when    map[string]interface{}
+	)
+	flush := func() error {
+		if current == nil {
+			return nil
 		}
-		ids = append(ids, id)
-	}
-	rows.Close()
-
-	// Now fetch each node
-	var nodes []Node
-	for _, id := range ids {
-		node, err := s.getNodeUnlocked(ctx, id)
+		node, err := current.node(when)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get node %s: %w", id, err)
+			return fmt.Errorf("failed to get node %s: %w", current.id, err)
 		}
-		if node != nil {
-			nodes = append(nodes, *node)
+		nodes = append(nodes, *node)
+		return nil
+	}
+	for rows.Next() {
+		var row nodeRow
+		var field, value, valueType sql.NullString
+		if err := rows.Scan(append(row.dest(), &field, &value, &valueType)...); err != nil {
+			return nil, fmt.Errorf("failed to scan node: %w", err)
+		}
+		// Rows of one behavior are adjacent, one per when condition
+		if current == nil || current.id != row.id {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			current = &row
+			when = make(map[string]interface{})
+		}
+		if field.Valid {
+			deserializedValue, err := deserializeWhenValue(value.String, valueType.String)
+			if err != nil {
+				return nil, fmt.Errorf("deserialize when condition %s for %s: %w", field.String, row.id, err)
+			}
+			when[field.String] = deserializedValue
 		}
 	}
-
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read nodes: %w", err)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
 	return nodes, nil
 }
 
@@ -823,6 +1035,87 @@ func (s *SQLiteGraphStore) AddEdge(ctx context.Context, edge Edge) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return s.insertEdge(ctx, edge)
+}
+
+// BatchAddEdges implements EdgeBatcher. Endpoints must be nodes of this
+// store, and the valid edges are written in one transaction.
+func (s *SQLiteGraphStore) BatchAddEdges(ctx context.Context, edges []Edge) ([]EdgeError, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rejected []EdgeError
+	valid := make([]Edge, 0, len(edges))
+	for i, edge := range edges {
+		if err := validateEdge(edge); err != nil {
+			rejected = append(rejected, EdgeError{Index: i, Edge: edge, Err: err})
+			continue
+		}
+		want := 2
+		if edge.Source == edge.Target {
+			want = 1
+		}
+		var n int
+		if err := s.db.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM behaviors WHERE id IN (?, ?)`, edge.Source, edge.Target).Scan(&n); err != nil {
+			return rejected, fmt.Errorf("failed to check edge endpoints: %w", err)
+		}
+		if n < want {
+			rejected = append(rejected, EdgeError{Index: i, Edge: edge, Err: danglingEdgeError(edge)})
+			continue
+		}
+		valid = append(valid, edge)
+	}
+	return rejected, s.insertEdgesUnlocked(ctx, valid)
+}
+
+// insertEdges implements edgeInserter.
+func (s *SQLiteGraphStore) insertEdges(ctx context.Context, edges []Edge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.insertEdgesUnlocked(ctx, edges)
+}
+
+// insertEdgesUnlocked writes edges as is in one transaction. The caller must
+// hold s.mu.
+func (s *SQLiteGraphStore) insertEdgesUnlocked(ctx context.Context, edges []Edge) error {
+	if len(edges) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin batch edge insert: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, edge := range edges {
+		if err := insertEdgeWith(ctx, tx, edge); err != nil {
+			return fmt.Errorf("%w (%s -> %s, %s)", err, edge.Source, edge.Target, edge.Kind)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit batch edge insert: %w", err)
+	}
+	s.bumpVersion()
+	return nil
+}
+
+// insertEdge writes edge as is, replacing any edge with the same source,
+// target, and kind. The caller must hold s.mu.
+func (s *SQLiteGraphStore) insertEdge(ctx context.Context, edge Edge) error {
+	if err := insertEdgeWith(ctx, s.db, edge); err != nil {
+		return err
+	}
+
+	s.bumpVersion()
+	return nil
+}
+
+// insertEdgeWith writes edge through q, within or outside a transaction.
+func insertEdgeWith(ctx context.Context, q dbQuerier, edge Edge) error {
 	var metadataJSON []byte
 	var err error
 	if edge.Metadata != nil {
@@ -835,24 +1128,22 @@ func (s *SQLiteGraphStore) AddEdge(ctx context.Context, edge Edge) error {
 	// Format CreatedAt for SQLite storage
 	var createdAtStr sql.NullString
 	if !edge.CreatedAt.IsZero() {
-		createdAtStr = sql.NullString{String: edge.CreatedAt.Format(time.RFC3339), Valid: true}
+		createdAtStr = sql.NullString{String: formatTimestamp(edge.CreatedAt), Valid: true}
 	}
 
 	// Format LastActivated for SQLite storage
 	var lastActivatedStr sql.NullString
 	if edge.LastActivated != nil && !edge.LastActivated.IsZero() {
-		lastActivatedStr = sql.NullString{String: edge.LastActivated.Format(time.RFC3339), Valid: true}
+		lastActivatedStr = sql.NullString{String: formatTimestamp(*edge.LastActivated), Valid: true}
 	}
 
-	_, err = s.db.ExecContext(ctx, `
+	_, err = q.ExecContext(ctx, `
 		INSERT OR REPLACE INTO edges (source, target, kind, weight, created_at, last_activated, metadata)
 		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`, edge.Source, edge.Target, edge.Kind, edge.Weight, createdAtStr, lastActivatedStr, nullBytes(metadataJSON))
 	if err != nil {
 		return fmt.Errorf("failed to add edge: %w", err)
 	}
-
-	s.bumpVersion()
 	return nil
 }
 
@@ -1092,19 +1383,45 @@ func (s *SQLiteGraphStore) traverseRecursive(ctx context.Context, current string
 
 // Sync exports dirty behaviors to JSONL files.
 // Uses incremental export when possible: only processes dirty behaviors
-// instead of full table scans.
+// instead of full table scans. With the append log enabled, dirty behaviors
+// are appended to nodes.log.jsonl instead of rewriting nodes.jsonl. The
+// synced files are then passed to the sync reporter, if one is set (see
+// SetSyncReporter).
 func (s *SQLiteGraphStore) Sync(ctx context.Context) error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	err := s.syncLocked(ctx)
+	report := syncReporter.Load()
+	var synced SyncedFiles
+	if err == nil && report != nil {
+		synced, err = readSyncedFiles(s.floopDir)
+	}
+	s.mu.Unlock()
 
+	if err == nil && report != nil {
+		(*report)(ctx, synced)
+	}
+	return err
+}
+
+// syncLocked implements Sync. Caller must hold the write lock.
+func (s *SQLiteGraphStore) syncLocked(ctx context.Context) error {
 	// Check if we have dirty behaviors
 	dirtyOps, err := s.getDirtyOperations(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get dirty operations: %w", err)
 	}
 
-	// If there are dirty behaviors, use incremental export
-	if len(dirtyOps) > 0 {
+	if s.appendLog {
+		if err := s.syncNodeLog(ctx, dirtyOps); err != nil {
+			return err
+		}
+	} else if _, err := os.Stat(s.nodesLogFile); err == nil {
+		// A leftover append log means nodes.jsonl is stale; fold it in.
+		if err := s.compactNodeLog(ctx); err != nil {
+			return fmt.Errorf("failed to compact append log: %w", err)
+		}
+	} else if len(dirtyOps) > 0 {
+		// If there are dirty behaviors, use incremental export
 		if err := s.incrementalExportNodes(ctx, dirtyOps); err != nil {
 			// Fall back to full export on error
 			if err := s.exportNodesToJSONL(ctx); err != nil {
@@ -1137,7 +1454,12 @@ func (s *SQLiteGraphStore) Sync(ctx context.Context) error {
 		return fmt.Errorf("failed to clear dirty flags: %w", err)
 	}
 
-	return nil
+	if err := s.flushChangelog(ctx); err != nil {
+		return err
+	}
+
+	// The JSONL now matches the database; the next open can skip importing it
+	return s.recordJSONLFingerprint(ctx)
 }
 
 // dirtyOperation represents a dirty behavior and its operation type.
@@ -1208,10 +1530,16 @@ func (s *SQLiteGraphStore) incrementalExportNodes(ctx context.Context, dirtyOps
 		}
 	}
 
+	nodes := make([]Node, 0, len(existingNodes))
+	for _, node := range existingNodes {
+		nodes = append(nodes, node)
+	}
+	sortNodes(nodes)
+
 	// Write all nodes back to file atomically
 	return atomicWriteFile(s.nodesFile, func(f *os.File) error {
 		encoder := json.NewEncoder(f)
-		for _, node := range existingNodes {
+		for _, node := range nodes {
 			if err := encoder.Encode(node); err != nil {
 				return fmt.Errorf("failed to encode node: %w", err)
 			}
@@ -1302,10 +1630,30 @@ func (s *SQLiteGraphStore) enrichNodeWithEmbedding(ctx context.Context, node *No
 	}
 }
 
-// exportNodesToJSONL exports all behaviors to the nodes.jsonl file.
+// sortNodes orders nodes by ID, so exports of an unchanged store are
+// byte-identical.
+func sortNodes(nodes []Node) {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+}
+
+// sortEdges orders edges by source, target, and kind.
+func sortEdges(edges []Edge) {
+	sort.Slice(edges, func(i, j int) bool {
+		a, b := edges[i], edges[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Target != b.Target {
+			return a.Target < b.Target
+		}
+		return a.Kind < b.Kind
+	})
+}
+
+// exportNodesToJSONL exports all behaviors to the nodes.jsonl file, ordered by ID.
 func (s *SQLiteGraphStore) exportNodesToJSONL(ctx context.Context) error {
 	// Get all behavior IDs first (close rows before nested queries)
-	rows, err := s.db.QueryContext(ctx, `SELECT id FROM behaviors`)
+	rows, err := s.db.QueryContext(ctx, `SELECT id FROM behaviors ORDER BY id`)
 	if err != nil {
 		return fmt.Errorf("failed to query behaviors: %w", err)
 	}
@@ -1346,9 +1694,10 @@ func (s *SQLiteGraphStore) exportNodesToJSONL(ctx context.Context) error {
 	})
 }
 
-// exportEdgesToJSONL exports all edges to the edges.jsonl file.
+// exportEdgesToJSONL exports all edges to the edges.jsonl file, ordered by
+// source, target, and kind.
 func (s *SQLiteGraphStore) exportEdgesToJSONL(ctx context.Context) error {
-	rows, err := s.db.QueryContext(ctx, `SELECT source, target, kind, weight, created_at, last_activated, metadata FROM edges`)
+	rows, err := s.db.QueryContext(ctx, `SELECT source, target, kind, weight, created_at, last_activated, metadata FROM edges ORDER BY source, target, kind`)
 	if err != nil {
 		return fmt.Errorf("failed to query edges: %w", err)
 	}
@@ -1405,12 +1754,20 @@ func (s *SQLiteGraphStore) exportEdgesToJSONL(ctx context.Context) error {
 	})
 }
 
-// Close syncs and closes the store.
+// Close syncs and closes the store, compacting any append log into nodes.jsonl.
 func (s *SQLiteGraphStore) Close() error {
-	if err := s.Sync(context.Background()); err != nil {
+	ctx := context.Background()
+	if err := s.Sync(ctx); err != nil {
 		// Log but don't fail on sync error during close
 		fmt.Fprintf(os.Stderr, "warning: failed to sync during close: %v\n", err)
 	}
+	s.mu.Lock()
+	if err := s.compactNodeLog(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to compact append log during close: %v\n", err)
+	} else if err := s.recordJSONLFingerprint(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+	s.mu.Unlock()
 	return s.db.Close()
 }
 
@@ -0,0 +1,127 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// RecordClientActivation increments the per-client activation count for a behavior.
+func (s *SQLiteGraphStore) RecordClientActivation(ctx context.Context, behaviorID, client string) error {
+	if client == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := formatTimestamp(time.Now())
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO behavior_client_stats (behavior_id, client, times_activated, last_activated)
+		VALUES (?, ?, 1, ?)
+		ON CONFLICT(behavior_id, client) DO UPDATE SET
+			times_activated = times_activated + 1,
+			last_activated = excluded.last_activated
+	`, behaviorID, client, now)
+	if err != nil {
+		return fmt.Errorf("record client activation for %s: %w", behaviorID, err)
+	}
+	return nil
+}
+
+// GetClientStats returns learn and activation counts per client, sorted by
+// client name.
+func (s *SQLiteGraphStore) GetClientStats(ctx context.Context) ([]ClientStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	byClient := make(map[string]*ClientStats)
+	get := func(client string) *ClientStats {
+		cs, ok := byClient[client]
+		if !ok {
+			cs = &ClientStats{Client: client}
+			byClient[client] = cs
+		}
+		return cs
+	}
+
+	learnedRows, err := s.db.QueryContext(ctx, `
+		SELECT provenance_source_agent, COUNT(*) FROM behaviors
+		WHERE provenance_source_agent IS NOT NULL AND provenance_source_agent != ''
+		GROUP BY provenance_source_agent`)
+	if err != nil {
+		return nil, fmt.Errorf("query learned counts: %w", err)
+	}
+	for learnedRows.Next() {
+		var client string
+		var count int
+		if err := learnedRows.Scan(&client, &count); err != nil {
+			learnedRows.Close()
+			return nil, fmt.Errorf("scan learned count: %w", err)
+		}
+		get(client).BehaviorsLearned = count
+	}
+	learnedRows.Close()
+
+	activationRows, err := s.db.QueryContext(ctx, `
+		SELECT client, COUNT(*), SUM(times_activated), MAX(last_activated)
+		FROM behavior_client_stats GROUP BY client`)
+	if err != nil {
+		return nil, fmt.Errorf("query client activations: %w", err)
+	}
+	defer activationRows.Close()
+	for activationRows.Next() {
+		var client string
+		var behaviors, activations int
+		var last sql.NullString
+		if err := activationRows.Scan(&client, &behaviors, &activations, &last); err != nil {
+			return nil, fmt.Errorf("scan client activations: %w", err)
+		}
+		cs := get(client)
+		cs.BehaviorsActivated = behaviors
+		cs.Activations = activations
+		if last.Valid {
+			if t, err := time.Parse(time.RFC3339, last.String); err == nil {
+				cs.LastActivated = &t
+			}
+		}
+	}
+	if err := activationRows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate client activations: %w", err)
+	}
+
+	return sortedClientStats(byClient), nil
+}
+
+// mergeClientStats sums per-client stats from several stores.
+func mergeClientStats(sets ...[]ClientStats) []ClientStats {
+	byClient := make(map[string]*ClientStats)
+	for _, set := range sets {
+		for _, cs := range set {
+			merged, ok := byClient[cs.Client]
+			if !ok {
+				merged = &ClientStats{Client: cs.Client}
+				byClient[cs.Client] = merged
+			}
+			merged.BehaviorsLearned += cs.BehaviorsLearned
+			merged.BehaviorsActivated += cs.BehaviorsActivated
+			merged.Activations += cs.Activations
+			if cs.LastActivated != nil && (merged.LastActivated == nil || cs.LastActivated.After(*merged.LastActivated)) {
+				t := *cs.LastActivated
+				merged.LastActivated = &t
+			}
+		}
+	}
+	return sortedClientStats(byClient)
+}
+
+func sortedClientStats(byClient map[string]*ClientStats) []ClientStats {
+	stats := make([]ClientStats, 0, len(byClient))
+	for _, cs := range byClient {
+		stats = append(stats, *cs)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Client < stats[j].Client })
+	return stats
+}
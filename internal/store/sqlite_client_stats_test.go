@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func clientStatsTestNode(id, sourceAgent string) Node {
+	return Node{
+		ID:   id,
+		Kind: NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name": id,
+			"kind": "directive",
+			"content": map[string]interface{}{
+				"canonical": "Behavior " + id,
+			},
+			"provenance": map[string]interface{}{
+				"source_type":  "learned",
+				"source_agent": sourceAgent,
+			},
+		},
+	}
+}
+
+func TestSQLiteGraphStore_ClientStats(t *testing.T) {
+	s, err := NewSQLiteGraphStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	defer s.Close()
+	ctx := context.Background()
+
+	mustAddNode(t, s, ctx, clientStatsTestNode("b-1", "claude-code"))
+	mustAddNode(t, s, ctx, clientStatsTestNode("b-2", "claude-code"))
+	mustAddNode(t, s, ctx, clientStatsTestNode("b-3", "cursor"))
+	mustAddNode(t, s, ctx, clientStatsTestNode("b-4", ""))
+
+	// source_agent round-trips through provenance
+	node := mustGetNode(t, s, ctx, "b-3")
+	prov, _ := node.Content["provenance"].(map[string]interface{})
+	if got := prov["source_agent"]; got != "cursor" {
+		t.Errorf("provenance source_agent = %v, want cursor", got)
+	}
+
+	for _, rec := range []struct{ id, client string }{
+		{"b-1", "cursor"},
+		{"b-1", "cursor"},
+		{"b-3", "cursor"},
+		{"b-1", "claude-code"},
+		{"b-2", ""}, // ignored
+	} {
+		if err := s.RecordClientActivation(ctx, rec.id, rec.client); err != nil {
+			t.Fatalf("RecordClientActivation(%s, %s) error = %v", rec.id, rec.client, err)
+		}
+	}
+
+	stats, err := s.GetClientStats(ctx)
+	if err != nil {
+		t.Fatalf("GetClientStats() error = %v", err)
+	}
+
+	want := []ClientStats{
+		{Client: "claude-code", BehaviorsLearned: 2, BehaviorsActivated: 1, Activations: 1},
+		{Client: "cursor", BehaviorsLearned: 1, BehaviorsActivated: 2, Activations: 3},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("GetClientStats() returned %d clients, want %d: %+v", len(stats), len(want), stats)
+	}
+	for i, w := range want {
+		got := stats[i]
+		if got.Client != w.Client || got.BehaviorsLearned != w.BehaviorsLearned ||
+			got.BehaviorsActivated != w.BehaviorsActivated || got.Activations != w.Activations {
+			t.Errorf("stats[%d] = %+v, want %+v", i, got, w)
+		}
+		if got.LastActivated == nil {
+			t.Errorf("stats[%d].LastActivated should be set", i)
+		}
+	}
+
+	// Deleting a behavior drops its per-client rows
+	if err := s.DeleteNode(ctx, "b-1"); err != nil {
+		t.Fatalf("DeleteNode() error = %v", err)
+	}
+	stats, err = s.GetClientStats(ctx)
+	if err != nil {
+		t.Fatalf("GetClientStats() error = %v", err)
+	}
+	for _, cs := range stats {
+		if cs.Client == "cursor" && cs.Activations != 1 {
+			t.Errorf("cursor activations after delete = %d, want 1", cs.Activations)
+		}
+		if cs.Client == "claude-code" && cs.Activations != 0 {
+			t.Errorf("claude-code activations after delete = %d, want 0", cs.Activations)
+		}
+	}
+}
@@ -13,7 +13,7 @@ func (s *SQLiteGraphStore) RecordCoActivation(ctx context.Context, pairKey strin
 
 	_, err := s.db.ExecContext(ctx,
 		`INSERT INTO co_activations (pair_key, activated_at) VALUES (?, ?)`,
-		pairKey, at.Format(time.RFC3339Nano))
+		pairKey, at.UTC().Format(time.RFC3339Nano))
 	if err != nil {
 		return fmt.Errorf("record co-activation: %w", err)
 	}
@@ -27,7 +27,7 @@ func (s *SQLiteGraphStore) GetCoActivations(ctx context.Context, pairKey string,
 
 	rows, err := s.db.QueryContext(ctx,
 		`SELECT activated_at FROM co_activations WHERE pair_key = ? AND activated_at > ? ORDER BY activated_at`,
-		pairKey, since.Format(time.RFC3339Nano))
+		pairKey, since.UTC().Format(time.RFC3339Nano))
 	if err != nil {
 		return nil, fmt.Errorf("get co-activations: %w", err)
 	}
@@ -55,7 +55,7 @@ func (s *SQLiteGraphStore) PruneCoActivations(ctx context.Context, before time.T
 
 	result, err := s.db.ExecContext(ctx,
 		`DELETE FROM co_activations WHERE activated_at < ?`,
-		before.Format(time.RFC3339Nano))
+		before.UTC().Format(time.RFC3339Nano))
 	if err != nil {
 		return 0, fmt.Errorf("prune co-activations: %w", err)
 	}
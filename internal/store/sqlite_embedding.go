@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"database/sql"
 	"encoding/binary"
 	"fmt"
 	"math"
@@ -64,7 +65,7 @@ func (s *SQLiteGraphStore) GetAllEmbeddings(ctx context.Context) ([]BehaviorEmbe
 	defer s.mu.RUnlock()
 
 	rows, err := s.db.QueryContext(ctx,
-		`SELECT id, embedding FROM behaviors WHERE embedding IS NOT NULL`)
+		`SELECT id, embedding, embedding_model FROM behaviors WHERE embedding IS NOT NULL`)
 	if err != nil {
 		return nil, fmt.Errorf("query embeddings: %w", err)
 	}
@@ -74,7 +75,8 @@ func (s *SQLiteGraphStore) GetAllEmbeddings(ctx context.Context) ([]BehaviorEmbe
 	for rows.Next() {
 		var id string
 		var blob []byte
-		if err := rows.Scan(&id, &blob); err != nil {
+		var model sql.NullString
+		if err := rows.Scan(&id, &blob, &model); err != nil {
 			return nil, fmt.Errorf("scan embedding: %w", err)
 		}
 		vec := decodeEmbedding(blob)
@@ -84,6 +86,7 @@ func (s *SQLiteGraphStore) GetAllEmbeddings(ctx context.Context) ([]BehaviorEmbe
 		results = append(results, BehaviorEmbedding{
 			BehaviorID: id,
 			Embedding:  vec,
+			Model:      model.String,
 		})
 	}
 
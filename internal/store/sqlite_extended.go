@@ -13,7 +13,7 @@ func (s *SQLiteGraphStore) UpdateConfidence(ctx context.Context, behaviorID stri
 
 	result, err := s.db.ExecContext(ctx,
 		`UPDATE behaviors SET confidence = ?, updated_at = ? WHERE id = ?`,
-		newConfidence, time.Now().Format(time.RFC3339), behaviorID)
+		newConfidence, formatTimestamp(time.Now()), behaviorID)
 	if err != nil {
 		return fmt.Errorf("failed to update confidence for %s: %w", behaviorID, err)
 	}
@@ -35,7 +35,7 @@ func (s *SQLiteGraphStore) RecordActivationHit(ctx context.Context, behaviorID s
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	now := time.Now().Format(time.RFC3339)
+	now := formatTimestamp(time.Now())
 	result, err := s.db.ExecContext(ctx,
 		`UPDATE behavior_stats SET times_activated = times_activated + 1, last_activated = ? WHERE behavior_id = ?`,
 		now, behaviorID)
@@ -60,7 +60,7 @@ func (s *SQLiteGraphStore) RecordConfirmed(ctx context.Context, behaviorID strin
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	now := time.Now().Format(time.RFC3339)
+	now := formatTimestamp(time.Now())
 	result, err := s.db.ExecContext(ctx,
 		`UPDATE behavior_stats SET times_confirmed = times_confirmed + 1, last_confirmed = ? WHERE behavior_id = ?`,
 		now, behaviorID)
@@ -103,6 +103,46 @@ func (s *SQLiteGraphStore) RecordOverridden(ctx context.Context, behaviorID stri
 	return nil
 }
 
+// BatchRecordFeedback applies several behaviors' feedback in a single
+// transaction. If any behavior is missing, nothing is recorded.
+func (s *SQLiteGraphStore) BatchRecordFeedback(ctx context.Context, updates []FeedbackUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin batch feedback: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := formatTimestamp(time.Now())
+	for _, u := range updates {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE behavior_stats SET
+				times_confirmed = times_confirmed + ?,
+				times_overridden = times_overridden + ?,
+				last_confirmed = CASE WHEN ? > 0 THEN ? ELSE last_confirmed END
+			WHERE behavior_id = ?
+		`, u.Confirmed, u.Overridden, u.Confirmed, now, u.BehaviorID)
+		if err != nil {
+			return fmt.Errorf("failed to record feedback for %s: %w", u.BehaviorID, err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to check rows affected: %w", err)
+		}
+		if rows == 0 {
+			return fmt.Errorf("behavior not found: %s", u.BehaviorID)
+		}
+	}
+
+	return tx.Commit()
+}
+
 // TouchEdges updates last_activated on all edges where the source or target
 // is one of the given behavior IDs. This enables temporal decay on edge
 // weights in the spreading activation engine.
@@ -117,7 +157,7 @@ func (s *SQLiteGraphStore) TouchEdges(ctx context.Context, behaviorIDs []string)
 	// Build parameterized IN clause
 	placeholders := make([]string, len(behaviorIDs))
 	args := make([]interface{}, 0, 1+2*len(behaviorIDs))
-	now := time.Now().Format(time.RFC3339)
+	now := formatTimestamp(time.Now())
 	args = append(args, now)
 	for i, id := range behaviorIDs {
 		placeholders[i] = "?"
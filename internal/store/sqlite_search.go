@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+// SearchBehaviors implements BehaviorSearcher over the behaviors_fts index,
+// ranking hits by BM25 with matches in the name weighted double.
+func (s *SQLiteGraphStore) SearchBehaviors(ctx context.Context, query string, limit int) ([]SearchHit, error) {
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// bm25 is lower for better matches, so it is negated into the score
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT b.id, -bm25(behaviors_fts, 2.0, 1.0, 1.0, 1.0, 1.0) AS score
+		FROM behaviors_fts
+		JOIN behaviors b ON b.rowid = behaviors_fts.rowid
+		WHERE behaviors_fts MATCH ? AND b.kind = ?
+		ORDER BY score DESC, b.id
+		LIMIT ?`, ftsMatch(terms), string(NodeKindBehavior), limit)
+	if err != nil {
+		return nil, fmt.Errorf("search behaviors: %w", err)
+	}
+	type scored struct {
+		id    string
+		score float64
+	}
+	var matches []scored
+	for rows.Next() {
+		var m scored
+		if err := rows.Scan(&m.id, &m.score); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan search hit: %w", err)
+		}
+		matches = append(matches, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("search behaviors: %w", err)
+	}
+
+	hits := make([]SearchHit, 0, len(matches))
+	for _, m := range matches {
+		node, err := s.getNodeUnlocked(ctx, m.id)
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			hits = append(hits, SearchHit{Node: *node, Score: m.score})
+		}
+	}
+	return hits, nil
+}
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// OpenTiming breaks down the time NewSQLiteGraphStore spent opening a store.
+type OpenTiming struct {
+	// Dir is the .floop directory that was opened.
+	Dir string
+
+	// Total is the time from start to a usable store.
+	Total time.Duration
+
+	// Schema covers opening the database and schema setup, including the
+	// integrity check when it ran.
+	Schema time.Duration
+
+	// Integrity is the time spent in the structural integrity check.
+	// IntegrityChecked is false when the check was skipped as not yet due.
+	Integrity        time.Duration
+	IntegrityChecked bool
+
+	// Import is the time spent deciding whether to import JSONL and doing
+	// so. Imported is false when the JSONL was unchanged since the last
+	// import or export.
+	Import   time.Duration
+	Imported bool
+}
+
+// String formats t as a one-line breakdown for --verbose output.
+func (t OpenTiming) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "opened %s in %s: schema %s", t.Dir, t.Total.Round(time.Microsecond), t.Schema.Round(time.Microsecond))
+	if t.IntegrityChecked {
+		fmt.Fprintf(&sb, " (integrity check %s)", t.Integrity.Round(time.Microsecond))
+	} else {
+		sb.WriteString(" (integrity check not due)")
+	}
+	if t.Imported {
+		fmt.Fprintf(&sb, ", JSONL import %s", t.Import.Round(time.Microsecond))
+	} else {
+		fmt.Fprintf(&sb, ", JSONL unchanged (%s)", t.Import.Round(time.Microsecond))
+	}
+	return sb.String()
+}
+
+// openTimingReporter receives the OpenTiming of every SQLite store opened.
+var openTimingReporter atomic.Pointer[func(OpenTiming)]
+
+// SetOpenTimingReporter makes fn receive the OpenTiming of every SQLite store
+// opened from now on. A nil fn stops reporting.
+func SetOpenTimingReporter(fn func(OpenTiming)) {
+	if fn == nil {
+		openTimingReporter.Store(nil)
+		return
+	}
+	openTimingReporter.Store(&fn)
+}
+
+// reportOpenTiming passes t to the reporter, if one is set.
+func reportOpenTiming(t OpenTiming) {
+	if fn := openTimingReporter.Load(); fn != nil {
+		(*fn)(t)
+	}
+}
+
+// jsonlFingerprint hashes the size and modification time of nodes.jsonl, the
+// node append log, and edges.jsonl. It changes whenever any of them is
+// written, without reading their content.
+func (s *SQLiteGraphStore) jsonlFingerprint() (string, error) {
+	h := sha256.New()
+	for _, path := range []string{s.nodesFile, s.nodesLogFile, s.edgesFile} {
+		info, err := os.Stat(path)
+		switch {
+		case os.IsNotExist(err):
+			fmt.Fprintf(h, "%s\x00missing\n", path)
+		case err != nil:
+			return "", fmt.Errorf("failed to stat %s: %w", path, err)
+		default:
+			fmt.Fprintf(h, "%s\x00%d\x00%d\n", path, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// storedJSONLFingerprint returns the fingerprint recorded by the last import
+// or export, or "" if none was recorded.
+func (s *SQLiteGraphStore) storedJSONLFingerprint(ctx context.Context) string {
+	var hash sql.NullString
+	if err := s.db.QueryRowContext(ctx, `SELECT jsonl_hash FROM export_state WHERE id = 1`).Scan(&hash); err != nil {
+		return ""
+	}
+	return hash.String
+}
+
+// recordJSONLFingerprint stores the current JSONL fingerprint, marking the
+// database and JSONL files as in step.
+func (s *SQLiteGraphStore) recordJSONLFingerprint(ctx context.Context) error {
+	hash, err := s.jsonlFingerprint()
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT OR REPLACE INTO export_state (id, last_export_time, jsonl_hash) VALUES (1, ?, ?)`,
+		time.Now().UTC().Format(time.RFC3339), hash); err != nil {
+		return fmt.Errorf("failed to record JSONL fingerprint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// openWithTiming opens a SQLite store at dir and returns it with its OpenTiming.
+func openWithTiming(t *testing.T, dir string) (*SQLiteGraphStore, OpenTiming) {
+	t.Helper()
+	var got OpenTiming
+	SetOpenTimingReporter(func(ot OpenTiming) { got = ot })
+	defer SetOpenTimingReporter(nil)
+
+	s, err := NewSQLiteGraphStore(dir)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	return s, got
+}
+
+func TestNewSQLiteGraphStore_SkipsUnchangedImport(t *testing.T) {
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+
+	s1, _ := openWithTiming(t, tmpDir)
+	mustAddNode(t, s1, ctx, Node{
+		ID:      "startup-1",
+		Kind:    NodeKindBehavior,
+		Content: map[string]interface{}{"name": "first", "kind": "directive", "content": map[string]interface{}{"canonical": "first"}},
+	})
+	if err := s1.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	s2, timing := openWithTiming(t, tmpDir)
+	s2.Close()
+	if timing.Imported {
+		t.Error("reopening with unchanged JSONL should skip the import")
+	}
+	if timing.Dir != filepath.Join(tmpDir, ".floop") || timing.Total <= 0 {
+		t.Errorf("timing = %+v, want Dir and Total set", timing)
+	}
+
+	// An external change to nodes.jsonl is picked up on the next open
+	f, err := os.OpenFile(filepath.Join(tmpDir, ".floop", "nodes.jsonl"), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	json.NewEncoder(f).Encode(Node{
+		ID:      "startup-2",
+		Kind:    NodeKindBehavior,
+		Content: map[string]interface{}{"name": "second", "kind": "directive", "content": map[string]interface{}{"canonical": "second"}},
+	})
+	f.Close()
+
+	s3, timing := openWithTiming(t, tmpDir)
+	defer s3.Close()
+	if !timing.Imported {
+		t.Error("reopening after the JSONL changed should import it")
+	}
+	if node, _ := s3.GetNode(ctx, "startup-2"); node == nil {
+		t.Error("node added to nodes.jsonl should be imported")
+	}
+}
+
+func TestNewSQLiteGraphStore_PeriodicIntegrityCheck(t *testing.T) {
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+
+	s1, _ := openWithTiming(t, tmpDir)
+	s1.Close()
+
+	s2, timing := openWithTiming(t, tmpDir)
+	if timing.IntegrityChecked {
+		t.Error("integrity check should not run again right after creation")
+	}
+
+	// Backdate the last check past the interval
+	old := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+	if _, err := s2.db.ExecContext(ctx, `INSERT OR REPLACE INTO config (key, value) VALUES (?, ?)`, integrityCheckKey, old); err != nil {
+		t.Fatalf("failed to backdate integrity check: %v", err)
+	}
+	s2.Close()
+
+	s3, timing := openWithTiming(t, tmpDir)
+	defer s3.Close()
+	if !timing.IntegrityChecked {
+		t.Error("integrity check should run once the interval has passed")
+	}
+	if integrityCheckDue(ctx, s3.db, time.Now()) {
+		t.Error("a passing check should be recorded")
+	}
+}
@@ -0,0 +1,40 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// stateKeyPrefix keeps StateStore keys apart from the store's own entries
+// in the config table.
+const stateKeyPrefix = "state/"
+
+// GetState returns the value stored under key, or "" if there is none.
+func (s *SQLiteGraphStore) GetState(ctx context.Context, key string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var value string
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM config WHERE key = ?`, stateKeyPrefix+key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get state %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// SetState stores value under key.
+func (s *SQLiteGraphStore) SetState(ctx context.Context, key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.db.ExecContext(ctx, `INSERT OR REPLACE INTO config (key, value) VALUES (?, ?)`,
+		stateKeyPrefix+key, value); err != nil {
+		return fmt.Errorf("set state %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSQLiteGraphStore_State(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	s, err := NewSQLiteGraphStore(root)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+
+	if got, err := s.GetState(ctx, "k"); err != nil || got != "" {
+		t.Errorf("GetState() of unset key = %q, %v; want empty", got, err)
+	}
+	for _, v := range []string{"one", "two"} {
+		if err := s.SetState(ctx, "k", v); err != nil {
+			t.Fatalf("SetState(%q) error = %v", v, err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// State survives reopening, apart from the store's own config entries
+	s, err = NewSQLiteGraphStore(root)
+	if err != nil {
+		t.Fatalf("reopen error = %v", err)
+	}
+	defer s.Close()
+	if got, _ := s.GetState(ctx, "k"); got != "two" {
+		t.Errorf("GetState() after reopen = %q, want two", got)
+	}
+	if got, _ := s.GetState(ctx, integrityCheckKey); got != "" {
+		t.Errorf("GetState(%s) = %q, want state kept apart from config", integrityCheckKey, got)
+	}
+}
+
+func TestMultiGraphStore_State(t *testing.T) {
+	ctx := context.Background()
+	localRoot := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", t.TempDir())
+
+	m, err := NewMultiGraphStore(localRoot)
+	if err != nil {
+		t.Fatalf("NewMultiGraphStore() error = %v", err)
+	}
+	defer m.Close()
+
+	if err := m.SetState(ctx, "k", "combined"); err != nil {
+		t.Fatalf("SetState() error = %v", err)
+	}
+	if got, _ := m.GetState(ctx, "k"); got != "combined" {
+		t.Errorf("GetState() = %q, want combined", got)
+	}
+	// The local store's own state under the same key is separate
+	if got, _ := m.LocalStore().(StateStore).GetState(ctx, "k"); got != "" {
+		t.Errorf("local GetState() = %q, want the combined view kept apart", got)
+	}
+}
@@ -53,7 +53,7 @@ func TestNewSQLiteGraphStore_CreatesGitignore(t *testing.T) {
 	}
 
 	content := string(data)
-	for _, entry := range []string{"floop.db\n", "floop.db-shm\n", "floop.db-wal\n", "audit.jsonl\n"} {
+	for _, entry := range []string{"floop.db\n", "floop.db-shm\n", "floop.db-wal\n", "audit.jsonl\n", "corrections.jsonl.imported\n", "context-cache.json\n", "metrics.json\n", "events.jsonl\n", "pending/\n", "sync/\n"} {
 		if !strings.Contains(content, entry) {
 			t.Errorf(".gitignore missing entry %q", strings.TrimSpace(entry))
 		}
@@ -187,6 +187,43 @@ func TestSQLiteGraphStore_UpdateNode(t *testing.T) {
 	}
 }
 
+func TestSQLiteGraphStore_PriorityRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, err := NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	node := Node{
+		ID:      "priority-1",
+		Kind:    NodeKindBehavior,
+		Content: map[string]interface{}{"name": "p", "kind": "directive", "content": map[string]interface{}{"canonical": "priority test"}},
+		Metadata: map[string]interface{}{
+			"confidence": 0.7,
+			"priority":   float64(2), // as decoded from JSON
+		},
+	}
+	if _, err := store.AddNode(ctx, node); err != nil {
+		t.Fatalf("AddNode() error = %v", err)
+	}
+	got, _ := store.GetNode(ctx, "priority-1")
+	if got.Metadata["priority"] != 2 {
+		t.Errorf("priority after AddNode = %v, want 2", got.Metadata["priority"])
+	}
+
+	// Priorities set in Go are ints
+	got.Metadata["priority"] = 7
+	if err := store.UpdateNode(ctx, *got); err != nil {
+		t.Fatalf("UpdateNode() error = %v", err)
+	}
+	got, _ = store.GetNode(ctx, "priority-1")
+	if got.Metadata["priority"] != 7 {
+		t.Errorf("priority after UpdateNode = %v, want 7", got.Metadata["priority"])
+	}
+}
+
 func TestSQLiteGraphStore_AddBehavior_AtomicInsert(t *testing.T) {
 	tmpDir := t.TempDir()
 	store, err := NewSQLiteGraphStore(tmpDir)
@@ -1038,6 +1075,81 @@ func TestSQLiteGraphStore_ConnectionPoolSettings(t *testing.T) {
 	}
 }
 
+func TestSQLiteGraphStore_DeterministicExport(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, err := NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	var ops []dirtyOperation
+	for i := 20; i > 0; i-- {
+		id := fmt.Sprintf("b-%02d", i)
+		mustAddNode(t, store, ctx, Node{
+			ID:   id,
+			Kind: NodeKindBehavior,
+			Content: map[string]interface{}{
+				"name":    "Behavior " + id,
+				"kind":    "directive",
+				"content": map[string]interface{}{"canonical": "Behavior " + id},
+			},
+		})
+		ops = append(ops, dirtyOperation{BehaviorID: id, Operation: "update"})
+	}
+	for _, e := range [][2]string{{"b-09", "b-01"}, {"b-01", "b-09"}, {"b-01", "b-02"}, {"b-20", "b-03"}} {
+		mustAddEdge(t, store, ctx, Edge{Source: e[0], Target: e[1], Kind: EdgeKindSimilarTo, Weight: 0.5, CreatedAt: time.Now()})
+	}
+	if err := store.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	read := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(tmpDir, ".floop", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	nodes, edges := read("nodes.jsonl"), read("edges.jsonl")
+	if first := strings.SplitN(nodes, "\n", 2)[0]; !strings.Contains(first, `"id":"b-01"`) {
+		t.Errorf("first node line = %s, want b-01", first)
+	}
+	if first := strings.SplitN(edges, "\n", 2)[0]; !strings.Contains(first, `"source":"b-01","target":"b-02"`) {
+		t.Errorf("first edge line = %s, want b-01 -> b-02", first)
+	}
+
+	// Re-exporting the unchanged store, fully or incrementally, is byte-identical
+	for i := 0; i < 3; i++ {
+		store.mu.Lock()
+		err := store.incrementalExportNodes(ctx, ops)
+		if err == nil {
+			err = store.exportEdgesToJSONL(ctx)
+		}
+		store.mu.Unlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := read("nodes.jsonl"); got != nodes {
+			t.Fatalf("incremental export %d differs:\n%s\nwant\n%s", i, got, nodes)
+		}
+		if got := read("edges.jsonl"); got != edges {
+			t.Fatalf("edge export %d differs:\n%s\nwant\n%s", i, got, edges)
+		}
+	}
+	store.mu.Lock()
+	err = store.exportNodesToJSONL(ctx)
+	store.mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := read("nodes.jsonl"); got != nodes {
+		t.Errorf("full export differs:\n%s\nwant\n%s", got, nodes)
+	}
+}
+
 func TestSQLiteGraphStore_IncrementalExport(t *testing.T) {
 	tmpDir := t.TempDir()
 	store, err := NewSQLiteGraphStore(tmpDir)
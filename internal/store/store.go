@@ -13,6 +13,10 @@ import (
 // canonical content already exists in the store under a different ID.
 var ErrDuplicateContent = errors.New("duplicate content")
 
+// ErrReadOnlyOrigin is returned when a write targets a node from a read-only
+// store, such as the policy store.
+var ErrReadOnlyOrigin = errors.New("node comes from a read-only store")
+
 // DuplicateContentError is a structured error returned by AddNode when a node
 // with identical canonical content already exists. It carries the ID of the
 // existing node so callers can use errors.As instead of string-parsing.
@@ -34,13 +38,30 @@ type Node struct {
 	Kind     NodeKind               `json:"kind"` // "behavior", "correction", "context-snapshot"
 	Content  map[string]interface{} `json:"content"`
 	Metadata map[string]interface{} `json:"metadata"`
+
+	// Origin is the underlying store the node was read from. MultiGraphStore
+	// sets it on read and uses it to route writes; it is never persisted.
+	Origin Origin `json:"-"`
 }
 
+// Origin identifies which store in a composite view a node came from.
+type Origin string
+
+const (
+	// OriginLocal is the project store (./.floop/).
+	OriginLocal Origin = "local"
+	// OriginGlobal is the user store (~/.floop/).
+	OriginGlobal Origin = "global"
+	// OriginPolicy is a read-only policy store attached with SetPolicyStore.
+	// Read-only scope stores have origins made by ScopeOrigin.
+	OriginPolicy Origin = "policy"
+)
+
 // Edge represents a relationship between nodes.
 type Edge struct {
 	Source        string                 `json:"source"`
 	Target        string                 `json:"target"`
-	Kind          EdgeKind               `json:"kind"`                     // "requires", "overrides", "conflicts", "learned-from", "similar-to"
+	Kind          EdgeKind               `json:"kind"`                     // "requires", "overrides", "conflicts", "learned-from", "similar-to", "specializes"
 	Weight        float64                `json:"weight"`                   // 0.0-1.0, activation transmission factor
 	CreatedAt     time.Time              `json:"created_at"`               // when edge was created
 	LastActivated *time.Time             `json:"last_activated,omitempty"` // when activation last flowed through
@@ -55,6 +76,13 @@ type EdgeWeightUpdate struct {
 	NewWeight float64  // Updated weight value
 }
 
+// FeedbackUpdate describes the feedback signals to record for a behavior.
+type FeedbackUpdate struct {
+	BehaviorID string // Behavior receiving the feedback
+	Confirmed  int    // Confirmations to add to times_confirmed
+	Overridden int    // Overrides to add to times_overridden
+}
+
 // EdgeKind represents the type of relationship between nodes.
 type EdgeKind string
 
@@ -67,6 +95,7 @@ const (
 	EdgeKindCoActivated  EdgeKind = "co-activated"
 	EdgeKindDeprecatedTo EdgeKind = "deprecated-to"
 	EdgeKindMergedInto   EdgeKind = "merged-into"
+	EdgeKindSpecializes  EdgeKind = "specializes" // Source is a specific case of the target
 )
 
 // ValidUserEdgeKinds defines the allowed edge kinds for user-facing commands.
@@ -77,6 +106,7 @@ var ValidUserEdgeKinds = map[EdgeKind]bool{
 	EdgeKindConflicts:   true,
 	EdgeKindSimilarTo:   true,
 	EdgeKindLearnedFrom: true,
+	EdgeKindSpecializes: true,
 }
 
 // NodeKind represents the type of a node in the behavior graph.
@@ -89,6 +119,9 @@ const (
 	NodeKindForgotten       NodeKind = "forgotten-behavior"
 	NodeKindDeprecated      NodeKind = "deprecated-behavior"
 	NodeKindMerged          NodeKind = "merged-behavior"
+	NodeKindRetired         NodeKind = "retired-behavior"
+	NodeKindQuarantined     NodeKind = "quarantined-behavior"
+	NodeKindFailure         NodeKind = "failure"
 )
 
 // Direction specifies edge traversal direction.
@@ -112,6 +145,8 @@ type GraphStore interface {
 	// Predicate is a map of field names to required values.
 	// Supports flat key matching only (e.g., "kind", "id").
 	// e.g., {"kind": "behavior"}
+	// The "tag" key matches behaviors carrying that tag or one below it
+	// in the hierarchy, e.g. {"tag": "testing"} finds "testing/unit".
 	QueryNodes(ctx context.Context, predicate map[string]interface{}) ([]Node, error)
 
 	// Edge operations
@@ -145,6 +180,9 @@ type ExtendedGraphStore interface {
 	// RecordOverridden records that a behavior was overridden by the user.
 	RecordOverridden(ctx context.Context, behaviorID string) error
 
+	// BatchRecordFeedback records feedback for several behaviors atomically.
+	BatchRecordFeedback(ctx context.Context, updates []FeedbackUpdate) error
+
 	// TouchEdges updates the last_activated timestamp on all edges involving the given behaviors.
 	TouchEdges(ctx context.Context, behaviorIDs []string) error
 
@@ -167,6 +205,14 @@ type ExtendedGraphStore interface {
 	ValidateBehaviorGraph(ctx context.Context) ([]ValidationError, error)
 }
 
+// ScopedStore exposes its local and global stores separately.
+// MultiGraphStore implements this interface; callers use it via type
+// assertion to work on each scope on its own.
+type ScopedStore interface {
+	LocalStore() GraphStore
+	GlobalStore() GraphStore
+}
+
 // CoActivationStore provides persistence for Hebbian co-activation tracking.
 // Implemented by SQLiteGraphStore. Used via type assertion from the MCP server.
 type CoActivationStore interface {
@@ -180,10 +226,44 @@ type CoActivationStore interface {
 	PruneCoActivations(ctx context.Context, before time.Time) (int, error)
 }
 
+// ClientStats summarizes how one agent client (e.g. the MCP client name from
+// initialize) has learned and activated behaviors.
+type ClientStats struct {
+	Client             string     `json:"client"`
+	BehaviorsLearned   int        `json:"behaviors_learned"`
+	BehaviorsActivated int        `json:"behaviors_activated"`
+	Activations        int        `json:"activations"`
+	LastActivated      *time.Time `json:"last_activated,omitempty"`
+}
+
+// ClientStatsStore provides per-client attribution of behavior activations.
+// Implemented by SQLiteGraphStore and MultiGraphStore. Used via type assertion.
+type ClientStatsStore interface {
+	// RecordClientActivation records that client activated the behavior.
+	RecordClientActivation(ctx context.Context, behaviorID, client string) error
+
+	// GetClientStats returns learn and activation counts per client.
+	GetClientStats(ctx context.Context) ([]ClientStats, error)
+}
+
+// StateStore persists small named values between runs, such as the
+// deduplication watermark. State is a cache: it is not exported to JSONL, so
+// callers must treat a missing value as "start over". Implemented by
+// SQLiteGraphStore, MultiGraphStore, and InMemoryGraphStore. Used via type
+// assertion.
+type StateStore interface {
+	// GetState returns the value stored under key, or "" if there is none.
+	GetState(ctx context.Context, key string) (string, error)
+
+	// SetState stores value under key, replacing any previous value.
+	SetState(ctx context.Context, key, value string) error
+}
+
 // BehaviorEmbedding pairs a behavior ID with its embedding vector.
 type BehaviorEmbedding struct {
 	BehaviorID string
 	Embedding  []float32
+	Model      string // Model that produced the embedding, if recorded
 }
 
 // EmbeddingStore provides embedding vector persistence.
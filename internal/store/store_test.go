@@ -1,7 +1,10 @@
 package store
 
 import (
+	"context"
 	"errors"
+	"reflect"
+	"sort"
 	"testing"
 )
 
@@ -30,3 +33,69 @@ func TestDuplicateContentError_As(t *testing.T) {
 		t.Errorf("ExistingID = %q, want %q", dupErr.ExistingID, "bhv-789")
 	}
 }
+
+func TestQueryNodes_TagPredicate(t *testing.T) {
+	ctx := context.Background()
+	stores := map[string]func(t *testing.T) GraphStore{
+		"memory": func(t *testing.T) GraphStore { return NewInMemoryGraphStore() },
+		"sqlite": func(t *testing.T) GraphStore {
+			s, err := NewSQLiteGraphStore(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { s.Close() })
+			return s
+		},
+		"multi": func(t *testing.T) GraphStore { return newTestMultiStore(t) },
+	}
+
+	tagged := func(id string, tags ...interface{}) Node {
+		node := namedNode(id, id, NodeKindBehavior)
+		node.Content["content"].(map[string]interface{})["tags"] = tags
+		return node
+	}
+
+	tests := []struct {
+		tag  string
+		want []string
+	}{
+		{"testing", []string{"b-testing", "b-unit", "b-unit-fast"}},
+		{"testing/unit", []string{"b-unit", "b-unit-fast"}},
+		{"testing/unit/fast", []string{"b-unit-fast"}},
+		{"go", []string{"b-unit"}},
+		{"test", nil},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			s := newStore(t)
+			mustAddNode(t, s, ctx, tagged("b-testing", "testing"))
+			mustAddNode(t, s, ctx, tagged("b-unit", "go", "testing/unit"))
+			mustAddNode(t, s, ctx, tagged("b-unit-fast", "testing/unit/fast"))
+			mustAddNode(t, s, ctx, tagged("b-tools", "testing-tools", "testing0"))
+
+			// Retagging replaces the indexed tags
+			retagged := tagged("b-old", "testing")
+			mustAddNode(t, s, ctx, retagged)
+			retagged.Content["content"].(map[string]interface{})["tags"] = []interface{}{"docs"}
+			if err := s.UpdateNode(ctx, retagged); err != nil {
+				t.Fatalf("UpdateNode() error = %v", err)
+			}
+
+			for _, tt := range tests {
+				nodes, err := s.QueryNodes(ctx, map[string]interface{}{"tag": tt.tag})
+				if err != nil {
+					t.Fatalf("QueryNodes(%q) error = %v", tt.tag, err)
+				}
+				var got []string
+				for _, n := range nodes {
+					got = append(got, n.ID)
+				}
+				sort.Strings(got)
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("QueryNodes(tag %q) = %v, want %v", tt.tag, got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+)
+
+// SyncedFileNames lists the files, relative to a .floop directory, that hold
+// a SQLite store's durable state once it has synced: the node and edge JSONL
+// and, for a partitioned global store, its partition manifest. A database
+// rebuilt from them on open holds the same behaviors and edges.
+var SyncedFileNames = []string{
+	"nodes.jsonl",
+	"nodes.log.jsonl",
+	"edges.jsonl",
+	filepath.ToSlash(filepath.Join(PartitionsDir, PartitionManifestFile)),
+}
+
+// SyncedFiles is the durable state of a SQLite store as of a completed Sync.
+type SyncedFiles struct {
+	// Dir is the store's .floop directory, or a partition directory.
+	Dir string
+
+	// Files holds the content of each of SyncedFileNames that exists, by
+	// name.
+	Files map[string][]byte
+}
+
+// syncReporter receives the SyncedFiles of every SQLite store after each
+// Sync.
+var syncReporter atomic.Pointer[func(context.Context, SyncedFiles)]
+
+// SetSyncReporter makes fn receive the SyncedFiles of every SQLite store
+// after each successful Sync, e.g. to replicate them. fn runs in the
+// goroutine that called Sync, after the store's lock is released, so it may
+// take its time without blocking other callers. A nil fn stops reporting.
+func SetSyncReporter(fn func(context.Context, SyncedFiles)) {
+	if fn == nil {
+		syncReporter.Store(nil)
+		return
+	}
+	syncReporter.Store(&fn)
+}
+
+// readSyncedFiles reads the synced files of the store in floopDir. Caller
+// must hold the write lock, so the files are read as one consistent state.
+func readSyncedFiles(floopDir string) (SyncedFiles, error) {
+	synced := SyncedFiles{Dir: floopDir, Files: make(map[string][]byte)}
+	for _, name := range SyncedFileNames {
+		data, err := os.ReadFile(filepath.Join(floopDir, filepath.FromSlash(name)))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return SyncedFiles{}, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		synced.Files[name] = data
+	}
+	return synced, nil
+}
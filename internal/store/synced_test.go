@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSetSyncReporter(t *testing.T) {
+	var reports []SyncedFiles
+	SetSyncReporter(func(_ context.Context, synced SyncedFiles) {
+		reports = append(reports, synced)
+	})
+	t.Cleanup(func() { SetSyncReporter(nil) })
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, err := NewSQLiteGraphStore(dir)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	defer s.Close()
+
+	if _, err := s.AddNode(ctx, changelogNode("b-1", "wrap-errors", "Wrap errors with %w")); err != nil {
+		t.Fatalf("AddNode() error = %v", err)
+	}
+	reports = nil
+	if err := s.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	if len(reports) != 1 {
+		t.Fatalf("got %d reports, want 1", len(reports))
+	}
+	synced := reports[0]
+	if synced.Dir != LocalFloopPath(dir) {
+		t.Errorf("Dir = %q, want %q", synced.Dir, LocalFloopPath(dir))
+	}
+	if !strings.Contains(string(synced.Files["nodes.jsonl"]), "wrap-errors") {
+		t.Errorf("nodes.jsonl = %q, want the synced behavior", synced.Files["nodes.jsonl"])
+	}
+	if _, ok := synced.Files["partitions/manifest.json"]; ok {
+		t.Error("unpartitioned store reported a partition manifest")
+	}
+
+	SetSyncReporter(nil)
+	if err := s.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if len(reports) != 1 {
+		t.Errorf("got %d reports after SetSyncReporter(nil), want 1", len(reports))
+	}
+}
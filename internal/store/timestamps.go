@@ -0,0 +1,48 @@
+package store
+
+import (
+	"strings"
+	"time"
+)
+
+// formatTimestamp formats t for storage: RFC3339 in UTC. Stored timestamps
+// are compared as strings (ORDER BY, range queries, sync and merge
+// resolution), which is only correct when they share a zone.
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// normalizeTimestamp rewrites an RFC3339 timestamp in UTC, keeping any
+// fractional seconds. Values that are already UTC, empty, or not RFC3339
+// (such as SQLite's datetime('now'), which is UTC) are returned unchanged.
+func normalizeTimestamp(s string) string {
+	if s == "" || strings.HasSuffix(s, "Z") {
+		return s
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return s
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// normalizeMetadataTimestamps rewrites in place the top-level timestamps of
+// a metadata map (keys ending in "_at" or "_until", such as retired_at or
+// expires_at) as UTC strings.
+func normalizeMetadataTimestamps(m map[string]interface{}) {
+	for k, v := range m {
+		if !strings.HasSuffix(k, "_at") && !strings.HasSuffix(k, "_until") {
+			continue
+		}
+		switch t := v.(type) {
+		case string:
+			m[k] = normalizeTimestamp(t)
+		case time.Time:
+			m[k] = t.UTC().Format(time.RFC3339Nano)
+		case *time.Time:
+			if t != nil {
+				m[k] = t.UTC().Format(time.RFC3339Nano)
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata" // DST zones regardless of the host's zoneinfo
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("LoadLocation(%s) error = %v", name, err)
+	}
+	return loc
+}
+
+func TestNormalizeTimestamp(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"utc unchanged", "2026-03-08T07:30:00Z", "2026-03-08T07:30:00Z"},
+		{"negative offset", "2026-03-08T01:30:00-05:00", "2026-03-08T06:30:00Z"},
+		{"positive offset", "2026-03-29T03:30:00+02:00", "2026-03-29T01:30:00Z"},
+		{"fractional seconds kept", "2026-11-01T01:30:00.25-04:00", "2026-11-01T05:30:00.25Z"},
+		{"sqlite datetime unchanged", "2026-03-08 07:30:00", "2026-03-08 07:30:00"},
+		{"empty", "", ""},
+		{"not a time", "soon", "soon"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeTimestamp(tt.in); got != tt.want {
+				t.Errorf("normalizeTimestamp(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatTimestamp_DSTOrdering(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+
+	// Instants straddling DST transitions, listed in chronological order.
+	// 01:30 happens twice in New York on 2026-11-01 (EDT, then EST).
+	instants := []time.Time{
+		time.Date(2026, 3, 8, 1, 59, 0, 0, ny),               // EST, just before spring forward
+		time.Date(2026, 3, 8, 3, 0, 0, 0, ny),                // EDT, one minute later
+		time.Date(2026, 3, 29, 1, 59, 0, 0, berlin),          // CET, just before spring forward
+		time.Date(2026, 3, 29, 3, 0, 0, 0, berlin),           // CEST, one minute later
+		time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC).In(ny), // 01:30 EDT
+		time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC).In(ny), // 01:30 EST
+	}
+
+	formatted := make([]string, len(instants))
+	for i, inst := range instants {
+		formatted[i] = formatTimestamp(inst)
+		if !strings.HasSuffix(formatted[i], "Z") {
+			t.Errorf("formatTimestamp(%v) = %q, want UTC", inst, formatted[i])
+		}
+		parsed, err := time.Parse(time.RFC3339, formatted[i])
+		if err != nil || !parsed.Equal(inst) {
+			t.Errorf("round trip of %v = %v, %v", inst, parsed, err)
+		}
+	}
+	if !sort.StringsAreSorted(formatted) {
+		t.Errorf("formatted timestamps do not sort chronologically: %v", formatted)
+	}
+	if formatted[4] == formatted[5] {
+		t.Errorf("the repeated 01:30 hour collapsed to %q", formatted[4])
+	}
+}
+
+func TestSQLiteGraphStore_TimestampsStoredInUTC(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+	store, err := NewSQLiteGraphStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	// Second 01:30 on the New York fall-back day, written with its offset
+	created := time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC).In(ny)
+	activated := created.Add(time.Hour)
+	mustAddNode(t, store, ctx, Node{
+		ID:   "b-1",
+		Kind: NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":       "b-1",
+			"kind":       "directive",
+			"content":    map[string]interface{}{"canonical": "Wrap errors"},
+			"provenance": map[string]interface{}{"created_at": created.Format(time.RFC3339)},
+		},
+		Metadata: map[string]interface{}{
+			"stats":      map[string]interface{}{"last_activated": activated.Format(time.RFC3339)},
+			"retired_at": created.Format(time.RFC3339),
+		},
+	})
+	mustAddNode(t, store, ctx, Node{
+		ID:   "b-2",
+		Kind: NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    "b-2",
+			"kind":    "directive",
+			"content": map[string]interface{}{"canonical": "No panics"},
+		},
+	})
+	mustAddEdge(t, store, ctx, Edge{Source: "b-1", Target: "b-2", Kind: EdgeKindSimilarTo, Weight: 0.5, CreatedAt: created, LastActivated: &activated})
+
+	queries := map[string]string{
+		"behaviors.provenance_created_at": `SELECT provenance_created_at FROM behaviors WHERE id = 'b-1'`,
+		"behaviors.created_at":            `SELECT created_at FROM behaviors WHERE id = 'b-1'`,
+		"behavior_stats.last_activated":   `SELECT last_activated FROM behavior_stats WHERE behavior_id = 'b-1'`,
+		"edges.created_at":                `SELECT created_at FROM edges`,
+		"edges.last_activated":            `SELECT last_activated FROM edges`,
+	}
+	for name, q := range queries {
+		var v string
+		if err := store.db.QueryRowContext(ctx, q).Scan(&v); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !strings.HasSuffix(v, "Z") {
+			t.Errorf("%s = %q, want UTC", name, v)
+		}
+	}
+
+	node, err := store.GetNode(ctx, "b-1")
+	if err != nil || node == nil {
+		t.Fatalf("GetNode() = %v, %v", node, err)
+	}
+	if got := node.Metadata["retired_at"]; got != "2026-11-01T06:30:00Z" {
+		t.Errorf("retired_at = %v, want 2026-11-01T06:30:00Z", got)
+	}
+	provenance, _ := node.Content["provenance"].(map[string]interface{})
+	if got, ok := provenance["created_at"].(time.Time); !ok || !got.Equal(created) {
+		t.Errorf("provenance created_at = %v, want %v", provenance["created_at"], created)
+	}
+	edges, err := store.GetEdges(ctx, "b-1", DirectionOutbound, EdgeKindSimilarTo)
+	if err != nil || len(edges) != 1 {
+		t.Fatalf("GetEdges() = %v, %v", edges, err)
+	}
+	if !edges[0].CreatedAt.Equal(created) || edges[0].LastActivated == nil || !edges[0].LastActivated.Equal(activated) {
+		t.Errorf("edge timestamps = %v, %v; want %v, %v", edges[0].CreatedAt, edges[0].LastActivated, created, activated)
+	}
+}
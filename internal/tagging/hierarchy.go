@@ -0,0 +1,57 @@
+package tagging
+
+import (
+	"sort"
+	"strings"
+)
+
+// Separator divides the levels of a hierarchical tag such as "testing/unit".
+const Separator = "/"
+
+// Matches reports whether tag is want or sits below it in the hierarchy:
+// "testing/unit" matches "testing", but "testing" does not match
+// "testing/unit" and "testing-tools" does not match "testing".
+func Matches(tag, want string) bool {
+	return tag == want || strings.HasPrefix(tag, want+Separator)
+}
+
+// HasTag reports whether any of tags matches want.
+func HasTag(tags []string, want string) bool {
+	for _, t := range tags {
+		if Matches(t, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// Ancestors returns the levels above tag, outermost first: "testing/unit/fast"
+// has the ancestors "testing" and "testing/unit". A flat tag has none.
+func Ancestors(tag string) []string {
+	levels := strings.Split(tag, Separator)
+	ancestors := make([]string, 0, len(levels)-1)
+	for i := 1; i < len(levels); i++ {
+		ancestors = append(ancestors, strings.Join(levels[:i], Separator))
+	}
+	return ancestors
+}
+
+// Expand returns tags together with all their ancestors, sorted and
+// deduplicated, so ["testing/unit"] becomes ["testing", "testing/unit"].
+func Expand(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(tags))
+	var expanded []string
+	for _, t := range tags {
+		for _, tag := range append(Ancestors(t), t) {
+			if tag != "" && !seen[tag] {
+				seen[tag] = true
+				expanded = append(expanded, tag)
+			}
+		}
+	}
+	sort.Strings(expanded)
+	return expanded
+}
@@ -0,0 +1,63 @@
+package tagging
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		tag, want string
+		match     bool
+	}{
+		{"testing", "testing", true},
+		{"testing/unit", "testing", true},
+		{"testing/unit/fast", "testing/unit", true},
+		{"testing", "testing/unit", false},
+		{"testing-tools", "testing", false},
+		{"go", "testing", false},
+	}
+	for _, tt := range tests {
+		if got := Matches(tt.tag, tt.want); got != tt.match {
+			t.Errorf("Matches(%q, %q) = %v, want %v", tt.tag, tt.want, got, tt.match)
+		}
+	}
+	if !HasTag([]string{"go", "testing/unit"}, "testing") || HasTag([]string{"go"}, "testing") {
+		t.Error("HasTag() should match a tag below the wanted one and nothing else")
+	}
+}
+
+func TestExpand(t *testing.T) {
+	if got := Ancestors("testing/unit/fast"); !reflect.DeepEqual(got, []string{"testing", "testing/unit"}) {
+		t.Errorf("Ancestors() = %v", got)
+	}
+	if got := Ancestors("go"); len(got) != 0 {
+		t.Errorf("Ancestors(flat) = %v, want none", got)
+	}
+	got := Expand([]string{"testing/unit", "go", "testing/integration"})
+	want := []string{"go", "testing", "testing/integration", "testing/unit"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expand() = %v, want %v", got, want)
+	}
+	if Expand(nil) != nil {
+		t.Error("Expand(nil) should be nil")
+	}
+}
+
+func TestNormalizeTag(t *testing.T) {
+	dict := NewDictionary()
+	tests := []struct {
+		in, want string
+	}{
+		{" Testing/Unit ", "testing/unit"},
+		{"golang//testing/", "go/testing"},
+		{"/", ""},
+		{"", ""},
+		{"ci pipeline", "cipipeline"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeTag(tt.in, dict); got != tt.want {
+			t.Errorf("NormalizeTag(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -37,7 +37,7 @@ func MergeTags(inferred, extra []string, dict *Dictionary) []string {
 	var userTags []string
 
 	for _, t := range extra {
-		normalized := normalizeTag(t, dict)
+		normalized := NormalizeTag(t, dict)
 		if normalized == "" {
 			continue
 		}
@@ -73,20 +73,31 @@ func MergeTags(inferred, extra []string, dict *Dictionary) []string {
 	return result
 }
 
-// normalizeTag normalizes a single tag: trims whitespace, lowercases,
-// looks up in dictionary for canonical form, and sanitizes.
-func normalizeTag(tag string, dict *Dictionary) string {
+// NormalizeTag normalizes a single tag: trims whitespace, lowercases,
+// looks up each level in dictionary for canonical form, and sanitizes.
+// Empty levels of a hierarchical tag are dropped, so " Golang//Testing/ "
+// becomes "go/testing". Returns "" when nothing is left.
+func NormalizeTag(tag string, dict *Dictionary) string {
 	tag = strings.ToLower(strings.TrimSpace(tag))
 	if tag == "" {
 		return ""
 	}
 
-	// If the dictionary maps this to a canonical tag, use that
-	if dict != nil {
-		if canonical, ok := dict.Lookup(tag); ok {
-			tag = canonical
+	var levels []string
+	for _, level := range strings.Split(tag, Separator) {
+		level = strings.TrimSpace(level)
+		if level == "" {
+			continue
+		}
+		// If the dictionary maps this to a canonical tag, use that
+		if dict != nil {
+			if canonical, ok := dict.Lookup(level); ok {
+				level = canonical
+			}
+		}
+		if level = sanitize.SanitizeBehaviorName(level); level != "" {
+			levels = append(levels, level)
 		}
 	}
-
-	return sanitize.SanitizeBehaviorName(tag)
+	return strings.Join(levels, Separator)
 }
@@ -39,7 +39,8 @@ func DefaultActivationTierConfig() ActivationTierConfig {
 
 // ActivationTierMapper maps spreading activation results to injection tiers.
 type ActivationTierMapper struct {
-	config ActivationTierConfig
+	config  ActivationTierConfig
+	parents map[string]*models.Behavior
 }
 
 // NewActivationTierMapper creates a new mapper with the given configuration.
@@ -47,6 +48,16 @@ func NewActivationTierMapper(config ActivationTierConfig) *ActivationTierMapper
 	return &ActivationTierMapper{config: config}
 }
 
+// WithParents sets the generalized parents that the compiler renders once
+// above their full-tier children, so the mapper budgets for each header once.
+func (m *ActivationTierMapper) WithParents(parents []models.Behavior) *ActivationTierMapper {
+	m.parents = make(map[string]*models.Behavior, len(parents))
+	for i := range parents {
+		m.parents[parents[i].ID] = &parents[i]
+	}
+	return m
+}
+
 // MapTier returns the appropriate tier for a given activation level and behavior kind.
 func (m *ActivationTierMapper) MapTier(activation float64, kind models.BehaviorKind) models.InjectionTier {
 	tier := models.TierOmitted
@@ -112,7 +123,8 @@ func (m *ActivationTierMapper) MapResults(
 	}
 
 	// Step 2: Check total tokens against budget and demote if necessary.
-	totalTokens := sumTokens(entries)
+	// Group headers are counted once per parent, on top of their children.
+	totalTokens := sumTokens(entries) + sumHeaderTokens(m.groupHeaders(entries))
 	if totalTokens > tokenBudget {
 		// Sort by activation ascending so we demote lowest first.
 		sort.SliceStable(entries, func(i, j int) bool {
@@ -132,10 +144,10 @@ func (m *ActivationTierMapper) MapResults(
 				}
 				// Demote one level.
 				newTier := entries[i].tier + 1
-				newTokens := estimateTokensForTier(entries[i].behavior, newTier)
-				totalTokens -= entries[i].tokens - newTokens
 				entries[i].tier = newTier
-				entries[i].tokens = newTokens
+				entries[i].tokens = estimateTokensForTier(entries[i].behavior, newTier)
+				// Demoting a child can drop its parent's header
+				totalTokens = sumTokens(entries) + sumHeaderTokens(m.groupHeaders(entries))
 				demoted = true
 				if totalTokens <= tokenBudget {
 					break
@@ -168,11 +180,62 @@ func (m *ActivationTierMapper) MapResults(
 		}
 	}
 
+	plan.GroupHeaders = m.groupHeaders(entries)
 	plan.TotalTokens = sumPlanTokens(plan)
 
 	return plan
 }
 
+// groupHeaders returns a full-tier header for each known parent with at
+// least two full-tier children, ordered by parent ID.
+func (m *ActivationTierMapper) groupHeaders(entries []tierEntry) []models.InjectedBehavior {
+	if len(m.parents) == 0 {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	for _, e := range entries {
+		if e.tier != models.TierFull {
+			continue
+		}
+		for _, id := range e.behavior.Specializes {
+			if _, ok := m.parents[id]; ok {
+				counts[id]++
+				break
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(counts))
+	for id, n := range counts {
+		if n >= 2 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	headers := make([]models.InjectedBehavior, 0, len(ids))
+	for _, id := range ids {
+		p := m.parents[id]
+		headers = append(headers, models.InjectedBehavior{
+			Behavior:  p,
+			Tier:      models.TierFull,
+			Content:   p.Content.Canonical,
+			TokenCost: estimateTokensForTier(p, models.TierFull),
+		})
+	}
+	return headers
+}
+
+// sumHeaderTokens returns the total token cost of group headers.
+func sumHeaderTokens(headers []models.InjectedBehavior) int {
+	total := 0
+	for _, h := range headers {
+		total += h.TokenCost
+	}
+	return total
+}
+
 // estimateTokensForTier estimates the token cost for a behavior at a given tier.
 func estimateTokensForTier(b *models.Behavior, tier models.InjectionTier) int {
 	content := contentForTier(b, tier)
@@ -245,5 +308,5 @@ func sumPlanTokens(plan *models.InjectionPlan) int {
 	for _, ib := range plan.NameOnlyBehaviors {
 		total += ib.TokenCost
 	}
-	return total
+	return total + sumHeaderTokens(plan.GroupHeaders)
 }
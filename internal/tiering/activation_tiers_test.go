@@ -463,3 +463,55 @@ func TestContentForTier_SummaryFallback(t *testing.T) {
 		t.Errorf("unexpected fallback truncation: %q", got)
 	}
 }
+
+func TestActivationTierMapper_MapResults_GroupHeaders(t *testing.T) {
+	parent := models.Behavior{
+		ID: "g1", Name: "general", Kind: models.BehaviorKindPreference,
+		Content: models.BehaviorContent{Canonical: "Prefer the maintained library for every dependency"},
+	}
+	behaviors := map[string]*models.Behavior{
+		"c1": {ID: "c1", Kind: models.BehaviorKindPreference, Specializes: []string{"g1"},
+			Content: models.BehaviorContent{Canonical: "use httpx not requests"}},
+		"c2": {ID: "c2", Kind: models.BehaviorKindPreference, Specializes: []string{"g1"},
+			Content: models.BehaviorContent{Canonical: "use pathlib not os.path"}},
+	}
+	results := []spreading.Result{
+		{BehaviorID: "c1", Activation: 0.9},
+		{BehaviorID: "c2", Activation: 0.8},
+	}
+
+	t.Run("header counted once", func(t *testing.T) {
+		mapper := NewActivationTierMapper(DefaultActivationTierConfig()).WithParents([]models.Behavior{parent})
+		plan := mapper.MapResults(results, behaviors, 10000)
+
+		if len(plan.GroupHeaders) != 1 || plan.GroupHeaders[0].Behavior.ID != "g1" {
+			t.Fatalf("GroupHeaders = %+v, want [g1]", plan.GroupHeaders)
+		}
+		want := plan.GroupHeaders[0].TokenCost
+		for _, ib := range plan.FullBehaviors {
+			want += ib.TokenCost
+		}
+		if plan.TotalTokens != want {
+			t.Errorf("TotalTokens = %d, want %d", plan.TotalTokens, want)
+		}
+	})
+
+	t.Run("no parents means no headers", func(t *testing.T) {
+		plan := NewActivationTierMapper(DefaultActivationTierConfig()).MapResults(results, behaviors, 10000)
+		if len(plan.GroupHeaders) != 0 {
+			t.Errorf("GroupHeaders = %+v, want none", plan.GroupHeaders)
+		}
+	})
+
+	t.Run("demotion drops header", func(t *testing.T) {
+		mapper := NewActivationTierMapper(DefaultActivationTierConfig()).WithParents([]models.Behavior{parent})
+		plan := mapper.MapResults(results, behaviors, 10)
+
+		if len(plan.FullBehaviors) < 2 && len(plan.GroupHeaders) != 0 {
+			t.Errorf("header kept with %d full-tier children", len(plan.FullBehaviors))
+		}
+		if plan.TotalTokens > 10 && len(plan.FullBehaviors) > 0 {
+			t.Errorf("TotalTokens = %d exceeds budget with full-tier behaviors left", plan.TotalTokens)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package tiering
+
+import (
+	"context"
+	"sort"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/ranking"
+	"github.com/nvandessel/floop/internal/spreading"
+)
+
+// ApplyExternalScores sends the candidates in results to scorer and merges
+// its adjustments into their activation levels, returning a copy sorted by
+// activation descending. Results without a behavior in behaviors are passed
+// through. When scorer is nil or fails, results are returned unchanged along
+// with the error, so callers fall back to built-in ranking.
+func ApplyExternalScores(
+	ctx context.Context,
+	scorer *ranking.ExternalScorer,
+	actCtx *models.ContextSnapshot,
+	results []spreading.Result,
+	behaviors map[string]*models.Behavior,
+) ([]spreading.Result, error) {
+	if scorer == nil || len(results) == 0 {
+		return results, nil
+	}
+
+	req := ranking.ExternalRequest{Context: actCtx, Candidates: make([]ranking.ExternalCandidate, 0, len(results))}
+	for _, r := range results {
+		b, ok := behaviors[r.BehaviorID]
+		if !ok || b == nil {
+			continue
+		}
+		req.Candidates = append(req.Candidates, ranking.ExternalCandidate{
+			BehaviorID: b.ID,
+			Name:       b.Name,
+			Kind:       string(b.Kind),
+			Canonical:  b.Content.Canonical,
+			Tags:       b.Content.Tags,
+			Priority:   b.Priority,
+			Confidence: b.Confidence,
+			Score:      r.Activation,
+		})
+	}
+
+	adjustments, err := scorer.Adjust(ctx, req)
+	if err != nil {
+		return results, err
+	}
+
+	adjusted := make([]spreading.Result, len(results))
+	copy(adjusted, results)
+	for i := range adjusted {
+		if adj, ok := adjustments[adjusted[i].BehaviorID]; ok {
+			adjusted[i].Activation = ranking.ApplyAdjustment(adjusted[i].Activation, adj)
+		}
+	}
+	sort.SliceStable(adjusted, func(i, j int) bool {
+		return adjusted[i].Activation > adjusted[j].Activation
+	})
+	return adjusted, nil
+}
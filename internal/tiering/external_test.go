@@ -0,0 +1,66 @@
+package tiering
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/ranking"
+	"github.com/nvandessel/floop/internal/spreading"
+)
+
+func TestApplyExternalScores(t *testing.T) {
+	behaviors := map[string]*models.Behavior{
+		"a": {ID: "a", Name: "a", Kind: models.BehaviorKindDirective},
+		"b": {ID: "b", Name: "b", Kind: models.BehaviorKindDirective},
+	}
+	results := []spreading.Result{
+		{BehaviorID: "a", Activation: 0.8},
+		{BehaviorID: "b", Activation: 0.5},
+	}
+
+	t.Run("nil scorer passes through", func(t *testing.T) {
+		got, err := ApplyExternalScores(context.Background(), nil, nil, results, behaviors)
+		if err != nil || len(got) != 2 || got[0].BehaviorID != "a" {
+			t.Errorf("got %v, %v", got, err)
+		}
+	})
+
+	t.Run("adjustments re-rank", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"adjustments": {"a": -0.4, "b": 0.3}}`))
+		}))
+		defer ts.Close()
+		scorer := ranking.NewExternalScorer(ranking.ExternalScorerConfig{URL: ts.URL, Timeout: time.Second})
+
+		got, err := ApplyExternalScores(context.Background(), scorer, &models.ContextSnapshot{}, results, behaviors)
+		if err != nil {
+			t.Fatalf("ApplyExternalScores() error = %v", err)
+		}
+		if got[0].BehaviorID != "b" || got[0].Activation != 0.8 {
+			t.Errorf("got %v, want b first at 0.8", got)
+		}
+		if results[0].Activation != 0.8 {
+			t.Error("input results were modified")
+		}
+	})
+
+	t.Run("scorer failure keeps built-in ranking", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "boom", http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+		scorer := ranking.NewExternalScorer(ranking.ExternalScorerConfig{URL: ts.URL, Timeout: time.Second})
+
+		got, err := ApplyExternalScores(context.Background(), scorer, nil, results, behaviors)
+		if err == nil {
+			t.Error("expected the scorer error")
+		}
+		if len(got) != 2 || got[0].BehaviorID != "a" || got[0].Activation != 0.8 {
+			t.Errorf("got %v, want unchanged results", got)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package tiering
+
+import (
+	"sort"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/ranking"
+	"github.com/nvandessel/floop/internal/spreading"
+)
+
+// ApplyTemporalSignals scales the activation of results whose behaviors
+// carry temporal tags by how well they fit actCtx's time of day, day of
+// week, and workload (see ranking.TemporalScore), returning a copy sorted by
+// activation descending. Activations stay within [0, 1]. A zero weight
+// returns results unchanged.
+func ApplyTemporalSignals(
+	actCtx *models.ContextSnapshot,
+	results []spreading.Result,
+	behaviors map[string]*models.Behavior,
+	weight float64,
+) []spreading.Result {
+	if weight <= 0 || actCtx == nil || len(results) == 0 {
+		return results
+	}
+
+	adjusted := make([]spreading.Result, len(results))
+	copy(adjusted, results)
+	changed := false
+	for i := range adjusted {
+		temporal, ok := ranking.TemporalScore(behaviors[adjusted[i].BehaviorID], actCtx)
+		if !ok {
+			continue
+		}
+		adjusted[i].Activation = min(1, ranking.ApplyTemporal(adjusted[i].Activation, temporal, weight))
+		changed = true
+	}
+	if !changed {
+		return results
+	}
+	sort.SliceStable(adjusted, func(i, j int) bool {
+		return adjusted[i].Activation > adjusted[j].Activation
+	})
+	return adjusted
+}
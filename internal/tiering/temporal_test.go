@@ -0,0 +1,43 @@
+package tiering
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/spreading"
+)
+
+func TestApplyTemporalSignals(t *testing.T) {
+	actCtx := &models.ContextSnapshot{Timestamp: time.Date(2026, 10, 14, 18, 0, 0, 0, time.UTC)}
+	behaviors := map[string]*models.Behavior{
+		"morning": {ID: "morning", Content: models.BehaviorContent{Tags: []string{"time/morning"}}},
+		"evening": {ID: "evening", Content: models.BehaviorContent{Tags: []string{"time/evening"}}},
+		"plain":   {ID: "plain"},
+	}
+	results := []spreading.Result{
+		{BehaviorID: "morning", Activation: 0.9},
+		{BehaviorID: "plain", Activation: 0.8},
+		{BehaviorID: "evening", Activation: 0.7},
+		{BehaviorID: "unknown", Activation: 0.6},
+	}
+
+	if got := ApplyTemporalSignals(actCtx, results, behaviors, 0); &got[0] != &results[0] {
+		t.Error("zero weight should return results unchanged")
+	}
+
+	got := ApplyTemporalSignals(actCtx, results, behaviors, 0.5)
+	want := map[string]float64{"evening": 1, "morning": 0.45, "plain": 0.8, "unknown": 0.6}
+	order := []string{"evening", "plain", "unknown", "morning"}
+	for i, r := range got {
+		if r.BehaviorID != order[i] {
+			t.Errorf("got[%d] = %s, want %s", i, r.BehaviorID, order[i])
+		}
+		if diff := r.Activation - want[r.BehaviorID]; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("%s activation = %f, want %f", r.BehaviorID, r.Activation, want[r.BehaviorID])
+		}
+	}
+	if results[0].Activation != 0.9 {
+		t.Error("input results were modified")
+	}
+}
@@ -0,0 +1,493 @@
+package vectorindex
+
+import (
+	"container/heap"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/nvandessel/floop/internal/vecmath"
+)
+
+// hnswFormatVersion is bumped when the persisted layout changes; files with
+// another version are rejected so the caller can rebuild.
+const hnswFormatVersion = 1
+
+// HNSWConfig configures an HNSWIndex. Zero values use the defaults.
+type HNSWConfig struct {
+	// Path is the file the index is persisted to. Empty keeps it in memory.
+	Path string
+
+	// M is the number of neighbors kept per node on each layer (default 16).
+	M int
+
+	// EfConstruction is the candidate list size when inserting (default 200).
+	EfConstruction int
+
+	// EfSearch is the minimum candidate list size when searching (default 64).
+	EfSearch int
+}
+
+// hnswNode is one vector in the graph. Removed vectors stay as tombstones so
+// the graph remains navigable, and are dropped when the index is compacted.
+type hnswNode struct {
+	ID      string
+	Vector  []float32 // L2-normalized
+	Links   [][]int32 // neighbor node indexes, per layer
+	Deleted bool
+}
+
+// hnswFile is the gob-encoded on-disk form of the index.
+type hnswFile struct {
+	Version        int
+	M              int
+	EfConstruction int
+	Entry          int
+	MaxLevel       int
+	Nodes          []hnswNode
+}
+
+// HNSWIndex is a pure-Go Hierarchical Navigable Small World graph for
+// approximate nearest neighbor search by cosine similarity. Thread-safe.
+// Unlike BruteForceIndex it persists to disk, so a restart only needs to
+// apply the embeddings that changed since the last Save (see Reconcile).
+type HNSWIndex struct {
+	mu       sync.RWMutex
+	cfg      HNSWConfig
+	nodes    []hnswNode
+	ids      map[string]int32 // live behavior ID → node index
+	entry    int32            // -1 when the graph is empty
+	maxLevel int
+	deleted  int
+	dirty    bool
+	rng      *rand.Rand
+}
+
+// NewHNSWIndex creates an HNSWIndex, loading it from cfg.Path if that file
+// exists. A file that cannot be read is an error; callers can remove it and
+// start over, since the index is derived from stored embeddings.
+func NewHNSWIndex(cfg HNSWConfig) (*HNSWIndex, error) {
+	if cfg.M <= 0 {
+		cfg.M = 16
+	}
+	if cfg.EfConstruction <= 0 {
+		cfg.EfConstruction = 200
+	}
+	if cfg.EfSearch <= 0 {
+		cfg.EfSearch = 64
+	}
+	h := &HNSWIndex{
+		cfg:   cfg,
+		ids:   make(map[string]int32),
+		entry: -1,
+		rng:   rand.New(rand.NewSource(1)),
+	}
+	if cfg.Path == "" {
+		return h, nil
+	}
+
+	f, err := os.Open(cfg.Path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open hnsw index: %w", err)
+	}
+	defer f.Close()
+
+	var data hnswFile
+	if err := gob.NewDecoder(f).Decode(&data); err != nil {
+		return nil, fmt.Errorf("decode hnsw index %s: %w", cfg.Path, err)
+	}
+	if data.Version != hnswFormatVersion {
+		return nil, fmt.Errorf("hnsw index %s has format version %d, want %d", cfg.Path, data.Version, hnswFormatVersion)
+	}
+	h.cfg.M, h.cfg.EfConstruction = data.M, data.EfConstruction
+	h.nodes, h.entry, h.maxLevel = data.Nodes, int32(data.Entry), data.MaxLevel
+	for i, n := range h.nodes {
+		if n.Deleted {
+			h.deleted++
+		} else {
+			h.ids[n.ID] = int32(i)
+		}
+	}
+	return h, nil
+}
+
+// Add inserts or replaces the vector for the given behavior ID.
+func (h *HNSWIndex) Add(_ context.Context, behaviorID string, vector []float32) error {
+	if len(vector) == 0 {
+		return fmt.Errorf("empty vector for %s", behaviorID)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.entry >= 0 {
+		if dims := len(h.nodes[h.entry].Vector); dims != len(vector) {
+			return fmt.Errorf("vector for %s has %d dims, index has %d", behaviorID, len(vector), dims)
+		}
+	}
+	h.add(behaviorID, normalized(vector))
+	return nil
+}
+
+// Remove deletes the vector for the given behavior ID. No-op if not found.
+func (h *HNSWIndex) Remove(_ context.Context, behaviorID string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(behaviorID)
+	return nil
+}
+
+// Reconcile makes the index hold exactly vectors: IDs that are missing or
+// whose vector changed are (re)inserted, and IDs not in vectors are removed.
+// Unchanged vectors are left in place, so reconciling a persisted index
+// against the embedding store only pays for what changed. It returns the
+// number of vectors added and removed.
+func (h *HNSWIndex) Reconcile(_ context.Context, vectors map[string][]float32) (added, removed int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var stale []string
+	for id := range h.ids {
+		if _, ok := vectors[id]; !ok {
+			stale = append(stale, id)
+		}
+	}
+	for _, id := range stale {
+		h.remove(id)
+	}
+	removed = len(stale)
+
+	// Insert in ID order so rebuilds produce the same graph
+	ids := make([]string, 0, len(vectors))
+	for id := range vectors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	// A different embedding model changes the dimensions: start over
+	if h.entry >= 0 {
+		for _, id := range ids {
+			if len(vectors[id]) > 0 {
+				if len(vectors[id]) != len(h.nodes[h.entry].Vector) {
+					removed += len(h.ids)
+					h.nodes, h.ids = nil, make(map[string]int32)
+					h.entry, h.maxLevel, h.deleted = -1, 0, 0
+					h.dirty = true
+				}
+				break
+			}
+		}
+	}
+
+	for _, id := range ids {
+		vec := vectors[id]
+		if len(vec) == 0 {
+			continue
+		}
+		if h.entry >= 0 && len(h.nodes[h.entry].Vector) != len(vec) {
+			continue
+		}
+		norm := normalized(vec)
+		if i, ok := h.ids[id]; ok && equalVectors(h.nodes[i].Vector, norm) {
+			continue
+		}
+		h.add(id, norm)
+		added++
+	}
+	return added, removed
+}
+
+// Search returns the topK most similar vectors to query, sorted by descending score.
+func (h *HNSWIndex) Search(_ context.Context, query []float32, topK int) ([]SearchResult, error) {
+	if len(query) == 0 || topK <= 0 {
+		return nil, nil
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if len(h.ids) == 0 {
+		return nil, nil
+	}
+	if dims := len(h.nodes[h.entry].Vector); dims != len(query) {
+		return nil, fmt.Errorf("query has %d dims, index has %d", len(query), dims)
+	}
+
+	q := normalized(query)
+	ep := []hnswCandidate{{id: h.entry, score: dot(q, h.nodes[h.entry].Vector)}}
+	for l := h.maxLevel; l > 0; l-- {
+		ep = h.searchLayer(q, ep, 1, l)
+	}
+	ef := h.cfg.EfSearch
+	if 2*topK > ef {
+		ef = 2 * topK
+	}
+
+	results := make([]SearchResult, 0, topK)
+	for _, c := range h.searchLayer(q, ep, ef, 0) {
+		n := h.nodes[c.id]
+		if n.Deleted {
+			continue
+		}
+		results = append(results, SearchResult{BehaviorID: n.ID, Score: c.score})
+		if len(results) == topK {
+			break
+		}
+	}
+	return results, nil
+}
+
+// Len returns the number of vectors in the index.
+func (h *HNSWIndex) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.ids)
+}
+
+// Save writes the index to its path if it changed since the last save.
+// It is a no-op for in-memory indexes.
+func (h *HNSWIndex) Save(_ context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.cfg.Path == "" || !h.dirty {
+		return nil
+	}
+
+	// Write to a temp file and rename, so a crash never leaves a torn index
+	tmp, err := os.CreateTemp(filepath.Dir(h.cfg.Path), filepath.Base(h.cfg.Path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("create hnsw index: %w", err)
+	}
+	data := hnswFile{
+		Version:        hnswFormatVersion,
+		M:              h.cfg.M,
+		EfConstruction: h.cfg.EfConstruction,
+		Entry:          int(h.entry),
+		MaxLevel:       h.maxLevel,
+		Nodes:          h.nodes,
+	}
+	if err := gob.NewEncoder(tmp).Encode(&data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("encode hnsw index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write hnsw index: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), h.cfg.Path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("replace hnsw index: %w", err)
+	}
+	h.dirty = false
+	return nil
+}
+
+// Close saves the index.
+func (h *HNSWIndex) Close() error {
+	return h.Save(context.Background())
+}
+
+// add inserts a normalized vector, replacing any live vector for id.
+// Caller must hold h.mu.
+func (h *HNSWIndex) add(id string, vec []float32) {
+	if _, ok := h.ids[id]; ok {
+		h.remove(id)
+	}
+	h.dirty = true
+
+	level := int(math.Floor(-math.Log(1-h.rng.Float64()) / math.Log(float64(h.cfg.M))))
+	n := int32(len(h.nodes))
+	h.nodes = append(h.nodes, hnswNode{ID: id, Vector: vec, Links: make([][]int32, level+1)})
+	h.ids[id] = n
+	if h.entry < 0 {
+		h.entry, h.maxLevel = n, level
+		return
+	}
+
+	ep := []hnswCandidate{{id: h.entry, score: dot(vec, h.nodes[h.entry].Vector)}}
+	for l := h.maxLevel; l > level; l-- {
+		ep = h.searchLayer(vec, ep, 1, l)
+	}
+	for l := min(level, h.maxLevel); l >= 0; l-- {
+		w := h.searchLayer(vec, ep, h.cfg.EfConstruction, l)
+		maxLinks := h.cfg.M
+		if l == 0 {
+			maxLinks = 2 * h.cfg.M
+		}
+		for _, c := range w[:min(h.cfg.M, len(w))] {
+			h.nodes[n].Links[l] = append(h.nodes[n].Links[l], c.id)
+			h.nodes[c.id].Links[l] = append(h.nodes[c.id].Links[l], n)
+			if len(h.nodes[c.id].Links[l]) > maxLinks {
+				h.prune(c.id, l, maxLinks)
+			}
+		}
+		ep = w
+	}
+	if level > h.maxLevel {
+		h.entry, h.maxLevel = n, level
+	}
+}
+
+// remove tombstones the live vector for id, compacting the graph once
+// tombstones outnumber live vectors. Caller must hold h.mu.
+func (h *HNSWIndex) remove(id string) {
+	i, ok := h.ids[id]
+	if !ok {
+		return
+	}
+	h.nodes[i].Deleted = true
+	delete(h.ids, id)
+	h.deleted++
+	h.dirty = true
+	if h.deleted > len(h.ids) {
+		h.compact()
+	}
+}
+
+// compact rebuilds the graph from the live vectors, dropping tombstones.
+// Caller must hold h.mu.
+func (h *HNSWIndex) compact() {
+	live := make([]hnswNode, 0, len(h.ids))
+	for _, n := range h.nodes {
+		if !n.Deleted {
+			live = append(live, n)
+		}
+	}
+	sort.Slice(live, func(i, j int) bool { return live[i].ID < live[j].ID })
+
+	h.nodes, h.ids = nil, make(map[string]int32, len(live))
+	h.entry, h.maxLevel, h.deleted = -1, 0, 0
+	for _, n := range live {
+		h.add(n.ID, n.Vector)
+	}
+	h.dirty = true
+}
+
+// prune keeps the maxLinks closest neighbors of node i on layer l.
+// Caller must hold h.mu.
+func (h *HNSWIndex) prune(i int32, l, maxLinks int) {
+	vec := h.nodes[i].Vector
+	links := h.nodes[i].Links[l]
+	sort.Slice(links, func(a, b int) bool {
+		return dot(vec, h.nodes[links[a]].Vector) > dot(vec, h.nodes[links[b]].Vector)
+	})
+	h.nodes[i].Links[l] = links[:maxLinks]
+}
+
+// searchLayer runs a best-first search of layer l from the entry points,
+// returning up to ef nearest nodes (tombstones included) by descending score.
+func (h *HNSWIndex) searchLayer(q []float32, entry []hnswCandidate, ef, l int) []hnswCandidate {
+	visited := make(map[int32]bool, ef*4)
+	candidates := &hnswMaxHeap{}
+	results := &hnswMinHeap{}
+	for _, c := range entry {
+		visited[c.id] = true
+		heap.Push(candidates, c)
+		heap.Push(results, c)
+	}
+	for results.Len() > ef {
+		heap.Pop(results)
+	}
+
+	for candidates.Len() > 0 {
+		c := heap.Pop(candidates).(hnswCandidate)
+		if results.Len() >= ef && c.score < (*results)[0].score {
+			break
+		}
+		links := h.nodes[c.id].Links
+		if l >= len(links) {
+			continue
+		}
+		for _, nb := range links[l] {
+			if visited[nb] {
+				continue
+			}
+			visited[nb] = true
+			s := dot(q, h.nodes[nb].Vector)
+			if results.Len() < ef || s > (*results)[0].score {
+				heap.Push(candidates, hnswCandidate{id: nb, score: s})
+				heap.Push(results, hnswCandidate{id: nb, score: s})
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+
+	out := make([]hnswCandidate, results.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = heap.Pop(results).(hnswCandidate)
+	}
+	return out
+}
+
+// hnswCandidate is a node index with its similarity to the query.
+type hnswCandidate struct {
+	id    int32
+	score float64
+}
+
+// hnswMaxHeap pops the most similar candidate first.
+type hnswMaxHeap []hnswCandidate
+
+func (h hnswMaxHeap) Len() int            { return len(h) }
+func (h hnswMaxHeap) Less(i, j int) bool  { return h[i].score > h[j].score }
+func (h hnswMaxHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *hnswMaxHeap) Push(x interface{}) { *h = append(*h, x.(hnswCandidate)) }
+func (h *hnswMaxHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
+
+// hnswMinHeap pops the least similar candidate first.
+type hnswMinHeap []hnswCandidate
+
+func (h hnswMinHeap) Len() int            { return len(h) }
+func (h hnswMinHeap) Less(i, j int) bool  { return h[i].score < h[j].score }
+func (h hnswMinHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *hnswMinHeap) Push(x interface{}) { *h = append(*h, x.(hnswCandidate)) }
+func (h *hnswMinHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
+
+// normalized returns an L2-normalized copy of vec.
+func normalized(vec []float32) []float32 {
+	cp := make([]float32, len(vec))
+	copy(cp, vec)
+	vecmath.Normalize(cp)
+	return cp
+}
+
+// dot is the cosine similarity of two normalized vectors.
+func dot(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+func equalVectors(a, b []float32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
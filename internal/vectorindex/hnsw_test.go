@@ -0,0 +1,258 @@
+package vectorindex
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func newTestHNSW(t *testing.T, path string) *HNSWIndex {
+	t.Helper()
+	idx, err := NewHNSWIndex(HNSWConfig{Path: path})
+	if err != nil {
+		t.Fatalf("NewHNSWIndex() error = %v", err)
+	}
+	return idx
+}
+
+func randomVectors(n, dims int) map[string][]float32 {
+	rng := rand.New(rand.NewSource(42))
+	vectors := make(map[string][]float32, n)
+	for i := 0; i < n; i++ {
+		vec := make([]float32, dims)
+		for d := range vec {
+			vec[d] = float32(rng.NormFloat64())
+		}
+		vectors["b"+strconv.Itoa(i)] = vec
+	}
+	return vectors
+}
+
+func TestHNSWIndex_AddAndSearch(t *testing.T) {
+	idx := newTestHNSW(t, "")
+	ctx := context.Background()
+
+	mustAdd(t, idx, ctx, "b1", []float32{1, 0, 0})
+	mustAdd(t, idx, ctx, "b2", []float32{0, 1, 0})
+	mustAdd(t, idx, ctx, "b3", []float32{0.9, 0.1, 0})
+
+	results := mustSearch(t, idx, ctx, []float32{1, 0, 0}, 2)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].BehaviorID != "b1" || results[1].BehaviorID != "b3" {
+		t.Errorf("expected [b1 b3], got [%s %s]", results[0].BehaviorID, results[1].BehaviorID)
+	}
+	if results[0].Score < 0.99 {
+		t.Errorf("expected score ~1.0 for exact match, got %f", results[0].Score)
+	}
+}
+
+func TestHNSWIndex_ReplaceAndRemove(t *testing.T) {
+	idx := newTestHNSW(t, "")
+	ctx := context.Background()
+
+	mustAdd(t, idx, ctx, "b1", []float32{1, 0})
+	mustAdd(t, idx, ctx, "b2", []float32{0, 1})
+	mustAdd(t, idx, ctx, "b1", []float32{0, 1}) // replace
+
+	if idx.Len() != 2 {
+		t.Errorf("expected Len()=2 after replace, got %d", idx.Len())
+	}
+	if err := idx.Remove(ctx, "b2"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := idx.Remove(ctx, "nonexistent"); err != nil {
+		t.Errorf("expected nil error for removing nonexistent, got %v", err)
+	}
+
+	results := mustSearch(t, idx, ctx, []float32{0, 1}, 5)
+	if len(results) != 1 || results[0].BehaviorID != "b1" || results[0].Score < 0.99 {
+		t.Errorf("expected only the replaced b1, got %+v", results)
+	}
+}
+
+func TestHNSWIndex_DimensionMismatch(t *testing.T) {
+	idx := newTestHNSW(t, "")
+	ctx := context.Background()
+	mustAdd(t, idx, ctx, "b1", []float32{1, 0})
+
+	if err := idx.Add(ctx, "b2", []float32{1, 0, 0}); err == nil {
+		t.Error("expected an error adding a vector with different dims")
+	}
+	if _, err := idx.Search(ctx, []float32{1, 0, 0}, 1); err == nil {
+		t.Error("expected an error searching with different dims")
+	}
+}
+
+func TestHNSWIndex_Recall(t *testing.T) {
+	ctx := context.Background()
+	vectors := randomVectors(500, 32)
+	hnsw := newTestHNSW(t, "")
+	bf := NewBruteForceIndex()
+	for id, vec := range vectors {
+		mustAdd(t, hnsw, ctx, id, vec)
+		mustAdd(t, bf, ctx, id, vec)
+	}
+
+	queries := randomVectors(20, 32)
+	var hits, total int
+	for _, q := range queries {
+		want := make(map[string]bool)
+		for _, r := range mustSearch(t, bf, ctx, q, 10) {
+			want[r.BehaviorID] = true
+		}
+		for _, r := range mustSearch(t, hnsw, ctx, q, 10) {
+			if want[r.BehaviorID] {
+				hits++
+			}
+		}
+		total += len(want)
+	}
+	if recall := float64(hits) / float64(total); recall < 0.9 {
+		t.Errorf("recall@10 = %.2f, want >= 0.9", recall)
+	}
+}
+
+func TestHNSWIndex_Compaction(t *testing.T) {
+	idx := newTestHNSW(t, "")
+	ctx := context.Background()
+	vectors := randomVectors(50, 8)
+	for id, vec := range vectors {
+		mustAdd(t, idx, ctx, id, vec)
+	}
+	for i := 0; i < 40; i++ {
+		if err := idx.Remove(ctx, "b"+strconv.Itoa(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if idx.Len() != 10 {
+		t.Errorf("expected Len()=10, got %d", idx.Len())
+	}
+	if len(idx.nodes) > 2*idx.Len() {
+		t.Errorf("expected tombstones to be compacted, graph has %d nodes for %d vectors", len(idx.nodes), idx.Len())
+	}
+	results := mustSearch(t, idx, ctx, vectors["b45"], 10)
+	if len(results) != 10 || results[0].BehaviorID != "b45" {
+		t.Errorf("expected all 10 live vectors with b45 first, got %+v", results)
+	}
+}
+
+func TestHNSWIndex_SaveAndLoad(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "hnsw.gob")
+	vectors := randomVectors(100, 16)
+
+	idx := newTestHNSW(t, path)
+	for id, vec := range vectors {
+		mustAdd(t, idx, ctx, id, vec)
+	}
+	want := mustSearch(t, idx, ctx, vectors["b7"], 5)
+	if err := idx.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	loaded := newTestHNSW(t, path)
+	if loaded.Len() != 100 {
+		t.Fatalf("expected Len()=100 after load, got %d", loaded.Len())
+	}
+	got := mustSearch(t, loaded, ctx, vectors["b7"], 5)
+	for i := range want {
+		if got[i].BehaviorID != want[i].BehaviorID {
+			t.Errorf("result %d = %s after load, want %s", i, got[i].BehaviorID, want[i].BehaviorID)
+		}
+	}
+}
+
+func TestHNSWIndex_LoadCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hnsw.gob")
+	if err := os.WriteFile(path, []byte("not an index"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewHNSWIndex(HNSWConfig{Path: path}); err == nil {
+		t.Error("expected an error loading a corrupt index")
+	}
+}
+
+func TestHNSWIndex_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	idx := newTestHNSW(t, "")
+	mustAdd(t, idx, ctx, "keep", []float32{1, 0})
+	mustAdd(t, idx, ctx, "changed", []float32{0, 1})
+	mustAdd(t, idx, ctx, "gone", []float32{1, 1})
+
+	added, removed := idx.Reconcile(ctx, map[string][]float32{
+		"keep":    {2, 0}, // same direction, so unchanged once normalized
+		"changed": {1, 0},
+		"new":     {0, 1},
+	})
+	if added != 2 || removed != 1 {
+		t.Errorf("Reconcile() = (%d, %d), want (2, 1)", added, removed)
+	}
+	if idx.Len() != 3 {
+		t.Errorf("expected Len()=3, got %d", idx.Len())
+	}
+	results := mustSearch(t, idx, ctx, []float32{0, 1}, 1)
+	if len(results) != 1 || results[0].BehaviorID != "new" {
+		t.Errorf("expected new closest to [0 1], got %+v", results)
+	}
+
+	if added, removed := idx.Reconcile(ctx, map[string][]float32{
+		"keep": {1, 0}, "changed": {1, 0}, "new": {0, 1},
+	}); added != 0 || removed != 0 {
+		t.Errorf("second Reconcile() = (%d, %d), want no changes", added, removed)
+	}
+}
+
+func TestHNSWIndex_SearchEdgeCases(t *testing.T) {
+	ctx := context.Background()
+	idx := newTestHNSW(t, "")
+	if results := mustSearch(t, idx, ctx, []float32{1, 0}, 5); len(results) != 0 {
+		t.Errorf("expected empty results from an empty index, got %d", len(results))
+	}
+	mustAdd(t, idx, ctx, "b1", []float32{1, 0})
+	if results := mustSearch(t, idx, ctx, []float32{1, 0}, 0); len(results) != 0 {
+		t.Errorf("expected empty results for topK=0, got %d", len(results))
+	}
+	if results := mustSearch(t, idx, ctx, []float32{}, 1); len(results) != 0 {
+		t.Errorf("expected empty results for empty query, got %d", len(results))
+	}
+}
+
+func TestHNSWIndex_ConcurrentAccess(t *testing.T) {
+	idx := newTestHNSW(t, "")
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			id := strconv.Itoa(n)
+			vec := []float32{float32(n), float32(n + 1), float32(n + 2)}
+			_ = idx.Add(ctx, id, vec)
+			_, _ = idx.Search(ctx, vec, 3)
+			_ = idx.Remove(ctx, id)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestHNSWIndex_ReconcileNewDimensions(t *testing.T) {
+	ctx := context.Background()
+	idx := newTestHNSW(t, "")
+	mustAdd(t, idx, ctx, "b1", []float32{1, 0})
+
+	added, removed := idx.Reconcile(ctx, map[string][]float32{"b1": {1, 0, 0}})
+	if added != 1 || removed != 1 {
+		t.Errorf("Reconcile() = (%d, %d), want (1, 1)", added, removed)
+	}
+	if results := mustSearch(t, idx, ctx, []float32{1, 0, 0}, 1); len(results) != 1 {
+		t.Errorf("expected b1 with the new dimensions, got %+v", results)
+	}
+}
@@ -8,11 +8,11 @@ import (
 )
 
 // LanceDBIndex is a stub for non-CGO builds.
-// All methods return errors; use BruteForceIndex instead.
+// All methods return errors; use HNSWIndex instead.
 type LanceDBIndex struct{}
 
 // NewLanceDBIndex requires CGO for the LanceDB Rust bindings.
-// Build with CGO_ENABLED=1 to use LanceDB, or use BruteForceIndex as a fallback.
+// Build with CGO_ENABLED=1 to use LanceDB, or use HNSWIndex as a fallback.
 func NewLanceDBIndex(_ LanceDBConfig) (*LanceDBIndex, error) {
 	return nil, errors.New("LanceDB requires CGO; build with CGO_ENABLED=1")
 }
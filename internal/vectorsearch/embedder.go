@@ -7,6 +7,10 @@ import (
 	"github.com/nvandessel/floop/internal/store"
 )
 
+// DocumentPrefix is the nomic-embed-text task prefix for stored behaviors.
+// Embeddings compared against the stored ones must use it too.
+const DocumentPrefix = "search_document: "
+
 // EmbedFunc is a function that returns a dense vector embedding for the given text.
 // This matches the signature of llm.EmbeddingComparer.Embed.
 type EmbedFunc func(ctx context.Context, text string) ([]float32, error)
@@ -42,12 +46,16 @@ func (e *Embedder) Available() bool {
 	return e != nil && e.embed != nil
 }
 
+// ModelName returns the model name recorded with stored embeddings.
+func (e *Embedder) ModelName() string {
+	return e.modelName
+}
+
 // EmbedAndStore embeds the given text with a search_document prefix and stores
 // the resulting vector in the embedding store. Returns the embedding vector
 // so callers can also insert it into the in-memory vector index.
 func (e *Embedder) EmbedAndStore(ctx context.Context, es store.EmbeddingStore, behaviorID, text string) ([]float32, error) {
-	prefixed := "search_document: " + text
-	vec, err := e.embed(ctx, prefixed)
+	vec, err := e.embed(ctx, DocumentPrefix+text)
 	if err != nil {
 		return nil, fmt.Errorf("embed behavior %s: %w", behaviorID, err)
 	}
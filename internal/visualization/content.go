@@ -0,0 +1,96 @@
+package visualization
+
+import (
+	"time"
+
+	"github.com/nvandessel/floop/internal/sanitize"
+)
+
+// The HTML graph page renders node content from the store, which accepts
+// arbitrary maps. Only the fields below reach the page; every string is
+// cleaned with sanitize.RenderText, and the page escapes it again on display.
+
+// provenanceFields are the provenance fields the graph page may show.
+var provenanceFields = []string{
+	"source_type", "created_at", "author", "correction_id", "package", "package_version",
+	"consolidated_by", "consolidated_at", "source_model", "source_agent", "source_project",
+}
+
+// statsCounters and statsTimes are the usage stats the graph page may show.
+var (
+	statsCounters = []string{"times_activated", "times_confirmed", "times_overridden", "times_followed"}
+	statsTimes    = []string{"created_at", "updated_at", "last_activated", "last_confirmed"}
+)
+
+// renderProvenance keeps the known provenance fields with string or time values.
+func renderProvenance(p map[string]interface{}) map[string]interface{} {
+	if p == nil {
+		return nil
+	}
+	out := make(map[string]interface{})
+	for _, field := range provenanceFields {
+		switch v := p[field].(type) {
+		case string:
+			if v != "" {
+				out[field] = sanitize.RenderText(v)
+			}
+		case time.Time:
+			out[field] = v.Format(time.RFC3339)
+		}
+	}
+	return out
+}
+
+// renderStats keeps the known counters with numeric values and the known
+// timestamps with string values.
+func renderStats(s map[string]interface{}) map[string]interface{} {
+	if s == nil {
+		return nil
+	}
+	out := make(map[string]interface{})
+	for _, field := range statsCounters {
+		switch v := s[field].(type) {
+		case int, int64, float64:
+			out[field] = v
+		}
+	}
+	for _, field := range statsTimes {
+		if v, ok := s[field].(string); ok && v != "" {
+			out[field] = sanitize.RenderText(v)
+		}
+	}
+	return out
+}
+
+// renderWhen keeps activation conditions with scalar or string-list values.
+func renderWhen(w map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(w))
+	for k, v := range w {
+		key := sanitize.RenderText(k)
+		switch v := v.(type) {
+		case string:
+			out[key] = sanitize.RenderText(v)
+		case bool, int, int64, float64:
+			out[key] = v
+		case []interface{}:
+			if list := renderStrings(v); len(list) == len(v) {
+				out[key] = list
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// renderStrings keeps the string elements of list, cleaned.
+func renderStrings(list []interface{}) []interface{} {
+	out := make([]interface{}, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, sanitize.RenderText(s))
+		}
+	}
+	return out
+}
@@ -8,8 +8,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/ranking"
+	"github.com/nvandessel/floop/internal/sanitize"
 	"github.com/nvandessel/floop/internal/store"
 )
 
@@ -41,10 +46,17 @@ var edgeStyles = map[store.EdgeKind]string{
 
 // RenderDOT produces a Graphviz DOT representation of the behavior graph.
 func RenderDOT(ctx context.Context, gs store.GraphStore) (string, error) {
-	// Get all behavior nodes
-	nodes, err := gs.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	return RenderDOTFiltered(ctx, gs, nil, nil, 0)
+}
+
+// RenderDOTFiltered is RenderDOT limited to the behaviors passing filter.
+// pageRank ranks behaviors for filter.TopN and may be nil otherwise.
+// decayRate is the spreading engine's edge decay rate, used for the
+// effective weights in edge tooltips; zero uses ranking.DefaultDecayRate.
+func RenderDOTFiltered(ctx context.Context, gs store.GraphStore, filter *Filter, pageRank map[string]float64, decayRate float64) (string, error) {
+	nodes, err := SelectNodes(ctx, gs, filter, pageRank)
 	if err != nil {
-		return "", fmt.Errorf("query nodes: %w", err)
+		return "", err
 	}
 
 	var b strings.Builder
@@ -80,27 +92,18 @@ func RenderDOT(ctx context.Context, gs store.GraphStore) (string, error) {
 	b.WriteString("\n")
 
 	// Render edges — collect from all nodes
-	seen := make(map[string]bool) // dedup "src|tgt|kind"
-	for _, node := range nodes {
-		edges, err := gs.GetEdges(ctx, node.ID, store.DirectionOutbound, "")
-		if err != nil {
-			return "", fmt.Errorf("get edges for node %s: %w", node.ID, err)
+	edges, err := CollectFilteredEdges(ctx, gs, nodes, filter)
+	if err != nil {
+		return "", err
+	}
+	for _, edge := range edges {
+		style := edgeStyles[edge.Kind]
+		if style == "" {
+			style = "solid"
 		}
-		for _, edge := range edges {
-			key := edge.Source + "|" + edge.Target + "|" + string(edge.Kind)
-			if seen[key] {
-				continue
-			}
-			seen[key] = true
-
-			style := edgeStyles[edge.Kind]
-			if style == "" {
-				style = "solid"
-			}
 
-			b.WriteString(fmt.Sprintf("  %q -> %q [label=%q, style=%s, weight=\"%.1f\"];\n",
-				edge.Source, edge.Target, string(edge.Kind), style, edge.Weight))
-		}
+		b.WriteString(fmt.Sprintf("  %q -> %q [label=%q, style=%s, weight=\"%.1f\", tooltip=\"weight=%.2f effective=%.2f\"];\n",
+			edge.Source, edge.Target, string(edge.Kind), style, edge.Weight, edge.Weight, effectiveWeight(edge, decayRate)))
 	}
 
 	b.WriteString("}\n")
@@ -109,9 +112,17 @@ func RenderDOT(ctx context.Context, gs store.GraphStore) (string, error) {
 
 // RenderJSON produces a JSON graph representation with nodes and edges arrays.
 func RenderJSON(ctx context.Context, gs store.GraphStore) (map[string]interface{}, error) {
-	nodes, err := gs.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	return RenderJSONFiltered(ctx, gs, nil, nil, 0)
+}
+
+// RenderJSONFiltered is RenderJSON limited to the behaviors passing filter.
+// pageRank ranks behaviors for filter.TopN and may be nil otherwise.
+// Each edge carries its effective weight under decayRate (see
+// RenderDOTFiltered) and when activation last flowed through it.
+func RenderJSONFiltered(ctx context.Context, gs store.GraphStore, filter *Filter, pageRank map[string]float64, decayRate float64) (map[string]interface{}, error) {
+	nodes, err := SelectNodes(ctx, gs, filter, pageRank)
 	if err != nil {
-		return nil, fmt.Errorf("query nodes: %w", err)
+		return nil, err
 	}
 
 	jsonNodes := make([]map[string]interface{}, 0, len(nodes))
@@ -138,26 +149,20 @@ func RenderJSON(ctx context.Context, gs store.GraphStore) (map[string]interface{
 	}
 
 	// Collect edges
-	seen := make(map[string]bool)
+	edges, err := CollectFilteredEdges(ctx, gs, nodes, filter)
+	if err != nil {
+		return nil, err
+	}
 	var jsonEdges []map[string]interface{}
-	for _, node := range nodes {
-		edges, err := gs.GetEdges(ctx, node.ID, store.DirectionOutbound, "")
-		if err != nil {
-			return nil, fmt.Errorf("get edges for node %s: %w", node.ID, err)
-		}
-		for _, edge := range edges {
-			key := edge.Source + "|" + edge.Target + "|" + string(edge.Kind)
-			if seen[key] {
-				continue
-			}
-			seen[key] = true
-			jsonEdges = append(jsonEdges, map[string]interface{}{
-				"source": edge.Source,
-				"target": edge.Target,
-				"kind":   string(edge.Kind),
-				"weight": edge.Weight,
-			})
+	for _, edge := range edges {
+		entry := map[string]interface{}{
+			"source": edge.Source,
+			"target": edge.Target,
+			"kind":   string(edge.Kind),
+			"weight": edge.Weight,
 		}
+		addEdgeDecay(entry, edge, decayRate)
+		jsonEdges = append(jsonEdges, entry)
 	}
 
 	return map[string]interface{}{
@@ -172,110 +177,63 @@ func RenderJSON(ctx context.Context, gs store.GraphStore) (map[string]interface{
 type EnrichmentData struct {
 	// PageRank maps behavior IDs to their PageRank scores (0.0-1.0).
 	PageRank map[string]float64
+
+	// Filter, when set, limits the graph to the behaviors passing it.
+	Filter *Filter
+
+	// Changes, when set, marks each node and edge with how it changed since
+	// a backup or timestamp. Pruned behaviors are added back as ghosts for
+	// unfiltered graphs; filters are evaluated against the current store.
+	Changes *backup.GraphDiff
+
+	// EdgeDecayRate is the spreading engine's edge decay rate, used for
+	// effective edge weights. Zero uses ranking.DefaultDecayRate.
+	EdgeDecayRate float64
 }
 
 // RenderEnrichedJSON produces a JSON graph with optional enrichment data (e.g. PageRank scores)
 // and additional node fields (canonical content) for the HTML visualization.
 // If enrichment is nil, it still adds content fields but skips PageRank.
+// Stored content is filtered to the fields the page shows (see content.go).
+// With PageRank available, nodes are ordered by descending score so the page
+// can load the most central behaviors first. With Changes set, nodes and
+// edges carry a "change" field and the graph a "changes" summary.
 func RenderEnrichedJSON(ctx context.Context, gs store.GraphStore, enrichment *EnrichmentData) (map[string]interface{}, error) {
-	nodes, err := gs.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	var filter *Filter
+	var pageRank map[string]float64
+	var changes *backup.GraphDiff
+	var decayRate float64
+	if enrichment != nil {
+		filter, pageRank, changes = enrichment.Filter, enrichment.PageRank, enrichment.Changes
+		decayRate = enrichment.EdgeDecayRate
+	}
+	nodes, err := SelectNodes(ctx, gs, filter, pageRank)
 	if err != nil {
-		return nil, fmt.Errorf("query nodes: %w", err)
+		return nil, err
+	}
+	if pageRank != nil {
+		sort.SliceStable(nodes, func(i, j int) bool {
+			return pageRank[nodes[i].ID] > pageRank[nodes[j].ID]
+		})
 	}
 
 	jsonNodes := make([]map[string]interface{}, 0, len(nodes))
 	for _, node := range nodes {
-		name := ""
-		if n, ok := node.Content["name"].(string); ok {
-			name = n
-		}
-		kind := ""
-		if k, ok := node.Content["kind"].(string); ok {
-			kind = k
-		}
-		confidence := 0.6
-		if meta, ok := node.Metadata["confidence"].(float64); ok {
-			confidence = meta
-		}
-
-		// Extract canonical content and tags from the nested content map
-		canonical := ""
-		var tags []interface{}
-		if content, ok := node.Content["content"].(map[string]interface{}); ok {
-			if c, ok := content["canonical"].(string); ok {
-				canonical = c
+		entry := enrichedNode(node, pageRank)
+		if changes != nil {
+			if status, ok := changes.Nodes[node.ID]; ok {
+				entry["change"] = string(status)
 			}
-			if t, ok := content["tags"].([]interface{}); ok {
-				tags = t
-			}
-		}
-		if tags == nil {
-			tags = []interface{}{}
-		}
-
-		scope := "local"
-		if s, ok := node.Metadata["scope"].(string); ok {
-			scope = s
-		}
-
-		// Extract stats from metadata
-		var stats map[string]interface{}
-		if s, ok := node.Metadata["stats"].(map[string]interface{}); ok {
-			stats = s
-		}
-
-		// Extract provenance
-		var provenance map[string]interface{}
-		if p, ok := node.Content["provenance"].(map[string]interface{}); ok {
-			provenance = p
 		}
-
-		// Extract priority
-		priority := 0
-		if p, ok := node.Metadata["priority"].(int); ok {
-			priority = p
-		} else if p, ok := node.Metadata["priority"].(float64); ok {
-			priority = int(p)
-		}
-
-		// Extract activation conditions
-		var when map[string]interface{}
-		if w, ok := node.Content["when"].(map[string]interface{}); ok && len(w) > 0 {
-			when = w
-		}
-
-		entry := map[string]interface{}{
-			"id":         node.ID,
-			"name":       name,
-			"kind":       kind,
-			"confidence": confidence,
-			"canonical":  canonical,
-			"scope":      scope,
-			"tags":       tags,
-		}
-
-		if stats != nil {
-			entry["stats"] = stats
-		}
-		if provenance != nil {
-			entry["provenance"] = provenance
-		}
-		if priority > 0 {
-			entry["priority"] = priority
-		}
-		if when != nil {
-			entry["when"] = when
-		}
-
-		// Add PageRank if available
-		if enrichment != nil && enrichment.PageRank != nil {
-			if pr, exists := enrichment.PageRank[node.ID]; exists {
-				entry["pagerank"] = pr
-			}
-		}
-
 		jsonNodes = append(jsonNodes, entry)
 	}
+	if changes != nil && filter.IsZero() {
+		for _, node := range changes.PrunedNodes {
+			entry := enrichedNode(node, nil)
+			entry["change"] = string(backup.ChangePruned)
+			jsonNodes = append(jsonNodes, entry)
+		}
+	}
 
 	// Build node scope map for edge scope derivation (reuse already-extracted scope)
 	nodeScope := make(map[string]string, len(jsonNodes))
@@ -284,28 +242,181 @@ func RenderEnrichedJSON(ctx context.Context, gs store.GraphStore, enrichment *En
 	}
 
 	// Collect edges
-	edges, err := CollectEdges(ctx, gs, nodes)
+	edges, err := CollectFilteredEdges(ctx, gs, nodes, filter)
 	if err != nil {
 		return nil, err
 	}
 
+	if changes != nil {
+		for _, edge := range changes.PrunedEdges {
+			if _, ok := nodeScope[edge.Source]; !ok {
+				continue
+			}
+			if _, ok := nodeScope[edge.Target]; ok {
+				edges = append(edges, edge)
+			}
+		}
+	}
+
 	jsonEdges := make([]map[string]interface{}, 0, len(edges))
 	for _, edge := range edges {
-		jsonEdges = append(jsonEdges, map[string]interface{}{
+		entry := map[string]interface{}{
 			"source": edge.Source,
 			"target": edge.Target,
 			"kind":   string(edge.Kind),
 			"weight": edge.Weight,
 			"scope":  deriveEdgeScope(nodeScope[edge.Source], nodeScope[edge.Target]),
-		})
+		}
+		addEdgeDecay(entry, edge, decayRate)
+		if changes != nil {
+			if status, ok := changes.Edges[backup.EdgeKey(edge)]; ok {
+				entry["change"] = string(status)
+			}
+		}
+		jsonEdges = append(jsonEdges, entry)
 	}
 
-	return map[string]interface{}{
+	result := map[string]interface{}{
 		"nodes":      jsonNodes,
 		"edges":      jsonEdges,
 		"node_count": len(jsonNodes),
 		"edge_count": len(jsonEdges),
-	}, nil
+	}
+	if changes != nil {
+		result["changes"] = changeSummary(changes.Base, jsonNodes, jsonEdges)
+	}
+	return result, nil
+}
+
+// effectiveWeight is the weight edge carries in spreading activation with
+// the given decay rate, zero meaning ranking.DefaultDecayRate.
+func effectiveWeight(edge store.Edge, decayRate float64) float64 {
+	if edge.LastActivated == nil {
+		return edge.Weight
+	}
+	if decayRate <= 0 {
+		decayRate = ranking.DefaultDecayRate
+	}
+	return ranking.EdgeDecay(edge.Weight, *edge.LastActivated, decayRate)
+}
+
+// addEdgeDecay adds an edge's effective weight and, if activation has
+// flowed through it, its last activation time to entry.
+func addEdgeDecay(entry map[string]interface{}, edge store.Edge, decayRate float64) {
+	entry["effective_weight"] = effectiveWeight(edge, decayRate)
+	if edge.LastActivated != nil {
+		entry["last_activated"] = edge.LastActivated.UTC().Format(time.RFC3339)
+	}
+}
+
+// changeSummary counts the change statuses among the rendered nodes and edges.
+func changeSummary(base time.Time, nodes, edges []map[string]interface{}) map[string]interface{} {
+	count := func(entries []map[string]interface{}) map[string]int {
+		counts := make(map[string]int)
+		for _, entry := range entries {
+			if status, ok := entry["change"].(string); ok {
+				counts[status]++
+			}
+		}
+		return counts
+	}
+	return map[string]interface{}{
+		"base":  base.UTC().Format(time.RFC3339),
+		"nodes": count(nodes),
+		"edges": count(edges),
+	}
+}
+
+// enrichedNode builds the page's entry for a behavior node.
+func enrichedNode(node store.Node, pageRank map[string]float64) map[string]interface{} {
+	name := ""
+	if n, ok := node.Content["name"].(string); ok {
+		name = sanitize.RenderText(n)
+	}
+	kind := ""
+	if k, ok := node.Content["kind"].(string); ok {
+		kind = sanitize.RenderText(k)
+	}
+	confidence := 0.6
+	if meta, ok := node.Metadata["confidence"].(float64); ok {
+		confidence = meta
+	}
+
+	// Extract canonical content and tags from the nested content map
+	canonical := ""
+	var tags []interface{}
+	if content, ok := node.Content["content"].(map[string]interface{}); ok {
+		if c, ok := content["canonical"].(string); ok {
+			canonical = sanitize.RenderText(c)
+		}
+		if t, ok := content["tags"].([]interface{}); ok {
+			tags = renderStrings(t)
+		}
+	}
+	if tags == nil {
+		tags = []interface{}{}
+	}
+
+	scope := "local"
+	if s, ok := node.Metadata["scope"].(string); ok {
+		scope = sanitize.RenderText(s)
+	}
+
+	// Extract stats from metadata
+	var stats map[string]interface{}
+	if s, ok := node.Metadata["stats"].(map[string]interface{}); ok {
+		stats = renderStats(s)
+	}
+
+	// Extract provenance
+	var provenance map[string]interface{}
+	if p, ok := node.Content["provenance"].(map[string]interface{}); ok {
+		provenance = renderProvenance(p)
+	}
+
+	// Extract priority
+	priority := 0
+	if p, ok := node.Metadata["priority"].(int); ok {
+		priority = p
+	} else if p, ok := node.Metadata["priority"].(float64); ok {
+		priority = int(p)
+	}
+
+	// Extract activation conditions
+	var when map[string]interface{}
+	if w, ok := node.Content["when"].(map[string]interface{}); ok && len(w) > 0 {
+		when = renderWhen(w)
+	}
+
+	entry := map[string]interface{}{
+		"id":         node.ID,
+		"name":       name,
+		"kind":       kind,
+		"confidence": confidence,
+		"canonical":  canonical,
+		"scope":      scope,
+		"tags":       tags,
+	}
+
+	if stats != nil {
+		entry["stats"] = stats
+	}
+	if provenance != nil {
+		entry["provenance"] = provenance
+	}
+	if priority > 0 {
+		entry["priority"] = priority
+	}
+	if when != nil {
+		entry["when"] = when
+	}
+
+	// Add PageRank if available
+	if pr, exists := pageRank[node.ID]; exists {
+		entry["pagerank"] = pr
+	}
+
+	return entry
 }
 
 // htmlTemplateData holds data passed to the HTML template.
@@ -402,6 +513,26 @@ func CollectEdges(ctx context.Context, gs store.GraphStore, nodes []store.Node)
 	return result, nil
 }
 
+// SelectNodes returns the behavior nodes passing filter (all of them when
+// filter is nil).
+func SelectNodes(ctx context.Context, gs store.GraphStore, filter *Filter, pageRank map[string]float64) ([]store.Node, error) {
+	nodes, err := gs.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, fmt.Errorf("query nodes: %w", err)
+	}
+	return filter.Apply(ctx, gs, nodes, pageRank)
+}
+
+// CollectFilteredEdges is CollectEdges, dropping edges that leave a filtered
+// graph or fall below its minimum weight.
+func CollectFilteredEdges(ctx context.Context, gs store.GraphStore, nodes []store.Node, filter *Filter) ([]store.Edge, error) {
+	edges, err := CollectEdges(ctx, gs, nodes)
+	if err != nil || filter.IsZero() {
+		return edges, err
+	}
+	return edgesWithin(edges, nodes, filter.MinWeight), nil
+}
+
 // deriveEdgeScope determines an edge's scope from its endpoint node scopes.
 // If both are the same scope, the edge gets that scope; otherwise "both".
 func deriveEdgeScope(sourceScope, targetScope string) string {
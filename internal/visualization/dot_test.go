@@ -2,12 +2,14 @@ package visualization
 
 import (
 	"context"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/nvandessel/floop/internal/backup"
 	"github.com/nvandessel/floop/internal/store"
 )
 
@@ -145,6 +147,55 @@ func TestRenderJSON_WithNodesAndEdges(t *testing.T) {
 	}
 }
 
+func TestRenderJSONFiltered_EdgeDecay(t *testing.T) {
+	gs := setupTestStore(t)
+	ctx := context.Background()
+
+	addBehavior(t, gs, "b1", "use-worktrees", "directive", 0.8)
+	addBehavior(t, gs, "b2", "tdd-workflow", "constraint", 0.9)
+	addBehavior(t, gs, "b3", "small-commits", "directive", 0.7)
+	last := time.Now().Add(-24 * time.Hour)
+	for _, e := range []store.Edge{
+		{Source: "b1", Target: "b2", Kind: store.EdgeKindRequires, Weight: 0.8, CreatedAt: last, LastActivated: &last},
+		{Source: "b1", Target: "b3", Kind: store.EdgeKindRequires, Weight: 0.6, CreatedAt: last},
+	} {
+		if err := gs.AddEdge(ctx, e); err != nil {
+			t.Fatalf("add edge: %v", err)
+		}
+	}
+
+	// A 24h half-life halves the edge activated a day ago
+	result, err := RenderJSONFiltered(ctx, gs, nil, nil, math.Ln2/24)
+	if err != nil {
+		t.Fatalf("RenderJSONFiltered: %v", err)
+	}
+	edges, _ := result["edges"].([]map[string]interface{})
+	if len(edges) != 2 {
+		t.Fatalf("edges = %v, want 2", result["edges"])
+	}
+	for _, e := range edges {
+		effective, _ := e["effective_weight"].(float64)
+		switch e["target"] {
+		case "b2":
+			if math.Abs(effective-0.4) > 0.001 || e["last_activated"] == nil {
+				t.Errorf("decayed edge = %v, want effective_weight 0.4 and last_activated", e)
+			}
+		case "b3":
+			if effective != 0.6 || e["last_activated"] != nil {
+				t.Errorf("never-activated edge = %v, want full weight and no last_activated", e)
+			}
+		}
+	}
+
+	dot, err := RenderDOTFiltered(ctx, gs, nil, nil, math.Ln2/24)
+	if err != nil {
+		t.Fatalf("RenderDOTFiltered: %v", err)
+	}
+	if !strings.Contains(dot, "weight=0.80 effective=0.40") {
+		t.Errorf("DOT missing effective weight tooltip:\n%s", dot)
+	}
+}
+
 func TestRenderEnrichedJSON_WithPageRank(t *testing.T) {
 	gs := setupTestStore(t)
 	ctx := context.Background()
@@ -1069,3 +1120,199 @@ func TestTruncate(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderEnrichedJSON_FiltersStoredContent(t *testing.T) {
+	gs := store.NewInMemoryGraphStore()
+	ctx := context.Background()
+
+	node := store.Node{
+		ID:   "b-hostile",
+		Kind: "behavior",
+		Content: map[string]interface{}{
+			"name": "hidden\u202ename",
+			"kind": "directive",
+			"content": map[string]interface{}{
+				"canonical": "Use uv\x1b[2J",
+				"tags":      []interface{}{"go", map[string]interface{}{"x": "y"}},
+			},
+			"provenance": map[string]interface{}{
+				"source_type": "imported",
+				"created_at":  time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+				"onload":      "<img src=x onerror=alert(1)>",
+			},
+			"when": map[string]interface{}{
+				"task":     "refactor",
+				"language": []interface{}{"go", "rust"},
+				"nested":   map[string]interface{}{"html": "<b>"},
+			},
+		},
+		Metadata: map[string]interface{}{
+			"confidence": 0.5,
+			"scope":      "local",
+			"stats": map[string]interface{}{
+				"times_activated": 3,
+				"times_followed":  "<script>",
+				"last_activated":  "2026-03-01T12:00:00Z",
+				"payload":         "<svg onload=alert(1)>",
+			},
+		},
+	}
+	if _, err := gs.AddNode(ctx, node); err != nil {
+		t.Fatalf("add node: %v", err)
+	}
+
+	result, err := RenderEnrichedJSON(ctx, gs, nil)
+	if err != nil {
+		t.Fatalf("RenderEnrichedJSON: %v", err)
+	}
+	entry := result["nodes"].([]map[string]interface{})[0]
+
+	if entry["name"] != "hiddenname" || entry["canonical"] != "Use uv[2J" {
+		t.Errorf("name, canonical = %q, %q; want control and invisible characters stripped", entry["name"], entry["canonical"])
+	}
+	if tags := entry["tags"].([]interface{}); len(tags) != 1 || tags[0] != "go" {
+		t.Errorf("tags = %v, want [go]", tags)
+	}
+
+	prov := entry["provenance"].(map[string]interface{})
+	if _, ok := prov["onload"]; ok || prov["created_at"] != "2026-03-01T12:00:00Z" || prov["source_type"] != "imported" {
+		t.Errorf("provenance = %v, want only known fields", prov)
+	}
+
+	when := entry["when"].(map[string]interface{})
+	if _, ok := when["nested"]; ok || when["task"] != "refactor" || len(when["language"].([]interface{})) != 2 {
+		t.Errorf("when = %v, want scalar and string-list conditions only", when)
+	}
+
+	stats := entry["stats"].(map[string]interface{})
+	if _, ok := stats["payload"]; ok {
+		t.Error("unknown stats field reached the page")
+	}
+	if _, ok := stats["times_followed"]; ok {
+		t.Error("non-numeric counter reached the page")
+	}
+	if stats["times_activated"] != 3 || stats["last_activated"] != "2026-03-01T12:00:00Z" {
+		t.Errorf("stats = %v", stats)
+	}
+}
+
+func TestRenderHTML_StoredContentCannotBreakPage(t *testing.T) {
+	ctx := context.Background()
+
+	baseline, err := RenderHTML(ctx, store.NewInMemoryGraphStore(), nil)
+	if err != nil {
+		t.Fatalf("RenderHTML(empty): %v", err)
+	}
+
+	payload := "</script><!--<script>alert(1)</script>\u2028\u2029]]>"
+	gs := store.NewInMemoryGraphStore()
+	node := store.Node{
+		ID:   "b-hostile",
+		Kind: "behavior",
+		Content: map[string]interface{}{
+			"name": payload,
+			"kind": payload,
+			"content": map[string]interface{}{
+				"canonical": payload,
+				"tags":      []interface{}{payload},
+			},
+			"provenance": map[string]interface{}{"source_type": payload, "created_at": payload},
+			"when":       map[string]interface{}{payload: payload},
+		},
+		Metadata: map[string]interface{}{
+			"confidence": 0.5,
+			"scope":      payload,
+			"stats":      map[string]interface{}{"times_activated": 1, "last_activated": payload},
+		},
+	}
+	if _, err := gs.AddNode(ctx, node); err != nil {
+		t.Fatalf("add node: %v", err)
+	}
+
+	html, err := RenderHTML(ctx, gs, nil)
+	if err != nil {
+		t.Fatalf("RenderHTML: %v", err)
+	}
+	htmlStr, baseStr := string(html), string(baseline)
+
+	// Stored content must not add markup of its own: every tag and comment
+	// opener in the page comes from the template.
+	for _, marker := range []string{"</script", "<script", "<!--"} {
+		if got, want := strings.Count(htmlStr, marker), strings.Count(baseStr, marker); got != want {
+			t.Errorf("%q appears %d times, template has %d", marker, got, want)
+		}
+	}
+	if strings.ContainsAny(htmlStr, "\u2028\u2029") {
+		t.Error("raw line separators reached the inline script")
+	}
+}
+
+func TestRenderEnrichedJSON_Changes(t *testing.T) {
+	gs := setupFilterStore(t)
+	changes := &backup.GraphDiff{
+		Base:  time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC),
+		Nodes: map[string]backup.ChangeStatus{"a": backup.ChangeNew, "b": backup.ChangeModified, "gone": backup.ChangePruned},
+		Edges: map[string]backup.ChangeStatus{"a:b:requires": backup.ChangeWeightIncreased, "gone:c:requires": backup.ChangePruned},
+		PrunedNodes: []store.Node{{
+			ID:      "gone",
+			Kind:    store.NodeKindBehavior,
+			Content: map[string]interface{}{"name": "gone", "kind": "directive"},
+		}},
+		PrunedEdges: []store.Edge{{Source: "gone", Target: "c", Kind: store.EdgeKindRequires, Weight: 0.5}},
+	}
+
+	result, err := RenderEnrichedJSON(context.Background(), gs, &EnrichmentData{Changes: changes})
+	if err != nil {
+		t.Fatalf("RenderEnrichedJSON() error = %v", err)
+	}
+
+	nodeChange := make(map[string]interface{})
+	for _, n := range result["nodes"].([]map[string]interface{}) {
+		nodeChange[n["id"].(string)] = n["change"]
+	}
+	for id, want := range map[string]interface{}{"a": "new", "b": "modified", "c": nil, "gone": "pruned"} {
+		if got, ok := nodeChange[id]; !ok || got != want {
+			t.Errorf("node %s change = %v (present %v), want %v", id, got, ok, want)
+		}
+	}
+
+	edgeChange := make(map[string]interface{})
+	for _, e := range result["edges"].([]map[string]interface{}) {
+		edgeChange[e["source"].(string)+":"+e["target"].(string)] = e["change"]
+	}
+	if edgeChange["a:b"] != "weight-increased" || edgeChange["gone:c"] != "pruned" {
+		t.Errorf("edge changes = %v", edgeChange)
+	}
+
+	summary := result["changes"].(map[string]interface{})
+	if summary["base"] != "2026-10-01T09:00:00Z" {
+		t.Errorf("changes base = %v", summary["base"])
+	}
+	if n := summary["nodes"].(map[string]int); n["new"] != 1 || n["modified"] != 1 || n["pruned"] != 1 {
+		t.Errorf("changes nodes = %v", n)
+	}
+
+	// Filters are evaluated against the current store, so ghosts are left out
+	result, err = RenderEnrichedJSON(context.Background(), gs, &EnrichmentData{Changes: changes, Filter: &Filter{Around: "b"}})
+	if err != nil {
+		t.Fatalf("RenderEnrichedJSON() error = %v", err)
+	}
+	for _, n := range result["nodes"].([]map[string]interface{}) {
+		if n["id"] == "gone" {
+			t.Error("pruned node should not be shown in a filtered graph")
+		}
+	}
+}
+
+func TestRenderHTML_DiffModeMarkers(t *testing.T) {
+	html, err := RenderHTML(context.Background(), setupFilterStore(t), nil)
+	if err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	htmlStr := string(html)
+	for _, marker := range []string{"diffMode", "changeColors", "legend-changes"} {
+		if !strings.Contains(htmlStr, marker) {
+			t.Errorf("expected %s in HTML", marker)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package visualization
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Filter narrows a rendered graph to the behaviors and edges worth looking
+// at, so large stores stay readable. The zero value keeps everything.
+type Filter struct {
+	Tags          []string // Keep behaviors having any of these tags
+	Kinds         []string // Keep behaviors of these kinds
+	MinConfidence float64  // Keep behaviors with at least this confidence
+	Around        string   // Keep only the neighborhood of this behavior ID
+	Depth         int      // Hops from Around to include (default 1)
+	TopN          int      // Keep the N highest-PageRank behaviors (0 = all)
+	MinWeight     float64  // Keep edges with at least this weight; Around follows only these
+}
+
+// MaxDepth caps neighborhood filters; beyond a few hops a neighborhood is
+// most of the graph.
+const MaxDepth = 5
+
+// Validate checks f's parameters.
+func (f *Filter) Validate() error {
+	valid := map[string]bool{
+		string(models.BehaviorKindDirective): true, string(models.BehaviorKindConstraint): true,
+		string(models.BehaviorKindProcedure): true, string(models.BehaviorKindPreference): true,
+		string(models.BehaviorKindEpisodic): true, string(models.BehaviorKindWorkflow): true,
+	}
+	for _, k := range f.Kinds {
+		if !valid[k] {
+			return fmt.Errorf("invalid kind %q: must be directive, constraint, procedure, preference, episodic, or workflow", k)
+		}
+	}
+	if f.MinConfidence < 0 || f.MinConfidence > 1 {
+		return fmt.Errorf("min confidence must be between 0.0 and 1.0, got %.2f", f.MinConfidence)
+	}
+	if f.Depth < 0 || f.Depth > MaxDepth {
+		return fmt.Errorf("depth must be between 0 and %d, got %d", MaxDepth, f.Depth)
+	}
+	if f.Depth > 0 && f.Around == "" {
+		return fmt.Errorf("depth requires a behavior to center the neighborhood on")
+	}
+	if f.MinWeight < 0 || f.MinWeight > 1 {
+		return fmt.Errorf("min weight must be between 0.0 and 1.0, got %.2f", f.MinWeight)
+	}
+	if f.TopN < 0 {
+		return fmt.Errorf("top N must not be negative, got %d", f.TopN)
+	}
+	return nil
+}
+
+// IsZero reports whether f keeps every behavior and edge.
+func (f *Filter) IsZero() bool {
+	return f == nil || (len(f.Tags) == 0 && len(f.Kinds) == 0 && f.MinConfidence <= 0 && f.Around == "" && f.TopN <= 0 && f.MinWeight <= 0)
+}
+
+// Apply returns the nodes that pass f, in their original order. Neighborhoods
+// are found by following edges in both directions between behaviors. TopN
+// ranks by pageRank and always keeps the Around behavior.
+func (f *Filter) Apply(ctx context.Context, gs store.GraphStore, nodes []store.Node, pageRank map[string]float64) ([]store.Node, error) {
+	if f.IsZero() {
+		return nodes, nil
+	}
+
+	var near map[string]bool
+	if f.Around != "" {
+		var err error
+		if near, err = neighborhood(ctx, gs, nodes, f.Around, f.Depth, f.MinWeight); err != nil {
+			return nil, err
+		}
+	}
+
+	kinds := toSet(f.Kinds)
+	tags := toSet(f.Tags)
+	kept := make([]store.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if near != nil && !near[node.ID] {
+			continue
+		}
+		if len(kinds) > 0 && !kinds[nodeKind(node)] {
+			continue
+		}
+		if len(tags) > 0 && !hasAnyTag(node, tags) {
+			continue
+		}
+		if nodeConfidence(node) < f.MinConfidence {
+			continue
+		}
+		kept = append(kept, node)
+	}
+
+	if f.TopN > 0 && len(kept) > f.TopN {
+		ranked := make([]store.Node, len(kept))
+		copy(ranked, kept)
+		sort.SliceStable(ranked, func(i, j int) bool {
+			if (ranked[i].ID == f.Around) != (ranked[j].ID == f.Around) {
+				return ranked[i].ID == f.Around
+			}
+			return pageRank[ranked[i].ID] > pageRank[ranked[j].ID]
+		})
+		top := make(map[string]bool, f.TopN)
+		for _, node := range ranked[:f.TopN] {
+			top[node.ID] = true
+		}
+		filtered := kept[:0]
+		for _, node := range kept {
+			if top[node.ID] {
+				filtered = append(filtered, node)
+			}
+		}
+		kept = filtered
+	}
+
+	return kept, nil
+}
+
+// neighborhood returns the IDs of behaviors within depth hops of id, over
+// edges of at least minWeight.
+func neighborhood(ctx context.Context, gs store.GraphStore, nodes []store.Node, id string, depth int, minWeight float64) (map[string]bool, error) {
+	if depth <= 0 {
+		depth = 1
+	}
+	behaviors := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		behaviors[node.ID] = true
+	}
+	if !behaviors[id] {
+		return nil, fmt.Errorf("behavior not found: %s", id)
+	}
+
+	near := map[string]bool{id: true}
+	frontier := []string{id}
+	for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+		var next []string
+		for _, cur := range frontier {
+			edges, err := gs.GetEdges(ctx, cur, store.DirectionBoth, "")
+			if err != nil {
+				return nil, fmt.Errorf("get edges for node %s: %w", cur, err)
+			}
+			for _, edge := range edges {
+				if edge.Weight < minWeight {
+					continue
+				}
+				other := edge.Target
+				if other == cur {
+					other = edge.Source
+				}
+				if behaviors[other] && !near[other] {
+					near[other] = true
+					next = append(next, other)
+				}
+			}
+		}
+		frontier = next
+	}
+	return near, nil
+}
+
+// edgesWithin drops edges with an endpoint outside nodes or a weight below
+// minWeight.
+func edgesWithin(edges []store.Edge, nodes []store.Node, minWeight float64) []store.Edge {
+	ids := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		ids[node.ID] = true
+	}
+	kept := edges[:0]
+	for _, edge := range edges {
+		if ids[edge.Source] && ids[edge.Target] && edge.Weight >= minWeight {
+			kept = append(kept, edge)
+		}
+	}
+	return kept
+}
+
+// nodeKind returns the behavior kind stored in a node's content.
+func nodeKind(node store.Node) string {
+	kind, _ := node.Content["kind"].(string)
+	return kind
+}
+
+// nodeConfidence returns a node's confidence, defaulting like the renderers.
+func nodeConfidence(node store.Node) float64 {
+	if c, ok := node.Metadata["confidence"].(float64); ok {
+		return c
+	}
+	return 0.6
+}
+
+// hasAnyTag reports whether node carries one of tags.
+func hasAnyTag(node store.Node, tags map[string]bool) bool {
+	content, _ := node.Content["content"].(map[string]interface{})
+	switch list := content["tags"].(type) {
+	case []interface{}:
+		for _, t := range list {
+			if s, ok := t.(string); ok && tags[s] {
+				return true
+			}
+		}
+	case []string:
+		for _, s := range list {
+			if tags[s] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		if v != "" {
+			set[v] = true
+		}
+	}
+	return set
+}
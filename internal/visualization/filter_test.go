@@ -0,0 +1,209 @@
+package visualization
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// setupFilterStore builds a chain a - b - c - d plus an unconnected e.
+func setupFilterStore(t *testing.T) store.GraphStore {
+	t.Helper()
+	gs := store.NewInMemoryGraphStore()
+	ctx := context.Background()
+	for _, n := range []struct {
+		id, kind   string
+		confidence float64
+		tags       []interface{}
+	}{
+		{"a", "directive", 0.9, []interface{}{"git"}},
+		{"b", "constraint", 0.8, []interface{}{"git", "safety"}},
+		{"c", "directive", 0.5, []interface{}{"go"}},
+		{"d", "preference", 0.7, nil},
+		{"e", "constraint", 0.95, []interface{}{"safety"}},
+	} {
+		node := store.Node{
+			ID:   n.id,
+			Kind: store.NodeKindBehavior,
+			Content: map[string]interface{}{
+				"name":    n.id,
+				"kind":    n.kind,
+				"content": map[string]interface{}{"canonical": "Behavior " + n.id, "tags": n.tags},
+			},
+			Metadata: map[string]interface{}{"confidence": n.confidence},
+		}
+		if _, err := gs.AddNode(ctx, node); err != nil {
+			t.Fatalf("add node %s: %v", n.id, err)
+		}
+	}
+	for _, e := range [][2]string{{"a", "b"}, {"b", "c"}, {"d", "c"}} {
+		edge := store.Edge{Source: e[0], Target: e[1], Kind: store.EdgeKindRequires, Weight: 0.8, CreatedAt: time.Now()}
+		if err := gs.AddEdge(ctx, edge); err != nil {
+			t.Fatalf("add edge: %v", err)
+		}
+	}
+	return gs
+}
+
+func TestFilter_Apply(t *testing.T) {
+	pageRank := map[string]float64{"a": 0.1, "b": 0.5, "c": 0.9, "d": 0.2, "e": 0.3}
+
+	tests := []struct {
+		name   string
+		filter *Filter
+		want   string
+	}{
+		{"nil keeps all", nil, "a,b,c,d,e"},
+		{"tags", &Filter{Tags: []string{"safety"}}, "b,e"},
+		{"kinds", &Filter{Kinds: []string{"directive"}}, "a,c"},
+		{"min confidence", &Filter{MinConfidence: 0.8}, "a,b,e"},
+		{"neighborhood", &Filter{Around: "b"}, "a,b,c"},
+		{"neighborhood depth 2", &Filter{Around: "a", Depth: 2}, "a,b,c"},
+		{"neighborhood follows inbound edges", &Filter{Around: "c"}, "b,c,d"},
+		{"top n", &Filter{TopN: 2}, "b,c"},
+		{"top n keeps around", &Filter{Around: "a", Depth: 2, TopN: 2}, "a,c"},
+		{"combined", &Filter{Tags: []string{"git", "go"}, MinConfidence: 0.6}, "a,b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := setupFilterStore(t)
+			nodes, err := SelectNodes(context.Background(), gs, tt.filter, pageRank)
+			if err != nil {
+				t.Fatalf("SelectNodes() error = %v", err)
+			}
+			ids := make([]string, 0, len(nodes))
+			for _, n := range nodes {
+				ids = append(ids, n.ID)
+			}
+			sort.Strings(ids)
+			if got := strings.Join(ids, ","); got != tt.want {
+				t.Errorf("SelectNodes() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_Apply_UnknownAround(t *testing.T) {
+	gs := setupFilterStore(t)
+	_, err := SelectNodes(context.Background(), gs, &Filter{Around: "missing"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "behavior not found") {
+		t.Errorf("SelectNodes() error = %v, want behavior not found", err)
+	}
+}
+
+func TestFilter_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  Filter
+		wantErr string
+	}{
+		{"zero", Filter{}, ""},
+		{"valid", Filter{Kinds: []string{"constraint"}, MinConfidence: 0.5, Around: "a", Depth: 3, TopN: 10}, ""},
+		{"bad kind", Filter{Kinds: []string{"forgotten-behavior"}}, "invalid kind"},
+		{"confidence too high", Filter{MinConfidence: 1.5}, "min confidence"},
+		{"depth too deep", Filter{Around: "a", Depth: MaxDepth + 1}, "depth must be"},
+		{"depth without around", Filter{Depth: 2}, "depth requires"},
+		{"negative top", Filter{TopN: -1}, "top N"},
+		{"weight too high", Filter{MinWeight: 1.5}, "min weight"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFilter_MinWeight(t *testing.T) {
+	ctx := context.Background()
+	gs := setupFilterStore(t)
+	weak := store.Edge{Source: "b", Target: "e", Kind: store.EdgeKindSimilarTo, Weight: 0.2, CreatedAt: time.Now()}
+	if err := gs.AddEdge(ctx, weak); err != nil {
+		t.Fatalf("add edge: %v", err)
+	}
+
+	nodes, err := SelectNodes(ctx, gs, &Filter{Around: "b", MinWeight: 0.5}, nil)
+	if err != nil {
+		t.Fatalf("SelectNodes() error = %v", err)
+	}
+	ids := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		ids = append(ids, n.ID)
+	}
+	sort.Strings(ids)
+	if got := strings.Join(ids, ","); got != "a,b,c" {
+		t.Errorf("neighborhood over strong edges = %s, want a,b,c", got)
+	}
+
+	result, err := RenderJSONFiltered(ctx, gs, &Filter{MinWeight: 0.5}, nil, 0)
+	if err != nil {
+		t.Fatalf("RenderJSONFiltered() error = %v", err)
+	}
+	if result["node_count"] != 5 || result["edge_count"] != 3 {
+		t.Errorf("node/edge count = %v/%v, want 5/3 (weak b -> e dropped)", result["node_count"], result["edge_count"])
+	}
+}
+
+func TestRenderJSONFiltered_DropsEdgesLeavingSelection(t *testing.T) {
+	gs := setupFilterStore(t)
+	result, err := RenderJSONFiltered(context.Background(), gs, &Filter{Kinds: []string{"directive", "constraint"}}, nil, 0)
+	if err != nil {
+		t.Fatalf("RenderJSONFiltered() error = %v", err)
+	}
+	if result["node_count"] != 4 {
+		t.Errorf("node_count = %v, want 4", result["node_count"])
+	}
+	// d -> c leaves the selection; a -> b and b -> c stay
+	if result["edge_count"] != 2 {
+		t.Errorf("edge_count = %v, want 2", result["edge_count"])
+	}
+}
+
+func TestRenderEnrichedJSON_FilterAndPageRankOrder(t *testing.T) {
+	gs := setupFilterStore(t)
+	enrichment := &EnrichmentData{
+		PageRank: map[string]float64{"a": 0.1, "b": 0.5, "c": 0.9, "d": 0.2, "e": 0.3},
+		Filter:   &Filter{Around: "b"},
+	}
+	result, err := RenderEnrichedJSON(context.Background(), gs, enrichment)
+	if err != nil {
+		t.Fatalf("RenderEnrichedJSON() error = %v", err)
+	}
+
+	// Most central first, so the page can load it before the rest
+	var ids []string
+	for _, n := range result["nodes"].([]map[string]interface{}) {
+		ids = append(ids, n["id"].(string))
+	}
+	if got := strings.Join(ids, ","); got != "c,b,a" {
+		t.Errorf("node order = %s, want c,b,a", got)
+	}
+	if result["edge_count"] != 2 {
+		t.Errorf("edge_count = %v, want 2", result["edge_count"])
+	}
+}
+
+func TestRenderHTML_ProgressiveLoading(t *testing.T) {
+	html, err := RenderHTML(context.Background(), setupFilterStore(t), nil)
+	if err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	htmlStr := string(html)
+	for _, marker := range []string{"LOAD_BATCH", "loadNextBatch", "stat-loading", "__getLoadState"} {
+		if !strings.Contains(htmlStr, marker) {
+			t.Errorf("expected %s in HTML", marker)
+		}
+	}
+}
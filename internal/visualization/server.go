@@ -28,6 +28,9 @@ type Server struct {
 // NewServer creates a new graph visualization server.
 func NewServer(gs store.GraphStore, enrichment *EnrichmentData) *Server {
 	cfg := spreading.DefaultConfig()
+	if enrichment != nil && enrichment.EdgeDecayRate > 0 {
+		cfg.TemporalDecayRate = enrichment.EdgeDecayRate
+	}
 	return &Server{
 		store:      gs,
 		enrichment: enrichment,